@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/signal"
 	"reflect"
 	"slices"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 )
@@ -136,6 +138,7 @@ type Application interface {
 	// Run starts the application and blocks until termination.
 	// This is equivalent to calling Init(), Start(), and then waiting
 	// for a termination signal (SIGINT, SIGTERM) before calling Stop().
+	// SIGHUP reloads the configuration of StdApplication, see ReloadConfig.
 	//
 	// This is the most common way to run a modular application:
 	//   if err := app.Run(); err != nil {
@@ -336,6 +339,44 @@ type StdApplication struct {
 	configFeeders       []Feeder                  // Optional per-application feeders (override global ConfigFeeders if non-nil)
 	startTime           time.Time                 // Tracks when the application was started
 	configLoadedHooks   []func(Application) error // Hooks to run after config loading but before module initialization
+	workers             *workerSupervisor         // Supervises modules implementing Worker while the application runs
+	profileOptions      *ProfileOptions           // Profile-layered config loading, nil when disabled
+	sectionFeeders      map[string][]Feeder       // Per-section feeders replacing the application-wide feeders
+	metrics             MetricsRegistry           // Registry shared by modules and core lifecycle metrics
+	metricsExports      []*metricsExport          // Exporters pushing metrics while the application runs
+	tenantWatch         *tenantWatch              // Keeps a TenantWatcher tenant service in sync while the application runs
+	configOverrides     map[string]ConfigProvider // Sections replaced after config loading, see SetConfigOverride
+	configValues        []configValue             // Fields set after config loading, see SetConfigValue
+	shutdownPhases      map[string]ShutdownPhase  // Shutdown phase annotations by module name
+	strictConfig        StrictConfigMode          // Handling of config file keys matching no section or field
+	timeline            timelineRecorder          // Lifecycle events, see Timeline
+	lazyInit            map[string]bool           // Lazy initialization annotations by module name
+	lazyModules         map[string]*lazyModule    // Modules whose initialization Init deferred, by name
+	initApp             Application               // Application passed to modules, kept for lazy ones
+
+	startupTimeout        time.Duration             // Bound on startup from Init to the end of Start, see SetStartupTimeout
+	startupDeadline       time.Time                 // When the running startup must have finished, zero without a bound
+	moduleTimeouts        map[string]ModuleTimeouts // Init and Start timeouts by module name
+	defaultModuleTimeouts ModuleTimeouts            // Timeouts of modules without their own
+
+	serviceInstrumentation bool                                      // Wrap services handed to other modules in their registered proxies
+	serviceTrackers        map[serviceTrackerKey]*ServiceCallTracker // Call statistics of instrumented services
+	serviceTrackersMu      sync.Mutex
+	contextAuditor         *ContextAuditor // Audits contexts of service calls and handlers, nil when disabled
+
+	lifecycleHooks map[LifecyclePhase][]LifecycleHook // Hooks run at each lifecycle phase, see OnLifecycle
+
+	swapMutex sync.Mutex // Serializes SwapModule calls
+
+	// registryMu guards moduleRegistry, svcRegistry and enhancedSvcRegistry, which
+	// SwapModule and lazy modules change while the application serves requests.
+	// svcRegistry is replaced rather than changed, so a snapshot may be read unlocked.
+	registryMu sync.RWMutex
+
+	cfgSectionsMu       sync.RWMutex      // Guards cfgSections against swaps by ReloadConfig
+	configBaseline      map[string]any    // Section configs as registered, before feeding; reloads start from them
+	configSectionOwners map[string]string // Module that registered each section
+	reloadMutex         sync.Mutex        // Serializes ReloadConfig calls
 }
 
 // NewStdApplication creates a new application instance with the provided configuration and logger.
@@ -381,10 +422,13 @@ func NewStdApplication(cp ConfigProvider, logger Logger) Application {
 		logger:              logger,
 		configFeeders:       nil,                                // default to nil to signal use of package-level ConfigFeeders
 		configLoadedHooks:   make([]func(Application) error, 0), // Initialize hooks slice
+		metrics:             NewMetricsRegistry(),
 	}
 
-	// Register the logger as a service so modules can depend on it
+	// Register the info and logger services so modules can depend on them
 	if app.enhancedSvcRegistry != nil {
+		_, _ = app.enhancedSvcRegistry.RegisterService(InfoServiceName, InfoProvider(infoService{app: app}))
+		_, _ = app.enhancedSvcRegistry.RegisterService(HealthServiceName, HealthService(healthService{app: app}))
 		_, _ = app.enhancedSvcRegistry.RegisterService("logger", logger) // Ignore error for logger service
 		app.svcRegistry = app.enhancedSvcRegistry.AsServiceRegistry()    // Update backwards compatible view
 	}
@@ -399,27 +443,65 @@ func (app *StdApplication) ConfigProvider() ConfigProvider {
 
 // SvcRegistry retrieves the service svcRegistry
 func (app *StdApplication) SvcRegistry() ServiceRegistry {
+	app.registryMu.RLock()
+	defer app.registryMu.RUnlock()
 	return app.svcRegistry
 }
 
 // RegisterModule adds a module to the application
 func (app *StdApplication) RegisterModule(module Module) {
+	app.registryMu.Lock()
+	defer app.registryMu.Unlock()
 	app.moduleRegistry[module.Name()] = module
 }
 
 // RegisterConfigSection registers a configuration section with the application
 func (app *StdApplication) RegisterConfigSection(section string, cp ConfigProvider) {
+	app.cfgSectionsMu.Lock()
+	defer app.cfgSectionsMu.Unlock()
 	app.cfgSections[section] = cp
 }
 
+// RegisterConfigSectionWithFeeders registers a configuration section that is fed only
+// by the given feeders, in order, instead of the application-wide feeders.
+//
+// Example:
+//
+//	app.RegisterConfigSectionWithFeeders("database", provider,
+//	    vaultFeeder, feeders.NewEnvFeeder())
+func (app *StdApplication) RegisterConfigSectionWithFeeders(section string, cp ConfigProvider, feeders ...Feeder) {
+	app.RegisterConfigSection(section, cp)
+	app.SetSectionFeeders(section, feeders...)
+}
+
+// SetSectionFeeders makes a configuration section read only from the given feeders,
+// in order, instead of the application-wide feeders (including base config and
+// profile feeders). It may be called before or after the section is registered,
+// e.g. for sections registered by modules. Calling it without feeders restores the
+// application-wide feeders for the section.
+func (app *StdApplication) SetSectionFeeders(section string, feeders ...Feeder) {
+	if len(feeders) == 0 {
+		delete(app.sectionFeeders, section)
+		return
+	}
+	if app.sectionFeeders == nil {
+		app.sectionFeeders = make(map[string][]Feeder)
+	}
+	app.sectionFeeders[section] = feeders
+}
+
 // ConfigSections retrieves all registered configuration sections
 func (app *StdApplication) ConfigSections() map[string]ConfigProvider {
-	return app.cfgSections
+	app.cfgSectionsMu.RLock()
+	defer app.cfgSectionsMu.RUnlock()
+	return maps.Clone(app.cfgSections)
 }
 
 // GetConfigSection retrieves a configuration section
 func (app *StdApplication) GetConfigSection(section string) (ConfigProvider, error) {
+	app.cfgSectionsMu.RLock()
 	cp, exists := app.cfgSections[section]
+	app.cfgSectionsMu.RUnlock()
 	if !exists {
 		return nil, fmt.Errorf("%w: %s", ErrConfigSectionNotFound, section)
 	}
@@ -434,31 +516,26 @@ func (app *StdApplication) SetConfigFeeders(feeders []Feeder) {
 
 // RegisterService adds a service with type checking
 func (app *StdApplication) RegisterService(name string, service any) error {
-	var actualName string
-
-	// Register with enhanced registry if available (handles automatic conflict resolution)
-	if app.enhancedSvcRegistry != nil {
-		var err error
-		actualName, err = app.enhancedSvcRegistry.RegisterService(name, service)
-		if err != nil {
-			return err
-		}
+	return app.RegisterServiceWithOptions(name, service)
+}
 
-		// Update backwards compatible view
-		app.svcRegistry = app.enhancedSvcRegistry.AsServiceRegistry()
-	} else {
-		// Check for duplicates using the backwards compatible registry
-		if _, exists := app.svcRegistry[name]; exists {
-			// Preserve contract: duplicate registrations are an error
-			if app.logger != nil {
-				app.logger.Debug("Service already registered", "name", name)
-			}
-			return ErrServiceAlreadyRegistered
-		}
+// RegisterServiceWithOptions adds a service like RegisterService, applying the given
+// registration options. Use AllowOverride to deliberately replace an existing service.
+func (app *StdApplication) RegisterServiceWithOptions(name string, service any, opts ...ServiceRegistrationOption) error {
+	_, err := app.registerService(nil, name, service, opts...)
+	return err
+}
 
-		// Fallback to direct registration for compatibility
-		app.svcRegistry[name] = service
-		actualName = name
+// registerService registers a service provided by module, or by the module being
+// initialized when module is nil, and returns the name it was registered under.
+// Observers are notified once the registry is unlocked.
+func (app *StdApplication) registerService(module Module, name string, service any, opts ...ServiceRegistrationOption) (string, error) {
+	entry, previous, err := app.addService(module, name, service, opts...)
+	if err != nil {
+		return "", err
+	}
+	if registry := app.enhancedSvcRegistry; registry != nil && registry.onChange != nil {
+		registry.onChange(entry, previous)
 	}
 
 	serviceType := reflect.TypeOf(service)
@@ -469,17 +546,124 @@ func (app *StdApplication) RegisterService(name string, service any) error {
 		typeName = "<nil>"
 	}
 	if app.logger != nil {
-		app.logger.Debug("Registered service", "name", name, "actualName", actualName, "type", typeName)
+		app.logger.Debug("Registered service", "name", name, "actualName", entry.ActualName, "type", typeName)
+	}
+	app.timeline.mark(TimelineServiceRegistered, entry.ModuleName, entry.ActualName)
+	return entry.ActualName, nil
+}
+
+// addService adds a service to the registries, returning its entry and the entry it
+// replaced, if any.
+func (app *StdApplication) addService(module Module, name string, service any, opts ...ServiceRegistrationOption) (entry, previous *ServiceRegistryEntry, err error) {
+	app.registryMu.Lock()
+	defer app.registryMu.Unlock()
+
+	// Register with enhanced registry if available (handles automatic conflict resolution)
+	if registry := app.enhancedSvcRegistry; registry != nil {
+		if module != nil {
+			current := registry.currentModule
+			registry.currentModule = module
+			defer func() { registry.currentModule = current }()
+		}
+		if entry, previous, err = registry.register(name, service, opts...); err != nil {
+			return nil, nil, err
+		}
+
+		// Update backwards compatible view
+		app.svcRegistry = registry.AsServiceRegistry()
+		return entry, previous, nil
+	}
+
+	var options serviceRegistrationOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// Check for duplicates using the backwards compatible registry
+	if _, exists := app.svcRegistry[name]; exists && !options.allowOverride {
+		// Preserve contract: duplicate registrations are an error
+		if app.logger != nil {
+			app.logger.Debug("Service already registered", "name", name)
+		}
+		return nil, nil, ErrServiceAlreadyRegistered
+	}
+
+	// Fallback to direct registration for compatibility
+	services := maps.Clone(app.svcRegistry)
+	if services == nil {
+		services = make(ServiceRegistry)
+	}
+	services[name] = service
+	app.svcRegistry = services
+	return &ServiceRegistryEntry{Service: service, OriginalName: name, ActualName: name}, nil, nil
+}
+
+// services returns the current snapshot of the service registry.
+func (app *StdApplication) services() ServiceRegistry {
+	app.registryMu.RLock()
+	defer app.registryMu.RUnlock()
+	return app.svcRegistry
+}
+
+// setModule replaces the registered instance of the named module.
+func (app *StdApplication) setModule(name string, module Module) {
+	app.registryMu.Lock()
+	defer app.registryMu.Unlock()
+	app.moduleRegistry[name] = module
+}
+
+// setCurrentModule attributes services registered from now on to module, or to no
+// module when it is nil, and returns the module they were attributed to before.
+func (app *StdApplication) setCurrentModule(module Module) Module {
+	app.registryMu.Lock()
+	defer app.registryMu.Unlock()
+	if app.enhancedSvcRegistry == nil {
+		return nil
+	}
+	previous := app.enhancedSvcRegistry.currentModule
+	app.enhancedSvcRegistry.currentModule = module
+	return previous
+}
+
+// SetServiceConflictPolicy sets how registering a service under a name that is
+// already in use is handled. The default, ServiceConflictRename, registers the
+// new service under a derived name.
+func (app *StdApplication) SetServiceConflictPolicy(policy ServiceConflictPolicy) {
+	if app.enhancedSvcRegistry != nil {
+		app.enhancedSvcRegistry.SetConflictPolicy(policy)
+	}
+}
+
+// reserveServiceNamespaces reserves the service namespaces declared by modules
+// implementing ServiceNamespaceProvider, in initialization order.
+func (app *StdApplication) reserveServiceNamespaces(moduleOrder []string) error {
+	if app.enhancedSvcRegistry == nil {
+		return nil
+	}
+	for _, moduleName := range moduleOrder {
+		provider, ok := app.moduleRegistry[moduleName].(ServiceNamespaceProvider)
+		if !ok {
+			continue
+		}
+		for _, namespace := range provider.ServiceNamespaces() {
+			if err := app.enhancedSvcRegistry.ReserveNamespace(namespace, moduleName); err != nil {
+				return fmt.Errorf("module '%s': %w", moduleName, err)
+			}
+		}
 	}
 	return nil
 }
 
 // GetService retrieves a service with type assertion
 func (app *StdApplication) GetService(name string, target any) error {
-	service, exists := app.svcRegistry[name]
+	service, exists := app.services()[name]
 	if !exists {
 		return fmt.Errorf("%w: %s", ErrServiceNotFound, name)
 	}
+	service, err := app.resolveService(name, service)
+	if err != nil {
+		return err
+	}
 
 	targetValue := reflect.ValueOf(target)
 	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
@@ -495,7 +679,13 @@ func (app *StdApplication) GetService(name string, target any) error {
 
 	// Case 1: Target is an interface that the service implements
 	if targetType.Kind() == reflect.Interface && serviceType.Implements(targetType) {
-		targetValue.Elem().Set(reflect.ValueOf(service))
+		var consumer string
+		app.registryMu.RLock()
+		if app.enhancedSvcRegistry != nil && app.enhancedSvcRegistry.currentModule != nil {
+			consumer = app.enhancedSvcRegistry.currentModule.Name()
+		}
+		app.registryMu.RUnlock()
+		targetValue.Elem().Set(reflect.ValueOf(app.instrumentService(consumer, name, service, targetType)))
 		return nil
 	}
 
@@ -543,7 +733,16 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 		return nil
 	}
 
+	initStart := time.Now()
+	app.initApp = appToPass
+	app.beginStartup(initStart)
+	if err := app.runLifecycleHooks(context.Background(), PhasePreInit); err != nil {
+		app.timeline.step(TimelineInitCompleted, "", initStart, err)
+		return err
+	}
+
 	errs := make([]error, 0)
+	sectionOwners := make(map[string]string)
 	for name, module := range app.moduleRegistry {
 		configurableModule, ok := module.(Configurable)
 		if !ok {
@@ -552,20 +751,30 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 			}
 			continue
 		}
+		sectionsBefore := slices.Collect(maps.Keys(app.cfgSections))
 		err := configurableModule.RegisterConfig(appToPass)
 		if err != nil {
 			errs = append(errs, fmt.Errorf("module %s failed to register config: %w", name, err))
 			continue
 		}
+		app.recordSectionOwners(sectionsBefore, name, sectionOwners)
 		if app.logger != nil {
 			app.logger.Debug("Registering module", "name", name)
 		}
 	}
 
 	// Configuration loading (AppConfigLoader will consult app.configFeeders directly now)
+	configStart, configErrs := time.Now(), len(errs)
+	app.captureConfigBaseline(sectionOwners)
 	if err := AppConfigLoader(app); err != nil {
 		errs = append(errs, fmt.Errorf("failed to load app config: %w", err))
 	}
+	if err := app.applyConfigOverrides(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := app.applyConfigValues(); err != nil {
+		errs = append(errs, err)
+	}
 
 	// Execute config loaded hooks after configuration is loaded but before modules initialize
 	if len(app.configLoadedHooks) > 0 {
@@ -581,6 +790,7 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 			app.logger.Debug("Config loaded hooks executed successfully")
 		}
 	}
+	app.timeline.step(TimelineConfigLoaded, "", configStart, errors.Join(errs[configErrs:]...))
 
 	// Build dependency graph
 	moduleOrder, err := app.resolveDependencies()
@@ -588,26 +798,54 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 		errs = append(errs, fmt.Errorf("failed to resolve module dependencies: %w", err))
 	}
 
+	if err = app.reserveServiceNamespaces(moduleOrder); err != nil {
+		errs = append(errs, fmt.Errorf("failed to reserve service namespaces: %w", err))
+	}
+
 	// Initialize modules in order
 	for _, moduleName := range moduleOrder {
+		if app.startupExpired(time.Now()) {
+			errs = append(errs, fmt.Errorf("%w: startup timeout elapsed before module '%s' initialized", ErrModuleInitTimeout, moduleName))
+			break
+		}
 		module := app.moduleRegistry[moduleName]
+		lazy := app.isLazy(moduleName, module)
 
-		if _, ok := module.(ServiceAware); ok {
+		if !lazy {
+			if err = app.initLazyDependencies(module); err != nil {
+				errs = append(errs, fmt.Errorf("module '%s' dependency failed: %w", moduleName, err))
+				continue
+			}
+		}
+
+		_, constructable := module.(Constructable)
+		if _, ok := module.(ServiceAware); ok && (!lazy || constructable) {
 			// Inject required services
-			app.moduleRegistry[moduleName], err = app.injectServices(module)
+			injected, err := app.injectServices(module)
 			if err != nil {
+				// Keep the module registered so it can still be inspected, e.g. in DependencyGraph
 				errs = append(errs, fmt.Errorf("failed to inject services for module '%s': %w", moduleName, err))
 				continue
 			}
-			module = app.moduleRegistry[moduleName] // Update reference after injection
+			app.setModule(moduleName, injected)
+			module = injected // Update reference after injection
 		}
 
-		// Set current module context for service registration tracking
-		if app.enhancedSvcRegistry != nil {
-			app.enhancedSvcRegistry.SetCurrentModule(module)
+		// Lazy modules are constructed now but initialize on first use
+		if lazy {
+			if err = app.deferInit(moduleName, module); err != nil {
+				errs = append(errs, err)
+			}
+			continue
 		}
 
-		if err = module.Init(appToPass); err != nil {
+		// Set current module context for service registration tracking
+		app.setCurrentModule(module)
+
+		moduleStart := time.Now()
+		err = app.initModule(moduleName, module, appToPass, true)
+		app.recordLifecycleDuration("init", moduleName, moduleStart, err)
+		if err != nil {
 			errs = append(errs, fmt.Errorf("module '%s' failed to initialize: %w", moduleName, err))
 			continue
 		}
@@ -615,7 +853,11 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 		if _, ok := module.(ServiceAware); ok {
 			// Register services provided by modules
 			for _, svc := range module.(ServiceAware).ProvidesServices() {
-				if err = app.RegisterService(svc.Name, svc.Instance); err != nil {
+				var opts []ServiceRegistrationOption
+				if svc.AllowOverride {
+					opts = append(opts, AllowOverride())
+				}
+				if err = app.RegisterServiceWithOptions(svc.Name, svc.Instance, opts...); err != nil {
 					// Collect registration errors (e.g., duplicates) for reporting
 					errs = append(errs, fmt.Errorf("module '%s' failed to register service '%s': %w", moduleName, svc.Name, err))
 					continue
@@ -624,9 +866,7 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 		}
 
 		// Clear current module context
-		if app.enhancedSvcRegistry != nil {
-			app.enhancedSvcRegistry.ClearCurrentModule()
-		}
+		app.setCurrentModule(nil)
 
 		app.logger.Info(fmt.Sprintf("Initialized module %s of type %T", moduleName, app.moduleRegistry[moduleName]))
 	}
@@ -636,12 +876,20 @@ func (app *StdApplication) InitWithApp(appToPass Application) error {
 		errs = append(errs, fmt.Errorf("failed to initialize tenant configurations: %w", err))
 	}
 
+	if len(errs) == 0 {
+		if err = app.runLifecycleHooks(context.Background(), PhasePostInit); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	// Mark as initialized only after completing Init flow
 	if len(errs) == 0 {
 		app.initialized = true
 	}
 
-	return errors.Join(errs...)
+	err = errors.Join(errs...)
+	app.timeline.step(TimelineInitCompleted, "", initStart, err)
+	return err
 }
 
 // initTenantConfigurations initializes tenant configurations after modules have registered their configs
@@ -650,17 +898,28 @@ func (app *StdApplication) initTenantConfigurations() error {
 	if err := app.GetService("tenantService", &tenantSvc); err == nil {
 		app.tenantService = tenantSvc
 
+		// Record tenant registrations in the timeline, starting with the loaded ones
+		app.recordTenantRegistrations(tenantSvc)
+
 		// If there's a TenantConfigLoader service, use it to load tenant configs
 		var loader TenantConfigLoader
-		if err = app.GetService("tenantConfigLoader", &loader); err == nil {
+		if err = app.GetService("tenantConfigLoader", &loader); err != nil {
+			// Tenant services backed by an external store, such as DynamicTenantService, load their own tenants
+			loader, _ = tenantSvc.(TenantConfigLoader)
+		}
+		if loader != nil {
 			app.logger.Debug("Loading tenant configurations using TenantConfigLoader")
 			if err = loader.LoadTenantConfigurations(app, tenantSvc); err != nil {
 				return fmt.Errorf("failed to load tenant configurations: %w", err)
 			}
 		}
 
-		// Register tenant-aware modules with the tenant service
-		for _, module := range app.moduleRegistry {
+		// Register tenant-aware modules with the tenant service; lazy ones register
+		// once they initialize
+		for name, module := range app.moduleRegistry {
+			if app.pendingLazy(name) {
+				continue
+			}
 			if tenantAwareModule, ok := module.(TenantAwareModule); ok {
 				if err := tenantSvc.RegisterTenantAwareModule(tenantAwareModule); err != nil {
 					app.logger.Warn("Failed to register tenant-aware module", "module", module.Name(), "error", err)
@@ -678,36 +937,81 @@ func (app *StdApplication) initTenantConfigurations() error {
 func (app *StdApplication) Start() error {
 	// Record the start time
 	app.startTime = time.Now()
+	app.beginStartup(app.startTime)
+	defer func() { app.startupDeadline = time.Time{} }()
 
 	// Create cancellable context for the application
 	ctx, cancel := context.WithCancel(context.Background())
 	app.ctx = ctx
 	app.cancel = cancel
 
+	if err := app.runLifecycleHooks(ctx, PhasePreStart); err != nil {
+		app.timeline.step(TimelineStarted, "", app.startTime, err)
+		return err
+	}
+
 	// Start modules in dependency order
 	modules, err := app.resolveDependencies()
 	if err != nil {
+		app.timeline.step(TimelineStarted, "", app.startTime, err)
+		return err
+	}
+	if err := app.validateShutdownPhases(); err != nil {
+		app.timeline.step(TimelineStarted, "", app.startTime, err)
 		return err
 	}
 
 	for _, name := range modules {
-		module := app.moduleRegistry[name]
+		module := app.GetModule(name)
 		startableModule, ok := module.(Startable)
 		if !ok {
 			app.logger.Debug("Module does not implement Startable, skipping", "module", name)
 			continue
 		}
+		if app.pendingLazy(name) {
+			app.logger.Debug("Lazy module not initialized, skipping start", "module", name)
+			continue
+		}
+		if app.lazyStarted(name) {
+			app.logger.Debug("Lazy module started when it initialized, skipping start", "module", name)
+			continue
+		}
+		if app.startupExpired(time.Now()) {
+			err := fmt.Errorf("failed to start module %s: %w: startup timeout elapsed before it started", name, ErrModuleStartTimeout)
+			app.timeline.step(TimelineStarted, "", app.startTime, err)
+			return err
+		}
 		app.logger.Info("Starting module", "module", name)
-		if err := startableModule.Start(ctx); err != nil {
-			return fmt.Errorf("failed to start module %s: %w", name, err)
+		startedAt := time.Now()
+		err := app.startModule(ctx, name, startableModule, true)
+		app.recordLifecycleDuration("start", name, startedAt, err)
+		if err != nil {
+			err = fmt.Errorf("failed to start module %s: %w", name, err)
+			app.timeline.step(TimelineStarted, "", app.startTime, err)
+			return err
 		}
 	}
 
+	// Launch supervised workers once every module has started
+	app.workers = startWorkers(ctx, app.logger, modules, app.moduleRegistry)
+
+	app.startMetricsExports(ctx)
+	app.startTenantWatch(ctx)
+
+	if err := app.runLifecycleHooks(ctx, PhasePostStart); err != nil {
+		app.timeline.step(TimelineStarted, "", app.startTime, err)
+		return err
+	}
+
+	app.timeline.step(TimelineStarted, "", app.startTime, nil)
+	app.logStartupBanner()
 	return nil
 }
 
 // Stop stops the application
 func (app *StdApplication) Stop() error {
+	stopStart := time.Now()
+
 	// Get modules in reverse dependency order
 	modules, err := app.resolveDependencies()
 	if err != nil {
@@ -721,20 +1025,59 @@ func (app *StdApplication) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Stop modules in reverse order
+	hookErr := app.runLifecycleHooks(ctx, PhasePreStop)
+
+	// Drain workers before stopping the modules they depend on, but after ingress
+	// and proxies have stopped feeding them
 	var lastErr error
-	for _, name := range modules {
-		module := app.moduleRegistry[name]
-		stoppableModule, ok := module.(Stoppable)
-		if !ok {
-			app.logger.Debug("Module does not implement Stoppable, skipping", "module", name)
-			continue
+	drainWorkers := func() {
+		if app.workers == nil {
+			return
 		}
-		app.logger.Info("Stopping module", "module", name)
-		if err = stoppableModule.Stop(ctx); err != nil {
-			app.logger.Error("Error stopping module", "module", name, "error", err)
+		app.logger.Info("Draining workers")
+		if err := app.workers.stop(ctx); err != nil {
+			app.logger.Error("Error draining workers", "error", err)
 			lastErr = err
 		}
+		app.workers = nil
+	}
+
+	// Stop modules phase by phase, each phase in reverse order
+	for _, stage := range app.shutdownPlan(modules) {
+		if stage.phase >= ShutdownPhaseDefault {
+			drainWorkers()
+		}
+		app.logger.Debug("Entering shutdown phase", "phase", stage.phase.String(), "modules", stage.modules)
+		for _, name := range stage.modules {
+			module := app.GetModule(name)
+			stoppableModule, ok := module.(Stoppable)
+			if !ok {
+				app.logger.Debug("Module does not implement Stoppable, skipping", "module", name)
+				continue
+			}
+			if app.pendingLazy(name) {
+				app.logger.Debug("Lazy module not initialized, skipping stop", "module", name)
+				continue
+			}
+			app.logger.Info("Stopping module", "module", name, "phase", stage.phase.String())
+			moduleStop := time.Now()
+			err = stoppableModule.Stop(ctx)
+			app.recordLifecycleDuration("stop", name, moduleStop, err)
+			if err != nil {
+				app.logger.Error("Error stopping module", "module", name, "error", err)
+				lastErr = err
+			}
+		}
+	}
+	drainWorkers()
+
+	app.stopTenantWatch()
+	app.stopMetricsExports(ctx)
+	app.contextAuditor.logSummary()
+
+	hookErr = errors.Join(hookErr, app.runLifecycleHooks(ctx, PhasePostStop))
+	if hookErr != nil {
+		lastErr = errors.Join(lastErr, hookErr)
 	}
 
 	// Cancel the main application context
@@ -742,31 +1085,54 @@ func (app *StdApplication) Stop() error {
 		app.cancel()
 	}
 
+	app.timeline.step(TimelineStopped, "", stopStart, lastErr)
 	return lastErr
 }
 
 // Run starts the application and blocks until termination
 func (app *StdApplication) Run() error {
+	return app.runAs(app)
+}
+
+// runAs is Run through the Init, Start and Stop of lifecycle, so that an
+// application embedding StdApplication runs its own overrides.
+func (app *StdApplication) runAs(lifecycle interface {
+	Init() error
+	Start() error
+	Stop() error
+}) error {
 	// Initialize
-	if err := app.Init(); err != nil {
+	if err := lifecycle.Init(); err != nil {
 		return err
 	}
 
 	// Start all modules
-	if err := app.Start(); err != nil {
+	if err := lifecycle.Start(); err != nil {
 		return err
 	}
 
 	// Setup signal handling
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 
-	// Wait for termination signal
-	sig := <-sigChan
-	app.logger.Info("Received signal, shutting down", "signal", sig)
+	// Wait for termination signal, reloading the configuration on SIGHUP
+	var reloader interface{ ReloadConfig(context.Context) error } = app
+	if r, ok := app.initApp.(interface{ ReloadConfig(context.Context) error }); ok {
+		reloader = r
+	}
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			app.logger.Info("Received signal, shutting down", "signal", sig)
+			break
+		}
+		app.logger.Info("Received SIGHUP, reloading configuration")
+		if err := reloader.ReloadConfig(app.ctx); err != nil {
+			app.logger.Error("Failed to reload configuration", "error", err)
+		}
+	}
 
 	// Stop all modules
-	return app.Stop()
+	return lifecycle.Stop()
 }
 
 // injectServices injects required services into a module
@@ -820,12 +1186,16 @@ func (app *StdApplication) resolveNameBasedDependencies(
 			continue // Skip interface-based dependencies
 		}
 
-		service, serviceFound := app.svcRegistry[dep.Name]
+		service, serviceFound := app.services()[dep.Name]
 		if serviceFound {
+			service, err := app.resolveService(dep.Name, service)
+			if err != nil {
+				return fmt.Errorf("failed to inject service '%s': %w", dep.Name, err)
+			}
 			if valid, err := checkServiceCompatibility(service, dep); !valid {
 				return fmt.Errorf("failed to inject service '%s': %w", dep.Name, err)
 			}
-			requiredServices[dep.Name] = service
+			requiredServices[dep.Name] = app.instrumentService(moduleName, dep.Name, service, dep.SatisfiesInterface)
 		} else if dep.Required {
 			return fmt.Errorf("%w: %s for %s", ErrRequiredServiceNotFound, dep.Name, moduleName)
 		}
@@ -863,10 +1233,14 @@ func (app *StdApplication) resolveInterfaceBasedDependencies(
 		matchedService, matchedServiceName := app.findServiceByInterface(dep)
 
 		if matchedService != nil {
+			var err error
+			if matchedService, err = app.resolveService(matchedServiceName, matchedService); err != nil {
+				return fmt.Errorf("failed to inject service '%s': %w", matchedServiceName, err)
+			}
 			if valid, err := checkServiceCompatibility(matchedService, dep); !valid {
 				return fmt.Errorf("failed to inject service '%s': %w", matchedServiceName, err)
 			}
-			requiredServices[dep.Name] = matchedService
+			requiredServices[dep.Name] = app.instrumentService(moduleName, matchedServiceName, matchedService, dep.SatisfiesInterface)
 		} else if dep.Required {
 			return fmt.Errorf("%w: no service found implementing interface %v for %s",
 				ErrRequiredServiceNotFound, dep.SatisfiesInterface, moduleName)
@@ -877,8 +1251,8 @@ func (app *StdApplication) resolveInterfaceBasedDependencies(
 
 // findServiceByInterface finds a service that implements the specified interface
 func (app *StdApplication) findServiceByInterface(dep ServiceDependency) (service any, serviceName string) {
-	for serviceName, service := range app.svcRegistry {
-		serviceType := reflect.TypeOf(service)
+	for serviceName, service := range app.services() {
+		serviceType := registeredType(service)
 		if app.typeImplementsInterface(serviceType, dep.SatisfiesInterface) {
 			return service, serviceName
 		}
@@ -1028,7 +1402,10 @@ func (app *StdApplication) Logger() Logger {
 func (app *StdApplication) SetLogger(logger Logger) {
 	app.logger = logger
 	// Also update the service registry so modules get the new logger via DI
-	app.svcRegistry["logger"] = logger
+	entry, previous, err := app.addService(nil, "logger", logger, AllowOverride())
+	if err == nil && app.enhancedSvcRegistry != nil && app.enhancedSvcRegistry.onChange != nil {
+		app.enhancedSvcRegistry.onChange(entry, previous)
+	}
 }
 
 // SetVerboseConfig enables or disables verbose configuration debugging
@@ -1078,8 +1455,10 @@ func (e EdgeType) String() string {
 	}
 }
 
-// resolveDependencies returns modules in initialization order
-func (app *StdApplication) resolveDependencies() ([]string, error) {
+// buildDependencyGraph returns the adjacency list of module dependencies, declared
+// and implied by required services, and the edges explaining them, in which cycles
+// are looked for.
+func (app *StdApplication) buildDependencyGraph() (map[string][]string, []DependencyEdge) {
 	// Create dependency graph and track dependency edges
 	graph := make(map[string][]string)
 	dependencyEdges := make([]DependencyEdge, 0)
@@ -1129,7 +1508,12 @@ func (app *StdApplication) resolveDependencies() ([]string, error) {
 		}
 		pruned = append(pruned, e)
 	}
-	dependencyEdges = pruned
+	return graph, pruned
+}
+
+// resolveDependencies returns modules in initialization order
+func (app *StdApplication) resolveDependencies() ([]string, error) {
+	graph, dependencyEdges := app.buildDependencyGraph()
 
 	// Enhanced topological sort with path tracking
 	var result []string
@@ -1608,26 +1992,55 @@ func (app *StdApplication) GetTenantConfig(tenantID TenantID, section string) (C
 
 // GetServicesByModule returns all services provided by a specific module
 func (app *StdApplication) GetServicesByModule(moduleName string) []string {
+	app.registryMu.RLock()
+	defer app.registryMu.RUnlock()
 	if app.enhancedSvcRegistry != nil {
-		return app.enhancedSvcRegistry.GetServicesByModule(moduleName)
+		return slices.Clone(app.enhancedSvcRegistry.GetServicesByModule(moduleName))
 	}
 	return nil
 }
 
-// GetServiceEntry retrieves detailed information about a registered service
+// GetServiceEntry retrieves detailed information about a registered service,
+// initializing the lazy module providing it on first use. It reports false when that
+// module fails to initialize.
 func (app *StdApplication) GetServiceEntry(serviceName string) (*ServiceRegistryEntry, bool) {
+	app.registryMu.RLock()
+	var (
+		entry *ServiceRegistryEntry
+		ok    bool
+	)
 	if app.enhancedSvcRegistry != nil {
-		return app.enhancedSvcRegistry.GetServiceEntry(serviceName)
+		entry, ok = app.enhancedSvcRegistry.GetServiceEntry(serviceName)
+	}
+	app.registryMu.RUnlock()
+	if !ok {
+		return nil, false
 	}
-	return nil, false
+	entry, err := app.resolveEntry(entry)
+	return entry, err == nil
 }
 
-// GetServicesByInterface returns all services that implement the given interface
+// GetServicesByInterface returns all services that implement the given interface,
+// initializing the lazy modules providing them. Services of lazy modules that fail
+// to initialize are left out.
 func (app *StdApplication) GetServicesByInterface(interfaceType reflect.Type) []*ServiceRegistryEntry {
-	if app.enhancedSvcRegistry != nil {
-		return app.enhancedSvcRegistry.GetServicesByInterface(interfaceType)
+	app.registryMu.RLock()
+	if app.enhancedSvcRegistry == nil {
+		app.registryMu.RUnlock()
+		return nil
 	}
-	return nil
+	entries := app.enhancedSvcRegistry.GetServicesByInterface(interfaceType)
+	app.registryMu.RUnlock()
+	resolved := entries[:0]
+	for _, entry := range entries {
+		resolvedEntry, err := app.resolveEntry(entry)
+		if err != nil {
+			app.logger.Warn("Skipping service of lazy module", "service", entry.ActualName, "error", err)
+			continue
+		}
+		resolved = append(resolved, resolvedEntry)
+	}
+	return resolved
 }
 
 // StartTime returns the time when the application was started
@@ -1637,12 +2050,16 @@ func (app *StdApplication) StartTime() time.Time {
 
 // GetModule returns the module with the given name, or nil if not found
 func (app *StdApplication) GetModule(name string) Module {
+	app.registryMu.RLock()
+	defer app.registryMu.RUnlock()
 	return app.moduleRegistry[name]
 }
 
 // GetAllModules returns a map of all registered modules by name.
 // Returns a copy to prevent external modification of the module registry.
 func (app *StdApplication) GetAllModules() map[string]Module {
+	app.registryMu.RLock()
+	defer app.registryMu.RUnlock()
 	result := make(map[string]Module, len(app.moduleRegistry))
 	for k, v := range app.moduleRegistry {
 		result[k] = v
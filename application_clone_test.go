@@ -0,0 +1,83 @@
+package modular
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greeterConfig struct {
+	Greeting string `yaml:"greeting" default:"hello"`
+	Name     string `yaml:"name" required:"true"`
+}
+
+// greeterModule registers a config section and provides its greeting as a service.
+type greeterModule struct {
+	greeting string
+}
+
+func (m *greeterModule) Name() string { return "greeter" }
+
+func (m *greeterModule) RegisterConfig(app Application) error {
+	app.RegisterConfigSection("greeter", NewStdConfigProvider(&greeterConfig{Greeting: "hi", Name: "template"}))
+	return nil
+}
+
+func (m *greeterModule) Init(app Application) error {
+	section, err := app.GetConfigSection("greeter")
+	if err != nil {
+		return err
+	}
+	cfg := section.GetConfig().(*greeterConfig)
+	m.greeting = cfg.Greeting + " " + cfg.Name
+	return nil
+}
+
+func (m *greeterModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{{Name: "greeting", Instance: m}}
+}
+
+func (m *greeterModule) RequiresServices() []ServiceDependency { return nil }
+
+func TestCloneForTest_OverridesConfigAndIsolatesServices(t *testing.T) {
+	template := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	template.RegisterModule(&greeterModule{})
+	require.NoError(t, template.RegisterService("fixture", "template-only"))
+
+	tests := []struct {
+		name      string
+		overrides map[string]any
+		expected  string
+	}{
+		{"registered config", nil, "hi template"},
+		{"config struct override", map[string]any{"greeter": &greeterConfig{Name: "alice"}}, "hello alice"},
+		{"provider override", map[string]any{"greeter": NewStdConfigProvider(&greeterConfig{Greeting: "hey", Name: "bob"})}, "hey bob"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := template.(TestCloneable).CloneForTest(tt.overrides)
+			require.NoError(t, app.Init())
+
+			var greeter *greeterModule
+			require.NoError(t, app.GetService("greeting", &greeter))
+			assert.Equal(t, tt.expected, greeter.greeting)
+
+			var fixture string
+			require.ErrorIs(t, app.GetService("fixture", &fixture), ErrServiceNotFound, "services are not copied")
+			assert.NotSame(t, template.ConfigProvider().GetConfig(), app.ConfigProvider().GetConfig())
+		})
+	}
+
+	assert.Empty(t, template.GetModule("greeter").(*greeterModule).greeting, "the template's module is not initialized")
+}
+
+func TestCloneForTest_InvalidOverrideFailsInit(t *testing.T) {
+	template := NewObservableApplication(NewStdConfigProvider(&testCfg{}), &testLogger{})
+	template.RegisterModule(&greeterModule{})
+
+	app := template.CloneForTest(map[string]any{"greeter": &greeterConfig{}})
+	_, observable := app.(*ObservableApplication)
+	assert.True(t, observable)
+	require.ErrorIs(t, app.Init(), ErrConfigRequiredFieldMissing)
+}
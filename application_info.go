@@ -0,0 +1,262 @@
+package modular
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// InfoPath is the conventional path for mounting NewInfoHandler.
+const InfoPath = "/__info"
+
+// InfoServiceName is the name of the InfoProvider service every StdApplication registers.
+const InfoServiceName = "app.info"
+
+// InfoProvider is implemented by applications that can describe their build and runtime.
+// StdApplication and ObservableApplication implement it, and register an InfoProvider
+// as the InfoServiceName service so modules can depend on it.
+type InfoProvider interface {
+	// Info returns a snapshot of the application's build and runtime information
+	Info() AppInfo
+}
+
+var (
+	_ InfoProvider = (*StdApplication)(nil)
+	_ InfoProvider = (*ObservableApplication)(nil)
+)
+
+// AppInfo describes a running application: the framework and module versions, the Go
+// runtime, the build's version control metadata and when the application started.
+type AppInfo struct {
+	// FrameworkVersion is the version of the modular module the binary was built with
+	FrameworkVersion string `json:"framework_version"`
+	// Modules lists the registered modules, sorted by name
+	Modules []ModuleInfo `json:"modules"`
+	Runtime RuntimeInfo  `json:"runtime"`
+	Build   BuildInfo    `json:"build"`
+	// StartedAt is when Start was called; zero until the application starts
+	StartedAt time.Time `json:"started_at,omitzero"`
+	// UptimeSeconds is the time since StartedAt
+	UptimeSeconds float64 `json:"uptime_seconds,omitempty"`
+}
+
+// ModuleInfo identifies a registered module and the Go module it was built from.
+type ModuleInfo struct {
+	Name string `json:"name"`
+	// Path is the Go module path providing the module's type
+	Path string `json:"path,omitempty"`
+	// Version is the Go module version, "(devel)" for the main module or local replacements
+	Version string `json:"version,omitempty"`
+}
+
+// RuntimeInfo describes the Go runtime the application runs on.
+type RuntimeInfo struct {
+	GoVersion  string `json:"go_version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+	NumCPU     int    `json:"num_cpu"`
+	GOMAXPROCS int    `json:"gomaxprocs"`
+	Goroutines int    `json:"goroutines"`
+}
+
+// BuildInfo is the build metadata embedded in the binary by the Go toolchain.
+type BuildInfo struct {
+	// Path is the main module's path
+	Path string `json:"path,omitempty"`
+	// Version is the main module's version
+	Version string `json:"version,omitempty"`
+	// VCS is the version control system the binary was built from, such as "git"
+	VCS string `json:"vcs,omitempty"`
+	// Revision is the commit the binary was built from
+	Revision string `json:"revision,omitempty"`
+	// Time is the commit time
+	Time string `json:"time,omitempty"`
+	// Modified reports whether the working tree had uncommitted changes
+	Modified bool `json:"modified"`
+}
+
+// readBuildInfo is the build information of the running binary, read once.
+var readBuildInfo = sync.OnceValue(func() *debug.BuildInfo {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	return info
+})
+
+// frameworkModulePath is the Go module path of this package.
+var frameworkModulePath = reflect.TypeOf(StdApplication{}).PkgPath()
+
+// Info returns the application's build and runtime information.
+func (app *StdApplication) Info() AppInfo {
+	buildInfo := readBuildInfo()
+	info := baseAppInfo(buildInfo)
+	for _, module := range app.GetAllModules() {
+		info.Modules = append(info.Modules, moduleInfo(buildInfo, module))
+	}
+	sort.Slice(info.Modules, func(i, j int) bool { return info.Modules[i].Name < info.Modules[j].Name })
+	if !app.startTime.IsZero() {
+		info.StartedAt = app.startTime
+		info.UptimeSeconds = time.Since(app.startTime).Seconds()
+	}
+	return info
+}
+
+// InfoFor returns the build and runtime information of app. Applications that do not
+// implement InfoProvider get the runtime and build information without modules.
+func InfoFor(app Application) AppInfo {
+	if provider, ok := app.(InfoProvider); ok {
+		return provider.Info()
+	}
+	return baseAppInfo(readBuildInfo())
+}
+
+// baseAppInfo returns the parts of AppInfo that don't depend on the application.
+func baseAppInfo(buildInfo *debug.BuildInfo) AppInfo {
+	return AppInfo{
+		FrameworkVersion: goModuleVersion(buildInfo, frameworkModulePath).Version,
+		Modules:          []ModuleInfo{},
+		Runtime: RuntimeInfo{
+			GoVersion:  runtime.Version(),
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+			NumCPU:     runtime.NumCPU(),
+			GOMAXPROCS: runtime.GOMAXPROCS(0),
+			Goroutines: runtime.NumGoroutine(),
+		},
+		Build: readBuild(buildInfo),
+	}
+}
+
+// infoService is the InfoServiceName service. It only exposes Info, so interface-based
+// service matching can't mistake the application for another service.
+type infoService struct {
+	app *StdApplication
+}
+
+func (s infoService) Info() AppInfo {
+	return s.app.Info()
+}
+
+// NewInfoHandler serves the provider's AppInfo as JSON. Mount it at InfoPath:
+//
+//	router.Handle(modular.InfoPath, modular.NewInfoHandler(app))
+func NewInfoHandler(provider InfoProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(provider.Info())
+	})
+}
+
+// moduleInfo looks up the Go module providing module's type.
+func moduleInfo(buildInfo *debug.BuildInfo, module Module) ModuleInfo {
+	moduleType := reflect.TypeOf(module)
+	for moduleType != nil && moduleType.Kind() == reflect.Pointer {
+		moduleType = moduleType.Elem()
+	}
+	info := ModuleInfo{Name: module.Name()}
+	if moduleType != nil && moduleType.PkgPath() != "" {
+		goModule := goModuleVersion(buildInfo, moduleType.PkgPath())
+		info.Path, info.Version = goModule.Path, goModule.Version
+	}
+	return info
+}
+
+// goModuleVersion returns the path and version of the Go module containing pkgPath,
+// following replace directives. It returns an empty module when it is unknown.
+func goModuleVersion(buildInfo *debug.BuildInfo, pkgPath string) debug.Module {
+	if buildInfo == nil {
+		return debug.Module{}
+	}
+	best := debug.Module{}
+	consider := func(module *debug.Module) {
+		if module == nil || len(module.Path) <= len(best.Path) {
+			return
+		}
+		if pkgPath != module.Path && !strings.HasPrefix(pkgPath, module.Path+"/") {
+			return
+		}
+		best = debug.Module{Path: module.Path, Version: module.Version}
+		if module.Replace != nil {
+			best.Version = module.Replace.Version
+		}
+		if best.Version == "" {
+			best.Version = "(devel)"
+		}
+	}
+	consider(&buildInfo.Main)
+	for _, dep := range buildInfo.Deps {
+		consider(dep)
+	}
+	return best
+}
+
+// readBuild extracts the main module and version control settings.
+func readBuild(buildInfo *debug.BuildInfo) BuildInfo {
+	if buildInfo == nil {
+		return BuildInfo{}
+	}
+	build := BuildInfo{Path: buildInfo.Main.Path, Version: buildInfo.Main.Version}
+	for _, setting := range buildInfo.Settings {
+		switch setting.Key {
+		case "vcs":
+			build.VCS = setting.Value
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.time":
+			build.Time = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// logStartupBanner logs a one-line summary of what started: framework version,
+// modules, Go runtime and build revision.
+func (app *StdApplication) logStartupBanner() {
+	info := app.Info()
+	modules := make([]string, 0, len(info.Modules))
+	for _, module := range info.Modules {
+		if module.Version != "" {
+			modules = append(modules, module.Name+"@"+module.Version)
+		} else {
+			modules = append(modules, module.Name)
+		}
+	}
+	revision := info.Build.Revision
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	if revision != "" && info.Build.Modified {
+		revision += "-dirty"
+	}
+	app.logger.Info("Application started",
+		"framework", info.FrameworkVersion,
+		"modules", strings.Join(modules, ","),
+		"go", info.Runtime.GoVersion,
+		"revision", revision,
+	)
+}
+
+// lifecycleMetadata is the AppInfo summary included in application lifecycle events.
+func (info AppInfo) lifecycleMetadata() map[string]interface{} {
+	modules := make(map[string]interface{}, len(info.Modules))
+	for _, module := range info.Modules {
+		modules[module.Name] = module.Version
+	}
+	return map[string]interface{}{
+		"framework_version": info.FrameworkVersion,
+		"go_version":        info.Runtime.GoVersion,
+		"modules":           modules,
+		"revision":          info.Build.Revision,
+		"modified":          info.Build.Modified,
+	}
+}
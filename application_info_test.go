@@ -0,0 +1,124 @@
+package modular
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdApplication_Info(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterModule(&lifecycleTestModule{testModule: testModule{name: "zeta"}})
+	app.RegisterModule(&lifecycleTestModule{testModule: testModule{name: "alpha"}})
+	require.NoError(t, app.Init())
+
+	info := app.(InfoProvider).Info()
+	require.Len(t, info.Modules, 2)
+	assert.Equal(t, "alpha", info.Modules[0].Name)
+	assert.Equal(t, "zeta", info.Modules[1].Name)
+	assert.Equal(t, frameworkModulePath, info.Modules[0].Path)
+	assert.Equal(t, runtime.Version(), info.Runtime.GoVersion)
+	assert.Equal(t, runtime.GOOS, info.Runtime.OS)
+	assert.True(t, info.StartedAt.IsZero(), "not started yet")
+
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	var provider InfoProvider
+	require.NoError(t, app.GetService(InfoServiceName, &provider))
+	info = provider.Info()
+	assert.False(t, info.StartedAt.IsZero())
+	assert.GreaterOrEqual(t, info.UptimeSeconds, 0.0)
+
+	rec := httptest.NewRecorder()
+	NewInfoHandler(provider).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, InfoPath, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var served map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Contains(t, served, "framework_version")
+	assert.Len(t, served["modules"], 2)
+	assert.Equal(t, runtime.Version(), served["runtime"].(map[string]any)["go_version"])
+}
+
+func TestGoModuleVersion(t *testing.T) {
+	buildInfo := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/app", Version: "(devel)"},
+		Deps: []*debug.Module{
+			{Path: "github.com/CrisisTextLine/modular", Version: "v1.11.11"},
+			{Path: "github.com/CrisisTextLine/modular/modules/reverseproxy/v2", Version: "v2.3.0"},
+			{Path: "github.com/CrisisTextLine/modular/modules/cache", Version: "v0.4.0", Replace: &debug.Module{Path: "../cache"}},
+		},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "4f2a9c1"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	tests := []struct {
+		pkgPath string
+		path    string
+		version string
+	}{
+		{"github.com/CrisisTextLine/modular", "github.com/CrisisTextLine/modular", "v1.11.11"},
+		{"github.com/CrisisTextLine/modular/modules/reverseproxy/v2", "github.com/CrisisTextLine/modular/modules/reverseproxy/v2", "v2.3.0"},
+		{"github.com/CrisisTextLine/modular/modules/cache", "github.com/CrisisTextLine/modular/modules/cache", "(devel)"},
+		{"github.com/CrisisTextLine/modular/feeders", "github.com/CrisisTextLine/modular", "v1.11.11"},
+		{"example.com/app/internal/orders", "example.com/app", "(devel)"},
+		{"github.com/CrisisTextLine/modularity", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkgPath, func(t *testing.T) {
+			module := goModuleVersion(buildInfo, tt.pkgPath)
+			assert.Equal(t, tt.path, module.Path)
+			assert.Equal(t, tt.version, module.Version)
+		})
+	}
+
+	build := readBuild(buildInfo)
+	assert.Equal(t, BuildInfo{Path: "example.com/app", Version: "(devel)", VCS: "git", Revision: "4f2a9c1", Modified: true}, build)
+}
+
+func TestObservableApplication_LifecycleEventsIncludeInfo(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterModule(&lifecycleTestModule{testModule: testModule{name: "worker"}})
+
+	var mu sync.Mutex
+	var started []ModuleLifecyclePayload
+	require.NoError(t, app.RegisterObserver(NewFunctionalObserver("info", func(ctx context.Context, event cloudevents.Event) error {
+		var payload ModuleLifecyclePayload
+		if err := event.DataAs(&payload); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		started = append(started, payload)
+		return nil
+	}), EventTypeApplicationStarted))
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(started) == 1
+	}, time.Second, 5*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	info := app.Info()
+	assert.Equal(t, info.FrameworkVersion, started[0].Version)
+	assert.Equal(t, runtime.Version(), started[0].Metadata["go_version"])
+	assert.Contains(t, started[0].Metadata["modules"], "worker")
+}
@@ -13,6 +13,26 @@ type observerRegistration struct {
 	observer     Observer
 	eventTypes   map[string]bool // set of event types this observer is interested in
 	registeredAt time.Time
+	stats        observerStats
+
+	// patterns are the wildcard event type patterns this observer is interested in
+	patterns []string
+}
+
+// wants reports whether the observer is interested in the event type.
+func (r *observerRegistration) wants(eventType string) bool {
+	if len(r.eventTypes) == 0 && len(r.patterns) == 0 {
+		return true
+	}
+	if r.eventTypes[eventType] {
+		return true
+	}
+	for _, pattern := range r.patterns {
+		if matchEventType(pattern, eventType) {
+			return true
+		}
+	}
+	return false
 }
 
 // ObservableApplication extends StdApplication with observer pattern capabilities.
@@ -22,29 +42,48 @@ type ObservableApplication struct {
 	*StdApplication
 	observers     map[string]*observerRegistration // key is observer ID
 	observerMutex sync.RWMutex
+	// moduleObservers holds the observers each module registered, by module name
+	moduleObservers map[string][]Observer
+	dispatcher      *eventDispatcher // optional buffered delivery, see WithEventDispatcher
 }
 
 // NewObservableApplication creates a new application instance with observer pattern support.
 // This wraps the standard application with observer capabilities while maintaining
 // all existing functionality.
-func NewObservableApplication(cp ConfigProvider, logger Logger) *ObservableApplication {
+//
+// By default each asynchronous notification is delivered in its own goroutine per
+// observer. Pass WithEventDispatcher to use a bounded queue and worker pool instead.
+func NewObservableApplication(cp ConfigProvider, logger Logger, opts ...ObservableOption) *ObservableApplication {
 	stdApp := NewStdApplication(cp, logger).(*StdApplication)
-	return &ObservableApplication{
-		StdApplication: stdApp,
-		observers:      make(map[string]*observerRegistration),
+	app := &ObservableApplication{
+		StdApplication:  stdApp,
+		observers:       make(map[string]*observerRegistration),
+		moduleObservers: make(map[string][]Observer),
+	}
+	stdApp.enhancedSvcRegistry.onChange = app.emitServiceChange
+	for _, opt := range opts {
+		opt(app)
 	}
+	return app
 }
 
 // RegisterObserver adds an observer to receive notifications from the application.
-// Observers can optionally filter events by type using the eventTypes parameter.
-// If eventTypes is empty, the observer receives all events.
+// Observers can optionally filter events by type using the eventTypes parameter,
+// which accepts exact event types and wildcard patterns (see EventTypeMatches).
+// Only matching events are dispatched to the observer. If eventTypes is empty,
+// the observer receives all events.
 func (app *ObservableApplication) RegisterObserver(observer Observer, eventTypes ...string) error {
 	app.observerMutex.Lock()
 	defer app.observerMutex.Unlock()
 
-	// Convert event types slice to map for O(1) lookups
+	// Convert event types slice to map for O(1) lookups, keeping patterns apart
 	eventTypeMap := make(map[string]bool)
+	var patterns []string
 	for _, eventType := range eventTypes {
+		if isEventTypePattern(eventType) {
+			patterns = append(patterns, eventType)
+			continue
+		}
 		eventTypeMap[eventType] = true
 	}
 
@@ -52,6 +91,7 @@ func (app *ObservableApplication) RegisterObserver(observer Observer, eventTypes
 		observer:     observer,
 		eventTypes:   eventTypeMap,
 		registeredAt: time.Now(),
+		patterns:     patterns,
 	}
 
 	app.logger.Info("Observer registered", "observerID", observer.ObserverID(), "eventTypes", eventTypes)
@@ -74,10 +114,9 @@ func (app *ObservableApplication) UnregisterObserver(observer Observer) error {
 
 // NotifyObservers sends a CloudEvent to all registered observers.
 // The notification process is non-blocking for the caller and handles observer errors gracefully.
+// When an event dispatcher is configured, asynchronous notifications are queued
+// and delivered by its worker pool according to its overflow policy.
 func (app *ObservableApplication) NotifyObservers(ctx context.Context, event cloudevents.Event) error {
-	app.observerMutex.RLock()
-	defer app.observerMutex.RUnlock()
-
 	// Ensure timestamp is set
 	if event.Time().IsZero() {
 		event.SetTime(time.Now())
@@ -90,40 +129,63 @@ func (app *ObservableApplication) NotifyObservers(ctx context.Context, event clo
 	}
 
 	// If the context requests synchronous delivery, invoke observers directly.
-	// Otherwise, notify observers in goroutines to avoid blocking.
+	// Otherwise, hand the event to the dispatcher or notify observers in goroutines to avoid blocking.
 	synchronous := IsSynchronousNotification(ctx)
+	if !synchronous && app.dispatcher != nil {
+		app.dispatcher.enqueue(ctx, event)
+		return nil
+	}
+
+	app.observerMutex.RLock()
+	defer app.observerMutex.RUnlock()
+
 	for _, registration := range app.observers {
 		registration := registration // capture for goroutine
 
 		// Check if observer is interested in this event type
-		if len(registration.eventTypes) > 0 && !registration.eventTypes[event.Type()] {
+		if !registration.wants(event.Type()) {
 			continue // observer not interested in this event type
 		}
 
-		notify := func() {
-			defer func() {
-				if r := recover(); r != nil {
-					app.logger.Error("Observer panicked", "observerID", registration.observer.ObserverID(), "event", event.Type(), "panic", r)
-				}
-			}()
-
-			if err := registration.observer.OnEvent(ctx, event); err != nil {
-				app.logger.Error("Observer error", "observerID", registration.observer.ObserverID(), "event", event.Type(), "error", err)
-			}
-		}
-
 		if synchronous {
-			notify()
+			app.notifyObserver(registration, ctx, event)
 		} else {
-			go notify()
+			go app.notifyObserver(registration, ctx, event)
 		}
 	}
 
 	return nil
 }
 
+// notifyObserver delivers a single event to an observer, recovering from panics
+// and recording the outcome in the observer's delivery stats.
+func (app *ObservableApplication) notifyObserver(registration *observerRegistration, ctx context.Context, event cloudevents.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			registration.stats.failed.Add(1)
+			app.logger.Error("Observer panicked", "observerID", registration.observer.ObserverID(), "event", event.Type(), "panic", r)
+		}
+	}()
+
+	if err := registration.observer.OnEvent(ctx, event); err != nil {
+		registration.stats.failed.Add(1)
+		app.logger.Error("Observer error", "observerID", registration.observer.ObserverID(), "event", event.Type(), "error", err)
+		return
+	}
+	registration.stats.delivered.Add(1)
+}
+
 // emitEvent is a helper method to emit CloudEvents with proper source information
 func (app *ObservableApplication) emitEvent(ctx context.Context, event cloudevents.Event) {
+	// The dispatcher queues without spawning goroutines; keeping emission inline
+	// also guarantees lifecycle events are queued before the dispatcher drains on Stop.
+	if app.dispatcher != nil && !IsSynchronousNotification(ctx) {
+		if err := app.NotifyObservers(ctx, event); err != nil {
+			app.logger.Error("Failed to notify observers", "event", event.Type(), "error", err)
+		}
+		return
+	}
+
 	// Use a separate goroutine to avoid blocking application operations
 	go func() {
 		if err := app.NotifyObservers(ctx, event); err != nil {
@@ -140,10 +202,11 @@ func (app *ObservableApplication) GetObservers() []ObserverInfo {
 
 	info := make([]ObserverInfo, 0, len(app.observers))
 	for _, registration := range app.observers {
-		eventTypes := make([]string, 0, len(registration.eventTypes))
+		eventTypes := make([]string, 0, len(registration.eventTypes)+len(registration.patterns))
 		for eventType := range registration.eventTypes {
 			eventTypes = append(eventTypes, eventType)
 		}
+		eventTypes = append(eventTypes, registration.patterns...)
 
 		info = append(info, ObserverInfo{
 			ID:           registration.observer.ObserverID(),
@@ -163,26 +226,34 @@ func (app *ObservableApplication) RegisterModule(module Module) {
 
 	// Emit synchronously so tests observing immediate module registration are reliable.
 	ctx := WithSynchronousNotification(context.Background())
-	evt := NewModuleLifecycleEvent("application", "module", module.Name(), "", "registered", map[string]interface{}{
+	evt := NewModuleLifecycleEvent("application", "module", module.Name(), moduleInfo(readBuildInfo(), module).Version, "registered", map[string]interface{}{
 		"moduleType": getTypeName(module),
 	})
 	app.emitEvent(ctx, evt)
 }
 
-// RegisterService registers a service and emits CloudEvent
-func (app *ObservableApplication) RegisterService(name string, service any) error {
-	err := app.StdApplication.RegisterService(name, service)
-	if err != nil {
-		return err
+// emitServiceChange emits EventTypeServiceRegistered for new services and
+// EventTypeServiceReplaced when a registration overrides an existing service.
+// It is installed as the registry's change hook, so services provided by modules
+// during Init are reported as well as direct RegisterService calls.
+func (app *ObservableApplication) emitServiceChange(entry, previous *ServiceRegistryEntry) {
+	data := map[string]interface{}{
+		"serviceName": entry.OriginalName,
+		"actualName":  entry.ActualName,
+		"serviceType": getTypeName(entry.Service),
+		"moduleName":  entry.ModuleName,
 	}
 
-	evt := NewCloudEvent(EventTypeServiceRegistered, "application", map[string]interface{}{
-		"serviceName": name,
-		"serviceType": getTypeName(service),
-	}, nil)
-	app.emitEvent(context.Background(), evt)
+	eventType := EventTypeServiceRegistered
+	if previous != nil {
+		eventType = EventTypeServiceReplaced
+		data["previousModuleName"] = previous.ModuleName
+		data["previousServiceType"] = getTypeName(previous.Service)
+		app.logger.Info("Service replaced", "name", entry.ActualName,
+			"module", entry.ModuleName, "previousModule", previous.ModuleName)
+	}
 
-	return nil
+	app.emitEvent(context.Background(), NewCloudEvent(eventType, "application", data, nil))
 }
 
 // Init initializes the application and emits lifecycle events
@@ -205,11 +276,9 @@ func (app *ObservableApplication) Init() error {
 	// Register observers for any ObservableModule instances BEFORE calling module Init()
 	for _, module := range app.moduleRegistry {
 		app.logger.Debug("Checking module for ObservableModule interface", "module", module.Name())
-		if observableModule, ok := module.(ObservableModule); ok {
+		if _, ok := module.(ObservableModule); ok {
 			app.logger.Debug("ObservableApplication registering observers for module", "module", module.Name())
-			if err := observableModule.RegisterObservers(app); err != nil {
-				app.logger.Error("Failed to register observers for module", "module", module.Name(), "error", err)
-			}
+			app.registerModuleObservers(module)
 		} else {
 			app.logger.Debug("Module does not implement ObservableModule", "module", module.Name())
 		}
@@ -239,6 +308,10 @@ func (app *ObservableApplication) Init() error {
 func (app *ObservableApplication) Start() error {
 	ctx := context.Background()
 
+	if app.dispatcher != nil {
+		app.dispatcher.start()
+	}
+
 	err := app.StdApplication.Start()
 	if err != nil {
 		failureEvt := NewModuleLifecycleEvent("application", "application", "", "", "failed", map[string]interface{}{"phase": "start", "error": err.Error()})
@@ -247,7 +320,8 @@ func (app *ObservableApplication) Start() error {
 	}
 
 	// Emit application started event
-	startedEvt := NewModuleLifecycleEvent("application", "application", "", "", "started", nil)
+	info := app.Info()
+	startedEvt := NewModuleLifecycleEvent("application", "application", "", info.FrameworkVersion, "started", info.lifecycleMetadata())
 	app.emitEvent(ctx, startedEvt)
 
 	return nil
@@ -261,16 +335,34 @@ func (app *ObservableApplication) Stop() error {
 	if err != nil {
 		failureEvt := NewModuleLifecycleEvent("application", "application", "", "", "failed", map[string]interface{}{"phase": "stop", "error": err.Error()})
 		app.emitEvent(ctx, failureEvt)
+		app.drainDispatcher()
 		return err
 	}
 
 	// Emit application stopped event
-	stoppedEvt := NewModuleLifecycleEvent("application", "application", "", "", "stopped", nil)
+	info := app.Info()
+	stoppedEvt := NewModuleLifecycleEvent("application", "application", "", info.FrameworkVersion, "stopped", info.lifecycleMetadata())
 	app.emitEvent(ctx, stoppedEvt)
 
+	app.drainDispatcher()
+
 	return nil
 }
 
+// drainDispatcher delivers queued events before Stop returns; events emitted
+// afterwards are dropped.
+func (app *ObservableApplication) drainDispatcher() {
+	if app.dispatcher != nil && !app.dispatcher.close() {
+		app.logger.Warn("Timed out delivering queued events", "timeout", app.dispatcher.config.DrainTimeout)
+	}
+}
+
+// Run initializes and starts the application with lifecycle events, blocks until
+// termination and then stops it.
+func (app *ObservableApplication) Run() error {
+	return app.runAs(app)
+}
+
 // getTypeName returns the type name of an interface{} value
 func getTypeName(v interface{}) string {
 	if v == nil {
@@ -286,3 +378,48 @@ func getTypeName(v interface{}) string {
 		return "unknown"
 	}
 }
+
+// registerModuleObservers lets an ObservableModule register its observers, recording
+// them so replaceModuleObservers can remove them again.
+func (app *ObservableApplication) registerModuleObservers(module Module) {
+	observable, ok := module.(ObservableModule)
+	if !ok {
+		return
+	}
+	subject := &moduleObserverSubject{ObservableApplication: app, module: module.Name()}
+	if err := observable.RegisterObservers(subject); err != nil {
+		app.logger.Error("Failed to register observers for module", "module", module.Name(), "error", err)
+	}
+}
+
+// replaceModuleObservers unregisters the observers the named module registered and
+// lets module, its new instance, register its own.
+func (app *ObservableApplication) replaceModuleObservers(name string, module Module) {
+	app.observerMutex.Lock()
+	previous := app.moduleObservers[name]
+	delete(app.moduleObservers, name)
+	app.observerMutex.Unlock()
+	for _, observer := range previous {
+		_ = app.UnregisterObserver(observer)
+	}
+	app.registerModuleObservers(module)
+}
+
+// moduleObserverSubject is the Subject given to a module's RegisterObservers. It
+// records the observers the module registers.
+type moduleObserverSubject struct {
+	*ObservableApplication
+	module string
+}
+
+// RegisterObserver registers the observer with the application and records it
+// for the module.
+func (s *moduleObserverSubject) RegisterObserver(observer Observer, eventTypes ...string) error {
+	if err := s.ObservableApplication.RegisterObserver(observer, eventTypes...); err != nil {
+		return err
+	}
+	s.observerMutex.Lock()
+	s.moduleObservers[s.module] = append(s.moduleObservers[s.module], observer)
+	s.observerMutex.Unlock()
+	return nil
+}
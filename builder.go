@@ -2,6 +2,8 @@ package modular
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 )
@@ -18,9 +20,31 @@ type ApplicationBuilder struct {
 	configDecorators  []ConfigDecorator
 	observers         []ObserverFunc
 	tenantLoader      TenantLoader
+	observableOptions []ObservableOption
+	profileOptions    *ProfileOptions
+	conflictPolicy    *ServiceConflictPolicy
+	shutdownPhases    map[string]ShutdownPhase
+	lazyModules       []string
+	startupTimeout    time.Duration
+	moduleTimeouts    map[string]ModuleTimeouts
+	defaultTimeouts   *ModuleTimeouts
+	configSections    map[string]ConfigProvider
+	configValues      []configValue
+	strictConfig      StrictConfigMode
+	instrumentation   bool
+	contextAudit      *ContextAuditConfig
+	metrics           MetricsRegistry
+	metricsExporters  []*metricsExport
 	enableObserver    bool
 	enableTenant      bool
 	configLoadedHooks []func(Application) error // Hooks to run after config loading
+	lifecycleHooks    []lifecycleHookRegistration
+}
+
+// lifecycleHookRegistration is a hook registered with WithLifecycleHook.
+type lifecycleHookRegistration struct {
+	phase LifecyclePhase
+	hook  LifecycleHook
 }
 
 // ObserverFunc is a functional observer that can be registered with the application
@@ -67,12 +91,120 @@ func (b *ApplicationBuilder) Build() (Application, error) {
 
 		// Create base application
 		if b.enableObserver {
-			app = NewObservableApplication(b.configProvider, b.logger)
+			app = NewObservableApplication(b.configProvider, b.logger, b.observableOptions...)
 		} else {
 			app = NewStdApplication(b.configProvider, b.logger)
 		}
 	}
 
+	if b.profileOptions != nil {
+		if profiled, ok := app.(interface{ SetProfileOptions(ProfileOptions) }); ok {
+			profiled.SetProfileOptions(*b.profileOptions)
+		}
+	}
+
+	if b.conflictPolicy != nil {
+		if policied, ok := app.(interface{ SetServiceConflictPolicy(ServiceConflictPolicy) }); ok {
+			policied.SetServiceConflictPolicy(*b.conflictPolicy)
+		}
+	}
+
+	if len(b.shutdownPhases) > 0 {
+		if phased, ok := app.(interface {
+			SetShutdownPhase(string, ShutdownPhase)
+		}); ok {
+			for name, phase := range b.shutdownPhases {
+				phased.SetShutdownPhase(name, phase)
+			}
+		}
+	}
+
+	if len(b.lifecycleHooks) > 0 {
+		hooked, ok := app.(interface {
+			OnLifecycle(LifecyclePhase, ...LifecycleHook)
+		})
+		if !ok {
+			return nil, fmt.Errorf("%w: %T does not support lifecycle hooks", ErrLifecycleHooksUnsupported, app)
+		}
+		for _, registration := range b.lifecycleHooks {
+			hooked.OnLifecycle(registration.phase, registration.hook)
+		}
+	}
+
+	if len(b.lazyModules) > 0 {
+		if lazy, ok := app.(interface{ SetLazyInit(string, bool) }); ok {
+			for _, name := range b.lazyModules {
+				lazy.SetLazyInit(name, true)
+			}
+		}
+	}
+
+	if b.startupTimeout > 0 || len(b.moduleTimeouts) > 0 || b.defaultTimeouts != nil {
+		if bounded, ok := app.(interface {
+			SetStartupTimeout(time.Duration)
+			SetModuleTimeouts(string, ModuleTimeouts)
+			SetDefaultModuleTimeouts(ModuleTimeouts)
+		}); ok {
+			bounded.SetStartupTimeout(b.startupTimeout)
+			for name, timeouts := range b.moduleTimeouts {
+				bounded.SetModuleTimeouts(name, timeouts)
+			}
+			if b.defaultTimeouts != nil {
+				bounded.SetDefaultModuleTimeouts(*b.defaultTimeouts)
+			}
+		}
+	}
+
+	if len(b.configSections) > 0 || len(b.configValues) > 0 {
+		if overridable, ok := app.(interface {
+			SetConfigOverride(string, ConfigProvider)
+			SetConfigValue(string, string, any)
+		}); ok {
+			for name, provider := range b.configSections {
+				overridable.SetConfigOverride(name, provider)
+			}
+			for _, cv := range b.configValues {
+				overridable.SetConfigValue(cv.section, cv.key, cv.value)
+			}
+		}
+	}
+
+	if b.strictConfig != StrictConfigOff {
+		if strict, ok := app.(interface{ SetStrictConfig(StrictConfigMode) }); ok {
+			strict.SetStrictConfig(b.strictConfig)
+		}
+	}
+
+	if b.instrumentation {
+		if instrumented, ok := app.(interface{ SetServiceInstrumentation(bool) }); ok {
+			instrumented.SetServiceInstrumentation(true)
+		}
+	}
+
+	if b.contextAudit != nil {
+		if audited, ok := app.(interface {
+			EnableContextAudit(ContextAuditConfig) *ContextAuditor
+		}); ok {
+			audited.EnableContextAudit(*b.contextAudit)
+		}
+	}
+
+	if b.metrics != nil {
+		if measured, ok := app.(interface{ SetMetrics(MetricsRegistry) }); ok {
+			measured.SetMetrics(b.metrics)
+		}
+	}
+
+	if len(b.metricsExporters) > 0 {
+		if exporting, ok := app.(interface {
+			AddMetricsExporter(MetricsExporter, time.Duration)
+		}); ok {
+			for _, export := range b.metricsExporters {
+				exporting.AddMetricsExporter(export.exporter, export.interval)
+			}
+		}
+	}
+
 	// Apply config decorators to the base config provider
 	if len(b.configDecorators) > 0 {
 		decoratedProvider := b.configProvider
@@ -159,6 +291,173 @@ func WithObserver(observers ...ObserverFunc) Option {
 	}
 }
 
+// WithObservableOptions enables the observer pattern and applies options to the
+// underlying ObservableApplication, such as WithEventDispatcher.
+func WithObservableOptions(opts ...ObservableOption) Option {
+	return func(b *ApplicationBuilder) error {
+		b.enableObserver = true
+		b.observableOptions = append(b.observableOptions, opts...)
+		return nil
+	}
+}
+
+// WithProfiles enables profile-layered configuration loading, see ProfileOptions.
+func WithProfiles(options ProfileOptions) Option {
+	return func(b *ApplicationBuilder) error {
+		b.profileOptions = &options
+		return nil
+	}
+}
+
+// WithServiceConflictPolicy sets how the application handles a service registered
+// under a name that is already in use. Use ServiceConflictError to turn accidental
+// overrides into Init errors; modules can still replace services deliberately with
+// ServiceProvider.AllowOverride.
+func WithServiceConflictPolicy(policy ServiceConflictPolicy) Option {
+	return func(b *ApplicationBuilder) error {
+		b.conflictPolicy = &policy
+		return nil
+	}
+}
+
+// WithShutdownPhase annotates the named module with the phase it stops in. See ShutdownPhase.
+func WithShutdownPhase(moduleName string, phase ShutdownPhase) Option {
+	return func(b *ApplicationBuilder) error {
+		if b.shutdownPhases == nil {
+			b.shutdownPhases = make(map[string]ShutdownPhase)
+		}
+		b.shutdownPhases[moduleName] = phase
+		return nil
+	}
+}
+
+// WithLazyInit marks the named modules to be initialized on first use rather than
+// during Init. See StdApplication.SetLazyInit.
+func WithLazyInit(moduleNames ...string) Option {
+	return func(b *ApplicationBuilder) error {
+		b.lazyModules = append(b.lazyModules, moduleNames...)
+		return nil
+	}
+}
+
+// WithStartupTimeout bounds application startup, from the beginning of Init to the
+// end of Start. See StdApplication.SetStartupTimeout.
+func WithStartupTimeout(timeout time.Duration) Option {
+	return func(b *ApplicationBuilder) error {
+		b.startupTimeout = timeout
+		return nil
+	}
+}
+
+// WithModuleTimeouts sets the Init and Start timeouts of the named module, so a
+// module hanging on an unreachable dependency fails startup with an error naming it:
+//
+//	app, err := modular.NewApplication(
+//	    modular.WithLogger(logger),
+//	    modular.WithModules(database.NewModule()),
+//	    modular.WithModuleTimeouts("database", modular.ModuleTimeouts{Init: 10 * time.Second}),
+//	)
+//
+// See StdApplication.SetModuleTimeouts.
+func WithModuleTimeouts(moduleName string, timeouts ModuleTimeouts) Option {
+	return func(b *ApplicationBuilder) error {
+		if b.moduleTimeouts == nil {
+			b.moduleTimeouts = make(map[string]ModuleTimeouts)
+		}
+		b.moduleTimeouts[moduleName] = timeouts
+		return nil
+	}
+}
+
+// WithDefaultModuleTimeouts sets the Init and Start timeouts of modules without
+// timeouts of their own. See StdApplication.SetDefaultModuleTimeouts.
+func WithDefaultModuleTimeouts(timeouts ModuleTimeouts) Option {
+	return func(b *ApplicationBuilder) error {
+		b.defaultTimeouts = &timeouts
+		return nil
+	}
+}
+
+// WithConfigSection sets the named config section to provider, replacing whatever
+// the module registered and feeders loaded for it, so tests and embedding code can
+// configure modules without files or environment variables:
+//
+//	app, err := modular.NewApplication(
+//	    modular.WithLogger(logger),
+//	    modular.WithModules(cache.NewModule()),
+//	    modular.WithConfigSection("cache", modular.NewStdConfigProvider(&cache.CacheConfig{Engine: "memory"})),
+//	)
+//
+// See StdApplication.SetConfigOverride.
+func WithConfigSection(name string, provider ConfigProvider) Option {
+	return func(b *ApplicationBuilder) error {
+		if b.configSections == nil {
+			b.configSections = make(map[string]ConfigProvider)
+		}
+		b.configSections[name] = provider
+		return nil
+	}
+}
+
+// WithConfigValue sets a single field of a config section after feeders and
+// WithConfigSection overrides are applied, e.g.
+// WithConfigValue("httpserver", "port", 0). The key is a dot-separated path of
+// field names or their yaml, json or toml tags. See StdApplication.SetConfigValue.
+func WithConfigValue(section, key string, value any) Option {
+	return func(b *ApplicationBuilder) error {
+		b.configValues = append(b.configValues, configValue{section: section, key: key, value: value})
+		return nil
+	}
+}
+
+// WithStrictConfig reports configuration file keys that do not map to any registered
+// config section or field, such as "requesttimeout" written for "request_timeout".
+// StrictConfigWarn logs each unknown key with the nearest known key; StrictConfigError
+// fails Init with ErrUnknownConfigKeys listing them. See StdApplication.SetStrictConfig.
+func WithStrictConfig(mode StrictConfigMode) Option {
+	return func(b *ApplicationBuilder) error {
+		b.strictConfig = mode
+		return nil
+	}
+}
+
+// WithServiceInstrumentation wraps services handed from one module to another in the
+// proxies registered with RegisterServiceProxy, recording per-method call counts,
+// errors and latency. See StdApplication.DescribeModules.
+func WithServiceInstrumentation() Option {
+	return func(b *ApplicationBuilder) error {
+		b.instrumentation = true
+		return nil
+	}
+}
+
+// WithContextAudit audits the contexts of HTTP handlers and service calls for
+// missing deadlines, context.Background and lost tenant or trace values. It enables
+// service instrumentation. See StdApplication.EnableContextAudit.
+func WithContextAudit(config ContextAuditConfig) Option {
+	return func(b *ApplicationBuilder) error {
+		b.contextAudit = &config
+		return nil
+	}
+}
+
+// WithMetrics replaces the application's default in-memory metrics registry.
+func WithMetrics(registry MetricsRegistry) Option {
+	return func(b *ApplicationBuilder) error {
+		b.metrics = registry
+		return nil
+	}
+}
+
+// WithMetricsExporter pushes the application's metrics to exporter every interval
+// while the application runs.
+func WithMetricsExporter(exporter MetricsExporter, interval time.Duration) Option {
+	return func(b *ApplicationBuilder) error {
+		b.metricsExporters = append(b.metricsExporters, &metricsExport{exporter: exporter, interval: interval})
+		return nil
+	}
+}
+
 // WithTenantAware enables tenant-aware functionality with the provided loader
 func WithTenantAware(loader TenantLoader) Option {
 	return func(b *ApplicationBuilder) error {
@@ -194,6 +493,33 @@ func WithOnConfigLoaded(hooks ...func(Application) error) Option {
 	}
 }
 
+// WithLifecycleHook registers hooks to run at a lifecycle phase. Hooks of a phase
+// run in registration order; see StdApplication.OnLifecycle for how errors are
+// handled.
+//
+// Example:
+//
+//	app, err := modular.NewApplication(
+//	    modular.WithLogger(logger),
+//	    modular.WithModules(modules...),
+//	    modular.WithLifecycleHook(modular.PhasePostStart, func(ctx context.Context, app modular.Application) error {
+//	        return discovery.Register(ctx, serviceName, addr)
+//	    }),
+//	    modular.WithLifecycleHook(modular.PhasePreStop, func(ctx context.Context, app modular.Application) error {
+//	        return discovery.Deregister(ctx, serviceName)
+//	    }),
+//	)
+func WithLifecycleHook(phase LifecyclePhase, hooks ...LifecycleHook) Option {
+	return func(b *ApplicationBuilder) error {
+		for _, hook := range hooks {
+			if hook != nil {
+				b.lifecycleHooks = append(b.lifecycleHooks, lifecycleHookRegistration{phase: phase, hook: hook})
+			}
+		}
+		return nil
+	}
+}
+
 // Convenience functions for creating common decorators
 
 // InstanceAwareConfig creates an instance-aware configuration decorator
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/CrisisTextLine/modular/cmd/modcli/internal/compat"
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/modfile"
+)
+
+// ErrIncompatibleDependencies is returned when the check finds incompatible module versions
+var ErrIncompatibleDependencies = errors.New("incompatible modular dependencies detected")
+
+// NewCheckCommand creates the check command
+func NewCheckCommand() *cobra.Command {
+	var (
+		goModPath    string
+		outputFormat string
+		fix          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "check [dir]",
+		Short: "Check Modular module versions for compatibility and upgrades",
+		Long: `Inspect a go.mod file for CrisisTextLine/modular dependencies and report
+known incompatible version combinations, available upgrades, and breaking-change
+notes. With --fix, suggested version bumps are written back to go.mod.
+
+Examples:
+  modcli check                      # Check ./go.mod
+  modcli check ./examples/basic-app # Check go.mod in a directory
+  modcli check --format json        # Machine-readable output
+  modcli check --fix                # Apply suggested upgrades to go.mod`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := goModPath
+			if len(args) == 1 {
+				path = filepath.Join(args[0], "go.mod")
+			}
+			return runCheck(cmd.OutOrStdout(), path, outputFormat, fix)
+		},
+	}
+
+	cmd.Flags().StringVar(&goModPath, "gomod", "go.mod", "Path to the go.mod file to check")
+	cmd.Flags().StringVar(&outputFormat, "format", "text", "Output format: text, json")
+	cmd.Flags().BoolVar(&fix, "fix", false, "Apply suggested version changes to go.mod")
+
+	return cmd
+}
+
+func runCheck(out io.Writer, goModPath, outputFormat string, fix bool) error {
+	data, err := os.ReadFile(goModPath)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", goModPath, err)
+	}
+
+	f, err := modfile.Parse(goModPath, data, nil)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", goModPath, err)
+	}
+
+	md, err := compat.LoadMetadata()
+	if err != nil {
+		return fmt.Errorf("failed to load compatibility metadata: %w", err)
+	}
+
+	checker := compat.NewChecker(md)
+	report, err := checker.Check(f)
+	if err != nil {
+		return fmt.Errorf("failed to check %s: %w", goModPath, err)
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Fprintln(out, string(encoded))
+	case "text", "txt":
+		writeCheckReportText(out, goModPath, report)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, outputFormat)
+	}
+
+	if !fix {
+		if report.HasErrors() {
+			return ErrIncompatibleDependencies
+		}
+		return nil
+	}
+
+	applied, err := checker.Fix(f, report)
+	if err != nil {
+		return fmt.Errorf("failed to apply fixes: %w", err)
+	}
+	if len(applied) == 0 {
+		fmt.Fprintln(out, "No changes to apply.")
+		return nil
+	}
+
+	formatted, err := f.Format()
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", goModPath, err)
+	}
+	if err := os.WriteFile(goModPath, formatted, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", goModPath, err)
+	}
+
+	fmt.Fprintf(out, "Updated %s:\n", goModPath)
+	for _, change := range applied {
+		fmt.Fprintf(out, "  - %s\n", change)
+	}
+	fmt.Fprintln(out, "Run 'go mod tidy' to refresh go.sum.")
+	return nil
+}
+
+func writeCheckReportText(out io.Writer, goModPath string, report *compat.Report) {
+	fmt.Fprintf(out, "Checked %s", goModPath)
+	if report.ModulePath != "" {
+		fmt.Fprintf(out, " (%s)", report.ModulePath)
+	}
+	fmt.Fprintln(out)
+
+	if len(report.Findings) == 0 {
+		fmt.Fprintln(out, "All Modular dependencies are up to date and compatible.")
+		return
+	}
+
+	for _, finding := range report.Findings {
+		fmt.Fprintf(out, "[%s] %s: %s\n", strings.ToUpper(string(finding.Severity)), finding.Module, finding.Message)
+	}
+}
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CrisisTextLine/modular/cmd/modcli/internal/compat"
+)
+
+const checkTestGoMod = `module example.com/app
+
+go 1.25
+
+require (
+	github.com/CrisisTextLine/modular v1.0.0
+	github.com/CrisisTextLine/modular/modules/cache v0.0.1
+)
+`
+
+func writeCheckGoMod(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(path, []byte(checkTestGoMod), 0600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	return path
+}
+
+func TestCheckCommand_ReportsIncompatibilities(t *testing.T) {
+	path := writeCheckGoMod(t)
+
+	cmd := NewCheckCommand()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs([]string{"--gomod", path})
+
+	err := cmd.Execute()
+	if !errors.Is(err, ErrIncompatibleDependencies) {
+		t.Fatalf("expected ErrIncompatibleDependencies, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "[ERROR] github.com/CrisisTextLine/modular/modules/cache") {
+		t.Errorf("expected incompatibility in output, got:\n%s", buf.String())
+	}
+	if !strings.Contains(buf.String(), "upgrade available") {
+		t.Errorf("expected upgrade suggestion in output, got:\n%s", buf.String())
+	}
+}
+
+func TestCheckCommand_JSONFormat(t *testing.T) {
+	path := writeCheckGoMod(t)
+
+	buf := new(bytes.Buffer)
+	_ = runCheck(buf, path, "json", false)
+
+	var report compat.Report
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("expected valid JSON output: %v\n%s", err, buf.String())
+	}
+	if report.ModulePath != "example.com/app" {
+		t.Errorf("expected module path example.com/app, got %s", report.ModulePath)
+	}
+	if len(report.Findings) == 0 {
+		t.Error("expected findings in JSON report")
+	}
+}
+
+func TestCheckCommand_Fix(t *testing.T) {
+	path := writeCheckGoMod(t)
+
+	buf := new(bytes.Buffer)
+	if err := runCheck(buf, path, "text", true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read go.mod: %v", err)
+	}
+	if strings.Contains(string(data), "modular v1.0.0") {
+		t.Errorf("expected core version to be upgraded, got:\n%s", data)
+	}
+
+	// A second check should now pass
+	buf.Reset()
+	if err := runCheck(buf, path, "text", false); err != nil {
+		t.Errorf("expected fixed go.mod to pass check, got %v\n%s", err, buf.String())
+	}
+}
+
+func TestCheckCommand_UnsupportedFormat(t *testing.T) {
+	path := writeCheckGoMod(t)
+	err := runCheck(new(bytes.Buffer), path, "xml", false)
+	if !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/CrisisTextLine/modular/cmd/modcli/internal/tenantconfig"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ErrConfigInvalid is returned when validation finds problems in a config file
+	ErrConfigInvalid = errors.New("invalid configuration")
+	// ErrConflictingConfigFlags is returned when --module and --root are combined
+	ErrConflictingConfigFlags = errors.New("--module and --root can't be combined")
+)
+
+// NewConfigCommand creates the config command
+func NewConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Validate application configuration files",
+		Long: `Work with application configuration files. Config sections are described by the
+modules' config structs, loaded from source as seen from the Go module in
+--project, so the modules must be dependencies of that module.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewConfigValidateCommand())
+
+	return cmd
+}
+
+// NewConfigValidateCommand creates the 'config validate' command
+func NewConfigValidateCommand() *cobra.Command {
+	var (
+		project      string
+		sections     []string
+		root         string
+		module       string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate <file>",
+		Short: "Validate a config file against module config structs",
+		Long: `Load a YAML, JSON or TOML config file and check each top-level section against
+the config struct of its module, using the struct's default, required and desc
+tags. Unknown sections and fields, values of the wrong type and missing required
+fields without a default are reported; the command fails if any are found.
+
+Top-level keys that aren't module sections are checked against the application's
+own config struct when --root names it, and reported as unknown sections
+otherwise. With --module, the whole file is checked as that module's section.
+
+Known modules: ` + strings.Join(knownModuleNames(), ", ") + `
+
+Examples:
+  modcli config validate config.yaml
+  modcli config validate config.yaml --root example.com/app/config.AppConfig
+  modcli config validate reverseproxy.json --module reverseproxy
+  modcli config validate config.toml --section billing=example.com/app/billing.Config --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigValidate(cmd.OutOrStdout(), args[0], project, sections, root, module, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&project, "project", ".", "Directory of the Go module depending on the modules")
+	cmd.Flags().StringSliceVar(&sections, "section", nil, "Custom config sections as section=import/path.Type")
+	cmd.Flags().StringVar(&root, "root", "", "Application config struct as import/path.Type, for top-level keys that aren't sections")
+	cmd.Flags().StringVarP(&module, "module", "m", "", "Check the whole file as the section of this module")
+	cmd.Flags().StringVar(&outputFormat, "format", "text", "Output format: text, json")
+
+	return cmd
+}
+
+// configValidationReport is the JSON output of 'config validate'
+type configValidationReport struct {
+	File     string                 `json:"file"`
+	Problems []tenantconfig.Problem `json:"problems"`
+}
+
+func runConfigValidate(out io.Writer, path, project string, customSections []string, root, module, outputFormat string) error {
+	if root != "" && module != "" {
+		return ErrConflictingConfigFlags
+	}
+	custom, err := parseTenantSections(customSections)
+	if err != nil {
+		return err
+	}
+	file, err := tenantconfig.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	report := configValidationReport{File: path}
+	switch {
+	case module != "":
+		specs, err := resolveTenantSections([]string{module}, customSections)
+		if err != nil {
+			return err
+		}
+		loaded, err := tenantconfig.Load(project, specs)
+		if err != nil {
+			return err
+		}
+		report.Problems = file.ValidateSection(loaded[0])
+	default:
+		// Load the config structs of the sections the file uses, and the root struct
+		// if given; unknown sections are reported by ValidateApp
+		var specs []tenantconfig.SectionSpec
+		for _, name := range file.SectionNames() {
+			if spec, ok := custom[name]; ok {
+				specs = append(specs, spec)
+			} else if spec, ok := tenantconfig.LookupModule(name); ok {
+				specs = append(specs, spec)
+			}
+		}
+		if root != "" {
+			spec, err := tenantconfig.ParseTypeSpec(root)
+			if err != nil {
+				return err
+			}
+			specs = append(specs, spec)
+		}
+		loaded, err := tenantconfig.Load(project, specs)
+		if err != nil {
+			return err
+		}
+		var rootFields []*tenantconfig.Field
+		byName := make(map[string]*tenantconfig.Section, len(loaded))
+		for _, section := range loaded {
+			if section.Name == "" {
+				rootFields = section.Fields
+				continue
+			}
+			byName[section.Name] = section
+		}
+		report.Problems = file.ValidateApp(rootFields, byName)
+	}
+	if report.Problems == nil {
+		report.Problems = []tenantconfig.Problem{}
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Fprintln(out, string(encoded))
+	case "text", "txt":
+		writeConfigReportText(out, report)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, outputFormat)
+	}
+
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("%w: %d problem(s) in %s", ErrConfigInvalid, len(report.Problems), path)
+	}
+	return nil
+}
+
+func writeConfigReportText(out io.Writer, report configValidationReport) {
+	fmt.Fprintf(out, "Validated %s\n", report.File)
+	if len(report.Problems) == 0 {
+		fmt.Fprintln(out, "The config file is valid.")
+		return
+	}
+	for _, problem := range report.Problems {
+		fmt.Fprintf(out, "[ERROR] %s\n", problem)
+	}
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const configTestRoot = `package config
+
+type AppConfig struct {
+	Name string ` + "`yaml:\"name\" json:\"name\" required:\"true\"`" + `
+	Port int    ` + "`yaml:\"port\" json:\"port\" default:\"8080\"`" + `
+}
+`
+
+// writeConfigTestProject writes the tenant test project with an application config
+// struct added.
+func writeConfigTestProject(t *testing.T) string {
+	t.Helper()
+	dir := writeTenantTestProject(t)
+	if err := os.MkdirAll(filepath.Join(dir, "config"), 0o750); err != nil {
+		t.Fatalf("failed to create package dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config", "config.go"), []byte(configTestRoot), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return dir
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func runConfigCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewConfigCommand()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestConfigValidate_Valid(t *testing.T) {
+	project := writeConfigTestProject(t)
+	path := writeConfigFile(t, "config.yaml", `
+name: orders
+billing:
+  endpoint: https://billing.example.com
+  retries: 5
+`)
+
+	output, err := runConfigCommand(t, "validate", path, "--project", project, "--section", tenantTestSection, "--root", "example.com/app/config.AppConfig")
+	if err != nil {
+		t.Fatalf("config validate failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "The config file is valid.") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
+func TestConfigValidate_ReportsProblems(t *testing.T) {
+	project := writeConfigTestProject(t)
+	path := writeConfigFile(t, "config.json", `{"port": "http", "debug": true, "billing": {"retries": 1.5}}`)
+
+	buf := new(bytes.Buffer)
+	err := runConfigValidate(buf, path, project, []string{tenantTestSection}, "example.com/app/config.AppConfig", "", "json")
+	if !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("expected ErrConfigInvalid, got %v", err)
+	}
+	var report configValidationReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("expected valid JSON output: %v\n%s", err, buf.String())
+	}
+	want := []string{
+		"billing.retries: invalid int: 1.5 is not a whole number",
+		"billing.endpoint: missing required field",
+		"debug: unknown field",
+		"port: invalid int: strconv.ParseInt: parsing \"http\": invalid syntax",
+		"name: missing required field",
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("problems = %+v, want %d", report.Problems, len(want))
+	}
+	for i, problem := range report.Problems {
+		if got := problem.Path + ": " + problem.Message; got != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, got, want[i])
+		}
+	}
+
+	// Without the root struct, top-level keys that aren't sections are unknown
+	buf.Reset()
+	_ = runConfigValidate(buf, path, project, []string{tenantTestSection}, "", "", "text")
+	if !strings.Contains(buf.String(), "debug: unknown config section") || !strings.Contains(buf.String(), "[ERROR]") {
+		t.Errorf("unexpected text output:\n%s", buf.String())
+	}
+}
+
+func TestConfigValidate_Module(t *testing.T) {
+	project := writeConfigTestProject(t)
+	path := writeConfigFile(t, "billing.toml", "endpoint = \"https://billing.example.com\"\nretry = 2\n")
+
+	output, err := runConfigCommand(t, "validate", path, "--project", project, "--section", tenantTestSection, "--module", "billing")
+	if !errors.Is(err, ErrConfigInvalid) {
+		t.Fatalf("expected ErrConfigInvalid, got %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "retry: unknown field") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	_, err = runConfigCommand(t, "validate", path, "--project", project, "--module", "billing", "--root", "example.com/app/config.AppConfig")
+	if !errors.Is(err, ErrConflictingConfigFlags) {
+		t.Errorf("expected ErrConflictingConfigFlags, got %v", err)
+	}
+}
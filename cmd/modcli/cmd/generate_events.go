@@ -0,0 +1,681 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+)
+
+// eventbusImportPath is the import path of the eventbus module generated events use.
+const eventbusImportPath = "github.com/CrisisTextLine/modular/modules/eventbus/v2"
+
+// Suffixes of the files read by 'generate events'. Other .json files are sample payloads.
+const (
+	eventSchemaSuffix = ".schema.json"
+	eventSampleSuffix = ".json"
+)
+
+var (
+	// ErrNoEventSchemas is returned when the schema directory holds no schemas or samples
+	ErrNoEventSchemas = errors.New("no event schemas found")
+	// ErrInvalidEventSchema is returned for schemas and samples that can't be turned into types
+	ErrInvalidEventSchema = errors.New("invalid event schema")
+)
+
+// NewGenerateEventsCommand creates the 'generate events' command
+func NewGenerateEventsCommand() *cobra.Command {
+	var (
+		schemas string
+		output  string
+		pkg     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events",
+		Short: "Generate typed event structs and eventbus helpers from JSON schemas",
+		Long: `Generate a Go file declaring a struct, a topic constant, a publish helper and
+typed subscribe wrappers for each event of a directory of JSON Schemas and sample
+payloads, so services sharing the event bus share the same event types.
+
+Each <topic>.schema.json file is a JSON Schema of the event payload and each
+other <topic>.json file is a sample payload the types are inferred from. The topic
+is the file name without the extension, unless the schema sets "x-topic".
+
+Examples:
+  modcli generate events --schemas ./schemas
+  modcli generate events --schemas ./schemas --output ./events/events_gen.go --package events
+  //go:generate modcli generate events --schemas ../schemas --output events_gen.go`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if pkg == "" {
+				abs, err := filepath.Abs(filepath.Dir(output))
+				if err != nil {
+					return fmt.Errorf("failed to resolve %s: %w", output, err)
+				}
+				pkg = filepath.Base(abs)
+			}
+			source, err := GenerateEvents(schemas, sanitizePackageName(pkg))
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(output), 0o750); err != nil {
+				return fmt.Errorf("failed to create %s: %w", filepath.Dir(output), err)
+			}
+			if err := os.WriteFile(output, source, 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", output, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Generated %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&schemas, "schemas", "s", "schemas", "Directory of JSON Schemas (<topic>.schema.json) and sample payloads (<topic>.json)")
+	cmd.Flags().StringVarP(&output, "output", "o", "events_gen.go", "Output file")
+	cmd.Flags().StringVarP(&pkg, "package", "p", "", "Package of the generated file (default the output directory name)")
+
+	return cmd
+}
+
+// eventDefinition is an event read from a schema or sample file.
+type eventDefinition struct {
+	topic  string
+	file   string
+	schema *orderedObject // nil for samples
+	sample any
+}
+
+// GenerateEvents returns the formatted source of a file in package pkg declaring the
+// events of the schemas and samples in dir.
+func GenerateEvents(dir, pkg string) ([]byte, error) {
+	events, err := loadEventDefinitions(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	gen := &eventTypeGenerator{decls: make(map[string]string), names: make(map[string]bool)}
+	var helpers bytes.Buffer
+	var topics []string
+	for _, event := range events {
+		base := exportedName(event.topic)
+		typeName := gen.reserve(base + "Event")
+		summary := fmt.Sprintf("is the payload of %s events.", event.topic)
+		if event.schema != nil {
+			gen.defs = schemaDefinitions(event.schema)
+			gen.refs = make(map[string]string)
+			if err := gen.declareSchema(typeName, summary, event.schema); err != nil {
+				return nil, fmt.Errorf("%s: %w", event.file, err)
+			}
+		} else {
+			gen.declareSample(typeName, summary+" Inferred from "+filepath.Base(event.file)+".", event.sample)
+		}
+		topicConst := "Topic" + base
+		topics = append(topics, fmt.Sprintf("// %s is the topic of %s.\n%s = %q", topicConst, typeName, topicConst, event.topic))
+		writeEventHelpers(&helpers, base, typeName, topicConst)
+	}
+
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by modcli generate events. DO NOT EDIT.\n\npackage %s\n\n", pkg)
+	file.WriteString("import (\n\"context\"\n\"fmt\"\n")
+	if gen.usesTime {
+		file.WriteString("\"time\"\n")
+	}
+	fmt.Fprintf(&file, "\n%q\n)\n\n", eventbusImportPath)
+	file.WriteString("// Event topics.\nconst (\n" + strings.Join(topics, "\n") + "\n)\n\n")
+	file.WriteString(`// EventPublisher publishes events. *eventbus.EventBusModule implements it.
+type EventPublisher interface {
+	Publish(ctx context.Context, topic string, payload interface{}) error
+}
+
+// EventSubscriber subscribes to events. *eventbus.EventBusModule implements it.
+type EventSubscriber interface {
+	Subscribe(ctx context.Context, topic string, handler eventbus.EventHandler) (eventbus.Subscription, error)
+	SubscribeAsync(ctx context.Context, topic string, handler eventbus.EventHandler) (eventbus.Subscription, error)
+}
+`)
+	for _, name := range gen.order {
+		file.WriteString(gen.decls[name])
+	}
+	file.Write(helpers.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated events: %w", err)
+	}
+	return source, nil
+}
+
+// loadEventDefinitions reads the schemas and samples in dir, sorted by topic.
+func loadEventDefinitions(dir string) ([]eventDefinition, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schemas: %w", err)
+	}
+	var events []eventDefinition
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, eventSampleSuffix) {
+			continue
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path) //nolint:gosec // reading the user's schema directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		value, err := decodeOrderedJSON(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidEventSchema, path, err)
+		}
+
+		event := eventDefinition{file: path}
+		if strings.HasSuffix(name, eventSchemaSuffix) {
+			schema, ok := value.(*orderedObject)
+			if !ok {
+				return nil, fmt.Errorf("%w: %s is not a JSON object", ErrInvalidEventSchema, path)
+			}
+			event.schema = schema
+			event.topic = strings.TrimSuffix(name, eventSchemaSuffix)
+			if topic, ok := schema.values["x-topic"].(string); ok && topic != "" {
+				event.topic = topic
+			}
+		} else {
+			event.sample = value
+			event.topic = strings.TrimSuffix(name, eventSampleSuffix)
+		}
+		if other, ok := seen[event.topic]; ok {
+			return nil, fmt.Errorf("%w: topic %s is defined by both %s and %s", ErrInvalidEventSchema, event.topic, other, path)
+		}
+		seen[event.topic] = path
+		events = append(events, event)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoEventSchemas, dir)
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].topic < events[j].topic })
+	return events, nil
+}
+
+// writeEventHelpers writes the publish helper and subscribe wrappers of an event.
+func writeEventHelpers(b *bytes.Buffer, base, typeName, topicConst string) {
+	fmt.Fprintf(b, `
+// Publish%[1]s publishes event to %[3]s.
+func Publish%[1]s(ctx context.Context, bus EventPublisher, event %[2]s) error {
+	return bus.Publish(ctx, %[3]s, event)
+}
+
+// Subscribe%[1]s calls handler with each %[2]s published to %[3]s, in the
+// publishing goroutine.
+func Subscribe%[1]s(ctx context.Context, bus EventSubscriber, handler func(context.Context, %[2]s) error) (eventbus.Subscription, error) {
+	return bus.Subscribe(ctx, %[3]s, decode%[1]s(handler))
+}
+
+// Subscribe%[1]sAsync calls handler with each %[2]s published to %[3]s, in the
+// event bus workers.
+func Subscribe%[1]sAsync(ctx context.Context, bus EventSubscriber, handler func(context.Context, %[2]s) error) (eventbus.Subscription, error) {
+	return bus.SubscribeAsync(ctx, %[3]s, decode%[1]s(handler))
+}
+
+func decode%[1]s(handler func(context.Context, %[2]s) error) eventbus.EventHandler {
+	return func(ctx context.Context, event eventbus.Event) error {
+		var payload %[2]s
+		if err := event.DataAs(&payload); err != nil {
+			return fmt.Errorf("decoding %%s event: %%w", %[3]s, err)
+		}
+		return handler(ctx, payload)
+	}
+}
+`, base, typeName, topicConst)
+}
+
+// eventTypeGenerator declares the Go types of event payloads.
+type eventTypeGenerator struct {
+	// Struct declarations by name, written in the order their names were reserved so
+	// that types precede the types they contain
+	decls    map[string]string
+	order    []string
+	names    map[string]bool
+	usesTime bool
+
+	// Definitions and declared $ref types of the schema being generated
+	defs map[string]any
+	refs map[string]string
+}
+
+// reserve returns name, or name with a numeric suffix when it is already declared.
+func (g *eventTypeGenerator) reserve(name string) string {
+	unique := name
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s%d", name, i)
+	}
+	g.names[unique] = true
+	g.order = append(g.order, unique)
+	return unique
+}
+
+// eventField is a field of a generated struct.
+type eventField struct {
+	name, goType, jsonName, doc string
+	omitEmpty                   bool
+}
+
+// writeStruct declares the struct name with fields, documented with summary and
+// description.
+func (g *eventTypeGenerator) writeStruct(name, summary, description string, fields []eventField) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n// %s %s\n", name, summary)
+	if description != "" {
+		fmt.Fprintf(&b, "//\n// %s\n", strings.ReplaceAll(description, "\n", "\n// "))
+	}
+	fmt.Fprintf(&b, "type %s struct {\n", name)
+	for _, field := range fields {
+		if field.doc != "" {
+			fmt.Fprintf(&b, "// %s\n", strings.ReplaceAll(field.doc, "\n", "\n// "))
+		}
+		tag := field.jsonName
+		if field.omitEmpty {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", field.name, field.goType, tag)
+	}
+	b.WriteString("}\n")
+	g.decls[name] = b.String()
+}
+
+// declareSchema declares the struct name for an object schema.
+func (g *eventTypeGenerator) declareSchema(name, summary string, schema *orderedObject) error {
+	if t, _ := schemaTypes(schema); t != "" && t != "object" {
+		return fmt.Errorf("%w: event payloads must be objects, not %s", ErrInvalidEventSchema, t)
+	}
+	return g.declareObject(name, summary, schema)
+}
+
+// declareObject declares the struct name for the properties of schema.
+func (g *eventTypeGenerator) declareObject(name, summary string, schema *orderedObject) error {
+	required := make(map[string]bool)
+	if list, ok := schema.values["required"].([]any); ok {
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+	var fields []eventField
+	if properties, ok := schema.values["properties"].(*orderedObject); ok {
+		used := make(map[string]bool)
+		for _, property := range properties.keys {
+			propSchema, ok := properties.values[property].(*orderedObject)
+			if !ok {
+				return fmt.Errorf("%w: property %s is not a schema", ErrInvalidEventSchema, property)
+			}
+			fieldName := exportedName(property)
+			for i := 2; used[fieldName]; i++ {
+				fieldName = fmt.Sprintf("%s%d", exportedName(property), i)
+			}
+			used[fieldName] = true
+			goType, err := g.schemaType(name+fieldName, propSchema, !required[property])
+			if err != nil {
+				return fmt.Errorf("property %s: %w", property, err)
+			}
+			fields = append(fields, eventField{
+				name:      fieldName,
+				goType:    goType,
+				jsonName:  property,
+				doc:       schemaDoc(propSchema),
+				omitEmpty: !required[property],
+			})
+		}
+	}
+	g.writeStruct(name, summary, schemaDoc(schema), fields)
+	return nil
+}
+
+// schemaType returns the Go type of schema, declaring the structs it needs under name.
+// Nullable values and optional structs are pointers; optional scalars are omitted
+// when empty instead.
+func (g *eventTypeGenerator) schemaType(name string, schema *orderedObject, optional bool) (string, error) {
+	if ref, ok := schema.values["$ref"].(string); ok {
+		goType, err := g.refType(ref)
+		if err == nil && optional && g.names[goType] {
+			goType = "*" + goType
+		}
+		return goType, err
+	}
+	t, nullable := schemaTypes(schema)
+	if t == "" {
+		switch {
+		case schema.values["properties"] != nil:
+			t = "object"
+		case schema.values["items"] != nil:
+			t = "array"
+		case schema.values["enum"] != nil:
+			t = enumType(schema.values["enum"])
+		}
+	}
+
+	var goType string
+	switch t {
+	case "string":
+		goType = "string"
+		if format, _ := schema.values["format"].(string); format == "date-time" {
+			goType = "time.Time"
+			g.usesTime = true
+		}
+	case "integer":
+		goType = "int64"
+	case "number":
+		goType = "float64"
+	case "boolean":
+		goType = "bool"
+	case "array":
+		itemType := "any"
+		if items, ok := schema.values["items"].(*orderedObject); ok {
+			var err error
+			if itemType, err = g.schemaType(name+"Item", items, false); err != nil {
+				return "", err
+			}
+		}
+		return "[]" + itemType, nil
+	case "object":
+		if _, ok := schema.values["properties"].(*orderedObject); !ok {
+			valueType := "any"
+			if additional, ok := schema.values["additionalProperties"].(*orderedObject); ok {
+				var err error
+				if valueType, err = g.schemaType(name+"Value", additional, false); err != nil {
+					return "", err
+				}
+			}
+			return "map[string]" + valueType, nil
+		}
+		structName := g.reserve(name)
+		if err := g.declareObject(structName, "is part of an event payload.", schema); err != nil {
+			return "", err
+		}
+		goType = structName
+	default:
+		// Untyped schemas and combinations such as oneOf accept any value
+		return "any", nil
+	}
+	if optional && goType != "string" && goType != "bool" && goType != "int64" && goType != "float64" || nullable {
+		return "*" + goType, nil
+	}
+	return goType, nil
+}
+
+// refType returns the type of a local $ref such as "#/$defs/Address", declaring it
+// on first use.
+func (g *eventTypeGenerator) refType(ref string) (string, error) {
+	if declared, ok := g.refs[ref]; ok {
+		return declared, nil
+	}
+	var defName string
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if strings.HasPrefix(ref, prefix) {
+			defName = strings.TrimPrefix(ref, prefix)
+		}
+	}
+	def, ok := g.defs[defName].(*orderedObject)
+	if defName == "" || !ok {
+		return "", fmt.Errorf("%w: unresolved $ref %s", ErrInvalidEventSchema, ref)
+	}
+	if t, _ := schemaTypes(def); t != "object" && def.values["properties"] == nil {
+		return g.schemaType(exportedName(defName), def, false)
+	}
+	name := g.reserve(exportedName(defName))
+	g.refs[ref] = name
+	if err := g.declareObject(name, "is part of an event payload.", def); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// declareSample declares the struct name inferred from a sample payload.
+func (g *eventTypeGenerator) declareSample(name, summary string, sample any) {
+	object, ok := sample.(*orderedObject)
+	if !ok {
+		object = &orderedObject{values: map[string]any{}}
+	}
+	var fields []eventField
+	used := make(map[string]bool)
+	for _, key := range object.keys {
+		fieldName := exportedName(key)
+		for i := 2; used[fieldName]; i++ {
+			fieldName = fmt.Sprintf("%s%d", exportedName(key), i)
+		}
+		used[fieldName] = true
+		fields = append(fields, eventField{name: fieldName, goType: g.sampleType(name+fieldName, object.values[key]), jsonName: key})
+	}
+	g.writeStruct(name, summary, "", fields)
+}
+
+// sampleType infers the Go type of a sample value, declaring the structs it needs
+// under name.
+func (g *eventTypeGenerator) sampleType(name string, value any) string {
+	switch v := value.(type) {
+	case string:
+		if _, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			g.usesTime = true
+			return "time.Time"
+		}
+		return "string"
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return "float64"
+		}
+		return "int64"
+	case bool:
+		return "bool"
+	case []any:
+		if len(v) == 0 {
+			return "[]any"
+		}
+		return "[]" + g.sampleType(name+"Item", v[0])
+	case *orderedObject:
+		structName := g.reserve(name)
+		g.declareSample(structName, "is part of an event payload.", v)
+		return structName
+	default:
+		return "any"
+	}
+}
+
+// schemaTypes returns the type of schema, the first non-null one for type lists, and
+// whether it allows null.
+func schemaTypes(schema *orderedObject) (string, bool) {
+	switch t := schema.values["type"].(type) {
+	case string:
+		return t, false
+	case []any:
+		var first string
+		nullable := false
+		for _, item := range t {
+			s, _ := item.(string)
+			if s == "null" {
+				nullable = true
+			} else if first == "" {
+				first = s
+			}
+		}
+		return first, nullable
+	}
+	return "", false
+}
+
+// enumType returns the JSON type shared by the values of an enum, or "".
+func enumType(enum any) string {
+	values, ok := enum.([]any)
+	if !ok || len(values) == 0 {
+		return ""
+	}
+	for _, value := range values {
+		if _, ok := value.(string); !ok {
+			return ""
+		}
+	}
+	return "string"
+}
+
+// schemaDoc returns the doc comment of a schema from its description or title, with
+// the allowed values of enums.
+func schemaDoc(schema *orderedObject) string {
+	doc, _ := schema.values["description"].(string)
+	if doc == "" {
+		doc, _ = schema.values["title"].(string)
+	}
+	if values, ok := schema.values["enum"].([]any); ok {
+		allowed := make([]string, 0, len(values))
+		for _, value := range values {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+		doc = strings.TrimSpace(doc + "\nOne of: " + strings.Join(allowed, ", "))
+	}
+	return doc
+}
+
+// schemaDefinitions returns the $defs and definitions of a schema.
+func schemaDefinitions(schema *orderedObject) map[string]any {
+	defs := make(map[string]any)
+	for _, key := range []string{"definitions", "$defs"} {
+		if object, ok := schema.values[key].(*orderedObject); ok {
+			for name, def := range object.values {
+				defs[name] = def
+			}
+		}
+	}
+	return defs
+}
+
+// eventInitialisms are the words exportedName writes in upper case.
+var eventInitialisms = map[string]bool{
+	"API": true, "DNS": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true,
+	"JSON": true, "SKU": true, "SQL": true, "TLS": true, "TTL": true, "UI": true, "URI": true,
+	"URL": true, "UTC": true, "UUID": true, "XML": true,
+}
+
+// exportedName turns a topic, property or definition name such as "user.created",
+// "user_id" or "billingAddress" into an exported Go identifier, UserCreated, UserID
+// and BillingAddress.
+func exportedName(s string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(word[len(word)-1]) ||
+			i+1 < len(runes) && unicode.IsLower(runes[i+1])):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		if upper := strings.ToUpper(w); eventInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(strings.ToUpper(w[:1]) + w[1:])
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// sanitizePackageName returns a valid package name for name.
+func sanitizePackageName(name string) string {
+	name = strings.ToLower(name)
+	var b strings.Builder
+	for _, r := range name {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 || unicode.IsDigit([]rune(b.String())[0]) {
+		return "events"
+	}
+	return b.String()
+}
+
+// orderedObject is a JSON object keeping the order of its keys, so that generated
+// fields follow the order of the schema or sample.
+type orderedObject struct {
+	keys   []string
+	values map[string]any
+}
+
+// decodeOrderedJSON decodes data with objects as *orderedObject and numbers as json.Number.
+func decodeOrderedJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeOrderedValue(dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+	return value, nil
+}
+
+func decodeOrderedValue(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err //nolint:wrapcheck // wrapped by the caller with the file name
+	}
+	switch token {
+	case json.Delim('{'):
+		object := &orderedObject{values: make(map[string]any)}
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err //nolint:wrapcheck // wrapped by the caller with the file name
+			}
+			key, _ := keyToken.(string)
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := object.values[key]; !exists {
+				object.keys = append(object.keys, key)
+			}
+			object.values[key] = value
+		}
+		_, err := dec.Token()
+		return object, err //nolint:wrapcheck // wrapped by the caller with the file name
+	case json.Delim('['):
+		array := []any{}
+		for dec.More() {
+			value, err := decodeOrderedValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, value)
+		}
+		_, err := dec.Token()
+		return array, err //nolint:wrapcheck // wrapped by the caller with the file name
+	default:
+		return token, nil
+	}
+}
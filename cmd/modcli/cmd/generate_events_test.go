@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const userCreatedSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "description": "A user signed up.",
+  "type": "object",
+  "required": ["user_id", "email", "created_at"],
+  "properties": {
+    "user_id": {"type": "string", "description": "The user's ID"},
+    "email": {"type": "string"},
+    "created_at": {"type": "string", "format": "date-time"},
+    "plan": {"enum": ["free", "pro"]},
+    "referrer": {"type": ["string", "null"]},
+    "age": {"type": "integer"},
+    "address": {"$ref": "#/$defs/postalAddress"},
+    "tags": {"type": "array", "items": {"type": "string"}},
+    "attributes": {"type": "object", "additionalProperties": {"type": "number"}}
+  },
+  "$defs": {
+    "postalAddress": {
+      "type": "object",
+      "required": ["city"],
+      "properties": {"city": {"type": "string"}, "zip": {"type": "string"}}
+    }
+  }
+}`
+
+const orderShippedSample = `{
+  "order_id": 42,
+  "shipped_at": "2026-01-02T15:04:05Z",
+  "total": 19.99,
+  "express": true,
+  "items": [{"sku": "A-1", "quantity": 2}]
+}`
+
+func writeEventSchemas(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestGenerateEvents(t *testing.T) {
+	dir := writeEventSchemas(t, map[string]string{
+		"user.created.schema.json": userCreatedSchema,
+		"order.shipped.json":       orderShippedSample,
+		"README.md":                "ignored",
+	})
+
+	source, err := GenerateEvents(dir, "events")
+	if err != nil {
+		t.Fatalf("GenerateEvents failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "events_gen.go", source, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, source)
+	}
+
+	// Compare with whitespace collapsed, ignoring gofmt's alignment
+	generated := strings.Join(strings.Fields(string(source)), " ")
+	for _, want := range []string{
+		"// Code generated by modcli generate events. DO NOT EDIT.",
+		"package events",
+		`"github.com/CrisisTextLine/modular/modules/eventbus/v2"`,
+		`TopicOrderShipped = "order.shipped"`,
+		`// TopicUserCreated is the topic of UserCreatedEvent. TopicUserCreated = "user.created"`,
+		// Schema types
+		"// UserCreatedEvent is the payload of user.created events.\n//\n// A user signed up.\ntype UserCreatedEvent struct {",
+		"// The user's ID\n\tUserID string `json:\"user_id\"`",
+		"CreatedAt time.Time `json:\"created_at\"`",
+		"// One of: free, pro\n\tPlan string `json:\"plan,omitempty\"`",
+		"Referrer *string `json:\"referrer,omitempty\"`",
+		"Age int64 `json:\"age,omitempty\"`",
+		"Address *PostalAddress `json:\"address,omitempty\"`",
+		"Tags []string `json:\"tags,omitempty\"`",
+		"Attributes map[string]float64 `json:\"attributes,omitempty\"`",
+		"type PostalAddress struct {\n\tCity string `json:\"city\"`\n\tZip  string `json:\"zip,omitempty\"`\n}",
+		// Sample types keep the sample's field order
+		"type OrderShippedEvent struct {\n\tOrderID int64 `json:\"order_id\"`\n\tShippedAt time.Time `json:\"shipped_at\"`",
+		"Total float64 `json:\"total\"`",
+		"Items []OrderShippedEventItemsItem `json:\"items\"`",
+		"type OrderShippedEventItemsItem struct {\n\tSKU      string `json:\"sku\"`\n\tQuantity int64  `json:\"quantity\"`\n}",
+		// Helpers
+		"func PublishUserCreated(ctx context.Context, bus EventPublisher, event UserCreatedEvent) error {",
+		"return bus.Publish(ctx, TopicUserCreated, event)",
+		"func SubscribeUserCreated(ctx context.Context, bus EventSubscriber, handler func(context.Context, UserCreatedEvent) error) (eventbus.Subscription, error) {",
+		"func SubscribeOrderShippedAsync(ctx context.Context, bus EventSubscriber, handler func(context.Context, OrderShippedEvent) error) (eventbus.Subscription, error) {",
+		"if err := event.DataAs(&payload); err != nil {",
+	} {
+		if !strings.Contains(generated, strings.Join(strings.Fields(want), " ")) {
+			t.Errorf("generated code missing %q:\n%s", want, source)
+		}
+	}
+	if strings.Index(generated, "type UserCreatedEvent struct") > strings.Index(generated, "type PostalAddress struct") {
+		t.Errorf("expected event types to precede the types they contain:\n%s", source)
+	}
+}
+
+func TestGenerateEvents_Errors(t *testing.T) {
+	if _, err := GenerateEvents(writeEventSchemas(t, nil), "events"); !errors.Is(err, ErrNoEventSchemas) {
+		t.Errorf("expected ErrNoEventSchemas, got %v", err)
+	}
+
+	for name, files := range map[string]map[string]string{
+		"invalid JSON":    {"a.json": "{"},
+		"non-object":      {"a.schema.json": `{"type": "array"}`},
+		"unresolved ref":  {"a.schema.json": `{"properties": {"b": {"$ref": "#/$defs/missing"}}}`},
+		"duplicate topic": {"a.json": `{}`, "b.schema.json": `{"x-topic": "a"}`},
+	} {
+		_, err := GenerateEvents(writeEventSchemas(t, files), "events")
+		if !errors.Is(err, ErrInvalidEventSchema) {
+			t.Errorf("%s: expected ErrInvalidEventSchema, got %v", name, err)
+		}
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	for in, want := range map[string]string{
+		"user.created":   "UserCreated",
+		"user_id":        "UserID",
+		"billingAddress": "BillingAddress",
+		"HTTPStatus":     "HTTPStatus",
+		"api-url":        "APIURL",
+		"3ds":            "X3ds",
+	} {
+		if got := exportedName(in); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateEventsCommand_WritesFile(t *testing.T) {
+	dir := writeEventSchemas(t, map[string]string{"order.shipped.json": orderShippedSample})
+	output := filepath.Join(t.TempDir(), "shipping", "events_gen.go")
+
+	cmd := NewGenerateEventsCommand()
+	cmd.SetArgs([]string{"--schemas", dir, "--output", output})
+	cmd.SetOut(&strings.Builder{})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	source, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("output not written: %v", err)
+	}
+	if !strings.Contains(string(source), "package shipping") {
+		t.Errorf("expected the package to default to the output directory name:\n%s", source)
+	}
+}
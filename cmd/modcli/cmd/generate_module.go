@@ -44,6 +44,7 @@ type ModuleOptions struct {
 	ProvidesServices bool
 	RequiresServices bool
 	GenerateTests    bool
+	GenerateBDD      bool // Controls whether to generate a Godog feature file and step definitions
 	SkipGoMod        bool // Controls whether to generate a go.mod file
 	ConfigOptions    *ConfigOptions
 }
@@ -239,6 +240,7 @@ func NewGenerateModuleCommand() *cobra.Command {
 	var outputDir string
 	var moduleName string
 	var skipGoMod bool
+	var generateBDD bool
 
 	cmd := &cobra.Command{
 		Use:   "module",
@@ -250,6 +252,7 @@ func NewGenerateModuleCommand() *cobra.Command {
 				ModuleName:    moduleName,
 				ConfigOptions: &ConfigOptions{},
 				SkipGoMod:     skipGoMod,
+				GenerateBDD:   generateBDD,
 			}
 
 			// Collect module information through prompts
@@ -272,6 +275,7 @@ func NewGenerateModuleCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&outputDir, "output", "o", ".", "Directory where the module will be generated")
 	cmd.Flags().StringVarP(&moduleName, "name", "n", "", "Name of the module to generate")
 	cmd.Flags().BoolVar(&skipGoMod, "skip-go-mod", false, "Skip generating go.mod file (useful when creating a module in a monorepo)")
+	cmd.Flags().BoolVar(&generateBDD, "bdd", false, "Generate a Godog feature file and step definitions for the module")
 
 	return cmd
 }
@@ -332,6 +336,11 @@ func promptForModuleInfo(options *ModuleOptions) error {
 			Help:    "If yes, test files will be generated for the module.",
 			Default: true,
 		},
+		{
+			Message: "Do you want to generate BDD tests (Godog feature file and step definitions)?",
+			Help:    "If yes, a features/ directory and a bdd_test.go harness covering lifecycle, configuration and events will be generated.",
+			Default: options.GenerateBDD,
+		},
 		{
 			Message: "Skip go.mod file generation?",
 			Help:    "If yes, no go.mod file will be generated (useful for modules in existing monorepos).",
@@ -349,12 +358,14 @@ func promptForModuleInfo(options *ModuleOptions) error {
 		ProvidesServices bool
 		RequiresServices bool
 		GenerateTests    bool
+		GenerateBDD      bool
 		SkipGoMod        bool
 	}
 
 	// Initialize with defaults
 	answers := moduleFeatures{
 		GenerateTests: true, // Default to true for test generation
+		GenerateBDD:   options.GenerateBDD,
 	}
 
 	err := survey.Ask([]*survey.Question{
@@ -391,9 +402,13 @@ func promptForModuleInfo(options *ModuleOptions) error {
 			Prompt: featureQuestions[7],
 		},
 		{
-			Name:   "SkipGoMod",
+			Name:   "GenerateBDD",
 			Prompt: featureQuestions[8],
 		},
+		{
+			Name:   "SkipGoMod",
+			Prompt: featureQuestions[9],
+		},
 	}, &answers, SurveyStdio.WithStdio())
 
 	if err != nil {
@@ -409,6 +424,7 @@ func promptForModuleInfo(options *ModuleOptions) error {
 	options.ProvidesServices = answers.ProvidesServices
 	options.RequiresServices = answers.RequiresServices
 	options.GenerateTests = answers.GenerateTests
+	options.GenerateBDD = answers.GenerateBDD
 	options.SkipGoMod = answers.SkipGoMod
 
 	// If module has configuration, collect config details
@@ -573,6 +589,13 @@ func GenerateModuleFiles(options *ModuleOptions) error {
 		}
 	}
 
+	// Generate BDD feature file and step definitions if requested
+	if options.GenerateBDD {
+		if err := generateBDDFiles(moduleDir, options); err != nil {
+			return fmt.Errorf("failed to generate BDD files: %w", err)
+		}
+	}
+
 	// Generate README.md
 	if err := generateReadmeFile(moduleDir, options); err != nil {
 		return fmt.Errorf("failed to generate README file: %w", err)
@@ -1313,6 +1336,244 @@ func (m *MockTenantService) RegisterTenantAwareModule(modular.TenantAwareModule)
 	return nil
 }
 
+// generateBDDFiles creates a Godog feature file and the step definitions that run it
+func generateBDDFiles(outputDir string, options *ModuleOptions) error {
+	featureTmpl := `Feature: {{.ModuleName}} Module
+  As a developer using the Modular framework
+  I want to use the {{.ModuleName}} module
+  So that its behavior is specified and verified alongside the code
+
+  Background:
+    Given I have a modular application with the {{.PackageName}} module registered
+
+  Scenario: Module registration
+    Then a module registered event should be emitted
+
+  Scenario: Module initialization
+    When the application is initialized
+    Then the {{.PackageName}} module should be available
+{{- if .HasConfig}}
+
+  Scenario: Configuration validation
+    When the application is initialized
+    Then the {{.PackageName}} configuration should be valid
+    And a config validated event should be emitted
+{{- end}}
+
+  Scenario: Module lifecycle
+    When the application is initialized
+    And the application is started
+    Then an application started event should be emitted
+    When the application is stopped
+    Then an application stopped event should be emitted
+`
+
+	bddTmpl := `package {{.PackageName}}
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cucumber/godog"
+)
+
+// {{.ModuleName}}BDDTestContext holds the state shared by the steps of a scenario
+type {{.ModuleName}}BDDTestContext struct {
+	app      modular.Application
+	module   *{{.ModuleName}}Module
+	observer *testEventObserver
+}
+
+// testEventObserver captures the events emitted during a scenario
+type testEventObserver struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (o *testEventObserver) OnEvent(ctx context.Context, event cloudevents.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+	return nil
+}
+
+func (o *testEventObserver) ObserverID() string {
+	return "test-observer-{{.PackageName}}"
+}
+
+// hasEvent reports whether an event of eventType has been observed
+func (o *testEventObserver) hasEvent(eventType string) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, event := range o.events {
+		if event.Type() == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// testLogger discards the application's log output
+type testLogger struct{}
+
+func (l *testLogger) Debug(msg string, args ...any) {}
+func (l *testLogger) Info(msg string, args ...any)  {}
+func (l *testLogger) Warn(msg string, args ...any)  {}
+func (l *testLogger) Error(msg string, args ...any) {}
+
+func (ctx *{{.ModuleName}}BDDTestContext) iHaveAModularApplicationWithTheModuleRegistered() error {
+	ctx.observer = &testEventObserver{}
+	app := modular.NewObservableApplication(modular.NewStdConfigProvider(struct{}{}), &testLogger{})
+	if err := app.RegisterObserver(ctx.observer); err != nil {
+		return fmt.Errorf("failed to register observer: %w", err)
+	}
+
+	module, ok := New{{.ModuleName}}Module().(*{{.ModuleName}}Module)
+	if !ok {
+		return fmt.Errorf("New{{.ModuleName}}Module returned an unexpected type")
+	}
+	ctx.module = module
+	app.RegisterModule(ctx.module)
+	ctx.app = app
+	return nil
+}
+
+func (ctx *{{.ModuleName}}BDDTestContext) theApplicationIsInitialized() error {
+	if err := ctx.app.Init(); err != nil {
+		return fmt.Errorf("failed to initialize application: %w", err)
+	}
+	return nil
+}
+
+func (ctx *{{.ModuleName}}BDDTestContext) theApplicationIsStarted() error {
+	if err := ctx.app.Start(); err != nil {
+		return fmt.Errorf("failed to start application: %w", err)
+	}
+	return nil
+}
+
+func (ctx *{{.ModuleName}}BDDTestContext) theApplicationIsStopped() error {
+	if err := ctx.app.Stop(); err != nil {
+		return fmt.Errorf("failed to stop application: %w", err)
+	}
+	return nil
+}
+
+func (ctx *{{.ModuleName}}BDDTestContext) theModuleShouldBeAvailable() error {
+	if ctx.app.GetModule(ctx.module.Name()) == nil {
+		return fmt.Errorf("module %q is not registered with the application", ctx.module.Name())
+	}
+	return nil
+}
+{{- if .HasConfig}}
+
+func (ctx *{{.ModuleName}}BDDTestContext) theConfigurationShouldBeValid() error {
+	if ctx.module.config == nil {
+		return fmt.Errorf("module %q did not register its configuration", ctx.module.Name())
+	}
+	if err := ctx.module.config.Validate(); err != nil {
+		return fmt.Errorf("configuration is invalid: %w", err)
+	}
+	return nil
+}
+{{- end}}
+
+// anEventShouldBeEmitted returns a step asserting that an event of eventType is
+// observed; events may be delivered asynchronously, so it waits briefly.
+func (ctx *{{.ModuleName}}BDDTestContext) anEventShouldBeEmitted(eventType string) func() error {
+	return func() error {
+		deadline := time.Now().Add(time.Second)
+		for !ctx.observer.hasEvent(eventType) {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("no %s event was emitted", eventType)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return nil
+	}
+}
+
+func Test{{.ModuleName}}ModuleBDD(t *testing.T) {
+	suite := godog.TestSuite{
+		ScenarioInitializer: func(s *godog.ScenarioContext) {
+			ctx := &{{.ModuleName}}BDDTestContext{}
+
+			// Background
+			s.Step("^I have a modular application with the {{.PackageName}} module registered$", ctx.iHaveAModularApplicationWithTheModuleRegistered)
+
+			// Lifecycle steps
+			s.Step("^the application is initialized$", ctx.theApplicationIsInitialized)
+			s.Step("^the application is started$", ctx.theApplicationIsStarted)
+			s.Step("^the application is stopped$", ctx.theApplicationIsStopped)
+			s.Step("^the {{.PackageName}} module should be available$", ctx.theModuleShouldBeAvailable)
+			{{- if .HasConfig}}
+
+			// Configuration steps
+			s.Step("^the {{.PackageName}} configuration should be valid$", ctx.theConfigurationShouldBeValid)
+			{{- end}}
+
+			// Event observation steps
+			s.Step("^a module registered event should be emitted$", ctx.anEventShouldBeEmitted(modular.EventTypeModuleRegistered))
+			s.Step("^a config validated event should be emitted$", ctx.anEventShouldBeEmitted(modular.EventTypeConfigValidated))
+			s.Step("^an application started event should be emitted$", ctx.anEventShouldBeEmitted(modular.EventTypeApplicationStarted))
+			s.Step("^an application stopped event should be emitted$", ctx.anEventShouldBeEmitted(modular.EventTypeApplicationStopped))
+		},
+		Options: &godog.Options{
+			Format:   "pretty",
+			Paths:    []string{"features"},
+			TestingT: t,
+			Strict:   true,
+		},
+	}
+
+	if suite.Run() != 0 {
+		t.Fatal("non-zero status returned, failed to run feature tests")
+	}
+}
+`
+
+	featuresDir := filepath.Join(outputDir, "features")
+	if err := os.MkdirAll(featuresDir, 0755); err != nil {
+		return fmt.Errorf("failed to create features directory: %w", err)
+	}
+
+	files := []struct {
+		name    string
+		path    string
+		content string
+	}{
+		{"feature", filepath.Join(featuresDir, options.PackageName+"_module.feature"), featureTmpl},
+		{"BDD test", filepath.Join(outputDir, "bdd_test.go"), bddTmpl},
+	}
+	for _, f := range files {
+		tmpl, err := template.New(f.name).Parse(f.content)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s template: %w", f.name, err)
+		}
+
+		file, err := os.Create(f.path)
+		if err != nil {
+			return fmt.Errorf("failed to create %s file: %w", f.name, err)
+		}
+
+		execErr := tmpl.Execute(file, options)
+		closeErr := file.Close()
+		if execErr != nil {
+			return fmt.Errorf("failed to execute %s template: %w", f.name, execErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to close %s file: %w", f.name, closeErr)
+		}
+	}
+
+	return nil
+}
+
 // generateReadmeFile creates a README.md file for the module
 func generateReadmeFile(outputDir string, options *ModuleOptions) error {
 	// Define the template as a raw string to avoid backtick-related syntax issues
@@ -1556,6 +1817,11 @@ func generateGoModFile(outputDir string, options *ModuleOptions) error {
 			return fmt.Errorf("failed to add testify requirement: %w", err)
 		}
 	}
+	if options.GenerateBDD {
+		if err := newModFile.AddRequire("github.com/cucumber/godog", "v0.15.1"); err != nil {
+			return fmt.Errorf("failed to add godog requirement: %w", err)
+		}
+	}
 
 	// --- Add Replace Directives ---
 	// 1. Copy replaces from parent, adjusting paths (only if parent was used and valid)
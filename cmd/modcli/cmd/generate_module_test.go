@@ -905,3 +905,81 @@ func main() {
 		t.Logf("Successfully compiled the generated module")
 	}
 }
+
+// TestGenerateModuleWithBDD checks that --bdd emits a feature file, step definitions and the godog requirement
+func TestGenerateModuleWithBDD(t *testing.T) {
+	testDir := t.TempDir()
+
+	origSetOptionsFn := cmd.SetOptionsFn
+	defer func() {
+		cmd.SetOptionsFn = origSetOptionsFn
+	}()
+	cmd.SetOptionsFn = func(options *cmd.ModuleOptions) bool {
+		options.PackageName = strings.ToLower(options.ModuleName)
+		options.HasConfig = true
+		options.HasStartupLogic = true
+		options.HasShutdownLogic = true
+		options.GenerateTests = true
+		return true
+	}
+
+	moduleCmd := cmd.NewGenerateModuleCommand()
+	buf := new(bytes.Buffer)
+	moduleCmd.SetOut(buf)
+	moduleCmd.SetErr(buf)
+	moduleCmd.SetArgs([]string{"--name", "Notifier", "--output", testDir, "--bdd"})
+	require.NoError(t, moduleCmd.Execute(), "Module generation failed: %s", buf.String())
+
+	packageDir := filepath.Join(testDir, "notifier")
+	feature, err := os.ReadFile(filepath.Join(packageDir, "features", "notifier_module.feature"))
+	require.NoError(t, err, "feature file should be generated")
+	assert.Contains(t, string(feature), "Feature: Notifier Module")
+	assert.Contains(t, string(feature), "Given I have a modular application with the notifier module registered")
+	assert.Contains(t, string(feature), "Scenario: Configuration validation")
+	assert.Contains(t, string(feature), "Scenario: Module lifecycle")
+
+	steps, err := os.ReadFile(filepath.Join(packageDir, "bdd_test.go"))
+	require.NoError(t, err, "bdd_test.go should be generated")
+	_, err = format.Source(steps)
+	require.NoError(t, err, "generated step definitions should be valid Go")
+	assert.Contains(t, string(steps), "func TestNotifierModuleBDD(t *testing.T)")
+	assert.Contains(t, string(steps), "Strict:   true")
+	for _, line := range strings.Split(string(feature), "\n") {
+		line = strings.TrimSpace(line)
+		for _, keyword := range []string{"Given ", "When ", "Then ", "And "} {
+			if step, ok := strings.CutPrefix(line, keyword); ok {
+				assert.Contains(t, string(steps), `"^`+step+`$"`, "every feature step should have a definition")
+			}
+		}
+	}
+
+	goMod, err := os.ReadFile(filepath.Join(packageDir, "go.mod"))
+	require.NoError(t, err)
+	assert.Contains(t, string(goMod), "github.com/cucumber/godog")
+}
+
+// TestGenerateModuleWithoutBDD checks that BDD scaffolding is opt-in
+func TestGenerateModuleWithoutBDD(t *testing.T) {
+	testDir := t.TempDir()
+
+	origSetOptionsFn := cmd.SetOptionsFn
+	defer func() {
+		cmd.SetOptionsFn = origSetOptionsFn
+	}()
+	cmd.SetOptionsFn = func(options *cmd.ModuleOptions) bool {
+		options.PackageName = strings.ToLower(options.ModuleName)
+		options.GenerateTests = true
+		return true
+	}
+
+	moduleCmd := cmd.NewGenerateModuleCommand()
+	moduleCmd.SetArgs([]string{"--name", "Plain", "--output", testDir})
+	require.NoError(t, moduleCmd.Execute())
+
+	packageDir := filepath.Join(testDir, "plain")
+	assert.NoFileExists(t, filepath.Join(packageDir, "bdd_test.go"))
+	assert.NoDirExists(t, filepath.Join(packageDir, "features"))
+	goMod, err := os.ReadFile(filepath.Join(packageDir, "go.mod"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(goMod), "godog")
+}
@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/tools/go/packages"
+)
+
+// modularImportPath is the import path of the framework generated code registers with.
+const modularImportPath = "github.com/CrisisTextLine/modular"
+
+var (
+	// ErrProxyInterfaceNotFound is returned when the package has no interface with the given name
+	ErrProxyInterfaceNotFound = errors.New("interface not found")
+	// ErrProxyInterfaceUnsupported is returned for generic interfaces and type constraints
+	ErrProxyInterfaceUnsupported = errors.New("interface cannot be proxied")
+)
+
+// NewGenerateProxyCommand creates the 'generate proxy' command
+func NewGenerateProxyCommand() *cobra.Command {
+	var (
+		dir        string
+		output     string
+		interfaces []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "Generate instrumented proxies for service interfaces",
+		Long: `Generate proxies that record per-method call counts, errors and latency for
+service interfaces. The generated file registers each proxy with
+modular.RegisterServiceProxy, so applications built with
+modular.WithServiceInstrumentation() wrap the services modules receive as these
+interfaces.
+
+Examples:
+  modcli generate proxy --interface QuoteService
+  modcli generate proxy --dir ./pricing --interface QuoteService,RateService
+  //go:generate modcli generate proxy --interface QuoteService`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(interfaces) == 0 {
+				return fmt.Errorf("%w: at least one --interface is required", ErrProxyInterfaceNotFound)
+			}
+			source, err := GenerateServiceProxies(dir, interfaces)
+			if err != nil {
+				return err
+			}
+			if output == "" {
+				output = strings.ToLower(interfaces[0]) + "_proxy.go"
+			}
+			path := filepath.Join(dir, output)
+			if err := os.WriteFile(path, source, 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Generated %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "d", ".", "Directory of the package declaring the interfaces")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file name within the package directory (default <interface>_proxy.go)")
+	cmd.Flags().StringSliceVarP(&interfaces, "interface", "i", nil, "Interfaces to generate proxies for")
+
+	return cmd
+}
+
+// GenerateServiceProxies returns the formatted source of a file, in the package in
+// dir, declaring and registering a proxy for each of the named interfaces.
+func GenerateServiceProxies(dir string, interfaces []string) ([]byte, error) {
+	// Dependencies are type-checked from source so loading doesn't depend on the
+	// export data format of the installed toolchain
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load package in %s: %w", dir, err)
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+	pkg := pkgs[0]
+	if len(pkg.Errors) > 0 {
+		return nil, fmt.Errorf("failed to load package in %s: %w", dir, pkg.Errors[0])
+	}
+
+	imports := newProxyImports(pkg.Types)
+	var body bytes.Buffer
+	var registrations []string
+	for _, name := range interfaces {
+		iface, err := lookupProxyInterface(pkg.Types, name)
+		if err != nil {
+			return nil, err
+		}
+		proxyName := lowerFirst(name) + "Proxy"
+		writeProxy(&body, imports, name, proxyName, iface)
+		registrations = append(registrations, fmt.Sprintf(
+			"modular.RegisterServiceProxy(func(service %[1]s, tracker *modular.ServiceCallTracker) %[1]s {\n"+
+				"return &%[2]s{service: service, tracker: tracker}\n})", name, proxyName))
+	}
+
+	var file bytes.Buffer
+	fmt.Fprintf(&file, "// Code generated by modcli generate proxy. DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	file.WriteString(imports.block())
+	file.WriteString("func init() {\n" + strings.Join(registrations, "\n") + "\n}\n")
+	file.Write(body.Bytes())
+
+	source, err := format.Source(file.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated proxies: %w", err)
+	}
+	return source, nil
+}
+
+// lookupProxyInterface finds the named, non-generic interface in pkg.
+func lookupProxyInterface(pkg *types.Package, name string) (*types.Interface, error) {
+	obj, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in package %s", ErrProxyInterfaceNotFound, name, pkg.Path())
+	}
+	iface, ok := obj.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s in package %s is not an interface", ErrProxyInterfaceNotFound, name, pkg.Path())
+	}
+	if named, ok := obj.Type().(*types.Named); ok && named.TypeParams().Len() > 0 {
+		return nil, fmt.Errorf("%w: %s is generic", ErrProxyInterfaceUnsupported, name)
+	}
+	if !iface.IsMethodSet() {
+		return nil, fmt.Errorf("%w: %s is a type constraint", ErrProxyInterfaceUnsupported, name)
+	}
+	return iface, nil
+}
+
+// writeProxy writes the proxy struct and its methods. Each method times the call and
+// reports the last result when it is an error.
+func writeProxy(b *bytes.Buffer, imports *proxyImports, ifaceName, proxyName string, iface *types.Interface) {
+	fmt.Fprintf(b, "\n// %s records calls to a %s. See modular.ServiceCallTracker.\n", proxyName, ifaceName)
+	fmt.Fprintf(b, "type %s struct {\nservice %s\ntracker *modular.ServiceCallTracker\n}\n", proxyName, ifaceName)
+
+	errorType := types.Universe.Lookup("error").Type()
+	for method := range iface.Methods() {
+		sig := method.Type().(*types.Signature)
+
+		params := make([]string, 0, sig.Params().Len())
+		args := make([]string, 0, sig.Params().Len())
+		for i := range sig.Params().Len() {
+			paramType := sig.Params().At(i).Type()
+			arg := "a" + strconv.Itoa(i)
+			if sig.Variadic() && i == sig.Params().Len()-1 {
+				params = append(params, arg+" ..."+imports.typeString(paramType.(*types.Slice).Elem()))
+				arg += "..."
+			} else {
+				params = append(params, arg+" "+imports.typeString(paramType))
+			}
+			args = append(args, arg)
+		}
+
+		results := make([]string, 0, sig.Results().Len())
+		for i := range sig.Results().Len() {
+			results = append(results, "r"+strconv.Itoa(i)+" "+imports.typeString(sig.Results().At(i).Type()))
+		}
+		returnsError := sig.Results().Len() > 0 &&
+			types.Identical(sig.Results().At(sig.Results().Len()-1).Type(), errorType)
+
+		call := fmt.Sprintf("proxy.service.%s(%s)", method.Name(), strings.Join(args, ", "))
+		signature := "(" + strings.Join(params, ", ") + ")"
+		if len(results) > 0 {
+			signature += " (" + strings.Join(results, ", ") + ")"
+		}
+		fmt.Fprintf(b, "\nfunc (proxy *%s) %s%s {\n", proxyName, method.Name(), signature)
+		// Methods taking a context pass it on so the application can audit it
+		track := fmt.Sprintf("proxy.tracker.Track(%q)", method.Name())
+		if sig.Params().Len() > 0 && isContextType(sig.Params().At(0).Type()) {
+			track = fmt.Sprintf("proxy.tracker.TrackContext(a0, %q)", method.Name())
+		}
+		if returnsError {
+			fmt.Fprintf(b, "done := %s\ndefer func() { done(r%d) }()\n", track, sig.Results().Len()-1)
+		} else {
+			fmt.Fprintf(b, "defer %s(nil)\n", track)
+		}
+		if sig.Results().Len() > 0 {
+			b.WriteString("return " + call + "\n}\n")
+		} else {
+			b.WriteString(call + "\n}\n")
+		}
+	}
+}
+
+// isContextType reports whether t is context.Context.
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// proxyImports collects the packages referenced by the generated code and gives
+// each a unique name.
+type proxyImports struct {
+	local *types.Package
+	names map[string]string // import path -> name used in the file
+	taken map[string]bool
+}
+
+func newProxyImports(local *types.Package) *proxyImports {
+	return &proxyImports{
+		local: local,
+		names: map[string]string{modularImportPath: "modular"},
+		taken: map[string]bool{"modular": true},
+	}
+}
+
+// typeString renders t as it is written in the generated file.
+func (p *proxyImports) typeString(t types.Type) string {
+	return types.TypeString(t, func(pkg *types.Package) string {
+		if pkg.Path() == p.local.Path() {
+			return ""
+		}
+		if name, ok := p.names[pkg.Path()]; ok {
+			return name
+		}
+		name := pkg.Name()
+		for i := 2; p.taken[name]; i++ {
+			name = pkg.Name() + strconv.Itoa(i)
+		}
+		p.names[pkg.Path()] = name
+		p.taken[name] = true
+		return name
+	})
+}
+
+// block returns the file's import declaration, with standard library packages
+// grouped before the others.
+func (p *proxyImports) block() string {
+	var std, other []string
+	for path := range p.names {
+		if strings.Contains(strings.SplitN(path, "/", 2)[0], ".") {
+			other = append(other, path)
+		} else {
+			std = append(std, path)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("import (\n")
+	for i, group := range [][]string{std, other} {
+		if i > 0 && len(std) > 0 {
+			b.WriteString("\n")
+		}
+		sort.Strings(group)
+		for _, path := range group {
+			if name := p.names[path]; name == lastPathElement(path) {
+				fmt.Fprintf(&b, "%q\n", path)
+			} else {
+				fmt.Fprintf(&b, "%s %q\n", name, path)
+			}
+		}
+	}
+	b.WriteString(")\n\n")
+	return b.String()
+}
+
+func lastPathElement(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
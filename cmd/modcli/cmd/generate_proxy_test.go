@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const proxyTestSource = `package pricing
+
+import (
+	"context"
+	"io"
+)
+
+type Quote struct{ Price float64 }
+
+type QuoteService interface {
+	io.Closer
+	Quote(ctx context.Context, symbol string) (*Quote, error)
+	Symbols() []string
+	Warm(symbols ...string)
+}
+
+type Sortable[T any] interface{ Less(T) bool }
+`
+
+func writeProxyTestPackage(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":     "module example.com/pricing\n\ngo 1.25\n",
+		"pricing.go": proxyTestSource,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestGenerateServiceProxies(t *testing.T) {
+	dir := writeProxyTestPackage(t)
+
+	source, err := GenerateServiceProxies(dir, []string{"QuoteService"})
+	if err != nil {
+		t.Fatalf("GenerateServiceProxies failed: %v", err)
+	}
+	if _, err := parser.ParseFile(token.NewFileSet(), "quoteservice_proxy.go", source, 0); err != nil {
+		t.Fatalf("generated code does not parse: %v\n%s", err, source)
+	}
+
+	generated := string(source)
+	for _, want := range []string{
+		"// Code generated by modcli generate proxy. DO NOT EDIT.",
+		"package pricing",
+		`"github.com/CrisisTextLine/modular"`,
+		"modular.RegisterServiceProxy(func(service QuoteService, tracker *modular.ServiceCallTracker) QuoteService {",
+		"func (proxy *quoteServiceProxy) Close() (r0 error) {",
+		"func (proxy *quoteServiceProxy) Quote(a0 context.Context, a1 string) (r0 *Quote, r1 error) {",
+		`done := proxy.tracker.TrackContext(a0, "Quote")`,
+		"defer func() { done(r1) }()",
+		"func (proxy *quoteServiceProxy) Symbols() (r0 []string) {",
+		`defer proxy.tracker.Track("Symbols")(nil)`,
+		"func (proxy *quoteServiceProxy) Warm(a0 ...string) {",
+		"proxy.service.Warm(a0...)",
+	} {
+		if !strings.Contains(generated, want) {
+			t.Errorf("generated code missing %q:\n%s", want, generated)
+		}
+	}
+}
+
+func TestGenerateProxyCommand_WritesFile(t *testing.T) {
+	dir := writeProxyTestPackage(t)
+
+	cmd := NewGenerateProxyCommand()
+	cmd.SetArgs([]string{"--dir", dir, "--interface", "QuoteService"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "quoteservice_proxy.go")); err != nil {
+		t.Fatalf("proxy file not written: %v", err)
+	}
+}
+
+func TestGenerateServiceProxies_Errors(t *testing.T) {
+	dir := writeProxyTestPackage(t)
+
+	if _, err := GenerateServiceProxies(dir, []string{"Missing"}); !errors.Is(err, ErrProxyInterfaceNotFound) {
+		t.Errorf("expected ErrProxyInterfaceNotFound, got %v", err)
+	}
+	if _, err := GenerateServiceProxies(dir, []string{"Quote"}); !errors.Is(err, ErrProxyInterfaceNotFound) {
+		t.Errorf("expected ErrProxyInterfaceNotFound for a struct, got %v", err)
+	}
+	if _, err := GenerateServiceProxies(dir, []string{"Sortable"}); !errors.Is(err, ErrProxyInterfaceUnsupported) {
+		t.Errorf("expected ErrProxyInterfaceUnsupported, got %v", err)
+	}
+}
@@ -96,6 +96,10 @@ It helps with generating modules, configurations, and other common tasks.`,
 	cmd.AddCommand(NewGenerateCommand())
 	cmd.AddCommand(NewDebugCommand())
 	cmd.AddCommand(NewContractCommand())
+	cmd.AddCommand(NewCheckCommand())
+	cmd.AddCommand(NewTenantCommand())
+	cmd.AddCommand(NewConfigCommand())
+	cmd.AddCommand(NewTimelineCommand())
 
 	return cmd
 }
@@ -114,6 +118,8 @@ func NewGenerateCommand() *cobra.Command {
 	// Add subcommands for generation
 	cmd.AddCommand(NewGenerateModuleCommand())
 	cmd.AddCommand(NewGenerateConfigCommand())
+	cmd.AddCommand(NewGenerateProxyCommand())
+	cmd.AddCommand(NewGenerateEventsCommand())
 
 	return cmd
 }
@@ -0,0 +1,304 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/CrisisTextLine/modular/cmd/modcli/internal/tenantconfig"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// ErrInvalidTenantID is returned for tenant IDs the file-based tenant loader wouldn't pick up
+	ErrInvalidTenantID = errors.New("invalid tenant ID")
+	// ErrTenantConfigExists is returned when a tenant config file would be overwritten
+	ErrTenantConfigExists = errors.New("tenant config file already exists")
+	// ErrTenantConfigInvalid is returned when validation finds problems in tenant config files
+	ErrTenantConfigInvalid = errors.New("invalid tenant configuration")
+)
+
+// tenantIDPattern matches the tenant IDs of files found by modular.DefaultTenantConfigLoader
+var tenantIDPattern = regexp.MustCompile(`^\w+$`)
+
+// tenantFilePattern matches the file names modular.DefaultTenantConfigLoader reads
+var tenantFilePattern = regexp.MustCompile(`^\w+\.(json|yaml|yml|toml|ini)$`)
+
+// NewTenantCommand creates the tenant command
+func NewTenantCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tenant",
+		Short: "Generate and validate per-tenant configuration files",
+		Long: `Work with the per-tenant configuration files read by the file-based tenant
+config loader. Config sections are described by the modules' config structs,
+loaded from source as seen from the Go module in --project, so the modules must
+be dependencies of that module.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			_ = cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(NewTenantNewCommand())
+	cmd.AddCommand(NewTenantValidateCommand())
+
+	return cmd
+}
+
+// NewTenantNewCommand creates the 'tenant new' command
+func NewTenantNewCommand() *cobra.Command {
+	var (
+		project  string
+		modules  []string
+		sections []string
+		output   string
+		format   string
+		force    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "new <tenant-id>",
+		Short: "Generate a tenant config file skeleton",
+		Long: `Generate a tenant config file with a section for each module, listing every
+field of its config struct with its default or zero value. Remove the fields the
+tenant doesn't override.
+
+Known modules: ` + strings.Join(knownModuleNames(), ", ") + `
+
+Examples:
+  modcli tenant new acme --modules reverseproxy,cache
+  modcli tenant new acme --modules reverseproxy --format json --output config/tenants
+  modcli tenant new acme --section billing=example.com/app/billing.Config --modules billing`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tenantID := args[0]
+			if !tenantIDPattern.MatchString(tenantID) {
+				return fmt.Errorf("%w: %q may only contain letters, digits and underscores", ErrInvalidTenantID, tenantID)
+			}
+			specs, err := resolveTenantSections(modules, sections)
+			if err != nil {
+				return err
+			}
+			loaded, err := tenantconfig.Load(project, specs)
+			if err != nil {
+				return err
+			}
+			content, err := tenantconfig.Skeleton(loaded, format)
+			if err != nil {
+				return err
+			}
+
+			if output == "-" {
+				if _, err := cmd.OutOrStdout().Write(content); err != nil {
+					return fmt.Errorf("failed to write config: %w", err)
+				}
+				return nil
+			}
+			path := filepath.Join(output, tenantID+"."+format)
+			if _, err := os.Stat(path); err == nil && !force {
+				return fmt.Errorf("%w: %s (use --force to overwrite)", ErrTenantConfigExists, path)
+			}
+			if err := os.MkdirAll(output, 0o750); err != nil {
+				return fmt.Errorf("failed to create %s: %w", output, err)
+			}
+			if err := os.WriteFile(path, content, 0o600); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Generated %s\n", path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&project, "project", ".", "Directory of the Go module depending on the modules")
+	cmd.Flags().StringSliceVarP(&modules, "modules", "m", nil, "Config sections to include, by module name")
+	cmd.Flags().StringSliceVar(&sections, "section", nil, "Custom config sections as section=import/path.Type")
+	cmd.Flags().StringVarP(&output, "output", "o", "tenants", "Directory to write the file to, or - for stdout")
+	cmd.Flags().StringVarP(&format, "format", "f", tenantconfig.FormatYAML, "File format: yaml, json, toml")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing file")
+
+	return cmd
+}
+
+// NewTenantValidateCommand creates the 'tenant validate' command
+func NewTenantValidateCommand() *cobra.Command {
+	var (
+		project      string
+		sections     []string
+		outputFormat string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "validate <dir>",
+		Short: "Validate tenant config files against module config structs",
+		Long: `Load every tenant config file in a directory and check each section against the
+config struct of its module. Unknown sections and fields, values of the wrong
+type and missing required fields are reported; the command fails if any are found.
+INI files are skipped.
+
+Examples:
+  modcli tenant validate config/tenants
+  modcli tenant validate config/tenants --project ./service --format json
+  modcli tenant validate tenants --section billing=example.com/app/billing.Config`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTenantValidate(cmd.OutOrStdout(), args[0], project, sections, outputFormat)
+		},
+	}
+
+	cmd.Flags().StringVar(&project, "project", ".", "Directory of the Go module depending on the modules")
+	cmd.Flags().StringSliceVar(&sections, "section", nil, "Custom config sections as section=import/path.Type")
+	cmd.Flags().StringVar(&outputFormat, "format", "text", "Output format: text, json")
+
+	return cmd
+}
+
+// tenantValidationReport is the JSON output of 'tenant validate'
+type tenantValidationReport struct {
+	Files    []string               `json:"files"`
+	Skipped  []string               `json:"skipped,omitempty"`
+	Problems []tenantconfig.Problem `json:"problems"`
+}
+
+func runTenantValidate(out io.Writer, dir, project string, customSections []string, outputFormat string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	report := tenantValidationReport{Problems: []tenantconfig.Problem{}}
+	var files []*tenantconfig.File
+	for _, entry := range entries {
+		if entry.IsDir() || !tenantFilePattern.MatchString(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if strings.EqualFold(filepath.Ext(path), ".ini") {
+			report.Skipped = append(report.Skipped, path)
+			continue
+		}
+		report.Files = append(report.Files, path)
+		file, err := tenantconfig.ReadFile(path)
+		if err != nil {
+			report.Problems = append(report.Problems, tenantconfig.Problem{File: path, Message: err.Error()})
+			continue
+		}
+		files = append(files, file)
+	}
+
+	// Load the config structs of the sections the files use; unknown sections are
+	// reported by Validate
+	custom, err := parseTenantSections(customSections)
+	if err != nil {
+		return err
+	}
+	var specs []tenantconfig.SectionSpec
+	seen := make(map[string]bool)
+	for _, file := range files {
+		for _, name := range file.SectionNames() {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			if spec, ok := custom[name]; ok {
+				specs = append(specs, spec)
+			} else if spec, ok := tenantconfig.LookupModule(name); ok {
+				specs = append(specs, spec)
+			}
+		}
+	}
+	loaded, err := tenantconfig.Load(project, specs)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*tenantconfig.Section, len(loaded))
+	for _, section := range loaded {
+		byName[section.Name] = section
+	}
+	for _, file := range files {
+		report.Problems = append(report.Problems, file.Validate(byName)...)
+	}
+
+	switch strings.ToLower(outputFormat) {
+	case "json":
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal report: %w", err)
+		}
+		fmt.Fprintln(out, string(encoded))
+	case "text", "txt":
+		writeTenantReportText(out, dir, report)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, outputFormat)
+	}
+
+	if len(report.Problems) > 0 {
+		return fmt.Errorf("%w: %d problem(s) in %s", ErrTenantConfigInvalid, len(report.Problems), dir)
+	}
+	return nil
+}
+
+func writeTenantReportText(out io.Writer, dir string, report tenantValidationReport) {
+	fmt.Fprintf(out, "Validated %d tenant config file(s) in %s\n", len(report.Files), dir)
+	for _, path := range report.Skipped {
+		fmt.Fprintf(out, "[SKIPPED] %s: INI files are not validated\n", path)
+	}
+	if len(report.Problems) == 0 {
+		fmt.Fprintln(out, "All tenant config files are valid.")
+		return
+	}
+	for _, problem := range report.Problems {
+		fmt.Fprintf(out, "[ERROR] %s\n", problem)
+	}
+}
+
+// resolveTenantSections returns the sections named by modules, which are custom
+// sections or known modules.
+func resolveTenantSections(modules, customSections []string) ([]tenantconfig.SectionSpec, error) {
+	custom, err := parseTenantSections(customSections)
+	if err != nil {
+		return nil, err
+	}
+	if len(modules) == 0 {
+		return nil, fmt.Errorf("%w: at least one of --modules is required", tenantconfig.ErrUnknownModule)
+	}
+	specs := make([]tenantconfig.SectionSpec, 0, len(modules))
+	for _, name := range modules {
+		name = strings.TrimSpace(name)
+		if spec, ok := custom[name]; ok {
+			specs = append(specs, spec)
+			continue
+		}
+		spec, ok := tenantconfig.LookupModule(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s (known modules: %s)", tenantconfig.ErrUnknownModule, name, strings.Join(knownModuleNames(), ", "))
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+func parseTenantSections(values []string) (map[string]tenantconfig.SectionSpec, error) {
+	custom := make(map[string]tenantconfig.SectionSpec, len(values))
+	for _, value := range values {
+		spec, err := tenantconfig.ParseSectionSpec(value)
+		if err != nil {
+			return nil, err
+		}
+		custom[spec.Name] = spec
+	}
+	return custom, nil
+}
+
+func knownModuleNames() []string {
+	names := make([]string, 0, len(tenantconfig.KnownModules))
+	for _, spec := range tenantconfig.KnownModules {
+		names = append(names, spec.Name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/CrisisTextLine/modular/cmd/modcli/internal/tenantconfig"
+)
+
+const tenantTestConfig = `package billing
+
+type Config struct {
+	Endpoint string ` + "`yaml:\"endpoint\" json:\"endpoint\" required:\"true\"`" + `
+	Retries  int    ` + "`yaml:\"retries\" json:\"retries\" default:\"3\"`" + `
+}
+`
+
+const tenantTestSection = "billing=example.com/app/billing.Config"
+
+// writeTenantTestProject writes a Go module declaring a config struct for the
+// custom billing section.
+func writeTenantTestProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "billing"), 0o750); err != nil {
+		t.Fatalf("failed to create package dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0o600); err != nil {
+		t.Fatalf("failed to write go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "billing", "billing.go"), []byte(tenantTestConfig), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return dir
+}
+
+func runTenantCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewTenantCommand()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestTenantCommand_NewAndValidate(t *testing.T) {
+	project := writeTenantTestProject(t)
+	tenants := filepath.Join(t.TempDir(), "tenants")
+
+	output, err := runTenantCommand(t, "new", "acme", "--project", project, "--section", tenantTestSection, "--modules", "billing", "--output", tenants)
+	if err != nil {
+		t.Fatalf("tenant new failed: %v\n%s", err, output)
+	}
+	content, err := os.ReadFile(filepath.Join(tenants, "acme.yaml"))
+	if err != nil {
+		t.Fatalf("expected acme.yaml to be written: %v", err)
+	}
+	if !strings.Contains(string(content), "billing:") || !strings.Contains(string(content), "retries: 3") {
+		t.Errorf("unexpected skeleton:\n%s", content)
+	}
+
+	_, err = runTenantCommand(t, "new", "acme", "--project", project, "--section", tenantTestSection, "--modules", "billing", "--output", tenants)
+	if !errors.Is(err, ErrTenantConfigExists) {
+		t.Errorf("expected ErrTenantConfigExists, got %v", err)
+	}
+
+	output, err = runTenantCommand(t, "validate", tenants, "--project", project, "--section", tenantTestSection)
+	if err != nil {
+		t.Fatalf("tenant validate failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "All tenant config files are valid.") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
+
+func TestTenantCommand_NewStdout(t *testing.T) {
+	project := writeTenantTestProject(t)
+
+	output, err := runTenantCommand(t, "new", "acme", "--project", project, "--section", tenantTestSection, "-m", "billing", "-o", "-", "-f", "json")
+	if err != nil {
+		t.Fatalf("tenant new failed: %v\n%s", err, output)
+	}
+	var data map[string]map[string]any
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		t.Fatalf("expected JSON output: %v\n%s", err, output)
+	}
+	if data["billing"]["retries"] != float64(3) {
+		t.Errorf("unexpected skeleton: %v", data)
+	}
+}
+
+func TestTenantCommand_NewErrors(t *testing.T) {
+	project := writeTenantTestProject(t)
+
+	if _, err := runTenantCommand(t, "new", "acme-corp", "--project", project, "-m", "billing"); !errors.Is(err, ErrInvalidTenantID) {
+		t.Errorf("expected ErrInvalidTenantID, got %v", err)
+	}
+	if _, err := runTenantCommand(t, "new", "acme", "--project", project, "-m", "billing"); !errors.Is(err, tenantconfig.ErrUnknownModule) {
+		t.Errorf("expected ErrUnknownModule, got %v", err)
+	}
+	if _, err := runTenantCommand(t, "new", "acme", "--project", project); !errors.Is(err, tenantconfig.ErrUnknownModule) {
+		t.Errorf("expected ErrUnknownModule without modules, got %v", err)
+	}
+	if _, err := runTenantCommand(t, "new", "acme", "--project", project, "--section", "billing"); !errors.Is(err, tenantconfig.ErrInvalidSectionSpec) {
+		t.Errorf("expected ErrInvalidSectionSpec, got %v", err)
+	}
+}
+
+func TestTenantCommand_ValidateReportsProblems(t *testing.T) {
+	project := writeTenantTestProject(t)
+	tenants := t.TempDir()
+	files := map[string]string{
+		"acme.json":  `{"billing": {"retries": "many"}}`,
+		"globex.ini": "[billing]\n",
+		"notes.txt":  "not a tenant",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tenants, name), []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	err := runTenantValidate(buf, tenants, project, []string{tenantTestSection}, "json")
+	if !errors.Is(err, ErrTenantConfigInvalid) {
+		t.Fatalf("expected ErrTenantConfigInvalid, got %v", err)
+	}
+	var report tenantValidationReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("expected valid JSON output: %v\n%s", err, buf.String())
+	}
+	if len(report.Files) != 1 || len(report.Skipped) != 1 {
+		t.Errorf("expected one validated and one skipped file, got %+v", report)
+	}
+	paths := make([]string, 0, len(report.Problems))
+	for _, problem := range report.Problems {
+		paths = append(paths, problem.Path)
+	}
+	if strings.Join(paths, ",") != "billing.retries,billing.endpoint" {
+		t.Errorf("unexpected problems: %+v", report.Problems)
+	}
+
+	buf.Reset()
+	_ = runTenantValidate(buf, tenants, project, []string{tenantTestSection}, "text")
+	if !strings.Contains(buf.String(), "[ERROR]") || !strings.Contains(buf.String(), "[SKIPPED]") {
+		t.Errorf("unexpected text output:\n%s", buf.String())
+	}
+}
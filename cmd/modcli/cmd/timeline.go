@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// ErrTimelineFetch is returned when a timeline can't be fetched from a running application
+var ErrTimelineFetch = errors.New("failed to fetch timeline")
+
+// timelineBarWidth is the width of the bars showing when each step ran
+const timelineBarWidth = 30
+
+// timelineEvent mirrors modular.TimelineEvent as served by modular.NewTimelineHandler.
+type timelineEvent struct {
+	Seq      uint64        `json:"seq"`
+	Kind     string        `json:"kind"`
+	Time     time.Time     `json:"time"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	Module   string        `json:"module,omitempty"`
+	Name     string        `json:"name,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// timelineOptions selects the events rendered by the timeline command
+type timelineOptions struct {
+	kinds   []string
+	module  string
+	slowest int
+	format  string
+}
+
+// NewTimelineCommand creates the timeline command
+func NewTimelineCommand() *cobra.Command {
+	var opts timelineOptions
+
+	cmd := &cobra.Command{
+		Use:   "timeline <file|url|->",
+		Short: "Render an application's lifecycle timeline",
+		Long: `Render the lifecycle timeline of a modular application: configuration
+loading, each module's init, start and stop with its duration, and the services
+and tenants registered. The timeline is read as JSON from a file, from stdin with
+"-", or from a running application serving modular.NewTimelineHandler.
+
+Examples:
+  modcli timeline http://localhost:8080/__timeline
+  modcli timeline timeline.json --kind module.init --kind module.start
+  modcli timeline timeline.json --slowest 5
+  modcli timeline timeline.json --module reverseproxy --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			events, err := readTimeline(cmd.Context(), cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			return renderTimeline(cmd.OutOrStdout(), events, opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&opts.kinds, "kind", nil, "Only show events of these kinds, e.g. module.init")
+	cmd.Flags().StringVar(&opts.module, "module", "", "Only show events concerning this module")
+	cmd.Flags().IntVar(&opts.slowest, "slowest", 0, "Only show the N longest steps, slowest first")
+	cmd.Flags().StringVar(&opts.format, "format", "text", "Output format: text, json")
+
+	return cmd
+}
+
+// readTimeline decodes a timeline from source: a file, "-" for in, or an HTTP(S) URL.
+func readTimeline(ctx context.Context, in io.Reader, source string) ([]timelineEvent, error) {
+	var r io.Reader
+	switch {
+	case source == "-":
+		r = in
+	case strings.HasPrefix(source, "http://"), strings.HasPrefix(source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTimelineFetch, err)
+		}
+		client := &http.Client{Timeout: 10 * time.Second}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrTimelineFetch, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("%w: %s returned %s", ErrTimelineFetch, source, resp.Status)
+		}
+		r = resp.Body
+	default:
+		f, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", source, err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var events []timelineEvent
+	if err := json.NewDecoder(r).Decode(&events); err != nil {
+		return nil, fmt.Errorf("failed to decode timeline from %s: %w", source, err)
+	}
+	return events, nil
+}
+
+// renderTimeline writes the events selected by opts in the requested format.
+func renderTimeline(out io.Writer, events []timelineEvent, opts timelineOptions) error {
+	// Offsets are relative to the whole timeline, not just the selected events
+	var origin, end time.Time
+	for _, e := range events {
+		if origin.IsZero() || e.Time.Before(origin) {
+			origin = e.Time
+		}
+		if stepEnd := e.Time.Add(e.Duration); stepEnd.After(end) {
+			end = stepEnd
+		}
+	}
+
+	selected := make([]timelineEvent, 0, len(events))
+	for _, e := range events {
+		if len(opts.kinds) > 0 && !slices.Contains(opts.kinds, e.Kind) {
+			continue
+		}
+		if opts.module != "" && e.Module != opts.module {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	if opts.slowest > 0 {
+		sort.SliceStable(selected, func(i, j int) bool { return selected[i].Duration > selected[j].Duration })
+		selected = selected[:min(opts.slowest, len(selected))]
+	}
+
+	switch strings.ToLower(opts.format) {
+	case "json":
+		encoded, err := json.MarshalIndent(selected, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal timeline: %w", err)
+		}
+		fmt.Fprintln(out, string(encoded))
+	case "text", "txt":
+		writeTimelineText(out, selected, origin, end.Sub(origin))
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, opts.format)
+	}
+	return nil
+}
+
+func writeTimelineText(out io.Writer, events []timelineEvent, origin time.Time, span time.Duration) {
+	if len(events) == 0 {
+		fmt.Fprintln(out, "No timeline events.")
+		return
+	}
+
+	fmt.Fprintf(out, "%10s  %10s  %-20s  %-*s  %s\n", "OFFSET", "DURATION", "EVENT", timelineBarWidth, "", "SUBJECT")
+	for _, e := range events {
+		duration := ""
+		if e.Duration > 0 {
+			duration = formatTimelineDuration(e.Duration)
+		}
+		subject := strings.TrimSpace(e.Module + " " + e.Name)
+		if e.Error != "" {
+			subject += "  ERROR: " + e.Error
+		}
+		fmt.Fprintf(out, "%10s  %10s  %-20s  %s  %s\n",
+			formatTimelineDuration(e.Time.Sub(origin)), duration, e.Kind, timelineBar(e, origin, span), subject)
+	}
+	fmt.Fprintf(out, "Total: %s\n", formatTimelineDuration(span))
+}
+
+// timelineBar draws when a step ran within the span of the timeline.
+func timelineBar(e timelineEvent, origin time.Time, span time.Duration) string {
+	bar := []rune(strings.Repeat(" ", timelineBarWidth))
+	if span <= 0 {
+		return string(bar)
+	}
+	scale := func(d time.Duration) int {
+		return min(int(float64(d)/float64(span)*timelineBarWidth), timelineBarWidth-1)
+	}
+	from := scale(e.Time.Sub(origin))
+	to := max(scale(e.Time.Add(e.Duration).Sub(origin)), from)
+	for i := from; i <= to; i++ {
+		bar[i] = '█'
+	}
+	if e.Duration == 0 {
+		bar[from] = '|'
+	}
+	return string(bar)
+}
+
+// formatTimelineDuration formats d with three decimals in a fitting unit.
+func formatTimelineDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.3fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%.3fms", float64(d)/float64(time.Millisecond))
+	default:
+		return fmt.Sprintf("%.3fµs", float64(d)/float64(time.Microsecond))
+	}
+}
@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const timelineTestJSON = `[
+  {"seq":1,"kind":"config.loaded","time":"2026-01-01T00:00:00Z","duration_ns":5000000},
+  {"seq":2,"kind":"module.init","time":"2026-01-01T00:00:00.005Z","duration_ns":80000000,"module":"database"},
+  {"seq":3,"kind":"service.registered","time":"2026-01-01T00:00:00.085Z","module":"database","name":"database.service"},
+  {"seq":4,"kind":"module.init","time":"2026-01-01T00:00:00.085Z","duration_ns":10000000,"module":"reverseproxy"},
+  {"seq":5,"kind":"tenant.registered","time":"2026-01-01T00:00:00.095Z","name":"acme"},
+  {"seq":6,"kind":"init.completed","time":"2026-01-01T00:00:00Z","duration_ns":95000000},
+  {"seq":7,"kind":"module.start","time":"2026-01-01T00:00:00.095Z","duration_ns":5000000,"module":"reverseproxy","error":"listen tcp :8080: address already in use"},
+  {"seq":8,"kind":"app.started","time":"2026-01-01T00:00:00.095Z","duration_ns":5000000,"error":"failed to start module reverseproxy"}
+]`
+
+func writeTimelineFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "timeline.json")
+	if err := os.WriteFile(path, []byte(timelineTestJSON), 0600); err != nil {
+		t.Fatalf("failed to write timeline: %v", err)
+	}
+	return path
+}
+
+func runTimelineCommand(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewTimelineCommand()
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestTimelineCommand_RendersText(t *testing.T) {
+	out, err := runTimelineCommand(t, "", writeTimelineFile(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 10 {
+		t.Fatalf("expected header, 8 events and total, got:\n%s", out)
+	}
+	for _, want := range []string{"OFFSET", "DURATION", "EVENT", "SUBJECT"} {
+		if !strings.Contains(lines[0], want) {
+			t.Errorf("expected %q in header %q", want, lines[0])
+		}
+	}
+	if !strings.Contains(lines[2], "5.000ms") || !strings.Contains(lines[2], "80.000ms") || !strings.Contains(lines[2], "database") {
+		t.Errorf("expected offset, duration and module of the database init, got %q", lines[2])
+	}
+	if !strings.Contains(lines[3], "database database.service") || !strings.Contains(lines[3], "|") {
+		t.Errorf("expected instantaneous service registration, got %q", lines[3])
+	}
+	if !strings.Contains(lines[7], "ERROR: listen tcp :8080: address already in use") {
+		t.Errorf("expected the start error, got %q", lines[7])
+	}
+	if lines[9] != "Total: 100.000ms" {
+		t.Errorf("expected total span, got %q", lines[9])
+	}
+}
+
+func TestTimelineCommand_Filters(t *testing.T) {
+	path := writeTimelineFile(t)
+
+	out, err := runTimelineCommand(t, "", path, "--kind", "module.init,module.start", "--slowest", "2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[1], "database") || !strings.Contains(lines[2], "reverseproxy") {
+		t.Errorf("expected the two slowest module steps, slowest first, got:\n%s", out)
+	}
+
+	out, err = runTimelineCommand(t, "", path, "--module", "reverseproxy", "--format", "json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var events []timelineEvent
+	if err := json.Unmarshal([]byte(out), &events); err != nil {
+		t.Fatalf("expected JSON output: %v\n%s", err, out)
+	}
+	if len(events) != 2 || events[0].Kind != "module.init" || events[1].Kind != "module.start" {
+		t.Errorf("expected the reverseproxy init and start, got %+v", events)
+	}
+
+	out, err = runTimelineCommand(t, "", path, "--module", "missing")
+	if err != nil || !strings.Contains(out, "No timeline events.") {
+		t.Errorf("expected no events, got %v:\n%s", err, out)
+	}
+}
+
+func TestTimelineCommand_Sources(t *testing.T) {
+	out, err := runTimelineCommand(t, timelineTestJSON, "-", "--kind", "tenant.registered")
+	if err != nil || !strings.Contains(out, "acme") {
+		t.Errorf("expected the timeline read from stdin, got %v:\n%s", err, out)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/__timeline" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(timelineTestJSON))
+	}))
+	defer server.Close()
+
+	out, err = runTimelineCommand(t, "", server.URL+"/__timeline", "--kind", "config.loaded")
+	if err != nil || !strings.Contains(out, "config.loaded") {
+		t.Errorf("expected the timeline fetched over HTTP, got %v:\n%s", err, out)
+	}
+
+	_, err = runTimelineCommand(t, "", server.URL+"/missing")
+	if !errors.Is(err, ErrTimelineFetch) {
+		t.Errorf("expected ErrTimelineFetch, got %v", err)
+	}
+}
+
+func TestTimelineCommand_Errors(t *testing.T) {
+	if _, err := runTimelineCommand(t, "", writeTimelineFile(t), "--format", "xml"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+	if _, err := runTimelineCommand(t, "{", "-"); err == nil || !strings.Contains(err.Error(), "failed to decode timeline") {
+		t.Errorf("expected a decode error, got %v", err)
+	}
+	if _, err := runTimelineCommand(t, "", filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,276 @@
+// Package compat inspects go.mod files for Modular framework dependencies and
+// reports version compatibility problems, available upgrades, and breaking
+// change notes based on metadata embedded in the CLI.
+package compat
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+//go:embed metadata.json
+var embeddedMetadata []byte
+
+// Define static errors
+var (
+	ErrInvalidMetadata = errors.New("invalid compatibility metadata")
+	ErrNilModFile      = errors.New("go.mod file cannot be nil")
+)
+
+// Severity describes how serious a finding is
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+)
+
+// FindingKind categorizes a finding
+type FindingKind string
+
+const (
+	KindIncompatible   FindingKind = "incompatible"
+	KindUpgrade        FindingKind = "upgrade"
+	KindBreakingChange FindingKind = "breaking_change"
+	KindDeprecatedPath FindingKind = "deprecated_path"
+)
+
+// BreakingChange describes a breaking change introduced in a specific version
+type BreakingChange struct {
+	Version string `json:"version"`
+	Note    string `json:"note"`
+}
+
+// ModuleInfo is the embedded metadata for a single Modular Go module
+type ModuleInfo struct {
+	Path            string           `json:"path"`
+	Latest          string           `json:"latest"`
+	MinCore         string           `json:"min_core,omitempty"`
+	ReplacedBy      string           `json:"replaced_by,omitempty"`
+	BreakingChanges []BreakingChange `json:"breaking_changes,omitempty"`
+}
+
+// Metadata is the full set of compatibility metadata
+type Metadata struct {
+	CoreModule string       `json:"core_module"`
+	Modules    []ModuleInfo `json:"modules"`
+}
+
+// Finding is a single compatibility observation about a go.mod requirement
+type Finding struct {
+	Kind      FindingKind `json:"kind"`
+	Severity  Severity    `json:"severity"`
+	Module    string      `json:"module"`
+	Current   string      `json:"current,omitempty"`
+	Suggested string      `json:"suggested,omitempty"`
+	Message   string      `json:"message"`
+}
+
+// Report is the result of checking a go.mod file
+type Report struct {
+	ModulePath string    `json:"module_path,omitempty"`
+	Findings   []Finding `json:"findings"`
+}
+
+// HasErrors reports whether any finding has error severity
+func (r *Report) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadMetadata parses the metadata embedded in the CLI binary
+func LoadMetadata() (*Metadata, error) {
+	return ParseMetadata(embeddedMetadata)
+}
+
+// ParseMetadata parses compatibility metadata from JSON
+func ParseMetadata(data []byte) (*Metadata, error) {
+	var md Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+	}
+	if md.CoreModule == "" {
+		return nil, fmt.Errorf("%w: core_module is required", ErrInvalidMetadata)
+	}
+	for _, m := range md.Modules {
+		if m.Path == "" || !semver.IsValid(m.Latest) {
+			return nil, fmt.Errorf("%w: module %q has invalid latest version %q", ErrInvalidMetadata, m.Path, m.Latest)
+		}
+	}
+	return &md, nil
+}
+
+// Lookup returns the metadata for a module path
+func (md *Metadata) Lookup(path string) (ModuleInfo, bool) {
+	for _, m := range md.Modules {
+		if m.Path == path {
+			return m, true
+		}
+	}
+	return ModuleInfo{}, false
+}
+
+// Checker evaluates go.mod requirements against compatibility metadata
+type Checker struct {
+	Metadata *Metadata
+}
+
+// NewChecker creates a checker using the given metadata
+func NewChecker(md *Metadata) *Checker {
+	return &Checker{Metadata: md}
+}
+
+// Check inspects the requirements of a parsed go.mod file
+func (c *Checker) Check(f *modfile.File) (*Report, error) {
+	if f == nil {
+		return nil, ErrNilModFile
+	}
+
+	report := &Report{Findings: []Finding{}}
+	if f.Module != nil {
+		report.ModulePath = f.Module.Mod.Path
+	}
+
+	required := make(map[string]string)
+	for _, req := range f.Require {
+		if c.isModularPath(req.Mod.Path) {
+			required[req.Mod.Path] = req.Mod.Version
+		}
+	}
+
+	paths := make([]string, 0, len(required))
+	for p := range required {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	coreVersion := required[c.Metadata.CoreModule]
+
+	for _, path := range paths {
+		current := required[path]
+		info, ok := c.Metadata.Lookup(path)
+		if !ok {
+			continue
+		}
+
+		if info.ReplacedBy != "" {
+			report.Findings = append(report.Findings, Finding{
+				Kind:      KindDeprecatedPath,
+				Severity:  SeverityWarning,
+				Module:    path,
+				Current:   current,
+				Suggested: info.ReplacedBy,
+				Message:   fmt.Sprintf("module path is superseded by %s; update imports and go.mod manually", info.ReplacedBy),
+			})
+		}
+
+		if info.MinCore != "" && coreVersion != "" && semver.Compare(coreVersion, info.MinCore) < 0 {
+			report.Findings = append(report.Findings, Finding{
+				Kind:      KindIncompatible,
+				Severity:  SeverityError,
+				Module:    path,
+				Current:   current,
+				Suggested: info.MinCore,
+				Message:   fmt.Sprintf("requires %s >= %s, found %s", c.Metadata.CoreModule, info.MinCore, coreVersion),
+			})
+		}
+
+		if semver.Compare(current, info.Latest) < 0 {
+			report.Findings = append(report.Findings, Finding{
+				Kind:      KindUpgrade,
+				Severity:  SeverityInfo,
+				Module:    path,
+				Current:   current,
+				Suggested: info.Latest,
+				Message:   fmt.Sprintf("upgrade available: %s -> %s", current, info.Latest),
+			})
+
+			for _, bc := range info.BreakingChanges {
+				if semver.Compare(current, bc.Version) < 0 && semver.Compare(bc.Version, info.Latest) <= 0 {
+					report.Findings = append(report.Findings, Finding{
+						Kind:      KindBreakingChange,
+						Severity:  SeverityWarning,
+						Module:    path,
+						Current:   current,
+						Suggested: bc.Version,
+						Message:   bc.Note,
+					})
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Fix applies the version changes suggested by a report to the go.mod file.
+// Upgrades are applied to every module with a newer release and the core
+// module is raised to satisfy the highest minimum requirement. Deprecated
+// module paths are left untouched because they require import rewrites.
+// It returns the list of applied changes in "path old -> new" form.
+func (c *Checker) Fix(f *modfile.File, report *Report) ([]string, error) {
+	if f == nil {
+		return nil, ErrNilModFile
+	}
+
+	targets := make(map[string]string)
+	current := make(map[string]string)
+	for _, finding := range report.Findings {
+		switch finding.Kind {
+		case KindUpgrade:
+			raiseTarget(targets, finding.Module, finding.Suggested)
+			current[finding.Module] = finding.Current
+		case KindIncompatible:
+			raiseTarget(targets, c.Metadata.CoreModule, finding.Suggested)
+		}
+	}
+
+	for _, req := range f.Require {
+		if _, ok := targets[req.Mod.Path]; ok {
+			current[req.Mod.Path] = req.Mod.Version
+		}
+	}
+
+	paths := make([]string, 0, len(targets))
+	for p := range targets {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var applied []string
+	for _, path := range paths {
+		target := targets[path]
+		if semver.Compare(current[path], target) >= 0 {
+			continue
+		}
+		if err := f.AddRequire(path, target); err != nil {
+			return applied, fmt.Errorf("failed to update %s: %w", path, err)
+		}
+		applied = append(applied, fmt.Sprintf("%s %s -> %s", path, current[path], target))
+	}
+
+	f.Cleanup()
+	return applied, nil
+}
+
+func raiseTarget(targets map[string]string, path, version string) {
+	if existing, ok := targets[path]; !ok || semver.Compare(version, existing) > 0 {
+		targets[path] = version
+	}
+}
+
+func (c *Checker) isModularPath(path string) bool {
+	return path == c.Metadata.CoreModule || strings.HasPrefix(path, c.Metadata.CoreModule+"/")
+}
@@ -0,0 +1,147 @@
+package compat
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/mod/modfile"
+)
+
+const testMetadata = `{
+  "core_module": "github.com/CrisisTextLine/modular",
+  "modules": [
+    {"path": "github.com/CrisisTextLine/modular", "latest": "v1.11.0"},
+    {"path": "github.com/CrisisTextLine/modular/modules/cache", "latest": "v0.3.0", "min_core": "v1.10.0",
+     "breaking_changes": [{"version": "v0.2.0", "note": "cache engine config renamed"}]},
+    {"path": "github.com/CrisisTextLine/modular/modules/eventbus", "latest": "v1.6.0",
+     "replaced_by": "github.com/CrisisTextLine/modular/modules/eventbus/v2"}
+  ]
+}`
+
+const testGoMod = `module example.com/app
+
+go 1.25
+
+require (
+	github.com/CrisisTextLine/modular v1.9.0
+	github.com/CrisisTextLine/modular/modules/cache v0.1.0
+	github.com/CrisisTextLine/modular/modules/eventbus v1.6.0
+	github.com/stretchr/testify v1.11.1
+)
+`
+
+func parseTestInputs(t *testing.T) (*Checker, *modfile.File) {
+	t.Helper()
+	md, err := ParseMetadata([]byte(testMetadata))
+	if err != nil {
+		t.Fatalf("failed to parse metadata: %v", err)
+	}
+	f, err := modfile.Parse("go.mod", []byte(testGoMod), nil)
+	if err != nil {
+		t.Fatalf("failed to parse go.mod: %v", err)
+	}
+	return NewChecker(md), f
+}
+
+func TestLoadMetadata_Embedded(t *testing.T) {
+	md, err := LoadMetadata()
+	if err != nil {
+		t.Fatalf("embedded metadata should be valid: %v", err)
+	}
+	if _, ok := md.Lookup(md.CoreModule); !ok {
+		t.Errorf("embedded metadata should describe the core module")
+	}
+}
+
+func TestParseMetadata_Invalid(t *testing.T) {
+	if _, err := ParseMetadata([]byte(`{"modules": []}`)); err == nil {
+		t.Error("expected error for missing core_module")
+	}
+	if _, err := ParseMetadata([]byte(`{"core_module": "x", "modules": [{"path": "x", "latest": "latest"}]}`)); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	checker, f := parseTestInputs(t)
+
+	report, err := checker.Check(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.ModulePath != "example.com/app" {
+		t.Errorf("expected module path example.com/app, got %s", report.ModulePath)
+	}
+
+	kinds := make(map[FindingKind]int)
+	for _, finding := range report.Findings {
+		kinds[finding.Kind]++
+		if strings.Contains(finding.Module, "testify") {
+			t.Errorf("non-modular dependency should be ignored: %+v", finding)
+		}
+	}
+
+	if kinds[KindIncompatible] != 1 {
+		t.Errorf("expected 1 incompatibility finding, got %d", kinds[KindIncompatible])
+	}
+	if kinds[KindUpgrade] != 2 {
+		t.Errorf("expected 2 upgrade findings (core and cache), got %d", kinds[KindUpgrade])
+	}
+	if kinds[KindBreakingChange] != 1 {
+		t.Errorf("expected 1 breaking change finding, got %d", kinds[KindBreakingChange])
+	}
+	if kinds[KindDeprecatedPath] != 1 {
+		t.Errorf("expected 1 deprecated path finding, got %d", kinds[KindDeprecatedPath])
+	}
+	if !report.HasErrors() {
+		t.Error("expected report to contain errors")
+	}
+}
+
+func TestChecker_Fix(t *testing.T) {
+	checker, f := parseTestInputs(t)
+
+	report, err := checker.Check(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	applied, err := checker.Fix(f, report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(applied) != 2 {
+		t.Fatalf("expected 2 applied changes, got %d: %v", len(applied), applied)
+	}
+
+	versions := make(map[string]string)
+	for _, req := range f.Require {
+		versions[req.Mod.Path] = req.Mod.Version
+	}
+	if v := versions["github.com/CrisisTextLine/modular"]; v != "v1.11.0" {
+		t.Errorf("expected core to be upgraded to v1.11.0, got %s", v)
+	}
+	if v := versions["github.com/CrisisTextLine/modular/modules/cache"]; v != "v0.3.0" {
+		t.Errorf("expected cache to be upgraded to v0.3.0, got %s", v)
+	}
+	if v := versions["github.com/CrisisTextLine/modular/modules/eventbus"]; v != "v1.6.0" {
+		t.Errorf("deprecated path should not be rewritten, got %s", v)
+	}
+
+	// Re-checking after fix should leave no errors
+	report, err = checker.Check(f)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.HasErrors() {
+		t.Errorf("expected no errors after fix, got %+v", report.Findings)
+	}
+}
+
+func TestChecker_NilModFile(t *testing.T) {
+	checker, _ := parseTestInputs(t)
+	if _, err := checker.Check(nil); err == nil {
+		t.Error("expected error for nil go.mod")
+	}
+}
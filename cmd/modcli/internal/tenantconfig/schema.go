@@ -0,0 +1,308 @@
+// Package tenantconfig loads the config structs of Modular modules from source and
+// uses them to generate and validate per-tenant configuration files, as read by the
+// framework's file-based tenant config loader, and application configuration files.
+package tenantconfig
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// Define static errors
+var (
+	ErrUnknownModule       = errors.New("unknown module")
+	ErrInvalidSectionSpec  = errors.New("invalid config section spec")
+	ErrConfigTypeNotFound  = errors.New("config type not found")
+	ErrUnsupportedFormat   = errors.New("unsupported config format")
+	ErrConfigPackageFailed = errors.New("failed to load config package")
+)
+
+// Kind classifies a config field by how its values are written in config files
+type Kind string
+
+const (
+	KindString   Kind = "string"
+	KindBool     Kind = "bool"
+	KindInt      Kind = "int"
+	KindUint     Kind = "uint"
+	KindFloat    Kind = "float"
+	KindDuration Kind = "duration"
+	KindStruct   Kind = "struct"
+	KindSlice    Kind = "slice"
+	KindMap      Kind = "map"
+	KindAny      Kind = "any"
+)
+
+// SectionSpec names the Go type holding a config section
+type SectionSpec struct {
+	Name       string `json:"name"`
+	ImportPath string `json:"import_path"`
+	TypeName   string `json:"type_name"`
+}
+
+// KnownModules lists the config sections of the modules shipped with Modular
+var KnownModules = []SectionSpec{
+	{Name: "auth", ImportPath: "github.com/CrisisTextLine/modular/modules/auth", TypeName: "Config"},
+	{Name: "cache", ImportPath: "github.com/CrisisTextLine/modular/modules/cache", TypeName: "CacheConfig"},
+	{Name: "chimux", ImportPath: "github.com/CrisisTextLine/modular/modules/chimux", TypeName: "ChiMuxConfig"},
+	{Name: "database", ImportPath: "github.com/CrisisTextLine/modular/modules/database/v2", TypeName: "Config"},
+	{Name: "diagnostics", ImportPath: "github.com/CrisisTextLine/modular/modules/diagnostics", TypeName: "DiagnosticsConfig"},
+	{Name: "eventbus", ImportPath: "github.com/CrisisTextLine/modular/modules/eventbus/v2", TypeName: "EventBusConfig"},
+	{Name: "eventlogger", ImportPath: "github.com/CrisisTextLine/modular/modules/eventlogger", TypeName: "EventLoggerConfig"},
+	{Name: "httpclient", ImportPath: "github.com/CrisisTextLine/modular/modules/httpclient", TypeName: "Config"},
+	{Name: "httpserver", ImportPath: "github.com/CrisisTextLine/modular/modules/httpserver", TypeName: "HTTPServerConfig"},
+	{Name: "letsencrypt", ImportPath: "github.com/CrisisTextLine/modular/modules/letsencrypt", TypeName: "LetsEncryptConfig"},
+	{Name: "locks", ImportPath: "github.com/CrisisTextLine/modular/modules/locks", TypeName: "LocksConfig"},
+	{Name: "logmasker", ImportPath: "github.com/CrisisTextLine/modular/modules/logmasker", TypeName: "LogMaskerConfig"},
+	{Name: "pinger", ImportPath: "github.com/CrisisTextLine/modular/modules/pinger", TypeName: "PingerConfig"},
+	{Name: "reverseproxy", ImportPath: "github.com/CrisisTextLine/modular/modules/reverseproxy/v2", TypeName: "ReverseProxyConfig"},
+	{Name: "scheduler", ImportPath: "github.com/CrisisTextLine/modular/modules/scheduler", TypeName: "SchedulerConfig"},
+}
+
+// LookupModule returns the config section of a known module
+func LookupModule(name string) (SectionSpec, bool) {
+	for _, spec := range KnownModules {
+		if spec.Name == name {
+			return spec, true
+		}
+	}
+	return SectionSpec{}, false
+}
+
+// ParseSectionSpec parses a custom section given as "section=import/path.Type"
+func ParseSectionSpec(s string) (SectionSpec, error) {
+	name, typePath, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return SectionSpec{}, fmt.Errorf("%w: %q, expected section=import/path.Type", ErrInvalidSectionSpec, s)
+	}
+	spec, err := ParseTypeSpec(typePath)
+	if err != nil {
+		return SectionSpec{}, fmt.Errorf("%w: %q, expected section=import/path.Type", ErrInvalidSectionSpec, s)
+	}
+	spec.Name = name
+	return spec, nil
+}
+
+// ParseTypeSpec parses a config struct given as "import/path.Type", returning a
+// spec without a section name.
+func ParseTypeSpec(typePath string) (SectionSpec, error) {
+	dot := strings.LastIndex(typePath, ".")
+	if dot <= 0 || dot == len(typePath)-1 || dot < strings.LastIndex(typePath, "/") {
+		return SectionSpec{}, fmt.Errorf("%w: %q, expected import/path.Type", ErrInvalidSectionSpec, typePath)
+	}
+	return SectionSpec{ImportPath: typePath[:dot], TypeName: typePath[dot+1:]}, nil
+}
+
+// Field describes a field of a config struct
+type Field struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Kind        Kind   `json:"kind"`
+	Default     string `json:"default,omitempty"`
+	HasDefault  bool   `json:"has_default,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Nullable fields are pointers, which stay unset when left out
+	Nullable bool `json:"nullable,omitempty"`
+	// Fields are those of struct fields
+	Fields []*Field `json:"fields,omitempty"`
+	// Elem describes the elements of slice and map fields
+	Elem *Field `json:"elem,omitempty"`
+
+	tag reflect.StructTag
+}
+
+// Key returns the key of the field in files of the given format, or false when the
+// format's tag excludes the field. Untagged fields use the field name, as the
+// framework's feeders do.
+func (f *Field) Key(format string) (string, bool) {
+	tag, ok := f.tag.Lookup(format)
+	if !ok {
+		return f.Name, true
+	}
+	key, _, _ := strings.Cut(tag, ",")
+	if key == "-" {
+		return "", false
+	}
+	if key == "" {
+		return f.Name, true
+	}
+	return key, true
+}
+
+// Section is a config section and the fields of its struct
+type Section struct {
+	SectionSpec
+	Fields []*Field `json:"fields"`
+}
+
+// Load type-checks the packages of specs as seen from the Go module in dir and
+// describes their config structs. The packages must be dependencies of that module.
+func Load(dir string, specs []SectionSpec) ([]*Section, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	paths := make(map[string]bool)
+	for _, spec := range specs {
+		paths[spec.ImportPath] = true
+	}
+	patterns := make([]string, 0, len(paths))
+	for path := range paths {
+		patterns = append(patterns, path)
+	}
+	sort.Strings(patterns)
+
+	// Dependencies are type-checked from source so loading doesn't depend on the
+	// export data format of the installed toolchain
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrConfigPackageFailed, err)
+	}
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, pkg := range pkgs {
+		byPath[pkg.PkgPath] = pkg
+	}
+
+	sections := make([]*Section, 0, len(specs))
+	for _, spec := range specs {
+		pkg := byPath[spec.ImportPath]
+		if pkg == nil || pkg.Types == nil {
+			return nil, fmt.Errorf("%w: %s is not a dependency of the module in %s", ErrConfigPackageFailed, spec.ImportPath, dir)
+		}
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("%w: %s: %w", ErrConfigPackageFailed, spec.ImportPath, pkg.Errors[0])
+		}
+		obj, ok := pkg.Types.Scope().Lookup(spec.TypeName).(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.%s", ErrConfigTypeNotFound, spec.ImportPath, spec.TypeName)
+		}
+		st, ok := obj.Type().Underlying().(*types.Struct)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s.%s is not a struct", ErrConfigTypeNotFound, spec.ImportPath, spec.TypeName)
+		}
+		d := &describer{visiting: map[*types.Named]bool{}}
+		if named, ok := obj.Type().(*types.Named); ok {
+			d.visiting[named] = true
+		}
+		sections = append(sections, &Section{SectionSpec: spec, Fields: d.structFields(st)})
+	}
+	return sections, nil
+}
+
+// describer turns go/types struct types into Fields, describing recursive types
+// as KindAny where they recur.
+type describer struct {
+	visiting map[*types.Named]bool
+}
+
+func (d *describer) structFields(st *types.Struct) []*Field {
+	fields := make([]*Field, 0, st.NumFields())
+	for i := range st.NumFields() {
+		v := st.Field(i)
+		if !v.Exported() {
+			continue
+		}
+		tag := reflect.StructTag(st.Tag(i))
+		field := d.describe(v.Type())
+		if field == nil {
+			continue
+		}
+		field.Name = v.Name()
+		field.tag = tag
+		field.Default, field.HasDefault = tag.Lookup("default")
+		field.Required = tag.Get("required") == "true"
+		field.Description = tag.Get("desc")
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// describe returns the field for values of t, or nil for types that can't be
+// configured, such as funcs and channels.
+func (d *describer) describe(t types.Type) *Field {
+	field := &Field{Type: types.TypeString(t, func(pkg *types.Package) string { return pkg.Name() })}
+
+	if named, ok := t.(*types.Named); ok {
+		if obj := named.Obj(); obj.Pkg() != nil && obj.Pkg().Path() == "time" {
+			switch obj.Name() {
+			case "Duration":
+				field.Kind = KindDuration
+				return field
+			case "Time":
+				field.Kind = KindString
+				return field
+			}
+		}
+	}
+
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			field.Kind = KindBool
+		case u.Info()&types.IsUnsigned != 0:
+			field.Kind = KindUint
+		case u.Info()&types.IsInteger != 0:
+			field.Kind = KindInt
+		case u.Info()&types.IsFloat != 0:
+			field.Kind = KindFloat
+		case u.Info()&types.IsString != 0:
+			field.Kind = KindString
+		default:
+			return nil
+		}
+	case *types.Struct:
+		if named, ok := t.(*types.Named); ok {
+			if d.visiting[named] {
+				field.Kind = KindAny
+				return field
+			}
+			d.visiting[named] = true
+			defer delete(d.visiting, named)
+		}
+		field.Kind = KindStruct
+		field.Fields = d.structFields(u)
+	case *types.Pointer:
+		elem := d.describe(u.Elem())
+		if elem == nil {
+			return nil
+		}
+		elem.Type = field.Type
+		elem.Nullable = true
+		return elem
+	case *types.Slice:
+		if basic, ok := u.Elem().Underlying().(*types.Basic); ok && basic.Kind() == types.Byte {
+			field.Kind = KindString
+			return field
+		}
+		if field.Elem = d.describe(u.Elem()); field.Elem == nil {
+			return nil
+		}
+		field.Kind = KindSlice
+	case *types.Array:
+		if field.Elem = d.describe(u.Elem()); field.Elem == nil {
+			return nil
+		}
+		field.Kind = KindSlice
+	case *types.Map:
+		if field.Elem = d.describe(u.Elem()); field.Elem == nil {
+			return nil
+		}
+		field.Kind = KindMap
+	case *types.Interface:
+		field.Kind = KindAny
+	default:
+		return nil
+	}
+	return field
+}
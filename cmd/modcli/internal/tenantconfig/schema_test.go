@@ -0,0 +1,164 @@
+package tenantconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testConfigSource = `package billing
+
+import "time"
+
+type Config struct {
+	Endpoint string        ` + "`yaml:\"endpoint\" json:\"endpoint\" toml:\"endpoint\" required:\"true\" desc:\"Billing API endpoint\"`" + `
+	Timeout  time.Duration ` + "`yaml:\"timeout\" json:\"timeout\" toml:\"timeout\" default:\"5s\"`" + `
+	Retries  uint          ` + "`yaml:\"retries\" json:\"retries\" toml:\"retries\" default:\"3\"`" + `
+	Currencies []string    ` + "`yaml:\"currencies\" json:\"currencies\" toml:\"currencies\" default:\"[\\\"EUR\\\"]\"`" + `
+	Plans    map[string]Plan ` + "`yaml:\"plans\" json:\"plans\" toml:\"plans\"`" + `
+	Limits   Limits        ` + "`yaml:\"limits\" json:\"limits\" toml:\"limits\"`" + `
+	Override *Limits       ` + "`yaml:\"override\" json:\"override\" toml:\"override\"`" + `
+	Secret   string        ` + "`yaml:\"-\" json:\"-\" toml:\"-\"`" + `
+	Untagged bool
+	Hook     func()
+	internal string
+}
+
+type Plan struct {
+	Price float64 ` + "`yaml:\"price\" json:\"price\" toml:\"price\"`" + `
+}
+
+type Limits struct {
+	Daily int    ` + "`yaml:\"daily\" json:\"daily\" toml:\"daily\"`" + `
+	Next  *Limits ` + "`yaml:\"next\" json:\"next\" toml:\"next\"`" + `
+}
+
+type NotAStruct string
+`
+
+// writeTestProject writes a Go module declaring the billing config struct.
+func writeTestProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":             "module example.com/app\n\ngo 1.25\n",
+		"billing/billing.go": testConfigSource,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+var billingSpec = SectionSpec{Name: "billing", ImportPath: "example.com/app/billing", TypeName: "Config"}
+
+func loadBilling(t *testing.T) *Section {
+	t.Helper()
+	sections, err := Load(writeTestProject(t), []SectionSpec{billingSpec})
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return sections[0]
+}
+
+func TestLoad(t *testing.T) {
+	section := loadBilling(t)
+
+	var names []string
+	fields := make(map[string]*Field)
+	for _, field := range section.Fields {
+		names = append(names, field.Name)
+		fields[field.Name] = field
+	}
+	want := []string{"Endpoint", "Timeout", "Retries", "Currencies", "Plans", "Limits", "Override", "Secret", "Untagged"}
+	if len(names) != len(want) {
+		t.Fatalf("fields = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("fields = %v, want %v", names, want)
+		}
+	}
+
+	checks := []struct {
+		field string
+		kind  Kind
+	}{
+		{"Endpoint", KindString}, {"Timeout", KindDuration}, {"Retries", KindUint}, {"Currencies", KindSlice},
+		{"Plans", KindMap}, {"Limits", KindStruct}, {"Override", KindStruct}, {"Untagged", KindBool},
+	}
+	for _, check := range checks {
+		if got := fields[check.field].Kind; got != check.kind {
+			t.Errorf("%s kind = %s, want %s", check.field, got, check.kind)
+		}
+	}
+	if !fields["Endpoint"].Required || fields["Endpoint"].Description != "Billing API endpoint" {
+		t.Errorf("Endpoint tags not read: %+v", fields["Endpoint"])
+	}
+	if !fields["Override"].Nullable || fields["Limits"].Nullable {
+		t.Error("only pointer fields are nullable")
+	}
+	if next := fields["Limits"].Fields[1]; next.Kind != KindAny {
+		t.Errorf("recursive field kind = %s, want %s", next.Kind, KindAny)
+	}
+	if fields["Plans"].Elem.Fields[0].Kind != KindFloat {
+		t.Errorf("map elements not described: %+v", fields["Plans"].Elem)
+	}
+
+	if key, ok := fields["Secret"].Key(FormatYAML); ok {
+		t.Errorf("excluded field has key %q", key)
+	}
+	if key, _ := fields["Untagged"].Key(FormatJSON); key != "Untagged" {
+		t.Errorf("untagged field key = %q, want the field name", key)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	dir := writeTestProject(t)
+
+	_, err := Load(dir, []SectionSpec{{Name: "x", ImportPath: "example.com/app/billing", TypeName: "Missing"}})
+	if !errors.Is(err, ErrConfigTypeNotFound) {
+		t.Errorf("expected ErrConfigTypeNotFound, got %v", err)
+	}
+	_, err = Load(dir, []SectionSpec{{Name: "x", ImportPath: "example.com/app/billing", TypeName: "NotAStruct"}})
+	if !errors.Is(err, ErrConfigTypeNotFound) {
+		t.Errorf("expected ErrConfigTypeNotFound for a non-struct, got %v", err)
+	}
+	_, err = Load(dir, []SectionSpec{{Name: "x", ImportPath: "example.com/missing", TypeName: "Config"}})
+	if !errors.Is(err, ErrConfigPackageFailed) {
+		t.Errorf("expected ErrConfigPackageFailed, got %v", err)
+	}
+}
+
+func TestParseSectionSpec(t *testing.T) {
+	spec, err := ParseSectionSpec("billing=example.com/app/billing.Config")
+	if err != nil {
+		t.Fatalf("ParseSectionSpec failed: %v", err)
+	}
+	if spec != billingSpec {
+		t.Errorf("spec = %+v, want %+v", spec, billingSpec)
+	}
+
+	for _, invalid := range []string{"billing", "=example.com/app.Config", "billing=example.com/app", "billing=example.com/app.", "billing=example.com/app.v2/billing"} {
+		if _, err := ParseSectionSpec(invalid); !errors.Is(err, ErrInvalidSectionSpec) {
+			t.Errorf("ParseSectionSpec(%q) = %v, want ErrInvalidSectionSpec", invalid, err)
+		}
+	}
+}
+
+func TestLookupModule(t *testing.T) {
+	spec, ok := LookupModule("reverseproxy")
+	if !ok || spec.TypeName != "ReverseProxyConfig" {
+		t.Errorf("LookupModule(reverseproxy) = %+v, %v", spec, ok)
+	}
+	if _, ok := LookupModule("missing"); ok {
+		t.Error("unknown module found")
+	}
+}
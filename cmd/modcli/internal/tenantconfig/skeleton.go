@@ -0,0 +1,223 @@
+package tenantconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported config file formats
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+	FormatTOML = "toml"
+)
+
+// Skeleton returns a tenant config file in format with a section for each of
+// sections, listing every field with its default value or, without one, its zero
+// value. Pointer and interface fields are left unset; in YAML they are written as
+// null and field descriptions are added as comments.
+func Skeleton(sections []*Section, format string) ([]byte, error) {
+	switch format {
+	case FormatYAML, "yml":
+		root := &yaml.Node{Kind: yaml.MappingNode}
+		for _, section := range sections {
+			root.Content = append(root.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Value: section.Name, HeadComment: section.ImportPath + "." + section.TypeName},
+				yamlStruct(section.Fields))
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(root); err != nil {
+			return nil, fmt.Errorf("failed to encode YAML: %w", err)
+		}
+		return buf.Bytes(), nil
+	case FormatJSON:
+		root := make(object, 0, len(sections))
+		for _, section := range sections {
+			root = append(root, member{section.Name, structValue(section.Fields, FormatJSON)})
+		}
+		data, err := json.MarshalIndent(root, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode JSON: %w", err)
+		}
+		return append(data, '\n'), nil
+	case FormatTOML:
+		root := make(map[string]any, len(sections))
+		for _, section := range sections {
+			root[section.Name] = structValue(section.Fields, FormatTOML).toMap()
+		}
+		data, err := toml.Marshal(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode TOML: %w", err)
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// member is a key of an object and its value
+type member struct {
+	key   string
+	value any
+}
+
+// object is a JSON object keeping its members in field order
+type object []member
+
+// MarshalJSON encodes the members in order
+func (o object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(m.key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode key %s: %w", m.key, err)
+		}
+		value, err := json.Marshal(m.value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", m.key, err)
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// toMap converts the object to a map, leaving out unset values, which TOML can't
+// represent.
+func (o object) toMap() map[string]any {
+	m := make(map[string]any, len(o))
+	for _, member := range o {
+		switch value := member.value.(type) {
+		case nil:
+		case object:
+			m[member.key] = value.toMap()
+		default:
+			m[member.key] = value
+		}
+	}
+	return m
+}
+
+// structValue returns the skeleton of a struct's fields for format.
+func structValue(fields []*Field, format string) object {
+	o := make(object, 0, len(fields))
+	for _, field := range fields {
+		if key, ok := field.Key(format); ok {
+			o = append(o, member{key, fieldValue(field, format)})
+		}
+	}
+	return o
+}
+
+// fieldValue returns the skeleton value of a field: its default, or its zero value.
+func fieldValue(field *Field, format string) any {
+	if field.HasDefault {
+		return defaultValue(field)
+	}
+	if field.Nullable {
+		return nil
+	}
+	switch field.Kind {
+	case KindString:
+		return ""
+	case KindBool:
+		return false
+	case KindInt, KindUint, KindFloat:
+		return 0
+	case KindDuration:
+		return "0s"
+	case KindStruct:
+		return structValue(field.Fields, format)
+	case KindSlice:
+		return []any{}
+	case KindMap:
+		return object{}
+	default:
+		return nil
+	}
+}
+
+// defaultValue converts the default tag of a field to a value of its kind. Slice and
+// map defaults are JSON, as the framework reads them.
+func defaultValue(field *Field) any {
+	switch field.Kind {
+	case KindBool:
+		if v, err := strconv.ParseBool(field.Default); err == nil {
+			return v
+		}
+	case KindInt, KindUint:
+		if v, err := strconv.ParseInt(field.Default, 10, 64); err == nil {
+			return v
+		}
+	case KindFloat:
+		if v, err := strconv.ParseFloat(field.Default, 64); err == nil {
+			return v
+		}
+	case KindSlice:
+		items := []any{}
+		_ = json.Unmarshal([]byte(field.Default), &items)
+		return items
+	case KindMap:
+		entries := map[string]any{}
+		_ = json.Unmarshal([]byte(field.Default), &entries)
+		return entries
+	}
+	return field.Default
+}
+
+// yamlStruct returns the mapping node of a struct's fields, commented with their
+// descriptions.
+func yamlStruct(fields []*Field) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, field := range fields {
+		key, ok := field.Key(FormatYAML)
+		if !ok {
+			continue
+		}
+		keyNode := &yaml.Node{Kind: yaml.ScalarNode, Value: key, HeadComment: yamlComment(field)}
+		var valueNode *yaml.Node
+		if field.Kind == KindStruct && !field.HasDefault && !field.Nullable {
+			valueNode = yamlStruct(field.Fields)
+		} else {
+			value := fieldValue(field, FormatYAML)
+			if o, ok := value.(object); ok {
+				value = o.toMap()
+			}
+			valueNode = &yaml.Node{}
+			if err := valueNode.Encode(value); err != nil {
+				valueNode = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+			}
+			if valueNode.Kind == yaml.MappingNode || valueNode.Kind == yaml.SequenceNode {
+				valueNode.Style = yaml.FlowStyle
+			}
+		}
+		node.Content = append(node.Content, keyNode, valueNode)
+	}
+	return node
+}
+
+// yamlComment describes a field for the comment above its key.
+func yamlComment(field *Field) string {
+	var notes []string
+	if field.Description != "" {
+		notes = append(notes, field.Description)
+	}
+	if field.Required && !field.HasDefault {
+		notes = append(notes, "(required)")
+	}
+	return strings.Join(notes, " ")
+}
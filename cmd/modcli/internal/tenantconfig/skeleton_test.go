@@ -0,0 +1,93 @@
+package tenantconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSkeleton_YAML(t *testing.T) {
+	section := loadBilling(t)
+
+	content, err := Skeleton([]*Section{section}, FormatYAML)
+	if err != nil {
+		t.Fatalf("Skeleton failed: %v", err)
+	}
+	want := `# example.com/app/billing.Config
+billing:
+  # Billing API endpoint (required)
+  endpoint: ""
+  timeout: 5s
+  retries: 3
+  currencies: [EUR]
+  plans: {}
+  limits:
+    daily: 0
+    next: null
+  override: null
+  Untagged: false
+`
+	if string(content) != want {
+		t.Errorf("skeleton:\n%s\nwant:\n%s", content, want)
+	}
+}
+
+func TestSkeleton_JSONAndTOML(t *testing.T) {
+	section := loadBilling(t)
+
+	content, err := Skeleton([]*Section{section}, FormatJSON)
+	if err != nil {
+		t.Fatalf("Skeleton failed: %v", err)
+	}
+	for _, want := range []string{`"endpoint": ""`, `"timeout": "5s"`, `"retries": 3`, `"override": null`, `"daily": 0`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("JSON skeleton missing %s:\n%s", want, content)
+		}
+	}
+	if strings.Index(string(content), `"endpoint"`) > strings.Index(string(content), `"timeout"`) {
+		t.Errorf("JSON skeleton doesn't keep field order:\n%s", content)
+	}
+
+	content, err = Skeleton([]*Section{section}, FormatTOML)
+	if err != nil {
+		t.Fatalf("Skeleton failed: %v", err)
+	}
+	for _, want := range []string{"[billing]", "timeout = '5s'", "[billing.limits]"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("TOML skeleton missing %s:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "override") {
+		t.Errorf("TOML skeleton contains unset pointer:\n%s", content)
+	}
+
+	if _, err := Skeleton([]*Section{section}, "ini"); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestSkeleton_Validates(t *testing.T) {
+	section := loadBilling(t)
+	sections := map[string]*Section{"billing": section}
+	dir := t.TempDir()
+
+	for _, format := range []string{FormatYAML, FormatJSON, FormatTOML} {
+		content, err := Skeleton([]*Section{section}, format)
+		if err != nil {
+			t.Fatalf("Skeleton(%s) failed: %v", format, err)
+		}
+		path := filepath.Join(dir, "tenant."+format)
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			t.Fatalf("failed to write %s: %v", path, err)
+		}
+		file, err := ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile(%s) failed: %v", format, err)
+		}
+		if problems := file.Validate(sections); len(problems) != 0 {
+			t.Errorf("%s skeleton has problems: %v", format, problems)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package tenantconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// File is a parsed tenant config file
+type File struct {
+	Path   string
+	Format string
+	// TenantID is the file name without its extension
+	TenantID string
+	Data     map[string]any
+}
+
+// FormatForPath returns the config format of a file by its extension.
+func FormatForPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".toml":
+		return FormatTOML, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedFormat, ext)
+	}
+}
+
+// ReadFile reads and parses a tenant config file.
+func ReadFile(path string) (*File, error) {
+	format, err := FormatForPath(path)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	data := make(map[string]any)
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(content, &data)
+	case FormatJSON:
+		err = json.Unmarshal(content, &data)
+	case FormatTOML:
+		err = toml.Unmarshal(content, &data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	base := filepath.Base(path)
+	return &File{
+		Path:     path,
+		Format:   format,
+		TenantID: strings.TrimSuffix(base, filepath.Ext(base)),
+		Data:     data,
+	}, nil
+}
+
+// SectionNames returns the sorted top-level sections of the file.
+func (f *File) SectionNames() []string {
+	names := make([]string, 0, len(f.Data))
+	for name := range f.Data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Problem is a mistake found in a tenant config file
+type Problem struct {
+	File    string `json:"file"`
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%s: %s", p.File, p.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s", p.File, p.Path, p.Message)
+}
+
+// Validate checks the file against the config structs of sections, keyed by section
+// name. It reports unknown sections and fields, values that can't be converted to
+// their field's type, and required fields without a default that are missing.
+func (f *File) Validate(sections map[string]*Section) []Problem {
+	v := &validator{file: f}
+	for _, name := range f.SectionNames() {
+		section, ok := sections[name]
+		if !ok {
+			v.report(name, "unknown config section")
+			continue
+		}
+		v.checkStruct(section.Fields, f.Data[name], name)
+	}
+	return v.problems
+}
+
+// ValidateApp checks an application config file. Top-level keys naming one of
+// sections are checked against its config struct, as Validate does, and the others
+// against root, the fields of the application's own config struct. Without root,
+// they are reported as unknown sections.
+func (f *File) ValidateApp(root []*Field, sections map[string]*Section) []Problem {
+	if root == nil {
+		return f.Validate(sections)
+	}
+	v := &validator{file: f}
+	rootValues := make(map[string]any)
+	for _, name := range f.SectionNames() {
+		if section, ok := sections[name]; ok {
+			v.checkStruct(section.Fields, f.Data[name], name)
+		} else {
+			rootValues[name] = f.Data[name]
+		}
+	}
+	v.checkStruct(root, rootValues, "")
+	return v.problems
+}
+
+// ValidateSection checks a file holding the fields of a single section at its top
+// level, as read by a module-specific config file.
+func (f *File) ValidateSection(section *Section) []Problem {
+	v := &validator{file: f}
+	v.checkStruct(section.Fields, f.Data, "")
+	return v.problems
+}
+
+// validator collects the problems of a file
+type validator struct {
+	file     *File
+	problems []Problem
+}
+
+func (v *validator) report(path, format string, args ...any) {
+	v.problems = append(v.problems, Problem{File: v.file.Path, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) checkStruct(fields []*Field, value any, path string) {
+	if value == nil {
+		value = map[string]any{}
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		v.report(path, "expected a mapping, got %s", describeValue(value))
+		return
+	}
+
+	known := make(map[string]*Field, len(fields))
+	for _, field := range fields {
+		if key, ok := field.Key(v.file.Format); ok {
+			known[key] = field
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		field, ok := known[key]
+		if !ok {
+			v.report(joinPath(path, key), "unknown field%s", suggestKey(key, known))
+			continue
+		}
+		v.checkValue(field, values[key], joinPath(path, key))
+	}
+
+	for _, field := range fields {
+		key, ok := field.Key(v.file.Format)
+		if !ok || !field.Required || field.HasDefault {
+			continue
+		}
+		if _, set := values[key]; !set {
+			v.report(joinPath(path, key), "missing required field")
+		}
+	}
+}
+
+func (v *validator) checkValue(field *Field, value any, path string) {
+	if value == nil {
+		// Unset values leave the field at its zero value
+		return
+	}
+	switch field.Kind {
+	case KindAny:
+	case KindStruct:
+		v.checkStruct(field.Fields, value, path)
+	case KindSlice:
+		switch items := value.(type) {
+		case []any:
+			for i, item := range items {
+				v.checkValue(field.Elem, item, fmt.Sprintf("%s[%d]", path, i))
+			}
+		case string:
+			// Comma separated lists of scalars
+			if field.Elem.Kind == KindStruct || field.Elem.Kind == KindSlice || field.Elem.Kind == KindMap {
+				v.report(path, "expected a list, got %s", describeValue(value))
+			}
+		default:
+			v.report(path, "expected a list, got %s", describeValue(value))
+		}
+	case KindMap:
+		entries, ok := value.(map[string]any)
+		if !ok {
+			v.report(path, "expected a mapping, got %s", describeValue(value))
+			return
+		}
+		keys := make([]string, 0, len(entries))
+		for key := range entries {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			v.checkValue(field.Elem, entries[key], joinPath(path, key))
+		}
+	default:
+		if err := checkScalar(field.Kind, value); err != nil {
+			v.report(path, "invalid %s: %v", field.Type, err)
+		}
+	}
+}
+
+// joinPath returns the path of key in the value at path, which is empty at the top level.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// checkScalar reports whether value, as decoded from a config file, converts to a
+// field of kind. Strings are converted as the framework's feeders do.
+func checkScalar(kind Kind, value any) error {
+	switch value.(type) {
+	case map[string]any, []any:
+		return fmt.Errorf("expected a single value, got %s", describeValue(value))
+	}
+
+	text, isString := value.(string)
+	switch kind {
+	case KindString:
+		return nil
+	case KindBool:
+		if _, ok := value.(bool); ok {
+			return nil
+		}
+		if isString {
+			_, err := strconv.ParseBool(text)
+			return err
+		}
+	case KindInt, KindUint:
+		if isString {
+			_, err := strconv.ParseInt(text, 10, 64)
+			if kind == KindUint {
+				_, err = strconv.ParseUint(text, 10, 64)
+			}
+			return err
+		}
+		n, ok := toFloat(value)
+		if !ok {
+			break
+		}
+		if n != math.Trunc(n) {
+			return fmt.Errorf("%v is not a whole number", value)
+		}
+		if kind == KindUint && n < 0 {
+			return fmt.Errorf("%v is negative", value)
+		}
+		return nil
+	case KindFloat:
+		if isString {
+			_, err := strconv.ParseFloat(text, 64)
+			return err
+		}
+		if _, ok := toFloat(value); ok {
+			return nil
+		}
+	case KindDuration:
+		if isString {
+			_, err := time.ParseDuration(text)
+			return err
+		}
+		// Numbers are nanoseconds
+		if _, ok := toFloat(value); ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("unexpected %s", describeValue(value))
+}
+
+// toFloat returns a number decoded by any of the supported formats as a float64.
+func toFloat(value any) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// describeValue names the type of a decoded value for problem messages.
+func describeValue(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "a mapping"
+	case []any:
+		return "a list"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case int, int64, uint64, float64:
+		return "a number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// suggestKey points at a known key differing from key only in case or separators.
+func suggestKey(key string, known map[string]*Field) string {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(s))
+	}
+	for candidate := range known {
+		if normalize(candidate) == normalize(key) {
+			return fmt.Sprintf(" (did you mean %q?)", candidate)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package tenantconfig
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTenantFile(t *testing.T, name, content string) *File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	file, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	return file
+}
+
+func TestValidate(t *testing.T) {
+	sections := map[string]*Section{"billing": loadBilling(t)}
+
+	file := writeTenantFile(t, "acme.yaml", `
+billing:
+  endpoint: https://billing.example.com
+  timeout: 5 minutes
+  retries: -1
+  currencies: EUR,USD
+  plans:
+    basic: {price: cheap}
+    pro: {price: 9.5, seats: 3}
+  limits: [1]
+  Override: {}
+shipping: {}
+`)
+	if file.TenantID != "acme" || file.Format != FormatYAML {
+		t.Errorf("file = %+v", file)
+	}
+
+	want := []string{
+		"billing.Override: unknown field (did you mean \"override\"?)",
+		"billing.limits: expected a mapping, got a list",
+		"billing.plans.basic.price: invalid float64: strconv.ParseFloat: parsing \"cheap\": invalid syntax",
+		"billing.plans.pro.seats: unknown field",
+		"billing.retries: invalid uint: -1 is negative",
+		"billing.timeout: invalid time.Duration: time: unknown unit \" minutes\" in duration \"5 minutes\"",
+		"shipping: unknown config section",
+	}
+	problems := file.Validate(sections)
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %v, want %d", problems, len(want))
+	}
+	for i, problem := range problems {
+		if got := problem.Path + ": " + problem.Message; got != want[i] {
+			t.Errorf("problem %d = %q, want %q", i, got, want[i])
+		}
+	}
+}
+
+func TestValidate_MissingRequired(t *testing.T) {
+	sections := map[string]*Section{"billing": loadBilling(t)}
+
+	file := writeTenantFile(t, "acme.json", `{"billing": {"retries": 2, "timeout": 1000000000}}`)
+	problems := file.Validate(sections)
+	if len(problems) != 1 || problems[0].Path != "billing.endpoint" || problems[0].Message != "missing required field" {
+		t.Errorf("problems = %v", problems)
+	}
+
+	file = writeTenantFile(t, "acme.toml", "[billing]\nendpoint = 'x'\nretries = 1.5\n")
+	problems = file.Validate(sections)
+	if len(problems) != 1 || problems[0].Message != "invalid uint: 1.5 is not a whole number" {
+		t.Errorf("problems = %v", problems)
+	}
+}
+
+func TestReadFile_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := ReadFile(filepath.Join(dir, "acme.ini")); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("expected ErrUnsupportedFormat, got %v", err)
+	}
+
+	path := filepath.Join(dir, "acme.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); err == nil {
+		t.Error("expected a parse error")
+	}
+}
@@ -69,7 +69,7 @@ type ModuleAwareFeeder interface {
 //
 // Note: WithPriority() is not part of this interface because it's a builder method
 // that returns the concrete feeder type for method chaining. All standard feeders
-// (EnvFeeder, YamlFeeder, JSONFeeder, TomlFeeder, DotEnvFeeder, AffixedEnvFeeder,
+// (EnvFeeder, YamlFeeder, JSONFeeder, TomlFeeder, IniFeeder, DotEnvFeeder, AffixedEnvFeeder,
 // TenantAffixedEnvFeeder) provide this method with a consistent signature:
 //
 //	WithPriority(priority int) *FeederType
@@ -87,3 +87,17 @@ type InstancePrefixFunc = feeders.InstancePrefixFunc
 func NewInstanceAwareEnvFeeder(prefixFunc InstancePrefixFunc) InstanceAwareFeeder {
 	return feeders.NewInstanceAwareEnvFeeder(prefixFunc)
 }
+
+// SectionFeedersConfigurable is implemented by applications that can feed individual
+// configuration sections from their own feeders instead of the application-wide ones.
+// Modules can use it from RegisterConfig to bind their section to specific sources:
+//
+//	if sfc, ok := app.(modular.SectionFeedersConfigurable); ok {
+//	    sfc.RegisterConfigSectionWithFeeders("database", provider, vaultFeeder, feeders.NewEnvFeeder())
+//	}
+type SectionFeedersConfigurable interface {
+	// RegisterConfigSectionWithFeeders registers a section fed only by the given feeders
+	RegisterConfigSectionWithFeeders(section string, cp ConfigProvider, feeders ...Feeder)
+	// SetSectionFeeders sets the feeders of a section, registered or not; no feeders restores the defaults
+	SetSectionFeeders(section string, feeders ...Feeder)
+}
@@ -0,0 +1,165 @@
+package modular
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// configValue is a single field set with SetConfigValue.
+type configValue struct {
+	section string
+	key     string
+	value   any
+}
+
+// SetConfigOverride replaces the named config section with provider once modules
+// have registered their sections and feeders have run, so the section holds exactly
+// the given config regardless of files and environment variables. Defaults and
+// validation are still applied to it. Sections no module registers are added.
+func (app *StdApplication) SetConfigOverride(section string, provider ConfigProvider) {
+	if app.configOverrides == nil {
+		app.configOverrides = make(map[string]ConfigProvider)
+	}
+	app.configOverrides[section] = provider
+}
+
+// SetConfigValue sets a single field of the named config section once modules have
+// registered their sections, feeders have run and overrides are applied. The key
+// is a dot-separated path of field names or their yaml, json or toml tags, e.g.
+// "server.port" or "Server.Port". Values are assigned directly when their type
+// fits, converted between numeric types, or parsed when given as strings. Required
+// fields and Validate are checked again on the sections set this way.
+func (app *StdApplication) SetConfigValue(section, key string, value any) {
+	app.configValues = append(app.configValues, configValue{section: section, key: key, value: value})
+}
+
+// applyConfigValues sets the fields given to SetConfigValue, in the order they were
+// set, then validates the sections they touched again. Defaults are not reapplied,
+// so a value explicitly set to zero is kept.
+func (app *StdApplication) applyConfigValues() error {
+	var touched []string
+	for _, cv := range app.configValues {
+		var provider ConfigProvider
+		if cv.section == mainConfigSection {
+			provider = app.cfgProvider
+		} else {
+			app.cfgSectionsMu.RLock()
+			provider = app.cfgSections[cv.section]
+			app.cfgSectionsMu.RUnlock()
+		}
+		if provider == nil {
+			return fmt.Errorf("config value %s.%s: %w: %s", cv.section, cv.key, ErrConfigSectionNotFound, cv.section)
+		}
+		if err := setConfigPath(provider.GetConfig(), cv.key, cv.value); err != nil {
+			return fmt.Errorf("config value %s.%s: %w", cv.section, cv.key, err)
+		}
+		if !slices.Contains(touched, cv.section) {
+			touched = append(touched, cv.section)
+		}
+	}
+
+	for _, section := range touched {
+		var cfg any
+		if section == mainConfigSection {
+			cfg = app.cfgProvider.GetConfig()
+		} else {
+			app.cfgSectionsMu.RLock()
+			cfg = app.cfgSections[section].GetConfig()
+			app.cfgSectionsMu.RUnlock()
+		}
+		if err := ValidateConfigRequired(cfg); err != nil {
+			return fmt.Errorf("config values for section %s: %w", section, err)
+		}
+		if validator, ok := cfg.(ConfigValidator); ok {
+			if err := validator.Validate(); err != nil {
+				return fmt.Errorf("config values for section %s: config validation failed: %w", section, err)
+			}
+		}
+	}
+	return nil
+}
+
+// setConfigPath sets the field at the dot-separated path in cfg, which must be a
+// pointer to a struct.
+func setConfigPath(cfg any, path string, value any) error {
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return ErrConfigNotPointer
+	}
+	field := v.Elem()
+	for _, name := range strings.Split(path, ".") {
+		for field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				field.Set(reflect.New(field.Type().Elem()))
+			}
+			field = field.Elem()
+		}
+		if field.Kind() != reflect.Struct {
+			return fmt.Errorf("%w: %s is not a struct", ErrInvalidFieldKind, name)
+		}
+		next, ok := configField(field, name)
+		if !ok {
+			return fmt.Errorf("%w: field %s", ErrConfigFieldNotFound, name)
+		}
+		field = next
+	}
+	if !field.CanSet() {
+		return fmt.Errorf("%w: %s", ErrFieldCannotBeSet, path)
+	}
+	return assignConfigValue(field, value)
+}
+
+// configField returns the field of structValue matching name by field name, case
+// insensitively, or by yaml, json or toml tag.
+func configField(structValue reflect.Value, name string) (reflect.Value, bool) {
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if strings.EqualFold(field.Name, name) {
+			return structValue.Field(i), true
+		}
+		for _, tag := range []string{"yaml", "json", "toml"} {
+			if tagName, _, _ := strings.Cut(field.Tag.Get(tag), ","); tagName == name {
+				return structValue.Field(i), true
+			}
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// assignConfigValue stores value in field.
+func assignConfigValue(field reflect.Value, value any) error {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	case v.Type().AssignableTo(field.Type()):
+		field.Set(v)
+		return nil
+	case isNumericKind(v.Kind()) && isNumericKind(field.Kind()):
+		field.Set(v.Convert(field.Type()))
+		return nil
+	case v.Kind() == reflect.String:
+		return setDefaultValue(field, v.String())
+	default:
+		return fmt.Errorf("%w: cannot assign %s to %s", ErrIncompatibleFieldKind, v.Type(), field.Type())
+	}
+}
+
+// isNumericKind reports whether kind is an integer or floating point kind.
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,123 @@
+package modular
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type overrideTestConfig struct {
+	Name    string        `yaml:"name"`
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+	TLS     *struct {
+		Enabled bool `json:"enabled"`
+	} `yaml:"tls"`
+}
+
+type validatedOverrideConfig struct {
+	Host string `yaml:"host" required:"true"`
+	Port int    `yaml:"port" default:"8080"`
+}
+
+func (c *validatedOverrideConfig) Validate() error {
+	if c.Port < 0 {
+		return fmt.Errorf("%w: port %d", ErrConfigValidationFailed, c.Port)
+	}
+	return nil
+}
+
+func TestWithConfigSection_ReplacesModuleSection(t *testing.T) {
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithModules(&greeterModule{}),
+		WithConfigSection("greeter", NewStdConfigProvider(&greeterConfig{Name: "alice"})),
+	)
+	require.NoError(t, err)
+	require.NoError(t, app.Init())
+
+	greeter := app.GetModule("greeter").(*greeterModule)
+	assert.Equal(t, "hello alice", greeter.greeting, "defaults are applied to the override")
+}
+
+func TestWithConfigValue_SetsFields(t *testing.T) {
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithModules(&greeterModule{}),
+		WithConfigSection("server", NewStdConfigProvider(&overrideTestConfig{Name: "api", Port: 80})),
+		WithConfigValue("greeter", "name", "bob"),
+		WithConfigValue("server", "Port", int64(8080)),
+		WithConfigValue("server", "timeout", "5s"),
+		WithConfigValue("server", "tls.enabled", true),
+	)
+	require.NoError(t, err)
+	require.NoError(t, app.Init())
+
+	assert.Equal(t, "hi bob", app.GetModule("greeter").(*greeterModule).greeting)
+
+	section, err := app.GetConfigSection("server")
+	require.NoError(t, err)
+	cfg := section.GetConfig().(*overrideTestConfig)
+	assert.Equal(t, "api", cfg.Name)
+	assert.Equal(t, 8080, cfg.Port)
+	assert.Equal(t, 5*time.Second, cfg.Timeout)
+	require.NotNil(t, cfg.TLS)
+	assert.True(t, cfg.TLS.Enabled)
+}
+
+func TestWithConfigValue_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		option  Option
+		wantErr error
+	}{
+		{"unknown section", WithConfigValue("missing", "name", "x"), ErrConfigSectionNotFound},
+		{"unknown field", WithConfigValue("greeter", "nickname", "x"), ErrConfigFieldNotFound},
+		{"incompatible value", WithConfigValue("greeter", "name", []int{1}), ErrIncompatibleFieldKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, err := NewApplication(WithLogger(&testLogger{}), WithModules(&greeterModule{}), tt.option)
+			require.NoError(t, err)
+			assert.ErrorIs(t, app.Init(), tt.wantErr)
+		})
+	}
+}
+
+func TestWithConfigValue_ValidatesSections(t *testing.T) {
+	newApp := func(key string, value any) Application {
+		app, err := NewApplication(
+			WithLogger(&testLogger{}),
+			WithConfigSection("svc", NewStdConfigProvider(&validatedOverrideConfig{Host: "localhost"})),
+			WithConfigValue("svc", key, value),
+		)
+		require.NoError(t, err)
+		return app
+	}
+
+	err := newApp("port", -5).Init()
+	require.ErrorIs(t, err, ErrConfigValidationFailed)
+	assert.Contains(t, err.Error(), "config values for section svc")
+
+	assert.ErrorIs(t, newApp("host", "").Init(), ErrConfigRequiredFieldMissing)
+
+	app := newApp("port", 0)
+	require.NoError(t, app.Init())
+	section, err := app.GetConfigSection("svc")
+	require.NoError(t, err)
+	assert.Equal(t, 0, section.GetConfig().(*validatedOverrideConfig).Port, "defaults are not reapplied over set values")
+}
+
+func TestCloneForTest_KeepsConfigOverridesAndValues(t *testing.T) {
+	template := NewStdApplication(NewStdConfigProvider(&testCfg{}), &testLogger{}).(*StdApplication)
+	template.RegisterModule(&greeterModule{})
+	template.SetConfigOverride("greeter", NewStdConfigProvider(&greeterConfig{Greeting: "hey", Name: "template"}))
+	template.SetConfigValue("greeter", "name", "clone")
+
+	app := template.CloneForTest(nil)
+	require.NoError(t, app.Init())
+	assert.Equal(t, "hey clone", app.GetModule("greeter").(*greeterModule).greeting)
+}
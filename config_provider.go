@@ -2,7 +2,9 @@ package modular
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -321,6 +323,8 @@ type Config struct {
 	Logger Logger
 	// FieldTracker tracks which fields are populated by which feeders
 	FieldTracker FieldTracker
+	// SectionFeeders replaces Feeders for individual struct keys
+	SectionFeeders map[string][]Feeder
 }
 
 // NewConfig creates a new configuration builder.
@@ -335,11 +339,12 @@ type Config struct {
 //	err := cfg.Feed()                       // Load configuration
 func NewConfig() *Config {
 	return &Config{
-		Feeders:      make([]Feeder, 0),
-		StructKeys:   make(map[string]interface{}),
-		VerboseDebug: false,
-		Logger:       nil,
-		FieldTracker: NewDefaultFieldTracker(),
+		Feeders:        make([]Feeder, 0),
+		StructKeys:     make(map[string]interface{}),
+		VerboseDebug:   false,
+		Logger:         nil,
+		FieldTracker:   NewDefaultFieldTracker(),
+		SectionFeeders: make(map[string][]Feeder),
 	}
 }
 
@@ -354,7 +359,7 @@ func (c *Config) SetVerboseDebug(enabled bool, logger Logger) *Config {
 	}
 
 	// Apply verbose debugging to any verbose-aware feeders
-	for _, feeder := range c.Feeders {
+	for _, feeder := range c.allFeeders() {
 		if verboseFeeder, ok := feeder.(VerboseAwareFeeder); ok {
 			verboseFeeder.SetVerboseDebug(enabled, logger)
 		}
@@ -366,7 +371,34 @@ func (c *Config) SetVerboseDebug(enabled bool, logger Logger) *Config {
 // AddFeeder adds a configuration feeder to support verbose debugging and field tracking
 func (c *Config) AddFeeder(feeder Feeder) *Config {
 	c.Feeders = append(c.Feeders, feeder)
+	c.prepareFeeder(feeder)
+	return c
+}
+
+// SetSectionFeeders makes the struct registered under key read only from the given
+// feeders, in the given order (subject to feeder priority), instead of Feeders.
+func (c *Config) SetSectionFeeders(key string, feeders ...Feeder) *Config {
+	if c.SectionFeeders == nil {
+		c.SectionFeeders = make(map[string][]Feeder)
+	}
+	c.SectionFeeders[key] = feeders
+	for _, feeder := range feeders {
+		c.prepareFeeder(feeder)
+	}
+	return c
+}
 
+// allFeeders returns the shared feeders followed by every section-specific feeder
+func (c *Config) allFeeders() []Feeder {
+	feeders := append([]Feeder(nil), c.Feeders...)
+	for _, key := range slices.Sorted(maps.Keys(c.SectionFeeders)) {
+		feeders = append(feeders, c.SectionFeeders[key]...)
+	}
+	return feeders
+}
+
+// prepareFeeder applies the builder's verbose debugging and field tracking to a feeder
+func (c *Config) prepareFeeder(feeder Feeder) {
 	// If verbose debugging is enabled, apply it to this feeder
 	if c.VerboseDebug && c.Logger != nil {
 		if verboseFeeder, ok := feeder.(VerboseAwareFeeder); ok {
@@ -391,8 +423,6 @@ func (c *Config) AddFeeder(feeder Feeder) *Config {
 			}
 		}
 	}
-
-	return c
 }
 
 // AddStructKey adds a structure with a key to the configuration
@@ -409,7 +439,7 @@ func (c *Config) SetFieldTracker(tracker FieldTracker) *Config {
 	}
 
 	// Apply field tracking to any tracking-aware feeders
-	for _, feeder := range c.Feeders {
+	for _, feeder := range c.allFeeders() {
 		if trackingFeeder, ok := feeder.(FieldTrackingFeeder); ok {
 			trackingFeeder.SetFieldTracker(tracker)
 		}
@@ -501,7 +531,7 @@ func (c *Config) Feed() error {
 	}
 
 	// Sort feeders by priority (ascending order, so higher priority applies last)
-	sortedFeeders := c.sortFeedersByPriority()
+	defaultFeeders := c.sortFeedersByPriority(c.Feeders)
 
 	// If we have struct keys, feed them directly with field tracking
 	if len(c.StructKeys) > 0 {
@@ -509,12 +539,21 @@ func (c *Config) Feed() error {
 			c.Logger.Debug("Using enhanced feeding process with field tracking")
 		}
 
-		// Feed each struct key with each feeder
-		for key, target := range c.StructKeys {
+		// Feed each struct key with each feeder, in a deterministic order
+		for _, key := range c.structKeyOrder() {
+			target := c.StructKeys[key]
 			if c.VerboseDebug && c.Logger != nil {
 				c.Logger.Debug("Processing struct key", "key", key, "targetType", reflect.TypeOf(target))
 			}
 
+			sortedFeeders := defaultFeeders
+			if sectionFeeders, ok := c.SectionFeeders[key]; ok {
+				if c.VerboseDebug && c.Logger != nil {
+					c.Logger.Debug("Using section-specific feeders", "key", key, "feedersCount", len(sectionFeeders))
+				}
+				sortedFeeders = c.sortFeedersByPriority(sectionFeeders)
+			}
+
 			for i, f := range sortedFeeders {
 				if c.VerboseDebug && c.Logger != nil {
 					c.Logger.Debug("Applying feeder to struct", "key", key, "feederIndex", i, "feederType", fmt.Sprintf("%T", f))
@@ -616,14 +655,24 @@ func (c *Config) Feed() error {
 	return nil
 }
 
+// structKeyOrder returns the struct keys in feeding order: the main config first,
+// then sections sorted by name.
+func (c *Config) structKeyOrder() []string {
+	keys := slices.Sorted(maps.Keys(c.StructKeys))
+	if i := slices.Index(keys, mainConfigSection); i > 0 {
+		keys = append(append([]string{mainConfigSection}, keys[:i]...), keys[i+1:]...)
+	}
+	return keys
+}
+
 // sortFeedersByPriority sorts feeders by priority in ascending order.
 // Higher priority values are applied later, allowing them to override lower priority feeders.
 // Feeders without priority (not implementing PrioritizedFeeder) default to priority 0.
 // When priorities are equal, original order is preserved (stable sort).
-func (c *Config) sortFeedersByPriority() []Feeder {
+func (c *Config) sortFeedersByPriority(feeders []Feeder) []Feeder {
 	// Create a copy of feeders to avoid modifying the original slice
-	sortedFeeders := make([]Feeder, len(c.Feeders))
-	copy(sortedFeeders, c.Feeders)
+	sortedFeeders := make([]Feeder, len(feeders))
+	copy(sortedFeeders, feeders)
 
 	// Sort by priority (ascending, so highest priority applies last)
 	sort.SliceStable(sortedFeeders, func(i, j int) bool {
@@ -684,15 +733,19 @@ func loadAppConfig(app *StdApplication) error {
 	// Prepare config feeders - include base config feeder if enabled.
 	// Priority / order:
 	//   1. Base config feeder (if enabled)
-	//   2. Per-app feeders (if explicitly provided via SetConfigFeeders)
-	//   3. Global ConfigFeeders fallback (if no per-app feeders provided)
+	//   2. Profile feeder (if profile options are set)
+	//   3. Per-app feeders (if explicitly provided via SetConfigFeeders)
+	//   4. Global ConfigFeeders fallback (if no per-app feeders provided)
 	var effectiveFeeders []Feeder
 
-	// Start capacity estimation (base + either per-app or global)
+	// Start capacity estimation (base + profile + either per-app or global)
 	baseCount := 0
 	if IsBaseConfigEnabled() && GetBaseConfigFeeder() != nil {
 		baseCount = 1
 	}
+	if app.profileOptions != nil {
+		baseCount++
+	}
 	if app.configFeeders != nil {
 		effectiveFeeders = make([]Feeder, 0, baseCount+len(app.configFeeders))
 	} else {
@@ -711,6 +764,18 @@ func loadAppConfig(app *StdApplication) error {
 		}
 	}
 
+	// Add profile feeder so profile files override the base file but not later feeders
+	if app.profileOptions != nil {
+		opts := app.profileOptions
+		effectiveFeeders = append(effectiveFeeders, opts.feeder())
+		if app.IsVerboseConfig() {
+			app.logger.Debug("Added profile config feeder",
+				"configDir", opts.ConfigDir,
+				"baseName", opts.BaseName,
+				"profiles", opts.Profiles)
+		}
+	}
+
 	// Append per-app feeders if provided; else fall back to global
 	if app.configFeeders != nil {
 		effectiveFeeders = append(effectiveFeeders, app.configFeeders...)
@@ -742,6 +807,12 @@ func loadAppConfig(app *StdApplication) error {
 			app.logger.Debug("Added config feeder to builder", "type", fmt.Sprintf("%T", feeder))
 		}
 	}
+	for section, feeders := range app.sectionFeeders {
+		cfgBuilder.SetSectionFeeders(section, feeders...)
+		if app.IsVerboseConfig() {
+			app.logger.Debug("Added section-specific config feeders", "section", section, "count", len(feeders))
+		}
+	}
 
 	// Process configs
 	tempConfigs, hasConfigs := processConfigs(app, cfgBuilder)
@@ -764,6 +835,11 @@ func loadAppConfig(app *StdApplication) error {
 		return err
 	}
 
+	// Report keys in config files that no section or field consumes
+	if err := checkUnknownConfigKeys(app, effectiveFeeders); err != nil {
+		return err
+	}
+
 	// Apply instance-aware feeding for supported configurations AFTER regular feeding
 	if err := applyInstanceAwareFeeding(app, tempConfigs); err != nil {
 		if app.IsVerboseConfig() {
@@ -0,0 +1,255 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
+
+// Reloadable is an optional interface for modules that can apply configuration
+// changes at runtime. ReloadConfig calls Reload on each module whose config sections
+// changed, after GetConfigSection already returns the new configuration for them.
+//
+// Example:
+//
+//	func (m *MyModule) Reload(ctx context.Context, sections []string) error {
+//	    provider, err := m.app.GetConfigSection(m.Name())
+//	    if err != nil {
+//	        return err
+//	    }
+//	    m.limits.Store(provider.GetConfig().(*MyConfig).Limits)
+//	    return nil
+//	}
+type Reloadable interface {
+	// Reload applies the new configuration of the module's changed sections. On
+	// error the module must keep running with its previous configuration, which
+	// its sections are rolled back to.
+	Reload(ctx context.Context, sections []string) error
+}
+
+// ConfigReload reports the outcome of a configuration reload.
+type ConfigReload struct {
+	// Applied lists the changed sections now in effect, sorted
+	Applied []string `json:"applied"`
+	// RestartRequired lists changed sections of modules that don't implement
+	// Reloadable, and the main config if it changed. They keep their previous
+	// configuration until the application restarts.
+	RestartRequired []string `json:"restart_required"`
+	// Failed lists the changed sections of modules whose Reload failed
+	Failed []string `json:"failed"`
+}
+
+// captureConfigBaseline records deep copies of the configuration sections as modules
+// registered them, before any feeder ran, so reloads start from the same defaults.
+// It also records which module registered each section.
+func (app *StdApplication) captureConfigBaseline(owners map[string]string) {
+	app.configBaseline = make(map[string]any, len(app.cfgSections)+1)
+	if app.cfgProvider != nil && app.cfgProvider.GetConfig() != nil {
+		if copied, err := DeepCopyConfig(app.cfgProvider.GetConfig()); err == nil {
+			app.configBaseline[mainConfigSection] = copied
+		}
+	}
+	for section, provider := range app.cfgSections {
+		if provider == nil || provider.GetConfig() == nil {
+			continue
+		}
+		if copied, err := DeepCopyConfig(provider.GetConfig()); err == nil {
+			app.configBaseline[section] = copied
+		}
+	}
+	app.configSectionOwners = owners
+}
+
+// ReloadConfig loads the configuration again from the application's feeders, such
+// as changed config files or environment, and applies it to the running modules. It
+// can be called on SIGHUP, which Run does, or from a file watcher.
+//
+// Sections are loaded from the defaults their modules registered, then config
+// overrides and values are applied and the result validated; nothing changes when
+// that fails. Each module implementing Reloadable whose sections changed gets the new
+// sections and is notified, in dependency order. Changes to modules that can't reload
+// and to the main config are logged and only take effect after a restart.
+func (app *StdApplication) ReloadConfig(ctx context.Context) error {
+	_, err := app.reloadConfig(ctx)
+	return err
+}
+
+// reloadConfig reloads the configuration and reports which sections were applied.
+func (app *StdApplication) reloadConfig(ctx context.Context) (ConfigReload, error) {
+	app.reloadMutex.Lock()
+	defer app.reloadMutex.Unlock()
+
+	var result ConfigReload
+	if !app.initialized {
+		return result, ErrConfigReloadBeforeInit
+	}
+
+	staged, err := app.stageConfigReload()
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrConfigReloadFailed, err)
+	}
+
+	// Group the changed sections by the module that registered them
+	changedByModule := make(map[string][]string)
+	var unowned []string
+	for section, provider := range staged.cfgSections {
+		current, err := app.GetConfigSection(section)
+		if err != nil || reflect.DeepEqual(current.GetConfig(), provider.GetConfig()) {
+			continue
+		}
+		if owner, ok := app.configSectionOwners[section]; ok {
+			changedByModule[owner] = append(changedByModule[owner], section)
+		} else {
+			unowned = append(unowned, section)
+		}
+	}
+	if staged.cfgProvider != nil && app.cfgProvider != nil &&
+		!reflect.DeepEqual(staged.cfgProvider.GetConfig(), app.cfgProvider.GetConfig()) {
+		result.RestartRequired = append(result.RestartRequired, mainConfigSection)
+	}
+
+	// Sections registered by the application itself have no module to notify
+	app.swapConfigSections(staged, unowned)
+	result.Applied = append(result.Applied, unowned...)
+
+	order, err := app.resolveDependencies()
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrConfigReloadFailed, err)
+	}
+	var errs []error
+	for _, name := range order {
+		sections := changedByModule[name]
+		if len(sections) == 0 {
+			continue
+		}
+		sort.Strings(sections)
+		if app.pendingLazy(name) {
+			// A lazy module not initialized yet reads the new config when it is
+			app.swapConfigSections(staged, sections)
+			result.Applied = append(result.Applied, sections...)
+			continue
+		}
+		reloadable, ok := app.GetModule(name).(Reloadable)
+		if !ok {
+			app.logger.Warn("Config changed for a module that can't reload, restart to apply it",
+				"module", name, "sections", sections)
+			result.RestartRequired = append(result.RestartRequired, sections...)
+			continue
+		}
+
+		previous := app.swapConfigSections(staged, sections)
+		if err := reloadable.Reload(ctx, sections); err != nil {
+			app.restoreConfigSections(previous)
+			app.logger.Error("Module failed to reload config", "module", name, "error", err)
+			errs = append(errs, fmt.Errorf("module %s: %w", name, err))
+			result.Failed = append(result.Failed, sections...)
+			continue
+		}
+		app.logger.Info("Module reloaded config", "module", name, "sections", sections)
+		result.Applied = append(result.Applied, sections...)
+	}
+
+	sort.Strings(result.Applied)
+	sort.Strings(result.RestartRequired)
+	sort.Strings(result.Failed)
+	if len(errs) > 0 {
+		return result, fmt.Errorf("%w: %w", ErrConfigReloadFailed, errors.Join(errs...))
+	}
+	return result, nil
+}
+
+// stageConfigReload loads the configuration into copies of the baseline sections,
+// without touching the running configuration.
+func (app *StdApplication) stageConfigReload() (*StdApplication, error) {
+	var mainCfg ConfigProvider
+	if cfg, ok := app.configBaseline[mainConfigSection]; ok {
+		copied, err := DeepCopyConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		mainCfg = NewStdConfigProvider(copied)
+	}
+	staged := NewStdApplication(mainCfg, app.logger).(*StdApplication)
+	staged.verboseConfig = app.verboseConfig
+	staged.profileOptions = app.profileOptions
+	staged.configFeeders = app.configFeeders
+	staged.sectionFeeders = app.sectionFeeders
+	staged.strictConfig = app.strictConfig
+	staged.configOverrides = app.configOverrides
+	staged.configValues = app.configValues
+	for section, cfg := range app.configBaseline {
+		if section == mainConfigSection {
+			continue
+		}
+		copied, err := DeepCopyConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("section %s: %w", section, err)
+		}
+		staged.cfgSections[section] = NewStdConfigProvider(copied)
+	}
+
+	if err := AppConfigLoader(staged); err != nil {
+		return nil, err
+	}
+	if err := staged.applyConfigOverrides(); err != nil {
+		return nil, err
+	}
+	if err := staged.applyConfigValues(); err != nil {
+		return nil, err
+	}
+	return staged, nil
+}
+
+// swapConfigSections makes the staged providers of sections current and returns the
+// providers they replaced.
+func (app *StdApplication) swapConfigSections(staged *StdApplication, sections []string) map[string]ConfigProvider {
+	app.cfgSectionsMu.Lock()
+	defer app.cfgSectionsMu.Unlock()
+	previous := make(map[string]ConfigProvider, len(sections))
+	for _, section := range sections {
+		previous[section] = app.cfgSections[section]
+		app.cfgSections[section] = staged.cfgSections[section]
+	}
+	return previous
+}
+
+// restoreConfigSections puts back providers replaced by swapConfigSections.
+func (app *StdApplication) restoreConfigSections(previous map[string]ConfigProvider) {
+	app.cfgSectionsMu.Lock()
+	defer app.cfgSectionsMu.Unlock()
+	maps.Copy(app.cfgSections, previous)
+}
+
+// ReloadConfig reloads the configuration like StdApplication.ReloadConfig and emits
+// EventTypeConfigChanged with the sections applied, or EventTypeConfigReloadFailed.
+func (app *ObservableApplication) ReloadConfig(ctx context.Context) error {
+	result, err := app.reloadConfig(ctx)
+	data := map[string]interface{}{
+		"applied":          result.Applied,
+		"restart_required": result.RestartRequired,
+		"failed":           result.Failed,
+	}
+	if err != nil {
+		data["error"] = err.Error()
+		app.emitEvent(ctx, NewCloudEvent(EventTypeConfigReloadFailed, "application", data, nil))
+		return err
+	}
+	if len(result.Applied) > 0 || len(result.RestartRequired) > 0 {
+		app.emitEvent(ctx, NewCloudEvent(EventTypeConfigChanged, "application", data, nil))
+	}
+	return nil
+}
+
+// recordSectionOwners attributes the sections registered since before, a snapshot of
+// the section names, to module.
+func (app *StdApplication) recordSectionOwners(before []string, module string, owners map[string]string) {
+	for section := range app.cfgSections {
+		if !slices.Contains(before, section) {
+			owners[section] = module
+		}
+	}
+}
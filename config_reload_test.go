@@ -0,0 +1,207 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type reloadLimitsConfig struct {
+	Limit int
+}
+
+func (c *reloadLimitsConfig) Validate() error {
+	if c.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+	return nil
+}
+
+type reloadModeConfig struct {
+	Mode string
+}
+
+// reloadSource is the mutable configuration source fed by reloadFeeder
+type reloadSource struct {
+	limit int
+	mode  string
+}
+
+type reloadFeeder struct {
+	source *reloadSource
+}
+
+func (f reloadFeeder) Feed(structure interface{}) error {
+	switch cfg := structure.(type) {
+	case *reloadLimitsConfig:
+		cfg.Limit = f.source.limit
+	case *reloadModeConfig:
+		cfg.Mode = f.source.mode
+	}
+	return nil
+}
+
+// reloadableTestModule applies its limits section on Reload
+type reloadableTestModule struct {
+	app       Application
+	limit     int
+	reloads   [][]string
+	reloadErr error
+}
+
+func (m *reloadableTestModule) Name() string { return "limits" }
+
+func (m *reloadableTestModule) RegisterConfig(app Application) error {
+	app.RegisterConfigSection("limits", NewStdConfigProvider(&reloadLimitsConfig{}))
+	return nil
+}
+
+func (m *reloadableTestModule) Init(app Application) error {
+	m.app = app
+	return m.apply()
+}
+
+func (m *reloadableTestModule) Reload(_ context.Context, sections []string) error {
+	m.reloads = append(m.reloads, sections)
+	if m.reloadErr != nil {
+		return m.reloadErr
+	}
+	return m.apply()
+}
+
+func (m *reloadableTestModule) apply() error {
+	provider, err := m.app.GetConfigSection("limits")
+	if err != nil {
+		return err
+	}
+	m.limit = provider.GetConfig().(*reloadLimitsConfig).Limit
+	return nil
+}
+
+// staticTestModule reads its mode section only on Init
+type staticTestModule struct{}
+
+func (m *staticTestModule) Name() string { return "static" }
+
+func (m *staticTestModule) RegisterConfig(app Application) error {
+	app.RegisterConfigSection("mode", NewStdConfigProvider(&reloadModeConfig{}))
+	return nil
+}
+
+func (m *staticTestModule) Init(Application) error { return nil }
+
+func newReloadTestApp(t *testing.T, app *StdApplication, source *reloadSource) *reloadableTestModule {
+	t.Helper()
+	module := &reloadableTestModule{}
+	app.RegisterModule(module)
+	app.RegisterModule(&staticTestModule{})
+	app.SetConfigFeeders([]Feeder{reloadFeeder{source: source}})
+	require.NoError(t, app.Init())
+	return module
+}
+
+func sectionConfig(t *testing.T, app Application, section string) any {
+	t.Helper()
+	provider, err := app.GetConfigSection(section)
+	require.NoError(t, err)
+	return provider.GetConfig()
+}
+
+func TestReloadConfig_AppliesReloadableSections(t *testing.T) {
+	source := &reloadSource{limit: 10, mode: "fast"}
+	app := NewStdApplication(nil, &testLogger{}).(*StdApplication)
+	module := newReloadTestApp(t, app, source)
+	assert.Equal(t, 10, module.limit)
+
+	source.limit = 20
+	source.mode = "safe"
+	result, err := app.reloadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"limits"}, result.Applied)
+	assert.Equal(t, []string{"mode"}, result.RestartRequired)
+	assert.Empty(t, result.Failed)
+
+	assert.Equal(t, [][]string{{"limits"}}, module.reloads)
+	assert.Equal(t, 20, module.limit)
+	assert.Equal(t, "fast", sectionConfig(t, app, "mode").(*reloadModeConfig).Mode,
+		"modules that can't reload keep their configuration")
+
+	// Nothing changed, so nothing is reloaded
+	result, err = app.reloadConfig(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.Applied)
+	assert.Len(t, module.reloads, 1)
+}
+
+func TestReloadConfig_RollsBackFailedReload(t *testing.T) {
+	source := &reloadSource{limit: 10}
+	app := NewStdApplication(nil, &testLogger{}).(*StdApplication)
+	module := newReloadTestApp(t, app, source)
+
+	module.reloadErr = errors.New("pool busy")
+	source.limit = 20
+	result, err := app.reloadConfig(context.Background())
+	require.ErrorIs(t, err, ErrConfigReloadFailed)
+	assert.Contains(t, err.Error(), "pool busy")
+	assert.Equal(t, []string{"limits"}, result.Failed)
+	assert.Equal(t, 10, sectionConfig(t, app, "limits").(*reloadLimitsConfig).Limit)
+}
+
+func TestReloadConfig_InvalidConfigChangesNothing(t *testing.T) {
+	source := &reloadSource{limit: 10}
+	app := NewStdApplication(nil, &testLogger{}).(*StdApplication)
+	module := newReloadTestApp(t, app, source)
+
+	source.limit = -1
+	require.ErrorIs(t, app.ReloadConfig(context.Background()), ErrConfigReloadFailed)
+	assert.Empty(t, module.reloads)
+	assert.Equal(t, 10, sectionConfig(t, app, "limits").(*reloadLimitsConfig).Limit)
+}
+
+func TestReloadConfig_BeforeInit(t *testing.T) {
+	app := NewStdApplication(nil, &testLogger{}).(*StdApplication)
+	assert.ErrorIs(t, app.ReloadConfig(context.Background()), ErrConfigReloadBeforeInit)
+}
+
+func TestObservableApplication_ReloadConfigEmitsConfigChanged(t *testing.T) {
+	source := &reloadSource{limit: 10}
+	app := NewObservableApplication(nil, &testLogger{})
+	newReloadTestApp(t, app.StdApplication, source)
+
+	var (
+		mu     sync.Mutex
+		events []cloudevents.Event
+	)
+	require.NoError(t, app.RegisterObserver(NewFunctionalObserver("reload", func(_ context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}), EventTypeConfigChanged, EventTypeConfigReloadFailed))
+	eventCount := func(n int) func() bool {
+		return func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(events) == n
+		}
+	}
+
+	source.limit = 20
+	require.NoError(t, app.ReloadConfig(context.Background()))
+	require.Eventually(t, eventCount(1), time.Second, 10*time.Millisecond)
+	assert.Equal(t, EventTypeConfigChanged, events[0].Type())
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, []interface{}{"limits"}, data["applied"])
+
+	source.limit = -1
+	require.Error(t, app.ReloadConfig(context.Background()))
+	require.Eventually(t, eventCount(2), time.Second, 10*time.Millisecond)
+	assert.Equal(t, EventTypeConfigReloadFailed, events[1].Type())
+}
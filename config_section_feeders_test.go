@@ -0,0 +1,110 @@
+package modular
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sectionSourceConfig struct {
+	Sources []string
+}
+
+// sourceFeeder appends its name to the Sources field of the fed struct
+type sourceFeeder struct {
+	name string
+}
+
+func (f sourceFeeder) Feed(structure interface{}) error {
+	if cfg, ok := structure.(*sectionSourceConfig); ok {
+		cfg.Sources = append(cfg.Sources, f.name)
+	}
+	return nil
+}
+
+func TestConfig_SectionFeedersReplaceDefaults(t *testing.T) {
+	db := &sectionSourceConfig{}
+	cache := &sectionSourceConfig{}
+
+	cfg := NewConfig()
+	cfg.AddFeeder(sourceFeeder{name: "yaml"})
+	cfg.AddFeeder(sourceFeeder{name: "env"})
+	cfg.SetSectionFeeders("database", sourceFeeder{name: "vault"}, sourceFeeder{name: "env"})
+	cfg.AddStructKey("database", db)
+	cfg.AddStructKey("cache", cache)
+
+	require.NoError(t, cfg.Feed())
+	assert.Equal(t, []string{"vault", "env"}, db.Sources)
+	assert.Equal(t, []string{"yaml", "env"}, cache.Sources)
+}
+
+func TestConfig_FeedOrderIsDeterministic(t *testing.T) {
+	for range 5 {
+		var order []string
+		cfg := NewConfig()
+		cfg.AddFeeder(feedOrderRecorder{order: &order})
+		for _, key := range []string{"zeta", "alpha", mainConfigSection, "mid"} {
+			cfg.AddStructKey(key, &keyedConfig{Key: key})
+		}
+
+		require.NoError(t, cfg.Feed())
+		assert.Equal(t, []string{mainConfigSection, "alpha", "mid", "zeta"}, order)
+	}
+}
+
+type keyedConfig struct {
+	Key string
+}
+
+type feedOrderRecorder struct {
+	order *[]string
+}
+
+func (f feedOrderRecorder) Feed(structure interface{}) error {
+	*f.order = append(*f.order, structure.(*keyedConfig).Key)
+	return nil
+}
+
+func TestStdApplication_RegisterConfigSectionWithFeeders(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&sectionSourceConfig{}), &testLogger{}).(*StdApplication)
+	app.SetConfigFeeders([]Feeder{sourceFeeder{name: "yaml"}, sourceFeeder{name: "env"}})
+
+	db := &sectionSourceConfig{}
+	app.RegisterConfigSectionWithFeeders("database", NewStdConfigProvider(db), sourceFeeder{name: "vault"}, sourceFeeder{name: "env"})
+
+	// Feeders may also be assigned before a module registers its section
+	cache := &sectionSourceConfig{}
+	app.SetSectionFeeders("cache", sourceFeeder{name: "redis"})
+	app.RegisterConfigSection("cache", NewStdConfigProvider(cache))
+
+	other := &sectionSourceConfig{}
+	app.RegisterConfigSection("other", NewStdConfigProvider(other))
+
+	require.NoError(t, loadAppConfig(app))
+	assert.Equal(t, []string{"vault", "env"}, db.Sources)
+	assert.Equal(t, []string{"redis"}, cache.Sources)
+	assert.Equal(t, []string{"yaml", "env"}, other.Sources)
+	assert.Equal(t, []string{"yaml", "env"}, app.cfgProvider.GetConfig().(*sectionSourceConfig).Sources)
+
+	// Clearing the override restores the application-wide feeders
+	app.SetSectionFeeders("cache")
+	cache.Sources = nil
+	require.NoError(t, loadAppConfig(app))
+	assert.Equal(t, []string{"yaml", "env"}, cache.Sources)
+}
+
+func TestDecorator_ForwardsSectionFeeders(t *testing.T) {
+	std := NewStdApplication(nil, &testLogger{}).(*StdApplication)
+	std.SetConfigFeeders([]Feeder{sourceFeeder{name: "env"}})
+
+	var app Application = NewTenantAwareDecorator(std, nil)
+	sfc, ok := app.(SectionFeedersConfigurable)
+	require.True(t, ok)
+
+	db := &sectionSourceConfig{}
+	sfc.RegisterConfigSectionWithFeeders("database", NewStdConfigProvider(db), sourceFeeder{name: "vault"})
+
+	require.NoError(t, loadAppConfig(std))
+	assert.Equal(t, []string{"vault"}, db.Sources)
+}
@@ -0,0 +1,431 @@
+package modular
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/CrisisTextLine/modular/feeders"
+)
+
+// StrictConfigMode controls what happens when configuration files contain keys that
+// do not map to any registered config section or field.
+type StrictConfigMode int
+
+const (
+	// StrictConfigOff ignores unknown configuration keys. This is the default.
+	StrictConfigOff StrictConfigMode = iota
+	// StrictConfigWarn logs a warning for every unknown configuration key.
+	StrictConfigWarn
+	// StrictConfigError fails configuration loading when any key is unknown.
+	StrictConfigError
+)
+
+// UnknownConfigKey is a key found in a configuration source that does not map to
+// any registered config section or field.
+type UnknownConfigKey struct {
+	// Source is the file the key was read from
+	Source string
+	// Key is the dot-separated path of the key, e.g. "reverseproxy.requesttimeout"
+	Key string
+	// Suggestion is the closest known key at the same level, or "" if none is close
+	Suggestion string
+}
+
+// String formats the key with its source and suggestion.
+func (k UnknownConfigKey) String() string {
+	s := k.Key
+	if k.Suggestion != "" {
+		s += " (did you mean " + k.Suggestion + "?)"
+	}
+	if k.Source != "" {
+		s += " in " + k.Source
+	}
+	return s
+}
+
+// SetStrictConfig sets how configuration keys that match no registered section or
+// field are handled. Keys are checked in the YAML, JSON, TOML and INI files read by the
+// application's feeders, including base config and profile files. Environment
+// variables are not checked, as the process environment holds many variables that
+// are not meant for the application.
+func (app *StdApplication) SetStrictConfig(mode StrictConfigMode) {
+	app.strictConfig = mode
+}
+
+// checkUnknownConfigKeys reports the keys of the file-based feeders that match no
+// registered config section or field, according to the application's strict mode.
+func checkUnknownConfigKeys(app *StdApplication, effectiveFeeders []Feeder) error {
+	if app.strictConfig == StrictConfigOff {
+		return nil
+	}
+
+	var unknown []UnknownConfigKey
+	for _, feeder := range effectiveFeeders {
+		documents, err := configDocuments(feeder)
+		if err != nil {
+			return fmt.Errorf("strict config check: %w", err)
+		}
+		for source, data := range documents {
+			unknown = append(unknown, unknownTopLevelKeys(app, source, data)...)
+		}
+	}
+	for section, sectionFeeders := range app.sectionFeeders {
+		for _, feeder := range sectionFeeders {
+			documents, err := configDocuments(feeder)
+			if err != nil {
+				return fmt.Errorf("strict config check: %w", err)
+			}
+			provider := app.cfgSections[section]
+			if provider == nil || provider.GetConfig() == nil {
+				continue
+			}
+			for source, data := range documents {
+				if value, ok := data[section]; ok {
+					unknown = append(unknown, unknownConfigKeys(source, section, value, reflect.TypeOf(provider.GetConfig()))...)
+				}
+			}
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Slice(unknown, func(i, j int) bool {
+		if unknown[i].Source != unknown[j].Source {
+			return unknown[i].Source < unknown[j].Source
+		}
+		return unknown[i].Key < unknown[j].Key
+	})
+	if app.strictConfig == StrictConfigWarn {
+		for _, key := range unknown {
+			app.logger.Warn("Unknown configuration key", "key", key.Key, "source", key.Source, "suggestion", key.Suggestion)
+		}
+		return nil
+	}
+	keys := make([]string, len(unknown))
+	for i, key := range unknown {
+		keys[i] = key.String()
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownConfigKeys, strings.Join(keys, "; "))
+}
+
+// configDocuments returns the raw documents read by feeder, keyed by file, or nil
+// for feeders that do not read files. A file of a format the check can't read fails
+// it rather than going unchecked.
+func configDocuments(feeder Feeder) (map[string]map[string]interface{}, error) {
+	var files []string
+	switch f := feeder.(type) {
+	case *feeders.YamlFeeder:
+		files = []string{f.Path}
+	case *feeders.JSONFeeder:
+		files = []string{f.Path}
+	case *feeders.TomlFeeder:
+		files = []string{f.Path}
+	case *feeders.IniFeeder:
+		files = []string{f.Path}
+	case *feeders.ProfileFeeder:
+		files = f.Files()
+	case *feeders.BaseConfigFeeder:
+		var data map[string]interface{}
+		if err := f.Feed(&data); err != nil {
+			return nil, fmt.Errorf("reading base config %s: %w", f.BaseDir, err)
+		}
+		return map[string]map[string]interface{}{f.BaseDir: data}, nil
+	default:
+		return nil, nil
+	}
+
+	documents := make(map[string]map[string]interface{}, len(files))
+	for _, file := range files {
+		var fileFeeder Feeder
+		switch filepath.Ext(file) {
+		case ".json":
+			fileFeeder = feeders.NewJSONFeeder(file)
+		case ".toml":
+			fileFeeder = feeders.NewTomlFeeder(file)
+		case ".yaml", ".yml":
+			fileFeeder = feeders.NewYamlFeeder(file)
+		case ".ini":
+			fileFeeder = feeders.NewIniFeeder(file)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedFormatType, file)
+		}
+		var data map[string]interface{}
+		if err := fileFeeder.Feed(&data); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", file, err)
+		}
+		documents[file] = data
+	}
+	return documents, nil
+}
+
+// unknownTopLevelKeys checks a document's top-level keys against the registered
+// sections and the fields of the main config.
+func unknownTopLevelKeys(app *StdApplication, source string, data map[string]interface{}) []UnknownConfigKey {
+	var mainType reflect.Type
+	if app.cfgProvider != nil && app.cfgProvider.GetConfig() != nil {
+		mainType = structType(reflect.TypeOf(app.cfgProvider.GetConfig()))
+	}
+
+	keys := configKeyFormatOf(source)
+	var unknown []UnknownConfigKey
+	for _, key := range sortedKeys(data) {
+		value := data[key]
+		if provider, ok := app.cfgSections[key]; ok {
+			if provider != nil && provider.GetConfig() != nil {
+				unknown = append(unknown, unknownConfigKeys(source, key, value, reflect.TypeOf(provider.GetConfig()))...)
+			}
+			continue
+		}
+		if mainType != nil {
+			if field, ok := keys.field(mainType, key); ok {
+				unknown = append(unknown, unknownConfigKeys(source, key, value, field.Type)...)
+				continue
+			}
+		}
+
+		candidates := make([]string, 0, len(app.cfgSections))
+		for section := range app.cfgSections {
+			candidates = append(candidates, section)
+		}
+		if mainType != nil {
+			candidates = append(candidates, keys.names(mainType)...)
+		}
+		unknown = append(unknown, UnknownConfigKey{Source: source, Key: key, Suggestion: nearestConfigKey(key, candidates)})
+	}
+	return unknown
+}
+
+// unknownConfigKeys checks value, found at path, against the config type t,
+// descending into nested structs, maps and slices of structs.
+func unknownConfigKeys(source, path string, value interface{}, t reflect.Type) []UnknownConfigKey {
+	t = structOrElemType(t)
+	switch v := value.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			keys := configKeyFormatOf(source)
+			var unknown []UnknownConfigKey
+			for _, key := range sortedKeys(v) {
+				field, ok := keys.field(t, key)
+				if !ok {
+					unknown = append(unknown, UnknownConfigKey{
+						Source:     source,
+						Key:        path + "." + key,
+						Suggestion: nearestConfigKey(key, keys.names(t)),
+					})
+					continue
+				}
+				unknown = append(unknown, unknownConfigKeys(source, path+"."+key, v[key], field.Type)...)
+			}
+			return unknown
+		case reflect.Map:
+			var unknown []UnknownConfigKey
+			for _, key := range sortedKeys(v) {
+				unknown = append(unknown, unknownConfigKeys(source, path+"."+key, v[key], t.Elem())...)
+			}
+			return unknown
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			var unknown []UnknownConfigKey
+			for i, item := range v {
+				unknown = append(unknown, unknownConfigKeys(source, fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+			}
+			return unknown
+		}
+	case []map[string]interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			var unknown []UnknownConfigKey
+			for i, item := range v {
+				unknown = append(unknown, unknownConfigKeys(source, fmt.Sprintf("%s[%d]", path, i), item, t.Elem())...)
+			}
+			return unknown
+		}
+	}
+	return nil
+}
+
+// structOrElemType dereferences pointer types.
+func structOrElemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// structType returns the struct type t points to, or nil if it is not a struct.
+func structType(t reflect.Type) reflect.Type {
+	t = structOrElemType(t)
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	return t
+}
+
+// configKeyFormat matches the keys of a document to config fields the way the
+// feeder of its format does.
+type configKeyFormat struct {
+	field func(t reflect.Type, key string) (reflect.StructField, bool)
+	names func(t reflect.Type) []string
+}
+
+// configKeyFormatOf returns how the keys of the document read from source map to
+// config fields. INI files use ini tags and field names, other files their yaml,
+// json and toml tags.
+func configKeyFormatOf(source string) configKeyFormat {
+	if filepath.Ext(source) == ".ini" {
+		return configKeyFormat{field: iniStructField, names: iniKeyNames}
+	}
+	return configKeyFormat{field: configStructField, names: configKeyNames}
+}
+
+// configStructField returns the field of t matching key by yaml, json or toml tag,
+// or by field name case insensitively for untagged fields, as feeders match them.
+// Fields of embedded structs are promoted.
+func configStructField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous && structType(field.Type) != nil {
+			if _, tagged := configTagNames(field); !tagged {
+				if promoted, ok := configStructField(structType(field.Type), key); ok {
+					return promoted, true
+				}
+				continue
+			}
+		}
+		names, tagged := configTagNames(field)
+		for _, name := range names {
+			if name == key {
+				return field, true
+			}
+		}
+		if !tagged && strings.EqualFold(field.Name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// configTagNames returns the yaml, json and toml tag names of field, and whether it
+// has any.
+func configTagNames(field reflect.StructField) ([]string, bool) {
+	var names []string
+	for _, tag := range []string{"yaml", "json", "toml"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(tag), ","); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names, len(names) > 0
+}
+
+// configKeyNames returns the keys a file can use for the fields of t, preferring
+// each field's yaml tag.
+func configKeyNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if tagNames, tagged := configTagNames(field); tagged {
+			names = append(names, tagNames[0])
+			continue
+		}
+		if field.Anonymous && structType(field.Type) != nil {
+			names = append(names, configKeyNames(structType(field.Type))...)
+			continue
+		}
+		names = append(names, field.Name)
+	}
+	return names
+}
+
+// iniStructField returns the field of t the INI feeder populates from key, which is
+// the field with that ini tag, or of that name when untagged.
+func iniStructField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := iniKeyName(field); field.IsExported() && name != "-" && name == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// iniKeyNames returns the keys an INI file can use for the fields of t.
+func iniKeyNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if name := iniKeyName(field); field.IsExported() && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// iniKeyName returns the INI key of field, or "-" for fields the INI feeder skips.
+func iniKeyName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("ini"), ","); name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// nearestConfigKey returns the candidate closest to key, ignoring case, underscores
+// and hyphens, if it is close enough to be a likely typo.
+func nearestConfigKey(key string, candidates []string) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+	normalizedKey := normalize(key)
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		distance := levenshtein(normalizedKey, normalize(candidate))
+		if bestDistance == -1 || distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	if bestDistance == -1 || bestDistance > max(2, len(normalizedKey)/3) {
+		return ""
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,162 @@
+package modular
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/feeders"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strictTestConfig struct {
+	RequestTimeout time.Duration                `yaml:"request_timeout"`
+	Backends       map[string]strictTestBackend `yaml:"backends"`
+	Routes         []strictTestBackend          `yaml:"routes"`
+	Extra          map[string]string            `yaml:"extra"`
+}
+
+type strictTestBackend struct {
+	URL     string `yaml:"url"`
+	Retries int    `yaml:"retries"`
+}
+
+// warnRecordingLogger records the arguments of every warning.
+type warnRecordingLogger struct {
+	testLogger
+	mu    sync.Mutex
+	warns [][]any
+}
+
+func (l *warnRecordingLogger) Warn(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, append([]any{msg}, args...))
+}
+
+func newStrictTestApp(t *testing.T, logger Logger, mode StrictConfigMode, yaml string) *StdApplication {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(yaml), 0o600))
+
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{}), logger).(*StdApplication)
+	app.SetConfigFeeders([]Feeder{feeders.NewYamlFeeder(path)})
+	app.SetStrictConfig(mode)
+	app.RegisterConfigSection("proxy", NewStdConfigProvider(&strictTestConfig{}))
+	return app
+}
+
+const strictTestYAML = `
+str: hello
+nmu: 3
+proxy:
+  requesttimeout: 5s
+  backends:
+    api:
+      url: http://api
+      retires: 2
+  routes:
+    - url: /users
+      color: blue
+  extra:
+    anything: goes
+prxy:
+  request_timeout: 1s
+`
+
+func TestStrictConfig_ErrorListsUnknownKeys(t *testing.T) {
+	app := newStrictTestApp(t, &testLogger{}, StrictConfigError, strictTestYAML)
+
+	err := loadAppConfig(app)
+	require.ErrorIs(t, err, ErrUnknownConfigKeys)
+	assert.Contains(t, err.Error(), "nmu (did you mean num?)")
+	assert.Contains(t, err.Error(), "prxy (did you mean proxy?)")
+	assert.Contains(t, err.Error(), "proxy.requesttimeout (did you mean request_timeout?)")
+	assert.Contains(t, err.Error(), "proxy.backends.api.retires (did you mean retries?)")
+	assert.Contains(t, err.Error(), "proxy.routes[0].color in ")
+	assert.NotContains(t, err.Error(), "anything", "free-form maps accept any key")
+}
+
+func TestStrictConfig_WarnLogsAndContinues(t *testing.T) {
+	logger := &warnRecordingLogger{}
+	app := newStrictTestApp(t, logger, StrictConfigWarn, "str: hello\nproxy:\n  requesttimeout: 5s\n")
+
+	require.NoError(t, loadAppConfig(app))
+	require.Len(t, logger.warns, 1)
+	assert.Equal(t, "Unknown configuration key", logger.warns[0][0])
+	assert.Contains(t, logger.warns[0], "proxy.requesttimeout")
+	assert.Contains(t, logger.warns[0], "request_timeout")
+	assert.Equal(t, "hello", app.cfgProvider.GetConfig().(*testCfg).Str)
+}
+
+func TestStrictConfig_OffByDefault(t *testing.T) {
+	app := newStrictTestApp(t, &testLogger{}, StrictConfigOff, strictTestYAML)
+	require.NoError(t, loadAppConfig(app))
+
+	app = newStrictTestApp(t, &testLogger{}, StrictConfigError, "str: hello\nproxy:\n  request_timeout: 5s\n")
+	require.NoError(t, loadAppConfig(app))
+}
+
+func TestWithStrictConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("greeter:\n  name: alice\n  greting: hey\n"), 0o600))
+
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithModules(&greeterModule{}),
+		WithStrictConfig(StrictConfigError),
+	)
+	require.NoError(t, err)
+	app.(*StdApplication).SetConfigFeeders([]Feeder{feeders.NewYamlFeeder(path)})
+	err = app.Init()
+	require.ErrorIs(t, err, ErrUnknownConfigKeys)
+	assert.Contains(t, err.Error(), "greeter.greting (did you mean greeting?)")
+}
+
+func TestNearestConfigKey(t *testing.T) {
+	candidates := []string{"request_timeout", "backend_services", "cache_enabled"}
+	assert.Equal(t, "request_timeout", nearestConfigKey("requesttimeout", candidates))
+	assert.Equal(t, "request_timeout", nearestConfigKey("RequestTimeOut", candidates))
+	assert.Equal(t, "cache_enabled", nearestConfigKey("cach_enable", candidates))
+	assert.Empty(t, nearestConfigKey("metrics", candidates))
+}
+
+func TestStrictConfig_ChecksINIFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.ini")
+	require.NoError(t, os.WriteFile(path, []byte(`Str = hello
+Nmu = 3
+[proxy]
+RequestTimeout = 5s
+requesttimeout = 5s
+[proxy.Backends.api]
+URL = http://api
+Retires = 2
+`), 0o600))
+
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{}), &testLogger{}).(*StdApplication)
+	app.SetConfigFeeders([]Feeder{feeders.NewIniFeeder(path)})
+	app.SetStrictConfig(StrictConfigError)
+	app.RegisterConfigSection("proxy", NewStdConfigProvider(&strictTestConfig{}))
+
+	err := loadAppConfig(app)
+	require.ErrorIs(t, err, ErrUnknownConfigKeys)
+	assert.Contains(t, err.Error(), "Nmu (did you mean Num?)")
+	assert.Contains(t, err.Error(), "proxy.requesttimeout (did you mean RequestTimeout?)")
+	assert.Contains(t, err.Error(), "proxy.Backends.api.Retires (did you mean Retries?)")
+	assert.NotContains(t, err.Error(), "proxy.RequestTimeout ", "INI keys are field names")
+	assert.NotContains(t, err.Error(), "Str ")
+}
+
+func TestStrictConfig_ChecksINIProfileLayers(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("str: hello\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.ini"), []byte("Nmu = 3\n"), 0o600))
+
+	documents, err := configDocuments(feeders.NewProfileFeeder(dir, "config", "prod"))
+	require.NoError(t, err)
+	assert.Len(t, documents, 2)
+	assert.Equal(t, "3", documents[filepath.Join(dir, "config.prod.ini")]["Nmu"])
+}
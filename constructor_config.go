@@ -0,0 +1,84 @@
+package modular
+
+import (
+	"fmt"
+)
+
+// ConstructorDeps are the dependencies handed to a constructor built with
+// ConfigConstructor besides its configuration.
+type ConstructorDeps struct {
+	// App is the application constructing the module
+	App Application
+	// Services holds the services the module declared as requirements, by name
+	Services map[string]any
+}
+
+// DepService returns the required service name from deps as a T. It returns
+// ErrServiceNotFound when the service was not resolved, which only happens for
+// optional dependencies, and ErrServiceIncompatible when it is not a T.
+func DepService[T any](deps ConstructorDeps, name string) (T, error) {
+	var zero T
+	service, ok := deps.Services[name]
+	if !ok || service == nil {
+		return zero, fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+	typed, ok := service.(T)
+	if !ok {
+		return zero, fmt.Errorf("%w: service %s is %T, not %T", ErrServiceIncompatible, name, service, zero)
+	}
+	return typed, nil
+}
+
+// SectionConfig returns the loaded configuration of the named section as a *C, after
+// validating it with ValidateConfig. The section may hold a *C or a C; a C is
+// returned as a pointer to a copy. It returns ErrConfigSectionNotFound for sections
+// not registered and ErrConfigSectionType for sections of another type.
+func SectionConfig[C any](app Application, section string) (*C, error) {
+	provider, err := app.GetConfigSection(section)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrConfigSectionNotFound, section)
+	}
+	var cfg *C
+	switch typed := provider.GetConfig().(type) {
+	case *C:
+		cfg = typed
+	case C:
+		cfg = &typed
+	default:
+		return nil, fmt.Errorf("%w: section %s is %T, not %T", ErrConfigSectionType, section, typed, cfg)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("%w: section %s", ErrConfigNilPointer, section)
+	}
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, fmt.Errorf("section %s: %w", section, err)
+	}
+	return cfg, nil
+}
+
+// ConfigConstructor returns a ModuleConstructor that resolves and validates the
+// configuration of section before calling construct with it, replacing the
+// GetConfigSection call and type assertion of a module's Init. The module must
+// register the section in RegisterConfig; configuration is loaded before modules are
+// constructed, so construct receives the final values.
+//
+// Example:
+//
+//	func (m *CacheModule) Constructor() modular.ModuleConstructor {
+//	    return modular.ConfigConstructor("cache", func(cfg *CacheConfig, deps modular.ConstructorDeps) (modular.Module, error) {
+//	        client, err := modular.DepService[*redis.Client](deps, "redis")
+//	        if err != nil {
+//	            return nil, err
+//	        }
+//	        return &CacheModule{config: cfg, client: client}, nil
+//	    })
+//	}
+func ConfigConstructor[C any](section string, construct func(cfg *C, deps ConstructorDeps) (Module, error)) ModuleConstructor {
+	return func(app Application, services map[string]any) (Module, error) {
+		cfg, err := SectionConfig[C](app, section)
+		if err != nil {
+			return nil, err
+		}
+		return construct(cfg, ConstructorDeps{App: app, Services: services})
+	}
+}
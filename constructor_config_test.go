@@ -0,0 +1,119 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mailerConfig struct {
+	Sender  string `default:"noreply@example.com"`
+	Retries int    `default:"1"`
+}
+
+func (c *mailerConfig) Validate() error {
+	if c.Retries < 1 {
+		return errors.New("retries must be positive")
+	}
+	return nil
+}
+
+type mailerNamer interface {
+	Name() string
+}
+
+type staticNamer string
+
+func (n staticNamer) Name() string { return string(n) }
+
+// mailerModule is constructed with its configuration and the "namer" service.
+type mailerModule struct {
+	config *mailerConfig
+	namer  mailerNamer
+}
+
+func (m *mailerModule) Name() string { return "mailer" }
+
+func (m *mailerModule) RegisterConfig(app Application) error {
+	app.RegisterConfigSection("mailer", NewStdConfigProvider(&mailerConfig{}))
+	return nil
+}
+
+func (m *mailerModule) Init(Application) error { return nil }
+
+func (m *mailerModule) ProvidesServices() []ServiceProvider { return nil }
+
+func (m *mailerModule) RequiresServices() []ServiceDependency {
+	return []ServiceDependency{{Name: "namer", Required: true}}
+}
+
+func (m *mailerModule) Constructor() ModuleConstructor {
+	return ConfigConstructor("mailer", func(cfg *mailerConfig, deps ConstructorDeps) (Module, error) {
+		namer, err := DepService[mailerNamer](deps, "namer")
+		if err != nil {
+			return nil, err
+		}
+		return &mailerModule{config: cfg, namer: namer}, nil
+	})
+}
+
+func TestConfigConstructor(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	require.NoError(t, app.RegisterService("namer", staticNamer("world")))
+	app.RegisterModule(&mailerModule{})
+	require.NoError(t, app.Init())
+
+	constructed, ok := app.moduleRegistry["mailer"].(*mailerModule)
+	require.True(t, ok)
+	require.NotNil(t, constructed.config)
+	assert.Equal(t, "noreply@example.com", constructed.config.Sender, "the constructor gets the loaded configuration")
+	assert.Equal(t, "world", constructed.namer.Name())
+	assert.Same(t, sectionConfig(t, app, "mailer"), constructed.config)
+}
+
+func TestConfigConstructor_InvalidConfig(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	app.RegisterConfigSection("mailer", NewStdConfigProvider(&mailerConfig{Sender: "hi"}))
+
+	construct := ConfigConstructor("mailer", func(cfg *mailerConfig, deps ConstructorDeps) (Module, error) {
+		return &mailerModule{config: cfg}, nil
+	})
+	module, err := construct(app, nil)
+	require.NoError(t, err, "defaults are applied before validation")
+	assert.Equal(t, 1, module.(*mailerModule).config.Retries)
+
+	app.RegisterConfigSection("mailer", NewStdConfigProvider(&mailerConfig{Retries: -1}))
+	_, err = construct(app, nil)
+	require.ErrorContains(t, err, "retries must be positive")
+}
+
+func TestSectionConfig(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterConfigSection("value", NewStdConfigProvider(mailerConfig{Sender: "hey", Retries: 2}))
+	app.RegisterConfigSection("other", NewStdConfigProvider(&testCfg{Str: "x"}))
+
+	cfg, err := SectionConfig[mailerConfig](app, "value")
+	require.NoError(t, err)
+	assert.Equal(t, "hey", cfg.Sender, "sections holding values are returned as pointers")
+
+	_, err = SectionConfig[mailerConfig](app, "other")
+	require.ErrorIs(t, err, ErrConfigSectionType)
+	_, err = SectionConfig[mailerConfig](app, "missing")
+	require.ErrorIs(t, err, ErrConfigSectionNotFound)
+}
+
+func TestDepService(t *testing.T) {
+	deps := ConstructorDeps{Services: map[string]any{"namer": staticNamer("x"), "ctx": context.Background()}}
+
+	namer, err := DepService[mailerNamer](deps, "namer")
+	require.NoError(t, err)
+	assert.Equal(t, "x", namer.Name())
+
+	_, err = DepService[mailerNamer](deps, "ctx")
+	require.ErrorIs(t, err, ErrServiceIncompatible)
+	_, err = DepService[mailerNamer](deps, "missing")
+	require.ErrorIs(t, err, ErrServiceNotFound)
+}
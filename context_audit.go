@@ -0,0 +1,300 @@
+package modular
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ContextFindingKind classifies a context discipline problem found by a ContextAuditor.
+type ContextFindingKind string
+
+const (
+	// ContextFindingBackground is a call made with context.Background or context.TODO,
+	// which drops the caller's cancellation, deadline and values
+	ContextFindingBackground ContextFindingKind = "background"
+	// ContextFindingNoDeadline is a service call whose context has no deadline
+	ContextFindingNoDeadline ContextFindingKind = "no_deadline"
+	// ContextFindingLostTenant is a call made while serving a tenant's request with a
+	// context GetTenantIDFromContext can't find the tenant in, for example because
+	// the TenantContext was wrapped by context.WithTimeout
+	ContextFindingLostTenant ContextFindingKind = "lost_tenant"
+	// ContextFindingLostTrace is a call made while serving a traced request with a
+	// context the trace can't be found in
+	ContextFindingLostTrace ContextFindingKind = "lost_trace"
+)
+
+// ContextAuditConfig configures a ContextAuditor.
+type ContextAuditConfig struct {
+	// TenantHeader marks requests served for a tenant. Default "X-Tenant-ID".
+	TenantHeader string
+	// TraceHeader marks traced requests. Default "traceparent".
+	TraceHeader string
+	// HasTrace reports whether ctx carries a trace, for example through the
+	// OpenTelemetry span context. Lost traces aren't detected when nil.
+	HasTrace func(ctx context.Context) bool
+	// IgnoreDeadlines disables ContextFindingNoDeadline for code that deliberately
+	// relies on cancellation only
+	IgnoreDeadlines bool
+}
+
+// ContextFinding aggregates the occurrences of one kind of problem at one location.
+type ContextFinding struct {
+	Kind ContextFindingKind `json:"kind"`
+	// Location is the audited handler name or the "service.Method" called
+	Location  string    `json:"location"`
+	Count     uint64    `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen"`
+}
+
+// contextFindingKey identifies the findings aggregated together.
+type contextFindingKey struct {
+	kind     ContextFindingKind
+	location string
+}
+
+// contextAuditScopeKey is the context key of the request scope set by
+// ContextAuditor.Handler.
+type contextAuditScopeKey struct{}
+
+// contextAuditScope records what a request's context is expected to carry.
+type contextAuditScope struct {
+	tenant bool
+	trace  bool
+}
+
+// ContextAuditor detects context misuse in HTTP handlers and service calls: calls
+// made with context.Background, service calls without a deadline, and tenant or trace
+// values dropped on the way from a request to the services it calls. It is a debug
+// facility enabled with StdApplication.EnableContextAudit or WithContextAudit.
+//
+// Findings are aggregated by kind and location. The first occurrence of each is
+// logged as a warning and, on an ObservableApplication, emitted as
+// EventTypeContextAuditFinding; Stop logs a summary of all of them.
+//
+// A nil *ContextAuditor is valid and audits nothing.
+type ContextAuditor struct {
+	config ContextAuditConfig
+	logger Logger
+	// onFinding is called with the first occurrence of each finding
+	onFinding func(ctx context.Context, finding ContextFinding)
+
+	mu       sync.Mutex
+	findings map[contextFindingKey]*ContextFinding
+}
+
+// NewContextAuditor creates an auditor logging findings to logger.
+func NewContextAuditor(config ContextAuditConfig, logger Logger) *ContextAuditor {
+	if config.TenantHeader == "" {
+		config.TenantHeader = "X-Tenant-ID"
+	}
+	if config.TraceHeader == "" {
+		config.TraceHeader = "traceparent"
+	}
+	return &ContextAuditor{
+		config:   config,
+		logger:   logger,
+		findings: make(map[contextFindingKey]*ContextFinding),
+	}
+}
+
+// Handler wraps handler so the contexts it passes downstream, to the services it
+// calls and the requests it sends through Transport, are checked for lost tenant and
+// trace values. The request's own context is only checked for a lost trace, since
+// servers always give requests a context of their own. Name identifies the handler
+// in findings.
+func (a *ContextAuditor) Handler(name string, handler http.Handler) http.Handler {
+	if a == nil {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		scope := &contextAuditScope{
+			tenant: r.Header.Get(a.config.TenantHeader) != "",
+			trace:  r.Header.Get(a.config.TraceHeader) != "",
+		}
+		if _, ok := GetTenantIDFromContext(ctx); ok {
+			scope.tenant = true
+		}
+		if scope.trace && a.config.HasTrace != nil && !a.config.HasTrace(ctx) {
+			a.report(ctx, ContextFindingLostTrace, name)
+		}
+		// The scope travels as a value so it survives the wrapping that loses a
+		// TenantContext
+		handler.ServeHTTP(w, r.WithContext(context.WithValue(ctx, contextAuditScopeKey{}, scope)))
+	})
+}
+
+// Transport wraps base so the context of every outgoing request is checked like a
+// service call, at the location "name host". A nil base is http.DefaultTransport.
+func (a *ContextAuditor) Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if a == nil {
+		return base
+	}
+	return contextAuditTransport{auditor: a, name: name, base: base}
+}
+
+// contextAuditTransport audits the contexts of the requests it sends.
+type contextAuditTransport struct {
+	auditor *ContextAuditor
+	name    string
+	base    http.RoundTripper
+}
+
+// RoundTrip checks the request's context and sends it.
+func (t contextAuditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.auditor.Check(req.Context(), t.name+" "+req.URL.Host)
+	return t.base.RoundTrip(req) //nolint:wrapcheck // the transport's errors are returned as they are
+}
+
+// Check audits the context of a service call made at location. Generated service
+// proxies call it through ServiceCallTracker.TrackContext.
+func (a *ContextAuditor) Check(ctx context.Context, location string) {
+	if a == nil {
+		return
+	}
+	if ctx == nil || isBackgroundContext(ctx) {
+		a.report(ctx, ContextFindingBackground, location)
+		return
+	}
+	if _, ok := ctx.Deadline(); !ok && !a.config.IgnoreDeadlines {
+		a.report(ctx, ContextFindingNoDeadline, location)
+	}
+	scope, ok := ctx.Value(contextAuditScopeKey{}).(*contextAuditScope)
+	if !ok {
+		return
+	}
+	if _, ok := GetTenantIDFromContext(ctx); scope.tenant && !ok {
+		a.report(ctx, ContextFindingLostTenant, location)
+	}
+	if scope.trace && a.config.HasTrace != nil && !a.config.HasTrace(ctx) {
+		a.report(ctx, ContextFindingLostTrace, location)
+	}
+}
+
+// isBackgroundContext reports whether ctx is context.Background or context.TODO.
+func isBackgroundContext(ctx context.Context) bool {
+	return ctx == context.Background() || ctx == context.TODO()
+}
+
+// report records an occurrence of kind at location, logging and announcing the
+// first one.
+func (a *ContextAuditor) report(ctx context.Context, kind ContextFindingKind, location string) {
+	now := time.Now()
+	key := contextFindingKey{kind: kind, location: location}
+	a.mu.Lock()
+	finding, seen := a.findings[key]
+	if !seen {
+		finding = &ContextFinding{Kind: kind, Location: location, FirstSeen: now}
+		a.findings[key] = finding
+	}
+	finding.Count++
+	finding.LastSeen = now
+	first := *finding
+	a.mu.Unlock()
+
+	if seen {
+		return
+	}
+	if a.logger != nil {
+		a.logger.Warn("Context audit finding", "kind", string(kind), "location", location)
+	}
+	if a.onFinding != nil {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		a.onFinding(ctx, first)
+	}
+}
+
+// Findings returns the findings so far, sorted by location and kind.
+func (a *ContextAuditor) Findings() []ContextFinding {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	findings := make([]ContextFinding, 0, len(a.findings))
+	for _, finding := range a.findings {
+		findings = append(findings, *finding)
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Location != findings[j].Location {
+			return findings[i].Location < findings[j].Location
+		}
+		return findings[i].Kind < findings[j].Kind
+	})
+	return findings
+}
+
+// logSummary logs every finding with its count.
+func (a *ContextAuditor) logSummary() {
+	if a == nil || a.logger == nil {
+		return
+	}
+	findings := a.Findings()
+	if len(findings) == 0 {
+		a.logger.Info("Context audit found no problems")
+		return
+	}
+	for _, finding := range findings {
+		a.logger.Warn("Context audit summary", "kind", string(finding.Kind), "location", finding.Location,
+			"count", finding.Count, "first_seen", finding.FirstSeen, "last_seen", finding.LastSeen)
+	}
+}
+
+// EnableContextAudit starts auditing the contexts of instrumented service calls and
+// returns the auditor, which also wraps HTTP handlers with ContextAuditor.Handler.
+// It enables service instrumentation, since service calls are audited by the proxies
+// registered with RegisterServiceProxy, and must be called before Init.
+func (app *StdApplication) EnableContextAudit(config ContextAuditConfig) *ContextAuditor {
+	app.contextAuditor = NewContextAuditor(config, app.logger)
+	app.serviceInstrumentation = true
+	return app.contextAuditor
+}
+
+// EnableContextAudit starts auditing like StdApplication.EnableContextAudit and emits
+// EventTypeContextAuditFinding for the first occurrence of each finding.
+func (app *ObservableApplication) EnableContextAudit(config ContextAuditConfig) *ContextAuditor {
+	auditor := app.StdApplication.EnableContextAudit(config)
+	auditor.onFinding = func(ctx context.Context, finding ContextFinding) {
+		app.emitEvent(ctx, NewCloudEvent(EventTypeContextAuditFinding, "application", finding, nil))
+	}
+	return auditor
+}
+
+// ContextAuditor returns the application's context auditor, nil unless
+// EnableContextAudit was called.
+func (app *StdApplication) ContextAuditor() *ContextAuditor {
+	return app.contextAuditor
+}
+
+// ContextAuditorFor returns the context auditor of app, nil when it has none. Modules
+// use it to audit the HTTP handlers they register:
+//
+//	router.Handle("/orders", modular.ContextAuditorFor(app).Handler("orders", ordersHandler))
+func ContextAuditorFor(app Application) *ContextAuditor {
+	if audited, ok := app.(interface{ ContextAuditor() *ContextAuditor }); ok {
+		return audited.ContextAuditor()
+	}
+	return nil
+}
+
+// AuditHandler wraps handler with ContextAuditor.Handler, and returns it unchanged
+// when the audit is disabled. It only uses net/http types, so modules built against
+// an older framework version can find it with a type assertion on the Application.
+func (app *StdApplication) AuditHandler(name string, handler http.Handler) http.Handler {
+	return app.contextAuditor.Handler(name, handler)
+}
+
+// AuditTransport wraps base with ContextAuditor.Transport, and returns it unchanged
+// when the audit is disabled. Like AuditHandler, it only uses net/http types.
+func (app *StdApplication) AuditTransport(name string, base http.RoundTripper) http.RoundTripper {
+	return app.contextAuditor.Transport(name, base)
+}
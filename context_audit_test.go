@@ -0,0 +1,227 @@
+package modular
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type traceKey struct{}
+
+func newTestContextAuditor() *ContextAuditor {
+	return NewContextAuditor(ContextAuditConfig{
+		HasTrace: func(ctx context.Context) bool { return ctx.Value(traceKey{}) != nil },
+	}, &testLogger{})
+}
+
+func findingKinds(findings []ContextFinding) map[string][]ContextFindingKind {
+	kinds := make(map[string][]ContextFindingKind)
+	for _, finding := range findings {
+		kinds[finding.Location] = append(kinds[finding.Location], finding.Kind)
+	}
+	return kinds
+}
+
+func TestContextAuditor_Check(t *testing.T) {
+	auditor := newTestContextAuditor()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	auditor.Check(ctx, "quotes.Good")
+	auditor.Check(context.Background(), "quotes.Background")
+	auditor.Check(context.TODO(), "quotes.Background")
+	auditor.Check(context.WithValue(context.Background(), traceKey{}, "t"), "quotes.NoDeadline")
+
+	assert.Equal(t, map[string][]ContextFindingKind{
+		"quotes.Background": {ContextFindingBackground},
+		"quotes.NoDeadline": {ContextFindingNoDeadline},
+	}, findingKinds(auditor.Findings()))
+	assert.Equal(t, uint64(2), auditor.Findings()[0].Count)
+}
+
+func TestContextAuditor_HandlerDetectsLostValues(t *testing.T) {
+	auditor := newTestContextAuditor()
+	handler := auditor.Handler("orders", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenantCtx := NewTenantContext(context.WithValue(r.Context(), traceKey{}, "t"), "acme")
+		// Wrapping the TenantContext loses the tenant, and a context not derived
+		// from the traced one loses the trace
+		ctx, cancel := context.WithTimeout(tenantCtx, time.Minute)
+		defer cancel()
+		auditor.Check(ctx, "quotes.Quote")
+		auditor.Check(tenantCtx, "quotes.Symbols")
+		detached, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), time.Minute)
+		defer cancel()
+		auditor.Check(NewTenantContext(detached, "acme"), "quotes.Warm")
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req := httptest.NewRequestWithContext(ctx, http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, map[string][]ContextFindingKind{
+		"orders":         {ContextFindingLostTrace},
+		"quotes.Quote":   {ContextFindingLostTenant},
+		"quotes.Symbols": {ContextFindingNoDeadline},
+		"quotes.Warm":    {ContextFindingLostTrace},
+	}, findingKinds(auditor.Findings()))
+
+	var nilAuditor *ContextAuditor
+	assert.NotNil(t, nilAuditor.Handler("orders", handler))
+	nilAuditor.Check(context.Background(), "quotes.Quote")
+	assert.Empty(t, nilAuditor.Findings())
+}
+
+func TestContextAuditor_TransportAuditsOutgoingRequests(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer backend.Close()
+	auditor := newTestContextAuditor()
+	client := &http.Client{Transport: auditor.Transport("client", nil)}
+	host := strings.TrimPrefix(backend.URL, "http://")
+
+	handler := auditor.Handler("orders", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, backend.URL+"/quotes", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+
+		// A request detached from the handler's context drops the tenant
+		resp, err = client.Get(backend.URL + "/warm") //nolint:noctx // the missing context is what is audited
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, map[string][]ContextFindingKind{
+		"client " + host: {ContextFindingBackground, ContextFindingLostTenant},
+	}, findingKinds(auditor.Findings()))
+
+	var nilAuditor *ContextAuditor
+	assert.Equal(t, http.DefaultTransport, nilAuditor.Transport("client", nil))
+}
+
+func TestStdApplication_AuditHandler(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	handler := http.NotFoundHandler()
+	assert.NotNil(t, app.AuditHandler("orders", handler), "handlers are kept when the audit is disabled")
+	assert.Equal(t, http.DefaultTransport, app.AuditTransport("client", http.DefaultTransport))
+
+	app.EnableContextAudit(ContextAuditConfig{})
+	var audited interface {
+		AuditHandler(name string, handler http.Handler) http.Handler
+	} = NewBaseApplicationDecorator(app)
+	served := false
+	audited.AuditHandler("orders", http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		_, served = r.Context().Value(contextAuditScopeKey{}).(*contextAuditScope)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	assert.True(t, served, "the request was served in an audit scope")
+}
+
+type contextQuoteService interface {
+	Quote(ctx context.Context, symbol string) (float64, error)
+}
+
+// contextQuoteServiceProxy is what modcli generate proxy emits for contextQuoteService.
+type contextQuoteServiceProxy struct {
+	service contextQuoteService
+	tracker *ServiceCallTracker
+}
+
+func (proxy *contextQuoteServiceProxy) Quote(a0 context.Context, a1 string) (r0 float64, r1 error) {
+	done := proxy.tracker.TrackContext(a0, "Quote")
+	defer func() { done(r1) }()
+	return proxy.service.Quote(a0, a1)
+}
+
+func init() {
+	RegisterServiceProxy(func(service contextQuoteService, tracker *ServiceCallTracker) contextQuoteService {
+		return &contextQuoteServiceProxy{service: service, tracker: tracker}
+	})
+}
+
+type contextQuotes struct{}
+
+func (contextQuotes) Quote(ctx context.Context, symbol string) (float64, error) { return 42, nil }
+
+type contextQuoteProviderModule struct{ testModule }
+
+func (m contextQuoteProviderModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{{Name: "quotes", Instance: contextQuoteService(contextQuotes{})}}
+}
+
+type contextQuoteConsumerModule struct {
+	testModule
+	quotes contextQuoteService
+}
+
+func (m *contextQuoteConsumerModule) Init(app Application) error {
+	return app.GetService("quotes", &m.quotes)
+}
+
+func TestContextAudit_ServiceCalls(t *testing.T) {
+	var mu sync.Mutex
+	var events []cloudevents.Event
+	app := NewObservableApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	require.NoError(t, app.RegisterObserver(NewFunctionalObserver("audit", func(ctx context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}), EventTypeContextAuditFinding))
+	auditor := app.EnableContextAudit(ContextAuditConfig{})
+	assert.Same(t, auditor, ContextAuditorFor(app))
+
+	consumer := &contextQuoteConsumerModule{testModule: testModule{name: "checkout", dependencies: []string{"pricing"}}}
+	app.RegisterModule(contextQuoteProviderModule{testModule{name: "pricing"}})
+	app.RegisterModule(consumer)
+	require.NoError(t, app.Init())
+	require.IsType(t, &contextQuoteServiceProxy{}, consumer.quotes)
+
+	for range 3 {
+		_, err := consumer.quotes.Quote(context.Background(), "ACME")
+		require.NoError(t, err)
+	}
+	findings := auditor.Findings()
+	require.Len(t, findings, 1)
+	assert.Equal(t, ContextFindingBackground, findings[0].Kind)
+	assert.Equal(t, "quotes.Quote", findings[0].Location)
+	assert.Equal(t, uint64(3), findings[0].Count)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 1
+	}, time.Second, 10*time.Millisecond, "only the first occurrence is emitted")
+	var data ContextFinding
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "quotes.Quote", data.Location)
+}
+
+func TestWithContextAudit(t *testing.T) {
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithConfigProvider(NewStdConfigProvider(&testCfg{Str: "app"})),
+		WithObserver(func(ctx context.Context, event cloudevents.Event) error { return nil }),
+		WithContextAudit(ContextAuditConfig{IgnoreDeadlines: true}),
+	)
+	require.NoError(t, err)
+	auditor := ContextAuditorFor(app)
+	require.NotNil(t, auditor, "the auditor is found through decorators")
+	assert.True(t, auditor.config.IgnoreDeadlines)
+	assert.Nil(t, ContextAuditorFor(NewStdApplication(nil, &testLogger{})))
+}
@@ -2,6 +2,7 @@ package modular
 
 import (
 	"context"
+	"net/http"
 	"reflect"
 	"time"
 
@@ -106,6 +107,66 @@ func (d *BaseApplicationDecorator) SetVerboseConfig(enabled bool) {
 	d.inner.SetVerboseConfig(enabled)
 }
 
+// RegisterConfigSectionWithFeeders forwards to the inner application. If it does not
+// support per-section feeders, the section is registered with the application-wide feeders.
+func (d *BaseApplicationDecorator) RegisterConfigSectionWithFeeders(section string, cp ConfigProvider, feeders ...Feeder) {
+	if inner, ok := d.inner.(SectionFeedersConfigurable); ok {
+		inner.RegisterConfigSectionWithFeeders(section, cp, feeders...)
+		return
+	}
+	d.inner.RegisterConfigSection(section, cp)
+}
+
+// SetSectionFeeders forwards to the inner application when it supports per-section feeders
+func (d *BaseApplicationDecorator) SetSectionFeeders(section string, feeders ...Feeder) {
+	if inner, ok := d.inner.(SectionFeedersConfigurable); ok {
+		inner.SetSectionFeeders(section, feeders...)
+	}
+}
+
+// Profiles returns the active configuration profiles of the inner application
+func (d *BaseApplicationDecorator) Profiles() []string {
+	return ActiveProfiles(d.inner)
+}
+
+// Metrics returns the metrics registry of the inner application
+func (d *BaseApplicationDecorator) Metrics() MetricsRegistry {
+	return MetricsFor(d.inner)
+}
+
+// ContextAuditor returns the context auditor of the inner application
+func (d *BaseApplicationDecorator) ContextAuditor() *ContextAuditor {
+	return ContextAuditorFor(d.inner)
+}
+
+// AuditHandler wraps handler with the context auditor of the inner application
+func (d *BaseApplicationDecorator) AuditHandler(name string, handler http.Handler) http.Handler {
+	return ContextAuditorFor(d.inner).Handler(name, handler)
+}
+
+// AuditTransport wraps base with the context auditor of the inner application
+func (d *BaseApplicationDecorator) AuditTransport(name string, base http.RoundTripper) http.RoundTripper {
+	return ContextAuditorFor(d.inner).Transport(name, base)
+}
+
+// DependencyGraph returns the dependency graph of the inner application
+func (d *BaseApplicationDecorator) DependencyGraph() *DependencyGraph {
+	return DependencyGraphFor(d.inner)
+}
+
+// ReloadConfig reloads the configuration of the inner application
+func (d *BaseApplicationDecorator) ReloadConfig(ctx context.Context) error {
+	if reloader, ok := d.inner.(interface{ ReloadConfig(context.Context) error }); ok {
+		return reloader.ReloadConfig(ctx)
+	}
+	return ErrConfigReloadUnsupported
+}
+
+// Info returns the build and runtime information of the inner application
+func (d *BaseApplicationDecorator) Info() AppInfo {
+	return InfoFor(d.inner)
+}
+
 func (d *BaseApplicationDecorator) IsVerboseConfig() bool {
 	return d.inner.IsVerboseConfig()
 }
@@ -191,3 +252,13 @@ func (d *BaseApplicationDecorator) GetObservers() []ObserverInfo {
 func (d *BaseApplicationDecorator) OnConfigLoaded(hook func(Application) error) {
 	d.inner.OnConfigLoaded(hook)
 }
+
+// OnLifecycle forwards the hook registration to the inner application, if it
+// supports lifecycle hooks
+func (d *BaseApplicationDecorator) OnLifecycle(phase LifecyclePhase, hooks ...LifecycleHook) {
+	if hooked, ok := d.inner.(interface {
+		OnLifecycle(LifecyclePhase, ...LifecycleHook)
+	}); ok {
+		hooked.OnLifecycle(phase, hooks...)
+	}
+}
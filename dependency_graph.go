@@ -0,0 +1,287 @@
+package modular
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// DependencyGraphPath is the conventional path for mounting NewDependencyGraphHandler.
+const DependencyGraphPath = "/__dependencies"
+
+// Module states reported in a DependencyGraph, from the application's timeline.
+const (
+	// ModuleStateRegistered is a module not initialized yet
+	ModuleStateRegistered = "registered"
+	// ModuleStateLazy is a lazy module whose initialization is still deferred
+	ModuleStateLazy = "lazy"
+	// ModuleStateInitFailed is a module whose Init returned an error
+	ModuleStateInitFailed = "init_failed"
+	// ModuleStateInitialized is a module initialized but not started
+	ModuleStateInitialized = "initialized"
+	// ModuleStateStartFailed is a module whose Start returned an error
+	ModuleStateStartFailed = "start_failed"
+	// ModuleStateStarted is a running module
+	ModuleStateStarted = "started"
+	// ModuleStateStopped is a module stopped after running
+	ModuleStateStopped = "stopped"
+)
+
+// DependencyGraph describes the modules of an application, the dependencies between
+// them and the order they are initialized in.
+type DependencyGraph struct {
+	// Modules lists the registered modules, sorted by name
+	Modules []DependencyGraphModule `json:"modules"`
+	// Edges lists the dependencies ordering initialization, sorted
+	Edges []DependencyGraphEdge `json:"edges"`
+	// InitOrder lists the modules in the order they are initialized and started.
+	// It is empty when no order could be resolved.
+	InitOrder []string `json:"init_order"`
+	// Error explains why no initialization order could be resolved, such as a
+	// circular dependency or a dependency on a module that isn't registered
+	Error string `json:"error,omitempty"`
+}
+
+// DependencyGraphModule is a module in a DependencyGraph.
+type DependencyGraphModule struct {
+	Name string `json:"name"`
+	// State is the module's lifecycle state, one of the ModuleState constants
+	State string `json:"state"`
+	// Error is the error of the module's failed Init or Start
+	Error string `json:"error,omitempty"`
+	// DependsOn lists the modules the module declares as dependencies
+	DependsOn []string `json:"depends_on,omitempty"`
+	// Provides lists the names of the services the module provides
+	Provides []string `json:"provides,omitempty"`
+	// Requires lists the services the module requires
+	Requires []DependencyGraphService `json:"requires,omitempty"`
+}
+
+// DependencyGraphService is a service a module requires.
+type DependencyGraphService struct {
+	Name string `json:"name"`
+	// Interface is the interface the service is matched by, for interface-based
+	// dependencies
+	Interface string `json:"interface,omitempty"`
+	Required  bool   `json:"required"`
+	// Providers lists the modules providing a matching service. A service
+	// registered by the application itself has no provider module but is
+	// Available.
+	Providers []string `json:"providers,omitempty"`
+	// Available reports whether a provider or registered service satisfies the
+	// requirement
+	Available bool `json:"available"`
+}
+
+// DependencyGraphEdge is a dependency of module From on module To.
+type DependencyGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Type is "module" for declared dependencies, "named-service" and
+	// "interface-service" for dependencies implied by required services
+	Type string `json:"type"`
+	// Service is the required service implying the dependency
+	Service string `json:"service,omitempty"`
+	// Interface is the interface the service is matched by
+	Interface string `json:"interface,omitempty"`
+}
+
+// DependencyGraphProvider is implemented by applications exposing their dependency
+// graph. StdApplication and ObservableApplication implement it.
+type DependencyGraphProvider interface {
+	// DependencyGraph returns the resolved module and service dependencies
+	DependencyGraph() *DependencyGraph
+}
+
+var (
+	_ DependencyGraphProvider = (*StdApplication)(nil)
+	_ DependencyGraphProvider = (*ObservableApplication)(nil)
+)
+
+// DependencyGraph returns the application's modules with their state, the services
+// they provide and require, the dependencies between them and the initialization
+// order. It can be called at any time, including when Init failed, to find out why a
+// module didn't start: a missing required service, a dependency cycle reported in
+// Error, or the module's own Init error. Render it with its DOT method or as JSON.
+func (app *StdApplication) DependencyGraph() *DependencyGraph {
+	graph := &DependencyGraph{
+		Modules:   []DependencyGraphModule{},
+		Edges:     []DependencyGraphEdge{},
+		InitOrder: []string{},
+	}
+
+	_, edges := app.buildDependencyGraph()
+	for _, edge := range edges {
+		graphEdge := DependencyGraphEdge{From: edge.From, To: edge.To, Type: edge.Type.String(), Service: edge.ServiceName}
+		if edge.InterfaceType != nil {
+			graphEdge.Interface = edge.InterfaceType.String()
+		}
+		graph.Edges = append(graph.Edges, graphEdge)
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		a, b := graph.Edges[i], graph.Edges[j]
+		return a.From+"\x00"+a.To+"\x00"+a.Type < b.From+"\x00"+b.To+"\x00"+b.Type
+	})
+
+	if order, err := app.resolveDependencies(); err != nil {
+		graph.Error = err.Error()
+	} else {
+		graph.InitOrder = order
+	}
+
+	requiredInterfaces, serviceProviders := app.collectServiceRequirements()
+	providersByInterface := make(map[string][]string) // keyed by consumer, service and interface
+	for _, match := range app.findInterfaceMatches(requiredInterfaces) {
+		key := match.Consumer + "\x00" + match.ServiceName + "\x00" + match.InterfaceType.String()
+		if !slices.Contains(providersByInterface[key], match.Provider) {
+			providersByInterface[key] = append(providersByInterface[key], match.Provider)
+		}
+	}
+
+	timeline := app.Timeline()
+	names := make([]string, 0, len(app.moduleRegistry))
+	for name := range app.moduleRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		module := app.moduleRegistry[name]
+		node := DependencyGraphModule{Name: name}
+		node.State, node.Error = app.moduleState(name, timeline)
+		if aware, ok := module.(DependencyAware); ok {
+			node.DependsOn = slices.Sorted(slices.Values(aware.Dependencies()))
+		}
+		if aware, ok := module.(ServiceAware); ok {
+			for _, provided := range aware.ProvidesServices() {
+				node.Provides = append(node.Provides, provided.Name)
+			}
+			sort.Strings(node.Provides)
+			for _, dep := range aware.RequiresServices() {
+				node.Requires = append(node.Requires, app.requiredServiceInfo(name, dep, serviceProviders, providersByInterface))
+			}
+		}
+		graph.Modules = append(graph.Modules, node)
+	}
+	return graph
+}
+
+// moduleState returns the lifecycle state of the named module from the timeline, and
+// the error of its failed Init or Start.
+func (app *StdApplication) moduleState(name string, timeline Timeline) (string, string) {
+	if app.pendingLazy(name) {
+		if err := app.lazyModules[name].failure(); err != nil {
+			return ModuleStateInitFailed, err.Error()
+		}
+		return ModuleStateLazy, ""
+	}
+	state := ModuleStateRegistered
+	for _, event := range timeline.Module(name) {
+		switch event.Kind {
+		case TimelineModuleInit:
+			if event.Error != "" {
+				return ModuleStateInitFailed, event.Error
+			}
+			state = ModuleStateInitialized
+		case TimelineModuleStart:
+			if event.Error != "" {
+				return ModuleStateStartFailed, event.Error
+			}
+			state = ModuleStateStarted
+		case TimelineModuleStop:
+			state = ModuleStateStopped
+		}
+	}
+	return state, ""
+}
+
+// requiredServiceInfo describes the service dependency dep of the named module and
+// the modules providing it.
+func (app *StdApplication) requiredServiceInfo(
+	consumer string,
+	dep ServiceDependency,
+	serviceProviders map[string]string,
+	providersByInterface map[string][]string,
+) DependencyGraphService {
+	info := DependencyGraphService{Name: dep.Name, Required: dep.Required}
+	if app.isInterfaceBasedDependency(dep) {
+		info.Interface = dep.SatisfiesInterface.String()
+		info.Providers = slices.Sorted(slices.Values(providersByInterface[consumer+"\x00"+dep.Name+"\x00"+info.Interface]))
+	} else if provider, ok := serviceProviders[dep.Name]; ok {
+		info.Providers = []string{provider}
+	}
+	_, registered := app.services()[dep.Name]
+	info.Available = len(info.Providers) > 0 || registered
+	return info
+}
+
+// DOT renders the graph in the Graphviz DOT language, with an edge from each module
+// to the modules it depends on. Modules that failed, or miss a required service, are
+// drawn in red.
+//
+//	curl -s localhost:8080/__dependencies?format=dot | dot -Tsvg > modules.svg
+func (g *DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph modules {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+	for _, module := range g.Modules {
+		attrs := []string{fmt.Sprintf("label=%q", module.Name+"\n"+module.State)}
+		if module.unhealthy() {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "  %q [%s];\n", module.Name, strings.Join(attrs, ", "))
+	}
+	for _, edge := range g.Edges {
+		switch {
+		case edge.Service != "":
+			fmt.Fprintf(&b, "  %q -> %q [label=%q, style=dashed];\n", edge.From, edge.To, edge.Service)
+		default:
+			fmt.Fprintf(&b, "  %q -> %q;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// unhealthy reports whether the module failed or misses a required service.
+func (m DependencyGraphModule) unhealthy() bool {
+	if m.State == ModuleStateInitFailed || m.State == ModuleStateStartFailed {
+		return true
+	}
+	for _, svc := range m.Requires {
+		if svc.Required && !svc.Available {
+			return true
+		}
+	}
+	return false
+}
+
+// DependencyGraphFor returns the dependency graph of app, or nil for applications
+// that do not implement DependencyGraphProvider.
+func DependencyGraphFor(app Application) *DependencyGraph {
+	if provider, ok := app.(DependencyGraphProvider); ok {
+		return provider.DependencyGraph()
+	}
+	return nil
+}
+
+// NewDependencyGraphHandler serves the provider's DependencyGraph as JSON, or as DOT
+// with ?format=dot. Mount it at DependencyGraphPath:
+//
+//	router.Handle(modular.DependencyGraphPath, modular.NewDependencyGraphHandler(app))
+func NewDependencyGraphHandler(provider DependencyGraphProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		graph := provider.DependencyGraph()
+		w.Header().Set("Cache-Control", "no-store")
+		if r.URL.Query().Get("format") == "dot" {
+			w.Header().Set("Content-Type", "text/vnd.graphviz")
+			_, _ = w.Write([]byte(graph.DOT()))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(graph)
+	})
+}
@@ -0,0 +1,153 @@
+package modular
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphPinger interface {
+	Ping() error
+}
+
+type graphDatabase struct{}
+
+func (graphDatabase) Ping() error { return nil }
+
+// graphTestModule declares dependencies and services for dependency graph tests.
+type graphTestModule struct {
+	testModule
+	provides []ServiceProvider
+	requires []ServiceDependency
+	initErr  error
+}
+
+func (m *graphTestModule) Init(Application) error                { return m.initErr }
+func (m *graphTestModule) ProvidesServices() []ServiceProvider   { return m.provides }
+func (m *graphTestModule) RequiresServices() []ServiceDependency { return m.requires }
+
+func newGraphTestApp(modules ...Module) *StdApplication {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	for _, module := range modules {
+		app.RegisterModule(module)
+	}
+	return app
+}
+
+func TestStdApplication_DependencyGraph(t *testing.T) {
+	app := newGraphTestApp(
+		&graphTestModule{
+			testModule: testModule{name: "db"},
+			provides:   []ServiceProvider{{Name: "database", Instance: graphDatabase{}}},
+		},
+		&graphTestModule{
+			testModule: testModule{name: "api", dependencies: []string{"web"}},
+			requires:   []ServiceDependency{{Name: "database", Required: true}},
+		},
+		&graphTestModule{
+			testModule: testModule{name: "health"},
+			requires: []ServiceDependency{{
+				Name: "pinger", MatchByInterface: true,
+				SatisfiesInterface: reflect.TypeOf((*graphPinger)(nil)).Elem(),
+			}},
+		},
+		&graphTestModule{testModule: testModule{name: "web"}},
+	)
+
+	graph := app.DependencyGraph()
+	assert.Empty(t, graph.Error)
+	assert.Equal(t, []string{"db", "web", "api", "health"}, graph.InitOrder)
+	assert.Equal(t, []DependencyGraphEdge{
+		{From: "api", To: "db", Type: "named-service", Service: "database"},
+		{From: "api", To: "web", Type: "module"},
+		{From: "health", To: "db", Type: "interface-service", Service: "pinger", Interface: "modular.graphPinger"},
+	}, graph.Edges)
+
+	require.Len(t, graph.Modules, 4)
+	api := graph.Modules[0]
+	assert.Equal(t, "api", api.Name)
+	assert.Equal(t, ModuleStateRegistered, api.State)
+	assert.Equal(t, []string{"web"}, api.DependsOn)
+	assert.Equal(t, []DependencyGraphService{{Name: "database", Required: true, Providers: []string{"db"}, Available: true}}, api.Requires)
+	assert.Equal(t, []string{"database"}, graph.Modules[1].Provides)
+	assert.Equal(t, []string{"db"}, graph.Modules[2].Requires[0].Providers)
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+	for _, module := range app.DependencyGraph().Modules {
+		assert.Equal(t, ModuleStateStarted, module.State, module.Name)
+	}
+}
+
+func TestStdApplication_DependencyGraphExplainsFailures(t *testing.T) {
+	app := newGraphTestApp(
+		&graphTestModule{
+			testModule: testModule{name: "mailer"},
+			requires:   []ServiceDependency{{Name: "smtp", Required: true}},
+		},
+	)
+	require.Error(t, app.Init())
+
+	graph := app.DependencyGraph()
+	mailer := graph.Modules[0]
+	assert.Equal(t, []DependencyGraphService{{Name: "smtp", Required: true}}, mailer.Requires)
+	assert.True(t, mailer.unhealthy(), "a missing required service marks the module")
+	dot := graph.DOT()
+	assert.Contains(t, dot, "\"mailer\" [label=")
+	assert.Contains(t, dot, "color=red")
+
+	cyclic := newGraphTestApp(
+		&graphTestModule{testModule: testModule{name: "a", dependencies: []string{"b"}}},
+		&graphTestModule{testModule: testModule{name: "b", dependencies: []string{"a"}}},
+	)
+	graph = cyclic.DependencyGraph()
+	assert.Contains(t, graph.Error, ErrCircularDependency.Error())
+	assert.Empty(t, graph.InitOrder)
+	assert.Len(t, graph.Edges, 2)
+}
+
+func TestStdApplication_DependencyGraphInitFailure(t *testing.T) {
+	app := newGraphTestApp(&graphTestModule{testModule: testModule{name: "queue"}, initErr: errors.New("broker unreachable")})
+	require.Error(t, app.Init())
+
+	queue := app.DependencyGraph().Modules[0]
+	assert.Equal(t, ModuleStateInitFailed, queue.State)
+	assert.Contains(t, queue.Error, "broker unreachable")
+}
+
+func TestNewDependencyGraphHandler(t *testing.T) {
+	app := newGraphTestApp(
+		&graphTestModule{testModule: testModule{name: "api", dependencies: []string{"web"}}},
+		&graphTestModule{testModule: testModule{name: "web"}},
+	)
+	handler := NewDependencyGraphHandler(app)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DependencyGraphPath, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var graph DependencyGraph
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &graph))
+	assert.Equal(t, []string{"web", "api"}, graph.InitOrder)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, DependencyGraphPath+"?format=dot", nil))
+	assert.Equal(t, "text/vnd.graphviz", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "digraph modules {")
+	assert.Contains(t, rec.Body.String(), "\"api\" -> \"web\";")
+
+	assert.Nil(t, DependencyGraphFor(&testApplicationWithoutGraph{}))
+	decorated := NewBaseApplicationDecorator(app)
+	assert.Equal(t, graph.InitOrder, DependencyGraphFor(decorated).InitOrder)
+}
+
+// testApplicationWithoutGraph is an Application not exposing a dependency graph.
+type testApplicationWithoutGraph struct {
+	Application
+}
@@ -0,0 +1,272 @@
+package modular
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTenantRefreshInterval is how often a DynamicTenantService polls its
+// source for changes unless configured otherwise.
+const DefaultTenantRefreshInterval = 30 * time.Second
+
+// TenantDefinition describes a tenant as stored in a TenantSource.
+type TenantDefinition struct {
+	// ID identifies the tenant
+	ID TenantID
+	// Sections holds the JSON of each of the tenant's configuration sections, keyed by section name
+	Sections map[string]json.RawMessage
+	// Feeders feed the tenant's configuration sections, such as the file feeder of a DirectoryTenantSource
+	Feeders []Feeder
+	// Version identifies the definition's content so changes can be detected.
+	// When empty, a hash of Sections is used.
+	Version string
+}
+
+// version returns the definition's Version, or a hash of its sections.
+func (d TenantDefinition) version() string {
+	if d.Version != "" {
+		return d.Version
+	}
+	// Marshaling sorts the section keys, so equal sections hash equally
+	data, err := json.Marshal(d.Sections)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// TenantSource loads tenant definitions from an external store, such as a
+// directory of config files, a database table or an HTTP endpoint.
+type TenantSource interface {
+	// LoadTenants returns every tenant currently in the store
+	LoadTenants(ctx context.Context) ([]TenantDefinition, error)
+}
+
+// TenantWatcher is implemented by tenant services that keep their tenants in sync
+// with an external store while the application runs. The application calls
+// WatchTenants once its modules have started and cancels ctx when it stops.
+type TenantWatcher interface {
+	WatchTenants(ctx context.Context) error
+}
+
+// DynamicTenantService is a tenant service whose tenants come from a TenantSource
+// instead of being registered in code before Init. It loads the tenants during
+// Init and, while the application runs, polls the source: tenants added to the
+// store are registered, tenants whose definition changed get the new configuration,
+// and tenants removed from the store are removed, with tenant-aware modules
+// notified through OnTenantRegistered and OnTenantRemoved. A changed tenant is
+// notified as removed and then registered again, so modules rebuild it from the new
+// configuration.
+//
+// Register it as the tenant service; unless a separate tenantConfigLoader service
+// is registered, the application uses it to load the tenants:
+//
+//	tenants := modular.NewDynamicTenantService(logger, modular.NewDirectoryTenantSource("tenants"))
+//	app.RegisterService("tenantService", tenants)
+//
+// Tenants registered in code are left alone by refreshes.
+type DynamicTenantService struct {
+	*StandardTenantService
+	source   TenantSource
+	interval time.Duration
+
+	refreshMu sync.Mutex
+	app       Application
+	versions  map[TenantID]string // tenants loaded from the source
+}
+
+// Compile-time checks that DynamicTenantService loads and watches its tenants
+var (
+	_ TenantService      = (*DynamicTenantService)(nil)
+	_ TenantConfigLoader = (*DynamicTenantService)(nil)
+	_ TenantWatcher      = (*DynamicTenantService)(nil)
+)
+
+// DynamicTenantOption configures a DynamicTenantService.
+type DynamicTenantOption func(*DynamicTenantService)
+
+// WithTenantRefreshInterval sets how often the source is polled for changes.
+func WithTenantRefreshInterval(interval time.Duration) DynamicTenantOption {
+	return func(s *DynamicTenantService) {
+		if interval > 0 {
+			s.interval = interval
+		}
+	}
+}
+
+// NewDynamicTenantService creates a tenant service backed by source.
+func NewDynamicTenantService(logger Logger, source TenantSource, opts ...DynamicTenantOption) *DynamicTenantService {
+	s := &DynamicTenantService{
+		StandardTenantService: NewStandardTenantService(logger),
+		source:                source,
+		interval:              DefaultTenantRefreshInterval,
+		versions:              make(map[TenantID]string),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// LoadTenantConfigurations implements TenantConfigLoader, loading the tenants
+// from the source once the application's config sections are registered.
+func (s *DynamicTenantService) LoadTenantConfigurations(app Application, _ TenantService) error {
+	s.refreshMu.Lock()
+	s.app = app
+	s.refreshMu.Unlock()
+
+	if err := s.Refresh(context.Background()); err != nil {
+		return err
+	}
+	app.Logger().Info("Loaded tenants from source", "tenantCount", len(s.GetTenants()))
+	return nil
+}
+
+// Refresh synchronizes the registered tenants with the source. A tenant whose
+// configuration fails to load keeps its previous configuration; the failures are
+// returned together once the other tenants are synchronized.
+func (s *DynamicTenantService) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if s.app == nil {
+		return ErrTenantSourceNotLoaded
+	}
+
+	definitions, err := s.source.LoadTenants(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTenantSourceFailed, err)
+	}
+
+	var errs []error
+	present := make(map[TenantID]bool, len(definitions))
+	for _, definition := range definitions {
+		if definition.ID == "" {
+			s.logger.Warn("Skipping tenant definition without an ID")
+			continue
+		}
+		present[definition.ID] = true
+
+		version := definition.version()
+		previous, known := s.versions[definition.ID]
+		if known && previous == version {
+			continue
+		}
+
+		configs, err := s.loadConfigs(definition)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", definition.ID, err))
+			continue
+		}
+
+		if known {
+			s.replaceTenantConfigs(definition.ID, configs)
+			s.logger.Info("Reloaded tenant configuration", "tenantID", definition.ID)
+		} else if err := s.RegisterTenant(definition.ID, configs); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", definition.ID, err))
+			continue
+		}
+		s.versions[definition.ID] = version
+	}
+
+	for tenantID := range s.versions {
+		if present[tenantID] {
+			continue
+		}
+		if err := s.RemoveTenant(tenantID); err != nil && !errors.Is(err, ErrTenantNotFound) {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenantID, err))
+			continue
+		}
+		delete(s.versions, tenantID)
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadConfigs feeds the application's config sections for a tenant definition.
+func (s *DynamicTenantService) loadConfigs(definition TenantDefinition) (map[string]ConfigProvider, error) {
+	configFeeders := append([]Feeder(nil), definition.Feeders...)
+	if len(definition.Sections) > 0 {
+		configFeeders = append(configFeeders, tenantSectionsFeeder(definition.Sections))
+	}
+	return loadTenantConfig(s.app, configFeeders, string(definition.ID))
+}
+
+// WatchTenants implements TenantWatcher, refreshing the tenants on the configured
+// interval until ctx is cancelled. Failed refreshes are logged and retried on the
+// next tick.
+func (s *DynamicTenantService) WatchTenants(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Warn("Failed to refresh tenants from source", "error", err)
+			}
+		}
+	}
+}
+
+// tenantSectionsFeeder feeds configuration sections from their JSON.
+type tenantSectionsFeeder map[string]json.RawMessage
+
+// Feed does nothing; sections are fed by key.
+func (f tenantSectionsFeeder) Feed(interface{}) error {
+	return nil
+}
+
+// FeedKey unmarshals the section named key into target.
+func (f tenantSectionsFeeder) FeedKey(key string, target interface{}) error {
+	raw, ok := f[key]
+	if !ok {
+		return nil
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return fmt.Errorf("failed to decode section %s: %w", key, err)
+	}
+	return nil
+}
+
+// tenantWatch runs the tenant service's TenantWatcher while the application runs.
+type tenantWatch struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startTenantWatch starts watching tenants if the tenant service is a TenantWatcher.
+func (app *StdApplication) startTenantWatch(ctx context.Context) {
+	watcher, ok := app.tenantService.(TenantWatcher)
+	if !ok || app.tenantWatch != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	watch := &tenantWatch{cancel: cancel, done: make(chan struct{})}
+	app.tenantWatch = watch
+	go func() {
+		defer close(watch.done)
+		if err := watcher.WatchTenants(ctx); err != nil {
+			app.logger.Error("Tenant watch stopped", "error", err)
+		}
+	}()
+}
+
+// stopTenantWatch stops watching tenants and waits for the watcher to return.
+func (app *StdApplication) stopTenantWatch() {
+	if app.tenantWatch == nil {
+		return
+	}
+	app.tenantWatch.cancel()
+	<-app.tenantWatch.done
+	app.tenantWatch = nil
+}
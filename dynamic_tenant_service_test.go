@@ -0,0 +1,296 @@
+package modular
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type dynamicTenantDBConfig struct {
+	DSN      string `json:"dsn" yaml:"dsn"`
+	MaxConns int    `json:"max_conns" yaml:"max_conns"`
+}
+
+// tenantRecordingModule records the tenant notifications it receives.
+type tenantRecordingModule struct {
+	testModule
+	mu         sync.Mutex
+	registered []TenantID
+	removed    []TenantID
+	sequence   []string
+}
+
+func (m *tenantRecordingModule) OnTenantRegistered(tenantID TenantID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.registered = append(m.registered, tenantID)
+	m.sequence = append(m.sequence, "registered "+string(tenantID))
+}
+
+func (m *tenantRecordingModule) OnTenantRemoved(tenantID TenantID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.removed = append(m.removed, tenantID)
+	m.sequence = append(m.sequence, "removed "+string(tenantID))
+}
+
+func (m *tenantRecordingModule) notifications() ([]TenantID, []TenantID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	registered := slices.Clone(m.registered)
+	removed := slices.Clone(m.removed)
+	slices.Sort(registered)
+	slices.Sort(removed)
+	return registered, removed
+}
+
+// newDynamicTenantApp creates an application with a database config section,
+// a tenant-aware module and the dynamic tenant service registered.
+func newDynamicTenantApp(t *testing.T, tenants *DynamicTenantService) (Application, *tenantRecordingModule) {
+	t.Helper()
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterConfigSection("database", NewStdConfigProvider(&dynamicTenantDBConfig{MaxConns: 5}))
+	module := &tenantRecordingModule{testModule: testModule{name: "recorder"}}
+	app.RegisterModule(module)
+	require.NoError(t, app.RegisterService("tenantService", tenants))
+	require.NoError(t, app.Init())
+	return app, module
+}
+
+func tenantDSN(t *testing.T, tenants TenantService, tenantID TenantID) string {
+	t.Helper()
+	provider, err := tenants.GetTenantConfig(tenantID, "database")
+	require.NoError(t, err)
+	return provider.GetConfig().(*dynamicTenantDBConfig).DSN
+}
+
+func TestDynamicTenantService_DirectorySource(t *testing.T) {
+	dir := t.TempDir()
+	writeTenant := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	writeTenant("acme.yaml", "database:\n  dsn: postgres://acme\n")
+	writeTenant("globex.json", `{"database": {"dsn": "postgres://globex", "max_conns": 20}}`)
+	writeTenant("notes.txt", "not a tenant")
+
+	tenants := NewDynamicTenantService(&testLogger{}, NewDirectoryTenantSource(dir))
+	app, module := newDynamicTenantApp(t, tenants)
+
+	registered, _ := module.notifications()
+	assert.Equal(t, []TenantID{"acme", "globex"}, registered, "tenants are loaded during Init")
+	assert.Equal(t, "postgres://acme", tenantDSN(t, tenants, "acme"))
+	provider, err := tenants.GetTenantConfig("globex", "database")
+	require.NoError(t, err)
+	assert.Equal(t, 20, provider.GetConfig().(*dynamicTenantDBConfig).MaxConns)
+	assert.Equal(t, 5, app.ConfigSections()["database"].GetConfig().(*dynamicTenantDBConfig).MaxConns, "the base config is untouched")
+
+	// Onboard a tenant, change one and offboard another
+	writeTenant("initech.yaml", "database:\n  dsn: postgres://initech\n")
+	writeTenant("acme.yaml", "database:\n  dsn: postgres://acme-v2\n")
+	require.NoError(t, os.Remove(filepath.Join(dir, "globex.json")))
+	require.NoError(t, tenants.Refresh(context.Background()))
+
+	registered, removed := module.notifications()
+	assert.Equal(t, []TenantID{"acme", "acme", "globex", "initech"}, registered, "changed tenants are registered again")
+	assert.Equal(t, []TenantID{"acme", "globex"}, removed)
+	module.mu.Lock()
+	acme := slices.DeleteFunc(slices.Clone(module.sequence), func(n string) bool { return !strings.HasSuffix(n, " acme") })
+	module.mu.Unlock()
+	assert.Equal(t, []string{"registered acme", "removed acme", "registered acme"}, acme,
+		"a changed tenant is removed before it is registered with its new configuration")
+	assert.Equal(t, "postgres://acme-v2", tenantDSN(t, tenants, "acme"))
+	assert.Equal(t, "postgres://initech", tenantDSN(t, tenants, "initech"))
+	assert.ElementsMatch(t, []TenantID{"acme", "initech"}, tenants.GetTenants())
+}
+
+func TestDynamicTenantService_KeepsTenantsOnFailures(t *testing.T) {
+	source := &stubTenantSource{definitions: []TenantDefinition{
+		{ID: "acme", Sections: map[string]json.RawMessage{"database": json.RawMessage(`{"dsn": "postgres://acme"}`)}},
+	}}
+	tenants := NewDynamicTenantService(&testLogger{}, source)
+	_, module := newDynamicTenantApp(t, tenants)
+	require.NoError(t, tenants.RegisterTenant("manual", nil))
+
+	// A source failure leaves the tenants as they were
+	source.set(nil, errors.New("connection refused"))
+	require.ErrorIs(t, tenants.Refresh(context.Background()), ErrTenantSourceFailed)
+	assert.ElementsMatch(t, []TenantID{"acme", "manual"}, tenants.GetTenants())
+
+	// A tenant whose configuration fails to load keeps its previous configuration
+	source.set([]TenantDefinition{
+		{ID: "acme", Sections: map[string]json.RawMessage{"database": json.RawMessage(`{"dsn": 42}`)}},
+	}, nil)
+	err := tenants.Refresh(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tenant acme")
+	assert.Equal(t, "postgres://acme", tenantDSN(t, tenants, "acme"))
+
+	// Tenants registered in code are left alone when the source empties
+	source.set([]TenantDefinition{}, nil)
+	require.NoError(t, tenants.Refresh(context.Background()))
+	assert.Equal(t, []TenantID{"manual"}, tenants.GetTenants())
+	_, removed := module.notifications()
+	assert.Equal(t, []TenantID{"acme"}, removed)
+}
+
+func TestDynamicTenantService_RefreshBeforeLoad(t *testing.T) {
+	tenants := NewDynamicTenantService(&testLogger{}, &stubTenantSource{})
+	assert.ErrorIs(t, tenants.Refresh(context.Background()), ErrTenantSourceNotLoaded)
+}
+
+func TestDynamicTenantService_WatchesWhileRunning(t *testing.T) {
+	var mu sync.Mutex
+	document := `{"acme": {"database": {"dsn": "postgres://acme"}}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = io.WriteString(w, document)
+	}))
+	t.Cleanup(server.Close)
+
+	tenants := NewDynamicTenantService(&testLogger{}, NewHTTPTenantSource(server.URL), WithTenantRefreshInterval(10*time.Millisecond))
+	app, module := newDynamicTenantApp(t, tenants)
+	require.NoError(t, app.Start())
+
+	mu.Lock()
+	document = `{"acme": {"database": {"dsn": "postgres://acme"}}, "globex": {}}`
+	mu.Unlock()
+	assert.Eventually(t, func() bool {
+		registered, _ := module.notifications()
+		return slices.Contains(registered, "globex")
+	}, time.Second, 5*time.Millisecond, "tenants onboarded at runtime are registered")
+	assert.Equal(t, "postgres://acme", tenantDSN(t, tenants, "acme"))
+
+	require.NoError(t, app.Stop())
+	mu.Lock()
+	document = `{}`
+	mu.Unlock()
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, tenants.GetTenants(), 2, "the source isn't watched once stopped")
+}
+
+func TestHTTPTenantSource_ETag(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, `{"acme": {"database": {"dsn": "postgres://acme"}}, "globex": null}`)
+	}))
+	t.Cleanup(server.Close)
+
+	source := NewHTTPTenantSource(server.URL)
+	source.Header = http.Header{"Authorization": {"Bearer token"}}
+	first, err := source.LoadTenants(context.Background())
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+
+	second, err := source.LoadTenants(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "a 304 keeps the previous tenants")
+	assert.Equal(t, int32(2), requests.Load())
+
+	failing := NewHTTPTenantSource(server.URL + "/missing")
+	failing.Client = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway", Body: http.NoBody}, nil
+	})}
+	_, err = failing.LoadTenants(context.Background())
+	assert.ErrorIs(t, err, ErrTenantSourceStatus)
+}
+
+func TestSQLTenantSource(t *testing.T) {
+	db := sql.OpenDB(tenantRowsDriver{rows: [][2]driver.Value{
+		{"acme", []byte(`{"database": {"dsn": "postgres://acme"}}`)},
+		{"globex", nil},
+	}})
+	t.Cleanup(func() { _ = db.Close() })
+
+	tenants := NewDynamicTenantService(&testLogger{}, NewSQLTenantSource(db, "SELECT id, config FROM tenants"))
+	newDynamicTenantApp(t, tenants)
+	assert.ElementsMatch(t, []TenantID{"acme", "globex"}, tenants.GetTenants())
+	assert.Equal(t, "postgres://acme", tenantDSN(t, tenants, "acme"))
+}
+
+// stubTenantSource returns the definitions it is given.
+type stubTenantSource struct {
+	mu          sync.Mutex
+	definitions []TenantDefinition
+	err         error
+}
+
+func (s *stubTenantSource) set(definitions []TenantDefinition, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.definitions, s.err = definitions, err
+}
+
+func (s *stubTenantSource) LoadTenants(context.Context) ([]TenantDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.definitions, s.err
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+// tenantRowsDriver is a database/sql connector whose every query returns rows.
+type tenantRowsDriver struct {
+	rows [][2]driver.Value
+}
+
+func (d tenantRowsDriver) Open(string) (driver.Conn, error) { return tenantRowsConn(d), nil }
+func (d tenantRowsDriver) Connect(context.Context) (driver.Conn, error) {
+	return tenantRowsConn(d), nil
+}
+func (d tenantRowsDriver) Driver() driver.Driver { return d }
+
+type tenantRowsConn tenantRowsDriver
+
+func (c tenantRowsConn) Prepare(string) (driver.Stmt, error) { return tenantRowsStmt(c), nil }
+func (c tenantRowsConn) Close() error                        { return nil }
+func (c tenantRowsConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type tenantRowsStmt tenantRowsDriver
+
+func (s tenantRowsStmt) Close() error                               { return nil }
+func (s tenantRowsStmt) NumInput() int                              { return 0 }
+func (s tenantRowsStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s tenantRowsStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &tenantRows{rows: s.rows}, nil
+}
+
+type tenantRows struct {
+	rows [][2]driver.Value
+}
+
+func (r *tenantRows) Columns() []string { return []string{"id", "config"} }
+func (r *tenantRows) Close() error      { return nil }
+func (r *tenantRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	dest[0], dest[1] = r.rows[0][0], r.rows[0][1]
+	r.rows = r.rows[1:]
+	return nil
+}
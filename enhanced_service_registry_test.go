@@ -245,3 +245,82 @@ func TestEnhancedServiceRegistry_CounterFallback(t *testing.T) {
 	assert.True(t, found)
 	assert.Equal(t, service5, retrieved5)
 }
+
+func TestEnhancedServiceRegistry_AllowOverride(t *testing.T) {
+	registry := NewEnhancedServiceRegistry()
+	var changes [][2]*ServiceRegistryEntry
+	registry.onChange = func(entry, previous *ServiceRegistryEntry) {
+		changes = append(changes, [2]*ServiceRegistryEntry{entry, previous})
+	}
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule1{})
+	_, err := registry.RegisterService("evaluator", &ServiceRegistryTestImplementation1{})
+	require.NoError(t, err)
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule2{})
+	replacement := &ServiceRegistryTestImplementation2{}
+	actualName, err := registry.RegisterService("evaluator", replacement, AllowOverride())
+	registry.ClearCurrentModule()
+	require.NoError(t, err)
+	assert.Equal(t, "evaluator", actualName)
+
+	retrieved, found := registry.GetService("evaluator")
+	assert.True(t, found)
+	assert.Same(t, replacement, retrieved)
+	assert.Empty(t, registry.GetServicesByModule("module1"))
+	assert.Equal(t, []string{"evaluator"}, registry.GetServicesByModule("module2"))
+
+	require.Len(t, changes, 2)
+	assert.Nil(t, changes[0][1])
+	assert.Equal(t, "module2", changes[1][0].ModuleName)
+	require.NotNil(t, changes[1][1])
+	assert.Equal(t, "module1", changes[1][1].ModuleName)
+}
+
+func TestEnhancedServiceRegistry_ConflictErrorPolicy(t *testing.T) {
+	registry := NewEnhancedServiceRegistry()
+	registry.SetConflictPolicy(ServiceConflictError)
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule1{})
+	_, err := registry.RegisterService("evaluator", &ServiceRegistryTestImplementation1{})
+	require.NoError(t, err)
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule2{})
+	_, err = registry.RegisterService("evaluator", &ServiceRegistryTestImplementation2{})
+	require.ErrorIs(t, err, ErrServiceAlreadyRegistered)
+	assert.Contains(t, err.Error(), `module "module1"`)
+
+	_, err = registry.RegisterService("evaluator", &ServiceRegistryTestImplementation2{}, AllowOverride())
+	require.NoError(t, err)
+	registry.ClearCurrentModule()
+
+	entry, found := registry.GetServiceEntry("evaluator")
+	require.True(t, found)
+	assert.Equal(t, "module2", entry.ModuleName)
+}
+
+func TestEnhancedServiceRegistry_Namespaces(t *testing.T) {
+	registry := NewEnhancedServiceRegistry()
+	require.NoError(t, registry.ReserveNamespace("module1", "module1"))
+	require.NoError(t, registry.ReserveNamespace("module1", "module1"), "re-reserving by the owner is a no-op")
+	require.ErrorIs(t, registry.ReserveNamespace("module1", "module2"), ErrServiceNamespaceReserved)
+
+	owner, reserved := registry.NamespaceOwner(NamespacedServiceName("module1", "evaluator"))
+	assert.True(t, reserved)
+	assert.Equal(t, "module1", owner)
+	_, reserved = registry.NamespaceOwner("module1x.evaluator")
+	assert.False(t, reserved, "namespaces match whole name segments")
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule2{})
+	_, err := registry.RegisterService("module1.evaluator", &ServiceRegistryTestImplementation2{})
+	require.ErrorIs(t, err, ErrServiceNamespaceReserved)
+
+	registry.SetCurrentModule(&ServiceRegistryTestModule1{})
+	_, err = registry.RegisterService("module1.evaluator", &ServiceRegistryTestImplementation1{})
+	require.NoError(t, err)
+	registry.ClearCurrentModule()
+
+	// The application itself may register into any namespace
+	_, err = registry.RegisterService("module1.evaluator", "app override", AllowOverride())
+	require.NoError(t, err)
+}
@@ -25,6 +25,10 @@ var (
 	ErrConfigSectionError    = errors.New("failed to load app config: error triggered by section")
 	ErrLoggerNotSet          = errors.New("logger not set in application builder")
 
+	// ErrLifecycleHooksUnsupported is returned when lifecycle hooks are registered with a
+	// base application that cannot run them
+	ErrLifecycleHooksUnsupported = errors.New("application does not support lifecycle hooks")
+
 	// Config validation errors - problems with configuration structure and values
 	ErrConfigNil                  = errors.New("config is nil")
 	ErrConfigNotPointer           = errors.New("config must be a pointer")
@@ -44,10 +48,17 @@ var (
 	ErrConfigSetupError           = errors.New("config setup error")
 	ErrConfigNilPointer           = errors.New("config is nil pointer")
 	ErrFieldCannotBeSet           = errors.New("field cannot be set")
+	ErrConfigFieldNotFound        = errors.New("config field not found")
+	ErrUnknownConfigKeys          = errors.New("unknown configuration keys")
+	ErrConfigReloadFailed         = errors.New("config reload failed")
+	ErrConfigReloadBeforeInit     = errors.New("config cannot be reloaded before the application is initialized")
+	ErrConfigReloadUnsupported    = errors.New("application does not support config reload")
+	ErrConfigSectionType          = errors.New("config section has an unexpected type")
 
 	// Service registry errors
 	ErrServiceAlreadyRegistered = errors.New("service already registered")
 	ErrServiceNotFound          = errors.New("service not found")
+	ErrServiceNamespaceReserved = errors.New("service namespace reserved by another module")
 
 	// Service injection errors
 	ErrTargetNotPointer      = errors.New("target must be a non-nil pointer")
@@ -61,6 +72,7 @@ var (
 	ErrCircularDependency      = errors.New("circular dependency detected")
 	ErrModuleDependencyMissing = errors.New("module depends on non-existent module")
 	ErrRequiredServiceNotFound = errors.New("required service not found for module")
+	ErrShutdownPhaseConflict   = errors.New("shutdown phase contradicts module dependencies")
 
 	// Constructor errors
 	ErrConstructorNotFunction              = errors.New("constructor must be a function")
@@ -81,6 +93,28 @@ var (
 	ErrTenantRegisterNilConfig         = errors.New("cannot register nil config for tenant")
 	ErrMockTenantConfigsNotInitialized = errors.New("mock tenant configs not initialized")
 	ErrConfigSectionNotFoundForTenant  = errors.New("config section not found for tenant")
+	ErrTenantSourceFailed              = errors.New("failed to load tenants from source")
+	ErrTenantSourceNotLoaded           = errors.New("dynamic tenant service has not loaded its tenants")
+	ErrTenantSourceStatus              = errors.New("unexpected tenant source response status")
+
+	// Module swap errors
+	ErrModuleNotFound     = errors.New("module not found")
+	ErrModuleNotSwappable = errors.New("module cannot be swapped")
+	ErrModuleSwapFailed   = errors.New("module swap failed")
+
+	// Lazy initialization errors
+	ErrLazyModuleInitFailed = errors.New("lazy module initialization failed")
+
+	// Startup timeout errors
+	ErrModuleInitTimeout  = errors.New("module init timed out")
+	ErrModuleStartTimeout = errors.New("module start timed out")
+
+	// Worker errors
+	ErrWorkerPanicked     = errors.New("worker panicked")
+	ErrWorkerDrainTimeout = errors.New("timed out waiting for workers to drain")
+
+	// Metrics export errors
+	ErrMetricsExportRejected = errors.New("metrics export rejected")
 
 	// Observer/Event emission errors
 	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
@@ -0,0 +1,379 @@
+package modular
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// OverflowPolicy determines what the event dispatcher does when its queue is full.
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest discards the event being published (default)
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued event to make room
+	OverflowDropOldest
+	// OverflowBlock blocks the publisher until there is room or its context is done
+	OverflowBlock
+)
+
+// String returns a human-readable name for the overflow policy.
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropNewest:
+		return "drop-newest"
+	case OverflowDropOldest:
+		return "drop-oldest"
+	case OverflowBlock:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// EventDispatcherConfig configures buffered observer delivery for an ObservableApplication.
+type EventDispatcherConfig struct {
+	// QueueSize is the number of events buffered before the overflow policy applies. Default 1024.
+	QueueSize int
+
+	// Workers is the number of goroutines delivering events. Use 1 to preserve event order. Default 4.
+	Workers int
+
+	// BatchSize is the maximum number of events a worker delivers at once. Default 64.
+	BatchSize int
+
+	// BatchInterval is how long a worker waits to fill a batch once it has received an event.
+	// Zero delivers whatever is queued immediately.
+	BatchInterval time.Duration
+
+	// Overflow determines what happens when the queue is full.
+	Overflow OverflowPolicy
+
+	// DrainTimeout bounds how long Stop waits for queued events to be delivered. Default 5s.
+	DrainTimeout time.Duration
+}
+
+// normalized fills in defaults for unset fields.
+func (c EventDispatcherConfig) normalized() EventDispatcherConfig {
+	if c.QueueSize <= 0 {
+		c.QueueSize = 1024
+	}
+	if c.Workers <= 0 {
+		c.Workers = 4
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = 64
+	}
+	if c.DrainTimeout <= 0 {
+		c.DrainTimeout = 5 * time.Second
+	}
+	return c
+}
+
+// BatchObserver is an optional interface for observers that can process several
+// events in one call. When an event dispatcher is configured, such observers
+// receive each delivered batch (filtered to their event types) through OnEvents
+// instead of one OnEvent call per event. Each call carries the context the events
+// were published with; a batch of events published with different contexts is
+// split into one call per run of events sharing a context.
+type BatchObserver interface {
+	Observer
+	OnEvents(ctx context.Context, events []cloudevents.Event) error
+}
+
+// ObserverDeliveryStats reports delivery outcomes for a single observer.
+type ObserverDeliveryStats struct {
+	// Delivered is the number of events handed to the observer without error
+	Delivered uint64 `json:"delivered"`
+	// Failed is the number of events for which the observer returned an error or panicked
+	Failed uint64 `json:"failed"`
+	// Dropped is the number of events discarded by the overflow policy before delivery
+	Dropped uint64 `json:"dropped"`
+}
+
+// observerStats holds the live counters behind ObserverDeliveryStats.
+type observerStats struct {
+	delivered atomic.Uint64
+	failed    atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+func (s *observerStats) snapshot() ObserverDeliveryStats {
+	return ObserverDeliveryStats{
+		Delivered: s.delivered.Load(),
+		Failed:    s.failed.Load(),
+		Dropped:   s.dropped.Load(),
+	}
+}
+
+// ObservableOption configures an ObservableApplication at construction.
+type ObservableOption func(*ObservableApplication)
+
+// WithEventDispatcher delivers asynchronous observer notifications through a
+// bounded queue drained by a worker pool, instead of one goroutine per observer
+// per event. Events published with WithSynchronousNotification are still
+// delivered inline.
+//
+// The workers run from Start until Stop. Events published before Start are
+// queued and delivered once it runs; while no worker runs, a full queue drops
+// new events whatever the overflow policy, since blocking would stall Init.
+// Events published after Stop are dropped and counted in ObserverStats.
+func WithEventDispatcher(config EventDispatcherConfig) ObservableOption {
+	return func(app *ObservableApplication) {
+		app.dispatcher = newEventDispatcher(app, config.normalized())
+	}
+}
+
+// queuedEvent is an event waiting for delivery.
+type queuedEvent struct {
+	ctx   context.Context
+	event cloudevents.Event
+}
+
+// eventDispatcher buffers events and delivers them to observers in batches.
+type eventDispatcher struct {
+	app    *ObservableApplication
+	config EventDispatcherConfig
+	queue  chan queuedEvent
+	wg     sync.WaitGroup
+
+	// mu guards started and closed; senders hold a read lock so the queue is
+	// never closed mid-send
+	mu      sync.RWMutex
+	started bool
+	closed  bool
+}
+
+// newEventDispatcher creates a dispatcher whose workers run once start is called.
+func newEventDispatcher(app *ObservableApplication, config EventDispatcherConfig) *eventDispatcher {
+	return &eventDispatcher{
+		app:    app,
+		config: config,
+		queue:  make(chan queuedEvent, config.QueueSize),
+	}
+}
+
+// start launches the workers, which deliver the events queued so far first.
+// It does nothing once the dispatcher was started or closed.
+func (d *eventDispatcher) start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started || d.closed {
+		return
+	}
+	d.started = true
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+}
+
+// enqueue queues an event according to the overflow policy. Events are dropped
+// once the dispatcher is closed, and when the queue is full before it started.
+func (d *eventDispatcher) enqueue(ctx context.Context, event cloudevents.Event) {
+	item := queuedEvent{ctx: ctx, event: event}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		d.app.recordDropped(event)
+		d.app.logger.Warn("Event published after the event dispatcher stopped was dropped", "event", event.Type())
+		return
+	}
+
+	select {
+	case d.queue <- item:
+		return
+	default:
+	}
+
+	if !d.started {
+		// No worker frees room until Start
+		d.app.recordDropped(event)
+		return
+	}
+
+	switch d.config.Overflow {
+	case OverflowBlock:
+		select {
+		case d.queue <- item:
+		case <-ctx.Done():
+			d.app.recordDropped(event)
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case oldest := <-d.queue:
+				d.app.recordDropped(oldest.event)
+			default:
+			}
+			select {
+			case d.queue <- item:
+				return
+			default:
+			}
+		}
+	default:
+		d.app.recordDropped(event)
+	}
+}
+
+// work delivers batches until the queue is closed and drained.
+func (d *eventDispatcher) work() {
+	defer d.wg.Done()
+
+	batch := make([]queuedEvent, 0, d.config.BatchSize)
+	for first := range d.queue {
+		batch = append(batch[:0], first)
+		batch = d.fill(batch)
+		d.app.deliver(batch)
+	}
+}
+
+// fill adds queued events to batch until it is full, the queue is empty (or
+// BatchInterval elapses when set), or the queue is closed.
+func (d *eventDispatcher) fill(batch []queuedEvent) []queuedEvent {
+	if d.config.BatchInterval <= 0 {
+		for len(batch) < d.config.BatchSize {
+			select {
+			case item, ok := <-d.queue:
+				if !ok {
+					return batch
+				}
+				batch = append(batch, item)
+			default:
+				return batch
+			}
+		}
+		return batch
+	}
+
+	timer := time.NewTimer(d.config.BatchInterval)
+	defer timer.Stop()
+	for len(batch) < d.config.BatchSize {
+		select {
+		case item, ok := <-d.queue:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, item)
+		case <-timer.C:
+			return batch
+		}
+	}
+	return batch
+}
+
+// close stops accepting queued events and waits for workers to drain the queue.
+// It reports false if the drain timeout expired first.
+func (d *eventDispatcher) close() bool {
+	d.mu.Lock()
+	if d.closed {
+		d.mu.Unlock()
+		return true
+	}
+	d.closed = true
+	close(d.queue)
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-time.After(d.config.DrainTimeout):
+		return false
+	}
+}
+
+// deliver hands a batch of events to every interested observer.
+func (app *ObservableApplication) deliver(batch []queuedEvent) {
+	app.observerMutex.RLock()
+	registrations := make([]*observerRegistration, 0, len(app.observers))
+	for _, registration := range app.observers {
+		registrations = append(registrations, registration)
+	}
+	app.observerMutex.RUnlock()
+
+	for _, registration := range registrations {
+		interested := make([]queuedEvent, 0, len(batch))
+		for _, item := range batch {
+			if registration.wants(item.event.Type()) {
+				interested = append(interested, item)
+			}
+		}
+		if len(interested) == 0 {
+			continue
+		}
+
+		if batchObserver, ok := registration.observer.(BatchObserver); ok && len(interested) > 1 {
+			// One call per run of events published with the same context
+			for start := 0; start < len(interested); {
+				ctx := interested[start].ctx
+				events := []cloudevents.Event{interested[start].event}
+				end := start + 1
+				for ; end < len(interested) && interested[end].ctx == ctx; end++ {
+					events = append(events, interested[end].event)
+				}
+				app.notifyBatch(registration, batchObserver, ctx, events)
+				start = end
+			}
+			continue
+		}
+
+		for _, item := range interested {
+			app.notifyObserver(registration, item.ctx, item.event)
+		}
+	}
+}
+
+// notifyBatch delivers several events to a BatchObserver, recovering from panics.
+func (app *ObservableApplication) notifyBatch(registration *observerRegistration, observer BatchObserver, ctx context.Context, events []cloudevents.Event) {
+	count := uint64(len(events))
+	defer func() {
+		if r := recover(); r != nil {
+			registration.stats.failed.Add(count)
+			app.logger.Error("Observer panicked", "observerID", observer.ObserverID(), "events", len(events), "panic", r)
+		}
+	}()
+
+	if err := observer.OnEvents(ctx, events); err != nil {
+		registration.stats.failed.Add(count)
+		app.logger.Error("Observer error", "observerID", observer.ObserverID(), "events", len(events), "error", err)
+		return
+	}
+	registration.stats.delivered.Add(count)
+}
+
+// recordDropped counts a dropped event against every observer that would have received it.
+func (app *ObservableApplication) recordDropped(event cloudevents.Event) {
+	app.observerMutex.RLock()
+	defer app.observerMutex.RUnlock()
+
+	for _, registration := range app.observers {
+		if registration.wants(event.Type()) {
+			registration.stats.dropped.Add(1)
+		}
+	}
+	app.logger.Debug("Event dropped by dispatcher", "event", event.Type())
+}
+
+// ObserverStats returns delivery statistics for each registered observer, keyed by observer ID.
+func (app *ObservableApplication) ObserverStats() map[string]ObserverDeliveryStats {
+	app.observerMutex.RLock()
+	defer app.observerMutex.RUnlock()
+
+	stats := make(map[string]ObserverDeliveryStats, len(app.observers))
+	for id, registration := range app.observers {
+		stats[id] = registration.stats.snapshot()
+	}
+	return stats
+}
@@ -0,0 +1,241 @@
+package modular
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRecordingObserver records events and the size of each batch it receives
+type batchRecordingObserver struct {
+	id      string
+	mu      sync.Mutex
+	events  []string
+	batches []int
+}
+
+func (o *batchRecordingObserver) ObserverID() string { return o.id }
+
+func (o *batchRecordingObserver) OnEvent(ctx context.Context, event cloudevents.Event) error {
+	return o.OnEvents(ctx, []cloudevents.Event{event})
+}
+
+func (o *batchRecordingObserver) OnEvents(_ context.Context, events []cloudevents.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, event := range events {
+		o.events = append(o.events, event.Type())
+	}
+	o.batches = append(o.batches, len(events))
+	return nil
+}
+
+func (o *batchRecordingObserver) received() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.events)
+}
+
+func newDispatcherTestEvent(eventType string) cloudevents.Event {
+	return NewCloudEvent(eventType, "test", nil, nil)
+}
+
+func TestEventDispatcher_BatchesInOrder(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 1, BatchSize: 10, BatchInterval: 20 * time.Millisecond}))
+
+	observer := &batchRecordingObserver{id: "batch"}
+	require.NoError(t, app.RegisterObserver(observer))
+	app.dispatcher.start()
+
+	types := []string{"test.a", "test.b", "test.c", "test.d", "test.e"}
+	for _, eventType := range types {
+		require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent(eventType)))
+	}
+
+	assert.Eventually(t, func() bool { return observer.received() == len(types) }, time.Second, 5*time.Millisecond)
+	assert.True(t, app.dispatcher.close())
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	assert.Equal(t, types, observer.events, "a single worker should preserve order")
+	assert.Less(t, len(observer.batches), len(types), "events should be delivered in batches")
+	assert.Equal(t, uint64(len(types)), app.ObserverStats()["batch"].Delivered)
+}
+
+func TestEventDispatcher_OverflowDropNewest(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 1, QueueSize: 2, BatchSize: 1}))
+
+	release := make(chan struct{})
+	var delivered atomic.Int32
+	blocking := NewFunctionalObserver("blocking", func(ctx context.Context, event cloudevents.Event) error {
+		<-release
+		delivered.Add(1)
+		return nil
+	})
+	filtered := NewFunctionalObserver("filtered", func(ctx context.Context, event cloudevents.Event) error { return nil })
+	require.NoError(t, app.RegisterObserver(blocking))
+	require.NoError(t, app.RegisterObserver(filtered, "test.other"))
+	app.dispatcher.start()
+
+	// The first event occupies the worker, the next two fill the queue, the rest are dropped
+	require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.event")))
+	assert.Eventually(t, func() bool { return len(app.dispatcher.queue) == 0 }, time.Second, time.Millisecond)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.event")))
+	}
+
+	stats := app.ObserverStats()
+	assert.Equal(t, uint64(3), stats["blocking"].Dropped)
+	assert.Equal(t, uint64(0), stats["filtered"].Dropped, "observers not interested in the event are not charged")
+
+	close(release)
+	assert.True(t, app.dispatcher.close())
+	assert.Equal(t, int32(3), delivered.Load())
+	assert.Equal(t, uint64(3), app.ObserverStats()["blocking"].Delivered)
+}
+
+func TestEventDispatcher_OverflowDropOldest(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 1, QueueSize: 1, BatchSize: 1, Overflow: OverflowDropOldest}))
+
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var received []string
+	observer := NewFunctionalObserver("recorder", func(ctx context.Context, event cloudevents.Event) error {
+		<-release
+		mu.Lock()
+		received = append(received, event.Type())
+		mu.Unlock()
+		return nil
+	})
+	require.NoError(t, app.RegisterObserver(observer))
+	app.dispatcher.start()
+
+	require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.first")))
+	assert.Eventually(t, func() bool { return len(app.dispatcher.queue) == 0 }, time.Second, time.Millisecond)
+	require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.old")))
+	require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.new")))
+
+	close(release)
+	assert.True(t, app.dispatcher.close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"test.first", "test.new"}, received)
+	assert.Equal(t, uint64(1), app.ObserverStats()["recorder"].Dropped)
+}
+
+func TestEventDispatcher_FailuresAndPanicsAreCounted(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 2}))
+
+	var calls atomic.Int32
+	observer := NewFunctionalObserver("flaky", func(ctx context.Context, event cloudevents.Event) error {
+		switch calls.Add(1) {
+		case 1:
+			return errObserver
+		case 2:
+			panic("observer panic")
+		}
+		return nil
+	})
+	require.NoError(t, app.RegisterObserver(observer))
+	app.dispatcher.start()
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.event")))
+	}
+	assert.True(t, app.dispatcher.close())
+
+	stats := app.ObserverStats()["flaky"]
+	assert.Equal(t, uint64(2), stats.Failed)
+	assert.Equal(t, uint64(1), stats.Delivered)
+}
+
+func TestEventDispatcher_WorkersRunFromStartUntilStop(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{QueueSize: 2, Overflow: OverflowBlock}))
+
+	var mu sync.Mutex
+	var received []string
+	observer := NewFunctionalObserver("lifecycle", func(ctx context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Type())
+		return nil
+	})
+	require.NoError(t, app.RegisterObserver(observer, "test.early", "test.late"))
+
+	// Before Start events wait in the queue, and a full queue drops instead of blocking
+	for i := 0; i < 3; i++ {
+		require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.early")))
+	}
+	mu.Lock()
+	assert.Empty(t, received)
+	mu.Unlock()
+	assert.Equal(t, uint64(1), app.ObserverStats()["lifecycle"].Dropped)
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	// After Stop the dispatcher is closed and events are dropped
+	require.NoError(t, app.NotifyObservers(context.Background(), newDispatcherTestEvent("test.late")))
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"test.early", "test.early"}, received)
+	assert.Equal(t, uint64(2), app.ObserverStats()["lifecycle"].Dropped)
+}
+
+type dispatcherContextKey string
+
+func TestEventDispatcher_BatchesKeepEachEventsContext(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 1, BatchSize: 10}))
+	observer := &contextRecordingObserver{id: "contexts"}
+	require.NoError(t, app.RegisterObserver(observer))
+
+	first := context.WithValue(context.Background(), dispatcherContextKey("request"), "first")
+	second := context.WithValue(context.Background(), dispatcherContextKey("request"), "second")
+	for _, ctx := range []context.Context{first, first, second} {
+		require.NoError(t, app.NotifyObservers(ctx, newDispatcherTestEvent("test.event")))
+	}
+	app.dispatcher.start()
+	assert.True(t, app.dispatcher.close())
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	assert.Equal(t, []string{"first", "second"}, observer.requests)
+	assert.Equal(t, []int{2, 1}, observer.batches)
+}
+
+// contextRecordingObserver records the request value of the context of each batch
+type contextRecordingObserver struct {
+	id       string
+	mu       sync.Mutex
+	requests []string
+	batches  []int
+}
+
+func (o *contextRecordingObserver) ObserverID() string { return o.id }
+
+func (o *contextRecordingObserver) OnEvent(ctx context.Context, event cloudevents.Event) error {
+	return o.OnEvents(ctx, []cloudevents.Event{event})
+}
+
+func (o *contextRecordingObserver) OnEvents(ctx context.Context, events []cloudevents.Event) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	request, _ := ctx.Value(dispatcherContextKey("request")).(string)
+	o.requests = append(o.requests, request)
+	o.batches = append(o.batches, len(events))
+	return nil
+}
@@ -127,18 +127,19 @@ func main() {
 		},
 	}
 
-	// Initialize application
-	mainConfigProvider := modular.NewStdConfigProvider(appConfig)
-	app := modular.NewStdApplication(mainConfigProvider, &testLogger{})
-
-	// Register configurations
-	app.RegisterConfigSection("eventbus", modular.NewStdConfigProvider(eventbusConfig))
-
-	// Register modules
-	app.RegisterModule(eventbus.NewModule())
+	// Create the application with the eventbus configuration set in code
+	app, err := modular.NewApplication(
+		modular.WithLogger(&testLogger{}),
+		modular.WithConfigProvider(modular.NewStdConfigProvider(appConfig)),
+		modular.WithModules(eventbus.NewModule()),
+		modular.WithConfigSection("eventbus", modular.NewStdConfigProvider(eventbusConfig)),
+	)
+	if err != nil {
+		log.Fatal("Failed to create application:", err)
+	}
 
 	// Initialize application
-	err := app.Init()
+	err = app.Init()
 	if err != nil {
 		log.Fatal("Failed to initialize application:", err)
 	}
@@ -134,8 +134,6 @@ func (et *EventTracker) Validate() bool {
 }
 
 func main() {
-	ctx := context.Background()
-
 	// Create application configuration
 	appConfig := &AppConfig{
 		Name:        "NATS EventBus Demo",
@@ -169,28 +167,33 @@ func main() {
 		},
 	}
 
-	// Initialize application
-	mainConfigProvider := modular.NewStdConfigProvider(appConfig)
-	app := modular.NewStdApplication(mainConfigProvider, &testLogger{})
-
-	// Register configurations
-	app.RegisterConfigSection("eventbus", modular.NewStdConfigProvider(eventbusConfig))
-
-	// Register modules
-	app.RegisterModule(eventbus.NewModule())
+	// Create the application with the eventbus configuration set in code. The publisher
+	// stops in the ingress phase, so no events are published once shutdown begins; the
+	// subscriber stops in the consumers phase, after the publisher and before the event
+	// bus, and drains what is in flight.
+	tracker := &EventTracker{}
+	app, err := modular.NewApplication(
+		modular.WithLogger(&testLogger{}),
+		modular.WithConfigProvider(modular.NewStdConfigProvider(appConfig)),
+		modular.WithModules(
+			eventbus.NewModule(),
+			&publisherModule{tracker: tracker},
+			&subscriberModule{tracker: tracker},
+		),
+		modular.WithConfigSection("eventbus", modular.NewStdConfigProvider(eventbusConfig)),
+	)
+	if err != nil {
+		log.Fatal("Failed to create application:", err)
+	}
 
 	// Initialize application
-	err := app.Init()
+	err = app.Init()
 	if err != nil {
 		log.Fatal("Failed to initialize application:", err)
 	}
 
-	// Get services
-	var eventBusService *eventbus.EventBusModule
-	err = app.GetService("eventbus.provider", &eventBusService)
-	if err != nil {
-		log.Fatal("Failed to get eventbus service:", err)
-	}
+	// Check if NATS service is available
+	checkNATSAvailability()
 
 	// Start application
 	err = app.Start()
@@ -204,58 +207,18 @@ func main() {
 	fmt.Println("  - All topics routed through NATS")
 	fmt.Println()
 
-	// Check if NATS service is available
-	checkNATSAvailability()
-
-	// Give the eventbus a moment to fully initialize connections
-	time.Sleep(500 * time.Millisecond)
-
-	// Create event tracker for validation
-	tracker := &EventTracker{}
-
-	// Set up a wait group for services
-	var wg sync.WaitGroup
-
 	// Set up signal handling for graceful shutdown
 	signalChan := make(chan os.Signal, 1)
 	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
 
-	// Create a stop channel that will be closed to signal all goroutines to stop
-	stopChan := make(chan struct{})
-
-	// Start Publisher Service (Service 1)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		runPublisherService(ctx, eventBusService, stopChan, tracker)
-	}()
-
-	// Start Subscriber Services (Service 2)
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		runSubscriberService(ctx, eventBusService, stopChan, tracker)
-	}()
-
-	// Wait for signal or service completion
 	fmt.Println("🔄 Services are running. Press Ctrl+C to stop...")
 	fmt.Println()
 
 	// Wait for shutdown signal
 	<-signalChan
 
-	// Graceful shutdown - close the stop channel to broadcast to all goroutines
+	// Stop the application: publisher first, then the subscriber drains, then the event bus
 	fmt.Println("\n🛑 Shutting down services...")
-	close(stopChan)
-
-	// Wait for services to complete (they will stop when they receive the signal)
-	wg.Wait()
-
-	// Wait a moment for async processing to complete
-	fmt.Println("⏳ Waiting for event processing to complete...")
-	time.Sleep(2 * time.Second)
-
-	// Stop application after services have stopped
 	err = app.Stop()
 	if err != nil {
 		log.Printf("Warning during shutdown: %v", err)
@@ -279,7 +242,46 @@ func main() {
 	fmt.Println("✅ Application shutdown complete")
 }
 
-// runPublisherService simulates a service that publishes events
+// publisherModule simulates a service that publishes events. It is the source of
+// events, so it stops in the ingress phase before anything consumes them.
+type publisherModule struct {
+	eventBus *eventbus.EventBusModule
+	tracker  *EventTracker
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+func (m *publisherModule) Name() string           { return "publisher" }
+func (m *publisherModule) Dependencies() []string { return []string{eventbus.ModuleName} }
+func (m *publisherModule) ShutdownPhase() modular.ShutdownPhase {
+	return modular.ShutdownPhaseIngress
+}
+
+func (m *publisherModule) Init(app modular.Application) error {
+	return app.GetService("eventbus.provider", &m.eventBus)
+}
+
+func (m *publisherModule) Start(ctx context.Context) error {
+	m.stopChan = make(chan struct{})
+	m.done = make(chan struct{})
+	go func() {
+		defer close(m.done)
+		runPublisherService(context.WithoutCancel(ctx), m.eventBus, m.stopChan, m.tracker)
+	}()
+	return nil
+}
+
+func (m *publisherModule) Stop(ctx context.Context) error {
+	close(m.stopChan)
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// runPublisherService publishes events until stopChan is closed
 func runPublisherService(ctx context.Context, eventBus *eventbus.EventBusModule, stopChan <-chan struct{}, tracker *EventTracker) {
 	fmt.Println("📤 Publisher Service started")
 
@@ -362,8 +364,51 @@ func runPublisherService(ctx context.Context, eventBus *eventbus.EventBusModule,
 	}
 }
 
-// runSubscriberService simulates a service that subscribes to events
-func runSubscriberService(ctx context.Context, eventBus *eventbus.EventBusModule, stopChan <-chan struct{}, tracker *EventTracker) {
+// subscriberModule simulates a service that subscribes to events. It stops in the
+// consumers phase: after the publisher, and before the event bus it depends on.
+type subscriberModule struct {
+	eventBus      *eventbus.EventBusModule
+	tracker       *EventTracker
+	subscriptions []eventbus.Subscription
+}
+
+func (m *subscriberModule) Name() string           { return "subscriber" }
+func (m *subscriberModule) Dependencies() []string { return []string{eventbus.ModuleName} }
+func (m *subscriberModule) ShutdownPhase() modular.ShutdownPhase {
+	return modular.ShutdownPhaseConsumers
+}
+
+func (m *subscriberModule) Init(app modular.Application) error {
+	return app.GetService("eventbus.provider", &m.eventBus)
+}
+
+func (m *subscriberModule) Start(ctx context.Context) error {
+	subscriptions, err := subscribe(context.WithoutCancel(ctx), m.eventBus, m.tracker)
+	m.subscriptions = subscriptions
+	return err
+}
+
+// Stop waits until every published event was consumed, then cancels the subscriptions.
+func (m *subscriberModule) Stop(ctx context.Context) error {
+	fmt.Println("📨 Subscriber Service draining...")
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for !m.tracker.Validate() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	for _, sub := range m.subscriptions {
+		_ = sub.Cancel()
+	}
+	fmt.Println("📨 Subscriber Service stopped")
+	return nil
+}
+
+// subscribe subscribes to the order, analytics and notification events
+func subscribe(ctx context.Context, eventBus *eventbus.EventBusModule, tracker *EventTracker) ([]eventbus.Subscription, error) {
 	fmt.Println("📨 Subscriber Service started")
 
 	// Subscribe to order events
@@ -378,10 +423,8 @@ func runSubscriberService(ctx context.Context, eventBus *eventbus.EventBusModule
 		return nil
 	})
 	if err != nil {
-		fmt.Printf("Error subscribing to order events: %v\n", err)
-		return
+		return nil, fmt.Errorf("subscribing to order events: %w", err)
 	}
-	defer orderSub.Cancel()
 
 	// Subscribe to analytics events asynchronously
 	analyticsSub, err := eventBus.SubscribeAsync(ctx, "analytics.*", func(ctx context.Context, event eventbus.Event) error {
@@ -397,10 +440,8 @@ func runSubscriberService(ctx context.Context, eventBus *eventbus.EventBusModule
 		return nil
 	})
 	if err != nil {
-		fmt.Printf("Error subscribing to analytics events: %v\n", err)
-		return
+		return []eventbus.Subscription{orderSub}, fmt.Errorf("subscribing to analytics events: %w", err)
 	}
-	defer analyticsSub.Cancel()
 
 	// Subscribe to notification events
 	notifSub, err := eventBus.Subscribe(ctx, "notification.*", func(ctx context.Context, event eventbus.Event) error {
@@ -414,17 +455,12 @@ func runSubscriberService(ctx context.Context, eventBus *eventbus.EventBusModule
 		return nil
 	})
 	if err != nil {
-		fmt.Printf("Error subscribing to notification events: %v\n", err)
-		return
+		return []eventbus.Subscription{orderSub, analyticsSub}, fmt.Errorf("subscribing to notification events: %w", err)
 	}
-	defer notifSub.Cancel()
 
 	fmt.Println("✅ All subscriptions active")
 	fmt.Println()
-
-	// Wait for stop signal
-	<-stopChan
-	fmt.Println("📨 Subscriber Service stopping...")
+	return []eventbus.Subscription{orderSub, analyticsSub, notifSub}, nil
 }
 
 func checkNATSAvailability() {
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"time"
 
 	"github.com/golobby/cast"
 )
@@ -237,13 +236,13 @@ func (f *AffixedEnvFeeder) setFieldFromEnv(field reflect.Value, fieldType *refle
 
 // setFieldValue converts and sets a field value
 func setFieldValue(field reflect.Value, strValue string) error {
-	// Special handling for time.Duration
-	if field.Type() == reflect.TypeOf(time.Duration(0)) {
-		duration, err := time.ParseDuration(strValue)
+	// Special handling for time.Duration and slices and maps of it, which cast can't convert
+	if isDurationType(field.Type()) {
+		duration, err := parseDurationString(strValue, field.Type())
 		if err != nil {
-			return fmt.Errorf("cannot convert value to type %v: %w", field.Type(), err)
+			return err
 		}
-		field.Set(reflect.ValueOf(duration))
+		field.Set(duration)
 		return nil
 	}
 
@@ -0,0 +1,66 @@
+package feeders
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// durationType is the type of time.Duration fields, which feeders parse from strings
+// like "30s" or "1h30m" rather than casting them to integers.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// isDurationType reports whether t is time.Duration, or a pointer, slice, array or map
+// with time.Duration elements at any depth.
+func isDurationType(t reflect.Type) bool {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t == durationType
+		}
+	}
+}
+
+// parseDurationString converts a string from an environment variable to t, which is
+// time.Duration, []time.Duration from a comma separated list such as "1s, 5s, 30s", or a
+// map of time.Duration from comma separated key=duration pairs such as "read=5s,write=10s".
+func parseDurationString(value string, t reflect.Type) (reflect.Value, error) {
+	switch {
+	case t == durationType:
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("cannot convert value to type %v: %w", t, err)
+		}
+		return reflect.ValueOf(duration), nil
+	case t.Kind() == reflect.Slice && t.Elem() == durationType:
+		slice := reflect.MakeSlice(t, 0, strings.Count(value, ",")+1)
+		for _, item := range strings.Split(value, ",") {
+			duration, err := parseDurationString(item, durationType)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			slice = reflect.Append(slice, duration)
+		}
+		return slice, nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem() == durationType:
+		result := reflect.MakeMap(t)
+		for _, entry := range strings.Split(value, ",") {
+			key, item, found := strings.Cut(entry, "=")
+			key = strings.TrimSpace(key)
+			if !found || key == "" {
+				return reflect.Value{}, fmt.Errorf("%w: %q", ErrDurationMapEntry, entry)
+			}
+			duration, err := parseDurationString(item, durationType)
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("map entry %q: %w", key, err)
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(t.Key()), duration)
+		}
+		return result, nil
+	default:
+		return reflect.Value{}, fmt.Errorf("%w: %v", ErrDurationUnsupportedType, t)
+	}
+}
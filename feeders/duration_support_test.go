@@ -2,6 +2,7 @@ package feeders
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -288,3 +289,63 @@ type testLogger struct {
 func (l *testLogger) Debug(msg string, args ...any) {
 	l.messages = append(l.messages, msg)
 }
+
+// DurationCollectionsConfig has slices and maps of time.Duration
+type DurationCollectionsConfig struct {
+	RetryBackoff []time.Duration            `env:"RETRY_BACKOFF" yaml:"retry_backoff"`
+	Timeouts     map[string]time.Duration   `env:"TIMEOUTS" yaml:"timeouts"`
+	Optional     map[string]*time.Duration  `yaml:"optional"`
+	Schedules    map[string][]time.Duration `yaml:"schedules"`
+	Nested       []struct {
+		Delays []time.Duration `yaml:"delays"`
+	} `yaml:"nested"`
+}
+
+func TestEnvFeeder_TimeDurationCollections(t *testing.T) {
+	t.Setenv("RETRY_BACKOFF", "100ms, 1s,30s")
+	t.Setenv("TIMEOUTS", "read=5s, write=1h30m")
+
+	config := &DurationCollectionsConfig{}
+	require.NoError(t, NewEnvFeeder().Feed(config))
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, time.Second, 30 * time.Second}, config.RetryBackoff)
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": 90 * time.Minute}, config.Timeouts)
+
+	t.Setenv("TIMEOUTS", "read")
+	require.ErrorIs(t, NewEnvFeeder().Feed(&DurationCollectionsConfig{}), ErrDurationMapEntry)
+
+	t.Setenv("TIMEOUTS", "")
+	t.Setenv("RETRY_BACKOFF", "1s,soon")
+	err := NewEnvFeeder().Feed(&DurationCollectionsConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"soon"`)
+}
+
+func TestYamlFeeder_TimeDurationCollections(t *testing.T) {
+	yamlFile := filepath.Join(t.TempDir(), "durations.yaml")
+	require.NoError(t, os.WriteFile(yamlFile, []byte(`retry_backoff: [100ms, 1s]
+timeouts:
+  read: 5s
+  write: 1h30m
+optional:
+  idle: 2m
+schedules:
+  nightly: [1h, 2h]
+nested:
+  - delays: [10s]
+`), 0600))
+
+	config := &DurationCollectionsConfig{}
+	require.NoError(t, NewYamlFeeder(yamlFile).Feed(config))
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, time.Second}, config.RetryBackoff)
+	assert.Equal(t, map[string]time.Duration{"read": 5 * time.Second, "write": 90 * time.Minute}, config.Timeouts)
+	require.NotNil(t, config.Optional["idle"])
+	assert.Equal(t, 2*time.Minute, *config.Optional["idle"])
+	assert.Equal(t, map[string][]time.Duration{"nightly": {time.Hour, 2 * time.Hour}}, config.Schedules)
+	require.Len(t, config.Nested, 1)
+	assert.Equal(t, []time.Duration{10 * time.Second}, config.Nested[0].Delays)
+
+	require.NoError(t, os.WriteFile(yamlFile, []byte("timeouts:\n  read: soon\n"), 0600))
+	err := NewYamlFeeder(yamlFile).Feed(&DurationCollectionsConfig{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "map entry 'read'")
+}
@@ -0,0 +1,208 @@
+package feeders
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigKeyEnvVar names the environment variable holding the base64-encoded
+// 256-bit key YAML feeders decrypt AES256_GCM values with when no Decrypter is set.
+const ConfigKeyEnvVar = "MODULAR_CONFIG_KEY"
+
+const (
+	aesGCMValuePrefix = "ENC[AES256_GCM,"
+	ageArmorHeader    = "-----BEGIN AGE ENCRYPTED FILE-----"
+)
+
+// Decrypter decrypts encrypted configuration values at feed time, so configuration
+// files containing credentials can be committed safely.
+type Decrypter interface {
+	// CanDecrypt reports whether value is encrypted in a format Decrypt understands
+	CanDecrypt(value string) bool
+	// Decrypt returns the plaintext of value
+	Decrypt(value string) (string, error)
+}
+
+// IsEncryptedValue reports whether value is an encrypted configuration value: an
+// ENC[...] value or an armored age blob.
+func IsEncryptedValue(value string) bool {
+	value = strings.TrimSpace(value)
+	return strings.HasPrefix(value, "ENC[") && strings.HasSuffix(value, "]") ||
+		strings.HasPrefix(value, ageArmorHeader)
+}
+
+// AESGCMDecrypter decrypts SOPS-style values of the form
+//
+//	ENC[AES256_GCM,data:<base64>,iv:<base64>,tag:<base64>,type:<str|int|float|bool>]
+//
+// with a 256-bit key. EncryptValue produces such values.
+type AESGCMDecrypter struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCMDecrypter creates a decrypter for a 32-byte key, such as a data key
+// fetched from a KMS.
+func NewAESGCMDecrypter(key []byte) (*AESGCMDecrypter, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCMDecrypter{aead: aead}, nil
+}
+
+// NewAESGCMDecrypterFromEnv creates a decrypter for the base64-encoded key in the
+// named environment variable.
+func NewAESGCMDecrypterFromEnv(envVar string) (*AESGCMDecrypter, error) {
+	encoded := os.Getenv(envVar)
+	if encoded == "" {
+		return nil, fmt.Errorf("%w: %s is not set", ErrDecryptionKeyMissing, envVar)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is not valid base64: %w", ErrInvalidDecryptionKey, envVar, err)
+	}
+	return NewAESGCMDecrypter(key)
+}
+
+// CanDecrypt reports whether value is an ENC[AES256_GCM,...] value.
+func (d *AESGCMDecrypter) CanDecrypt(value string) bool {
+	return strings.HasPrefix(strings.TrimSpace(value), aesGCMValuePrefix)
+}
+
+// Decrypt returns the plaintext of an ENC[AES256_GCM,...] value.
+func (d *AESGCMDecrypter) Decrypt(value string) (string, error) {
+	fields, err := parseEncryptedValue(value)
+	if err != nil {
+		return "", err
+	}
+	data, dataErr := base64.StdEncoding.DecodeString(fields["data"])
+	iv, ivErr := base64.StdEncoding.DecodeString(fields["iv"])
+	tag, tagErr := base64.StdEncoding.DecodeString(fields["tag"])
+	if dataErr != nil || ivErr != nil || tagErr != nil {
+		return "", fmt.Errorf("%w: data, iv and tag must be base64", ErrInvalidEncryptedValue)
+	}
+	if len(iv) != d.aead.NonceSize() || len(tag) != d.aead.Overhead() {
+		return "", fmt.Errorf("%w: unexpected iv or tag length", ErrInvalidEncryptedValue)
+	}
+	plaintext, err := d.aead.Open(nil, iv, append(data, tag...), nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrDecryptionFailed, err)
+	}
+	return string(plaintext), nil
+}
+
+// EncryptValue encrypts plaintext as an ENC[AES256_GCM,...] string value that
+// AESGCMDecrypter decrypts with the same key.
+func EncryptValue(key []byte, plaintext string) (string, error) {
+	aead, err := newAESGCM(key)
+	if err != nil {
+		return "", err
+	}
+	iv := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", fmt.Errorf("failed to generate iv: %w", err)
+	}
+	sealed := aead.Seal(nil, iv, []byte(plaintext), nil)
+	data, tag := sealed[:len(sealed)-aead.Overhead()], sealed[len(sealed)-aead.Overhead():]
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]",
+		base64.StdEncoding.EncodeToString(data),
+		base64.StdEncoding.EncodeToString(iv),
+		base64.StdEncoding.EncodeToString(tag)), nil
+}
+
+func newAESGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%w: AES256_GCM needs a 32-byte key, got %d bytes", ErrInvalidDecryptionKey, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDecryptionKey, err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDecryptionKey, err)
+	}
+	return aead, nil
+}
+
+// parseEncryptedValue splits ENC[AES256_GCM,key:value,...] into its fields.
+func parseEncryptedValue(value string) (map[string]string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, aesGCMValuePrefix) || !strings.HasSuffix(value, "]") {
+		return nil, fmt.Errorf("%w: expected ENC[AES256_GCM,...]", ErrInvalidEncryptedValue)
+	}
+	fields := make(map[string]string)
+	for _, part := range strings.Split(value[len(aesGCMValuePrefix):len(value)-1], ",") {
+		name, fieldValue, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed field %q", ErrInvalidEncryptedValue, part)
+		}
+		fields[name] = fieldValue
+	}
+	for _, required := range []string{"data", "iv", "tag"} {
+		if _, ok := fields[required]; !ok {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidEncryptedValue, required)
+		}
+	}
+	return fields, nil
+}
+
+// encryptedValueType returns the type: field of an ENC[...] value, "str" by default.
+func encryptedValueType(value string) string {
+	fields, err := parseEncryptedValue(value)
+	if err != nil || fields["type"] == "" {
+		return "str"
+	}
+	return fields["type"]
+}
+
+// decryptYAMLNode decrypts the encrypted scalars in node in place. Without a
+// decrypter, the key in ConfigKeyEnvVar is used if set; encrypted values that can't
+// be decrypted are errors rather than being fed as ciphertext.
+func decryptYAMLNode(node *yaml.Node, decrypter Decrypter) error {
+	if node.Kind == yaml.ScalarNode {
+		if !IsEncryptedValue(node.Value) {
+			return nil
+		}
+		if decrypter == nil {
+			envDecrypter, err := NewAESGCMDecrypterFromEnv(ConfigKeyEnvVar)
+			if err != nil {
+				return fmt.Errorf("line %d: cannot decrypt value: %w", node.Line, err)
+			}
+			decrypter = envDecrypter
+		}
+		if !decrypter.CanDecrypt(node.Value) {
+			return fmt.Errorf("line %d: %w", node.Line, ErrUnsupportedEncryptedValue)
+		}
+		plaintext, err := decrypter.Decrypt(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		// Typed values are resolved from their plaintext like any other scalar
+		valueType := "str"
+		if strings.HasPrefix(strings.TrimSpace(node.Value), "ENC[") {
+			valueType = encryptedValueType(node.Value)
+		}
+		node.Value = plaintext
+		node.Style = 0
+		if valueType == "str" {
+			node.Tag = "!!str"
+		} else {
+			node.Tag = ""
+		}
+		return nil
+	}
+	for _, child := range node.Content {
+		if err := decryptYAMLNode(child, decrypter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package feeders
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encryptedTestConfig struct {
+	Host     string `yaml:"host"`
+	Password string `yaml:"password"`
+	Port     int    `yaml:"port"`
+	Database struct {
+		DSN string `yaml:"dsn"`
+	} `yaml:"database"`
+}
+
+var testEncryptionKey = bytes.Repeat([]byte{7}, 32)
+
+func writeEncryptedYAML(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func encrypt(t *testing.T, plaintext string) string {
+	t.Helper()
+	value, err := EncryptValue(testEncryptionKey, plaintext)
+	require.NoError(t, err)
+	return value
+}
+
+func TestAESGCMDecrypter_RoundTrip(t *testing.T) {
+	value := encrypt(t, "s3cret, with: punctuation")
+	assert.True(t, strings.HasPrefix(value, "ENC[AES256_GCM,data:"))
+	assert.True(t, IsEncryptedValue(value))
+
+	decrypter, err := NewAESGCMDecrypter(testEncryptionKey)
+	require.NoError(t, err)
+	assert.True(t, decrypter.CanDecrypt(value))
+	plaintext, err := decrypter.Decrypt(value)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret, with: punctuation", plaintext)
+
+	other, err := NewAESGCMDecrypter(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	_, err = other.Decrypt(value)
+	assert.ErrorIs(t, err, ErrDecryptionFailed, "the wrong key fails authentication")
+
+	_, err = decrypter.Decrypt("ENC[AES256_GCM,data:abc,type:str]")
+	assert.ErrorIs(t, err, ErrInvalidEncryptedValue)
+	_, err = NewAESGCMDecrypter([]byte("short"))
+	assert.ErrorIs(t, err, ErrInvalidDecryptionKey)
+}
+
+func TestYamlFeeder_DecryptsValues(t *testing.T) {
+	decrypter, err := NewAESGCMDecrypter(testEncryptionKey)
+	require.NoError(t, err)
+	port := strings.Replace(encrypt(t, "5432"), "type:str", "type:int", 1)
+	path := writeEncryptedYAML(t, "host: db.internal\n"+
+		"password: "+encrypt(t, "hunter2")+"\n"+
+		"port: "+port+"\n"+
+		"database:\n  dsn: \""+encrypt(t, "postgres://user:pw@db/app")+"\"\n")
+
+	var cfg encryptedTestConfig
+	require.NoError(t, NewYamlFeeder(path).WithDecrypter(decrypter).Feed(&cfg))
+	assert.Equal(t, "db.internal", cfg.Host)
+	assert.Equal(t, "hunter2", cfg.Password)
+	assert.Equal(t, 5432, cfg.Port, "typed values are converted after decryption")
+	assert.Equal(t, "postgres://user:pw@db/app", cfg.Database.DSN)
+
+	var section struct {
+		DSN string `yaml:"dsn"`
+	}
+	require.NoError(t, NewYamlFeeder(path).WithDecrypter(decrypter).FeedKey("database", &section))
+	assert.Equal(t, "postgres://user:pw@db/app", section.DSN)
+}
+
+func TestYamlFeeder_DecryptsWithKeyFromEnv(t *testing.T) {
+	path := writeEncryptedYAML(t, "password: "+encrypt(t, "hunter2")+"\n")
+
+	var cfg encryptedTestConfig
+	err := NewYamlFeeder(path).Feed(&cfg)
+	require.ErrorIs(t, err, ErrDecryptionKeyMissing, "ciphertext is never fed as a value")
+	assert.Empty(t, cfg.Password)
+
+	t.Setenv(ConfigKeyEnvVar, base64.StdEncoding.EncodeToString(testEncryptionKey))
+	require.NoError(t, NewYamlFeeder(path).Feed(&cfg))
+	assert.Equal(t, "hunter2", cfg.Password)
+}
+
+// armorDecrypter stands in for an age decrypter.
+type armorDecrypter struct{}
+
+func (armorDecrypter) CanDecrypt(value string) bool {
+	return strings.HasPrefix(value, ageArmorHeader)
+}
+
+func (armorDecrypter) Decrypt(string) (string, error) {
+	return "from-age", nil
+}
+
+func TestYamlFeeder_CustomDecrypter(t *testing.T) {
+	path := writeEncryptedYAML(t, "password: |\n"+
+		"  -----BEGIN AGE ENCRYPTED FILE-----\n"+
+		"  YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOQ==\n"+
+		"  -----END AGE ENCRYPTED FILE-----\n"+
+		"host: "+encrypt(t, "db.internal")+"\n")
+
+	var cfg encryptedTestConfig
+	err := NewYamlFeeder(path).WithDecrypter(armorDecrypter{}).Feed(&cfg)
+	require.ErrorIs(t, err, ErrUnsupportedEncryptedValue, "values the decrypter doesn't understand are errors")
+
+	path = writeEncryptedYAML(t, "password: |\n"+
+		"  -----BEGIN AGE ENCRYPTED FILE-----\n"+
+		"  YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOQ==\n"+
+		"  -----END AGE ENCRYPTED FILE-----\n")
+	require.NoError(t, NewYamlFeeder(path).WithDecrypter(armorDecrypter{}).Feed(&cfg))
+	assert.Equal(t, "from-age", cfg.Password)
+}
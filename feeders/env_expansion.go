@@ -0,0 +1,100 @@
+package feeders
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// expandEnvReferences replaces ${VAR} and ${VAR:-default} references in value
+// with the variable's value from lookup. ${VAR} expands to an empty string when
+// VAR is unset; the default is used when VAR is unset or empty. "$${" is an
+// escaped, literal "${". A "$" not followed by "{" is kept as is.
+func expandEnvReferences(value string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			b.WriteString(value)
+			return b.String(), nil
+		}
+		if start > 0 && value[start-1] == '$' {
+			b.WriteString(value[:start-1])
+			b.WriteString("${")
+			value = value[start+2:]
+			continue
+		}
+		b.WriteString(value[:start])
+
+		end := strings.IndexByte(value[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("%w: unterminated %q", ErrInvalidEnvReference, value[start:])
+		}
+		reference := value[start+2 : start+end]
+		name, fallback, hasDefault := strings.Cut(reference, ":-")
+		if !isEnvName(name) {
+			return "", fmt.Errorf("%w: ${%s}", ErrInvalidEnvReference, reference)
+		}
+		if resolved, ok := lookup(name); ok && (resolved != "" || !hasDefault) {
+			b.WriteString(resolved)
+		} else {
+			b.WriteString(fallback)
+		}
+		value = value[start+end+1:]
+	}
+}
+
+// isEnvName reports whether name is a valid environment variable name.
+func isEnvName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// expandYAMLEnvNode expands environment variable references in the scalar
+// values of node, looking variables up in the global environment catalog.
+// Mapping keys are left untouched. Plain scalars are resolved again after
+// expansion, so "port: ${PORT}" decodes as an integer; quoted scalars stay
+// strings.
+func expandYAMLEnvNode(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		expanded, err := expandEnvReferences(node.Value, GetGlobalEnvCatalog().Get)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		if expanded != node.Value {
+			node.Value = expanded
+			if node.Style&(yaml.TaggedStyle|yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) == 0 {
+				node.Tag = ""
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := expandYAMLEnvNode(node.Content[i]); err != nil {
+				return err
+			}
+		}
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := expandYAMLEnvNode(child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
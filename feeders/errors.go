@@ -46,6 +46,35 @@ var (
 	ErrYamlExpectedMapForSlice  = errors.New("expected map for slice element")
 )
 
+// INI feeder errors
+var (
+	ErrIniInvalidStructureType = errors.New("expected pointer to struct or map")
+	ErrIniInvalidLine          = errors.New("invalid INI line")
+	ErrIniExpectedSection      = errors.New("expected section for field")
+	ErrIniExpectedValue        = errors.New("expected value for field")
+	ErrIniCannotConvert        = errors.New("cannot convert value to field type")
+)
+
+// Duration conversion errors
+var (
+	ErrDurationMapEntry        = errors.New("expected key=duration map entry")
+	ErrDurationUnsupportedType = errors.New("unsupported time.Duration field type")
+)
+
+// Encrypted value errors
+var (
+	ErrDecryptionKeyMissing      = errors.New("decryption key not configured")
+	ErrInvalidDecryptionKey      = errors.New("invalid decryption key")
+	ErrInvalidEncryptedValue     = errors.New("invalid encrypted value")
+	ErrUnsupportedEncryptedValue = errors.New("no decrypter for encrypted value")
+	ErrDecryptionFailed          = errors.New("decryption failed")
+)
+
+// Environment variable expansion errors
+var (
+	ErrInvalidEnvReference = errors.New("invalid environment variable reference")
+)
+
 // General feeder errors
 var (
 	ErrJsonFeederUnavailable = errors.New("json feeder unavailable")
@@ -144,3 +173,19 @@ func wrapYamlArraySizeExceeded(fieldPath string, arraySize, maxSize int) error {
 func wrapYamlExpectedMapForSliceError(fieldPath string, index int, got interface{}) error {
 	return fmt.Errorf("%w %d in field %s, got %T", ErrYamlExpectedMapForSlice, index, fieldPath, got)
 }
+
+func wrapIniStructureError(got interface{}) error {
+	return fmt.Errorf("%w, got %T", ErrIniInvalidStructureType, got)
+}
+
+func wrapIniLineError(lineNum int, line string) error {
+	return fmt.Errorf("%w %d: %q", ErrIniInvalidLine, lineNum, line)
+}
+
+func wrapIniSectionError(fieldPath string, got interface{}) error {
+	return fmt.Errorf("%w %s, got %T", ErrIniExpectedSection, fieldPath, got)
+}
+
+func wrapIniValueError(fieldPath string) error {
+	return fmt.Errorf("%w %s, got a section", ErrIniExpectedValue, fieldPath)
+}
@@ -0,0 +1,428 @@
+package feeders
+
+import (
+	"bufio"
+	"bytes"
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// textUnmarshalerType is used to detect fields that parse their own values
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// IniFeeder is a feeder that reads INI files with optional verbose debug logging.
+//
+// Keys before the first section header belong to the top-level struct, and each
+// [section] populates the nested struct or map field with the matching `ini` tag.
+// Dotted section names nest further, so [database.replica] populates the Replica
+// field of the Database struct, or the "replica" entry of a map of structs. Values
+// are converted to the field's type; slices are comma-separated lists.
+//
+//	name = orders
+//	[database]
+//	host = db.internal
+//	port = 5432
+//	[database.replica]
+//	host = replica.internal
+type IniFeeder struct {
+	Path         string
+	verboseDebug bool
+	logger       interface {
+		Debug(msg string, args ...any)
+	}
+	fieldTracker FieldTracker
+	priority     int
+}
+
+// NewIniFeeder creates a new IniFeeder that reads from the specified INI file
+func NewIniFeeder(filePath string) *IniFeeder {
+	return &IniFeeder{
+		Path:         filePath,
+		verboseDebug: false,
+		logger:       nil,
+		fieldTracker: nil,
+		priority:     0, // Default priority
+	}
+}
+
+// WithPriority sets the priority for this feeder and returns the feeder for chaining.
+// Higher priority values mean the feeder will be applied later, allowing it to override
+// values from lower priority feeders.
+func (i *IniFeeder) WithPriority(priority int) *IniFeeder {
+	i.priority = priority
+	return i
+}
+
+// Priority returns the priority value for this feeder.
+func (i *IniFeeder) Priority() int {
+	return i.priority
+}
+
+// SetVerboseDebug enables or disables verbose debug logging
+func (i *IniFeeder) SetVerboseDebug(enabled bool, logger interface{ Debug(msg string, args ...any) }) {
+	i.verboseDebug = enabled
+	i.logger = logger
+	if enabled && logger != nil {
+		i.logger.Debug("Verbose INI feeder debugging enabled")
+	}
+}
+
+// SetFieldTracker sets the field tracker for recording field populations
+func (i *IniFeeder) SetFieldTracker(tracker FieldTracker) {
+	i.fieldTracker = tracker
+}
+
+// Feed reads the INI file and populates the provided structure
+func (i *IniFeeder) Feed(structure interface{}) error {
+	if i.verboseDebug && i.logger != nil {
+		i.logger.Debug("IniFeeder: Starting feed process", "filePath", i.Path, "structureType", reflect.TypeOf(structure))
+	}
+
+	err := i.feed(structure, "")
+
+	if i.verboseDebug && i.logger != nil {
+		if err != nil {
+			i.logger.Debug("IniFeeder: Feed completed with error", "filePath", i.Path, "error", err)
+		} else {
+			i.logger.Debug("IniFeeder: Feed completed successfully", "filePath", i.Path)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("ini feed error: %w", err)
+	}
+	return nil
+}
+
+// FeedKey reads an INI file and populates target from the section named key
+func (i *IniFeeder) FeedKey(key string, target interface{}) error {
+	if i.verboseDebug && i.logger != nil {
+		i.logger.Debug("IniFeeder: Starting FeedKey process", "filePath", i.Path, "key", key, "targetType", reflect.TypeOf(target))
+	}
+
+	err := i.feed(target, key)
+
+	if i.verboseDebug && i.logger != nil {
+		if err != nil {
+			i.logger.Debug("IniFeeder: FeedKey completed with error", "filePath", i.Path, "key", key, "error", err)
+		} else {
+			i.logger.Debug("IniFeeder: FeedKey completed successfully", "filePath", i.Path, "key", key)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("ini feed error: %w", err)
+	}
+	return nil
+}
+
+// feed parses the file and populates target from the section named key, or from the
+// whole file when key is empty.
+func (i *IniFeeder) feed(target interface{}, key string) error {
+	data, err := os.ReadFile(i.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read INI file %s: %w", i.Path, err)
+	}
+	iniData, err := parseINI(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse INI file %s: %w", i.Path, err)
+	}
+
+	if key != "" {
+		section, ok := iniData[key].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		iniData = section
+	}
+
+	targetValue := reflect.ValueOf(target)
+	if targetValue.Kind() != reflect.Ptr || targetValue.IsNil() {
+		return wrapIniStructureError(target)
+	}
+	switch elem := targetValue.Elem(); {
+	case elem.Kind() == reflect.Struct:
+		return i.processStructFields(elem, iniData, "", key)
+	case elem.Type() == reflect.TypeOf(iniData):
+		elem.Set(reflect.ValueOf(iniData))
+		return nil
+	default:
+		return wrapIniStructureError(target)
+	}
+}
+
+// processStructFields iterates through struct fields and populates them from a section
+func (i *IniFeeder) processStructFields(rv reflect.Value, section map[string]interface{}, fieldPrefix, keyPrefix string) error {
+	structType := rv.Type()
+
+	for idx := 0; idx < rv.NumField(); idx++ {
+		field := rv.Field(idx)
+		fieldType := structType.Field(idx)
+
+		// Skip unexported fields
+		if !field.CanSet() {
+			continue
+		}
+
+		iniKey := fieldType.Name
+		if tag := fieldType.Tag.Get("ini"); tag == "-" {
+			continue
+		} else if name := strings.Split(tag, ",")[0]; name != "" {
+			iniKey = name
+		}
+
+		fieldPath := fieldType.Name
+		if fieldPrefix != "" {
+			fieldPath = fieldPrefix + "." + fieldType.Name
+		}
+		sourceKey := iniKey
+		if keyPrefix != "" {
+			sourceKey = keyPrefix + "." + iniKey
+		}
+
+		if value, exists := section[iniKey]; exists {
+			if err := i.processField(field, value, fieldPath, sourceKey); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// processField sets a single field from a value, which is a section for structs and
+// maps and a string otherwise
+func (i *IniFeeder) processField(field reflect.Value, value interface{}, fieldPath, sourceKey string) error {
+	section, isSection := value.(map[string]interface{})
+
+	switch {
+	case !isSection && field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType):
+		// Types such as time.Time parse themselves
+		str, _ := value.(string)
+		if err := field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return fmt.Errorf("%w for field %s: %w", ErrIniCannotConvert, fieldPath, err)
+		}
+		i.recordField(field, fieldPath, sourceKey, field.Interface())
+		return nil
+
+	case field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct:
+		if !isSection {
+			return wrapIniSectionError(fieldPath, value)
+		}
+		ptrValue := reflect.New(field.Type().Elem())
+		if err := i.processStructFields(ptrValue.Elem(), section, fieldPath, sourceKey); err != nil {
+			return err
+		}
+		field.Set(ptrValue)
+		return nil
+
+	case field.Kind() == reflect.Struct:
+		if !isSection {
+			return wrapIniSectionError(fieldPath, value)
+		}
+		return i.processStructFields(field, section, fieldPath, sourceKey)
+
+	case field.Kind() == reflect.Map:
+		if !isSection {
+			return wrapIniSectionError(fieldPath, value)
+		}
+		return i.setMapFromINI(field, section, fieldPath, sourceKey)
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return wrapIniValueError(fieldPath)
+	}
+	if err := setINIValue(field, str); err != nil {
+		return fmt.Errorf("%w for field %s: %w", ErrIniCannotConvert, fieldPath, err)
+	}
+	i.recordField(field, fieldPath, sourceKey, field.Interface())
+	return nil
+}
+
+// setMapFromINI populates a map from a section. Maps of structs take their entries from
+// the section's subsections, other maps from its keys.
+func (i *IniFeeder) setMapFromINI(field reflect.Value, section map[string]interface{}, fieldPath, sourceKey string) error {
+	mapType := field.Type()
+	if mapType.Key().Kind() != reflect.String {
+		return fmt.Errorf("%w for field %s: map keys must be strings", ErrIniCannotConvert, fieldPath)
+	}
+
+	newMap := reflect.MakeMap(mapType)
+	for key, value := range section {
+		entry := reflect.New(mapType.Elem()).Elem()
+		if err := i.processField(entry, value, fieldPath+"."+key, sourceKey+"."+key); err != nil {
+			return err
+		}
+		newMap.SetMapIndex(reflect.ValueOf(key).Convert(mapType.Key()), entry)
+	}
+	field.Set(newMap)
+	i.recordField(field, fieldPath, sourceKey, field.Interface())
+
+	if i.verboseDebug && i.logger != nil {
+		i.logger.Debug("IniFeeder: Successfully set map field", "fieldPath", fieldPath, "mapSize", newMap.Len())
+	}
+	return nil
+}
+
+// recordField records a field population if a tracker is set
+func (i *IniFeeder) recordField(field reflect.Value, fieldPath, sourceKey string, value interface{}) {
+	if i.fieldTracker == nil {
+		return
+	}
+	i.fieldTracker.RecordFieldPopulation(FieldPopulation{
+		FieldPath:  fieldPath,
+		FieldName:  fieldPath,
+		FieldType:  field.Type().String(),
+		FeederType: "IniFeeder",
+		SourceType: "ini_file",
+		SourceKey:  sourceKey,
+		Value:      value,
+		SearchKeys: []string{sourceKey},
+		FoundKey:   sourceKey,
+	})
+}
+
+// setINIValue converts an INI value to the field's type. Slices and arrays are
+// comma-separated lists and pointers are allocated.
+func setINIValue(field reflect.Value, value string) error {
+	switch field.Kind() { //nolint:exhaustive // default case handles all other types
+	case reflect.Ptr:
+		ptrValue := reflect.New(field.Type().Elem())
+		if err := setINIValue(ptrValue.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(ptrValue)
+		return nil
+	case reflect.Slice:
+		items := splitINIList(value)
+		slice := reflect.MakeSlice(field.Type(), len(items), len(items))
+		for idx, item := range items {
+			if err := setINIValue(slice.Index(idx), item); err != nil {
+				return err
+			}
+		}
+		field.Set(slice)
+		return nil
+	case reflect.Array:
+		items := splitINIList(value)
+		if len(items) > field.Len() {
+			return fmt.Errorf("%w: %d items for an array of %d", ErrIniCannotConvert, len(items), field.Len())
+		}
+		for idx, item := range items {
+			if err := setINIValue(field.Index(idx), item); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return setFieldValue(field, value)
+	}
+}
+
+// splitINIList splits a comma-separated list, trimming each item
+func splitINIList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for idx, item := range items {
+		items[idx] = strings.TrimSpace(item)
+	}
+	return items
+}
+
+// parseINI parses INI data into nested maps. Sections become maps, with dotted section
+// names nested inside their parents, and values are strings. Lines starting with ';' or
+// '#' are comments, as is the rest of an unquoted value after " ;" or " #". Values may
+// be wrapped in double quotes, with Go escapes, or single quotes.
+func parseINI(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	current := root
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineNum == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, wrapIniLineError(lineNum, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				return nil, wrapIniLineError(lineNum, line)
+			}
+			section, err := iniSection(root, name)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			current = section
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, wrapIniLineError(lineNum, line)
+		}
+		value, err := parseINIValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		if _, isSection := current[key].(map[string]interface{}); isSection {
+			return nil, fmt.Errorf("%w: line %d: key %q is also a section", ErrIniInvalidLine, lineNum, key)
+		}
+		current[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read INI data: %w", err)
+	}
+	return root, nil
+}
+
+// iniSection returns the map for a dotted section name, creating it and its parents
+func iniSection(root map[string]interface{}, name string) (map[string]interface{}, error) {
+	section := root
+	for _, part := range strings.Split(name, ".") {
+		part = strings.TrimSpace(part)
+		switch existing := section[part].(type) {
+		case map[string]interface{}:
+			section = existing
+		case nil:
+			child := make(map[string]interface{})
+			section[part] = child
+			section = child
+		default:
+			return nil, fmt.Errorf("%w: section %q conflicts with key %q", ErrIniInvalidLine, name, part)
+		}
+	}
+	return section, nil
+}
+
+// parseINIValue unquotes a quoted value or strips an inline comment from an unquoted one
+func parseINIValue(value string) (string, error) {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		unquoted, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid quoted value %s", ErrIniInvalidLine, value)
+		}
+		return unquoted, nil
+	}
+	if len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'' {
+		return value[1 : len(value)-1], nil
+	}
+	for _, marker := range []string{" ;", " #", "\t;", "\t#"} {
+		if idx := strings.Index(value, marker); idx >= 0 {
+			value = value[:idx]
+		}
+	}
+	return strings.TrimSpace(value), nil
+}
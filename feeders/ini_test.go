@@ -0,0 +1,142 @@
+package feeders
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeIniFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.ini")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+type iniReplicaConfig struct {
+	Host string `ini:"host"`
+	Port int    `ini:"port"`
+}
+
+type iniDatabaseConfig struct {
+	Host     string                      `ini:"host"`
+	Port     int                         `ini:"port"`
+	Timeout  time.Duration               `ini:"timeout"`
+	Tables   []string                    `ini:"tables"`
+	ReadOnly *bool                       `ini:"read_only"`
+	Replica  *iniReplicaConfig           `ini:"replica"`
+	Shards   map[string]iniReplicaConfig `ini:"shards"`
+}
+
+type iniAppConfig struct {
+	Name     string            `ini:"name"`
+	Debug    bool              `ini:"debug"`
+	Started  time.Time         `ini:"started"`
+	Database iniDatabaseConfig `ini:"database"`
+	Labels   map[string]string `ini:"labels"`
+	Ignored  string            `ini:"-"`
+	Version  string
+}
+
+const iniTestContent = `; application settings
+name = "orders service"
+debug = true
+started = 2026-10-16T09:00:00Z
+Version = 1.2.3 ; inline comment
+Ignored = nope
+
+[database]
+host = db.internal
+port = 5432
+timeout = 5s
+tables = orders, refunds
+read_only = false
+
+[database.replica]
+host = 'replica.internal'
+port = 5433
+
+[database.shards.eu]
+host = eu.internal
+
+[labels]
+team = payments
+`
+
+func TestIniFeeder_Feed(t *testing.T) {
+	tracker := NewDefaultFieldTracker()
+	feeder := NewIniFeeder(writeIniFile(t, iniTestContent))
+	feeder.SetFieldTracker(tracker)
+
+	var cfg iniAppConfig
+	require.NoError(t, feeder.Feed(&cfg))
+
+	assert.Equal(t, "orders service", cfg.Name)
+	assert.True(t, cfg.Debug)
+	assert.Equal(t, time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC), cfg.Started)
+	assert.Equal(t, "1.2.3", cfg.Version)
+	assert.Empty(t, cfg.Ignored)
+
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, 5432, cfg.Database.Port)
+	assert.Equal(t, 5*time.Second, cfg.Database.Timeout)
+	assert.Equal(t, []string{"orders", "refunds"}, cfg.Database.Tables)
+	require.NotNil(t, cfg.Database.ReadOnly)
+	assert.False(t, *cfg.Database.ReadOnly)
+	assert.Equal(t, &iniReplicaConfig{Host: "replica.internal", Port: 5433}, cfg.Database.Replica)
+	assert.Equal(t, map[string]iniReplicaConfig{"eu": {Host: "eu.internal"}}, cfg.Database.Shards)
+	assert.Equal(t, map[string]string{"team": "payments"}, cfg.Labels)
+
+	var found bool
+	for _, population := range tracker.GetFieldPopulations() {
+		if population.FieldPath == "Database.Replica.Port" {
+			found = true
+			assert.Equal(t, "IniFeeder", population.FeederType)
+			assert.Equal(t, "database.replica.port", population.SourceKey)
+		}
+	}
+	assert.True(t, found, "nested field population should be tracked")
+}
+
+func TestIniFeeder_FeedKey(t *testing.T) {
+	feeder := NewIniFeeder(writeIniFile(t, iniTestContent))
+
+	var database iniDatabaseConfig
+	require.NoError(t, feeder.FeedKey("database", &database))
+	assert.Equal(t, "db.internal", database.Host)
+	assert.Equal(t, "replica.internal", database.Replica.Host)
+
+	var missing iniDatabaseConfig
+	require.NoError(t, feeder.FeedKey("cache", &missing))
+	assert.Empty(t, missing.Host)
+
+	var raw map[string]interface{}
+	require.NoError(t, feeder.FeedKey("labels", &raw))
+	assert.Equal(t, map[string]interface{}{"team": "payments"}, raw)
+}
+
+func TestIniFeeder_Errors(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		target  interface{}
+		err     error
+	}{
+		"line without key":      {"name\n", &iniAppConfig{}, ErrIniInvalidLine},
+		"unterminated section":  {"[database\n", &iniAppConfig{}, ErrIniInvalidLine},
+		"key and section clash": {"database = x\n[database]\nhost = y\n", &iniAppConfig{}, ErrIniInvalidLine},
+		"invalid number":        {"[database]\nport = high\n", &iniAppConfig{}, ErrIniCannotConvert},
+		"value for struct":      {"database = db.internal\n", &iniAppConfig{}, ErrIniExpectedSection},
+		"section for value":     {"[name]\nfirst = orders\n", &iniAppConfig{}, ErrIniExpectedValue},
+		"non-pointer target":    {"name = orders\n", iniAppConfig{}, ErrIniInvalidStructureType},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := NewIniFeeder(writeIniFile(t, tt.content)).Feed(tt.target)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
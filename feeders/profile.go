@@ -0,0 +1,137 @@
+package feeders
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+)
+
+// profileFileExtensions lists the supported configuration file extensions in lookup order
+var profileFileExtensions = []string{".yaml", ".yml", ".json", ".toml", ".ini"}
+
+// ProfileFeeder layers a base configuration file with one file per active profile.
+// For base name "config" and profiles ["prod", "eu"] it feeds, in order and when present:
+//
+//	config.yaml, config.prod.yaml, config.eu.yaml
+//
+// Later files override values from earlier ones. Each layer may be YAML (.yaml/.yml),
+// JSON or TOML; missing files are skipped.
+type ProfileFeeder struct {
+	Dir      string   // Directory containing the configuration files
+	BaseName string   // Base file name without extension (e.g., "config")
+	Profiles []string // Active profiles, lowest precedence first
+
+	verboseDebug bool
+	logger       interface{ Debug(msg string, args ...any) }
+	fieldTracker FieldTracker
+}
+
+// NewProfileFeeder creates a feeder for dir/baseName.<ext> layered with
+// dir/baseName.<profile>.<ext> for each profile.
+func NewProfileFeeder(dir, baseName string, profiles ...string) *ProfileFeeder {
+	return &ProfileFeeder{
+		Dir:      dir,
+		BaseName: baseName,
+		Profiles: profiles,
+	}
+}
+
+// SetVerboseDebug enables or disables verbose debug logging
+func (p *ProfileFeeder) SetVerboseDebug(enabled bool, logger interface{ Debug(msg string, args ...any) }) {
+	p.verboseDebug = enabled
+	p.logger = logger
+	if enabled && logger != nil {
+		p.logger.Debug("Verbose profile feeder debugging enabled", "dir", p.Dir, "baseName", p.BaseName, "profiles", p.Profiles)
+	}
+}
+
+// SetFieldTracker sets the field tracker for recording field populations
+func (p *ProfileFeeder) SetFieldTracker(tracker FieldTracker) {
+	p.fieldTracker = tracker
+}
+
+// Files returns the configuration files that will be fed, in precedence order.
+func (p *ProfileFeeder) Files() []string {
+	names := make([]string, 0, len(p.Profiles)+1)
+	names = append(names, p.BaseName)
+	for _, profile := range p.Profiles {
+		names = append(names, p.BaseName+"."+profile)
+	}
+
+	files := make([]string, 0, len(names))
+	for _, name := range names {
+		if file := p.findFile(name); file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// Feed populates the structure from each layer in turn
+func (p *ProfileFeeder) Feed(structure interface{}) error {
+	for _, file := range p.Files() {
+		if p.verboseDebug && p.logger != nil {
+			p.logger.Debug("ProfileFeeder: Feeding layer", "file", file, "structureType", reflect.TypeOf(structure))
+		}
+		if err := p.layerFeeder(file).Feed(structure); err != nil {
+			return fmt.Errorf("profile layer %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// FeedKey populates the target from the given key of each layer in turn
+func (p *ProfileFeeder) FeedKey(key string, target interface{}) error {
+	for _, file := range p.Files() {
+		if p.verboseDebug && p.logger != nil {
+			p.logger.Debug("ProfileFeeder: Feeding key from layer", "file", file, "key", key)
+		}
+		if err := p.layerFeeder(file).FeedKey(key, target); err != nil {
+			return fmt.Errorf("profile layer %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// profileLayerFeeder is implemented by the file feeders used for each layer
+type profileLayerFeeder interface {
+	Feed(structure interface{}) error
+	FeedKey(key string, target interface{}) error
+	SetVerboseDebug(enabled bool, logger interface{ Debug(msg string, args ...any) })
+	SetFieldTracker(tracker FieldTracker)
+}
+
+// layerFeeder returns the file feeder matching the file's extension
+func (p *ProfileFeeder) layerFeeder(file string) profileLayerFeeder {
+	var feeder profileLayerFeeder
+	switch filepath.Ext(file) {
+	case ".json":
+		feeder = NewJSONFeeder(file)
+	case ".toml":
+		feeder = NewTomlFeeder(file)
+	case ".ini":
+		feeder = NewIniFeeder(file)
+	default:
+		feeder = NewYamlFeeder(file)
+	}
+
+	if p.verboseDebug {
+		feeder.SetVerboseDebug(true, p.logger)
+	}
+	if p.fieldTracker != nil {
+		feeder.SetFieldTracker(p.fieldTracker)
+	}
+	return feeder
+}
+
+// findFile returns the first existing dir/name.<ext>, or "" if there is none
+func (p *ProfileFeeder) findFile(name string) string {
+	for _, ext := range profileFileExtensions {
+		file := filepath.Join(p.Dir, name+ext)
+		if info, err := os.Stat(file); err == nil && !info.IsDir() {
+			return file
+		}
+	}
+	return ""
+}
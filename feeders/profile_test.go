@@ -0,0 +1,71 @@
+package feeders
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type profileTestConfig struct {
+	Name     string `yaml:"name" json:"name" toml:"name"`
+	Port     int    `yaml:"port" json:"port" toml:"port"`
+	Debug    bool   `yaml:"debug" json:"debug" toml:"debug"`
+	Region   string `yaml:"region" json:"region" toml:"region"`
+	LogLevel string `yaml:"log_level" json:"log_level" toml:"log_level"`
+}
+
+func writeProfileFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+}
+
+func TestProfileFeeder_LayersInOrder(t *testing.T) {
+	dir := t.TempDir()
+	writeProfileFile(t, dir, "config.yaml", "name: app\nport: 8080\ndebug: true\nlog_level: debug\n")
+	writeProfileFile(t, dir, "config.prod.json", `{"port": 80, "debug": false, "log_level": "warn"}`)
+	writeProfileFile(t, dir, "config.eu.toml", "region = \"eu-west-1\"\nlog_level = \"error\"\n")
+
+	feeder := NewProfileFeeder(dir, "config", "prod", "eu", "missing")
+
+	files := feeder.Files()
+	if len(files) != 3 {
+		t.Fatalf("expected 3 layers, got %v", files)
+	}
+
+	var cfg profileTestConfig
+	if err := feeder.Feed(&cfg); err != nil {
+		t.Fatalf("feed failed: %v", err)
+	}
+
+	expected := profileTestConfig{Name: "app", Port: 80, Debug: false, Region: "eu-west-1", LogLevel: "error"}
+	if cfg != expected {
+		t.Errorf("expected %+v, got %+v", expected, cfg)
+	}
+}
+
+func TestProfileFeeder_FeedKey(t *testing.T) {
+	dir := t.TempDir()
+	writeProfileFile(t, dir, "app.yml", "server:\n  name: app\n  port: 8080\n")
+	writeProfileFile(t, dir, "app.staging.yml", "server:\n  port: 9090\n")
+
+	var cfg profileTestConfig
+	if err := NewProfileFeeder(dir, "app", "staging").FeedKey("server", &cfg); err != nil {
+		t.Fatalf("feed key failed: %v", err)
+	}
+
+	if cfg.Name != "app" || cfg.Port != 9090 {
+		t.Errorf("expected name=app port=9090, got %+v", cfg)
+	}
+}
+
+func TestProfileFeeder_NoFiles(t *testing.T) {
+	var cfg profileTestConfig
+	if err := NewProfileFeeder(t.TempDir(), "config", "prod").Feed(&cfg); err != nil {
+		t.Fatalf("expected no error without files, got %v", err)
+	}
+	if cfg != (profileTestConfig{}) {
+		t.Errorf("expected zero config, got %+v", cfg)
+	}
+}
@@ -1,6 +1,7 @@
 package feeders
 
 import (
+	"encoding"
 	"fmt"
 	"os"
 	"reflect"
@@ -178,6 +179,11 @@ func (t *TomlFeeder) processStructFields(rv reflect.Value, tomlData map[string]i
 
 // processField processes a single field, handling nested structs, slices, and basic types
 func (t *TomlFeeder) processField(field reflect.Value, fieldType reflect.StructField, value interface{}, fieldPath string) error {
+	// Types parsing their own text, such as time.Time and net.IP, are set from strings
+	if _, isString := value.(string); isString && reflect.PointerTo(field.Type()).Implements(textUnmarshalerType) {
+		return t.setFieldFromTOML(field, value, fieldPath)
+	}
+
 	fieldKind := field.Kind()
 
 	switch fieldKind {
@@ -188,8 +194,15 @@ func (t *TomlFeeder) processField(field reflect.Value, fieldType reflect.StructF
 	case reflect.Struct:
 		// Handle nested structs
 		if nestedMap, ok := value.(map[string]interface{}); ok {
+			if t.verboseDebug && t.logger != nil {
+				t.logger.Debug("TomlFeeder: Processing nested struct", "fieldPath", fieldPath, "structType", field.Type())
+			}
 			return t.processStructFields(field, nestedMap, fieldPath)
 		}
+		// Structs such as time.Time are filled from TOML datetimes and strings
+		if _, ok := convertTOMLValue(value, field.Type()); ok {
+			return t.setFieldFromTOML(field, value, fieldPath)
+		}
 		return wrapTomlMapError(fieldPath, value)
 
 	case reflect.Slice:
@@ -271,14 +284,16 @@ func (t *TomlFeeder) setPointerFromTOML(field reflect.Value, value interface{},
 					return fmt.Errorf("error processing pointer to struct: %w", err)
 				}
 				field.Set(ptrValue)
+			} else if converted, ok := convertTOMLValue(value, elemType); ok {
+				ptrValue.Elem().Set(converted)
+				field.Set(ptrValue)
 			} else {
 				return wrapTomlConvertError(value, field.Type().String(), fieldPath)
 			}
 		default:
 			// Handle pointer to basic type
-			convertedValue := reflect.ValueOf(value)
-			if convertedValue.Type().ConvertibleTo(elemType) {
-				ptrValue.Elem().Set(convertedValue.Convert(elemType))
+			if convertedValue, ok := convertTOMLValue(value, elemType); ok {
+				ptrValue.Elem().Set(convertedValue)
 				field.Set(ptrValue)
 			} else {
 				return wrapTomlConvertError(value, field.Type().String(), fieldPath)
@@ -381,9 +396,11 @@ func (t *TomlFeeder) setFieldFromTOML(field reflect.Value, value interface{}, fi
 	}
 
 	// Convert and set the value
-	convertedValue := reflect.ValueOf(value)
-	if convertedValue.Type().ConvertibleTo(field.Type()) {
-		field.Set(convertedValue.Convert(field.Type()))
+	if convertedValue, ok := convertTOMLValue(value, field.Type()); ok {
+		field.Set(convertedValue)
+		if t.verboseDebug && t.logger != nil {
+			t.logger.Debug("TomlFeeder: Set field", "fieldPath", fieldPath, "fieldType", field.Type())
+		}
 
 		// Record field population
 		if t.fieldTracker != nil {
@@ -441,6 +458,8 @@ func (t *TomlFeeder) setSliceFromTOML(field reflect.Value, value interface{}, fi
 				if err := t.processStructFields(elem, itemMap, fmt.Sprintf("%s[%d]", fieldPath, i)); err != nil {
 					return fmt.Errorf("error processing slice element %d: %w", i, err)
 				}
+			} else if convertedItem, ok := convertTOMLValue(item, elemType); ok {
+				elem.Set(convertedItem)
 			} else {
 				return wrapTomlSliceElementError(item, elemType.String(), fieldPath, i)
 			}
@@ -462,10 +481,9 @@ func (t *TomlFeeder) setSliceFromTOML(field reflect.Value, value interface{}, fi
 				}
 			} else {
 				// Pointer to basic type
-				convertedItem := reflect.ValueOf(item)
-				if convertedItem.Type().ConvertibleTo(ptrElemType) {
+				if convertedItem, ok := convertTOMLValue(item, ptrElemType); ok {
 					ptrValue := reflect.New(ptrElemType)
-					ptrValue.Elem().Set(convertedItem.Convert(ptrElemType))
+					ptrValue.Elem().Set(convertedItem)
 					elem.Set(ptrValue)
 				} else {
 					return wrapTomlSliceElementError(item, elemType.String(), fieldPath, i)
@@ -473,9 +491,8 @@ func (t *TomlFeeder) setSliceFromTOML(field reflect.Value, value interface{}, fi
 			}
 		default:
 			// Handle basic types
-			convertedItem := reflect.ValueOf(item)
-			if convertedItem.Type().ConvertibleTo(elemType) {
-				elem.Set(convertedItem.Convert(elemType))
+			if convertedItem, ok := convertTOMLValue(item, elemType); ok {
+				elem.Set(convertedItem)
 			} else {
 				return wrapTomlSliceElementError(item, elemType.String(), fieldPath, i)
 			}
@@ -588,18 +605,16 @@ func (t *TomlFeeder) setMapFromTOML(field reflect.Value, tomlData map[string]int
 					newMap.SetMapIndex(keyValue, reflect.Zero(valueType)) // Set to nil pointer
 				} else {
 					keyValue := reflect.ValueOf(key)
-					valueReflect := reflect.ValueOf(value)
 
 					// Create a new pointer to the element type
 					ptrValue := reflect.New(elemType)
 
-					if valueReflect.Type().ConvertibleTo(elemType) {
-						convertedValue := valueReflect.Convert(elemType)
+					if convertedValue, ok := convertTOMLValue(value, elemType); ok {
 						ptrValue.Elem().Set(convertedValue)
 						newMap.SetMapIndex(keyValue, ptrValue)
 					} else {
 						if t.verboseDebug && t.logger != nil {
-							t.logger.Debug("TomlFeeder: Cannot convert map value for pointer type", "key", key, "valueType", valueReflect.Type(), "targetType", elemType)
+							t.logger.Debug("TomlFeeder: Cannot convert map value for pointer type", "key", key, "valueType", reflect.TypeOf(value), "targetType", elemType)
 						}
 					}
 				}
@@ -613,14 +628,12 @@ func (t *TomlFeeder) setMapFromTOML(field reflect.Value, tomlData map[string]int
 		// Map of primitive types - use direct conversion
 		for key, value := range tomlData {
 			keyValue := reflect.ValueOf(key)
-			valueReflect := reflect.ValueOf(value)
 
-			if valueReflect.Type().ConvertibleTo(valueType) {
-				convertedValue := valueReflect.Convert(valueType)
+			if convertedValue, ok := convertTOMLValue(value, valueType); ok {
 				newMap.SetMapIndex(keyValue, convertedValue)
 			} else {
 				if t.verboseDebug && t.logger != nil {
-					t.logger.Debug("TomlFeeder: Cannot convert map value", "key", key, "valueType", valueReflect.Type(), "targetType", valueType)
+					t.logger.Debug("TomlFeeder: Cannot convert map value", "key", key, "valueType", reflect.TypeOf(value), "targetType", valueType)
 				}
 			}
 		}
@@ -628,14 +641,12 @@ func (t *TomlFeeder) setMapFromTOML(field reflect.Value, tomlData map[string]int
 		// Map of primitive types - use direct conversion
 		for key, value := range tomlData {
 			keyValue := reflect.ValueOf(key)
-			valueReflect := reflect.ValueOf(value)
 
-			if valueReflect.Type().ConvertibleTo(valueType) {
-				convertedValue := valueReflect.Convert(valueType)
+			if convertedValue, ok := convertTOMLValue(value, valueType); ok {
 				newMap.SetMapIndex(keyValue, convertedValue)
 			} else {
 				if t.verboseDebug && t.logger != nil {
-					t.logger.Debug("TomlFeeder: Cannot convert map value", "key", key, "valueType", valueReflect.Type(), "targetType", valueType)
+					t.logger.Debug("TomlFeeder: Cannot convert map value", "key", key, "valueType", reflect.TypeOf(value), "targetType", valueType)
 				}
 			}
 		}
@@ -667,3 +678,37 @@ func (t *TomlFeeder) setMapFromTOML(field reflect.Value, tomlData map[string]int
 
 	return nil
 }
+
+// convertTOMLValue converts a decoded TOML value to target. Durations are parsed from
+// strings like "30s", strings fill types implementing encoding.TextUnmarshaler, and
+// other values are converted as Go allows, except numbers, which never become strings.
+func convertTOMLValue(value interface{}, target reflect.Type) (reflect.Value, bool) {
+	valueReflect := reflect.ValueOf(value)
+	if !valueReflect.IsValid() {
+		return reflect.Value{}, false
+	}
+	if str, ok := value.(string); ok {
+		if target == durationType {
+			duration, err := time.ParseDuration(str)
+			if err != nil {
+				return reflect.Value{}, false
+			}
+			return reflect.ValueOf(duration), true
+		}
+		if reflect.PointerTo(target).Implements(textUnmarshalerType) {
+			ptr := reflect.New(target)
+			if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+				return reflect.Value{}, false
+			}
+			return ptr.Elem(), true
+		}
+	}
+	if target.Kind() == reflect.String && valueReflect.Kind() != reflect.String {
+		// Go converts integers to strings as runes
+		return reflect.Value{}, false
+	}
+	if !valueReflect.Type().ConvertibleTo(target) {
+		return reflect.Value{}, false
+	}
+	return valueReflect.Convert(target), true
+}
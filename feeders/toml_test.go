@@ -1,8 +1,13 @@
 package feeders
 
 import (
+	"errors"
+	"net"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestTomlFeeder_Feed(t *testing.T) {
@@ -47,3 +52,77 @@ Debug = true
 		t.Errorf("Expected Debug to be true, got false")
 	}
 }
+
+func TestTomlFeeder_TypedValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	content := `
+started = 2024-01-02T03:04:05Z
+deadline = "2024-06-30T00:00:00Z"
+listen_ip = "10.0.0.1"
+retry_delays = ["1s", "5s", "30s"]
+
+[timeouts]
+read = "5s"
+write = "10s"
+
+[[windows]]
+opens = 2024-01-01T09:00:00Z
+closes = 2024-01-01T17:00:00Z
+`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	type window struct {
+		Opens  time.Time `toml:"opens"`
+		Closes time.Time `toml:"closes"`
+	}
+	var config struct {
+		Started     time.Time                `toml:"started"`
+		Deadline    *time.Time               `toml:"deadline"`
+		ListenIP    net.IP                   `toml:"listen_ip"`
+		RetryDelays []time.Duration          `toml:"retry_delays"`
+		Timeouts    map[string]time.Duration `toml:"timeouts"`
+		Windows     []window                 `toml:"windows"`
+	}
+	if err := NewTomlFeeder(path).Feed(&config); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC); !config.Started.Equal(want) {
+		t.Errorf("Expected Started %v, got %v", want, config.Started)
+	}
+	if config.Deadline == nil || config.Deadline.Month() != time.June {
+		t.Errorf("Expected Deadline in June, got %v", config.Deadline)
+	}
+	if !config.ListenIP.Equal(net.ParseIP("10.0.0.1")) {
+		t.Errorf("Expected ListenIP 10.0.0.1, got %v", config.ListenIP)
+	}
+	if !reflect.DeepEqual(config.RetryDelays, []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}) {
+		t.Errorf("Unexpected RetryDelays %v", config.RetryDelays)
+	}
+	if config.Timeouts["read"] != 5*time.Second || config.Timeouts["write"] != 10*time.Second {
+		t.Errorf("Unexpected Timeouts %v", config.Timeouts)
+	}
+	if len(config.Windows) != 1 || config.Windows[0].Closes.Hour() != 17 {
+		t.Errorf("Unexpected Windows %v", config.Windows)
+	}
+}
+
+func TestTomlFeeder_NumberForStringField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte("name = 65\n"), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	var config struct {
+		Name string `toml:"name"`
+	}
+	err := NewTomlFeeder(path).Feed(&config)
+	if !errors.Is(err, ErrTomlCannotConvert) {
+		t.Fatalf("Expected ErrTomlCannotConvert, got %v", err)
+	}
+	if config.Name != "" {
+		t.Errorf("Expected the number not to be converted to %q", config.Name)
+	}
+}
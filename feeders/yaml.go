@@ -51,6 +51,10 @@ type YamlFeeder struct {
 	}
 	fieldTracker FieldTracker
 	priority     int
+	decrypter    Decrypter
+
+	// disableEnvExpansion turns off ${VAR} expansion in values
+	disableEnvExpansion bool
 }
 
 // NewYamlFeeder creates a new YamlFeeder that reads from the specified YAML file
@@ -72,6 +76,22 @@ func (y *YamlFeeder) WithPriority(priority int) *YamlFeeder {
 	return y
 }
 
+// WithDecrypter sets the decrypter for encrypted values and returns the feeder for
+// chaining. Without one, AES256_GCM values are decrypted with the key in ConfigKeyEnvVar.
+func (y *YamlFeeder) WithDecrypter(decrypter Decrypter) *YamlFeeder {
+	y.decrypter = decrypter
+	return y
+}
+
+// WithEnvExpansion enables or disables the expansion of ${VAR} and ${VAR:-default}
+// references in YAML values, which is enabled by default, and returns the feeder
+// for chaining. Variables are looked up in the environment catalog, so values
+// loaded by a DotEnvFeeder can be referenced too.
+func (y *YamlFeeder) WithEnvExpansion(enabled bool) *YamlFeeder {
+	y.disableEnvExpansion = !enabled
+	return y
+}
+
 // Priority returns the priority value for this feeder.
 func (y *YamlFeeder) Priority() int {
 	return y.priority
@@ -180,6 +200,27 @@ func (y *YamlFeeder) feedWithTracking(structure interface{}) error {
 		return fmt.Errorf("failed to read YAML file: %w", err)
 	}
 
+	// Parse YAML content, expand environment variables and decrypt encrypted values
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		if y.verboseDebug && y.logger != nil {
+			y.logger.Debug("YamlFeeder: Failed to parse YAML content", "filePath", y.Path, "error", err)
+		}
+		return fmt.Errorf("failed to parse YAML content: %w", err)
+	}
+	if !y.disableEnvExpansion {
+		if err := expandYAMLEnvNode(&document); err != nil {
+			return fmt.Errorf("failed to expand environment variables in YAML file %s: %w", y.Path, err)
+		}
+	}
+	if err := decryptYAMLNode(&document, y.decrypter); err != nil {
+		return fmt.Errorf("failed to decrypt YAML file %s: %w", y.Path, err)
+	}
+	if document.Kind == 0 {
+		// Empty file
+		return nil
+	}
+
 	// Check if we're dealing with a struct pointer
 	structValue := reflect.ValueOf(structure)
 	if structValue.Kind() != reflect.Ptr || structValue.Elem().Kind() != reflect.Struct {
@@ -187,15 +228,14 @@ func (y *YamlFeeder) feedWithTracking(structure interface{}) error {
 		if y.verboseDebug && y.logger != nil {
 			y.logger.Debug("YamlFeeder: Not a struct pointer, using standard YAML unmarshaling", "structureType", reflect.TypeOf(structure))
 		}
-		if err := yaml.Unmarshal(content, structure); err != nil {
+		if err := document.Decode(structure); err != nil {
 			return fmt.Errorf("failed to unmarshal YAML data: %w", err)
 		}
 		return nil
 	}
 
-	// Parse YAML content
 	data := make(map[string]interface{})
-	if err := yaml.Unmarshal(content, &data); err != nil {
+	if err := document.Decode(&data); err != nil {
 		if y.verboseDebug && y.logger != nil {
 			y.logger.Debug("YamlFeeder: Failed to parse YAML content", "filePath", y.Path, "error", err)
 		}
@@ -741,7 +781,12 @@ func (y *YamlFeeder) setMapFromYaml(field reflect.Value, yamlData map[string]int
 				// Create a new pointer to the element type
 				ptrValue := reflect.New(elemType)
 
-				if valueReflect.Type().ConvertibleTo(elemType) {
+				if isDurationType(elemType) {
+					if err := y.setFieldValue(ptrValue.Elem(), value); err != nil {
+						return fmt.Errorf("error processing map entry '%s': %w", key, err)
+					}
+					newMap.SetMapIndex(keyValue, ptrValue)
+				} else if valueReflect.Type().ConvertibleTo(elemType) {
 					convertedValue := valueReflect.Convert(elemType)
 					ptrValue.Elem().Set(convertedValue)
 					newMap.SetMapIndex(keyValue, ptrValue)
@@ -762,7 +807,14 @@ func (y *YamlFeeder) setMapFromYaml(field reflect.Value, yamlData map[string]int
 			keyValue := reflect.ValueOf(key)
 			valueReflect := reflect.ValueOf(value)
 
-			if valueReflect.Type().ConvertibleTo(valueType) {
+			// Durations are parsed from strings like "30s" rather than converted from integers
+			if isDurationType(valueType) {
+				elem := reflect.New(valueType).Elem()
+				if err := y.setFieldValue(elem, value); err != nil {
+					return fmt.Errorf("error processing map entry '%s': %w", key, err)
+				}
+				newMap.SetMapIndex(keyValue, elem)
+			} else if valueReflect.Type().ConvertibleTo(valueType) {
 				convertedValue := valueReflect.Convert(valueType)
 				newMap.SetMapIndex(keyValue, convertedValue)
 			} else {
@@ -777,7 +829,14 @@ func (y *YamlFeeder) setMapFromYaml(field reflect.Value, yamlData map[string]int
 			keyValue := reflect.ValueOf(key)
 			valueReflect := reflect.ValueOf(value)
 
-			if valueReflect.Type().ConvertibleTo(valueType) {
+			// Durations are parsed from strings like "30s" rather than converted from integers
+			if isDurationType(valueType) {
+				elem := reflect.New(valueType).Elem()
+				if err := y.setFieldValue(elem, value); err != nil {
+					return fmt.Errorf("error processing map entry '%s': %w", key, err)
+				}
+				newMap.SetMapIndex(keyValue, elem)
+			} else if valueReflect.Type().ConvertibleTo(valueType) {
 				convertedValue := valueReflect.Convert(valueType)
 				newMap.SetMapIndex(keyValue, convertedValue)
 			} else {
@@ -827,7 +886,7 @@ func (y *YamlFeeder) setFieldValue(field reflect.Value, value interface{}) error
 	}
 
 	// Special handling for time.Duration
-	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+	if field.Type() == durationType {
 		if valueReflect.Kind() == reflect.String {
 			str := valueReflect.String()
 			duration, err := time.ParseDuration(str)
@@ -920,6 +979,13 @@ func (y *YamlFeeder) setSliceValue(field reflect.Value, valueReflect reflect.Val
 		sourceElem := valueReflect.Index(i)
 		targetElem := newSlice.Index(i)
 
+		if isDurationType(elemType) {
+			if err := y.setFieldValue(targetElem, sourceElem.Interface()); err != nil {
+				return fmt.Errorf("error setting slice element %d: %w", i, err)
+			}
+			continue
+		}
+
 		// Try direct conversion first
 		if sourceElem.Type().ConvertibleTo(elemType) {
 			targetElem.Set(sourceElem.Convert(elemType))
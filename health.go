@@ -0,0 +1,255 @@
+package modular
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Conventional paths for mounting the health handlers.
+const (
+	// LivenessPath is the conventional path for mounting NewLivenessHandler
+	LivenessPath = "/__health/live"
+	// ReadinessPath is the conventional path for mounting NewReadinessHandler
+	ReadinessPath = "/__health/ready"
+)
+
+// HealthServiceName is the name of the HealthService every StdApplication registers.
+const HealthServiceName = "app.health"
+
+// DefaultHealthCheckTimeout bounds health checks run with a context without a deadline.
+const DefaultHealthCheckTimeout = 5 * time.Second
+
+// HealthStatus is the health of a module or of the application.
+type HealthStatus string
+
+const (
+	// HealthStatusHealthy means fully operational
+	HealthStatusHealthy HealthStatus = "healthy"
+	// HealthStatusDegraded means operational with reduced capacity, such as a cache
+	// serving without its backing store. Degraded applications remain ready.
+	HealthStatusDegraded HealthStatus = "degraded"
+	// HealthStatusUnhealthy means unable to serve
+	HealthStatusUnhealthy HealthStatus = "unhealthy"
+)
+
+// severity orders statuses from healthy to unhealthy.
+func (s HealthStatus) severity() int {
+	switch s {
+	case HealthStatusHealthy:
+		return 0
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// HealthReport is a module's report of its health.
+type HealthReport struct {
+	Status HealthStatus `json:"status"`
+	// Message explains a status other than healthy
+	Message string `json:"message,omitempty"`
+	// Details are module-specific diagnostics, such as connection pool usage
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// HealthReporter is implemented by modules that can report their health, such as
+// whether a database answers or a broker is connected. The HealthService aggregates
+// the reports of every registered module into the application's readiness.
+//
+// HealthCheck should return within the context's deadline; reports that don't are
+// counted as unhealthy.
+type HealthReporter interface {
+	HealthCheck(ctx context.Context) HealthReport
+}
+
+// HealthStatusReporter is HealthReporter with standard library types only, for
+// modules built against modular releases without HealthReport. The status is a
+// HealthStatus value, such as "healthy"; any other value counts as unhealthy.
+// Modules implementing HealthReporter are checked through it instead.
+type HealthStatusReporter interface {
+	HealthCheckStatus(ctx context.Context) (status, message string, details map[string]any)
+}
+
+// LivenessReporter is implemented by modules that can detect they are broken beyond
+// recovery, such as a deadlocked worker, so that the process should be restarted.
+// Liveness only considers these modules: a module whose dependency is down is not
+// ready, but restarting the process would not help it.
+type LivenessReporter interface {
+	LivenessCheck(ctx context.Context) HealthReport
+}
+
+// HealthResult is the aggregated health of the application.
+type HealthResult struct {
+	// Status is the worst status reported
+	Status HealthStatus `json:"status"`
+	// Modules are the reports of the modules that were checked, by module name
+	Modules map[string]HealthReport `json:"modules"`
+	// Message explains an unhealthy status not caused by a module
+	Message   string    `json:"message,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthService reports the aggregated readiness and liveness of the application's
+// modules. StdApplication and ObservableApplication implement it, and register a
+// HealthService as the HealthServiceName service so modules such as httpserver can
+// mount NewReadinessHandler and NewLivenessHandler.
+type HealthService interface {
+	// Readiness checks every HealthReporter and HealthStatusReporter module. The application is not ready
+	// before it has started or once it stops.
+	Readiness(ctx context.Context) HealthResult
+
+	// Liveness checks every LivenessReporter module
+	Liveness(ctx context.Context) HealthResult
+}
+
+var (
+	_ HealthService = (*StdApplication)(nil)
+	_ HealthService = (*ObservableApplication)(nil)
+)
+
+// Readiness checks the health of every module implementing HealthReporter or
+// HealthStatusReporter.
+func (app *StdApplication) Readiness(ctx context.Context) HealthResult {
+	checks := make(map[string]func(context.Context) HealthReport)
+	for name, module := range app.GetAllModules() {
+		switch reporter := module.(type) {
+		case HealthReporter:
+			checks[name] = reporter.HealthCheck
+		case HealthStatusReporter:
+			checks[name] = func(ctx context.Context) HealthReport {
+				status, message, details := reporter.HealthCheckStatus(ctx)
+				report := HealthReport{Status: HealthStatus(status), Message: message, Details: details}
+				switch report.Status {
+				case "", HealthStatusHealthy, HealthStatusDegraded:
+				default:
+					report.Status = HealthStatusUnhealthy
+				}
+				return report
+			}
+		}
+	}
+	result := runHealthChecks(ctx, checks)
+	if app.startTime.IsZero() || app.ctx == nil || app.ctx.Err() != nil {
+		result.Status = HealthStatusUnhealthy
+		result.Message = "application is not running"
+	}
+	return result
+}
+
+// Liveness checks the health of every module implementing LivenessReporter.
+func (app *StdApplication) Liveness(ctx context.Context) HealthResult {
+	checks := make(map[string]func(context.Context) HealthReport)
+	for name, module := range app.GetAllModules() {
+		if reporter, ok := module.(LivenessReporter); ok {
+			checks[name] = reporter.LivenessCheck
+		}
+	}
+	return runHealthChecks(ctx, checks)
+}
+
+// HealthFor returns the health service of app. Applications that do not implement
+// HealthService get nil.
+func HealthFor(app Application) HealthService {
+	if service, ok := app.(HealthService); ok {
+		return service
+	}
+	return nil
+}
+
+// runHealthChecks runs checks concurrently and aggregates their reports. Checks that
+// panic or outlive the context are reported unhealthy.
+func runHealthChecks(ctx context.Context, checks map[string]func(context.Context) HealthReport) HealthResult {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultHealthCheckTimeout)
+		defer cancel()
+	}
+
+	type namedReport struct {
+		name   string
+		report HealthReport
+	}
+	reports := make(chan namedReport, len(checks))
+	for name, check := range checks {
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					reports <- namedReport{name, HealthReport{Status: HealthStatusUnhealthy, Message: fmt.Sprintf("health check panicked: %v", r)}}
+				}
+			}()
+			report := check(ctx)
+			if report.Status == "" {
+				report.Status = HealthStatusHealthy
+			}
+			reports <- namedReport{name, report}
+		}()
+	}
+
+	result := HealthResult{Status: HealthStatusHealthy, Modules: make(map[string]HealthReport, len(checks))}
+collect:
+	for range checks {
+		select {
+		case r := <-reports:
+			result.Modules[r.name] = r.report
+		case <-ctx.Done():
+			break collect
+		}
+	}
+	for name := range checks {
+		if _, ok := result.Modules[name]; !ok {
+			result.Modules[name] = HealthReport{Status: HealthStatusUnhealthy, Message: "health check timed out"}
+		}
+	}
+	for _, report := range result.Modules {
+		if report.Status.severity() > result.Status.severity() {
+			result.Status = report.Status
+		}
+	}
+	result.CheckedAt = time.Now()
+	return result
+}
+
+// healthService is the HealthServiceName service. It only exposes the HealthService
+// methods, so interface-based service matching can't mistake the application for
+// another service.
+type healthService struct {
+	app *StdApplication
+}
+
+func (s healthService) Readiness(ctx context.Context) HealthResult {
+	return s.app.Readiness(ctx)
+}
+
+func (s healthService) Liveness(ctx context.Context) HealthResult {
+	return s.app.Liveness(ctx)
+}
+
+// NewReadinessHandler serves the service's readiness as JSON, with status 503 when the
+// application is unhealthy. Mount it at ReadinessPath:
+//
+//	router.Handle(modular.ReadinessPath, modular.NewReadinessHandler(health))
+func NewReadinessHandler(service HealthService) http.Handler {
+	return newHealthHandler(service.Readiness)
+}
+
+// NewLivenessHandler serves the service's liveness as JSON, with status 503 when a
+// module reports it is unhealthy. Mount it at LivenessPath.
+func NewLivenessHandler(service HealthService) http.Handler {
+	return newHealthHandler(service.Liveness)
+}
+
+func newHealthHandler(check func(context.Context) HealthResult) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		result := check(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		if result.Status == HealthStatusUnhealthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	})
+}
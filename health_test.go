@@ -0,0 +1,183 @@
+package modular
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// healthTestModule reports the health it is given.
+type healthTestModule struct {
+	testModule
+	report   HealthReport
+	liveness *HealthReport
+	block    bool
+}
+
+func (m *healthTestModule) HealthCheck(ctx context.Context) HealthReport {
+	if m.block {
+		<-ctx.Done()
+	}
+	return m.report
+}
+
+// livenessTestModule also reports its liveness.
+type livenessTestModule struct {
+	healthTestModule
+}
+
+func (m *livenessTestModule) LivenessCheck(context.Context) HealthReport {
+	return *m.liveness
+}
+
+type panickingHealthModule struct {
+	testModule
+}
+
+func (panickingHealthModule) HealthCheck(context.Context) HealthReport {
+	panic("boom")
+}
+
+func TestHealthService_Readiness(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	database := &healthTestModule{testModule: testModule{name: "database"}, report: HealthReport{Status: HealthStatusHealthy}}
+	cache := &healthTestModule{testModule: testModule{name: "cache"}, report: HealthReport{
+		Status:  HealthStatusDegraded,
+		Message: "redis unavailable, serving from memory",
+	}}
+	app.RegisterModule(database)
+	app.RegisterModule(cache)
+	app.RegisterModule(&lifecycleTestModule{testModule: testModule{name: "plain"}})
+	require.NoError(t, app.Init())
+
+	var health HealthService
+	require.NoError(t, app.GetService(HealthServiceName, &health))
+
+	result := health.Readiness(context.Background())
+	assert.Equal(t, HealthStatusUnhealthy, result.Status, "not ready before the application starts")
+	assert.Equal(t, "application is not running", result.Message)
+
+	require.NoError(t, app.Start())
+	result = health.Readiness(context.Background())
+	assert.Equal(t, HealthStatusDegraded, result.Status, "the worst module status wins")
+	require.Len(t, result.Modules, 2, "modules without HealthCheck are not reported")
+	assert.Equal(t, "redis unavailable, serving from memory", result.Modules["cache"].Message)
+	assert.False(t, result.CheckedAt.IsZero())
+
+	database.report = HealthReport{Status: HealthStatusUnhealthy, Message: "connection refused"}
+	assert.Equal(t, HealthStatusUnhealthy, health.Readiness(context.Background()).Status)
+
+	require.NoError(t, app.Stop())
+	database.report = HealthReport{}
+	result = health.Readiness(context.Background())
+	assert.Equal(t, HealthStatusUnhealthy, result.Status, "not ready once stopped")
+	assert.Equal(t, HealthStatusHealthy, result.Modules["database"].Status, "an empty status counts as healthy")
+}
+
+// statusTestModule reports its health with standard library types only.
+type statusTestModule struct {
+	testModule
+	status string
+}
+
+func (m *statusTestModule) HealthCheckStatus(context.Context) (string, string, map[string]any) {
+	return m.status, "checked " + m.name, map[string]any{"checks": 2}
+}
+
+func TestHealthService_ReadinessOfHealthStatusReporters(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	pinger := &statusTestModule{testModule: testModule{name: "pinger"}, status: "degraded"}
+	app.RegisterModule(pinger)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	defer func() { require.NoError(t, app.Stop()) }()
+
+	result := HealthFor(app).Readiness(context.Background())
+	assert.Equal(t, HealthStatusDegraded, result.Status)
+	assert.Equal(t, HealthReport{Status: HealthStatusDegraded, Message: "checked pinger", Details: map[string]any{"checks": 2}}, result.Modules["pinger"])
+
+	pinger.status = "broken"
+	assert.Equal(t, HealthStatusUnhealthy, HealthFor(app).Readiness(context.Background()).Status, "unknown statuses count as unhealthy")
+}
+
+func TestHealthService_Liveness(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	worker := &livenessTestModule{healthTestModule{
+		testModule: testModule{name: "worker"},
+		report:     HealthReport{Status: HealthStatusUnhealthy, Message: "queue unreachable"},
+		liveness:   &HealthReport{Status: HealthStatusHealthy},
+	}}
+	app.RegisterModule(worker)
+	app.RegisterModule(&healthTestModule{testModule: testModule{name: "database"}, report: HealthReport{Status: HealthStatusUnhealthy}})
+
+	health := HealthFor(app)
+	require.NotNil(t, health)
+	result := health.Liveness(context.Background())
+	assert.Equal(t, HealthStatusHealthy, result.Status, "unready dependencies don't fail liveness")
+	assert.Equal(t, []string{"worker"}, keys(result.Modules))
+
+	worker.liveness = &HealthReport{Status: HealthStatusUnhealthy, Message: "deadlocked"}
+	assert.Equal(t, HealthStatusUnhealthy, health.Liveness(context.Background()).Status)
+}
+
+func TestRunHealthChecks_TimeoutsAndPanics(t *testing.T) {
+	slow := &healthTestModule{block: true, report: HealthReport{Status: HealthStatusHealthy}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	result := runHealthChecks(ctx, map[string]func(context.Context) HealthReport{
+		"slow":  slow.HealthCheck,
+		"panic": panickingHealthModule{}.HealthCheck,
+	})
+	assert.Equal(t, HealthStatusUnhealthy, result.Status)
+	assert.Equal(t, "health check timed out", result.Modules["slow"].Message)
+	assert.Equal(t, "health check panicked: boom", result.Modules["panic"].Message)
+
+	result = runHealthChecks(context.Background(), nil)
+	assert.Equal(t, HealthStatusHealthy, result.Status, "an application without checks is healthy")
+}
+
+func TestHealthHandlers(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterModule(&healthTestModule{testModule: testModule{name: "database"}, report: HealthReport{
+		Status:  HealthStatusHealthy,
+		Details: map[string]any{"open_connections": 3},
+	}})
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+	health := HealthFor(app)
+
+	rec := httptest.NewRecorder()
+	NewReadinessHandler(health).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var served map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, "healthy", served["status"])
+	database := served["modules"].(map[string]any)["database"].(map[string]any)
+	assert.Equal(t, float64(3), database["details"].(map[string]any)["open_connections"])
+
+	rec = httptest.NewRecorder()
+	NewLivenessHandler(health).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	require.NoError(t, app.Stop())
+	rec = httptest.NewRecorder()
+	NewReadinessHandler(health).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func keys(reports map[string]HealthReport) []string {
+	names := make([]string, 0, len(reports))
+	for name := range reports {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,311 @@
+package modular
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LazyInitAware is an optional interface for modules that ask to be initialized on
+// first use rather than during Init. Annotations set with SetLazyInit take
+// precedence.
+type LazyInitAware interface {
+	LazyInit() bool
+}
+
+// LazyModuleInitializer is implemented by applications that can initialize lazy
+// modules on demand. StdApplication and ObservableApplication implement it.
+type LazyModuleInitializer interface {
+	// InitLazyModule initializes the named lazy module if it hasn't been, see
+	// StdApplication.InitLazyModule.
+	InitLazyModule(name string) error
+}
+
+var (
+	_ LazyModuleInitializer = (*StdApplication)(nil)
+	_ LazyModuleInitializer = (*ObservableApplication)(nil)
+)
+
+// lazyModule tracks the initialization of a lazy module.
+type lazyModule struct {
+	name string
+
+	mu      sync.Mutex
+	done    atomic.Bool // set once initialization finished, successfully or not
+	err     error
+	started atomic.Bool // set once initialization started the module
+
+	// services are the instances of the module's services by the name it provides
+	// them under, set once it initialized
+	services map[string]any
+	// registered holds the names the placeholders of those services were registered
+	// under, which differ from the provided names when a conflict policy renamed them
+	registered map[string]string
+}
+
+// failure returns the error initialization finished with, if it finished.
+func (l *lazyModule) failure() error {
+	if !l.done.Load() {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.err
+}
+
+// lazyService stands in the service registry for a service of a lazy module that
+// may not have initialized yet.
+type lazyService struct {
+	module *lazyModule
+	name   string
+	// declared is the instance the module listed before Init, used to match the
+	// service against interfaces until the module initializes
+	declared any
+}
+
+// instance returns the service instance once the module initialized.
+func (s *lazyService) instance() (any, bool) {
+	if !s.module.done.Load() {
+		return nil, false
+	}
+	instance, ok := s.module.services[s.name]
+	return instance, ok
+}
+
+// registeredType returns the type a registered service is matched against
+// interfaces with.
+func registeredType(service any) reflect.Type {
+	if placeholder, ok := service.(*lazyService); ok {
+		if instance, ok := placeholder.instance(); ok {
+			return reflect.TypeOf(instance)
+		}
+		return reflect.TypeOf(placeholder.declared)
+	}
+	return reflect.TypeOf(service)
+}
+
+// SetLazyInit marks the named module to be initialized on first use, or not,
+// overriding the module's own LazyInitAware declaration.
+//
+// Init still registers a lazy module's config and constructs it, but defers its
+// Init until one of its services is looked up with GetService, injected into
+// another module or found by interface, until a module depending on it
+// initializes, or until InitLazyModule is called. That keeps expensive modules such
+// as databases and caches from slowing down invocations that don't use them.
+//
+// Its services are registered during Init under the names ProvidesServices
+// returns before the module initializes, so that method must list them up front.
+// A lazy module initializing after Start is started right away. Modules
+// implementing Worker are always initialized during Init.
+func (app *StdApplication) SetLazyInit(moduleName string, lazy bool) {
+	if app.lazyInit == nil {
+		app.lazyInit = make(map[string]bool)
+	}
+	app.lazyInit[moduleName] = lazy
+}
+
+// isLazy reports whether the named module is initialized on first use.
+func (app *StdApplication) isLazy(name string, module Module) bool {
+	if _, ok := module.(Worker); ok {
+		return false
+	}
+	if lazy, ok := app.lazyInit[name]; ok {
+		return lazy
+	}
+	aware, ok := module.(LazyInitAware)
+	return ok && aware.LazyInit()
+}
+
+// pendingLazy reports whether the named module is lazy and hasn't initialized
+// successfully.
+func (app *StdApplication) pendingLazy(name string) bool {
+	lazy, ok := app.lazyModules[name]
+	return ok && (!lazy.done.Load() || lazy.failure() != nil)
+}
+
+// lazyStarted reports whether the named module is lazy and was started when it
+// initialized, so Start must not start it again.
+func (app *StdApplication) lazyStarted(name string) bool {
+	lazy, ok := app.lazyModules[name]
+	return ok && lazy.started.Load()
+}
+
+// InitLazyModule initializes the named lazy module now if it hasn't been, for
+// example to warm it up in the background once the application started. Each lazy
+// module initializes once: concurrent callers wait for it, and a failed
+// initialization is returned to every later caller and service lookup. It returns
+// nil for modules that are not lazy and ErrModuleNotFound for unknown ones.
+func (app *StdApplication) InitLazyModule(name string) error {
+	if app.GetModule(name) == nil {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+	lazy, ok := app.lazyModules[name]
+	if !ok {
+		return nil
+	}
+	return app.initLazy(lazy)
+}
+
+// deferInit registers placeholders for the services of a lazy module, to initialize
+// it when they are first used.
+func (app *StdApplication) deferInit(name string, module Module) error {
+	lazy := &lazyModule{name: name, services: make(map[string]any), registered: make(map[string]string)}
+	if app.lazyModules == nil {
+		app.lazyModules = make(map[string]*lazyModule)
+	}
+	app.lazyModules[name] = lazy
+
+	aware, ok := module.(ServiceAware)
+	if !ok {
+		return nil
+	}
+	for _, svc := range aware.ProvidesServices() {
+		var opts []ServiceRegistrationOption
+		if svc.AllowOverride {
+			opts = append(opts, AllowOverride())
+		}
+		placeholder := &lazyService{module: lazy, name: svc.Name, declared: svc.Instance}
+		actualName, err := app.registerService(module, svc.Name, placeholder, opts...)
+		if err != nil {
+			return fmt.Errorf("module '%s' failed to register service '%s': %w", name, svc.Name, err)
+		}
+		lazy.registered[svc.Name] = actualName
+	}
+	app.logger.Debug("Deferred initialization of lazy module", "module", name)
+	return nil
+}
+
+// initLazyDependencies initializes the lazy modules the module depends on by name.
+func (app *StdApplication) initLazyDependencies(module Module) error {
+	aware, ok := module.(DependencyAware)
+	if !ok {
+		return nil
+	}
+	for _, dependency := range aware.Dependencies() {
+		if lazy, ok := app.lazyModules[dependency]; ok {
+			if err := app.initLazy(lazy); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// initLazy initializes a lazy module once, returning the error of the first
+// attempt to every caller.
+func (app *StdApplication) initLazy(lazy *lazyModule) error {
+	if lazy.done.Load() {
+		return lazy.failure()
+	}
+	lazy.mu.Lock()
+	defer lazy.mu.Unlock()
+	if lazy.done.Load() {
+		return lazy.err
+	}
+	if err := app.initLazyModule(lazy); err != nil {
+		lazy.err = fmt.Errorf("%w: %w", ErrLazyModuleInitFailed, err)
+		app.logger.Error("Lazy module failed to initialize", "module", lazy.name, "error", err)
+	}
+	lazy.done.Store(true)
+	return lazy.err
+}
+
+// initLazyModule initializes a lazy module the way Init initializes the others,
+// and starts it when the application is running.
+func (app *StdApplication) initLazyModule(lazy *lazyModule) error {
+	name := lazy.name
+	module := app.GetModule(name)
+	if err := app.initLazyDependencies(module); err != nil {
+		return err
+	}
+	if _, ok := module.(ServiceAware); ok {
+		// Constructable modules had their services injected when constructed
+		if _, constructed := module.(Constructable); !constructed {
+			if _, err := app.injectServices(module); err != nil {
+				return fmt.Errorf("failed to inject services for module '%s': %w", name, err)
+			}
+		}
+	}
+
+	// A module may initialize while another one does, so the current module of
+	// the registry is restored afterwards
+	previous := app.setCurrentModule(module)
+	defer app.setCurrentModule(previous)
+	moduleStart := time.Now()
+	err := app.initModule(name, module, app.initApp, false)
+	app.recordLifecycleDuration("init", name, moduleStart, err)
+	if err != nil {
+		return fmt.Errorf("module '%s' failed to initialize: %w", name, err)
+	}
+
+	if aware, ok := module.(ServiceAware); ok {
+		for _, svc := range aware.ProvidesServices() {
+			if actualName, ok := lazy.registered[svc.Name]; ok {
+				if placeholder, ok := app.services()[actualName].(*lazyService); ok && placeholder.module == lazy {
+					lazy.services[svc.Name] = svc.Instance
+					continue
+				}
+			}
+			var opts []ServiceRegistrationOption
+			if svc.AllowOverride {
+				opts = append(opts, AllowOverride())
+			}
+			if _, err := app.registerService(module, svc.Name, svc.Instance, opts...); err != nil {
+				return fmt.Errorf("module '%s' failed to register service '%s': %w", name, svc.Name, err)
+			}
+		}
+	}
+
+	if tenantAware, ok := module.(TenantAwareModule); ok && app.tenantService != nil {
+		if err := app.tenantService.RegisterTenantAwareModule(tenantAware); err != nil {
+			app.logger.Warn("Failed to register tenant-aware module", "module", name, "error", err)
+		}
+	}
+	app.logger.Info(fmt.Sprintf("Initialized lazy module %s of type %T", name, module))
+
+	if startable, ok := module.(Startable); ok && app.ctx != nil && app.ctx.Err() == nil {
+		app.logger.Info("Starting module", "module", name)
+		startedAt := time.Now()
+		err := app.startModule(app.ctx, name, startable, false)
+		app.recordLifecycleDuration("start", name, startedAt, err)
+		if err != nil {
+			return fmt.Errorf("failed to start module %s: %w", name, err)
+		}
+		lazy.started.Store(true)
+	}
+	return nil
+}
+
+// resolveService returns the instance a registered service stands for,
+// initializing the lazy module providing it on first use.
+func (app *StdApplication) resolveService(name string, service any) (any, error) {
+	placeholder, ok := service.(*lazyService)
+	if !ok {
+		return service, nil
+	}
+	if err := app.initLazy(placeholder.module); err != nil {
+		return nil, fmt.Errorf("service '%s': %w", name, err)
+	}
+	instance, ok := placeholder.instance()
+	if !ok {
+		return nil, fmt.Errorf("%w: module '%s' did not provide service '%s' once initialized",
+			ErrLazyModuleInitFailed, placeholder.module.name, name)
+	}
+	return instance, nil
+}
+
+// resolveEntry returns the entry with the instance its service stands for.
+func (app *StdApplication) resolveEntry(entry *ServiceRegistryEntry) (*ServiceRegistryEntry, error) {
+	if _, ok := entry.Service.(*lazyService); !ok {
+		return entry, nil
+	}
+	instance, err := app.resolveService(entry.ActualName, entry.Service)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *entry
+	resolved.Service = instance
+	return &resolved, nil
+}
@@ -0,0 +1,213 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lazyStore is the service provided by lazyTestModule.
+type lazyStore interface {
+	Value() string
+}
+
+// lazyTestModule counts its lifecycle calls and provides itself as a lazyStore.
+type lazyTestModule struct {
+	name    string
+	deps    []string
+	lazy    bool
+	initErr error
+
+	inits, starts, stops atomic.Int32
+	value                string
+}
+
+func (m *lazyTestModule) Name() string           { return m.name }
+func (m *lazyTestModule) Dependencies() []string { return m.deps }
+func (m *lazyTestModule) LazyInit() bool         { return m.lazy }
+func (m *lazyTestModule) Value() string          { return m.value }
+
+func (m *lazyTestModule) Init(Application) error {
+	m.inits.Add(1)
+	time.Sleep(10 * time.Millisecond) // an expensive connection
+	if m.initErr != nil {
+		return m.initErr
+	}
+	m.value = m.name + " ready"
+	return nil
+}
+
+func (m *lazyTestModule) Start(context.Context) error {
+	m.starts.Add(1)
+	return nil
+}
+
+func (m *lazyTestModule) Stop(context.Context) error {
+	m.stops.Add(1)
+	return nil
+}
+
+func (m *lazyTestModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{{Name: m.name + ".store", Instance: m}}
+}
+
+func (m *lazyTestModule) RequiresServices() []ServiceDependency {
+	return nil
+}
+
+func newLazyTestApp(modules ...Module) *StdApplication {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	for _, module := range modules {
+		app.RegisterModule(module)
+	}
+	return app
+}
+
+func TestLazyInit_OnFirstServiceLookup(t *testing.T) {
+	db := &lazyTestModule{name: "db", lazy: true}
+	app := newLazyTestApp(db)
+	require.NoError(t, app.Init())
+	assert.Zero(t, db.inits.Load(), "lazy modules don't initialize during Init")
+	assert.Equal(t, []string{"db.store"}, app.GetServicesByModule("db"))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var store lazyStore
+			if assert.NoError(t, app.GetService("db.store", &store)) {
+				assert.Equal(t, "db ready", store.Value())
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), db.inits.Load(), "concurrent lookups initialize the module once")
+
+	entry, ok := app.GetServiceEntry("db.store")
+	require.True(t, ok)
+	assert.Same(t, db, entry.Service)
+	assert.Equal(t, "db", entry.ModuleName)
+}
+
+func TestLazyInit_RenamedService(t *testing.T) {
+	db := &lazyTestModule{name: "db", lazy: true}
+	app := newLazyTestApp(db)
+	existing := &lazyTestModule{name: "existing", value: "existing"}
+	require.NoError(t, app.RegisterService("db.store", existing))
+	require.NoError(t, app.Init())
+
+	names := app.GetServicesByModule("db")
+	require.Len(t, names, 1)
+	require.NotEqual(t, "db.store", names[0], "the conflicting service is registered under a derived name")
+
+	var store lazyStore
+	require.NoError(t, app.GetService(names[0], &store))
+	assert.Equal(t, "db ready", store.Value())
+	assert.Equal(t, names, app.GetServicesByModule("db"), "initializing doesn't register the service again")
+
+	require.NoError(t, app.GetService("db.store", &store))
+	assert.Same(t, existing, store)
+}
+
+func TestLazyInit_FailurePropagates(t *testing.T) {
+	errConnect := errors.New("connection refused")
+	db := &lazyTestModule{name: "db", lazy: true, initErr: errConnect}
+	app := newLazyTestApp(db)
+	require.NoError(t, app.Init())
+
+	var store lazyStore
+	err := app.GetService("db.store", &store)
+	require.ErrorIs(t, err, ErrLazyModuleInitFailed)
+	require.ErrorIs(t, err, errConnect)
+	assert.Nil(t, store)
+
+	assert.ErrorIs(t, app.GetService("db.store", &store), errConnect, "the failure is returned again")
+	assert.ErrorIs(t, app.InitLazyModule("db"), errConnect)
+	assert.Equal(t, int32(1), db.inits.Load(), "a failed initialization is not retried")
+	assert.Empty(t, app.GetServicesByInterface(reflect.TypeOf((*lazyStore)(nil)).Elem()))
+}
+
+func TestLazyInit_Dependencies(t *testing.T) {
+	db := &lazyTestModule{name: "db", lazy: true}
+	cache := &lazyTestModule{name: "cache", lazy: true}
+	api := &lazyTestModule{name: "api", deps: []string{"db"}}
+	app := newLazyTestApp(db, cache, api)
+
+	require.NoError(t, app.Init())
+	assert.Equal(t, int32(1), db.inits.Load(), "a module depending on a lazy one initializes it")
+	assert.Zero(t, cache.inits.Load())
+
+	require.NoError(t, app.Start())
+	assert.Equal(t, int32(1), db.starts.Load())
+	assert.Zero(t, cache.starts.Load(), "lazy modules not initialized are not started")
+
+	// Found by interface after Start, the cache initializes and starts right away
+	entries := app.GetServicesByInterface(reflect.TypeOf((*lazyStore)(nil)).Elem())
+	assert.Len(t, entries, 3)
+	assert.Equal(t, int32(1), cache.inits.Load())
+	assert.Equal(t, int32(1), cache.starts.Load())
+	assert.Equal(t, "cache ready", cache.Value())
+
+	require.NoError(t, app.Stop())
+	assert.Equal(t, int32(1), cache.stops.Load())
+	assert.Equal(t, int32(1), db.stops.Load())
+}
+
+func TestLazyInit_StartsOnceWhenResolvedDuringStart(t *testing.T) {
+	db := &lazyTestModule{name: "db", lazy: true}
+	app := newLazyTestApp(db)
+	app.OnLifecycle(PhasePreStart, func(_ context.Context, app Application) error {
+		var store lazyStore
+		return app.GetService("db.store", &store)
+	})
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	assert.Equal(t, int32(1), db.inits.Load())
+	assert.Equal(t, int32(1), db.starts.Load(), "a lazy module started when it initialized is not started again")
+
+	require.NoError(t, app.Stop())
+	assert.Equal(t, int32(1), db.stops.Load())
+}
+
+func TestLazyInit_Trigger(t *testing.T) {
+	db := &lazyTestModule{name: "db"}
+	cache := &lazyTestModule{name: "cache", lazy: true}
+	app := newLazyTestApp(db, cache)
+	app.SetLazyInit("db", true)
+	app.SetLazyInit("cache", false)
+
+	require.NoError(t, app.Init())
+	assert.Zero(t, db.inits.Load(), "annotations make modules lazy")
+	assert.Equal(t, int32(1), cache.inits.Load(), "annotations override the module's declaration")
+
+	require.NoError(t, app.InitLazyModule("db"))
+	require.NoError(t, app.InitLazyModule("db"))
+	assert.Equal(t, int32(1), db.inits.Load())
+	assert.NoError(t, app.InitLazyModule("cache"), "modules that are not lazy are already initialized")
+	assert.ErrorIs(t, app.InitLazyModule("missing"), ErrModuleNotFound)
+}
+
+func TestWithLazyInit(t *testing.T) {
+	db := &lazyTestModule{name: "db"}
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithModules(db),
+		WithLazyInit("db"),
+	)
+	require.NoError(t, err)
+	require.NoError(t, app.Init())
+	assert.Zero(t, db.inits.Load())
+
+	var store lazyStore
+	require.NoError(t, app.GetService("db.store", &store))
+	assert.Equal(t, "db ready", store.Value())
+}
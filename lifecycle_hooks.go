@@ -0,0 +1,110 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// LifecyclePhase identifies a point in the application lifecycle where hooks run.
+type LifecyclePhase int
+
+const (
+	// PhasePreInit runs at the start of Init, before modules register their config.
+	// An error aborts Init.
+	PhasePreInit LifecyclePhase = iota
+	// PhasePostInit runs at the end of Init, once every module is initialized and
+	// tenants are loaded. It only runs when initialization succeeded, and an error
+	// fails Init.
+	PhasePostInit
+	// PhasePreStart runs at the start of Start, before any module starts. An error
+	// aborts Start.
+	PhasePreStart
+	// PhasePostStart runs at the end of Start, once every module and worker has
+	// started, for example to register with service discovery. An error fails Start;
+	// the started modules keep running until Stop.
+	PhasePostStart
+	// PhasePreStop runs at the start of Stop, before any module stops, for example to
+	// deregister from service discovery. Errors don't prevent the shutdown.
+	PhasePreStop
+	// PhasePostStop runs at the end of Stop, once every module has stopped. Errors
+	// are reported by Stop.
+	PhasePostStop
+)
+
+// String returns the phase name.
+func (p LifecyclePhase) String() string {
+	switch p {
+	case PhasePreInit:
+		return "pre-init"
+	case PhasePostInit:
+		return "post-init"
+	case PhasePreStart:
+		return "pre-start"
+	case PhasePostStart:
+		return "post-start"
+	case PhasePreStop:
+		return "pre-stop"
+	case PhasePostStop:
+		return "post-stop"
+	default:
+		return "phase(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// LifecycleHook is a callback run at a lifecycle phase. The context is cancelled
+// when the application stops during the start phases, and carries the shutdown
+// timeout during the stop phases.
+type LifecycleHook func(ctx context.Context, app Application) error
+
+// OnLifecycle registers hooks to run at phase. Hooks of a phase run in
+// registration order. In the init and start phases the first failing hook stops
+// the phase and its error is returned by Init or Start; in the stop phases every
+// hook runs and their errors are returned together by Stop.
+//
+// Config loaded hooks (see OnConfigLoaded) run between PhasePreInit and the
+// initialization of the modules.
+func (app *StdApplication) OnLifecycle(phase LifecyclePhase, hooks ...LifecycleHook) {
+	if app.lifecycleHooks == nil {
+		app.lifecycleHooks = make(map[LifecyclePhase][]LifecycleHook)
+	}
+	for _, hook := range hooks {
+		if hook != nil {
+			app.lifecycleHooks[phase] = append(app.lifecycleHooks[phase], hook)
+		}
+	}
+}
+
+// runLifecycleHooks runs the hooks registered for phase. Stop phases run every
+// hook and join their errors; other phases return the first error.
+func (app *StdApplication) runLifecycleHooks(ctx context.Context, phase LifecyclePhase) error {
+	hooks := app.lifecycleHooks[phase]
+	if len(hooks) == 0 {
+		return nil
+	}
+	if app.logger != nil {
+		app.logger.Debug("Executing lifecycle hooks", "phase", phase.String(), "count", len(hooks))
+	}
+
+	hookApp := app.initApp
+	if hookApp == nil {
+		hookApp = app
+	}
+	continueOnError := phase == PhasePreStop || phase == PhasePostStop
+
+	var errs []error
+	for i, hook := range hooks {
+		if err := hook(ctx, hookApp); err != nil {
+			err = fmt.Errorf("%s hook %d failed: %w", phase, i, err)
+			if !continueOnError {
+				return err
+			}
+			if app.logger != nil {
+				app.logger.Error("Lifecycle hook failed", "phase", phase.String(), "hook", i, "error", err)
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lifecycleRecorder records module calls and hook runs in order.
+type lifecycleRecorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *lifecycleRecorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *lifecycleRecorder) hook(call string) LifecycleHook {
+	return func(context.Context, Application) error {
+		r.record(call)
+		return nil
+	}
+}
+
+// lifecycleRecordingModule records its Init, Start and Stop calls.
+type lifecycleRecordingModule struct {
+	testModule
+	recorder *lifecycleRecorder
+}
+
+func (m lifecycleRecordingModule) Init(Application) error {
+	m.recorder.record("module.init")
+	return nil
+}
+
+func (m lifecycleRecordingModule) Start(context.Context) error {
+	m.recorder.record("module.start")
+	return nil
+}
+
+func (m lifecycleRecordingModule) Stop(context.Context) error {
+	m.recorder.record("module.stop")
+	return nil
+}
+
+func newLifecycleTestApp(recorder *lifecycleRecorder) *StdApplication {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	app.RegisterModule(lifecycleRecordingModule{testModule: testModule{name: "recorder"}, recorder: recorder})
+	return app
+}
+
+func TestLifecycleHooks_RunAroundModules(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app := newLifecycleTestApp(recorder)
+	for _, phase := range []LifecyclePhase{PhasePreInit, PhasePostInit, PhasePreStart, PhasePostStart, PhasePreStop, PhasePostStop} {
+		app.OnLifecycle(phase, recorder.hook(phase.String()+".1"), recorder.hook(phase.String()+".2"))
+	}
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	assert.Equal(t, []string{
+		"pre-init.1", "pre-init.2", "module.init", "post-init.1", "post-init.2",
+		"pre-start.1", "pre-start.2", "module.start", "post-start.1", "post-start.2",
+		"pre-stop.1", "pre-stop.2", "module.stop", "post-stop.1", "post-stop.2",
+	}, recorder.calls)
+}
+
+func TestLifecycleHooks_PreInitErrorAbortsInit(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app := newLifecycleTestApp(recorder)
+	hookErr := errors.New("secrets unavailable")
+	app.OnLifecycle(PhasePreInit, func(context.Context, Application) error { return hookErr })
+	app.OnLifecycle(PhasePreInit, recorder.hook("pre-init.2"))
+	app.OnLifecycle(PhasePostInit, recorder.hook("post-init"))
+
+	err := app.Init()
+	require.ErrorIs(t, err, hookErr)
+	assert.Contains(t, err.Error(), "pre-init hook 0 failed")
+	assert.Empty(t, recorder.calls, "neither later hooks nor modules should run")
+}
+
+func TestLifecycleHooks_PostStartErrorFailsStart(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app := newLifecycleTestApp(recorder)
+	hookErr := errors.New("registry unreachable")
+	app.OnLifecycle(PhasePostStart, func(context.Context, Application) error { return hookErr })
+
+	require.NoError(t, app.Init())
+	require.ErrorIs(t, app.Start(), hookErr)
+	assert.Equal(t, []string{"module.init", "module.start"}, recorder.calls)
+	require.NoError(t, app.Stop())
+}
+
+func TestLifecycleHooks_StopHookErrorsAreJoined(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app := newLifecycleTestApp(recorder)
+	preStopErr := errors.New("deregister failed")
+	postStopErr := errors.New("flush failed")
+	app.OnLifecycle(PhasePreStop,
+		func(context.Context, Application) error { return preStopErr },
+		recorder.hook("pre-stop.2"))
+	app.OnLifecycle(PhasePostStop, func(context.Context, Application) error { return postStopErr })
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	err := app.Stop()
+	require.ErrorIs(t, err, preStopErr)
+	require.ErrorIs(t, err, postStopErr)
+	assert.Equal(t, []string{"module.init", "module.start", "pre-stop.2", "module.stop"}, recorder.calls)
+}
+
+func TestLifecycleHooks_HooksReceiveApplication(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	require.NoError(t, app.RegisterService("registry", "consul"))
+
+	var registry string
+	app.OnLifecycle(PhasePostStart, func(_ context.Context, a Application) error {
+		return a.GetService("registry", &registry)
+	})
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+	assert.Equal(t, "consul", registry)
+}
+
+func TestWithLifecycleHook(t *testing.T) {
+	recorder := &lifecycleRecorder{}
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithConfigProvider(NewStdConfigProvider(&testCfg{Str: "app"})),
+		WithModules(lifecycleRecordingModule{testModule: testModule{name: "recorder"}, recorder: recorder}),
+		WithLifecycleHook(PhasePreStart, recorder.hook("pre-start")),
+		WithLifecycleHook(PhasePostStop, recorder.hook("post-stop")),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+	assert.Equal(t, []string{"module.init", "pre-start", "module.start", "module.stop", "post-stop"}, recorder.calls)
+}
+
+func TestLifecyclePhase_String(t *testing.T) {
+	assert.Equal(t, "post-start", PhasePostStart.String())
+	assert.Equal(t, "phase(42)", LifecyclePhase(42).String())
+}
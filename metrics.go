@@ -0,0 +1,423 @@
+package modular
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsRegistry is a lightweight facade for recording metrics. Modules record
+// through it instead of maintaining their own collectors, and the application decides
+// where the values go through a MetricsExporter or a pull endpoint such as
+// NewPrometheusMetricsHandler.
+//
+// Labels are given as alternating key/value pairs:
+//
+//	app.Metrics().Counter("reverseproxy.requests", "backend", "api", "status", "200").Inc()
+//
+// Instruments are created on first use and cached, so callers may look them up on
+// every call or keep them. A name is bound to the kind it was first created with;
+// asking for it as another kind returns an instrument that discards values.
+type MetricsRegistry interface {
+	// Counter returns the monotonically increasing counter with the given name and labels
+	Counter(name string, labels ...string) Counter
+
+	// Gauge returns the gauge with the given name and labels
+	Gauge(name string, labels ...string) Gauge
+
+	// Histogram returns the histogram with the given name and labels. Observations are
+	// bucketed by DefaultHistogramBuckets.
+	Histogram(name string, labels ...string) Histogram
+}
+
+// MetricsGatherer is implemented by registries that can report their current values.
+type MetricsGatherer interface {
+	// Gather returns a snapshot of every metric, sorted by name and labels
+	Gather() []MetricSnapshot
+}
+
+// MetricsAware is implemented by applications that expose a metrics registry.
+type MetricsAware interface {
+	// Metrics returns the application's metrics registry
+	Metrics() MetricsRegistry
+}
+
+// Counter is a metric that only goes up.
+type Counter interface {
+	// Inc adds one to the counter
+	Inc()
+	// Add adds delta, which must not be negative, to the counter
+	Add(delta float64)
+}
+
+// Gauge is a metric that can go up and down.
+type Gauge interface {
+	// Set replaces the gauge's value
+	Set(value float64)
+	// Add adds delta, which may be negative, to the gauge
+	Add(delta float64)
+}
+
+// Histogram samples observations such as durations or sizes into buckets.
+type Histogram interface {
+	// Observe records a single observation
+	Observe(value float64)
+}
+
+// MetricKind identifies the type of a metric.
+type MetricKind string
+
+const (
+	// MetricKindCounter is a monotonically increasing counter
+	MetricKindCounter MetricKind = "counter"
+	// MetricKindGauge is a value that can go up and down
+	MetricKindGauge MetricKind = "gauge"
+	// MetricKindHistogram is a bucketed distribution of observations
+	MetricKindHistogram MetricKind = "histogram"
+)
+
+// DefaultHistogramBuckets are the upper bounds used by histograms, suited to durations
+// in seconds.
+var DefaultHistogramBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// MetricSnapshot is the value of one metric at the time it was gathered.
+type MetricSnapshot struct {
+	Name   string
+	Kind   MetricKind
+	Labels map[string]string
+
+	// Value is the current value of counters and gauges
+	Value float64
+
+	// Count, Sum and Buckets describe histograms. Bucket counts are cumulative.
+	Count   uint64
+	Sum     float64
+	Buckets []HistogramBucket
+}
+
+// HistogramBucket is the number of observations less than or equal to UpperBound.
+type HistogramBucket struct {
+	UpperBound float64
+	Count      uint64
+}
+
+// MetricsFor returns the metrics registry of app. Applications that do not implement
+// MetricsAware get a registry that discards everything.
+func MetricsFor(app Application) MetricsRegistry {
+	if aware, ok := app.(MetricsAware); ok {
+		if registry := aware.Metrics(); registry != nil {
+			return registry
+		}
+	}
+	return NewNoopMetrics()
+}
+
+// ScopedMetrics returns a registry that prefixes every metric name with scope and a
+// dot, so a module can record "requests" and have it reported as "<module>.requests".
+func ScopedMetrics(registry MetricsRegistry, scope string) MetricsRegistry {
+	return &scopedMetrics{registry: registry, prefix: scope + "."}
+}
+
+type scopedMetrics struct {
+	registry MetricsRegistry
+	prefix   string
+}
+
+func (s *scopedMetrics) Counter(name string, labels ...string) Counter {
+	return s.registry.Counter(s.prefix+name, labels...)
+}
+
+func (s *scopedMetrics) Gauge(name string, labels ...string) Gauge {
+	return s.registry.Gauge(s.prefix+name, labels...)
+}
+
+func (s *scopedMetrics) Histogram(name string, labels ...string) Histogram {
+	return s.registry.Histogram(s.prefix+name, labels...)
+}
+
+// NewNoopMetrics returns a registry that discards every value.
+func NewNoopMetrics() MetricsRegistry {
+	return noopMetrics{}
+}
+
+type noopMetrics struct{}
+
+func (noopMetrics) Counter(string, ...string) Counter     { return noopInstrument{} }
+func (noopMetrics) Gauge(string, ...string) Gauge         { return noopInstrument{} }
+func (noopMetrics) Histogram(string, ...string) Histogram { return noopInstrument{} }
+
+type noopInstrument struct{}
+
+func (noopInstrument) Inc()            {}
+func (noopInstrument) Add(float64)     {}
+func (noopInstrument) Set(float64)     {}
+func (noopInstrument) Observe(float64) {}
+
+// NewMetricsRegistry returns an in-memory registry. It implements MetricsGatherer, so
+// its values can be served with NewPrometheusMetricsHandler or pushed by a MetricsExporter.
+func NewMetricsRegistry() MetricsRegistry {
+	return &memoryMetrics{metrics: make(map[string]*memoryMetric)}
+}
+
+type memoryMetrics struct {
+	mu      sync.RWMutex
+	metrics map[string]*memoryMetric
+	kinds   sync.Map // metric name -> MetricKind it was first created with
+}
+
+// memoryMetric is one labelled series. Counter and gauge values are float64 bits
+// updated atomically; histograms are guarded by mu.
+type memoryMetric struct {
+	name   string
+	kind   MetricKind
+	labels map[string]string
+
+	bits atomic.Uint64
+
+	mu      sync.Mutex
+	count   uint64
+	sum     float64
+	buckets []uint64 // per bucket, not cumulative
+}
+
+func (r *memoryMetrics) Counter(name string, labels ...string) Counter {
+	if metric := r.lookup(name, MetricKindCounter, labels); metric != nil {
+		return (*memoryCounter)(metric)
+	}
+	return noopInstrument{}
+}
+
+func (r *memoryMetrics) Gauge(name string, labels ...string) Gauge {
+	if metric := r.lookup(name, MetricKindGauge, labels); metric != nil {
+		return (*memoryGauge)(metric)
+	}
+	return noopInstrument{}
+}
+
+func (r *memoryMetrics) Histogram(name string, labels ...string) Histogram {
+	if metric := r.lookup(name, MetricKindHistogram, labels); metric != nil {
+		return (*memoryHistogram)(metric)
+	}
+	return noopInstrument{}
+}
+
+// lookup returns the series for name and labels, creating it on first use. It returns
+// nil when name was first created as another kind.
+func (r *memoryMetrics) lookup(name string, kind MetricKind, labels []string) *memoryMetric {
+	if existing, loaded := r.kinds.LoadOrStore(name, kind); loaded && existing.(MetricKind) != kind {
+		return nil
+	}
+
+	labelMap := labelPairs(labels)
+	key := seriesKey(name, labelMap)
+
+	r.mu.RLock()
+	metric, ok := r.metrics[key]
+	r.mu.RUnlock()
+	if ok {
+		return metric
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if metric, ok = r.metrics[key]; ok {
+		return metric
+	}
+	metric = &memoryMetric{name: name, kind: kind, labels: labelMap}
+	if kind == MetricKindHistogram {
+		metric.buckets = make([]uint64, len(DefaultHistogramBuckets))
+	}
+	r.metrics[key] = metric
+	return metric
+}
+
+// Gather returns a snapshot of every metric, sorted by name and labels.
+func (r *memoryMetrics) Gather() []MetricSnapshot {
+	r.mu.RLock()
+	keys := make([]string, 0, len(r.metrics))
+	for key := range r.metrics {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	metrics := make([]*memoryMetric, 0, len(keys))
+	for _, key := range keys {
+		metrics = append(metrics, r.metrics[key])
+	}
+	r.mu.RUnlock()
+
+	snapshots := make([]MetricSnapshot, 0, len(metrics))
+	for _, metric := range metrics {
+		snapshots = append(snapshots, metric.snapshot())
+	}
+	return snapshots
+}
+
+func (m *memoryMetric) snapshot() MetricSnapshot {
+	snapshot := MetricSnapshot{Name: m.name, Kind: m.kind, Labels: m.labels}
+	if m.kind != MetricKindHistogram {
+		snapshot.Value = math.Float64frombits(m.bits.Load())
+		return snapshot
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	snapshot.Count = m.count
+	snapshot.Sum = m.sum
+	snapshot.Buckets = make([]HistogramBucket, len(DefaultHistogramBuckets))
+	var cumulative uint64
+	for i, bound := range DefaultHistogramBuckets {
+		cumulative += m.buckets[i]
+		snapshot.Buckets[i] = HistogramBucket{UpperBound: bound, Count: cumulative}
+	}
+	return snapshot
+}
+
+// add atomically adds delta to the series' float value.
+func (m *memoryMetric) add(delta float64) {
+	for {
+		old := m.bits.Load()
+		updated := math.Float64bits(math.Float64frombits(old) + delta)
+		if m.bits.CompareAndSwap(old, updated) {
+			return
+		}
+	}
+}
+
+type memoryCounter memoryMetric
+
+func (c *memoryCounter) Inc() { c.Add(1) }
+
+func (c *memoryCounter) Add(delta float64) {
+	if delta < 0 {
+		return
+	}
+	(*memoryMetric)(c).add(delta)
+}
+
+type memoryGauge memoryMetric
+
+func (g *memoryGauge) Set(value float64) { g.bits.Store(math.Float64bits(value)) }
+
+func (g *memoryGauge) Add(delta float64) { (*memoryMetric)(g).add(delta) }
+
+type memoryHistogram memoryMetric
+
+func (h *memoryHistogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.count++
+	h.sum += value
+	if i, _ := slices.BinarySearch(DefaultHistogramBuckets, value); i < len(h.buckets) {
+		h.buckets[i]++
+	}
+}
+
+// labelPairs turns alternating key/value pairs into a map. A trailing key without a
+// value gets an empty value.
+func labelPairs(labels []string) map[string]string {
+	if len(labels) == 0 {
+		return nil
+	}
+	pairs := make(map[string]string, (len(labels)+1)/2)
+	for i := 0; i < len(labels); i += 2 {
+		value := ""
+		if i+1 < len(labels) {
+			value = labels[i+1]
+		}
+		pairs[labels[i]] = value
+	}
+	return pairs
+}
+
+// seriesKey identifies a series by its name and sorted labels.
+func seriesKey(name string, labels map[string]string) string {
+	if len(labels) == 0 {
+		return name
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(name)
+	for _, key := range keys {
+		b.WriteByte(0)
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(labels[key])
+	}
+	return b.String()
+}
+
+// Metrics returns the application's metrics registry. Unless replaced with SetMetrics
+// it is an in-memory registry created with the application.
+func (app *StdApplication) Metrics() MetricsRegistry {
+	return app.metrics
+}
+
+// SetMetrics replaces the application's metrics registry, e.g. with NewNoopMetrics to
+// disable metrics or with an adapter for an existing metrics library. It must be called
+// before Init so modules and lifecycle metrics use the new registry.
+func (app *StdApplication) SetMetrics(registry MetricsRegistry) {
+	if registry == nil {
+		registry = NewNoopMetrics()
+	}
+	app.metrics = registry
+}
+
+// AddMetricsExporter pushes the application's metrics to exporter every interval while
+// the application runs, and once more when it stops. The registry must implement
+// MetricsGatherer, as the default registry does.
+func (app *StdApplication) AddMetricsExporter(exporter MetricsExporter, interval time.Duration) {
+	app.metricsExports = append(app.metricsExports, &metricsExport{exporter: exporter, interval: interval})
+}
+
+// recordLifecycleDuration records how long a module took to init, start or stop as
+// the histogram modular.module.<phase>.duration, in seconds, and in the timeline.
+func (app *StdApplication) recordLifecycleDuration(phase, moduleName string, started time.Time, err error) {
+	app.timeline.step(TimelineEventKind("module."+phase), moduleName, started, err)
+	if app.metrics == nil {
+		return
+	}
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	app.metrics.Histogram("modular.module."+phase+".duration", "module", moduleName, "result", result).
+		Observe(time.Since(started).Seconds())
+}
+
+// startMetricsExports starts the periodic metrics exports.
+func (app *StdApplication) startMetricsExports(ctx context.Context) {
+	if len(app.metricsExports) == 0 {
+		return
+	}
+	gatherer, ok := app.metrics.(MetricsGatherer)
+	if !ok {
+		app.logger.Warn("Metrics registry cannot be gathered, metrics exporters are disabled", "registry", fmt.Sprintf("%T", app.metrics))
+		return
+	}
+	for _, export := range app.metricsExports {
+		if export.interval > 0 {
+			export.start(ctx, gatherer, app.logger)
+		}
+	}
+}
+
+// stopMetricsExports stops the periodic metrics exports and runs a final export.
+func (app *StdApplication) stopMetricsExports(ctx context.Context) {
+	gatherer, ok := app.metrics.(MetricsGatherer)
+	if !ok {
+		return
+	}
+	for _, export := range app.metricsExports {
+		export.stop(ctx, gatherer, app.logger)
+	}
+}
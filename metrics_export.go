@@ -0,0 +1,166 @@
+package modular
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsExporter pushes metrics to a monitoring system such as an OpenTelemetry
+// collector. The application calls ExportMetrics on every export interval while it
+// runs and once more when it stops. Pull-based systems such as Prometheus can use
+// NewPrometheusMetricsHandler instead.
+type MetricsExporter interface {
+	// ExportMetrics publishes a snapshot of every metric in the registry
+	ExportMetrics(ctx context.Context, metrics []MetricSnapshot) error
+}
+
+// MetricsExporterFunc adapts a function to the MetricsExporter interface.
+type MetricsExporterFunc func(ctx context.Context, metrics []MetricSnapshot) error
+
+// ExportMetrics calls f.
+func (f MetricsExporterFunc) ExportMetrics(ctx context.Context, metrics []MetricSnapshot) error {
+	return f(ctx, metrics)
+}
+
+// PrometheusContentType is the content type of the Prometheus text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// NewPrometheusMetricsHandler serves the gatherer's metrics in the Prometheus text
+// exposition format. Dots and other characters Prometheus does not allow in names are
+// replaced by underscores, so "reverseproxy.requests" is served as "reverseproxy_requests".
+func NewPrometheusMetricsHandler(gatherer MetricsGatherer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", PrometheusContentType)
+		_ = WritePrometheusMetrics(w, gatherer.Gather())
+	})
+}
+
+// WritePrometheusMetrics writes metrics in the Prometheus text exposition format.
+func WritePrometheusMetrics(w io.Writer, metrics []MetricSnapshot) error {
+	out := bufio.NewWriter(w)
+	lastName := ""
+	for _, metric := range metrics {
+		name := prometheusName(metric.Name)
+		if name != lastName {
+			fmt.Fprintf(out, "# TYPE %s %s\n", name, metric.Kind)
+			lastName = name
+		}
+		if metric.Kind != MetricKindHistogram {
+			fmt.Fprintf(out, "%s%s %s\n", name, prometheusLabels(metric.Labels, "", 0), prometheusValue(metric.Value))
+			continue
+		}
+		for _, bucket := range metric.Buckets {
+			fmt.Fprintf(out, "%s_bucket%s %d\n", name, prometheusLabels(metric.Labels, "le", bucket.UpperBound), bucket.Count)
+		}
+		fmt.Fprintf(out, "%s_bucket%s %d\n", name, prometheusLabels(metric.Labels, "le", math.Inf(1)), metric.Count)
+		fmt.Fprintf(out, "%s_sum%s %s\n", name, prometheusLabels(metric.Labels, "", 0), prometheusValue(metric.Sum))
+		fmt.Fprintf(out, "%s_count%s %d\n", name, prometheusLabels(metric.Labels, "", 0), metric.Count)
+	}
+	if err := out.Flush(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// prometheusName replaces characters not allowed in Prometheus metric and label names.
+func prometheusName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == ':' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// prometheusLabels formats labels, adding the extra label when extraName is set.
+func prometheusLabels(labels map[string]string, extraName string, extraValue float64) string {
+	if len(labels) == 0 && extraName == "" {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		parts = append(parts, prometheusName(key)+`="`+prometheusLabelValue(labels[key])+`"`)
+	}
+	if extraName != "" {
+		parts = append(parts, extraName+`="`+prometheusValue(extraValue)+`"`)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// prometheusLabelReplacer escapes the characters the exposition format requires
+// escaped in label values. Everything else, including non-ASCII UTF-8, is kept as is.
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusLabelValue escapes a label value for the text exposition format.
+func prometheusLabelValue(value string) string {
+	return prometheusLabelReplacer.Replace(value)
+}
+
+func prometheusValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// metricsExport pushes a registry's metrics to an exporter on an interval.
+type metricsExport struct {
+	exporter MetricsExporter
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     sync.WaitGroup
+}
+
+// start runs periodic exports until stop is called.
+func (e *metricsExport) start(ctx context.Context, gatherer MetricsGatherer, logger Logger) {
+	ctx, e.cancel = context.WithCancel(ctx)
+	e.done.Add(1)
+	go func() {
+		defer e.done.Done()
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				e.export(ctx, gatherer, logger)
+			}
+		}
+	}()
+}
+
+// stop ends periodic exports and runs a final export with ctx.
+func (e *metricsExport) stop(ctx context.Context, gatherer MetricsGatherer, logger Logger) {
+	if e.cancel != nil {
+		e.cancel()
+		e.done.Wait()
+		e.cancel = nil
+	}
+	e.export(ctx, gatherer, logger)
+}
+
+func (e *metricsExport) export(ctx context.Context, gatherer MetricsGatherer, logger Logger) {
+	if err := e.exporter.ExportMetrics(ctx, gatherer.Gather()); err != nil && logger != nil {
+		logger.Warn("Failed to export metrics", "error", err)
+	}
+}
@@ -0,0 +1,230 @@
+package modular
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// OTLPMetricsConfig configures an OTLPMetricsExporter.
+type OTLPMetricsConfig struct {
+	// Endpoint is the URL metrics are posted to, usually the collector's
+	// "http://host:4318/v1/metrics"
+	Endpoint string
+
+	// ServiceName is reported as the service.name resource attribute when set
+	ServiceName string
+
+	// Headers are added to every export request, e.g. for authentication
+	Headers map[string]string
+
+	// Client sends the export requests. Defaults to a client with a 10 second timeout.
+	Client *http.Client
+}
+
+// OTLPMetricsExporter pushes metrics to an OpenTelemetry collector using OTLP over
+// HTTP with JSON encoding, so the core needs no OpenTelemetry dependency. Counters
+// are exported as cumulative monotonic sums, gauges as gauges and histograms as
+// cumulative explicit-bucket histograms. Register it with WithMetricsExporter.
+type OTLPMetricsExporter struct {
+	config OTLPMetricsConfig
+	client *http.Client
+	start  time.Time
+}
+
+// NewOTLPMetricsExporter returns an exporter posting to config.Endpoint.
+func NewOTLPMetricsExporter(config OTLPMetricsConfig) *OTLPMetricsExporter {
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OTLPMetricsExporter{config: config, client: client, start: time.Now()}
+}
+
+// ExportMetrics posts the metrics as one OTLP export request.
+func (e *OTLPMetricsExporter) ExportMetrics(ctx context.Context, metrics []MetricSnapshot) error {
+	body, err := json.Marshal(e.request(metrics, time.Now()))
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create metrics export request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range e.config.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to export metrics: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %s returned %s", ErrMetricsExportRejected, e.config.Endpoint, resp.Status)
+	}
+	return nil
+}
+
+// request converts the snapshots, sorted by name, to an OTLP export request with
+// one metric per name and one data point per label set.
+func (e *OTLPMetricsExporter) request(metrics []MetricSnapshot, now time.Time) otlpExportRequest {
+	start := otlpTime(e.start)
+	end := otlpTime(now)
+
+	var out []otlpMetric
+	for _, snapshot := range metrics {
+		if len(out) == 0 || out[len(out)-1].Name != snapshot.Name {
+			out = append(out, otlpMetric{Name: snapshot.Name})
+		}
+		metric := &out[len(out)-1]
+		attributes := otlpAttributes(snapshot.Labels)
+
+		switch snapshot.Kind {
+		case MetricKindCounter:
+			if metric.Sum == nil {
+				metric.Sum = &otlpSum{AggregationTemporality: otlpCumulative, IsMonotonic: true}
+			}
+			metric.Sum.DataPoints = append(metric.Sum.DataPoints, otlpNumberPoint{
+				Attributes: attributes, StartTimeUnixNano: start, TimeUnixNano: end, AsDouble: snapshot.Value,
+			})
+		case MetricKindGauge:
+			if metric.Gauge == nil {
+				metric.Gauge = &otlpGauge{}
+			}
+			metric.Gauge.DataPoints = append(metric.Gauge.DataPoints, otlpNumberPoint{
+				Attributes: attributes, TimeUnixNano: end, AsDouble: snapshot.Value,
+			})
+		case MetricKindHistogram:
+			if metric.Histogram == nil {
+				metric.Histogram = &otlpHistogram{AggregationTemporality: otlpCumulative}
+			}
+			metric.Histogram.DataPoints = append(metric.Histogram.DataPoints, otlpHistogramPoint(snapshot, attributes, start, end))
+		}
+	}
+
+	var resource otlpResource
+	if e.config.ServiceName != "" {
+		resource.Attributes = otlpAttributes(map[string]string{"service.name": e.config.ServiceName})
+	}
+	return otlpExportRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource: resource,
+		ScopeMetrics: []otlpScopeMetrics{{
+			Scope:   otlpScope{Name: "github.com/CrisisTextLine/modular"},
+			Metrics: out,
+		}},
+	}}}
+}
+
+// otlpHistogramPoint converts the cumulative bucket counts of a snapshot to the
+// per-bucket counts of OTLP, whose last bucket holds observations above every bound.
+func otlpHistogramPoint(snapshot MetricSnapshot, attributes []otlpKeyValue, start, end string) otlpHistogramDataPoint {
+	point := otlpHistogramDataPoint{
+		Attributes:        attributes,
+		StartTimeUnixNano: start,
+		TimeUnixNano:      end,
+		Count:             strconv.FormatUint(snapshot.Count, 10),
+		Sum:               snapshot.Sum,
+		BucketCounts:      make([]string, 0, len(snapshot.Buckets)+1),
+		ExplicitBounds:    make([]float64, 0, len(snapshot.Buckets)),
+	}
+	var below uint64
+	for _, bucket := range snapshot.Buckets {
+		point.ExplicitBounds = append(point.ExplicitBounds, bucket.UpperBound)
+		point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(bucket.Count-below, 10))
+		below = bucket.Count
+	}
+	point.BucketCounts = append(point.BucketCounts, strconv.FormatUint(snapshot.Count-below, 10))
+	return point
+}
+
+// otlpAttributes converts labels to OTLP string attributes, sorted by key.
+func otlpAttributes(labels map[string]string) []otlpKeyValue {
+	if len(labels) == 0 {
+		return nil
+	}
+	attributes := make([]otlpKeyValue, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		attributes = append(attributes, otlpKeyValue{Key: key, Value: otlpAnyValue{StringValue: labels[key]}})
+	}
+	return attributes
+}
+
+// otlpTime formats t as the nanoseconds since the epoch, which the JSON encoding of
+// OTLP represents as a string.
+func otlpTime(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const otlpCumulative = 2
+
+// The JSON encoding of the OTLP ExportMetricsServiceRequest message.
+type (
+	otlpExportRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpMetric struct {
+		Name      string         `json:"name"`
+		Sum       *otlpSum       `json:"sum,omitempty"`
+		Gauge     *otlpGauge     `json:"gauge,omitempty"`
+		Histogram *otlpHistogram `json:"histogram,omitempty"`
+	}
+	otlpSum struct {
+		DataPoints             []otlpNumberPoint `json:"dataPoints"`
+		AggregationTemporality int               `json:"aggregationTemporality"`
+		IsMonotonic            bool              `json:"isMonotonic"`
+	}
+	otlpGauge struct {
+		DataPoints []otlpNumberPoint `json:"dataPoints"`
+	}
+	otlpHistogram struct {
+		DataPoints             []otlpHistogramDataPoint `json:"dataPoints"`
+		AggregationTemporality int                      `json:"aggregationTemporality"`
+	}
+	otlpNumberPoint struct {
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano,omitempty"`
+		TimeUnixNano      string         `json:"timeUnixNano"`
+		AsDouble          float64        `json:"asDouble"`
+	}
+	otlpHistogramDataPoint struct {
+		Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+		StartTimeUnixNano string         `json:"startTimeUnixNano"`
+		TimeUnixNano      string         `json:"timeUnixNano"`
+		Count             string         `json:"count"`
+		Sum               float64        `json:"sum"`
+		BucketCounts      []string       `json:"bucketCounts"`
+		ExplicitBounds    []float64      `json:"explicitBounds"`
+	}
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+	otlpAnyValue struct {
+		StringValue string `json:"stringValue"`
+	}
+)
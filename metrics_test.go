@@ -0,0 +1,216 @@
+package modular
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsRegistry_Instruments(t *testing.T) {
+	registry := NewMetricsRegistry()
+
+	registry.Counter("proxy.requests", "backend", "api").Inc()
+	registry.Counter("proxy.requests", "backend", "api").Add(2)
+	registry.Counter("proxy.requests", "backend", "api").Add(-5) // ignored
+	registry.Counter("proxy.requests", "backend", "auth").Inc()
+	gauge := registry.Gauge("proxy.connections")
+	gauge.Set(10)
+	gauge.Add(-3)
+	histogram := registry.Histogram("proxy.latency")
+	histogram.Observe(0.02)
+	histogram.Observe(0.3)
+	histogram.Observe(30)
+
+	// A name keeps the kind it was created with
+	registry.Gauge("proxy.requests", "backend", "api").Set(100)
+
+	metrics := registry.(MetricsGatherer).Gather()
+	require.Len(t, metrics, 4)
+
+	assert.Equal(t, "proxy.connections", metrics[0].Name)
+	assert.InDelta(t, 7, metrics[0].Value, 0)
+
+	latency := metrics[1]
+	assert.Equal(t, MetricKindHistogram, latency.Kind)
+	assert.Equal(t, uint64(3), latency.Count)
+	assert.InDelta(t, 30.32, latency.Sum, 1e-9)
+	assert.Equal(t, HistogramBucket{UpperBound: 0.025, Count: 1}, latency.Buckets[2])
+	assert.Equal(t, HistogramBucket{UpperBound: 10, Count: 2}, latency.Buckets[len(latency.Buckets)-1])
+
+	assert.Equal(t, map[string]string{"backend": "api"}, metrics[2].Labels)
+	assert.InDelta(t, 3, metrics[2].Value, 0)
+	assert.Equal(t, map[string]string{"backend": "auth"}, metrics[3].Labels)
+}
+
+func TestMetricsRegistry_ConcurrentUpdates(t *testing.T) {
+	registry := NewMetricsRegistry()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				registry.Counter("hits").Inc()
+				registry.Histogram("sizes").Observe(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	metrics := registry.(MetricsGatherer).Gather()
+	assert.InDelta(t, 8000, metrics[0].Value, 0)
+	assert.Equal(t, uint64(8000), metrics[1].Count)
+}
+
+func TestScopedMetrics(t *testing.T) {
+	registry := NewMetricsRegistry()
+	ScopedMetrics(registry, "reverseproxy").Counter("requests").Inc()
+
+	metrics := registry.(MetricsGatherer).Gather()
+	require.Len(t, metrics, 1)
+	assert.Equal(t, "reverseproxy.requests", metrics[0].Name)
+}
+
+func TestPrometheusMetricsHandler(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.Counter("reverseproxy.requests", "backend", "api").Add(3)
+	registry.Histogram("db.query-time").Observe(0.2)
+
+	rec := httptest.NewRecorder()
+	NewPrometheusMetricsHandler(registry.(MetricsGatherer)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, PrometheusContentType, rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE reverseproxy_requests counter\nreverseproxy_requests{backend=\"api\"} 3\n")
+	assert.Contains(t, body, "# TYPE db_query_time histogram\n")
+	assert.Contains(t, body, "db_query_time_bucket{le=\"0.1\"} 0\n")
+	assert.Contains(t, body, "db_query_time_bucket{le=\"0.25\"} 1\n")
+	assert.Contains(t, body, "db_query_time_bucket{le=\"+Inf\"} 1\n")
+	assert.Contains(t, body, "db_query_time_sum 0.2\n")
+	assert.Contains(t, body, "db_query_time_count 1\n")
+}
+
+func TestWritePrometheusMetrics_EscapesLabelValues(t *testing.T) {
+	registry := NewMetricsRegistry()
+	registry.Counter("requests", "path", "C:\\temp\n\"quoted\"\té").Inc()
+
+	var out strings.Builder
+	require.NoError(t, WritePrometheusMetrics(&out, registry.(MetricsGatherer).Gather()))
+	assert.Equal(t, "# TYPE requests counter\nrequests{path=\"C:\\\\temp\\n\\\"quoted\\\"\té\"} 1\n", out.String(),
+		"only backslash, quote and newline are escaped")
+}
+
+func TestOTLPMetricsExporter(t *testing.T) {
+	var received map[string]any
+	var header string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Authorization")
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer collector.Close()
+
+	registry := NewMetricsRegistry()
+	registry.Counter("reverseproxy.requests", "backend", "api").Add(3)
+	registry.Counter("reverseproxy.requests", "backend", "web").Inc()
+	registry.Gauge("connections").Set(12)
+	registry.Histogram("db.query").Observe(0.2)
+	registry.Histogram("db.query").Observe(20)
+
+	exporter := NewOTLPMetricsExporter(OTLPMetricsConfig{
+		Endpoint:    collector.URL + "/v1/metrics",
+		ServiceName: "checkout",
+		Headers:     map[string]string{"Authorization": "Bearer token"},
+	})
+	require.NoError(t, exporter.ExportMetrics(context.Background(), registry.(MetricsGatherer).Gather()))
+	assert.Equal(t, "Bearer token", header)
+
+	resource := received["resourceMetrics"].([]any)[0].(map[string]any)
+	assert.Equal(t, "checkout", resource["resource"].(map[string]any)["attributes"].([]any)[0].(map[string]any)["value"].(map[string]any)["stringValue"])
+	metrics := map[string]map[string]any{}
+	for _, metric := range resource["scopeMetrics"].([]any)[0].(map[string]any)["metrics"].([]any) {
+		metrics[metric.(map[string]any)["name"].(string)] = metric.(map[string]any)
+	}
+
+	sum := metrics["reverseproxy.requests"]["sum"].(map[string]any)
+	assert.Equal(t, true, sum["isMonotonic"])
+	assert.InDelta(t, 2, sum["aggregationTemporality"], 0)
+	require.Len(t, sum["dataPoints"], 2)
+	assert.InDelta(t, 3, sum["dataPoints"].([]any)[0].(map[string]any)["asDouble"], 0)
+
+	gauge := metrics["connections"]["gauge"].(map[string]any)
+	assert.InDelta(t, 12, gauge["dataPoints"].([]any)[0].(map[string]any)["asDouble"], 0)
+
+	point := metrics["db.query"]["histogram"].(map[string]any)["dataPoints"].([]any)[0].(map[string]any)
+	assert.Equal(t, "2", point["count"])
+	buckets := point["bucketCounts"].([]any)
+	require.Len(t, buckets, len(DefaultHistogramBuckets)+1)
+	assert.Equal(t, "1", buckets[5], "0.2 falls in the (0.1, 0.25] bucket")
+	assert.Equal(t, "1", buckets[len(buckets)-1], "20 falls above every bound")
+}
+
+func TestOTLPMetricsExporter_RejectedExport(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer collector.Close()
+
+	exporter := NewOTLPMetricsExporter(OTLPMetricsConfig{Endpoint: collector.URL})
+	require.ErrorIs(t, exporter.ExportMetrics(context.Background(), nil), ErrMetricsExportRejected)
+}
+
+func TestMetricsFor_FallsBackToNoop(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&struct{}{}), &testLogger{})
+	assert.NotNil(t, MetricsFor(app))
+	_, gatherable := MetricsFor(app).(MetricsGatherer)
+	assert.True(t, gatherable, "applications get an in-memory registry by default")
+
+	decorated := NewTenantAwareDecorator(app, nil)
+	assert.Same(t, MetricsFor(app), MetricsFor(decorated))
+
+	app.(*StdApplication).SetMetrics(nil)
+	MetricsFor(app).Counter("anything").Inc() // discarded without panicking
+}
+
+func TestApplication_LifecycleMetricsAndExport(t *testing.T) {
+	var mu sync.Mutex
+	var exported [][]MetricSnapshot
+	exporter := MetricsExporterFunc(func(ctx context.Context, metrics []MetricSnapshot) error {
+		mu.Lock()
+		defer mu.Unlock()
+		exported = append(exported, metrics)
+		return nil
+	})
+
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithModules(&lifecycleTestModule{testModule: testModule{name: "worker"}}),
+		WithMetricsExporter(exporter, time.Hour),
+	)
+	require.NoError(t, err)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, exported, 1, "the final export runs on Stop")
+	phases := map[string]MetricSnapshot{}
+	for _, metric := range exported[0] {
+		phases[metric.Name] = metric
+	}
+	for _, name := range []string{"modular.module.init.duration", "modular.module.start.duration", "modular.module.stop.duration"} {
+		metric, ok := phases[name]
+		require.True(t, ok, name)
+		assert.Equal(t, map[string]string{"module": "worker", "result": "success"}, metric.Labels)
+		assert.Equal(t, uint64(1), metric.Count)
+	}
+}
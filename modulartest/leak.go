@@ -0,0 +1,346 @@
+// Package modulartest provides helpers for testing modular applications and modules.
+package modulartest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// defaultSettleTimeout is how long VerifyClean waits for goroutines and file
+// descriptors to go away once the test ended.
+const defaultSettleTimeout = 2 * time.Second
+
+// frameworkPackage is the package path of the modular framework, which goroutines
+// outside of any module's package are attributed to.
+const frameworkPackage = "github.com/CrisisTextLine/modular"
+
+// defaultIgnoredTopFunctions are goroutines the runtime and standard library start
+// once per process, so they may first appear during any test.
+var defaultIgnoredTopFunctions = []string{
+	"os/signal.signal_recv",
+	"os/signal.loop",
+	"runtime.ensureSigM",
+	"runtime.ReadTrace",
+}
+
+// defaultIgnoredFiles are file descriptors the runtime opens once per process,
+// such as the network poller's.
+var defaultIgnoredFiles = []string{
+	"anon_inode:[eventpoll]",
+	"anon_inode:[eventfd]",
+}
+
+// Option configures VerifyClean.
+type Option func(*verifier)
+
+// WithTimeout sets how long VerifyClean waits for goroutines and file descriptors to
+// be released after the test ends, since stopping modules may finish asynchronously.
+// Default: 2s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *verifier) {
+		v.timeout = timeout
+	}
+}
+
+// IgnoreTopFunction ignores goroutines whose stack starts in the named function, such
+// as "net/http.(*persistConn).readLoop" for idle connections of a shared transport.
+func IgnoreTopFunction(function string) Option {
+	return func(v *verifier) {
+		v.ignoredTopFunctions = append(v.ignoredTopFunctions, function)
+	}
+}
+
+// IgnoreFileDescriptors disables the file descriptor check, for tests sharing
+// connection pools or files with other tests.
+func IgnoreFileDescriptors() Option {
+	return func(v *verifier) {
+		v.skipFiles = true
+	}
+}
+
+// VerifyClean fails t if the application leaks goroutines or file descriptors. Call
+// it before app.Init: it snapshots the goroutines and open file descriptors of the
+// process, and once the test and its earlier registered cleanups ran, typically
+// after app.Stop, it reports those started since and still around, with the stacks
+// of leaked goroutines attributed to the modules whose code they run:
+//
+//	app := modular.NewStdApplication(modular.NewStdConfigProvider(nil), logger)
+//	app.RegisterModule(scheduler.NewModule())
+//	modulartest.VerifyClean(t, app)
+//	require.NoError(t, app.Init())
+//	require.NoError(t, app.Start())
+//	require.NoError(t, app.Stop())
+//
+// Goroutines and file descriptors are process-wide, so tests using VerifyClean must
+// not run in parallel with others. File descriptors are only checked on Linux.
+func VerifyClean(t testing.TB, app modular.Application, opts ...Option) {
+	t.Helper()
+
+	v := &verifier{
+		app:                 app,
+		timeout:             defaultSettleTimeout,
+		ignoredTopFunctions: append([]string(nil), defaultIgnoredTopFunctions...),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	v.goroutines = goroutineIDs(snapshotGoroutines())
+	if !v.skipFiles {
+		v.files = snapshotFiles()
+	}
+
+	t.Cleanup(func() {
+		goroutines, files := v.wait()
+		if len(goroutines) == 0 && len(files) == 0 {
+			return
+		}
+		t.Errorf("%s", v.report(goroutines, files))
+	})
+}
+
+// verifier holds the snapshot VerifyClean compares against.
+type verifier struct {
+	app                 modular.Application
+	timeout             time.Duration
+	ignoredTopFunctions []string
+	skipFiles           bool
+
+	goroutines map[int]bool
+	files      map[int]string // nil when file descriptors can't be listed
+}
+
+// wait polls for leaked goroutines and file descriptors until there are none or the
+// timeout expires, and returns the last ones seen.
+func (v *verifier) wait() ([]goroutine, map[int]string) {
+	deadline := time.Now().Add(v.timeout)
+	delay := time.Millisecond
+	for {
+		goroutines := v.leakedGoroutines()
+		files := v.leakedFiles()
+		if (len(goroutines) == 0 && len(files) == 0) || time.Now().After(deadline) {
+			return goroutines, files
+		}
+		time.Sleep(delay)
+		delay = min(2*delay, 100*time.Millisecond)
+	}
+}
+
+func (v *verifier) leakedGoroutines() []goroutine {
+	var leaked []goroutine
+	for _, g := range snapshotGoroutines() {
+		if v.goroutines[g.id] || v.ignored(g) {
+			continue
+		}
+		leaked = append(leaked, g)
+	}
+	return leaked
+}
+
+// ignored reports whether g belongs to the testing framework or was ignored.
+func (v *verifier) ignored(g goroutine) bool {
+	for _, function := range g.functions {
+		if strings.HasPrefix(function, "testing.") {
+			return true
+		}
+	}
+	return len(g.functions) > 0 && slices.Contains(v.ignoredTopFunctions, g.functions[0])
+}
+
+func (v *verifier) leakedFiles() map[int]string {
+	if v.files == nil {
+		return nil
+	}
+	leaked := make(map[int]string)
+	for fd, target := range snapshotFiles() {
+		if v.files[fd] == target || slices.Contains(defaultIgnoredFiles, target) {
+			continue
+		}
+		leaked[fd] = target
+	}
+	return leaked
+}
+
+// report describes the leaks, attributing goroutines to modules.
+func (v *verifier) report(goroutines []goroutine, files map[int]string) string {
+	packages := modulePackages(v.app)
+
+	var b strings.Builder
+	if len(goroutines) > 0 {
+		fmt.Fprintf(&b, "modulartest: %d goroutine(s) leaked:\n", len(goroutines))
+		for _, g := range goroutines {
+			fmt.Fprintf(&b, "\n[%s]\n%s\n", attribute(g, packages), g.stack)
+		}
+	}
+	if len(files) > 0 {
+		fds := make([]int, 0, len(files))
+		for fd := range files {
+			fds = append(fds, fd)
+		}
+		sort.Ints(fds)
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "modulartest: %d file descriptor(s) leaked:\n", len(files))
+		for _, fd := range fds {
+			fmt.Fprintf(&b, "  %d -> %s\n", fd, files[fd])
+		}
+	}
+	return b.String()
+}
+
+// modulePackages returns the names of the application's modules by the package
+// path of their type.
+func modulePackages(app modular.Application) map[string][]string {
+	packages := make(map[string][]string)
+	if app == nil {
+		return packages
+	}
+	for name, module := range app.GetAllModules() {
+		moduleType := reflect.TypeOf(module)
+		for moduleType != nil && moduleType.Kind() == reflect.Ptr {
+			moduleType = moduleType.Elem()
+		}
+		if moduleType == nil || moduleType.PkgPath() == "" {
+			continue
+		}
+		packages[moduleType.PkgPath()] = append(packages[moduleType.PkgPath()], name)
+	}
+	for _, names := range packages {
+		sort.Strings(names)
+	}
+	return packages
+}
+
+// attribute names the modules whose code g runs, preferring the most specific
+// package in its stack, or the framework.
+func attribute(g goroutine, packages map[string][]string) string {
+	best := ""
+	for _, function := range g.functions {
+		for pkg := range packages {
+			if strings.HasPrefix(function, pkg+".") && len(pkg) > len(best) {
+				best = pkg
+			}
+		}
+	}
+	if best != "" {
+		names := packages[best]
+		if len(names) == 1 {
+			return "module " + names[0]
+		}
+		return "modules " + strings.Join(names, ", ")
+	}
+	for _, function := range g.functions {
+		if strings.HasPrefix(function, frameworkPackage+".") {
+			return "framework"
+		}
+	}
+	return "unattributed"
+}
+
+// goroutine is a goroutine parsed from runtime.Stack.
+type goroutine struct {
+	id    int
+	stack string
+	// functions lists the functions of the stack from the top, ending with the
+	// function that created the goroutine
+	functions []string
+}
+
+// snapshotGoroutines returns every goroutine but the calling one.
+func snapshotGoroutines() []goroutine {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	var goroutines []goroutine
+	for i, block := range strings.Split(string(buf), "\n\n") {
+		if i == 0 {
+			continue // the calling goroutine
+		}
+		if g, ok := parseGoroutine(block); ok {
+			goroutines = append(goroutines, g)
+		}
+	}
+	return goroutines
+}
+
+// parseGoroutine parses a goroutine's block of a runtime.Stack dump.
+func parseGoroutine(block string) (goroutine, bool) {
+	block = strings.TrimSpace(block)
+	header, frames, _ := strings.Cut(block, "\n")
+	fields := strings.Fields(header)
+	if len(fields) < 2 || fields[0] != "goroutine" {
+		return goroutine{}, false
+	}
+	id, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return goroutine{}, false
+	}
+
+	g := goroutine{id: id, stack: block}
+	scanner := bufio.NewScanner(strings.NewReader(frames))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "\t") {
+			continue // file and line of the previous frame
+		}
+		if creator, ok := strings.CutPrefix(line, "created by "); ok {
+			creator, _, _ = strings.Cut(creator, " in goroutine ")
+			g.functions = append(g.functions, creator)
+			continue
+		}
+		if i := strings.LastIndex(line, "("); i > 0 {
+			line = line[:i]
+		}
+		g.functions = append(g.functions, line)
+	}
+	return g, true
+}
+
+func goroutineIDs(goroutines []goroutine) map[int]bool {
+	ids := make(map[int]bool, len(goroutines))
+	for _, g := range goroutines {
+		ids[g.id] = true
+	}
+	return ids
+}
+
+// snapshotFiles returns the open file descriptors of the process and what they refer
+// to, or nil when they can't be listed.
+func snapshotFiles() map[int]string {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return nil
+	}
+	files := make(map[int]string, len(entries))
+	for _, entry := range entries {
+		fd, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		// The directory listed above was closed already, and fails to resolve
+		target, err := os.Readlink("/proc/self/fd/" + entry.Name())
+		if err != nil {
+			continue
+		}
+		files[fd] = target
+	}
+	return files
+}
@@ -0,0 +1,180 @@
+package modulartest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discardLogger struct{}
+
+func (discardLogger) Info(string, ...any)  {}
+func (discardLogger) Error(string, ...any) {}
+func (discardLogger) Warn(string, ...any)  {}
+func (discardLogger) Debug(string, ...any) {}
+
+// recordingTB records the failures of VerifyClean instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+}
+
+func (r *recordingTB) Helper()                {}
+func (r *recordingTB) Cleanup(cleanup func()) { r.cleanups = append(r.cleanups, cleanup) }
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// finish runs the cleanups like the end of a test.
+func (r *recordingTB) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+// workerModule runs a worker until the application stops.
+type workerModule struct{}
+
+func (workerModule) Name() string                   { return "worker" }
+func (workerModule) Init(modular.Application) error { return nil }
+func (workerModule) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// leakyModule starts a goroutine, and optionally opens a file, that outlive Stop.
+type leakyModule struct {
+	release  chan struct{}
+	openFile bool
+	file     *os.File
+}
+
+func (m *leakyModule) Name() string                   { return "leaky" }
+func (m *leakyModule) Init(modular.Application) error { return nil }
+
+func (m *leakyModule) Start(context.Context) error {
+	go func() {
+		<-m.release
+	}()
+	if m.openFile {
+		file, err := os.CreateTemp("", "modulartest-leak-*")
+		if err != nil {
+			return err
+		}
+		m.file = file
+	}
+	return nil
+}
+
+func (m *leakyModule) Stop(context.Context) error { return nil }
+
+// cleanUp releases what the module leaked.
+func (m *leakyModule) cleanUp() {
+	close(m.release)
+	if m.file != nil {
+		_ = m.file.Close()
+		_ = os.Remove(m.file.Name())
+	}
+}
+
+// runApplication initializes, starts and stops app.
+func runApplication(t *testing.T, app modular.Application) {
+	t.Helper()
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+}
+
+func TestVerifyClean_FrameworkServicesStopCleanly(t *testing.T) {
+	for cycle := range 2 {
+		t.Run(fmt.Sprintf("cycle %d", cycle), func(t *testing.T) {
+			app, err := modular.NewApplication(
+				modular.WithLogger(discardLogger{}),
+				modular.WithConfigProvider(modular.NewStdConfigProvider(&struct{}{})),
+				modular.WithModules(workerModule{}),
+				modular.WithObserver(func(context.Context, cloudevents.Event) error { return nil }),
+				modular.WithObservableOptions(modular.WithEventDispatcher(modular.EventDispatcherConfig{Workers: 2})),
+				modular.WithMetricsExporter(modular.MetricsExporterFunc(func(context.Context, []modular.MetricSnapshot) error {
+					return nil
+				}), 10*time.Millisecond),
+			)
+			require.NoError(t, err)
+			VerifyClean(t, app)
+			runApplication(t, app)
+		})
+	}
+}
+
+func TestVerifyClean_ReportsLeakedGoroutine(t *testing.T) {
+	module := &leakyModule{release: make(chan struct{})}
+	defer module.cleanUp()
+	app := modular.NewStdApplication(modular.NewStdConfigProvider(&struct{}{}), discardLogger{})
+	app.RegisterModule(module)
+
+	tb := &recordingTB{}
+	VerifyClean(tb, app, WithTimeout(50*time.Millisecond), IgnoreFileDescriptors())
+	runApplication(t, app)
+	tb.finish()
+
+	require.Len(t, tb.errors, 1)
+	report := tb.errors[0]
+	assert.Contains(t, report, "modulartest: 1 goroutine(s) leaked")
+	assert.Contains(t, report, "[module leaky]")
+	assert.Contains(t, report, "created by github.com/CrisisTextLine/modular/modulartest.(*leakyModule).Start")
+}
+
+func TestVerifyClean_ReportsLeakedFileDescriptor(t *testing.T) {
+	if _, err := os.Stat("/proc/self/fd"); err != nil {
+		t.Skip("file descriptors can't be listed on this platform")
+	}
+	module := &leakyModule{release: make(chan struct{}), openFile: true}
+	defer module.cleanUp()
+	app := modular.NewStdApplication(modular.NewStdConfigProvider(&struct{}{}), discardLogger{})
+	app.RegisterModule(module)
+
+	tb := &recordingTB{}
+	VerifyClean(tb, app, WithTimeout(50*time.Millisecond), IgnoreTopFunction("github.com/CrisisTextLine/modular/modulartest.(*leakyModule).Start.func1"))
+	runApplication(t, app)
+	tb.finish()
+
+	require.Len(t, tb.errors, 1)
+	assert.Contains(t, tb.errors[0], "modulartest: 1 file descriptor(s) leaked")
+	assert.Contains(t, tb.errors[0], module.file.Name())
+	assert.NotContains(t, tb.errors[0], "goroutine(s) leaked", "the goroutine was ignored")
+}
+
+func TestParseGoroutine(t *testing.T) {
+	g, ok := parseGoroutine(strings.Join([]string{
+		"goroutine 42 [chan receive]:",
+		"github.com/CrisisTextLine/modular/modules/scheduler.(*Scheduler).worker(0xc000123450)",
+		"\t/src/modules/scheduler/scheduler.go:120 +0x45",
+		"created by github.com/CrisisTextLine/modular/modules/scheduler.(*Scheduler).Start in goroutine 7",
+		"\t/src/modules/scheduler/scheduler.go:80 +0x99",
+	}, "\n"))
+	require.True(t, ok)
+	assert.Equal(t, 42, g.id)
+	assert.Equal(t, []string{
+		"github.com/CrisisTextLine/modular/modules/scheduler.(*Scheduler).worker",
+		"github.com/CrisisTextLine/modular/modules/scheduler.(*Scheduler).Start",
+	}, g.functions)
+
+	packages := map[string][]string{
+		"github.com/CrisisTextLine/modular":                   {"core"},
+		"github.com/CrisisTextLine/modular/modules/scheduler": {"scheduler"},
+	}
+	assert.Equal(t, "module scheduler", attribute(g, packages))
+	assert.Equal(t, "unattributed", attribute(g, nil))
+
+	_, ok = parseGoroutine("not a goroutine")
+	assert.False(t, ok)
+}
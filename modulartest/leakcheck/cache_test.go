@@ -0,0 +1,30 @@
+package leakcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/modules/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_StopsCleanly(t *testing.T) {
+	app := newApp(map[string]any{"cache": &cache.CacheConfig{
+		Engine:          "memory",
+		DefaultTTL:      time.Minute,
+		CleanupInterval: 50 * time.Millisecond,
+		MaxItems:        100,
+	}})
+	mod := cache.NewModule().(*cache.CacheModule)
+	app.RegisterModule(mod)
+	startClean(t, app)
+
+	ctx := context.Background()
+	require.NoError(t, mod.Set(ctx, "key", "value", 10*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	_, found := mod.Get(ctx, "key")
+	assert.False(t, found)
+	assert.NoError(t, app.Stop())
+}
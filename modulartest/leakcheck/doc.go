@@ -0,0 +1,2 @@
+// Package leakcheck runs the lifecycle of core modules under modulartest.VerifyClean.
+package leakcheck
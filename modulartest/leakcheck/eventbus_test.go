@@ -0,0 +1,50 @@
+package leakcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/modules/eventbus/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus_StopsCleanly(t *testing.T) {
+	app := newApp(nil)
+	mod := eventbus.NewModule().(*eventbus.EventBusModule)
+	app.RegisterModule(mod)
+	startClean(t, app)
+
+	ctx := context.Background()
+	received := make(chan struct{}, 2)
+	handler := func(context.Context, eventbus.Event) error {
+		received <- struct{}{}
+		return nil
+	}
+	_, err := mod.Subscribe(ctx, "leakcheck.sync", handler)
+	require.NoError(t, err)
+	_, err = mod.SubscribeAsync(ctx, "leakcheck.async", handler)
+	require.NoError(t, err)
+
+	require.NoError(t, mod.Publish(ctx, "leakcheck.sync", "payload"))
+	require.NoError(t, mod.Publish(ctx, "leakcheck.async", "payload"))
+	for range 2 {
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("event was not delivered")
+		}
+	}
+
+	// An asynchronous handler still running at Stop.
+	_, err = mod.SubscribeAsync(ctx, "leakcheck.slow", func(ctx context.Context, _ eventbus.Event) error {
+		received <- struct{}{}
+		time.Sleep(200 * time.Millisecond)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, mod.Publish(ctx, "leakcheck.slow", "payload"))
+	<-received
+	assert.NoError(t, app.Stop())
+}
@@ -0,0 +1,88 @@
+package leakcheck
+
+import (
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/CrisisTextLine/modular/modulartest"
+	"github.com/stretchr/testify/require"
+)
+
+// newApp returns an application logging nowhere, with the given config sections
+// registered before its modules register their defaults.
+func newApp(sections map[string]any) modular.Application {
+	app := modular.NewStdApplication(modular.NewStdConfigProvider(nil), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for name, cfg := range sections {
+		app.RegisterConfigSection(name, modular.NewStdConfigProvider(cfg))
+	}
+	return app
+}
+
+// startClean initializes and starts app under modulartest.VerifyClean. The caller
+// exercises the modules and stops the application, after which VerifyClean checks
+// that nothing they started is left behind.
+func startClean(t *testing.T, app modular.Application) {
+	t.Helper()
+	modulartest.VerifyClean(t, app)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+}
+
+// freePort returns a loopback port nothing listens on.
+func freePort(t *testing.T) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// testRouter is the router service the reverse proxy and the HTTP server need. It
+// matches patterns exactly, or by prefix for chi-style "/prefix/*" patterns, and a
+// pattern registered again replaces its handler.
+type testRouter struct {
+	mu     sync.RWMutex
+	routes map[string]http.Handler
+}
+
+func newTestRouter() *testRouter {
+	return &testRouter{routes: make(map[string]http.Handler)}
+}
+
+func (r *testRouter) Handle(pattern string, handler http.Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes[pattern] = handler
+}
+
+func (r *testRouter) HandleFunc(pattern string, handler http.HandlerFunc) { r.Handle(pattern, handler) }
+
+func (r *testRouter) Mount(pattern string, h http.Handler) { r.Handle(pattern, h) }
+
+func (r *testRouter) Use(...func(http.Handler) http.Handler) {}
+
+func (r *testRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	handler, ok := r.routes[req.URL.Path]
+	if !ok {
+		best := -1
+		for pattern, h := range r.routes {
+			prefix, wildcard := strings.CutSuffix(pattern, "*")
+			if wildcard && strings.HasPrefix(req.URL.Path, prefix) && len(prefix) > best {
+				handler, best = h, len(prefix)
+			}
+		}
+	}
+	r.mu.RUnlock()
+	if handler == nil {
+		http.NotFound(w, req)
+		return
+	}
+	handler.ServeHTTP(w, req)
+}
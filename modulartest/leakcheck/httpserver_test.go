@@ -0,0 +1,48 @@
+package leakcheck
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/modules/httpserver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPServer_StopsCleanly(t *testing.T) {
+	port := freePort(t)
+	app := newApp(map[string]any{"httpserver": &httpserver.HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ReadTimeout:     time.Second,
+		WriteTimeout:    time.Second,
+		IdleTimeout:     time.Minute,
+		ShutdownTimeout: time.Second,
+	}})
+	router := newTestRouter()
+	router.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	})
+	require.NoError(t, app.RegisterService("router", router))
+	app.RegisterModule(httpserver.NewHTTPServerModule())
+	startClean(t, app)
+
+	// The connection is kept alive, for the server to close on Stop.
+	client := &http.Client{Transport: &http.Transport{}}
+	var resp *http.Response
+	require.Eventually(t, func() bool {
+		r, err := client.Get(fmt.Sprintf("http://127.0.0.1:%d/", port))
+		if err != nil {
+			return false
+		}
+		resp = r
+		return true
+	}, 5*time.Second, 20*time.Millisecond)
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, app.Stop())
+}
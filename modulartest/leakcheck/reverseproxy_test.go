@@ -0,0 +1,46 @@
+package leakcheck
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/modules/reverseproxy/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReverseProxy_HealthCheckerStopsCleanly(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(backend.Close)
+
+	app := newApp(map[string]any{"reverseproxy": &reverseproxy.ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		DefaultBackend:  "api",
+		RequestTimeout:  5 * time.Second,
+		HealthCheck: reverseproxy.HealthCheckConfig{
+			Enabled:                true,
+			Interval:               20 * time.Millisecond,
+			Timeout:                time.Second,
+			RecentRequestThreshold: time.Millisecond,
+			ExpectedStatusCodes:    []int{http.StatusOK},
+		},
+	}})
+	router := newTestRouter()
+	require.NoError(t, app.RegisterService("router", router))
+	app.RegisterModule(reverseproxy.NewModule())
+	startClean(t, app)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	// Let the health checker probe the backend a few times.
+	time.Sleep(100 * time.Millisecond)
+	assert.NoError(t, app.Stop())
+}
@@ -0,0 +1,63 @@
+package leakcheck
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular/modules/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_StopsCleanly(t *testing.T) {
+	app := newApp(map[string]any{"scheduler": &scheduler.SchedulerConfig{
+		WorkerCount:     2,
+		QueueSize:       10,
+		ShutdownTimeout: time.Second,
+		StorageType:     "memory",
+		CheckInterval:   50 * time.Millisecond,
+		RetentionDays:   1,
+	}})
+	mod := scheduler.NewModule().(*scheduler.SchedulerModule)
+	app.RegisterModule(mod)
+	startClean(t, app)
+
+	ran := make(chan struct{}, 2)
+	_, err := mod.ScheduleJob(scheduler.Job{
+		Name:  "once",
+		RunAt: time.Now(),
+		JobFunc: func(context.Context) error {
+			ran <- struct{}{}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	_, err = mod.ScheduleJob(scheduler.Job{
+		Name:        "recurring",
+		Schedule:    "@every 1h",
+		IsRecurring: true,
+		JobFunc:     func(context.Context) error { return nil },
+	})
+	require.NoError(t, err)
+
+	_, err = mod.ScheduleJob(scheduler.Job{
+		Name:  "running at stop",
+		RunAt: time.Now(),
+		JobFunc: func(ctx context.Context) error {
+			ran <- struct{}{}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+	require.NoError(t, err)
+
+	for range 2 {
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatal("job did not run")
+		}
+	}
+	assert.NoError(t, app.Stop())
+}
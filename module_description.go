@@ -0,0 +1,41 @@
+package modular
+
+import (
+	"slices"
+	"sort"
+)
+
+// ModuleDescription describes a registered module: what it depends on, the services
+// it provides and, with service instrumentation enabled, the calls it has made to
+// other modules' services.
+type ModuleDescription struct {
+	Name string `json:"name"`
+	// Dependencies are the modules the module declares with DependencyAware
+	Dependencies []string `json:"dependencies,omitempty"`
+	// ProvidedServices are the names of the services the module registered
+	ProvidedServices []string `json:"provided_services,omitempty"`
+	// ServiceCalls are the module's calls through instrumented services, sorted by
+	// service and method. See SetServiceInstrumentation.
+	ServiceCalls []ServiceCallStats `json:"service_calls,omitempty"`
+}
+
+// DescribeModules returns a description of every registered module, sorted by name.
+func (app *StdApplication) DescribeModules() []ModuleDescription {
+	modules := app.GetAllModules()
+	descriptions := make([]ModuleDescription, 0, len(modules))
+	for name, module := range modules {
+		description := ModuleDescription{
+			Name:         name,
+			ServiceCalls: app.serviceCallStats(name),
+		}
+		if aware, ok := module.(DependencyAware); ok {
+			description.Dependencies = slices.Clone(aware.Dependencies())
+		}
+		if services := app.GetServicesByModule(name); len(services) > 0 {
+			description.ProvidedServices = slices.Sorted(slices.Values(services))
+		}
+		descriptions = append(descriptions, description)
+	}
+	sort.Slice(descriptions, func(i, j int) bool { return descriptions[i].Name < descriptions[j].Name })
+	return descriptions
+}
@@ -0,0 +1,333 @@
+package modular
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ModuleSwap describes the replacement of a module by SwapModule.
+type ModuleSwap struct {
+	// Module is the new, uninitialized instance, named like the module it replaces.
+	// Nil clones the running module, which must then implement ModuleCloner.
+	Module Module
+
+	// Config replaces config sections for the new instance, keyed by section name.
+	// Values may be ConfigProviders or config structs; defaults and validation are
+	// applied to them as for SetConfigOverride.
+	Config map[string]any
+}
+
+// ModuleSwapper is implemented by applications that can replace a module while
+// they run. StdApplication and ObservableApplication implement it.
+type ModuleSwapper interface {
+	// SwapModule replaces the named module with a new instance, see
+	// StdApplication.SwapModule.
+	SwapModule(ctx context.Context, name string, swap ModuleSwap) error
+}
+
+var (
+	_ ModuleSwapper = (*StdApplication)(nil)
+	_ ModuleSwapper = (*ObservableApplication)(nil)
+)
+
+// SwapModule replaces the named module in place, for example to apply a changed
+// reverse proxy configuration without restarting the application:
+//
+//	err := app.(modular.ModuleSwapper).SwapModule(ctx, "reverseproxy", modular.ModuleSwap{
+//	    Module: reverseproxy.NewModule(),
+//	    Config: map[string]any{"reverseproxy": newConfig},
+//	})
+//
+// The new instance has its services injected and is initialized with the swapped
+// config sections, takes over the services the running instance provides, and is
+// started when the application is running. Routes it registers on a router such as
+// chimux replace the running instance's handlers for the same patterns. Only then
+// is the previous instance stopped, with ctx bounding its Stop.
+//
+// If any step before that fails, the previous config sections and services are
+// restored, the new instance is stopped if it had started, and the running
+// instance keeps serving; the returned error wraps ErrModuleSwapFailed. Handlers a
+// failed Start had already registered cannot be taken back by the application.
+//
+// Modules that received the previous instance's services keep using them; look
+// services up with GetService to follow swaps. Modules implementing Worker can't
+// be swapped, and RegisterConfig is not called on the new instance, since its
+// config sections already exist.
+func (app *StdApplication) SwapModule(ctx context.Context, name string, swap ModuleSwap) error {
+	return app.swapModule(ctx, app, name, swap)
+}
+
+// SwapModule replaces the named module like StdApplication.SwapModule and emits
+// EventTypeModuleSwapped or EventTypeModuleSwapFailed. Once the swap commits, the
+// observers the previous instance registered are unregistered and the new instance
+// registers its own.
+func (app *ObservableApplication) SwapModule(ctx context.Context, name string, swap ModuleSwap) error {
+	previous := app.GetModule(name)
+	err := app.swapModule(ctx, app, name, swap)
+	if err != nil {
+		evt := NewModuleLifecycleEvent("application", "module", name, "", "swap_failed", map[string]interface{}{
+			"error": err.Error(),
+		})
+		app.emitEvent(ctx, evt)
+		return err
+	}
+
+	replacement := app.GetModule(name)
+	evt := NewModuleLifecycleEvent("application", "module", name, moduleInfo(readBuildInfo(), replacement).Version, "swapped", map[string]interface{}{
+		"moduleType":         getTypeName(replacement),
+		"previousModuleType": getTypeName(previous),
+	})
+	app.emitEvent(ctx, evt)
+	return nil
+}
+
+// moduleObserverRegistry is implemented by applications that track the observers
+// modules register, so SwapModule can hand them over to the new instance.
+type moduleObserverRegistry interface {
+	replaceModuleObservers(name string, module Module)
+}
+
+func (app *StdApplication) swapModule(ctx context.Context, appToPass Application, name string, swap ModuleSwap) error {
+	app.swapMutex.Lock()
+	defer app.swapMutex.Unlock()
+
+	if !app.initialized || app.enhancedSvcRegistry == nil {
+		return fmt.Errorf("%w: module %s: application is not initialized", ErrModuleSwapFailed, name)
+	}
+	previous := app.GetModule(name)
+	if previous == nil {
+		return fmt.Errorf("%w: %s", ErrModuleNotFound, name)
+	}
+	if _, ok := previous.(Worker); ok {
+		return fmt.Errorf("%w: module %s runs supervised workers", ErrModuleNotSwappable, name)
+	}
+	replacement := swap.Module
+	if replacement == nil {
+		cloner, ok := previous.(ModuleCloner)
+		if !ok {
+			return fmt.Errorf("%w: module %s implements no ModuleCloner and no new instance was given", ErrModuleNotSwappable, name)
+		}
+		replacement = cloner.CloneModule()
+	}
+	if replacement.Name() != name {
+		return fmt.Errorf("%w: new instance of module %s is named %s", ErrModuleNotSwappable, name, replacement.Name())
+	}
+
+	// Swap the config sections, keeping the current providers for rollback
+	previousSections := make(map[string]ConfigProvider, len(swap.Config))
+	restoreSections := func() {
+		app.cfgSectionsMu.Lock()
+		defer app.cfgSectionsMu.Unlock()
+		for section, provider := range previousSections {
+			if provider == nil {
+				delete(app.cfgSections, section)
+				continue
+			}
+			app.cfgSections[section] = provider
+		}
+	}
+	for section, value := range swap.Config {
+		provider, ok := value.(ConfigProvider)
+		if !ok {
+			provider = NewStdConfigProvider(value)
+		}
+		if err := ValidateConfig(provider.GetConfig()); err != nil {
+			restoreSections()
+			return fmt.Errorf("%w: module %s: config section %s: %w", ErrModuleSwapFailed, name, section, err)
+		}
+		app.cfgSectionsMu.Lock()
+		previousSections[section] = app.cfgSections[section]
+		app.cfgSections[section] = provider
+		app.cfgSectionsMu.Unlock()
+	}
+
+	registry := app.enhancedSvcRegistry
+	app.registryMu.Lock()
+	registry.beginSwap(name)
+	app.registryMu.Unlock()
+	rollback := func(cause error) error {
+		app.registryMu.Lock()
+		registry.currentModule = nil
+		changes := registry.endSwap(false)
+		app.svcRegistry = registry.AsServiceRegistry()
+		app.registryMu.Unlock()
+		if registry.onChange != nil {
+			for _, change := range changes {
+				registry.onChange(change.entry, change.previous)
+			}
+		}
+		restoreSections()
+		app.logger.Error("Module swap failed, keeping the running instance", "module", name, "error", cause)
+		return fmt.Errorf("%w: module %s: %w", ErrModuleSwapFailed, name, cause)
+	}
+
+	var err error
+	if _, ok := replacement.(ServiceAware); ok {
+		if replacement, err = app.injectServices(replacement); err != nil {
+			return rollback(fmt.Errorf("failed to inject services: %w", err))
+		}
+	}
+
+	app.setCurrentModule(replacement)
+	initStart := time.Now()
+	err = replacement.Init(appToPass)
+	app.recordLifecycleDuration("init", name, initStart, err)
+	if err != nil {
+		return rollback(fmt.Errorf("failed to initialize: %w", err))
+	}
+	if serviceAware, ok := replacement.(ServiceAware); ok {
+		for _, svc := range serviceAware.ProvidesServices() {
+			var opts []ServiceRegistrationOption
+			if svc.AllowOverride {
+				opts = append(opts, AllowOverride())
+			}
+			// The registry view is refreshed once the swap commits
+			app.registryMu.Lock()
+			entry, replaced, err := registry.register(svc.Name, svc.Instance, opts...)
+			app.registryMu.Unlock()
+			if err != nil {
+				return rollback(fmt.Errorf("failed to register service '%s': %w", svc.Name, err))
+			}
+			if registry.onChange != nil {
+				registry.onChange(entry, replaced)
+			}
+		}
+	}
+	app.setCurrentModule(nil)
+
+	running := app.ctx != nil && app.ctx.Err() == nil
+	if startable, ok := replacement.(Startable); ok && running {
+		startedAt := time.Now()
+		err = startable.Start(app.ctx)
+		app.recordLifecycleDuration("start", name, startedAt, err)
+		if err != nil {
+			if stoppable, ok := replacement.(Stoppable); ok {
+				if stopErr := stoppable.Stop(ctx); stopErr != nil {
+					app.logger.Warn("Failed to stop module instance that failed to start", "module", name, "error", stopErr)
+				}
+			}
+			return rollback(fmt.Errorf("failed to start: %w", err))
+		}
+	}
+
+	app.registryMu.Lock()
+	registry.endSwap(true)
+	app.svcRegistry = registry.AsServiceRegistry()
+	app.moduleRegistry[name] = replacement
+	app.registryMu.Unlock()
+
+	if observers, ok := appToPass.(moduleObserverRegistry); ok {
+		observers.replaceModuleObservers(name, replacement)
+	}
+
+	if tenantAware, ok := replacement.(TenantAwareModule); ok && app.tenantService != nil {
+		if err := app.tenantService.RegisterTenantAwareModule(tenantAware); err != nil {
+			app.logger.Warn("Failed to register tenant-aware module", "module", name, "error", err)
+		}
+	}
+
+	if stoppable, ok := previous.(Stoppable); ok && running {
+		stoppedAt := time.Now()
+		err = stoppable.Stop(ctx)
+		app.recordLifecycleDuration("stop", name, stoppedAt, err)
+		if err != nil {
+			app.logger.Warn("Previous module instance failed to stop", "module", name, "error", err)
+		}
+	}
+
+	app.logger.Info("Swapped module", "module", name, "type", fmt.Sprintf("%T", replacement))
+	return nil
+}
+
+// serviceSwap records the services of a module while SwapModule replaces it, so the
+// new instance's registrations take them over and can be rolled back.
+type serviceSwap struct {
+	moduleName string
+	// previous holds the replaced instance's entries by actual name
+	previous map[string]*ServiceRegistryEntry
+	// targets maps the original names of those entries to their actual names
+	targets map[string]string
+	// replaced and added hold the actual names registered by the new instance
+	replaced map[string]bool
+	added    []string
+}
+
+// beginSwap starts tracking the services of moduleName for a swap.
+func (r *EnhancedServiceRegistry) beginSwap(moduleName string) {
+	swap := &serviceSwap{
+		moduleName: moduleName,
+		previous:   make(map[string]*ServiceRegistryEntry),
+		targets:    make(map[string]string),
+		replaced:   make(map[string]bool),
+	}
+	for _, actualName := range r.moduleServices[moduleName] {
+		if entry, ok := r.services[actualName]; ok {
+			swap.previous[actualName] = entry
+			swap.targets[entry.OriginalName] = actualName
+		}
+	}
+	r.swap = swap
+}
+
+// swapTarget returns the actual name of the swapped module's service that a
+// registration of name by moduleName replaces.
+func (r *EnhancedServiceRegistry) swapTarget(name, moduleName string) (string, bool) {
+	if r.swap == nil || r.swap.moduleName != moduleName {
+		return "", false
+	}
+	actualName, ok := r.swap.targets[name]
+	if !ok || r.swap.replaced[actualName] {
+		return "", false
+	}
+	r.swap.replaced[actualName] = true
+	return actualName, true
+}
+
+// serviceChange is a registry change to notify onChange of once the registry is
+// no longer locked.
+type serviceChange struct {
+	entry, previous *ServiceRegistryEntry
+}
+
+// endSwap finishes a swap. On commit, services of the previous instance that the
+// new one didn't register again are removed; otherwise the previous instance's
+// services are restored and the new instance's registrations removed. It returns
+// the restored services, for onChange.
+func (r *EnhancedServiceRegistry) endSwap(commit bool) []serviceChange {
+	swap := r.swap
+	r.swap = nil
+	if swap == nil {
+		return nil
+	}
+
+	if commit {
+		for actualName := range swap.previous {
+			if !swap.replaced[actualName] {
+				r.removeService(swap.moduleName, actualName)
+			}
+		}
+		return nil
+	}
+
+	for _, actualName := range swap.added {
+		r.removeService(swap.moduleName, actualName)
+	}
+	var changes []serviceChange
+	for actualName := range swap.replaced {
+		current := r.services[actualName]
+		previous := swap.previous[actualName]
+		r.services[actualName] = previous
+		if current != nil && current != previous {
+			changes = append(changes, serviceChange{entry: previous, previous: current})
+		}
+	}
+	return changes
+}
+
+// removeService drops a service registered by moduleName, freeing its name.
+func (r *EnhancedServiceRegistry) removeService(moduleName, actualName string) {
+	delete(r.services, actualName)
+	delete(r.nameCounters, actualName)
+	r.removeModuleService(moduleName, actualName)
+}
@@ -0,0 +1,245 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errSwapStartFailed = errors.New("start failed")
+
+// swappableModule provides its greeting as a service and records its lifecycle.
+type swappableModule struct {
+	greeterModule
+	startErr error
+	started  bool
+	stopped  bool
+}
+
+func (m *swappableModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{{Name: "greeting", Instance: m}}
+}
+
+func (m *swappableModule) Start(context.Context) error {
+	if m.startErr != nil {
+		return m.startErr
+	}
+	m.started = true
+	return nil
+}
+
+func (m *swappableModule) Stop(context.Context) error {
+	m.stopped = true
+	return nil
+}
+
+func (m *swappableModule) CloneModule() Module {
+	return &swappableModule{}
+}
+
+func newSwapTestApp(t *testing.T, app Application) (Application, *swappableModule) {
+	t.Helper()
+	original := &swappableModule{}
+	app.RegisterModule(original)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+	return app, original
+}
+
+func greetingService(t *testing.T, app Application) *swappableModule {
+	t.Helper()
+	var service *swappableModule
+	require.NoError(t, app.GetService("greeting", &service))
+	return service
+}
+
+func TestSwapModule_ReplacesRunningInstance(t *testing.T) {
+	app, original := newSwapTestApp(t, NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}))
+
+	replacement := &swappableModule{}
+	err := app.(ModuleSwapper).SwapModule(context.Background(), "greeter", ModuleSwap{
+		Module: replacement,
+		Config: map[string]any{"greeter": &greeterConfig{Name: "swapped"}},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, replacement.started)
+	assert.True(t, original.stopped)
+	assert.Same(t, replacement, greetingService(t, app))
+	assert.Equal(t, "hello swapped", greetingService(t, app).greeting)
+	assert.Same(t, replacement, app.(*StdApplication).moduleRegistry["greeter"])
+
+	names := app.(*StdApplication).enhancedSvcRegistry.GetServicesByModule("greeter")
+	assert.Equal(t, []string{"greeting"}, names)
+}
+
+func TestSwapModule_ClonesWithoutInstance(t *testing.T) {
+	app, original := newSwapTestApp(t, NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}))
+
+	err := app.(ModuleSwapper).SwapModule(context.Background(), "greeter", ModuleSwap{
+		Config: map[string]any{"greeter": NewStdConfigProvider(&greeterConfig{Greeting: "hey", Name: "clone"})},
+	})
+	require.NoError(t, err)
+
+	service := greetingService(t, app)
+	assert.NotSame(t, original, service)
+	assert.Equal(t, "hey clone", service.greeting)
+	assert.True(t, original.stopped)
+}
+
+func TestSwapModule_RollsBackWhenStartFails(t *testing.T) {
+	app, original := newSwapTestApp(t, NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}))
+
+	replacement := &swappableModule{startErr: errSwapStartFailed}
+	err := app.(ModuleSwapper).SwapModule(context.Background(), "greeter", ModuleSwap{
+		Module: replacement,
+		Config: map[string]any{"greeter": &greeterConfig{Name: "broken"}},
+	})
+	require.ErrorIs(t, err, ErrModuleSwapFailed)
+	require.ErrorIs(t, err, errSwapStartFailed)
+
+	assert.True(t, replacement.stopped)
+	assert.False(t, original.stopped)
+	assert.Same(t, original, greetingService(t, app))
+	assert.Same(t, original, app.(*StdApplication).moduleRegistry["greeter"])
+
+	section, err := app.GetConfigSection("greeter")
+	require.NoError(t, err)
+	assert.Equal(t, "template", section.GetConfig().(*greeterConfig).Name)
+}
+
+func TestSwapModule_RejectsInvalidSwaps(t *testing.T) {
+	app, original := newSwapTestApp(t, NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}))
+	swapper := app.(ModuleSwapper)
+	ctx := context.Background()
+
+	err := swapper.SwapModule(ctx, "missing", ModuleSwap{})
+	require.ErrorIs(t, err, ErrModuleNotFound)
+
+	err = swapper.SwapModule(ctx, "greeter", ModuleSwap{Module: &greeterModule{}, Config: map[string]any{
+		"greeter": &greeterConfig{},
+	}})
+	require.ErrorIs(t, err, ErrModuleSwapFailed, "required name is missing")
+
+	assert.False(t, original.stopped)
+	assert.Same(t, original, greetingService(t, app))
+}
+
+func TestSwapModule_ObservableEmitsEvents(t *testing.T) {
+	observable := NewObservableApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	var mu sync.Mutex
+	var events []cloudevents.Event
+	observer := NewFunctionalObserver("swap-observer", func(_ context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	})
+	require.NoError(t, observable.RegisterObserver(observer, EventTypeModuleSwapped, EventTypeModuleSwapFailed, EventTypeServiceReplaced))
+	app, _ := newSwapTestApp(t, observable)
+	swapper := app.(ModuleSwapper)
+
+	require.NoError(t, swapper.SwapModule(context.Background(), "greeter", ModuleSwap{Module: &swappableModule{}}))
+	require.Error(t, swapper.SwapModule(context.Background(), "greeter", ModuleSwap{Module: &swappableModule{startErr: errSwapStartFailed}}))
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		var swapped, failed, replaced int
+		for _, event := range events {
+			switch event.Type() {
+			case EventTypeModuleSwapped:
+				swapped++
+			case EventTypeModuleSwapFailed:
+				failed++
+			case EventTypeServiceReplaced:
+				replaced++
+			}
+		}
+		// Services are replaced by both swaps and restored by the rollback
+		return swapped == 1 && failed == 1 && replaced == 3
+	}, time.Second, 10*time.Millisecond)
+}
+
+// observingSwapModule registers an observer under its own ID.
+type observingSwapModule struct {
+	swappableModule
+	observerID string
+}
+
+func (m *observingSwapModule) RegisterObservers(subject Subject) error {
+	return subject.RegisterObserver(NewFunctionalObserver(m.observerID, func(context.Context, cloudevents.Event) error {
+		return nil
+	}))
+}
+
+func (m *observingSwapModule) EmitEvent(context.Context, cloudevents.Event) error { return nil }
+
+func (m *observingSwapModule) GetRegisteredEventTypes() []string { return nil }
+
+func TestSwapModule_ReplacesModuleObservers(t *testing.T) {
+	observable := NewObservableApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	observable.RegisterModule(&observingSwapModule{observerID: "original"})
+	require.NoError(t, observable.Init())
+	require.NoError(t, observable.Start())
+	t.Cleanup(func() { _ = observable.Stop() })
+
+	observerIDs := func() []string {
+		var ids []string
+		for _, info := range observable.GetObservers() {
+			ids = append(ids, info.ID)
+		}
+		return ids
+	}
+	require.Equal(t, []string{"original"}, observerIDs())
+
+	err := observable.SwapModule(context.Background(), "greeter", ModuleSwap{
+		Module: &observingSwapModule{swappableModule: swappableModule{startErr: errSwapStartFailed}, observerID: "failed"},
+	})
+	require.ErrorIs(t, err, ErrModuleSwapFailed)
+	assert.Equal(t, []string{"original"}, observerIDs(), "a failed swap registers no observers")
+
+	require.NoError(t, observable.SwapModule(context.Background(), "greeter", ModuleSwap{
+		Module: &observingSwapModule{observerID: "replacement"},
+	}))
+	assert.Equal(t, []string{"replacement"}, observerIDs(), "the previous instance's observers are unregistered")
+}
+
+func TestSwapModule_ConcurrentWithLookups(t *testing.T) {
+	app, _ := newSwapTestApp(t, NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}))
+
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			var service *swappableModule
+			assert.NoError(t, app.GetService("greeting", &service))
+			assert.NotNil(t, app.(*StdApplication).GetModule("greeter"))
+			_, err := app.GetConfigSection("greeter")
+			assert.NoError(t, err)
+		}
+	}()
+
+	for range 20 {
+		err := app.(ModuleSwapper).SwapModule(context.Background(), "greeter", ModuleSwap{
+			Config: map[string]any{"greeter": &greeterConfig{Name: "swapped"}},
+		})
+		require.NoError(t, err)
+	}
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,100 @@
+package admindashboard
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// AdminDashboardConfig defines the admin listener serving the dashboard.
+type AdminDashboardConfig struct {
+	// Address is the host:port of the admin listener. It is separate from the
+	// application's HTTP server so it can stay off public interfaces.
+	Address string `json:"address" yaml:"address" env:"ADDRESS" default:"127.0.0.1:8090"`
+
+	// BasePath prefixes the dashboard page and its API
+	BasePath string `json:"basePath" yaml:"basePath" env:"BASE_PATH" default:"/admin"`
+
+	// Username is the HTTP basic auth user name, used when Password is set
+	Username string `json:"username" yaml:"username" env:"USERNAME" default:"admin"`
+
+	// Password must be sent with HTTP basic auth. It is required unless
+	// AllowUnauthenticated is set.
+	Password string `json:"password" yaml:"password" env:"PASSWORD"`
+
+	// AllowUnauthenticated serves the dashboard without a password, on a loopback
+	// address or to the networks of AllowedCIDRs
+	AllowUnauthenticated bool `json:"allowUnauthenticated" yaml:"allowUnauthenticated" env:"ALLOW_UNAUTHENTICATED"`
+
+	// AllowedCIDRs, when set, restricts clients to these networks
+	AllowedCIDRs []string `json:"allowedCIDRs" yaml:"allowedCIDRs" env:"ALLOWED_CIDRS"`
+
+	// AllowedHosts are the host names requests may address besides localhost, IP
+	// addresses and the host of Address, such as the DNS name of the admin listener.
+	// Requests for other hosts are rejected, so a site whose name was rebound to the
+	// listener's address can't use the dashboard from a browser.
+	AllowedHosts []string `json:"allowedHosts" yaml:"allowedHosts" env:"ALLOWED_HOSTS"`
+
+	// ReadOnly disables the actions: draining backends, toggling maintenance,
+	// reloading config and controlling jobs
+	ReadOnly bool `json:"readOnly" yaml:"readOnly" env:"READ_ONLY"`
+
+	// EventBufferSize is how many of the most recent events the dashboard shows
+	EventBufferSize int `json:"eventBufferSize" yaml:"eventBufferSize" env:"EVENT_BUFFER_SIZE" default:"100"`
+
+	// RefreshInterval is how often the dashboard page refreshes its data
+	RefreshInterval time.Duration `json:"refreshInterval" yaml:"refreshInterval" env:"REFRESH_INTERVAL" default:"5s"`
+}
+
+// Validate implements the ConfigValidator interface for AdminDashboardConfig. A
+// password is required unless AllowUnauthenticated is set, and a listener without
+// a password or CIDR allowlist must bind to a loopback address.
+func (c *AdminDashboardConfig) Validate() error {
+	host, _, err := net.SplitHostPort(c.Address)
+	if err != nil {
+		return fmt.Errorf("%w: address %q: %w", ErrInvalidConfig, c.Address, err)
+	}
+	if c.BasePath != "" && !strings.HasPrefix(c.BasePath, "/") {
+		return fmt.Errorf("%w: basePath %q must start with /", ErrInvalidConfig, c.BasePath)
+	}
+	if c.Password != "" && c.Username == "" {
+		return fmt.Errorf("%w: password requires a username", ErrInvalidConfig)
+	}
+	if _, err := c.allowedPrefixes(); err != nil {
+		return err
+	}
+	if c.EventBufferSize < 0 || c.RefreshInterval < 0 {
+		return fmt.Errorf("%w: eventBufferSize and refreshInterval must not be negative", ErrInvalidConfig)
+	}
+	if c.Password == "" && !c.AllowUnauthenticated {
+		return ErrPasswordRequired
+	}
+	if c.Password == "" && len(c.AllowedCIDRs) == 0 && !isLoopbackHost(host) {
+		return fmt.Errorf("%w: %s", ErrUnprotectedListener, c.Address)
+	}
+	return nil
+}
+
+// allowedPrefixes parses AllowedCIDRs.
+func (c *AdminDashboardConfig) allowedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.AllowedCIDRs))
+	for _, cidr := range c.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("%w: allowedCIDRs entry %q: %w", ErrInvalidConfig, cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// isLoopbackHost reports whether a listener host only accepts local connections.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.IsLoopback()
+}
@@ -0,0 +1,36 @@
+package admindashboard
+
+import (
+	"errors"
+)
+
+// Module-specific errors for the admin dashboard module.
+var (
+	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
+	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrInvalidConfig is returned for malformed addresses, paths, CIDRs or sizes
+	ErrInvalidConfig = errors.New("invalid admin dashboard configuration")
+
+	// ErrPasswordRequired is returned without a password unless unauthenticated
+	// access is allowed
+	ErrPasswordRequired = errors.New("admin dashboard requires a password unless allowUnauthenticated is set")
+
+	// ErrUnprotectedListener is returned when a listener reachable from other hosts
+	// has neither a password nor a CIDR allowlist
+	ErrUnprotectedListener = errors.New("admin dashboard listener on a non-loopback address requires password or allowedCIDRs")
+
+	// ErrNotRunning is returned when asking for the listener address before Start
+	ErrNotRunning = errors.New("admin dashboard listener is not running")
+
+	// ErrUnknownSource is returned when an action names a source that doesn't
+	// provide it
+	ErrUnknownSource = errors.New("unknown source")
+
+	// ErrUnknownJobAction is returned for job actions other than pause, resume and
+	// trigger
+	ErrUnknownJobAction = errors.New("unknown job action")
+
+	// ErrActionUnavailable is returned when no module or service provides an action
+	ErrActionUnavailable = errors.New("no module or service provides this action")
+)
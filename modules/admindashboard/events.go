@@ -0,0 +1,20 @@
+package admindashboard
+
+// Event type constants for admin dashboard module events.
+// Following CloudEvents specification reverse domain notation.
+const (
+	// Configuration events
+	EventTypeConfigLoaded = "com.modular.admindashboard.config.loaded"
+
+	// Action events
+	EventTypeActionSucceeded = "com.modular.admindashboard.action.succeeded"
+	EventTypeActionFailed    = "com.modular.admindashboard.action.failed"
+
+	// EventTypeAccessDenied is emitted when a request fails authentication, the CIDR
+	// allowlist or the action header check
+	EventTypeAccessDenied = "com.modular.admindashboard.access.denied"
+
+	// Module lifecycle events
+	EventTypeModuleStarted = "com.modular.admindashboard.module.started"
+	EventTypeModuleStopped = "com.modular.admindashboard.module.stopped"
+)
@@ -0,0 +1,193 @@
+package admindashboard
+
+import (
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// ActionHeader must be set on action requests. Browsers don't send custom headers
+// with cross-site requests, so this keeps other sites from triggering actions with
+// the credentials a browser remembers for the dashboard.
+const ActionHeader = "X-Admin-Dashboard-Action"
+
+//go:embed dashboard.html
+var dashboardPage []byte
+
+// newHandler returns the admin listener's handler, serving the dashboard page and
+// its API under the base path behind the access checks.
+func (m *AdminDashboardModule) newHandler() http.Handler {
+	base := strings.TrimSuffix(m.config.BasePath, "/")
+	mux := http.NewServeMux()
+	if base != "" {
+		mux.Handle("GET "+base, http.RedirectHandler(base+"/", http.StatusMovedPermanently))
+	}
+	mux.HandleFunc("GET "+base+"/{$}", m.handlePage)
+	mux.HandleFunc("GET "+base+"/api/state", m.handleState)
+	if !m.config.ReadOnly {
+		mux.Handle("POST "+base+"/api/backends/{source}/{backend}/drain", m.requireActionHeader(http.HandlerFunc(m.handleDrain)))
+		mux.Handle("POST "+base+"/api/maintenance", m.requireActionHeader(http.HandlerFunc(m.handleMaintenance)))
+		mux.Handle("POST "+base+"/api/config/reload", m.requireActionHeader(http.HandlerFunc(m.handleReload)))
+		mux.Handle("POST "+base+"/api/jobs/{source}/{job}/{action}", m.requireActionHeader(http.HandlerFunc(m.handleJob)))
+	}
+	return m.authorize(mux)
+}
+
+// authorize rejects requests for other hosts and from outside the CIDR allowlist
+// with 403, and requests without the basic auth credentials with 401.
+func (m *AdminDashboardModule) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.hostAllowed(r.Host) {
+			m.denied(w, r, http.StatusForbidden, "host not allowed")
+			return
+		}
+		if len(m.allowed) > 0 && !m.clientAllowed(r.RemoteAddr) {
+			m.denied(w, r, http.StatusForbidden, "client address not allowed")
+			return
+		}
+		if m.config.Password != "" {
+			username, password, ok := r.BasicAuth()
+			validUser := subtle.ConstantTimeCompare([]byte(username), []byte(m.config.Username)) == 1
+			validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(m.config.Password)) == 1
+			if !ok || !validUser || !validPassword {
+				w.Header().Set("WWW-Authenticate", `Basic realm="admin dashboard", charset="UTF-8"`)
+				m.denied(w, r, http.StatusUnauthorized, "missing or invalid credentials")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireActionHeader rejects action requests without ActionHeader with 403.
+func (m *AdminDashboardModule) requireActionHeader(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(ActionHeader) == "" {
+			m.denied(w, r, http.StatusForbidden, "missing "+ActionHeader+" header")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostAllowed reports whether a request's Host names the admin listener: localhost,
+// an IP address, the host of the configured address or one of AllowedHosts.
+func (m *AdminDashboardModule) hostAllowed(requestHost string) bool {
+	host, _, err := net.SplitHostPort(requestHost)
+	if err != nil {
+		host = strings.Trim(requestHost, "[]")
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" {
+		return true
+	}
+	if _, err := netip.ParseAddr(host); err == nil {
+		return true
+	}
+	if configured, _, err := net.SplitHostPort(m.config.Address); err == nil && strings.EqualFold(host, configured) {
+		return true
+	}
+	for _, allowed := range m.config.AllowedHosts {
+		if strings.EqualFold(host, strings.TrimSuffix(strings.TrimSpace(allowed), ".")) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientAllowed reports whether the client address is in the CIDR allowlist.
+func (m *AdminDashboardModule) clientAllowed(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range m.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// denied logs, emits and answers a request failing the access checks.
+func (m *AdminDashboardModule) denied(w http.ResponseWriter, r *http.Request, status int, reason string) {
+	m.logger.Warn("Admin dashboard access denied", "remote_addr", r.RemoteAddr, "path", r.URL.Path, "reason", reason)
+	m.emitEvent(r.Context(), EventTypeAccessDenied, map[string]interface{}{
+		"remote_addr": r.RemoteAddr,
+		"path":        r.URL.Path,
+		"reason":      reason,
+	})
+	http.Error(w, http.StatusText(status), status)
+}
+
+// handlePage serves the dashboard page, which loads its data from the API.
+func (m *AdminDashboardModule) handlePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; frame-ancestors 'none'")
+	w.Header().Set("Cache-Control", "no-store")
+	_, _ = w.Write(dashboardPage)
+}
+
+// handleState serves the dashboard's state, with feature flags evaluated for the
+// tenant in the tenant query parameter.
+func (m *AdminDashboardModule) handleState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusOK, m.State(r.Context(), modular.TenantID(r.URL.Query().Get("tenant"))))
+}
+
+func (m *AdminDashboardModule) handleDrain(w http.ResponseWriter, r *http.Request) {
+	writeActionResult(w, m.DrainBackend(r.Context(), r.PathValue("source"), r.PathValue("backend")))
+}
+
+func (m *AdminDashboardModule) handleMaintenance(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Enabled == nil {
+		http.Error(w, `body must be {"enabled": true|false}`, http.StatusBadRequest)
+		return
+	}
+	writeActionResult(w, m.SetMaintenance(r.Context(), *body.Enabled))
+}
+
+func (m *AdminDashboardModule) handleReload(w http.ResponseWriter, r *http.Request) {
+	writeActionResult(w, m.ReloadConfig(r.Context()))
+}
+
+func (m *AdminDashboardModule) handleJob(w http.ResponseWriter, r *http.Request) {
+	writeActionResult(w, m.ControlJob(r.Context(), r.PathValue("source"), r.PathValue("job"), JobAction(r.PathValue("action"))))
+}
+
+// writeActionResult answers an action request with 204, or the error.
+func writeActionResult(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.Is(err, ErrUnknownJobAction):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrUnknownSource):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrActionUnavailable):
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+	default:
+		http.Error(w, err.Error(), http.StatusBadGateway)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
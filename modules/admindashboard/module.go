@@ -0,0 +1,257 @@
+// Package admindashboard serves an admin dashboard for the modular framework.
+//
+// The admin dashboard module serves a small web UI on a dedicated admin listener,
+// protected by HTTP basic auth and optionally a CIDR allowlist. It shows the application's
+// modules, health, proxy backends and their circuit breakers, recent events,
+// tenants and feature flag values, and can drain backends, toggle maintenance mode
+// and reload config through the admin APIs of modules and services.
+//
+// Example configuration:
+//
+//	admindashboard:
+//	  address: 0.0.0.0:8090
+//	  username: admin
+//	  password: change-me
+//	  allowedCIDRs: ["10.0.0.0/8"]
+//	  eventBufferSize: 200
+package admindashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ModuleName is the unique identifier for the admin dashboard module.
+const ModuleName = "admindashboard"
+
+// ServiceName is the name of the service provided by this module.
+const ServiceName = "admindashboard.provider"
+
+// AdminDashboardModule runs the admin listener serving the dashboard.
+type AdminDashboardModule struct {
+	name    string
+	app     modular.Application
+	config  *AdminDashboardConfig
+	logger  modular.Logger
+	subject modular.Subject
+	allowed []netip.Prefix
+	events  *eventBuffer
+
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener
+}
+
+// NewModule creates a new instance of the admin dashboard module.
+func NewModule() modular.Module {
+	return &AdminDashboardModule{
+		name:   ModuleName,
+		events: &eventBuffer{},
+	}
+}
+
+// Name returns the unique identifier for this module.
+func (m *AdminDashboardModule) Name() string {
+	return m.name
+}
+
+// RegisterConfig registers the module's configuration structure.
+func (m *AdminDashboardModule) RegisterConfig(app modular.Application) error {
+	// Check if admin dashboard config is already registered (e.g., by tests)
+	if existing, err := app.GetConfigSection(m.Name()); err == nil && existing != nil {
+		return nil
+	}
+
+	defaultConfig := &AdminDashboardConfig{
+		Address:         "127.0.0.1:8090",
+		BasePath:        "/admin",
+		Username:        "admin",
+		EventBufferSize: 100,
+		RefreshInterval: 5 * time.Second,
+	}
+
+	app.RegisterConfigSection(m.Name(), modular.NewStdConfigProvider(defaultConfig))
+	return nil
+}
+
+// Init validates the configuration.
+func (m *AdminDashboardModule) Init(app modular.Application) error {
+	cfg, err := app.GetConfigSection(m.name)
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.name, err)
+	}
+
+	m.app = app
+	m.config = cfg.GetConfig().(*AdminDashboardConfig)
+	m.logger = app.Logger()
+
+	if err := m.config.Validate(); err != nil {
+		return err
+	}
+	allowed, err := m.config.allowedPrefixes()
+	if err != nil {
+		return err
+	}
+	m.allowed = allowed
+	m.events.resize(m.config.EventBufferSize)
+
+	m.emitEvent(context.Background(), EventTypeConfigLoaded, map[string]interface{}{
+		"address":       m.config.Address,
+		"base_path":     m.config.BasePath,
+		"authenticated": m.config.Password != "",
+		"allowed_cidrs": len(m.allowed),
+		"read_only":     m.config.ReadOnly,
+	})
+
+	m.logger.Info("Admin dashboard module initialized", "address", m.config.Address)
+	return nil
+}
+
+// Start starts the admin listener.
+func (m *AdminDashboardModule) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server != nil {
+		return nil
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", m.config.Address)
+	if err != nil {
+		return fmt.Errorf("starting admin dashboard listener on %s: %w", m.config.Address, err)
+	}
+
+	m.listener = listener
+	m.server = &http.Server{
+		Handler:           m.newHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// Draining a backend waits for its in-flight requests
+		WriteTimeout: 2 * time.Minute,
+	}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Error("Admin dashboard listener failed", "error", err)
+		}
+	}(m.server)
+
+	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
+		"address": listener.Addr().String(),
+	})
+	m.logger.Info("Admin dashboard listener started", "address", listener.Addr().String())
+	return nil
+}
+
+// Stop shuts the admin listener down.
+func (m *AdminDashboardModule) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return nil
+	}
+
+	err := m.server.Shutdown(ctx)
+	m.server = nil
+	m.listener = nil
+	if err != nil {
+		return fmt.Errorf("stopping admin dashboard listener: %w", err)
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStopped, map[string]interface{}{
+		"address": m.config.Address,
+	})
+	m.logger.Info("Admin dashboard listener stopped")
+	return nil
+}
+
+// Dependencies returns the names of modules this module depends on.
+func (m *AdminDashboardModule) Dependencies() []string {
+	return nil
+}
+
+// ProvidesServices declares the services provided by this module.
+func (m *AdminDashboardModule) ProvidesServices() []modular.ServiceProvider {
+	return []modular.ServiceProvider{
+		{
+			Name:        ServiceName,
+			Description: "Admin dashboard state and actions",
+			Instance:    m,
+		},
+	}
+}
+
+// RequiresServices declares the services this module uses. The modules and services
+// it shows are discovered when the dashboard is requested.
+func (m *AdminDashboardModule) RequiresServices() []modular.ServiceDependency {
+	return nil
+}
+
+// Addr returns the address the admin listener accepts connections on, which
+// differs from the configured address when it uses port 0.
+func (m *AdminDashboardModule) Addr() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listener == nil {
+		return "", ErrNotRunning
+	}
+	return m.listener.Addr().String(), nil
+}
+
+// RegisterObservers implements the ObservableModule interface. The module observes
+// every event to show the most recent ones.
+func (m *AdminDashboardModule) RegisterObservers(subject modular.Subject) error {
+	m.subject = subject
+	if err := subject.RegisterObserver(m); err != nil {
+		return fmt.Errorf("failed to register admin dashboard as observer: %w", err)
+	}
+	return nil
+}
+
+// EmitEvent implements the ObservableModule interface.
+func (m *AdminDashboardModule) EmitEvent(ctx context.Context, event cloudevents.Event) error {
+	if m.subject == nil {
+		return ErrNoSubjectForEventEmission
+	}
+	if err := m.subject.NotifyObservers(ctx, event); err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+	return nil
+}
+
+// emitEvent creates and emits a CloudEvent for the admin dashboard module. It
+// silently skips emission when no subject is available.
+func (m *AdminDashboardModule) emitEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if m.subject == nil {
+		return
+	}
+
+	event := modular.NewCloudEvent(eventType, "admindashboard-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+		if errors.Is(emitErr, ErrNoSubjectForEventEmission) {
+			return
+		}
+		if m.logger != nil {
+			m.logger.Warn("Failed to emit admin dashboard event", "eventType", eventType, "error", emitErr)
+		}
+	}
+}
+
+// GetRegisteredEventTypes implements the ObservableModule interface.
+// Returns all event types that this admin dashboard module can emit.
+func (m *AdminDashboardModule) GetRegisteredEventTypes() []string {
+	return []string{
+		EventTypeConfigLoaded,
+		EventTypeActionSucceeded,
+		EventTypeActionFailed,
+		EventTypeAccessDenied,
+		EventTypeModuleStarted,
+		EventTypeModuleStopped,
+	}
+}
@@ -0,0 +1,427 @@
+package admindashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// proxyModule is a module exposing the dashboard's interfaces like a reverse proxy.
+type proxyModule struct {
+	mu          sync.Mutex
+	drained     []string
+	maintenance bool
+}
+
+func (p *proxyModule) Name() string                   { return "proxy" }
+func (p *proxyModule) Init(modular.Application) error { return nil }
+func (p *proxyModule) Dependencies() []string         { return []string{ModuleName} }
+
+func (p *proxyModule) DashboardHealth(context.Context) map[string]error {
+	return map[string]error{"upstream": errors.New("2 of 3 backends down"), "cache": nil}
+}
+
+func (p *proxyModule) DashboardBackends(context.Context) []map[string]any {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	draining := len(p.drained) > 0
+	return []map[string]any{
+		{"name": "users", "url": "http://users:8080", "healthy": true, "circuit_state": "closed", "draining": draining, "in_flight": 3},
+		{"name": "orders", "url": "http://orders:8080", "circuit_state": "open", "in_flight": "many"},
+	}
+}
+
+func (p *proxyModule) DrainBackend(ctx context.Context, backend string) error {
+	if backend != "users" {
+		return errors.New("unknown backend")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.drained = append(p.drained, backend)
+	return nil
+}
+
+func (p *proxyModule) DashboardFeatureFlags(ctx context.Context, tenantID modular.TenantID) map[string]bool {
+	return map[string]bool{"new-checkout": tenantID == "acme", "beta-search": true}
+}
+
+func (p *proxyModule) SetMaintenance(ctx context.Context, enabled bool) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maintenance = enabled
+	return nil
+}
+
+func (p *proxyModule) InMaintenance() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.maintenance
+}
+
+// reloaderFunc is a ConfigReloader service.
+type reloaderFunc func(ctx context.Context) error
+
+func (f reloaderFunc) ReloadConfig(ctx context.Context) error { return f(ctx) }
+
+// jobController is a JobController service like the scheduler module.
+type jobController struct {
+	mu      sync.Mutex
+	actions []string
+}
+
+func (c *jobController) record(action, jobID string) error {
+	if jobID != "nightly-report" {
+		return errors.New("job not found")
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actions = append(c.actions, action+" "+jobID)
+	return nil
+}
+
+func (c *jobController) PauseJob(jobID string) error   { return c.record("pause", jobID) }
+func (c *jobController) ResumeJob(jobID string) error  { return c.record("resume", jobID) }
+func (c *jobController) TriggerNow(jobID string) error { return c.record("trigger", jobID) }
+
+// startDashboard runs the admin dashboard module in an observable application with
+// the given config, along with the proxy module, a job controller service and a
+// config reloader service.
+func startDashboard(t *testing.T, config *AdminDashboardConfig, reloader ConfigReloader) (*AdminDashboardModule, *proxyModule, string) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	app := modular.NewObservableApplication(modular.NewStdConfigProvider(nil), logger)
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+
+	tenants := modular.NewStandardTenantService(logger)
+	require.NoError(t, tenants.RegisterTenant("globex", nil))
+	require.NoError(t, tenants.RegisterTenant("acme", nil))
+	require.NoError(t, app.RegisterService("tenantService", tenants))
+	if reloader != nil {
+		require.NoError(t, app.RegisterService("configReloader", reloader))
+	}
+	require.NoError(t, app.RegisterService("scheduler", &jobController{}))
+
+	proxy := &proxyModule{}
+	module := NewModule().(*AdminDashboardModule)
+	app.RegisterModule(proxy)
+	app.RegisterModule(module)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	addr, err := module.Addr()
+	require.NoError(t, err)
+	return module, proxy, "http://" + addr
+}
+
+func testConfig() *AdminDashboardConfig {
+	return &AdminDashboardConfig{
+		Address:         "127.0.0.1:0",
+		BasePath:        "/admin",
+		Username:        "admin",
+		Password:        "secret",
+		EventBufferSize: 50,
+		RefreshInterval: 2 * time.Second,
+	}
+}
+
+// request sends a request with basic auth when password is set, and the action
+// header for POST requests.
+func request(t *testing.T, method, url, password, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	if password != "" {
+		req.SetBasicAuth("admin", password)
+	}
+	if method == http.MethodPost {
+		req.Header.Set(ActionHeader, "1")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, data
+}
+
+func getState(t *testing.T, url string) State {
+	t.Helper()
+	resp, body := request(t, http.MethodGet, url, "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var state State
+	require.NoError(t, json.Unmarshal(body, &state))
+	return state
+}
+
+func TestAdminDashboard_RequiresCredentials(t *testing.T) {
+	_, _, base := startDashboard(t, testConfig(), nil)
+
+	resp, _ := request(t, http.MethodGet, base+"/admin/", "", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Basic")
+	resp, _ = request(t, http.MethodGet, base+"/admin/api/state", "wrong", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, body := request(t, http.MethodGet, base+"/admin", "secret", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "redirected to the page")
+	assert.Equal(t, "/admin/", resp.Request.URL.Path)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+	assert.Contains(t, string(body), "<title>Admin Dashboard</title>")
+}
+
+func TestAdminDashboard_State(t *testing.T) {
+	_, _, base := startDashboard(t, testConfig(), reloaderFunc(func(context.Context) error { return nil }))
+
+	state := getState(t, base+"/admin/api/state?tenant=acme")
+	assert.False(t, state.StartedAt.IsZero())
+	assert.Equal(t, "acme", state.Tenant)
+	assert.Equal(t, 2.0, state.RefreshSeconds)
+
+	require.Len(t, state.Modules, 2)
+	assert.Equal(t, ModuleInfo{Name: "admindashboard", Dependencies: []string{}, Services: []string{ServiceName}, Startable: true, Stoppable: true}, state.Modules[0])
+	assert.Equal(t, ModuleInfo{Name: "proxy", Dependencies: []string{ModuleName}, Services: []string{}}, state.Modules[1])
+
+	assert.Equal(t, []ComponentHealth{
+		{Source: "proxy", Name: "cache", Healthy: true},
+		{Source: "proxy", Name: "upstream", Message: "2 of 3 backends down"},
+	}, state.Health)
+	require.Len(t, state.Backends, 2)
+	assert.Equal(t, BackendStatus{Source: "proxy", Name: "users", URL: "http://users:8080", Healthy: true, CircuitState: "closed", InFlight: 3}, state.Backends[0])
+	assert.Equal(t, BackendStatus{Source: "proxy", Name: "orders", URL: "http://orders:8080", CircuitState: "open"}, state.Backends[1],
+		"values of the wrong type are left out")
+	assert.Equal(t, []string{"acme", "globex"}, state.Tenants)
+	assert.Equal(t, []FeatureFlag{
+		{Source: "proxy", Name: "beta-search", Enabled: true},
+		{Source: "proxy", Name: "new-checkout", Enabled: true},
+	}, state.FeatureFlags)
+	assert.Equal(t, []MaintenanceStatus{{Source: "proxy"}}, state.Maintenance)
+	assert.Equal(t, Actions{Drain: []string{"proxy"}, Maintenance: []string{"proxy"}, Reload: []string{"configReloader"}, Jobs: []string{"scheduler"}}, state.Actions)
+
+	state = getState(t, base+"/admin/api/state")
+	assert.False(t, state.FeatureFlags[1].Enabled, "flags are evaluated without a tenant")
+}
+
+func TestAdminDashboard_Actions(t *testing.T) {
+	reloads := 0
+	module, proxy, base := startDashboard(t, testConfig(), reloaderFunc(func(context.Context) error {
+		reloads++
+		if reloads > 1 {
+			return errors.New("invalid config file")
+		}
+		return nil
+	}))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, base+"/admin/api/backends/proxy/users/drain", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("admin", "secret")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "actions require the action header")
+
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/backends/proxy/users/drain", "secret", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, []string{"users"}, proxy.drained)
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/backends/proxy/payments/drain", "secret", "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/backends/cache/users/drain", "secret", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/maintenance", "secret", `{"enabled":true}`)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.True(t, proxy.InMaintenance())
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/maintenance", "secret", `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/config/reload", "secret", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp, body := request(t, http.MethodPost, base+"/admin/api/config/reload", "secret", "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Contains(t, string(body), "configReloader: invalid config file")
+
+	state := module.State(context.Background(), "")
+	assert.True(t, state.Backends[0].Draining)
+	assert.Equal(t, []MaintenanceStatus{{Source: "proxy", Enabled: true}}, state.Maintenance)
+
+	// The dashboard shows the events of its own actions, most recent first
+	var actions []string
+	for _, event := range state.Events {
+		if event.Type == EventTypeActionSucceeded || event.Type == EventTypeActionFailed {
+			var data map[string]string
+			require.NoError(t, json.Unmarshal(event.Data, &data))
+			actions = append(actions, data["action"]+" "+data["target"])
+		}
+	}
+	assert.Equal(t, []string{"reload ", "reload ", "maintenance.enable ", "drain cache/users", "drain proxy/payments", "drain proxy/users"}, actions)
+}
+
+func TestAdminDashboard_JobActions(t *testing.T) {
+	module, _, base := startDashboard(t, testConfig(), nil)
+	var jobs *jobController
+	require.NoError(t, module.app.GetService("scheduler", &jobs))
+
+	for _, action := range []string{"pause", "resume", "trigger"} {
+		resp, _ := request(t, http.MethodPost, base+"/admin/api/jobs/scheduler/nightly-report/"+action, "secret", "")
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode, action)
+	}
+	assert.Equal(t, []string{"pause nightly-report", "resume nightly-report", "trigger nightly-report"}, jobs.actions)
+
+	resp, _ := request(t, http.MethodPost, base+"/admin/api/jobs/scheduler/nightly-report/delete", "secret", "")
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	resp, body := request(t, http.MethodPost, base+"/admin/api/jobs/scheduler/cleanup/pause", "secret", "")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Contains(t, string(body), "pause job cleanup of scheduler: job not found")
+	resp, _ = request(t, http.MethodPost, base+"/admin/api/jobs/cron/nightly-report/pause", "secret", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestAdminDashboard_ReadOnly(t *testing.T) {
+	config := testConfig()
+	config.ReadOnly = true
+	_, proxy, base := startDashboard(t, config, nil)
+
+	resp, _ := request(t, http.MethodPost, base+"/admin/api/backends/proxy/users/drain", "secret", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, proxy.drained)
+	assert.True(t, getState(t, base+"/admin/api/state").ReadOnly)
+}
+
+func TestAdminDashboard_ActionUnavailable(t *testing.T) {
+	module, _, _ := startDashboard(t, testConfig(), nil)
+	assert.ErrorIs(t, module.ReloadConfig(context.Background()), ErrActionUnavailable)
+}
+
+func TestAdminDashboard_CIDRAllowlist(t *testing.T) {
+	config := testConfig()
+	config.Password = ""
+	config.AllowUnauthenticated = true
+	config.AllowedCIDRs = []string{"10.0.0.0/8"}
+	_, _, base := startDashboard(t, config, nil)
+
+	resp, _ := request(t, http.MethodGet, base+"/admin/api/state", "", "")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	module := &AdminDashboardModule{config: config}
+	module.allowed, _ = config.allowedPrefixes()
+	assert.True(t, module.clientAllowed("10.1.2.3:5555"))
+	assert.True(t, module.clientAllowed("[::ffff:10.1.2.3]:5555"))
+	assert.False(t, module.clientAllowed("192.168.1.1:5555"))
+}
+
+func TestAdminDashboard_HostHeader(t *testing.T) {
+	_, _, base := startDashboard(t, testConfig(), nil)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, base+"/admin/api/state", nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("admin", "secret")
+	req.Host = "attacker.example"
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode, "rebound host names are rejected")
+
+	module := &AdminDashboardModule{config: &AdminDashboardConfig{Address: "admin.internal:8090", AllowedHosts: []string{"dashboard.example.com"}}}
+	for _, host := range []string{"localhost:8090", "127.0.0.1:8090", "[::1]:8090", "10.0.0.5", "admin.internal:8090", "Dashboard.Example.com"} {
+		assert.True(t, module.hostAllowed(host), host)
+	}
+	assert.False(t, module.hostAllowed("attacker.example:8090"))
+	assert.False(t, module.hostAllowed("localhost.attacker.example"))
+}
+
+// reloadingApp is an application that reloads config, like the modular applications.
+type reloadingApp struct {
+	modular.Application
+	reloads int
+}
+
+func (a *reloadingApp) ReloadConfig(context.Context) error {
+	a.reloads++
+	return nil
+}
+
+func TestAdminDashboard_ReloadsApplicationConfig(t *testing.T) {
+	module, _, _ := startDashboard(t, testConfig(), nil)
+	app := &reloadingApp{Application: module.app}
+	module.app = app
+
+	require.NoError(t, module.ReloadConfig(context.Background()))
+	assert.Equal(t, 1, app.reloads)
+	assert.Equal(t, []string{"application"}, module.State(context.Background(), "").Actions.Reload)
+}
+
+func TestEventBuffer(t *testing.T) {
+	buffer := &eventBuffer{}
+	buffer.add(RecentEvent{ID: "dropped"})
+	assert.Empty(t, buffer.recent(), "no events are kept until resized")
+
+	buffer.resize(3)
+	for _, id := range []string{"1", "2", "3", "4", "5"} {
+		buffer.add(RecentEvent{ID: id})
+	}
+	ids := func() []string {
+		var ids []string
+		for _, event := range buffer.recent() {
+			ids = append(ids, event.ID)
+		}
+		return ids
+	}
+	assert.Equal(t, []string{"5", "4", "3"}, ids())
+
+	buffer.resize(2)
+	assert.Equal(t, []string{"5", "4"}, ids())
+	buffer.add(RecentEvent{ID: "6"})
+	assert.Equal(t, []string{"6", "5"}, ids())
+}
+
+func TestEventData(t *testing.T) {
+	event := cloudevents.NewEvent()
+	require.NoError(t, event.SetData(cloudevents.TextPlain, "not json"))
+	assert.JSONEq(t, `"not json"`, string(eventData(event.Data())))
+	assert.JSONEq(t, `{"a":1}`, string(eventData([]byte(`{"a":1}`))))
+	assert.Nil(t, eventData(nil))
+}
+
+func TestAdminDashboardConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config AdminDashboardConfig
+		err    error
+	}{
+		{"no password", AdminDashboardConfig{Address: "127.0.0.1:8090"}, ErrPasswordRequired},
+		{"public without protection", AdminDashboardConfig{Address: "0.0.0.0:8090", AllowUnauthenticated: true}, ErrUnprotectedListener},
+		{"missing port", AdminDashboardConfig{Address: "127.0.0.1"}, ErrInvalidConfig},
+		{"relative base path", AdminDashboardConfig{Address: "127.0.0.1:8090", BasePath: "admin"}, ErrInvalidConfig},
+		{"password without username", AdminDashboardConfig{Address: "127.0.0.1:8090", Password: "secret"}, ErrInvalidConfig},
+		{"bad cidr", AdminDashboardConfig{Address: "0.0.0.0:8090", AllowedCIDRs: []string{"10.0.0.0"}}, ErrInvalidConfig},
+		{"negative buffer", AdminDashboardConfig{Address: "127.0.0.1:8090", EventBufferSize: -1}, ErrInvalidConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.Validate(), tt.err)
+		})
+	}
+
+	for _, valid := range []AdminDashboardConfig{
+		{Address: "localhost:8090", AllowUnauthenticated: true},
+		{Address: "[::1]:8090", AllowUnauthenticated: true},
+		{Address: ":8090", Username: "admin", Password: "secret"},
+		{Address: "0.0.0.0:8090", AllowUnauthenticated: true, AllowedCIDRs: []string{"10.0.0.0/8"}},
+	} {
+		assert.NoError(t, valid.Validate(), valid.Address)
+	}
+}
@@ -0,0 +1,173 @@
+package admindashboard
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// The dashboard finds what it shows and acts on in the application's modules and
+// registered services implementing the interfaces below, naming each by the module,
+// or the service, it was found in, and reloads config through the application when
+// it implements ConfigReloader. The interfaces only use standard library and modular
+// types, so modules implement them without importing this package: the reverseproxy
+// module implements HealthReporter, BackendReporter, BackendDrainer and
+// MaintenanceController, its feature flag evaluator FeatureFlagReporter and the
+// scheduler module JobController. Other modules can be exposed by registering an
+// adapter service.
+
+// HealthReporter reports the health of a module's components by name, nil for
+// healthy ones and the reason otherwise.
+type HealthReporter interface {
+	DashboardHealth(ctx context.Context) map[string]error
+}
+
+// BackendReporter reports the backends of a proxy and the state of their circuit
+// breakers, one map per backend with the keys of BackendStatus in JSON: "name",
+// "url", "healthy", "circuit_state", "draining" and "in_flight".
+type BackendReporter interface {
+	DashboardBackends(ctx context.Context) []map[string]any
+}
+
+// BackendDrainer drains a backend, so it stops receiving new requests once those in
+// flight finish.
+type BackendDrainer interface {
+	DrainBackend(ctx context.Context, backend string) error
+}
+
+// FeatureFlagReporter reports the values of feature flags, for a tenant when
+// tenantID is not empty.
+type FeatureFlagReporter interface {
+	DashboardFeatureFlags(ctx context.Context, tenantID modular.TenantID) map[string]bool
+}
+
+// MaintenanceController switches maintenance mode on and off.
+type MaintenanceController interface {
+	SetMaintenance(ctx context.Context, enabled bool) error
+	InMaintenance() bool
+}
+
+// ConfigReloader reloads configuration.
+type ConfigReloader interface {
+	ReloadConfig(ctx context.Context) error
+}
+
+// JobController pauses, resumes and runs the jobs of a scheduler, identified by job
+// ID. The scheduler module implements it.
+type JobController interface {
+	PauseJob(jobID string) error
+	ResumeJob(jobID string) error
+	TriggerNow(jobID string) error
+}
+
+// JobAction is an action a JobController performs on a job.
+type JobAction string
+
+const (
+	// JobActionPause stops the job from running on its schedule
+	JobActionPause JobAction = "pause"
+	// JobActionResume lets a paused job run on its schedule again
+	JobActionResume JobAction = "resume"
+	// JobActionTrigger runs the job right away
+	JobActionTrigger JobAction = "trigger"
+)
+
+// ComponentHealth is the health of a component reported by a HealthReporter.
+type ComponentHealth struct {
+	Source  string `json:"source"`
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Message string `json:"message,omitempty"`
+}
+
+// BackendStatus is the state of a backend reported by a BackendReporter.
+type BackendStatus struct {
+	Source  string `json:"source"`
+	Name    string `json:"name"`
+	URL     string `json:"url,omitempty"`
+	Healthy bool   `json:"healthy"`
+	// CircuitState is the state of the backend's circuit breaker, such as "closed",
+	// "open" or "half-open", empty without one
+	CircuitState string `json:"circuit_state,omitempty"`
+	Draining     bool   `json:"draining"`
+	InFlight     int    `json:"in_flight"`
+}
+
+// FeatureFlag is the value of a feature flag reported by a FeatureFlagReporter.
+type FeatureFlag struct {
+	Source  string `json:"source"`
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// ModuleInfo describes a module of the application.
+type ModuleInfo struct {
+	Name         string   `json:"name"`
+	Dependencies []string `json:"dependencies"`
+	Services     []string `json:"services"`
+	Startable    bool     `json:"startable"`
+	Stoppable    bool     `json:"stoppable"`
+}
+
+// MaintenanceStatus reports whether a MaintenanceController is in maintenance mode.
+type MaintenanceStatus struct {
+	Source  string `json:"source"`
+	Enabled bool   `json:"enabled"`
+}
+
+// RecentEvent is an event observed by the dashboard.
+type RecentEvent struct {
+	ID     string          `json:"id"`
+	Type   string          `json:"type"`
+	Source string          `json:"source"`
+	Time   time.Time       `json:"time"`
+	Data   json.RawMessage `json:"data,omitempty"`
+}
+
+// Actions lists the actions the dashboard can perform.
+type Actions struct {
+	Drain       []string `json:"drain"`       // sources of backend drainers
+	Maintenance []string `json:"maintenance"` // sources of maintenance controllers
+	Reload      []string `json:"reload"`      // sources of config reloaders
+	Jobs        []string `json:"jobs"`        // sources of job controllers
+}
+
+// State is everything the dashboard shows.
+type State struct {
+	StartedAt      time.Time           `json:"started_at"`
+	Tenant         string              `json:"tenant,omitempty"`
+	Modules        []ModuleInfo        `json:"modules"`
+	Health         []ComponentHealth   `json:"health"`
+	Backends       []BackendStatus     `json:"backends"`
+	Tenants        []string            `json:"tenants"`
+	FeatureFlags   []FeatureFlag       `json:"feature_flags"`
+	Maintenance    []MaintenanceStatus `json:"maintenance"`
+	Events         []RecentEvent       `json:"events"`
+	Actions        Actions             `json:"actions"`
+	ReadOnly       bool                `json:"read_only"`
+	RefreshSeconds float64             `json:"refresh_seconds"`
+}
+
+// DashboardService reports the dashboard's state and performs its actions from code.
+type DashboardService interface {
+	// State collects what the dashboard shows, with feature flags evaluated for
+	// tenantID when it is not empty
+	State(ctx context.Context, tenantID modular.TenantID) State
+
+	// DrainBackend drains the backend of the BackendDrainer found in source
+	DrainBackend(ctx context.Context, source, backend string) error
+
+	// SetMaintenance switches maintenance mode of every MaintenanceController
+	SetMaintenance(ctx context.Context, enabled bool) error
+
+	// ReloadConfig reloads the config of every ConfigReloader
+	ReloadConfig(ctx context.Context) error
+
+	// ControlJob performs action on a job of the JobController found in source
+	ControlJob(ctx context.Context, source, jobID string, action JobAction) error
+
+	// Addr returns the address the admin listener accepts connections on
+	Addr() (string, error)
+}
@@ -0,0 +1,380 @@
+package admindashboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// provider is a module or service implementing one of the dashboard's interfaces.
+type provider[T any] struct {
+	source string
+	value  T
+}
+
+// providers returns the modules, then the other registered services, implementing T,
+// each sorted by name. The dashboard itself, which performs its actions through the
+// others, is left out.
+func providers[T any](m *AdminDashboardModule) []provider[T] {
+	app := m.app
+	var found []provider[T]
+	seen := map[any]bool{m: true}
+	add := func(source string, value T) {
+		if reflect.TypeOf(value).Comparable() {
+			if seen[value] {
+				return
+			}
+			seen[value] = true
+		}
+		found = append(found, provider[T]{source: source, value: value})
+	}
+
+	modules := app.GetAllModules()
+	names := make([]string, 0, len(modules))
+	for name := range modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := modules[name].(T); ok {
+			add(name, value)
+		}
+	}
+
+	entries := app.GetServicesByInterface(reflect.TypeOf((*T)(nil)).Elem())
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ActualName < entries[j].ActualName })
+	for _, entry := range entries {
+		if value, ok := entry.Service.(T); ok {
+			add(entry.ActualName, value)
+		}
+	}
+	return found
+}
+
+// State collects what the dashboard shows, with feature flags evaluated for tenantID
+// when it is not empty.
+func (m *AdminDashboardModule) State(ctx context.Context, tenantID modular.TenantID) State {
+	state := State{
+		StartedAt:      m.app.StartTime(),
+		Tenant:         string(tenantID),
+		Modules:        m.modules(),
+		Health:         []ComponentHealth{},
+		Backends:       []BackendStatus{},
+		Tenants:        []string{},
+		FeatureFlags:   []FeatureFlag{},
+		Maintenance:    []MaintenanceStatus{},
+		Events:         m.events.recent(),
+		ReadOnly:       m.config.ReadOnly,
+		RefreshSeconds: m.config.RefreshInterval.Seconds(),
+		Actions:        Actions{Drain: []string{}, Maintenance: []string{}, Reload: []string{}, Jobs: []string{}},
+	}
+
+	for _, p := range providers[HealthReporter](m) {
+		components := p.value.DashboardHealth(ctx)
+		names := make([]string, 0, len(components))
+		for name := range components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			health := ComponentHealth{Source: p.source, Name: name, Healthy: components[name] == nil}
+			if !health.Healthy {
+				health.Message = components[name].Error()
+			}
+			state.Health = append(state.Health, health)
+		}
+	}
+	for _, p := range providers[BackendReporter](m) {
+		for _, report := range p.value.DashboardBackends(ctx) {
+			backend := backendStatus(report)
+			backend.Source = p.source
+			state.Backends = append(state.Backends, backend)
+		}
+	}
+	for _, p := range providers[FeatureFlagReporter](m) {
+		flags := p.value.DashboardFeatureFlags(ctx, tenantID)
+		names := make([]string, 0, len(flags))
+		for name := range flags {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			state.FeatureFlags = append(state.FeatureFlags, FeatureFlag{Source: p.source, Name: name, Enabled: flags[name]})
+		}
+	}
+	for _, p := range providers[MaintenanceController](m) {
+		state.Maintenance = append(state.Maintenance, MaintenanceStatus{Source: p.source, Enabled: p.value.InMaintenance()})
+		state.Actions.Maintenance = append(state.Actions.Maintenance, p.source)
+	}
+	for _, p := range providers[BackendDrainer](m) {
+		state.Actions.Drain = append(state.Actions.Drain, p.source)
+	}
+	for _, p := range m.reloaders() {
+		state.Actions.Reload = append(state.Actions.Reload, p.source)
+	}
+	for _, p := range providers[JobController](m) {
+		state.Actions.Jobs = append(state.Actions.Jobs, p.source)
+	}
+
+	var tenants modular.TenantService
+	if err := m.app.GetService("tenantService", &tenants); err == nil && tenants != nil {
+		for _, tenant := range tenants.GetTenants() {
+			state.Tenants = append(state.Tenants, string(tenant))
+		}
+		sort.Strings(state.Tenants)
+	}
+	return state
+}
+
+// backendStatus reads a backend reported by a BackendReporter, ignoring values of
+// the wrong type.
+func backendStatus(report map[string]any) BackendStatus {
+	var status BackendStatus
+	for key, value := range report {
+		var field any
+		switch key {
+		case "name":
+			field = &status.Name
+		case "url":
+			field = &status.URL
+		case "healthy":
+			field = &status.Healthy
+		case "circuit_state":
+			field = &status.CircuitState
+		case "draining":
+			field = &status.Draining
+		case "in_flight":
+			field = &status.InFlight
+		default:
+			continue
+		}
+		// Decode each value on its own, so one of the wrong type leaves only its field unset
+		if data, err := json.Marshal(value); err == nil {
+			_ = json.Unmarshal(data, field)
+		}
+	}
+	return status
+}
+
+// reloaders returns the application, when it reloads config, followed by the other
+// ConfigReloaders.
+func (m *AdminDashboardModule) reloaders() []provider[ConfigReloader] {
+	var found []provider[ConfigReloader]
+	if reloader, ok := m.app.(ConfigReloader); ok {
+		found = append(found, provider[ConfigReloader]{source: "application", value: reloader})
+	}
+	return append(found, providers[ConfigReloader](m)...)
+}
+
+// modules describes the application's modules, sorted by name.
+func (m *AdminDashboardModule) modules() []ModuleInfo {
+	modules := m.app.GetAllModules()
+	infos := make([]ModuleInfo, 0, len(modules))
+	for name, module := range modules {
+		info := ModuleInfo{
+			Name:         name,
+			Dependencies: []string{},
+			Services:     []string{},
+		}
+		if aware, ok := module.(modular.DependencyAware); ok && aware.Dependencies() != nil {
+			info.Dependencies = aware.Dependencies()
+		}
+		if services := m.app.GetServicesByModule(name); services != nil {
+			info.Services = services
+		}
+		_, info.Startable = module.(modular.Startable)
+		_, info.Stoppable = module.(modular.Stoppable)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// DrainBackend drains the backend of the BackendDrainer found in source.
+func (m *AdminDashboardModule) DrainBackend(ctx context.Context, source, backend string) error {
+	for _, p := range providers[BackendDrainer](m) {
+		if p.source != source {
+			continue
+		}
+		err := p.value.DrainBackend(ctx, backend)
+		if err != nil {
+			err = fmt.Errorf("draining backend %s of %s: %w", backend, source, err)
+		}
+		m.actionDone(ctx, "drain", source+"/"+backend, err)
+		return err
+	}
+	err := fmt.Errorf("%w: %s does not drain backends", ErrUnknownSource, source)
+	m.actionDone(ctx, "drain", source+"/"+backend, err)
+	return err
+}
+
+// SetMaintenance switches maintenance mode of every MaintenanceController.
+func (m *AdminDashboardModule) SetMaintenance(ctx context.Context, enabled bool) error {
+	action := "maintenance.disable"
+	if enabled {
+		action = "maintenance.enable"
+	}
+	controllers := providers[MaintenanceController](m)
+	if len(controllers) == 0 {
+		m.actionDone(ctx, action, "", ErrActionUnavailable)
+		return ErrActionUnavailable
+	}
+	var errs []error
+	for _, p := range controllers {
+		if err := p.value.SetMaintenance(ctx, enabled); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.source, err))
+		}
+	}
+	err := errors.Join(errs...)
+	m.actionDone(ctx, action, "", err)
+	return err
+}
+
+// ReloadConfig reloads the config of every ConfigReloader.
+func (m *AdminDashboardModule) ReloadConfig(ctx context.Context) error {
+	reloaders := m.reloaders()
+	if len(reloaders) == 0 {
+		m.actionDone(ctx, "reload", "", ErrActionUnavailable)
+		return ErrActionUnavailable
+	}
+	var errs []error
+	for _, p := range reloaders {
+		if err := p.value.ReloadConfig(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.source, err))
+		}
+	}
+	err := errors.Join(errs...)
+	m.actionDone(ctx, "reload", "", err)
+	return err
+}
+
+// ControlJob performs action on a job of the JobController found in source.
+func (m *AdminDashboardModule) ControlJob(ctx context.Context, source, jobID string, action JobAction) error {
+	name := "job." + string(action)
+	target := source + "/" + jobID
+	for _, p := range providers[JobController](m) {
+		if p.source != source {
+			continue
+		}
+		var err error
+		switch action {
+		case JobActionPause:
+			err = p.value.PauseJob(jobID)
+		case JobActionResume:
+			err = p.value.ResumeJob(jobID)
+		case JobActionTrigger:
+			err = p.value.TriggerNow(jobID)
+		default:
+			err = fmt.Errorf("%w: %q", ErrUnknownJobAction, action)
+			m.actionDone(ctx, name, target, err)
+			return err
+		}
+		if err != nil {
+			err = fmt.Errorf("%s job %s of %s: %w", action, jobID, source, err)
+		}
+		m.actionDone(ctx, name, target, err)
+		return err
+	}
+	err := fmt.Errorf("%w: %s does not control jobs", ErrUnknownSource, source)
+	m.actionDone(ctx, name, target, err)
+	return err
+}
+
+// actionDone logs and emits the outcome of an action.
+func (m *AdminDashboardModule) actionDone(ctx context.Context, action, target string, err error) {
+	if err != nil {
+		m.logger.Warn("Admin dashboard action failed", "action", action, "target", target, "error", err)
+		m.emitEvent(ctx, EventTypeActionFailed, map[string]interface{}{
+			"action": action,
+			"target": target,
+			"error":  err.Error(),
+		})
+		return
+	}
+	m.logger.Info("Admin dashboard action succeeded", "action", action, "target", target)
+	m.emitEvent(ctx, EventTypeActionSucceeded, map[string]interface{}{
+		"action": action,
+		"target": target,
+	})
+}
+
+// OnEvent implements modular.Observer, recording the event for the dashboard.
+func (m *AdminDashboardModule) OnEvent(ctx context.Context, event cloudevents.Event) error {
+	m.events.add(RecentEvent{
+		ID:     event.ID(),
+		Type:   event.Type(),
+		Source: event.Source(),
+		Time:   event.Time(),
+		Data:   eventData(event.Data()),
+	})
+	return nil
+}
+
+// ObserverID implements modular.Observer.
+func (m *AdminDashboardModule) ObserverID() string {
+	return m.name
+}
+
+// eventData returns an event's data as JSON, quoting data that isn't JSON.
+func eventData(data []byte) json.RawMessage {
+	if len(data) == 0 {
+		return nil
+	}
+	if json.Valid(data) {
+		return append(json.RawMessage(nil), data...)
+	}
+	quoted, _ := json.Marshal(string(data))
+	return quoted
+}
+
+// eventBuffer keeps the most recent events. It keeps none until resized.
+type eventBuffer struct {
+	mu     sync.Mutex
+	size   int
+	events []RecentEvent // oldest first once full, starting at next
+	next   int
+}
+
+// resize changes how many events the buffer keeps, keeping the most recent ones.
+func (b *eventBuffer) resize(size int) {
+	recent := b.recent()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.size, b.next = size, 0
+	b.events = b.events[:0]
+	for i := min(len(recent), size) - 1; i >= 0; i-- {
+		b.events = append(b.events, recent[i])
+	}
+}
+
+func (b *eventBuffer) add(event RecentEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.size <= 0 {
+		return
+	}
+	if len(b.events) < b.size {
+		b.events = append(b.events, event)
+		return
+	}
+	b.events[b.next] = event
+	b.next = (b.next + 1) % b.size
+}
+
+// recent returns the events, most recent first.
+func (b *eventBuffer) recent() []RecentEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	recent := make([]RecentEvent, 0, len(b.events))
+	for i := len(b.events) - 1; i >= 0; i-- {
+		recent = append(recent, b.events[(b.next+i)%len(b.events)])
+	}
+	return recent
+}
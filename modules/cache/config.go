@@ -31,9 +31,9 @@ import (
 //	CACHE_MAX_ITEMS=10000
 type CacheConfig struct {
 	// Engine specifies the cache engine to use.
-	// Supported values: "memory", "redis"
+	// Supported values: "memory", "redis", "tiered"
 	// Default: "memory"
-	Engine string `json:"engine" yaml:"engine" env:"ENGINE" default:"memory" validate:"oneof=memory redis"`
+	Engine string `json:"engine" yaml:"engine" env:"ENGINE" default:"memory" validate:"oneof=memory redis tiered"`
 
 	// DefaultTTL is the default time-to-live for cache entries.
 	// Used when no explicit TTL is provided in cache operations.
@@ -68,4 +68,25 @@ type CacheConfig struct {
 	// Connections older than this will be closed and recreated.
 	// Helps prevent connection staleness in long-running applications.
 	ConnectionMaxAge time.Duration `json:"connectionMaxAge" yaml:"connectionMaxAge" env:"CONNECTION_MAX_AGE" default:"3600s"`
+
+	// LocalMaxItems is the maximum number of items kept in the in-memory tier of
+	// the tiered engine. When this limit is reached, least recently used items are
+	// evicted. Only applicable to tiered cache engine.
+	LocalMaxItems int `json:"localMaxItems" yaml:"localMaxItems" env:"LOCAL_MAX_ITEMS" default:"1000"`
+
+	// LocalTTL caps how long the tiered engine keeps an item in memory before
+	// reading it from Redis again. It bounds how stale a local copy can get when an
+	// invalidation from another replica is missed.
+	// Only applicable to tiered cache engine.
+	LocalTTL time.Duration `json:"localTTL" yaml:"localTTL" env:"LOCAL_TTL" default:"30s"`
+
+	// TTLJitter randomizes the TTLs of the tiered engine by up to this fraction,
+	// e.g. 0.1 for ±10%, so items written together don't expire together.
+	// Must be between 0 and 1.
+	TTLJitter float64 `json:"ttlJitter" yaml:"ttlJitter" env:"TTL_JITTER"`
+
+	// InvalidationTopic is the eventbus topic the tiered engine broadcasts
+	// invalidations on, so other replicas drop their local copies of changed keys.
+	// Only used when an eventbus.provider service is available.
+	InvalidationTopic string `json:"invalidationTopic" yaml:"invalidationTopic" env:"INVALIDATION_TOPIC" default:"cache.invalidate"`
 }
@@ -98,3 +98,19 @@ type CacheEngine interface {
 	// The context can be used for operation timeouts.
 	DeleteMulti(ctx context.Context, keys []string) error
 }
+
+// Incrementer is implemented by cache engines that keep integer counters. It is
+// separate from CacheEngine, so engines without counters remain valid engines;
+// CacheModule.Increment returns ErrIncrementUnsupported for them.
+type Incrementer interface {
+	// Increment atomically adds delta to the integer counter stored at key and
+	// returns its new value. A missing or expired key starts from zero and expires
+	// after ttl; incrementing an existing counter leaves its expiration unchanged.
+	// Returns ErrNotCounter if key holds a value that is not a counter.
+	//
+	// For network-based caches the counter is shared by every process using the
+	// backend, so it can coordinate limits across replicas.
+	//
+	// The context can be used for operation timeouts.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
@@ -15,9 +15,21 @@ var (
 	// ErrInvalidValue is returned when the value cannot be stored in the cache
 	ErrInvalidValue = errors.New("invalid cache value")
 
+	// ErrNotCounter is returned when incrementing a key that holds a non-counter value
+	ErrNotCounter = errors.New("cache value is not a counter")
+
+	// ErrIncrementUnsupported is returned when incrementing a counter with an engine that doesn't implement Incrementer
+	ErrIncrementUnsupported = errors.New("cache engine does not support counters")
+
 	// ErrNotConnected is returned when an operation is attempted on a cache that is not connected
 	ErrNotConnected = errors.New("cache not connected")
 
+	// ErrInvalidTTLJitter is returned when the TTL jitter is not between 0 and 1
+	ErrInvalidTTLJitter = errors.New("ttl jitter must be between 0 and 1")
+
+	// ErrSubscribeUnsupported is returned when the invalidation bus can't deliver invalidations
+	ErrSubscribeUnsupported = errors.New("invalidation bus does not support subscriptions")
+
 	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
 	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
 )
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// HealthCheckStatus reports the cache to the application's readiness, which makes
+// the module a modular.HealthStatusReporter. A Redis cache is unhealthy while Redis
+// doesn't answer, and a tiered cache degraded, since its local tier keeps serving.
+func (m *CacheModule) HealthCheckStatus(ctx context.Context) (status, message string, details map[string]any) {
+	if m.cacheEngine == nil {
+		return "unhealthy", ErrNotConnected.Error(), nil
+	}
+	details = map[string]any{"engine": m.config.Engine}
+
+	var err error
+	switch engine := m.cacheEngine.(type) {
+	case *RedisCache:
+		if err = engine.Ping(ctx); err != nil {
+			return "unhealthy", err.Error(), details
+		}
+	case *TieredCache:
+		if err = engine.Ping(ctx); err != nil {
+			return "degraded", "serving from the local tier: " + err.Error(), details
+		}
+	}
+	return "healthy", "", details
+}
+
+// Ping checks that Redis answers.
+func (c *RedisCache) Ping(ctx context.Context) error {
+	if c.client == nil {
+		return ErrNotConnected
+	}
+	if err := c.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping Redis server: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the Redis tier answers.
+func (c *TieredCache) Ping(ctx context.Context) error {
+	return c.remote.Ping(ctx)
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheModuleHealthCheckStatus(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	ctx := context.Background()
+
+	tiered := &CacheModule{config: tieredTestConfig(s), cacheEngine: newTieredTestCache(t, tieredTestConfig(s), nil)}
+	remote := NewRedisCache(&CacheConfig{Engine: "redis", RedisURL: "redis://" + s.Addr()})
+	redisModule := &CacheModule{config: remote.config, cacheEngine: remote}
+
+	status, _, _ := redisModule.HealthCheckStatus(ctx)
+	assert.Equal(t, "unhealthy", status, "a Redis cache is unhealthy before it connects")
+	require.NoError(t, remote.Connect(ctx))
+	t.Cleanup(func() { _ = remote.Close(ctx) })
+
+	for _, module := range []*CacheModule{redisModule, tiered} {
+		status, message, details := module.HealthCheckStatus(ctx)
+		assert.Equal(t, "healthy", status)
+		assert.Empty(t, message)
+		assert.Equal(t, module.config.Engine, details["engine"])
+	}
+
+	s.SetError("server unavailable")
+	status, message, _ := redisModule.HealthCheckStatus(ctx)
+	assert.Equal(t, "unhealthy", status)
+	assert.Contains(t, message, "server unavailable")
+	status, message, _ = tiered.HealthCheckStatus(ctx)
+	assert.Equal(t, "degraded", status)
+	assert.Contains(t, message, "serving from the local tier")
+
+	memory := &CacheModule{config: &CacheConfig{Engine: "memory"}, cacheEngine: NewMemoryCache(&CacheConfig{})}
+	status, _, _ = memory.HealthCheckStatus(ctx)
+	assert.Equal(t, "healthy", status)
+}
@@ -201,6 +201,40 @@ func (c *MemoryCache) DeleteMulti(ctx context.Context, keys []string) error {
 	return nil
 }
 
+// Increment adds delta to the counter stored at key, creating it with ttl
+func (c *MemoryCache) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	c.ensureCleanupRun(ctx)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	item, found := c.items[key]
+	if found && !item.expiration.IsZero() && time.Now().After(item.expiration) {
+		found = false
+	}
+	if found {
+		count, ok := item.value.(int64)
+		if !ok {
+			return 0, ErrNotCounter
+		}
+		item.value = count + delta
+		c.items[key] = item
+		return count + delta, nil
+	}
+
+	if c.config.MaxItems > 0 && len(c.items) >= c.config.MaxItems {
+		if _, exists := c.items[key]; !exists {
+			return 0, ErrCacheFull
+		}
+	}
+	var exp time.Time
+	if ttl > 0 {
+		exp = time.Now().Add(ttl)
+	}
+	c.items[key] = cacheItem{value: delta, expiration: exp}
+	return delta, nil
+}
+
 // startCleanupTimer starts the cleanup timer for expired items
 func (c *MemoryCache) startCleanupTimer(ctx context.Context) {
 	// Run cleanup immediately on start
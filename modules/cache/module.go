@@ -9,6 +9,8 @@
 // The cache module supports the following engines:
 //   - "memory": In-memory cache with LRU eviction and TTL support
 //   - "redis": Redis-based cache with connection pooling and persistence
+//   - "tiered": In-memory LRU in front of Redis, with invalidations broadcast
+//     to other replicas through the eventbus module
 //
 // # Configuration
 //
@@ -61,6 +63,11 @@
 //	err := cache.SetMulti(ctx, items, time.Minute*10)
 //
 //	results, err := cache.GetMulti(ctx, []string{"key1", "key2"})
+//
+//	// Load a value once on a miss, even with many concurrent callers
+//	user, err := cache.GetOrLoad(ctx, "user:123", time.Minute, func(ctx context.Context) (interface{}, error) {
+//	    return loadUser(ctx, "123")
+//	})
 package cache
 
 import (
@@ -71,6 +78,7 @@ import (
 
 	"github.com/CrisisTextLine/modular"
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"golang.org/x/sync/singleflight"
 )
 
 // ModuleName is the unique identifier for the cache module.
@@ -80,6 +88,10 @@ const ModuleName = "cache"
 // Other modules can use this name to request the cache service through dependency injection.
 const ServiceName = "cache.provider"
 
+// EventBusServiceName is the name of the optional eventbus service the tiered
+// engine broadcasts invalidations on.
+const EventBusServiceName = "eventbus.provider"
+
 // CacheModule provides caching functionality for the modular framework.
 // It supports multiple cache backends (memory and Redis) and provides a unified
 // interface for caching operations including TTL management, batch operations,
@@ -110,6 +122,12 @@ type CacheModule struct {
 	// EmitEvent (read) when asynchronous emissions occur before observer registration completes.
 	subject   modular.Subject
 	subjectMu sync.RWMutex
+	// invalidationBus carries the invalidations of the tiered engine, if available
+	invalidationBus InvalidationBus
+	// usesEventBus is set when the application provides an eventbus service
+	usesEventBus bool
+	// loads coalesces concurrent GetOrLoad calls for the same key
+	loads singleflight.Group
 }
 
 // NewModule creates a new instance of the cache module.
@@ -170,6 +188,7 @@ func (m *CacheModule) RegisterConfig(app modular.Application) error {
 // Supported cache engines:
 //   - "memory": In-memory cache with LRU eviction
 //   - "redis": Redis-based distributed cache
+//   - "tiered": In-memory LRU in front of Redis
 //   - fallback: defaults to memory cache for unknown engines
 func (m *CacheModule) Init(app modular.Application) error {
 	// Retrieve the registered config section for access
@@ -181,6 +200,10 @@ func (m *CacheModule) Init(app modular.Application) error {
 	m.config = cfg.GetConfig().(*CacheConfig)
 	m.logger = app.Logger()
 
+	if m.config.TTLJitter < 0 || m.config.TTLJitter > 1 {
+		return fmt.Errorf("%w: %v", ErrInvalidTTLJitter, m.config.TTLJitter)
+	}
+
 	// Initialize the appropriate cache engine based on configuration
 	switch m.config.Engine {
 	case "memory":
@@ -196,6 +219,20 @@ func (m *CacheModule) Init(app modular.Application) error {
 	case "redis":
 		m.cacheEngine = NewRedisCache(m.config)
 		m.logger.Info("Initialized Redis cache engine", "url", m.config.RedisURL)
+	case "tiered":
+		tiered := NewTieredCache(m.config)
+		tiered.SetEventEmitter(func(ctx context.Context, event cloudevents.Event) {
+			if err := m.EmitEvent(ctx, event); err != nil {
+				m.logger.Debug("Failed to emit cache event from tiered engine", "error", err, "event_type", event.Type())
+			}
+		})
+		if m.invalidationBus != nil {
+			tiered.SetInvalidationBus(m.invalidationBus)
+		} else {
+			m.logger.Warn("No eventbus service available, tiered cache invalidations won't reach other replicas")
+		}
+		m.cacheEngine = tiered
+		m.logger.Info("Initialized tiered cache engine", "url", m.config.RedisURL, "localMaxItems", m.config.LocalMaxItems)
 	default:
 		memCache := NewMemoryCache(m.config)
 		// Provide event emission callback to memory cache for fallback case too
@@ -286,6 +323,17 @@ func (m *CacheModule) Dependencies() []string {
 	return nil
 }
 
+// ShutdownPhaseOrder returns the storage phase of the application's shutdown,
+// modular.ShutdownPhaseStorage, so the cache closes after the modules using it
+// stopped. With an event bus to broadcast invalidations on, the cache stops in the
+// consumers phase instead, modular.ShutdownPhaseConsumers, before the event bus.
+func (m *CacheModule) ShutdownPhaseOrder() int {
+	if m.usesEventBus {
+		return 400
+	}
+	return 500
+}
+
 // ProvidesServices declares services provided by this module.
 // The cache module provides a cache service that can be injected into other modules.
 //
@@ -302,9 +350,18 @@ func (m *CacheModule) ProvidesServices() []modular.ServiceProvider {
 }
 
 // RequiresServices declares services required by this module.
-// The cache module operates independently and requires no external services.
+// The cache module operates independently; the tiered engine optionally uses the
+// eventbus service to broadcast invalidations.
+//
+// Optional services:
+//   - "eventbus.provider": Broadcasts tiered cache invalidations to other replicas
 func (m *CacheModule) RequiresServices() []modular.ServiceDependency {
-	return nil
+	return []modular.ServiceDependency{
+		{
+			Name:     EventBusServiceName,
+			Required: false,
+		},
+	}
 }
 
 // Constructor provides a dependency injection constructor for the module.
@@ -312,6 +369,15 @@ func (m *CacheModule) RequiresServices() []modular.ServiceDependency {
 // the module instance with any required services.
 func (m *CacheModule) Constructor() modular.ModuleConstructor {
 	return func(app modular.Application, services map[string]any) (modular.Module, error) {
+		if busSvc, exists := services[EventBusServiceName]; exists {
+			m.usesEventBus = true
+			if bus, ok := busSvc.(InvalidationBus); ok {
+				m.invalidationBus = bus
+			} else {
+				app.Logger().Warn("eventbus.provider service found but does not implement InvalidationBus",
+					"type", fmt.Sprintf("%T", busSvc))
+			}
+		}
 		return m, nil
 	}
 }
@@ -364,6 +430,41 @@ func (m *CacheModule) Get(ctx context.Context, key string) (interface{}, bool) {
 	return value, found
 }
 
+// GetOrLoad retrieves a cached item by key, calling load to produce it on a miss and
+// caching the result with ttl, or the default TTL if ttl is 0. Concurrent callers
+// missing the same key share a single call to load, so an expired popular key
+// doesn't send a stampede of requests to the underlying source. Errors from load
+// are returned and nothing is cached.
+//
+// Example:
+//
+//	value, err := cache.GetOrLoad(ctx, "user:123", time.Minute, func(ctx context.Context) (interface{}, error) {
+//	    return db.LoadUser(ctx, "123")
+//	})
+func (m *CacheModule) GetOrLoad(ctx context.Context, key string, ttl time.Duration, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	if value, found := m.Get(ctx, key); found {
+		return value, nil
+	}
+	value, err, _ := m.loads.Do(key, func() (interface{}, error) {
+		// A caller that loaded the key may have finished since the miss above
+		if value, found := m.cacheEngine.Get(ctx, key); found {
+			return value, nil
+		}
+		value, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.Set(ctx, key, value, ttl); err != nil {
+			m.logger.Warn("Failed to cache loaded item", "cache_key", key, "error", err)
+		}
+		return value, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load cache item %s: %w", key, err)
+	}
+	return value, nil
+}
+
 // Set stores an item in the cache with an optional TTL.
 // If ttl is 0, uses the default TTL from configuration.
 // The value can be any serializable type.
@@ -537,6 +638,34 @@ func (m *CacheModule) DeleteMulti(ctx context.Context, keys []string) error {
 	return nil
 }
 
+// Increment atomically adds delta to the integer counter stored at key and returns
+// its new value. A missing key starts from zero and expires after ttl, or the default
+// TTL if ttl is 0. With the Redis engine the counter is shared by every replica
+// using the same Redis, which makes it suitable for distributed rate limits and
+// quotas. No events are emitted for increments, as they are typically on hot paths.
+// Returns ErrIncrementUnsupported when the engine doesn't implement Incrementer.
+//
+// Example:
+//
+//	count, err := cache.Increment(ctx, "ratelimit:tenant-a:28512", 1, 2*time.Minute)
+//	if err == nil && count > limit {
+//	    // reject the request
+//	}
+func (m *CacheModule) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if ttl == 0 {
+		ttl = m.config.DefaultTTL
+	}
+	incrementer, ok := m.cacheEngine.(Incrementer)
+	if !ok {
+		return 0, fmt.Errorf("%w: %T", ErrIncrementUnsupported, m.cacheEngine)
+	}
+	count, err := incrementer.Increment(ctx, key, delta, ttl)
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment cache counter: %w", err)
+	}
+	return count, nil
+}
+
 // RegisterObservers implements the ObservableModule interface.
 // This allows the cache module to register as an observer for events it's interested in.
 func (m *CacheModule) RegisterObservers(subject modular.Subject) error {
@@ -651,3 +651,80 @@ func TestRedisGetWithServerError(t *testing.T) {
 	// Close cache
 	cache.Close(ctx)
 }
+
+// TestMemoryCacheIncrement tests counters in the memory cache
+func TestMemoryCacheIncrement(t *testing.T) {
+	t.Parallel()
+	cache := NewMemoryCache(&CacheConfig{CleanupInterval: time.Minute, MaxItems: 10})
+	ctx := context.Background()
+
+	count, err := cache.Increment(ctx, "hits", 1, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	count, err = cache.Increment(ctx, "hits", 4, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	count, err = cache.Increment(ctx, "hits", -2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = cache.Increment(ctx, "short", 1, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	time.Sleep(5 * time.Millisecond)
+	count, err = cache.Increment(ctx, "short", 1, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "expired counters start over")
+
+	require.NoError(t, cache.Set(ctx, "name", "value", time.Minute))
+	_, err = cache.Increment(ctx, "name", 1, time.Minute)
+	assert.ErrorIs(t, err, ErrNotCounter)
+}
+
+// counterlessEngine is an engine written without counters.
+type counterlessEngine struct{ CacheEngine }
+
+// TestCacheModuleIncrementWithoutIncrementer tests engines that don't keep counters
+func TestCacheModuleIncrementWithoutIncrementer(t *testing.T) {
+	t.Parallel()
+	config := &CacheConfig{CleanupInterval: time.Minute, MaxItems: 10, DefaultTTL: time.Minute}
+	module := &CacheModule{config: config, cacheEngine: NewMemoryCache(config)}
+	count, err := module.Increment(context.Background(), "hits", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	module.cacheEngine = counterlessEngine{NewMemoryCache(config)}
+	_, err = module.Increment(context.Background(), "hits", 1, 0)
+	assert.ErrorIs(t, err, ErrIncrementUnsupported)
+}
+
+// TestRedisIncrement tests counters in Redis
+func TestRedisIncrement(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+
+	cache := NewRedisCache(&CacheConfig{Engine: "redis", RedisURL: "redis://" + s.Addr()})
+	ctx := context.Background()
+	_, err := cache.Increment(ctx, "hits", 1, time.Minute)
+	assert.ErrorIs(t, err, ErrNotConnected)
+
+	require.NoError(t, cache.Connect(ctx))
+	defer cache.Close(ctx)
+
+	count, err := cache.Increment(ctx, "hits", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+	s.FastForward(30 * time.Second)
+	count, err = cache.Increment(ctx, "hits", 3, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), count)
+	assert.Equal(t, 30*time.Second, s.TTL("hits"), "incrementing keeps the counter's expiration")
+
+	value, found := cache.Get(ctx, "hits")
+	assert.True(t, found)
+	assert.InDelta(t, 5, value, 0)
+
+	require.NoError(t, cache.Set(ctx, "name", "value", time.Minute))
+	_, err = cache.Increment(ctx, "name", 1, time.Minute)
+	assert.ErrorIs(t, err, ErrNotCounter)
+}
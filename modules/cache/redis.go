@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -16,6 +17,16 @@ type RedisCache struct {
 	client *redis.Client
 }
 
+// incrementScript adds to a counter and sets the expiration of counters it creates.
+// KEYS[1] is the counter, ARGV[1] the delta and ARGV[2] the TTL in milliseconds.
+var incrementScript = redis.NewScript(`
+local count = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return count
+`)
+
 // NewRedisCache creates a new Redis cache engine
 func NewRedisCache(config *CacheConfig) *RedisCache {
 	return &RedisCache{
@@ -190,3 +201,21 @@ func (c *RedisCache) DeleteMulti(ctx context.Context, keys []string) error {
 	}
 	return nil
 }
+
+// Increment adds delta to the counter stored at key with INCRBY, setting ttl on
+// counters it creates. Counters are stored as plain integers, so Get returns them as
+// float64 like any other JSON number.
+func (c *RedisCache) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	if c.client == nil {
+		return 0, ErrNotConnected
+	}
+
+	count, err := incrementScript.Run(ctx, c.client, []string{key}, delta, ttl.Milliseconds()).Int64()
+	if err != nil {
+		if strings.Contains(err.Error(), "not an integer") {
+			return 0, fmt.Errorf("%w: %s", ErrNotCounter, key)
+		}
+		return 0, fmt.Errorf("failed to increment Redis key %s: %w", key, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,460 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+// InvalidationBus broadcasts the invalidations of a tiered cache to the other
+// replicas sharing its Redis. The eventbus module's eventbus.provider service
+// implements it.
+//
+// To receive the invalidations of other replicas, the bus also needs a method
+//
+//	Subscribe(ctx context.Context, topic string, handler H) (S, error)
+//
+// where H is a function type func(context.Context, cloudevents.Event) error and S
+// has a Cancel() error method, as the eventbus module provides.
+type InvalidationBus interface {
+	Publish(ctx context.Context, topic string, payload interface{}) error
+}
+
+// invalidation is the payload of an invalidation broadcast.
+type invalidation struct {
+	Source string   `json:"source"`
+	Keys   []string `json:"keys,omitempty"`
+	Flush  bool     `json:"flush,omitempty"`
+}
+
+// TieredCache implements CacheEngine with an in-memory LRU in front of Redis.
+//
+// Writes go through to Redis before updating the local tier and are broadcast on
+// the invalidation bus, if any, so other replicas drop their local copies.
+// Concurrent reads of a key missing locally share a single Redis lookup. Values are
+// kept locally in their JSON form, so Get returns the same types from either tier.
+type TieredCache struct {
+	config       *CacheConfig
+	local        *lruCache
+	remote       *RedisCache
+	lookups      singleflight.Group
+	bus          InvalidationBus
+	source       string
+	subscription interface{ Cancel() error }
+	eventEmitter func(ctx context.Context, event cloudevents.Event)
+}
+
+// lookup is the result of a shared Redis lookup.
+type lookup struct {
+	value interface{}
+	found bool
+}
+
+// NewTieredCache creates a new tiered cache engine
+func NewTieredCache(config *CacheConfig) *TieredCache {
+	return &TieredCache{
+		config: config,
+		local:  newLRUCache(config.LocalMaxItems),
+		remote: NewRedisCache(config),
+		source: newSourceID(),
+	}
+}
+
+// SetEventEmitter sets the event emission callback for the tiered cache
+func (c *TieredCache) SetEventEmitter(emitter func(ctx context.Context, event cloudevents.Event)) {
+	c.eventEmitter = emitter
+}
+
+// SetInvalidationBus sets the bus invalidations are broadcast on. It must be set
+// before Connect to receive the invalidations of other replicas.
+func (c *TieredCache) SetInvalidationBus(bus InvalidationBus) {
+	c.bus = bus
+}
+
+// Connect connects to Redis and subscribes to invalidations
+func (c *TieredCache) Connect(ctx context.Context) error {
+	if err := c.remote.Connect(ctx); err != nil {
+		return err
+	}
+	if c.bus == nil {
+		return nil
+	}
+	subscription, err := subscribeInvalidations(ctx, c.bus, c.config.InvalidationTopic, c.handleInvalidation)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to cache invalidations: %w", err)
+	}
+	c.subscription = subscription
+	return nil
+}
+
+// Close unsubscribes from invalidations and closes the connection to Redis
+func (c *TieredCache) Close(ctx context.Context) error {
+	if c.subscription != nil {
+		if err := c.subscription.Cancel(); err != nil {
+			return fmt.Errorf("failed to unsubscribe from cache invalidations: %w", err)
+		}
+		c.subscription = nil
+	}
+	return c.remote.Close(ctx)
+}
+
+// Get retrieves an item from the local tier, or from Redis on a local miss
+func (c *TieredCache) Get(ctx context.Context, key string) (interface{}, bool) {
+	if value, found := c.local.get(key); found {
+		return value, true
+	}
+	result, _, _ := c.lookups.Do(key, func() (interface{}, error) {
+		generation := c.local.generation()
+		value, found := c.remote.Get(ctx, key)
+		if found {
+			c.local.fill(key, value, c.localTTL(0), generation)
+		}
+		return lookup{value: value, found: found}, nil
+	})
+	found := result.(lookup)
+	return found.value, found.found
+}
+
+// Set stores an item in Redis and the local tier
+func (c *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	local, err := jsonValue(value)
+	if err != nil {
+		return err
+	}
+	ttl = c.jitter(ttl)
+	if err := c.remote.Set(ctx, key, value, ttl); err != nil {
+		c.local.delete(key)
+		return err
+	}
+	c.local.set(key, local, c.localTTL(ttl))
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Delete removes an item from Redis and the local tier
+func (c *TieredCache) Delete(ctx context.Context, key string) error {
+	c.local.delete(key)
+	if err := c.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Keys: []string{key}})
+	return nil
+}
+
+// Flush removes all items from Redis and the local tier
+func (c *TieredCache) Flush(ctx context.Context) error {
+	c.local.flush()
+	if err := c.remote.Flush(ctx); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Flush: true})
+	return nil
+}
+
+// GetMulti retrieves multiple items, reading the ones missing locally from Redis
+func (c *TieredCache) GetMulti(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(keys))
+	var missing []string
+	for _, key := range keys {
+		if value, found := c.local.get(key); found {
+			result[key] = value
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	generation := c.local.generation()
+	values, err := c.remote.GetMulti(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range values {
+		result[key] = value
+		c.local.fill(key, value, c.localTTL(0), generation)
+	}
+	return result, nil
+}
+
+// SetMulti stores multiple items in Redis and the local tier. The items share one
+// jittered TTL.
+func (c *TieredCache) SetMulti(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+	if len(items) == 0 {
+		return nil
+	}
+	local := make(map[string]interface{}, len(items))
+	keys := make([]string, 0, len(items))
+	for key, value := range items {
+		normalized, err := jsonValue(value)
+		if err != nil {
+			return err
+		}
+		local[key] = normalized
+		keys = append(keys, key)
+	}
+
+	ttl = c.jitter(ttl)
+	if err := c.remote.SetMulti(ctx, items, ttl); err != nil {
+		c.local.delete(keys...)
+		return err
+	}
+	for key, value := range local {
+		c.local.set(key, value, c.localTTL(ttl))
+	}
+	c.broadcast(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+// DeleteMulti removes multiple items from Redis and the local tier
+func (c *TieredCache) DeleteMulti(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	c.local.delete(keys...)
+	if err := c.remote.DeleteMulti(ctx, keys); err != nil {
+		return err
+	}
+	c.broadcast(ctx, invalidation{Keys: keys})
+	return nil
+}
+
+// Increment adds delta to the counter stored at key in Redis. Counters are not
+// broadcast, as increments are typically on hot paths, so other replicas may read
+// a counter up to LocalTTL old with Get; Increment itself always returns the
+// current count.
+func (c *TieredCache) Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	c.local.delete(key)
+	return c.remote.Increment(ctx, key, delta, ttl)
+}
+
+// handleInvalidation drops the local copies of the keys invalidated by another replica
+func (c *TieredCache) handleInvalidation(_ context.Context, event cloudevents.Event) error {
+	var message invalidation
+	if err := event.DataAs(&message); err != nil {
+		return fmt.Errorf("failed to decode cache invalidation: %w", err)
+	}
+	if message.Source == c.source {
+		return nil
+	}
+	if message.Flush {
+		c.local.flush()
+	} else {
+		c.local.delete(message.Keys...)
+	}
+	return nil
+}
+
+// broadcast publishes an invalidation for the other replicas. Failures are reported
+// as cache error events rather than failing the write, which has already reached
+// Redis; other replicas then serve their local copies until LocalTTL expires them.
+func (c *TieredCache) broadcast(ctx context.Context, message invalidation) {
+	if c.bus == nil {
+		return
+	}
+	message.Source = c.source
+	err := c.bus.Publish(ctx, c.config.InvalidationTopic, message)
+	if err == nil || c.eventEmitter == nil {
+		return
+	}
+	c.eventEmitter(ctx, modular.NewCloudEvent(EventTypeCacheError, "cache-service", map[string]interface{}{
+		"error":     err.Error(),
+		"engine":    c.config.Engine,
+		"operation": "invalidate",
+	}, nil))
+}
+
+// localTTL returns how long an item stored with ttl is kept in the local tier
+func (c *TieredCache) localTTL(ttl time.Duration) time.Duration {
+	if c.config.LocalTTL > 0 && (ttl <= 0 || ttl > c.config.LocalTTL) {
+		return c.config.LocalTTL
+	}
+	return ttl
+}
+
+// jitter randomizes ttl by up to TTLJitter in either direction
+func (c *TieredCache) jitter(ttl time.Duration) time.Duration {
+	if c.config.TTLJitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	spread := (rand.Float64()*2 - 1) * c.config.TTLJitter * float64(ttl) //nolint:gosec // jitter doesn't need a secure source
+	return max(ttl+time.Duration(spread), time.Millisecond)
+}
+
+// jsonValue returns value as Redis returns it after a JSON round trip
+func jsonValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, ErrInvalidValue
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, ErrInvalidValue
+	}
+	return normalized, nil
+}
+
+// newSourceID returns a random identifier telling this cache's invalidations apart
+// from those of other replicas.
+func newSourceID() string {
+	id := make([]byte, 8)
+	_, _ = crand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// subscribeInvalidations subscribes handler to topic on bus. The eventbus module's
+// Subscribe takes its own named handler type, which can't be named here without
+// depending on the module, so the handler is converted to it by reflection.
+func subscribeInvalidations(ctx context.Context, bus InvalidationBus, topic string, handler func(context.Context, cloudevents.Event) error) (interface{ Cancel() error }, error) {
+	subscribe := reflect.ValueOf(bus).MethodByName("Subscribe")
+	if !subscribe.IsValid() {
+		return nil, ErrSubscribeUnsupported
+	}
+	method := subscribe.Type()
+	if method.NumIn() != 3 || method.NumOut() != 2 ||
+		!reflect.TypeOf(ctx).AssignableTo(method.In(0)) ||
+		!reflect.TypeOf(topic).AssignableTo(method.In(1)) ||
+		!reflect.TypeOf(handler).ConvertibleTo(method.In(2)) ||
+		method.Out(1) != reflect.TypeOf((*error)(nil)).Elem() {
+		return nil, fmt.Errorf("%w: unexpected Subscribe signature %s", ErrSubscribeUnsupported, method)
+	}
+
+	out := subscribe.Call([]reflect.Value{
+		reflect.ValueOf(ctx),
+		reflect.ValueOf(topic),
+		reflect.ValueOf(handler).Convert(method.In(2)),
+	})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return nil, fmt.Errorf("failed to subscribe to %s: %w", topic, err)
+	}
+	subscription, ok := out[0].Interface().(interface{ Cancel() error })
+	if !ok {
+		return nil, fmt.Errorf("%w: subscription can't be cancelled", ErrSubscribeUnsupported)
+	}
+	return subscription, nil
+}
+
+// lruCache is the in-memory tier of TieredCache, evicting the least recently used
+// items beyond maxItems.
+type lruCache struct {
+	mutex    sync.Mutex
+	maxItems int
+	order    *list.List
+	items    map[string]*list.Element
+	// gen changes with every write, so lookups that raced a write don't fill the
+	// tier with the value read before it.
+	gen uint64
+}
+
+type lruEntry struct {
+	key        string
+	value      interface{}
+	expiration time.Time
+}
+
+func newLRUCache(maxItems int) *lruCache {
+	return &lruCache{
+		maxItems: maxItems,
+		order:    list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (l *lruCache) get(key string) (interface{}, bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	element, found := l.items[key]
+	if !found {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expiration.IsZero() && time.Now().After(entry.expiration) {
+		l.order.Remove(element)
+		delete(l.items, key)
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return entry.value, true
+}
+
+func (l *lruCache) generation() uint64 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.gen
+}
+
+// set stores a written value
+func (l *lruCache) set(key string, value interface{}, ttl time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.gen++
+	l.store(key, value, ttl)
+}
+
+// fill stores a value read from Redis, unless the tier was written since generation
+func (l *lruCache) fill(key string, value interface{}, ttl time.Duration, generation uint64) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.gen == generation {
+		l.store(key, value, ttl)
+	}
+}
+
+func (l *lruCache) store(key string, value interface{}, ttl time.Duration) {
+	if l.maxItems <= 0 {
+		return
+	}
+	var expiration time.Time
+	if ttl > 0 {
+		expiration = time.Now().Add(ttl)
+	}
+	if element, found := l.items[key]; found {
+		element.Value = &lruEntry{key: key, value: value, expiration: expiration}
+		l.order.MoveToFront(element)
+		return
+	}
+	l.items[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiration: expiration})
+	for l.order.Len() > l.maxItems {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+func (l *lruCache) delete(keys ...string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.gen++
+	for _, key := range keys {
+		if element, found := l.items[key]; found {
+			l.order.Remove(element)
+			delete(l.items, key)
+		}
+	}
+}
+
+func (l *lruCache) flush() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.gen++
+	l.order.Init()
+	l.items = make(map[string]*list.Element)
+}
+
+func (l *lruCache) size() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,315 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/alicebob/miniredis/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEventHandler mirrors the eventbus module's named handler type.
+type testEventHandler func(ctx context.Context, event cloudevents.Event) error
+
+// testBus delivers published events synchronously to its subscribers, like the
+// eventbus module's memory engine.
+type testBus struct {
+	mu        sync.Mutex
+	handlers  map[int]testEventHandler
+	next      int
+	published atomic.Int32
+}
+
+type testSubscription struct {
+	bus *testBus
+	id  int
+}
+
+func (s *testSubscription) Cancel() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	delete(s.bus.handlers, s.id)
+	return nil
+}
+
+func (b *testBus) Publish(ctx context.Context, topic string, payload interface{}) error {
+	b.published.Add(1)
+	event := cloudevents.NewEvent()
+	event.SetType(topic)
+	event.SetSource("test")
+	if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	handlers := make([]testEventHandler, 0, len(b.handlers))
+	for _, handler := range b.handlers {
+		handlers = append(handlers, handler)
+	}
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *testBus) Subscribe(_ context.Context, _ string, handler testEventHandler) (*testSubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.handlers == nil {
+		b.handlers = make(map[int]testEventHandler)
+	}
+	b.next++
+	b.handlers[b.next] = handler
+	return &testSubscription{bus: b, id: b.next}, nil
+}
+
+// publishOnlyBus can broadcast invalidations but not receive them.
+type publishOnlyBus struct{}
+
+func (publishOnlyBus) Publish(context.Context, string, interface{}) error { return nil }
+
+func tieredTestConfig(s *miniredis.Miniredis) *CacheConfig {
+	return &CacheConfig{
+		Engine:            "tiered",
+		DefaultTTL:        time.Minute,
+		RedisURL:          "redis://" + s.Addr(),
+		LocalMaxItems:     100,
+		LocalTTL:          time.Minute,
+		InvalidationTopic: "cache.invalidate",
+	}
+}
+
+// newTieredTestCache connects a tiered cache with config, sharing bus if not nil.
+func newTieredTestCache(t *testing.T, config *CacheConfig, bus InvalidationBus) *TieredCache {
+	t.Helper()
+	cache := NewTieredCache(config)
+	if bus != nil {
+		cache.SetInvalidationBus(bus)
+	}
+	require.NoError(t, cache.Connect(context.Background()))
+	t.Cleanup(func() { _ = cache.Close(context.Background()) })
+	return cache
+}
+
+func TestTieredCacheReadAndWriteThrough(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	cache := newTieredTestCache(t, tieredTestConfig(s), nil)
+	ctx := context.Background()
+
+	require.NoError(t, cache.Set(ctx, "user:1", map[string]int{"age": 30}, time.Minute))
+	assert.True(t, s.Exists("user:1"), "writes go through to Redis")
+	value, found := cache.Get(ctx, "user:1")
+	require.True(t, found)
+	assert.Equal(t, map[string]interface{}{"age": float64(30)}, value, "local values have the types Redis returns")
+
+	// Reads fill the local tier, which then serves them without Redis
+	require.NoError(t, s.Set("user:2", `"remote"`))
+	value, found = cache.Get(ctx, "user:2")
+	require.True(t, found)
+	assert.Equal(t, "remote", value)
+	s.Del("user:2")
+	value, found = cache.Get(ctx, "user:2")
+	assert.True(t, found)
+	assert.Equal(t, "remote", value)
+
+	results, err := cache.GetMulti(ctx, []string{"user:1", "user:2", "user:3"})
+	require.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	require.NoError(t, cache.Delete(ctx, "user:1"))
+	_, found = cache.Get(ctx, "user:1")
+	assert.False(t, found)
+	assert.False(t, s.Exists("user:1"))
+
+	require.NoError(t, cache.SetMulti(ctx, map[string]interface{}{"a": 1, "b": 2}, time.Minute))
+	require.NoError(t, cache.DeleteMulti(ctx, []string{"a"}))
+	results, err = cache.GetMulti(ctx, []string{"a", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"b": float64(2)}, results)
+
+	require.NoError(t, cache.Flush(ctx))
+	assert.Equal(t, 0, cache.local.size())
+	_, found = cache.Get(ctx, "b")
+	assert.False(t, found)
+
+	count, err := cache.Increment(ctx, "hits", 2, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestTieredCacheLocalLimits(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	config := tieredTestConfig(s)
+	config.LocalMaxItems = 2
+	config.LocalTTL = 50 * time.Millisecond
+	cache := newTieredTestCache(t, config, nil)
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		require.NoError(t, cache.Set(ctx, key, key, time.Minute))
+	}
+	assert.Equal(t, 2, cache.local.size())
+	_, found := cache.local.get("a")
+	assert.False(t, found, "the least recently used item is evicted")
+
+	// Local copies expire after LocalTTL and are read from Redis again
+	require.NoError(t, s.Set("b", `"updated"`))
+	value, _ := cache.Get(ctx, "b")
+	assert.Equal(t, "b", value)
+	require.Eventually(t, func() bool {
+		value, _ := cache.Get(ctx, "b")
+		return value == "updated"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTieredCacheInvalidation(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	bus := &testBus{}
+	first := newTieredTestCache(t, tieredTestConfig(s), bus)
+	second := newTieredTestCache(t, tieredTestConfig(s), bus)
+	ctx := context.Background()
+
+	require.NoError(t, first.Set(ctx, "config", "v1", time.Minute))
+	value, _ := second.Get(ctx, "config")
+	assert.Equal(t, "v1", value)
+
+	// A write on one replica drops the other's local copy
+	require.NoError(t, first.Set(ctx, "config", "v2", time.Minute))
+	value, _ = second.Get(ctx, "config")
+	assert.Equal(t, "v2", value)
+	value, _ = first.Get(ctx, "config")
+	assert.Equal(t, "v2", value, "a replica ignores its own invalidations")
+
+	require.NoError(t, first.Delete(ctx, "config"))
+	_, found := second.Get(ctx, "config")
+	assert.False(t, found)
+
+	require.NoError(t, second.Set(ctx, "other", "x", time.Minute))
+	_, _ = first.Get(ctx, "other")
+	require.NoError(t, second.Flush(ctx))
+	assert.Equal(t, 0, first.local.size())
+
+	// Closed caches stop receiving invalidations
+	require.NoError(t, second.Close(ctx))
+	published := bus.published.Load()
+	require.NoError(t, first.Set(ctx, "config", "v3", time.Minute))
+	assert.Equal(t, published+1, bus.published.Load())
+	assert.Len(t, bus.handlers, 1)
+}
+
+func TestTieredCacheRequiresSubscribableBus(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	cache := NewTieredCache(tieredTestConfig(s))
+	cache.SetInvalidationBus(publishOnlyBus{})
+	require.ErrorIs(t, cache.Connect(context.Background()), ErrSubscribeUnsupported)
+	_ = cache.Close(context.Background())
+}
+
+func TestTieredCacheTTLJitter(t *testing.T) {
+	t.Parallel()
+	cache := NewTieredCache(&CacheConfig{TTLJitter: 0.5})
+	seen := make(map[time.Duration]bool)
+	for range 50 {
+		ttl := cache.jitter(10 * time.Second)
+		assert.GreaterOrEqual(t, ttl, 5*time.Second)
+		assert.LessOrEqual(t, ttl, 15*time.Second)
+		seen[ttl] = true
+	}
+	assert.Greater(t, len(seen), 1)
+	assert.Equal(t, time.Duration(0), cache.jitter(0), "items without a TTL don't get one")
+
+	module := NewModule().(*CacheModule)
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&CacheConfig{Engine: "tiered", TTLJitter: 2}))
+	require.ErrorIs(t, module.Init(app), ErrInvalidTTLJitter)
+}
+
+func TestCacheModuleTieredEngine(t *testing.T) {
+	t.Parallel()
+	s := miniredis.RunT(t)
+	bus := &testBus{}
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(tieredTestConfig(s)))
+	constructed, err := NewModule().(*CacheModule).Constructor()(app, map[string]any{EventBusServiceName: bus})
+	require.NoError(t, err)
+	module := constructed.(*CacheModule)
+	require.NoError(t, module.Init(app))
+	assert.IsType(t, &TieredCache{}, module.cacheEngine)
+	assert.Equal(t, 400, module.ShutdownPhaseOrder(), "stops before the event bus it broadcasts on")
+	assert.Equal(t, 500, NewModule().(*CacheModule).ShutdownPhaseOrder())
+
+	ctx := context.Background()
+	require.NoError(t, module.Start(ctx))
+	require.NoError(t, module.Set(ctx, "key", "value", 0))
+	assert.Equal(t, int32(1), bus.published.Load())
+	require.NoError(t, module.Stop(ctx))
+}
+
+func TestCacheModuleGetOrLoad(t *testing.T) {
+	t.Parallel()
+	module := NewModule().(*CacheModule)
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&CacheConfig{
+		Engine:          "memory",
+		DefaultTTL:      time.Minute,
+		CleanupInterval: time.Minute,
+		MaxItems:        100,
+	}))
+	require.NoError(t, module.Init(app))
+	ctx := context.Background()
+	require.NoError(t, module.Start(ctx))
+	t.Cleanup(func() { _ = module.Stop(ctx) })
+
+	// Concurrent misses share one load
+	var loads atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (interface{}, error) {
+		loads.Add(1)
+		<-release
+		return "loaded", nil
+	}
+	var wg sync.WaitGroup
+	values := make([]interface{}, 10)
+	for i := range values {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := module.GetOrLoad(ctx, "popular", 0, load)
+			assert.NoError(t, err)
+			values[i] = value
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+	for _, value := range values {
+		assert.Equal(t, "loaded", value)
+	}
+
+	// Later calls are served from the cache
+	value, err := module.GetOrLoad(ctx, "popular", 0, load)
+	require.NoError(t, err)
+	assert.Equal(t, "loaded", value)
+	assert.Equal(t, int32(1), loads.Load())
+
+	// Load errors are returned and not cached
+	_, err = module.GetOrLoad(ctx, "broken", 0, func(context.Context) (interface{}, error) {
+		return nil, ErrInvalidValue
+	})
+	require.ErrorIs(t, err, ErrInvalidValue)
+	_, found := module.Get(ctx, "broken")
+	assert.False(t, found)
+}
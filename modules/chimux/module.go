@@ -39,6 +39,7 @@
 //   - "chimux.router": The full ChiMuxModule instance
 //   - "router": BasicRouter interface for simple routing needs
 //   - "chi.router": Direct access to the underlying Chi router
+//   - "chimux.route_groups": RouteGroupService for prefix and middleware scoped route groups
 //
 // # Usage Examples
 //
@@ -158,6 +159,10 @@ type ChiMuxModule struct {
 	disabledMu sync.RWMutex
 	// routeRegistry tracks registered routes with their methods for runtime management.
 	routeRegistry []struct{ method, pattern string }
+	// routeHandlers holds the handler of each registered method and pattern, which
+	// registering them again swaps without changing the chi tree.
+	routeHandlersMu sync.Mutex
+	routeHandlers   map[string]*atomic.Pointer[http.Handler]
 	// middleware tracking for runtime enable/disable
 	middlewareMu    sync.RWMutex
 	middlewares     map[string]*controllableMiddleware
@@ -177,6 +182,7 @@ func NewChiMuxModule() modular.Module {
 		tenantConfigs:  make(map[modular.TenantID]*ChiMuxConfig),
 		disabledRoutes: make(map[string]map[string]bool),
 		middlewares:    make(map[string]*controllableMiddleware),
+		routeHandlers:  make(map[string]*atomic.Pointer[http.Handler]),
 	}
 }
 
@@ -483,6 +489,7 @@ func (m *ChiMuxModule) Dependencies() []string {
 //   - "chimux.router": The full ChiMuxModule instance
 //   - "router": BasicRouter interface for simple routing needs
 //   - "chi.router": Direct access to the underlying Chi router
+//   - "chimux.route_groups": RouteGroupService for prefix and middleware scoped route groups
 func (m *ChiMuxModule) ProvidesServices() []modular.ServiceProvider {
 	return []modular.ServiceProvider{
 		{
@@ -500,6 +507,11 @@ func (m *ChiMuxModule) ProvidesServices() []modular.ServiceProvider {
 			Description: "Full Chi router with Route/Group support",
 			Instance:    m.ChiRouter(),
 		},
+		{
+			Name:        RouteGroupServiceName,
+			Description: "Route group registration for prefix and middleware scoped routes",
+			Instance:    m,
+		},
 	}
 }
 
@@ -596,8 +608,7 @@ func (m *ChiMuxModule) ChiRouter() chi.Router {
 
 // Get registers a GET handler for the pattern
 func (m *ChiMuxModule) Get(pattern string, handler http.HandlerFunc) {
-	m.router.Get(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"GET", pattern})
+	m.handle("GET", pattern, handler)
 
 	// Emit route registered event
 	m.emitEvent(context.Background(), EventTypeRouteRegistered, map[string]interface{}{
@@ -608,8 +619,7 @@ func (m *ChiMuxModule) Get(pattern string, handler http.HandlerFunc) {
 
 // Post registers a POST handler for the pattern
 func (m *ChiMuxModule) Post(pattern string, handler http.HandlerFunc) {
-	m.router.Post(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"POST", pattern})
+	m.handle("POST", pattern, handler)
 
 	// Emit route registered event
 	m.emitEvent(context.Background(), EventTypeRouteRegistered, map[string]interface{}{
@@ -620,8 +630,7 @@ func (m *ChiMuxModule) Post(pattern string, handler http.HandlerFunc) {
 
 // Put registers a PUT handler for the pattern
 func (m *ChiMuxModule) Put(pattern string, handler http.HandlerFunc) {
-	m.router.Put(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"PUT", pattern})
+	m.handle("PUT", pattern, handler)
 
 	// Emit route registered event
 	m.emitEvent(context.Background(), EventTypeRouteRegistered, map[string]interface{}{
@@ -632,8 +641,7 @@ func (m *ChiMuxModule) Put(pattern string, handler http.HandlerFunc) {
 
 // Delete registers a DELETE handler for the pattern
 func (m *ChiMuxModule) Delete(pattern string, handler http.HandlerFunc) {
-	m.router.Delete(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"DELETE", pattern})
+	m.handle("DELETE", pattern, handler)
 
 	// Emit route registered event
 	m.emitEvent(context.Background(), EventTypeRouteRegistered, map[string]interface{}{
@@ -644,25 +652,63 @@ func (m *ChiMuxModule) Delete(pattern string, handler http.HandlerFunc) {
 
 // Patch registers a PATCH handler for the pattern
 func (m *ChiMuxModule) Patch(pattern string, handler http.HandlerFunc) {
-	m.router.Patch(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"PATCH", pattern})
+	m.handle("PATCH", pattern, handler)
 }
 
 // Head registers a HEAD handler for the pattern
 func (m *ChiMuxModule) Head(pattern string, handler http.HandlerFunc) {
-	m.router.Head(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"HEAD", pattern})
+	m.handle("HEAD", pattern, handler)
 }
 
 // Options registers an OPTIONS handler for the pattern
 func (m *ChiMuxModule) Options(pattern string, handler http.HandlerFunc) {
-	m.router.Options(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"OPTIONS", pattern})
+	m.handle("OPTIONS", pattern, handler)
 }
 
 // Mount attaches another http.Handler at the given pattern
 func (m *ChiMuxModule) Mount(pattern string, handler http.Handler) {
-	m.router.Mount(pattern, handler)
+	m.handle(routeMount, pattern, handler)
+}
+
+// routeMount is the method under which routeHandlers holds mounted handlers.
+const routeMount = "MOUNT"
+
+// handle routes method and pattern to handler, method being "ANY" for all methods
+// or routeMount to mount handler. Only the first registration of a method and
+// pattern adds a route to the chi tree; registering them again, as a swapped module
+// does, replaces the handler behind that route atomically, since the tree can't
+// change safely while it serves requests, and chi refuses to mount a pattern twice.
+// It reports whether the route is new.
+func (m *ChiMuxModule) handle(method, pattern string, handler http.Handler) bool {
+	m.routeHandlersMu.Lock()
+	defer m.routeHandlersMu.Unlock()
+
+	key := method + " " + pattern
+	if current, ok := m.routeHandlers[key]; ok {
+		current.Store(&handler)
+		return false
+	}
+	current := new(atomic.Pointer[http.Handler])
+	current.Store(&handler)
+	if m.routeHandlers == nil {
+		m.routeHandlers = make(map[string]*atomic.Pointer[http.Handler])
+	}
+	m.routeHandlers[key] = current
+
+	route := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		(*current.Load()).ServeHTTP(w, r)
+	})
+	switch method {
+	case "ANY":
+		m.router.Handle(pattern, route)
+	case routeMount:
+		m.router.Mount(pattern, route)
+		return true
+	default:
+		m.router.Method(method, pattern, route)
+	}
+	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{method, pattern})
+	return true
 }
 
 // Use appends middleware to the chain
@@ -719,14 +765,12 @@ func (m *ChiMuxModule) RemoveMiddleware(name string) error {
 
 // Handle registers a handler for a specific pattern
 func (m *ChiMuxModule) Handle(pattern string, handler http.Handler) {
-	m.router.Handle(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"ANY", pattern})
+	m.handle("ANY", pattern, handler)
 }
 
 // HandleFunc registers a handler function for a specific pattern
 func (m *ChiMuxModule) HandleFunc(pattern string, handler http.HandlerFunc) {
-	m.router.HandleFunc(pattern, handler)
-	m.routeRegistry = append(m.routeRegistry, struct{ method, pattern string }{"ANY", pattern})
+	m.handle("ANY", pattern, handler)
 }
 
 // ServeHTTP implements the http.Handler interface to properly handle base path prefixing
@@ -771,19 +815,19 @@ func (m *ChiMuxModule) With(middlewares ...func(http.Handler) http.Handler) chi.
 }
 
 func (m *ChiMuxModule) Method(method, pattern string, h http.Handler) {
-	m.router.Method(method, pattern, h)
+	m.handle(strings.ToUpper(method), pattern, h)
 }
 
 func (m *ChiMuxModule) MethodFunc(method, pattern string, h http.HandlerFunc) {
-	m.router.MethodFunc(method, pattern, h)
+	m.handle(strings.ToUpper(method), pattern, h)
 }
 
 func (m *ChiMuxModule) Connect(pattern string, h http.HandlerFunc) {
-	m.router.Connect(pattern, h)
+	m.handle(http.MethodConnect, pattern, h)
 }
 
 func (m *ChiMuxModule) Trace(pattern string, h http.HandlerFunc) {
-	m.router.Trace(pattern, h)
+	m.handle(http.MethodTrace, pattern, h)
 }
 
 func (m *ChiMuxModule) NotFound(h http.HandlerFunc) {
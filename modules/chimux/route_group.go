@@ -0,0 +1,171 @@
+package chimux
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RouteGroupServiceName is the name of the service for registering route groups.
+const RouteGroupServiceName = "chimux.route_groups"
+
+// RouteGroupService registers groups of routes that share a path prefix and a
+// middleware chain, such as the versions of an API.
+//
+// Example:
+//
+//	groups.RouteGroup("/api/v1", func(r chimux.Router) {
+//	    r.Use(legacyAuth)
+//	    r.Get("/users", listUsersV1)
+//	})
+//	groups.RouteGroup("/api/v2", func(r chimux.Router) {
+//	    r.Use(tokenAuth, rateLimit)
+//	    r.Get("/users", listUsersV2)
+//	    r.Route("/admin", func(r chi.Router) {
+//	        r.Use(requireAdmin)
+//	        r.Delete("/users/{id}", deleteUser)
+//	    })
+//	})
+type RouteGroupService interface {
+	// RouteGroup calls configure with a router whose patterns are relative to
+	// prefix. Middleware added with Use applies only to the routes of the group
+	// and must be added before them. Several groups, from different modules,
+	// may share a prefix, each with its own middleware.
+	RouteGroup(prefix string, configure func(Router))
+}
+
+// RouteGroup registers a group of routes under prefix. Routes of the group are
+// tracked like routes registered on the module, under their full pattern, so
+// they can be disabled with DisableRoute and emit route registered events.
+func (m *ChiMuxModule) RouteGroup(prefix string, configure func(Router)) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	m.router.Group(func(r chi.Router) {
+		configure(&routeGroup{Router: r, module: m, prefix: prefix})
+	})
+	m.logger.Debug("Registered route group", "prefix", prefix)
+}
+
+// routeGroup is the router passed to a RouteGroup callback. It prefixes the
+// patterns registered on the underlying inline chi router and records them in
+// the module's route registry.
+type routeGroup struct {
+	chi.Router
+	module *ChiMuxModule
+	prefix string
+}
+
+// pattern returns the full pattern of a route registered on the group.
+func (g *routeGroup) pattern(pattern string) string {
+	if pattern == "" || pattern == "/" {
+		if g.prefix == "" {
+			return "/"
+		}
+		return g.prefix
+	}
+	return g.prefix + pattern
+}
+
+// handle registers a route of the group with the module, wrapped in the group's
+// middleware, and emits a route registered event.
+func (g *routeGroup) handle(method, pattern string, handler http.Handler) {
+	g.module.handle(method, pattern, chi.Chain(g.Router.Middlewares()...).Handler(handler))
+	g.module.emitEvent(context.Background(), EventTypeRouteRegistered, map[string]interface{}{
+		"method":  method,
+		"pattern": pattern,
+		"group":   g.prefix,
+	})
+}
+
+// sub returns a group for r with the given full prefix.
+func (g *routeGroup) sub(r chi.Router, prefix string) *routeGroup {
+	return &routeGroup{Router: r, module: g.module, prefix: prefix}
+}
+
+func (g *routeGroup) Get(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodGet, pattern, handler)
+}
+
+func (g *routeGroup) Post(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodPost, pattern, handler)
+}
+
+func (g *routeGroup) Put(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodPut, pattern, handler)
+}
+
+func (g *routeGroup) Delete(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodDelete, pattern, handler)
+}
+
+func (g *routeGroup) Patch(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodPatch, pattern, handler)
+}
+
+func (g *routeGroup) Head(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodHead, pattern, handler)
+}
+
+func (g *routeGroup) Options(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodOptions, pattern, handler)
+}
+
+func (g *routeGroup) Connect(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodConnect, pattern, handler)
+}
+
+func (g *routeGroup) Trace(pattern string, handler http.HandlerFunc) {
+	g.MethodFunc(http.MethodTrace, pattern, handler)
+}
+
+func (g *routeGroup) MethodFunc(method, pattern string, handler http.HandlerFunc) {
+	g.Method(method, pattern, handler)
+}
+
+func (g *routeGroup) Method(method, pattern string, handler http.Handler) {
+	g.handle(strings.ToUpper(method), g.pattern(pattern), handler)
+}
+
+func (g *routeGroup) Handle(pattern string, handler http.Handler) {
+	if method, rest, found := strings.Cut(pattern, " "); found {
+		g.Method(method, rest, handler)
+		return
+	}
+	g.handle("ANY", g.pattern(pattern), handler)
+}
+
+func (g *routeGroup) HandleFunc(pattern string, handler http.HandlerFunc) {
+	g.Handle(pattern, handler)
+}
+
+func (g *routeGroup) Mount(pattern string, handler http.Handler) {
+	g.module.handle(routeMount, g.pattern(pattern), chi.Chain(g.Router.Middlewares()...).Handler(handler))
+}
+
+// Route registers a nested group under pattern with its own middleware chain.
+func (g *routeGroup) Route(pattern string, fn func(chi.Router)) chi.Router {
+	nested := g.sub(g.Router.With(), strings.TrimSuffix(g.pattern(pattern), "/"))
+	fn(nested)
+	return nested
+}
+
+// Group registers a nested group under the same prefix with its own middleware chain.
+func (g *routeGroup) Group(fn func(chi.Router)) chi.Router {
+	nested := g.sub(g.Router.With(), g.prefix)
+	if fn != nil {
+		fn(nested)
+	}
+	return nested
+}
+
+// With returns a router under the same prefix whose routes use the group's and
+// the given middleware.
+func (g *routeGroup) With(middlewares ...func(http.Handler) http.Handler) chi.Router {
+	return g.sub(g.Router.With(middlewares...), g.prefix)
+}
+
+var (
+	_ RouteGroupService = (*ChiMuxModule)(nil)
+	_ Router            = (*routeGroup)(nil)
+)
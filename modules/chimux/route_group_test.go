@@ -0,0 +1,128 @@
+package chimux
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouteGroupTestModule(t *testing.T) *ChiMuxModule {
+	t.Helper()
+	module := NewChiMuxModule().(*ChiMuxModule)
+	mockApp := NewMockApplication()
+	require.NoError(t, module.RegisterConfig(mockApp))
+	require.NoError(t, module.RegisterObservers(mockApp))
+	require.NoError(t, module.Init(mockApp))
+	return module
+}
+
+// headerMiddleware appends value to the X-Middleware response header.
+func headerMiddleware(value string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", value)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func respond(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func serve(module *ChiMuxModule, method, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	module.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+	return w
+}
+
+func TestRouteGroup_VersionedAPIs(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+
+	module.RouteGroup("/api/v1", func(r Router) {
+		r.Use(headerMiddleware("v1"))
+		r.Get("/users", respond("users-v1"))
+	})
+	module.RouteGroup("/api/v2/", func(r Router) {
+		r.Use(headerMiddleware("v2"))
+		r.Get("/users", respond("users-v2"))
+		r.Get("/", respond("v2-index"))
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(headerMiddleware("admin"))
+			r.Delete("/users/{id}", func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte("deleted " + chi.URLParam(req, "id")))
+			})
+		})
+	})
+	module.Get("/health", respond("ok"))
+
+	w := serve(module, http.MethodGet, "/api/v1/users")
+	assert.Equal(t, "users-v1", w.Body.String())
+	assert.Equal(t, []string{"v1"}, w.Header().Values("X-Middleware"))
+
+	w = serve(module, http.MethodGet, "/api/v2/users")
+	assert.Equal(t, "users-v2", w.Body.String())
+	assert.Equal(t, []string{"v2"}, w.Header().Values("X-Middleware"))
+
+	w = serve(module, http.MethodGet, "/api/v2")
+	assert.Equal(t, "v2-index", w.Body.String())
+
+	w = serve(module, http.MethodDelete, "/api/v2/admin/users/7")
+	assert.Equal(t, "deleted 7", w.Body.String())
+	assert.Equal(t, []string{"v2", "admin"}, w.Header().Values("X-Middleware"))
+
+	w = serve(module, http.MethodGet, "/health")
+	assert.Equal(t, "ok", w.Body.String())
+	assert.Empty(t, w.Header().Values("X-Middleware"), "group middleware should not apply outside the group")
+}
+
+func TestRouteGroup_SharedPrefixAcrossModules(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+
+	module.RouteGroup("/api/v1", func(r Router) {
+		r.Use(headerMiddleware("orders"))
+		r.Get("/orders", respond("orders"))
+	})
+	module.RouteGroup("/api/v1", func(r Router) {
+		r.Get("/invoices", respond("invoices"))
+	})
+
+	w := serve(module, http.MethodGet, "/api/v1/orders")
+	assert.Equal(t, "orders", w.Body.String())
+	assert.Equal(t, []string{"orders"}, w.Header().Values("X-Middleware"))
+
+	w = serve(module, http.MethodGet, "/api/v1/invoices")
+	assert.Equal(t, "invoices", w.Body.String())
+	assert.Empty(t, w.Header().Values("X-Middleware"))
+}
+
+func TestRouteGroup_RoutesCanBeDisabled(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+	module.RouteGroup("/api/v1", func(r Router) {
+		r.Get("/users", respond("users"))
+		r.Handle("POST /users", respond("created"))
+	})
+
+	require.NoError(t, module.DisableRoute(http.MethodGet, "/api/v1/users"))
+	assert.Equal(t, http.StatusNotFound, serve(module, http.MethodGet, "/api/v1/users").Code)
+	assert.Equal(t, "created", serve(module, http.MethodPost, "/api/v1/users").Body.String())
+	require.NoError(t, module.DisableRoute(http.MethodPost, "/api/v1/users"))
+}
+
+func TestRouteGroup_ProvidedAsService(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+	for _, service := range module.ProvidesServices() {
+		if service.Name == RouteGroupServiceName {
+			_, ok := service.Instance.(RouteGroupService)
+			assert.True(t, ok)
+			return
+		}
+	}
+	t.Fatalf("service %s not provided", RouteGroupServiceName)
+}
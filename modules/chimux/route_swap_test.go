@@ -0,0 +1,69 @@
+package chimux
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteSwap_RegisteringAgainReplacesHandler(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+
+	module.Get("/users", respond("users-1"))
+	module.Mount("/static", respond("static-1"))
+	module.RouteGroup("/api", func(r Router) {
+		r.Use(headerMiddleware("old"))
+		r.Get("/items", respond("items-1"))
+	})
+
+	// A swapped module registers its routes again
+	module.Get("/users", respond("users-2"))
+	require.NotPanics(t, func() {
+		module.Mount("/static", respond("static-2"))
+	})
+	module.RouteGroup("/api", func(r Router) {
+		r.Use(headerMiddleware("new"))
+		r.Get("/items", respond("items-2"))
+	})
+
+	assert.Equal(t, "users-2", serve(module, http.MethodGet, "/users").Body.String())
+	assert.Equal(t, "static-2", serve(module, http.MethodGet, "/static/app.js").Body.String())
+	items := serve(module, http.MethodGet, "/api/items")
+	assert.Equal(t, "items-2", items.Body.String())
+	assert.Equal(t, []string{"new"}, items.Header().Values("X-Middleware"))
+
+	count := 0
+	for _, rt := range module.routeRegistry {
+		if rt.method == http.MethodGet && rt.pattern == "/users" {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count, "a replaced route is tracked once")
+}
+
+func TestRouteSwap_ConcurrentWithRequests(t *testing.T) {
+	module := newRouteGroupTestModule(t)
+	module.Get("/users", respond("users"))
+	module.Mount("/static", respond("static"))
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			module.Get("/users", respond("users"))
+			module.Mount("/static", respond("static"))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 100 {
+			assert.Equal(t, "users", serve(module, http.MethodGet, "/users").Body.String())
+			assert.Equal(t, "static", serve(module, http.MethodGet, "/static/x").Body.String())
+		}
+	}()
+	wg.Wait()
+}
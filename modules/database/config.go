@@ -51,6 +51,74 @@ type ConnectionConfig struct {
 
 	// AWSIAMAuth contains AWS IAM authentication configuration
 	AWSIAMAuth *AWSIAMAuthConfig `json:"aws_iam_auth,omitempty" yaml:"aws_iam_auth,omitempty"`
+
+	// StartupJitter delays the initial connection by a random duration up to this value,
+	// so that many instances starting together do not connect at the same moment
+	StartupJitter time.Duration `json:"startup_jitter" yaml:"startup_jitter" env:"STARTUP_JITTER"`
+
+	// MaxAcquireWait bounds how long Exec, Query and BeginTx wait for a connection from
+	// the pool before failing with ErrConnectionAcquireTimeout. Query and BeginTx return
+	// before their rows or transaction end, so the bound covers starting them. Zero waits
+	// as long as the caller's context allows.
+	MaxAcquireWait time.Duration `json:"max_acquire_wait" yaml:"max_acquire_wait" env:"MAX_ACQUIRE_WAIT"`
+
+	// Reconnect contains health check and reconnection configuration
+	Reconnect *ReconnectConfig `json:"reconnect,omitempty" yaml:"reconnect,omitempty"`
+
+	// Replicas lists read replicas of this connection. Reader balances reads across
+	// them, while Writer and the service's query methods always use the primary.
+	Replicas []ReplicaConfig `json:"replicas,omitempty" yaml:"replicas,omitempty"`
+
+	// ReplicaLoadBalancing selects how Reader picks a replica: "round_robin" (default),
+	// "random" or "least_connections"
+	ReplicaLoadBalancing string `json:"replica_load_balancing" yaml:"replica_load_balancing" env:"REPLICA_LOAD_BALANCING"`
+}
+
+// ReconnectConfig configures background health checks for a connection and
+// reconnection with exponential backoff when a health check fails.
+type ReconnectConfig struct {
+	// Enabled turns on background health checks and reconnection
+	Enabled bool `json:"enabled" yaml:"enabled" env:"RECONNECT_ENABLED"`
+
+	// HealthCheckInterval is how often the connection is pinged while healthy.
+	// Default is 30 seconds
+	HealthCheckInterval time.Duration `json:"health_check_interval" yaml:"health_check_interval" env:"RECONNECT_HEALTH_CHECK_INTERVAL"`
+
+	// PingTimeout bounds each health check and reconnection ping.
+	// Default is 5 seconds
+	PingTimeout time.Duration `json:"ping_timeout" yaml:"ping_timeout" env:"RECONNECT_PING_TIMEOUT"`
+
+	// InitialBackoff is the delay before the first reconnection attempt.
+	// Default is 1 second
+	InitialBackoff time.Duration `json:"initial_backoff" yaml:"initial_backoff" env:"RECONNECT_INITIAL_BACKOFF"`
+
+	// MaxBackoff caps the delay between reconnection attempts.
+	// Default is 1 minute
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" env:"RECONNECT_MAX_BACKOFF"`
+
+	// MaxAttempts is the number of reconnection attempts before giving up until the next
+	// health check. Zero retries until the connection recovers or the module stops.
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts" env:"RECONNECT_MAX_ATTEMPTS"`
+}
+
+// withDefaults returns a copy of the configuration with unset durations defaulted.
+func (c ReconnectConfig) withDefaults() ReconnectConfig {
+	if c.HealthCheckInterval <= 0 {
+		c.HealthCheckInterval = DefaultHealthCheckInterval
+	}
+	if c.PingTimeout <= 0 {
+		c.PingTimeout = DefaultConnectionTimeout
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = DefaultReconnectInitialBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = DefaultReconnectMaxBackoff
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		c.MaxBackoff = c.InitialBackoff
+	}
+	return c
 }
 
 // AWSIAMAuthConfig represents AWS IAM authentication configuration.
@@ -0,0 +1,227 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindStyle is the positional placeholder syntax of a database driver.
+type BindStyle int
+
+const (
+	// BindQuestion uses ? placeholders, as MySQL and SQLite do
+	BindQuestion BindStyle = iota
+	// BindDollar uses $1, $2, ... placeholders, as PostgreSQL does
+	BindDollar
+	// BindAt uses @p1, @p2, ... placeholders, as SQL Server does
+	BindAt
+)
+
+// placeholder returns the placeholder of the n-th argument, counting from 1.
+func (s BindStyle) placeholder(n int) string {
+	switch s {
+	case BindDollar:
+		return "$" + strconv.Itoa(n)
+	case BindAt:
+		return "@p" + strconv.Itoa(n)
+	default:
+		return "?"
+	}
+}
+
+// BindStyleForDriver returns the placeholder syntax of the named database/sql driver,
+// such as the driver of a database.ConnectionConfig.
+func BindStyleForDriver(driverName string) BindStyle {
+	switch strings.ToLower(driverName) {
+	case "postgres", "postgresql", "pgx", "pgxv4", "pq", "cloudsqlpostgres":
+		return BindDollar
+	case "sqlserver", "mssql", "azuresql":
+		return BindAt
+	default:
+		return BindQuestion
+	}
+}
+
+// BindStyleOf returns the placeholder syntax of the driver behind q, found through
+// its driver package when q is a *sql.DB or exposes one with DB() *sql.DB, as
+// database.DatabaseService does, or as given to WithBindStyle. Other values, such
+// as transactions, default to BindQuestion.
+func BindStyleOf(q any) BindStyle {
+	var db *sql.DB
+	switch v := q.(type) {
+	case *BoundQueryer:
+		return v.style
+	case *sql.DB:
+		db = v
+	case interface{ DB() *sql.DB }:
+		db = v.DB()
+	}
+	if db == nil {
+		return BindQuestion
+	}
+	driverType := reflect.TypeOf(db.Driver())
+	for driverType.Kind() == reflect.Ptr {
+		driverType = driverType.Elem()
+	}
+	pkg := driverType.PkgPath()
+	switch {
+	case strings.Contains(pkg, "jackc/pgx"), strings.Contains(pkg, "lib/pq"):
+		return BindDollar
+	case strings.Contains(pkg, "mssqldb"):
+		return BindAt
+	default:
+		return BindQuestion
+	}
+}
+
+// BoundQueryer runs queries and statements with a known placeholder syntax, for
+// values BindStyleOf cannot inspect such as *sql.Tx.
+type BoundQueryer struct {
+	q interface {
+		Querier
+		Execer
+	}
+	style BindStyle
+}
+
+// WithBindStyle returns q with its placeholder syntax fixed to style:
+//
+//	tx, _ := db.BeginTx(ctx, nil)
+//	_, err := dbx.NamedExec(ctx, dbx.WithBindStyle(tx, dbx.BindDollar), query, user)
+func WithBindStyle(q interface {
+	Querier
+	Execer
+}, style BindStyle) *BoundQueryer {
+	return &BoundQueryer{q: q, style: style}
+}
+
+// QueryContext runs query on the wrapped Querier.
+func (b *BoundQueryer) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return b.q.QueryContext(ctx, query, args...) //nolint:wrapcheck // passes through the wrapped querier
+}
+
+// ExecContext runs query on the wrapped Execer.
+func (b *BoundQueryer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return b.q.ExecContext(ctx, query, args...) //nolint:wrapcheck // passes through the wrapped execer
+}
+
+// Named rewrites the :name parameters of query into positional placeholders of the
+// given style and returns the matching arguments, taken from arg: a map[string]any
+// or a struct, whose fields are named as for scanning. Parameters may repeat; "::"
+// casts and quoted text are left alone.
+//
+//	query, args, err := dbx.Named(dbx.BindDollar,
+//	    "UPDATE users SET name = :name WHERE id = :id", user)
+func Named(style BindStyle, query string, arg any) (string, []any, error) {
+	lookup, err := namedLookup(arg)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	var args []any
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ':' && i+1 < len(query) && query[i+1] == ':':
+			b.WriteString("::")
+			i++
+			continue
+		case c == ':' && i+1 < len(query) && isNameByte(query[i+1]):
+			end := i + 1
+			for end < len(query) && isNameByte(query[end]) {
+				end++
+			}
+			name := query[i+1 : end]
+			value, ok := lookup(name)
+			if !ok {
+				return "", nil, fmt.Errorf("%w: %s", ErrMissingNamedParam, name)
+			}
+			args = append(args, value)
+			b.WriteString(style.placeholder(len(args)))
+			i = end - 1
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), args, nil
+}
+
+// isNameByte reports whether c may appear in a parameter name.
+func isNameByte(c byte) bool {
+	return c == '_' || c == '.' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// namedLookup returns a function looking parameters up in arg.
+func namedLookup(arg any) (func(name string) (any, bool), error) {
+	if m, ok := arg.(map[string]any); ok {
+		return func(name string) (any, bool) {
+			value, ok := m[name]
+			return value, ok
+		}, nil
+	}
+
+	v := reflect.ValueOf(arg)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: got %T", ErrInvalidNamedArg, arg)
+	}
+	fields := structFields(v.Type())
+	return func(name string) (any, bool) {
+		index, ok := fields[strings.ToLower(name)]
+		if !ok {
+			return nil, false
+		}
+		field, err := v.FieldByIndexErr(index)
+		if err != nil {
+			return nil, true // nil embedded pointer
+		}
+		return field.Interface(), true
+	}, nil
+}
+
+// NamedExec runs a statement with :name parameters taken from arg, using the
+// placeholder syntax of e as reported by BindStyleOf.
+func NamedExec(ctx context.Context, e Execer, query string, arg any) (sql.Result, error) {
+	bound, args, err := Named(BindStyleOf(e), query, arg)
+	if err != nil {
+		return nil, err
+	}
+	result, err := e.ExecContext(ctx, bound, args...)
+	if err != nil {
+		return nil, fmt.Errorf("exec: %w", err)
+	}
+	return result, nil
+}
+
+// NamedSelect is Select with :name parameters taken from arg.
+func NamedSelect[T any](ctx context.Context, q Querier, query string, arg any) ([]T, error) {
+	bound, args, err := Named(BindStyleOf(q), query, arg)
+	if err != nil {
+		return nil, err
+	}
+	return Select[T](ctx, q, bound, args...)
+}
+
+// NamedGet is Get with :name parameters taken from arg.
+func NamedGet[T any](ctx context.Context, q Querier, query string, arg any) (T, error) {
+	bound, args, err := Named(BindStyleOf(q), query, arg)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Get[T](ctx, q, bound, args...)
+}
@@ -0,0 +1,74 @@
+package dbx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamed_BindStyles(t *testing.T) {
+	arg := map[string]any{"id": 7, "name": "alice"}
+	query := "UPDATE users SET name = :name, note = 'at 10:30' WHERE id = :id AND owner_id = :id AND kind = :name::text"
+
+	bound, args, err := Named(BindDollar, query, arg)
+	require.NoError(t, err)
+	assert.Equal(t, "UPDATE users SET name = $1, note = 'at 10:30' WHERE id = $2 AND owner_id = $3 AND kind = $4::text", bound)
+	assert.Equal(t, []any{"alice", 7, 7, "alice"}, args)
+
+	bound, _, err = Named(BindQuestion, "SELECT * FROM users WHERE id = :id", arg)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = ?", bound)
+
+	bound, _, err = Named(BindAt, "SELECT * FROM users WHERE id = :id", arg)
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT * FROM users WHERE id = @p1", bound)
+}
+
+func TestNamed_StructArgument(t *testing.T) {
+	team := "core"
+	u := &user{ID: 3, UserName: "carol", Team: &team}
+
+	bound, args, err := Named(BindQuestion, "INSERT INTO users (id, name, team) VALUES (:id, :name, :team)", u)
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO users (id, name, team) VALUES (?, ?, ?)", bound)
+	assert.Equal(t, []any{int64(3), "carol", &team}, args)
+
+	_, _, err = Named(BindQuestion, "SELECT :secret", u)
+	assert.ErrorIs(t, err, ErrMissingNamedParam)
+
+	_, _, err = Named(BindQuestion, "SELECT :id", 42)
+	assert.ErrorIs(t, err, ErrInvalidNamedArg)
+}
+
+func TestNamedExecAndSelect(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	assert.Equal(t, BindQuestion, BindStyleOf(db))
+
+	_, err := NamedExec(ctx, db, "INSERT INTO users (id, name) VALUES (:id, :name)", user{ID: 3, UserName: "carol"})
+	require.NoError(t, err)
+
+	users, err := NamedSelect[user](ctx, db, "SELECT id, name FROM users WHERE id >= :min ORDER BY id", map[string]any{"min": 2})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.Equal(t, "carol", users[1].UserName)
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer func() { _ = tx.Rollback() }()
+	bound := WithBindStyle(tx, BindQuestion)
+	name, err := NamedGet[string](ctx, bound, "SELECT name FROM users WHERE id = :id", map[string]any{"id": 3})
+	require.NoError(t, err)
+	assert.Equal(t, "carol", name)
+	assert.Equal(t, BindDollar, BindStyleOf(WithBindStyle(tx, BindDollar)))
+}
+
+func TestBindStyleForDriver(t *testing.T) {
+	assert.Equal(t, BindDollar, BindStyleForDriver("pgx"))
+	assert.Equal(t, BindDollar, BindStyleForDriver("postgres"))
+	assert.Equal(t, BindAt, BindStyleForDriver("sqlserver"))
+	assert.Equal(t, BindQuestion, BindStyleForDriver("mysql"))
+	assert.Equal(t, BindQuestion, BindStyleForDriver("sqlite"))
+}
@@ -0,0 +1,38 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+// WithPgxConn runs fn with the native pgx connection behind one connection of db's
+// pool, for pgx-only features such as CopyFrom, batches and LISTEN/NOTIFY. The
+// connection is borrowed from the pool the database module manages, so pool limits,
+// reconnection and AWS IAM credential refresh still apply, and it is returned to the
+// pool when fn returns. fn must not close the connection or keep it after returning.
+// Connections opened with the "pgx" driver, including IAM-authenticated PostgreSQL
+// connections, are supported; others fail with ErrNotPgxConnection.
+//
+//	err := dbx.WithPgxConn(ctx, dbService.DB(), func(conn *pgx.Conn) error {
+//	    _, err := conn.CopyFrom(ctx, pgx.Identifier{"events"}, columns, pgx.CopyFromRows(rows))
+//	    return err
+//	})
+func WithPgxConn(ctx context.Context, db *sql.DB, fn func(conn *pgx.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("acquiring connection: %w", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error { //nolint:wrapcheck // fn's error is returned as is
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("%w: got %T", ErrNotPgxConnection, driverConn)
+		}
+		return fn(pgxConn.Conn())
+	})
+}
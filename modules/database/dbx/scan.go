@@ -0,0 +1,253 @@
+// Package dbx adds generic row scanning, named parameters and native pgx access on
+// top of the connections managed by the database module. It is opt-in: the database
+// module does not depend on it, and it works with anything exposing the database/sql
+// query methods, including database.DatabaseService, *sql.DB, *sql.Tx and *sql.Conn.
+//
+//	users, err := dbx.Select[User](ctx, db, "SELECT id, name FROM users WHERE team = $1", team)
+//	user, err := dbx.Get[User](ctx, db, "SELECT id, name FROM users WHERE id = $1", id)
+//	count, err := dbx.Get[int](ctx, db, "SELECT COUNT(*) FROM users")
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Errors returned by the dbx helpers.
+var (
+	ErrUnknownColumn     = errors.New("column has no matching struct field")
+	ErrTooManyColumns    = errors.New("query returns more than one column for a non-struct type")
+	ErrMissingNamedParam = errors.New("named parameter has no value")
+	ErrInvalidNamedArg   = errors.New("named parameters require a struct or map[string]any argument")
+	ErrNotPgxConnection  = errors.New("connection does not use the pgx v5 driver")
+)
+
+// Querier runs queries returning rows. It is implemented by database.DatabaseService,
+// *sql.DB, *sql.Tx and *sql.Conn.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Execer runs statements that return no rows. It is implemented by
+// database.DatabaseService, *sql.DB, *sql.Tx and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// Select runs query and scans every row into a T. Struct types are scanned by
+// matching columns to fields, other types must be queried with a single column.
+func Select[T any](ctx context.Context, q Querier, query string, args ...any) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+	return ScanAll[T](rows)
+}
+
+// Get runs query and scans its first row into a T, returning sql.ErrNoRows when the
+// query returns no rows.
+func Get[T any](ctx context.Context, q Querier, query string, args ...any) (T, error) {
+	var zero T
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return zero, fmt.Errorf("query: %w", err)
+	}
+	defer rows.Close()
+
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return zero, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return zero, fmt.Errorf("reading rows: %w", err)
+		}
+		return zero, sql.ErrNoRows
+	}
+	value, err := scan()
+	if err != nil {
+		return zero, err
+	}
+	if err := rows.Close(); err != nil {
+		return zero, fmt.Errorf("closing rows: %w", err)
+	}
+	return value, nil
+}
+
+// ScanAll scans every remaining row of rows into a T and closes rows.
+func ScanAll[T any](rows *sql.Rows) ([]T, error) {
+	defer rows.Close()
+
+	scan, err := newRowScanner[T](rows)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, 0)
+	for rows.Next() {
+		value, err := scan()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading rows: %w", err)
+	}
+	return values, nil
+}
+
+// newRowScanner returns a function scanning the current row of rows into a T.
+func newRowScanner[T any](rows *sql.Rows) (func() (T, error), error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("reading columns: %w", err)
+	}
+
+	t := reflect.TypeFor[T]()
+	target := t
+	for target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
+	if !isStructDest(target) {
+		if len(columns) != 1 {
+			return nil, fmt.Errorf("%w: %s from %d columns", ErrTooManyColumns, t, len(columns))
+		}
+		return func() (T, error) {
+			var value T
+			if err := rows.Scan(&value); err != nil {
+				return value, fmt.Errorf("scanning row: %w", err)
+			}
+			return value, nil
+		}, nil
+	}
+
+	fields := structFields(target)
+	indexes := make([][]int, len(columns))
+	for i, column := range columns {
+		index, ok := fields[strings.ToLower(column)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s in %s", ErrUnknownColumn, column, target)
+		}
+		indexes[i] = index
+	}
+
+	return func() (T, error) {
+		var value T
+		dest := reflect.ValueOf(&value).Elem()
+		for dest.Kind() == reflect.Ptr {
+			dest.Set(reflect.New(dest.Type().Elem()))
+			dest = dest.Elem()
+		}
+		pointers := make([]any, len(indexes))
+		for i, index := range indexes {
+			pointers[i] = fieldByIndexAlloc(dest, index).Addr().Interface()
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return value, fmt.Errorf("scanning row: %w", err)
+		}
+		return value, nil
+	}, nil
+}
+
+var (
+	scannerType = reflect.TypeFor[sql.Scanner]()
+	timeType    = reflect.TypeFor[time.Time]()
+)
+
+// isStructDest reports whether t is scanned field by field rather than as a single
+// value, which is the case for structs that are not sql.Scanners or time.Time.
+func isStructDest(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !reflect.PointerTo(t).Implements(scannerType)
+}
+
+// structFieldsCache holds the column-to-field mapping of each struct type.
+var structFieldsCache sync.Map
+
+// structFields maps the lower-cased column names of struct type t to field index
+// paths. A field's column is its db tag, or its name in snake case; "-" skips it.
+// Fields of untagged embedded structs are promoted.
+func structFields(t reflect.Type) map[string][]int {
+	if cached, ok := structFieldsCache.Load(t); ok {
+		return cached.(map[string][]int)
+	}
+	fields := make(map[string][]int)
+	collectStructFields(t, nil, fields)
+	structFieldsCache.Store(t, fields)
+	return fields
+}
+
+// collectStructFields adds the fields of t, reached through index, to fields.
+func collectStructFields(t reflect.Type, index []int, fields map[string][]int) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// Unexported embedded structs still promote their exported fields, but a nil
+		// unexported embedded pointer could not be allocated.
+		if !field.IsExported() && (!field.Anonymous || field.Type.Kind() == reflect.Ptr) {
+			continue
+		}
+		tag := field.Tag.Get("db")
+		if tag == "-" {
+			continue
+		}
+		fieldIndex := append(append([]int(nil), index...), i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && tag == "" && isStructDest(fieldType) {
+			collectStructFields(fieldType, fieldIndex, fields)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+		if _, exists := fields[strings.ToLower(name)]; !exists || len(index) == 0 {
+			fields[strings.ToLower(name)] = fieldIndex
+		}
+	}
+}
+
+// fieldByIndexAlloc returns the field of v at index, allocating nil embedded pointers.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			for v.Kind() == reflect.Ptr {
+				if v.IsNil() {
+					v.Set(reflect.New(v.Type().Elem()))
+				}
+				v = v.Elem()
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// snakeCase converts a Go field name such as "UserID" to "user_id".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package dbx
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // Import pure Go SQLite driver
+)
+
+type auditFields struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type user struct {
+	ID       int64
+	UserName string `db:"name"`
+	Email    sql.NullString
+	Team     *string
+	Secret   string `db:"-"`
+	auditFields
+}
+
+// newTestDB returns an in-memory SQLite database with a populated users table.
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, email TEXT, team TEXT, created_at TIMESTAMP)`)
+	require.NoError(t, err)
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	_, err = db.Exec(`INSERT INTO users VALUES (1, 'alice', 'alice@example.com', 'core', ?), (2, 'bob', NULL, NULL, ?)`, created, created)
+	require.NoError(t, err)
+	return db
+}
+
+func TestSelect_ScansStructs(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	users, err := Select[user](ctx, db, "SELECT id, name, email, team, created_at FROM users ORDER BY id")
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	assert.Equal(t, int64(1), users[0].ID)
+	assert.Equal(t, "alice", users[0].UserName)
+	assert.Equal(t, sql.NullString{String: "alice@example.com", Valid: true}, users[0].Email)
+	require.NotNil(t, users[0].Team)
+	assert.Equal(t, "core", *users[0].Team)
+	assert.Equal(t, 2026, users[0].CreatedAt.Year(), "embedded struct fields are promoted")
+
+	assert.False(t, users[1].Email.Valid)
+	assert.Nil(t, users[1].Team)
+
+	pointers, err := Select[*user](ctx, db, "SELECT id FROM users WHERE id = ?", 2)
+	require.NoError(t, err)
+	require.Len(t, pointers, 1)
+	assert.Equal(t, int64(2), pointers[0].ID)
+
+	none, err := Select[user](ctx, db, "SELECT id FROM users WHERE id = 99")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestGet_ScansSingleRow(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	u, err := Get[user](ctx, db, "SELECT id, name FROM users WHERE name = ?", "bob")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), u.ID)
+
+	count, err := Get[int](ctx, db, "SELECT COUNT(*) FROM users")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	_, err = Get[user](ctx, db, "SELECT id FROM users WHERE id = 99")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSelect_Errors(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+
+	_, err := Select[user](ctx, db, "SELECT id, name AS nickname FROM users")
+	assert.ErrorIs(t, err, ErrUnknownColumn)
+
+	_, err = Select[string](ctx, db, "SELECT name, email FROM users")
+	assert.ErrorIs(t, err, ErrTooManyColumns)
+
+	_, err = Get[user](ctx, db, "SELECT id, secret FROM users")
+	assert.Error(t, err)
+}
+
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "user_id", snakeCase("UserID"))
+	assert.Equal(t, "id", snakeCase("ID"))
+	assert.Equal(t, "http_status", snakeCase("HTTPStatus"))
+	assert.Equal(t, "created_at", snakeCase("CreatedAt"))
+}
+
+func TestWithPgxConn_RequiresPgxDriver(t *testing.T) {
+	db := newTestDB(t)
+	called := false
+	err := WithPgxConn(context.Background(), db, func(conn *pgx.Conn) error {
+		called = true
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrNotPgxConnection)
+	assert.False(t, called)
+}
@@ -1,6 +1,10 @@
 package database
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // Static error definitions to avoid dynamic error creation (err113 linter)
 var (
@@ -14,3 +18,42 @@ var (
 	// without proper migration service initialization
 	ErrMigrationServiceNotInitialized = errors.New("migration service not initialized")
 )
+
+// Connection resilience errors
+var (
+	// ErrConnectionAcquireTimeout is returned when no pooled connection became available
+	// within the connection's MaxAcquireWait. Use errors.As with *AcquireTimeoutError for details.
+	ErrConnectionAcquireTimeout = errors.New("timed out waiting for a database connection")
+)
+
+// AcquireTimeoutError describes a failure to obtain a pooled connection within MaxAcquireWait.
+// It matches ErrConnectionAcquireTimeout with errors.Is.
+type AcquireTimeoutError struct {
+	// Connection is the name of the database connection
+	Connection string
+	// Waited is how long the caller waited before giving up
+	Waited time.Duration
+	// InUse is the number of connections in use when the wait expired
+	InUse int
+	// MaxOpen is the pool's maximum number of open connections (0 means unlimited)
+	MaxOpen int
+}
+
+func (e *AcquireTimeoutError) Error() string {
+	return fmt.Sprintf("%s: connection %q waited %s (%d/%d in use)",
+		ErrConnectionAcquireTimeout.Error(), e.Connection, e.Waited, e.InUse, e.MaxOpen)
+}
+
+// Unwrap allows errors.Is(err, ErrConnectionAcquireTimeout)
+func (e *AcquireTimeoutError) Unwrap() error {
+	return ErrConnectionAcquireTimeout
+}
+
+// Read replica errors
+var (
+	// ErrEmptyReplicaDSN is returned when a read replica has no DSN
+	ErrEmptyReplicaDSN = errors.New("read replica connection string (DSN) cannot be empty")
+
+	// ErrUnknownReplicaLoadBalancing is returned when ReplicaLoadBalancing names an unknown strategy
+	ErrUnknownReplicaLoadBalancing = errors.New("unknown replica load balancing strategy")
+)
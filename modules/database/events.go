@@ -8,6 +8,13 @@ const (
 	EventTypeDisconnected    = "com.modular.database.disconnected"
 	EventTypeConnectionError = "com.modular.database.connection.error"
 
+	// Connection lifecycle events
+	EventTypeConnectionLost           = "com.modular.database.connection.lost"
+	EventTypeReconnectAttempt         = "com.modular.database.reconnect.attempt"
+	EventTypeReconnected              = "com.modular.database.reconnected"
+	EventTypeReconnectFailed          = "com.modular.database.reconnect.failed"
+	EventTypeConnectionAcquireTimeout = "com.modular.database.connection.acquire_timeout"
+
 	// Query events
 	EventTypeQueryExecuted = "com.modular.database.query.executed"
 	EventTypeQueryError    = "com.modular.database.query.error"
@@ -9,6 +9,7 @@ require (
 	github.com/cucumber/godog v0.15.1
 	github.com/davepgreene/go-db-credential-refresh v1.2.1
 	github.com/davepgreene/go-db-credential-refresh/store/awsrds v1.2.1
+	github.com/jackc/pgx/v5 v5.7.5
 	github.com/stretchr/testify v1.11.1
 	modernc.org/sqlite v1.38.0
 )
@@ -48,7 +49,6 @@ require (
 	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
 	github.com/jackc/pgtype v1.14.4 // indirect
 	github.com/jackc/pgx/v4 v4.18.3 // indirect
-	github.com/jackc/pgx/v5 v5.7.5 // indirect
 	github.com/jackc/puddle/v2 v2.2.2 // indirect
 	github.com/json-iterator/go v1.1.12 // indirect
 	github.com/lib/pq v1.10.9 // indirect
@@ -0,0 +1,43 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// HealthCheckStatus pings every connection for the application's readiness, which
+// makes the module a modular.HealthStatusReporter. The module is unhealthy while a
+// connection doesn't answer, and the details hold the pool statistics of each
+// connection by name.
+func (m *Module) HealthCheckStatus(ctx context.Context) (status, message string, details map[string]any) {
+	names := make([]string, 0, len(m.services))
+	for name := range m.services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	status = "healthy"
+	details = make(map[string]any, len(names))
+	var failures []string
+	for _, name := range names {
+		service := m.services[name]
+		stats := service.Stats()
+		connection := map[string]any{
+			"open_connections": stats.OpenConnections,
+			"in_use":           stats.InUse,
+			"idle":             stats.Idle,
+			"wait_count":       stats.WaitCount,
+		}
+		if err := service.Ping(ctx); err != nil {
+			connection["error"] = err.Error()
+			failures = append(failures, name+": "+err.Error())
+			status = "unhealthy"
+		}
+		details[name] = connection
+	}
+	if len(failures) > 0 {
+		message = "connections not answering: " + strings.Join(failures, "; ")
+	}
+	return status, message, details
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// downService is a connection that doesn't answer pings
+type downService struct {
+	DatabaseService
+}
+
+func (downService) Ping(context.Context) error { return errPingFailed }
+func (downService) Stats() sql.DBStats         { return sql.DBStats{WaitCount: 3} }
+func (downService) Close() error               { return nil }
+
+func TestModule_HealthCheckStatus(t *testing.T) {
+	module := NewModule()
+	app := NewMockApplication()
+	require.NoError(t, module.RegisterConfig(app))
+	app.RegisterConfigSection("database", &MockConfigProvider{config: &Config{
+		Default:     "primary",
+		Connections: map[string]*ConnectionConfig{"primary": {Driver: "sqlite", DSN: ":memory:"}},
+	}})
+	require.NoError(t, module.Init(app))
+	t.Cleanup(func() { _ = module.Stop(context.Background()) })
+
+	status, message, details := module.HealthCheckStatus(context.Background())
+	assert.Equal(t, "healthy", status)
+	assert.Empty(t, message)
+	assert.Contains(t, details["primary"], "open_connections")
+
+	module.services["replica"] = downService{}
+	status, message, details = module.HealthCheckStatus(context.Background())
+	assert.Equal(t, "unhealthy", status)
+	assert.Equal(t, "connections not answering: replica: ping failed", message)
+	assert.Equal(t, map[string]any{
+		"open_connections": 0,
+		"in_use":           0,
+		"idle":             0,
+		"wait_count":       int64(3),
+		"error":            "ping failed",
+	}, details["replica"])
+}
@@ -246,9 +246,21 @@ func (m *migrationServiceImpl) RunMigration(ctx context.Context, migration Migra
 	return nil
 }
 
+// MigrationLockKey is the lock a MigrationRunner with a locker holds while it
+// runs migrations.
+const MigrationLockKey = "database:migrations"
+
+// MigrationLocker runs a function while holding a distributed lock, such as the
+// locks module's locks.provider service. It waits until the lock is free.
+type MigrationLocker interface {
+	WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error
+}
+
 // MigrationRunner helps run multiple migrations
 type MigrationRunner struct {
 	service MigrationService
+	locker  MigrationLocker
+	lockTTL time.Duration
 }
 
 // NewMigrationRunner creates a new migration runner
@@ -258,8 +270,31 @@ func NewMigrationRunner(service MigrationService) *MigrationRunner {
 	}
 }
 
+// WithLocker makes the runner apply migrations while holding MigrationLockKey, so
+// that when several instances start together only one applies them while the
+// others wait and then find them applied. The lock is refreshed while migrations
+// run; a ttl of zero uses the locker's default.
+func (r *MigrationRunner) WithLocker(locker MigrationLocker, ttl time.Duration) *MigrationRunner {
+	r.locker = locker
+	r.lockTTL = ttl
+	return r
+}
+
 // RunMigrations runs a set of migrations in order
 func (r *MigrationRunner) RunMigrations(ctx context.Context, migrations []Migration) error {
+	if r.locker == nil {
+		return r.runMigrations(ctx, migrations)
+	}
+	if err := r.locker.WithLock(ctx, MigrationLockKey, r.lockTTL, func(ctx context.Context) error {
+		return r.runMigrations(ctx, migrations)
+	}); err != nil {
+		return fmt.Errorf("failed to run migrations under lock %s: %w", MigrationLockKey, err)
+	}
+	return nil
+}
+
+// runMigrations applies the migrations not applied yet.
+func (r *MigrationRunner) runMigrations(ctx context.Context, migrations []Migration) error {
 	// Sort migrations by version to ensure correct order
 	sort.Slice(migrations, func(i, j int) bool {
 		return migrations[i].Version < migrations[j].Version
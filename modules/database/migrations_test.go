@@ -3,6 +3,7 @@ package database
 import (
 	"context"
 	"database/sql"
+	"sync"
 	"testing"
 	"time"
 
@@ -79,3 +80,57 @@ func TestMigrationService_TableNameValidation(t *testing.T) {
 		t.Fatalf("expected validation error for invalid table name")
 	}
 }
+
+// recordingLocker runs functions under a single in-process lock, recording the keys
+type recordingLocker struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (l *recordingLocker) WithLock(ctx context.Context, key string, _ time.Duration, fn func(ctx context.Context) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keys = append(l.keys, key)
+	return fn(ctx)
+}
+
+func TestMigrationRunner_WithLocker(t *testing.T) {
+	db := openTestDB(t)
+	db.SetMaxOpenConns(1)
+	svc := NewMigrationService(db, nil)
+	locker := &recordingLocker{}
+	ctx := context.Background()
+
+	migrations := []Migration{
+		{ID: "001_create_table", Version: "001", SQL: "CREATE TABLE locked (id INTEGER PRIMARY KEY)"},
+	}
+
+	// Instances starting together apply the migrations once
+	var wg sync.WaitGroup
+	errs := make(chan error, 3)
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- NewMigrationRunner(svc).WithLocker(locker, time.Minute).RunMigrations(ctx, migrations)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("locked migration run failed: %v", err)
+		}
+	}
+
+	if len(locker.keys) != 3 || locker.keys[0] != MigrationLockKey {
+		t.Fatalf("expected every run under %s, got %v", MigrationLockKey, locker.keys)
+	}
+	applied, err := svc.GetAppliedMigrations(ctx)
+	if err != nil {
+		t.Fatalf("failed to fetch applied migrations: %v", err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("expected 1 applied migration, got %d (%v)", len(applied), applied)
+	}
+}
@@ -83,6 +83,22 @@ func (l *lazyDefaultService) DB() *sql.DB {
 	return service.DB()
 }
 
+func (l *lazyDefaultService) Reader() *sql.DB {
+	service := l.module.GetDefaultService()
+	if service == nil {
+		return nil
+	}
+	return service.Reader()
+}
+
+func (l *lazyDefaultService) Writer() *sql.DB {
+	service := l.module.GetDefaultService()
+	if service == nil {
+		return nil
+	}
+	return service.Writer()
+}
+
 func (l *lazyDefaultService) Ping(ctx context.Context) error {
 	service := l.module.GetDefaultService()
 	if service == nil {
@@ -410,6 +426,7 @@ type Module struct {
 	subject     modular.Subject // For event observation
 	subjectMu   sync.RWMutex    // Protects subject field from race conditions
 	logger      modular.Logger  // For structured logging
+	monitors    map[string]*connectionMonitor
 }
 
 var (
@@ -429,6 +446,7 @@ func NewModule() *Module {
 	return &Module{
 		connections: make(map[string]*sql.DB),
 		services:    make(map[string]DatabaseService),
+		monitors:    make(map[string]*connectionMonitor),
 	}
 }
 
@@ -526,7 +544,8 @@ func (m *Module) Init(app modular.Application) error {
 
 // Start starts the database module and verifies connectivity.
 // This method performs health checks on all database connections
-// to ensure they are ready for use by other modules.
+// to ensure they are ready for use by other modules, then starts
+// background reconnection monitors for connections that enable them.
 func (m *Module) Start(ctx context.Context) error {
 	// Test connections to make sure they're still alive
 	for name, db := range m.connections {
@@ -534,6 +553,19 @@ func (m *Module) Start(ctx context.Context) error {
 			return fmt.Errorf("failed to ping database connection '%s': %w", name, err)
 		}
 	}
+
+	if m.monitors == nil {
+		m.monitors = make(map[string]*connectionMonitor)
+	}
+	for name, service := range m.services {
+		connConfig, ok := m.config.Connections[name]
+		if !ok || connConfig.Reconnect == nil || !connConfig.Reconnect.Enabled {
+			continue
+		}
+		monitor := newConnectionMonitor(name, service, *connConfig.Reconnect, m.emitEvent)
+		monitor.start()
+		m.monitors[name] = monitor
+	}
 	return nil
 }
 
@@ -541,6 +573,12 @@ func (m *Module) Start(ctx context.Context) error {
 // This method gracefully closes all database connections and services,
 // ensuring proper cleanup during application shutdown.
 func (m *Module) Stop(ctx context.Context) error {
+	// Stop reconnection monitors before their connections are closed
+	for name, monitor := range m.monitors {
+		monitor.stop()
+		delete(m.monitors, name)
+	}
+
 	// Close all database services
 	for name, service := range m.services {
 		if err := service.Close(); err != nil {
@@ -585,6 +623,13 @@ func (m *Module) Dependencies() []string {
 	return nil // No dependencies
 }
 
+// ShutdownPhaseOrder returns the storage phase of the application's shutdown,
+// modular.ShutdownPhaseStorage, so connections close after the modules writing
+// through them stopped.
+func (m *Module) ShutdownPhaseOrder() int {
+	return 500
+}
+
 // ProvidesServices declares services provided by this module.
 // The database module provides:
 //   - database.manager: Module instance for direct database management
@@ -722,6 +767,7 @@ func (m *Module) GetService(name string) (DatabaseService, bool) {
 func (m *Module) initializeConnections(app modular.Application) error {
 	// Initialize database connections
 	if len(m.config.Connections) > 0 {
+		initStart := time.Now()
 		for name, connConfig := range m.config.Connections {
 			if connConfig.Driver == "" {
 				return ErrMissingDriver
@@ -731,10 +777,18 @@ func (m *Module) initializeConnections(app modular.Application) error {
 			}
 
 			// Create the database service and connect
-			dbService, err := NewDatabaseService(*connConfig, app.Logger())
+			dbService, err := newDatabaseService(name, *connConfig, app.Logger())
 			if err != nil {
 				return fmt.Errorf("failed to create database service for '%s': %w", name, err)
 			}
+			dbService.emit = m.emitEvent
+
+			// Spread out connection attempts from instances starting together
+			if connConfig.StartupJitter > 0 {
+				delay := time.Until(initStart.Add(jitter(connConfig.StartupJitter)))
+				m.logger.Debug("Delaying database connection", "connection", name, "delay", delay)
+				sleepContext(context.Background(), delay)
+			}
 
 			if err := dbService.Connect(); err != nil {
 				// Emit connection error event
@@ -836,6 +890,11 @@ func (m *Module) GetRegisteredEventTypes() []string {
 		EventTypeConnected,
 		EventTypeDisconnected,
 		EventTypeConnectionError,
+		EventTypeConnectionLost,
+		EventTypeReconnectAttempt,
+		EventTypeReconnected,
+		EventTypeReconnectFailed,
+		EventTypeConnectionAcquireTimeout,
 		EventTypeQueryExecuted,
 		EventTypeQueryError,
 		EventTypeTransactionStarted,
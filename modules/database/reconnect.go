@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+)
+
+// connectionMonitor pings a connection periodically and, when a ping fails,
+// retries with exponential backoff until the connection recovers. database/sql
+// re-dials broken connections on demand, so a successful ping means the pool
+// is usable again.
+type connectionMonitor struct {
+	name    string
+	service DatabaseService
+	config  ReconnectConfig
+	emit    func(ctx context.Context, eventType string, data map[string]interface{})
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+func newConnectionMonitor(name string, service DatabaseService, config ReconnectConfig,
+	emit func(ctx context.Context, eventType string, data map[string]interface{}),
+) *connectionMonitor {
+	return &connectionMonitor{
+		name:    name,
+		service: service,
+		config:  config.withDefaults(),
+		emit:    emit,
+		done:    make(chan struct{}),
+	}
+}
+
+// start runs the monitor in the background until stop is called.
+func (c *connectionMonitor) start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	go c.run(ctx)
+}
+
+// stop halts the monitor and waits for it to exit.
+func (c *connectionMonitor) stop() {
+	if c.cancel == nil {
+		return
+	}
+	c.cancel()
+	<-c.done
+}
+
+func (c *connectionMonitor) run(ctx context.Context) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := c.ping(ctx)
+		if err == nil || ctx.Err() != nil {
+			continue
+		}
+
+		lostAt := time.Now()
+		c.emit(ctx, EventTypeConnectionLost, map[string]interface{}{
+			"connection_name": c.name,
+			"error":           err.Error(),
+		})
+		c.reconnect(ctx, lostAt)
+		ticker.Reset(c.config.HealthCheckInterval)
+	}
+}
+
+// reconnect pings with exponential backoff until the connection recovers,
+// MaxAttempts is exhausted, or ctx is cancelled.
+func (c *connectionMonitor) reconnect(ctx context.Context, lostAt time.Time) {
+	var lastErr error
+	for attempt := 1; c.config.MaxAttempts <= 0 || attempt <= c.config.MaxAttempts; attempt++ {
+		delay := backoffDelay(c.config.InitialBackoff, c.config.MaxBackoff, attempt)
+		c.emit(ctx, EventTypeReconnectAttempt, map[string]interface{}{
+			"connection_name": c.name,
+			"attempt":         attempt,
+			"delay_ms":        delay.Milliseconds(),
+		})
+		if !sleepContext(ctx, delay) {
+			return
+		}
+
+		if lastErr = c.ping(ctx); lastErr == nil {
+			c.emit(ctx, EventTypeReconnected, map[string]interface{}{
+				"connection_name": c.name,
+				"attempts":        attempt,
+				"downtime_ms":     time.Since(lostAt).Milliseconds(),
+			})
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+
+	c.emit(ctx, EventTypeReconnectFailed, map[string]interface{}{
+		"connection_name": c.name,
+		"attempts":        c.config.MaxAttempts,
+		"error":           lastErr.Error(),
+	})
+}
+
+func (c *connectionMonitor) ping(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, c.config.PingTimeout)
+	defer cancel()
+	return c.service.Ping(pingCtx) //nolint:wrapcheck // reported in events only
+}
+
+// backoffDelay returns the delay before the given reconnection attempt:
+// initial doubled per attempt, capped at maxBackoff, with the upper half
+// randomized so that instances losing a shared database do not retry in lockstep.
+func backoffDelay(initial, maxBackoff time.Duration, attempt int) time.Duration {
+	delay := initial
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	half := delay / 2
+	return half + jitter(delay-half)
+}
+
+// jitter returns a random duration in [0, limit).
+func jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return rand.N(limit) //nolint:gosec // jitter does not need a cryptographic source
+}
+
+// sleepContext waits for d or until ctx is done, reporting whether the full
+// duration elapsed.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
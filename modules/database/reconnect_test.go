@@ -0,0 +1,160 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errPingFailed = errors.New("ping failed")
+
+// flakyPingService fails the first failures pings after the initial health check succeeds
+type flakyPingService struct {
+	DatabaseService
+	pings    atomic.Int32
+	failures int32
+}
+
+func (s *flakyPingService) Ping(ctx context.Context) error {
+	n := s.pings.Add(1)
+	if n > 1 && n <= 1+s.failures {
+		return errPingFailed
+	}
+	return nil
+}
+
+type lifecycleRecorder struct {
+	mu     sync.Mutex
+	events []string
+	data   []map[string]interface{}
+}
+
+func (r *lifecycleRecorder) emit(_ context.Context, eventType string, data map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType)
+	r.data = append(r.data, data)
+}
+
+func (r *lifecycleRecorder) has(eventType string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func TestConnectionMonitor_ReconnectsWithBackoff(t *testing.T) {
+	service := &flakyPingService{failures: 3}
+	recorder := &lifecycleRecorder{}
+	monitor := newConnectionMonitor("primary", service, ReconnectConfig{
+		Enabled:             true,
+		HealthCheckInterval: 5 * time.Millisecond,
+		InitialBackoff:      time.Millisecond,
+		MaxBackoff:          4 * time.Millisecond,
+	}, recorder.emit)
+
+	monitor.start()
+	require.Eventually(t, func() bool { return recorder.has(EventTypeReconnected) }, 2*time.Second, 5*time.Millisecond)
+	monitor.stop()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	require.GreaterOrEqual(t, len(recorder.events), 5)
+	assert.Equal(t, []string{
+		EventTypeConnectionLost,
+		EventTypeReconnectAttempt,
+		EventTypeReconnectAttempt,
+		EventTypeReconnectAttempt,
+		EventTypeReconnected,
+	}, recorder.events[:5])
+	assert.Equal(t, "primary", recorder.data[0]["connection_name"])
+	assert.Equal(t, 3, recorder.data[4]["attempts"])
+}
+
+func TestConnectionMonitor_GivesUpAfterMaxAttempts(t *testing.T) {
+	service := &flakyPingService{failures: 100}
+	recorder := &lifecycleRecorder{}
+	monitor := newConnectionMonitor("primary", service, ReconnectConfig{
+		Enabled:             true,
+		HealthCheckInterval: 5 * time.Millisecond,
+		InitialBackoff:      time.Millisecond,
+		MaxAttempts:         2,
+	}, recorder.emit)
+
+	monitor.start()
+	require.Eventually(t, func() bool { return recorder.has(EventTypeReconnectFailed) }, 2*time.Second, 5*time.Millisecond)
+	monitor.stop()
+
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	assert.Equal(t, []string{
+		EventTypeConnectionLost,
+		EventTypeReconnectAttempt,
+		EventTypeReconnectAttempt,
+		EventTypeReconnectFailed,
+	}, recorder.events[:4])
+	assert.Equal(t, errPingFailed.Error(), recorder.data[3]["error"])
+}
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 1; attempt <= 10; attempt++ {
+		base := time.Second << (attempt - 1)
+		if base > 10*time.Second {
+			base = 10 * time.Second
+		}
+		delay := backoffDelay(time.Second, 10*time.Second, attempt)
+		assert.GreaterOrEqual(t, delay, base/2, "attempt %d", attempt)
+		assert.Less(t, delay, base+1, "attempt %d", attempt)
+	}
+	assert.Zero(t, jitter(0))
+}
+
+func TestDatabaseService_MaxAcquireWait(t *testing.T) {
+	service, err := newDatabaseService("primary", ConnectionConfig{
+		Driver:             "sqlite",
+		DSN:                ":memory:",
+		MaxOpenConnections: 1,
+		MaxAcquireWait:     20 * time.Millisecond,
+	}, &MockLogger{})
+	require.NoError(t, err)
+	recorder := &lifecycleRecorder{}
+	service.emit = recorder.emit
+	require.NoError(t, service.Connect())
+	defer service.Close()
+
+	// Holding the only connection in a transaction exhausts the pool
+	tx, err := service.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	_, err = service.ExecContext(context.Background(), "SELECT 1")
+	require.ErrorIs(t, err, ErrConnectionAcquireTimeout)
+	var acquireErr *AcquireTimeoutError
+	require.ErrorAs(t, err, &acquireErr)
+	assert.Equal(t, "primary", acquireErr.Connection)
+	assert.Equal(t, 1, acquireErr.MaxOpen)
+	assert.True(t, recorder.has(EventTypeConnectionAcquireTimeout))
+
+	_, err = service.QueryContext(context.Background(), "SELECT 1")
+	require.ErrorIs(t, err, ErrConnectionAcquireTimeout)
+	_, err = service.BeginTx(context.Background(), nil)
+	require.ErrorIs(t, err, ErrConnectionAcquireTimeout)
+
+	// Once the transaction ends the connection is returned to the pool
+	require.NoError(t, tx.Rollback())
+	_, err = service.ExecContext(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+
+	rows, err := service.QueryContext(context.Background(), "SELECT 1")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+)
+
+// Replica load balancing strategies for ConnectionConfig.ReplicaLoadBalancing
+const (
+	// ReplicaLoadBalancingRoundRobin cycles through the replicas in order
+	ReplicaLoadBalancingRoundRobin = "round_robin"
+	// ReplicaLoadBalancingRandom picks a replica at random
+	ReplicaLoadBalancingRandom = "random"
+	// ReplicaLoadBalancingLeastConnections picks the replica with the fewest connections in use
+	ReplicaLoadBalancingLeastConnections = "least_connections"
+)
+
+// ReplicaConfig configures a read replica of a connection. The replica uses the
+// primary's driver, and the primary's pool and AWS IAM settings unless overridden,
+// so a replica of an IAM-authenticated primary gets its own refreshing token store:
+//
+//	connections:
+//	  main:
+//	    driver: postgres
+//	    dsn: "postgres://app@primary.example.com:5432/app"
+//	    replica_load_balancing: least_connections
+//	    replicas:
+//	      - dsn: "postgres://app@replica-1.example.com:5432/app"
+//	      - dsn: "postgres://app@replica-2.example.com:5432/app"
+//	        max_open_connections: 50
+type ReplicaConfig struct {
+	// DSN is the replica's connection string
+	DSN string `json:"dsn" yaml:"dsn"`
+
+	// MaxOpenConnections overrides the primary's MaxOpenConnections for this replica
+	MaxOpenConnections int `json:"max_open_connections" yaml:"max_open_connections"`
+
+	// MaxIdleConnections overrides the primary's MaxIdleConnections for this replica
+	MaxIdleConnections int `json:"max_idle_connections" yaml:"max_idle_connections"`
+
+	// AWSIAMAuth overrides the primary's AWS IAM authentication for this replica,
+	// for example when it lives in another region
+	AWSIAMAuth *AWSIAMAuthConfig `json:"aws_iam_auth,omitempty" yaml:"aws_iam_auth,omitempty"`
+}
+
+// replicaConnectionConfig returns the configuration used to open replica: the
+// primary's configuration with the replica's DSN and overrides applied.
+func replicaConnectionConfig(primary ConnectionConfig, replica ReplicaConfig) ConnectionConfig {
+	config := primary
+	config.DSN = replica.DSN
+	config.Replicas = nil
+	if replica.MaxOpenConnections > 0 {
+		config.MaxOpenConnections = replica.MaxOpenConnections
+	}
+	if replica.MaxIdleConnections > 0 {
+		config.MaxIdleConnections = replica.MaxIdleConnections
+	}
+	if replica.AWSIAMAuth != nil {
+		config.AWSIAMAuth = replica.AWSIAMAuth
+	}
+	return config
+}
+
+// validateReplicas checks the replica DSNs and load balancing strategy of config.
+func validateReplicas(config ConnectionConfig) error {
+	for i, replica := range config.Replicas {
+		if replica.DSN == "" {
+			return fmt.Errorf("%w: replica %d", ErrEmptyReplicaDSN, i)
+		}
+	}
+	switch config.ReplicaLoadBalancing {
+	case "", ReplicaLoadBalancingRoundRobin, ReplicaLoadBalancingRandom, ReplicaLoadBalancingLeastConnections:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownReplicaLoadBalancing, config.ReplicaLoadBalancing)
+	}
+}
+
+// Reader returns the pool to send reads to: one of the replicas chosen by
+// ReplicaLoadBalancing, or the primary when the connection has no replicas.
+// Replicas may lag behind the primary, so reads that must observe a preceding
+// write should use Writer.
+func (s *databaseServiceImpl) Reader() *sql.DB {
+	s.connMutex.RLock()
+	defer s.connMutex.RUnlock()
+	if len(s.replicas) == 0 {
+		return s.db
+	}
+	return s.replicas[s.pickReplica()]
+}
+
+// Writer returns the primary's pool. It is the same pool as DB.
+func (s *databaseServiceImpl) Writer() *sql.DB {
+	return s.DB()
+}
+
+// pickReplica returns the index of the replica Reader uses next. The caller
+// holds connMutex.
+func (s *databaseServiceImpl) pickReplica() int {
+	switch s.config.ReplicaLoadBalancing {
+	case ReplicaLoadBalancingRandom:
+		return rand.IntN(len(s.replicas)) //nolint:gosec // load balancing does not need a cryptographic source
+	case ReplicaLoadBalancingLeastConnections:
+		best, bestInUse := 0, s.replicas[0].Stats().InUse
+		for i, replica := range s.replicas[1:] {
+			if inUse := replica.Stats().InUse; inUse < bestInUse {
+				best, bestInUse = i+1, inUse
+			}
+		}
+		return best
+	default:
+		return int((s.replicaNext.Add(1) - 1) % uint64(len(s.replicas)))
+	}
+}
@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// newReplicaTestService connects a service to a SQLite primary and one replica
+// per name, each holding a "node" table with its name.
+func newReplicaTestService(t *testing.T, loadBalancing string, names ...string) *databaseServiceImpl {
+	t.Helper()
+	dir := t.TempDir()
+	nodeDSN := func(name string) string {
+		dsn := filepath.Join(dir, name+".db")
+		db, err := sql.Open("sqlite", dsn)
+		require.NoError(t, err)
+		defer db.Close()
+		_, err = db.Exec("CREATE TABLE node (name TEXT)")
+		require.NoError(t, err)
+		_, err = db.Exec("INSERT INTO node (name) VALUES (?)", name)
+		require.NoError(t, err)
+		return dsn
+	}
+
+	config := ConnectionConfig{Driver: "sqlite", DSN: nodeDSN("primary"), ReplicaLoadBalancing: loadBalancing}
+	for _, name := range names {
+		config.Replicas = append(config.Replicas, ReplicaConfig{DSN: nodeDSN(name)})
+	}
+	service, err := newDatabaseService("main", config, &MockLogger{})
+	require.NoError(t, err)
+	require.NoError(t, service.Connect())
+	t.Cleanup(func() { _ = service.Close() })
+	return service
+}
+
+func nodeName(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	var name string
+	require.NoError(t, db.QueryRow("SELECT name FROM node").Scan(&name))
+	return name
+}
+
+func TestReplicas_RoundRobinReads(t *testing.T) {
+	service := newReplicaTestService(t, "", "replica-1", "replica-2")
+
+	var reads []string
+	for range 4 {
+		reads = append(reads, nodeName(t, service.Reader()))
+	}
+	assert.Equal(t, []string{"replica-1", "replica-2", "replica-1", "replica-2"}, reads)
+	assert.Equal(t, "primary", nodeName(t, service.Writer()))
+	assert.Same(t, service.DB(), service.Writer())
+
+	var name string
+	require.NoError(t, service.QueryRowContext(context.Background(), "SELECT name FROM node").Scan(&name))
+	assert.Equal(t, "primary", name, "the service's query methods should use the primary")
+}
+
+func TestReplicas_LeastConnections(t *testing.T) {
+	service := newReplicaTestService(t, ReplicaLoadBalancingLeastConnections, "replica-1", "replica-2")
+
+	// Hold a connection on the first replica
+	conn, err := service.replicas[0].Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, "replica-2", nodeName(t, service.Reader()))
+	assert.Equal(t, "replica-2", nodeName(t, service.Reader()))
+}
+
+func TestReplicas_Random(t *testing.T) {
+	service := newReplicaTestService(t, ReplicaLoadBalancingRandom, "replica-1", "replica-2")
+
+	for range 10 {
+		assert.Contains(t, []string{"replica-1", "replica-2"}, nodeName(t, service.Reader()))
+	}
+}
+
+func TestReplicas_ReaderWithoutReplicasUsesPrimary(t *testing.T) {
+	service := newReplicaTestService(t, "")
+	assert.Same(t, service.DB(), service.Reader())
+}
+
+func TestReplicas_Validation(t *testing.T) {
+	_, err := newDatabaseService("main", ConnectionConfig{
+		Driver: "sqlite", DSN: ":memory:", Replicas: []ReplicaConfig{{}},
+	}, &MockLogger{})
+	require.ErrorIs(t, err, ErrEmptyReplicaDSN)
+
+	_, err = newDatabaseService("main", ConnectionConfig{
+		Driver: "sqlite", DSN: ":memory:", ReplicaLoadBalancing: "fastest",
+	}, &MockLogger{})
+	require.ErrorIs(t, err, ErrUnknownReplicaLoadBalancing)
+}
+
+func TestReplicas_ConnectFailureClosesOpenedPools(t *testing.T) {
+	service, err := newDatabaseService("main", ConnectionConfig{
+		Driver:   "sqlite",
+		DSN:      ":memory:",
+		Replicas: []ReplicaConfig{{DSN: filepath.Join(t.TempDir(), "missing", "replica.db")}},
+	}, &MockLogger{})
+	require.NoError(t, err)
+
+	err = service.Connect()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read replica 0")
+	assert.Nil(t, service.DB())
+}
+
+func TestReplicaConnectionConfig_InheritsPrimary(t *testing.T) {
+	primaryIAM := &AWSIAMAuthConfig{Enabled: true, Region: "us-east-1"}
+	replicaIAM := &AWSIAMAuthConfig{Enabled: true, Region: "us-west-2"}
+	primary := ConnectionConfig{
+		Driver:             "postgres",
+		DSN:                "postgres://app@primary:5432/app",
+		MaxOpenConnections: 20,
+		MaxIdleConnections: 5,
+		AWSIAMAuth:         primaryIAM,
+		Replicas:           []ReplicaConfig{{DSN: "postgres://app@replica:5432/app"}},
+	}
+
+	config := replicaConnectionConfig(primary, ReplicaConfig{DSN: "postgres://app@replica:5432/app", MaxOpenConnections: 50})
+	assert.Equal(t, "postgres://app@replica:5432/app", config.DSN)
+	assert.Equal(t, 50, config.MaxOpenConnections)
+	assert.Equal(t, 5, config.MaxIdleConnections)
+	assert.Same(t, primaryIAM, config.AWSIAMAuth, "replicas should reuse the primary's IAM authentication")
+	assert.Empty(t, config.Replicas)
+
+	config = replicaConnectionConfig(primary, ReplicaConfig{DSN: "postgres://app@replica:5432/app", AWSIAMAuth: replicaIAM})
+	assert.Same(t, replicaIAM, config.AWSIAMAuth)
+}
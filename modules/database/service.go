@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/CrisisTextLine/modular"
@@ -23,6 +24,15 @@ var (
 const (
 	// DefaultConnectionTimeout is the default timeout for database connection tests
 	DefaultConnectionTimeout = 5 * time.Second
+
+	// DefaultHealthCheckInterval is the default interval between reconnect health checks
+	DefaultHealthCheckInterval = 30 * time.Second
+
+	// DefaultReconnectInitialBackoff is the default delay before the first reconnection attempt
+	DefaultReconnectInitialBackoff = time.Second
+
+	// DefaultReconnectMaxBackoff is the default cap on the delay between reconnection attempts
+	DefaultReconnectMaxBackoff = time.Minute
 )
 
 // DatabaseService defines the operations that can be performed with a database
@@ -36,6 +46,13 @@ type DatabaseService interface {
 	// DB returns the underlying database connection
 	DB() *sql.DB
 
+	// Reader returns the pool for reads: a read replica when the connection has
+	// replicas, otherwise the primary
+	Reader() *sql.DB
+
+	// Writer returns the primary's pool, for writes and reads that must observe them
+	Writer() *sql.DB
+
 	// Ping verifies the database connection is still alive
 	Ping(ctx context.Context) error
 
@@ -102,6 +119,7 @@ type DatabaseService interface {
 
 // databaseServiceImpl implements the DatabaseService interface
 type databaseServiceImpl struct {
+	name             string
 	config           ConnectionConfig
 	db               *sql.DB
 	migrationService MigrationService
@@ -110,10 +128,22 @@ type databaseServiceImpl struct {
 	ctx              context.Context
 	cancel           context.CancelFunc
 	connMutex        sync.RWMutex // Protect database connection during recreation
+
+	// replicas are the read replica pools, in configuration order
+	replicas    []*sql.DB
+	replicaNext atomic.Uint64
+
+	// emit reports connection lifecycle events; set by the module, nil for standalone services
+	emit func(ctx context.Context, eventType string, data map[string]interface{})
 }
 
 // NewDatabaseService creates a new database service from configuration
 func NewDatabaseService(config ConnectionConfig, logger modular.Logger) (DatabaseService, error) {
+	return newDatabaseService("default", config, logger)
+}
+
+// newDatabaseService creates a database service for a named connection
+func newDatabaseService(name string, config ConnectionConfig, logger modular.Logger) (*databaseServiceImpl, error) {
 	if config.Driver == "" {
 		return nil, ErrEmptyDriver
 	}
@@ -122,8 +152,13 @@ func NewDatabaseService(config ConnectionConfig, logger modular.Logger) (Databas
 		return nil, ErrEmptyDSN
 	}
 
+	if err := validateReplicas(config); err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	service := &databaseServiceImpl{
+		name:   name,
 		config: config,
 		logger: logger,
 		ctx:    ctx,
@@ -134,13 +169,47 @@ func NewDatabaseService(config ConnectionConfig, logger modular.Logger) (Databas
 }
 
 func (s *databaseServiceImpl) Connect() error {
+	db, err := s.open(s.config)
+	if err != nil {
+		return err
+	}
+
+	replicas := make([]*sql.DB, 0, len(s.config.Replicas))
+	for i, replica := range s.config.Replicas {
+		s.logger.Info("Connecting to database read replica", "connection", s.name, "replica", i)
+		replicaDB, err := s.open(replicaConnectionConfig(s.config, replica))
+		if err != nil {
+			for _, opened := range append(replicas, db) {
+				_ = opened.Close()
+			}
+			return fmt.Errorf("connecting to read replica %d: %w", i, err)
+		}
+		replicas = append(replicas, replicaDB)
+	}
+
+	s.connMutex.Lock()
+	s.db = db
+	s.replicas = replicas
+	s.connMutex.Unlock()
+
+	// Initialize migration service after successful connection
+	if s.eventEmitter != nil {
+		s.migrationService = NewMigrationService(s.db, s.eventEmitter)
+	}
+
+	return nil
+}
+
+// open opens and pings a pool for config, using AWS IAM authentication when it
+// is enabled.
+func (s *databaseServiceImpl) open(config ConnectionConfig) (*sql.DB, error) {
 	var db *sql.DB
 	var err error
 
 	// If AWS IAM authentication is enabled, use go-db-credential-refresh for automatic token management
-	if s.config.AWSIAMAuth != nil && s.config.AWSIAMAuth.Enabled {
+	if config.AWSIAMAuth != nil && config.AWSIAMAuth.Enabled {
 		s.logger.Info("Connecting to database with AWS IAM authentication using credential refresh")
-		db, err = createDBWithCredentialRefresh(s.ctx, s.config, s.logger)
+		db, err = createDBWithCredentialRefresh(s.ctx, config, s.logger)
 		if err != nil {
 			s.logger.Error("AWS IAM authentication failed",
 				"error", err.Error(),
@@ -152,31 +221,31 @@ func (s *databaseServiceImpl) Connect() error {
 					"5. Ensure network connectivity to RDS (security groups, VPC, etc.)",
 					"6. Check CloudTrail logs for IAM authentication attempts",
 				})
-			return fmt.Errorf("failed to create database connection with credential refresh: %w", err)
+			return nil, fmt.Errorf("failed to create database connection with credential refresh: %w", err)
 		}
 	} else {
 		// Standard connection without IAM authentication
-		dsn := s.config.DSN
+		dsn := config.DSN
 
 		// Preprocess DSN to handle special characters
 		dsn, err = preprocessDSNForParsing(dsn)
 		if err != nil {
-			return fmt.Errorf("failed to preprocess DSN: %w", err)
+			return nil, fmt.Errorf("failed to preprocess DSN: %w", err)
 		}
 
-		db, err = sql.Open(s.config.Driver, dsn)
+		db, err = sql.Open(config.Driver, dsn)
 		if err != nil {
-			return fmt.Errorf("failed to open database connection: %w", err)
+			return nil, fmt.Errorf("failed to open database connection: %w", err)
 		}
 
 		// Configure connection pool
-		configureConnectionPool(db, s.config)
+		configureConnectionPool(db, config)
 	}
 
 	// Test connection with configurable timeout
 	timeout := DefaultConnectionTimeout
-	if s.config.AWSIAMAuth != nil && s.config.AWSIAMAuth.ConnectionTimeout > 0 {
-		timeout = s.config.AWSIAMAuth.ConnectionTimeout
+	if config.AWSIAMAuth != nil && config.AWSIAMAuth.ConnectionTimeout > 0 {
+		timeout = config.AWSIAMAuth.ConnectionTimeout
 	}
 	s.logger.Debug("Testing database connection", "timeout", timeout)
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -184,11 +253,11 @@ func (s *databaseServiceImpl) Connect() error {
 	if err := db.PingContext(ctx); err != nil {
 		if closeErr := db.Close(); closeErr != nil {
 			s.logger.Error("Failed to ping database and close connection", "ping_error", err, "close_error", closeErr)
-			return fmt.Errorf("failed to ping database and close connection: %w", err)
+			return nil, fmt.Errorf("failed to ping database and close connection: %w", err)
 		}
 
 		// Provide detailed diagnostics for ping failures
-		if s.config.AWSIAMAuth != nil && s.config.AWSIAMAuth.Enabled {
+		if config.AWSIAMAuth != nil && config.AWSIAMAuth.Enabled {
 			s.logger.Error("Database ping failed with IAM authentication",
 				"error", err.Error(),
 				"timeout", timeout,
@@ -205,20 +274,11 @@ func (s *databaseServiceImpl) Connect() error {
 				"error", err.Error(),
 				"timeout", timeout)
 		}
-		return fmt.Errorf("failed to ping database: %w", err)
+		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 	s.logger.Info("Database connection test successful")
 
-	s.connMutex.Lock()
-	s.db = db
-	s.connMutex.Unlock()
-
-	// Initialize migration service after successful connection
-	if s.eventEmitter != nil {
-		s.migrationService = NewMigrationService(s.db, s.eventEmitter)
-	}
-
-	return nil
+	return db, nil
 }
 
 func (s *databaseServiceImpl) Close() error {
@@ -230,14 +290,20 @@ func (s *databaseServiceImpl) Close() error {
 	// Close database connection
 	s.connMutex.Lock()
 	defer s.connMutex.Unlock()
+	var errs []error
+	for i, replica := range s.replicas {
+		if err := replica.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing read replica %d: %w", i, err))
+		}
+	}
+	s.replicas = nil
 	if s.db != nil {
-		err := s.db.Close()
-		if err != nil {
-			return fmt.Errorf("closing database connection: %w", err)
+		if err := s.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing database connection: %w", err))
 		}
 		s.db = nil
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
 func (s *databaseServiceImpl) DB() *sql.DB {
@@ -274,6 +340,18 @@ func (s *databaseServiceImpl) ExecContext(ctx context.Context, query string, arg
 	if s.db == nil {
 		return nil, ErrDatabaseNotConnected
 	}
+	if s.config.MaxAcquireWait > 0 {
+		conn, err := s.acquireConn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer conn.Close()
+		result, err := conn.ExecContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("executing query: %w", err)
+		}
+		return result, nil
+	}
 	result, err := s.db.ExecContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("executing query: %w", err)
@@ -299,6 +377,20 @@ func (s *databaseServiceImpl) QueryContext(ctx context.Context, query string, ar
 	if s.db == nil {
 		return nil, ErrDatabaseNotConnected
 	}
+	if s.config.MaxAcquireWait > 0 {
+		startCtx, started := s.startWithin(ctx)
+		rows, err := s.db.QueryContext(startCtx, query, args...)
+		if timeoutErr := started(); timeoutErr != nil {
+			if rows != nil {
+				_ = rows.Close()
+			}
+			return nil, timeoutErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("querying database: %w", err)
+		}
+		return rows, nil
+	}
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying database: %w", err)
@@ -325,6 +417,20 @@ func (s *databaseServiceImpl) BeginTx(ctx context.Context, opts *sql.TxOptions)
 	if s.db == nil {
 		return nil, ErrDatabaseNotConnected
 	}
+	if s.config.MaxAcquireWait > 0 {
+		startCtx, started := s.startWithin(ctx)
+		tx, err := s.db.BeginTx(startCtx, opts)
+		if timeoutErr := started(); timeoutErr != nil {
+			if tx != nil {
+				_ = tx.Rollback()
+			}
+			return nil, timeoutErr
+		}
+		if err != nil {
+			return nil, fmt.Errorf("beginning database transaction: %w", err)
+		}
+		return tx, nil
+	}
 	tx, err := s.db.BeginTx(ctx, opts)
 	if err != nil {
 		return nil, fmt.Errorf("beginning database transaction: %w", err)
@@ -333,14 +439,65 @@ func (s *databaseServiceImpl) BeginTx(ctx context.Context, opts *sql.TxOptions)
 }
 
 func (s *databaseServiceImpl) Begin() (*sql.Tx, error) {
-	if s.db == nil {
-		return nil, ErrDatabaseNotConnected
+	return s.BeginTx(context.Background(), nil)
+}
+
+// acquireConn obtains a dedicated connection from the pool, waiting at most
+// MaxAcquireWait. The caller's context still bounds the operation itself.
+func (s *databaseServiceImpl) acquireConn(ctx context.Context) (*sql.Conn, error) {
+	acquireCtx, cancel := context.WithTimeout(ctx, s.config.MaxAcquireWait)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := s.db.Conn(acquireCtx)
+	if err == nil {
+		return conn, nil
 	}
-	tx, err := s.db.BeginTx(context.Background(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("beginning database transaction: %w", err)
+	if ctx.Err() != nil || !errors.Is(err, context.DeadlineExceeded) {
+		return nil, fmt.Errorf("acquiring database connection: %w", err)
 	}
-	return tx, nil
+	return nil, s.acquireTimeout(ctx, start)
+}
+
+// startWithin returns the context to start rows or a transaction on, which outlive
+// the call and so run on the pool rather than a dedicated connection. A timer
+// cancels the context once MaxAcquireWait passes; calling started when the start
+// returned stops it, and returns an *AcquireTimeoutError if it had fired. Without a
+// timeout the context goes with the caller's context, like the rows or transaction.
+func (s *databaseServiceImpl) startWithin(ctx context.Context) (context.Context, func() error) {
+	startCtx, cancel := context.WithCancel(ctx)
+	start := time.Now()
+	timer := time.AfterFunc(s.config.MaxAcquireWait, cancel)
+	return startCtx, func() error {
+		if timer.Stop() || ctx.Err() != nil {
+			return nil
+		}
+		return s.acquireTimeout(ctx, start)
+	}
+}
+
+// acquireTimeout reports a wait for a pooled connection that took longer than
+// MaxAcquireWait since start.
+func (s *databaseServiceImpl) acquireTimeout(ctx context.Context, start time.Time) error {
+	stats := s.db.Stats()
+	acquireErr := &AcquireTimeoutError{
+		Connection: s.name,
+		Waited:     time.Since(start),
+		InUse:      stats.InUse,
+		MaxOpen:    stats.MaxOpenConnections,
+	}
+	s.logger.Warn("Timed out waiting for database connection",
+		"connection", s.name, "waited", acquireErr.Waited, "in_use", stats.InUse, "max_open", stats.MaxOpenConnections)
+	if s.emit != nil {
+		s.emit(ctx, EventTypeConnectionAcquireTimeout, map[string]interface{}{
+			"connection_name": s.name,
+			"waited_ms":       acquireErr.Waited.Milliseconds(),
+			"in_use":          stats.InUse,
+			"max_open":        stats.MaxOpenConnections,
+			"wait_count":      stats.WaitCount,
+		})
+	}
+	return acquireErr
 }
 
 // CommitTransaction commits a transaction and emits appropriate events
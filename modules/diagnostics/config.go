@@ -0,0 +1,97 @@
+package diagnostics
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// DiagnosticsConfig defines the admin listener and the endpoints it serves.
+type DiagnosticsConfig struct {
+	// Address is the host:port of the admin listener. It is separate from the
+	// application's HTTP server so it can stay off public interfaces.
+	Address string `json:"address" yaml:"address" env:"ADDRESS" default:"127.0.0.1:6060"`
+
+	// BasePath prefixes every diagnostics endpoint
+	BasePath string `json:"basePath" yaml:"basePath" env:"BASE_PATH" default:"/debug"`
+
+	// AuthToken, when set, must be sent as "Authorization: Bearer <token>"
+	AuthToken string `json:"authToken" yaml:"authToken" env:"AUTH_TOKEN"`
+
+	// AllowedCIDRs, when set, restricts clients to these networks
+	AllowedCIDRs []string `json:"allowedCIDRs" yaml:"allowedCIDRs" env:"ALLOWED_CIDRS"`
+
+	// DisablePprof stops serving the net/http/pprof profiles
+	DisablePprof bool `json:"disablePprof" yaml:"disablePprof" env:"DISABLE_PPROF"`
+
+	// DisableExpvar stops serving the expvar variables
+	DisableExpvar bool `json:"disableExpvar" yaml:"disableExpvar" env:"DISABLE_EXPVAR"`
+
+	// DisableRuntimeMetrics stops serving Go runtime metrics
+	DisableRuntimeMetrics bool `json:"disableRuntimeMetrics" yaml:"disableRuntimeMetrics" env:"DISABLE_RUNTIME_METRICS"`
+
+	// BlockProfileRate is passed to runtime.SetBlockProfileRate while the module runs.
+	// Zero leaves the block profile rate alone. Stop restores the rate previously set
+	// with SetBlockProfileRate, which is off unless the application set one with it.
+	BlockProfileRate int `json:"blockProfileRate" yaml:"blockProfileRate" env:"BLOCK_PROFILE_RATE"`
+
+	// MutexProfileFraction is passed to runtime.SetMutexProfileFraction while the
+	// module runs. Zero leaves mutex profiling off.
+	MutexProfileFraction int `json:"mutexProfileFraction" yaml:"mutexProfileFraction" env:"MUTEX_PROFILE_FRACTION"`
+
+	// MaxProfileDuration caps the seconds parameter of CPU profiles and execution traces
+	MaxProfileDuration time.Duration `json:"maxProfileDuration" yaml:"maxProfileDuration" env:"MAX_PROFILE_DURATION" default:"60s"`
+
+	// DumpDir is the directory dumps are written to when no DumpStorage service is
+	// registered. Empty disables dump capture without a DumpStorage.
+	DumpDir string `json:"dumpDir" yaml:"dumpDir" env:"DUMP_DIR"`
+
+	// DumpPrefix prefixes the keys dumps are stored under
+	DumpPrefix string `json:"dumpPrefix" yaml:"dumpPrefix" env:"DUMP_PREFIX" default:"diagnostics/"`
+}
+
+// Validate implements the ConfigValidator interface for DiagnosticsConfig. A listener
+// without an auth token or CIDR allowlist must bind to a loopback address.
+func (c *DiagnosticsConfig) Validate() error {
+	host, _, err := net.SplitHostPort(c.Address)
+	if err != nil {
+		return fmt.Errorf("%w: address %q: %w", ErrInvalidConfig, c.Address, err)
+	}
+	if c.BasePath != "" && !strings.HasPrefix(c.BasePath, "/") {
+		return fmt.Errorf("%w: basePath %q must start with /", ErrInvalidConfig, c.BasePath)
+	}
+	if _, err := c.allowedPrefixes(); err != nil {
+		return err
+	}
+	if c.BlockProfileRate < 0 || c.MutexProfileFraction < 0 || c.MaxProfileDuration < 0 {
+		return fmt.Errorf("%w: profile rates and durations must not be negative", ErrInvalidConfig)
+	}
+	if c.AuthToken == "" && len(c.AllowedCIDRs) == 0 && !isLoopbackHost(host) {
+		return fmt.Errorf("%w: %s", ErrUnprotectedListener, c.Address)
+	}
+	return nil
+}
+
+// allowedPrefixes parses AllowedCIDRs.
+func (c *DiagnosticsConfig) allowedPrefixes() ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(c.AllowedCIDRs))
+	for _, cidr := range c.AllowedCIDRs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("%w: allowedCIDRs entry %q: %w", ErrInvalidConfig, cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// isLoopbackHost reports whether a listener host only accepts local connections.
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && addr.IsLoopback()
+}
@@ -0,0 +1,99 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+)
+
+// CaptureDump writes the named runtime profile, such as "heap", "goroutine", "allocs"
+// or "block", to the registered DumpStorage, or to DumpDir without one. Goroutine
+// dumps are stored as text with full stacks, other profiles in the gzipped protobuf
+// format read by go tool pprof.
+func (m *DiagnosticsModule) CaptureDump(ctx context.Context, profile string) (Dump, error) {
+	dump, err := m.captureDump(ctx, profile)
+	if err != nil {
+		m.logger.Warn("Failed to capture diagnostics dump", "profile", profile, "error", err)
+		m.emitEvent(ctx, EventTypeDumpFailed, map[string]interface{}{
+			"profile": profile,
+			"error":   err.Error(),
+		})
+		return Dump{}, err
+	}
+	m.logger.Info("Captured diagnostics dump", "profile", profile, "key", dump.Key, "size", dump.Size)
+	m.emitEvent(ctx, EventTypeDumpCaptured, map[string]interface{}{
+		"profile": dump.Profile,
+		"key":     dump.Key,
+		"size":    dump.Size,
+	})
+	return dump, nil
+}
+
+func (m *DiagnosticsModule) captureDump(ctx context.Context, profile string) (Dump, error) {
+	p := pprof.Lookup(profile)
+	if p == nil {
+		return Dump{}, fmt.Errorf("%w: %q", ErrUnknownProfile, profile)
+	}
+	storage := m.dumpStorage()
+	if storage == nil {
+		return Dump{}, ErrNoDumpStorage
+	}
+
+	debug, extension := 0, ".pb.gz"
+	if profile == "goroutine" {
+		debug, extension = 2, ".txt"
+	}
+	var buf bytes.Buffer
+	if err := p.WriteTo(&buf, debug); err != nil {
+		return Dump{}, fmt.Errorf("writing %s profile: %w", profile, err)
+	}
+
+	dump := Dump{
+		Profile: profile,
+		Key:     m.config.DumpPrefix + profile + "-" + time.Now().UTC().Format("20060102T150405.000000000Z") + extension,
+		Size:    buf.Len(),
+	}
+	if err := storage.Put(ctx, dump.Key, &buf); err != nil {
+		return Dump{}, fmt.Errorf("storing %s: %w", dump.Key, err)
+	}
+	return dump, nil
+}
+
+// dumpStorage returns the registered DumpStorage, a directory store for DumpDir, or
+// nil when neither is available.
+func (m *DiagnosticsModule) dumpStorage() DumpStorage {
+	if m.storage != nil {
+		return m.storage
+	}
+	if m.config.DumpDir != "" {
+		return dirStorage(m.config.DumpDir)
+	}
+	return nil
+}
+
+// dirStorage stores dumps as files below a directory, keys being relative paths.
+type dirStorage string
+
+func (d dirStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	path := filepath.Join(string(d), filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("creating dump directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("creating dump file: %w", err)
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("writing dump file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing dump file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package diagnostics
+
+import (
+	"errors"
+)
+
+// Module-specific errors for the diagnostics module.
+var (
+	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
+	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrInvalidConfig is returned for malformed addresses, paths, CIDRs or rates
+	ErrInvalidConfig = errors.New("invalid diagnostics configuration")
+
+	// ErrUnprotectedListener is returned when a listener reachable from other hosts
+	// has neither an auth token nor a CIDR allowlist
+	ErrUnprotectedListener = errors.New("diagnostics listener on a non-loopback address requires authToken or allowedCIDRs")
+
+	// ErrUnknownProfile is returned when capturing a dump of a profile the runtime does not have
+	ErrUnknownProfile = errors.New("unknown profile")
+
+	// ErrNoDumpStorage is returned when capturing a dump without a DumpStorage or dumpDir
+	ErrNoDumpStorage = errors.New("no dump storage configured")
+
+	// ErrNotRunning is returned when asking for the listener address before Start
+	ErrNotRunning = errors.New("diagnostics listener is not running")
+)
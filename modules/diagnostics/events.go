@@ -0,0 +1,19 @@
+package diagnostics
+
+// Event type constants for diagnostics module events.
+// Following CloudEvents specification reverse domain notation.
+const (
+	// Configuration events
+	EventTypeConfigLoaded = "com.modular.diagnostics.config.loaded"
+
+	// Dump events
+	EventTypeDumpCaptured = "com.modular.diagnostics.dump.captured"
+	EventTypeDumpFailed   = "com.modular.diagnostics.dump.failed"
+
+	// EventTypeAccessDenied is emitted when a request fails authentication or the CIDR allowlist
+	EventTypeAccessDenied = "com.modular.diagnostics.access.denied"
+
+	// Module lifecycle events
+	EventTypeModuleStarted = "com.modular.diagnostics.module.started"
+	EventTypeModuleStopped = "com.modular.diagnostics.module.stopped"
+)
@@ -0,0 +1,183 @@
+package diagnostics
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"net/netip"
+	"runtime"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// newHandler returns the admin listener's handler, serving the enabled endpoints
+// under the base path behind the access checks.
+func (m *DiagnosticsModule) newHandler() http.Handler {
+	base := strings.TrimSuffix(m.config.BasePath, "/")
+	mux := http.NewServeMux()
+	if !m.config.DisablePprof {
+		mux.HandleFunc(base+"/pprof/", m.handlePprof(base+"/pprof/"))
+	}
+	if !m.config.DisableExpvar {
+		mux.Handle("GET "+base+"/vars", expvar.Handler())
+	}
+	if !m.config.DisableRuntimeMetrics {
+		mux.HandleFunc("GET "+base+"/runtime", m.handleRuntime)
+	}
+	mux.HandleFunc("POST "+base+"/dumps/{profile}", m.handleDump)
+	return m.authorize(mux)
+}
+
+// authorize rejects requests from outside the CIDR allowlist with 403 and requests
+// without the auth token with 401.
+func (m *DiagnosticsModule) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(m.allowed) > 0 && !m.clientAllowed(r.RemoteAddr) {
+			m.denied(w, r, http.StatusForbidden, "client address not allowed")
+			return
+		}
+		if m.config.AuthToken != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(m.config.AuthToken)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="diagnostics"`)
+				m.denied(w, r, http.StatusUnauthorized, "missing or invalid token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientAllowed reports whether the client address is in the CIDR allowlist.
+func (m *DiagnosticsModule) clientAllowed(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range m.allowed {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// denied logs, emits and answers a request failing the access checks.
+func (m *DiagnosticsModule) denied(w http.ResponseWriter, r *http.Request, status int, reason string) {
+	m.logger.Warn("Diagnostics access denied", "remote_addr", r.RemoteAddr, "path", r.URL.Path, "reason", reason)
+	m.emitEvent(r.Context(), EventTypeAccessDenied, map[string]interface{}{
+		"remote_addr": r.RemoteAddr,
+		"path":        r.URL.Path,
+		"reason":      reason,
+	})
+	http.Error(w, http.StatusText(status), status)
+}
+
+// handlePprof serves the pprof index and profiles under prefix. net/http/pprof
+// resolves named profiles only under /debug/pprof/, so they are dispatched here.
+func (m *DiagnosticsModule) handlePprof(prefix string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch name := strings.TrimPrefix(r.URL.Path, prefix); name {
+		case "":
+			pprof.Index(w, r)
+		case "cmdline":
+			pprof.Cmdline(w, r)
+		case "symbol":
+			pprof.Symbol(w, r)
+		case "profile":
+			m.capProfileSeconds(r)
+			pprof.Profile(w, r)
+		case "trace":
+			m.capProfileSeconds(r)
+			pprof.Trace(w, r)
+		default:
+			pprof.Handler(name).ServeHTTP(w, r)
+		}
+	}
+}
+
+// capProfileSeconds limits the seconds parameter of a CPU profile or trace request
+// to MaxProfileDuration. pprof defaults a missing parameter to 30 seconds for CPU
+// profiles and 1 second for traces.
+func (m *DiagnosticsModule) capProfileSeconds(r *http.Request) {
+	limit := m.config.MaxProfileDuration
+	if limit <= 0 {
+		return
+	}
+	query := r.URL.Query()
+	seconds, err := strconv.ParseFloat(query.Get("seconds"), 64)
+	if err != nil || seconds <= 0 {
+		if !strings.HasSuffix(r.URL.Path, "/profile") || limit >= 30*time.Second {
+			return
+		}
+		seconds = 30
+	}
+	if time.Duration(seconds*float64(time.Second)) <= limit {
+		return
+	}
+	query.Set("seconds", strconv.FormatFloat(limit.Seconds(), 'f', -1, 64))
+	r.URL.RawQuery = query.Encode()
+}
+
+// handleRuntime serves Go runtime metrics as JSON. Scalar metrics from runtime/metrics
+// are reported by name; histograms are left out.
+func (m *DiagnosticsModule) handleRuntime(w http.ResponseWriter, r *http.Request) {
+	descriptions := metrics.All()
+	samples := make([]metrics.Sample, len(descriptions))
+	for i, description := range descriptions {
+		samples[i].Name = description.Name
+	}
+	metrics.Read(samples)
+
+	values := make(map[string]interface{}, len(samples))
+	for _, sample := range samples {
+		switch sample.Value.Kind() {
+		case metrics.KindUint64:
+			values[sample.Name] = sample.Value.Uint64()
+		case metrics.KindFloat64:
+			values[sample.Name] = sample.Value.Float64()
+		case metrics.KindFloat64Histogram, metrics.KindBad:
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"go_version":     runtime.Version(),
+		"goroutines":     runtime.NumGoroutine(),
+		"gomaxprocs":     runtime.GOMAXPROCS(0),
+		"num_cpu":        runtime.NumCPU(),
+		"uptime_seconds": time.Since(m.started).Seconds(),
+		"metrics":        values,
+	})
+}
+
+// handleDump captures the profile named in the path to dump storage.
+func (m *DiagnosticsModule) handleDump(w http.ResponseWriter, r *http.Request) {
+	dump, err := m.CaptureDump(r.Context(), r.PathValue("profile"))
+	switch {
+	case errors.Is(err, ErrUnknownProfile):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, ErrNoDumpStorage):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		writeJSON(w, http.StatusCreated, dump)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,306 @@
+// Package diagnostics serves pprof, expvar and runtime metrics endpoints for the
+// modular framework.
+//
+// The diagnostics module mounts net/http/pprof, expvar and Go runtime metrics on a
+// dedicated admin listener, separate from the application's HTTP server, protected
+// by a bearer token and/or a CIDR allowlist. It also controls block and mutex
+// profile sampling, caps the duration of CPU profiles and traces, and captures heap
+// and goroutine dumps on demand to a DumpStorage service or a local directory.
+//
+// Example configuration:
+//
+//	diagnostics:
+//	  address: 0.0.0.0:6060
+//	  authToken: change-me
+//	  allowedCIDRs: ["10.0.0.0/8"]
+//	  blockProfileRate: 10000
+//	  mutexProfileFraction: 100
+//	  dumpDir: /var/tmp/dumps
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"reflect"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ModuleName is the unique identifier for the diagnostics module.
+const ModuleName = "diagnostics"
+
+// ServiceName is the name of the service provided by this module.
+const ServiceName = "diagnostics.provider"
+
+// DumpStorageServiceName is the key under which an optional DumpStorage is injected.
+const DumpStorageServiceName = "dumpStorage"
+
+// blockProfileRate is the rate last set through SetBlockProfileRate, since the runtime
+// doesn't report the current block profile rate.
+var blockProfileRate atomic.Int64
+
+// SetBlockProfileRate calls runtime.SetBlockProfileRate and returns the rate previously
+// set through it. The runtime can't report its rate, so the module restores the rate
+// set through this function when it stops, and applications profiling blocking on
+// their own set their rate with it rather than with the runtime directly.
+func SetBlockProfileRate(rate int) int {
+	runtime.SetBlockProfileRate(rate)
+	return int(blockProfileRate.Swap(int64(rate)))
+}
+
+// DiagnosticsModule runs the admin listener serving diagnostics endpoints.
+type DiagnosticsModule struct {
+	name    string
+	config  *DiagnosticsConfig
+	logger  modular.Logger
+	subject modular.Subject
+	storage DumpStorage
+	allowed []netip.Prefix
+
+	mu                    sync.Mutex
+	server                *http.Server
+	listener              net.Listener
+	started               time.Time
+	previousBlockRate     int
+	previousMutexFraction int
+}
+
+// NewModule creates a new instance of the diagnostics module.
+func NewModule() modular.Module {
+	return &DiagnosticsModule{
+		name: ModuleName,
+	}
+}
+
+// Name returns the unique identifier for this module.
+func (m *DiagnosticsModule) Name() string {
+	return m.name
+}
+
+// RegisterConfig registers the module's configuration structure.
+func (m *DiagnosticsModule) RegisterConfig(app modular.Application) error {
+	// Check if diagnostics config is already registered (e.g., by tests)
+	if existing, err := app.GetConfigSection(m.Name()); err == nil && existing != nil {
+		return nil
+	}
+
+	defaultConfig := &DiagnosticsConfig{
+		Address:            "127.0.0.1:6060",
+		BasePath:           "/debug",
+		MaxProfileDuration: 60 * time.Second,
+		DumpPrefix:         "diagnostics/",
+	}
+
+	app.RegisterConfigSection(m.Name(), modular.NewStdConfigProvider(defaultConfig))
+	return nil
+}
+
+// Init validates the configuration.
+func (m *DiagnosticsModule) Init(app modular.Application) error {
+	cfg, err := app.GetConfigSection(m.name)
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.name, err)
+	}
+
+	m.config = cfg.GetConfig().(*DiagnosticsConfig)
+	m.logger = app.Logger()
+
+	if err := m.config.Validate(); err != nil {
+		return err
+	}
+	allowed, err := m.config.allowedPrefixes()
+	if err != nil {
+		return err
+	}
+	m.allowed = allowed
+
+	m.emitEvent(context.Background(), EventTypeConfigLoaded, map[string]interface{}{
+		"address":        m.config.Address,
+		"base_path":      m.config.BasePath,
+		"pprof":          !m.config.DisablePprof,
+		"expvar":         !m.config.DisableExpvar,
+		"runtime":        !m.config.DisableRuntimeMetrics,
+		"authenticated":  m.config.AuthToken != "",
+		"allowed_cidrs":  len(m.allowed),
+		"dump_storage":   m.storage != nil,
+		"dump_directory": m.config.DumpDir,
+	})
+
+	m.logger.Info("Diagnostics module initialized", "address", m.config.Address)
+	return nil
+}
+
+// Start applies the profile sampling rates and starts the admin listener.
+func (m *DiagnosticsModule) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server != nil {
+		return nil
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", m.config.Address)
+	if err != nil {
+		return fmt.Errorf("starting diagnostics listener on %s: %w", m.config.Address, err)
+	}
+
+	if m.config.BlockProfileRate > 0 {
+		m.previousBlockRate = SetBlockProfileRate(m.config.BlockProfileRate)
+	}
+	if m.config.MutexProfileFraction > 0 {
+		m.previousMutexFraction = runtime.SetMutexProfileFraction(m.config.MutexProfileFraction)
+	}
+
+	m.started = time.Now()
+	m.listener = listener
+	// No write timeout: CPU profiles and traces stream for up to MaxProfileDuration
+	m.server = &http.Server{
+		Handler:           m.newHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func(server *http.Server) {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			m.logger.Error("Diagnostics listener failed", "error", err)
+		}
+	}(m.server)
+
+	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
+		"address": listener.Addr().String(),
+	})
+	m.logger.Info("Diagnostics listener started", "address", listener.Addr().String())
+	return nil
+}
+
+// Stop shuts the admin listener down and restores the profile sampling rates.
+func (m *DiagnosticsModule) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.server == nil {
+		return nil
+	}
+
+	err := m.server.Shutdown(ctx)
+	m.server = nil
+	m.listener = nil
+	if m.config.BlockProfileRate > 0 {
+		SetBlockProfileRate(m.previousBlockRate)
+	}
+	if m.config.MutexProfileFraction > 0 {
+		runtime.SetMutexProfileFraction(m.previousMutexFraction)
+	}
+	if err != nil {
+		return fmt.Errorf("stopping diagnostics listener: %w", err)
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStopped, map[string]interface{}{
+		"address": m.config.Address,
+	})
+	m.logger.Info("Diagnostics listener stopped")
+	return nil
+}
+
+// Dependencies returns the names of modules this module depends on.
+func (m *DiagnosticsModule) Dependencies() []string {
+	return nil
+}
+
+// ProvidesServices declares the services provided by this module.
+func (m *DiagnosticsModule) ProvidesServices() []modular.ServiceProvider {
+	return []modular.ServiceProvider{
+		{
+			Name:        ServiceName,
+			Description: "Runtime diagnostics and dump capture",
+			Instance:    m,
+		},
+	}
+}
+
+// RequiresServices declares the services this module uses. A DumpStorage is
+// optional; without one, dumps are written to DumpDir.
+func (m *DiagnosticsModule) RequiresServices() []modular.ServiceDependency {
+	return []modular.ServiceDependency{
+		{
+			Name:               DumpStorageServiceName,
+			Required:           false,
+			MatchByInterface:   true,
+			SatisfiesInterface: reflect.TypeOf((*DumpStorage)(nil)).Elem(),
+		},
+	}
+}
+
+// Constructor provides a dependency injection constructor for the module.
+func (m *DiagnosticsModule) Constructor() modular.ModuleConstructor {
+	return func(app modular.Application, services map[string]any) (modular.Module, error) {
+		if storage, ok := services[DumpStorageServiceName].(DumpStorage); ok {
+			m.storage = storage
+		}
+		return m, nil
+	}
+}
+
+// Addr returns the address the admin listener accepts connections on, which
+// differs from the configured address when it uses port 0.
+func (m *DiagnosticsModule) Addr() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.listener == nil {
+		return "", ErrNotRunning
+	}
+	return m.listener.Addr().String(), nil
+}
+
+// RegisterObservers implements the ObservableModule interface.
+func (m *DiagnosticsModule) RegisterObservers(subject modular.Subject) error {
+	m.subject = subject
+	return nil
+}
+
+// EmitEvent implements the ObservableModule interface.
+func (m *DiagnosticsModule) EmitEvent(ctx context.Context, event cloudevents.Event) error {
+	if m.subject == nil {
+		return ErrNoSubjectForEventEmission
+	}
+	if err := m.subject.NotifyObservers(ctx, event); err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+	return nil
+}
+
+// emitEvent creates and emits a CloudEvent for the diagnostics module. It silently
+// skips emission when no subject is available.
+func (m *DiagnosticsModule) emitEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if m.subject == nil {
+		return
+	}
+
+	event := modular.NewCloudEvent(eventType, "diagnostics-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+		if errors.Is(emitErr, ErrNoSubjectForEventEmission) {
+			return
+		}
+		if m.logger != nil {
+			m.logger.Warn("Failed to emit diagnostics event", "eventType", eventType, "error", emitErr)
+		}
+	}
+}
+
+// GetRegisteredEventTypes implements the ObservableModule interface.
+// Returns all event types that this diagnostics module can emit.
+func (m *DiagnosticsModule) GetRegisteredEventTypes() []string {
+	return []string{
+		EventTypeConfigLoaded,
+		EventTypeDumpCaptured,
+		EventTypeDumpFailed,
+		EventTypeAccessDenied,
+		EventTypeModuleStarted,
+		EventTypeModuleStopped,
+	}
+}
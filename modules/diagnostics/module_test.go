@@ -0,0 +1,294 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStorage records stored dumps by key.
+type memoryStorage struct {
+	mu    sync.Mutex
+	dumps map[string][]byte
+}
+
+func (s *memoryStorage) Put(ctx context.Context, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.dumps == nil {
+		s.dumps = make(map[string][]byte)
+	}
+	s.dumps[key] = data
+	return nil
+}
+
+func (s *memoryStorage) get(key string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.dumps[key]
+}
+
+// eventRecorder records the events emitted to an observable application.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (r *eventRecorder) record(ctx context.Context, event cloudevents.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRecorder) ofType(eventType string) []cloudevents.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []cloudevents.Event
+	for _, event := range r.events {
+		if event.Type() == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// startDiagnostics runs the diagnostics module in an observable application with
+// the given config, and a DumpStorage service when storage is not nil.
+func startDiagnostics(t *testing.T, config *DiagnosticsConfig, storage DumpStorage) (*DiagnosticsModule, string, *eventRecorder) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	app := modular.NewObservableApplication(modular.NewStdConfigProvider(nil), logger)
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+
+	recorder := &eventRecorder{}
+	require.NoError(t, app.RegisterObserver(modular.NewFunctionalObserver("diagnostics-test", recorder.record)))
+	if storage != nil {
+		require.NoError(t, app.RegisterService("test.dumpStorage", storage))
+	}
+
+	module := NewModule().(*DiagnosticsModule)
+	app.RegisterModule(module)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	addr, err := module.Addr()
+	require.NoError(t, err)
+	return module, "http://" + addr, recorder
+}
+
+func testConfig() *DiagnosticsConfig {
+	return &DiagnosticsConfig{
+		Address:            "127.0.0.1:0",
+		BasePath:           "/debug",
+		AuthToken:          "secret",
+		MaxProfileDuration: time.Minute,
+		DumpPrefix:         "diagnostics/",
+	}
+}
+
+func request(t *testing.T, method, url, token string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(context.Background(), method, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, body
+}
+
+func TestDiagnostics_EndpointsRequireToken(t *testing.T) {
+	_, base, recorder := startDiagnostics(t, testConfig(), nil)
+
+	resp, _ := request(t, http.MethodGet, base+"/debug/vars", "")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("WWW-Authenticate"), "Bearer")
+	resp, _ = request(t, http.MethodGet, base+"/debug/vars", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.Eventually(t, func() bool { return len(recorder.ofType(EventTypeAccessDenied)) == 2 }, time.Second, 10*time.Millisecond)
+
+	resp, body := request(t, http.MethodGet, base+"/debug/vars", "secret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"memstats"`)
+
+	resp, body = request(t, http.MethodGet, base+"/debug/runtime", "secret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var runtimeMetrics struct {
+		Goroutines int                `json:"goroutines"`
+		Metrics    map[string]float64 `json:"metrics"`
+	}
+	require.NoError(t, json.Unmarshal(body, &runtimeMetrics))
+	assert.Positive(t, runtimeMetrics.Goroutines)
+	assert.Contains(t, runtimeMetrics.Metrics, "/sched/goroutines:goroutines")
+
+	resp, body = request(t, http.MethodGet, base+"/debug/pprof/", "secret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "goroutine")
+
+	resp, body = request(t, http.MethodGet, base+"/debug/pprof/goroutine?debug=1", "secret")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), "goroutine profile:")
+}
+
+func TestDiagnostics_CustomBasePathAndDisabledEndpoints(t *testing.T) {
+	config := testConfig()
+	config.BasePath = "/admin"
+	config.AuthToken = ""
+	config.DisableExpvar = true
+	_, base, _ := startDiagnostics(t, config, nil)
+
+	resp, body := request(t, http.MethodGet, base+"/admin/pprof/heap?debug=1", "")
+	require.Equal(t, http.StatusOK, resp.StatusCode, "named profiles resolve outside /debug/pprof/")
+	assert.Contains(t, string(body), "heap profile:")
+
+	resp, _ = request(t, http.MethodGet, base+"/admin/vars", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestDiagnostics_CaptureDumpToStorage(t *testing.T) {
+	storage := &memoryStorage{}
+	module, base, recorder := startDiagnostics(t, testConfig(), storage)
+
+	resp, body := request(t, http.MethodPost, base+"/debug/dumps/goroutine", "secret")
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	var dump Dump
+	require.NoError(t, json.Unmarshal(body, &dump))
+	assert.Equal(t, "goroutine", dump.Profile)
+	assert.True(t, strings.HasPrefix(dump.Key, "diagnostics/goroutine-"))
+	assert.True(t, strings.HasSuffix(dump.Key, ".txt"))
+	assert.Contains(t, string(storage.get(dump.Key)), "goroutine ")
+	assert.Len(t, storage.get(dump.Key), dump.Size)
+
+	heap, err := module.CaptureDump(context.Background(), "heap")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(heap.Key, ".pb.gz"))
+	assert.True(t, bytes.HasPrefix(storage.get(heap.Key), []byte{0x1f, 0x8b}), "gzipped protobuf profile")
+	require.Eventually(t, func() bool { return len(recorder.ofType(EventTypeDumpCaptured)) == 2 }, time.Second, 10*time.Millisecond)
+
+	resp, _ = request(t, http.MethodPost, base+"/debug/dumps/nonexistent", "secret")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	require.Eventually(t, func() bool { return len(recorder.ofType(EventTypeDumpFailed)) == 1 }, time.Second, 10*time.Millisecond)
+
+	resp, _ = request(t, http.MethodGet, base+"/debug/dumps/heap", "secret")
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+}
+
+func TestDiagnostics_CaptureDumpToDirectory(t *testing.T) {
+	module := &DiagnosticsModule{
+		config: testConfig(),
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	_, err := module.CaptureDump(context.Background(), "heap")
+	require.ErrorIs(t, err, ErrNoDumpStorage)
+
+	module.config.DumpDir = t.TempDir()
+	dump, err := module.CaptureDump(context.Background(), "allocs")
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(module.config.DumpDir, filepath.FromSlash(dump.Key)))
+	require.NoError(t, err)
+	assert.Equal(t, int64(dump.Size), info.Size())
+}
+
+func TestDiagnostics_CIDRAllowlist(t *testing.T) {
+	config := testConfig()
+	config.AuthToken = ""
+	config.AllowedCIDRs = []string{"10.0.0.0/8"}
+	_, base, _ := startDiagnostics(t, config, nil)
+
+	resp, _ := request(t, http.MethodGet, base+"/debug/vars", "")
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	module := &DiagnosticsModule{config: config}
+	module.allowed, _ = config.allowedPrefixes()
+	assert.True(t, module.clientAllowed("10.1.2.3:5555"))
+	assert.True(t, module.clientAllowed("[::ffff:10.1.2.3]:5555"))
+	assert.False(t, module.clientAllowed("192.168.1.1:5555"))
+}
+
+func TestDiagnostics_ProfileSampling(t *testing.T) {
+	previous := runtime.SetMutexProfileFraction(-1)
+	SetBlockProfileRate(5)
+	defer SetBlockProfileRate(0)
+	config := testConfig()
+	config.MutexProfileFraction = 7
+	config.BlockProfileRate = 10
+	module, _, _ := startDiagnostics(t, config, nil)
+	assert.Equal(t, 7, runtime.SetMutexProfileFraction(-1))
+
+	require.NoError(t, module.Stop(context.Background()))
+	assert.Equal(t, previous, runtime.SetMutexProfileFraction(-1), "restored on stop")
+	assert.Equal(t, 5, SetBlockProfileRate(5), "the application's block profile rate is restored on stop")
+	_, err := module.Addr()
+	assert.ErrorIs(t, err, ErrNotRunning)
+}
+
+func TestDiagnostics_CapProfileSeconds(t *testing.T) {
+	module := &DiagnosticsModule{config: &DiagnosticsConfig{MaxProfileDuration: 5 * time.Second}}
+
+	r := httptest.NewRequest(http.MethodGet, "/debug/pprof/profile?seconds=600", nil)
+	module.capProfileSeconds(r)
+	assert.Equal(t, "5", r.URL.Query().Get("seconds"))
+
+	r = httptest.NewRequest(http.MethodGet, "/debug/pprof/profile", nil)
+	module.capProfileSeconds(r)
+	assert.Equal(t, "5", r.URL.Query().Get("seconds"), "pprof's 30 second default is capped too")
+
+	r = httptest.NewRequest(http.MethodGet, "/debug/pprof/trace?seconds=2", nil)
+	module.capProfileSeconds(r)
+	assert.Equal(t, "2", r.URL.Query().Get("seconds"))
+}
+
+func TestDiagnosticsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config DiagnosticsConfig
+		err    error
+	}{
+		{"public without protection", DiagnosticsConfig{Address: "0.0.0.0:6060"}, ErrUnprotectedListener},
+		{"missing port", DiagnosticsConfig{Address: "127.0.0.1"}, ErrInvalidConfig},
+		{"relative base path", DiagnosticsConfig{Address: "127.0.0.1:6060", BasePath: "debug"}, ErrInvalidConfig},
+		{"bad cidr", DiagnosticsConfig{Address: "0.0.0.0:6060", AllowedCIDRs: []string{"10.0.0.0"}}, ErrInvalidConfig},
+		{"negative rate", DiagnosticsConfig{Address: "127.0.0.1:6060", BlockProfileRate: -1}, ErrInvalidConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.Validate(), tt.err)
+		})
+	}
+
+	for _, valid := range []DiagnosticsConfig{
+		{Address: "localhost:6060"},
+		{Address: "[::1]:6060"},
+		{Address: ":6060", AuthToken: "secret"},
+		{Address: "0.0.0.0:6060", AllowedCIDRs: []string{"10.0.0.0/8"}},
+	} {
+		assert.NoError(t, valid.Validate(), valid.Address)
+	}
+}
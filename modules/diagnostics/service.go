@@ -0,0 +1,30 @@
+package diagnostics
+
+import (
+	"context"
+	"io"
+)
+
+// DumpStorage stores captured dumps. When a service implementing it is registered,
+// dumps are written to it under keys such as "diagnostics/heap-20260102T030405Z.pb.gz",
+// so they outlive the instance that captured them.
+type DumpStorage interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+}
+
+// Dump describes a captured dump.
+type Dump struct {
+	Profile string `json:"profile"`
+	Key     string `json:"key"`
+	Size    int    `json:"size"`
+}
+
+// DiagnosticsService captures dumps and reports where the admin listener runs.
+type DiagnosticsService interface {
+	// CaptureDump writes the named runtime profile, such as "heap" or "goroutine",
+	// to dump storage
+	CaptureDump(ctx context.Context, profile string) (Dump, error)
+
+	// Addr returns the address the admin listener accepts connections on
+	Addr() (string, error)
+}
@@ -0,0 +1,183 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BridgePathExtension is the CloudEvents extension recording the engines an event
+// has been bridged from, as a comma-separated list. Bridges never relay an event
+// to an engine already on its path, which prevents loops between engines bridged
+// in both directions.
+const BridgePathExtension = "eventbusbridgepath"
+
+// BridgeRule relays events consumed from one engine to another, for example to
+// copy audit events from the memory engine to Kafka while migrating engines.
+//
+//	bridges:
+//	  - name: "audit-to-kafka"
+//	    from: "memory"
+//	    to: "kafka"
+//	    topics: ["audit.*"]
+type BridgeRule struct {
+	// Name identifies the bridge in logs, events and SetBridgeTransform.
+	Name string `json:"name" yaml:"name" validate:"required"`
+
+	// From is the name of the engine events are consumed from.
+	From string `json:"from" yaml:"from" validate:"required"`
+
+	// To is the name of the engine events are republished to.
+	To string `json:"to" yaml:"to" validate:"required"`
+
+	// Topics lists the topic patterns to relay, such as "audit.*" or exact topics.
+	Topics []string `json:"topics" yaml:"topics" validate:"required,min=1"`
+}
+
+// BridgeTransform is called for every event a bridge relays, with a copy of the
+// event. It returns the event to republish, or false to skip the event. An error
+// skips the event and is reported as a failed bridge delivery.
+type BridgeTransform func(ctx context.Context, event Event) (Event, bool, error)
+
+// validateBridges checks that bridges have unique names and connect two different
+// configured engines.
+func validateBridges(bridges []BridgeRule, engineNames map[string]bool) error {
+	names := make(map[string]bool, len(bridges))
+	for _, bridge := range bridges {
+		if bridge.Name == "" {
+			return fmt.Errorf("%w: bridge name is required", ErrInvalidBridgeRule)
+		}
+		if names[bridge.Name] {
+			return fmt.Errorf("%w: duplicate bridge name %s", ErrInvalidBridgeRule, bridge.Name)
+		}
+		names[bridge.Name] = true
+		if !engineNames[bridge.From] {
+			return fmt.Errorf("%w: bridge %s: %w: %s", ErrInvalidBridgeRule, bridge.Name, ErrUnknownEngineRef, bridge.From)
+		}
+		if !engineNames[bridge.To] {
+			return fmt.Errorf("%w: bridge %s: %w: %s", ErrInvalidBridgeRule, bridge.Name, ErrUnknownEngineRef, bridge.To)
+		}
+		if bridge.From == bridge.To {
+			return fmt.Errorf("%w: bridge %s relays engine %s to itself", ErrInvalidBridgeRule, bridge.Name, bridge.From)
+		}
+		if len(bridge.Topics) == 0 {
+			return fmt.Errorf("%w: bridge %s has no topics", ErrInvalidBridgeRule, bridge.Name)
+		}
+	}
+	return nil
+}
+
+// SetBridgeTransform registers a transformation hook for the named bridge. It must be
+// called before Start.
+func (m *EventBusModule) SetBridgeTransform(bridge string, transform BridgeTransform) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.bridgeTransforms == nil {
+		m.bridgeTransforms = make(map[string]BridgeTransform)
+	}
+	m.bridgeTransforms[bridge] = transform
+}
+
+// startBridges subscribes each bridge to its topics on the source engine. Called with
+// m.mutex held after the engines have started.
+func (m *EventBusModule) startBridges(ctx context.Context) error {
+	for _, bridge := range m.config.Bridges {
+		source, ok := m.router.engines[bridge.From]
+		if !ok {
+			return fmt.Errorf("%w: bridge %s source %s", ErrEngineNotFound, bridge.Name, bridge.From)
+		}
+		target, ok := m.router.engines[bridge.To]
+		if !ok {
+			return fmt.Errorf("%w: bridge %s target %s", ErrEngineNotFound, bridge.Name, bridge.To)
+		}
+		handler := m.expiringHandler(m.bridgeHandler(bridge, target, m.bridgeTransforms[bridge.Name]))
+		for _, topic := range bridge.Topics {
+			sub, err := source.Subscribe(ctx, topic, handler)
+			if err != nil {
+				return fmt.Errorf("subscribing bridge %s to topic %s on engine %s: %w", bridge.Name, topic, bridge.From, err)
+			}
+			m.bridgeSubscriptions = append(m.bridgeSubscriptions, bridgeSubscription{engine: source, sub: sub})
+		}
+		m.logger.Info("Started eventbus bridge", "bridge", bridge.Name, "from", bridge.From, "to", bridge.To, "topics", bridge.Topics)
+	}
+	return nil
+}
+
+// stopBridges unsubscribes all bridges. Called with m.mutex held before the engines stop.
+func (m *EventBusModule) stopBridges(ctx context.Context) {
+	for _, bs := range m.bridgeSubscriptions {
+		if err := bs.engine.Unsubscribe(ctx, bs.sub); err != nil {
+			m.logger.Debug("Failed to unsubscribe eventbus bridge", "topic", bs.sub.Topic(), "error", err)
+		}
+	}
+	m.bridgeSubscriptions = nil
+}
+
+// bridgeSubscription is a bridge's subscription on its source engine.
+type bridgeSubscription struct {
+	engine EventBus
+	sub    Subscription
+}
+
+// bridgeHandler returns the subscription handler relaying events for bridge to target.
+func (m *EventBusModule) bridgeHandler(bridge BridgeRule, target EventBus, transform BridgeTransform) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		path := bridgePath(event)
+		if slices.Contains(path, bridge.To) {
+			return nil
+		}
+
+		relayed := event.Clone()
+		if transform != nil {
+			var ok bool
+			var err error
+			relayed, ok, err = transform(ctx, relayed)
+			if err != nil {
+				m.bridgeFailed(ctx, bridge, event, fmt.Errorf("transform: %w", err))
+				return nil
+			}
+			if !ok {
+				return nil
+			}
+		}
+		relayed.SetExtension(BridgePathExtension, strings.Join(append(path, bridge.From), ","))
+
+		if err := target.Publish(ctx, relayed); err != nil {
+			m.bridgeFailed(ctx, bridge, event, err)
+			return fmt.Errorf("bridge %s publishing to engine %s: %w", bridge.Name, bridge.To, err)
+		}
+		go m.emitEvent(ctx, EventTypeMessageBridged, map[string]interface{}{
+			"bridge": bridge.Name,
+			"from":   bridge.From,
+			"to":     bridge.To,
+			"topic":  relayed.Type(),
+		})
+		return nil
+	}
+}
+
+// bridgeFailed logs and emits a failed bridge delivery.
+func (m *EventBusModule) bridgeFailed(ctx context.Context, bridge BridgeRule, event Event, err error) {
+	m.logger.Warn("Eventbus bridge failed to relay event", "bridge", bridge.Name, "topic", event.Type(), "error", err)
+	go m.emitEvent(ctx, EventTypeBridgeFailed, map[string]interface{}{
+		"bridge": bridge.Name,
+		"from":   bridge.From,
+		"to":     bridge.To,
+		"topic":  event.Type(),
+		"error":  err.Error(),
+	})
+}
+
+// bridgePath returns the engines an event has been bridged from.
+func bridgePath(event Event) []string {
+	value, ok := event.Extensions()[BridgePathExtension]
+	if !ok {
+		return nil
+	}
+	path, ok := value.(string)
+	if !ok || path == "" {
+		return nil
+	}
+	return strings.Split(path, ",")
+}
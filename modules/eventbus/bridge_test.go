@@ -0,0 +1,129 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBridgeTestModule creates a started module with "primary" and "secondary" memory
+// engines, audit topics routed to primary, and the given bridges.
+func newBridgeTestModule(t *testing.T, bridges []BridgeRule) *EventBusModule {
+	t.Helper()
+
+	config := &EventBusConfig{
+		Engines: []EngineConfig{
+			{Name: "primary", Type: "memory", Config: map[string]interface{}{"workerCount": 2}},
+			{Name: "secondary", Type: "memory", Config: map[string]interface{}{"workerCount": 2}},
+		},
+		Routing: []RoutingRule{{Topics: []string{"audit.*"}, Engine: "primary"}},
+		Bridges: bridges,
+	}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	return &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+}
+
+// collectTopics subscribes to topic on engine and records received event types.
+func collectTopics(t *testing.T, engine EventBus, topic string) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var received []string
+	_, err := engine.Subscribe(context.Background(), topic, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Type())
+		return nil
+	})
+	require.NoError(t, err)
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func TestBridge_RelaysMatchingTopics(t *testing.T) {
+	m := newBridgeTestModule(t, []BridgeRule{{Name: "audit", From: "primary", To: "secondary", Topics: []string{"audit.*"}}})
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	received := collectTopics(t, m.router.engines["secondary"], "audit.*")
+	require.NoError(t, m.Publish(ctx, "audit.login", map[string]string{"user": "a"}))
+	require.NoError(t, m.Publish(ctx, "user.created", map[string]string{"user": "a"}))
+
+	require.Eventually(t, func() bool { return len(received()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"audit.login"}, received())
+}
+
+func TestBridge_PreventsLoops(t *testing.T) {
+	m := newBridgeTestModule(t, []BridgeRule{
+		{Name: "forward", From: "primary", To: "secondary", Topics: []string{"audit.*"}},
+		{Name: "back", From: "secondary", To: "primary", Topics: []string{"audit.*"}},
+	})
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	onPrimary := collectTopics(t, m.router.engines["primary"], "audit.*")
+	onSecondary := collectTopics(t, m.router.engines["secondary"], "audit.*")
+	require.NoError(t, m.Publish(ctx, "audit.login", nil))
+
+	require.Eventually(t, func() bool { return len(onSecondary()) == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Len(t, onPrimary(), 1)
+	assert.Len(t, onSecondary(), 1)
+}
+
+func TestBridge_Transform(t *testing.T) {
+	m := newBridgeTestModule(t, []BridgeRule{{Name: "audit", From: "primary", To: "secondary", Topics: []string{"audit.*"}}})
+	m.SetBridgeTransform("audit", func(ctx context.Context, event Event) (Event, bool, error) {
+		if event.Type() == "audit.debug" {
+			return event, false, nil
+		}
+		event.SetType("legacy." + event.Type())
+		return event, true, nil
+	})
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	received := collectTopics(t, m.router.engines["secondary"], "legacy.*")
+	require.NoError(t, m.Publish(ctx, "audit.debug", nil))
+	require.NoError(t, m.Publish(ctx, "audit.login", nil))
+
+	require.Eventually(t, func() bool { return len(received()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"legacy.audit.login"}, received())
+}
+
+func TestBridgeConfig_Validation(t *testing.T) {
+	engines := []EngineConfig{{Name: "a", Type: "memory"}, {Name: "b", Type: "memory"}}
+	tests := []struct {
+		name    string
+		bridges []BridgeRule
+	}{
+		{"missing name", []BridgeRule{{From: "a", To: "b", Topics: []string{"x.*"}}}},
+		{"unknown source", []BridgeRule{{Name: "x", From: "c", To: "b", Topics: []string{"x.*"}}}},
+		{"same engine", []BridgeRule{{Name: "x", From: "a", To: "a", Topics: []string{"x.*"}}}},
+		{"no topics", []BridgeRule{{Name: "x", From: "a", To: "b"}}},
+		{"duplicate name", []BridgeRule{
+			{Name: "x", From: "a", To: "b", Topics: []string{"x.*"}},
+			{Name: "x", From: "b", To: "a", Topics: []string{"x.*"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &EventBusConfig{Engines: engines, Bridges: tt.bridges}
+			assert.ErrorIs(t, config.ValidateConfig(), ErrInvalidBridgeRule)
+		})
+	}
+
+	single := &EventBusConfig{Bridges: []BridgeRule{{Name: "x", From: "a", To: "b", Topics: []string{"x.*"}}}}
+	assert.ErrorIs(t, single.ValidateConfig(), ErrInvalidBridgeRule)
+}
@@ -8,8 +8,14 @@ import (
 
 // Static errors for validation
 var (
-	ErrDuplicateEngineName = errors.New("duplicate engine name")
-	ErrUnknownEngineRef    = errors.New("routing rule references unknown engine")
+	ErrDuplicateEngineName   = errors.New("duplicate engine name")
+	ErrUnknownEngineRef      = errors.New("routing rule references unknown engine")
+	ErrInvalidBridgeRule     = errors.New("invalid bridge rule")
+	ErrInvalidTopicTTL       = errors.New("invalid topic TTL")
+	ErrInvalidHandlerTimeout = errors.New("invalid handler timeout")
+	ErrInvalidTopicVersion   = errors.New("invalid topic version")
+
+	ErrInvalidDeadLetterConfig = errors.New("invalid dead-letter configuration")
 )
 
 // EngineConfig defines the configuration for an individual event bus engine.
@@ -26,6 +32,10 @@ type EngineConfig struct {
 	// Config contains engine-specific configuration as a map.
 	// The structure depends on the engine type.
 	Config map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+
+	// DeadLetter dead-letters events on this engine's topics whose handler keeps
+	// failing, unless their routing rule has its own policy.
+	DeadLetter *DeadLetterConfig `json:"deadLetter,omitempty" yaml:"deadLetter,omitempty"`
 }
 
 // RoutingRule defines how topics are routed to engines.
@@ -37,6 +47,10 @@ type RoutingRule struct {
 	// Engine is the name of the engine to route matching topics to.
 	// Must match the name of a configured engine.
 	Engine string `json:"engine" yaml:"engine" validate:"required"`
+
+	// DeadLetter dead-letters events on matching topics whose handler keeps failing,
+	// overriding the engine's policy.
+	DeadLetter *DeadLetterConfig `json:"deadLetter,omitempty" yaml:"deadLetter,omitempty"`
 }
 
 // EventBusConfig defines the configuration for the event bus module.
@@ -151,6 +165,29 @@ type EventBusConfig struct {
 	// This should be kept secure and may be provided via environment variables.
 	ExternalBrokerPassword string `json:"externalBrokerPassword,omitempty" yaml:"externalBrokerPassword,omitempty" env:"EXTERNAL_BROKER_PASSWORD"`
 
+	// Compression selects the payload compression used by broker engines
+	// ("redis", "kafka", "kinesis", "nats"): "none" (default), "gzip" or "zstd".
+	// Consumers detect compressed payloads by their magic bytes, but subscribers on
+	// releases without compression support cannot read them, so redeploy subscribers
+	// before enabling compression on publishers. Used in single-engine mode;
+	// multi-engine setups set "compression" in each engine's config map.
+	Compression string `json:"compression,omitempty" yaml:"compression,omitempty" validate:"omitempty,oneof=none gzip zstd" env:"COMPRESSION"`
+
+	// CompressionThreshold is the serialized event size in bytes above which
+	// payloads are compressed. Defaults to 1024 when compression is enabled.
+	CompressionThreshold int `json:"compressionThreshold,omitempty" yaml:"compressionThreshold,omitempty" validate:"omitempty,min=0" env:"COMPRESSION_THRESHOLD"`
+
+	// MaxPayloadSize is the maximum encoded (post-compression) event size in bytes
+	// accepted by broker engines. Larger events are rejected on publish with
+	// ErrPayloadTooLarge instead of failing inside the broker. 0 means unlimited.
+	MaxPayloadSize int `json:"maxPayloadSize,omitempty" yaml:"maxPayloadSize,omitempty" validate:"omitempty,min=0" env:"MAX_PAYLOAD_SIZE"`
+
+	// MaxDecompressedSize is the maximum size in bytes a received compressed payload
+	// may expand to; larger payloads are rejected instead of being decompressed into
+	// memory. Publishers reject events whose serialized size exceeds it. Defaults to
+	// 32 MiB and is never lower than MaxPayloadSize.
+	MaxDecompressedSize int `json:"maxDecompressedSize,omitempty" yaml:"maxDecompressedSize,omitempty" validate:"omitempty,min=0" env:"MAX_DECOMPRESSED_SIZE"`
+
 	// --- Multi-Engine Configuration (New) ---
 
 	// Engines defines multiple event bus engines that can be used simultaneously.
@@ -162,6 +199,44 @@ type EventBusConfig struct {
 	// If no routing rules are specified and multiple engines are configured,
 	// all topics will be routed to the first engine.
 	Routing []RoutingRule `json:"routing,omitempty" yaml:"routing,omitempty" validate:"dive"`
+
+	// Bridges relay events consumed from one engine to another. Events carry the
+	// engines they were bridged from, so bridges in both directions don't loop.
+	// Requires multi-engine configuration.
+	Bridges []BridgeRule `json:"bridges,omitempty" yaml:"bridges,omitempty" validate:"dive"`
+
+	// TopicTTLs gives events published to matching topics a time to live, keyed by
+	// exact topic or wildcard pattern such as "notifications.*". Events still
+	// undelivered once it has passed are skipped, and pruned from durable-memory
+	// queues. An exact topic wins over patterns, and longer patterns over shorter
+	// ones. WithTTL overrides it per publish. Unlike EventTTL, which governs
+	// retention, it applies in both single- and multi-engine mode.
+	TopicTTLs map[string]time.Duration `json:"topicTTLs,omitempty" yaml:"topicTTLs,omitempty"`
+
+	// HandlerTimeouts caps how long a handler may run for events on matching topics,
+	// keyed by exact topic or wildcard pattern like TopicTTLs. A handler exceeding it
+	// has its context cancelled and stops occupying its worker.
+	HandlerTimeouts map[string]time.Duration `json:"handlerTimeouts,omitempty" yaml:"handlerTimeouts,omitempty"`
+
+	// HandlerTimeoutRequeues is how many times a durable-memory subscription requeues
+	// an event whose handler timed out before dropping it. Zero drops it at once;
+	// other engines never requeue.
+	HandlerTimeoutRequeues int `json:"handlerTimeoutRequeues,omitempty" yaml:"handlerTimeoutRequeues,omitempty" env:"HANDLER_TIMEOUT_REQUEUES"`
+
+	// TopicVersions is the current payload version of events on matching topics,
+	// keyed by exact topic or wildcard pattern like TopicTTLs. Events published to
+	// them carry it in their envelope, and older events are upcast to it on consume
+	// with the upcasters registered with RegisterUpcaster.
+	TopicVersions map[string]int `json:"topicVersions,omitempty" yaml:"topicVersions,omitempty"`
+
+	// DisableTracing turns off OpenTelemetry trace context propagation. By default
+	// publishing injects the publisher's trace context into the event's traceparent
+	// and tracestate extensions and handlers run in a span continuing it.
+	DisableTracing bool `json:"disableTracing,omitempty" yaml:"disableTracing,omitempty" env:"DISABLE_TRACING"`
+
+	// DeadLetter publishes events whose handler keeps failing to a dead-letter topic
+	// instead of dropping them. Engines and routing rules can override it.
+	DeadLetter *DeadLetterConfig `json:"deadLetter,omitempty" yaml:"deadLetter,omitempty"`
 }
 
 // IsMultiEngine returns true if this configuration uses multiple engines.
@@ -200,7 +275,15 @@ func (c *EventBusConfig) ValidateConfig() error {
 				return fmt.Errorf("%w: %s", ErrUnknownEngineRef, rule.Engine)
 			}
 		}
+
+		if err := validateBridges(c.Bridges, engineNames); err != nil {
+			return err
+		}
 	} else {
+		if len(c.Bridges) > 0 {
+			return fmt.Errorf("%w: bridges require multiple engines", ErrInvalidBridgeRule)
+		}
+
 		// Validate single-engine configuration has required fields
 		if c.Engine == "" {
 			c.Engine = "memory" // Default value
@@ -229,6 +312,19 @@ func (c *EventBusConfig) ValidateConfig() error {
 		}
 	}
 
+	if err := validateTopicTTLs(c.TopicTTLs); err != nil {
+		return err
+	}
+	if err := validateHandlerTimeouts(c.HandlerTimeouts, c.HandlerTimeoutRequeues); err != nil {
+		return err
+	}
+	if err := validateTopicVersions(c.TopicVersions); err != nil {
+		return err
+	}
+	if err := validateDeadLetters(c); err != nil {
+		return err
+	}
+
 	// Default source if not specified
 	if c.Source == "" {
 		c.Source = "eventbus"
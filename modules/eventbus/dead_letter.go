@@ -0,0 +1,307 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CloudEvents extensions describing why an event was dead-lettered. The
+// dead-lettered event keeps the original event's data and other extensions.
+const (
+	// DeadLetterTopicExtension holds the topic the event was published to
+	DeadLetterTopicExtension = "eventbusdlqtopic"
+	// DeadLetterEventIDExtension holds the ID of the original event
+	DeadLetterEventIDExtension = "eventbusdlqeventid"
+	// DeadLetterAttemptsExtension holds how many times the handler failed
+	DeadLetterAttemptsExtension = "eventbusdlqattempts"
+	// DeadLetterErrorExtension holds the handler's last error
+	DeadLetterErrorExtension = "eventbusdlqerror"
+	// DeadLetterFailedAtExtension holds when the handler last failed, as an RFC 3339 timestamp
+	DeadLetterFailedAtExtension = "eventbusdlqfailedat"
+)
+
+// defaultDeadLetterAttempts is how many times a handler runs before its event is
+// dead-lettered when DeadLetterConfig.MaxAttempts is not set.
+const defaultDeadLetterAttempts = 3
+
+// DeadLetterConfig publishes events whose handler keeps failing to a dead-letter
+// topic instead of dropping them. The handler is retried up to MaxAttempts times;
+// once every attempt failed the event is published to Topic, on the engine that
+// topic routes to, with the DeadLetter extensions describing the failures.
+//
+// It can be set for the whole bus, per engine or per routing rule; a routing
+// rule's policy wins over its engine's, which wins over the bus's:
+//
+//	deadLetter:
+//	  topic: "dead-letters"
+//	engines:
+//	  - name: "kafka"
+//	    type: "kafka"
+//	    deadLetter:
+//	      topic: "kafka.dead-letters"
+//	      maxAttempts: 5
+//	      retryDelay: 1s
+//
+// A handler that timed out counts as a failed attempt, so events on topics with
+// a dead-letter policy are dead-lettered instead of requeued by
+// HandlerTimeoutRequeues. Events already dead-lettered are never dead-lettered
+// again.
+type DeadLetterConfig struct {
+	// Topic is the topic failed events are published to.
+	Topic string `json:"topic" yaml:"topic" validate:"required"`
+
+	// MaxAttempts is how many times the handler runs before the event is
+	// dead-lettered. Default: 3
+	MaxAttempts int `json:"maxAttempts,omitempty" yaml:"maxAttempts,omitempty"`
+
+	// RetryDelay is how long to wait between attempts. Default: no delay
+	RetryDelay time.Duration `json:"retryDelay,omitempty" yaml:"retryDelay,omitempty"`
+}
+
+// attempts returns how many times the handler runs before dead-lettering.
+func (c *DeadLetterConfig) attempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return defaultDeadLetterAttempts
+}
+
+// validate checks the dead-letter topic, attempts and delay. Defaults are applied
+// on use so validating a tenant's config doesn't change the tenant service's copy.
+func (c *DeadLetterConfig) validate(scope string) error {
+	if c.Topic == "" {
+		return fmt.Errorf("%w: %s has no topic", ErrInvalidDeadLetterConfig, scope)
+	}
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("%w: %s has maxAttempts %d", ErrInvalidDeadLetterConfig, scope, c.MaxAttempts)
+	}
+	if c.RetryDelay < 0 {
+		return fmt.Errorf("%w: %s has retryDelay %s", ErrInvalidDeadLetterConfig, scope, c.RetryDelay)
+	}
+	return nil
+}
+
+// validateDeadLetters checks the bus, engine and routing rule dead-letter policies.
+func validateDeadLetters(c *EventBusConfig) error {
+	if c.DeadLetter != nil {
+		if err := c.DeadLetter.validate("deadLetter"); err != nil {
+			return err
+		}
+	}
+	for _, engine := range c.Engines {
+		if engine.DeadLetter != nil {
+			if err := engine.DeadLetter.validate("engine " + engine.Name); err != nil {
+				return err
+			}
+		}
+	}
+	for i, rule := range c.Routing {
+		if rule.DeadLetter != nil {
+			if err := rule.DeadLetter.validate(fmt.Sprintf("routing rule %d", i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeadLetter describes why an event was dead-lettered.
+type DeadLetter struct {
+	// Topic is the topic the event was published to
+	Topic string
+	// EventID is the ID of the original event
+	EventID string
+	// Attempts is how many times the handler failed
+	Attempts int
+	// LastError is the handler's last error
+	LastError string
+	// FailedAt is when the handler last failed
+	FailedAt time.Time
+}
+
+// EventDeadLetter returns why event was dead-lettered, if it is a dead-lettered event.
+func EventDeadLetter(event Event) (DeadLetter, bool) {
+	extensions := event.Extensions()
+	topic, ok := extensions[DeadLetterTopicExtension].(string)
+	if !ok {
+		return DeadLetter{}, false
+	}
+	deadLetter := DeadLetter{
+		Topic:    topic,
+		Attempts: intExtension(event, DeadLetterAttemptsExtension),
+	}
+	deadLetter.EventID, _ = extensions[DeadLetterEventIDExtension].(string)
+	deadLetter.LastError, _ = extensions[DeadLetterErrorExtension].(string)
+	if failedAt, ok := extensions[DeadLetterFailedAtExtension].(string); ok {
+		deadLetter.FailedAt, _ = time.Parse(time.RFC3339Nano, failedAt)
+	}
+	return deadLetter, true
+}
+
+// intExtension returns an integer extension of event, or 0 when it is missing.
+// Extensions set as int are stored as int32, and arrive as strings from brokers.
+func intExtension(event Event, name string) int {
+	switch v := event.Extensions()[name].(type) {
+	case int32:
+		return int(v)
+	case int:
+		return v
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return 0
+}
+
+// deadLetterPolicy returns the dead-letter policy for topic: that of the first
+// routing rule matching it, else of the engine it routes to, else of the bus.
+func (r *EngineRouter) deadLetterPolicy(topic string) *DeadLetterConfig {
+	engine := r.defaultEngine
+	for _, rule := range r.routing {
+		if r.matchesAny(topic, rule.Topics) {
+			if rule.DeadLetter != nil {
+				return rule.DeadLetter
+			}
+			engine = rule.Engine
+			break
+		}
+	}
+	if policy := r.engineDeadLetters[engine]; policy != nil {
+		return policy
+	}
+	return r.deadLetter
+}
+
+// matchesAny reports whether topic matches one of patterns.
+func (r *EngineRouter) matchesAny(topic string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if r.topicMatches(topic, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// deadLetterHandler wraps handler so that, on topics with a dead-letter policy, it
+// is retried while it fails and the event is then published to the dead-letter
+// topic through router. The event counts as handled once dead-lettered. If the
+// subscription's context is cancelled before then, the handler's error is
+// returned and the event isn't dead-lettered.
+func (m *EventBusModule) deadLetterHandler(router *EngineRouter, handler EventHandler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		policy := router.deadLetterPolicy(event.Type())
+		if policy == nil {
+			return handler(ctx, event)
+		}
+		if _, ok := EventDeadLetter(event); ok {
+			return handler(ctx, event)
+		}
+
+		maxAttempts := policy.attempts()
+		var err error
+		attempts := 0
+		for attempts < maxAttempts {
+			attempts++
+			if err = handler(ctx, event); err == nil {
+				return nil
+			}
+			if ctx.Err() != nil {
+				return err
+			}
+			if attempts < maxAttempts && policy.RetryDelay > 0 {
+				timer := time.NewTimer(policy.RetryDelay)
+				select {
+				case <-ctx.Done():
+					timer.Stop()
+					return err
+				case <-timer.C:
+				}
+			}
+		}
+
+		if dlqErr := m.deadLetter(ctx, router, policy, event, attempts, err); dlqErr != nil {
+			return errors.Join(err, dlqErr)
+		}
+		return nil
+	}
+}
+
+// deadLetterRedelivered returns the function engines routed by router use to
+// dead-letter an event they stopped redelivering after attempts failed deliveries.
+// It reports false, without publishing, when the event's topic has no dead-letter
+// policy or the event is already a dead letter.
+func (m *EventBusModule) deadLetterRedelivered(router *EngineRouter) func(ctx context.Context, event Event, attempts int, err error) (bool, error) {
+	return func(ctx context.Context, event Event, attempts int, err error) (bool, error) {
+		policy := router.deadLetterPolicy(event.Type())
+		if policy == nil {
+			return false, nil
+		}
+		if _, ok := EventDeadLetter(event); ok {
+			return false, nil
+		}
+		return true, m.deadLetter(ctx, router, policy, event, attempts, err)
+	}
+}
+
+// deadLetter publishes a copy of event with the failure metadata to the policy's
+// topic, counts it and emits message.dead_lettered.
+func (m *EventBusModule) deadLetter(ctx context.Context, router *EngineRouter, policy *DeadLetterConfig, event Event, attempts int, handlerErr error) error {
+	failedAt := time.Now().UTC()
+	dead := event.Clone()
+	dead.SetID(uuid.New().String())
+	dead.SetType(policy.Topic)
+	dead.SetExtension(DeadLetterTopicExtension, event.Type())
+	dead.SetExtension(DeadLetterEventIDExtension, event.ID())
+	dead.SetExtension(DeadLetterAttemptsExtension, attempts)
+	dead.SetExtension(DeadLetterErrorExtension, handlerErr.Error())
+	dead.SetExtension(DeadLetterFailedAtExtension, failedAt.Format(time.RFC3339Nano))
+	// The dead letter must outlive the original event and never be requeued
+	dead.SetExtension(ExpiresAtExtension, nil)
+	dead.SetExtension(TimeoutRequeuesExtension, nil)
+
+	if err := router.Publish(ctx, dead); err != nil {
+		if m.logger != nil {
+			m.logger.Error("Failed to dead-letter event", "topic", event.Type(), "event_id", event.ID(),
+				"dead_letter_topic", policy.Topic, "error", err)
+		}
+		return fmt.Errorf("dead-lettering event %s to topic %s: %w", event.ID(), policy.Topic, err)
+	}
+
+	m.deadLetterMutex.Lock()
+	if m.deadLetterCounts == nil {
+		m.deadLetterCounts = make(map[string]uint64)
+	}
+	m.deadLetterCounts[event.Type()]++
+	m.deadLetterMutex.Unlock()
+
+	if m.logger != nil {
+		m.logger.Warn("Dead-lettered event after failed handler attempts", "topic", event.Type(),
+			"event_id", event.ID(), "dead_letter_topic", policy.Topic, "attempts", attempts, "error", handlerErr)
+	}
+	go m.emitEvent(ctx, EventTypeMessageDeadLettered, map[string]interface{}{
+		"topic":             event.Type(),
+		"event_id":          event.ID(),
+		"dead_letter_topic": policy.Topic,
+		"attempts":          attempts,
+		"error":             handlerErr.Error(),
+	})
+	return nil
+}
+
+// DeadLetterStats returns the number of dead-lettered events per original topic
+// since the module was created.
+func (m *EventBusModule) DeadLetterStats() map[string]uint64 {
+	m.deadLetterMutex.Lock()
+	defer m.deadLetterMutex.Unlock()
+	stats := make(map[string]uint64, len(m.deadLetterCounts))
+	for topic, count := range m.deadLetterCounts {
+		stats[topic] = count
+	}
+	return stats
+}
@@ -0,0 +1,307 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDeadLetterTestModule creates and starts a module with the given config.
+func newDeadLetterTestModule(t *testing.T, config *EventBusConfig) *EventBusModule {
+	t.Helper()
+
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+	router.SetModuleReference(m)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	return m
+}
+
+// collectDeadLetters subscribes to topic and returns the events received on it.
+func collectDeadLetters(t *testing.T, m *EventBusModule, topic string) <-chan Event {
+	t.Helper()
+	received := make(chan Event, 10)
+	_, err := m.Subscribe(context.Background(), topic, func(_ context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+	return received
+}
+
+func TestDeadLetter_ConfigValidation(t *testing.T) {
+	config := &EventBusConfig{Engine: "memory", DeadLetter: &DeadLetterConfig{}}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidDeadLetterConfig)
+
+	config = &EventBusConfig{Engine: "memory", DeadLetter: &DeadLetterConfig{Topic: "dlq", MaxAttempts: -1}}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidDeadLetterConfig)
+
+	config = &EventBusConfig{
+		Engines: []EngineConfig{{Name: "memory", Type: "memory", DeadLetter: &DeadLetterConfig{Topic: "dlq", RetryDelay: -time.Second}}},
+	}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidDeadLetterConfig)
+
+	config = &EventBusConfig{
+		Engines: []EngineConfig{{Name: "memory", Type: "memory"}},
+		Routing: []RoutingRule{{Topics: []string{"*"}, Engine: "memory", DeadLetter: &DeadLetterConfig{}}},
+	}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidDeadLetterConfig)
+
+	config = &EventBusConfig{Engine: "memory", DeadLetter: &DeadLetterConfig{Topic: "dlq"}}
+	require.NoError(t, config.ValidateConfig())
+	assert.Equal(t, 0, config.DeadLetter.MaxAttempts, "defaults should not be written to the config")
+	assert.Equal(t, defaultDeadLetterAttempts, config.DeadLetter.attempts())
+}
+
+func TestDeadLetter_PolicyLookup(t *testing.T) {
+	bus := &DeadLetterConfig{Topic: "bus.dlq"}
+	engine := &DeadLetterConfig{Topic: "kafka.dlq"}
+	rule := &DeadLetterConfig{Topic: "orders.dlq"}
+	config := &EventBusConfig{
+		DeadLetter: bus,
+		Engines: []EngineConfig{
+			{Name: "memory", Type: "memory"},
+			{Name: "kafka", Type: "memory", DeadLetter: engine},
+		},
+		Routing: []RoutingRule{
+			{Topics: []string{"orders.*"}, Engine: "kafka", DeadLetter: rule},
+			{Topics: []string{"billing.*"}, Engine: "kafka"},
+		},
+	}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+
+	assert.Same(t, rule, router.deadLetterPolicy("orders.created"))
+	assert.Same(t, engine, router.deadLetterPolicy("billing.charged"))
+	assert.Same(t, bus, router.deadLetterPolicy("users.created"))
+}
+
+func TestDeadLetter_PublishesAfterFailedAttempts(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engine:      "memory",
+		WorkerCount: 1,
+		DeadLetter:  &DeadLetterConfig{Topic: "dead-letters", MaxAttempts: 3},
+	})
+	ctx := context.Background()
+	deadLetters := collectDeadLetters(t, m, "dead-letters")
+
+	var attempts atomic.Int32
+	_, err := m.SubscribeAsync(ctx, "orders.created", func(context.Context, Event) error {
+		attempts.Add(1)
+		return errors.New("payment service unavailable")
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "orders.created", map[string]string{"order": "42"}))
+
+	var dead Event
+	select {
+	case dead = <-deadLetters:
+	case <-time.After(time.Second):
+		t.Fatal("event was not dead-lettered")
+	}
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Equal(t, "dead-letters", dead.Type())
+
+	info, ok := EventDeadLetter(dead)
+	require.True(t, ok)
+	assert.Equal(t, "orders.created", info.Topic)
+	assert.NotEqual(t, dead.ID(), info.EventID)
+	assert.NotEmpty(t, info.EventID)
+	assert.Equal(t, 3, info.Attempts)
+	assert.Equal(t, "payment service unavailable", info.LastError)
+	assert.WithinDuration(t, time.Now(), info.FailedAt, time.Minute)
+
+	var payload map[string]string
+	require.NoError(t, dead.DataAs(&payload))
+	assert.Equal(t, "42", payload["order"])
+	assert.Equal(t, map[string]uint64{"orders.created": 1}, m.DeadLetterStats())
+}
+
+func TestDeadLetter_EventsAnEngineStoppedRedelivering(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engines: []EngineConfig{{Name: "memory", Type: "memory"}},
+		Routing: []RoutingRule{
+			{Topics: []string{"orders.*"}, Engine: "memory", DeadLetter: &DeadLetterConfig{Topic: "dead-letters"}},
+			{Topics: []string{"*"}, Engine: "memory"},
+		},
+	})
+	ctx := context.Background()
+	deadLetters := collectDeadLetters(t, m, "dead-letters")
+	deadLetter := m.deadLetterRedelivered(m.router)
+
+	event := cloudevents.NewEvent()
+	event.SetID("order-1")
+	event.SetSource("test")
+	event.SetType("orders.created")
+	deadLettered, err := deadLetter(ctx, event, 5, ErrMaxDeliveriesExceeded)
+	require.NoError(t, err)
+	assert.True(t, deadLettered)
+
+	select {
+	case dead := <-deadLetters:
+		info, ok := EventDeadLetter(dead)
+		require.True(t, ok)
+		assert.Equal(t, "order-1", info.EventID)
+		assert.Equal(t, 5, info.Attempts)
+		assert.Equal(t, ErrMaxDeliveriesExceeded.Error(), info.LastError)
+	case <-time.After(time.Second):
+		t.Fatal("event was not dead-lettered")
+	}
+
+	// Topics without a policy are left to the engine
+	event.SetType("audit.logged")
+	deadLettered, err = deadLetter(ctx, event, 5, ErrMaxDeliveriesExceeded)
+	require.NoError(t, err)
+	assert.False(t, deadLettered)
+}
+
+func TestDeadLetter_RecoveringHandlerIsNotDeadLettered(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engine:     "memory",
+		DeadLetter: &DeadLetterConfig{Topic: "dead-letters", MaxAttempts: 3, RetryDelay: time.Millisecond},
+	})
+	ctx := context.Background()
+	deadLetters := collectDeadLetters(t, m, "dead-letters")
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	_, err := m.Subscribe(ctx, "orders.created", func(context.Context, Event) error {
+		if attempts.Add(1) < 2 {
+			return errors.New("transient")
+		}
+		close(done)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "orders.created", nil))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler was not retried")
+	}
+
+	select {
+	case event := <-deadLetters:
+		t.Fatalf("unexpected dead letter %s", event.ID())
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Empty(t, m.DeadLetterStats())
+}
+
+func TestDeadLetter_FailingDeadLetterHandlerIsNotDeadLetteredAgain(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engine:     "memory",
+		DeadLetter: &DeadLetterConfig{Topic: "dead-letters", MaxAttempts: 2},
+	})
+	ctx := context.Background()
+
+	var deadLetterAttempts atomic.Int32
+	_, err := m.Subscribe(ctx, "dead-letters", func(context.Context, Event) error {
+		deadLetterAttempts.Add(1)
+		return errors.New("still failing")
+	})
+	require.NoError(t, err)
+	_, err = m.Subscribe(ctx, "orders.created", func(context.Context, Event) error {
+		return errors.New("failing")
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "orders.created", nil))
+	require.Eventually(t, func() bool { return deadLetterAttempts.Load() == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), deadLetterAttempts.Load())
+	assert.Equal(t, map[string]uint64{"orders.created": 1}, m.DeadLetterStats())
+}
+
+func TestDeadLetter_RoutesToRuleTopicOnItsEngine(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engines: []EngineConfig{
+			{Name: "primary", Type: "memory", Config: map[string]interface{}{"workerCount": 2}},
+			{Name: "failures", Type: "memory", Config: map[string]interface{}{"workerCount": 2}},
+		},
+		Routing: []RoutingRule{
+			{Topics: []string{"orders.*"}, Engine: "primary", DeadLetter: &DeadLetterConfig{Topic: "failed.orders", MaxAttempts: 1}},
+			{Topics: []string{"failed.*"}, Engine: "failures"},
+		},
+	})
+	ctx := context.Background()
+	deadLetters := collectDeadLetters(t, m, "failed.orders")
+	subject := &recordingSubject{}
+	require.NoError(t, m.RegisterObservers(subject))
+
+	_, err := m.Subscribe(ctx, "orders.created", func(context.Context, Event) error {
+		return errors.New("failing")
+	})
+	require.NoError(t, err)
+	_, err = m.Subscribe(ctx, "users.created", func(context.Context, Event) error {
+		return errors.New("failing")
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "users.created", nil))
+	require.NoError(t, m.Publish(ctx, "orders.created", nil))
+
+	select {
+	case dead := <-deadLetters:
+		info, ok := EventDeadLetter(dead)
+		require.True(t, ok)
+		assert.Equal(t, "orders.created", info.Topic)
+		assert.Equal(t, 1, info.Attempts)
+	case <-time.After(time.Second):
+		t.Fatal("event was not dead-lettered")
+	}
+	assert.Equal(t, "failures", m.router.GetEngineForTopic("failed.orders"))
+	require.Eventually(t, func() bool { return subject.has(EventTypeMessageDeadLettered) }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, map[string]uint64{"orders.created": 1}, m.DeadLetterStats(), "topics without a policy should not be dead-lettered")
+}
+
+func TestDeadLetter_TimedOutHandlerIsDeadLettered(t *testing.T) {
+	m := newDeadLetterTestModule(t, &EventBusConfig{
+		Engine:                 "durable-memory",
+		HandlerTimeouts:        map[string]time.Duration{"jobs.*": 10 * time.Millisecond},
+		HandlerTimeoutRequeues: 2,
+		DeadLetter:             &DeadLetterConfig{Topic: "jobs-dlq", MaxAttempts: 2},
+	})
+	ctx := context.Background()
+	deadLetters := collectDeadLetters(t, m, "jobs-dlq")
+
+	_, err := m.Subscribe(ctx, "jobs.stuck", func(ctx context.Context, _ Event) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "jobs.stuck", nil))
+	select {
+	case dead := <-deadLetters:
+		info, ok := EventDeadLetter(dead)
+		require.True(t, ok)
+		assert.Equal(t, 2, info.Attempts)
+		assert.Contains(t, info.LastError, ErrHandlerTimeout.Error())
+	case <-time.After(time.Second):
+		t.Fatal("event was not dead-lettered")
+	}
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(2), m.HandlerTimeoutStats()["jobs.stuck"], "dead-lettered events should not be requeued")
+}
+
+func TestEventDeadLetter_RegularEvent(t *testing.T) {
+	event := cloudevents.NewEvent()
+	event.SetType("orders.created")
+	_, ok := EventDeadLetter(event)
+	assert.False(t, ok)
+}
@@ -25,28 +25,35 @@ var ErrDurableQueueClosed = errors.New("durable queue closed")
 //
 // The zero maxDepth means unlimited capacity (use with caution: unbounded growth).
 type durableQueue struct {
-	mu       sync.Mutex
-	items    *list.List
-	maxDepth int           // 0 = unlimited
-	notEmpty chan struct{} // buffered(1); signaled on Push so consumer can wake
-	notFull  chan struct{} // buffered(1); signaled on TryPop from a full queue
+	mu        sync.Mutex
+	items     *list.List
+	maxDepth  int           // 0 = unlimited
+	notEmpty  chan struct{} // buffered(1); signaled on Push so consumer can wake
+	notFull   chan struct{} // buffered(1); signaled on TryPop from a full queue
+	onExpired func(Event)   // called for events pruned after their TTL passed; may be nil
 }
 
-func newDurableQueue(maxDepth int) *durableQueue {
+func newDurableQueue(maxDepth int, onExpired func(Event)) *durableQueue {
 	return &durableQueue{
-		items:    list.New(),
-		maxDepth: maxDepth,
-		notEmpty: make(chan struct{}, 1),
-		notFull:  make(chan struct{}, 1),
+		items:     list.New(),
+		maxDepth:  maxDepth,
+		notEmpty:  make(chan struct{}, 1),
+		notFull:   make(chan struct{}, 1),
+		onExpired: onExpired,
 	}
 }
 
 // Push enqueues event. If maxDepth > 0 and the queue is already at capacity,
-// Push blocks until a slot is freed, ctx is cancelled, or done is closed.
+// expired events are pruned to make room; if none have expired, Push blocks
+// until a slot is freed, ctx is cancelled, or done is closed.
 // Returns ctx.Err() on cancellation or ErrDurableQueueClosed when done is closed.
 func (q *durableQueue) Push(ctx context.Context, done <-chan struct{}, event Event) error {
 	for {
 		q.mu.Lock()
+		var pruned []Event
+		if q.maxDepth > 0 && q.items.Len() >= q.maxDepth {
+			pruned = q.pruneExpiredLocked(time.Now())
+		}
 		if q.maxDepth <= 0 || q.items.Len() < q.maxDepth {
 			q.items.PushBack(event)
 			// Non-blocking signal: wake at most one waiting TryPop caller.
@@ -55,6 +62,7 @@ func (q *durableQueue) Push(ctx context.Context, done <-chan struct{}, event Eve
 			default:
 			}
 			q.mu.Unlock()
+			q.reportExpired(pruned)
 			return nil
 		}
 		q.mu.Unlock()
@@ -94,6 +102,43 @@ func (q *durableQueue) TryPop() (Event, bool) {
 	return event, true
 }
 
+// Requeue appends an event taken off the queue back to it without blocking, so
+// the dispatch loop can redeliver it even when the queue is at capacity.
+func (q *durableQueue) Requeue(event Event) {
+	q.mu.Lock()
+	q.items.PushBack(event)
+	select {
+	case q.notEmpty <- struct{}{}:
+	default:
+	}
+	q.mu.Unlock()
+}
+
+// pruneExpiredLocked removes and returns the queued events whose TTL has passed.
+// Callers hold q.mu.
+func (q *durableQueue) pruneExpiredLocked(now time.Time) []Event {
+	var pruned []Event
+	for e := q.items.Front(); e != nil; {
+		next := e.Next()
+		if event := e.Value.(Event); eventExpired(event, now) { //nolint:forcetypeassert // durableQueue only stores Event values
+			q.items.Remove(e)
+			pruned = append(pruned, event)
+		}
+		e = next
+	}
+	return pruned
+}
+
+// reportExpired passes pruned events to the onExpired callback.
+func (q *durableQueue) reportExpired(events []Event) {
+	if q.onExpired == nil {
+		return
+	}
+	for _, event := range events {
+		q.onExpired(event)
+	}
+}
+
 // Notify returns the channel that is signaled whenever an item is pushed.
 // Callers should loop back to TryPop after receiving from this channel since
 // the notification is a hint, not a 1:1 guarantee per item.
@@ -324,7 +369,7 @@ func (d *DurableMemoryEventBus) subscribe(_ context.Context, topic string, handl
 		topic:    topic,
 		handler:  handler,
 		isAsync:  isAsync,
-		queue:    newDurableQueue(d.queueDepth()),
+		queue:    newDurableQueue(d.queueDepth(), d.expired),
 		done:     make(chan struct{}),
 		finished: make(chan struct{}),
 	}
@@ -404,6 +449,13 @@ func (d *DurableMemoryEventBus) Stats() (delivered uint64) {
 	return atomic.LoadUint64(&d.deliveredCount)
 }
 
+// expired reports an event pruned or skipped after its TTL passed to the module.
+func (d *DurableMemoryEventBus) expired(event Event) {
+	if d.module != nil {
+		d.module.recordExpired(d.ctx, event, "queue")
+	}
+}
+
 // handleEvents is the per-subscription event dispatch loop.
 // It drains the subscription's durableQueue and invokes the handler for each event.
 // The loop exits when the bus context is cancelled or the subscription is cancelled.
@@ -421,12 +473,22 @@ func (d *DurableMemoryEventBus) handleEvents(sub *durableSub) {
 			if sub.isCancelled() {
 				return
 			}
+			if eventExpired(event, time.Now()) {
+				d.expired(event)
+				continue
+			}
 			err := sub.handler(d.ctx, event)
 			if err != nil {
 				slog.Error("Durable event handler failed",
 					"error", err,
 					"topic", event.Type(),
 					"subscription_id", sub.id)
+				if d.module != nil {
+					if requeued, ok := d.module.requeueAfterTimeout(event, err); ok {
+						sub.queue.Requeue(requeued)
+						continue
+					}
+				}
 			}
 			atomic.AddUint64(&d.deliveredCount, 1)
 			continue
@@ -47,6 +47,10 @@ type EngineRouter struct {
 	engines       map[string]EventBus // Map of engine name to EventBus instance
 	routing       []RoutingRule       // Routing rules in order of precedence
 	defaultEngine string              // Default engine name for unmatched topics
+
+	// Dead-letter policies of the bus and of each engine by name
+	deadLetter        *DeadLetterConfig
+	engineDeadLetters map[string]*DeadLetterConfig
 }
 
 // NewEngineRouter creates a new engine router with the given configuration.
@@ -55,11 +59,15 @@ func NewEngineRouter(config *EventBusConfig) (*EngineRouter, error) {
 		engines:       make(map[string]EventBus),
 		routing:       config.Routing,
 		defaultEngine: config.GetDefaultEngine(),
+
+		deadLetter:        config.DeadLetter,
+		engineDeadLetters: make(map[string]*DeadLetterConfig),
 	}
 
 	if config.IsMultiEngine() {
 		// Create engines from multi-engine configuration
 		for _, engineConfig := range config.Engines {
+			router.engineDeadLetters[engineConfig.Name] = engineConfig.DeadLetter
 			engine, err := createEngine(engineConfig.Type, engineConfig.Config)
 			if err != nil {
 				return nil, fmt.Errorf("failed to create engine %s (%s): %w",
@@ -78,6 +86,10 @@ func NewEngineRouter(config *EventBusConfig) (*EngineRouter, error) {
 			"externalBrokerURL":      config.ExternalBrokerURL,
 			"externalBrokerUser":     config.ExternalBrokerUser,
 			"externalBrokerPassword": config.ExternalBrokerPassword,
+			"compression":            config.Compression,
+			"compressionThreshold":   config.CompressionThreshold,
+			"maxPayloadSize":         config.MaxPayloadSize,
+			"maxDecompressedSize":    config.MaxDecompressedSize,
 		}
 
 		engine, err := createEngine(config.Engine, engineConfig)
@@ -101,7 +113,8 @@ func createEngine(engineType string, config map[string]interface{}) (EventBus, e
 }
 
 // SetModuleReference sets the module reference for all memory event buses
-// This enables memory engines to emit events through the module
+// This enables memory engines to emit events through the module, and the Redis
+// streams engine to dead-letter the events it stops redelivering
 func (r *EngineRouter) SetModuleReference(module *EventBusModule) {
 	for _, engine := range r.engines {
 		if memoryEngine, ok := engine.(*MemoryEventBus); ok {
@@ -110,6 +123,9 @@ func (r *EngineRouter) SetModuleReference(module *EventBusModule) {
 		if durableEngine, ok := engine.(*DurableMemoryEventBus); ok {
 			durableEngine.SetModule(module)
 		}
+		if streamsEngine, ok := engine.(*RedisStreamsEventBus); ok {
+			streamsEngine.deadLetter = module.deadLetterRedelivered(r)
+		}
 	}
 }
 
@@ -296,6 +312,18 @@ func (r *EngineRouter) CollectPerEngineStats() map[string]DeliveryStats {
 	return stats
 }
 
+// CollectPayloadStats returns payload compression and size-limit statistics for
+// engines that encode payloads for a broker (NATS, Kafka, Kinesis, Redis).
+func (r *EngineRouter) CollectPayloadStats() map[string]PayloadStats {
+	stats := make(map[string]PayloadStats)
+	for name, engine := range r.engines {
+		if provider, ok := engine.(payloadStatsProvider); ok {
+			stats[name] = provider.PayloadStats()
+		}
+	}
+	return stats
+}
+
 // init registers the built-in engine types.
 func init() {
 	// Register memory engine
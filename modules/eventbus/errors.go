@@ -12,4 +12,28 @@ var (
 
 	// ErrNATSConnectionNotEstablished is returned when NATS connection is not established
 	ErrNATSConnectionNotEstablished = errors.New("NATS connection is not established")
+
+	// ErrPayloadTooLarge is returned when an encoded event exceeds the engine's maxPayloadSize
+	ErrPayloadTooLarge = errors.New("event payload exceeds maximum size")
+
+	// ErrUnsupportedCompression is returned when an engine is configured with an unknown compression algorithm
+	ErrUnsupportedCompression = errors.New("unsupported payload compression")
+
+	// ErrHandlerTimeout is returned to the engine when an event handler exceeds its topic's handler timeout
+	ErrHandlerTimeout = errors.New("event handler timed out")
+
+	// ErrInvalidUpcaster is returned when registering an upcaster without a topic, function or valid version
+	ErrInvalidUpcaster = errors.New("invalid upcaster")
+
+	// ErrUpcastFailed is returned to the engine when an upcaster fails to convert an event being consumed
+	ErrUpcastFailed = errors.New("event upcast failed")
+
+	// ErrInvalidTenantEngineConfig is returned when a tenant's eventbus config section cannot configure its engines
+	ErrInvalidTenantEngineConfig = errors.New("invalid tenant engine configuration")
+
+	// ErrTenantEnginesUnavailable is returned when a tenant with dedicated engines publishes or subscribes while they aren't running
+	ErrTenantEnginesUnavailable = errors.New("tenant engines unavailable")
+
+	// ErrMaxDeliveriesExceeded is the error dead-lettered events carry when the Redis streams engine stopped redelivering them
+	ErrMaxDeliveriesExceeded = errors.New("event delivered too many times")
 )
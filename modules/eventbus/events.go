@@ -7,6 +7,33 @@ const (
 	EventTypeMessagePublished = "com.modular.eventbus.message.published"
 	EventTypeMessageReceived  = "com.modular.eventbus.message.received"
 	EventTypeMessageFailed    = "com.modular.eventbus.message.failed"
+	EventTypeMessageRejected  = "com.modular.eventbus.message.rejected"
+
+	// EventTypeMessageExpired is emitted when an event's TTL passed before delivery
+	// and it was skipped or pruned
+	EventTypeMessageExpired = "com.modular.eventbus.message.expired"
+
+	// EventTypeHandlerTimeout is emitted when a handler exceeds its topic's handler
+	// timeout and its context is cancelled
+	EventTypeHandlerTimeout = "com.modular.eventbus.handler.timeout"
+
+	// EventTypeUpcastFailed is emitted when an upcaster fails to convert an event to
+	// its topic's version and the event isn't delivered
+	EventTypeUpcastFailed = "com.modular.eventbus.message.upcast_failed"
+
+	// EventTypeMessageDeadLettered is emitted when an event whose handler kept failing
+	// is published to its dead-letter topic
+	EventTypeMessageDeadLettered = "com.modular.eventbus.message.dead_lettered"
+
+	// Bridge events, emitted when a bridge relays an event between engines
+	EventTypeMessageBridged = "com.modular.eventbus.message.bridged"
+	EventTypeBridgeFailed   = "com.modular.eventbus.bridge.failed"
+
+	// Tenant engine events, emitted when a tenant's dedicated engines start, stop or
+	// fail to start
+	EventTypeTenantEnginesStarted = "com.modular.eventbus.tenant.engines.started"
+	EventTypeTenantEnginesStopped = "com.modular.eventbus.tenant.engines.stopped"
+	EventTypeTenantEnginesFailed  = "com.modular.eventbus.tenant.engines.failed"
 
 	// Topic events
 	EventTypeTopicCreated = "com.modular.eventbus.topic.created"
@@ -0,0 +1,130 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutRequeuesExtension is the CloudEvents extension counting how many times an
+// event was requeued after its handler exceeded the topic's handler timeout.
+const TimeoutRequeuesExtension = "eventbustimeoutrequeues"
+
+// validateHandlerTimeouts checks that every handler timeout is positive and the
+// requeue limit is not negative.
+func validateHandlerTimeouts(timeouts map[string]time.Duration, requeues int) error {
+	for topic, timeout := range timeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("%w: topic %s has handler timeout %s", ErrInvalidHandlerTimeout, topic, timeout)
+		}
+	}
+	if requeues < 0 {
+		return fmt.Errorf("%w: handlerTimeoutRequeues is %d", ErrInvalidHandlerTimeout, requeues)
+	}
+	return nil
+}
+
+// handlerTimeout returns the handler timeout configured for topic, preferring an
+// exact topic over the longest matching wildcard pattern.
+func (c *EventBusConfig) handlerTimeout(topic string) (time.Duration, bool) {
+	if timeout, ok := c.HandlerTimeouts[topic]; ok {
+		return timeout, true
+	}
+	var best string
+	var bestTimeout time.Duration
+	for pattern, timeout := range c.HandlerTimeouts {
+		if matchesTopic(topic, pattern) && len(pattern) > len(best) {
+			best, bestTimeout = pattern, timeout
+		}
+	}
+	return bestTimeout, best != ""
+}
+
+// timeoutHandler wraps handler so it runs with the topic's handler timeout. Once the
+// timeout passes the handler's context is cancelled and the wrapper returns an error
+// wrapping ErrHandlerTimeout without waiting for the handler, freeing the worker.
+// A handler ignoring its context keeps running in the background until it returns.
+func (m *EventBusModule) timeoutHandler(handler EventHandler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		if m.config == nil {
+			return handler(ctx, event)
+		}
+		timeout, ok := m.config.handlerTimeout(event.Type())
+		if !ok {
+			return handler(ctx, event)
+		}
+
+		handlerCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		result := make(chan error, 1)
+		go func() {
+			result <- handler(handlerCtx, event)
+		}()
+
+		select {
+		case err := <-result:
+			return err
+		case <-handlerCtx.Done():
+			if !errors.Is(handlerCtx.Err(), context.DeadlineExceeded) || ctx.Err() != nil {
+				return fmt.Errorf("event handler cancelled: %w", handlerCtx.Err())
+			}
+			m.recordHandlerTimeout(ctx, event, timeout)
+			return fmt.Errorf("%w: topic %s after %s", ErrHandlerTimeout, event.Type(), timeout)
+		}
+	}
+}
+
+// recordHandlerTimeout counts a handler timeout against its topic and emits
+// handler.timeout.
+func (m *EventBusModule) recordHandlerTimeout(ctx context.Context, event Event, timeout time.Duration) {
+	m.timeoutMutex.Lock()
+	if m.timeoutCounts == nil {
+		m.timeoutCounts = make(map[string]uint64)
+	}
+	m.timeoutCounts[event.Type()]++
+	m.timeoutMutex.Unlock()
+
+	if m.logger != nil {
+		m.logger.Warn("Event handler timed out", "topic", event.Type(), "event_id", event.ID(), "timeout", timeout)
+	}
+	go m.emitEvent(ctx, EventTypeHandlerTimeout, map[string]interface{}{
+		"topic":    event.Type(),
+		"event_id": event.ID(),
+		"timeout":  timeout.String(),
+		"requeues": timeoutRequeues(event),
+	})
+}
+
+// HandlerTimeoutStats returns the number of handler timeouts per topic since the
+// module was created.
+func (m *EventBusModule) HandlerTimeoutStats() map[string]uint64 {
+	m.timeoutMutex.Lock()
+	defer m.timeoutMutex.Unlock()
+	stats := make(map[string]uint64, len(m.timeoutCounts))
+	for topic, count := range m.timeoutCounts {
+		stats[topic] = count
+	}
+	return stats
+}
+
+// timeoutRequeues returns how many times event was requeued after a handler timeout.
+func timeoutRequeues(event Event) int {
+	return intExtension(event, TimeoutRequeuesExtension)
+}
+
+// requeueAfterTimeout returns the event to redeliver after err, a handler error, if
+// it is a handler timeout and the event has requeues left. Engines able to redeliver
+// to a single subscription call it to nack timed-out events.
+func (m *EventBusModule) requeueAfterTimeout(event Event, err error) (Event, bool) {
+	if m.config == nil || !errors.Is(err, ErrHandlerTimeout) {
+		return Event{}, false
+	}
+	requeues := timeoutRequeues(event)
+	if requeues >= m.config.HandlerTimeoutRequeues {
+		return Event{}, false
+	}
+	requeued := event.Clone()
+	requeued.SetExtension(TimeoutRequeuesExtension, requeues+1)
+	return requeued, true
+}
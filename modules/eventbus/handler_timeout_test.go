@@ -0,0 +1,228 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHandlerTimeoutTestModule creates a started single-engine module with the given
+// handler timeouts.
+func newHandlerTimeoutTestModule(t *testing.T, engine string, timeouts map[string]time.Duration, requeues int) *EventBusModule {
+	t.Helper()
+
+	config := &EventBusConfig{Engine: engine, WorkerCount: 1, HandlerTimeouts: timeouts, HandlerTimeoutRequeues: requeues}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+	router.SetModuleReference(m)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	return m
+}
+
+// recordingSubject records the event types it is notified of.
+type recordingSubject struct {
+	mu    sync.Mutex
+	types []string
+}
+
+func (s *recordingSubject) RegisterObserver(modular.Observer, ...string) error { return nil }
+func (s *recordingSubject) UnregisterObserver(modular.Observer) error          { return nil }
+func (s *recordingSubject) GetObservers() []modular.ObserverInfo               { return nil }
+
+func (s *recordingSubject) NotifyObservers(_ context.Context, event cloudevents.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.types = append(s.types, event.Type())
+	return nil
+}
+
+func (s *recordingSubject) has(eventType string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.types, eventType)
+}
+
+func TestHandlerTimeout_ConfigValidation(t *testing.T) {
+	config := &EventBusConfig{Engine: "memory", HandlerTimeouts: map[string]time.Duration{"jobs.*": 0}}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidHandlerTimeout)
+
+	config = &EventBusConfig{Engine: "memory", HandlerTimeoutRequeues: -1}
+	require.ErrorIs(t, config.ValidateConfig(), ErrInvalidHandlerTimeout)
+
+	config = &EventBusConfig{Engine: "memory", HandlerTimeouts: map[string]time.Duration{"jobs.*": time.Second}}
+	require.NoError(t, config.ValidateConfig())
+}
+
+func TestHandlerTimeout_TopicLookup(t *testing.T) {
+	config := &EventBusConfig{HandlerTimeouts: map[string]time.Duration{
+		"jobs.*":        time.Second,
+		"jobs.report.*": 2 * time.Second,
+		"jobs.export":   3 * time.Second,
+	}}
+
+	timeout, ok := config.handlerTimeout("jobs.export")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, timeout)
+
+	timeout, ok = config.handlerTimeout("jobs.report.daily")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, timeout)
+
+	timeout, ok = config.handlerTimeout("jobs.resize")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, timeout)
+
+	_, ok = config.handlerTimeout("users.created")
+	assert.False(t, ok)
+}
+
+func TestHandlerTimeout_CancelsStuckAsyncHandler(t *testing.T) {
+	m := newHandlerTimeoutTestModule(t, "memory", map[string]time.Duration{"jobs.*": 30 * time.Millisecond}, 0)
+	ctx := context.Background()
+
+	cancelled := make(chan error, 1)
+	var processed atomic.Int32
+	_, err := m.SubscribeAsync(ctx, "jobs.*", func(ctx context.Context, event Event) error {
+		if event.Type() == "jobs.stuck" {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return ctx.Err()
+		}
+		processed.Add(1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "jobs.stuck", nil))
+	require.NoError(t, m.Publish(ctx, "jobs.quick", nil))
+
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("stuck handler context was not cancelled")
+	}
+	require.Eventually(t, func() bool { return processed.Load() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, map[string]uint64{"jobs.stuck": 1}, m.HandlerTimeoutStats())
+}
+
+func TestHandlerTimeout_FreesWorkerFromHandlerIgnoringContext(t *testing.T) {
+	m := newHandlerTimeoutTestModule(t, "memory", map[string]time.Duration{"jobs.*": 20 * time.Millisecond}, 0)
+	ctx := context.Background()
+
+	release := make(chan struct{})
+	defer close(release)
+	var processed atomic.Int32
+	_, err := m.SubscribeAsync(ctx, "jobs.*", func(ctx context.Context, event Event) error {
+		if event.Type() == "jobs.stuck" {
+			<-release
+			return nil
+		}
+		processed.Add(1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "jobs.stuck", nil))
+	require.NoError(t, m.Publish(ctx, "jobs.quick", nil))
+
+	require.Eventually(t, func() bool { return processed.Load() == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, uint64(1), m.HandlerTimeoutStats()["jobs.stuck"])
+}
+
+func TestHandlerTimeout_UnconfiguredTopicsUnaffected(t *testing.T) {
+	m := newHandlerTimeoutTestModule(t, "memory", map[string]time.Duration{"jobs.*": 10 * time.Millisecond}, 0)
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	_, err := m.Subscribe(ctx, "users.created", func(ctx context.Context, event Event) error {
+		time.Sleep(40 * time.Millisecond)
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		close(done)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "users.created", nil))
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler was not called")
+	}
+	assert.Empty(t, m.HandlerTimeoutStats())
+}
+
+func TestHandlerTimeout_DurableMemoryRequeues(t *testing.T) {
+	m := newHandlerTimeoutTestModule(t, "durable-memory", map[string]time.Duration{"jobs.flaky": 20 * time.Millisecond}, 2)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var attempts []int
+	_, err := m.Subscribe(ctx, "jobs.flaky", func(ctx context.Context, event Event) error {
+		mu.Lock()
+		attempts = append(attempts, timeoutRequeues(event))
+		mu.Unlock()
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "jobs.flaky", nil))
+
+	require.Eventually(t, func() bool {
+		return m.HandlerTimeoutStats()["jobs.flaky"] == 3
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []int{0, 1, 2}, attempts)
+	mu.Unlock()
+}
+
+func TestHandlerTimeout_EmitsEvent(t *testing.T) {
+	m := newHandlerTimeoutTestModule(t, "memory", map[string]time.Duration{"jobs.*": 10 * time.Millisecond}, 0)
+	subject := &recordingSubject{}
+	require.NoError(t, m.RegisterObservers(subject))
+	ctx := context.Background()
+
+	_, err := m.Subscribe(ctx, "jobs.stuck", func(ctx context.Context, event Event) error {
+		<-ctx.Done()
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.Publish(ctx, "jobs.stuck", nil))
+
+	require.Eventually(t, func() bool {
+		return subject.has(EventTypeHandlerTimeout)
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestHandlerTimeout_RequeueOnlyForTimeouts(t *testing.T) {
+	m := &EventBusModule{config: &EventBusConfig{HandlerTimeoutRequeues: 1}}
+	event := newTestCloudEvent("jobs.flaky", nil)
+
+	_, ok := m.requeueAfterTimeout(event, errors.New("boom"))
+	assert.False(t, ok)
+
+	requeued, ok := m.requeueAfterTimeout(event, ErrHandlerTimeout)
+	require.True(t, ok)
+	assert.Equal(t, 1, timeoutRequeues(requeued))
+	assert.Equal(t, 0, timeoutRequeues(event))
+
+	_, ok = m.requeueAfterTimeout(requeued, ErrHandlerTimeout)
+	assert.False(t, ok)
+}
@@ -0,0 +1,87 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/nats-io/nats.go"
+)
+
+// EngineHealthChecker is implemented by engines that can check the connection to
+// their broker. The module's health check pings every started engine implementing
+// it; the Redis, Redis Streams, NATS and Kinesis engines do.
+type EngineHealthChecker interface {
+	Ping(ctx context.Context) error
+}
+
+// HealthCheckStatus reports the engines to the application's readiness, which makes
+// the module a modular.HealthStatusReporter. The module is unhealthy before it
+// starts and while an engine's broker doesn't answer, and the details hold the
+// state of each engine by name.
+func (m *EventBusModule) HealthCheckStatus(ctx context.Context) (status, message string, details map[string]any) {
+	m.mutex.RLock()
+	started, router := m.isStarted, m.router
+	m.mutex.RUnlock()
+	if !started || router == nil {
+		return "unhealthy", "event bus not started", nil
+	}
+
+	names := router.GetEngineNames()
+	sort.Strings(names)
+	status = "healthy"
+	details = make(map[string]any, len(names))
+	var failures []string
+	for _, name := range names {
+		checker, ok := router.engines[name].(EngineHealthChecker)
+		if !ok {
+			details[name] = "started"
+			continue
+		}
+		if err := checker.Ping(ctx); err != nil {
+			details[name] = err.Error()
+			failures = append(failures, name+": "+err.Error())
+			status = "unhealthy"
+			continue
+		}
+		details[name] = "connected"
+	}
+	if len(failures) > 0 {
+		message = "engines not connected: " + strings.Join(failures, "; ")
+	}
+	return status, message, details
+}
+
+// Ping checks that Redis answers.
+func (r *RedisEventBus) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("pinging Redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that Redis answers.
+func (r *RedisStreamsEventBus) Ping(ctx context.Context) error {
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("pinging Redis: %w", err)
+	}
+	return nil
+}
+
+// Ping checks that the NATS connection is established.
+func (n *NatsEventBus) Ping(context.Context) error {
+	if status := n.conn.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("%w: %s", ErrNATSConnectionNotEstablished, status)
+	}
+	return nil
+}
+
+// Ping checks that the stream can be described.
+func (k *KinesisEventBus) Ping(ctx context.Context) error {
+	if _, err := k.client.DescribeStream(ctx, &kinesis.DescribeStreamInput{StreamName: &k.config.StreamName}); err != nil {
+		return fmt.Errorf("describing Kinesis stream %s: %w", k.config.StreamName, err)
+	}
+	return nil
+}
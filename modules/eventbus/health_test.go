@@ -0,0 +1,60 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBrokerDown = errors.New("broker down")
+
+// pingingEventBus is a memory engine whose broker answers pings with err
+type pingingEventBus struct {
+	*CustomMemoryEventBus
+	err error
+}
+
+func (p *pingingEventBus) Ping(context.Context) error { return p.err }
+
+func TestEventBusModule_HealthCheckStatus(t *testing.T) {
+	broker := &pingingEventBus{}
+	RegisterEngine("health-test", func(config map[string]interface{}) (EventBus, error) {
+		engine, err := NewCustomMemoryEventBus(config)
+		if err != nil {
+			return nil, err
+		}
+		broker.CustomMemoryEventBus = engine.(*CustomMemoryEventBus)
+		return broker, nil
+	})
+	router, err := NewEngineRouter(&EventBusConfig{
+		Engines: []EngineConfig{
+			{Name: "local", Type: "memory"},
+			{Name: "broker", Type: "health-test"},
+		},
+		Routing: []RoutingRule{{Topics: []string{"remote.*"}, Engine: "broker"}},
+	})
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: &EventBusConfig{}, router: router, logger: &mockLogger{}}
+
+	status, message, _ := m.HealthCheckStatus(context.Background())
+	assert.Equal(t, "unhealthy", status)
+	assert.Equal(t, "event bus not started", message)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	status, message, details := m.HealthCheckStatus(ctx)
+	assert.Equal(t, "healthy", status)
+	assert.Empty(t, message)
+	assert.Equal(t, map[string]any{"local": "started", "broker": "connected"}, details)
+
+	broker.err = errBrokerDown
+	status, message, details = m.HealthCheckStatus(ctx)
+	assert.Equal(t, "unhealthy", status)
+	assert.Equal(t, "engines not connected: broker: broker down", message)
+	assert.Equal(t, "broker down", details["broker"])
+}
@@ -4,16 +4,53 @@ package eventbus
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/IBM/sarama"
 	"github.com/google/uuid"
 )
 
+// ErrInvalidKafkaConfig is returned when a kafka engine's config can't configure the client
+var ErrInvalidKafkaConfig = errors.New("invalid kafka configuration")
+
+// Acknowledgement levels for KafkaConfig.Acks.
+const (
+	// KafkaAcksAll waits for all in-sync replicas to persist an event (default)
+	KafkaAcksAll = "all"
+	// KafkaAcksLeader waits for the partition leader only
+	KafkaAcksLeader = "leader"
+	// KafkaAcksNone doesn't wait for the broker
+	KafkaAcksNone = "none"
+)
+
+// Initial offsets for KafkaConfig.InitialOffset, used by consumer groups without
+// committed offsets.
+const (
+	// KafkaOffsetNewest consumes events published from now on (default)
+	KafkaOffsetNewest = "newest"
+	// KafkaOffsetOldest replays the events retained by the topic
+	KafkaOffsetOldest = "oldest"
+)
+
+// Partition key strategies for KafkaConfig.PartitionKey, applied to events published
+// without a partition key in their context (see WithPartitionKey).
+const (
+	// KafkaPartitionKeyNone publishes without a key, spreading events over partitions (default)
+	KafkaPartitionKeyNone = "none"
+	// KafkaPartitionKeyTopic keys events by their topic, keeping each topic's events in order
+	KafkaPartitionKeyTopic = "topic"
+	// KafkaPartitionKeySubject keys events by their CloudEvents subject
+	KafkaPartitionKeySubject = "subject"
+	// KafkaPartitionKeyExtensionPrefix followed by an extension name keys events by
+	// that CloudEvents extension, e.g. "extension:tenantid"
+	KafkaPartitionKeyExtensionPrefix = "extension:"
+)
+
 // KafkaEventBus implements EventBus using Apache Kafka
 type KafkaEventBus struct {
 	config          *KafkaConfig
@@ -26,15 +63,40 @@ type KafkaEventBus struct {
 	wg              sync.WaitGroup
 	isStarted       bool
 	consumerGroupID string
+	payload         *payloadCodec
+
+	// The consumer loop consumes the subscribed topics in one consumer group session,
+	// which is restarted with the new topics when subscriptions change
+	consumerMutex   sync.Mutex
+	consumerRunning bool
+	sessionCancel   context.CancelFunc
 }
 
 // KafkaConfig holds Kafka-specific configuration
 type KafkaConfig struct {
-	Brokers        []string          `json:"brokers"`
+	Brokers []string `json:"brokers"`
+	// GroupID is the consumer group. Instances sharing it split the partitions of
+	// the subscribed topics, so each event is handled by one of them.
 	GroupID        string            `json:"groupId"`
 	SecurityConfig map[string]string `json:"security"`
 	ProducerConfig map[string]string `json:"producer"`
 	ConsumerConfig map[string]string `json:"consumer"`
+
+	// Acks is the acknowledgement published events wait for: "all" (default),
+	// "leader" or "none"
+	Acks string `json:"acks"`
+	// Retries is how many times a failed publish is retried. Defaults to 3.
+	Retries int `json:"retries"`
+	// RetryBackoff is the wait between publish retries. Defaults to 100ms.
+	RetryBackoff time.Duration `json:"retryBackoff"`
+	// Idempotent makes retried publishes exactly-once per partition. Requires acks "all".
+	Idempotent bool `json:"idempotent"`
+	// InitialOffset is where a consumer group without committed offsets starts:
+	// "newest" (default) or "oldest" to replay retained events
+	InitialOffset string `json:"initialOffset"`
+	// PartitionKey derives the partition key of events published without one in
+	// their context: "none" (default), "topic", "subject" or "extension:<name>"
+	PartitionKey string `json:"partitionKey"`
 }
 
 // kafkaSubscription represents a subscription in the Kafka event bus
@@ -78,6 +140,13 @@ func (s *kafkaSubscription) Cancel() error {
 	return nil
 }
 
+// isCancelled reports whether the subscription has been cancelled
+func (s *kafkaSubscription) isCancelled() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cancelled
+}
+
 // KafkaConsumerGroupHandler implements sarama.ConsumerGroupHandler
 type KafkaConsumerGroupHandler struct {
 	eventBus      *KafkaEventBus
@@ -110,7 +179,7 @@ func (h *KafkaConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSes
 			h.mutex.RLock()
 			subs := make([]*kafkaSubscription, 0)
 			for _, sub := range h.subscriptions {
-				if h.topicMatches(msg.Topic, sub.topic) {
+				if h.topicMatches(msg.Topic, sub.topic) && !sub.isCancelled() {
 					subs = append(subs, sub)
 				}
 			}
@@ -118,7 +187,7 @@ func (h *KafkaConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSes
 
 			// Deserialize once per message, reuse for all matching subscriptions
 			var event Event
-			err := json.Unmarshal(msg.Value, &event)
+			err := h.eventBus.payload.decode(msg.Value, &event)
 			if err != nil {
 				slog.Error("Failed to deserialize Kafka message", "error", err, "topic", msg.Topic)
 				session.MarkMessage(msg, "")
@@ -156,37 +225,146 @@ func (h *KafkaConsumerGroupHandler) topicMatches(messageTopic, subscriptionTopic
 
 // NewKafkaEventBus creates a new Kafka-based event bus
 func NewKafkaEventBus(config map[string]interface{}) (EventBus, error) {
+	kafkaConfig, err := parseKafkaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := newPayloadCodec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	saramaConfig, err := newSaramaConfig(kafkaConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create producer
+	producer, err := sarama.NewSyncProducer(kafkaConfig.Brokers, saramaConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
+	}
+
+	// Create consumer group
+	consumerGroup, err := sarama.NewConsumerGroup(kafkaConfig.Brokers, kafkaConfig.GroupID, saramaConfig)
+	if err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
+	}
+
+	return &KafkaEventBus{
+		config:          kafkaConfig,
+		producer:        producer,
+		consumerGroup:   consumerGroup,
+		subscriptions:   make(map[string]map[string]*kafkaSubscription),
+		consumerGroupID: kafkaConfig.GroupID,
+		payload:         payload,
+	}, nil
+}
+
+// parseKafkaConfig reads a KafkaConfig from an engine config map.
+func parseKafkaConfig(config map[string]interface{}) (*KafkaConfig, error) {
 	kafkaConfig := &KafkaConfig{
 		Brokers:        []string{"localhost:9092"},
 		GroupID:        "eventbus-" + uuid.New().String(),
 		SecurityConfig: make(map[string]string),
 		ProducerConfig: make(map[string]string),
 		ConsumerConfig: make(map[string]string),
+		Acks:           KafkaAcksAll,
+		Retries:        3,
+		RetryBackoff:   100 * time.Millisecond,
+		InitialOffset:  KafkaOffsetNewest,
+		PartitionKey:   KafkaPartitionKeyNone,
 	}
 
-	// Parse configuration
-	if brokers, ok := config["brokers"].([]interface{}); ok {
+	switch brokers := config["brokers"].(type) {
+	case []interface{}:
 		kafkaConfig.Brokers = make([]string, len(brokers))
 		for i, broker := range brokers {
-			kafkaConfig.Brokers[i] = broker.(string)
+			kafkaConfig.Brokers[i] = fmt.Sprint(broker)
 		}
+	case []string:
+		kafkaConfig.Brokers = brokers
 	}
 	if groupID, ok := config["groupId"].(string); ok {
 		kafkaConfig.GroupID = groupID
 	}
 	if security, ok := config["security"].(map[string]interface{}); ok {
 		for k, v := range security {
-			kafkaConfig.SecurityConfig[k] = v.(string)
+			kafkaConfig.SecurityConfig[k] = fmt.Sprint(v)
+		}
+	}
+	if acks, ok := config["acks"]; ok {
+		kafkaConfig.Acks = fmt.Sprint(acks)
+	}
+	if retries, ok := intConfigValue(config["retries"]); ok {
+		kafkaConfig.Retries = retries
+	}
+	if backoff, ok := config["retryBackoff"].(string); ok {
+		d, err := time.ParseDuration(backoff)
+		if err != nil {
+			return nil, fmt.Errorf("%w: retryBackoff %q: %w", ErrInvalidKafkaConfig, backoff, err)
 		}
+		kafkaConfig.RetryBackoff = d
 	}
+	if idempotent, ok := config["idempotent"].(bool); ok {
+		kafkaConfig.Idempotent = idempotent
+	}
+	if offset, ok := config["initialOffset"].(string); ok {
+		kafkaConfig.InitialOffset = offset
+	}
+	if partitionKey, ok := config["partitionKey"].(string); ok {
+		kafkaConfig.PartitionKey = partitionKey
+	}
+	return kafkaConfig, nil
+}
 
-	// Create Sarama configuration
+// newSaramaConfig returns the client configuration for kafkaConfig.
+func newSaramaConfig(kafkaConfig *KafkaConfig) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.Version = sarama.V2_6_0_0
 	saramaConfig.Producer.Return.Successes = true
-	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
 	saramaConfig.Consumer.Group.Rebalance.Strategy = sarama.NewBalanceStrategyRoundRobin()
-	saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+
+	switch kafkaConfig.Acks {
+	case KafkaAcksAll, "-1", "":
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	case KafkaAcksLeader, "1":
+		saramaConfig.Producer.RequiredAcks = sarama.WaitForLocal
+	case KafkaAcksNone, "0":
+		saramaConfig.Producer.RequiredAcks = sarama.NoResponse
+	default:
+		return nil, fmt.Errorf("%w: unknown acks %q", ErrInvalidKafkaConfig, kafkaConfig.Acks)
+	}
+	if kafkaConfig.Retries < 0 || kafkaConfig.RetryBackoff < 0 {
+		return nil, fmt.Errorf("%w: retries and retryBackoff must not be negative", ErrInvalidKafkaConfig)
+	}
+	saramaConfig.Producer.Retry.Max = kafkaConfig.Retries
+	saramaConfig.Producer.Retry.Backoff = kafkaConfig.RetryBackoff
+	if kafkaConfig.Idempotent {
+		if saramaConfig.Producer.RequiredAcks != sarama.WaitForAll {
+			return nil, fmt.Errorf("%w: idempotent publishing requires acks %q", ErrInvalidKafkaConfig, KafkaAcksAll)
+		}
+		saramaConfig.Producer.Idempotent = true
+		saramaConfig.Net.MaxOpenRequests = 1
+	}
+
+	switch kafkaConfig.InitialOffset {
+	case KafkaOffsetNewest, "":
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetNewest
+	case KafkaOffsetOldest:
+		saramaConfig.Consumer.Offsets.Initial = sarama.OffsetOldest
+	default:
+		return nil, fmt.Errorf("%w: unknown initialOffset %q", ErrInvalidKafkaConfig, kafkaConfig.InitialOffset)
+	}
+
+	switch key := kafkaConfig.PartitionKey; {
+	case key == "", key == KafkaPartitionKeyNone, key == KafkaPartitionKeyTopic, key == KafkaPartitionKeySubject:
+	case strings.HasPrefix(key, KafkaPartitionKeyExtensionPrefix) && len(key) > len(KafkaPartitionKeyExtensionPrefix):
+	default:
+		return nil, fmt.Errorf("%w: unknown partitionKey %q", ErrInvalidKafkaConfig, key)
+	}
 
 	// Apply security configuration
 	for key, value := range kafkaConfig.SecurityConfig {
@@ -207,26 +385,7 @@ func NewKafkaEventBus(config map[string]interface{}) (EventBus, error) {
 		}
 	}
 
-	// Create producer
-	producer, err := sarama.NewSyncProducer(kafkaConfig.Brokers, saramaConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Kafka producer: %w", err)
-	}
-
-	// Create consumer group
-	consumerGroup, err := sarama.NewConsumerGroup(kafkaConfig.Brokers, kafkaConfig.GroupID, saramaConfig)
-	if err != nil {
-		producer.Close()
-		return nil, fmt.Errorf("failed to create Kafka consumer group: %w", err)
-	}
-
-	return &KafkaEventBus{
-		config:          kafkaConfig,
-		producer:        producer,
-		consumerGroup:   consumerGroup,
-		subscriptions:   make(map[string]map[string]*kafkaSubscription),
-		consumerGroupID: kafkaConfig.GroupID,
-	}, nil
+	return saramaConfig, nil
 }
 
 // Start initializes the Kafka event bus
@@ -293,19 +452,19 @@ func (k *KafkaEventBus) Publish(ctx context.Context, event Event) error {
 		return ErrEventBusNotStarted
 	}
 
-	eventData, err := json.Marshal(event)
+	eventData, err := k.payload.encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
+		return err
 	}
 
 	// Create Kafka message
 	message := &sarama.ProducerMessage{
 		Topic: event.Type(),
-		Value: sarama.StringEncoder(eventData),
+		Value: sarama.ByteEncoder(eventData),
 	}
 
 	// Set partition key if provided (otherwise uses client's default partitioner)
-	if key, ok := PartitionKeyFromContext(ctx); ok {
+	if key, ok := k.partitionKey(ctx, event); ok {
 		message.Key = sarama.StringEncoder(key)
 	}
 
@@ -318,6 +477,33 @@ func (k *KafkaEventBus) Publish(ctx context.Context, event Event) error {
 	return nil
 }
 
+// partitionKey returns the key to publish event with: the context's partition key,
+// else the one derived by the configured PartitionKey strategy.
+func (k *KafkaEventBus) partitionKey(ctx context.Context, event Event) (string, bool) {
+	if key, ok := PartitionKeyFromContext(ctx); ok {
+		return key, true
+	}
+	if k.config == nil {
+		return "", false
+	}
+	switch strategy := k.config.PartitionKey; {
+	case strategy == KafkaPartitionKeyTopic:
+		return event.Type(), true
+	case strategy == KafkaPartitionKeySubject:
+		return event.Subject(), event.Subject() != ""
+	case strings.HasPrefix(strategy, KafkaPartitionKeyExtensionPrefix):
+		if value, ok := event.Extensions()[strings.TrimPrefix(strategy, KafkaPartitionKeyExtensionPrefix)]; ok {
+			return fmt.Sprint(value), true
+		}
+	}
+	return "", false
+}
+
+// PayloadStats returns compression and size-limit statistics for published events
+func (k *KafkaEventBus) PayloadStats() PayloadStats {
+	return k.payload.stats()
+}
+
 // Subscribe registers a handler for a topic
 func (k *KafkaEventBus) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
 	return k.subscribe(ctx, topic, handler, false)
@@ -357,45 +543,73 @@ func (k *KafkaEventBus) subscribe(ctx context.Context, topic string, handler Eve
 	k.subscriptions[topic][sub.id] = sub
 	k.topicMutex.Unlock()
 
-	// Start consumer group for this topic if not already started
-	go k.startConsumerGroup()
+	// Start consuming the topic, or rejoin the group with it
+	k.startConsumerGroup()
 
 	return sub, nil
 }
 
-// startConsumerGroup starts the Kafka consumer group
+// kafkaConsumeRetryDelay is the wait before rejoining the consumer group after an error
+const kafkaConsumeRetryDelay = time.Second
+
+// startConsumerGroup starts the consumer loop, or ends its current session so that
+// it rejoins the consumer group with the current subscriptions.
 func (k *KafkaEventBus) startConsumerGroup() {
-	handler := &KafkaConsumerGroupHandler{
-		eventBus:      k,
-		subscriptions: make(map[string]*kafkaSubscription),
-	}
+	k.consumerMutex.Lock()
+	defer k.consumerMutex.Unlock()
 
-	// Collect all subscriptions
-	k.topicMutex.RLock()
-	topics := make([]string, 0)
-	for topic, subs := range k.subscriptions {
-		topics = append(topics, topic)
-		for _, sub := range subs {
-			handler.subscriptions[sub.id] = sub
+	if k.consumerRunning {
+		if k.sessionCancel != nil {
+			k.sessionCancel()
 		}
-	}
-	k.topicMutex.RUnlock()
-
-	if len(topics) == 0 {
 		return
 	}
+	k.consumerRunning = true
+	k.wg.Go(k.consume)
+}
 
-	// Start consuming (Go 1.25 WaitGroup.Go)
-	k.wg.Go(func() {
-		for {
-			if err := k.consumerGroup.Consume(k.ctx, topics, handler); err != nil {
-				slog.Error("Kafka consumer group error", "error", err)
+// consume runs consumer group sessions for the subscribed topics until the bus stops
+// or no subscriptions remain. Offsets are committed per consumer group, so a restarted
+// instance resumes where the group left off.
+func (k *KafkaEventBus) consume() {
+	for {
+		// Sessions start under consumerMutex so that a subscription made meanwhile
+		// either joins this session or cancels it
+		k.consumerMutex.Lock()
+		handler := &KafkaConsumerGroupHandler{
+			eventBus:      k,
+			subscriptions: make(map[string]*kafkaSubscription),
+		}
+		k.topicMutex.RLock()
+		topics := make([]string, 0, len(k.subscriptions))
+		for topic, subs := range k.subscriptions {
+			topics = append(topics, topic)
+			for _, sub := range subs {
+				handler.subscriptions[sub.id] = sub
 			}
-			if k.ctx.Err() != nil {
-				return
+		}
+		k.topicMutex.RUnlock()
+		if len(topics) == 0 || k.ctx.Err() != nil {
+			k.consumerRunning = false
+			k.sessionCancel = nil
+			k.consumerMutex.Unlock()
+			return
+		}
+		sessionCtx, cancel := context.WithCancel(k.ctx)
+		k.sessionCancel = cancel
+		k.consumerMutex.Unlock()
+
+		err := k.consumerGroup.Consume(sessionCtx, topics, handler)
+		sessionEnded := sessionCtx.Err() != nil
+		cancel()
+		if err != nil && !sessionEnded {
+			slog.Error("Kafka consumer group error", "error", err)
+			select {
+			case <-k.ctx.Done():
+			case <-time.After(kafkaConsumeRetryDelay):
 			}
 		}
-	})
+	}
 }
 
 // Unsubscribe removes a subscription
@@ -417,14 +631,20 @@ func (k *KafkaEventBus) Unsubscribe(ctx context.Context, subscription Subscripti
 
 	// Remove from subscriptions map
 	k.topicMutex.Lock()
-	defer k.topicMutex.Unlock()
-
+	topicRemoved := false
 	if subs, ok := k.subscriptions[sub.topic]; ok {
 		delete(subs, sub.id)
 		if len(subs) == 0 {
 			delete(k.subscriptions, sub.topic)
+			topicRemoved = true
 		}
 	}
+	k.topicMutex.Unlock()
+
+	// Leave the topic's partitions to other members of the group
+	if topicRemoved {
+		k.startConsumerGroup()
+	}
 
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
 
@@ -510,3 +511,170 @@ func TestKafkaConsumeClaimMultipleMessages(t *testing.T) {
 
 	assert.Len(t, session.markedMsgs, 2)
 }
+
+func TestKafkaConfigParsing(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := parseKafkaConfig(map[string]interface{}{})
+		require.NoError(t, err)
+		saramaConfig, err := newSaramaConfig(cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"localhost:9092"}, cfg.Brokers)
+		assert.Equal(t, sarama.WaitForAll, saramaConfig.Producer.RequiredAcks)
+		assert.Equal(t, 3, saramaConfig.Producer.Retry.Max)
+		assert.Equal(t, 100*time.Millisecond, saramaConfig.Producer.Retry.Backoff)
+		assert.Equal(t, sarama.OffsetNewest, saramaConfig.Consumer.Offsets.Initial)
+		assert.False(t, saramaConfig.Producer.Idempotent)
+	})
+
+	t.Run("engine config", func(t *testing.T) {
+		cfg, err := parseKafkaConfig(map[string]interface{}{
+			"brokers":       []interface{}{"kafka-1:9092", "kafka-2:9092"},
+			"groupId":       "billing",
+			"acks":          "leader",
+			"retries":       10,
+			"retryBackoff":  "250ms",
+			"initialOffset": "oldest",
+			"partitionKey":  "extension:tenantid",
+		})
+		require.NoError(t, err)
+		saramaConfig, err := newSaramaConfig(cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Brokers)
+		assert.Equal(t, "billing", cfg.GroupID)
+		assert.Equal(t, sarama.WaitForLocal, saramaConfig.Producer.RequiredAcks)
+		assert.Equal(t, 10, saramaConfig.Producer.Retry.Max)
+		assert.Equal(t, 250*time.Millisecond, saramaConfig.Producer.Retry.Backoff)
+		assert.Equal(t, sarama.OffsetOldest, saramaConfig.Consumer.Offsets.Initial)
+	})
+
+	t.Run("idempotent publishing", func(t *testing.T) {
+		saramaConfig, err := newSaramaConfig(&KafkaConfig{Acks: KafkaAcksAll, Retries: 5, Idempotent: true})
+		require.NoError(t, err)
+		assert.True(t, saramaConfig.Producer.Idempotent)
+		assert.Equal(t, 1, saramaConfig.Net.MaxOpenRequests)
+		require.NoError(t, saramaConfig.Validate())
+	})
+
+	t.Run("rejects invalid settings", func(t *testing.T) {
+		_, err := parseKafkaConfig(map[string]interface{}{"retryBackoff": "soon"})
+		assert.ErrorIs(t, err, ErrInvalidKafkaConfig)
+
+		for name, cfg := range map[string]*KafkaConfig{
+			"acks":               {Acks: "most"},
+			"negative retries":   {Retries: -1},
+			"idempotent leader":  {Acks: KafkaAcksLeader, Idempotent: true},
+			"initial offset":     {InitialOffset: "middle"},
+			"partition key":      {PartitionKey: "random"},
+			"extension key name": {PartitionKey: KafkaPartitionKeyExtensionPrefix},
+		} {
+			_, err := newSaramaConfig(cfg)
+			assert.ErrorIs(t, err, ErrInvalidKafkaConfig, name)
+		}
+
+		_, err = NewKafkaEventBus(map[string]interface{}{"acks": "most"})
+		assert.ErrorIs(t, err, ErrInvalidKafkaConfig)
+	})
+}
+
+func TestKafkaPartitionKeyStrategies(t *testing.T) {
+	event := newTestCloudEvent("orders.created", "data")
+	event.SetSubject("order-7")
+	event.SetExtension("tenantid", "acme")
+
+	for strategy, want := range map[string]interface{}{
+		KafkaPartitionKeyNone:                         nil,
+		KafkaPartitionKeyTopic:                        "orders.created",
+		KafkaPartitionKeySubject:                      "order-7",
+		KafkaPartitionKeyExtensionPrefix + "tenantid": "acme",
+		KafkaPartitionKeyExtensionPrefix + "region":   nil,
+	} {
+		t.Run(strategy, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks.NewMockSyncProducer(ctrl)
+			bus := newTestKafkaEventBus(m)
+			bus.config.PartitionKey = strategy
+			defer bus.cancel()
+
+			m.EXPECT().
+				SendMessage(gomock.Any()).
+				DoAndReturn(func(msg *sarama.ProducerMessage) (int32, int64, error) {
+					if want == nil {
+						assert.Nil(t, msg.Key)
+						return 0, 0, nil
+					}
+					keyBytes, err := msg.Key.Encode()
+					require.NoError(t, err)
+					assert.Equal(t, want, string(keyBytes))
+					return 0, 0, nil
+				})
+			require.NoError(t, bus.Publish(context.Background(), event))
+		})
+	}
+
+	t.Run("context key takes precedence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		m := mocks.NewMockSyncProducer(ctrl)
+		bus := newTestKafkaEventBus(m)
+		bus.config.PartitionKey = KafkaPartitionKeyTopic
+		defer bus.cancel()
+
+		m.EXPECT().
+			SendMessage(gomock.Any()).
+			DoAndReturn(func(msg *sarama.ProducerMessage) (int32, int64, error) {
+				keyBytes, err := msg.Key.Encode()
+				require.NoError(t, err)
+				assert.Equal(t, "user-42", string(keyBytes))
+				return 0, 0, nil
+			})
+		require.NoError(t, bus.Publish(WithPartitionKey(context.Background(), "user-42"), event))
+	})
+}
+
+func TestKafkaConsumerRejoinsOnSubscriptionChange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	bus := newTestKafkaEventBus(mocks.NewMockSyncProducer(ctrl))
+	consumerGroup := mocks.NewMockConsumerGroup(ctrl)
+	bus.consumerGroup = consumerGroup
+	defer bus.cancel()
+
+	// Each session blocks until it is ended and reports the topics it consumed
+	sessions := make(chan []string, 10)
+	consumerGroup.EXPECT().
+		Consume(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, topics []string, _ sarama.ConsumerGroupHandler) error {
+			sorted := append([]string(nil), topics...)
+			sort.Strings(sorted)
+			sessions <- sorted
+			<-ctx.Done()
+			return nil
+		}).
+		AnyTimes()
+
+	nextSession := func() []string {
+		select {
+		case topics := <-sessions:
+			return topics
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for a consumer group session")
+			return nil
+		}
+	}
+	noop := func(ctx context.Context, event Event) error { return nil }
+
+	orders, err := bus.Subscribe(context.Background(), "orders", noop)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders"}, nextSession())
+
+	_, err = bus.Subscribe(context.Background(), "users", noop)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders", "users"}, nextSession(), "a new topic restarts the session")
+
+	require.NoError(t, bus.Unsubscribe(context.Background(), orders))
+	assert.Equal(t, []string{"users"}, nextSession(), "a topic without subscribers is left")
+
+	bus.cancel()
+	bus.wg.Wait()
+	assert.False(t, bus.consumerRunning)
+}
@@ -4,7 +4,6 @@ package eventbus
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -47,6 +46,7 @@ type KinesisEventBus struct {
 	shardScanOnce sync.Once
 	activeShards  map[string]struct{}
 	shardMutex    sync.Mutex
+	payload       *payloadCodec
 }
 
 // DefaultKinesisPollInterval is the standard polling interval for Kinesis GetRecords.
@@ -151,6 +151,11 @@ func NewKinesisEventBus(config map[string]interface{}) (EventBus, error) {
 		kinesisConfig.PollInterval = DefaultKinesisPollInterval
 	}
 
+	payload, err := newPayloadCodec(config)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create AWS config
 	cfg, err := awsconfig.LoadDefaultConfig(context.TODO(),
 		awsconfig.WithRegion(kinesisConfig.Region))
@@ -166,6 +171,7 @@ func NewKinesisEventBus(config map[string]interface{}) (EventBus, error) {
 		client:        client,
 		subscriptions: make(map[string]map[string]*kinesisSubscription),
 		activeShards:  make(map[string]struct{}),
+		payload:       payload,
 	}, nil
 }
 
@@ -266,9 +272,9 @@ func (k *KinesisEventBus) Publish(ctx context.Context, event Event) error {
 		return ErrEventBusNotStarted
 	}
 
-	eventData, err := json.Marshal(event)
+	eventData, err := k.payload.encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
+		return err
 	}
 
 	// Determine partition key: use context hint if set and non-empty, otherwise default to topic
@@ -290,6 +296,11 @@ func (k *KinesisEventBus) Publish(ctx context.Context, event Event) error {
 	return nil
 }
 
+// PayloadStats returns compression and size-limit statistics for published events
+func (k *KinesisEventBus) PayloadStats() PayloadStats {
+	return k.payload.stats()
+}
+
 // Subscribe registers a handler for a topic
 func (k *KinesisEventBus) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
 	return k.subscribe(ctx, topic, handler, false)
@@ -469,7 +480,7 @@ func (k *KinesisEventBus) readShard(shardID string) {
 			// Process records
 			for _, record := range resp.Records {
 				var event Event
-				err := json.Unmarshal(record.Data, &event)
+				err := k.payload.decode(record.Data, &event)
 				if err != nil {
 					slog.Error("Failed to deserialize Kinesis record", "error", err)
 					continue
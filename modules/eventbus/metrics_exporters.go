@@ -42,9 +42,11 @@ var (
 // ----- Prometheus Collector -----
 
 // PrometheusCollector implements prometheus.Collector for EventBus delivery stats.
-// It exposes two metrics (cumulative counters):
+// It exposes four metrics (cumulative counters):
 //   modular_eventbus_delivered_total{engine="<name>"}
 //   modular_eventbus_dropped_total{engine="<name>"}
+//   modular_eventbus_payload_compressed_total{engine="<name>"}
+//   modular_eventbus_payload_rejected_total{engine="<name>"}
 // plus aggregate pseudo-engine label engine="_all" for totals.
 //
 // Metric naming base can be customized via namespace param in constructor.
@@ -53,8 +55,10 @@ var (
 type PrometheusCollector struct {
 	eventBus *EventBusModule
 	// metric descriptors
-	deliveredDesc *prometheus.Desc
-	droppedDesc   *prometheus.Desc
+	deliveredDesc  *prometheus.Desc
+	droppedDesc    *prometheus.Desc
+	compressedDesc *prometheus.Desc
+	rejectedDesc   *prometheus.Desc
 }
 
 // NewPrometheusCollector creates a new collector for the given event bus.
@@ -75,6 +79,16 @@ func NewPrometheusCollector(eventBus *EventBusModule, namespace string) *Prometh
 			"Total dropped events (cumulative)",
 			[]string{"engine"}, nil,
 		),
+		compressedDesc: prometheus.NewDesc(
+			fmt.Sprintf("%s_payload_compressed_total", namespace),
+			"Total published events whose payload was compressed (cumulative)",
+			[]string{"engine"}, nil,
+		),
+		rejectedDesc: prometheus.NewDesc(
+			fmt.Sprintf("%s_payload_rejected_total", namespace),
+			"Total published events rejected for exceeding the maximum payload size (cumulative)",
+			[]string{"engine"}, nil,
+		),
 	}
 }
 
@@ -82,6 +96,8 @@ func NewPrometheusCollector(eventBus *EventBusModule, namespace string) *Prometh
 func (c *PrometheusCollector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.deliveredDesc
 	ch <- c.droppedDesc
+	ch <- c.compressedDesc
+	ch <- c.rejectedDesc
 }
 
 // Collect gathers current stats and emits ConstMetrics.
@@ -97,6 +113,16 @@ func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
 	// Aggregate pseudo engine
 	ch <- prometheus.MustNewConstMetric(c.deliveredDesc, prometheus.CounterValue, float64(totalDelivered), "_all")
 	ch <- prometheus.MustNewConstMetric(c.droppedDesc, prometheus.CounterValue, float64(totalDropped), "_all")
+
+	var totalCompressed, totalRejected uint64
+	for engine, s := range c.eventBus.PayloadStats() {
+		ch <- prometheus.MustNewConstMetric(c.compressedDesc, prometheus.CounterValue, float64(s.Compressed), engine)
+		ch <- prometheus.MustNewConstMetric(c.rejectedDesc, prometheus.CounterValue, float64(s.Rejected), engine)
+		totalCompressed += s.Compressed
+		totalRejected += s.Rejected
+	}
+	ch <- prometheus.MustNewConstMetric(c.compressedDesc, prometheus.CounterValue, float64(totalCompressed), "_all")
+	ch <- prometheus.MustNewConstMetric(c.rejectedDesc, prometheus.CounterValue, float64(totalRejected), "_all")
 }
 
 // ----- Datadog / StatsD Exporter -----
@@ -108,6 +134,8 @@ func (c *PrometheusCollector) Collect(ch chan<- prometheus.Metric) {
 // It sends metrics:
 //   eventbus.delivered_total (tags: engine:<name>)
 //   eventbus.dropped_total (tags: engine:<name>)
+//   eventbus.payload_compressed_total (tags: engine:<name>)
+//   eventbus.payload_rejected_total (tags: engine:<name>)
 // plus engine:_all aggregate.
 
 type DatadogStatsdExporter struct {
@@ -171,6 +199,17 @@ func (e *DatadogStatsdExporter) flush() {
 	aggTags := append(e.baseTags, "engine:_all")
 	_ = e.client.Gauge("delivered_total", float64(totalDelivered), aggTags, 1)
 	_ = e.client.Gauge("dropped_total", float64(totalDropped), aggTags, 1)
+
+	var totalCompressed, totalRejected uint64
+	for engine, s := range e.eventBus.PayloadStats() {
+		engineTags := append(e.baseTags, "engine:"+engine)
+		_ = e.client.Gauge("payload_compressed_total", float64(s.Compressed), engineTags, 1)
+		_ = e.client.Gauge("payload_rejected_total", float64(s.Rejected), engineTags, 1)
+		totalCompressed += s.Compressed
+		totalRejected += s.Rejected
+	}
+	_ = e.client.Gauge("payload_compressed_total", float64(totalCompressed), aggTags, 1)
+	_ = e.client.Gauge("payload_rejected_total", float64(totalRejected), aggTags, 1)
 	// Removed always-on goroutine gauge per review feedback; runtime metrics belong in a broader runtime exporter.
 }
 
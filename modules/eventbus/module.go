@@ -14,6 +14,7 @@
 //   - CloudEvents 1.0 compliant event model with extensions
 //   - Subscription management with unique identifiers
 //   - Event TTL and retention policies
+//   - OpenTelemetry trace context propagation through event metadata
 //
 // # Configuration
 //
@@ -117,6 +118,8 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	cevent "github.com/cloudevents/sdk-go/v2/event"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ModuleName is the unique identifier for the eventbus module.
@@ -137,6 +140,7 @@ const ServiceName = "eventbus.provider"
 //   - modular.Startable: Startup logic
 //   - modular.Stoppable: Shutdown logic
 //   - modular.ObservableModule: Event observation and emission
+//   - modular.TenantAwareModule: Dedicated engines per tenant
 //   - EventBus: Event publishing and subscription interface
 //
 // Event processing is thread-safe and supports concurrent publishers and subscribers.
@@ -148,6 +152,42 @@ type EventBusModule struct {
 	mutex     sync.RWMutex
 	isStarted bool
 	subject   modular.Subject // For event observation (guarded by mutex)
+
+	// Cross-engine bridges: transformation hooks by bridge name and the bridges'
+	// subscriptions on their source engines (guarded by mutex)
+	bridgeTransforms    map[string]BridgeTransform
+	bridgeSubscriptions []bridgeSubscription
+
+	// Events skipped or pruned after their TTL passed, per topic
+	expiredMutex  sync.Mutex
+	expiredCounts map[string]uint64
+
+	// Handlers cancelled after exceeding their topic's handler timeout, per topic
+	timeoutMutex  sync.Mutex
+	timeoutCounts map[string]uint64
+
+	// Events published to a dead-letter topic after their handler kept failing, per topic
+	deadLetterMutex  sync.Mutex
+	deadLetterCounts map[string]uint64
+
+	// Upcasters by topic pattern and version they convert from, and the upcasts
+	// applied per topic
+	upcastMutex  sync.RWMutex
+	upcasters    map[string]map[int]Upcaster
+	upcastCounts map[string]UpcastStats
+
+	// Tracer provider and propagator overrides for trace context propagation
+	tracingMutex   sync.RWMutex
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+
+	// Tenants registered with the tenant service, some with dedicated engines, which
+	// only run while the module is started (tenantsActive)
+	tenantService modular.TenantService
+	tenantMutex   sync.RWMutex
+	tenants       map[modular.TenantID]*tenantEngines
+	tenantsActive bool
+	tenantStops   sync.WaitGroup
 }
 
 // DeliveryStats represents basic delivery outcomes for an engine or aggregate.
@@ -249,6 +289,12 @@ func (m *EventBusModule) Init(app modular.Application) error {
 	// Set module reference for memory engines to enable event emission
 	m.router.SetModuleReference(m)
 
+	// Tenants get dedicated engines from their eventbus tenant config section
+	var tenantService modular.TenantService
+	if err := app.GetService("tenantService", &tenantService); err == nil && tenantService != nil {
+		m.tenantService = tenantService
+	}
+
 	if m.config.IsMultiEngine() {
 		m.logger.Info("Initialized multi-engine eventbus",
 			"engines", len(m.config.Engines),
@@ -300,6 +346,17 @@ func (m *EventBusModule) Start(ctx context.Context) error {
 		return fmt.Errorf("starting engine router: %w", err)
 	}
 
+	// Relay events between engines once all of them are running
+	if err := m.startBridges(ctx); err != nil {
+		m.stopBridges(ctx)
+		if stopErr := m.router.Stop(ctx); stopErr != nil {
+			m.logger.Warn("Failed to stop engines after bridge startup failure", "error", stopErr)
+		}
+		return fmt.Errorf("starting bridges: %w", err)
+	}
+
+	m.startTenants(ctx)
+
 	m.isStarted = true
 	if m.config.IsMultiEngine() {
 		m.logger.Info("Event bus started with multiple engines",
@@ -358,6 +415,10 @@ func (m *EventBusModule) Stop(ctx context.Context) error {
 		return nil
 	}
 
+	// Stop relaying before the engines go away
+	m.stopBridges(ctx)
+	m.stopTenants(ctx)
+
 	// Stop the engine router (which stops all engines)
 	err := m.router.Stop(ctx)
 	if err != nil {
@@ -392,6 +453,13 @@ func (m *EventBusModule) Dependencies() []string {
 	return nil
 }
 
+// ShutdownPhaseOrder returns the consumers phase of the application's shutdown,
+// modular.ShutdownPhaseConsumers, so the event bus delivers the events published
+// while requests drain before it stops.
+func (m *EventBusModule) ShutdownPhaseOrder() int {
+	return 400
+}
+
 // ProvidesServices declares services provided by this module.
 // The eventbus module provides an event bus service that can be injected
 // into other modules for event-driven communication.
@@ -442,7 +510,8 @@ func (m *EventBusModule) PublishCloudEvent(ctx context.Context, event Event) err
 // The event will be delivered to all active subscribers of the topic.
 // Type patterns and wildcards may be supported depending on the engine.
 // With multiple engines, the event is routed to the appropriate engine
-// based on the configured routing rules.
+// based on the configured routing rules. With the tenant context of a tenant
+// with dedicated engines, the event is routed among the tenant's engines instead.
 //
 // Example:
 //
@@ -466,10 +535,35 @@ func (m *EventBusModule) Publish(ctx context.Context, topic string, payload inte
 // fields stay in one place.
 func (m *EventBusModule) publishEvent(ctx context.Context, event Event) error {
 	topic := event.Type()
+	m.applyTTL(ctx, &event)
+	m.applyVersion(&event)
+	if eventExpired(event, time.Now()) {
+		m.recordExpired(ctx, event, "publish")
+		return nil
+	}
+	router, _, err := m.routerFor(ctx)
+	if err != nil {
+		go m.emitEvent(ctx, EventTypeMessageFailed, map[string]interface{}{
+			"topic": topic,
+			"error": err.Error(),
+		})
+		return fmt.Errorf("publishing event to topic %s: %w", topic, err)
+	}
+	ctx, finishSpan := m.startPublishSpan(ctx, &event)
 	startTime := time.Now()
-	err := m.router.Publish(ctx, event)
+	err = router.Publish(ctx, event)
 	duration := time.Since(startTime)
+	finishSpan(err)
 	if err != nil {
+		var tooLarge *PayloadTooLargeError
+		if errors.As(err, &tooLarge) {
+			go m.emitEvent(ctx, EventTypeMessageRejected, map[string]interface{}{
+				"topic":         topic,
+				"reason":        "payload_too_large",
+				"payload_bytes": tooLarge.Size,
+				"limit_bytes":   tooLarge.Limit,
+			})
+		}
 		go m.emitEvent(ctx, EventTypeMessageFailed, map[string]interface{}{
 			"topic":       topic,
 			"error":       err.Error(),
@@ -490,7 +584,9 @@ func (m *EventBusModule) publishEvent(ctx context.Context, event Event) error {
 // to the specified topic. The handler blocks the event delivery until it completes.
 //
 // With multiple engines, the subscription is created on the engine that
-// handles the specified topic according to the routing configuration.
+// handles the specified topic according to the routing configuration. With the
+// tenant context of a tenant with dedicated engines, it is created on the
+// tenant's engines and only receives the tenant's events.
 //
 // Use synchronous subscriptions for:
 //   - Lightweight event processing
@@ -507,10 +603,15 @@ func (m *EventBusModule) publishEvent(ctx context.Context, event Event) error {
 //	    return updateLastLoginTime(user.ID)
 //	})
 func (m *EventBusModule) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
-	sub, err := m.router.Subscribe(ctx, topic, handler)
+	router, tenant, err := m.routerFor(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("subscribing to topic %s: %w", topic, err)
+	}
+	sub, err := router.Subscribe(ctx, topic, m.expiringHandler(m.upcastingHandler(m.tracingHandler(m.deadLetterHandler(router, m.timeoutHandler(handler))))))
 	if err != nil {
 		return nil, fmt.Errorf("subscribing to topic %s: %w", topic, err)
 	}
+	tenant.track(sub)
 
 	// Emit subscription created event
 	go m.emitEvent(ctx, EventTypeSubscriptionCreated, map[string]interface{}{
@@ -527,7 +628,9 @@ func (m *EventBusModule) Subscribe(ctx context.Context, topic string, handler Ev
 // allowing the event publisher to continue without waiting for processing.
 //
 // With multiple engines, the subscription is created on the engine that
-// handles the specified topic according to the routing configuration.
+// handles the specified topic according to the routing configuration. With the
+// tenant context of a tenant with dedicated engines, it is created on the
+// tenant's engines and only receives the tenant's events.
 //
 // Use asynchronous subscriptions for:
 //   - Heavy processing operations
@@ -545,10 +648,15 @@ func (m *EventBusModule) Subscribe(ctx context.Context, topic string, handler Ev
 //	    return generateThumbnails(imageData)
 //	})
 func (m *EventBusModule) SubscribeAsync(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
-	sub, err := m.router.SubscribeAsync(ctx, topic, handler)
+	router, tenant, err := m.routerFor(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("subscribing async to topic %s: %w", topic, err)
 	}
+	sub, err := router.SubscribeAsync(ctx, topic, m.expiringHandler(m.upcastingHandler(m.tracingHandler(m.deadLetterHandler(router, m.timeoutHandler(handler))))))
+	if err != nil {
+		return nil, fmt.Errorf("subscribing async to topic %s: %w", topic, err)
+	}
+	tenant.track(sub)
 
 	// Emit subscription created event
 	go m.emitEvent(ctx, EventTypeSubscriptionCreated, map[string]interface{}{
@@ -575,7 +683,7 @@ func (m *EventBusModule) Unsubscribe(ctx context.Context, subscription Subscript
 	topic := subscription.Topic()
 	subscriptionID := subscription.ID()
 
-	err := m.router.Unsubscribe(ctx, subscription)
+	err := m.unsubscribe(ctx, subscription)
 	if err != nil {
 		return fmt.Errorf("unsubscribing: %w", err)
 	}
@@ -652,6 +760,15 @@ func (m *EventBusModule) PerEngineStats() map[string]DeliveryStats {
 	return m.router.CollectPerEngineStats()
 }
 
+// PayloadStats returns payload compression and size-limit statistics per broker
+// engine. Safe to call before Start; returns an empty map if router not yet built.
+func (m *EventBusModule) PayloadStats() map[string]PayloadStats {
+	if m.router == nil {
+		return map[string]PayloadStats{}
+	}
+	return m.router.CollectPayloadStats()
+}
+
 // Static errors for err113 compliance
 var (
 	_ = ErrNoSubjectForEventEmission // Reference the local error
@@ -719,6 +836,16 @@ func (m *EventBusModule) GetRegisteredEventTypes() []string {
 		EventTypeMessagePublished,
 		EventTypeMessageReceived,
 		EventTypeMessageFailed,
+		EventTypeMessageRejected,
+		EventTypeMessageExpired,
+		EventTypeHandlerTimeout,
+		EventTypeUpcastFailed,
+		EventTypeMessageDeadLettered,
+		EventTypeMessageBridged,
+		EventTypeBridgeFailed,
+		EventTypeTenantEnginesStarted,
+		EventTypeTenantEnginesStopped,
+		EventTypeTenantEnginesFailed,
 		EventTypeTopicCreated,
 		EventTypeTopicDeleted,
 		EventTypeSubscriptionCreated,
@@ -2,7 +2,6 @@ package eventbus
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -23,6 +22,7 @@ type NatsEventBus struct {
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
 	isStarted     bool
+	payload       *payloadCodec
 }
 
 // NatsConfig holds NATS-specific configuration
@@ -133,6 +133,11 @@ func NewNatsEventBus(config map[string]interface{}) (EventBus, error) {
 		natsConfig.SubscribeTimeout = subscribeTimeout
 	}
 
+	payload, err := newPayloadCodec(config)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create NATS connection options
 	opts := []nats.Option{
 		nats.Name(natsConfig.ConnectionName),
@@ -163,6 +168,7 @@ func NewNatsEventBus(config map[string]interface{}) (EventBus, error) {
 		config:        natsConfig,
 		conn:          conn,
 		subscriptions: make(map[string]map[string]*natsSubscription),
+		payload:       payload,
 	}, nil
 }
 
@@ -230,9 +236,9 @@ func (n *NatsEventBus) Publish(ctx context.Context, event Event) error {
 		return ErrEventBusNotStarted
 	}
 
-	eventData, err := json.Marshal(event)
+	eventData, err := n.payload.encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
+		return err
 	}
 
 	// Convert topic to NATS subject (replace wildcards if needed)
@@ -247,6 +253,11 @@ func (n *NatsEventBus) Publish(ctx context.Context, event Event) error {
 	return nil
 }
 
+// PayloadStats returns compression and size-limit statistics for published events
+func (n *NatsEventBus) PayloadStats() PayloadStats {
+	return n.payload.stats()
+}
+
 // Subscribe registers a handler for a topic
 func (n *NatsEventBus) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
 	return n.subscribe(ctx, topic, handler, false)
@@ -293,7 +304,7 @@ func (n *NatsEventBus) subscribe(ctx context.Context, topic string, handler Even
 
 		// Deserialize event
 		var event Event
-		err := json.Unmarshal(msg.Data, &event)
+		err := n.payload.decode(msg.Data, &event)
 		if err != nil {
 			slog.Error("Failed to deserialize NATS message", "error", err, "subject", msg.Subject)
 			return
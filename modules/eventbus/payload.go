@@ -0,0 +1,332 @@
+package eventbus
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Payload compression algorithms supported by the broker engines.
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+	CompressionZstd = "zstd"
+)
+
+// DefaultCompressionThreshold is the serialized event size, in bytes, above which
+// events are compressed when compression is enabled and no threshold is set.
+const DefaultCompressionThreshold = 1024
+
+// DefaultMaxDecompressedSize bounds the size, in bytes, a received compressed payload
+// may expand to when no maxDecompressedSize is set.
+const DefaultMaxDecompressedSize = 32 << 20
+
+// Magic prefixes used to recognise compressed payloads. Uncompressed events are JSON
+// objects and always start with '{', so plain payloads from older publishers still decode.
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// PayloadTooLargeError describes an event rejected because of its size.
+type PayloadTooLargeError struct {
+	Topic string
+	Size  int
+	Limit int
+}
+
+// Error implements error
+func (e *PayloadTooLargeError) Error() string {
+	return fmt.Sprintf("%s: topic %s is %d bytes, limit is %d bytes", ErrPayloadTooLarge, e.Topic, e.Size, e.Limit)
+}
+
+// Unwrap allows errors.Is(err, ErrPayloadTooLarge)
+func (e *PayloadTooLargeError) Unwrap() error {
+	return ErrPayloadTooLarge
+}
+
+// PayloadConfig controls how broker engines encode event payloads. It is read from
+// the engine's config map ("compression", "compressionThreshold", "maxPayloadSize",
+// "maxDecompressedSize").
+type PayloadConfig struct {
+	// Compression is "none" (default), "gzip" or "zstd"
+	Compression string `json:"compression"`
+
+	// CompressionThreshold is the minimum serialized size in bytes before compressing.
+	// Defaults to DefaultCompressionThreshold.
+	CompressionThreshold int `json:"compressionThreshold"`
+
+	// MaxPayloadSize rejects events whose encoded (post-compression) size exceeds
+	// this many bytes. 0 means unlimited.
+	MaxPayloadSize int `json:"maxPayloadSize"`
+
+	// MaxDecompressedSize bounds the size a received compressed payload may expand
+	// to, protecting subscribers from compression bombs. Publishers reject events
+	// whose serialized size exceeds it. Defaults to DefaultMaxDecompressedSize and is
+	// never lower than MaxPayloadSize.
+	MaxDecompressedSize int `json:"maxDecompressedSize"`
+}
+
+// PayloadStats reports payload encoding outcomes for an engine.
+type PayloadStats struct {
+	Compressed         uint64 `json:"compressed" yaml:"compressed"`
+	Rejected           uint64 `json:"rejected" yaml:"rejected"`
+	UncompressedBytes  uint64 `json:"uncompressedBytes" yaml:"uncompressedBytes"`
+	CompressedBytes    uint64 `json:"compressedBytes" yaml:"compressedBytes"`
+	LargestPayloadSize uint64 `json:"largestPayloadSize" yaml:"largestPayloadSize"`
+}
+
+// payloadStatsProvider is implemented by engines that encode payloads with a payloadCodec
+type payloadStatsProvider interface {
+	PayloadStats() PayloadStats
+}
+
+// parsePayloadConfig reads the payload settings from an engine config map.
+func parsePayloadConfig(config map[string]interface{}) (PayloadConfig, error) {
+	cfg := PayloadConfig{
+		Compression:          CompressionNone,
+		CompressionThreshold: DefaultCompressionThreshold,
+		MaxDecompressedSize:  DefaultMaxDecompressedSize,
+	}
+	if compression, ok := config["compression"].(string); ok && compression != "" {
+		cfg.Compression = compression
+	}
+	if threshold, ok := intConfigValue(config["compressionThreshold"]); ok && threshold > 0 {
+		cfg.CompressionThreshold = threshold
+	}
+	if maxSize, ok := intConfigValue(config["maxPayloadSize"]); ok && maxSize > 0 {
+		cfg.MaxPayloadSize = maxSize
+	}
+	if maxSize, ok := intConfigValue(config["maxDecompressedSize"]); ok && maxSize > 0 {
+		cfg.MaxDecompressedSize = maxSize
+	}
+	cfg.MaxDecompressedSize = max(cfg.MaxDecompressedSize, cfg.MaxPayloadSize)
+
+	switch cfg.Compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+		return cfg, nil
+	default:
+		return cfg, fmt.Errorf("%w: %q", ErrUnsupportedCompression, cfg.Compression)
+	}
+}
+
+// intConfigValue converts the numeric types produced by YAML, JSON and Go literals to int
+func intConfigValue(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// payloadCodec serializes events for broker engines, compressing and enforcing size
+// limits according to its PayloadConfig. It is safe for concurrent use.
+type payloadCodec struct {
+	config PayloadConfig
+
+	compressed        atomic.Uint64
+	rejected          atomic.Uint64
+	uncompressedBytes atomic.Uint64
+	compressedBytes   atomic.Uint64
+	largest           atomic.Uint64
+}
+
+// newPayloadCodec creates a codec from an engine config map.
+func newPayloadCodec(config map[string]interface{}) (*payloadCodec, error) {
+	cfg, err := parsePayloadConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return &payloadCodec{config: cfg}, nil
+}
+
+// encode serializes event, compressing it above the threshold and rejecting it with
+// a *PayloadTooLargeError when the result exceeds MaxPayloadSize. A nil codec
+// produces plain JSON.
+func (c *payloadCodec) encode(event Event) ([]byte, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize event: %w", err)
+	}
+	if c == nil {
+		return data, nil
+	}
+
+	// Subscribers refuse to expand payloads beyond MaxDecompressedSize
+	if len(data) > c.config.MaxDecompressedSize {
+		c.rejected.Add(1)
+		return nil, &PayloadTooLargeError{Topic: event.Type(), Size: len(data), Limit: c.config.MaxDecompressedSize}
+	}
+
+	if c.config.Compression != CompressionNone && len(data) >= c.config.CompressionThreshold {
+		compressed, err := compressPayload(c.config.Compression, data)
+		if err != nil {
+			return nil, err
+		}
+		// Only keep the compressed form when it actually saves space
+		if len(compressed) < len(data) {
+			c.compressed.Add(1)
+			c.uncompressedBytes.Add(uint64(len(data)))
+			c.compressedBytes.Add(uint64(len(compressed)))
+			data = compressed
+		}
+	}
+
+	size := uint64(len(data))
+	for {
+		largest := c.largest.Load()
+		if size <= largest || c.largest.CompareAndSwap(largest, size) {
+			break
+		}
+	}
+
+	if c.config.MaxPayloadSize > 0 && len(data) > c.config.MaxPayloadSize {
+		c.rejected.Add(1)
+		return nil, &PayloadTooLargeError{Topic: event.Type(), Size: len(data), Limit: c.config.MaxPayloadSize}
+	}
+	return data, nil
+}
+
+// decode deserializes a payload produced by encode, or a plain JSON event. Compressed
+// payloads are recognised by their magic bytes, so decoding does not depend on the
+// local compression setting and a nil codec decodes too. Payloads that expand beyond
+// MaxDecompressedSize are rejected with ErrPayloadTooLarge.
+func (c *payloadCodec) decode(data []byte, event *Event) error {
+	limit := DefaultMaxDecompressedSize
+	if c != nil {
+		limit = c.config.MaxDecompressedSize
+	}
+
+	var err error
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		data, err = decompressPayload(CompressionGzip, data, limit)
+	case bytes.HasPrefix(data, zstdMagic):
+		data, err = decompressPayload(CompressionZstd, data, limit)
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, event); err != nil {
+		return fmt.Errorf("failed to deserialize event: %w", err)
+	}
+	return nil
+}
+
+// stats returns a snapshot of the codec's counters
+func (c *payloadCodec) stats() PayloadStats {
+	if c == nil {
+		return PayloadStats{}
+	}
+	return PayloadStats{
+		Compressed:         c.compressed.Load(),
+		Rejected:           c.rejected.Load(),
+		UncompressedBytes:  c.uncompressedBytes.Load(),
+		CompressedBytes:    c.compressedBytes.Load(),
+		LargestPayloadSize: c.largest.Load(),
+	}
+}
+
+// zstd encoders and decoders are expensive to create and safe for concurrent use.
+// Decoders are shared per decompressed size limit.
+var (
+	zstdOnce     sync.Once
+	zstdEncoder  *zstd.Encoder
+	zstdErr      error
+	zstdDecoders sync.Map // map[int]*zstd.Decoder
+)
+
+func zstdEncoderInstance() (*zstd.Encoder, error) {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+	})
+	return zstdEncoder, zstdErr
+}
+
+// zstdDecoderInstance returns a decoder that refuses to produce more than limit bytes
+func zstdDecoderInstance(limit int) (*zstd.Decoder, error) {
+	if decoder, ok := zstdDecoders.Load(limit); ok {
+		return decoder.(*zstd.Decoder), nil
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(limit)))
+	if err != nil {
+		return nil, err
+	}
+	actual, loaded := zstdDecoders.LoadOrStore(limit, decoder)
+	if loaded {
+		decoder.Close()
+	}
+	return actual.(*zstd.Decoder), nil
+}
+
+func compressPayload(algorithm string, data []byte) ([]byte, error) {
+	switch algorithm {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("gzip compress: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("gzip compress: %w", err)
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		encoder, err := zstdEncoderInstance()
+		if err != nil {
+			return nil, fmt.Errorf("zstd compress: %w", err)
+		}
+		return encoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCompression, algorithm)
+	}
+}
+
+// decompressPayload expands data, failing with ErrPayloadTooLarge when the result
+// would exceed limit bytes.
+func decompressPayload(algorithm string, data []byte, limit int) ([]byte, error) {
+	tooLarge := fmt.Errorf("%w: decompressed payload exceeds %d bytes", ErrPayloadTooLarge, limit)
+	switch algorithm {
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gzip decompress: %w", err)
+		}
+		defer r.Close()
+		out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+		if err != nil {
+			return nil, fmt.Errorf("gzip decompress: %w", err)
+		}
+		if len(out) > limit {
+			return nil, tooLarge
+		}
+		return out, nil
+	case CompressionZstd:
+		decoder, err := zstdDecoderInstance(limit)
+		if err != nil {
+			return nil, fmt.Errorf("zstd decompress: %w", err)
+		}
+		out, err := decoder.DecodeAll(data, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || len(out) > limit {
+			return nil, tooLarge
+		}
+		if err != nil {
+			return nil, fmt.Errorf("zstd decompress: %w", err)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCompression, algorithm)
+	}
+}
@@ -0,0 +1,287 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/IBM/sarama"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/CrisisTextLine/modular/modules/eventbus/mocks"
+)
+
+func newPayloadTestEvent(t *testing.T, topic string, size int) Event {
+	t.Helper()
+	return newTestCloudEvent(topic, map[string]string{"body": strings.Repeat("a", size)})
+}
+
+func TestPayloadCodec_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression string
+		size        int
+		magic       []byte
+	}{
+		{"gzip above threshold", CompressionGzip, 4096, gzipMagic},
+		{"zstd above threshold", CompressionZstd, 4096, zstdMagic},
+		{"below threshold stays plain", CompressionGzip, 10, []byte("{")},
+		{"compression disabled", CompressionNone, 4096, []byte("{")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			codec, err := newPayloadCodec(map[string]interface{}{"compression": tt.compression})
+			require.NoError(t, err)
+
+			event := newPayloadTestEvent(t, "payload.test", tt.size)
+			data, err := codec.encode(event)
+			require.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(data, tt.magic), "unexpected payload prefix %x", data[:4])
+
+			var decoded Event
+			require.NoError(t, codec.decode(data, &decoded))
+			assert.Equal(t, event.ID(), decoded.ID())
+			assert.Equal(t, event.Data(), decoded.Data())
+		})
+	}
+}
+
+func TestPayloadCodec_DecodesOtherEncodings(t *testing.T) {
+	// A consumer without compression configured still reads compressed payloads
+	zstdCodec, err := newPayloadCodec(map[string]interface{}{"compression": CompressionZstd, "compressionThreshold": 1})
+	require.NoError(t, err)
+	plain, err := newPayloadCodec(map[string]interface{}{})
+	require.NoError(t, err)
+
+	event := newPayloadTestEvent(t, "payload.test", 2048)
+	data, err := zstdCodec.encode(event)
+	require.NoError(t, err)
+
+	var decoded Event
+	require.NoError(t, plain.decode(data, &decoded))
+	assert.Equal(t, event.ID(), decoded.ID())
+
+	// A nil codec (engines built without a constructor) behaves like plain JSON
+	var nilCodec *payloadCodec
+	data, err = nilCodec.encode(event)
+	require.NoError(t, err)
+	assert.True(t, json.Valid(data))
+	require.NoError(t, zstdCodec.decode(data, &decoded))
+	assert.Equal(t, PayloadStats{}, nilCodec.stats())
+}
+
+func TestPayloadCodec_MaxPayloadSize(t *testing.T) {
+	codec, err := newPayloadCodec(map[string]interface{}{"maxPayloadSize": 512})
+	require.NoError(t, err)
+
+	_, err = codec.encode(newPayloadTestEvent(t, "payload.small", 10))
+	require.NoError(t, err)
+
+	_, err = codec.encode(newPayloadTestEvent(t, "payload.large", 4096))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+	var tooLarge *PayloadTooLargeError
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, "payload.large", tooLarge.Topic)
+	assert.Equal(t, 512, tooLarge.Limit)
+	assert.Greater(t, tooLarge.Size, 4096)
+
+	// The limit applies after compression, so compressible events fit
+	compressing, err := newPayloadCodec(map[string]interface{}{
+		"compression":    CompressionGzip,
+		"maxPayloadSize": float64(512), // as decoded from JSON
+	})
+	require.NoError(t, err)
+	_, err = compressing.encode(newPayloadTestEvent(t, "payload.large", 4096))
+	require.NoError(t, err)
+
+	stats := codec.stats()
+	assert.Equal(t, uint64(1), stats.Rejected)
+	assert.Zero(t, stats.Compressed)
+
+	stats = compressing.stats()
+	assert.Equal(t, uint64(1), stats.Compressed)
+	assert.Less(t, stats.CompressedBytes, stats.UncompressedBytes)
+}
+
+func TestPayloadCodec_UnsupportedCompression(t *testing.T) {
+	_, err := newPayloadCodec(map[string]interface{}{"compression": "lz4"})
+	require.ErrorIs(t, err, ErrUnsupportedCompression)
+
+	_, err = NewNatsEventBus(map[string]interface{}{"url": "nats://127.0.0.1:1", "compression": "lz4"})
+	require.ErrorIs(t, err, ErrUnsupportedCompression)
+}
+
+func TestKafkaPublish_RejectsOversizedPayload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	producer := mocks.NewMockSyncProducer(ctrl)
+	bus := newTestKafkaEventBus(producer)
+	defer bus.cancel()
+	bus.payload, _ = newPayloadCodec(map[string]interface{}{"maxPayloadSize": 256})
+
+	// SendMessage must not be called for the oversized event
+	producer.EXPECT().SendMessage(gomock.Any()).DoAndReturn(func(msg *sarama.ProducerMessage) (int32, int64, error) {
+		assert.Equal(t, "payload.small", msg.Topic)
+		return 0, 0, nil
+	}).Times(1)
+
+	require.NoError(t, bus.Publish(context.Background(), newPayloadTestEvent(t, "payload.small", 10)))
+	err := bus.Publish(context.Background(), newPayloadTestEvent(t, "payload.large", 1024))
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+	assert.Equal(t, uint64(1), bus.PayloadStats().Rejected)
+}
+
+func TestNatsEventBus_CompressedDelivery(t *testing.T) {
+	url := startTestNATSServer(t)
+
+	bus, err := NewNatsEventBus(map[string]interface{}{
+		"url":                  url,
+		"compression":          CompressionZstd,
+		"compressionThreshold": 256,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, bus.Start(ctx))
+	defer bus.Stop(ctx)
+
+	received := make(chan Event, 1)
+	_, err = bus.Subscribe(ctx, "payload.nats", func(ctx context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+
+	event := newPayloadTestEvent(t, "payload.nats", 8192)
+	require.NoError(t, bus.Publish(ctx, event))
+
+	select {
+	case got := <-received:
+		assert.Equal(t, event.ID(), got.ID())
+		assert.Equal(t, event.Data(), got.Data())
+	case <-time.After(5 * time.Second):
+		t.Fatal("compressed event was not delivered")
+	}
+	assert.Equal(t, uint64(1), bus.(*NatsEventBus).PayloadStats().Compressed)
+}
+
+// payloadTestSubject records events emitted by the module
+type payloadTestSubject struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (s *payloadTestSubject) RegisterObserver(observer modular.Observer, eventTypes ...string) error {
+	return nil
+}
+
+func (s *payloadTestSubject) UnregisterObserver(observer modular.Observer) error {
+	return nil
+}
+
+func (s *payloadTestSubject) NotifyObservers(ctx context.Context, event cloudevents.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.Clone())
+	return nil
+}
+
+func (s *payloadTestSubject) GetObservers() []modular.ObserverInfo {
+	return nil
+}
+
+func (s *payloadTestSubject) eventsOfType(eventType string) []cloudevents.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []cloudevents.Event
+	for _, event := range s.events {
+		if event.Type() == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+func TestEventBusModule_EmitsRejectedEvent(t *testing.T) {
+	url := startTestNATSServer(t)
+
+	config := &EventBusConfig{
+		Source: "payload-test",
+		Engines: []EngineConfig{{
+			Name: "nats",
+			Type: "nats",
+			Config: map[string]interface{}{
+				"url":            url,
+				"maxPayloadSize": 1024,
+			},
+		}},
+	}
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, router.Start(ctx))
+	defer router.Stop(ctx)
+
+	subject := &payloadTestSubject{}
+	m := &EventBusModule{name: ModuleName, config: config, router: router, subject: subject}
+
+	err = m.Publish(ctx, "payload.module", map[string]string{"body": strings.Repeat("a", 4096)})
+	require.ErrorIs(t, err, ErrPayloadTooLarge)
+
+	require.Eventually(t, func() bool {
+		return len(subject.eventsOfType(EventTypeMessageRejected)) == 1
+	}, time.Second, 10*time.Millisecond)
+	var data map[string]interface{}
+	require.NoError(t, subject.eventsOfType(EventTypeMessageRejected)[0].DataAs(&data))
+	assert.Equal(t, "payload.module", data["topic"])
+	assert.Equal(t, "payload_too_large", data["reason"])
+	assert.InDelta(t, 1024, data["limit_bytes"], 0)
+
+	assert.Equal(t, uint64(1), m.PayloadStats()["nats"].Rejected)
+}
+
+func TestPayloadCodec_BoundsDecompressedSize(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			publisher, err := newPayloadCodec(map[string]interface{}{"compression": compression})
+			require.NoError(t, err)
+			subscriber, err := newPayloadCodec(map[string]interface{}{"maxDecompressedSize": 4096})
+			require.NoError(t, err)
+
+			// Highly compressible payloads fit the wire limit but expand past the subscriber's bound
+			data, err := publisher.encode(newPayloadTestEvent(t, "payload.bomb", 64*1024))
+			require.NoError(t, err)
+			require.Less(t, len(data), 4096)
+
+			var decoded Event
+			require.ErrorIs(t, subscriber.decode(data, &decoded), ErrPayloadTooLarge)
+
+			data, err = publisher.encode(newPayloadTestEvent(t, "payload.small", 2048))
+			require.NoError(t, err)
+			require.NoError(t, subscriber.decode(data, &decoded))
+		})
+	}
+}
+
+func TestPayloadCodec_RejectsEventsSubscribersCannotExpand(t *testing.T) {
+	codec, err := newPayloadCodec(map[string]interface{}{"compression": CompressionGzip, "maxDecompressedSize": 4096})
+	require.NoError(t, err)
+
+	_, err = codec.encode(newPayloadTestEvent(t, "payload.large", 8192))
+	var tooLarge *PayloadTooLargeError
+	require.ErrorAs(t, err, &tooLarge)
+	assert.Equal(t, 4096, tooLarge.Limit)
+
+	// The bound never drops below the wire limit
+	codec, err = newPayloadCodec(map[string]interface{}{"maxPayloadSize": 8192, "maxDecompressedSize": 1024})
+	require.NoError(t, err)
+	assert.Equal(t, 8192, codec.config.MaxDecompressedSize)
+}
@@ -1,6 +1,9 @@
 package eventbus
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // partitionKeyCtxKey is the context key for partition key routing hints.
 type partitionKeyCtxKey struct{}
@@ -35,3 +38,26 @@ func PartitionKeyFromContext(ctx context.Context) (string, bool) {
 	key, ok := ctx.Value(partitionKeyCtxKey{}).(string)
 	return key, ok
 }
+
+// ttlCtxKey is the context key for the per-publish TTL.
+type ttlCtxKey struct{}
+
+// WithTTL returns a context that gives events published with it a time to live.
+// Events still undelivered once the TTL has passed are skipped instead of handed
+// to subscribers, which suits time-sensitive notifications where late delivery is
+// worse than none. It takes precedence over TopicTTLs.
+//
+// Example:
+//
+//	ctx = eventbus.WithTTL(ctx, 30*time.Second)
+//	err := eventBus.Publish(ctx, "ride.offer", offer)
+func WithTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ttlCtxKey{}, ttl)
+}
+
+// TTLFromContext extracts the TTL set with WithTTL.
+// Returns the TTL and true if set, or zero and false if not set.
+func TTLFromContext(ctx context.Context) (time.Duration, bool) {
+	ttl, ok := ctx.Value(ttlCtxKey{}).(time.Duration)
+	return ttl, ok
+}
@@ -2,16 +2,29 @@ package eventbus
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrInvalidRedisConfig is returned when a redis engine's config is invalid
+var ErrInvalidRedisConfig = errors.New("invalid redis configuration")
+
+// Delivery modes for RedisConfig.Mode.
+const (
+	// RedisModePubSub delivers events with Redis pub/sub, to the subscribers
+	// connected when they are published (default)
+	RedisModePubSub = "pubsub"
+	// RedisModeStreams stores events in Redis Streams read through consumer groups
+	RedisModeStreams = "streams"
+)
+
 // RedisEventBus implements EventBus using Redis pub/sub
 type RedisEventBus struct {
 	config        *RedisConfig
@@ -22,6 +35,7 @@ type RedisEventBus struct {
 	cancel        context.CancelFunc
 	wg            sync.WaitGroup
 	isStarted     bool
+	payload       *payloadCodec
 }
 
 // RedisConfig holds Redis-specific configuration
@@ -31,6 +45,36 @@ type RedisConfig struct {
 	Username string `json:"username"`
 	Password string `json:"password"` //nolint:gosec // config field, not a hardcoded secret
 	PoolSize int    `json:"poolSize"`
+
+	// Mode is how events are delivered: "pubsub" (default) or "streams".
+	// The settings below apply to the streams mode only.
+	Mode string `json:"mode"`
+	// StreamPrefix is prepended to a topic to name its stream. Defaults to "eventbus:".
+	StreamPrefix string `json:"streamPrefix"`
+	// GroupID names the consumer groups reading the streams. Instances sharing it
+	// split the events of each subscribed topic, and resume where the group left
+	// off after a restart; give instances that must each receive every event
+	// their own GroupID. Required in streams mode.
+	GroupID string `json:"groupId"`
+	// ConsumerName identifies this instance within its consumer groups. Defaults to a random ID.
+	ConsumerName string `json:"consumerName"`
+	// MaxLen trims each stream to about this many events on publish. 0 keeps every event.
+	MaxLen int64 `json:"maxLen"`
+	// BatchSize is how many events a read or claim fetches at once. Defaults to 10.
+	BatchSize int `json:"batchSize"`
+	// BlockTimeout is how long a read waits for new events. Defaults to 1s.
+	BlockTimeout time.Duration `json:"blockTimeout"`
+	// ClaimMinIdle is how long an event stays delivered but unacknowledged, for
+	// example because its consumer crashed, before it is claimed and redelivered.
+	// Defaults to 30s.
+	ClaimMinIdle time.Duration `json:"claimMinIdle"`
+	// ClaimInterval is how often pending events are claimed, and wildcard
+	// subscriptions look for new streams. Defaults to 5s.
+	ClaimInterval time.Duration `json:"claimInterval"`
+	// MaxDeliveries is how many times an event is delivered before it stops being
+	// redelivered and is handed to its topic's dead-letter policy, or dropped when
+	// the topic has none. Defaults to 5.
+	MaxDeliveries int `json:"maxDeliveries"`
 }
 
 // redisSubscription represents a subscription in the Redis event bus
@@ -78,12 +122,50 @@ func (s *redisSubscription) Cancel() error {
 	return nil
 }
 
-// NewRedisEventBus creates a new Redis-based event bus
+// NewRedisEventBus creates a new Redis-based event bus. It uses pub/sub unless
+// the config's mode is "streams", in which case it returns a RedisStreamsEventBus.
 func NewRedisEventBus(config map[string]interface{}) (EventBus, error) {
+	redisConfig, err := parseRedisConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := newPayloadCodec(config)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := newRedisClient(redisConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if redisConfig.Mode == RedisModeStreams {
+		return newRedisStreamsEventBus(redisConfig, client, payload), nil
+	}
+
+	return &RedisEventBus{
+		config:        redisConfig,
+		client:        client,
+		subscriptions: make(map[string]map[string]*redisSubscription),
+		payload:       payload,
+	}, nil
+}
+
+// parseRedisConfig reads a RedisConfig from an engine config map.
+func parseRedisConfig(config map[string]interface{}) (*RedisConfig, error) {
 	redisConfig := &RedisConfig{
-		URL:      "redis://localhost:6379",
-		DB:       0,
-		PoolSize: 10,
+		URL:           "redis://localhost:6379",
+		DB:            0,
+		PoolSize:      10,
+		Mode:          RedisModePubSub,
+		StreamPrefix:  "eventbus:",
+		ConsumerName:  uuid.New().String(),
+		BatchSize:     10,
+		BlockTimeout:  time.Second,
+		ClaimMinIdle:  30 * time.Second,
+		ClaimInterval: 5 * time.Second,
+		MaxDeliveries: 5,
 	}
 
 	// Parse configuration
@@ -102,7 +184,63 @@ func NewRedisEventBus(config map[string]interface{}) (EventBus, error) {
 	if poolSize, ok := config["poolSize"].(int); ok {
 		redisConfig.PoolSize = poolSize
 	}
+	if mode, ok := config["mode"].(string); ok && mode != "" {
+		redisConfig.Mode = mode
+	}
+	if prefix, ok := config["streamPrefix"].(string); ok {
+		redisConfig.StreamPrefix = prefix
+	}
+	if groupID, ok := config["groupId"].(string); ok && groupID != "" {
+		redisConfig.GroupID = groupID
+	}
+	if consumerName, ok := config["consumerName"].(string); ok && consumerName != "" {
+		redisConfig.ConsumerName = consumerName
+	}
+	if maxLen, ok := intConfigValue(config["maxLen"]); ok {
+		redisConfig.MaxLen = int64(maxLen)
+	}
+	if batchSize, ok := intConfigValue(config["batchSize"]); ok {
+		redisConfig.BatchSize = batchSize
+	}
+	if maxDeliveries, ok := intConfigValue(config["maxDeliveries"]); ok {
+		redisConfig.MaxDeliveries = maxDeliveries
+	}
+	durations := map[string]*time.Duration{
+		"blockTimeout":  &redisConfig.BlockTimeout,
+		"claimMinIdle":  &redisConfig.ClaimMinIdle,
+		"claimInterval": &redisConfig.ClaimInterval,
+	}
+	for key, target := range durations {
+		switch value := config[key].(type) {
+		case time.Duration:
+			*target = value
+		case string:
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s %q: %w", ErrInvalidRedisConfig, key, value, err)
+			}
+			*target = d
+		}
+	}
+
+	if redisConfig.Mode != RedisModePubSub && redisConfig.Mode != RedisModeStreams {
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidRedisConfig, redisConfig.Mode)
+	}
+	if redisConfig.Mode == RedisModeStreams && redisConfig.GroupID == "" {
+		// A random group would be abandoned, with its pending events, on every restart
+		return nil, fmt.Errorf("%w: groupId is required in streams mode", ErrInvalidRedisConfig)
+	}
+	if redisConfig.MaxLen < 0 || redisConfig.BatchSize <= 0 || redisConfig.MaxDeliveries <= 0 {
+		return nil, fmt.Errorf("%w: maxLen must not be negative, and batchSize and maxDeliveries must be positive", ErrInvalidRedisConfig)
+	}
+	if redisConfig.BlockTimeout <= 0 || redisConfig.ClaimMinIdle <= 0 || redisConfig.ClaimInterval <= 0 {
+		return nil, fmt.Errorf("%w: blockTimeout, claimMinIdle and claimInterval must be positive", ErrInvalidRedisConfig)
+	}
+	return redisConfig, nil
+}
 
+// newRedisClient returns a client for redisConfig.
+func newRedisClient(redisConfig *RedisConfig) (*redis.Client, error) {
 	// Parse Redis connection URL
 	opts, err := redis.ParseURL(redisConfig.URL)
 	if err != nil {
@@ -119,13 +257,7 @@ func NewRedisEventBus(config map[string]interface{}) (EventBus, error) {
 		opts.Password = redisConfig.Password
 	}
 
-	client := redis.NewClient(opts)
-
-	return &RedisEventBus{
-		config:        redisConfig,
-		client:        client,
-		subscriptions: make(map[string]map[string]*redisSubscription),
-	}, nil
+	return redis.NewClient(opts), nil
 }
 
 // Start initializes the Redis event bus
@@ -195,9 +327,9 @@ func (r *RedisEventBus) Publish(ctx context.Context, event Event) error {
 		return ErrEventBusNotStarted
 	}
 
-	eventData, err := json.Marshal(event)
+	eventData, err := r.payload.encode(event)
 	if err != nil {
-		return fmt.Errorf("failed to serialize event: %w", err)
+		return err
 	}
 
 	// Publish to Redis
@@ -209,6 +341,11 @@ func (r *RedisEventBus) Publish(ctx context.Context, event Event) error {
 	return nil
 }
 
+// PayloadStats returns compression and size-limit statistics for published events
+func (r *RedisEventBus) PayloadStats() PayloadStats {
+	return r.payload.stats()
+}
+
 // Subscribe registers a handler for a topic
 func (r *RedisEventBus) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
 	return r.subscribe(ctx, topic, handler, false)
@@ -343,7 +480,7 @@ func (r *RedisEventBus) handleMessages(sub *redisSubscription) {
 
 			// Deserialize event
 			var event Event
-			err := json.Unmarshal([]byte(msg.Payload), &event)
+			err := r.payload.decode([]byte(msg.Payload), &event)
 			if err != nil {
 				slog.Error("Failed to deserialize Redis message", "error", err, "topic", msg.Channel)
 				continue
@@ -0,0 +1,565 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisStreamEventField is the stream entry field holding the encoded event.
+const redisStreamEventField = "event"
+
+// RedisStreamsEventBus implements EventBus using Redis Streams. Each topic is
+// stored in its own stream, read through a consumer group per subscribed topic,
+// so events outlive the subscribers that were connected when they were published:
+//
+//   - Instances sharing RedisConfig.GroupID split the events of a topic, and
+//     resume where their group left off after a restart.
+//   - An event is acknowledged once its synchronous handlers succeed. Events
+//     left unacknowledged, because a handler failed or its consumer crashed, are
+//     claimed and redelivered after RedisConfig.ClaimMinIdle, so delivery is
+//     at least once. After RedisConfig.MaxDeliveries deliveries an event is
+//     dead-lettered if its topic has a dead-letter policy, or else dropped.
+//   - Wildcard topics ("orders.*") read every stream matching the pattern,
+//     discovering new streams every RedisConfig.ClaimInterval.
+//
+// It is created by the "redis" engine with mode "streams" and requires Redis 6.2
+// or later.
+type RedisStreamsEventBus struct {
+	config     *RedisConfig
+	client     *redis.Client
+	readers    map[string]*redisStreamReader
+	topicMutex sync.RWMutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+	isStarted  bool
+	payload    *payloadCodec
+
+	// deadLetter dead-letters events delivered more than MaxDeliveries times,
+	// reporting false when their topic has no dead-letter policy
+	deadLetter func(ctx context.Context, event Event, attempts int, err error) (bool, error)
+}
+
+// redisStreamSubscription represents a subscription in the Redis Streams event bus
+type redisStreamSubscription struct {
+	id        string
+	topic     string
+	handler   EventHandler
+	isAsync   bool
+	cancelled bool
+	mutex     sync.RWMutex
+}
+
+// Topic returns the topic of the subscription
+func (s *redisStreamSubscription) Topic() string {
+	return s.topic
+}
+
+// ID returns the unique identifier for the subscription
+func (s *redisStreamSubscription) ID() string {
+	return s.id
+}
+
+// IsAsync returns whether the subscription is asynchronous
+func (s *redisStreamSubscription) IsAsync() bool {
+	return s.isAsync
+}
+
+// Cancel cancels the subscription
+func (s *redisStreamSubscription) Cancel() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.cancelled = true
+	return nil
+}
+
+// isCancelled reports whether the subscription has been cancelled
+func (s *redisStreamSubscription) isCancelled() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.cancelled
+}
+
+// redisStreamReader reads the streams of one subscribed topic through the
+// topic's consumer group and delivers their events to the topic's subscriptions.
+type redisStreamReader struct {
+	topic string
+	group string
+	// since is the ID consumer groups are created at, so a new group receives the
+	// events published after the subscription
+	since         string
+	subscriptions map[string]*redisStreamSubscription
+	done          chan struct{}
+
+	// streams are the stream keys read, with their groups created. Only the
+	// reader's goroutine uses them once it runs.
+	streams []string
+}
+
+// newRedisStreamsEventBus creates a Redis Streams event bus using client.
+func newRedisStreamsEventBus(config *RedisConfig, client *redis.Client, payload *payloadCodec) *RedisStreamsEventBus {
+	return &RedisStreamsEventBus{
+		config:  config,
+		client:  client,
+		readers: make(map[string]*redisStreamReader),
+		payload: payload,
+	}
+}
+
+// Start initializes the Redis Streams event bus
+func (r *RedisStreamsEventBus) Start(ctx context.Context) error {
+	if r.isStarted {
+		return nil
+	}
+
+	if err := r.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	r.isStarted = true
+	return nil
+}
+
+// Stop shuts down the Redis Streams event bus. Events being read but not yet
+// handled stay pending in their consumer group and are claimed by other consumers.
+func (r *RedisStreamsEventBus) Stop(ctx context.Context) error {
+	if !r.isStarted {
+		return nil
+	}
+
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	r.topicMutex.Lock()
+	for _, reader := range r.readers {
+		for _, sub := range reader.subscriptions {
+			_ = sub.Cancel()
+		}
+		close(reader.done)
+	}
+	r.readers = make(map[string]*redisStreamReader)
+	r.topicMutex.Unlock()
+
+	// Readers exit once their blocking read returns, within BlockTimeout
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ErrEventBusShutdownTimeout
+	}
+
+	if err := r.client.Close(); err != nil {
+		return fmt.Errorf("error closing Redis client: %w", err)
+	}
+
+	r.isStarted = false
+	return nil
+}
+
+// Publish appends an event to its topic's stream, trimming the stream to about
+// MaxLen events when set
+func (r *RedisStreamsEventBus) Publish(ctx context.Context, event Event) error {
+	if !r.isStarted {
+		return ErrEventBusNotStarted
+	}
+
+	eventData, err := r.payload.encode(event)
+	if err != nil {
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: r.streamKey(event.Type()),
+		Values: map[string]interface{}{redisStreamEventField: eventData},
+	}
+	if r.config.MaxLen > 0 {
+		args.MaxLen = r.config.MaxLen
+		args.Approx = true
+	}
+	if err := r.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("failed to publish to Redis stream: %w", err)
+	}
+
+	return nil
+}
+
+// PayloadStats returns compression and size-limit statistics for published events
+func (r *RedisStreamsEventBus) PayloadStats() PayloadStats {
+	return r.payload.stats()
+}
+
+// Subscribe registers a handler for a topic
+func (r *RedisStreamsEventBus) Subscribe(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
+	return r.subscribe(ctx, topic, handler, false)
+}
+
+// SubscribeAsync registers a handler for a topic with asynchronous processing.
+// Events are acknowledged as soon as they are dispatched to asynchronous handlers.
+func (r *RedisStreamsEventBus) SubscribeAsync(ctx context.Context, topic string, handler EventHandler) (Subscription, error) {
+	return r.subscribe(ctx, topic, handler, true)
+}
+
+// subscribe is the internal implementation for both Subscribe and SubscribeAsync
+func (r *RedisStreamsEventBus) subscribe(ctx context.Context, topic string, handler EventHandler, isAsync bool) (Subscription, error) {
+	if !r.isStarted {
+		return nil, ErrEventBusNotStarted
+	}
+
+	if handler == nil {
+		return nil, ErrEventHandlerNil
+	}
+
+	sub := &redisStreamSubscription{
+		id:      uuid.New().String(),
+		topic:   topic,
+		handler: handler,
+		isAsync: isAsync,
+	}
+
+	r.topicMutex.Lock()
+	defer r.topicMutex.Unlock()
+
+	reader, ok := r.readers[topic]
+	if !ok {
+		reader = &redisStreamReader{
+			topic:         topic,
+			group:         r.config.GroupID + ":" + topic,
+			since:         fmt.Sprintf("%d-0", time.Now().UnixMilli()-1),
+			subscriptions: make(map[string]*redisStreamSubscription),
+			done:          make(chan struct{}),
+		}
+		// Wildcard topics find their streams when the reader starts
+		if !strings.Contains(topic, "*") {
+			key := r.streamKey(topic)
+			if err := r.createGroup(ctx, reader, key); err != nil {
+				return nil, err
+			}
+			reader.streams = []string{key}
+		}
+		r.readers[topic] = reader
+
+		r.wg.Add(1)
+		go r.read(reader)
+	}
+	reader.subscriptions[sub.id] = sub
+
+	return sub, nil
+}
+
+// Unsubscribe removes a subscription. The topic's consumer group is kept, so
+// subscribing again resumes where it left off.
+func (r *RedisStreamsEventBus) Unsubscribe(ctx context.Context, subscription Subscription) error {
+	if !r.isStarted {
+		return ErrEventBusNotStarted
+	}
+
+	sub, ok := subscription.(*redisStreamSubscription)
+	if !ok {
+		return ErrInvalidSubscriptionType
+	}
+
+	if err := sub.Cancel(); err != nil {
+		return err
+	}
+
+	r.topicMutex.Lock()
+	defer r.topicMutex.Unlock()
+
+	if reader, ok := r.readers[sub.topic]; ok {
+		delete(reader.subscriptions, sub.id)
+		if len(reader.subscriptions) == 0 {
+			close(reader.done)
+			delete(r.readers, sub.topic)
+		}
+	}
+
+	return nil
+}
+
+// Topics returns a list of all active topics
+func (r *RedisStreamsEventBus) Topics() []string {
+	r.topicMutex.RLock()
+	defer r.topicMutex.RUnlock()
+
+	topics := make([]string, 0, len(r.readers))
+	for topic := range r.readers {
+		topics = append(topics, topic)
+	}
+
+	return topics
+}
+
+// SubscriberCount returns the number of subscribers for a topic
+func (r *RedisStreamsEventBus) SubscriberCount(topic string) int {
+	r.topicMutex.RLock()
+	defer r.topicMutex.RUnlock()
+
+	if reader, ok := r.readers[topic]; ok {
+		return len(reader.subscriptions)
+	}
+
+	return 0
+}
+
+// streamKey returns the key of a topic's stream.
+func (r *RedisStreamsEventBus) streamKey(topic string) string {
+	return r.config.StreamPrefix + topic
+}
+
+// createGroup creates the reader's consumer group on the stream at key, keeping
+// the group if it already exists.
+func (r *RedisStreamsEventBus) createGroup(ctx context.Context, reader *redisStreamReader, key string) error {
+	err := r.client.XGroupCreateMkStream(ctx, key, reader.group, reader.since).Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create Redis consumer group %s on %s: %w", reader.group, key, err)
+	}
+	return nil
+}
+
+// read runs a reader until it is stopped, reading new events and, every
+// ClaimInterval, discovering streams and claiming pending events.
+func (r *RedisStreamsEventBus) read(reader *redisStreamReader) {
+	defer r.wg.Done()
+
+	var lastClaim time.Time
+	for {
+		select {
+		case <-reader.done:
+			return
+		case <-r.ctx.Done():
+			return
+		default:
+		}
+
+		if time.Since(lastClaim) >= r.config.ClaimInterval {
+			if strings.Contains(reader.topic, "*") {
+				r.discoverStreams(reader)
+			}
+			r.claimPending(reader)
+			lastClaim = time.Now()
+		}
+
+		if len(reader.streams) == 0 {
+			select {
+			case <-reader.done:
+			case <-r.ctx.Done():
+			case <-time.After(r.config.BlockTimeout):
+			}
+			continue
+		}
+
+		streams := make([]string, 0, 2*len(reader.streams))
+		streams = append(streams, reader.streams...)
+		for range reader.streams {
+			streams = append(streams, ">")
+		}
+		result, err := r.client.XReadGroup(r.ctx, &redis.XReadGroupArgs{
+			Group:    reader.group,
+			Consumer: r.config.ConsumerName,
+			Streams:  streams,
+			Count:    int64(r.config.BatchSize),
+			Block:    r.config.BlockTimeout,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || r.ctx.Err() != nil {
+				continue
+			}
+			slog.Error("Failed to read Redis streams", "error", err, "topic", reader.topic)
+			select {
+			case <-reader.done:
+			case <-r.ctx.Done():
+			case <-time.After(r.config.BlockTimeout):
+			}
+			continue
+		}
+
+		for _, stream := range result {
+			for _, msg := range stream.Messages {
+				r.deliver(reader, stream.Stream, msg, 1)
+			}
+		}
+	}
+}
+
+// discoverStreams adds the streams matching a wildcard reader's topic that it
+// does not read yet.
+func (r *RedisStreamsEventBus) discoverStreams(reader *redisStreamReader) {
+	known := make(map[string]bool, len(reader.streams))
+	for _, key := range reader.streams {
+		known[key] = true
+	}
+
+	iter := r.client.ScanType(r.ctx, 0, r.streamKey(reader.topic), int64(r.config.BatchSize), "stream").Iterator()
+	for iter.Next(r.ctx) {
+		key := iter.Val()
+		if known[key] {
+			continue
+		}
+		if err := r.createGroup(r.ctx, reader, key); err != nil {
+			slog.Error("Failed to read new Redis stream", "error", err, "topic", reader.topic)
+			continue
+		}
+		known[key] = true
+		reader.streams = append(reader.streams, key)
+	}
+	if err := iter.Err(); err != nil && r.ctx.Err() == nil {
+		slog.Error("Failed to look up Redis streams", "error", err, "topic", reader.topic)
+	}
+}
+
+// claimPending claims and delivers the events of the reader's streams that have
+// been pending for at least ClaimMinIdle.
+func (r *RedisStreamsEventBus) claimPending(reader *redisStreamReader) {
+	for _, key := range reader.streams {
+		start := "0-0"
+		for {
+			msgs, next, err := r.client.XAutoClaim(r.ctx, &redis.XAutoClaimArgs{
+				Stream:   key,
+				Group:    reader.group,
+				Consumer: r.config.ConsumerName,
+				MinIdle:  r.config.ClaimMinIdle,
+				Start:    start,
+				Count:    int64(r.config.BatchSize),
+			}).Result()
+			if err != nil {
+				if r.ctx.Err() == nil {
+					slog.Error("Failed to claim pending Redis stream events", "error", err, "stream", key)
+				}
+				break
+			}
+			deliveries := r.deliveryCounts(key, reader.group, msgs)
+			for _, msg := range msgs {
+				r.deliver(reader, key, msg, deliveries[msg.ID])
+			}
+			if next == "0-0" || len(msgs) == 0 {
+				break
+			}
+			start = next
+		}
+	}
+}
+
+// deliveryCounts returns how many times each of the claimed entries of stream has
+// been delivered, counting the claim, from the group's pending entries list.
+func (r *RedisStreamsEventBus) deliveryCounts(stream, group string, msgs []redis.XMessage) map[string]int64 {
+	counts := make(map[string]int64, len(msgs))
+	if len(msgs) == 0 {
+		return counts
+	}
+	pending, err := r.client.XPendingExt(r.ctx, &redis.XPendingExtArgs{
+		Stream:   stream,
+		Group:    group,
+		Start:    msgs[0].ID,
+		End:      msgs[len(msgs)-1].ID,
+		Count:    int64(len(msgs)),
+		Consumer: r.config.ConsumerName,
+	}).Result()
+	if err != nil {
+		if r.ctx.Err() == nil {
+			slog.Error("Failed to read Redis stream delivery counts", "error", err, "stream", stream)
+		}
+		return counts
+	}
+	for _, entry := range pending {
+		counts[entry.ID] = entry.RetryCount
+	}
+	return counts
+}
+
+// deliver hands an entry of stream, delivered deliveries times, to the reader's
+// subscriptions and acknowledges it unless a synchronous handler failed. An entry
+// delivered more than MaxDeliveries times is dead-lettered or dropped instead.
+func (r *RedisStreamsEventBus) deliver(reader *redisStreamReader, stream string, msg redis.XMessage, deliveries int64) {
+	data, _ := msg.Values[redisStreamEventField].(string)
+	var event Event
+	if err := r.payload.decode([]byte(data), &event); err != nil {
+		// No consumer can handle the entry, so don't leave it pending
+		slog.Error("Failed to deserialize Redis stream entry", "error", err, "stream", stream, "id", msg.ID)
+		r.ack(reader, stream, msg.ID)
+		return
+	}
+	if deliveries > int64(r.config.MaxDeliveries) {
+		r.stopRedelivering(reader, stream, msg.ID, event, int(deliveries-1))
+		return
+	}
+
+	r.topicMutex.RLock()
+	subs := make([]*redisStreamSubscription, 0, len(reader.subscriptions))
+	for _, sub := range reader.subscriptions {
+		if !sub.isCancelled() {
+			subs = append(subs, sub)
+		}
+	}
+	r.topicMutex.RUnlock()
+
+	// Leave the entry pending for another consumer once unsubscribed
+	if len(subs) == 0 {
+		return
+	}
+
+	failed := false
+	for _, sub := range subs {
+		if sub.isAsync {
+			go r.processEvent(sub, event)
+			continue
+		}
+		if !r.processEvent(sub, event) {
+			failed = true
+		}
+	}
+	if !failed {
+		r.ack(reader, stream, msg.ID)
+	}
+}
+
+// stopRedelivering acknowledges an entry whose deliveries all failed, after
+// handing its event to the topic's dead-letter policy. If dead-lettering fails the
+// entry stays pending and is tried again on the next claim.
+func (r *RedisStreamsEventBus) stopRedelivering(reader *redisStreamReader, stream, id string, event Event, attempts int) {
+	err := fmt.Errorf("%w: %d deliveries of stream entry %s", ErrMaxDeliveriesExceeded, attempts, id)
+	if r.deadLetter != nil {
+		deadLettered, dlqErr := r.deadLetter(r.ctx, event, attempts, err)
+		if dlqErr != nil {
+			slog.Error("Failed to dead-letter Redis stream entry", "error", dlqErr, "stream", stream, "id", id)
+			return
+		}
+		if deadLettered {
+			r.ack(reader, stream, id)
+			return
+		}
+	}
+	slog.Error("Dropping Redis stream entry delivered too many times", "stream", stream, "id", id,
+		"topic", event.Type(), "deliveries", attempts)
+	r.ack(reader, stream, id)
+}
+
+// ack acknowledges a stream entry in the reader's consumer group.
+func (r *RedisStreamsEventBus) ack(reader *redisStreamReader, stream, id string) {
+	if err := r.client.XAck(r.ctx, stream, reader.group, id).Err(); err != nil && r.ctx.Err() == nil {
+		slog.Error("Failed to acknowledge Redis stream entry", "error", err, "stream", stream, "id", id)
+	}
+}
+
+// processEvent runs a subscription's handler, reporting whether it succeeded
+func (r *RedisStreamsEventBus) processEvent(sub *redisStreamSubscription, event Event) bool {
+	if err := sub.handler(r.ctx, event); err != nil {
+		slog.Error("Redis stream event handler failed", "error", err, "topic", event.Type())
+		return false
+	}
+	return true
+}
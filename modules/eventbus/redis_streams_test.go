@@ -0,0 +1,267 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRedisStreamsEventBus starts a streams mode Redis engine on server with
+// short timeouts, applying overrides to its config.
+func newTestRedisStreamsEventBus(t *testing.T, server *miniredis.Miniredis, overrides map[string]interface{}) *RedisStreamsEventBus {
+	t.Helper()
+	config := map[string]interface{}{
+		"url":           "redis://" + server.Addr(),
+		"mode":          RedisModeStreams,
+		"groupId":       "eventbus-test",
+		"blockTimeout":  "20ms",
+		"claimInterval": "20ms",
+	}
+	for key, value := range overrides {
+		config[key] = value
+	}
+
+	bus, err := NewRedisEventBus(config)
+	require.NoError(t, err)
+	streams, ok := bus.(*RedisStreamsEventBus)
+	require.True(t, ok, "mode streams should create a RedisStreamsEventBus")
+	require.NoError(t, streams.Start(context.Background()))
+	t.Cleanup(func() { _ = streams.Stop(context.Background()) })
+	return streams
+}
+
+// eventRecorder records the IDs of the events handed to its handler.
+type eventRecorder struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (r *eventRecorder) handle(_ context.Context, event Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, event.ID())
+	return nil
+}
+
+func (r *eventRecorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids...)
+}
+
+func publishTestEvents(t *testing.T, bus EventBus, topic string, count int) []string {
+	t.Helper()
+	ids := make([]string, 0, count)
+	for i := range count {
+		event := newTestCloudEvent(topic, i)
+		event.SetID(fmt.Sprintf("%s-%d", topic, i))
+		require.NoError(t, bus.Publish(context.Background(), event))
+		ids = append(ids, event.ID())
+	}
+	return ids
+}
+
+func TestRedisStreams_PublishSubscribe(t *testing.T) {
+	server := miniredis.RunT(t)
+	bus := newTestRedisStreamsEventBus(t, server, nil)
+
+	var recorder eventRecorder
+	sub, err := bus.Subscribe(context.Background(), "orders.created", recorder.handle)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"orders.created"}, bus.Topics())
+	assert.Equal(t, 1, bus.SubscriberCount("orders.created"))
+
+	ids := publishTestEvents(t, bus, "orders.created", 3)
+	require.Eventually(t, func() bool { return len(recorder.received()) == 3 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, ids, recorder.received())
+
+	// Every event was acknowledged
+	pending, err := bus.client.XPending(context.Background(), "eventbus:orders.created", bus.config.GroupID+":orders.created").Result()
+	require.NoError(t, err)
+	assert.Zero(t, pending.Count)
+
+	require.NoError(t, bus.Unsubscribe(context.Background(), sub))
+	assert.Empty(t, bus.Topics())
+}
+
+func TestRedisStreams_ConsumerGroups(t *testing.T) {
+	server := miniredis.RunT(t)
+	first := newTestRedisStreamsEventBus(t, server, map[string]interface{}{"groupId": "workers"})
+	second := newTestRedisStreamsEventBus(t, server, map[string]interface{}{"groupId": "workers"})
+	audit := newTestRedisStreamsEventBus(t, server, map[string]interface{}{"groupId": "audit"})
+
+	var firstRecorder, secondRecorder, auditRecorder eventRecorder
+	_, err := first.Subscribe(context.Background(), "jobs", firstRecorder.handle)
+	require.NoError(t, err)
+	_, err = second.Subscribe(context.Background(), "jobs", secondRecorder.handle)
+	require.NoError(t, err)
+	_, err = audit.Subscribe(context.Background(), "jobs", auditRecorder.handle)
+	require.NoError(t, err)
+
+	ids := publishTestEvents(t, first, "jobs", 20)
+
+	// The instances of a group split the events; other groups receive them all
+	require.Eventually(t, func() bool {
+		return len(firstRecorder.received())+len(secondRecorder.received()) == 20 && len(auditRecorder.received()) == 20
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, ids, append(firstRecorder.received(), secondRecorder.received()...))
+	assert.Equal(t, ids, auditRecorder.received())
+}
+
+func TestRedisStreams_MaxLenTrimsStreams(t *testing.T) {
+	server := miniredis.RunT(t)
+	bus := newTestRedisStreamsEventBus(t, server, map[string]interface{}{"maxLen": 5})
+
+	publishTestEvents(t, bus, "metrics", 12)
+
+	length, err := bus.client.XLen(context.Background(), "eventbus:metrics").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), length)
+}
+
+func TestRedisStreams_ClaimsPendingEventsAfterCrash(t *testing.T) {
+	server := miniredis.RunT(t)
+	config := map[string]interface{}{"groupId": "billing", "claimMinIdle": "50ms"}
+
+	// The first instance receives the event but never acknowledges it
+	crashed := newTestRedisStreamsEventBus(t, server, config)
+	received := make(chan struct{}, 1)
+	_, err := crashed.Subscribe(context.Background(), "invoices", func(context.Context, Event) error {
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		return errors.New("crashed")
+	})
+	require.NoError(t, err)
+	ids := publishTestEvents(t, crashed, "invoices", 1)
+	<-received
+	require.NoError(t, crashed.Stop(context.Background()))
+
+	// Events published while no instance runs stay in the stream
+	publisher := newTestRedisStreamsEventBus(t, server, nil)
+	later := publishTestEvents(t, publisher, "invoices", 1)
+
+	// A new instance of the group claims the pending event and resumes the group
+	recovered := newTestRedisStreamsEventBus(t, server, config)
+	var recorder eventRecorder
+	_, err = recovered.Subscribe(context.Background(), "invoices", recorder.handle)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return len(recorder.received()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{ids[0], later[0]}, recorder.received())
+}
+
+func TestRedisStreams_WildcardSubscriptionDiscoversStreams(t *testing.T) {
+	server := miniredis.RunT(t)
+	bus := newTestRedisStreamsEventBus(t, server, nil)
+
+	var recorder eventRecorder
+	_, err := bus.Subscribe(context.Background(), "users.*", recorder.handle)
+	require.NoError(t, err)
+
+	created := publishTestEvents(t, bus, "users.created", 1)
+	deleted := publishTestEvents(t, bus, "users.deleted", 1)
+	publishTestEvents(t, bus, "orders.created", 1)
+
+	require.Eventually(t, func() bool { return len(recorder.received()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{created[0], deleted[0]}, recorder.received())
+}
+
+func TestRedisStreams_StopsRedeliveringAfterMaxDeliveries(t *testing.T) {
+	server := miniredis.RunT(t)
+	bus := newTestRedisStreamsEventBus(t, server, map[string]interface{}{"claimMinIdle": "10ms", "maxDeliveries": 3})
+
+	type deadLetter struct {
+		event    Event
+		attempts int
+		err      error
+	}
+	deadLetters := make(chan deadLetter, 2)
+	bus.deadLetter = func(_ context.Context, event Event, attempts int, err error) (bool, error) {
+		deadLetters <- deadLetter{event: event, attempts: attempts, err: err}
+		return event.Type() == "payments", nil
+	}
+
+	var mu sync.Mutex
+	deliveries := make(map[string]int)
+	failing := func(_ context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		deliveries[event.Type()]++
+		return errors.New("always fails")
+	}
+	_, err := bus.Subscribe(context.Background(), "payments", failing)
+	require.NoError(t, err)
+	_, err = bus.Subscribe(context.Background(), "receipts", failing)
+	require.NoError(t, err)
+	payment := publishTestEvents(t, bus, "payments", 1)
+	publishTestEvents(t, bus, "receipts", 1)
+
+	// Both entries are handed over once their deliveries are used up: the one whose
+	// topic has a dead-letter policy is dead-lettered, the other one dropped
+	var got []deadLetter
+	for range 2 {
+		select {
+		case dl := <-deadLetters:
+			got = append(got, dl)
+		case <-time.After(2 * time.Second):
+			t.Fatal("entries delivered too many times were not handed to the dead-letter policy")
+		}
+	}
+	for _, dl := range got {
+		assert.Equal(t, 3, dl.attempts)
+		require.ErrorIs(t, dl.err, ErrMaxDeliveriesExceeded)
+		if dl.event.Type() == "payments" {
+			assert.Equal(t, payment[0], dl.event.ID())
+		}
+	}
+	mu.Lock()
+	assert.Equal(t, map[string]int{"payments": 3, "receipts": 3}, deliveries)
+	mu.Unlock()
+
+	for _, topic := range []string{"payments", "receipts"} {
+		require.Eventually(t, func() bool {
+			pending, err := bus.client.XPending(context.Background(), "eventbus:"+topic, bus.config.GroupID+":"+topic).Result()
+			return err == nil && pending.Count == 0
+		}, time.Second, 10*time.Millisecond, "entry of %s is acknowledged", topic)
+	}
+}
+
+func TestRedisConfig_Streams(t *testing.T) {
+	config, err := parseRedisConfig(map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, RedisModePubSub, config.Mode)
+	assert.Equal(t, "eventbus:", config.StreamPrefix)
+	assert.Equal(t, 30*time.Second, config.ClaimMinIdle)
+	assert.Equal(t, 5, config.MaxDeliveries)
+
+	config, err = parseRedisConfig(map[string]interface{}{
+		"mode":         RedisModeStreams,
+		"groupId":      "workers",
+		"maxLen":       1000,
+		"claimMinIdle": "1m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "workers", config.GroupID)
+	assert.Equal(t, int64(1000), config.MaxLen)
+	assert.Equal(t, time.Minute, config.ClaimMinIdle)
+
+	_, err = parseRedisConfig(map[string]interface{}{"mode": RedisModeStreams})
+	require.ErrorIs(t, err, ErrInvalidRedisConfig, "streams mode requires a groupId")
+	_, err = parseRedisConfig(map[string]interface{}{"maxDeliveries": 0})
+	require.ErrorIs(t, err, ErrInvalidRedisConfig)
+	_, err = parseRedisConfig(map[string]interface{}{"mode": "lists"})
+	require.ErrorIs(t, err, ErrInvalidRedisConfig)
+	_, err = parseRedisConfig(map[string]interface{}{"claimInterval": "soon"})
+	require.ErrorIs(t, err, ErrInvalidRedisConfig)
+	_, err = parseRedisConfig(map[string]interface{}{"maxLen": -1})
+	require.ErrorIs(t, err, ErrInvalidRedisConfig)
+}
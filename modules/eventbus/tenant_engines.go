@@ -0,0 +1,318 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// tenantEngines tracks the engines of a tenant registered with the tenant service.
+// A tenant whose "eventbus" tenant config section is set gets dedicated engines,
+// configured by that section like the application's eventbus config. Other tenants
+// use the shared engines.
+type tenantEngines struct {
+	mu       sync.Mutex
+	resolved bool            // the tenant config section was looked up
+	config   *EventBusConfig // nil when the tenant uses the shared engines
+	router   *EngineRouter   // the running dedicated engines
+	removed  atomic.Bool     // the tenant was removed from the tenant service
+
+	// IDs of the subscriptions made on the dedicated engines, which engines don't tell
+	// apart from subscriptions made on other engines of the same type
+	subscriptions map[string]bool
+}
+
+// track records a subscription made on the tenant's dedicated engines. It does
+// nothing for subscriptions on the shared engines, which have no tenant.
+func (t *tenantEngines) track(subscription Subscription) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.subscriptions == nil {
+		t.subscriptions = make(map[string]bool)
+	}
+	t.subscriptions[subscription.ID()] = true
+}
+
+// tenantEngineConfig returns the validated engine config of the tenant's "eventbus"
+// tenant config section, or nil when the tenant has no such section.
+func (m *EventBusModule) tenantEngineConfig(tenantID modular.TenantID) (*EventBusConfig, error) {
+	if m.tenantService == nil {
+		return nil, nil
+	}
+	provider, err := m.tenantService.GetTenantConfig(tenantID, m.name)
+	if errors.Is(err, modular.ErrTenantNotFound) || errors.Is(err, modular.ErrTenantConfigNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: tenant %s: %w", ErrInvalidTenantEngineConfig, tenantID, err)
+	}
+	base, ok := provider.GetConfig().(*EventBusConfig)
+	if !ok {
+		return nil, fmt.Errorf("%w: tenant %s: unexpected config type %T", ErrInvalidTenantEngineConfig, tenantID, provider.GetConfig())
+	}
+
+	// Validation fills in defaults, which must not leak into the tenant service's copy
+	config := *base
+	if len(config.Bridges) > 0 {
+		return nil, fmt.Errorf("%w: tenant %s: bridges are only supported in the application config", ErrInvalidTenantEngineConfig, tenantID)
+	}
+	if err := config.ValidateConfig(); err != nil {
+		return nil, fmt.Errorf("%w: tenant %s: %w", ErrInvalidTenantEngineConfig, tenantID, err)
+	}
+	return &config, nil
+}
+
+// routerFor returns the router handling publishes and subscriptions made with ctx. A
+// tenant context of a tenant with dedicated engines gets the tenant's router, and
+// never falls back to the shared engines: if the tenant's engines are unavailable the
+// error is returned instead. Every other context gets the shared router, and no
+// tenant.
+func (m *EventBusModule) routerFor(ctx context.Context) (*EngineRouter, *tenantEngines, error) {
+	tenantID, ok := modular.GetTenantIDFromContext(ctx)
+	if !ok {
+		return m.router, nil, nil
+	}
+	m.tenantMutex.RLock()
+	tenant := m.tenants[tenantID]
+	m.tenantMutex.RUnlock()
+	if tenant == nil {
+		return m.router, nil, nil
+	}
+
+	router, err := m.tenantRouter(ctx, tenantID, tenant)
+	if err != nil {
+		return nil, nil, err
+	}
+	if router == nil {
+		return m.router, nil, nil
+	}
+	return router, tenant, nil
+}
+
+// tenantRouter returns the router of the tenant's dedicated engines, starting them on
+// first use, or nil when the tenant uses the shared engines.
+func (m *EventBusModule) tenantRouter(ctx context.Context, tenantID modular.TenantID, tenant *tenantEngines) (*EngineRouter, error) {
+	tenant.mu.Lock()
+	defer tenant.mu.Unlock()
+
+	if tenant.removed.Load() {
+		return nil, fmt.Errorf("%w: tenant %s was removed", ErrTenantEnginesUnavailable, tenantID)
+	}
+	if !tenant.resolved {
+		config, err := m.tenantEngineConfig(tenantID)
+		if err != nil {
+			return nil, err
+		}
+		tenant.config, tenant.resolved = config, true
+	}
+	if tenant.config == nil {
+		return nil, nil
+	}
+	if tenant.router != nil {
+		return tenant.router, nil
+	}
+
+	m.tenantMutex.RLock()
+	active := m.tenantsActive
+	m.tenantMutex.RUnlock()
+	if !active {
+		return nil, fmt.Errorf("%w: tenant %s: %w", ErrTenantEnginesUnavailable, tenantID, ErrEventBusNotStarted)
+	}
+
+	router, err := NewEngineRouter(tenant.config)
+	if err == nil {
+		router.SetModuleReference(m)
+		if err = router.Start(context.WithoutCancel(ctx)); err != nil {
+			// Close the engines that did start
+			_ = router.Stop(context.WithoutCancel(ctx))
+		}
+	}
+	if err != nil {
+		m.logger.Error("Failed to start tenant engines", "tenant", tenantID, "error", err)
+		go m.emitEvent(ctx, EventTypeTenantEnginesFailed, map[string]interface{}{
+			"tenant": string(tenantID),
+			"error":  err.Error(),
+		})
+		return nil, fmt.Errorf("%w: tenant %s: %w", ErrTenantEnginesUnavailable, tenantID, err)
+	}
+
+	tenant.router = router
+	m.logger.Info("Started tenant engines", "tenant", tenantID, "engines", router.GetEngineNames())
+	go m.emitEvent(ctx, EventTypeTenantEnginesStarted, map[string]interface{}{
+		"tenant":  string(tenantID),
+		"engines": router.GetEngineNames(),
+	})
+	return router, nil
+}
+
+// stopTenantRouter stops the tenant's dedicated engines, if running.
+func (m *EventBusModule) stopTenantRouter(ctx context.Context, tenantID modular.TenantID, tenant *tenantEngines, reason string) {
+	tenant.mu.Lock()
+	router := tenant.router
+	tenant.router = nil
+	tenant.mu.Unlock()
+	if router == nil {
+		return
+	}
+
+	if err := router.Stop(ctx); err != nil {
+		m.logger.Warn("Failed to stop tenant engines", "tenant", tenantID, "error", err)
+	}
+	m.logger.Info("Stopped tenant engines", "tenant", tenantID, "reason", reason)
+	go m.emitEvent(ctx, EventTypeTenantEnginesStopped, map[string]interface{}{
+		"tenant": string(tenantID),
+		"reason": reason,
+	})
+}
+
+// startTenants allows tenant engines to run and starts those of the registered
+// tenants. A tenant whose engines fail to start doesn't fail the module: its
+// publishes and subscriptions return the error, and retry starting them.
+func (m *EventBusModule) startTenants(ctx context.Context) {
+	m.tenantMutex.Lock()
+	m.tenantsActive = true
+	tenants := make(map[modular.TenantID]*tenantEngines, len(m.tenants))
+	for tenantID, tenant := range m.tenants {
+		tenants[tenantID] = tenant
+	}
+	m.tenantMutex.Unlock()
+
+	for tenantID, tenant := range tenants {
+		if _, err := m.tenantRouter(ctx, tenantID, tenant); err != nil {
+			m.logger.Warn("Tenant engines unavailable", "tenant", tenantID, "error", err)
+		}
+	}
+}
+
+// stopTenants stops the engines of every tenant, including those being removed.
+func (m *EventBusModule) stopTenants(ctx context.Context) {
+	m.tenantMutex.Lock()
+	m.tenantsActive = false
+	tenants := make(map[modular.TenantID]*tenantEngines, len(m.tenants))
+	for tenantID, tenant := range m.tenants {
+		tenants[tenantID] = tenant
+	}
+	m.tenantMutex.Unlock()
+
+	for tenantID, tenant := range tenants {
+		m.stopTenantRouter(ctx, tenantID, tenant, "module stopped")
+	}
+	m.tenantStops.Wait()
+}
+
+// OnTenantRegistered implements modular.TenantAwareModule. The tenant's config is
+// looked up when the module starts or the tenant first publishes or subscribes, as
+// the tenant service holds its lock while notifying modules.
+func (m *EventBusModule) OnTenantRegistered(tenantID modular.TenantID) {
+	m.tenantMutex.Lock()
+	defer m.tenantMutex.Unlock()
+	if m.tenants == nil {
+		m.tenants = make(map[modular.TenantID]*tenantEngines)
+	}
+	if tenant, exists := m.tenants[tenantID]; !exists || tenant.removed.Load() {
+		m.tenants[tenantID] = &tenantEngines{}
+	}
+}
+
+// OnTenantRemoved implements modular.TenantAwareModule. The tenant's dedicated
+// engines are stopped in the background, and its publishes and subscriptions fail
+// from now on instead of reaching the shared engines.
+func (m *EventBusModule) OnTenantRemoved(tenantID modular.TenantID) {
+	m.tenantMutex.RLock()
+	tenant := m.tenants[tenantID]
+	m.tenantMutex.RUnlock()
+	if tenant == nil {
+		return
+	}
+	tenant.removed.Store(true)
+	tenant.mu.Lock()
+	dedicated := tenant.config != nil
+	tenant.mu.Unlock()
+	if !dedicated {
+		m.tenantMutex.Lock()
+		if m.tenants[tenantID] == tenant {
+			delete(m.tenants, tenantID)
+		}
+		m.tenantMutex.Unlock()
+		return
+	}
+
+	// The removed tenant is kept, so its events can't reach the shared engines
+	m.tenantStops.Add(1)
+	go func() {
+		defer m.tenantStops.Done()
+		m.stopTenantRouter(context.Background(), tenantID, tenant, "tenant removed")
+	}()
+}
+
+// GetTenantRouter returns the router of the tenant's running dedicated engines, or
+// nil when the tenant uses the shared engines or its engines aren't running.
+func (m *EventBusModule) GetTenantRouter(tenantID modular.TenantID) *EngineRouter {
+	m.tenantMutex.RLock()
+	tenant := m.tenants[tenantID]
+	m.tenantMutex.RUnlock()
+	if tenant == nil {
+		return nil
+	}
+	tenant.mu.Lock()
+	defer tenant.mu.Unlock()
+	return tenant.router
+}
+
+// TenantsWithDedicatedEngines returns the tenants whose dedicated engines are running,
+// sorted.
+func (m *EventBusModule) TenantsWithDedicatedEngines() []modular.TenantID {
+	m.tenantMutex.RLock()
+	tenants := make(map[modular.TenantID]*tenantEngines, len(m.tenants))
+	for tenantID, tenant := range m.tenants {
+		tenants[tenantID] = tenant
+	}
+	m.tenantMutex.RUnlock()
+
+	running := make([]modular.TenantID, 0, len(tenants))
+	for tenantID, tenant := range tenants {
+		tenant.mu.Lock()
+		if tenant.router != nil {
+			running = append(running, tenantID)
+		}
+		tenant.mu.Unlock()
+	}
+	sort.Slice(running, func(i, j int) bool { return running[i] < running[j] })
+	return running
+}
+
+// unsubscribe removes a subscription from the engines it was made on.
+func (m *EventBusModule) unsubscribe(ctx context.Context, subscription Subscription) error {
+	m.tenantMutex.RLock()
+	tenants := make([]*tenantEngines, 0, len(m.tenants))
+	for _, tenant := range m.tenants {
+		tenants = append(tenants, tenant)
+	}
+	m.tenantMutex.RUnlock()
+
+	id := subscription.ID()
+	for _, tenant := range tenants {
+		tenant.mu.Lock()
+		owned := tenant.subscriptions[id]
+		delete(tenant.subscriptions, id)
+		router := tenant.router
+		tenant.mu.Unlock()
+		if !owned {
+			continue
+		}
+		if router == nil {
+			// The subscription ended with the tenant's engines
+			return ErrSubscriptionNotFound
+		}
+		return router.Unsubscribe(ctx, subscription)
+	}
+	return m.router.Unsubscribe(ctx, subscription)
+}
@@ -0,0 +1,213 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTenantTestModule creates a single-engine module notified of the tenants of a
+// standard tenant service.
+func newTenantTestModule(t *testing.T) (*EventBusModule, *modular.StandardTenantService, *recordingSubject) {
+	t.Helper()
+
+	config := &EventBusConfig{Engine: "memory"}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	subject := &recordingSubject{}
+	tenants := modular.NewStandardTenantService(&mockLogger{})
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}, subject: subject, tenantService: tenants}
+	router.SetModuleReference(m)
+	require.NoError(t, tenants.RegisterTenantAwareModule(m))
+	return m, tenants, subject
+}
+
+// registerTenantEngines registers a tenant whose eventbus section configures config.
+func registerTenantEngines(t *testing.T, tenants *modular.StandardTenantService, tenantID modular.TenantID, config any) {
+	t.Helper()
+	require.NoError(t, tenants.RegisterTenant(tenantID, map[string]modular.ConfigProvider{
+		ModuleName: modular.NewStdConfigProvider(config),
+	}))
+}
+
+// receivedPayloads subscribes to topic with ctx and returns the "from" field of the
+// events received.
+func receivedPayloads(t *testing.T, m *EventBusModule, ctx context.Context, topic string) func() []string {
+	t.Helper()
+	var mu sync.Mutex
+	var received []string
+	_, err := m.Subscribe(ctx, topic, func(ctx context.Context, event Event) error {
+		var data map[string]string
+		if err := event.DataAs(&data); err != nil {
+			return err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, data["from"])
+		return nil
+	})
+	require.NoError(t, err)
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func tenantCtx(tenantID modular.TenantID) context.Context {
+	return modular.NewTenantContext(context.Background(), tenantID)
+}
+
+func TestTenantEngines_Isolation(t *testing.T) {
+	m, tenants, subject := newTenantTestModule(t)
+	registerTenantEngines(t, tenants, "dedicated", &EventBusConfig{Engine: "memory"})
+	require.NoError(t, tenants.RegisterTenant("shared", nil))
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	assert.Equal(t, []modular.TenantID{"dedicated"}, m.TenantsWithDedicatedEngines())
+	require.NotNil(t, m.GetTenantRouter("dedicated"))
+	assert.Nil(t, m.GetTenantRouter("shared"))
+	require.Eventually(t, func() bool { return subject.has(EventTypeTenantEnginesStarted) }, time.Second, 5*time.Millisecond)
+
+	onShared := receivedPayloads(t, m, ctx, "orders.created")
+	onSharedTenant := receivedPayloads(t, m, tenantCtx("shared"), "orders.created")
+	onDedicated := receivedPayloads(t, m, tenantCtx("dedicated"), "orders.created")
+	assert.Equal(t, 2, m.SubscriberCount("orders.created"), "the dedicated tenant's subscription is not on the shared engines")
+
+	require.NoError(t, m.Publish(tenantCtx("dedicated"), "orders.created", map[string]string{"from": "dedicated"}))
+	require.NoError(t, m.Publish(tenantCtx("shared"), "orders.created", map[string]string{"from": "shared"}))
+	require.NoError(t, m.Publish(ctx, "orders.created", map[string]string{"from": "app"}))
+
+	require.Eventually(t, func() bool { return len(onShared()) == 2 && len(onDedicated()) == 1 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.ElementsMatch(t, []string{"shared", "app"}, onShared())
+	assert.ElementsMatch(t, []string{"shared", "app"}, onSharedTenant())
+	assert.Equal(t, []string{"dedicated"}, onDedicated())
+}
+
+func TestTenantEngines_Lifecycle(t *testing.T) {
+	m, tenants, subject := newTenantTestModule(t)
+	registerTenantEngines(t, tenants, "acme", &EventBusConfig{Engine: "memory"})
+
+	// Tenant engines only run while the module is started
+	require.ErrorIs(t, m.Publish(tenantCtx("acme"), "orders.created", nil), ErrTenantEnginesUnavailable)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	router := m.GetTenantRouter("acme")
+	require.NotNil(t, router)
+
+	// Tenants registered later start their engines on first use
+	registerTenantEngines(t, tenants, "globex", &EventBusConfig{Engine: "memory"})
+	assert.Nil(t, m.GetTenantRouter("globex"))
+	require.NoError(t, m.Publish(tenantCtx("globex"), "orders.created", nil))
+	assert.NotNil(t, m.GetTenantRouter("globex"))
+
+	// Removing a tenant stops its engines, and its events don't fall back to the shared engines
+	sub, err := m.Subscribe(tenantCtx("acme"), "orders.created", func(context.Context, Event) error { return nil })
+	require.NoError(t, err)
+	require.NoError(t, tenants.RemoveTenant("acme"))
+	require.ErrorIs(t, m.Publish(tenantCtx("acme"), "orders.created", nil), ErrTenantEnginesUnavailable)
+	require.Eventually(t, func() bool { return subject.has(EventTypeTenantEnginesStopped) }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []modular.TenantID{"globex"}, m.TenantsWithDedicatedEngines())
+	assert.ErrorIs(t, m.Unsubscribe(ctx, sub), ErrSubscriptionNotFound)
+
+	// Unsubscribing finds subscriptions on a tenant's engines
+	sub, err = m.Subscribe(tenantCtx("globex"), "orders.created", func(context.Context, Event) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, m.GetTenantRouter("globex").SubscriberCount("orders.created"))
+	require.NoError(t, m.Unsubscribe(ctx, sub))
+	assert.Zero(t, m.GetTenantRouter("globex").SubscriberCount("orders.created"))
+
+	require.NoError(t, m.Stop(ctx))
+	assert.Empty(t, m.TenantsWithDedicatedEngines())
+
+	// Restarting the module restarts the engines of registered tenants
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	assert.Equal(t, []modular.TenantID{"globex"}, m.TenantsWithDedicatedEngines())
+}
+
+func TestTenantEngines_MultiEngineOverride(t *testing.T) {
+	m, tenants, _ := newTenantTestModule(t)
+	registerTenantEngines(t, tenants, "acme", &EventBusConfig{
+		Engines: []EngineConfig{{Name: "acme-fast", Type: "memory"}, {Name: "acme-durable", Type: "durable-memory"}},
+		Routing: []RoutingRule{{Topics: []string{"billing.*"}, Engine: "acme-durable"}},
+	})
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	router := m.GetTenantRouter("acme")
+	require.NotNil(t, router)
+	assert.ElementsMatch(t, []string{"acme-fast", "acme-durable"}, router.GetEngineNames())
+	assert.Equal(t, "acme-durable", router.GetEngineForTopic("billing.charged"))
+
+	received := receivedPayloads(t, m, tenantCtx("acme"), "billing.charged")
+	require.NoError(t, m.Publish(tenantCtx("acme"), "billing.charged", map[string]string{"from": "acme"}))
+	require.Eventually(t, func() bool { return len(received()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Zero(t, m.SubscriberCount("billing.charged"))
+}
+
+func TestTenantEngines_InvalidConfigFailsClosed(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  any
+		wantErr error
+	}{
+		{"wrong config type", &struct{ Engine string }{Engine: "memory"}, ErrInvalidTenantEngineConfig},
+		{"bridges", &EventBusConfig{
+			Engines: []EngineConfig{{Name: "a", Type: "memory"}, {Name: "b", Type: "memory"}},
+			Bridges: []BridgeRule{{Name: "x", From: "a", To: "b", Topics: []string{"x.*"}}},
+		}, ErrInvalidTenantEngineConfig},
+		{"invalid routing", &EventBusConfig{
+			Engines: []EngineConfig{{Name: "a", Type: "memory"}},
+			Routing: []RoutingRule{{Topics: []string{"x.*"}, Engine: "b"}},
+		}, ErrUnknownEngineRef},
+		{"engine fails to start", &EventBusConfig{Engines: []EngineConfig{{Name: "a", Type: "missing"}}}, ErrTenantEnginesUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, tenants, subject := newTenantTestModule(t)
+			registerTenantEngines(t, tenants, "acme", tt.config)
+			ctx := context.Background()
+			require.NoError(t, m.Start(ctx), "a tenant's engines don't fail the module")
+			t.Cleanup(func() { _ = m.Stop(ctx) })
+
+			onShared := receivedPayloads(t, m, ctx, "x.created")
+			require.ErrorIs(t, m.Publish(tenantCtx("acme"), "x.created", map[string]string{"from": "acme"}), tt.wantErr)
+			_, err := m.Subscribe(tenantCtx("acme"), "x.created", func(context.Context, Event) error { return nil })
+			require.ErrorIs(t, err, tt.wantErr)
+
+			time.Sleep(20 * time.Millisecond)
+			assert.Empty(t, onShared())
+			if tt.wantErr == ErrTenantEnginesUnavailable {
+				assert.True(t, subject.has(EventTypeTenantEnginesFailed))
+			}
+		})
+	}
+}
+
+func TestTenantEngines_WithoutTenantService(t *testing.T) {
+	m, _, _ := newTenantTestModule(t)
+	m.tenantService = nil
+	m.OnTenantRegistered("acme")
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+
+	received := receivedPayloads(t, m, ctx, "orders.created")
+	require.NoError(t, m.Publish(tenantCtx("acme"), "orders.created", map[string]string{"from": "acme"}))
+	require.Eventually(t, func() bool { return len(received()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, m.TenantsWithDedicatedEngines())
+}
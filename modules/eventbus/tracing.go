@@ -0,0 +1,171 @@
+package eventbus
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceParentExtension and TraceStateExtension are the CloudEvents distributed tracing
+// extensions carrying the W3C trace context of the span that published an event. They
+// travel with the event through every engine, so handlers continue the publisher's
+// trace across async boundaries.
+const (
+	TraceParentExtension = "traceparent"
+	TraceStateExtension  = "tracestate"
+)
+
+// tracerName is the instrumentation scope of the publish and process spans.
+const tracerName = "github.com/CrisisTextLine/modular/modules/eventbus/v2"
+
+// SetTracerProvider sets the provider of the tracer creating publish and process spans.
+// By default the global provider is used, so spans are recorded once an SDK is
+// installed with otel.SetTracerProvider; without one the trace context is still
+// propagated.
+func (m *EventBusModule) SetTracerProvider(provider trace.TracerProvider) {
+	m.tracingMutex.Lock()
+	defer m.tracingMutex.Unlock()
+	m.tracerProvider = provider
+}
+
+// SetTextMapPropagator sets the propagator injecting trace context into events on
+// publish and extracting it on delivery. By default W3C trace context is propagated
+// regardless of the global propagator. Keys are stored as CloudEvents extensions,
+// which are lowercase alphanumeric, so other keys are normalized to that form.
+func (m *EventBusModule) SetTextMapPropagator(propagator propagation.TextMapPropagator) {
+	m.tracingMutex.Lock()
+	defer m.tracingMutex.Unlock()
+	m.propagator = propagator
+}
+
+// tracing returns the tracer and propagator to use, or false when tracing is disabled.
+func (m *EventBusModule) tracing() (trace.Tracer, propagation.TextMapPropagator, bool) {
+	if m.config != nil && m.config.DisableTracing {
+		return nil, nil, false
+	}
+	m.tracingMutex.RLock()
+	provider, propagator := m.tracerProvider, m.propagator
+	m.tracingMutex.RUnlock()
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	if propagator == nil {
+		propagator = propagation.TraceContext{}
+	}
+	return provider.Tracer(tracerName), propagator, true
+}
+
+// startPublishSpan starts the "<topic> publish" span for event and injects its trace
+// context into the event. When ctx carries no span, a trace context the event already
+// carries is continued. The returned function ends the span with the publish result.
+func (m *EventBusModule) startPublishSpan(ctx context.Context, event *Event) (context.Context, func(error)) {
+	tracer, propagator, ok := m.tracing()
+	if !ok {
+		return ctx, func(error) {}
+	}
+	carrier := eventCarrier{event: event}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = propagator.Extract(ctx, carrier)
+	}
+	ctx, span := tracer.Start(ctx, event.Type()+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(m.spanAttributes(*event, "publish")...),
+	)
+	propagator.Inject(ctx, carrier)
+	return ctx, func(err error) { endSpan(span, err) }
+}
+
+// tracingHandler wraps handler so it runs in a "<topic> process" span continuing the
+// trace context carried by the event. Whether the span is sampled follows the tracer
+// provider's sampler, which for the default parent-based samplers respects the
+// publisher's sampling decision.
+func (m *EventBusModule) tracingHandler(handler EventHandler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		tracer, propagator, ok := m.tracing()
+		if !ok {
+			return handler(ctx, event)
+		}
+		ctx = propagator.Extract(ctx, eventCarrier{event: &event})
+		ctx, span := tracer.Start(ctx, event.Type()+" process",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(m.spanAttributes(event, "process")...),
+		)
+		err := handler(ctx, event)
+		endSpan(span, err)
+		return err
+	}
+}
+
+// spanAttributes returns the messaging attributes of a span for event.
+func (m *EventBusModule) spanAttributes(event Event, operation string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("messaging.system", ModuleName),
+		attribute.String("messaging.operation", operation),
+		attribute.String("messaging.destination.name", event.Type()),
+		attribute.String("messaging.message.id", event.ID()),
+	}
+	if m.router != nil {
+		attrs = append(attrs, attribute.String("eventbus.engine", m.router.GetEngineForTopic(event.Type())))
+	}
+	return attrs
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// eventCarrier adapts the extensions of an event to a propagation.TextMapCarrier.
+type eventCarrier struct {
+	event *Event
+}
+
+// Get returns the extension for key as a string.
+func (c eventCarrier) Get(key string) string {
+	value, ok := c.event.Extensions()[extensionName(key)]
+	if !ok {
+		return ""
+	}
+	s, _ := value.(string)
+	return s
+}
+
+// Set stores value as the extension for key.
+func (c eventCarrier) Set(key, value string) {
+	if name := extensionName(key); name != "" {
+		c.event.SetExtension(name, value)
+	}
+}
+
+// Keys returns the names of the event's extensions.
+func (c eventCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.event.Extensions()))
+	for key := range c.event.Extensions() {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// extensionName converts a propagation key to a valid CloudEvents extension name by
+// lowercasing it and dropping characters other than letters and digits.
+func extensionName(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return -1
+		}
+	}, key)
+}
@@ -0,0 +1,209 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracingTestModule creates a started single-engine module recording its spans.
+func newTracingTestModule(t *testing.T, config *EventBusConfig, sampler sdktrace.Sampler) (*EventBusModule, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+	router.SetModuleReference(m)
+
+	recorder := tracetest.NewSpanRecorder()
+	m.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler), sdktrace.WithSpanProcessor(recorder)))
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	return m, recorder
+}
+
+// findSpan returns the ended span named name.
+func findSpan(t *testing.T, recorder *tracetest.SpanRecorder, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range recorder.Ended() {
+		if span.Name() == name {
+			return span
+		}
+	}
+	t.Fatalf("span %q not recorded", name)
+	return nil
+}
+
+// awaitSpanContext waits for a handler to report the span context it ran in.
+func awaitSpanContext(t *testing.T, handled <-chan trace.SpanContext) trace.SpanContext {
+	t.Helper()
+	select {
+	case sc := <-handled:
+		return sc
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler not called")
+		return trace.SpanContext{}
+	}
+}
+
+func TestTracing_PublishAndProcessSpans(t *testing.T) {
+	for _, engine := range []string{"memory", "durable-memory"} {
+		t.Run(engine, func(t *testing.T) {
+			m, recorder := newTracingTestModule(t, &EventBusConfig{Engine: engine, WorkerCount: 1}, sdktrace.AlwaysSample())
+			ctx := context.Background()
+
+			handled := make(chan trace.SpanContext, 1)
+			_, err := m.SubscribeAsync(ctx, "orders.created", func(ctx context.Context, event Event) error {
+				handled <- trace.SpanContextFromContext(ctx)
+				return errors.New("boom")
+			})
+			require.NoError(t, err)
+
+			parentCtx, parent := m.tracerProvider.Tracer("test").Start(ctx, "request")
+			require.NoError(t, m.Publish(parentCtx, "orders.created", map[string]string{"id": "1"}))
+			parent.End()
+
+			handlerSpan := awaitSpanContext(t, handled)
+			require.Eventually(t, func() bool { return len(recorder.Ended()) == 3 }, 2*time.Second, 10*time.Millisecond)
+
+			publish := findSpan(t, recorder, "orders.created publish")
+			process := findSpan(t, recorder, "orders.created process")
+			assert.Equal(t, trace.SpanKindProducer, publish.SpanKind())
+			assert.Equal(t, trace.SpanKindConsumer, process.SpanKind())
+			assert.Equal(t, parent.SpanContext().SpanID(), publish.Parent().SpanID())
+			assert.Equal(t, publish.SpanContext().SpanID(), process.Parent().SpanID())
+			assert.Equal(t, parent.SpanContext().TraceID(), process.SpanContext().TraceID())
+			assert.Equal(t, process.SpanContext().SpanID(), handlerSpan.SpanID())
+			assert.Equal(t, codes.Error, process.Status().Code)
+			assert.Contains(t, publish.Attributes(), attribute.String("messaging.destination.name", "orders.created"))
+			assert.Contains(t, process.Attributes(), attribute.String("messaging.operation", "process"))
+		})
+	}
+}
+
+func TestTracing_RespectsSampling(t *testing.T) {
+	m, recorder := newTracingTestModule(t, &EventBusConfig{Engine: "memory"}, sdktrace.ParentBased(sdktrace.AlwaysSample()))
+	ctx := context.Background()
+
+	handled := make(chan trace.SpanContext, 1)
+	_, err := m.Subscribe(ctx, "orders.created", func(ctx context.Context, event Event) error {
+		handled <- trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// A remote parent that was not sampled, as received from an upstream service
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+		Remote:  true,
+	})
+	require.NoError(t, m.Publish(trace.ContextWithRemoteSpanContext(ctx, remote), "orders.created", nil))
+
+	handlerSpan := awaitSpanContext(t, handled)
+	assert.Equal(t, remote.TraceID(), handlerSpan.TraceID())
+	assert.False(t, handlerSpan.IsSampled())
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestTracing_BrokerRoundTrip(t *testing.T) {
+	m, recorder := newTracingTestModule(t, &EventBusConfig{Engine: "memory"}, sdktrace.AlwaysSample())
+	ctx, parent := m.tracerProvider.Tracer("test").Start(context.Background(), "request")
+	defer parent.End()
+
+	// Broker engines serialize events, so the trace context must survive encoding
+	event := newTestCloudEvent("orders.created", nil)
+	ctx, finish := m.startPublishSpan(ctx, &event)
+	finish(nil)
+	data, err := (*payloadCodec)(nil).encode(event)
+	require.NoError(t, err)
+	var decoded Event
+	require.NoError(t, (*payloadCodec)(nil).decode(data, &decoded))
+
+	var handlerSpan trace.SpanContext
+	handler := m.tracingHandler(func(ctx context.Context, event Event) error {
+		handlerSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+	require.NoError(t, handler(context.Background(), decoded))
+
+	publish := findSpan(t, recorder, "orders.created publish")
+	assert.Equal(t, trace.SpanContextFromContext(ctx), publish.SpanContext())
+	assert.Equal(t, parent.SpanContext().TraceID(), handlerSpan.TraceID())
+	assert.Equal(t, publish.SpanContext().SpanID(), findSpan(t, recorder, "orders.created process").Parent().SpanID())
+}
+
+func TestTracing_ContinuesEventTraceContext(t *testing.T) {
+	m, recorder := newTracingTestModule(t, &EventBusConfig{Engine: "memory"}, sdktrace.AlwaysSample())
+
+	event := newTestCloudEvent("orders.created", nil)
+	event.SetExtension(TraceParentExtension, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.NoError(t, m.PublishCloudEvent(context.Background(), event))
+
+	publish := findSpan(t, recorder, "orders.created publish")
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", publish.SpanContext().TraceID().String())
+	assert.Equal(t, "b7ad6b7169203331", publish.Parent().SpanID().String())
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", event.Extensions()[TraceParentExtension],
+		"the caller's event must not be modified")
+}
+
+func TestTracing_Disabled(t *testing.T) {
+	m, recorder := newTracingTestModule(t, &EventBusConfig{Engine: "memory", DisableTracing: true}, sdktrace.AlwaysSample())
+	ctx, parent := m.tracerProvider.Tracer("test").Start(context.Background(), "request")
+	defer parent.End()
+
+	received := make(chan Event, 1)
+	_, err := m.Subscribe(ctx, "orders.created", func(ctx context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.Publish(ctx, "orders.created", nil))
+
+	event := <-received
+	assert.NotContains(t, event.Extensions(), TraceParentExtension)
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestTracing_CustomPropagator(t *testing.T) {
+	m, _ := newTracingTestModule(t, &EventBusConfig{Engine: "memory"}, sdktrace.AlwaysSample())
+	m.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	member, err := baggage.NewMember("tenant", "acme")
+	require.NoError(t, err)
+	bag, err := baggage.New(member)
+	require.NoError(t, err)
+
+	event := newTestCloudEvent("orders.created", nil)
+	_, finish := m.startPublishSpan(baggage.ContextWithBaggage(context.Background(), bag), &event)
+	finish(nil)
+	assert.Contains(t, event.Extensions(), "baggage")
+
+	var tenant string
+	handler := m.tracingHandler(func(ctx context.Context, event Event) error {
+		tenant = baggage.FromContext(ctx).Member("tenant").Value()
+		return nil
+	})
+	require.NoError(t, handler(context.Background(), event))
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestExtensionName(t *testing.T) {
+	assert.Equal(t, "traceparent", extensionName("traceparent"))
+	assert.Equal(t, "xb3traceid", extensionName("X-B3-TraceId"))
+	assert.Equal(t, "", extensionName("--"))
+}
@@ -0,0 +1,129 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ExpiresAtExtension is the CloudEvents extension holding the time after which an
+// event must not be delivered, as an RFC 3339 timestamp. It is set when an event
+// is published with WithTTL or to a topic with a configured TTL, and travels with
+// the event through every engine.
+const ExpiresAtExtension = "eventbusexpiresat"
+
+// EventExpiresAt returns the time after which event expires, if it has one.
+func EventExpiresAt(event Event) (time.Time, bool) {
+	value, ok := event.Extensions()[ExpiresAtExtension]
+	if !ok {
+		return time.Time{}, false
+	}
+	var expiresAt time.Time
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return time.Time{}, false
+		}
+		expiresAt = parsed
+	case time.Time:
+		expiresAt = v
+	default:
+		return time.Time{}, false
+	}
+	return expiresAt, true
+}
+
+// eventExpired reports whether event has an expiration at or before now.
+func eventExpired(event Event, now time.Time) bool {
+	expiresAt, ok := EventExpiresAt(event)
+	return ok && !now.Before(expiresAt)
+}
+
+// validateTopicTTLs checks that every topic TTL is positive.
+func validateTopicTTLs(ttls map[string]time.Duration) error {
+	for topic, ttl := range ttls {
+		if ttl <= 0 {
+			return fmt.Errorf("%w: topic %s has TTL %s", ErrInvalidTopicTTL, topic, ttl)
+		}
+	}
+	return nil
+}
+
+// topicTTL returns the TTL configured for topic, preferring an exact topic over the
+// longest matching wildcard pattern.
+func (c *EventBusConfig) topicTTL(topic string) (time.Duration, bool) {
+	if ttl, ok := c.TopicTTLs[topic]; ok {
+		return ttl, true
+	}
+	var best string
+	var bestTTL time.Duration
+	for pattern, ttl := range c.TopicTTLs {
+		if matchesTopic(topic, pattern) && len(pattern) > len(best) {
+			best, bestTTL = pattern, ttl
+		}
+	}
+	return bestTTL, best != ""
+}
+
+// applyTTL sets the expiration of an event being published from the context or
+// topic TTL, unless the event already carries one.
+func (m *EventBusModule) applyTTL(ctx context.Context, event *Event) {
+	if _, ok := event.Extensions()[ExpiresAtExtension]; ok {
+		return
+	}
+	ttl, ok := TTLFromContext(ctx)
+	if !ok && m.config != nil {
+		ttl, ok = m.config.topicTTL(event.Type())
+	}
+	if !ok || ttl <= 0 {
+		return
+	}
+	event.SetExtension(ExpiresAtExtension, time.Now().Add(ttl).UTC().Format(time.RFC3339Nano))
+}
+
+// expiringHandler wraps handler so expired events are counted and skipped instead
+// of delivered.
+func (m *EventBusModule) expiringHandler(handler EventHandler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		if eventExpired(event, time.Now()) {
+			m.recordExpired(ctx, event, "delivery")
+			return nil
+		}
+		return handler(ctx, event)
+	}
+}
+
+// recordExpired counts an expired event against its topic and emits
+// message.expired. Stage is where the event was found expired: "publish",
+// "delivery" or "queue" for events pruned from a durable queue.
+func (m *EventBusModule) recordExpired(ctx context.Context, event Event, stage string) {
+	m.expiredMutex.Lock()
+	if m.expiredCounts == nil {
+		m.expiredCounts = make(map[string]uint64)
+	}
+	m.expiredCounts[event.Type()]++
+	m.expiredMutex.Unlock()
+
+	data := map[string]interface{}{
+		"topic":    event.Type(),
+		"event_id": event.ID(),
+		"stage":    stage,
+	}
+	if expiresAt, ok := EventExpiresAt(event); ok {
+		data["expired_at"] = expiresAt.Format(time.RFC3339Nano)
+	}
+	go m.emitEvent(ctx, EventTypeMessageExpired, data)
+}
+
+// ExpiredStats returns the number of expired events skipped or pruned per topic
+// since the module was created.
+func (m *EventBusModule) ExpiredStats() map[string]uint64 {
+	m.expiredMutex.Lock()
+	defer m.expiredMutex.Unlock()
+	stats := make(map[string]uint64, len(m.expiredCounts))
+	for topic, count := range m.expiredCounts {
+		stats[topic] = count
+	}
+	return stats
+}
@@ -0,0 +1,174 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTTLTestModule creates a started single-engine module using the given engine.
+func newTTLTestModule(t *testing.T, engine string, topicTTLs map[string]time.Duration) *EventBusModule {
+	t.Helper()
+
+	config := &EventBusConfig{Engine: engine, WorkerCount: 2, TopicTTLs: topicTTLs}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+	router.SetModuleReference(m)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	return m
+}
+
+// newExpiringEvent returns an event of the given type expiring at expiresAt.
+func newExpiringEvent(topic string, expiresAt time.Time) Event {
+	event := newTestCloudEvent(topic, nil)
+	event.SetExtension(ExpiresAtExtension, expiresAt.UTC().Format(time.RFC3339Nano))
+	return event
+}
+
+func TestTTL_ExpiredEventsSkippedAtDelivery(t *testing.T) {
+	m := newTTLTestModule(t, "memory", nil)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var received []string
+	release := make(chan struct{})
+	_, err := m.Subscribe(ctx, "alerts.*", func(ctx context.Context, event Event) error {
+		if event.Type() == "alerts.blocker" {
+			<-release
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Type())
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Publish(ctx, "alerts.blocker", nil))
+	require.NoError(t, m.Publish(WithTTL(ctx, 20*time.Millisecond), "alerts.offer", nil))
+	require.NoError(t, m.Publish(ctx, "alerts.durable", nil))
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(received) == 2
+	}, time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, []string{"alerts.blocker", "alerts.durable"}, received)
+	mu.Unlock()
+	assert.Equal(t, map[string]uint64{"alerts.offer": 1}, m.ExpiredStats())
+}
+
+func TestTTL_AlreadyExpiredEventsNotPublished(t *testing.T) {
+	m := newTTLTestModule(t, "memory", nil)
+	ctx := context.Background()
+
+	delivered := make(chan Event, 1)
+	_, err := m.Subscribe(ctx, "alerts.offer", func(ctx context.Context, event Event) error {
+		delivered <- event
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, m.PublishCloudEvent(ctx, newExpiringEvent("alerts.offer", time.Now().Add(-time.Second))))
+	assert.Equal(t, map[string]uint64{"alerts.offer": 1}, m.ExpiredStats())
+	select {
+	case <-delivered:
+		t.Fatal("expired event was delivered")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTTL_TopicTTLSetsExpiration(t *testing.T) {
+	m := newTTLTestModule(t, "memory", map[string]time.Duration{
+		"alerts.*":       time.Minute,
+		"alerts.urgent*": time.Second,
+		"alerts.page":    time.Hour,
+	})
+	ctx := context.Background()
+
+	delivered := make(chan Event, 4)
+	for _, topic := range []string{"alerts.*", "audit.*"} {
+		_, err := m.Subscribe(ctx, topic, func(ctx context.Context, event Event) error {
+			delivered <- event
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	expectTTL := func(topic string, publishCtx context.Context, want time.Duration) {
+		t.Helper()
+		start := time.Now()
+		require.NoError(t, m.Publish(publishCtx, topic, nil))
+		event := <-delivered
+		expiresAt, ok := EventExpiresAt(event)
+		if want == 0 {
+			assert.False(t, ok, topic)
+			return
+		}
+		require.True(t, ok, topic)
+		assert.WithinDuration(t, start.Add(want), expiresAt, 100*time.Millisecond, topic)
+	}
+	expectTTL("alerts.page", ctx, time.Hour)
+	expectTTL("alerts.urgent.fire", ctx, time.Second)
+	expectTTL("alerts.info", WithTTL(ctx, 10*time.Second), 10*time.Second)
+	expectTTL("audit.login", ctx, 0)
+}
+
+func TestTTL_DurableQueuePrunesExpiredEvents(t *testing.T) {
+	var pruned []string
+	q := newDurableQueue(2, func(event Event) { pruned = append(pruned, event.Type()) })
+	ctx := context.Background()
+	done := make(chan struct{})
+
+	require.NoError(t, q.Push(ctx, done, newExpiringEvent("stale", time.Now().Add(10*time.Millisecond))))
+	require.NoError(t, q.Push(ctx, done, newTestCloudEvent("kept", nil)))
+	time.Sleep(20 * time.Millisecond)
+
+	// The queue is full, but the expired event makes room instead of blocking
+	pushCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	require.NoError(t, q.Push(pushCtx, done, newTestCloudEvent("fresh", nil)))
+	assert.Equal(t, []string{"stale"}, pruned)
+	assert.Equal(t, 2, q.Len())
+
+	first, ok := q.TryPop()
+	require.True(t, ok)
+	assert.Equal(t, "kept", first.Type())
+}
+
+func TestTTL_DurableEngineSkipsExpiredEvents(t *testing.T) {
+	m := newTTLTestModule(t, "durable-memory", nil)
+	engine := m.router.engines[m.config.GetDefaultEngine()]
+	ctx := context.Background()
+
+	delivered := make(chan string, 2)
+	_, err := engine.Subscribe(ctx, "alerts.*", func(ctx context.Context, event Event) error {
+		delivered <- event.Type()
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, engine.Publish(ctx, newExpiringEvent("alerts.offer", time.Now().Add(-time.Second))))
+	require.NoError(t, engine.Publish(ctx, newTestCloudEvent("alerts.page", nil)))
+	assert.Equal(t, "alerts.page", <-delivered)
+	require.Eventually(t, func() bool { return m.ExpiredStats()["alerts.offer"] == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestTopicTTLs_Validation(t *testing.T) {
+	config := &EventBusConfig{TopicTTLs: map[string]time.Duration{"alerts.*": -time.Second}}
+	assert.ErrorIs(t, config.ValidateConfig(), ErrInvalidTopicTTL)
+
+	config = &EventBusConfig{TopicTTLs: map[string]time.Duration{"alerts.*": time.Second}}
+	assert.NoError(t, config.ValidateConfig())
+}
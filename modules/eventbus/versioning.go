@@ -0,0 +1,211 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// EnvelopeVersionExtension is the CloudEvents extension holding the version of an
+// event's payload shape. Events published to a topic with a configured version
+// carry it; events without one are version 1.
+const EnvelopeVersionExtension = "eventbusversion"
+
+// Upcaster converts an event from one payload version to the next, for example by
+// renaming or restructuring fields of its data. It receives a copy of the event and
+// returns the converted event; the bus sets its version. An error fails delivery of
+// the event.
+type Upcaster func(ctx context.Context, event Event) (Event, error)
+
+// UpcastStats reports the upcasts applied to events of a topic on consume.
+type UpcastStats struct {
+	// Applied counts the individual upcasts, so an event upcast from v1 to v3 counts twice
+	Applied uint64 `json:"applied" yaml:"applied"`
+	// Failed counts events whose upcast returned an error
+	Failed uint64 `json:"failed" yaml:"failed"`
+}
+
+// EventVersion returns the payload version of event, 1 for events without one.
+func EventVersion(event Event) int {
+	switch v := event.Extensions()[EnvelopeVersionExtension].(type) {
+	case int32:
+		return int(v)
+	case int:
+		return v
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return 1
+}
+
+// validateTopicVersions checks that every topic version is at least 1.
+func validateTopicVersions(versions map[string]int) error {
+	for topic, version := range versions {
+		if version < 1 {
+			return fmt.Errorf("%w: topic %s has version %d", ErrInvalidTopicVersion, topic, version)
+		}
+	}
+	return nil
+}
+
+// topicVersion returns the payload version configured for topic, preferring an
+// exact topic over the longest matching wildcard pattern.
+func (c *EventBusConfig) topicVersion(topic string) (int, bool) {
+	if version, ok := c.TopicVersions[topic]; ok {
+		return version, true
+	}
+	var best string
+	var bestVersion int
+	for pattern, version := range c.TopicVersions {
+		if matchesTopic(topic, pattern) && len(pattern) > len(best) {
+			best, bestVersion = pattern, version
+		}
+	}
+	return bestVersion, best != ""
+}
+
+// RegisterUpcaster registers the upcaster converting events of matching topics from
+// fromVersion to fromVersion+1. Topic is an exact topic or a wildcard pattern, and
+// an exact topic wins over patterns, and longer patterns over shorter ones.
+//
+// Handlers receive events upcast one version at a time up to the topic's configured
+// version in TopicVersions, or as far as upcasters are registered for topics without
+// one. That lets new code consume long-lived durable streams still holding events
+// published in older shapes. Upcasters can be registered at any time and apply to
+// events consumed afterwards.
+//
+// Example:
+//
+//	bus.RegisterUpcaster("order.created", 1, func(ctx context.Context, e eventbus.Event) (eventbus.Event, error) {
+//	    var v1 OrderCreatedV1
+//	    if err := e.DataAs(&v1); err != nil {
+//	        return e, err
+//	    }
+//	    err := e.SetData(cloudevents.ApplicationJSON, OrderCreatedV2{ID: v1.ID, Total: Money{Amount: v1.Total}})
+//	    return e, err
+//	})
+func (m *EventBusModule) RegisterUpcaster(topic string, fromVersion int, upcaster Upcaster) error {
+	if topic == "" || fromVersion < 1 || upcaster == nil {
+		return fmt.Errorf("%w: topic %q from version %d", ErrInvalidUpcaster, topic, fromVersion)
+	}
+	m.upcastMutex.Lock()
+	defer m.upcastMutex.Unlock()
+	if m.upcasters == nil {
+		m.upcasters = make(map[string]map[int]Upcaster)
+	}
+	if m.upcasters[topic] == nil {
+		m.upcasters[topic] = make(map[int]Upcaster)
+	}
+	m.upcasters[topic][fromVersion] = upcaster
+	return nil
+}
+
+// upcaster returns the upcaster from version for topic, preferring an exact topic
+// over the longest matching wildcard pattern.
+func (m *EventBusModule) upcaster(topic string, version int) Upcaster {
+	m.upcastMutex.RLock()
+	defer m.upcastMutex.RUnlock()
+	if upcaster, ok := m.upcasters[topic][version]; ok {
+		return upcaster
+	}
+	var best string
+	var bestUpcaster Upcaster
+	for pattern, upcasters := range m.upcasters {
+		if upcaster, ok := upcasters[version]; ok && matchesTopic(topic, pattern) && len(pattern) > len(best) {
+			best, bestUpcaster = pattern, upcaster
+		}
+	}
+	return bestUpcaster
+}
+
+// applyVersion stamps an event being published with its topic's configured version,
+// unless the event already carries one.
+func (m *EventBusModule) applyVersion(event *Event) {
+	if _, ok := event.Extensions()[EnvelopeVersionExtension]; ok || m.config == nil {
+		return
+	}
+	if version, ok := m.config.topicVersion(event.Type()); ok {
+		event.SetExtension(EnvelopeVersionExtension, version)
+	}
+}
+
+// upcastingHandler wraps handler so it receives events upcast to their topic's
+// version. An event is delivered as far as it could be upcast when an upcaster in
+// the chain is missing, so handlers can check EventVersion.
+func (m *EventBusModule) upcastingHandler(handler EventHandler) EventHandler {
+	return func(ctx context.Context, event Event) error {
+		upcast, err := m.upcast(ctx, event)
+		if err != nil {
+			return err
+		}
+		return handler(ctx, upcast)
+	}
+}
+
+// upcast applies the upcasters registered for the event's topic, from its version up
+// to the topic's configured one.
+func (m *EventBusModule) upcast(ctx context.Context, event Event) (Event, error) {
+	topic := event.Type()
+	target, limited := 0, false
+	if m.config != nil {
+		target, limited = m.config.topicVersion(topic)
+	}
+	version := EventVersion(event)
+	applied := uint64(0)
+	for !limited || version < target {
+		upcaster := m.upcaster(topic, version)
+		if upcaster == nil {
+			break
+		}
+		next, err := upcaster(ctx, event.Clone())
+		if err != nil {
+			m.recordUpcasts(topic, applied, true)
+			go m.emitEvent(ctx, EventTypeUpcastFailed, map[string]interface{}{
+				"topic":        topic,
+				"event_id":     event.ID(),
+				"from_version": version,
+				"to_version":   version + 1,
+				"error":        err.Error(),
+			})
+			return event, fmt.Errorf("%w: topic %s from version %d: %w", ErrUpcastFailed, topic, version, err)
+		}
+		version++
+		next.SetExtension(EnvelopeVersionExtension, version)
+		event = next
+		applied++
+	}
+	if applied > 0 {
+		m.recordUpcasts(topic, applied, false)
+	}
+	return event, nil
+}
+
+// recordUpcasts adds upcasts applied to an event of topic, and whether one failed,
+// to the topic's stats.
+func (m *EventBusModule) recordUpcasts(topic string, applied uint64, failed bool) {
+	m.upcastMutex.Lock()
+	defer m.upcastMutex.Unlock()
+	if m.upcastCounts == nil {
+		m.upcastCounts = make(map[string]UpcastStats)
+	}
+	stats := m.upcastCounts[topic]
+	stats.Applied += applied
+	if failed {
+		stats.Failed++
+	}
+	m.upcastCounts[topic] = stats
+}
+
+// UpcastStats returns the upcasts applied and failed per topic since the module was
+// created.
+func (m *EventBusModule) UpcastStats() map[string]UpcastStats {
+	m.upcastMutex.RLock()
+	defer m.upcastMutex.RUnlock()
+	stats := make(map[string]UpcastStats, len(m.upcastCounts))
+	for topic, counts := range m.upcastCounts {
+		stats[topic] = counts
+	}
+	return stats
+}
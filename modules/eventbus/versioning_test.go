@@ -0,0 +1,156 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVersioningTestModule creates a started memory-engine module with the given
+// topic versions.
+func newVersioningTestModule(t *testing.T, versions map[string]int) *EventBusModule {
+	t.Helper()
+
+	config := &EventBusConfig{Engine: "memory", WorkerCount: 2, TopicVersions: versions}
+	require.NoError(t, config.ValidateConfig())
+	router, err := NewEngineRouter(config)
+	require.NoError(t, err)
+	m := &EventBusModule{name: ModuleName, config: config, router: router, logger: &mockLogger{}}
+	router.SetModuleReference(m)
+
+	ctx := context.Background()
+	require.NoError(t, m.Start(ctx))
+	t.Cleanup(func() { _ = m.Stop(ctx) })
+	return m
+}
+
+// renameField returns an upcaster renaming a field of a JSON object payload.
+func renameField(from, to string) Upcaster {
+	return func(ctx context.Context, event Event) (Event, error) {
+		var data map[string]interface{}
+		if err := event.DataAs(&data); err != nil {
+			return event, err
+		}
+		data[to] = data[from]
+		delete(data, from)
+		return event, event.SetData(cloudevents.ApplicationJSON, data)
+	}
+}
+
+// publishVersioned publishes an event with the given payload version.
+func publishVersioned(t *testing.T, m *EventBusModule, topic string, version int, data map[string]interface{}) {
+	t.Helper()
+	event := newTestCloudEvent(topic, data)
+	event.SetExtension(EnvelopeVersionExtension, version)
+	require.NoError(t, m.PublishCloudEvent(context.Background(), event))
+}
+
+func TestUpcast_OnConsume(t *testing.T) {
+	m := newVersioningTestModule(t, map[string]int{"order.*": 3})
+	ctx := context.Background()
+	require.NoError(t, m.RegisterUpcaster("order.*", 1, renameField("amount", "total")))
+	require.NoError(t, m.RegisterUpcaster("order.created", 2, renameField("total", "grand_total")))
+	require.NoError(t, m.RegisterUpcaster("order.*", 2, renameField("total", "unused")))
+
+	received := make(chan Event, 4)
+	_, err := m.Subscribe(ctx, "order.created", func(ctx context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+
+	receive := func() (int, map[string]interface{}) {
+		select {
+		case event := <-received:
+			var data map[string]interface{}
+			require.NoError(t, event.DataAs(&data))
+			return EventVersion(event), data
+		case <-time.After(time.Second):
+			t.Fatal("event not delivered")
+			return 0, nil
+		}
+	}
+
+	// Version 1 events go through the whole chain, with the exact topic's upcaster
+	// winning over the pattern's
+	publishVersioned(t, m, "order.created", 1, map[string]interface{}{"amount": 5})
+	version, data := receive()
+	assert.Equal(t, 3, version)
+	assert.Equal(t, map[string]interface{}{"grand_total": float64(5)}, data)
+
+	publishVersioned(t, m, "order.created", 2, map[string]interface{}{"total": 7})
+	version, data = receive()
+	assert.Equal(t, 3, version)
+	assert.Equal(t, map[string]interface{}{"grand_total": float64(7)}, data)
+
+	// Events published now carry the topic's version and are delivered as they are
+	require.NoError(t, m.Publish(ctx, "order.created", map[string]interface{}{"grand_total": 9}))
+	version, data = receive()
+	assert.Equal(t, 3, version)
+	assert.Equal(t, map[string]interface{}{"grand_total": float64(9)}, data)
+
+	assert.Equal(t, map[string]UpcastStats{"order.created": {Applied: 3}}, m.UpcastStats())
+}
+
+func TestUpcast_UnversionedTopic(t *testing.T) {
+	m := newVersioningTestModule(t, nil)
+	ctx := context.Background()
+	require.NoError(t, m.RegisterUpcaster("user.renamed", 1, renameField("name", "display_name")))
+
+	received := make(chan Event, 1)
+	_, err := m.Subscribe(ctx, "user.renamed", func(ctx context.Context, event Event) error {
+		received <- event
+		return nil
+	})
+	require.NoError(t, err)
+
+	// Topics without a configured version are upcast as far as upcasters go
+	require.NoError(t, m.Publish(ctx, "user.renamed", map[string]interface{}{"name": "Ada"}))
+	select {
+	case event := <-received:
+		_, versioned := event.Extensions()[EnvelopeVersionExtension]
+		assert.True(t, versioned)
+		assert.Equal(t, 2, EventVersion(event))
+		var data map[string]interface{}
+		require.NoError(t, event.DataAs(&data))
+		assert.Equal(t, map[string]interface{}{"display_name": "Ada"}, data)
+	case <-time.After(time.Second):
+		t.Fatal("event not delivered")
+	}
+}
+
+func TestUpcast_Failure(t *testing.T) {
+	m := newVersioningTestModule(t, map[string]int{"order.created": 2})
+	errShape := errors.New("unknown shape")
+	require.NoError(t, m.RegisterUpcaster("order.created", 1, func(ctx context.Context, event Event) (Event, error) {
+		return event, errShape
+	}))
+
+	handled := false
+	handler := m.upcastingHandler(func(ctx context.Context, event Event) error {
+		handled = true
+		return nil
+	})
+	// Events stored before versioning was configured have no version and count as 1
+	err := handler(context.Background(), newTestCloudEvent("order.created", nil))
+	require.ErrorIs(t, err, ErrUpcastFailed)
+	require.ErrorIs(t, err, errShape)
+	assert.False(t, handled, "events failing to upcast are not delivered")
+	assert.Equal(t, map[string]UpcastStats{"order.created": {Failed: 1}}, m.UpcastStats())
+}
+
+func TestUpcast_Validation(t *testing.T) {
+	m := &EventBusModule{}
+	noop := func(ctx context.Context, event Event) (Event, error) { return event, nil }
+	assert.ErrorIs(t, m.RegisterUpcaster("", 1, noop), ErrInvalidUpcaster)
+	assert.ErrorIs(t, m.RegisterUpcaster("order.created", 0, noop), ErrInvalidUpcaster)
+	assert.ErrorIs(t, m.RegisterUpcaster("order.created", 1, nil), ErrInvalidUpcaster)
+
+	config := &EventBusConfig{Engine: "memory", TopicVersions: map[string]int{"order.*": 0}}
+	assert.ErrorIs(t, config.ValidateConfig(), ErrInvalidTopicVersion)
+}
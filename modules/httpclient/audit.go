@@ -0,0 +1,244 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultAuditTopic is the topic used when publishing request audit records
+// to an AuditPublisher and VerboseOptions.EventTopic is not set.
+const DefaultAuditTopic = "httpclient.audit"
+
+// redactedHeaderValue replaces credentials in audit records.
+const redactedHeaderValue = "[REDACTED]"
+
+// AuditPublisher publishes request audit records to a message bus.
+// The eventbus module's EventBusModule satisfies this interface, so request
+// summaries can be centralized with:
+//
+//	client.SetAuditPublisher(eventBusModule)
+type AuditPublisher interface {
+	Publish(ctx context.Context, topic string, payload interface{}) error
+}
+
+// auditFunc receives request audit records from the logging transport.
+type auditFunc func(ctx context.Context, eventType string, data map[string]interface{})
+
+// sensitiveAuditHeaders are never included verbatim in audit records, since
+// those are shipped to shared infrastructure rather than local logs.
+var sensitiveAuditHeaders = map[string]bool{
+	"authorization":       true,
+	"proxy-authorization": true,
+	"cookie":              true,
+	"set-cookie":          true,
+}
+
+// auditQueueSize bounds the audit records waiting to be published. Records arriving
+// while the queue is full are dropped rather than slowing requests down.
+const auditQueueSize = 1024
+
+// auditPublication is an audit record waiting to be published.
+type auditPublication struct {
+	ctx       context.Context
+	publisher AuditPublisher
+	topic     string
+	payload   map[string]interface{}
+}
+
+// bodyCapture keeps the first max bytes written to it, recording whether more
+// followed. The transport may still be sending a request body while the response
+// is read, so it is safe for concurrent use.
+type bodyCapture struct {
+	mu        sync.Mutex
+	max       int
+	data      []byte
+	truncated bool
+}
+
+func (c *bodyCapture) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(p)
+	if room := c.max - len(c.data); n > room {
+		c.truncated = true
+		p = p[:max(room, 0)]
+	}
+	c.data = append(c.data, p...)
+	return n, nil
+}
+
+// captured returns the bytes kept so far and whether the body was longer.
+func (c *bodyCapture) captured() (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return string(c.data), c.truncated
+}
+
+// teeReadCloser reads a body through a tee into a bodyCapture.
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// auditedBody is a response body that emits its audit record, with the captured
+// part of the body, once the caller closes it.
+type auditedBody struct {
+	teeReadCloser
+	once sync.Once
+	emit func()
+}
+
+func (b *auditedBody) Close() error {
+	err := b.Closer.Close()
+	b.once.Do(b.emit)
+	return err
+}
+
+// audit emits a summary of the exchange when event logging is enabled. When
+// response bodies are audited, the record is emitted once the caller closes the
+// body, with the part of it the caller read.
+func (t *loggingTransport) audit(req *http.Request, resp *http.Response, requestID string, duration time.Duration, requestBody *bodyCapture, err error) {
+	if t.Audit == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"id":                     requestID,
+		"method":                 req.Method,
+		"url":                    req.URL.Redacted(),
+		"duration_ms":            duration.Milliseconds(),
+		"request_content_length": req.ContentLength,
+	}
+	if t.LogHeaders {
+		data["request_headers"] = auditHeaders(req.Header)
+	}
+	addRequestBody := func() {
+		if requestBody != nil {
+			data["request_body"], data["request_body_truncated"] = requestBody.captured()
+		}
+	}
+
+	if err != nil {
+		addRequestBody()
+		data["error"] = err.Error()
+		t.Audit(req.Context(), EventTypeRequestFailed, data)
+		return
+	}
+
+	if resp != nil {
+		data["status_code"] = resp.StatusCode
+		data["response_content_length"] = resp.ContentLength
+		if t.LogHeaders {
+			data["response_headers"] = auditHeaders(resp.Header)
+		}
+		if t.AuditBodyMaxSize > 0 && resp.Body != nil && resp.Body != http.NoBody {
+			if reason := shouldOmitResponseBody(resp); reason != "" {
+				data["response_body_omitted"] = reason
+			} else {
+				responseBody := &bodyCapture{max: t.AuditBodyMaxSize}
+				ctx := req.Context()
+				resp.Body = &auditedBody{
+					teeReadCloser: teeReadCloser{Reader: io.TeeReader(resp.Body, responseBody), Closer: resp.Body},
+					emit: func() {
+						addRequestBody()
+						data["response_body"], data["response_body_truncated"] = responseBody.captured()
+						t.Audit(ctx, EventTypeRequestCompleted, data)
+					},
+				}
+				return
+			}
+		}
+	}
+
+	addRequestBody()
+	t.Audit(req.Context(), EventTypeRequestCompleted, data)
+}
+
+// auditHeaders flattens headers for an audit record, redacting credentials.
+func auditHeaders(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for key, values := range header {
+		if sensitiveAuditHeaders[strings.ToLower(key)] {
+			result[key] = redactedHeaderValue
+			continue
+		}
+		result[key] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// SetAuditPublisher sets a publisher that receives request audit records in
+// addition to the CloudEvents emitted when VerboseOptions.LogToEvents is enabled.
+// Pass nil to stop publishing.
+func (m *HTTPClientModule) SetAuditPublisher(publisher AuditPublisher) {
+	m.auditMu.Lock()
+	m.auditPublisher = publisher
+	m.auditMu.Unlock()
+}
+
+// publishAudit emits an audit record as a CloudEvent and, when configured, queues it
+// for the audit worker to publish, so requests don't wait for the publisher.
+func (m *HTTPClientModule) publishAudit(ctx context.Context, eventType string, data map[string]interface{}) {
+	m.emitEvent(ctx, eventType, data)
+
+	m.auditMu.Lock()
+	publisher := m.auditPublisher
+	if publisher != nil && m.auditQueue == nil {
+		m.auditQueue = make(chan auditPublication, auditQueueSize)
+		m.auditDone = make(chan struct{})
+		go m.runAuditWorker(m.auditQueue, m.auditDone)
+	}
+	queue := m.auditQueue
+	m.auditMu.Unlock()
+	if publisher == nil {
+		return
+	}
+
+	topic := DefaultAuditTopic
+	if m.config != nil && m.config.VerboseOptions != nil && m.config.VerboseOptions.EventTopic != "" {
+		topic = m.config.VerboseOptions.EventTopic
+	}
+
+	payload := make(map[string]interface{}, len(data)+1)
+	for k, v := range data {
+		payload[k] = v
+	}
+	payload["type"] = eventType
+
+	// The request context is usually cancelled once the response is consumed
+	select {
+	case queue <- auditPublication{ctx: context.WithoutCancel(ctx), publisher: publisher, topic: topic, payload: payload}:
+	default:
+		m.logger.Debug("HTTP client audit queue full, dropping record", "topic", topic)
+	}
+}
+
+// runAuditWorker publishes queued audit records until done is closed.
+func (m *HTTPClientModule) runAuditWorker(queue <-chan auditPublication, done <-chan struct{}) {
+	for {
+		select {
+		case p := <-queue:
+			if err := p.publisher.Publish(p.ctx, p.topic, p.payload); err != nil {
+				m.logger.Debug("Failed to publish HTTP client audit record", "error", err, "topic", p.topic)
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// stopAuditWorker stops the audit worker. Records still queued are dropped; a
+// later record starts a new worker.
+func (m *HTTPClientModule) stopAuditWorker() {
+	m.auditMu.Lock()
+	defer m.auditMu.Unlock()
+	if m.auditDone != nil {
+		close(m.auditDone)
+		m.auditQueue = nil
+		m.auditDone = nil
+	}
+}
@@ -0,0 +1,216 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type auditRecord struct {
+	eventType string
+	data      map[string]interface{}
+}
+
+type auditCollector struct {
+	mu      sync.Mutex
+	records []auditRecord
+}
+
+func (c *auditCollector) collect(_ context.Context, eventType string, data map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.records = append(c.records, auditRecord{eventType: eventType, data: data})
+}
+
+type recordingPublisher struct {
+	mu       sync.Mutex
+	topics   []string
+	payloads []interface{}
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, topic string, payload interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.topics = append(p.topics, topic)
+	p.payloads = append(p.payloads, payload)
+	return nil
+}
+
+func (p *recordingPublisher) count() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.topics)
+}
+
+func TestLoggingTransport_AuditEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"12345","status":"created"}`))
+	}))
+	defer server.Close()
+
+	collector := &auditCollector{}
+	transport := &loggingTransport{
+		Transport:        http.DefaultTransport,
+		Logger:           &TestLogger{},
+		LogHeaders:       true,
+		Audit:            collector.collect,
+		AuditBodyMaxSize: 10,
+	}
+	client := &http.Client{Transport: transport}
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"name":"a long request body"}`))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	// The caller must still receive the complete response body
+	assert.Equal(t, `{"id":"12345","status":"created"}`, string(body))
+
+	require.Len(t, collector.records, 1)
+	record := collector.records[0]
+	assert.Equal(t, EventTypeRequestCompleted, record.eventType)
+	assert.Equal(t, http.MethodPost, record.data["method"])
+	assert.Equal(t, http.StatusCreated, record.data["status_code"])
+	assert.Equal(t, `{"name":"a`, record.data["request_body"])
+	assert.Equal(t, true, record.data["request_body_truncated"])
+	assert.Equal(t, `{"id":"123`, record.data["response_body"])
+	assert.Equal(t, true, record.data["response_body_truncated"])
+
+	requestHeaders := record.data["request_headers"].(map[string]string)
+	assert.Equal(t, redactedHeaderValue, requestHeaders["Authorization"])
+	responseHeaders := record.data["response_headers"].(map[string]string)
+	assert.Equal(t, redactedHeaderValue, responseHeaders["Set-Cookie"])
+}
+
+func TestLoggingTransport_AuditFailureWithoutBodies(t *testing.T) {
+	collector := &auditCollector{}
+	transport := &loggingTransport{
+		Transport: http.DefaultTransport,
+		Logger:    &TestLogger{},
+		Audit:     collector.collect,
+	}
+	client := &http.Client{Transport: transport, Timeout: time.Second}
+
+	_, err := client.Get("http://127.0.0.1:1/unreachable")
+	require.Error(t, err)
+
+	require.Len(t, collector.records, 1)
+	record := collector.records[0]
+	assert.Equal(t, EventTypeRequestFailed, record.eventType)
+	assert.Contains(t, record.data, "error")
+	assert.NotContains(t, record.data, "request_headers")
+	assert.NotContains(t, record.data, "request_body")
+}
+
+func TestHTTPClientModule_PublishAudit(t *testing.T) {
+	publisher := &recordingPublisher{}
+	m := &HTTPClientModule{
+		logger: &TestLogger{},
+		config: &Config{VerboseOptions: &VerboseOptions{EventTopic: "audit.outbound"}},
+	}
+	m.SetAuditPublisher(publisher)
+
+	m.publishAudit(context.Background(), EventTypeRequestCompleted, map[string]interface{}{"status_code": 200})
+
+	assert.Eventually(t, func() bool { return publisher.count() == 1 }, time.Second, 10*time.Millisecond)
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+	assert.Equal(t, "audit.outbound", publisher.topics[0])
+	payload := publisher.payloads[0].(map[string]interface{})
+	assert.Equal(t, EventTypeRequestCompleted, payload["type"])
+	assert.Equal(t, 200, payload["status_code"])
+}
+
+func TestLoggingTransport_AuditRedactsURLAndTeesBodies(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("hello, audited world"))
+	}))
+	defer server.Close()
+
+	collector := &auditCollector{}
+	transport := &loggingTransport{
+		Transport:        http.DefaultTransport,
+		Logger:           &TestLogger{},
+		Audit:            collector.collect,
+		AuditBodyMaxSize: 64,
+	}
+	client := &http.Client{Transport: transport}
+
+	url := strings.Replace(server.URL, "http://", "http://user:secret@", 1) + "/upload"
+	// A reader without GetBody, which the request can't replay
+	req, err := http.NewRequest(http.MethodPost, url, io.NopCloser(strings.NewReader("streamed body")))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	prefix := make([]byte, 5)
+	_, err = io.ReadFull(resp.Body, prefix)
+	require.NoError(t, err)
+	collector.mu.Lock()
+	assert.Empty(t, collector.records, "the record is emitted once the body is closed")
+	collector.mu.Unlock()
+	require.NoError(t, resp.Body.Close())
+
+	require.Len(t, collector.records, 1)
+	record := collector.records[0]
+	assert.NotContains(t, record.data["url"], "secret")
+	assert.Contains(t, record.data["url"], "user:xxxxx@")
+	assert.Equal(t, "streamed body", record.data["request_body"])
+	assert.Equal(t, false, record.data["request_body_truncated"])
+	assert.Equal(t, "hello", record.data["response_body"], "only what the caller read is captured")
+}
+
+// blockingPublisher blocks every Publish until release is closed.
+type blockingPublisher struct {
+	recordingPublisher
+	release chan struct{}
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, topic string, payload interface{}) error {
+	<-p.release
+	return p.recordingPublisher.Publish(ctx, topic, payload)
+}
+
+func TestHTTPClientModule_PublishAuditIsBounded(t *testing.T) {
+	publisher := &blockingPublisher{release: make(chan struct{})}
+	m := &HTTPClientModule{logger: &TestLogger{}, config: &Config{}}
+	m.SetAuditPublisher(publisher)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range auditQueueSize + 100 {
+			m.publishAudit(context.Background(), EventTypeRequestCompleted, map[string]interface{}{})
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing audit records blocked on a slow publisher")
+	}
+
+	close(publisher.release)
+	assert.Eventually(t, func() bool { return publisher.count() >= auditQueueSize }, 5*time.Second, 10*time.Millisecond)
+	assert.LessOrEqual(t, publisher.count(), auditQueueSize+1, "records beyond the queue are dropped")
+
+	m.stopAuditWorker()
+	assert.Nil(t, m.auditQueue)
+}
@@ -35,6 +35,14 @@ var (
 //	  max_body_log_size: 1024
 //	  log_to_file: true
 //	  log_file_path: "/var/log/httpclient"
+//	  log_to_events: true
+//	  event_body_max_size: 512
+//	proxy:
+//	  url: "http://proxy.corp.example:3128"
+//	  no_proxy: "localhost,.svc.cluster.local"
+//	retry:
+//	  max_attempts: 3
+//	  initial_backoff: 100ms
 //
 // Example environment variables:
 //
@@ -93,6 +101,15 @@ type Config struct {
 	// VerboseOptions configures the behavior when Verbose is enabled.
 	// This allows fine-grained control over what gets logged and where.
 	VerboseOptions *VerboseOptions `yaml:"verbose_options" json:"verbose_options" env:"VERBOSE_OPTIONS"`
+
+	// Proxy routes outbound requests through an HTTP, HTTPS or SOCKS5 proxy,
+	// with per-host overrides and exclusions.
+	// Default: nil (direct connections)
+	Proxy *ProxyConfig `yaml:"proxy" json:"proxy" env:"PROXY"`
+
+	// Retry retries failed requests with backoff, within a per-host retry budget.
+	// Default: nil (no retries)
+	Retry *RetryConfig `yaml:"retry" json:"retry" env:"RETRY"`
 }
 
 // VerboseOptions configures the behavior of verbose logging.
@@ -130,6 +147,24 @@ type VerboseOptions struct {
 	// The directory must be writable by the application.
 	// Default: "" (current directory)
 	LogFilePath string `yaml:"log_file_path" json:"log_file_path" env:"LOG_FILE_PATH"`
+
+	// LogToEvents emits a summary of every request/response as a CloudEvent
+	// (com.modular.httpclient.request.completed / request.failed) so request
+	// auditing can be centralized through observers or an AuditPublisher.
+	// Headers are included when LogHeaders is enabled, with credentials redacted.
+	// Default: false
+	LogToEvents bool `yaml:"log_to_events" json:"log_to_events" env:"LOG_TO_EVENTS"`
+
+	// EventBodyMaxSize includes request and response bodies in audit events,
+	// truncated to this many bytes. Binary and compressed bodies are never included.
+	// Response bodies are captured as the caller reads them, delaying the event until
+	// the caller closes the body.
+	// Default: 0 (bodies omitted)
+	EventBodyMaxSize int `yaml:"event_body_max_size" json:"event_body_max_size" env:"EVENT_BODY_MAX_SIZE"`
+
+	// EventTopic is the topic used when publishing audit records to an AuditPublisher.
+	// Default: "httpclient.audit"
+	EventTopic string `yaml:"event_topic" json:"event_topic" env:"EVENT_TOPIC"`
 }
 
 // Validate checks the configuration values and sets sensible defaults.
@@ -178,5 +213,17 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("config validation error: %w", ErrLogFilePathRequired)
 	}
 
+	if c.Proxy != nil {
+		if err := c.Proxy.validate(); err != nil {
+			return fmt.Errorf("config validation error: %w", err)
+		}
+	}
+
+	if c.Retry != nil {
+		if err := c.Retry.validate(); err != nil {
+			return fmt.Errorf("config validation error: %w", err)
+		}
+	}
+
 	return nil
 }
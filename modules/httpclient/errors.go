@@ -10,4 +10,8 @@ var (
 	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
 	// ErrUnsafeFilename is returned when a URL cannot be safely converted to a filename
 	ErrUnsafeFilename = errors.New("URL contains no valid characters for filename after sanitization")
+	// ErrInvalidProxyConfig is returned when the outbound proxy configuration is invalid
+	ErrInvalidProxyConfig = errors.New("invalid proxy configuration")
+	// ErrInvalidRetryConfig is returned when the retry configuration is invalid
+	ErrInvalidRetryConfig = errors.New("invalid retry configuration")
 )
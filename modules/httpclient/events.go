@@ -21,4 +21,12 @@ const (
 	// Configuration events
 	EventTypeConfigLoaded   = "com.modular.httpclient.config.loaded"
 	EventTypeTimeoutChanged = "com.modular.httpclient.timeout.changed"
+
+	// Request audit events (emitted when verbose_options.log_to_events is enabled)
+	EventTypeRequestCompleted = "com.modular.httpclient.request.completed"
+	EventTypeRequestFailed    = "com.modular.httpclient.request.failed"
+
+	// Retry events (emitted when retries are configured)
+	EventTypeRequestRetried       = "com.modular.httpclient.request.retried"
+	EventTypeRetryBudgetExhausted = "com.modular.httpclient.retry.budget_exhausted"
 )
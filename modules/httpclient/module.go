@@ -14,6 +14,7 @@
 //   - Comprehensive request/response logging with file output
 //   - Request modification pipeline for adding headers, authentication, etc.
 //   - Performance-optimized transport settings
+//   - Outbound HTTP, HTTPS and SOCKS5 proxies with per-host overrides
 //   - Support for compression and keep-alive control
 //   - Service interface for dependency injection
 //
@@ -162,6 +163,15 @@ type HTTPClientModule struct {
 	// Use RWMutex to avoid data race (pattern aligned with cache module fix).
 	subject   modular.Subject
 	subjectMu sync.RWMutex
+	// auditPublisher optionally receives request audit records (see SetAuditPublisher)
+	// from a single worker reading auditQueue until auditDone is closed
+	auditPublisher AuditPublisher
+	auditQueue     chan auditPublication
+	auditDone      chan struct{}
+	auditMu        sync.Mutex
+	// retryConfig and retryBudget are the retry policy shared by the client and RetryDecorator
+	retryConfig *RetryConfig
+	retryBudget *retryBudget
 }
 
 // Make sure HTTPClientModule implements necessary interfaces
@@ -257,6 +267,18 @@ func (m *HTTPClientModule) Init(app modular.Application) error {
 		DisableCompression:  m.config.DisableCompression,
 		DisableKeepAlives:   m.config.DisableKeepAlives,
 	}
+	if m.config.Proxy != nil {
+		proxy, err := m.config.Proxy.proxyFunc()
+		if err != nil {
+			return fmt.Errorf("failed to configure proxy: %w", err)
+		}
+		m.transport.Proxy = proxy
+		m.logger.Info("HTTP client proxy configured",
+			"default_proxy", redactedProxyURL(m.config.Proxy.URL),
+			"overrides", len(m.config.Proxy.Overrides),
+			"from_environment", m.config.Proxy.FromEnvironment,
+		)
+	}
 
 	// Create the HTTP client with the transport
 	baseTransport := http.RoundTripper(m.transport)
@@ -290,7 +312,7 @@ func (m *HTTPClientModule) Init(app modular.Application) error {
 			}
 		}
 
-		baseTransport = &loggingTransport{
+		lt := &loggingTransport{
 			Transport:      baseTransport,
 			Logger:         m.logger,
 			FileLogger:     m.fileLogger,
@@ -299,6 +321,36 @@ func (m *HTTPClientModule) Init(app modular.Application) error {
 			MaxBodyLogSize: m.config.VerboseOptions.MaxBodyLogSize,
 			LogToFile:      m.config.VerboseOptions.LogToFile && m.fileLogger != nil,
 		}
+
+		// Emit request/response summaries as events for centralized auditing
+		if m.config.VerboseOptions.LogToEvents {
+			lt.Audit = m.publishAudit
+			lt.AuditBodyMaxSize = m.config.VerboseOptions.EventBodyMaxSize
+			m.logger.Info("HTTP client event logging enabled",
+				"body_max_size", m.config.VerboseOptions.EventBodyMaxSize,
+			)
+		}
+		baseTransport = lt
+	}
+
+	// Retry outside the logging transport, so that every attempt is logged
+	if m.config.Retry != nil {
+		if err := m.config.Retry.validate(); err != nil {
+			return fmt.Errorf("failed to configure retries: %w", err)
+		}
+		m.retryConfig = m.config.Retry
+		m.retryBudget = newRetryBudget(m.retryConfig.Budget)
+		baseTransport = newRetryTransport(baseTransport, m.retryConfig, m.retryBudget, m.emitEvent)
+		m.logger.Info("HTTP client retries enabled",
+			"max_attempts", m.retryConfig.MaxAttempts,
+			"backoff", m.retryConfig.Backoff,
+			"budget", m.retryConfig.Budget != nil,
+		)
+	}
+
+	// The context audit sees each request once, as the caller sent it
+	if auditor, ok := app.(transportAuditor); ok {
+		baseTransport = auditor.AuditTransport(ModuleName, baseTransport)
 	}
 
 	m.httpClient = &http.Client{
@@ -353,6 +405,7 @@ func (m *HTTPClientModule) Start(ctx context.Context) error {
 func (m *HTTPClientModule) Stop(ctx context.Context) error {
 	m.logger.Info("Stopping HTTP client module")
 	m.transport.CloseIdleConnections()
+	m.stopAuditWorker()
 
 	// Close the file logger if it exists
 	if m.fileLogger != nil {
@@ -380,6 +433,11 @@ func (m *HTTPClientModule) ProvidesServices() []modular.ServiceProvider {
 			Description: "HTTP client service interface (ClientService) for advanced features",
 			Instance:    m, // Provide the service interface for modules that need additional features
 		},
+		{
+			Name:        RetryServiceName,
+			Description: "RoundTripper decorator (RoundTripperDecorator) adding the module's retry policy",
+			Instance:    m.RetryDecorator(),
+		},
 	}
 }
 
@@ -580,6 +638,12 @@ func shouldOmitResponseBody(resp *http.Response) string {
 	return ""
 }
 
+// transportAuditor is implemented by applications auditing the contexts of outgoing
+// requests, see modular.StdApplication.AuditTransport.
+type transportAuditor interface {
+	AuditTransport(name string, base http.RoundTripper) http.RoundTripper
+}
+
 // loggingTransport provides verbose logging of HTTP requests and responses.
 type loggingTransport struct {
 	Transport      http.RoundTripper
@@ -589,6 +653,9 @@ type loggingTransport struct {
 	LogBody        bool
 	MaxBodyLogSize int
 	LogToFile      bool
+	// Audit receives request/response summaries when event logging is enabled
+	Audit            auditFunc
+	AuditBodyMaxSize int
 }
 
 // RoundTrip implements the http.RoundTripper interface and adds logging.
@@ -600,6 +667,15 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	// Log the request
 	t.logRequest(requestID, req)
 
+	// Capture the audited part of the request body as the transport sends it
+	var requestBody *bodyCapture
+	if t.Audit != nil && t.AuditBodyMaxSize > 0 && req.Body != nil && req.Body != http.NoBody {
+		requestBody = &bodyCapture{max: t.AuditBodyMaxSize}
+		body := req.Body
+		req = req.Clone(req.Context())
+		req.Body = &teeReadCloser{Reader: io.TeeReader(body, requestBody), Closer: body}
+	}
+
 	// Execute the actual request
 	resp, err := t.Transport.RoundTrip(req)
 
@@ -615,6 +691,7 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 			"duration_ms", duration.Milliseconds(),
 			"error", err,
 		)
+		t.audit(req, resp, requestID, duration, requestBody, err)
 		return resp, fmt.Errorf("http request failed: %w", err)
 	}
 
@@ -626,6 +703,8 @@ func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 		t.handleFileLogging(requestID, req, resp, duration)
 	}
 
+	t.audit(req, resp, requestID, duration, requestBody, nil)
+
 	return resp, nil
 }
 
@@ -1058,5 +1137,9 @@ func (m *HTTPClientModule) GetRegisteredEventTypes() []string {
 		EventTypeModuleStopped,
 		EventTypeConfigLoaded,
 		EventTypeTimeoutChanged,
+		EventTypeRequestCompleted,
+		EventTypeRequestFailed,
+		EventTypeRequestRetried,
+		EventTypeRetryBudgetExhausted,
 	}
 }
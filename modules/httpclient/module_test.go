@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -210,6 +211,50 @@ func TestHTTPClientModule_Client(t *testing.T) {
 }
 
 // TestHTTPClientModule_WithTimeout tests the WithTimeout method
+// auditingMockApplication counts the requests sent through the context audit.
+type auditingMockApplication struct {
+	*MockApplication
+	audited atomic.Int64
+}
+
+func (a *auditingMockApplication) AuditTransport(name string, base http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		a.audited.Add(1)
+		return base.RoundTrip(req)
+	})
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func TestHTTPClientModule_AuditsRequestsOfAuditingApplications(t *testing.T) {
+	var attempts atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	app := &auditingMockApplication{MockApplication: new(MockApplication)}
+	app.On("Logger").Return(&TestLogger{})
+	app.On("GetConfigSection", "httpclient").Return(modular.NewStdConfigProvider(&Config{
+		Retry: &RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond},
+	}), nil)
+	module := NewHTTPClientModule().(*HTTPClientModule)
+	require.NoError(t, module.Init(app))
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := module.Client().Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+
+	assert.Equal(t, int64(3), attempts.Load())
+	assert.Equal(t, int64(1), app.audited.Load(), "the request is audited once, not on every retry")
+}
+
 func TestHTTPClientModule_WithTimeout(t *testing.T) {
 	// Create module and manually set the HTTP client
 	module := &HTTPClientModule{
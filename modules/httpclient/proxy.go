@@ -0,0 +1,279 @@
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ProxyDirect is the proxy URL of an override sending matching hosts directly,
+// bypassing the default proxy.
+const ProxyDirect = "direct"
+
+// ProxyConfig routes outbound requests through an HTTP, HTTPS or SOCKS5 proxy.
+// HTTPS requests through an HTTP proxy are tunnelled with CONNECT.
+//
+// Like every setting of the module, the proxy can differ per configuration profile,
+// for example in config.prod.yaml:
+//
+//	proxy:
+//	  url: "http://proxy.corp.example:3128"
+//	  username: "svc-egress"
+//	  password: "${EGRESS_PROXY_PASSWORD}"
+//	  no_proxy: "localhost,127.0.0.1,10.0.0.0/8,.svc.cluster.local"
+//	  overrides:
+//	    - hosts: ["*.partner.example"]
+//	      url: "socks5://partner-gw.corp.example:1080"
+//	    - hosts: ["metadata.internal"]
+//	      url: "direct"
+type ProxyConfig struct {
+	// URL is the default proxy, with an http, https, socks5 or socks5h scheme.
+	// Credentials may be given in the URL or with Username and Password.
+	// Default: "" (no proxy)
+	URL string `yaml:"url" json:"url" env:"URL"`
+
+	// Username authenticates with the default proxy.
+	Username string `yaml:"username" json:"username" env:"USERNAME"`
+
+	// Password authenticates with the default proxy.
+	Password string `yaml:"password" json:"password" env:"PASSWORD"`
+
+	// NoProxy is a comma-separated list of hosts reached directly, in the format of
+	// the NO_PROXY environment variable: host names matching themselves and their
+	// subdomains ("example.com"), subdomains only (".example.com" or "*.example.com"),
+	// IP addresses, CIDR ranges, an optional ":port" suffix, or "*" for every host.
+	// Overrides take precedence.
+	NoProxy string `yaml:"no_proxy" json:"no_proxy" env:"NO_PROXY"`
+
+	// FromEnvironment reads the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment
+	// variables when the module initializes, and uses them when URL is empty.
+	// Default: false
+	FromEnvironment bool `yaml:"from_environment" json:"from_environment" env:"FROM_ENVIRONMENT"`
+
+	// Overrides choose another proxy for some hosts. The first override with a
+	// matching host pattern wins.
+	Overrides []ProxyOverride `yaml:"overrides" json:"overrides"`
+}
+
+// ProxyOverride routes requests to some hosts through a specific proxy.
+type ProxyOverride struct {
+	// Hosts are patterns in the format of ProxyConfig.NoProxy
+	Hosts []string `yaml:"hosts" json:"hosts"`
+
+	// URL is the proxy for the hosts, or "direct" to bypass proxies
+	URL string `yaml:"url" json:"url"`
+
+	// Username authenticates with the proxy.
+	Username string `yaml:"username" json:"username"`
+
+	// Password authenticates with the proxy.
+	Password string `yaml:"password" json:"password"`
+}
+
+// validate checks the proxy URLs and host patterns.
+func (c *ProxyConfig) validate() error {
+	if _, err := c.proxyFunc(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// proxyFunc returns the function choosing the proxy of each request, for
+// http.Transport.Proxy.
+func (c *ProxyConfig) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	defaultProxy, err := parseProxyURL(c.URL, c.Username, c.Password)
+	if err != nil {
+		return nil, err
+	}
+	noProxy, err := parseHostPatterns(strings.Split(c.NoProxy, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	type override struct {
+		hosts []hostPattern
+		proxy *url.URL // nil for direct connections
+	}
+	overrides := make([]override, 0, len(c.Overrides))
+	for i, o := range c.Overrides {
+		if len(o.Hosts) == 0 {
+			return nil, fmt.Errorf("%w: override %d has no hosts", ErrInvalidProxyConfig, i)
+		}
+		if o.URL == "" {
+			return nil, fmt.Errorf("%w: override %d has no url, use %q to bypass proxies", ErrInvalidProxyConfig, i, ProxyDirect)
+		}
+		hosts, err := parseHostPatterns(o.Hosts)
+		if err != nil {
+			return nil, err
+		}
+		var proxy *url.URL
+		if o.URL != ProxyDirect {
+			if proxy, err = parseProxyURL(o.URL, o.Username, o.Password); err != nil {
+				return nil, err
+			}
+		}
+		overrides = append(overrides, override{hosts: hosts, proxy: proxy})
+	}
+
+	httpsProxy := defaultProxy
+	if c.FromEnvironment && defaultProxy == nil {
+		if defaultProxy, err = parseProxyURL(getenvAny("HTTP_PROXY", "http_proxy"), "", ""); err != nil {
+			return nil, err
+		}
+		if httpsProxy, err = parseProxyURL(getenvAny("HTTPS_PROXY", "https_proxy"), "", ""); err != nil {
+			return nil, err
+		}
+		envNoProxy, err := parseHostPatterns(strings.Split(getenvAny("NO_PROXY", "no_proxy"), ","))
+		if err != nil {
+			return nil, err
+		}
+		noProxy = append(noProxy, envNoProxy...)
+	}
+
+	return func(req *http.Request) (*url.URL, error) {
+		host, port := requestHostPort(req.URL)
+		for _, o := range overrides {
+			if matchesAny(o.hosts, host, port) {
+				return o.proxy, nil
+			}
+		}
+		if matchesAny(noProxy, host, port) {
+			return nil, nil
+		}
+		if req.URL.Scheme == "https" {
+			return httpsProxy, nil
+		}
+		return defaultProxy, nil
+	}, nil
+}
+
+// getenvAny returns the first non-empty environment variable of names.
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseProxyURL parses a proxy URL, adding the credentials unless it has some.
+// An empty URL means no proxy.
+func parseProxyURL(raw, username, password string) (*url.URL, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidProxyConfig, err)
+	}
+	switch proxy.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("%w: unsupported proxy scheme %q in %s", ErrInvalidProxyConfig, proxy.Scheme, proxy.Redacted())
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("%w: proxy url %s has no host", ErrInvalidProxyConfig, proxy.Redacted())
+	}
+	if proxy.User == nil && username != "" {
+		proxy.User = url.UserPassword(username, password)
+	}
+	return proxy, nil
+}
+
+// hostPattern matches request hosts, like an entry of NO_PROXY.
+type hostPattern struct {
+	any    bool       // "*"
+	cidr   *net.IPNet // an IP range
+	host   string     // an exact host name or IP address
+	domain string     // a suffix such as ".example.com"
+	port   string     // the port to match, if any
+}
+
+// parseHostPatterns parses host patterns, skipping blank entries.
+func parseHostPatterns(entries []string) ([]hostPattern, error) {
+	patterns := make([]hostPattern, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			patterns = append(patterns, hostPattern{any: true})
+			continue
+		}
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			patterns = append(patterns, hostPattern{cidr: cidr})
+			continue
+		}
+
+		var pattern hostPattern
+		host := entry
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			host, pattern.port = h, p
+		}
+		host = strings.TrimPrefix(strings.Trim(host, "[]"), "*")
+		switch {
+		case host == "" || host == ".":
+			return nil, fmt.Errorf("%w: invalid host pattern %q", ErrInvalidProxyConfig, entry)
+		case strings.HasPrefix(host, "."):
+			pattern.domain = host
+		default:
+			pattern.host = host
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// matches reports whether the pattern matches a request to host and port. Host
+// names match the pattern's subdomains too, unless the pattern starts with a dot.
+func (p hostPattern) matches(host, port string) bool {
+	switch {
+	case p.any:
+		return true
+	case p.cidr != nil:
+		ip := net.ParseIP(host)
+		return ip != nil && p.cidr.Contains(ip)
+	case p.port != "" && p.port != port:
+		return false
+	case p.domain != "":
+		return strings.HasSuffix(host, p.domain)
+	default:
+		return host == p.host || (net.ParseIP(host) == nil && strings.HasSuffix(host, "."+p.host))
+	}
+}
+
+func matchesAny(patterns []hostPattern, host, port string) bool {
+	for _, p := range patterns {
+		if p.matches(host, port) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestHostPort returns the lowercased host of u and its port, defaulting by scheme.
+func requestHostPort(u *url.URL) (string, string) {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https", "wss":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	return strings.ToLower(u.Hostname()), port
+}
+
+// redactedProxyURL returns a proxy URL for logging, without its password.
+func redactedProxyURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Redacted()
+	}
+	return ""
+}
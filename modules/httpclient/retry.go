@@ -0,0 +1,490 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RetryServiceName is the name of the RoundTripperDecorator service adding the
+// module's retry policy to a transport.
+const RetryServiceName = "httpclient-retry"
+
+// Backoff strategies for RetryConfig.Backoff
+const (
+	BackoffExponential = "exponential"
+	BackoffLinear      = "linear"
+	BackoffConstant    = "constant"
+)
+
+// maxDrainedBodySize bounds how much of a retried response is read so its
+// connection can be reused.
+const maxDrainedBodySize = 64 << 10
+
+// maxTrackedRetryHosts bounds the per-host retry budgets kept before idle hosts are pruned.
+const maxTrackedRetryHosts = 1024
+
+// defaultRetryableStatusCodes are retried when RetryConfig.RetryableStatusCodes is empty.
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// defaultRetryableMethods are the idempotent methods retried when
+// RetryConfig.RetryableMethods is empty.
+var defaultRetryableMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodOptions,
+	http.MethodPut,
+	http.MethodDelete,
+	http.MethodTrace,
+}
+
+// RoundTripperDecorator wraps a transport with additional behavior.
+type RoundTripperDecorator func(http.RoundTripper) http.RoundTripper
+
+// RetryConfig retries failed requests with backoff. Requests are retried when the
+// transport returns an error or the response has a retryable status code, as long
+// as the method is retryable and the request body can be replayed (see
+// http.Request.GetBody, set by http.NewRequest for in-memory bodies).
+//
+// Example YAML configuration:
+//
+//	retry:
+//	  max_attempts: 4
+//	  backoff: exponential
+//	  initial_backoff: 200ms
+//	  max_backoff: 5s
+//	  jitter: true
+//	  retryable_status_codes: [429, 502, 503, 504]
+//	  retryable_methods: [GET, HEAD, PUT, DELETE]
+//	  budget:
+//	    ratio: 0.2
+//	    min_retries: 10
+//	    window: 10s
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first request.
+	// Default: 3
+	MaxAttempts int `yaml:"max_attempts" json:"max_attempts" env:"MAX_ATTEMPTS"`
+
+	// Backoff is the strategy computing the delay before each retry:
+	// "exponential" multiplies InitialBackoff by Multiplier for each retry,
+	// "linear" adds InitialBackoff for each retry, and "constant" always waits InitialBackoff.
+	// Default: "exponential"
+	Backoff string `yaml:"backoff" json:"backoff" env:"BACKOFF"`
+
+	// InitialBackoff is the delay before the first retry.
+	// Default: 100ms
+	InitialBackoff time.Duration `yaml:"initial_backoff" json:"initial_backoff" env:"INITIAL_BACKOFF"`
+
+	// MaxBackoff caps the delay before a retry.
+	// Default: 10s
+	MaxBackoff time.Duration `yaml:"max_backoff" json:"max_backoff" env:"MAX_BACKOFF"`
+
+	// Multiplier grows the delay of the exponential strategy.
+	// Default: 2
+	Multiplier float64 `yaml:"multiplier" json:"multiplier" env:"MULTIPLIER"`
+
+	// Jitter randomizes each delay between half and all of its value, so clients
+	// failing together do not retry together.
+	// Default: false
+	Jitter bool `yaml:"jitter" json:"jitter" env:"JITTER"`
+
+	// RetryableStatusCodes are the response status codes that are retried.
+	// Default: 429, 502, 503, 504
+	RetryableStatusCodes []int `yaml:"retryable_status_codes" json:"retryable_status_codes" env:"RETRYABLE_STATUS_CODES"`
+
+	// RetryableMethods are the request methods that are retried.
+	// Default: GET, HEAD, OPTIONS, PUT, DELETE, TRACE (the idempotent methods)
+	RetryableMethods []string `yaml:"retryable_methods" json:"retryable_methods" env:"RETRYABLE_METHODS"`
+
+	// IgnoreRetryAfter disables honouring the Retry-After header of 429 and 503
+	// responses. When honoured, a Retry-After longer than MaxBackoff ends the retries.
+	// Default: false
+	IgnoreRetryAfter bool `yaml:"ignore_retry_after" json:"ignore_retry_after" env:"IGNORE_RETRY_AFTER"`
+
+	// Budget limits retries per host, so that retries cannot multiply the load
+	// on a host that is already failing.
+	// Default: nil (unlimited)
+	Budget *RetryBudgetConfig `yaml:"budget" json:"budget"`
+}
+
+// RetryBudgetConfig limits the retries sent to each host within a time window
+// to MinRetries plus Ratio times the number of requests.
+type RetryBudgetConfig struct {
+	// Ratio is the number of retries allowed per request to the host.
+	// Default: 0.1
+	Ratio float64 `yaml:"ratio" json:"ratio" env:"RATIO"`
+
+	// MinRetries are always allowed within a window, so hosts receiving few
+	// requests can still be retried.
+	// Default: 10
+	MinRetries int `yaml:"min_retries" json:"min_retries" env:"MIN_RETRIES"`
+
+	// Window is the period over which requests and retries are counted.
+	// Default: 10s
+	Window time.Duration `yaml:"window" json:"window" env:"WINDOW"`
+}
+
+// validate checks the retry settings and sets defaults.
+func (c *RetryConfig) validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("%w: max_attempts must not be negative", ErrInvalidRetryConfig)
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 3
+	}
+
+	c.Backoff = strings.ToLower(c.Backoff)
+	switch c.Backoff {
+	case "":
+		c.Backoff = BackoffExponential
+	case BackoffExponential, BackoffLinear, BackoffConstant:
+	default:
+		return fmt.Errorf("%w: unknown backoff strategy %q", ErrInvalidRetryConfig, c.Backoff)
+	}
+
+	if c.InitialBackoff < 0 || c.MaxBackoff < 0 {
+		return fmt.Errorf("%w: backoff durations must not be negative", ErrInvalidRetryConfig)
+	}
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = 10 * time.Second
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		return fmt.Errorf("%w: max_backoff %s is less than initial_backoff %s",
+			ErrInvalidRetryConfig, c.MaxBackoff, c.InitialBackoff)
+	}
+
+	if c.Multiplier == 0 {
+		c.Multiplier = 2
+	}
+	if c.Multiplier < 1 {
+		return fmt.Errorf("%w: multiplier must be at least 1", ErrInvalidRetryConfig)
+	}
+
+	if len(c.RetryableStatusCodes) == 0 {
+		c.RetryableStatusCodes = append([]int(nil), defaultRetryableStatusCodes...)
+	}
+	for _, code := range c.RetryableStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("%w: invalid retryable status code %d", ErrInvalidRetryConfig, code)
+		}
+	}
+
+	if len(c.RetryableMethods) == 0 {
+		c.RetryableMethods = append([]string(nil), defaultRetryableMethods...)
+	}
+	for i, method := range c.RetryableMethods {
+		c.RetryableMethods[i] = strings.ToUpper(strings.TrimSpace(method))
+	}
+
+	if c.Budget != nil {
+		if c.Budget.Ratio < 0 || c.Budget.MinRetries < 0 || c.Budget.Window < 0 {
+			return fmt.Errorf("%w: budget settings must not be negative", ErrInvalidRetryConfig)
+		}
+		if c.Budget.Ratio == 0 {
+			c.Budget.Ratio = 0.1
+		}
+		if c.Budget.MinRetries == 0 {
+			c.Budget.MinRetries = 10
+		}
+		if c.Budget.Window == 0 {
+			c.Budget.Window = 10 * time.Second
+		}
+	}
+	return nil
+}
+
+// delay returns the backoff before the given retry, counting from 1.
+func (c *RetryConfig) delay(retry int) time.Duration {
+	var d float64
+	switch c.Backoff {
+	case BackoffConstant:
+		d = float64(c.InitialBackoff)
+	case BackoffLinear:
+		d = float64(c.InitialBackoff) * float64(retry)
+	default:
+		d = float64(c.InitialBackoff) * math.Pow(c.Multiplier, float64(retry-1))
+	}
+	if d > float64(c.MaxBackoff) {
+		d = float64(c.MaxBackoff)
+	}
+	if c.Jitter {
+		d = d/2 + rand.Float64()*d/2 //nolint:gosec // jitter does not need a secure source
+	}
+	return time.Duration(d)
+}
+
+// NewRetryTransport wraps next with the retry policy of config. Each transport
+// created this way has its own retry budget.
+func NewRetryTransport(next http.RoundTripper, config RetryConfig) (http.RoundTripper, error) {
+	if config.Budget != nil {
+		budget := *config.Budget
+		config.Budget = &budget
+	}
+	config.RetryableStatusCodes = append([]int(nil), config.RetryableStatusCodes...)
+	config.RetryableMethods = append([]string(nil), config.RetryableMethods...)
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return newRetryTransport(next, &config, newRetryBudget(config.Budget), nil), nil
+}
+
+// retryTransport retries failed requests according to a RetryConfig.
+type retryTransport struct {
+	Transport   http.RoundTripper
+	config      *RetryConfig
+	budget      *retryBudget
+	statusCodes map[int]bool
+	methods     map[string]bool
+	// notify receives retry events; it may be nil
+	notify auditFunc
+}
+
+// newRetryTransport creates a retry transport for a validated config.
+func newRetryTransport(next http.RoundTripper, config *RetryConfig, budget *retryBudget, notify auditFunc) *retryTransport {
+	t := &retryTransport{
+		Transport:   next,
+		config:      config,
+		budget:      budget,
+		statusCodes: make(map[int]bool, len(config.RetryableStatusCodes)),
+		methods:     make(map[string]bool, len(config.RetryableMethods)),
+		notify:      notify,
+	}
+	for _, code := range config.RetryableStatusCodes {
+		t.statusCodes[code] = true
+	}
+	for _, method := range config.RetryableMethods {
+		t.methods[method] = true
+	}
+	return t
+}
+
+// RoundTrip sends the request, retrying it while it fails and attempts and budget remain.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	t.budget.recordRequest(host)
+
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+	if !t.methods[req.Method] || !replayable || t.config.MaxAttempts < 2 {
+		return t.Transport.RoundTrip(req) //nolint:wrapcheck // transport errors are returned unchanged
+	}
+
+	ctx := req.Context()
+	attemptReq := req
+	for attempt := 1; ; attempt++ {
+		resp, err := t.Transport.RoundTrip(attemptReq)
+		if !t.shouldRetry(ctx, resp, err) || attempt >= t.config.MaxAttempts {
+			return resp, err //nolint:wrapcheck // transport errors are returned unchanged
+		}
+
+		delay := t.config.delay(attempt)
+		if resp != nil && !t.config.IgnoreRetryAfter {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				if retryAfter > t.config.MaxBackoff {
+					return resp, nil
+				}
+				delay = max(delay, retryAfter)
+			}
+		}
+
+		if !t.budget.tryRetry(host) {
+			t.emit(ctx, EventTypeRetryBudgetExhausted, map[string]interface{}{
+				"method":  req.Method,
+				"url":     req.URL.String(),
+				"host":    host,
+				"attempt": attempt,
+			})
+			return resp, err //nolint:wrapcheck // transport errors are returned unchanged
+		}
+
+		data := map[string]interface{}{
+			"method":   req.Method,
+			"url":      req.URL.String(),
+			"attempt":  attempt,
+			"delay_ms": delay.Milliseconds(),
+		}
+		if err != nil {
+			data["error"] = err.Error()
+		} else {
+			data["status_code"] = resp.StatusCode
+			drainBody(resp)
+		}
+		t.emit(ctx, EventTypeRequestRetried, data)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("request cancelled while waiting to retry: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		attemptReq = req.Clone(ctx)
+		if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("failed to replay request body: %w", bodyErr)
+			}
+			attemptReq.Body = body
+		}
+	}
+}
+
+// shouldRetry reports whether an attempt failed in a way worth retrying.
+func (t *retryTransport) shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return t.statusCodes[resp.StatusCode]
+}
+
+// emit sends a retry event when a notifier is configured.
+func (t *retryTransport) emit(ctx context.Context, eventType string, data map[string]interface{}) {
+	if t.notify != nil {
+		t.notify(ctx, eventType, data)
+	}
+}
+
+// drainBody reads and closes a response that is discarded for a retry, so its
+// connection can be reused.
+func drainBody(resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	_, _ = io.CopyN(io.Discard, resp.Body, maxDrainedBodySize)
+	_ = resp.Body.Close()
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// retryBudget counts requests and retries per host. A nil budget allows every retry.
+type retryBudget struct {
+	config RetryBudgetConfig
+	now    func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostRetryBudget
+}
+
+// hostRetryBudget counts a host's requests and retries in the current window.
+type hostRetryBudget struct {
+	windowStart time.Time
+	requests    int
+	retries     int
+}
+
+// newRetryBudget creates a budget, or returns nil when config is nil.
+func newRetryBudget(config *RetryBudgetConfig) *retryBudget {
+	if config == nil {
+		return nil
+	}
+	return &retryBudget{
+		config: *config,
+		now:    time.Now,
+		hosts:  make(map[string]*hostRetryBudget),
+	}
+}
+
+// host returns the counters of the host's current window. The caller holds mu.
+func (b *retryBudget) host(name string) *hostRetryBudget {
+	now := b.now()
+	h, ok := b.hosts[name]
+	if !ok {
+		if len(b.hosts) >= maxTrackedRetryHosts {
+			b.prune(now)
+		}
+		h = &hostRetryBudget{windowStart: now}
+		b.hosts[name] = h
+	}
+	if now.Sub(h.windowStart) >= b.config.Window {
+		*h = hostRetryBudget{windowStart: now}
+	}
+	return h
+}
+
+// prune forgets hosts whose window has ended. The caller holds mu.
+func (b *retryBudget) prune(now time.Time) {
+	for name, h := range b.hosts {
+		if now.Sub(h.windowStart) >= b.config.Window {
+			delete(b.hosts, name)
+		}
+	}
+}
+
+// recordRequest counts a request to the host.
+func (b *retryBudget) recordRequest(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.host(host).requests++
+}
+
+// tryRetry reserves a retry to the host, reporting false when the budget is spent.
+func (b *retryBudget) tryRetry(host string) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := b.host(host)
+	allowed := float64(b.config.MinRetries) + b.config.Ratio*float64(h.requests)
+	if float64(h.retries+1) > allowed {
+		return false
+	}
+	h.retries++
+	return true
+}
+
+// RetryDecorator returns a decorator adding the module's retry policy, configured
+// by Config.Retry, to a transport. Transports decorated this way share the
+// module's per-host retry budget. When retries are not configured the default
+// policy is used.
+//
+// The decorator is also provided as the "httpclient-retry" service:
+//
+//	decorate := services["httpclient-retry"].(httpclient.RoundTripperDecorator)
+//	client := &http.Client{Transport: decorate(myTransport)}
+func (m *HTTPClientModule) RetryDecorator() RoundTripperDecorator {
+	config, budget := m.retryConfig, m.retryBudget
+	if config == nil {
+		config = &RetryConfig{}
+		_ = config.validate() // Defaults are valid
+	}
+	return func(next http.RoundTripper) http.RoundTripper {
+		return newRetryTransport(next, config, budget, m.emitEvent)
+	}
+}
@@ -0,0 +1,382 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedTransport returns its responses in order, recording the requests' bodies.
+type scriptedTransport struct {
+	mu        sync.Mutex
+	responses []func() (*http.Response, error)
+	bodies    []string
+}
+
+func (t *scriptedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	body := ""
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		body = string(data)
+	}
+	t.bodies = append(t.bodies, body)
+	next := t.responses[0]
+	if len(t.responses) > 1 {
+		t.responses = t.responses[1:]
+	}
+	return next()
+}
+
+func (t *scriptedTransport) attempts() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.bodies)
+}
+
+func respond(status int, header ...string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("body"))}
+		for i := 0; i+1 < len(header); i += 2 {
+			resp.Header.Set(header[i], header[i+1])
+		}
+		return resp, nil
+	}
+}
+
+func fail(err error) func() (*http.Response, error) {
+	return func() (*http.Response, error) { return nil, err }
+}
+
+func newTestRetryTransport(t *testing.T, next http.RoundTripper, config RetryConfig) http.RoundTripper {
+	t.Helper()
+	if config.InitialBackoff == 0 {
+		config.InitialBackoff = time.Millisecond
+	}
+	transport, err := NewRetryTransport(next, config)
+	require.NoError(t, err)
+	return transport
+}
+
+func TestRetryTransport_RetriesRetryableStatus(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){
+		respond(http.StatusServiceUnavailable),
+		respond(http.StatusBadGateway),
+		respond(http.StatusOK),
+	}}
+	transport := newTestRetryTransport(t, next, RetryConfig{MaxAttempts: 3})
+
+	req, err := http.NewRequest(http.MethodPut, "http://api.example/items/1", strings.NewReader("payload"))
+	require.NoError(t, err)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"payload", "payload", "payload"}, next.bodies, "the body should be replayed for each attempt")
+}
+
+func TestRetryTransport_RetriesTransportErrors(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){
+		fail(errors.New("connection reset")),
+		respond(http.StatusOK),
+	}}
+	transport := newTestRetryTransport(t, next, RetryConfig{})
+
+	req := httptest.NewRequest(http.MethodGet, "http://api.example/", nil)
+	resp, err := transport.RoundTrip(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, 2, next.attempts())
+}
+
+func TestRetryTransport_StopsAfterMaxAttempts(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){respond(http.StatusServiceUnavailable)}}
+	transport := newTestRetryTransport(t, next, RetryConfig{MaxAttempts: 4})
+
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://api.example/", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "the last response should be returned")
+	assert.Equal(t, 4, next.attempts())
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "body", string(body))
+}
+
+func TestRetryTransport_DoesNotRetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RetryConfig
+		req    func() *http.Request
+		status int
+	}{
+		{
+			name:   "non-idempotent method",
+			req:    func() *http.Request { return httptest.NewRequest(http.MethodPost, "http://api.example/", nil) },
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "non-retryable status",
+			req:    func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://api.example/", nil) },
+			status: http.StatusInternalServerError,
+		},
+		{
+			name: "body cannot be replayed",
+			req: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPut, "http://api.example/", io.NopCloser(strings.NewReader("x")))
+				req.GetBody = nil
+				return req
+			},
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "single attempt",
+			config: RetryConfig{MaxAttempts: 1},
+			req:    func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://api.example/", nil) },
+			status: http.StatusServiceUnavailable,
+		},
+		{
+			name:   "retry-after beyond max backoff",
+			config: RetryConfig{MaxBackoff: time.Second},
+			req:    func() *http.Request { return httptest.NewRequest(http.MethodGet, "http://api.example/", nil) },
+			status: http.StatusTooManyRequests,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := &scriptedTransport{responses: []func() (*http.Response, error){
+				respond(tt.status, "Retry-After", "120"),
+				respond(http.StatusOK),
+			}}
+			transport := newTestRetryTransport(t, next, tt.config)
+
+			resp, err := transport.RoundTrip(tt.req())
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, 1, next.attempts())
+		})
+	}
+}
+
+func TestRetryTransport_HonoursRetryAfter(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){
+		respond(http.StatusTooManyRequests, "Retry-After", "1"),
+		respond(http.StatusOK),
+	}}
+	transport := newTestRetryTransport(t, next, RetryConfig{})
+
+	start := time.Now()
+	resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, "http://api.example/", nil))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "the retry should wait for Retry-After")
+}
+
+func TestRetryTransport_CancelledWhileWaiting(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){respond(http.StatusServiceUnavailable)}}
+	transport := newTestRetryTransport(t, next, RetryConfig{InitialBackoff: time.Minute, MaxBackoff: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodGet, "http://api.example/", nil).WithContext(ctx)
+
+	resp, err := transport.RoundTrip(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, resp)
+	assert.Equal(t, 1, next.attempts())
+}
+
+func TestRetryTransport_BudgetLimitsRetriesPerHost(t *testing.T) {
+	next := &scriptedTransport{responses: []func() (*http.Response, error){respond(http.StatusServiceUnavailable)}}
+	transport := newTestRetryTransport(t, next, RetryConfig{
+		MaxAttempts: 3,
+		Budget:      &RetryBudgetConfig{Ratio: 0.01, MinRetries: 2, Window: time.Minute},
+	})
+
+	send := func(url string) {
+		resp, err := transport.RoundTrip(httptest.NewRequest(http.MethodGet, url, nil))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	send("http://a.example/")
+	assert.Equal(t, 3, next.attempts(), "the first request should use the minimum retries")
+
+	send("http://a.example/")
+	assert.Equal(t, 4, next.attempts(), "the spent budget should stop retries to the host")
+
+	send("http://b.example/")
+	assert.Equal(t, 7, next.attempts(), "other hosts should have their own budget")
+}
+
+func TestRetryBudget_ResetsEachWindow(t *testing.T) {
+	now := time.Now()
+	budget := newRetryBudget(&RetryBudgetConfig{Ratio: 0.5, MinRetries: 1, Window: time.Second})
+	budget.now = func() time.Time { return now }
+
+	budget.recordRequest("a")
+	budget.recordRequest("a")
+	assert.True(t, budget.tryRetry("a"))
+	assert.True(t, budget.tryRetry("a"))
+	assert.False(t, budget.tryRetry("a"))
+
+	now = now.Add(time.Second)
+	assert.True(t, budget.tryRetry("a"))
+}
+
+func TestRetryConfig_Delay(t *testing.T) {
+	exponential := RetryConfig{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	require.NoError(t, exponential.validate())
+	assert.Equal(t, 100*time.Millisecond, exponential.delay(1))
+	assert.Equal(t, 400*time.Millisecond, exponential.delay(3))
+	assert.Equal(t, time.Second, exponential.delay(10), "delays should be capped at max_backoff")
+
+	linear := RetryConfig{Backoff: "linear", InitialBackoff: 100 * time.Millisecond}
+	require.NoError(t, linear.validate())
+	assert.Equal(t, 300*time.Millisecond, linear.delay(3))
+
+	constant := RetryConfig{Backoff: "Constant", InitialBackoff: 100 * time.Millisecond}
+	require.NoError(t, constant.validate())
+	assert.Equal(t, 100*time.Millisecond, constant.delay(5))
+
+	jitter := RetryConfig{InitialBackoff: 100 * time.Millisecond, Jitter: true}
+	require.NoError(t, jitter.validate())
+	for i := 0; i < 20; i++ {
+		d := jitter.delay(2)
+		assert.GreaterOrEqual(t, d, 100*time.Millisecond)
+		assert.LessOrEqual(t, d, 200*time.Millisecond)
+	}
+}
+
+func TestRetryConfig_Validate(t *testing.T) {
+	config := &Config{Retry: &RetryConfig{RetryableMethods: []string{"get", " put"}, Budget: &RetryBudgetConfig{}}}
+	require.NoError(t, config.Validate())
+	assert.Equal(t, 3, config.Retry.MaxAttempts)
+	assert.Equal(t, BackoffExponential, config.Retry.Backoff)
+	assert.Equal(t, []int{429, 502, 503, 504}, config.Retry.RetryableStatusCodes)
+	assert.Equal(t, []string{"GET", "PUT"}, config.Retry.RetryableMethods)
+	assert.Equal(t, 0.1, config.Retry.Budget.Ratio)
+	assert.Equal(t, 10, config.Retry.Budget.MinRetries)
+	assert.Equal(t, 10*time.Second, config.Retry.Budget.Window)
+
+	invalid := []*RetryConfig{
+		{MaxAttempts: -1},
+		{Backoff: "fibonacci"},
+		{InitialBackoff: time.Second, MaxBackoff: time.Millisecond},
+		{Multiplier: 0.5},
+		{RetryableStatusCodes: []int{42}},
+		{Budget: &RetryBudgetConfig{Ratio: -1}},
+	}
+	for _, retry := range invalid {
+		err := (&Config{Retry: retry}).Validate()
+		assert.ErrorIs(t, err, ErrInvalidRetryConfig, "%+v", retry)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, d, float64(2*time.Second))
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("-1")
+	assert.False(t, ok)
+}
+
+// newRetryTestModule initializes the module with retry settings.
+func newRetryTestModule(t *testing.T, retry *RetryConfig) *HTTPClientModule {
+	t.Helper()
+	config := &Config{Retry: retry}
+	require.NoError(t, config.Validate())
+
+	mockApp := new(MockApplication)
+	mockApp.On("Logger").Return(&TestLogger{})
+	mockApp.On("GetConfigSection", "httpclient").Return(modular.NewStdConfigProvider(config), nil)
+	module := NewHTTPClientModule().(*HTTPClientModule)
+	require.NoError(t, module.Init(mockApp))
+	t.Cleanup(module.transport.CloseIdleConnections)
+	return module
+}
+
+// flakyServer fails its first failures requests with 503 Service Unavailable.
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestModule_ClientRetriesWhenConfigured(t *testing.T) {
+	server, requests := flakyServer(t, 2)
+	module := newRetryTestModule(t, &RetryConfig{InitialBackoff: time.Millisecond})
+
+	resp, err := module.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestModule_ClientDoesNotRetryByDefault(t *testing.T) {
+	server, requests := flakyServer(t, 1)
+	module := newRetryTestModule(t, nil)
+
+	resp, err := module.Client().Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestModule_RetryDecoratorService(t *testing.T) {
+	server, requests := flakyServer(t, 1)
+	module := newRetryTestModule(t, &RetryConfig{InitialBackoff: time.Millisecond})
+
+	var decorate RoundTripperDecorator
+	for _, svc := range module.ProvidesServices() {
+		if svc.Name == RetryServiceName {
+			decorate = svc.Instance.(RoundTripperDecorator)
+		}
+	}
+	require.NotNil(t, decorate)
+
+	client := &http.Client{Transport: decorate(http.DefaultTransport)}
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+}
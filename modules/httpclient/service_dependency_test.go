@@ -51,7 +51,7 @@ func TestServiceDependencyResolution(t *testing.T) {
 	require.True(t, ok, "httpclient should be ServiceAware")
 
 	providedServices := serviceAware.ProvidesServices()
-	require.Len(t, providedServices, 2, "httpclient should provide 2 services")
+	require.Len(t, providedServices, 3, "httpclient should provide 3 services")
 
 	// Verify service names and that the http.Client implements HTTPDoer
 	serviceNames := make(map[string]bool)
@@ -64,6 +64,7 @@ func TestServiceDependencyResolution(t *testing.T) {
 	}
 	assert.True(t, serviceNames["httpclient"], "should provide 'httpclient' service")
 	assert.True(t, serviceNames["httpclient-service"], "should provide 'httpclient-service' service")
+	assert.True(t, serviceNames["httpclient-retry"], "should provide 'httpclient-retry' service")
 
 	// Test that the HTTP client implements the HTTPDoer interface
 	require.NotNil(t, httpClient)
@@ -133,6 +133,9 @@ func (ctx *HTTPServerBDDTestContext) iHaveAnHTTPServerWithTLSAndEventObservation
 		WriteTimeout:    30 * time.Second,
 		IdleTimeout:     120 * time.Second,
 		ShutdownTimeout: 10 * time.Second,
+		// Drain on shutdown so that drain events are emitted
+		DrainTimeout:          10 * time.Second,
+		DrainProgressInterval: 10 * time.Millisecond,
 		TLS: &TLSConfig{
 			Enabled:      true,
 			CertFile:     "",
@@ -174,6 +177,10 @@ func (ctx *HTTPServerBDDTestContext) iHaveAnHTTPServerWithTLSAndEventObservation
 			fmt.Printf("Warning: Failed to write test response: %v\n", err)
 		}
 	})
+	router.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	})
 
 	// Register the router service
 	if err := ctx.app.RegisterService("router", router); err != nil {
@@ -222,6 +229,43 @@ func (ctx *HTTPServerBDDTestContext) iHaveAnHTTPServerWithTLSAndEventObservation
 	return nil
 }
 
+func (ctx *HTTPServerBDDTestContext) aSlowRequestIsInFlight() error {
+	if ctx.service == nil || ctx.service.server == nil {
+		return fmt.Errorf("server not available")
+	}
+	client := &http.Client{Timeout: 5 * time.Second, Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	go func() {
+		resp, err := client.Get(fmt.Sprintf("https://%s/slow", ctx.service.server.Addr))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for ctx.service.drain.inFlight.Load() == 0 {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("slow request did not reach the server")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return nil
+}
+
+func (ctx *HTTPServerBDDTestContext) drainEventsShouldBeEmitted() error {
+	time.Sleep(100 * time.Millisecond) // Allow time for asynchronous event delivery
+
+	emitted := make(map[string]bool)
+	for _, event := range ctx.eventObserver.GetEvents() {
+		emitted[event.Type()] = true
+	}
+	for _, eventType := range []string{EventTypeServerDrainStarted, EventTypeServerDrainProgress, EventTypeServerDrainCompleted} {
+		if !emitted[eventType] {
+			return fmt.Errorf("event of type %s was not emitted", eventType)
+		}
+	}
+	return nil
+}
+
 func (ctx *HTTPServerBDDTestContext) aServerStartedEventShouldBeEmitted() error {
 	time.Sleep(500 * time.Millisecond) // Allow time for server startup and event emission
 
@@ -507,6 +551,7 @@ func TestHTTPServerModuleEvents(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Background
 			ctx.Given(`^I have a modular application with httpserver module configured$`, testCtx.iHaveAModularApplicationWithHTTPServerModuleConfigured)
@@ -560,6 +605,8 @@ func TestHTTPServerModuleEvents(t *testing.T) {
 			// Additional When steps
 			ctx.When(`^the server processes requests$`, testCtx.theServerProcessesRequests)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.When(`^I request the health check endpoint$`, testCtx.iRequestTheHealthCheckEndpoint)
 			ctx.When(`^I register custom handlers with the server$`, testCtx.iRegisterCustomHandlersWithTheServer)
 			ctx.When(`^requests are processed through the server$`, testCtx.requestsAreProcessedThroughTheServer)
@@ -157,6 +157,7 @@ func TestHTTPServerModuleHealthAndMonitoring(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Use common scenario setup to reduce duplication
 			setupCommonBDDScenarios(ctx, testCtx)
@@ -187,6 +188,8 @@ func TestHTTPServerModuleHealthAndMonitoring(t *testing.T) {
 			// Additional When steps
 			ctx.When(`^the HTTPS server is started$`, testCtx.theHTTPSServerIsStarted)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.When(`^I register custom handlers with the server$`, testCtx.iRegisterCustomHandlersWithTheServer)
 			ctx.When(`^requests are processed through the server$`, testCtx.requestsAreProcessedThroughTheServer)
 			ctx.When(`^the HTTPS server is started with auto-generation$`, testCtx.theHTTPSServerIsStartedWithAutoGeneration)
@@ -301,6 +301,7 @@ func TestHTTPServerModuleLifecycle(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Background
 			ctx.Given(`^I have a modular application with httpserver module configured$`, testCtx.iHaveAModularApplicationWithHTTPServerModuleConfigured)
@@ -327,6 +328,8 @@ func TestHTTPServerModuleLifecycle(t *testing.T) {
 			ctx.Given(`^I have a running HTTP server$`, testCtx.iHaveARunningHTTPServer)
 			ctx.Given(`^I have an HTTP server running$`, testCtx.iHaveAnHTTPServerRunning)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.Then(`^the server should stop accepting new connections$`, testCtx.theServerShouldStopAcceptingNewConnections)
 			ctx.Then(`^existing connections should be allowed to complete$`, testCtx.existingConnectionsShouldBeAllowedToComplete)
 			ctx.Then(`^the shutdown should complete within the timeout$`, testCtx.theShutdownShouldCompleteWithinTheTimeout)
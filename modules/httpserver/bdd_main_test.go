@@ -1,6 +1,7 @@
 package httpserver
 
 import (
+	"context"
 	"testing"
 
 	"github.com/cucumber/godog"
@@ -24,12 +25,22 @@ func setupCommonBDDScenarios(ctx *godog.ScenarioContext, testCtx *HTTPServerBDDT
 	ctx.Then(`^the server should accept HTTP requests$`, testCtx.theServerShouldAcceptHTTPRequests)
 }
 
+// stopServerAfterScenario stops the scenario's server once it finishes, so the next
+// scenario can bind the same port.
+func stopServerAfterScenario(ctx *godog.ScenarioContext, testCtx *HTTPServerBDDTestContext) {
+	ctx.After(func(c context.Context, _ *godog.Scenario, err error) (context.Context, error) {
+		testCtx.resetContext()
+		return c, err
+	})
+}
+
 // TestHTTPServerModuleBDD runs the complete BDD test suite for the HTTP server module
 // This is the main entry point that registers all scenario steps from all themed test files
 func TestHTTPServerModuleBDD(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Set up common scenarios to reduce duplication
 			setupCommonBDDScenarios(ctx, testCtx)
@@ -51,6 +62,8 @@ func TestHTTPServerModuleBDD(t *testing.T) {
 			ctx.Given(`^I have a running HTTP server$`, testCtx.iHaveARunningHTTPServer)
 			ctx.Given(`^I have an HTTP server running$`, testCtx.iHaveAnHTTPServerRunning)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.Then(`^the server should stop accepting new connections$`, testCtx.theServerShouldStopAcceptingNewConnections)
 			ctx.Then(`^existing connections should be allowed to complete$`, testCtx.existingConnectionsShouldBeAllowedToComplete)
 			ctx.Then(`^the shutdown should complete within the timeout$`, testCtx.theShutdownShouldCompleteWithinTheTimeout)
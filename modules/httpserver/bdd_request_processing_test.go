@@ -234,6 +234,7 @@ func TestHTTPServerModuleRequestProcessing(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Background
 			ctx.Given(`^I have a modular application with httpserver module configured$`, testCtx.iHaveAModularApplicationWithHTTPServerModuleConfigured)
@@ -279,6 +280,8 @@ func TestHTTPServerModuleRequestProcessing(t *testing.T) {
 			ctx.When(`^the HTTPS server is started$`, testCtx.theHTTPSServerIsStarted)
 			ctx.When(`^the server processes requests$`, testCtx.theServerProcessesRequests)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.When(`^I request the health check endpoint$`, testCtx.iRequestTheHealthCheckEndpoint)
 			ctx.When(`^the HTTPS server is started with auto-generation$`, testCtx.theHTTPSServerIsStartedWithAutoGeneration)
 			ctx.When(`^the httpserver module starts$`, func() error { return nil })
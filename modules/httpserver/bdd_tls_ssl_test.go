@@ -198,6 +198,7 @@ func TestHTTPServerModuleTLS(t *testing.T) {
 	suite := godog.TestSuite{
 		ScenarioInitializer: func(ctx *godog.ScenarioContext) {
 			testCtx := &HTTPServerBDDTestContext{}
+			stopServerAfterScenario(ctx, testCtx)
 
 			// Use common scenario setup to reduce duplication
 			setupCommonBDDScenarios(ctx, testCtx)
@@ -228,6 +229,8 @@ func TestHTTPServerModuleTLS(t *testing.T) {
 			// Additional When steps
 			ctx.When(`^the server processes requests$`, testCtx.theServerProcessesRequests)
 			ctx.When(`^the server shutdown is initiated$`, testCtx.theServerShutdownIsInitiated)
+			ctx.When(`^a slow request is in flight$`, testCtx.aSlowRequestIsInFlight)
+			ctx.Then(`^drain events should be emitted$`, testCtx.drainEventsShouldBeEmitted)
 			ctx.When(`^I request the health check endpoint$`, testCtx.iRequestTheHealthCheckEndpoint)
 			ctx.When(`^I register custom handlers with the server$`, testCtx.iRegisterCustomHandlersWithTheServer)
 			ctx.When(`^requests are processed through the server$`, testCtx.requestsAreProcessedThroughTheServer)
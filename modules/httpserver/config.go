@@ -13,6 +13,10 @@ var (
 	ErrTLSNoDomainsSpecified = errors.New("TLS auto-generation is enabled but no domains specified")
 	ErrTLSNoCertificateFile  = errors.New("TLS is enabled but no certificate file specified")
 	ErrTLSNoKeyFile          = errors.New("TLS is enabled but no key file specified")
+	ErrInvalidListener       = errors.New("invalid listener configuration")
+	ErrInvalidDrainTimeout   = errors.New("drain timeout must not be negative")
+	ErrInvalidProtocols      = errors.New("invalid server protocols")
+	ErrInvalidHTTP2Config    = errors.New("invalid HTTP/2 configuration")
 )
 
 // DefaultTimeout is the default timeout value
@@ -34,12 +38,31 @@ type HTTPServerConfig struct {
 	WriteTimeout time.Duration `yaml:"write_timeout" json:"write_timeout" env:"WRITE_TIMEOUT"`
 
 	// IdleTimeout is the maximum amount of time to wait for the next request.
+	// It also closes HTTP/2 connections that have had no open streams this long.
 	IdleTimeout time.Duration `yaml:"idle_timeout" json:"idle_timeout" env:"IDLE_TIMEOUT"`
 
 	// ShutdownTimeout is the maximum amount of time to wait during graceful
 	// shutdown.
 	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" json:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
 
+	// DrainTimeout enables draining on Stop: the server stops accepting connections
+	// and waits up to DrainTimeout for in-flight requests, reporting progress with
+	// drain events, before closing the connections that remain. It replaces
+	// ShutdownTimeout when set. Default: 0 (no draining).
+	DrainTimeout time.Duration `yaml:"drain_timeout" json:"drain_timeout" env:"DRAIN_TIMEOUT"`
+
+	// DrainRejectNewRequests answers requests arriving on open connections during a
+	// drain with 503 Service Unavailable and a Retry-After header.
+	DrainRejectNewRequests bool `yaml:"drain_reject_new_requests" json:"drain_reject_new_requests" env:"DRAIN_REJECT_NEW_REQUESTS"`
+
+	// DrainRetryAfter is the Retry-After sent with requests rejected during a drain.
+	// Default: 5s
+	DrainRetryAfter time.Duration `yaml:"drain_retry_after" json:"drain_retry_after" env:"DRAIN_RETRY_AFTER"`
+
+	// DrainProgressInterval is how often drain progress events report the requests
+	// still in flight. Default: 1s
+	DrainProgressInterval time.Duration `yaml:"drain_progress_interval" json:"drain_progress_interval" env:"DRAIN_PROGRESS_INTERVAL"`
+
 	// MaxHeaderBytes limits the total size of HTTP request headers the server
 	// will accept. Rejects oversized requests with 431 before parsing the body.
 	// Go's built-in default is 1MB; a tighter limit reduces DoS surface.
@@ -48,6 +71,50 @@ type HTTPServerConfig struct {
 
 	// TLS configuration if HTTPS is enabled
 	TLS *TLSConfig `yaml:"tls" json:"tls"`
+
+	// Protocols lists the protocols the server accepts: "http1", "http2" (HTTP/2
+	// over TLS) and "h2c" (HTTP/2 over cleartext TCP). Default: http1 and http2.
+	Protocols []string `yaml:"protocols" json:"protocols" env:"PROTOCOLS"`
+
+	// HTTP2 tunes HTTP/2 connections, over TLS or h2c.
+	HTTP2 *HTTP2Config `yaml:"http2" json:"http2"`
+
+	// Listeners are additional named HTTP listeners, each serving its own handler
+	// tree. Use them to keep admin, debug or metrics endpoints off the public port.
+	// Modules register routes on a listener through ListenerRouter.
+	Listeners map[string]*ListenerConfig `yaml:"listeners" json:"listeners"`
+}
+
+// ListenerConfig configures a named listener. Named listeners serve plain HTTP and
+// share the server's timeouts, header limits and HTTP/2 settings; they accept
+// HTTP/1, and h2c when it is one of the server's protocols.
+type ListenerConfig struct {
+	// Host is the hostname or IP address to bind to. Default: 127.0.0.1, so
+	// listeners are only reachable from the local machine unless configured otherwise.
+	Host string `yaml:"host" json:"host"`
+
+	// Port is the port number to listen on. Required.
+	Port int `yaml:"port" json:"port"`
+}
+
+// HTTP2Config tunes HTTP/2 connections. Zero values keep Go's defaults.
+type HTTP2Config struct {
+	// MaxConcurrentStreams is how many requests a client may have in flight at
+	// once on one connection. Default: Go's default, at least 100.
+	MaxConcurrentStreams int `yaml:"max_concurrent_streams" json:"max_concurrent_streams" env:"HTTP2_MAX_CONCURRENT_STREAMS"`
+
+	// MaxReadFrameSize is the largest frame the server reads, between 16KiB and
+	// 16MiB. Default: Go's default.
+	MaxReadFrameSize int `yaml:"max_read_frame_size" json:"max_read_frame_size" env:"HTTP2_MAX_READ_FRAME_SIZE"`
+
+	// PingInterval pings connections that have received nothing for this long,
+	// so peers that went away without closing their connection are detected.
+	// Default: 0 (no pings).
+	PingInterval time.Duration `yaml:"ping_interval" json:"ping_interval" env:"HTTP2_PING_INTERVAL"`
+
+	// PingTimeout closes a connection whose ping is not answered within it.
+	// Default: 15s
+	PingTimeout time.Duration `yaml:"ping_timeout" json:"ping_timeout" env:"HTTP2_PING_TIMEOUT"`
 }
 
 // TLSConfig holds the TLS configuration for HTTPS support
@@ -112,6 +179,37 @@ func (c *HTTPServerConfig) Validate() error {
 		c.MaxHeaderBytes = 32 * 1024 // 32KB
 	}
 
+	if c.DrainTimeout < 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidDrainTimeout, c.DrainTimeout)
+	}
+
+	if c.DrainRetryAfter <= 0 {
+		c.DrainRetryAfter = 5 * time.Second
+	}
+
+	if c.DrainProgressInterval <= 0 {
+		c.DrainProgressInterval = time.Second
+	}
+
+	if err := c.validateProtocols(); err != nil {
+		return err
+	}
+
+	for name, listener := range c.Listeners {
+		if name == "" || listener == nil {
+			return fmt.Errorf("%w: listener %q is empty", ErrInvalidListener, name)
+		}
+		if listener.Host == "" {
+			listener.Host = "127.0.0.1"
+		}
+		if listener.Port <= 0 || listener.Port > 65535 {
+			return fmt.Errorf("%w: listener %q: %w: %d", ErrInvalidListener, name, ErrInvalidPort, listener.Port)
+		}
+		if listener.Host == c.Host && listener.Port == c.Port {
+			return fmt.Errorf("%w: listener %q uses the server address", ErrInvalidListener, name)
+		}
+	}
+
 	// Validate TLS configuration if enabled
 	if c.TLS != nil && c.TLS.Enabled {
 		// If using service, we don't need cert/key files
@@ -0,0 +1,109 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// drainState tracks the requests in flight so that Stop can drain them.
+type drainState struct {
+	inFlight atomic.Int64
+	draining atomic.Bool
+}
+
+// wrapHandlerWithDrain counts the requests in flight and, during a drain, closes
+// connections after their current request and optionally rejects new requests.
+func (m *HTTPServerModule) wrapHandlerWithDrain(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.drain.draining.Load() {
+			w.Header().Set("Connection", "close")
+			if cfg := m.currentConfig(); cfg.DrainRejectNewRequests {
+				retryAfter := int(math.Ceil(cfg.DrainRetryAfter.Seconds()))
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, "server is shutting down", http.StatusServiceUnavailable)
+				return
+			}
+		}
+
+		m.drain.inFlight.Add(1)
+		defer m.drain.inFlight.Add(-1)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// drainServers stops the server and named listeners from accepting connections and
+// waits up to DrainTimeout for the requests in flight, emitting drain events. The
+// connections still open when the timeout expires are closed.
+func (m *HTTPServerModule) drainServers(ctx context.Context) error {
+	start := time.Now()
+	m.drain.draining.Store(true)
+	m.emitDrainEvent(ctx, EventTypeServerDrainStarted, map[string]interface{}{
+		"in_flight": m.drain.inFlight.Load(),
+		"timeout":   m.config.DrainTimeout.String(),
+	})
+
+	drainCtx, cancel := context.WithTimeout(ctx, m.config.DrainTimeout)
+	defer cancel()
+
+	stopProgress := make(chan struct{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		ticker := time.NewTicker(m.config.DrainProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopProgress:
+				return
+			case <-ticker.C:
+				m.emitDrainEvent(ctx, EventTypeServerDrainProgress, map[string]interface{}{
+					"in_flight": m.drain.inFlight.Load(),
+					"elapsed":   time.Since(start).String(),
+				})
+			}
+		}
+	}()
+
+	err := m.server.Shutdown(drainCtx)
+	listenersErr := m.stopListeners(drainCtx)
+	close(stopProgress)
+	<-progressDone
+
+	timedOut := errors.Is(err, context.DeadlineExceeded) || errors.Is(listenersErr, context.DeadlineExceeded)
+	remaining := m.drain.inFlight.Load()
+	if timedOut {
+		_ = m.server.Close()
+	}
+	m.emitDrainEvent(ctx, EventTypeServerDrainCompleted, map[string]interface{}{
+		"in_flight": remaining,
+		"elapsed":   time.Since(start).String(),
+		"timed_out": timedOut,
+	})
+
+	if timedOut {
+		m.logger.Warn("HTTP server drain timed out", "in_flight", remaining, "timeout", m.config.DrainTimeout)
+		return fmt.Errorf("%w: %d after %s", ErrDrainTimeout, remaining, m.config.DrainTimeout)
+	}
+	if err != nil {
+		return fmt.Errorf("error shutting down HTTP server: %w", err)
+	}
+	return listenersErr
+}
+
+// emitDrainEvent emits a drain event, logging emission failures.
+func (m *HTTPServerModule) emitDrainEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	data["host"] = m.config.Host
+	data["port"] = m.config.Port
+	event := modular.NewCloudEvent(eventType, "httpserver-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+		m.logger.Debug("Failed to emit drain event", "event", eventType, "error", emitErr)
+	}
+}
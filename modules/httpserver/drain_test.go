@@ -0,0 +1,162 @@
+package httpserver
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drainTestSubject delivers events to a testEventObserver.
+type drainTestSubject struct {
+	observer *testEventObserver
+}
+
+func (s *drainTestSubject) RegisterObserver(modular.Observer, ...string) error { return nil }
+func (s *drainTestSubject) UnregisterObserver(modular.Observer) error          { return nil }
+func (s *drainTestSubject) GetObservers() []modular.ObserverInfo               { return nil }
+func (s *drainTestSubject) NotifyObservers(ctx context.Context, event cloudevents.Event) error {
+	return s.observer.OnEvent(ctx, event)
+}
+
+// newDrainTestModule starts a server whose /slow requests block until release is closed.
+func newDrainTestModule(t *testing.T, drainTimeout time.Duration, release <-chan struct{}) (*HTTPServerModule, *testEventObserver) {
+	t.Helper()
+	observer := newTestEventObserver()
+	module := &HTTPServerModule{subject: &drainTestSubject{observer: observer}}
+	module.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	module.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			<-release
+		}
+		_, _ = w.Write([]byte("done"))
+	})
+	module.config = &HTTPServerConfig{
+		Host:                  "127.0.0.1",
+		Port:                  freePort(t),
+		DrainTimeout:          drainTimeout,
+		DrainProgressInterval: 10 * time.Millisecond,
+	}
+	require.NoError(t, module.config.Validate())
+	require.NoError(t, module.Start(context.Background()))
+	return module, observer
+}
+
+// startSlowRequest issues a /slow request and waits until the server is handling it.
+func startSlowRequest(t *testing.T, module *HTTPServerModule) <-chan error {
+	t.Helper()
+	result := make(chan error, 1)
+	go func() {
+		resp, err := http.Get("http://" + module.server.Addr + "/slow")
+		if err == nil {
+			resp.Body.Close()
+		}
+		result <- err
+	}()
+	require.Eventually(t, func() bool { return module.drain.inFlight.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+	return result
+}
+
+func eventData(t *testing.T, observer *testEventObserver, eventType string) map[string]interface{} {
+	t.Helper()
+	var data map[string]interface{}
+	require.Eventually(t, func() bool {
+		for _, event := range observer.GetEvents() {
+			if event.Type() == eventType {
+				return event.DataAs(&data) == nil
+			}
+		}
+		return false
+	}, 2*time.Second, 5*time.Millisecond, "expected a %s event", eventType)
+	return data
+}
+
+func TestDrain_WaitsForInFlightRequests(t *testing.T) {
+	release := make(chan struct{})
+	module, observer := newDrainTestModule(t, 5*time.Second, release)
+	slow := startSlowRequest(t, module)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- module.Stop(context.Background()) }()
+
+	// Progress is reported while the request runs
+	assert.Equal(t, float64(1), eventData(t, observer, EventTypeServerDrainStarted)["in_flight"])
+	assert.Equal(t, float64(1), eventData(t, observer, EventTypeServerDrainProgress)["in_flight"])
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + module.server.Addr + "/")
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err != nil
+	}, 2*time.Second, 5*time.Millisecond, "no new connections are accepted during the drain")
+
+	close(release)
+	require.NoError(t, <-stopped)
+	require.NoError(t, <-slow, "the in-flight request completes")
+
+	completed := eventData(t, observer, EventTypeServerDrainCompleted)
+	assert.Equal(t, false, completed["timed_out"])
+	assert.Equal(t, float64(0), completed["in_flight"])
+	assert.False(t, module.started)
+}
+
+func TestDrain_TimeoutClosesRemainingConnections(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	module, observer := newDrainTestModule(t, 50*time.Millisecond, release)
+	slow := startSlowRequest(t, module)
+
+	err := module.Stop(context.Background())
+	require.ErrorIs(t, err, ErrDrainTimeout)
+	assert.Error(t, <-slow, "the request still in flight is cut off")
+	assert.False(t, module.started, "the server is stopped despite the timeout")
+
+	completed := eventData(t, observer, EventTypeServerDrainCompleted)
+	assert.Equal(t, true, completed["timed_out"])
+	assert.Equal(t, float64(1), completed["in_flight"])
+}
+
+func TestDrain_NewRequestsDuringDrain(t *testing.T) {
+	module := &HTTPServerModule{config: &HTTPServerConfig{DrainRejectNewRequests: true}}
+	require.NoError(t, module.config.Validate())
+	handler := module.wrapHandlerWithDrain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Connection"))
+
+	module.drain.draining.Store(true)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "5", w.Header().Get("Retry-After"))
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+
+	module.config.DrainRejectNewRequests = false
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "requests are served during the drain unless rejection is enabled")
+	assert.Equal(t, "close", w.Header().Get("Connection"))
+}
+
+func TestDrainConfig_Validate(t *testing.T) {
+	cfg := &HTTPServerConfig{}
+	require.NoError(t, cfg.Validate())
+	assert.Zero(t, cfg.DrainTimeout, "draining is off by default")
+	assert.Equal(t, 5*time.Second, cfg.DrainRetryAfter)
+	assert.Equal(t, time.Second, cfg.DrainProgressInterval)
+
+	cfg = &HTTPServerConfig{DrainTimeout: -time.Second}
+	assert.ErrorIs(t, cfg.Validate(), ErrInvalidDrainTimeout)
+}
@@ -8,4 +8,20 @@ import (
 var (
 	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
 	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrUnknownListener is returned when routes target a listener that is not configured
+	ErrUnknownListener = errors.New("unknown listener")
+
+	// ErrInvalidRoute is returned by HandleOn when a listener can't serve a pattern, such as a
+	// duplicate or conflicting pattern
+	ErrInvalidRoute = errors.New("invalid route")
+
+	// ErrDrainTimeout is returned by Stop when requests are still in flight once the drain timeout expires
+	ErrDrainTimeout = errors.New("drain timed out with requests in flight")
+
+	// ErrReloadRequiresRestart is returned by Reload when a changed setting only applies after a restart
+	ErrReloadRequiresRestart = errors.New("configuration change requires a restart")
+
+	// ErrUnexpectedConfigType is returned when the config section holds another type than HTTPServerConfig
+	ErrUnexpectedConfigType = errors.New("unexpected config type")
 )
@@ -7,6 +7,11 @@ const (
 	EventTypeServerStarted = "com.modular.httpserver.server.started"
 	EventTypeServerStopped = "com.modular.httpserver.server.stopped"
 
+	// Drain events, emitted when Stop drains in-flight requests (see DrainTimeout)
+	EventTypeServerDrainStarted   = "com.modular.httpserver.server.drain.started"
+	EventTypeServerDrainProgress  = "com.modular.httpserver.server.drain.progress"
+	EventTypeServerDrainCompleted = "com.modular.httpserver.server.drain.completed"
+
 	// Request handling events
 	EventTypeRequestReceived = "com.modular.httpserver.request.received"
 	EventTypeRequestHandled  = "com.modular.httpserver.request.handled"
@@ -97,6 +97,7 @@ Feature: HTTP Server Module
   Scenario: All registered httpserver events are emitted
     Given I have an httpserver with TLS and event observation enabled
     When the httpserver processes a request
+    And a slow request is in flight
     And the server shutdown is initiated
     Then a server started event should be emitted
     And a config loaded event should be emitted
@@ -104,4 +105,5 @@ Feature: HTTP Server Module
     And a TLS configured event should be emitted
     And a request received event should be emitted
     And a request handled event should be emitted
+    And drain events should be emitted
     And all registered events should be emitted during testing
\ No newline at end of file
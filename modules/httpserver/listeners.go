@@ -0,0 +1,183 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// ListenerRouter registers routes on the server's named listeners. The httpserver
+// service implements it, so modules can keep admin endpoints off the public port:
+//
+//	var router httpserver.ListenerRouter
+//	if err := app.GetService("httpserver", &router); err != nil {
+//	    return err
+//	}
+//	router.HandleOn("admin", "/debug/vars", expvar.Handler())
+type ListenerRouter interface {
+	// HandleOn registers handler for pattern on the named listener. Patterns
+	// follow http.ServeMux syntax.
+	HandleOn(listener, pattern string, handler http.Handler) error
+
+	// ListenerAddress returns the address the named listener is bound to, or an
+	// empty string if it is not running.
+	ListenerAddress(listener string) string
+}
+
+var _ ListenerRouter = (*HTTPServerModule)(nil)
+
+// namedListener is the handler tree and server of a configured listener.
+type namedListener struct {
+	mux     *http.ServeMux
+	server  *http.Server
+	port    *sharedListener
+	address string
+}
+
+// HandleOn registers handler for pattern on the named listener. Routes may be
+// registered before the listener's configuration is loaded; listeners that are
+// not configured by the time the server starts cause Start to fail. A pattern
+// the listener already serves, or one http.ServeMux rejects, returns an
+// ErrInvalidRoute error.
+func (m *HTTPServerModule) HandleOn(listener, pattern string, handler http.Handler) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.config != nil {
+		if _, ok := m.config.Listeners[listener]; !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownListener, listener)
+		}
+	}
+	if m.listeners == nil {
+		m.listeners = make(map[string]*namedListener)
+	}
+	named, ok := m.listeners[listener]
+	if !ok {
+		named = &namedListener{mux: http.NewServeMux()}
+	}
+	if err := handleOnMux(named.mux, pattern, handler); err != nil {
+		return fmt.Errorf("%w: %q on listener %s: %w", ErrInvalidRoute, pattern, listener, err)
+	}
+	m.listeners[listener] = named
+	return nil
+}
+
+// handleOnMux registers handler for pattern on mux, returning the reason
+// http.ServeMux panics with for invalid, duplicate and conflicting patterns.
+func handleOnMux(mux *http.ServeMux, pattern string, handler http.Handler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	mux.Handle(pattern, handler)
+	return nil
+}
+
+// ListenerAddress returns the address the named listener is bound to.
+func (m *HTTPServerModule) ListenerAddress(listener string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if named, ok := m.listeners[listener]; ok {
+		return named.address
+	}
+	return ""
+}
+
+// startListeners binds and serves every configured named listener. Listeners
+// without routes serve 404 for every request.
+func (m *HTTPServerModule) startListeners(ctx context.Context) error {
+	m.mu.Lock()
+	for name := range m.listeners {
+		if _, ok := m.config.Listeners[name]; !ok {
+			m.mu.Unlock()
+			return fmt.Errorf("%w: %s", ErrUnknownListener, name)
+		}
+	}
+	if m.listeners == nil {
+		m.listeners = make(map[string]*namedListener)
+	}
+	names := make([]string, 0, len(m.config.Listeners))
+	for name := range m.config.Listeners {
+		names = append(names, name)
+		if _, ok := m.listeners[name]; !ok {
+			m.listeners[name] = &namedListener{mux: http.NewServeMux()}
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cfg := m.config.Listeners[name]
+		named := m.listeners[name]
+		addr := net.JoinHostPort(cfg.Host, fmt.Sprint(cfg.Port))
+		ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+		if err != nil {
+			m.mu.Unlock()
+			_ = m.stopListeners(ctx)
+			return fmt.Errorf("failed to start listener %s on %s: %w", name, addr, err)
+		}
+		named.address = ln.Addr().String()
+		named.port = newSharedListener(ln)
+		named.server = m.newServer(m.config, named.address, m.wrapHandlerWithDrain(m.wrapHandlerWithRequestEvents(m.auditHandler(ModuleName+"."+name, named.mux))), true)
+		go m.serveListener(name, named.server, named.port.handoff())
+		m.logger.Info("HTTP listener started", "listener", name, "address", named.address)
+	}
+	m.mu.Unlock()
+
+	for _, name := range names {
+		event := modular.NewCloudEvent(EventTypeServerStarted, "httpserver-service", map[string]interface{}{
+			"listener": name,
+			"address":  m.ListenerAddress(name),
+		}, nil)
+		if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+			m.logger.Debug("Failed to emit listener started event", "error", emitErr)
+		}
+	}
+	return nil
+}
+
+// serveListener serves the connections of a named listener from ln.
+func (m *HTTPServerModule) serveListener(name string, server *http.Server, ln net.Listener) {
+	if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		m.logger.Error("HTTP listener error", "listener", name, "error", err)
+	}
+}
+
+// stopListeners gracefully shuts down the running named listeners.
+func (m *HTTPServerModule) stopListeners(ctx context.Context) error {
+	m.mu.Lock()
+	running := make(map[string]*namedListener)
+	for name, named := range m.listeners {
+		if named.server != nil {
+			running[name] = named
+		}
+	}
+	m.mu.Unlock()
+
+	var errs []error
+	for name, named := range running {
+		if err := named.server.Shutdown(ctx); err != nil {
+			// Close the connections that didn't finish in time
+			_ = named.server.Close()
+			errs = append(errs, fmt.Errorf("error shutting down listener %s: %w", name, err))
+		}
+		m.mu.Lock()
+		named.server = nil
+		named.port = nil
+		named.address = ""
+		m.mu.Unlock()
+
+		event := modular.NewCloudEvent(EventTypeServerStopped, "httpserver-service", map[string]interface{}{
+			"listener": name,
+		}, nil)
+		if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+			m.logger.Debug("Failed to emit listener stopped event", "error", emitErr)
+		}
+	}
+	return errors.Join(errs...)
+}
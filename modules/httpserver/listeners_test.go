@@ -0,0 +1,155 @@
+package httpserver
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// freePort returns a port that was free when the function was called.
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := ln.Addr().(*net.TCPAddr).Port
+	require.NoError(t, ln.Close())
+	return port
+}
+
+func getBody(t *testing.T, url string) (int, string) {
+	t.Helper()
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestNamedListeners_ServeSeparateHandlerTrees(t *testing.T) {
+	module := &HTTPServerModule{}
+	module.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	module.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("public"))
+	})
+
+	// Routes can be registered before the configuration is loaded
+	require.NoError(t, module.HandleOn("admin", "/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("metrics"))
+	})))
+
+	module.config = &HTTPServerConfig{
+		Host: "127.0.0.1",
+		Port: freePort(t),
+		Listeners: map[string]*ListenerConfig{
+			"admin": {Port: freePort(t)},
+		},
+	}
+	require.NoError(t, module.config.Validate())
+	assert.Equal(t, "127.0.0.1", module.config.Listeners["admin"].Host, "listeners bind to localhost by default")
+
+	require.ErrorIs(t, module.HandleOn("debug", "/", http.NotFoundHandler()), ErrUnknownListener)
+	require.ErrorIs(t, module.HandleOn("admin", "/metrics", http.NotFoundHandler()), ErrInvalidRoute, "duplicate pattern")
+	require.ErrorIs(t, module.HandleOn("admin", "", http.NotFoundHandler()), ErrInvalidRoute, "empty pattern")
+
+	ctx := context.Background()
+	require.NoError(t, module.Start(ctx))
+	require.NoError(t, module.HandleOn("admin", "/debug", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("debug"))
+	})))
+
+	adminURL := "http://" + module.ListenerAddress("admin")
+	status, body := getBody(t, adminURL+"/metrics")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "metrics", body)
+	_, body = getBody(t, adminURL+"/debug")
+	assert.Equal(t, "debug", body, "routes can be added while the listener runs")
+	status, _ = getBody(t, adminURL+"/")
+	assert.Equal(t, http.StatusNotFound, status, "the public handler is not served on the admin listener")
+
+	_, body = getBody(t, "http://"+module.server.Addr+"/metrics")
+	assert.Equal(t, "public", body)
+
+	require.NoError(t, module.Stop(ctx))
+	assert.Empty(t, module.ListenerAddress("admin"))
+	_, err := net.DialTimeout("tcp", strings.TrimPrefix(adminURL, "http://"), time.Second)
+	assert.Error(t, err, "the admin listener is closed on stop")
+}
+
+func TestNamedListeners_UnconfiguredListenerFailsStart(t *testing.T) {
+	module := &HTTPServerModule{}
+	module.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	module.handler = http.NotFoundHandler()
+	require.NoError(t, module.HandleOn("admin", "/", http.NotFoundHandler()))
+
+	module.config = &HTTPServerConfig{Host: "127.0.0.1", Port: freePort(t)}
+	require.NoError(t, module.config.Validate())
+	require.ErrorIs(t, module.Start(context.Background()), ErrUnknownListener)
+}
+
+func TestListenerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		listener *ListenerConfig
+	}{
+		{"missing port", &ListenerConfig{}},
+		{"port out of range", &ListenerConfig{Port: 70000}},
+		{"same address as server", &ListenerConfig{Host: "0.0.0.0", Port: 8080}},
+		{"empty", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &HTTPServerConfig{Listeners: map[string]*ListenerConfig{"admin": tt.listener}}
+			require.ErrorIs(t, cfg.Validate(), ErrInvalidListener)
+		})
+	}
+}
+
+// auditingMockApplication records the handlers wrapped for the context audit.
+type auditingMockApplication struct {
+	*SimpleMockApplication
+	mu      sync.Mutex
+	audited []string
+}
+
+func (a *auditingMockApplication) AuditHandler(name string, handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		a.audited = append(a.audited, name+" "+r.URL.Path)
+		a.mu.Unlock()
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestStart_AuditsHandlersOfAuditingApplications(t *testing.T) {
+	app := &auditingMockApplication{SimpleMockApplication: NewSimpleMockApplication()}
+	module := &HTTPServerModule{app: app, logger: NewSimpleMockLogger()}
+	module.handler = http.NotFoundHandler()
+	require.NoError(t, module.HandleOn("admin", "/metrics", http.NotFoundHandler()))
+	module.config = &HTTPServerConfig{
+		Host:      "127.0.0.1",
+		Port:      freePort(t),
+		Listeners: map[string]*ListenerConfig{"admin": {Port: freePort(t)}},
+	}
+	require.NoError(t, module.config.Validate())
+	require.NoError(t, module.Start(context.Background()))
+	defer func() { _ = module.Stop(context.Background()) }()
+
+	getBody(t, "http://"+module.server.Addr+"/orders")
+	getBody(t, "http://"+module.ListenerAddress("admin")+"/metrics")
+
+	app.mu.Lock()
+	defer app.mu.Unlock()
+	assert.Equal(t, []string{"httpserver /orders", "httpserver.admin /metrics"}, app.audited)
+}
@@ -90,9 +90,12 @@ type HTTPServerModule struct {
 	app                modular.Application
 	logger             modular.Logger
 	handler            http.Handler
+	port               *sharedListener
+	listeners          map[string]*namedListener // Named listeners by name (guarded by mu)
 	started            bool
 	certificateService CertificateService
 	subject            modular.Subject // For event observation (guarded by mu)
+	drain              drainState
 	mu                 sync.RWMutex
 }
 
@@ -241,23 +244,28 @@ func (m *HTTPServerModule) Start(ctx context.Context) error {
 	// safe functionally, but to avoid duplicate emissions, only wrap if it's not our
 	// wrapper already. Since we can't reliably detect prior wrapping without adding
 	// types, we conservatively wrap here to guarantee event emission.
-	effectiveHandler := m.wrapHandlerWithRequestEvents(m.handler)
+	effectiveHandler := m.wrapHandlerWithDrain(m.wrapHandlerWithRequestEvents(m.auditHandler(ModuleName, m.handler)))
+	m.drain.draining.Store(false)
 
-	// Create server with configured timeouts
-	m.server = &http.Server{
-		Addr:           addr,
-		Handler:        effectiveHandler,
-		ReadTimeout:    m.config.ReadTimeout,
-		WriteTimeout:   m.config.WriteTimeout,
-		IdleTimeout:    m.config.IdleTimeout,
-		MaxHeaderBytes: m.config.MaxHeaderBytes,
+	// TLS certificates are loaded before binding, so a bad certificate fails Start
+	tlsConfig, err := m.serverTLSConfig(m.config)
+	if err != nil {
+		return err
+	}
+	ln, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
 	}
+	m.port = newSharedListener(ln)
 
-	// Start the server in a goroutine
-	go m.runServer(ctx, addr)
+	// Create server with configured timeouts
+	m.server = m.newServer(m.config, addr, effectiveHandler, false)
+	m.server.TLSConfig = tlsConfig
+	m.emitTLSEnabledEvent(ctx)
+	go m.runServer(m.server, m.port.handoff())
 
-	// Test that server is actually listening
-	if err := m.waitForServerStart(ctx, addr); err != nil {
+	if err := m.startListeners(ctx); err != nil {
+		_ = m.server.Close()
 		return err
 	}
 
@@ -282,16 +290,30 @@ func (m *HTTPServerModule) Start(ctx context.Context) error {
 	return nil
 }
 
-// runServer starts the HTTP server with appropriate TLS configuration
-func (m *HTTPServerModule) runServer(ctx context.Context, addr string) {
-	m.logger.Info("Starting HTTP server", "address", addr)
+// newServer creates a server with the timeouts, header limit and protocols of cfg.
+func (m *HTTPServerModule) newServer(cfg *HTTPServerConfig, addr string, handler http.Handler, cleartext bool) *http.Server {
+	server := &http.Server{
+		Addr:           addr,
+		Handler:        handler,
+		ReadTimeout:    cfg.ReadTimeout,
+		WriteTimeout:   cfg.WriteTimeout,
+		IdleTimeout:    cfg.IdleTimeout,
+		MaxHeaderBytes: cfg.MaxHeaderBytes,
+	}
+	cfg.configureProtocols(server, cleartext)
+	return server
+}
+
+// runServer serves connections from ln, with TLS if the server has a TLS configuration
+func (m *HTTPServerModule) runServer(server *http.Server, ln net.Listener) {
+	m.logger.Info("Starting HTTP server", "address", server.Addr)
 	var err error
 
 	// Start server with or without TLS based on configuration
-	if m.config.TLS != nil && m.config.TLS.Enabled {
-		err = m.startTLSServer(ctx)
+	if server.TLSConfig != nil {
+		err = server.ServeTLS(ln, "", "")
 	} else {
-		err = m.server.ListenAndServe()
+		err = server.Serve(ln)
 	}
 
 	// If server was shut down gracefully, err will be http.ErrServerClosed
@@ -300,148 +322,87 @@ func (m *HTTPServerModule) runServer(ctx context.Context, addr string) {
 	}
 }
 
-// startTLSServer configures and starts the server with TLS
-func (m *HTTPServerModule) startTLSServer(ctx context.Context) error {
+// serverTLSConfig loads the certificates of the TLS configuration of cfg. It returns
+// nil if TLS is disabled, or if certificates could not be generated and the server
+// falls back to plain HTTP.
+func (m *HTTPServerModule) serverTLSConfig(cfg *HTTPServerConfig) (*tls.Config, error) {
+	if cfg.TLS == nil || !cfg.TLS.Enabled {
+		return nil, nil
+	}
+
 	// Configure TLS
 	tlsConfig := &tls.Config{
 		MinVersion: tls.VersionTLS12,
 	}
 
 	// UseService flag takes precedence
-	if m.config.TLS.UseService {
-		return m.startWithCertificateService(ctx, tlsConfig)
-	} else if m.config.TLS.AutoGenerate {
-		return m.startWithAutoGeneratedCerts(ctx, tlsConfig)
-	} else {
-		return m.startWithCertificateFiles(ctx)
-	}
-}
-
-// startWithCertificateService starts server using certificate service
-func (m *HTTPServerModule) startWithCertificateService(ctx context.Context, tlsConfig *tls.Config) error {
-	if m.certificateService != nil {
-		m.logger.Info("Using certificate service for TLS")
-		tlsConfig.GetCertificate = m.certificateService.GetCertificate
-
-		// Emit TLS enabled event SYNCHRONOUSLY
-		tlsEvent := modular.NewCloudEvent(EventTypeTLSEnabled, "httpserver-service", map[string]interface{}{
-			"method": "certificate_service",
-		}, nil)
-		if emitErr := m.EmitEvent(ctx, tlsEvent); emitErr != nil {
-			m.logger.Debug("Failed to emit TLS enabled event", "error", emitErr)
+	if cfg.TLS.UseService {
+		if m.certificateService != nil {
+			m.logger.Info("Using certificate service for TLS")
+			tlsConfig.GetCertificate = m.certificateService.GetCertificate
+			return tlsConfig, nil
 		}
 
-		m.server.TLSConfig = tlsConfig
-		if err := m.server.ListenAndServeTLS("", ""); err != nil {
-			return fmt.Errorf("failed to start HTTPS server with certificate service: %w", err)
+		// Fall back to auto-generated certificates if UseService is true but no service is available
+		m.logger.Warn("No certificate service available, falling back to auto-generated certificates")
+		if len(cfg.TLS.Domains) == 0 {
+			cfg.TLS.Domains = []string{"localhost"}
 		}
-		return nil
-	}
-
-	// Fall back to auto-generated certificates if UseService is true but no service is available
-	m.logger.Warn("No certificate service available, falling back to auto-generated certificates")
-	if len(m.config.TLS.Domains) == 0 {
-		m.config.TLS.Domains = []string{"localhost"}
-	}
-	return m.startWithAutoGeneratedCerts(ctx, tlsConfig)
-}
-
-// startWithAutoGeneratedCerts starts server with auto-generated certificates
-func (m *HTTPServerModule) startWithAutoGeneratedCerts(ctx context.Context, tlsConfig *tls.Config) error {
-	m.logger.Info("Auto-generating self-signed certificates", "domains", m.config.TLS.Domains)
-
-	// Emit TLS enabled event SYNCHRONOUSLY before starting server
-	tlsEvent := modular.NewCloudEvent(EventTypeTLSEnabled, "httpserver-service", map[string]interface{}{
-		"method":  "auto_generate",
-		"domains": m.config.TLS.Domains,
-	}, nil)
-	if emitErr := m.EmitEvent(ctx, tlsEvent); emitErr != nil {
-		m.logger.Debug("Failed to emit TLS auto-generate event", "error", emitErr)
 	}
 
-	cert, key, err := m.generateSelfSignedCertificate(m.config.TLS.Domains)
-	if err != nil {
-		m.logger.Error("Failed to generate self-signed certificate", "error", err)
-		if err := m.server.ListenAndServe(); err != nil {
-			return fmt.Errorf("failed to start HTTP server after certificate generation failure: %w", err)
+	certFile, keyFile := cfg.TLS.CertFile, cfg.TLS.KeyFile
+	if cfg.TLS.UseService || cfg.TLS.AutoGenerate {
+		m.logger.Info("Auto-generating self-signed certificates", "domains", cfg.TLS.Domains)
+		var err error
+		certFile, keyFile, err = m.generateSelfSignedCertificate(cfg.TLS.Domains)
+		if err != nil {
+			m.logger.Error("Failed to generate self-signed certificate", "error", err)
+			return nil, nil
 		}
-		return nil
-	}
-
-	m.server.TLSConfig = tlsConfig
-	if err := m.server.ListenAndServeTLS(cert, key); err != nil {
-		return fmt.Errorf("failed to start HTTPS server with auto-generated certificates: %w", err)
-	}
-	return nil
-}
-
-// startWithCertificateFiles starts server using provided certificate files
-func (m *HTTPServerModule) startWithCertificateFiles(ctx context.Context) error {
-	m.logger.Info("Using TLS configuration", "cert", m.config.TLS.CertFile, "key", m.config.TLS.KeyFile)
-
-	// Emit TLS enabled event SYNCHRONOUSLY
-	tlsEvent := modular.NewCloudEvent(EventTypeTLSEnabled, "httpserver-service", map[string]interface{}{
-		"method":    "certificate_files",
-		"cert_file": m.config.TLS.CertFile,
-		"key_file":  m.config.TLS.KeyFile,
-	}, nil)
-	if emitErr := m.EmitEvent(ctx, tlsEvent); emitErr != nil {
-		m.logger.Debug("Failed to emit TLS configured event", "error", emitErr)
+	} else {
+		m.logger.Info("Using TLS configuration", "cert", certFile, "key", keyFile)
 	}
 
-	if err := m.server.ListenAndServeTLS(m.config.TLS.CertFile, m.config.TLS.KeyFile); err != nil {
-		return fmt.Errorf("failed to start HTTPS server with certificate files: %w", err)
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
 	}
-	return nil
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return tlsConfig, nil
 }
 
-// waitForServerStart waits for the server to start accepting connections
-func (m *HTTPServerModule) waitForServerStart(ctx context.Context, addr string) error {
-	timeout := time.Second
-	if deadline, ok := ctx.Deadline(); ok {
-		timeout = time.Until(deadline)
-	}
-
-	checkCtx, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
-
-	check := func() error {
-		var dialer net.Dialer
-		conn, err := dialer.DialContext(checkCtx, "tcp", addr)
-		if err != nil {
-			return fmt.Errorf("dialing server: %w", err)
-		}
-		if closeErr := conn.Close(); closeErr != nil {
-			m.logger.Warn("Failed to close connection", "error", closeErr)
-		}
-		return nil
+// emitTLSEnabledEvent emits the TLS enabled event if the server serves TLS
+func (m *HTTPServerModule) emitTLSEnabledEvent(ctx context.Context) {
+	if m.server.TLSConfig == nil {
+		return
 	}
 
-	// Try to connect to the server with retries
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	startTime := time.Now()
-	for {
-		err := check()
-		if err == nil {
-			break // Successfully connected
+	var data map[string]interface{}
+	failure := "Failed to emit TLS enabled event"
+	switch {
+	case m.certificateService != nil && m.config.TLS.UseService:
+		data = map[string]interface{}{
+			"method": "certificate_service",
 		}
-
-		// Check if the timeout has expired
-		if time.Since(startTime) > timeout {
-			return fmt.Errorf("failed to start HTTP server within timeout: %w", err)
+	case m.config.TLS.UseService || m.config.TLS.AutoGenerate:
+		data = map[string]interface{}{
+			"method":  "auto_generate",
+			"domains": m.config.TLS.Domains,
 		}
-
-		// Wait before retrying
-		select {
-		case <-checkCtx.Done():
-			return ErrServerStartTimeout
-		case <-ticker.C:
+		failure = "Failed to emit TLS auto-generate event"
+	default:
+		data = map[string]interface{}{
+			"method":    "certificate_files",
+			"cert_file": m.config.TLS.CertFile,
+			"key_file":  m.config.TLS.KeyFile,
 		}
+		failure = "Failed to emit TLS configured event"
 	}
 
-	return nil
+	tlsEvent := modular.NewCloudEvent(EventTypeTLSEnabled, "httpserver-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, tlsEvent); emitErr != nil {
+		m.logger.Debug(failure, "error", emitErr)
+	}
 }
 
 // emitTLSConfiguredEvent emits TLS configured event if TLS is enabled
@@ -482,24 +443,43 @@ func (m *HTTPServerModule) emitTLSConfiguredEvent(ctx context.Context) {
 //  5. Mark server as stopped
 //
 // If the shutdown timeout is exceeded, the server will be forcefully closed.
+//
+// With DrainTimeout configured, Stop drains instead: it waits up to DrainTimeout for
+// in-flight requests while emitting drain events, then closes the connections that
+// remain and returns ErrDrainTimeout if any requests were cut off.
 func (m *HTTPServerModule) Stop(ctx context.Context) error {
 	if m.server == nil || !m.started {
 		return ErrServerNotStarted
 	}
 
-	m.logger.Info("Stopping HTTP server", "timeout", m.config.ShutdownTimeout)
-
-	// Create a context with timeout for shutdown
-	shutdownCtx, cancel := context.WithTimeout(
-		ctx,
-		m.config.ShutdownTimeout,
-	)
-	defer cancel()
+	var drainErr error
+	if m.config.DrainTimeout > 0 {
+		m.logger.Info("Draining HTTP server", "timeout", m.config.DrainTimeout)
 
-	// Shutdown the server gracefully
-	err := m.server.Shutdown(shutdownCtx)
-	if err != nil {
-		return fmt.Errorf("error shutting down HTTP server: %w", err)
+		// A drain that times out still stops the server, closing the remaining connections
+		drainErr = m.drainServers(ctx)
+		if drainErr != nil && !errors.Is(drainErr, ErrDrainTimeout) {
+			return drainErr
+		}
+	} else {
+		m.logger.Info("Stopping HTTP server", "timeout", m.config.ShutdownTimeout)
+
+		// Create a context with timeout for shutdown
+		shutdownCtx, cancel := context.WithTimeout(
+			ctx,
+			m.config.ShutdownTimeout,
+		)
+		defer cancel()
+
+		// Shutdown the server and named listeners gracefully
+		err := m.server.Shutdown(shutdownCtx)
+		listenersErr := m.stopListeners(shutdownCtx)
+		if err != nil {
+			return fmt.Errorf("error shutting down HTTP server: %w", err)
+		}
+		if listenersErr != nil {
+			return listenersErr
+		}
 	}
 
 	m.started = false
@@ -519,7 +499,7 @@ func (m *HTTPServerModule) Stop(ctx context.Context) error {
 		m.logger.Debug("Failed to emit server stopped event", "error", emitErr)
 	}
 
-	return nil
+	return drainErr
 }
 
 // ProvidesServices returns the services provided by this module
@@ -533,6 +513,13 @@ func (m *HTTPServerModule) ProvidesServices() []modular.ServiceProvider {
 	}
 }
 
+// ShutdownPhaseOrder returns the ingress phase of the application's shutdown,
+// modular.ShutdownPhaseIngress, so the server stops accepting requests before the
+// modules serving them stop.
+func (m *HTTPServerModule) ShutdownPhaseOrder() int {
+	return 100
+}
+
 // RequiresServices returns the services required by this module
 func (m *HTTPServerModule) RequiresServices() []modular.ServiceDependency {
 	deps := []modular.ServiceDependency{
@@ -687,6 +674,20 @@ func (m *HTTPServerModule) EmitEvent(ctx context.Context, event cloudevents.Even
 	return nil
 }
 
+// handlerAuditor is implemented by applications auditing the contexts that handlers
+// pass downstream, see modular.StdApplication.AuditHandler.
+type handlerAuditor interface {
+	AuditHandler(name string, handler http.Handler) http.Handler
+}
+
+// auditHandler wraps handler with the application's context audit, when enabled.
+func (m *HTTPServerModule) auditHandler(name string, handler http.Handler) http.Handler {
+	if auditor, ok := m.app.(handlerAuditor); ok {
+		return auditor.AuditHandler(name, handler)
+	}
+	return handler
+}
+
 // wrapHandlerWithRequestEvents wraps the HTTP handler to emit request events
 func (m *HTTPServerModule) wrapHandlerWithRequestEvents(handler http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -770,6 +771,9 @@ func (m *HTTPServerModule) GetRegisteredEventTypes() []string {
 	return []string{
 		EventTypeServerStarted,
 		EventTypeServerStopped,
+		EventTypeServerDrainStarted,
+		EventTypeServerDrainProgress,
+		EventTypeServerDrainCompleted,
 		EventTypeRequestReceived,
 		EventTypeRequestHandled,
 		EventTypeTLSEnabled,
@@ -0,0 +1,89 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Server protocols for HTTPServerConfig.Protocols
+const (
+	// ProtocolHTTP1 is HTTP/1.0 and HTTP/1.1, over TCP or TLS
+	ProtocolHTTP1 = "http1"
+	// ProtocolHTTP2 is HTTP/2 over TLS, negotiated with ALPN
+	ProtocolHTTP2 = "http2"
+	// ProtocolH2C is HTTP/2 over cleartext TCP. Clients must start with HTTP/2
+	// ("prior knowledge"), as gRPC clients and proxies such as Envoy do; the
+	// HTTP/1.1 Upgrade mechanism is not supported.
+	ProtocolH2C = "h2c"
+)
+
+// validateProtocols checks the configured protocols and HTTP/2 settings.
+func (c *HTTPServerConfig) validateProtocols() error {
+	if len(c.Protocols) > 0 {
+		cleartext, encrypted := false, false
+		for _, protocol := range c.Protocols {
+			switch protocol {
+			case ProtocolHTTP1:
+				cleartext, encrypted = true, true
+			case ProtocolH2C:
+				cleartext = true
+			case ProtocolHTTP2:
+				encrypted = true
+			default:
+				return fmt.Errorf("%w: unknown protocol %q", ErrInvalidProtocols, protocol)
+			}
+		}
+		tlsEnabled := c.TLS != nil && c.TLS.Enabled
+		if !cleartext && !tlsEnabled {
+			return fmt.Errorf("%w: %q requires TLS; enable TLS or add %q or %q", ErrInvalidProtocols, ProtocolHTTP2, ProtocolHTTP1, ProtocolH2C)
+		}
+		if !encrypted && tlsEnabled {
+			return fmt.Errorf("%w: %q is not served over TLS; disable TLS or add %q or %q", ErrInvalidProtocols, ProtocolH2C, ProtocolHTTP1, ProtocolHTTP2)
+		}
+	}
+
+	if h2 := c.HTTP2; h2 != nil {
+		if h2.MaxConcurrentStreams < 0 {
+			return fmt.Errorf("%w: max_concurrent_streams is %d", ErrInvalidHTTP2Config, h2.MaxConcurrentStreams)
+		}
+		if h2.MaxReadFrameSize != 0 && (h2.MaxReadFrameSize < 16<<10 || h2.MaxReadFrameSize > 16<<20) {
+			return fmt.Errorf("%w: max_read_frame_size %d is not between 16KiB and 16MiB", ErrInvalidHTTP2Config, h2.MaxReadFrameSize)
+		}
+		if h2.PingInterval < 0 || h2.PingTimeout < 0 {
+			return fmt.Errorf("%w: ping_interval and ping_timeout must not be negative", ErrInvalidHTTP2Config)
+		}
+	}
+	return nil
+}
+
+// configureProtocols applies the configured protocols and HTTP/2 settings to
+// server. A cleartext server, such as a named listener, always accepts HTTP/1
+// unless h2c is configured.
+func (c *HTTPServerConfig) configureProtocols(server *http.Server, cleartext bool) {
+	if len(c.Protocols) > 0 {
+		protocols := new(http.Protocols)
+		for _, protocol := range c.Protocols {
+			switch protocol {
+			case ProtocolHTTP1:
+				protocols.SetHTTP1(true)
+			case ProtocolHTTP2:
+				protocols.SetHTTP2(true)
+			case ProtocolH2C:
+				protocols.SetUnencryptedHTTP2(true)
+			}
+		}
+		if cleartext && !protocols.UnencryptedHTTP2() {
+			protocols.SetHTTP1(true)
+		}
+		server.Protocols = protocols
+	}
+
+	if h2 := c.HTTP2; h2 != nil {
+		server.HTTP2 = &http.HTTP2Config{
+			MaxConcurrentStreams: h2.MaxConcurrentStreams,
+			MaxReadFrameSize:     h2.MaxReadFrameSize,
+			SendPingTimeout:      h2.PingInterval,
+			PingTimeout:          h2.PingTimeout,
+		}
+	}
+}
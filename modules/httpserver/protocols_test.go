@@ -0,0 +1,135 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startProtocolTestModule starts a module answering every request with the
+// protocol it was served over.
+func startProtocolTestModule(t *testing.T, config *HTTPServerConfig) *HTTPServerModule {
+	t.Helper()
+	module := &HTTPServerModule{}
+	module.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	module.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Proto))
+	})
+	config.Host = "127.0.0.1"
+	config.Port = freePort(t)
+	require.NoError(t, config.Validate())
+	module.config = config
+
+	require.NoError(t, module.Start(context.Background()))
+	t.Cleanup(func() { _ = module.Stop(context.Background()) })
+	return module
+}
+
+// protocolClient returns a client speaking the given protocols, trusting any certificate.
+func protocolClient(configure func(*http.Protocols)) *http.Client {
+	protocols := new(http.Protocols)
+	configure(protocols)
+	return &http.Client{
+		Timeout: 5 * time.Second,
+		Transport: &http.Transport{
+			Protocols:       protocols,
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec // test server uses a self-signed certificate
+		},
+	}
+}
+
+func requestProto(t *testing.T, client *http.Client, url string) (string, error) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return resp.Proto, nil
+}
+
+func TestProtocols_H2C(t *testing.T) {
+	module := startProtocolTestModule(t, &HTTPServerConfig{
+		Protocols: []string{ProtocolHTTP1, ProtocolH2C},
+		HTTP2:     &HTTP2Config{MaxConcurrentStreams: 50},
+	})
+	url := "http://" + module.server.Addr + "/"
+
+	proto, err := requestProto(t, protocolClient(func(p *http.Protocols) { p.SetUnencryptedHTTP2(true) }), url)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", proto)
+
+	proto, err = requestProto(t, protocolClient(func(p *http.Protocols) { p.SetHTTP1(true) }), url)
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", proto)
+
+	assert.Equal(t, 50, module.server.HTTP2.MaxConcurrentStreams)
+}
+
+func TestProtocols_WithoutH2C(t *testing.T) {
+	module := startProtocolTestModule(t, &HTTPServerConfig{})
+	url := "http://" + module.server.Addr + "/"
+
+	_, err := requestProto(t, protocolClient(func(p *http.Protocols) { p.SetUnencryptedHTTP2(true) }), url)
+	require.Error(t, err, "h2c is not accepted unless configured")
+}
+
+func TestProtocols_TLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, generateTestCertificate(certFile, keyFile))
+	tlsConfig := func() *TLSConfig { return &TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile} }
+	both := protocolClient(func(p *http.Protocols) { p.SetHTTP1(true); p.SetHTTP2(true) })
+
+	module := startProtocolTestModule(t, &HTTPServerConfig{TLS: tlsConfig()})
+	proto, err := requestProto(t, both, "https://"+module.server.Addr+"/")
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/2.0", proto, "HTTP/2 is negotiated over TLS by default")
+
+	module = startProtocolTestModule(t, &HTTPServerConfig{TLS: tlsConfig(), Protocols: []string{ProtocolHTTP1}})
+	proto, err = requestProto(t, both, "https://"+module.server.Addr+"/")
+	require.NoError(t, err)
+	assert.Equal(t, "HTTP/1.1", proto)
+}
+
+func TestProtocols_Validate(t *testing.T) {
+	config := &HTTPServerConfig{Protocols: []string{ProtocolHTTP1, "spdy"}}
+	require.ErrorIs(t, config.Validate(), ErrInvalidProtocols)
+
+	config = &HTTPServerConfig{Protocols: []string{ProtocolHTTP2}}
+	require.ErrorIs(t, config.Validate(), ErrInvalidProtocols, "HTTP/2 over TLS alone can't serve a cleartext server")
+
+	config = &HTTPServerConfig{Protocols: []string{ProtocolH2C}}
+	require.NoError(t, config.Validate())
+
+	config = &HTTPServerConfig{TLS: &TLSConfig{Enabled: true, AutoGenerate: true, Domains: []string{"localhost"}}, Protocols: []string{ProtocolH2C}}
+	require.ErrorIs(t, config.Validate(), ErrInvalidProtocols, "cleartext HTTP/2 alone can't serve a TLS server")
+
+	config = &HTTPServerConfig{TLS: &TLSConfig{Enabled: true, AutoGenerate: true, Domains: []string{"localhost"}}, Protocols: []string{ProtocolH2C, ProtocolHTTP2}}
+	require.NoError(t, config.Validate())
+
+	config = &HTTPServerConfig{HTTP2: &HTTP2Config{MaxReadFrameSize: 1024}}
+	require.ErrorIs(t, config.Validate(), ErrInvalidHTTP2Config)
+
+	config = &HTTPServerConfig{HTTP2: &HTTP2Config{MaxConcurrentStreams: -1}}
+	require.ErrorIs(t, config.Validate(), ErrInvalidHTTP2Config)
+}
+
+func TestProtocols_NamedListenersAcceptHTTP1(t *testing.T) {
+	server := &http.Server{}
+	config := &HTTPServerConfig{Protocols: []string{ProtocolHTTP2}, HTTP2: &HTTP2Config{PingInterval: time.Minute}}
+	config.configureProtocols(server, true)
+	assert.True(t, server.Protocols.HTTP1())
+	assert.Equal(t, time.Minute, server.HTTP2.SendPingTimeout)
+
+	config.configureProtocols(server, false)
+	assert.False(t, server.Protocols.HTTP1())
+}
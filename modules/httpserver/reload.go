@@ -0,0 +1,205 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Reload applies the timeouts, header limit, protocols and TLS settings of the
+// httpserver config section while the server keeps its port, which makes the module
+// Reloadable for the application's ReloadConfig. A new server with the reloaded
+// settings takes over the port and the listeners' ports, then the previous servers
+// shut down gracefully within ShutdownTimeout. TLS certificate files are read again,
+// so Reload also picks up renewed certificates.
+//
+// Changing the host, port or listeners requires a restart and rejects the reload,
+// as does a certificate that fails to load; the server keeps its previous settings.
+func (m *HTTPServerModule) Reload(ctx context.Context, _ []string) error {
+	if m.app == nil || m.config == nil {
+		return ErrServerNotStarted
+	}
+	section, err := m.app.GetConfigSection(m.Name())
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.Name(), err)
+	}
+	next, ok := section.GetConfig().(*HTTPServerConfig)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnexpectedConfigType, section.GetConfig())
+	}
+	if err := next.Validate(); err != nil {
+		return fmt.Errorf("invalid httpserver configuration: %w", err)
+	}
+	if next.Host != m.config.Host || next.Port != m.config.Port {
+		return fmt.Errorf("%w: address changed from %s:%d to %s:%d",
+			ErrReloadRequiresRestart, m.config.Host, m.config.Port, next.Host, next.Port)
+	}
+	if !reflect.DeepEqual(next.Listeners, m.config.Listeners) {
+		return fmt.Errorf("%w: listeners changed", ErrReloadRequiresRestart)
+	}
+
+	if !m.started {
+		// Start applies the reloaded settings
+		m.mu.Lock()
+		m.config = next
+		m.mu.Unlock()
+		return nil
+	}
+
+	tlsConfig, err := m.serverTLSConfig(next)
+	if err != nil {
+		return err
+	}
+
+	// New servers take over the ports before the previous ones stop accepting
+	m.mu.Lock()
+	previous := []*http.Server{m.server}
+	m.server = m.newServer(next, m.server.Addr, m.server.Handler, false)
+	m.server.TLSConfig = tlsConfig
+	go m.runServer(m.server, m.port.handoff())
+
+	names := make([]string, 0, len(m.listeners))
+	for name, named := range m.listeners {
+		if named.server != nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		named := m.listeners[name]
+		previous = append(previous, named.server)
+		named.server = m.newServer(next, named.address, named.server.Handler, true)
+		go m.serveListener(name, named.server, named.port.handoff())
+	}
+	m.config = next
+	m.mu.Unlock()
+
+	shutdownCtx, cancel := context.WithTimeout(ctx, next.ShutdownTimeout)
+	defer cancel()
+	for _, server := range previous {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			// Close the connections that didn't finish in time
+			_ = server.Close()
+		}
+	}
+
+	m.logger.Info("Reloaded HTTP server configuration", "address", m.server.Addr,
+		"tls_enabled", tlsConfig != nil, "listeners", len(names))
+	return nil
+}
+
+// currentConfig returns the configuration, which Reload replaces while requests are served.
+func (m *HTTPServerModule) currentConfig() *HTTPServerConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// sharedListener accepts the connections of a port for the servers that take turns
+// serving it, so that Reload can replace a server without closing its port. The
+// port closes once every server it was handed to has stopped.
+type sharedListener struct {
+	net.Listener
+	accepted chan acceptResult
+	closed   chan struct{}
+	mu       sync.Mutex
+	servers  int
+}
+
+// acceptResult is a connection, or the error, returned by the port's Accept.
+type acceptResult struct {
+	conn net.Conn
+	err  error
+}
+
+// newSharedListener starts accepting the connections of ln.
+func newSharedListener(ln net.Listener) *sharedListener {
+	s := &sharedListener{
+		Listener: ln,
+		accepted: make(chan acceptResult),
+		closed:   make(chan struct{}),
+	}
+	go s.acceptLoop()
+	return s
+}
+
+// acceptLoop hands the accepted connections to the servers until the port closes.
+func (s *sharedListener) acceptLoop() {
+	for {
+		conn, err := s.Listener.Accept()
+		select {
+		case s.accepted <- acceptResult{conn: conn, err: err}:
+		case <-s.closed:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+	}
+}
+
+// handoff returns a listener for one more server to serve the port with.
+func (s *sharedListener) handoff() net.Listener {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.servers++
+	return &handoffListener{port: s, done: make(chan struct{})}
+}
+
+// release closes the port after the last server using it stopped.
+func (s *sharedListener) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.servers--
+	if s.servers == 0 {
+		close(s.closed)
+		_ = s.Listener.Close()
+	}
+}
+
+// handoffListener is the view of a shared port for one server. Closing it, as
+// http.Server.Shutdown does, stops that server from accepting connections.
+type handoffListener struct {
+	port *sharedListener
+	done chan struct{}
+	once sync.Once
+}
+
+// Accept returns the next connection of the port, or net.ErrClosed once closed.
+func (l *handoffListener) Accept() (net.Conn, error) {
+	select {
+	case <-l.done:
+		return nil, net.ErrClosed
+	default:
+	}
+	select {
+	case result := <-l.port.accepted:
+		return result.conn, result.err
+	case <-l.done:
+		return nil, net.ErrClosed
+	case <-l.port.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Close stops accepting connections for this server.
+func (l *handoffListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+		l.port.release()
+	})
+	return nil
+}
+
+// Addr returns the address of the port.
+func (l *handoffListener) Addr() net.Addr {
+	return l.port.Addr()
+}
@@ -0,0 +1,137 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startReloadTestModule starts a module with cfg and returns the application to
+// reload its config section through.
+func startReloadTestModule(t *testing.T, cfg *HTTPServerConfig) (*HTTPServerModule, *SimpleMockApplication) {
+	t.Helper()
+	app := NewSimpleMockApplication()
+	app.RegisterConfigSection(ModuleName, NewMockConfigProvider(cfg))
+	module := NewHTTPServerModule().(*HTTPServerModule)
+	require.NoError(t, module.Init(app))
+	require.NoError(t, module.config.Validate())
+	module.handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	require.NoError(t, module.Start(context.Background()))
+	t.Cleanup(func() { _ = module.Stop(context.Background()) })
+	return module, app
+}
+
+func TestReload_AppliesTimeoutsOnTheSamePort(t *testing.T) {
+	port := freePort(t)
+	module, app := startReloadTestModule(t, &HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ReadTimeout:     time.Second,
+		ShutdownTimeout: time.Second,
+		Listeners:       map[string]*ListenerConfig{"admin": {Port: freePort(t)}},
+	})
+	url := "http://" + module.server.Addr + "/"
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	previous := module.server
+
+	app.RegisterConfigSection(ModuleName, NewMockConfigProvider(&HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ReadTimeout:     3 * time.Second,
+		WriteTimeout:    4 * time.Second,
+		MaxHeaderBytes:  8 << 10,
+		ShutdownTimeout: time.Second,
+		Listeners:       module.config.Listeners,
+	}))
+	require.NoError(t, module.Reload(context.Background(), []string{ModuleName}))
+
+	assert.NotSame(t, previous, module.server)
+	assert.Equal(t, 3*time.Second, module.server.ReadTimeout)
+	assert.Equal(t, 4*time.Second, module.server.WriteTimeout)
+	assert.Equal(t, 8<<10, module.server.MaxHeaderBytes)
+	assert.Equal(t, 3*time.Second, module.listeners["admin"].server.ReadTimeout)
+
+	// The kept-alive connection to the previous server was closed, a new one reaches the new server
+	status, body := getBody(t, url)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", body)
+	status, _ = getBody(t, "http://"+module.ListenerAddress("admin")+"/")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	// The port closes once the server that took it over stops
+	require.NoError(t, module.Stop(context.Background()))
+	ln, err := net.Listen("tcp", module.server.Addr)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+}
+
+func TestReload_LoadsNewCertificateFiles(t *testing.T) {
+	module := &HTTPServerModule{logger: NewSimpleMockLogger()}
+	firstCert, firstKey, err := module.generateSelfSignedCertificate([]string{"localhost"})
+	require.NoError(t, err)
+	secondCert, secondKey, err := module.generateSelfSignedCertificate([]string{"localhost"})
+	require.NoError(t, err)
+
+	port := freePort(t)
+	module, app := startReloadTestModule(t, &HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ShutdownTimeout: time.Second,
+		TLS:             &TLSConfig{Enabled: true, CertFile: firstCert, KeyFile: firstKey},
+	})
+	serial := func() string {
+		conn, err := tls.Dial("tcp", module.server.Addr, &tls.Config{InsecureSkipVerify: true}) //nolint:gosec // self-signed test certificate
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.String()
+	}
+	before := serial()
+
+	app.RegisterConfigSection(ModuleName, NewMockConfigProvider(&HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ShutdownTimeout: time.Second,
+		TLS:             &TLSConfig{Enabled: true, CertFile: secondCert, KeyFile: secondKey},
+	}))
+	require.NoError(t, module.Reload(context.Background(), []string{ModuleName}))
+
+	assert.NotEqual(t, before, serial())
+}
+
+func TestReload_RejectsChangesThatRequireARestart(t *testing.T) {
+	port := freePort(t)
+	module, app := startReloadTestModule(t, &HTTPServerConfig{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ShutdownTimeout: time.Second,
+	})
+	previous := module.server
+
+	app.RegisterConfigSection(ModuleName, NewMockConfigProvider(&HTTPServerConfig{
+		Host: "127.0.0.1",
+		Port: freePort(t),
+	}))
+	require.ErrorIs(t, module.Reload(context.Background(), []string{ModuleName}), ErrReloadRequiresRestart)
+
+	app.RegisterConfigSection(ModuleName, NewMockConfigProvider(&HTTPServerConfig{
+		Host: "127.0.0.1",
+		Port: port,
+		TLS:  &TLSConfig{Enabled: true, CertFile: "missing.pem", KeyFile: "missing-key.pem"},
+	}))
+	require.Error(t, module.Reload(context.Background(), []string{ModuleName}))
+
+	assert.Same(t, previous, module.server)
+	status, _ := getBody(t, "http://"+module.server.Addr+"/")
+	assert.Equal(t, http.StatusOK, status)
+}
@@ -0,0 +1,88 @@
+package locks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LockBackend stores locks for the locks module. A lock is identified by its key
+// and owned by the holder that acquired it with a unique token; only the owner's
+// token can unlock or extend it. Custom backends can be installed with
+// LocksModule.SetBackend.
+type LockBackend interface {
+	// TryLock acquires key for token until ttl passes, reporting false if another
+	// token holds it.
+	TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+
+	// Unlock releases key if token holds it, reporting false otherwise.
+	Unlock(ctx context.Context, key, token string) (bool, error)
+
+	// Extend resets the expiry of key to ttl from now if token holds it, reporting
+	// false otherwise.
+	Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+
+	// Close releases the backend's resources.
+	Close() error
+}
+
+// memoryBackend keeps locks in process memory.
+type memoryBackend struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+	now   func() time.Time
+}
+
+type memoryLock struct {
+	token   string
+	expires time.Time
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{locks: make(map[string]memoryLock), now: time.Now}
+}
+
+func (b *memoryBackend) TryLock(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := b.now()
+	if held, ok := b.locks[key]; ok && held.token != token && now.Before(held.expires) {
+		return false, nil
+	}
+	b.locks[key] = memoryLock{token: token, expires: now.Add(ttl)}
+	return true, nil
+}
+
+func (b *memoryBackend) Unlock(_ context.Context, key, token string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.ownedLocked(key, token) {
+		return false, nil
+	}
+	delete(b.locks, key)
+	return true, nil
+}
+
+func (b *memoryBackend) Extend(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.ownedLocked(key, token) {
+		return false, nil
+	}
+	b.locks[key] = memoryLock{token: token, expires: b.now().Add(ttl)}
+	return true, nil
+}
+
+func (b *memoryBackend) Close() error {
+	return nil
+}
+
+// ownedLocked reports whether token holds an unexpired lock on key. Callers hold b.mu.
+func (b *memoryBackend) ownedLocked(key, token string) bool {
+	held, ok := b.locks[key]
+	if ok && !b.now().Before(held.expires) {
+		delete(b.locks, key)
+		return false
+	}
+	return ok && held.token == token
+}
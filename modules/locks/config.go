@@ -0,0 +1,80 @@
+package locks
+
+import (
+	"fmt"
+	"time"
+)
+
+// Driver identifies the backend that stores locks.
+type Driver string
+
+const (
+	// DriverMemory keeps locks in process memory. Locks only exclude holders within
+	// the same process, which suits tests and single-instance deployments.
+	DriverMemory Driver = "memory"
+	// DriverRedis stores locks as keys on a single Redis instance
+	DriverRedis Driver = "redis"
+	// DriverDatabase uses the advisory locks of the database provided by the
+	// database module
+	DriverDatabase Driver = "database"
+)
+
+// Dialect identifies the advisory lock functions used by the database driver.
+type Dialect string
+
+const (
+	// DialectPostgres uses pg_try_advisory_lock and pg_advisory_unlock
+	DialectPostgres Dialect = "postgres"
+	// DialectMySQL uses GET_LOCK and RELEASE_LOCK
+	DialectMySQL Dialect = "mysql"
+)
+
+// LocksConfig defines the configuration for the locks module.
+type LocksConfig struct {
+	// Driver is memory, redis or database
+	Driver Driver `json:"driver" yaml:"driver" env:"DRIVER" default:"memory"`
+
+	// DefaultTTL is how long locks acquired without a TTL are held before they expire
+	DefaultTTL time.Duration `json:"defaultTTL" yaml:"defaultTTL" env:"DEFAULT_TTL" default:"30s"`
+
+	// RetryInterval is how often Acquire retries a lock held by someone else
+	RetryInterval time.Duration `json:"retryInterval" yaml:"retryInterval" env:"RETRY_INTERVAL" default:"100ms"`
+
+	// KeyPrefix is prepended to lock keys in Redis and to database lock names, so
+	// several applications can share a backend
+	KeyPrefix string `json:"keyPrefix" yaml:"keyPrefix" env:"KEY_PREFIX" default:"locks:"`
+
+	// RedisURL is the connection URL for the redis driver.
+	// Format: redis://[username:password@]host:port[/database]
+	RedisURL string `json:"redisURL" yaml:"redisURL" env:"REDIS_URL"`
+
+	// RedisPassword is the password for Redis authentication
+	RedisPassword string `json:"redisPassword" yaml:"redisPassword" env:"REDIS_PASSWORD"`
+
+	// RedisDB is the Redis database number to use
+	RedisDB int `json:"redisDB" yaml:"redisDB" env:"REDIS_DB"`
+
+	// DatabaseDialect is postgres or mysql. Only used by the database driver.
+	DatabaseDialect Dialect `json:"databaseDialect" yaml:"databaseDialect" env:"DATABASE_DIALECT" default:"postgres"`
+}
+
+// Validate implements the ConfigValidator interface for LocksConfig.
+func (c *LocksConfig) Validate() error {
+	if c.DefaultTTL < 0 || c.RetryInterval < 0 {
+		return fmt.Errorf("%w: defaultTTL and retryInterval must not be negative", ErrInvalidConfig)
+	}
+	switch c.Driver {
+	case DriverMemory:
+	case DriverRedis:
+		if c.RedisURL == "" {
+			return fmt.Errorf("%w: the redis driver needs a redisURL", ErrInvalidConfig)
+		}
+	case DriverDatabase:
+		if c.DatabaseDialect != DialectPostgres && c.DatabaseDialect != DialectMySQL {
+			return fmt.Errorf("%w: %q", ErrUnknownDialect, c.DatabaseDialect)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownDriver, c.Driver)
+	}
+	return nil
+}
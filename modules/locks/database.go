@@ -0,0 +1,200 @@
+package locks
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DBProvider is implemented by services exposing a database connection pool, such
+// as the database module's database.service.
+type DBProvider interface {
+	DB() *sql.DB
+}
+
+// mysqlMaxLockName is the longest lock name GET_LOCK accepts.
+const mysqlMaxLockName = 64
+
+// databaseBackend uses session-level advisory locks. Each held lock pins a
+// connection from the pool until it is released. Advisory locks don't expire on
+// their own, so the backend releases locks whose TTL passes without an Extend; the
+// database also releases them if the process dies and its connections drop.
+type databaseBackend struct {
+	db      *sql.DB
+	dialect Dialect
+
+	mu   sync.Mutex
+	held map[string]*databaseLock
+}
+
+type databaseLock struct {
+	token string
+	conn  *sql.Conn
+	timer *time.Timer
+}
+
+func newDatabaseBackend(db *sql.DB, dialect Dialect) *databaseBackend {
+	return &databaseBackend{db: db, dialect: dialect, held: make(map[string]*databaseLock)}
+}
+
+func (b *databaseBackend) TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	// Reserve the key so b.mu isn't held while talking to the database. Advisory
+	// locks are reentrant per session, so local holders are excluded here.
+	pending := &databaseLock{token: token}
+	b.mu.Lock()
+	if _, ok := b.held[key]; ok {
+		b.mu.Unlock()
+		return false, nil
+	}
+	b.held[key] = pending
+	b.mu.Unlock()
+
+	conn, acquired, err := b.lock(ctx, key)
+	b.mu.Lock()
+	if b.held[key] != pending {
+		// Close released every lock while this one was being acquired
+		b.mu.Unlock()
+		if acquired {
+			_ = b.unlock(context.WithoutCancel(ctx), key, &databaseLock{conn: conn})
+		}
+		return false, err
+	}
+	if !acquired {
+		delete(b.held, key)
+		b.mu.Unlock()
+		return false, err
+	}
+	pending.conn = conn
+	pending.timer = time.AfterFunc(ttl, func() { b.expire(key, pending) })
+	b.mu.Unlock()
+	return true, nil
+}
+
+// lock takes the advisory lock on a connection of its own, which it returns while
+// the lock is held.
+func (b *databaseBackend) lock(ctx context.Context, key string) (*sql.Conn, bool, error) {
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get a database connection for lock %s: %w", key, err)
+	}
+	query, arg := b.lockQuery(key)
+	var acquired sql.NullBool
+	if err := conn.QueryRowContext(ctx, query, arg).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	if !acquired.Valid || !acquired.Bool {
+		_ = conn.Close()
+		return nil, false, nil
+	}
+	return conn, true, nil
+}
+
+// heldBy returns the lock on key if token holds it. Locks still being acquired
+// are not held yet.
+func (b *databaseBackend) heldBy(key, token string) (*databaseLock, bool) {
+	lock, ok := b.held[key]
+	if !ok || lock.token != token || lock.conn == nil {
+		return nil, false
+	}
+	return lock, true
+}
+
+func (b *databaseBackend) Unlock(ctx context.Context, key, token string) (bool, error) {
+	b.mu.Lock()
+	lock, ok := b.heldBy(key, token)
+	if !ok {
+		b.mu.Unlock()
+		return false, nil
+	}
+	delete(b.held, key)
+	lock.timer.Stop()
+	b.mu.Unlock()
+
+	return true, b.unlock(ctx, key, lock)
+}
+
+func (b *databaseBackend) Extend(_ context.Context, key, token string, ttl time.Duration) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lock, ok := b.heldBy(key, token)
+	if !ok {
+		return false, nil
+	}
+	lock.timer.Reset(ttl)
+	return true, nil
+}
+
+// Close releases every held lock.
+func (b *databaseBackend) Close() error {
+	b.mu.Lock()
+	held := b.held
+	b.held = make(map[string]*databaseLock)
+	b.mu.Unlock()
+
+	for key, lock := range held {
+		if lock.conn == nil {
+			// Still being acquired; TryLock releases it
+			continue
+		}
+		lock.timer.Stop()
+		_ = b.unlock(context.Background(), key, lock)
+	}
+	return nil
+}
+
+// expire releases a lock whose TTL passed, unless it was released or replaced since.
+func (b *databaseBackend) expire(key string, lock *databaseLock) {
+	b.mu.Lock()
+	if b.held[key] != lock {
+		b.mu.Unlock()
+		return
+	}
+	delete(b.held, key)
+	b.mu.Unlock()
+	_ = b.unlock(context.Background(), key, lock)
+}
+
+// unlock releases the advisory lock and returns its connection to the pool.
+func (b *databaseBackend) unlock(ctx context.Context, key string, lock *databaseLock) error {
+	defer func() { _ = lock.conn.Close() }()
+	query, arg := b.unlockQuery(key)
+	if _, err := lock.conn.ExecContext(ctx, query, arg); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+	return nil
+}
+
+func (b *databaseBackend) lockQuery(key string) (string, any) {
+	if b.dialect == DialectMySQL {
+		return "SELECT GET_LOCK(?, 0)", mysqlLockName(key)
+	}
+	return "SELECT pg_try_advisory_lock($1)", postgresLockID(key)
+}
+
+func (b *databaseBackend) unlockQuery(key string) (string, any) {
+	if b.dialect == DialectMySQL {
+		return "SELECT RELEASE_LOCK(?)", mysqlLockName(key)
+	}
+	return "SELECT pg_advisory_unlock($1)", postgresLockID(key)
+}
+
+// postgresLockID maps a key to the 64-bit identifier of a Postgres advisory lock.
+func postgresLockID(key string) int64 {
+	sum := sha256.Sum256([]byte(key))
+	return int64(binary.BigEndian.Uint64(sum[:8])) //nolint:gosec // G115: wrapping into the signed lock ID space is intended
+}
+
+// mysqlLockName returns key, or a hash of it when it is too long for GET_LOCK.
+func mysqlLockName(key string) string {
+	if len(key) <= mysqlMaxLockName {
+		return key
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:mysqlMaxLockName/2])
+}
@@ -0,0 +1,90 @@
+package locks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDriver answers advisory lock queries with true once release is closed.
+type blockingDriver struct {
+	queried chan struct{}
+	release chan struct{}
+}
+
+func (d *blockingDriver) Open(string) (driver.Conn, error) { return &blockingConn{driver: d}, nil }
+
+type blockingConn struct {
+	driver *blockingDriver
+}
+
+func (c *blockingConn) Prepare(string) (driver.Stmt, error) { return nil, driver.ErrSkip }
+func (c *blockingConn) Close() error                        { return nil }
+func (c *blockingConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+func (c *blockingConn) QueryContext(ctx context.Context, _ string, _ []driver.NamedValue) (driver.Rows, error) {
+	select {
+	case c.driver.queried <- struct{}{}:
+	default:
+	}
+	select {
+	case <-c.driver.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &boolRows{}, nil
+}
+
+type boolRows struct {
+	done bool
+}
+
+func (r *boolRows) Columns() []string { return []string{"locked"} }
+func (r *boolRows) Close() error      { return nil }
+
+func (r *boolRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = true
+	return nil
+}
+
+func TestDatabaseBackend_TryLockDoesNotBlockOtherKeys(t *testing.T) {
+	drv := &blockingDriver{queried: make(chan struct{}, 1), release: make(chan struct{})}
+	sql.Register("locks-blocking", drv)
+	db, err := sql.Open("locks-blocking", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	backend := newDatabaseBackend(db, DialectPostgres)
+	ctx := context.Background()
+	acquired := make(chan bool, 1)
+	go func() {
+		ok, _ := backend.TryLock(ctx, "slow", "a", time.Minute)
+		acquired <- ok
+	}()
+	<-drv.queried
+
+	// The slow key is reserved, and other operations don't wait behind its query
+	ok, err := backend.TryLock(ctx, "slow", "b", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok, "a lock being acquired is not free")
+	extended, err := backend.Extend(ctx, "slow", "a", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, extended, "a lock being acquired is not held yet")
+
+	close(drv.release)
+	assert.True(t, <-acquired)
+	extended, err = backend.Extend(ctx, "slow", "a", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, extended)
+	require.NoError(t, backend.Close())
+}
@@ -0,0 +1,38 @@
+package locks
+
+import (
+	"errors"
+)
+
+// Module-specific errors for the locks module.
+var (
+	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
+	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrInvalidConfig is returned for configurations missing required settings
+	ErrInvalidConfig = errors.New("invalid locks configuration")
+
+	// ErrUnknownDriver is returned for drivers other than memory, redis and database
+	ErrUnknownDriver = errors.New("unknown lock driver")
+
+	// ErrUnknownDialect is returned for database dialects other than postgres and mysql
+	ErrUnknownDialect = errors.New("unknown database dialect")
+
+	// ErrNoDatabase is returned when the database driver is configured but no
+	// database service is available
+	ErrNoDatabase = errors.New("database lock driver requires a database service")
+
+	// ErrLockHeld is returned by TryAcquire when another holder has the lock
+	ErrLockHeld = errors.New("lock is held by another holder")
+
+	// ErrLockNotHeld is returned when releasing or refreshing a lock that expired or
+	// was taken over by another holder
+	ErrLockNotHeld = errors.New("lock is not held")
+
+	// ErrLockLost is returned by WithLock when the lock could not be kept for the
+	// whole run of its function
+	ErrLockLost = errors.New("lock was lost")
+
+	// ErrEmptyKey is returned when acquiring a lock without a key
+	ErrEmptyKey = errors.New("lock key must not be empty")
+)
@@ -0,0 +1,18 @@
+package locks
+
+// Event type constants for locks module events.
+// Following CloudEvents specification reverse domain notation.
+const (
+	// Configuration events
+	EventTypeConfigLoaded = "com.modular.locks.config.loaded"
+
+	// Lock events
+	EventTypeLockAcquired  = "com.modular.locks.lock.acquired"
+	EventTypeLockReleased  = "com.modular.locks.lock.released"
+	EventTypeLockContended = "com.modular.locks.lock.contended"
+	EventTypeLockLost      = "com.modular.locks.lock.lost"
+
+	// Module lifecycle events
+	EventTypeModuleStarted = "com.modular.locks.module.started"
+	EventTypeModuleStopped = "com.modular.locks.module.stopped"
+)
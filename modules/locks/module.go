@@ -0,0 +1,515 @@
+// Package locks provides distributed locks for the modular framework.
+//
+// The locks module lets several instances of an application coordinate work that
+// must only run in one place at a time, such as scheduled jobs, database migrations
+// or leader election. Locks are stored in process memory, in Redis, or as database
+// advisory locks through the database module, and expire after a TTL unless their
+// holder refreshes them. Lock state changes are emitted as CloudEvents.
+//
+// Example configuration:
+//
+//	locks:
+//	  driver: redis
+//	  redisURL: redis://localhost:6379
+//	  defaultTTL: 30s
+//
+// Usage:
+//
+//	var locker locks.LockService
+//	app.GetService(locks.ServiceName, &locker)
+//	err := locker.WithLock(ctx, "nightly-report", time.Minute, func(ctx context.Context) error {
+//	    return generateReport(ctx)
+//	})
+package locks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ModuleName is the unique identifier for the locks module.
+const ModuleName = "locks"
+
+// ServiceName is the name of the service provided by this module.
+const ServiceName = "locks.provider"
+
+// DatabaseServiceName is the key under which the database used by the database
+// driver is injected.
+const DatabaseServiceName = "database.service"
+
+// LocksModule provides distributed locks backed by memory, Redis or a database.
+type LocksModule struct {
+	name    string
+	config  *LocksConfig
+	logger  modular.Logger
+	subject modular.Subject
+	db      DBProvider
+	backend LockBackend
+
+	mu   sync.Mutex
+	held map[string]*Lock // by token
+
+	acquired  atomic.Uint64
+	released  atomic.Uint64
+	contended atomic.Uint64
+	lost      atomic.Uint64
+	errors    atomic.Uint64
+}
+
+var _ LockService = (*LocksModule)(nil)
+
+// NewModule creates a new instance of the locks module.
+func NewModule() modular.Module {
+	return &LocksModule{
+		name: ModuleName,
+		held: make(map[string]*Lock),
+	}
+}
+
+// Name returns the unique identifier for this module.
+func (m *LocksModule) Name() string {
+	return m.name
+}
+
+// RegisterConfig registers the module's configuration structure.
+func (m *LocksModule) RegisterConfig(app modular.Application) error {
+	// Check if locks config is already registered (e.g., by tests)
+	if existing, err := app.GetConfigSection(m.Name()); err == nil && existing != nil {
+		return nil
+	}
+
+	defaultConfig := &LocksConfig{
+		Driver:          DriverMemory,
+		DefaultTTL:      30 * time.Second,
+		RetryInterval:   100 * time.Millisecond,
+		KeyPrefix:       "locks:",
+		DatabaseDialect: DialectPostgres,
+	}
+
+	app.RegisterConfigSection(m.Name(), modular.NewStdConfigProvider(defaultConfig))
+	return nil
+}
+
+// SetBackend installs a custom LockBackend, replacing the configured driver. It
+// must be called before Init.
+func (m *LocksModule) SetBackend(backend LockBackend) {
+	m.backend = backend
+}
+
+// Init validates the configuration and creates the configured backend.
+func (m *LocksModule) Init(app modular.Application) error {
+	cfg, err := app.GetConfigSection(m.name)
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.name, err)
+	}
+
+	m.config = cfg.GetConfig().(*LocksConfig)
+	m.logger = app.Logger()
+
+	if err := m.config.Validate(); err != nil {
+		return err
+	}
+	if m.config.DefaultTTL == 0 {
+		m.config.DefaultTTL = 30 * time.Second
+	}
+	if m.config.RetryInterval == 0 {
+		m.config.RetryInterval = 100 * time.Millisecond
+	}
+
+	if m.backend == nil {
+		switch m.config.Driver {
+		case DriverRedis:
+			backend, err := newRedisBackend(m.config)
+			if err != nil {
+				return err
+			}
+			m.backend = backend
+		case DriverDatabase:
+			if m.db == nil || m.db.DB() == nil {
+				return ErrNoDatabase
+			}
+			m.backend = newDatabaseBackend(m.db.DB(), m.config.DatabaseDialect)
+		default:
+			m.backend = newMemoryBackend()
+		}
+	}
+
+	m.emitEvent(context.Background(), EventTypeConfigLoaded, map[string]interface{}{
+		"driver":      string(m.config.Driver),
+		"default_ttl": m.config.DefaultTTL.String(),
+	})
+
+	m.logger.Info("Locks module initialized", "driver", m.config.Driver)
+	return nil
+}
+
+// Start checks that the backend is reachable.
+func (m *LocksModule) Start(ctx context.Context) error {
+	if backend, ok := m.backend.(*redisBackend); ok {
+		if err := backend.ping(ctx); err != nil {
+			return err
+		}
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
+		"driver": string(m.config.Driver),
+	})
+	m.logger.Info("Locks module started", "driver", m.config.Driver)
+	return nil
+}
+
+// Stop releases the locks still held through this module, so other instances
+// don't have to wait for them to expire, and closes the backend.
+func (m *LocksModule) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	held := make([]*Lock, 0, len(m.held))
+	for _, lock := range m.held {
+		held = append(held, lock)
+	}
+	m.mu.Unlock()
+
+	for _, lock := range held {
+		if err := m.Release(ctx, lock); err != nil && !errors.Is(err, ErrLockNotHeld) {
+			m.logger.Warn("Failed to release lock on stop", "key", lock.Key, "error", err)
+		}
+	}
+	if err := m.backend.Close(); err != nil {
+		return fmt.Errorf("failed to close lock backend: %w", err)
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStopped, map[string]interface{}{
+		"released": len(held),
+	})
+	m.logger.Info("Locks module stopped")
+	return nil
+}
+
+// Dependencies returns the names of modules this module depends on.
+func (m *LocksModule) Dependencies() []string {
+	return nil
+}
+
+// ProvidesServices declares the services provided by this module.
+func (m *LocksModule) ProvidesServices() []modular.ServiceProvider {
+	return []modular.ServiceProvider{
+		{
+			Name:        ServiceName,
+			Description: "Distributed locks",
+			Instance:    m,
+		},
+	}
+}
+
+// RequiresServices declares the services this module uses. A database is only
+// needed by the database driver.
+func (m *LocksModule) RequiresServices() []modular.ServiceDependency {
+	return []modular.ServiceDependency{
+		{
+			Name:               DatabaseServiceName,
+			Required:           false,
+			MatchByInterface:   true,
+			SatisfiesInterface: reflect.TypeOf((*DBProvider)(nil)).Elem(),
+		},
+	}
+}
+
+// Constructor provides a dependency injection constructor for the module.
+func (m *LocksModule) Constructor() modular.ModuleConstructor {
+	return func(app modular.Application, services map[string]any) (modular.Module, error) {
+		if db, ok := services[DatabaseServiceName].(DBProvider); ok {
+			m.db = db
+		}
+		return m, nil
+	}
+}
+
+// Acquire waits until it acquires the lock on key or ctx is done.
+func (m *LocksModule) Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(m.config.RetryInterval)
+	defer ticker.Stop()
+
+	contended := false
+	for {
+		lock, err := m.tryAcquire(ctx, key, ttl, !contended)
+		if !errors.Is(err, ErrLockHeld) {
+			return lock, err
+		}
+		contended = true
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for lock %s: %w", key, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// TryAcquire acquires the lock on key if it is free, returning ErrLockHeld otherwise.
+func (m *LocksModule) TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	return m.tryAcquire(ctx, key, ttl, true)
+}
+
+// tryAcquire makes one acquisition attempt, counting contention only when
+// countContention is set so that Acquire's retries count once.
+func (m *LocksModule) tryAcquire(ctx context.Context, key string, ttl time.Duration, countContention bool) (*Lock, error) {
+	if key == "" {
+		return nil, ErrEmptyKey
+	}
+	if ttl <= 0 {
+		ttl = m.config.DefaultTTL
+	}
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	acquired, err := m.backend.TryLock(ctx, m.config.KeyPrefix+key, token, ttl)
+	if err != nil {
+		m.errors.Add(1)
+		return nil, err
+	}
+	if !acquired {
+		if countContention {
+			m.contended.Add(1)
+			m.emitEvent(ctx, EventTypeLockContended, map[string]interface{}{"key": key})
+		}
+		return nil, fmt.Errorf("%w: %s", ErrLockHeld, key)
+	}
+
+	lock := &Lock{Key: key, Token: token, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+	m.mu.Lock()
+	m.held[token] = lock
+	m.mu.Unlock()
+	m.acquired.Add(1)
+
+	m.emitEvent(ctx, EventTypeLockAcquired, map[string]interface{}{
+		"key":    key,
+		"ttl_ms": ttl.Milliseconds(),
+	})
+	m.logger.Debug("Lock acquired", "key", key, "ttl", ttl)
+	return lock, nil
+}
+
+// Release releases a held lock.
+func (m *LocksModule) Release(ctx context.Context, lock *Lock) error {
+	m.mu.Lock()
+	delete(m.held, lock.Token)
+	m.mu.Unlock()
+
+	released, err := m.backend.Unlock(ctx, m.config.KeyPrefix+lock.Key, lock.Token)
+	if err != nil {
+		m.errors.Add(1)
+		return err
+	}
+	if !released {
+		m.markLost(ctx, lock)
+		return fmt.Errorf("%w: %s", ErrLockNotHeld, lock.Key)
+	}
+	m.released.Add(1)
+
+	m.emitEvent(ctx, EventTypeLockReleased, map[string]interface{}{
+		"key":     lock.Key,
+		"held_ms": time.Since(lock.AcquiredAt).Milliseconds(),
+	})
+	m.logger.Debug("Lock released", "key", lock.Key)
+	return nil
+}
+
+// Refresh extends a held lock to expire ttl from now.
+func (m *LocksModule) Refresh(ctx context.Context, lock *Lock, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = m.config.DefaultTTL
+	}
+	now := time.Now()
+	extended, err := m.backend.Extend(ctx, m.config.KeyPrefix+lock.Key, lock.Token, ttl)
+	if err != nil {
+		m.errors.Add(1)
+		return err
+	}
+	if !extended {
+		m.markLost(ctx, lock)
+		return fmt.Errorf("%w: %s", ErrLockNotHeld, lock.Key)
+	}
+
+	m.mu.Lock()
+	lock.ExpiresAt = now.Add(ttl)
+	m.mu.Unlock()
+	return nil
+}
+
+// WithLock runs fn while holding the lock on key.
+func (m *LocksModule) WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	if ttl <= 0 {
+		ttl = m.config.DefaultTTL
+	}
+	lock, err := m.Acquire(ctx, key, ttl)
+	if err != nil {
+		return err
+	}
+	return m.runLocked(ctx, lock, ttl, fn)
+}
+
+// TryWithLock runs fn while holding the lock on key if it is free.
+func (m *LocksModule) TryWithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) (bool, error) {
+	if ttl <= 0 {
+		ttl = m.config.DefaultTTL
+	}
+	lock, err := m.TryAcquire(ctx, key, ttl)
+	if errors.Is(err, ErrLockHeld) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, m.runLocked(ctx, lock, ttl, fn)
+}
+
+// runLocked runs fn while keeping lock refreshed, then releases it. fn's context is
+// cancelled when the lock is lost.
+func (m *LocksModule) runLocked(ctx context.Context, lock *Lock, ttl time.Duration, fn func(ctx context.Context) error) error {
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	done := make(chan struct{})
+	refreshed := make(chan struct{})
+	go func() {
+		defer close(refreshed)
+		m.keepRefreshed(runCtx, lock, ttl, done, cancel)
+	}()
+
+	fnErr := fn(runCtx)
+	close(done)
+	<-refreshed
+
+	if errors.Is(context.Cause(runCtx), ErrLockLost) {
+		return errors.Join(fnErr, fmt.Errorf("%w: %s", ErrLockLost, lock.Key))
+	}
+	if err := m.Release(context.WithoutCancel(ctx), lock); err != nil {
+		return errors.Join(fnErr, err)
+	}
+	return fnErr
+}
+
+// keepRefreshed refreshes lock every third of its TTL until done is closed. Backend
+// errors are retried until the lock would have expired; then, or when the lock is
+// found taken over, lost is called with ErrLockLost.
+func (m *LocksModule) keepRefreshed(ctx context.Context, lock *Lock, ttl time.Duration, done <-chan struct{}, lost context.CancelCauseFunc) {
+	ticker := time.NewTicker(max(ttl/3, time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := m.Refresh(ctx, lock, ttl)
+		if err == nil {
+			continue
+		}
+		m.mu.Lock()
+		expired := !time.Now().Before(lock.ExpiresAt)
+		m.mu.Unlock()
+		if errors.Is(err, ErrLockNotHeld) || expired {
+			if !errors.Is(err, ErrLockNotHeld) {
+				m.markLost(ctx, lock)
+			}
+			lost(ErrLockLost)
+			return
+		}
+		m.logger.Warn("Failed to refresh lock, retrying", "key", lock.Key, "error", err)
+	}
+}
+
+// markLost records that a held lock expired or was taken over.
+func (m *LocksModule) markLost(ctx context.Context, lock *Lock) {
+	m.mu.Lock()
+	delete(m.held, lock.Token)
+	m.mu.Unlock()
+	m.lost.Add(1)
+	m.emitEvent(ctx, EventTypeLockLost, map[string]interface{}{"key": lock.Key})
+	m.logger.Warn("Lock lost", "key", lock.Key)
+}
+
+// Stats returns lock operation counts.
+func (m *LocksModule) Stats() LockStats {
+	m.mu.Lock()
+	held := len(m.held)
+	m.mu.Unlock()
+	return LockStats{
+		Acquired:  m.acquired.Load(),
+		Released:  m.released.Load(),
+		Contended: m.contended.Load(),
+		Lost:      m.lost.Load(),
+		Errors:    m.errors.Load(),
+		Held:      held,
+	}
+}
+
+// newToken returns a random token identifying a lock holder.
+func newToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// RegisterObservers implements the ObservableModule interface.
+func (m *LocksModule) RegisterObservers(subject modular.Subject) error {
+	m.subject = subject
+	return nil
+}
+
+// EmitEvent implements the ObservableModule interface.
+func (m *LocksModule) EmitEvent(ctx context.Context, event cloudevents.Event) error {
+	if m.subject == nil {
+		return ErrNoSubjectForEventEmission
+	}
+	if err := m.subject.NotifyObservers(ctx, event); err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+	return nil
+}
+
+// emitEvent creates and emits a CloudEvent for the locks module. It silently skips
+// emission when no subject is available.
+func (m *LocksModule) emitEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if m.subject == nil {
+		return
+	}
+
+	event := modular.NewCloudEvent(eventType, "locks-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+		if errors.Is(emitErr, ErrNoSubjectForEventEmission) {
+			return
+		}
+		if m.logger != nil {
+			m.logger.Warn("Failed to emit locks event", "eventType", eventType, "error", emitErr)
+		}
+	}
+}
+
+// GetRegisteredEventTypes implements the ObservableModule interface.
+// Returns all event types that this locks module can emit.
+func (m *LocksModule) GetRegisteredEventTypes() []string {
+	return []string{
+		EventTypeConfigLoaded,
+		EventTypeLockAcquired,
+		EventTypeLockReleased,
+		EventTypeLockContended,
+		EventTypeLockLost,
+		EventTypeModuleStarted,
+		EventTypeModuleStopped,
+	}
+}
@@ -0,0 +1,249 @@
+package locks
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/alicebob/miniredis/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder records the types of events emitted to an observable application.
+type eventRecorder struct {
+	mu    sync.Mutex
+	types []string
+}
+
+func (r *eventRecorder) record(ctx context.Context, event cloudevents.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types = append(r.types, event.Type())
+	return nil
+}
+
+func (r *eventRecorder) count(eventType string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, recorded := range r.types {
+		if recorded == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+// startLocks runs the locks module in an observable application with the given config.
+func startLocks(t *testing.T, config *LocksConfig) (*LocksModule, *eventRecorder) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	app := modular.NewObservableApplication(modular.NewStdConfigProvider(nil), logger)
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+
+	recorder := &eventRecorder{}
+	require.NoError(t, app.RegisterObserver(modular.NewFunctionalObserver("locks-test", recorder.record)))
+
+	module := NewModule().(*LocksModule)
+	app.RegisterModule(module)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+
+	var service LockService
+	require.NoError(t, app.GetService(ServiceName, &service))
+	return module, recorder
+}
+
+func TestLocks_AcquireReleaseAndContention(t *testing.T) {
+	mr := miniredis.RunT(t)
+	drivers := map[string]*LocksConfig{
+		"memory": {Driver: DriverMemory, RetryInterval: 5 * time.Millisecond, KeyPrefix: "locks:"},
+		"redis":  {Driver: DriverRedis, RetryInterval: 5 * time.Millisecond, KeyPrefix: "locks:", RedisURL: "redis://" + mr.Addr()},
+	}
+	for name, config := range drivers {
+		t.Run(name, func(t *testing.T) {
+			locks, recorder := startLocks(t, config)
+			ctx := context.Background()
+
+			first, err := locks.TryAcquire(ctx, "report", time.Minute)
+			require.NoError(t, err)
+			_, err = locks.TryAcquire(ctx, "report", time.Minute)
+			require.ErrorIs(t, err, ErrLockHeld)
+
+			waitCtx, cancel := context.WithTimeout(ctx, 30*time.Millisecond)
+			_, err = locks.Acquire(waitCtx, "report", time.Minute)
+			cancel()
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			acquired := make(chan *Lock)
+			go func() {
+				lock, err := locks.Acquire(ctx, "report", time.Minute)
+				assert.NoError(t, err)
+				acquired <- lock
+			}()
+			require.Eventually(t, func() bool { return locks.Stats().Contended == 3 }, time.Second, time.Millisecond)
+			require.NoError(t, locks.Refresh(ctx, first, 2*time.Minute))
+			require.NoError(t, locks.Release(ctx, first))
+			second := <-acquired
+			assert.NotEqual(t, first.Token, second.Token)
+
+			require.ErrorIs(t, locks.Release(ctx, first), ErrLockNotHeld, "a released lock can't be released again")
+			require.ErrorIs(t, locks.Refresh(ctx, first, time.Minute), ErrLockNotHeld)
+
+			stats := locks.Stats()
+			assert.Equal(t, uint64(2), stats.Acquired)
+			assert.Equal(t, uint64(1), stats.Released)
+			assert.Equal(t, uint64(3), stats.Contended, "each TryAcquire and Acquire call counts contention once")
+			assert.Equal(t, 1, stats.Held)
+
+			require.Eventually(t, func() bool {
+				return recorder.count(EventTypeLockAcquired) == 2 && recorder.count(EventTypeLockReleased) == 1
+			}, time.Second, 5*time.Millisecond)
+		})
+	}
+}
+
+func TestLocks_RedisLockExpires(t *testing.T) {
+	mr := miniredis.RunT(t)
+	locks, _ := startLocks(t, &LocksConfig{Driver: DriverRedis, RetryInterval: time.Millisecond, KeyPrefix: "app:", RedisURL: "redis://" + mr.Addr()})
+	ctx := context.Background()
+
+	expired, err := locks.TryAcquire(ctx, "migrations", time.Second)
+	require.NoError(t, err)
+	assert.True(t, mr.Exists("app:migrations"))
+
+	mr.FastForward(2 * time.Second)
+	taken, err := locks.TryAcquire(ctx, "migrations", time.Minute)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, locks.Release(ctx, expired), ErrLockNotHeld, "an expired holder can't release the new holder's lock")
+	assert.True(t, mr.Exists("app:migrations"))
+	require.NoError(t, locks.Release(ctx, taken))
+	assert.False(t, mr.Exists("app:migrations"))
+}
+
+func TestLocks_MemoryLockExpires(t *testing.T) {
+	backend := newMemoryBackend()
+	now := time.Now()
+	backend.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	acquired, err := backend.TryLock(ctx, "job", "a", time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = backend.TryLock(ctx, "job", "b", time.Second)
+	require.NoError(t, err)
+	assert.False(t, acquired)
+
+	now = now.Add(2 * time.Second)
+	extended, err := backend.Extend(ctx, "job", "a", time.Second)
+	require.NoError(t, err)
+	assert.False(t, extended, "expired locks can't be extended")
+	acquired, err = backend.TryLock(ctx, "job", "b", time.Second)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+// losingBackend is a memory backend that stops extending locks once lose is set.
+type losingBackend struct {
+	*memoryBackend
+	lose atomic.Bool
+}
+
+func (b *losingBackend) Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	if b.lose.Load() {
+		return false, nil
+	}
+	return b.memoryBackend.Extend(ctx, key, token, ttl)
+}
+
+func TestLocks_WithLock(t *testing.T) {
+	backend := &losingBackend{memoryBackend: newMemoryBackend()}
+	module := NewModule().(*LocksModule)
+	module.SetBackend(backend)
+	locks, recorder := func() (*LocksModule, *eventRecorder) {
+		logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+		app := modular.NewObservableApplication(modular.NewStdConfigProvider(nil), logger)
+		app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&LocksConfig{Driver: DriverMemory, RetryInterval: time.Millisecond}))
+		recorder := &eventRecorder{}
+		require.NoError(t, app.RegisterObserver(modular.NewFunctionalObserver("locks-test", recorder.record)))
+		app.RegisterModule(module)
+		require.NoError(t, app.Init())
+		require.NoError(t, app.Start())
+		t.Cleanup(func() { _ = app.Stop() })
+		return module, recorder
+	}()
+	ctx := context.Background()
+
+	// The lock is refreshed while fn outlives its TTL
+	err := locks.WithLock(ctx, "leader", 30*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(100 * time.Millisecond)
+		_, err := locks.TryAcquire(ctx, "leader", time.Second)
+		assert.ErrorIs(t, err, ErrLockHeld)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 0, locks.Stats().Held)
+
+	backend.lose.Store(true)
+	err = locks.WithLock(ctx, "leader", 30*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	require.ErrorIs(t, err, ErrLockLost)
+	assert.Equal(t, uint64(1), locks.Stats().Lost)
+	require.Eventually(t, func() bool { return recorder.count(EventTypeLockLost) == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestLocksConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config LocksConfig
+		err    error
+	}{
+		{"unknown driver", LocksConfig{Driver: "etcd"}, ErrUnknownDriver},
+		{"redis without url", LocksConfig{Driver: DriverRedis}, ErrInvalidConfig},
+		{"unknown dialect", LocksConfig{Driver: DriverDatabase, DatabaseDialect: "oracle"}, ErrUnknownDialect},
+		{"negative ttl", LocksConfig{Driver: DriverMemory, DefaultTTL: -time.Second}, ErrInvalidConfig},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.Validate(), tt.err)
+		})
+	}
+}
+
+func TestLockNames(t *testing.T) {
+	assert.Equal(t, postgresLockID("locks:a"), postgresLockID("locks:a"))
+	assert.NotEqual(t, postgresLockID("locks:a"), postgresLockID("locks:b"))
+	assert.Equal(t, "locks:short", mysqlLockName("locks:short"))
+	long := mysqlLockName("locks:" + string(make([]byte, 100)))
+	assert.Len(t, long, mysqlMaxLockName)
+}
+
+func TestLocks_TryWithLock(t *testing.T) {
+	locks, _ := startLocks(t, &LocksConfig{Driver: DriverMemory, RetryInterval: time.Millisecond})
+	ctx := context.Background()
+
+	ran, err := locks.TryWithLock(ctx, "nightly", time.Second, func(ctx context.Context) error {
+		// Another holder skips the work instead of waiting
+		nested, err := locks.TryWithLock(ctx, "nightly", time.Second, func(context.Context) error {
+			t.Error("fn ran while the lock was held")
+			return nil
+		})
+		assert.False(t, nested)
+		return err
+	})
+	require.NoError(t, err)
+	assert.True(t, ran)
+	assert.Equal(t, 0, locks.Stats().Held)
+}
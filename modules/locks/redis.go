@@ -0,0 +1,85 @@
+package locks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Scripts compare the stored token before changing a key, so a holder whose lock
+// expired can't release or extend a lock since acquired by someone else.
+var (
+	redisUnlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+	redisExtendScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// redisBackend stores each lock as a key holding its owner's token, expiring
+// with the lock's TTL.
+type redisBackend struct {
+	client *redis.Client
+}
+
+func newRedisBackend(config *LocksConfig) (*redisBackend, error) {
+	opts, err := redis.ParseURL(config.RedisURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	if config.RedisPassword != "" {
+		opts.Password = config.RedisPassword
+	}
+	if config.RedisDB != 0 {
+		opts.DB = config.RedisDB
+	}
+	return &redisBackend{client: redis.NewClient(opts)}, nil
+}
+
+// ping checks that the Redis server is reachable.
+func (b *redisBackend) ping(ctx context.Context) error {
+	if err := b.client.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to ping Redis server: %w", err)
+	}
+	return nil
+}
+
+func (b *redisBackend) TryLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	acquired, err := b.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+	return acquired, nil
+}
+
+func (b *redisBackend) Unlock(ctx context.Context, key, token string) (bool, error) {
+	deleted, err := redisUnlockScript.Run(ctx, b.client, []string{key}, token).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+	return deleted == 1, nil
+}
+
+func (b *redisBackend) Extend(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	extended, err := redisExtendScript.Run(ctx, b.client, []string{key}, token, ttl.Milliseconds()).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("failed to extend lock %s: %w", key, err)
+	}
+	return extended == 1, nil
+}
+
+func (b *redisBackend) Close() error {
+	if err := b.client.Close(); err != nil {
+		return fmt.Errorf("failed to close Redis client: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package locks
+
+import (
+	"context"
+	"time"
+)
+
+// Lock is a held lock. Pass it back to Release or Refresh.
+type Lock struct {
+	// Key is the name the lock was acquired under
+	Key string
+	// Token identifies this holder; only it can release or refresh the lock
+	Token string
+	// AcquiredAt is when the lock was acquired
+	AcquiredAt time.Time
+	// ExpiresAt is when the lock expires unless it is refreshed
+	ExpiresAt time.Time
+}
+
+// LockStats counts lock operations since the module started.
+type LockStats struct {
+	// Acquired is the number of locks acquired
+	Acquired uint64 `json:"acquired"`
+	// Released is the number of locks released by their holder
+	Released uint64 `json:"released"`
+	// Contended is the number of acquisition attempts that found the lock held
+	Contended uint64 `json:"contended"`
+	// Lost is the number of locks that expired or were taken over while held
+	Lost uint64 `json:"lost"`
+	// Errors is the number of backend errors
+	Errors uint64 `json:"errors"`
+	// Held is the number of locks currently held through this module
+	Held int `json:"held"`
+}
+
+// LockService provides distributed locks shared by every instance of an application
+// using the same backend.
+type LockService interface {
+	// Acquire waits until it acquires the lock on key or ctx is done. The lock
+	// expires after ttl unless refreshed; a ttl of zero uses the configured DefaultTTL.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+
+	// TryAcquire acquires the lock on key if it is free, returning ErrLockHeld otherwise.
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) (*Lock, error)
+
+	// Release releases a held lock. It returns ErrLockNotHeld if the lock expired
+	// or was taken over.
+	Release(ctx context.Context, lock *Lock) error
+
+	// Refresh extends a held lock to expire ttl from now. It returns ErrLockNotHeld
+	// if the lock expired or was taken over.
+	Refresh(ctx context.Context, lock *Lock, ttl time.Duration) error
+
+	// WithLock runs fn while holding the lock on key, refreshing it in the
+	// background and releasing it when fn returns. If the lock is lost while fn
+	// runs, fn's context is cancelled and WithLock returns ErrLockLost.
+	WithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) error
+
+	// TryWithLock runs fn like WithLock if the lock on key is free, and reports
+	// whether it ran. It returns false without running fn when the lock is held.
+	TryWithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) (bool, error)
+
+	// Stats returns lock operation counts.
+	Stats() LockStats
+}
@@ -0,0 +1,92 @@
+package pinger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// maxBodyBytes caps how much of a response body http checks read.
+const maxBodyBytes = 1 << 20
+
+// prober runs one probe of a check's target.
+type prober func(ctx context.Context) error
+
+// newProber returns the probe for a validated check.
+func newProber(check CheckConfig, client *http.Client, resolver *net.Resolver) prober {
+	switch check.Type {
+	case CheckTypeTCP:
+		return func(ctx context.Context) error { return probeTCP(ctx, check.Address) }
+	case CheckTypeDNS:
+		return func(ctx context.Context) error { return probeDNS(ctx, resolver, check.Host) }
+	default:
+		return func(ctx context.Context) error { return probeHTTP(ctx, client, check) }
+	}
+}
+
+// probeHTTP sends the check's request and verifies the response status and body.
+func probeHTTP(ctx context.Context, client *http.Client, check CheckConfig) error {
+	var body io.Reader
+	if check.Body != "" {
+		body = strings.NewReader(check.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, check.Method, check.URL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range check.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if check.ExpectedStatus != 0 && resp.StatusCode != check.ExpectedStatus {
+		return fmt.Errorf("%w: got %d, want %d", ErrUnexpectedStatus, resp.StatusCode, check.ExpectedStatus)
+	}
+	if check.ExpectedStatus == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299) {
+		return fmt.Errorf("%w: got %d, want 2xx", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	if check.ExpectedBody == "" {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxBodyBytes))
+		return nil
+	}
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if !strings.Contains(string(content), check.ExpectedBody) {
+		return ErrUnexpectedBody
+	}
+	return nil
+}
+
+// probeTCP opens and closes a TCP connection to address.
+func probeTCP(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// probeDNS resolves host and requires at least one address.
+func probeDNS(ctx context.Context, resolver *net.Resolver, host string) error {
+	addresses, err := resolver.LookupHost(ctx, host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve: %w", err)
+	}
+	if len(addresses) == 0 {
+		return ErrNoAddresses
+	}
+	return nil
+}
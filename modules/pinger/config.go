@@ -0,0 +1,160 @@
+package pinger
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+// CheckType identifies how a synthetic check probes its target.
+type CheckType string
+
+const (
+	// CheckTypeHTTP sends an HTTP request and verifies the response status and body
+	CheckTypeHTTP CheckType = "http"
+	// CheckTypeTCP opens a TCP connection to a host:port address
+	CheckTypeTCP CheckType = "tcp"
+	// CheckTypeDNS resolves a host name
+	CheckTypeDNS CheckType = "dns"
+)
+
+// PingerConfig defines the synthetic checks run by the pinger module.
+type PingerConfig struct {
+	// Checks are the synthetic checks to run
+	Checks []CheckConfig `json:"checks" yaml:"checks"`
+
+	// DefaultInterval is how often checks without their own interval run
+	DefaultInterval time.Duration `json:"defaultInterval" yaml:"defaultInterval" env:"DEFAULT_INTERVAL" default:"30s"`
+
+	// DefaultTimeout bounds checks without their own timeout
+	DefaultTimeout time.Duration `json:"defaultTimeout" yaml:"defaultTimeout" env:"DEFAULT_TIMEOUT" default:"5s"`
+}
+
+// CheckConfig defines a single synthetic check.
+//
+//	checks:
+//	  - name: payments-api
+//	    type: http
+//	    url: https://payments.example.com/health
+//	    expectedStatus: 200
+//	    expectedBody: '"status":"ok"'
+//	  - name: smtp
+//	    type: tcp
+//	    address: smtp.example.com:587
+//	  - name: partner-dns
+//	    type: dns
+//	    host: api.partner.example.com
+type CheckConfig struct {
+	// Name identifies the check in results, events and health reports
+	Name string `json:"name" yaml:"name"`
+
+	// Type is http, tcp or dns
+	Type CheckType `json:"type" yaml:"type"`
+
+	// Interval is how often the check runs. Defaults to DefaultInterval.
+	Interval time.Duration `json:"interval" yaml:"interval"`
+
+	// Timeout bounds a single run of the check. Defaults to DefaultTimeout.
+	Timeout time.Duration `json:"timeout" yaml:"timeout"`
+
+	// FailureThreshold is the number of consecutive failures before the check is
+	// reported unhealthy. Defaults to 1.
+	FailureThreshold int `json:"failureThreshold" yaml:"failureThreshold"`
+
+	// SuccessThreshold is the number of consecutive successes before a failing check
+	// is reported healthy again. Defaults to 1.
+	SuccessThreshold int `json:"successThreshold" yaml:"successThreshold"`
+
+	// Critical makes the application unhealthy, and so not ready, while the check is
+	// unhealthy. Other unhealthy checks only report the application degraded.
+	Critical bool `json:"critical" yaml:"critical"`
+
+	// URL is the target of http checks
+	URL string `json:"url" yaml:"url"`
+
+	// Method is the HTTP method of http checks. Defaults to GET.
+	Method string `json:"method" yaml:"method"`
+
+	// Headers are added to the requests of http checks
+	Headers map[string]string `json:"headers" yaml:"headers"`
+
+	// Body is sent with the requests of http checks
+	Body string `json:"body" yaml:"body"`
+
+	// ExpectedStatus is the response status http checks require. Zero accepts any 2xx status.
+	ExpectedStatus int `json:"expectedStatus" yaml:"expectedStatus"`
+
+	// ExpectedBody is a substring the response body of http checks must contain
+	ExpectedBody string `json:"expectedBody" yaml:"expectedBody"`
+
+	// Address is the host:port target of tcp checks
+	Address string `json:"address" yaml:"address"`
+
+	// Host is the name dns checks resolve
+	Host string `json:"host" yaml:"host"`
+}
+
+// Validate implements the ConfigValidator interface for PingerConfig.
+func (c *PingerConfig) Validate() error {
+	names := make(map[string]struct{}, len(c.Checks))
+	for i := range c.Checks {
+		check := &c.Checks[i]
+		if check.Name == "" {
+			return fmt.Errorf("%w: check %d has no name", ErrInvalidCheck, i)
+		}
+		if _, ok := names[check.Name]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateCheckName, check.Name)
+		}
+		names[check.Name] = struct{}{}
+		if err := check.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks that the fields required by the check's type are set.
+func (c *CheckConfig) validate() error {
+	if c.Interval < 0 || c.Timeout < 0 || c.FailureThreshold < 0 || c.SuccessThreshold < 0 {
+		return fmt.Errorf("%w: %s has a negative interval, timeout or threshold", ErrInvalidCheck, c.Name)
+	}
+	switch c.Type {
+	case CheckTypeHTTP:
+		target, err := url.Parse(c.URL)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return fmt.Errorf("%w: %s needs an http or https url", ErrInvalidCheck, c.Name)
+		}
+	case CheckTypeTCP:
+		if _, _, err := net.SplitHostPort(c.Address); err != nil {
+			return fmt.Errorf("%w: %s needs a host:port address: %w", ErrInvalidCheck, c.Name, err)
+		}
+	case CheckTypeDNS:
+		if c.Host == "" {
+			return fmt.Errorf("%w: %s needs a host", ErrInvalidCheck, c.Name)
+		}
+	default:
+		return fmt.Errorf("%w: %s has type %q", ErrUnknownCheckType, c.Name, c.Type)
+	}
+	return nil
+}
+
+// withDefaults returns the check with unset interval, timeout and thresholds filled in.
+func (c CheckConfig) withDefaults(config *PingerConfig) CheckConfig {
+	if c.Interval <= 0 {
+		c.Interval = config.DefaultInterval
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = config.DefaultTimeout
+	}
+	if c.FailureThreshold <= 0 {
+		c.FailureThreshold = 1
+	}
+	if c.SuccessThreshold <= 0 {
+		c.SuccessThreshold = 1
+	}
+	if c.Type == CheckTypeHTTP && c.Method == "" {
+		c.Method = "GET"
+	}
+	return c
+}
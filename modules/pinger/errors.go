@@ -0,0 +1,32 @@
+package pinger
+
+import (
+	"errors"
+)
+
+// Module-specific errors for the pinger module.
+var (
+	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
+	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrInvalidCheck is returned for checks missing the fields their type requires
+	ErrInvalidCheck = errors.New("invalid pinger check")
+
+	// ErrDuplicateCheckName is returned when two checks share a name
+	ErrDuplicateCheckName = errors.New("duplicate pinger check name")
+
+	// ErrUnknownCheckType is returned for check types other than http, tcp and dns
+	ErrUnknownCheckType = errors.New("unknown pinger check type")
+
+	// ErrUnknownCheck is returned when asking for the result of a check that is not configured
+	ErrUnknownCheck = errors.New("unknown pinger check")
+
+	// ErrUnexpectedStatus is returned by http checks receiving a status other than the expected one
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+
+	// ErrUnexpectedBody is returned by http checks whose response lacks the expected body
+	ErrUnexpectedBody = errors.New("response body does not contain expected content")
+
+	// ErrNoAddresses is returned by dns checks resolving a host to no addresses
+	ErrNoAddresses = errors.New("host resolved to no addresses")
+)
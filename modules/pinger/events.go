@@ -0,0 +1,16 @@
+package pinger
+
+// Event type constants for pinger module events.
+// Following CloudEvents specification reverse domain notation.
+const (
+	// Configuration events
+	EventTypeConfigLoaded = "com.modular.pinger.config.loaded"
+
+	// Check events
+	EventTypeCheckHealthy   = "com.modular.pinger.check.healthy"
+	EventTypeCheckUnhealthy = "com.modular.pinger.check.unhealthy"
+
+	// Module lifecycle events
+	EventTypeModuleStarted = "com.modular.pinger.module.started"
+	EventTypeModuleStopped = "com.modular.pinger.module.stopped"
+)
@@ -0,0 +1,412 @@
+// Package pinger provides config-driven synthetic checks for the modular framework.
+//
+// The pinger module periodically probes dependencies the application relies on but
+// does not otherwise route to, such as third-party APIs, mail relays or partner DNS
+// names. Each check is an HTTP request with an expected status and body, a TCP
+// connect, or a DNS resolution. Results are available through the PingerService and
+// the application's readiness, and state changes are emitted as CloudEvents.
+//
+// Example configuration:
+//
+//	pinger:
+//	  defaultInterval: 30s
+//	  defaultTimeout: 5s
+//	  checks:
+//	    - name: payments-api
+//	      type: http
+//	      url: https://payments.example.com/health
+//	      expectedStatus: 200
+//	      failureThreshold: 3
+package pinger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// ModuleName is the unique identifier for the pinger module.
+const ModuleName = "pinger"
+
+// ServiceName is the name of the service provided by this module.
+const ServiceName = "pinger.provider"
+
+// PingerModule runs synthetic checks on intervals and tracks their health.
+type PingerModule struct {
+	name    string
+	config  *PingerConfig
+	logger  modular.Logger
+	subject modular.Subject
+
+	client   *http.Client
+	resolver *net.Resolver
+
+	mu      sync.RWMutex
+	checks  []*checkRunner
+	byName  map[string]*checkRunner
+	running bool
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// checkRunner is a configured check with its probe and latest result.
+type checkRunner struct {
+	config CheckConfig
+	probe  prober
+	result CheckResult // guarded by PingerModule.mu
+}
+
+// NewModule creates a new instance of the pinger module.
+func NewModule() modular.Module {
+	return &PingerModule{
+		name: ModuleName,
+	}
+}
+
+// Name returns the unique identifier for this module.
+func (m *PingerModule) Name() string {
+	return m.name
+}
+
+// RegisterConfig registers the module's configuration structure.
+func (m *PingerModule) RegisterConfig(app modular.Application) error {
+	// Check if pinger config is already registered (e.g., by tests)
+	if existing, err := app.GetConfigSection(m.Name()); err == nil && existing != nil {
+		return nil
+	}
+
+	defaultConfig := &PingerConfig{
+		DefaultInterval: 30 * time.Second,
+		DefaultTimeout:  5 * time.Second,
+	}
+
+	app.RegisterConfigSection(m.Name(), modular.NewStdConfigProvider(defaultConfig))
+	return nil
+}
+
+// Init validates the configured checks and prepares them to run.
+func (m *PingerModule) Init(app modular.Application) error {
+	cfg, err := app.GetConfigSection(m.name)
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.name, err)
+	}
+
+	m.config = cfg.GetConfig().(*PingerConfig)
+	m.logger = app.Logger()
+
+	if err := m.config.Validate(); err != nil {
+		return err
+	}
+
+	if m.client == nil {
+		m.client = &http.Client{}
+	}
+	if m.resolver == nil {
+		m.resolver = net.DefaultResolver
+	}
+
+	m.mu.Lock()
+	m.checks = make([]*checkRunner, 0, len(m.config.Checks))
+	m.byName = make(map[string]*checkRunner, len(m.config.Checks))
+	for _, check := range m.config.Checks {
+		check = check.withDefaults(m.config)
+		runner := &checkRunner{
+			config: check,
+			probe:  newProber(check, m.client, m.resolver),
+			result: CheckResult{Name: check.Name, Type: check.Type, Status: CheckStatusUnknown},
+		}
+		m.checks = append(m.checks, runner)
+		m.byName[check.Name] = runner
+	}
+	m.mu.Unlock()
+
+	m.emitEvent(context.Background(), EventTypeConfigLoaded, map[string]interface{}{
+		"check_count":      len(m.config.Checks),
+		"default_interval": m.config.DefaultInterval.String(),
+		"default_timeout":  m.config.DefaultTimeout.String(),
+	})
+
+	m.logger.Info("Pinger module initialized", "checks", len(m.config.Checks))
+	return nil
+}
+
+// Start runs every check immediately and then on its interval.
+func (m *PingerModule) Start(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	// Checks outlive the start context; Stop cancels them
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.cancel = cancel
+	m.running = true
+	checks := m.checks
+	m.mu.Unlock()
+
+	for _, runner := range checks {
+		m.wg.Add(1)
+		go m.runLoop(runCtx, runner)
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
+		"check_count": len(checks),
+	})
+	m.logger.Info("Pinger started", "checks", len(checks))
+	return nil
+}
+
+// Stop cancels running checks and waits for them to return.
+func (m *PingerModule) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	if !m.running {
+		m.mu.Unlock()
+		return nil
+	}
+	m.running = false
+	m.cancel()
+	m.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for pinger checks to stop: %w", ctx.Err())
+	}
+
+	m.emitEvent(ctx, EventTypeModuleStopped, map[string]interface{}{
+		"check_count": len(m.checks),
+	})
+	m.logger.Info("Pinger stopped")
+	return nil
+}
+
+// Dependencies returns the names of modules this module depends on.
+func (m *PingerModule) Dependencies() []string {
+	return nil
+}
+
+// ProvidesServices declares the services provided by this module.
+func (m *PingerModule) ProvidesServices() []modular.ServiceProvider {
+	return []modular.ServiceProvider{
+		{
+			Name:        ServiceName,
+			Description: "Synthetic check results",
+			Instance:    m,
+		},
+	}
+}
+
+// Results returns the latest result of every check, in configuration order.
+func (m *PingerModule) Results() []CheckResult {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	results := make([]CheckResult, 0, len(m.checks))
+	for _, runner := range m.checks {
+		results = append(results, runner.result)
+	}
+	return results
+}
+
+// Result returns the latest result of the named check.
+func (m *PingerModule) Result(name string) (CheckResult, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	runner, ok := m.byName[name]
+	if !ok {
+		return CheckResult{}, fmt.Errorf("%w: %s", ErrUnknownCheck, name)
+	}
+	return runner.result, nil
+}
+
+// Healthy reports whether no check is unhealthy. Checks that have not been judged yet
+// do not count against health.
+func (m *PingerModule) Healthy() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, runner := range m.checks {
+		if runner.result.Status == CheckStatusUnhealthy {
+			return false
+		}
+	}
+	return true
+}
+
+// HealthCheckStatus reports the checks to the application's readiness, which makes
+// the module a modular.HealthStatusReporter. The status is unhealthy while a critical
+// check is unhealthy and degraded while another one is, and the details hold the
+// result of every judged check by name.
+func (m *PingerModule) HealthCheckStatus(context.Context) (status, message string, details map[string]any) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status = "healthy"
+	details = make(map[string]any, len(m.checks))
+	var unhealthy []string
+	for _, runner := range m.checks {
+		if runner.result.Status == CheckStatusUnknown {
+			continue
+		}
+		details[runner.result.Name] = resultDetails(runner.result)
+		if runner.result.Status != CheckStatusUnhealthy {
+			continue
+		}
+		unhealthy = append(unhealthy, runner.result.Name)
+		if runner.config.Critical {
+			status = "unhealthy"
+		} else if status == "healthy" {
+			status = "degraded"
+		}
+	}
+	if len(unhealthy) > 0 {
+		message = "unhealthy checks: " + strings.Join(unhealthy, ", ")
+	}
+	return status, message, details
+}
+
+// resultDetails describes a check result in health reports and events.
+func resultDetails(result CheckResult) map[string]interface{} {
+	details := map[string]interface{}{
+		"check":                 result.Name,
+		"type":                  string(result.Type),
+		"status":                string(result.Status),
+		"latency_ms":            result.Latency.Milliseconds(),
+		"consecutive_failures":  result.ConsecutiveFailures,
+		"consecutive_successes": result.ConsecutiveSuccesses,
+	}
+	if result.LastError != "" {
+		details["error"] = result.LastError
+	}
+	return details
+}
+
+// runLoop probes a check immediately and then on every interval until ctx is done.
+func (m *PingerModule) runLoop(ctx context.Context, runner *checkRunner) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(runner.config.Interval)
+	defer ticker.Stop()
+	for {
+		m.runCheck(ctx, runner)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runCheck probes a check once and records the outcome.
+func (m *PingerModule) runCheck(ctx context.Context, runner *checkRunner) {
+	probeCtx, cancel := context.WithTimeout(ctx, runner.config.Timeout)
+	started := time.Now()
+	err := runner.probe(probeCtx)
+	latency := time.Since(started)
+	cancel()
+
+	if ctx.Err() != nil {
+		// Probes cut short by Stop say nothing about the target
+		return
+	}
+	m.record(ctx, runner, started, latency, err)
+}
+
+// record updates a check's result, emitting an event when its status changes.
+func (m *PingerModule) record(ctx context.Context, runner *checkRunner, checked time.Time, latency time.Duration, probeErr error) {
+	m.mu.Lock()
+	result := &runner.result
+	previous := result.Status
+	result.LastChecked = checked
+	result.Latency = latency
+	if probeErr == nil {
+		result.LastSuccess = checked
+		result.LastError = ""
+		result.ConsecutiveFailures = 0
+		result.ConsecutiveSuccesses++
+		if previous != CheckStatusHealthy && (previous == CheckStatusUnknown || result.ConsecutiveSuccesses >= runner.config.SuccessThreshold) {
+			result.Status = CheckStatusHealthy
+		}
+	} else {
+		result.LastError = probeErr.Error()
+		result.ConsecutiveSuccesses = 0
+		result.ConsecutiveFailures++
+		if result.ConsecutiveFailures >= runner.config.FailureThreshold {
+			result.Status = CheckStatusUnhealthy
+		}
+	}
+	snapshot := *result
+	m.mu.Unlock()
+
+	if snapshot.Status == CheckStatusUnknown || snapshot.Status == previous {
+		return
+	}
+
+	eventType := EventTypeCheckHealthy
+	if snapshot.Status == CheckStatusUnhealthy {
+		eventType = EventTypeCheckUnhealthy
+		m.logger.Warn("Synthetic check unhealthy", "check", snapshot.Name, "error", snapshot.LastError)
+	} else {
+		m.logger.Info("Synthetic check healthy", "check", snapshot.Name)
+	}
+	data := resultDetails(snapshot)
+	data["previous_status"] = string(previous)
+	m.emitEvent(ctx, eventType, data)
+}
+
+// RegisterObservers implements the ObservableModule interface.
+func (m *PingerModule) RegisterObservers(subject modular.Subject) error {
+	m.subject = subject
+	return nil
+}
+
+// EmitEvent implements the ObservableModule interface.
+func (m *PingerModule) EmitEvent(ctx context.Context, event cloudevents.Event) error {
+	if m.subject == nil {
+		return ErrNoSubjectForEventEmission
+	}
+	if err := m.subject.NotifyObservers(ctx, event); err != nil {
+		return fmt.Errorf("failed to notify observers: %w", err)
+	}
+	return nil
+}
+
+// emitEvent creates and emits a CloudEvent for the pinger module. It silently skips
+// emission when no subject is available.
+func (m *PingerModule) emitEvent(ctx context.Context, eventType string, data map[string]interface{}) {
+	if m.subject == nil {
+		return
+	}
+
+	event := modular.NewCloudEvent(eventType, "pinger-service", data, nil)
+	if emitErr := m.EmitEvent(ctx, event); emitErr != nil {
+		if errors.Is(emitErr, ErrNoSubjectForEventEmission) {
+			return
+		}
+		if m.logger != nil {
+			m.logger.Warn("Failed to emit pinger event", "eventType", eventType, "error", emitErr)
+		}
+	}
+}
+
+// GetRegisteredEventTypes implements the ObservableModule interface.
+// Returns all event types that this pinger module can emit.
+func (m *PingerModule) GetRegisteredEventTypes() []string {
+	return []string{
+		EventTypeConfigLoaded,
+		EventTypeCheckHealthy,
+		EventTypeCheckUnhealthy,
+		EventTypeModuleStarted,
+		EventTypeModuleStopped,
+	}
+}
@@ -0,0 +1,182 @@
+package pinger
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder records the types of events emitted to an observable application.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (r *eventRecorder) record(ctx context.Context, event cloudevents.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *eventRecorder) ofType(eventType string) []cloudevents.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var matched []cloudevents.Event
+	for _, event := range r.events {
+		if event.Type() == eventType {
+			matched = append(matched, event)
+		}
+	}
+	return matched
+}
+
+// startPinger runs the pinger module in an observable application with the given
+// config.
+func startPinger(t *testing.T, config *PingerConfig) (*PingerModule, *eventRecorder) {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	app := modular.NewObservableApplication(modular.NewStdConfigProvider(nil), logger)
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+
+	recorder := &eventRecorder{}
+	require.NoError(t, app.RegisterObserver(modular.NewFunctionalObserver("pinger-test", recorder.record)))
+
+	module := NewModule().(*PingerModule)
+	app.RegisterModule(module)
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	t.Cleanup(func() { _ = app.Stop() })
+	return module, recorder
+}
+
+func TestPinger_ChecksReportStateChanges(t *testing.T) {
+	var failing atomic.Bool
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	t.Cleanup(backend.Close)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	module, recorder := startPinger(t, &PingerConfig{
+		DefaultInterval: 20 * time.Millisecond,
+		DefaultTimeout:  time.Second,
+		Checks: []CheckConfig{
+			{Name: "api", Type: CheckTypeHTTP, URL: backend.URL, ExpectedStatus: http.StatusOK, ExpectedBody: `"ok"`, FailureThreshold: 2},
+			{Name: "socket", Type: CheckTypeTCP, Address: listener.Addr().String(), Critical: true},
+			{Name: "resolver", Type: CheckTypeDNS, Host: "localhost"},
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		for _, result := range module.Results() {
+			if result.Status != CheckStatusHealthy {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.True(t, module.Healthy())
+	status, message, details := module.HealthCheckStatus(context.Background())
+	assert.Equal(t, "healthy", status)
+	assert.Empty(t, message)
+	assert.Len(t, details, 3)
+
+	failing.Store(true)
+	require.Eventually(t, func() bool {
+		result, err := module.Result("api")
+		return err == nil && result.Status == CheckStatusUnhealthy
+	}, 2*time.Second, 10*time.Millisecond)
+	result, err := module.Result("api")
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, result.ConsecutiveFailures, 2)
+	assert.Contains(t, result.LastError, "unexpected response status")
+	assert.False(t, module.Healthy())
+	status, message, details = module.HealthCheckStatus(context.Background())
+	assert.Equal(t, "degraded", status, "only critical checks make the application unhealthy")
+	assert.Equal(t, "unhealthy checks: api", message)
+	assert.Equal(t, string(CheckStatusUnhealthy), details["api"].(map[string]interface{})["status"])
+
+	require.Eventually(t, func() bool { return len(recorder.ofType(EventTypeCheckUnhealthy)) == 1 }, time.Second, 10*time.Millisecond)
+	var data map[string]interface{}
+	require.NoError(t, recorder.ofType(EventTypeCheckUnhealthy)[0].DataAs(&data))
+	assert.Equal(t, "api", data["check"])
+	assert.Equal(t, string(CheckStatusHealthy), data["previous_status"])
+
+	failing.Store(false)
+	require.Eventually(t, module.Healthy, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, recorder.ofType(EventTypeCheckHealthy), 4, "each check turns healthy once, and api recovers once")
+
+	_, err = module.Result("missing")
+	require.ErrorIs(t, err, ErrUnknownCheck)
+}
+
+func TestPinger_FailureThresholdDelaysUnhealthy(t *testing.T) {
+	module := &PingerModule{}
+	runner := &checkRunner{
+		config: CheckConfig{Name: "flaky", FailureThreshold: 3, SuccessThreshold: 2},
+		result: CheckResult{Name: "flaky", Status: CheckStatusHealthy},
+	}
+	module.checks = []*checkRunner{runner}
+	module.logger = slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	ctx := context.Background()
+
+	for range 2 {
+		module.record(ctx, runner, time.Now(), time.Millisecond, assert.AnError)
+	}
+	assert.Equal(t, CheckStatusHealthy, runner.result.Status)
+	module.record(ctx, runner, time.Now(), time.Millisecond, assert.AnError)
+	assert.Equal(t, CheckStatusUnhealthy, runner.result.Status)
+	runner.config.Critical = true
+	status, _, _ := module.HealthCheckStatus(ctx)
+	assert.Equal(t, "unhealthy", status)
+
+	module.record(ctx, runner, time.Now(), time.Millisecond, nil)
+	assert.Equal(t, CheckStatusUnhealthy, runner.result.Status, "recovery needs SuccessThreshold successes")
+	module.record(ctx, runner, time.Now(), time.Millisecond, nil)
+	assert.Equal(t, CheckStatusHealthy, runner.result.Status)
+}
+
+func TestPingerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []CheckConfig
+		err    error
+	}{
+		{"missing name", []CheckConfig{{Type: CheckTypeDNS, Host: "example.com"}}, ErrInvalidCheck},
+		{"duplicate name", []CheckConfig{{Name: "a", Type: CheckTypeDNS, Host: "a"}, {Name: "a", Type: CheckTypeDNS, Host: "b"}}, ErrDuplicateCheckName},
+		{"unknown type", []CheckConfig{{Name: "a", Type: "icmp"}}, ErrUnknownCheckType},
+		{"http without url", []CheckConfig{{Name: "a", Type: CheckTypeHTTP, URL: "ftp://example.com"}}, ErrInvalidCheck},
+		{"tcp without port", []CheckConfig{{Name: "a", Type: CheckTypeTCP, Address: "example.com"}}, ErrInvalidCheck},
+		{"dns without host", []CheckConfig{{Name: "a", Type: CheckTypeDNS}}, ErrInvalidCheck},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &PingerConfig{Checks: tt.checks}
+			require.ErrorIs(t, config.Validate(), tt.err)
+		})
+	}
+
+	valid := &PingerConfig{Checks: []CheckConfig{{Name: "a", Type: CheckTypeTCP, Address: "example.com:443"}}}
+	require.NoError(t, valid.Validate())
+}
@@ -0,0 +1,40 @@
+package pinger
+
+import "time"
+
+// CheckStatus is the health state of a check.
+type CheckStatus string
+
+const (
+	// CheckStatusUnknown means the check has not yet passed or failed enough times to be judged
+	CheckStatusUnknown CheckStatus = "unknown"
+	// CheckStatusHealthy means the check's target is reachable and as expected
+	CheckStatusHealthy CheckStatus = "healthy"
+	// CheckStatusUnhealthy means the check failed FailureThreshold times in a row
+	CheckStatusUnhealthy CheckStatus = "unhealthy"
+)
+
+// CheckResult is the latest outcome of a check.
+type CheckResult struct {
+	Name                 string        `json:"name"`
+	Type                 CheckType     `json:"type"`
+	Status               CheckStatus   `json:"status"`
+	LastChecked          time.Time     `json:"lastChecked"`
+	LastSuccess          time.Time     `json:"lastSuccess"`
+	LastError            string        `json:"lastError,omitempty"`
+	Latency              time.Duration `json:"latency"`
+	ConsecutiveFailures  int           `json:"consecutiveFailures"`
+	ConsecutiveSuccesses int           `json:"consecutiveSuccesses"`
+}
+
+// PingerService exposes the results of the configured synthetic checks.
+type PingerService interface {
+	// Results returns the latest result of every check, in configuration order
+	Results() []CheckResult
+
+	// Result returns the latest result of the named check
+	Result(name string) (CheckResult, error)
+
+	// Healthy reports whether no check is unhealthy
+	Healthy() bool
+}
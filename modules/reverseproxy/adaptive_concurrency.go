@@ -0,0 +1,330 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Adaptive concurrency defaults
+const (
+	defaultConcurrencyInitialLimit  = 20
+	defaultConcurrencyMinLimit      = 1
+	defaultConcurrencyMaxLimit      = 1000
+	defaultConcurrencyTolerance     = 2.0
+	defaultConcurrencyBackoffRatio  = 0.9
+	defaultConcurrencyBaselineReset = 30 * time.Second
+)
+
+// AdaptiveConcurrencyConfig limits the requests in flight to a backend to a limit
+// that adapts to the backend's latency, shedding the excess with 503s before the
+// backend is overwhelmed. The limit grows by one each time a limit's worth of
+// requests succeed within the latency tolerance (additive increase), and shrinks by
+// BackoffRatio when a request is slower than Tolerance times the baseline latency,
+// fails with a 5xx or times out (multiplicative decrease).
+//
+//	backend_configs:
+//	  orders:
+//	    adaptive_concurrency:
+//	      enabled: true
+//	      initial_limit: 50
+//	      max_limit: 200
+//	      tolerance: 1.5
+type AdaptiveConcurrencyConfig struct {
+	// Enabled turns on the adaptive concurrency limit for the backend
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"ENABLED"`
+
+	// InitialLimit is the limit before any latency is observed. Defaults to 20.
+	InitialLimit int `json:"initial_limit" yaml:"initial_limit" toml:"initial_limit" env:"INITIAL_LIMIT"`
+
+	// MinLimit and MaxLimit bound the limit. Default to 1 and 1000.
+	MinLimit int `json:"min_limit" yaml:"min_limit" toml:"min_limit" env:"MIN_LIMIT"`
+	MaxLimit int `json:"max_limit" yaml:"max_limit" toml:"max_limit" env:"MAX_LIMIT"`
+
+	// Tolerance is how many times the baseline latency a request may take before the
+	// backend is considered overloaded. Defaults to 2.
+	Tolerance float64 `json:"tolerance" yaml:"tolerance" toml:"tolerance" env:"TOLERANCE"`
+
+	// BackoffRatio is the factor the limit is multiplied by when the backend is
+	// overloaded, between 0 and 1. Defaults to 0.9.
+	BackoffRatio float64 `json:"backoff_ratio" yaml:"backoff_ratio" toml:"backoff_ratio" env:"BACKOFF_RATIO"`
+
+	// BaselineWindow is how often the baseline latency, the lowest latency observed,
+	// is re-measured, so it follows lasting changes in the backend. Defaults to 30s.
+	BaselineWindow time.Duration `json:"baseline_window" yaml:"baseline_window" toml:"baseline_window" env:"BASELINE_WINDOW"`
+}
+
+// validate checks the limits and ratios.
+func (c AdaptiveConcurrencyConfig) validate() error {
+	if c.InitialLimit < 0 || c.MinLimit < 0 || c.MaxLimit < 0 {
+		return fmt.Errorf("%w: limits must not be negative", ErrInvalidAdaptiveConcurrencyConfig)
+	}
+	c = c.withDefaults()
+	if c.MinLimit > c.MaxLimit {
+		return fmt.Errorf("%w: min_limit %d exceeds max_limit %d", ErrInvalidAdaptiveConcurrencyConfig, c.MinLimit, c.MaxLimit)
+	}
+	if c.Tolerance < 1 {
+		return fmt.Errorf("%w: tolerance %g must be at least 1", ErrInvalidAdaptiveConcurrencyConfig, c.Tolerance)
+	}
+	if c.BackoffRatio <= 0 || c.BackoffRatio >= 1 {
+		return fmt.Errorf("%w: backoff_ratio %g must be between 0 and 1", ErrInvalidAdaptiveConcurrencyConfig, c.BackoffRatio)
+	}
+	if c.BaselineWindow < 0 {
+		return fmt.Errorf("%w: baseline_window must not be negative", ErrInvalidAdaptiveConcurrencyConfig)
+	}
+	return nil
+}
+
+// withDefaults fills in the unset fields.
+func (c AdaptiveConcurrencyConfig) withDefaults() AdaptiveConcurrencyConfig {
+	if c.MinLimit == 0 {
+		c.MinLimit = defaultConcurrencyMinLimit
+	}
+	if c.MaxLimit == 0 {
+		c.MaxLimit = max(defaultConcurrencyMaxLimit, c.MinLimit)
+	}
+	if c.InitialLimit == 0 {
+		c.InitialLimit = defaultConcurrencyInitialLimit
+	}
+	c.InitialLimit = min(max(c.InitialLimit, c.MinLimit), c.MaxLimit)
+	if c.Tolerance == 0 {
+		c.Tolerance = defaultConcurrencyTolerance
+	}
+	if c.BackoffRatio == 0 {
+		c.BackoffRatio = defaultConcurrencyBackoffRatio
+	}
+	if c.BaselineWindow == 0 {
+		c.BaselineWindow = defaultConcurrencyBaselineReset
+	}
+	return c
+}
+
+// BackendConcurrencyStatus reports a backend's adaptive concurrency limit.
+type BackendConcurrencyStatus struct {
+	Backend  string `json:"backend"`
+	Limit    int    `json:"limit"`
+	InFlight int    `json:"in_flight"`
+	// BaselineLatencyMs is the lowest latency observed, the latency of the unloaded backend
+	BaselineLatencyMs float64 `json:"baseline_latency_ms"`
+	Shed              uint64  `json:"shed"`
+}
+
+// concurrencyLimit is the adaptive limit of one backend.
+type concurrencyLimit struct {
+	config AdaptiveConcurrencyConfig
+
+	mu           sync.Mutex
+	limit        float64
+	inFlight     int
+	baseline     time.Duration // lowest latency of the previous and current windows
+	windowMin    time.Duration // lowest latency of the current window
+	windowStart  time.Time
+	lastDecrease time.Time
+	shed         uint64
+}
+
+func newConcurrencyLimit(config AdaptiveConcurrencyConfig, now time.Time) *concurrencyLimit {
+	config = config.withDefaults()
+	return &concurrencyLimit{config: config, limit: float64(config.InitialLimit), windowStart: now}
+}
+
+// tryAcquire admits a request when fewer than limit requests are in flight.
+func (c *concurrencyLimit) tryAcquire() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.inFlight >= int(c.limit) {
+		c.shed++
+		return false
+	}
+	c.inFlight++
+	return true
+}
+
+// release ends a request that took latency, adapting the limit. It returns the old
+// and new limit.
+func (c *concurrencyLimit) release(now time.Time, latency time.Duration, dropped bool) (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	inFlight := c.inFlight
+	c.inFlight--
+	old := int(c.limit)
+
+	if !dropped && latency > 0 {
+		if now.Sub(c.windowStart) >= c.config.BaselineWindow {
+			// The previous window's minimum stays until the new window has samples
+			c.baseline = c.windowMin
+			c.windowMin = 0
+			c.windowStart = now
+		}
+		if c.windowMin == 0 || latency < c.windowMin {
+			c.windowMin = latency
+		}
+		if c.baseline == 0 || c.windowMin < c.baseline {
+			c.baseline = c.windowMin
+		}
+	}
+
+	overloaded := dropped || float64(latency) > float64(c.baseline)*c.config.Tolerance
+	switch {
+	case overloaded:
+		// Requests in flight together see the same overload; back off once for them
+		if now.Sub(c.lastDecrease) >= latency {
+			c.limit = math.Max(float64(c.config.MinLimit), c.limit*c.config.BackoffRatio)
+			c.lastDecrease = now
+		}
+	case inFlight*2 >= int(c.limit):
+		// Only grow a limit that is being used
+		c.limit = math.Min(float64(c.config.MaxLimit), c.limit+1/c.limit)
+	}
+	return old, int(c.limit)
+}
+
+func (c *concurrencyLimit) status(backend string) BackendConcurrencyStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return BackendConcurrencyStatus{
+		Backend:           backend,
+		Limit:             int(c.limit),
+		InFlight:          c.inFlight,
+		BaselineLatencyMs: float64(c.baseline) / float64(time.Millisecond),
+		Shed:              c.shed,
+	}
+}
+
+// adaptiveConcurrency holds the adaptive limits of the backends that enable one,
+// created on their first request. The zero value is ready to use.
+type adaptiveConcurrency struct {
+	mu     sync.Mutex
+	limits map[string]*concurrencyLimit
+	now    func() time.Time
+}
+
+func (a *adaptiveConcurrency) clock() time.Time {
+	if a.now != nil {
+		return a.now()
+	}
+	return time.Now()
+}
+
+// limit returns the limit of backend, creating it with config on first use.
+func (a *adaptiveConcurrency) limit(backend string, config AdaptiveConcurrencyConfig) *concurrencyLimit {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if l, ok := a.limits[backend]; ok {
+		return l
+	}
+	if a.limits == nil {
+		a.limits = make(map[string]*concurrencyLimit)
+	}
+	l := newConcurrencyLimit(config, a.clock())
+	a.limits[backend] = l
+	return l
+}
+
+// remove forgets the limit of a removed backend.
+func (a *adaptiveConcurrency) remove(backend string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.limits, backend)
+}
+
+// statuses returns the limit of every backend seen, sorted by backend ID.
+func (a *adaptiveConcurrency) statuses() []BackendConcurrencyStatus {
+	a.mu.Lock()
+	limits := make(map[string]*concurrencyLimit, len(a.limits))
+	for backend, l := range a.limits {
+		limits[backend] = l
+	}
+	a.mu.Unlock()
+
+	statuses := make([]BackendConcurrencyStatus, 0, len(limits))
+	for _, backend := range sortedKeys(limits) {
+		statuses = append(statuses, limits[backend].status(backend))
+	}
+	return statuses
+}
+
+// writePrometheus writes each backend's limit, requests in flight and shed requests
+// to p.
+func (a *adaptiveConcurrency) writePrometheus(p *prometheusWriter) {
+	statuses := a.statuses()
+	if len(statuses) == 0 {
+		return
+	}
+	metrics := []struct {
+		name, kind string
+		get        func(BackendConcurrencyStatus) float64
+	}{
+		{"reverseproxy_backend_concurrency_limit", "gauge", func(s BackendConcurrencyStatus) float64 { return float64(s.Limit) }},
+		{"reverseproxy_backend_concurrency_in_flight", "gauge", func(s BackendConcurrencyStatus) float64 { return float64(s.InFlight) }},
+		{"reverseproxy_backend_concurrency_shed_total", "counter", func(s BackendConcurrencyStatus) float64 { return float64(s.Shed) }},
+	}
+	for _, metric := range metrics {
+		p.family(metric.name, metric.kind)
+		for _, s := range statuses {
+			p.sample(metric.name, metric.get(s), "backend", s.Backend)
+		}
+	}
+}
+
+// ConcurrencyStatus returns the adaptive concurrency limit of every backend that
+// enables one and has served a request.
+func (m *ReverseProxyModule) ConcurrencyStatus() []BackendConcurrencyStatus {
+	return m.concurrency.statuses()
+}
+
+// acquireConcurrency admits a request to backend under its adaptive concurrency
+// limit, answering it with a 503 when the backend is at its limit. Admitted requests
+// are served through the returned writer, which records their status, and must call
+// the returned function once they end.
+func (m *ReverseProxyModule) acquireConcurrency(w http.ResponseWriter, r *http.Request, backend string) (http.ResponseWriter, func(), bool) {
+	config, ok := m.config.BackendConfigs[backend]
+	if !ok || !config.AdaptiveConcurrency.Enabled {
+		return w, func() {}, true
+	}
+	limit := m.concurrency.limit(backend, config.AdaptiveConcurrency)
+	if !limit.tryAcquire() {
+		m.shedConcurrency(w, r, backend, limit)
+		return w, nil, false
+	}
+
+	start := m.concurrency.clock()
+	rec := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+	return rec, func() {
+		now := m.concurrency.clock()
+		dropped := rec.status >= http.StatusInternalServerError || errors.Is(r.Context().Err(), context.DeadlineExceeded)
+		if old, current := limit.release(now, now.Sub(start), dropped); old != current {
+			m.emitEvent(r.Context(), EventTypeConcurrencyLimitChanged, map[string]interface{}{
+				"backend":   backend,
+				"old_limit": old,
+				"limit":     current,
+				"dropped":   dropped,
+			})
+		}
+	}, true
+}
+
+// shedConcurrency answers a request to a backend at its concurrency limit.
+func (m *ReverseProxyModule) shedConcurrency(w http.ResponseWriter, r *http.Request, backend string, limit *concurrencyLimit) {
+	status := limit.status(backend)
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Debug("Backend at its concurrency limit, shedding request",
+			"backend", backend, "limit", status.Limit, "path", sanitizeForLogging(r.URL.Path))
+	}
+	m.emitEvent(r.Context(), EventTypeConcurrencyShed, map[string]interface{}{
+		"backend": backend,
+		"limit":   status.Limit,
+		"method":  r.Method,
+		"path":    r.URL.Path,
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Retry-After", "1")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	if _, err := w.Write([]byte(`{"error":"Backend overloaded","code":"CONCURRENCY_LIMITED"}`)); err != nil && m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Error("Failed to write concurrency limit response", "error", err)
+	}
+}
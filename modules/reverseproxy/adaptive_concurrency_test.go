@@ -0,0 +1,180 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrencyLimit_AdditiveIncrease(t *testing.T) {
+	now := time.Unix(0, 0)
+	limit := newConcurrencyLimit(AdaptiveConcurrencyConfig{Enabled: true, InitialLimit: 4, MaxLimit: 5}, now)
+
+	// A limit's worth of fast requests while the limit is in use grows it by one
+	for range 4 {
+		for range 4 {
+			require.True(t, limit.tryAcquire())
+		}
+		for range 4 {
+			now = now.Add(time.Millisecond)
+			limit.release(now, 10*time.Millisecond, false)
+		}
+	}
+	assert.Equal(t, 5, limit.status("api").Limit)
+
+	// An idle backend's limit doesn't grow
+	for range 50 {
+		require.True(t, limit.tryAcquire())
+		limit.release(now, 10*time.Millisecond, false)
+	}
+	assert.Equal(t, 5, limit.status("api").Limit, "the limit is capped at max_limit")
+	assert.InDelta(t, 10, limit.status("api").BaselineLatencyMs, 0)
+}
+
+func TestConcurrencyLimit_MultiplicativeDecrease(t *testing.T) {
+	now := time.Unix(0, 0)
+	limit := newConcurrencyLimit(AdaptiveConcurrencyConfig{Enabled: true, InitialLimit: 100, MinLimit: 10, BackoffRatio: 0.5}, now)
+	require.True(t, limit.tryAcquire())
+	limit.release(now, 10*time.Millisecond, false)
+
+	// Latency beyond twice the baseline backs off
+	require.True(t, limit.tryAcquire())
+	now = now.Add(time.Second)
+	old, current := limit.release(now, 25*time.Millisecond, false)
+	assert.Equal(t, 100, old)
+	assert.Equal(t, 50, current)
+
+	// Requests overloaded together back off once
+	require.True(t, limit.tryAcquire())
+	_, current = limit.release(now.Add(time.Millisecond), 25*time.Millisecond, false)
+	assert.Equal(t, 50, current)
+
+	// Failures back off regardless of latency, down to min_limit
+	for range 5 {
+		now = now.Add(time.Second)
+		require.True(t, limit.tryAcquire())
+		limit.release(now, time.Millisecond, true)
+	}
+	assert.Equal(t, 10, limit.status("api").Limit)
+	assert.InDelta(t, 10, limit.status("api").BaselineLatencyMs, 0, "failures don't lower the baseline")
+}
+
+func TestConcurrencyLimit_BaselineFollowsBackend(t *testing.T) {
+	now := time.Unix(0, 0)
+	limit := newConcurrencyLimit(AdaptiveConcurrencyConfig{Enabled: true, BaselineWindow: time.Minute}, now)
+	require.True(t, limit.tryAcquire())
+	limit.release(now, 5*time.Millisecond, false)
+
+	// After a window the baseline is re-measured from the new latencies
+	for _, offset := range []time.Duration{61 * time.Second, 122 * time.Second} {
+		require.True(t, limit.tryAcquire())
+		limit.release(now.Add(offset), 20*time.Millisecond, false)
+	}
+	assert.InDelta(t, 20, limit.status("api").BaselineLatencyMs, 0)
+}
+
+func TestAdaptiveConcurrencyConfig_Validate(t *testing.T) {
+	require.NoError(t, AdaptiveConcurrencyConfig{}.validate())
+	assert.ErrorIs(t, AdaptiveConcurrencyConfig{MinLimit: 10, MaxLimit: 5}.validate(), ErrInvalidAdaptiveConcurrencyConfig)
+	assert.ErrorIs(t, AdaptiveConcurrencyConfig{Tolerance: 0.5}.validate(), ErrInvalidAdaptiveConcurrencyConfig)
+	assert.ErrorIs(t, AdaptiveConcurrencyConfig{BackoffRatio: 1.5}.validate(), ErrInvalidAdaptiveConcurrencyConfig)
+	assert.ErrorIs(t, AdaptiveConcurrencyConfig{InitialLimit: -1}.validate(), ErrInvalidAdaptiveConcurrencyConfig)
+
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://api.internal"},
+		BackendConfigs:  map[string]BackendServiceConfig{"api": {AdaptiveConcurrency: AdaptiveConcurrencyConfig{Enabled: true, Tolerance: 0.5}}},
+	}
+	assert.ErrorIs(t, m.validateConfig(), ErrInvalidAdaptiveConcurrencyConfig)
+}
+
+func TestAdaptiveConcurrency_ShedsRequestsBeyondLimit(t *testing.T) {
+	entered := make(chan struct{}, 2)
+	unblock := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		<-unblock
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		BackendConfigs: map[string]BackendServiceConfig{"api": {AdaptiveConcurrency: AdaptiveConcurrencyConfig{
+			Enabled:      true,
+			InitialLimit: 2,
+			MaxLimit:     2,
+		}}},
+		RequestTimeout: 5 * time.Second,
+	})
+	handler := m.createBackendProxyHandler("api")
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			handler(w, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+			assert.Equal(t, http.StatusOK, w.Code)
+		}()
+	}
+	<-entered
+	<-entered
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+	assert.Contains(t, w.Body.String(), "CONCURRENCY_LIMITED")
+
+	close(unblock)
+	wg.Wait()
+
+	status := m.ConcurrencyStatus()
+	require.Len(t, status, 1)
+	assert.Equal(t, BackendConcurrencyStatus{Backend: "api", Limit: 2, BaselineLatencyMs: status[0].BaselineLatencyMs, Shed: 1}, status[0])
+	assert.Eventually(t, func() bool { return len(subject.eventsOfType(EventTypeConcurrencyShed)) == 1 }, time.Second, 5*time.Millisecond)
+
+	var metrics bytes.Buffer
+	p := newPrometheusWriter(&metrics)
+	m.concurrency.writePrometheus(p)
+	require.NoError(t, p.flush())
+	assert.Contains(t, metrics.String(), `reverseproxy_backend_concurrency_limit{backend="api"} 2`)
+	assert.Contains(t, metrics.String(), `reverseproxy_backend_concurrency_shed_total{backend="api"} 1`)
+}
+
+func TestAdaptiveConcurrency_BackendFailuresLowerLimit(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		BackendConfigs: map[string]BackendServiceConfig{"api": {AdaptiveConcurrency: AdaptiveConcurrencyConfig{
+			Enabled:      true,
+			InitialLimit: 10,
+			BackoffRatio: 0.5,
+		}}},
+		RequestTimeout: 5 * time.Second,
+	})
+
+	w := httptest.NewRecorder()
+	m.createBackendProxyHandler("api")(w, httptest.NewRequest(http.MethodGet, "/api/orders", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, 5, m.ConcurrencyStatus()[0].Limit)
+
+	assert.Eventually(t, func() bool { return len(subject.eventsOfType(EventTypeConcurrencyLimitChanged)) == 1 }, time.Second, 5*time.Millisecond)
+	var data map[string]interface{}
+	require.NoError(t, subject.eventsOfType(EventTypeConcurrencyLimitChanged)[0].DataAs(&data))
+	assert.InDelta(t, 10, data["old_limit"], 0)
+	assert.InDelta(t, 5, data["limit"], 0)
+	assert.Equal(t, true, data["dropped"])
+}
@@ -0,0 +1,127 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// The methods below let the admindashboard module show and act on the proxy. They
+// only use standard library and modular types, so this module doesn't depend on it.
+
+// DashboardMaintenanceName is the name of the maintenance entry switched by
+// SetMaintenance.
+const DashboardMaintenanceName = "admin-dashboard"
+
+// DashboardHealth reports the health of each backend the health checker checks, nil
+// for healthy ones.
+func (m *ReverseProxyModule) DashboardHealth(context.Context) map[string]error {
+	statuses := m.GetHealthStatus()
+	health := make(map[string]error, len(statuses))
+	for backendID, status := range statuses {
+		if status.Healthy {
+			health[backendID] = nil
+			continue
+		}
+		if status.LastError != "" {
+			health[backendID] = fmt.Errorf("%w: %s", ErrBackendUnhealthy, status.LastError)
+			continue
+		}
+		health[backendID] = ErrBackendUnhealthy
+	}
+	return health
+}
+
+// DashboardBackends reports each configured backend with its health, circuit breaker
+// state, whether it is draining and its requests in flight.
+func (m *ReverseProxyModule) DashboardBackends(context.Context) []map[string]any {
+	if m.config == nil {
+		return nil
+	}
+	statuses := m.GetHealthStatus()
+	backends := make([]map[string]any, 0, len(m.config.BackendServices))
+	for _, backendID := range slices.Sorted(maps.Keys(m.config.BackendServices)) {
+		report := map[string]any{
+			"name":      backendID,
+			"url":       m.config.BackendServices[backendID],
+			"healthy":   true,
+			"draining":  m.drains.isDraining(backendID),
+			"in_flight": m.drains.inFlightCount(backendID),
+		}
+		if status, ok := statuses[backendID]; ok {
+			report["healthy"] = status.Healthy
+		}
+		if cb, ok := m.circuitBreakers[backendID]; ok {
+			report["circuit_state"] = cb.GetState().String()
+		}
+		backends = append(backends, report)
+	}
+	return backends
+}
+
+// DrainBackend stops routing new requests to a backend and waits for those in
+// flight like RemoveBackend, but keeps the backend configured. Its requests are
+// rejected until ResumeBackend is called.
+func (m *ReverseProxyModule) DrainBackend(ctx context.Context, backendID string) error {
+	if m.config == nil || m.config.BackendServices == nil {
+		return ErrNoBackendsConfigured
+	}
+	if _, exists := m.config.BackendServices[backendID]; !exists {
+		return fmt.Errorf("%w: %s", ErrBackendNotConfigured, backendID)
+	}
+	m.drainBackend(ctx, backendID)
+	return nil
+}
+
+// ResumeBackend routes requests to a backend drained with DrainBackend again.
+func (m *ReverseProxyModule) ResumeBackend(backendID string) {
+	m.drains.finishDrain(backendID)
+}
+
+// SetMaintenance puts every request into maintenance under DashboardMaintenanceName,
+// or ends that entry.
+func (m *ReverseProxyModule) SetMaintenance(_ context.Context, enabled bool) error {
+	if enabled {
+		m.EnableMaintenance(DashboardMaintenanceName, MaintenanceScope{}, 0)
+		return nil
+	}
+	m.DisableMaintenance(DashboardMaintenanceName)
+	return nil
+}
+
+// InMaintenance reports whether the entry switched by SetMaintenance is active.
+func (m *ReverseProxyModule) InMaintenance() bool {
+	return slices.ContainsFunc(m.MaintenanceStatus(), func(status MaintenanceStatus) bool {
+		return status.Name == DashboardMaintenanceName
+	})
+}
+
+// DashboardFeatureFlags reports the value of every configured feature flag, for
+// tenantID when it is not empty.
+func (f *FileBasedFeatureFlagEvaluator) DashboardFeatureFlags(ctx context.Context, tenantID modular.TenantID) map[string]bool {
+	names := make(map[string]bool)
+	collect := func(config any) {
+		if cfg, ok := config.(*ReverseProxyConfig); ok && cfg != nil {
+			for name := range cfg.FeatureFlags.Flags {
+				names[name] = true
+			}
+		}
+	}
+	if f.defaultConfigProvider != nil {
+		collect(f.defaultConfigProvider.GetConfig())
+	}
+	if tenantID != "" && f.tenantAwareConfig != nil {
+		collect(f.tenantAwareConfig.GetConfigWithContext(modular.NewTenantContext(ctx, tenantID)))
+	}
+
+	flags := make(map[string]bool, len(names))
+	for name := range names {
+		if value, err := f.EvaluateFlag(ctx, name, tenantID, nil); err == nil {
+			flags[name] = value
+		}
+	}
+	return flags
+}
@@ -0,0 +1,78 @@
+package reverseproxy
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The admindashboard module's interfaces, which the module and its feature flag
+// evaluator implement without importing it.
+var (
+	_ interface {
+		DashboardHealth(ctx context.Context) map[string]error
+	} = (*ReverseProxyModule)(nil)
+	_ interface {
+		DashboardBackends(ctx context.Context) []map[string]any
+	} = (*ReverseProxyModule)(nil)
+	_ interface {
+		DrainBackend(ctx context.Context, backend string) error
+	} = (*ReverseProxyModule)(nil)
+	_ interface {
+		SetMaintenance(ctx context.Context, enabled bool) error
+		InMaintenance() bool
+	} = (*ReverseProxyModule)(nil)
+	_ interface {
+		DashboardFeatureFlags(ctx context.Context, tenantID modular.TenantID) map[string]bool
+	} = (*FileBasedFeatureFlagEvaluator)(nil)
+)
+
+func TestAdminDashboard_BackendsAndDrain(t *testing.T) {
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{})
+	m.circuitBreakers["billing"] = NewCircuitBreaker("billing", nil)
+
+	ctx := context.Background()
+	require.NoError(t, m.DrainBackend(ctx, "billing"))
+	require.ErrorIs(t, m.DrainBackend(ctx, "missing"), ErrBackendNotConfigured)
+
+	backends := m.DashboardBackends(ctx)
+	require.Len(t, backends, 2)
+	assert.Equal(t, "api", backends[0]["name"])
+	assert.Equal(t, false, backends[0]["draining"])
+	assert.Equal(t, "billing", backends[1]["name"])
+	assert.Equal(t, true, backends[1]["draining"])
+	assert.Equal(t, "closed", backends[1]["circuit_state"])
+	assert.Equal(t, http.StatusServiceUnavailable, serveMaintenanceTest(m, "billing", "/billing", "").Code)
+
+	m.ResumeBackend("billing")
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "billing", "/billing", "").Code)
+}
+
+func TestAdminDashboard_Maintenance(t *testing.T) {
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{})
+	ctx := context.Background()
+
+	require.NoError(t, m.SetMaintenance(ctx, true))
+	assert.True(t, m.InMaintenance())
+	assert.Equal(t, http.StatusServiceUnavailable, serveMaintenanceTest(m, "api", "/api", "").Code)
+
+	require.NoError(t, m.SetMaintenance(ctx, false))
+	assert.False(t, m.InMaintenance())
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "api", "/api", "").Code)
+}
+
+func TestAdminDashboard_FeatureFlags(t *testing.T) {
+	app := NewMockTenantApplication()
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		FeatureFlags: FeatureFlagsConfig{Enabled: true, Flags: map[string]bool{"beta": true, "legacy": false}},
+	}))
+	evaluator, err := NewFileBasedFeatureFlagEvaluator(context.Background(), app, slog.Default())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"beta": true, "legacy": false}, evaluator.DashboardFeatureFlags(context.Background(), ""))
+}
@@ -0,0 +1,395 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// Retry defaults for RetryConfig.
+const (
+	defaultRetryBackoff     = 50 * time.Millisecond
+	defaultRetryMaxBackoff  = time.Second
+	defaultRetryMaxBodySize = 1 << 20
+)
+
+// defaultRetryableStatusCodes are the transient backend statuses retried by default.
+var defaultRetryableStatusCodes = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout}
+
+// IdempotencyKeyHeader marks a non-idempotent request as safe to retry.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryConfig retries backend requests that fail with a connection error or a
+// retryable status, such as a transient 502 or 503, before the failure reaches the
+// client. It is set per route in route_configs or per backend in backend_configs;
+// a route's config takes precedence.
+//
+//	route_configs:
+//	  "/api/orders/*":
+//	    retry:
+//	      max_retries: 2
+//	      retryable_status_codes: [502, 503]
+//	      backoff: 100ms
+//	      next_backend: true
+type RetryConfig struct {
+	// MaxRetries is how many times a failed request is retried. Zero disables retries.
+	MaxRetries int `json:"max_retries" yaml:"max_retries" toml:"max_retries"`
+
+	// RetryableStatusCodes are the backend response statuses that are retried.
+	// Defaults to 502, 503 and 504.
+	RetryableStatusCodes []int `json:"retryable_status_codes" yaml:"retryable_status_codes" toml:"retryable_status_codes"`
+
+	// Backoff is the delay before the first retry, doubled for each further retry
+	// with 10% jitter. Defaults to 50ms.
+	Backoff time.Duration `json:"backoff" yaml:"backoff" toml:"backoff"`
+
+	// MaxBackoff caps the delay between retries. Defaults to 1s.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" toml:"max_backoff"`
+
+	// NextBackend retries requests routed to a backend group on the group's next
+	// backend rather than the one that failed, passing over backends that are
+	// unhealthy, circuit-open or draining. The retry goes through the next backend's
+	// own proxy, with its path rewriting, transport and circuit breaker.
+	NextBackend bool `json:"next_backend" yaml:"next_backend" toml:"next_backend"`
+
+	// RetryNonIdempotent also retries POST, PATCH and other non-idempotent requests.
+	// Otherwise they are only retried when they carry an Idempotency-Key header.
+	RetryNonIdempotent bool `json:"retry_non_idempotent" yaml:"retry_non_idempotent" toml:"retry_non_idempotent"`
+
+	// MaxBodySize is the largest request body buffered so it can be sent again;
+	// requests with larger bodies are not retried. Defaults to 1MiB.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size" toml:"max_body_size"`
+}
+
+// validate checks the retry configuration.
+func (c *RetryConfig) validate() error {
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("%w: max_retries must not be negative", ErrInvalidRetryConfig)
+	}
+	for _, code := range c.RetryableStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("%w: invalid retryable status code %d", ErrInvalidRetryConfig, code)
+		}
+	}
+	if c.Backoff < 0 || c.MaxBackoff < 0 {
+		return fmt.Errorf("%w: backoff and max_backoff must not be negative", ErrInvalidRetryConfig)
+	}
+	if c.MaxBodySize < 0 {
+		return fmt.Errorf("%w: max_body_size must not be negative", ErrInvalidRetryConfig)
+	}
+	return nil
+}
+
+// enabled reports whether c retries requests.
+func (c *RetryConfig) enabled() bool {
+	return c != nil && c.MaxRetries > 0
+}
+
+// policy returns the RetryPolicy applying c, with defaults for unset fields.
+func (c *RetryConfig) policy() RetryPolicy {
+	backoff, maxBackoff, codes := c.Backoff, c.MaxBackoff, c.RetryableStatusCodes
+	if backoff == 0 {
+		backoff = defaultRetryBackoff
+	}
+	if maxBackoff == 0 {
+		maxBackoff = max(defaultRetryMaxBackoff, backoff)
+	}
+	if len(codes) == 0 {
+		codes = defaultRetryableStatusCodes
+	}
+	return DefaultRetryPolicy().
+		WithMaxRetries(c.MaxRetries).
+		WithBaseDelay(backoff).
+		WithMaxDelay(maxBackoff).
+		WithRetryableStatusCodes(codes...)
+}
+
+// allows reports whether r may be retried: idempotent methods always are, others
+// only when configured or when the request carries an idempotency key.
+func (c *RetryConfig) allows(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return c.RetryNonIdempotent || r.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// retryKey is the context key of the retry state of a backend request.
+type retryKey struct{}
+
+// retryGroupKey is the context key of the backend group a request was routed to.
+type retryGroupKey struct{}
+
+// retryState is the retry policy selected for a backend request.
+type retryState struct {
+	config  *RetryConfig
+	policy  RetryPolicy
+	backend string
+	group   []string
+
+	// inbound is the client request, directed anew by the proxy of each next backend
+	inbound *http.Request
+	tenant  modular.TenantID
+}
+
+// withRetryGroup records the backend group a request was routed to, so retries can
+// move on to the group's next backend.
+func withRetryGroup(r *http.Request, group []string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), retryGroupKey{}, group))
+}
+
+// retryConfigFor returns the retry config of the most specific route matching path
+// that sets one, else the backend's. Of equally long patterns, the lexically first wins.
+func (m *ReverseProxyModule) retryConfigFor(path, backend string) *RetryConfig {
+	route, ok := m.routeConfigFor(path, m.config.RouteConfigs, func(route RouteConfig) bool {
+		return route.Retry != nil
+	})
+	if ok {
+		return route.Retry
+	}
+	return m.config.BackendConfigs[backend].Retry
+}
+
+// selectRetry returns the request carrying its retry policy when one applies to the
+// request to backend, for the backend transport to retry it.
+func (m *ReverseProxyModule) selectRetry(r *http.Request, backend string) *http.Request {
+	if !m.retriesEnabled {
+		return r
+	}
+	config := m.retryConfigFor(r.URL.Path, backend)
+	if !config.enabled() || !config.allows(r) {
+		return r
+	}
+	state := &retryState{config: config, policy: config.policy(), backend: backend}
+	if config.NextBackend {
+		state.group, _ = r.Context().Value(retryGroupKey{}).([]string)
+		state.inbound = r
+		state.tenant = modular.TenantID(r.Header.Get(m.config.TenantIDHeader))
+	}
+	return r.WithContext(context.WithValue(r.Context(), retryKey{}, state))
+}
+
+// retryTransport wraps base so it retries requests carrying a retry policy, or
+// returns base when no route or backend configures retries.
+func (m *ReverseProxyModule) retryTransport(base http.RoundTripper) http.RoundTripper {
+	if !m.retriesEnabled {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &retryRoundTripper{base: base, module: m}
+}
+
+// retryProxy returns a copy of proxy whose transport retries requests, or proxy
+// itself when no route or backend configures retries.
+func (m *ReverseProxyModule) retryProxy(proxy *httputil.ReverseProxy) *httputil.ReverseProxy {
+	if !m.retriesEnabled {
+		return proxy
+	}
+	return &httputil.ReverseProxy{
+		Director:       proxy.Director,
+		Transport:      m.retryTransport(proxy.Transport),
+		FlushInterval:  proxy.FlushInterval,
+		ErrorLog:       proxy.ErrorLog,
+		BufferPool:     proxy.BufferPool,
+		ModifyResponse: proxy.ModifyResponse,
+		ErrorHandler:   proxy.ErrorHandler,
+	}
+}
+
+// retryRoundTripper retries requests according to the retry state in their context.
+type retryRoundTripper struct {
+	base   http.RoundTripper
+	module *ReverseProxyModule
+}
+
+func (t *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	state, ok := req.Context().Value(retryKey{}).(*retryState)
+	if !ok {
+		return t.base.RoundTrip(req) //nolint:wrapcheck // transport errors are passed through unchanged
+	}
+	body, ok := replayableBody(req, state.config.MaxBodySize)
+	if !ok {
+		return t.base.RoundTrip(req) //nolint:wrapcheck // transport errors are passed through unchanged
+	}
+
+	ctx := req.Context()
+	target, attemptReq := &retryTarget{backend: state.backend, transport: t.base}, req
+	for attempt := 0; ; attempt++ {
+		attemptReq.Body = body()
+		resp, err := target.roundTrip(attemptReq)
+		retryable := err != nil || state.policy.ShouldRetry(resp.StatusCode)
+		if !retryable || attempt >= state.policy.MaxRetries || ctx.Err() != nil {
+			return resp, err //nolint:wrapcheck // transport errors are passed through unchanged
+		}
+
+		data := map[string]interface{}{
+			"backend": target.backend,
+			"method":  req.Method,
+			"path":    req.URL.Path,
+			"attempt": attempt + 1,
+		}
+		if err != nil {
+			data["error"] = err.Error()
+		} else {
+			data["status"] = resp.StatusCode
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, defaultRetryMaxBodySize))
+			_ = resp.Body.Close()
+		}
+
+		attemptReq = req.Clone(ctx)
+		if next := t.nextBackend(state, target.backend); next != nil {
+			// The next backend's proxy directs the client request to its own URL and path
+			directed := state.inbound.Clone(ctx)
+			next.director(directed)
+			attemptReq.URL, attemptReq.Host = directed.URL, directed.Host
+			target = next
+		} else if target.director != nil {
+			directed := state.inbound.Clone(ctx)
+			target.director(directed)
+			attemptReq.URL, attemptReq.Host = directed.URL, directed.Host
+		}
+		data["retry_backend"] = target.backend
+		t.module.emitEvent(ctx, EventTypeRequestRetried, data)
+
+		timer := time.NewTimer(state.policy.CalculateBackoff(attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("retry of request to backend %s cancelled: %w", target.backend, ctx.Err())
+		}
+	}
+}
+
+// retryTarget is a backend a request is retried on, with the transport and circuit
+// breaker of that backend's own proxy. The first target is the backend the request
+// was routed to, whose handler already applies its circuit breaker and drain tracking.
+type retryTarget struct {
+	backend   string
+	transport http.RoundTripper
+	director  func(*http.Request)
+	breaker   *CircuitBreaker
+	drains    *backendDrainTracker
+}
+
+// roundTrip sends req to the target, through its circuit breaker and tracked as an
+// in-flight request of a draining backend until the response body is closed.
+func (rt *retryTarget) roundTrip(req *http.Request) (*http.Response, error) {
+	release := func() {}
+	if rt.drains != nil {
+		var accepted bool
+		if release, accepted = rt.drains.acquire(rt.backend); !accepted {
+			return nil, fmt.Errorf("%w: %s is draining", ErrBackendUnhealthy, rt.backend)
+		}
+	}
+
+	var resp *http.Response
+	var err error
+	if rt.breaker != nil {
+		resp, err = rt.breaker.Execute(req, rt.transport.RoundTrip)
+	} else {
+		resp, err = rt.transport.RoundTrip(req)
+	}
+	if err != nil || resp == nil {
+		release()
+		return resp, err //nolint:wrapcheck // transport errors are passed through unchanged
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+// releasingBody calls release once the response body is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err //nolint:wrapcheck // body errors are passed through unchanged
+}
+
+// nextBackend returns the first backend of the request's group after current that can
+// take traffic, resolved to its own proxy, or nil when the request is retried on the
+// same backend. Backends that are unhealthy, circuit-open or draining are skipped.
+func (t *retryRoundTripper) nextBackend(state *retryState, current string) *retryTarget {
+	i := slices.Index(state.group, current)
+	if i < 0 || len(state.group) < 2 || state.inbound == nil {
+		return nil
+	}
+	m := t.module
+	for step := 1; step < len(state.group); step++ {
+		next := state.group[(i+step)%len(state.group)]
+		if next == current || m.backendUnavailable(next) != "" {
+			continue
+		}
+		proxy, ok := m.getProxyForBackendAndTenant(next, state.tenant)
+		if !ok || proxy == nil || proxy.Director == nil {
+			continue
+		}
+		timeout := m.defaultRequestTimeout()
+		if info := requestTimeoutInfoFromContext(state.inbound.Context()); info != nil {
+			timeout = info.timeout
+		}
+		return &retryTarget{
+			backend:   next,
+			transport: m.requestTransport(proxy.Transport, timeout),
+			director:  proxy.Director,
+			breaker:   m.circuitBreakers[next],
+			drains:    &m.drains,
+		}
+	}
+	return nil
+}
+
+// replayableBody returns a function returning the request body for each attempt.
+// It reports false, leaving the body intact, when the body exceeds limit bytes and
+// the request can't be retried.
+func replayableBody(req *http.Request, limit int64) (func() io.ReadCloser, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return func() io.ReadCloser { return http.NoBody }, true
+	}
+	if limit <= 0 {
+		limit = defaultRetryMaxBodySize
+	}
+	if req.ContentLength > limit {
+		return nil, false
+	}
+	buf, err := io.ReadAll(io.LimitReader(req.Body, limit+1))
+	if err != nil || int64(len(buf)) > limit {
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buf), req.Body), Closer: req.Body}
+		return nil, false
+	}
+	_ = req.Body.Close()
+	return func() io.ReadCloser { return io.NopCloser(bytes.NewReader(buf)) }, true
+}
+
+// readCloser joins a reader with the closer of the body it reads from.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// parseBackendGroup returns the backends of a comma-separated backend group spec.
+func parseBackendGroup(group string) []string {
+	var backends []string
+	for _, part := range strings.Split(group, ",") {
+		if backend := strings.TrimSpace(part); backend != "" {
+			backends = append(backends, backend)
+		}
+	}
+	return backends
+}
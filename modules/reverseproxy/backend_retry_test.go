@@ -0,0 +1,257 @@
+package reverseproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyBackend starts a backend answering the first failures requests with
+// status and the rest with 200 echoing the request body, and returns the number of
+// requests it received.
+func newFlakyBackend(t *testing.T, failures int64, status int) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var hits atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(backend.Close)
+	return backend, &hits
+}
+
+// newRetryTestModule starts a module with the given configuration.
+func newRetryTestModule(t *testing.T, cfg *ReverseProxyConfig) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+	cfg.TenantIDHeader = "X-Tenant-ID"
+	cfg.RequestTimeout = 5 * time.Second
+
+	return newStartedTestModule(t, cfg)
+}
+
+func TestRetry_ConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config RetryConfig
+	}{
+		{"negative max retries", RetryConfig{MaxRetries: -1}},
+		{"invalid status code", RetryConfig{MaxRetries: 1, RetryableStatusCodes: []int{503, 999}}},
+		{"negative backoff", RetryConfig{MaxRetries: 1, Backoff: -time.Second}},
+		{"negative max body size", RetryConfig{MaxRetries: 1, MaxBodySize: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.validate(), ErrInvalidRetryConfig)
+		})
+	}
+
+	app := NewMockTenantApplication()
+	m := NewModule()
+	require.NoError(t, m.RegisterConfig(app))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://localhost:9000"},
+		RouteConfigs:    map[string]RouteConfig{"/api/*": {Retry: &RetryConfig{MaxRetries: -1}}},
+	}))
+	require.ErrorIs(t, m.Init(app), ErrInvalidRetryConfig)
+}
+
+func TestRetry_RetriesTransientStatus(t *testing.T) {
+	backend, hits := newFlakyBackend(t, 2, http.StatusServiceUnavailable)
+	_, router, subject := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"api": {Retry: &RetryConfig{MaxRetries: 2, Backoff: time.Millisecond}},
+		},
+	})
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(3), hits.Load())
+
+	events := subject.eventsOfType(EventTypeRequestRetried)
+	require.Len(t, events, 2)
+	var data map[string]interface{}
+	require.NoError(t, events[1].DataAs(&data))
+	assert.Equal(t, "api", data["backend"])
+	assert.Equal(t, "api", data["retry_backend"])
+	assert.InDelta(t, 2, data["attempt"], 0)
+	assert.InDelta(t, http.StatusServiceUnavailable, data["status"], 0)
+
+	// Retries are exhausted before the error surfaces
+	hits.Store(-10)
+	rec = serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, int64(-7), hits.Load())
+}
+
+func TestRetry_StatusNotRetryable(t *testing.T) {
+	backend, hits := newFlakyBackend(t, 1, http.StatusInternalServerError)
+	_, router, _ := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"api": {Retry: &RetryConfig{MaxRetries: 2, Backoff: time.Millisecond}},
+		},
+	})
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, int64(1), hits.Load())
+}
+
+func TestRetry_IdempotencyGuard(t *testing.T) {
+	backend, hits := newFlakyBackend(t, 1, http.StatusBadGateway)
+	_, router, _ := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"api": {Retry: &RetryConfig{MaxRetries: 1, Backoff: time.Millisecond}},
+		},
+	})
+
+	rec := serveVia(router, http.MethodPost, "/api/orders", "", "", `{"item":1}`)
+	assert.Equal(t, http.StatusBadGateway, rec.Code, "POST requests are not retried")
+	assert.Equal(t, int64(1), hits.Load())
+
+	hits.Store(0)
+	req := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(`{"item":1}`))
+	req.Header.Set(IdempotencyKeyHeader, "order-1")
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code, "unless they carry an idempotency key")
+	assert.Equal(t, `{"item":1}`, rec.Body.String(), "the body is sent again")
+	assert.Equal(t, int64(2), hits.Load())
+}
+
+func TestRetry_RouteConfigTakesPrecedence(t *testing.T) {
+	backend, hits := newFlakyBackend(t, 1, http.StatusServiceUnavailable)
+	_, router, _ := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/orders/*": {Retry: &RetryConfig{RetryNonIdempotent: true, MaxRetries: 1, Backoff: time.Millisecond}},
+		},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"api": {Retry: &RetryConfig{MaxRetries: 1, RetryableStatusCodes: []int{http.StatusBadGateway}}},
+		},
+	})
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the backend config doesn't retry 503")
+
+	hits.Store(0)
+	rec = serveVia(router, http.MethodPost, "/api/orders/1", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "the route config retries 503 and POST")
+	assert.Equal(t, int64(2), hits.Load())
+}
+
+func TestRetry_OversizedBodyNotRetried(t *testing.T) {
+	backend, hits := newFlakyBackend(t, 1, http.StatusServiceUnavailable)
+	_, router, _ := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"api": {Retry: &RetryConfig{MaxRetries: 1, Backoff: time.Millisecond, MaxBodySize: 8}},
+		},
+	})
+
+	rec := serveVia(router, http.MethodPut, "/api/users/1", "", "", strings.Repeat("x", 16))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, int64(1), hits.Load())
+
+	rec = serveVia(router, http.MethodPut, "/api/users/1", "", "", "small")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "small", rec.Body.String())
+}
+
+func TestRetry_NextBackendInGroup(t *testing.T) {
+	down, downHits := newFlakyBackend(t, 100, http.StatusServiceUnavailable)
+	up, upHits := newFlakyBackend(t, 0, http.StatusOK)
+	_, router, subject := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"a": down.URL, "b": up.URL},
+		Routes:          map[string]string{"/api/*": "a,b"},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*": {Retry: &RetryConfig{MaxRetries: 1, Backoff: time.Millisecond, NextBackend: true}},
+		},
+	})
+
+	for range 4 {
+		rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+	assert.Equal(t, int64(2), downHits.Load(), "round-robin picks the failing backend for half the requests")
+	assert.Equal(t, int64(4), upHits.Load(), "which are retried on the next backend")
+
+	events := subject.eventsOfType(EventTypeRequestRetried)
+	require.Len(t, events, 2)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "a", data["backend"])
+	assert.Equal(t, "b", data["retry_backend"])
+}
+
+func TestRetry_MostSpecificRouteConfig(t *testing.T) {
+	m := NewModule()
+	general := &RetryConfig{MaxRetries: 1}
+	specific := &RetryConfig{MaxRetries: 3}
+	m.config = &ReverseProxyConfig{
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*":        {Retry: general},
+			"/api/orders/*": {Retry: specific},
+			"/api/orders":   {},
+		},
+	}
+
+	for range 20 {
+		assert.Same(t, specific, m.retryConfigFor("/api/orders/1", "api"))
+		assert.Same(t, general, m.retryConfigFor("/api/users", "api"))
+	}
+}
+
+func TestRetry_NextBackendSkipsUnavailable(t *testing.T) {
+	down, _ := newFlakyBackend(t, 100, http.StatusServiceUnavailable)
+	draining, drainingHits := newFlakyBackend(t, 0, http.StatusOK)
+	var gotPath atomic.Value
+	up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath.Store(r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(up.Close)
+
+	m, router, subject := newRetryTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"a": down.URL, "b": draining.URL, "c": up.URL},
+		Routes:          map[string]string{"/api/*": "a,b,c"},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*": {Retry: &RetryConfig{MaxRetries: 1, Backoff: time.Millisecond, NextBackend: true}},
+		},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"c": {PathRewriting: PathRewritingConfig{StripBasePath: "/api"}},
+		},
+	})
+	m.drains.startDrain("b")
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, drainingHits.Load(), "a draining backend is skipped")
+	assert.Equal(t, "/users", gotPath.Load(), "the next backend's own proxy directs the request")
+	assert.Zero(t, m.drains.inFlightCount("c"), "the retry is released once its response is read")
+
+	events := subject.eventsOfType(EventTypeRequestRetried)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "c", data["retry_backend"])
+}
@@ -0,0 +1,179 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// BackendSelectorServiceName is the name of the service through which other modules
+// select backends by label.
+const BackendSelectorServiceName = "reverseproxy.backendSelector"
+
+// BackendSelector resolves a backend by its labels, applying the proxy's knowledge of
+// backend availability, so that internal service-to-service calls avoid the backends
+// the proxy avoids. The module provides it as the "reverseproxy.backendSelector"
+// service:
+//
+//	var selector reverseproxy.BackendSelector
+//	if err := app.GetService(reverseproxy.BackendSelectorServiceName, &selector); err != nil {
+//	    return err
+//	}
+//	backend, err := selector.SelectBackend(ctx, map[string]string{"service": "reports", "version": "v2"})
+//	if err != nil {
+//	    return err
+//	}
+//	resp, err := client.Get(backend.URL.JoinPath("/summary").String())
+//	selector.ReportResult(backend.ID, err)
+type BackendSelector interface {
+	// SelectBackend returns an available backend carrying all the given labels,
+	// rotating among matching backends like a backend group. Backends with an open
+	// circuit breaker, failing health checks or being drained are skipped, and with
+	// locality configured the closest ones are preferred. The tenant in ctx, if any,
+	// selects its backend URL overrides. It returns ErrNoMatchingBackend when no
+	// backend carries the labels and ErrNoAvailableBackend when none of those can
+	// take traffic.
+	SelectBackend(ctx context.Context, labels map[string]string) (*SelectedBackend, error)
+
+	// ReportResult records the outcome of a call to a selected backend in its circuit
+	// breaker, so failures seen by other modules open the circuit like failures of
+	// proxied requests. A nil err records a success.
+	ReportResult(backendID string, err error)
+}
+
+var _ BackendSelector = (*ReverseProxyModule)(nil)
+
+// SelectedBackend is a backend returned by a BackendSelector.
+type SelectedBackend struct {
+	// ID is the backend's ID in backend_services
+	ID string
+	// URL is the backend's base URL
+	URL *url.URL
+	// Labels are the labels the backend is configured with
+	Labels map[string]string
+}
+
+// SelectBackend implements BackendSelector.
+func (m *ReverseProxyModule) SelectBackend(ctx context.Context, labels map[string]string) (*SelectedBackend, error) {
+	config := m.config
+	if config == nil {
+		return nil, ErrConfigurationNil
+	}
+	if tenantID, ok := modular.GetTenantIDFromContext(ctx); ok {
+		if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
+			config = tenantCfg
+		}
+	}
+
+	matching := backendsMatchingLabels(config, labels)
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoMatchingBackend, labelSelector(labels))
+	}
+	var available []string
+	for _, backend := range matching {
+		if m.backendUnavailable(backend) == "" {
+			available = append(available, backend)
+		}
+	}
+	if len(available) == 0 {
+		return nil, fmt.Errorf("%w: all backends matching %s are unavailable", ErrNoAvailableBackend, labelSelector(labels))
+	}
+
+	// Rotate among the matching backends like a group, narrowing them to the closest
+	// tier with locality configured
+	counter := "labels:" + labelSelector(labels)
+	if m.locality != nil {
+		available, counter = m.localityCandidates(ctx, counter, available)
+	}
+	m.loadBalanceMutex.Lock()
+	selected := available[m.loadBalanceCounters[counter]%len(available)]
+	m.loadBalanceCounters[counter]++
+	m.loadBalanceMutex.Unlock()
+	if m.locality != nil {
+		m.locality.recordSelection(selected, m.locality.config.scope(m.backendLocality(selected)))
+	}
+
+	backendCfg := config.BackendConfigs[selected]
+	rawURL := config.BackendServices[selected]
+	if backendCfg.URL != "" {
+		rawURL = backendCfg.URL
+	}
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s URL %s: %w", selected, rawURL, err)
+	}
+	return &SelectedBackend{ID: selected, URL: target, Labels: maps.Clone(backendCfg.Labels)}, nil
+}
+
+// ReportResult implements BackendSelector.
+func (m *ReverseProxyModule) ReportResult(backendID string, err error) {
+	cb, ok := m.circuitBreakers[backendID]
+	if !ok || cb == nil {
+		return
+	}
+	if err != nil {
+		cb.RecordFailure()
+		return
+	}
+	cb.RecordSuccess()
+}
+
+// backendUnavailable returns why a backend can't take traffic, or "" when it can.
+func (m *ReverseProxyModule) backendUnavailable(backend string) string {
+	if cb, ok := m.circuitBreakers[backend]; ok && cb.IsOpen() {
+		return localityReasonCircuitOpen
+	}
+	if status, ok := m.GetBackendHealthStatus(backend); ok && !status.LastCheck.IsZero() && !status.HealthCheckPassing {
+		return localityReasonUnhealthy
+	}
+	if m.drains.isDraining(backend) {
+		return localityReasonDraining
+	}
+	return ""
+}
+
+// backendsMatchingLabels returns the sorted IDs of the backends with a URL carrying all
+// labels. Any backend matches an empty selector.
+func backendsMatchingLabels(config *ReverseProxyConfig, labels map[string]string) []string {
+	backends := make(map[string]struct{}, len(config.BackendServices))
+	for backend := range config.BackendServices {
+		backends[backend] = struct{}{}
+	}
+	for backend := range config.BackendConfigs {
+		backends[backend] = struct{}{}
+	}
+	var matching []string
+	for backend := range backends {
+		backendCfg := config.BackendConfigs[backend]
+		if config.BackendServices[backend] == "" && backendCfg.URL == "" {
+			continue
+		}
+		matches := true
+		for key, value := range labels {
+			if actual, ok := backendCfg.Labels[key]; !ok || actual != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			matching = append(matching, backend)
+		}
+	}
+	sort.Strings(matching)
+	return matching
+}
+
+// labelSelector formats labels as a canonical selector such as "service=reports,version=v2".
+func labelSelector(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, key+"="+labels[key])
+	}
+	return strings.Join(pairs, ",")
+}
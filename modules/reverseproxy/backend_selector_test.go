@@ -0,0 +1,107 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSelectorTestModule returns a module with two reports backends and a billing backend.
+func newSelectorTestModule() *ReverseProxyModule {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"reports-a": "http://reports-a.internal:8080",
+			"reports-b": "http://reports-b.internal:8080",
+			"billing":   "http://billing.internal",
+		},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"reports-a": {Labels: map[string]string{"service": "reports", "version": "v2"}},
+			"reports-b": {Labels: map[string]string{"service": "reports", "version": "v2"}, URL: "http://reports-b.internal:9090"},
+			"billing":   {Labels: map[string]string{"service": "billing"}},
+		},
+	}
+	return m
+}
+
+func TestSelectBackend_RotatesAmongMatchingBackends(t *testing.T) {
+	m := newSelectorTestModule()
+	labels := map[string]string{"service": "reports", "version": "v2"}
+
+	first, err := m.SelectBackend(context.Background(), labels)
+	require.NoError(t, err)
+	assert.Equal(t, "reports-a", first.ID)
+	assert.Equal(t, "http://reports-a.internal:8080", first.URL.String())
+	assert.Equal(t, labels, first.Labels)
+
+	second, err := m.SelectBackend(context.Background(), labels)
+	require.NoError(t, err)
+	assert.Equal(t, "reports-b", second.ID)
+	assert.Equal(t, "http://reports-b.internal:9090", second.URL.String(), "the backend config URL takes precedence")
+
+	billing, err := m.SelectBackend(context.Background(), map[string]string{"service": "billing"})
+	require.NoError(t, err)
+	assert.Equal(t, "billing", billing.ID)
+
+	_, err = m.SelectBackend(context.Background(), map[string]string{"service": "reports", "version": "v3"})
+	require.ErrorIs(t, err, ErrNoMatchingBackend)
+	assert.Contains(t, err.Error(), "service=reports,version=v3")
+}
+
+func TestSelectBackend_SkipsUnavailableBackends(t *testing.T) {
+	m := newSelectorTestModule()
+	labels := map[string]string{"service": "reports"}
+
+	breaker := NewCircuitBreaker("reports-a", nil)
+	m.circuitBreakers["reports-a"] = breaker
+	for range 5 {
+		m.ReportResult("reports-a", errors.New("connection refused"))
+	}
+	require.True(t, breaker.IsOpen(), "failures reported by callers open the circuit")
+	for range 3 {
+		selected, err := m.SelectBackend(context.Background(), labels)
+		require.NoError(t, err)
+		assert.Equal(t, "reports-b", selected.ID)
+	}
+
+	m.drains.startDrain("reports-b")
+	_, err := m.SelectBackend(context.Background(), labels)
+	require.ErrorIs(t, err, ErrNoAvailableBackend)
+
+	breaker.Reset()
+	m.ReportResult("reports-a", nil)
+	selected, err := m.SelectBackend(context.Background(), labels)
+	require.NoError(t, err)
+	assert.Equal(t, "reports-a", selected.ID)
+	m.ReportResult("unknown", errors.New("ignored"))
+}
+
+func TestSelectBackend_TenantOverrides(t *testing.T) {
+	m := newSelectorTestModule()
+	tenantCfg := *m.config
+	tenantCfg.BackendServices = map[string]string{"billing": "http://billing.acme.internal"}
+	m.setTenantConfig("acme", &tenantCfg)
+
+	ctx := modular.NewTenantContext(context.Background(), "acme")
+	selected, err := m.SelectBackend(ctx, map[string]string{"service": "billing"})
+	require.NoError(t, err)
+	assert.Equal(t, "http://billing.acme.internal", selected.URL.String())
+
+	_, err = m.SelectBackend(ctx, map[string]string{"service": "reports"})
+	require.NoError(t, err, "backends configured with a URL remain selectable")
+}
+
+func TestBackendSelectorService(t *testing.T) {
+	m := newSelectorTestModule()
+	var provided BackendSelector
+	for _, service := range m.ProvidesServices() {
+		if service.Name == BackendSelectorServiceName {
+			provided = service.Instance.(BackendSelector)
+		}
+	}
+	require.NotNil(t, provided)
+}
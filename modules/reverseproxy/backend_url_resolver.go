@@ -0,0 +1,238 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// Policies applied when a BackendURLResolver fails.
+const (
+	// BackendURLOnErrorFallback proxies the request to the backend's configured URL
+	BackendURLOnErrorFallback = "fallback"
+	// BackendURLOnErrorReject rejects the request with BackendURLResolutionConfig.ErrorStatus
+	BackendURLOnErrorReject = "reject"
+)
+
+// Defaults applied to BackendURLResolutionConfig fields left unset.
+const (
+	defaultBackendURLCacheSize   = 10000
+	defaultBackendURLErrorStatus = http.StatusBadGateway
+)
+
+// BackendURLResolver computes the upstream URL of each request proxied to a backend,
+// for example to send each user to the cluster holding their shard. The module uses
+// the resolver registered as the "backendURLResolver" service or set with
+// SetBackendURLResolver.
+type BackendURLResolver interface {
+	// ResolveBackendURL returns the URL the request is proxied to in place of the
+	// backend's configured URL. Path rewriting, header rewriting and the request
+	// path apply to it as they would to the configured URL. Returning a nil URL and
+	// a nil error keeps the configured URL. The tenant ID is empty for requests
+	// without a tenant.
+	ResolveBackendURL(ctx context.Context, backendID string, tenantID modular.TenantID, req *http.Request) (*url.URL, error)
+}
+
+// BackendURLCacheKeyer is optionally implemented by a BackendURLResolver whose results
+// can be cached. Requests to the same backend and tenant with the same key share a
+// resolved URL for BackendURLResolutionConfig.CacheTTL.
+type BackendURLCacheKeyer interface {
+	// BackendURLCacheKey returns the key identifying the resolution of the request,
+	// such as the user ID it is sharded by, or "" to resolve it without the cache.
+	BackendURLCacheKey(backendID string, tenantID modular.TenantID, req *http.Request) string
+}
+
+// BackendURLResolutionConfig configures how the URLs computed by a BackendURLResolver
+// are cached and what happens when the resolver fails.
+//
+//	backend_url_resolution:
+//	  cache_ttl: 30s
+//	  on_error: reject
+type BackendURLResolutionConfig struct {
+	// CacheTTL is how long resolved URLs are cached, for resolvers implementing
+	// BackendURLCacheKeyer. Zero disables caching.
+	CacheTTL time.Duration `json:"cache_ttl" yaml:"cache_ttl" toml:"cache_ttl" env:"BACKEND_URL_CACHE_TTL"`
+
+	// CacheSize is the maximum number of cached URLs. Default 10000.
+	CacheSize int `json:"cache_size" yaml:"cache_size" toml:"cache_size" env:"BACKEND_URL_CACHE_SIZE"`
+
+	// OnError is "fallback" (default) to use the backend's configured URL when the
+	// resolver fails, or "reject" to fail the request
+	OnError string `json:"on_error" yaml:"on_error" toml:"on_error" env:"BACKEND_URL_ON_ERROR"`
+
+	// ErrorStatus is the status of requests rejected because the resolver failed. Default 502.
+	ErrorStatus int `json:"error_status" yaml:"error_status" toml:"error_status" env:"BACKEND_URL_ERROR_STATUS"`
+}
+
+// validate checks the cache settings, error policy and error status.
+func (c *BackendURLResolutionConfig) validate() error {
+	if c.CacheTTL < 0 {
+		return fmt.Errorf("%w: cache_ttl %s is negative", ErrInvalidBackendURLResolution, c.CacheTTL)
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("%w: cache_size %d is negative", ErrInvalidBackendURLResolution, c.CacheSize)
+	}
+	switch c.OnError {
+	case "", BackendURLOnErrorFallback, BackendURLOnErrorReject:
+	default:
+		return fmt.Errorf("%w: unknown on_error policy %q", ErrInvalidBackendURLResolution, c.OnError)
+	}
+	if c.ErrorStatus != 0 && (c.ErrorStatus < 400 || c.ErrorStatus > 599) {
+		return fmt.Errorf("%w: error_status %d is not an error status", ErrInvalidBackendURLResolution, c.ErrorStatus)
+	}
+	return nil
+}
+
+// resolvedBackendURLKey is the context key under which the URL resolved for a
+// request is stored for the backend's director.
+type resolvedBackendURLKey struct{}
+
+// resolvedBackendURL is a URL resolved for a request to a backend.
+type resolvedBackendURL struct {
+	backendID string
+	url       *url.URL
+}
+
+// resolvedBackendURLFromContext returns the URL resolved for a request to backendID.
+func resolvedBackendURLFromContext(ctx context.Context, backendID string) (*url.URL, bool) {
+	resolved, ok := ctx.Value(resolvedBackendURLKey{}).(resolvedBackendURL)
+	if !ok || resolved.backendID != backendID {
+		return nil, false
+	}
+	return resolved.url, true
+}
+
+// backendURLCache caches resolved URLs until they expire.
+type backendURLCache struct {
+	mu      sync.Mutex
+	entries map[string]backendURLCacheEntry
+}
+
+// backendURLCacheEntry is a cached URL and when it expires.
+type backendURLCacheEntry struct {
+	url       *url.URL
+	expiresAt time.Time
+}
+
+// get returns the unexpired URL cached under key.
+func (c *backendURLCache) get(key string, now time.Time) (*url.URL, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.url, true
+}
+
+// put caches u under key, dropping expired entries, then arbitrary ones, when the
+// cache holds maxSize entries.
+func (c *backendURLCache) put(key string, u *url.URL, expiresAt time.Time, maxSize int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]backendURLCacheEntry)
+	}
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= maxSize {
+		now := time.Now()
+		for k, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxSize {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = backendURLCacheEntry{url: u, expiresAt: expiresAt}
+}
+
+// SetBackendURLResolver sets the resolver computing the upstream URL of each request,
+// replacing one provided as the "backendURLResolver" service. A nil resolver proxies
+// every request to the configured backend URLs.
+func (m *ReverseProxyModule) SetBackendURLResolver(resolver BackendURLResolver) {
+	m.backendURLResolver = resolver
+	m.backendURLCache = &backendURLCache{}
+}
+
+// resolveBackendURL returns r carrying the URL the resolver picked for a request to
+// backendID, or r unchanged when there is no resolver or it keeps the configured
+// URL. When the resolver fails and the policy is to reject, it writes the error
+// response and reports false.
+func (m *ReverseProxyModule) resolveBackendURL(w http.ResponseWriter, r *http.Request, backendID string, tenantID modular.TenantID) (*http.Request, bool) {
+	if m.backendURLResolver == nil {
+		return r, true
+	}
+	var cfg BackendURLResolutionConfig
+	if m.config != nil {
+		cfg = m.config.BackendURLResolution
+	}
+
+	var cacheKey string
+	if keyer, ok := m.backendURLResolver.(BackendURLCacheKeyer); ok && cfg.CacheTTL > 0 && m.backendURLCache != nil {
+		if key := keyer.BackendURLCacheKey(backendID, tenantID, r); key != "" {
+			cacheKey = backendID + "\x00" + string(tenantID) + "\x00" + key
+			if cached, ok := m.backendURLCache.get(cacheKey, time.Now()); ok {
+				return withResolvedBackendURL(r, backendID, cached), true
+			}
+		}
+	}
+
+	resolved, err := m.backendURLResolver.ResolveBackendURL(r.Context(), backendID, tenantID, r)
+	if err == nil && resolved != nil && resolved.Scheme != "http" && resolved.Scheme != "https" {
+		err = fmt.Errorf("%w: %s must use http or https", ErrInvalidUpstreamURL, resolved.Redacted())
+	}
+	if err != nil {
+		policy := cfg.OnError
+		if policy == "" {
+			policy = BackendURLOnErrorFallback
+		}
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Warn("Backend URL resolver failed", "backend", backendID, "policy", policy, "error", err)
+		}
+		m.emitEvent(r.Context(), EventTypeBackendURLResolveFailed, map[string]interface{}{
+			"backend": backendID,
+			"tenant":  string(tenantID),
+			"policy":  policy,
+			"error":   err.Error(),
+		})
+		if policy == BackendURLOnErrorReject {
+			status := cfg.ErrorStatus
+			if status == 0 {
+				status = defaultBackendURLErrorStatus
+			}
+			http.Error(w, fmt.Sprintf("Backend %s unavailable", backendID), status)
+			return r, false
+		}
+		return r, true
+	}
+	if resolved == nil {
+		return r, true
+	}
+
+	if cacheKey != "" {
+		size := cfg.CacheSize
+		if size == 0 {
+			size = defaultBackendURLCacheSize
+		}
+		m.backendURLCache.put(cacheKey, resolved, time.Now().Add(cfg.CacheTTL), size)
+	}
+	return withResolvedBackendURL(r, backendID, resolved), true
+}
+
+// withResolvedBackendURL returns r carrying u as the upstream URL for backendID.
+func withResolvedBackendURL(r *http.Request, backendID string, u *url.URL) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), resolvedBackendURLKey{}, resolvedBackendURL{backendID: backendID, url: u}))
+}
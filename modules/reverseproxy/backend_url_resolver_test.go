@@ -0,0 +1,190 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shardResolver sends requests with an X-User header to the shard of that user and
+// counts its resolutions.
+type shardResolver struct {
+	shards map[string]*url.URL
+	err    error
+	calls  atomic.Int32
+}
+
+func (s *shardResolver) ResolveBackendURL(ctx context.Context, backendID string, tenantID modular.TenantID, req *http.Request) (*url.URL, error) {
+	s.calls.Add(1)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.shards[req.Header.Get("X-User")], nil
+}
+
+// cachingShardResolver caches resolutions by user.
+type cachingShardResolver struct {
+	shardResolver
+}
+
+func (s *cachingShardResolver) BackendURLCacheKey(backendID string, tenantID modular.TenantID, req *http.Request) string {
+	return req.Header.Get("X-User")
+}
+
+// newNamedServer starts a server answering every request with its name and path.
+func newNamedServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Server", name)
+		w.Header().Set("X-Path", r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newResolverTestModule creates a module proxying backend "api" to the default server.
+func newResolverTestModule(t *testing.T, defaultURL string, resolution BackendURLResolutionConfig, resolver BackendURLResolver) *ReverseProxyModule {
+	t.Helper()
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices:      map[string]string{"api": defaultURL},
+		RequestTimeout:       5 * time.Second,
+		BackendURLResolution: resolution,
+	}
+	require.NoError(t, m.validateConfig())
+	require.NoError(t, m.createBackendProxy("api", defaultURL))
+	m.initialized = true
+	m.SetBackendURLResolver(resolver)
+	return m
+}
+
+// serveAs sends a request for path as user through handler.
+func serveAs(handler http.HandlerFunc, user, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestBackendURLResolver_RoutesPerRequest(t *testing.T) {
+	defaultServer := newNamedServer(t, "default")
+	shardA := newNamedServer(t, "shard-a")
+	shardB := newNamedServer(t, "shard-b")
+	shardBURL, err := url.Parse(shardB.URL + "/v2")
+	require.NoError(t, err)
+	shardAURL, err := url.Parse(shardA.URL)
+	require.NoError(t, err)
+
+	resolver := &shardResolver{shards: map[string]*url.URL{"alice": shardAURL, "bob": shardBURL}}
+	m := newResolverTestModule(t, defaultServer.URL, BackendURLResolutionConfig{}, resolver)
+	handler := m.createBackendProxyHandler("api")
+
+	rec := serveAs(handler, "alice", "/users/1")
+	assert.Equal(t, "shard-a", rec.Header().Get("X-Server"))
+	assert.Equal(t, "/users/1", rec.Header().Get("X-Path"))
+
+	rec = serveAs(handler, "bob", "/users/2")
+	assert.Equal(t, "shard-b", rec.Header().Get("X-Server"))
+	assert.Equal(t, "/v2/users/2", rec.Header().Get("X-Path"))
+
+	rec = serveAs(handler, "carol", "/users/3")
+	assert.Equal(t, "default", rec.Header().Get("X-Server"), "a nil URL keeps the configured backend")
+	assert.Equal(t, int32(3), resolver.calls.Load())
+}
+
+func TestBackendURLResolver_CachesByKey(t *testing.T) {
+	defaultServer := newNamedServer(t, "default")
+	shard := newNamedServer(t, "shard")
+	shardURL, err := url.Parse(shard.URL)
+	require.NoError(t, err)
+
+	resolver := &cachingShardResolver{shardResolver{shards: map[string]*url.URL{"alice": shardURL}}}
+	m := newResolverTestModule(t, defaultServer.URL, BackendURLResolutionConfig{CacheTTL: time.Minute}, resolver)
+	handler := m.createBackendProxyHandler("api")
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "shard", serveAs(handler, "alice", "/users").Header().Get("X-Server"))
+	}
+	assert.Equal(t, int32(1), resolver.calls.Load())
+
+	// Requests without a cache key and unresolved requests are not cached
+	serveAs(handler, "", "/users")
+	serveAs(handler, "", "/users")
+	assert.Equal(t, int32(3), resolver.calls.Load())
+}
+
+func TestBackendURLResolver_ErrorPolicies(t *testing.T) {
+	defaultServer := newNamedServer(t, "default")
+	resolver := &shardResolver{err: errors.New("shard directory unavailable")}
+
+	t.Run("fallback", func(t *testing.T) {
+		subject := &capturingSubject{}
+		m := newResolverTestModule(t, defaultServer.URL, BackendURLResolutionConfig{}, resolver)
+		m.subject = subject
+
+		rec := serveAs(m.createBackendProxyHandler("api"), "alice", "/users")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "default", rec.Header().Get("X-Server"))
+
+		events := subject.eventsOfType(EventTypeBackendURLResolveFailed)
+		require.Len(t, events, 1)
+		var data map[string]interface{}
+		require.NoError(t, events[0].DataAs(&data))
+		assert.Equal(t, "api", data["backend"])
+		assert.Equal(t, BackendURLOnErrorFallback, data["policy"])
+		assert.Equal(t, "shard directory unavailable", data["error"])
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		m := newResolverTestModule(t, defaultServer.URL, BackendURLResolutionConfig{
+			OnError:     BackendURLOnErrorReject,
+			ErrorStatus: http.StatusServiceUnavailable,
+		}, resolver)
+
+		rec := serveAs(m.createBackendProxyHandler("api"), "alice", "/users")
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		assert.Empty(t, rec.Header().Get("X-Server"))
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		unixURL, err := url.Parse("unix:///var/run/api.sock")
+		require.NoError(t, err)
+		m := newResolverTestModule(t, defaultServer.URL, BackendURLResolutionConfig{OnError: BackendURLOnErrorReject},
+			&shardResolver{shards: map[string]*url.URL{"alice": unixURL}})
+
+		rec := serveAs(m.createBackendProxyHandler("api"), "alice", "/users")
+		assert.Equal(t, http.StatusBadGateway, rec.Code)
+	})
+}
+
+func TestBackendURLResolutionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config BackendURLResolutionConfig
+	}{
+		{"negative cache ttl", BackendURLResolutionConfig{CacheTTL: -time.Second}},
+		{"negative cache size", BackendURLResolutionConfig{CacheSize: -1}},
+		{"unknown policy", BackendURLResolutionConfig{OnError: "retry"}},
+		{"success error status", BackendURLResolutionConfig{ErrorStatus: http.StatusOK}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.config.validate(), ErrInvalidBackendURLResolution)
+		})
+	}
+	valid := BackendURLResolutionConfig{CacheTTL: time.Minute, OnError: BackendURLOnErrorReject, ErrorStatus: http.StatusServiceUnavailable}
+	assert.NoError(t, valid.validate())
+}
@@ -0,0 +1,481 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// Bandwidth throttling modes, selecting what happens to a request arriving while its
+// tenant has used up its bandwidth.
+const (
+	// BandwidthModeShape accepts the request and slows its transfer down to the cap
+	BandwidthModeShape = "shape"
+	// BandwidthModeReject answers the request with BandwidthConfig.RejectStatus
+	BandwidthModeReject = "reject"
+)
+
+// otherTenantsLabel is the tenant recorded for tenant IDs the application doesn't know.
+const otherTenantsLabel = "other"
+
+// maxBandwidthChunk bounds the bytes transferred between throttling decisions, so
+// shaped streams flow evenly rather than in bursts.
+const maxBandwidthChunk = 32 * 1024
+
+// BandwidthConfig caps the request (ingress) and response (egress) bytes each tenant
+// may transfer per second. Bytes are metered by a token bucket per tenant and
+// direction while bodies stream, so a tenant's bulk transfers slow down, and hold
+// back the backend through backpressure, without affecting other tenants. Requests
+// without a tenant ID are not throttled, and requests with a tenant ID neither listed
+// in Tenants nor registered with the application share the Default bucket recorded
+// as "other", so tenant IDs made up by clients can't escape the cap.
+//
+//	bandwidth:
+//	  enabled: true
+//	  mode: shape
+//	  default:
+//	    egress_bytes_per_second: 10485760
+//	  tenants:
+//	    bulk-exporter:
+//	      egress_bytes_per_second: 1048576
+//	      egress_burst_bytes: 4194304
+type BandwidthConfig struct {
+	// Enabled turns on bandwidth throttling
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"BANDWIDTH_ENABLED"`
+
+	// Mode is "shape" (default) to slow a tenant's transfers down to its cap, or
+	// "reject" to also turn away the tenant's new requests while it is over its cap.
+	// Transfers already streaming are shaped in both modes.
+	Mode string `json:"mode" yaml:"mode" toml:"mode" env:"BANDWIDTH_MODE"`
+
+	// RejectStatus is the status of rejected requests, 429 (default) or 503
+	RejectStatus int `json:"reject_status" yaml:"reject_status" toml:"reject_status" env:"BANDWIDTH_REJECT_STATUS"`
+
+	// Default is the limit of tenants not listed in Tenants. Zero rates leave them unthrottled.
+	Default BandwidthLimit `json:"default" yaml:"default" toml:"default"`
+
+	// Tenants maps tenant IDs to their limits, replacing Default
+	Tenants map[string]BandwidthLimit `json:"tenants" yaml:"tenants" toml:"tenants"`
+}
+
+// BandwidthLimit is a tenant's bandwidth cap. A zero rate leaves that direction unthrottled.
+type BandwidthLimit struct {
+	// EgressBytesPerSecond caps the response bytes sent to the tenant's clients
+	EgressBytesPerSecond int64 `json:"egress_bytes_per_second" yaml:"egress_bytes_per_second" toml:"egress_bytes_per_second"`
+
+	// EgressBurstBytes is how many response bytes may be sent at once after a quiet
+	// period. Defaults to one second's worth.
+	EgressBurstBytes int64 `json:"egress_burst_bytes" yaml:"egress_burst_bytes" toml:"egress_burst_bytes"`
+
+	// IngressBytesPerSecond caps the request body bytes read from the tenant's clients
+	IngressBytesPerSecond int64 `json:"ingress_bytes_per_second" yaml:"ingress_bytes_per_second" toml:"ingress_bytes_per_second"`
+
+	// IngressBurstBytes is how many request bytes may be read at once after a quiet
+	// period. Defaults to one second's worth.
+	IngressBurstBytes int64 `json:"ingress_burst_bytes" yaml:"ingress_burst_bytes" toml:"ingress_burst_bytes"`
+}
+
+// validate checks the mode, reject status and every limit.
+func (c *BandwidthConfig) validate() error {
+	switch c.Mode {
+	case "", BandwidthModeShape, BandwidthModeReject:
+	default:
+		return fmt.Errorf("%w: mode %q must be %q or %q", ErrInvalidBandwidthConfig, c.Mode, BandwidthModeShape, BandwidthModeReject)
+	}
+	switch c.RejectStatus {
+	case 0, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	default:
+		return fmt.Errorf("%w: reject_status %d must be 429 or 503", ErrInvalidBandwidthConfig, c.RejectStatus)
+	}
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for tenantID, limit := range c.Tenants {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that rates and bursts are not negative.
+func (l BandwidthLimit) validate() error {
+	for name, value := range map[string]int64{
+		"egress_bytes_per_second":  l.EgressBytesPerSecond,
+		"egress_burst_bytes":       l.EgressBurstBytes,
+		"ingress_bytes_per_second": l.IngressBytesPerSecond,
+		"ingress_burst_bytes":      l.IngressBurstBytes,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%w: %s %d is negative", ErrInvalidBandwidthConfig, name, value)
+		}
+	}
+	return nil
+}
+
+// TenantBandwidthStatus reports the bytes a tenant has transferred and how often it
+// has been throttled.
+type TenantBandwidthStatus struct {
+	Tenant           string  `json:"tenant"`
+	EgressBytes      uint64  `json:"egress_bytes"`
+	IngressBytes     uint64  `json:"ingress_bytes"`
+	ThrottledSeconds float64 `json:"throttled_seconds"`
+	Rejected         uint64  `json:"rejected"`
+}
+
+// byteBucket is a token bucket metering bytes. Transfers may drive it into debt, which
+// the transferring request then waits out, so concurrent requests share the rate.
+type byteBucket struct {
+	mu     sync.Mutex
+	rate   float64 // bytes per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newByteBucket(rate, burst int64, now time.Time) *byteBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = rate
+	}
+	return &byteBucket{rate: float64(rate), burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// refill adds the tokens accrued since the last update. Callers hold b.mu.
+func (b *byteBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+}
+
+// take removes n bytes' worth of tokens and returns how long the caller must wait
+// before the bucket is out of debt.
+func (b *byteBucket) take(now time.Time, n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// deficit returns how long until the bucket holds tokens again, or zero when it does.
+func (b *byteBucket) deficit(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill(now)
+	if b.tokens > 0 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// chunkSize returns the bytes transferred between throttling decisions.
+func (b *byteBucket) chunkSize() int {
+	return int(math.Max(1, math.Min(b.burst, maxBandwidthChunk)))
+}
+
+// tenantBandwidth holds a tenant's buckets and counters.
+type tenantBandwidth struct {
+	tenant  string
+	egress  *byteBucket // nil when unthrottled
+	ingress *byteBucket // nil when unthrottled
+
+	mu           sync.Mutex
+	egressBytes  uint64
+	ingressBytes uint64
+	throttled    time.Duration
+	rejected     uint64
+}
+
+func (t *tenantBandwidth) record(egress, ingress int, throttled time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.egressBytes += uint64(egress)   //nolint:gosec // byte counts are never negative
+	t.ingressBytes += uint64(ingress) //nolint:gosec // byte counts are never negative
+	t.throttled += throttled
+}
+
+func (t *tenantBandwidth) status() TenantBandwidthStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TenantBandwidthStatus{
+		Tenant:           t.tenant,
+		EgressBytes:      t.egressBytes,
+		IngressBytes:     t.ingressBytes,
+		ThrottledSeconds: t.throttled.Seconds(),
+		Rejected:         t.rejected,
+	}
+}
+
+// bandwidthLimiter holds the buckets of every tenant seen.
+type bandwidthLimiter struct {
+	config  BandwidthConfig
+	reject  bool
+	status  int
+	now     func() time.Time
+	sleep   func(ctx context.Context, d time.Duration) error
+	known   func(tenantID string) bool // reports tenants registered with the application
+	mu      sync.Mutex
+	tenants map[string]*tenantBandwidth
+}
+
+// newBandwidthLimiter returns a limiter for the configured caps, or nil when
+// bandwidth throttling is disabled. Known reports the tenants registered with the
+// application, which are tracked individually through the default limit.
+func newBandwidthLimiter(config BandwidthConfig, known func(tenantID string) bool) *bandwidthLimiter {
+	if !config.Enabled {
+		return nil
+	}
+	status := config.RejectStatus
+	if status == 0 {
+		status = http.StatusTooManyRequests
+	}
+	return &bandwidthLimiter{
+		config:  config,
+		reject:  config.Mode == BandwidthModeReject,
+		status:  status,
+		now:     time.Now,
+		sleep:   sleepContext,
+		known:   known,
+		tenants: make(map[string]*tenantBandwidth),
+	}
+}
+
+// tenant returns the buckets of tenantID, creating them on first use, or nil when the
+// tenant is not throttled.
+func (l *bandwidthLimiter) tenant(tenantID string) *tenantBandwidth {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.tenants[tenantID]; ok {
+		return t
+	}
+	limit, listed := l.config.Tenants[tenantID]
+	if !listed {
+		limit = l.config.Default
+		if !l.known(tenantID) {
+			tenantID = otherTenantsLabel
+			if t, ok := l.tenants[tenantID]; ok {
+				return t
+			}
+		}
+	}
+	if limit.EgressBytesPerSecond <= 0 && limit.IngressBytesPerSecond <= 0 {
+		return nil
+	}
+	now := l.now()
+	t := &tenantBandwidth{
+		tenant:  tenantID,
+		egress:  newByteBucket(limit.EgressBytesPerSecond, limit.EgressBurstBytes, now),
+		ingress: newByteBucket(limit.IngressBytesPerSecond, limit.IngressBurstBytes, now),
+	}
+	l.tenants[tenantID] = t
+	return t
+}
+
+// forget stops tracking a tenant removed from the application.
+func (l *bandwidthLimiter) forget(tenantID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tenants, tenantID)
+}
+
+// statuses returns the counters of every tracked tenant, sorted by tenant ID.
+func (l *bandwidthLimiter) statuses() []TenantBandwidthStatus {
+	l.mu.Lock()
+	tenants := make(map[string]*tenantBandwidth, len(l.tenants))
+	for tenantID, t := range l.tenants {
+		tenants[tenantID] = t
+	}
+	l.mu.Unlock()
+
+	statuses := make([]TenantBandwidthStatus, 0, len(tenants))
+	for _, tenantID := range sortedKeys(tenants) {
+		statuses = append(statuses, tenants[tenantID].status())
+	}
+	return statuses
+}
+
+// writePrometheus writes each tenant's counters to p.
+func (l *bandwidthLimiter) writePrometheus(p *prometheusWriter) {
+	statuses := l.statuses()
+	counters := []struct {
+		name string
+		get  func(TenantBandwidthStatus) float64
+	}{
+		{"reverseproxy_tenant_egress_bytes_total", func(s TenantBandwidthStatus) float64 { return float64(s.EgressBytes) }},
+		{"reverseproxy_tenant_ingress_bytes_total", func(s TenantBandwidthStatus) float64 { return float64(s.IngressBytes) }},
+		{"reverseproxy_tenant_throttled_seconds_total", func(s TenantBandwidthStatus) float64 { return s.ThrottledSeconds }},
+		{"reverseproxy_tenant_bandwidth_rejected_total", func(s TenantBandwidthStatus) float64 { return float64(s.Rejected) }},
+	}
+	for _, counter := range counters {
+		p.family(counter.name, "counter")
+		for _, s := range statuses {
+			p.sample(counter.name, counter.get(s), "tenant", s.Tenant)
+		}
+	}
+}
+
+// sleepContext waits for d, returning early with the context's error when it ends.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // callers check for context errors
+	case <-timer.C:
+		return nil
+	}
+}
+
+// BandwidthStatus returns the bandwidth counters of every throttled tenant seen, or
+// nil when bandwidth throttling is disabled.
+func (m *ReverseProxyModule) BandwidthStatus() []TenantBandwidthStatus {
+	if m.bandwidth == nil {
+		return nil
+	}
+	return m.bandwidth.statuses()
+}
+
+// withBandwidth throttles the request and response bodies of handler to the caps of
+// the request's tenant.
+func (m *ReverseProxyModule) withBandwidth(handler http.HandlerFunc) http.HandlerFunc {
+	limiter := m.bandwidth
+	if limiter == nil || handler == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, ok := m.requestTenant(r)
+		if !ok {
+			handler(w, r)
+			return
+		}
+		tenant := limiter.tenant(tenantID)
+		if tenant == nil {
+			handler(w, r)
+			return
+		}
+
+		if limiter.reject {
+			now := limiter.now()
+			wait := time.Duration(0)
+			for _, bucket := range []*byteBucket{tenant.egress, tenant.ingress} {
+				if bucket != nil {
+					wait = max(wait, bucket.deficit(now))
+				}
+			}
+			if wait > 0 {
+				m.rejectBandwidth(w, r, limiter, tenant, wait)
+				return
+			}
+		}
+
+		ctx := r.Context()
+		if tenant.ingress != nil && r.Body != nil && r.Body != http.NoBody {
+			r.Body = &bandwidthReadCloser{ReadCloser: r.Body, ctx: ctx, limiter: limiter, tenant: tenant}
+		}
+		if tenant.egress != nil {
+			w = &bandwidthResponseWriter{ResponseWriter: w, ctx: ctx, limiter: limiter, tenant: tenant}
+		}
+		handler(w, r)
+	}
+}
+
+// requestTenant returns the tenant of r from its context or tenant header.
+func (m *ReverseProxyModule) requestTenant(r *http.Request) (string, bool) {
+	if tenantID, ok := modular.GetTenantIDFromContext(r.Context()); ok {
+		return string(tenantID), true
+	}
+	return TenantIDFromRequest(m.config.TenantIDHeader, r)
+}
+
+// rejectBandwidth answers a request from a tenant over its bandwidth cap.
+func (m *ReverseProxyModule) rejectBandwidth(w http.ResponseWriter, r *http.Request, limiter *bandwidthLimiter, tenant *tenantBandwidth, wait time.Duration) {
+	tenant.mu.Lock()
+	tenant.rejected++
+	tenant.mu.Unlock()
+
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Debug("Rejected request over tenant bandwidth cap", "tenant", tenant.tenant, "path", r.URL.Path, "retry_after", retryAfter)
+	}
+	m.emitEvent(r.Context(), EventTypeBandwidthRejected, map[string]interface{}{
+		"tenant":      tenant.tenant,
+		"path":        r.URL.Path,
+		"status":      limiter.status,
+		"retry_after": retryAfter,
+	})
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, "tenant bandwidth limit exceeded", limiter.status)
+}
+
+// bandwidthResponseWriter throttles the response bytes written for a tenant.
+type bandwidthResponseWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	limiter *bandwidthLimiter
+	tenant  *tenantBandwidth
+}
+
+func (w *bandwidthResponseWriter) Write(p []byte) (int, error) {
+	written := 0
+	chunk := w.tenant.egress.chunkSize()
+	for len(p) > 0 {
+		n := min(len(p), chunk)
+		wait := w.tenant.egress.take(w.limiter.now(), n)
+		if err := w.limiter.sleep(w.ctx, wait); err != nil {
+			return written, err
+		}
+		n, err := w.ResponseWriter.Write(p[:n])
+		w.tenant.record(n, 0, wait)
+		written += n
+		if err != nil {
+			return written, err //nolint:wrapcheck // passthrough of the underlying writer's error
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *bandwidthResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// bandwidthReadCloser throttles the request body bytes read for a tenant.
+type bandwidthReadCloser struct {
+	io.ReadCloser
+	ctx     context.Context
+	limiter *bandwidthLimiter
+	tenant  *tenantBandwidth
+}
+
+func (b *bandwidthReadCloser) Read(p []byte) (int, error) {
+	if chunk := b.tenant.ingress.chunkSize(); len(p) > chunk {
+		p = p[:chunk]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		wait := b.tenant.ingress.take(b.limiter.now(), n)
+		if sleepErr := b.limiter.sleep(b.ctx, wait); sleepErr != nil {
+			err = sleepErr
+		}
+		b.tenant.record(0, n, wait)
+	}
+	return n, err //nolint:wrapcheck // io.EOF must reach callers unwrapped
+}
@@ -0,0 +1,211 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBandwidthModule returns a module throttling with config whose limiter sleeps
+// by advancing a test clock, and the total time slept.
+func newTestBandwidthModule(t *testing.T, config BandwidthConfig) (*ReverseProxyModule, *capturingSubject, *time.Duration) {
+	t.Helper()
+	config.Enabled = true
+	m, subject := newTestModule(t, &ReverseProxyConfig{TenantIDHeader: "X-Tenant-ID", Bandwidth: config})
+	require.NoError(t, m.config.Bandwidth.validate())
+	m.bandwidth = newBandwidthLimiter(m.config.Bandwidth, m.isRegisteredTenant)
+
+	clock := &sloTestClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	slept := new(time.Duration)
+	m.bandwidth.now = clock.Now
+	m.bandwidth.sleep = func(ctx context.Context, d time.Duration) error {
+		*slept += d
+		clock.Advance(d)
+		return nil
+	}
+	return m, subject, slept
+}
+
+func tenantRequest(method, tenantID string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, "/api/export", body)
+	if tenantID != "" {
+		req.Header.Set("X-Tenant-ID", tenantID)
+	}
+	return req
+}
+
+func TestByteBucket(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := newByteBucket(1000, 500, now)
+
+	assert.Zero(t, b.take(now, 500), "burst is available immediately")
+	assert.Equal(t, time.Second, b.take(now, 1000), "debt is waited out at the rate")
+	assert.InDelta(t, float64(1001*time.Millisecond), float64(b.deficit(now)), float64(time.Microsecond))
+	assert.Zero(t, b.deficit(now.Add(2*time.Second)))
+	assert.Zero(t, b.take(now.Add(time.Hour), 500), "refill is capped at the burst")
+	assert.Equal(t, 100*time.Millisecond, b.take(now.Add(time.Hour), 100))
+
+	assert.Nil(t, newByteBucket(0, 100, now), "zero rate is unthrottled")
+	assert.Equal(t, 2000, newByteBucket(2000, 0, now).chunkSize(), "burst defaults to the rate")
+}
+
+func TestBandwidth_ShapesEgressPerTenant(t *testing.T) {
+	m, _, slept := newTestBandwidthModule(t, BandwidthConfig{
+		Default: BandwidthLimit{EgressBytesPerSecond: 1 << 20},
+		Tenants: map[string]BandwidthLimit{
+			"bulk": {EgressBytesPerSecond: 10000, EgressBurstBytes: 10000},
+		},
+	})
+	m.OnTenantRegistered("interactive")
+	payload := bytes.Repeat([]byte("x"), 100000)
+	handler := m.withBandwidth(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(payload)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "bulk", nil))
+	assert.Equal(t, payload, rec.Body.Bytes())
+	assert.Equal(t, 9*time.Second, *slept, "100KB at 10KB/s after a 10KB burst")
+
+	*slept = 0
+	rec = httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "interactive", nil))
+	assert.Len(t, rec.Body.Bytes(), len(payload))
+	assert.Zero(t, *slept, "other tenants have their own bucket")
+
+	rec = httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "", nil))
+	assert.Len(t, rec.Body.Bytes(), len(payload))
+	assert.Zero(t, *slept, "requests without a tenant are not throttled")
+
+	statuses := m.BandwidthStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "bulk", statuses[0].Tenant)
+	assert.Equal(t, uint64(100000), statuses[0].EgressBytes)
+	assert.InDelta(t, 9, statuses[0].ThrottledSeconds, 0.001)
+	assert.Equal(t, "interactive", statuses[1].Tenant)
+}
+
+func TestBandwidth_ShapesIngress(t *testing.T) {
+	m, _, slept := newTestBandwidthModule(t, BandwidthConfig{
+		Default: BandwidthLimit{IngressBytesPerSecond: 10000},
+	})
+	var received int
+	handler := m.withBandwidth(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received = len(body)
+	})
+
+	handler(httptest.NewRecorder(), tenantRequest(http.MethodPost, "uploader", strings.NewReader(strings.Repeat("x", 30000))))
+	assert.Equal(t, 30000, received)
+	assert.Equal(t, 2*time.Second, *slept)
+	assert.Equal(t, uint64(30000), m.BandwidthStatus()[0].IngressBytes)
+}
+
+func TestBandwidth_RejectMode(t *testing.T) {
+	m, subject, _ := newTestBandwidthModule(t, BandwidthConfig{
+		Mode:         BandwidthModeReject,
+		RejectStatus: http.StatusServiceUnavailable,
+		Default:      BandwidthLimit{EgressBytesPerSecond: 1000},
+	})
+	m.OnTenantRegistered("bulk")
+	m.OnTenantRegistered("interactive")
+	handler := m.withBandwidth(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 3000))
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "bulk", nil))
+	assert.Equal(t, http.StatusOK, rec.Code, "a transfer already under way is shaped, not cut off")
+
+	// The limiter sleeps until the bucket is only just out of debt, so the next
+	// request finds it empty
+	rec = httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "bulk", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	events := subject.eventsOfType(EventTypeBandwidthRejected)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "bulk", data["tenant"])
+	assert.Equal(t, uint64(1), m.BandwidthStatus()[0].Rejected)
+
+	rec = httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, "interactive", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBandwidth_UnknownTenantsShareABucket(t *testing.T) {
+	m, _, slept := newTestBandwidthModule(t, BandwidthConfig{
+		Default: BandwidthLimit{EgressBytesPerSecond: 1000},
+	})
+	m.OnTenantRegistered("acme")
+	handler := m.withBandwidth(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 1000))
+	})
+
+	// Made-up tenant IDs neither get a fresh burst nor grow the tracked tenants
+	for i := range 3 {
+		handler(httptest.NewRecorder(), tenantRequest(http.MethodGet, fmt.Sprintf("made-up-%d", i), nil))
+	}
+	assert.Equal(t, 2*time.Second, *slept)
+	handler(httptest.NewRecorder(), tenantRequest(http.MethodGet, "acme", nil))
+
+	statuses := m.BandwidthStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "acme", statuses[0].Tenant)
+	assert.Equal(t, TenantBandwidthStatus{Tenant: otherTenantsLabel, EgressBytes: 3000, ThrottledSeconds: 2}, statuses[1])
+
+	m.OnTenantRemoved("acme")
+	require.Len(t, m.BandwidthStatus(), 1, "removed tenants are no longer tracked")
+}
+
+func TestBandwidth_PrometheusMetrics(t *testing.T) {
+	m, _, _ := newTestBandwidthModule(t, BandwidthConfig{
+		Default: BandwidthLimit{EgressBytesPerSecond: 1 << 20},
+	})
+	m.OnTenantRegistered("acme")
+	handler := m.withBandwidth(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+	handler(httptest.NewRecorder(), tenantRequest(http.MethodGet, "acme", nil))
+
+	var out bytes.Buffer
+	p := newPrometheusWriter(&out)
+	m.bandwidth.writePrometheus(p)
+	require.NoError(t, p.flush())
+	assert.Contains(t, out.String(), "# TYPE reverseproxy_tenant_egress_bytes_total counter\n")
+	assert.Contains(t, out.String(), `reverseproxy_tenant_egress_bytes_total{tenant="acme"} 5`)
+	assert.Contains(t, out.String(), `reverseproxy_tenant_bandwidth_rejected_total{tenant="acme"} 0`)
+}
+
+func TestBandwidthConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config BandwidthConfig
+	}{
+		{"unknown mode", BandwidthConfig{Mode: "drop"}},
+		{"unsupported status", BandwidthConfig{RejectStatus: http.StatusBadGateway}},
+		{"negative default rate", BandwidthConfig{Default: BandwidthLimit{EgressBytesPerSecond: -1}}},
+		{"negative tenant burst", BandwidthConfig{Tenants: map[string]BandwidthLimit{"acme": {IngressBurstBytes: -1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.config.validate(), ErrInvalidBandwidthConfig)
+		})
+	}
+	valid := BandwidthConfig{Enabled: true, Mode: BandwidthModeReject, RejectStatus: http.StatusTooManyRequests}
+	assert.NoError(t, valid.validate())
+}
@@ -0,0 +1,190 @@
+package reverseproxy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// CacheKeyConfig selects the parts of a request that distinguish cached responses.
+// By default responses are keyed by backend, tenant, method and full URL.
+type CacheKeyConfig struct {
+	// Headers lists request headers whose values become part of the key, e.g.
+	// Accept-Language for localized responses
+	Headers []string `json:"headers" yaml:"headers" toml:"headers" env:"HEADERS"`
+
+	// QueryParams restricts the query parameters in the key to these. Empty keys on
+	// the full query string.
+	QueryParams []string `json:"query_params" yaml:"query_params" toml:"query_params" env:"QUERY_PARAMS"`
+
+	// IgnoreQueryParams drops these query parameters from the key, e.g. tracking
+	// parameters such as utm_source. Can't be combined with QueryParams.
+	IgnoreQueryParams []string `json:"ignore_query_params" yaml:"ignore_query_params" toml:"ignore_query_params" env:"IGNORE_QUERY_PARAMS"`
+
+	// SharedAcrossTenants leaves the tenant out of the key, so tenants share cached
+	// responses. Only enable it for responses that don't depend on the tenant.
+	SharedAcrossTenants bool `json:"shared_across_tenants" yaml:"shared_across_tenants" toml:"shared_across_tenants" env:"SHARED_ACROSS_TENANTS"`
+
+	// IgnoreVary caches responses under the request key alone, ignoring the Vary
+	// header of backend responses
+	IgnoreVary bool `json:"ignore_vary" yaml:"ignore_vary" toml:"ignore_vary" env:"IGNORE_VARY"`
+}
+
+// validate checks that headers and query parameters are named and that the query
+// parameter lists aren't combined.
+func (c *CacheKeyConfig) validate() error {
+	for _, header := range c.Headers {
+		if strings.TrimSpace(header) == "" {
+			return fmt.Errorf("%w: empty header name", ErrInvalidCacheKeyConfig)
+		}
+	}
+	if len(c.QueryParams) > 0 && len(c.IgnoreQueryParams) > 0 {
+		return fmt.Errorf("%w: query_params and ignore_query_params can't be combined", ErrInvalidCacheKeyConfig)
+	}
+	for _, param := range append(slices.Clone(c.QueryParams), c.IgnoreQueryParams...) {
+		if param == "" {
+			return fmt.Errorf("%w: empty query parameter name", ErrInvalidCacheKeyConfig)
+		}
+	}
+	return nil
+}
+
+// isSet reports whether any option is set, so a tenant's configuration replaces the
+// global one.
+func (c *CacheKeyConfig) isSet() bool {
+	return len(c.Headers) > 0 || len(c.QueryParams) > 0 || len(c.IgnoreQueryParams) > 0 || c.SharedAcrossTenants || c.IgnoreVary
+}
+
+// CacheKeyFunc returns the key under which the response of backend to r is cached.
+// Requests with equal keys share cached responses. defaultKey is the key built from
+// the cache_key configuration, so implementations can extend it or return it as is.
+// Backend Vary headers still apply to the returned key.
+type CacheKeyFunc func(r *http.Request, backend, defaultKey string) string
+
+// SetCacheKeyFunc sets the function computing response cache keys, replacing the
+// configured key. A nil function restores the configured key.
+func (m *ReverseProxyModule) SetCacheKeyFunc(fn CacheKeyFunc) {
+	m.cacheKeyFunc = fn
+}
+
+// cacheKeyConfig returns the cache key configuration for a request: that of the most
+// specific matching route that sets one, else the global one.
+func (m *ReverseProxyModule) cacheKeyConfig(r *http.Request, config *ReverseProxyConfig) *CacheKeyConfig {
+	routeConfig, ok := m.routeConfigFor(r.URL.Path, config.RouteConfigs, func(route RouteConfig) bool {
+		return route.CacheKey != nil
+	})
+	if ok {
+		return routeConfig.CacheKey
+	}
+	return &config.CacheKey
+}
+
+// routeConfigFor returns the config of the most specific route matching path among
+// those for which applies reports true. Of equally long patterns, the lexically
+// first wins, so the choice doesn't depend on map order.
+func (m *ReverseProxyModule) routeConfigFor(path string, routes map[string]RouteConfig, applies func(RouteConfig) bool) (RouteConfig, bool) {
+	var best string
+	var selected RouteConfig
+	found := false
+	for pattern, route := range routes {
+		if !applies(route) || !m.matchesRoute(path, pattern) {
+			continue
+		}
+		if !found || len(pattern) > len(best) || (len(pattern) == len(best) && pattern < best) {
+			best, selected, found = pattern, route, true
+		}
+	}
+	return selected, found
+}
+
+// generateCacheKey creates the key for the response of backend to r, before Vary
+// headers are applied.
+func (m *ReverseProxyModule) generateCacheKey(r *http.Request, backend string, keyConfig *CacheKeyConfig) string {
+	var b strings.Builder
+	b.WriteString(backend)
+	b.WriteByte(':')
+	// Include tenant ID in cache key for tenant isolation
+	if !keyConfig.SharedAcrossTenants {
+		tenantIDStr, _ := TenantIDFromRequest(m.config.TenantIDHeader, r)
+		b.WriteString(tenantIDStr)
+	}
+	b.WriteByte(':')
+	b.WriteString(r.Method)
+	b.WriteByte(':')
+	b.WriteString(cacheKeyURL(r.URL, keyConfig))
+	for _, header := range keyConfig.Headers {
+		name := http.CanonicalHeaderKey(strings.TrimSpace(header))
+		fmt.Fprintf(&b, "\n%s=%s", name, strings.Join(r.Header.Values(name), ","))
+	}
+
+	// Hash the key to keep it manageable
+	hash := sha256.Sum256([]byte(b.String()))
+	key := hex.EncodeToString(hash[:])
+	if m.cacheKeyFunc != nil {
+		key = m.cacheKeyFunc(r, backend, key)
+	}
+	return key
+}
+
+// cacheKeyURL returns the URL as it takes part in the key, with the query reduced to
+// the configured parameters.
+func cacheKeyURL(u *url.URL, keyConfig *CacheKeyConfig) string {
+	if len(keyConfig.QueryParams) == 0 && len(keyConfig.IgnoreQueryParams) == 0 {
+		return u.String()
+	}
+	query := u.Query()
+	if len(keyConfig.QueryParams) > 0 {
+		kept := make(url.Values, len(keyConfig.QueryParams))
+		for _, param := range keyConfig.QueryParams {
+			if values, ok := query[param]; ok {
+				kept[param] = values
+			}
+		}
+		query = kept
+	}
+	for _, param := range keyConfig.IgnoreQueryParams {
+		query.Del(param)
+	}
+	keyURL := *u
+	// Encode sorts the parameters, so their order doesn't split the cache
+	keyURL.RawQuery = query.Encode()
+	return keyURL.String()
+}
+
+// responseVary returns the request headers a response varies on, in canonical form
+// and sorted, and whether it may be cached at all, which Vary: * rules out.
+func responseVary(headers http.Header) ([]string, bool) {
+	var vary []string
+	for _, value := range headers.Values("Vary") {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			switch field {
+			case "":
+				continue
+			case "*":
+				return nil, false
+			}
+			vary = append(vary, http.CanonicalHeaderKey(field))
+		}
+	}
+	slices.Sort(vary)
+	return slices.Compact(vary), true
+}
+
+// varyKey returns the key of the variant of baseKey selected by the values the
+// request has for the vary headers.
+func varyKey(baseKey string, vary []string, r *http.Request) string {
+	if len(vary) == 0 {
+		return baseKey
+	}
+	h := sha256.New()
+	_, _ = h.Write([]byte(baseKey)) //nolint:gosec // G705: writing to a hash.Hash (sha256) never returns an error
+	for _, header := range vary {
+		_, _ = fmt.Fprintf(h, "\n%s=%s", header, strings.Join(r.Header.Values(header), ","))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,182 @@
+package reverseproxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCacheKeyTestHandler returns a cached handler answering with the number of backend
+// calls, and a function issuing GET requests against it.
+func newCacheKeyTestHandler(m *ReverseProxyModule, vary string) func(target string, header http.Header) string {
+	var calls atomic.Int32
+	m.responseCache = newResponseCache(time.Minute, 100, time.Hour)
+	handler := m.withCache(func(w http.ResponseWriter, r *http.Request) {
+		if vary != "" {
+			w.Header().Set("Vary", vary)
+		}
+		_, _ = fmt.Fprintf(w, "v%d", calls.Add(1))
+	}, "api")
+
+	return func(target string, header http.Header) string {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Body.String()
+	}
+}
+
+func TestCacheKey_QueryParams(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		CacheEnabled: true,
+		CacheTTL:     time.Minute,
+		CacheKey:     CacheKeyConfig{IgnoreQueryParams: []string{"utm_source"}},
+		RouteConfigs: map[string]RouteConfig{
+			"/search": {CacheKey: &CacheKeyConfig{QueryParams: []string{"q", "page"}}},
+		},
+	}
+	get := newCacheKeyTestHandler(m, "")
+
+	assert.Equal(t, "v1", get("/items?id=1&utm_source=mail", nil))
+	assert.Equal(t, "v1", get("/items?id=1", nil), "ignored parameters don't split the cache")
+	assert.Equal(t, "v2", get("/items?id=2", nil))
+
+	assert.Equal(t, "v3", get("/search?q=go&page=1&session=a", nil))
+	assert.Equal(t, "v3", get("/search?page=1&q=go&session=b", nil), "only listed parameters count, in any order")
+	assert.Equal(t, "v4", get("/search?q=go&page=2", nil))
+}
+
+func TestCacheKey_HeadersAndTenants(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		CacheEnabled:   true,
+		CacheTTL:       time.Minute,
+		TenantIDHeader: "X-Tenant-ID",
+		RouteConfigs: map[string]RouteConfig{
+			"/i18n/*":  {CacheKey: &CacheKeyConfig{Headers: []string{"accept-language"}}},
+			"/public/": {CacheKey: &CacheKeyConfig{SharedAcrossTenants: true}},
+		},
+	}
+	get := newCacheKeyTestHandler(m, "")
+	tenant := func(id string) http.Header { return http.Header{"X-Tenant-Id": {id}} }
+
+	assert.Equal(t, "v1", get("/i18n/home", http.Header{"Accept-Language": {"en"}}))
+	assert.Equal(t, "v2", get("/i18n/home", http.Header{"Accept-Language": {"fr"}}))
+	assert.Equal(t, "v1", get("/i18n/home", http.Header{"Accept-Language": {"en"}}))
+
+	assert.Equal(t, "v3", get("/private", tenant("a")))
+	assert.Equal(t, "v4", get("/private", tenant("b")), "tenants are partitioned by default")
+	assert.Equal(t, "v5", get("/public/", tenant("a")))
+	assert.Equal(t, "v5", get("/public/", tenant("b")), "shared routes serve every tenant")
+}
+
+func TestCacheKey_Vary(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{CacheEnabled: true, CacheTTL: time.Minute}
+	get := newCacheKeyTestHandler(m, "Accept-Encoding, accept")
+
+	assert.Equal(t, "v1", get("/items", http.Header{"Accept-Encoding": {"gzip"}}))
+	assert.Equal(t, "v2", get("/items", http.Header{"Accept-Encoding": {"br"}}))
+	assert.Equal(t, "v1", get("/items", http.Header{"Accept-Encoding": {"gzip"}}))
+	assert.Equal(t, "v2", get("/items", http.Header{"Accept-Encoding": {"br"}}))
+	assert.Equal(t, "v3", get("/items", http.Header{"Accept-Encoding": {"gzip"}, "Accept": {"text/html"}}))
+
+	m.config.CacheKey.IgnoreVary = true
+	get = newCacheKeyTestHandler(m, "Accept-Encoding")
+	assert.Equal(t, "v1", get("/items", http.Header{"Accept-Encoding": {"gzip"}}))
+	assert.Equal(t, "v1", get("/items", http.Header{"Accept-Encoding": {"br"}}))
+}
+
+func TestCacheKey_VaryStarIsNotCached(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{CacheEnabled: true, CacheTTL: time.Minute}
+	get := newCacheKeyTestHandler(m, "*")
+
+	assert.Equal(t, "v1", get("/items", nil))
+	assert.Equal(t, "v2", get("/items", nil))
+	assert.Empty(t, m.responseCache.cache)
+}
+
+func TestCacheKey_CacheKeyFunc(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{CacheEnabled: true, CacheTTL: time.Minute}
+	m.SetCacheKeyFunc(func(r *http.Request, backend, defaultKey string) string {
+		return backend + ":" + r.URL.Path
+	})
+	get := newCacheKeyTestHandler(m, "")
+
+	assert.Equal(t, "v1", get("/items?page=1", nil))
+	assert.Equal(t, "v1", get("/items?page=2", nil))
+	assert.Contains(t, m.responseCache.cache, "api:/items")
+}
+
+func TestResponseCache_CleanupDropsUnusedVary(t *testing.T) {
+	rc := newResponseCache(time.Minute, 100, time.Hour)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	key := varyKey("base", []string{"Accept"}, req)
+	rc.Set(key, http.StatusOK, nil, []byte("body"), time.Minute)
+	rc.setVary("base", []string{"Accept"}, key)
+	rc.setVary("gone", []string{"Accept"}, "missing")
+
+	rc.cleanup()
+	assert.Equal(t, map[string][]string{"base": {"Accept"}}, rc.vary)
+	assert.Equal(t, key, rc.variantKey("base", req))
+}
+
+func TestCacheKeyConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  CacheKeyConfig
+		wantErr bool
+	}{
+		{"empty", CacheKeyConfig{}, false},
+		{"valid", CacheKeyConfig{Headers: []string{"Accept-Language"}, QueryParams: []string{"q"}}, false},
+		{"empty header", CacheKeyConfig{Headers: []string{" "}}, true},
+		{"empty query param", CacheKeyConfig{IgnoreQueryParams: []string{""}}, true},
+		{"both query lists", CacheKeyConfig{QueryParams: []string{"q"}, IgnoreQueryParams: []string{"utm"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCacheKeyConfig)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCacheKeyURL(t *testing.T) {
+	u, _ := url.Parse("/a?b=2&a=1&utm=x")
+	assert.Equal(t, "/a?b=2&a=1&utm=x", cacheKeyURL(u, &CacheKeyConfig{}))
+	assert.Equal(t, "/a?a=1&b=2", cacheKeyURL(u, &CacheKeyConfig{IgnoreQueryParams: []string{"utm"}}))
+	assert.Equal(t, "/a?a=1", cacheKeyURL(u, &CacheKeyConfig{QueryParams: []string{"a", "c"}}))
+}
+
+func TestCacheKeyConfig_MostSpecificRoute(t *testing.T) {
+	m := NewModule()
+	api := &CacheKeyConfig{Headers: []string{"Accept"}}
+	search := &CacheKeyConfig{QueryParams: []string{"q"}}
+	config := &ReverseProxyConfig{RouteConfigs: map[string]RouteConfig{
+		"/api/*":        {CacheKey: api},
+		"/api/search/*": {CacheKey: search},
+		"/api/search":   {Timeout: time.Second},
+	}}
+
+	for range 20 {
+		assert.Same(t, search, m.cacheKeyConfig(httptest.NewRequest(http.MethodGet, "/api/search/items", nil), config))
+	}
+	assert.Same(t, api, m.cacheKeyConfig(httptest.NewRequest(http.MethodGet, "/api/users", nil), config))
+	assert.Same(t, &config.CacheKey, m.cacheKeyConfig(httptest.NewRequest(http.MethodGet, "/other", nil), config))
+}
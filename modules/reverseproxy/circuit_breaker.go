@@ -194,8 +194,10 @@ func (cb *CircuitBreaker) Execute(req *http.Request, fn func(*http.Request) (*ht
 	initialState := cb.GetState()
 
 	// Create a context with timeout
-	ctx := req.Context()
-	if cb.requestTimeout > 0 {
+	clientCtx := req.Context()
+	ctx := clientCtx
+	// Streaming uploads are bounded by their own deadline
+	if _, streaming := streamingUpload(clientCtx); cb.requestTimeout > 0 && !streaming {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(ctx, cb.requestTimeout)
 		defer cancel()
@@ -205,6 +207,12 @@ func (cb *CircuitBreaker) Execute(req *http.Request, fn func(*http.Request) (*ht
 	req = req.WithContext(ctx)
 	resp, err := fn(req)
 
+	// A client disconnect says nothing about backend health, so it is left out of
+	// request metrics and failure accounting
+	if clientAborted(clientCtx) {
+		return resp, err
+	}
+
 	// Record metrics
 	var statusCode int
 	if resp != nil {
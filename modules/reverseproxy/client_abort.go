@@ -0,0 +1,68 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// StatusClientClosedRequest is the non-standard status recorded when the client
+// disconnects before the backend responds. It is never seen by the client but keeps
+// abandoned requests out of 5xx statistics and circuit breaker failure counts.
+const StatusClientClosedRequest = 499
+
+// inboundContextKey is the context key under which the context of the client's
+// request is stored.
+type inboundContextKey struct{}
+
+// withInboundContext records the context of the client's request r in the contexts
+// the proxy derives from it, for clientAborted. A context recorded by an outer
+// handler is kept.
+func withInboundContext(r *http.Request) *http.Request {
+	if _, ok := r.Context().Value(inboundContextKey{}).(context.Context); ok {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), inboundContextKey{}, r.Context()))
+}
+
+// clientAborted reports whether ctx ended because the client went away, which is
+// when the context of the client's request recorded by withInboundContext was
+// cancelled. Contexts the proxy cancels itself, like the upstream context of a
+// finished attempt, don't count. Without a recorded request context, ctx is taken
+// as the client's.
+func clientAborted(ctx context.Context) bool {
+	inbound, ok := ctx.Value(inboundContextKey{}).(context.Context)
+	if !ok {
+		inbound = ctx
+	}
+	return errors.Is(inbound.Err(), context.Canceled)
+}
+
+// recordClientAbort reports a request the client abandoned before the backend
+// responded. These are counted separately from backend errors.
+func (m *ReverseProxyModule) recordClientAbort(r *http.Request, backend string, tenantID modular.TenantID, start time.Time) {
+	elapsed := time.Since(start)
+
+	if m.metrics != nil {
+		m.metrics.RecordClientAbort(backend)
+	}
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Debug("Client aborted request",
+			"backend", backend, "tenant_hash", obfuscateTenantID(tenantID),
+			"path", sanitizeForLogging(r.URL.Path), "elapsed", elapsed.String())
+	}
+
+	data := map[string]interface{}{
+		"backend":    backend,
+		"method":     r.Method,
+		"path":       r.URL.Path,
+		"elapsed_ms": elapsed.Milliseconds(),
+	}
+	if tenantID != "" {
+		data["tenant"] = string(tenantID)
+	}
+	m.emitEvent(context.WithoutCancel(r.Context()), EventTypeRequestClientAborted, data)
+}
@@ -0,0 +1,142 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClientAbortTestModule creates a module proxying to a backend that only returns
+// once the upstream request is cancelled, reporting when that happens on cancelled.
+func newClientAbortTestModule(t *testing.T, circuitBreaker bool) (*ReverseProxyModule, *capturingSubject, <-chan struct{}) {
+	t.Helper()
+
+	cancelled := make(chan struct{}, 1)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			cancelled <- struct{}{}
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  10 * time.Second,
+		CircuitBreakerConfig: CircuitBreakerConfig{
+			Enabled:          circuitBreaker,
+			FailureThreshold: 1,
+			OpenTimeout:      time.Minute,
+		},
+	})
+	m.metrics = NewMetricsCollector()
+	return m, subject, cancelled
+}
+
+// serveAndAbort serves a request whose client disconnects after a short delay
+func serveAndAbort(t *testing.T, handler http.HandlerFunc) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil).WithContext(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(httptest.NewRecorder(), req)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("handler did not return after the client disconnected")
+	}
+}
+
+func TestClientAbort_ExcludedFromCircuitBreakerAndErrors(t *testing.T) {
+	m, subject, cancelled := newClientAbortTestModule(t, true)
+
+	serveAndAbort(t, m.createBackendProxyHandler("api"))
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream request was not cancelled")
+	}
+
+	cb := m.circuitBreakers["api"]
+	require.NotNil(t, cb)
+	assert.Equal(t, StateClosed, cb.GetState())
+	assert.Zero(t, cb.GetFailureCount())
+
+	metrics := m.metrics.GetMetrics()
+	assert.Equal(t, 0, metrics["total_requests"])
+	assert.Equal(t, 1, metrics["total_client_aborts"])
+	backendMetrics := metrics["backends"].(map[string]interface{})["api"].(map[string]interface{})
+	assert.Equal(t, 1, backendMetrics["client_aborts"])
+
+	assert.Len(t, subject.eventsOfType(EventTypeRequestClientAborted), 1)
+	assert.Empty(t, subject.eventsOfType(EventTypeRequestFailed))
+}
+
+func TestClientAbort_WithoutCircuitBreaker(t *testing.T) {
+	m, subject, cancelled := newClientAbortTestModule(t, false)
+
+	serveAndAbort(t, m.createBackendProxyHandler("api"))
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("upstream request was not cancelled")
+	}
+
+	events := subject.eventsOfType(EventTypeRequestClientAborted)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "api", data["backend"])
+	assert.Equal(t, "/api/users", data["path"])
+	assert.Empty(t, subject.eventsOfType(EventTypeRequestFailed))
+	assert.Equal(t, 1, m.metrics.GetMetrics()["total_client_aborts"])
+}
+
+func TestCircuitBreaker_ExecuteIgnoresClientAbort(t *testing.T) {
+	cb := NewCircuitBreaker("api", NewMetricsCollector()).WithFailureThreshold(1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+
+	_, err := cb.Execute(req, func(req *http.Request) (*http.Response, error) {
+		return nil, req.Context().Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, StateClosed, cb.GetState())
+	assert.Zero(t, cb.GetFailureCount())
+	assert.Equal(t, 0, cb.metricsCollector.GetMetrics()["total_requests"])
+}
+
+func TestClientAborted_ChecksTheClientRequest(t *testing.T) {
+	req := withInboundContext(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// The proxy cancelling a context it derived is not a client abort
+	upstream, cancel := context.WithCancel(req.Context())
+	cancel()
+	assert.False(t, clientAborted(upstream))
+
+	clientCtx, disconnect := context.WithCancel(context.Background())
+	req = withInboundContext(req.WithContext(clientCtx))
+	assert.Same(t, req, withInboundContext(req), "the outer handler's request context is kept")
+	upstream, cancel = context.WithCancel(req.Context())
+	defer cancel()
+	disconnect()
+	assert.True(t, clientAborted(upstream))
+}
@@ -55,6 +55,8 @@ type CompositeHandler struct {
 	responseCache       *responseCache
 	eventEmitter        func(eventType string, data map[string]interface{})
 	responseTransformer ResponseTransformer
+
+	responseTransformerV2 ResponseTransformerV2
 }
 
 // NewCompositeHandler creates a new composite handler with the given backends and strategy.
@@ -121,9 +123,18 @@ func (h *CompositeHandler) SetResponseTransformer(transformer ResponseTransforme
 	h.responseTransformer = transformer
 }
 
+// SetResponseTransformerV2 sets a custom response transformer function that also
+// receives the TransformContext of the request. It takes precedence over a
+// transformer set with SetResponseTransformer.
+func (h *CompositeHandler) SetResponseTransformerV2(transformer ResponseTransformerV2) {
+	h.responseTransformerV2 = transformer
+}
+
 // ServeHTTP handles the request by forwarding it to all backends
 // and merging the responses.
 func (h *CompositeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = withInboundContext(r)
+
 	// Try to get response from cache first if caching is enabled.
 	if h.responseCache != nil && r.Method == http.MethodGet {
 		cacheKey := h.responseCache.GenerateKey(r)
@@ -226,7 +237,8 @@ func (h *CompositeHandler) executeFirstSuccess(ctx context.Context, w http.Respo
 		// Execute the request.
 		resp, err := h.executeBackendRequest(ctx, backend, r, bodyBytes) //nolint:bodyclose // Response body is closed after writing
 		if err != nil {
-			if circuitBreaker != nil {
+			// Client disconnects are not held against the backend
+			if circuitBreaker != nil && !clientAborted(ctx) {
 				circuitBreaker.RecordFailure()
 			}
 			continue
@@ -263,6 +275,7 @@ func (h *CompositeHandler) executeMerge(ctx context.Context, w http.ResponseWrit
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	responses := make(map[string]*http.Response)
+	tc := h.transformContextFor(r)
 
 	// Create a wait group to track each backend request.
 	for _, backend := range h.backends {
@@ -272,15 +285,24 @@ func (h *CompositeHandler) executeMerge(ctx context.Context, w http.ResponseWrit
 			circuitBreaker := h.circuitBreakers[b.ID]
 			if circuitBreaker != nil && circuitBreaker.IsOpen() {
 				// Circuit is open, skip this backend.
+				mu.Lock()
+				tc.Backends[b.ID] = BackendResult{Skipped: true}
+				mu.Unlock()
 				return
 			}
 
 			// Execute the request.
+			start := time.Now()
 			resp, err := h.executeBackendRequest(ctx, b, r, bodyBytes) //nolint:bodyclose // Response body is closed in mergeResponses cleanup
+			result := BackendResult{Latency: time.Since(start), Err: err}
 			if err != nil {
-				if circuitBreaker != nil {
+				// Client disconnects are not held against the backend
+				if circuitBreaker != nil && !clientAborted(ctx) {
 					circuitBreaker.RecordFailure()
 				}
+				mu.Lock()
+				tc.Backends[b.ID] = result
+				mu.Unlock()
 				return
 			}
 
@@ -290,8 +312,10 @@ func (h *CompositeHandler) executeMerge(ctx context.Context, w http.ResponseWrit
 			}
 
 			// Store the response.
+			result.StatusCode = resp.StatusCode
 			mu.Lock()
 			responses[b.ID] = resp
+			tc.Backends[b.ID] = result
 			mu.Unlock()
 		})
 	}
@@ -300,8 +324,14 @@ func (h *CompositeHandler) executeMerge(ctx context.Context, w http.ResponseWrit
 	wg.Wait()
 
 	// If custom transformer is set, use it
-	if h.responseTransformer != nil {
-		transformedResp, err := h.responseTransformer(responses)
+	if h.responseTransformerV2 != nil || h.responseTransformer != nil {
+		var transformedResp *http.Response
+		var err error
+		if h.responseTransformerV2 != nil {
+			transformedResp, err = h.responseTransformerV2(tc, responses)
+		} else {
+			transformedResp, err = h.responseTransformer(responses)
+		}
 		if err == nil && transformedResp != nil {
 			h.writeResponse(transformedResp, w)
 			transformedResp.Body.Close()
@@ -338,7 +368,8 @@ func (h *CompositeHandler) executeSequential(ctx context.Context, w http.Respons
 		// Execute the request.
 		resp, err := h.executeBackendRequest(ctx, backend, r, bodyBytes) //nolint:bodyclose // Response body is closed after use
 		if err != nil {
-			if circuitBreaker != nil {
+			// Client disconnects are not held against the backend
+			if circuitBreaker != nil && !clientAborted(ctx) {
 				circuitBreaker.RecordFailure()
 			}
 			continue
@@ -488,11 +519,7 @@ func (m *ReverseProxyModule) createCompositeHandler(ctx context.Context, routeCo
 		}
 
 		// Add to backends list
-		backends = append(backends, &Backend{
-			ID:     backendName,
-			URL:    backendURL,
-			Client: m.httpClient, // Use the module's HTTP client directly
-		})
+		backends = append(backends, m.newCompositeBackend(backendName, backendURL))
 	}
 
 	// Determine the strategy to use
@@ -535,6 +562,9 @@ func (m *ReverseProxyModule) createCompositeHandler(ctx context.Context, routeCo
 	if transformer, exists := m.responseTransformers[routeConfig.Pattern]; exists {
 		handler.SetResponseTransformer(transformer)
 	}
+	if transformer, exists := m.responseTransformersV2[routeConfig.Pattern]; exists {
+		handler.SetResponseTransformerV2(transformer)
+	}
 
 	return handler, nil
 }
@@ -595,6 +625,11 @@ func (m *ReverseProxyModule) createFeatureFlagAwareCompositeHandlerFunc(ctx cont
 		}
 
 		// Feature flag is enabled or not specified, proceed with composite logic
-		compositeHandler.ServeHTTP(w, r)
+		flags := make(map[string]bool)
+		if routeConfig.FeatureFlagID != "" {
+			flags[routeConfig.FeatureFlagID] = true
+		}
+		tc := m.newTransformContext(r, routeConfig.Pattern, flags, routeConfig.Backends, routeConfig.Metadata)
+		compositeHandler.ServeHTTP(w, withTransformContext(r, tc))
 	}, nil
 }
@@ -0,0 +1,391 @@
+package reverseproxy
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings the proxy can compress responses with.
+const (
+	CompressionGzip   = "gzip"
+	CompressionBrotli = "br"
+)
+
+// defaultCompressionMinSize is the smallest response body compressed when MinSize is unset.
+const defaultCompressionMinSize = 1024
+
+var (
+	// defaultCompressionAlgorithms lists the codings used when Algorithms is unset, most preferred first
+	defaultCompressionAlgorithms = []string{CompressionBrotli, CompressionGzip}
+
+	// defaultCompressibleContentTypes lists the media types compressed when ContentTypes is unset
+	defaultCompressibleContentTypes = []string{
+		"text/*",
+		"application/json",
+		"application/*+json",
+		"application/javascript",
+		"application/xml",
+		"application/*+xml",
+		"image/svg+xml",
+	}
+)
+
+// CompressionConfig configures compression of responses sent to clients. The proxy
+// compresses a response with the most preferred algorithm the client accepts, if
+// its content type is allowed and its body reaches MinSize. Responses the backend
+// already encoded pass through unchanged.
+//
+//	compression:
+//	  enabled: true
+//	  algorithms: ["br", "gzip"]
+//	  min_size: 1024
+//	  content_types: ["text/*", "application/json"]
+type CompressionConfig struct {
+	// Enabled turns on response compression
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"ENABLED"`
+
+	// Algorithms lists the codings to use, most preferred first: "br" and "gzip". Defaults to both, brotli first.
+	Algorithms []string `json:"algorithms" yaml:"algorithms" toml:"algorithms" env:"ALGORITHMS"`
+
+	// MinSize is the smallest response body in bytes worth compressing. Zero uses 1024.
+	MinSize int `json:"min_size" yaml:"min_size" toml:"min_size" env:"MIN_SIZE"`
+
+	// ContentTypes lists the media types to compress. Entries may use wildcards such as
+	// "text/*" or "application/*+json". Defaults to common text, JSON, JavaScript and XML types.
+	ContentTypes []string `json:"content_types" yaml:"content_types" toml:"content_types" env:"CONTENT_TYPES"`
+}
+
+// validate checks the minimum size, algorithms and content type patterns.
+func (c *CompressionConfig) validate() error {
+	if c.MinSize < 0 {
+		return fmt.Errorf("%w: min_size %d is negative", ErrInvalidCompressionConfig, c.MinSize)
+	}
+	for _, algorithm := range c.Algorithms {
+		if algorithm != CompressionGzip && algorithm != CompressionBrotli {
+			return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidCompressionConfig, algorithm)
+		}
+	}
+	for _, contentType := range c.ContentTypes {
+		if _, err := path.Match(contentType, ""); err != nil {
+			return fmt.Errorf("%w: content type pattern %q: %w", ErrInvalidCompressionConfig, contentType, err)
+		}
+	}
+	return nil
+}
+
+// compressionPolicy is a CompressionConfig with defaults applied.
+type compressionPolicy struct {
+	algorithms   []string
+	minSize      int
+	contentTypes []string
+}
+
+// compressionPolicy returns the compression settings for pattern: the route's own
+// compression config if it has one, otherwise the global one. It returns nil when
+// compression is disabled for the route.
+func (m *ReverseProxyModule) compressionPolicy(pattern string) *compressionPolicy {
+	if m.config == nil {
+		return nil
+	}
+	config := m.config.Compression
+	if routeConfig, ok := m.config.RouteConfigs[pattern]; ok && routeConfig.Compression != nil {
+		config = *routeConfig.Compression
+	}
+	if !config.Enabled {
+		return nil
+	}
+
+	policy := &compressionPolicy{
+		algorithms:   config.Algorithms,
+		minSize:      config.MinSize,
+		contentTypes: config.ContentTypes,
+	}
+	if len(policy.algorithms) == 0 {
+		policy.algorithms = defaultCompressionAlgorithms
+	}
+	if policy.minSize == 0 {
+		policy.minSize = defaultCompressionMinSize
+	}
+	if len(policy.contentTypes) == 0 {
+		policy.contentTypes = defaultCompressibleContentTypes
+	}
+	return policy
+}
+
+// allowsContentType reports whether responses of contentType may be compressed.
+func (p *compressionPolicy) allowsContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range p.contentTypes {
+		if matched, _ := path.Match(strings.ToLower(pattern), mediaType); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// withCompression wraps handler to compress its responses as configured for pattern.
+func (m *ReverseProxyModule) withCompression(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	policy := m.compressionPolicy(pattern)
+	if policy == nil || handler == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		encoding := negotiateEncoding(strings.Join(r.Header.Values("Accept-Encoding"), ","), policy.algorithms)
+		if encoding == "" || r.Method == http.MethodHead {
+			handler(w, r)
+			return
+		}
+
+		writer := &compressResponseWriter{ResponseWriter: w, policy: policy, encoding: encoding}
+		handler(writer, r)
+		if err := writer.Close(); err != nil && m.app != nil {
+			m.app.Logger().Debug("Failed to finish compressed response", "route", pattern, "error", err)
+		}
+	}
+}
+
+// negotiateEncoding returns the first of algorithms with the highest quality in an
+// Accept-Encoding header, or "" when the client accepts none of them.
+func negotiateEncoding(acceptEncoding string, algorithms []string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, entry := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(entry, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.EqualFold(key, "q") {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		accepted[name] = quality
+	}
+
+	best, bestQuality := "", 0.0
+	for _, algorithm := range algorithms {
+		quality, ok := accepted[algorithm]
+		if !ok {
+			quality = accepted["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = algorithm, quality
+		}
+	}
+	return best
+}
+
+// compressEncoder is a streaming encoder for one content coding.
+type compressEncoder interface {
+	io.WriteCloser
+	Flush() error
+}
+
+func newCompressEncoder(encoding string, w io.Writer) compressEncoder {
+	if encoding == CompressionBrotli {
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression)
+	}
+	gz, _ := gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	return gz
+}
+
+type compressState int
+
+const (
+	compressUndecided compressState = iota
+	compressPassthrough
+	compressActive
+)
+
+// compressResponseWriter buffers the start of a response until it knows whether to
+// compress it: the headers must allow it and the body must reach the minimum size.
+// Responses that don't qualify are written through unchanged.
+type compressResponseWriter struct {
+	http.ResponseWriter
+	policy   *compressionPolicy
+	encoding string
+
+	state   compressState
+	status  int
+	buf     []byte
+	encoder compressEncoder
+}
+
+func (w *compressResponseWriter) WriteHeader(status int) {
+	if w.state != compressUndecided {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if status < http.StatusOK {
+		// Informational responses, including protocol upgrades, go out as they are
+		if status == http.StatusSwitchingProtocols {
+			w.state = compressPassthrough
+		}
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+	if !w.headersAllowCompression() {
+		_ = w.passthrough()
+	}
+}
+
+func (w *compressResponseWriter) Write(p []byte) (int, error) {
+	if w.state == compressUndecided && w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	switch w.state {
+	case compressPassthrough:
+		return w.ResponseWriter.Write(p) //nolint:wrapcheck // passthrough of the underlying writer's error
+	case compressActive:
+		return w.encoder.Write(p) //nolint:wrapcheck // passthrough of the encoder's error
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.policy.minSize {
+		if err := w.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush sends everything written so far. A response still below the minimum size
+// is sent uncompressed, so streamed responses are never held back.
+func (w *compressResponseWriter) Flush() {
+	switch w.state {
+	case compressUndecided:
+		if w.status == 0 {
+			w.status = http.StatusOK
+		}
+		_ = w.passthrough()
+	case compressActive:
+		_ = w.encoder.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close writes out a buffered response too small to compress, or finishes the
+// compressed stream.
+func (w *compressResponseWriter) Close() error {
+	switch w.state {
+	case compressUndecided:
+		if w.status == 0 && len(w.buf) == 0 {
+			return nil
+		}
+		return w.passthrough()
+	case compressActive:
+		if err := w.encoder.Close(); err != nil {
+			return fmt.Errorf("failed to close %s encoder: %w", w.encoding, err)
+		}
+	}
+	return nil
+}
+
+// headersAllowCompression reports whether the status and headers allow compressing the response.
+func (w *compressResponseWriter) headersAllowCompression() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	h := w.Header()
+	// The backend already encoded the response
+	if encoding := h.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+	if h.Get("Content-Range") != "" {
+		return false
+	}
+	if strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+	if length, err := strconv.Atoi(h.Get("Content-Length")); err == nil && length < w.policy.minSize {
+		return false
+	}
+	if contentType := h.Get("Content-Type"); contentType != "" && !w.policy.allowsContentType(contentType) {
+		return false
+	}
+	return true
+}
+
+// startCompression sends the headers for a compressed response and compresses the buffered body.
+func (w *compressResponseWriter) startCompression() error {
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+		if !w.policy.allowsContentType(h.Get("Content-Type")) {
+			return w.passthrough()
+		}
+	}
+
+	h.Del("Content-Length")
+	h.Set("Content-Encoding", w.encoding)
+	addVary(h, "Accept-Encoding")
+	// A strong ETag no longer identifies the encoded representation
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	w.state = compressActive
+	w.encoder = newCompressEncoder(w.encoding, w.ResponseWriter)
+	buf := w.buf
+	w.buf = nil
+	if _, err := w.encoder.Write(buf); err != nil {
+		return fmt.Errorf("failed to compress response: %w", err)
+	}
+	return nil
+}
+
+// passthrough sends the headers and any buffered body unchanged.
+func (w *compressResponseWriter) passthrough() error {
+	w.state = compressPassthrough
+	w.ResponseWriter.WriteHeader(w.status)
+	if len(w.buf) == 0 {
+		return nil
+	}
+	buf := w.buf
+	w.buf = nil
+	if _, err := w.ResponseWriter.Write(buf); err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// addVary adds field to the Vary header unless it is already listed.
+func addVary(h http.Header, field string) {
+	for _, value := range h.Values("Vary") {
+		for _, existing := range strings.Split(value, ",") {
+			if existing = strings.TrimSpace(existing); existing == "*" || strings.EqualFold(existing, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
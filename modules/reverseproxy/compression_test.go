@@ -0,0 +1,168 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	algorithms := []string{CompressionBrotli, CompressionGzip}
+	tests := []struct {
+		acceptEncoding string
+		want           string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", CompressionGzip},
+		{"gzip, deflate, br", CompressionBrotli},
+		{"br;q=0.5, gzip", CompressionGzip},
+		{"br;q=0, gzip;q=0.1", CompressionGzip},
+		{"*", CompressionBrotli},
+		{"*;q=0.5, br;q=0", CompressionGzip},
+		{"GZIP;Q=1", CompressionGzip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptEncoding, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateEncoding(tt.acceptEncoding, algorithms))
+		})
+	}
+}
+
+func TestCompression_ThroughProxy(t *testing.T) {
+	large := strings.Repeat(`{"message":"hello"}`, 200)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/small":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		case "/api/image":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte(large))
+		case "/api/encoded":
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Set("Content-Encoding", CompressionGzip)
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			_, _ = gz.Write([]byte(large))
+			_ = gz.Close()
+			_, _ = w.Write(buf.Bytes())
+		default:
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(large))
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  10 * time.Second,
+		Compression:     CompressionConfig{Enabled: true},
+		RouteConfigs: map[string]RouteConfig{
+			"/raw/*": {Compression: &CompressionConfig{Enabled: false}},
+		},
+	})
+	handler := m.withCompression("/api/*", m.createBackendProxyHandler("api"))
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec
+	}
+
+	t.Run("brotli preferred", func(t *testing.T) {
+		rec := get("/api/data", "gzip, br")
+		assert.Equal(t, CompressionBrotli, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+		assert.Empty(t, rec.Header().Get("Content-Length"))
+		body, err := io.ReadAll(brotli.NewReader(rec.Body))
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		rec := get("/api/data", "gzip")
+		assert.Equal(t, CompressionGzip, rec.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	})
+
+	t.Run("client without compression support", func(t *testing.T) {
+		rec := get("/api/data", "")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, rec.Body.String())
+	})
+
+	t.Run("below minimum size", func(t *testing.T) {
+		rec := get("/api/small", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"ok":true}`, rec.Body.String())
+	})
+
+	t.Run("content type not allowed", func(t *testing.T) {
+		rec := get("/api/image", "gzip")
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, large, rec.Body.String())
+	})
+
+	t.Run("already compressed by backend", func(t *testing.T) {
+		rec := get("/api/encoded", "gzip, br")
+		assert.Equal(t, CompressionGzip, rec.Header().Get("Content-Encoding"))
+		reader, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, large, string(body), "the backend's encoding must not be compressed twice")
+	})
+
+	t.Run("disabled for route", func(t *testing.T) {
+		assert.Nil(t, m.compressionPolicy("/raw/*"))
+		assert.NotNil(t, m.compressionPolicy("/api/*"))
+	})
+}
+
+func TestCompression_FlushSendsSmallResponsesUncompressed(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{Compression: CompressionConfig{Enabled: true, MinSize: 100}}
+	handler := m.withCompression("/events", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte("first"))
+		require.NoError(t, http.NewResponseController(w).Flush())
+		_, _ = w.Write([]byte(strings.Repeat(" more", 100)))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	assert.True(t, rec.Flushed)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "first more"))
+}
+
+func TestCompressionConfig_Validate(t *testing.T) {
+	require.NoError(t, (&CompressionConfig{Algorithms: []string{CompressionGzip, CompressionBrotli}}).validate())
+	require.ErrorIs(t, (&CompressionConfig{Algorithms: []string{"deflate"}}).validate(), ErrInvalidCompressionConfig)
+	require.ErrorIs(t, (&CompressionConfig{MinSize: -1}).validate(), ErrInvalidCompressionConfig)
+	require.ErrorIs(t, (&CompressionConfig{ContentTypes: []string{"text/["}}).validate(), ErrInvalidCompressionConfig)
+}
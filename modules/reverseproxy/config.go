@@ -53,6 +53,69 @@ type ReverseProxyConfig struct {
 
 	// Error handling configuration
 	ErrorHandling ErrorHandlingConfig `json:"error_handling" yaml:"error_handling" toml:"error_handling"`
+
+	// Gateway timeout reporting configuration
+	TimeoutResponse TimeoutResponseConfig `json:"timeout_response" yaml:"timeout_response" toml:"timeout_response"`
+
+	// BackendDrainTimeout bounds how long RemoveBackend waits for in-flight requests. Default 30s.
+	BackendDrainTimeout time.Duration `json:"backend_drain_timeout" yaml:"backend_drain_timeout" toml:"backend_drain_timeout" env:"BACKEND_DRAIN_TIMEOUT"`
+
+	// Maintenance answers requests to selected backends, routes or tenants with a 503
+	Maintenance MaintenanceConfig `json:"maintenance" yaml:"maintenance" toml:"maintenance"`
+
+	// EventSampling thins out high-volume events such as request.received
+	EventSampling EventSamplingConfig `json:"event_sampling" yaml:"event_sampling" toml:"event_sampling"`
+
+	// CacheStaleWhileRevalidate is how long after expiry a cached response is still served
+	// while a single background request refreshes it. Zero disables it.
+	CacheStaleWhileRevalidate time.Duration `json:"cache_stale_while_revalidate" yaml:"cache_stale_while_revalidate" toml:"cache_stale_while_revalidate" env:"CACHE_STALE_WHILE_REVALIDATE"`
+
+	// CacheStaleIfError is how long after expiry a cached response is served in place of a
+	// 5xx response, including circuit-open responses. Zero disables it.
+	CacheStaleIfError time.Duration `json:"cache_stale_if_error" yaml:"cache_stale_if_error" toml:"cache_stale_if_error" env:"CACHE_STALE_IF_ERROR"`
+
+	// CacheKey selects the request headers and query parameters that distinguish cached
+	// responses, and whether tenants share them
+	CacheKey CacheKeyConfig `json:"cache_key" yaml:"cache_key" toml:"cache_key"`
+
+	// Compression configures gzip and brotli compression of responses sent to clients
+	Compression CompressionConfig `json:"compression" yaml:"compression" toml:"compression"`
+
+	// ScheduledRoutes reroute matching requests to another backend during time windows
+	ScheduledRoutes []ScheduledRouteConfig `json:"scheduled_routes" yaml:"scheduled_routes" toml:"scheduled_routes"`
+
+	// Prewarm opens idle connections to backends during Start
+	Prewarm PrewarmConfig `json:"prewarm" yaml:"prewarm" toml:"prewarm"`
+
+	// Readiness holds back the proxy's readiness, and optionally Start and routes,
+	// until critical backends are healthy
+	Readiness ReadinessConfig `json:"readiness" yaml:"readiness" toml:"readiness"`
+
+	// OpenAPI serves one OpenAPI document merged from the backends' specs
+	OpenAPI OpenAPIConfig `json:"openapi" yaml:"openapi" toml:"openapi"`
+
+	// SLO tracks availability and latency objectives per backend and route
+	SLO SLOConfig `json:"slo" yaml:"slo" toml:"slo"`
+
+	// BackendURLResolution configures caching and error handling of the BackendURLResolver
+	BackendURLResolution BackendURLResolutionConfig `json:"backend_url_resolution" yaml:"backend_url_resolution" toml:"backend_url_resolution"`
+
+	// Bandwidth caps the request and response bytes each tenant may transfer per second
+	Bandwidth BandwidthConfig `json:"bandwidth" yaml:"bandwidth" toml:"bandwidth"`
+
+	// RateLimit limits the requests each tenant may make per window, optionally
+	// shared across replicas through a RateLimitStore
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" toml:"rate_limit"`
+
+	// TenantOnboarding configures the HTTP API for registering tenants at runtime
+	TenantOnboarding TenantOnboardingConfig `json:"tenant_onboarding" yaml:"tenant_onboarding" toml:"tenant_onboarding"`
+
+	// FaultInjection disturbs a percentage of backend requests for chaos testing,
+	// outside production
+	FaultInjection FaultInjectionConfig `json:"fault_injection" yaml:"fault_injection" toml:"fault_injection"`
+
+	// Locality makes backend groups prefer backends in the proxy's zone and region
+	Locality LocalityConfig `json:"locality" yaml:"locality" toml:"locality"`
 }
 
 // RouteConfig defines feature flag-controlled routing configuration for specific routes.
@@ -88,6 +151,49 @@ type RouteConfig struct {
 	// DryRunBackend specifies the backend to compare against in dry-run mode
 	// If not specified, uses the AlternativeBackend for comparison
 	DryRunBackend string `json:"dry_run_backend" yaml:"dry_run_backend" toml:"dry_run_backend" env:"DRY_RUN_BACKEND"`
+
+	// Middleware lists named middleware (e.g. "auth", "rate-limit", "body-limit") applied to this route,
+	// outermost first. Names are resolved against RegisterRouteMiddleware and RouteMiddlewareProvider services.
+	Middleware []string `json:"middleware" yaml:"middleware" toml:"middleware" env:"MIDDLEWARE"`
+
+	// CacheStaleWhileRevalidate overrides the global stale-while-revalidate window for this route
+	CacheStaleWhileRevalidate time.Duration `json:"cache_stale_while_revalidate" yaml:"cache_stale_while_revalidate" toml:"cache_stale_while_revalidate" env:"CACHE_STALE_WHILE_REVALIDATE"`
+
+	// CacheStaleIfError overrides the global stale-if-error window for this route
+	CacheStaleIfError time.Duration `json:"cache_stale_if_error" yaml:"cache_stale_if_error" toml:"cache_stale_if_error" env:"CACHE_STALE_IF_ERROR"`
+
+	// CacheKey replaces the global cache key configuration for this route. Nil uses the
+	// global config.
+	CacheKey *CacheKeyConfig `json:"cache_key" yaml:"cache_key" toml:"cache_key"`
+
+	// Compression replaces the global compression config for this route, e.g. to disable it
+	// or to allow other content types. Nil uses the global config.
+	Compression *CompressionConfig `json:"compression" yaml:"compression" toml:"compression"`
+
+	// ContentTranslation translates bodies between the backend's media type and the
+	// ones clients send and accept, e.g. to serve XML clients from a JSON backend
+	ContentTranslation *ContentTranslationConfig `json:"content_translation" yaml:"content_translation" toml:"content_translation"`
+
+	// Upload configures streaming and progress tracking of large request bodies
+	Upload *UploadConfig `json:"upload" yaml:"upload" toml:"upload"`
+
+	// ContentRouting selects the backend from a request header or JSON body field
+	ContentRouting *ContentRoutingConfig `json:"content_routing" yaml:"content_routing" toml:"content_routing"`
+
+	// ResponseValidation checks backend responses against allowed status codes and a
+	// JSON Schema before they are returned
+	ResponseValidation *ResponseValidationConfig `json:"response_validation" yaml:"response_validation" toml:"response_validation"`
+
+	// LoadBalancingPolicy selects how requests are spread over the route's backend
+	// group: round_robin (default), weighted_round_robin, least_connections or random
+	LoadBalancingPolicy string `json:"load_balancing_policy" yaml:"load_balancing_policy" toml:"load_balancing_policy" env:"LOAD_BALANCING_POLICY"`
+
+	// Weights overrides the weights of the group's backends on this route, e.g.
+	// {stable: 90, canary: 10}. A zero weight takes the backend out of rotation.
+	Weights map[string]int `json:"weights" yaml:"weights" toml:"weights"`
+
+	// Retry retries failed requests on this route, replacing the backend's retry config
+	Retry *RetryConfig `json:"retry" yaml:"retry" toml:"retry"`
 }
 
 // CompositeRoute defines a route that combines responses from multiple backends.
@@ -103,6 +209,9 @@ type CompositeRoute struct {
 	// AlternativeBackend specifies an alternative single backend to use when the feature flag is disabled
 	// If FeatureFlagID is specified and evaluates to false, requests will be routed to this backend instead
 	AlternativeBackend string `json:"alternative_backend" yaml:"alternative_backend" toml:"alternative_backend" env:"ALTERNATIVE_BACKEND"`
+
+	// Metadata is passed to response transformers in the TransformContext of each request
+	Metadata map[string]string `json:"metadata" yaml:"metadata" toml:"metadata"`
 }
 
 // PathRewritingConfig defines configuration for path rewriting rules.
@@ -191,6 +300,55 @@ type BackendServiceConfig struct {
 	// Queue configuration
 	QueueSize    int           `json:"queue_size" yaml:"queue_size" toml:"queue_size" env:"QUEUE_SIZE"`
 	QueueTimeout time.Duration `json:"queue_timeout" yaml:"queue_timeout" toml:"queue_timeout" env:"QUEUE_TIMEOUT"`
+
+	// Region and Zone tag where the backend runs, for locality-aware selection from
+	// backend groups. See LocalityConfig.
+	Region string `json:"region" yaml:"region" toml:"region" env:"REGION"`
+	Zone   string `json:"zone" yaml:"zone" toml:"zone" env:"ZONE"`
+
+	// Weight is the backend's share of the traffic of the groups it belongs to, for
+	// the weighted load balancing policies. Defaults to 1.
+	Weight int `json:"weight" yaml:"weight" toml:"weight" env:"WEIGHT"`
+
+	// Labels describe the backend, such as service=reports and version=v2, for other
+	// modules selecting backends through the BackendSelector service.
+	Labels map[string]string `json:"labels" yaml:"labels" toml:"labels"`
+
+	// Redirects controls how redirects returned by this backend are handled: passed
+	// through, rewritten to the proxy's host, or followed server-side.
+	Redirects BackendRedirectConfig `json:"redirects" yaml:"redirects" toml:"redirects"`
+
+	// AdaptiveConcurrency limits the requests in flight to this backend to a limit
+	// adapted to its latency, shedding the excess with 503s.
+	AdaptiveConcurrency AdaptiveConcurrencyConfig `json:"adaptive_concurrency" yaml:"adaptive_concurrency" toml:"adaptive_concurrency"`
+
+	// ClientTLS configures the client certificate presented to this backend (mTLS).
+	// It is only honoured in tenant configuration, where it applies to that tenant's
+	// proxied connections alone.
+	ClientTLS *BackendTLSConfig `json:"client_tls" yaml:"client_tls" toml:"client_tls"`
+
+	// Retry retries failed requests to this backend on routes without a retry config
+	Retry *RetryConfig `json:"retry" yaml:"retry" toml:"retry"`
+}
+
+// BackendTLSConfig defines the TLS client certificate used toward a backend.
+// The certificate and key come either from CertFile/KeyFile or from SecretRef,
+// which is resolved by the resolver set with SetTLSSecretResolver.
+type BackendTLSConfig struct {
+	// CertFile is the path to the PEM-encoded client certificate
+	CertFile string `json:"cert_file" yaml:"cert_file" toml:"cert_file"`
+
+	// KeyFile is the path to the PEM-encoded private key for CertFile
+	KeyFile string `json:"key_file" yaml:"key_file" toml:"key_file"`
+
+	// SecretRef references a secret holding the certificate and key, as an alternative to files
+	SecretRef string `json:"secret_ref" yaml:"secret_ref" toml:"secret_ref"`
+
+	// CAFile is an optional PEM bundle used to verify the backend's certificate instead of the system roots
+	CAFile string `json:"ca_file" yaml:"ca_file" toml:"ca_file"`
+
+	// ServerName overrides the server name used to verify the backend's certificate
+	ServerName string `json:"server_name" yaml:"server_name" toml:"server_name"`
 }
 
 // EndpointConfig defines configuration for a specific endpoint within a backend service.
@@ -293,6 +451,10 @@ type FeatureFlagsConfig struct {
 
 	// Flags defines default values for feature flags. Tenant-specific overrides come from tenant config files.
 	Flags map[string]bool `json:"flags" yaml:"flags" toml:"flags" desc:"Default values for feature flags"`
+
+	// DecisionCacheTTL memoizes flag decisions per (flag, tenant) for this duration. Zero disables caching.
+	// Only enable it when flag decisions do not depend on individual request attributes.
+	DecisionCacheTTL time.Duration `json:"decision_cache_ttl" yaml:"decision_cache_ttl" toml:"decision_cache_ttl" env:"DECISION_CACHE_TTL" desc:"How long feature flag decisions are cached per flag and tenant (0 disables caching)"`
 }
 
 // MetricsConfig provides configuration for metrics collection.
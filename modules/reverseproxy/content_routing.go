@@ -0,0 +1,240 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// defaultContentRoutingMaxBodySize is the largest body inspected by content routing
+// rules when the route doesn't set one.
+const defaultContentRoutingMaxBodySize = 64 << 10
+
+// ContentRoutingConfig selects a route's backend from a request header or a field of
+// its JSON body. Rules are tried in order and the first matching one wins; requests
+// matching none go to Fallback, or the route's own backend without one.
+//
+//	route_configs:
+//	  "/api/orders/*":
+//	    content_routing:
+//	      max_body_size: 65536
+//	      fallback: orders-v1
+//	      rules:
+//	        - json_field: version
+//	          values: ["v2"]
+//	          backend: orders-v2
+//	        - header: X-Client
+//	          values: ["mobile"]
+//	          backend: orders-mobile
+type ContentRoutingConfig struct {
+	// Rules are tried in order; the first matching rule selects the backend
+	Rules []ContentRoutingRule `json:"rules" yaml:"rules" toml:"rules"`
+
+	// Fallback is the backend for requests no rule matches, including those whose body
+	// can't be inspected. Empty uses the route's own backend.
+	Fallback string `json:"fallback" yaml:"fallback" toml:"fallback" env:"FALLBACK"`
+
+	// MaxBodySize is the largest body in bytes buffered to evaluate json_field rules.
+	// Larger bodies are proxied unread and match no json_field rule. Zero uses 64 KiB.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size" toml:"max_body_size" env:"MAX_BODY_SIZE"`
+}
+
+// ContentRoutingRule matches a request header or JSON body field against values.
+// Exactly one of Header and JSONField must be set.
+type ContentRoutingRule struct {
+	// Header is the request header to match
+	Header string `json:"header" yaml:"header" toml:"header"`
+
+	// JSONField is the dot-separated path of a field in a JSON request body, e.g.
+	// "meta.version"; numeric segments index arrays
+	JSONField string `json:"json_field" yaml:"json_field" toml:"json_field"`
+
+	// Values the header or field must equal, compared as text, so 2 and true match
+	// "2" and "true". Empty matches any value present.
+	Values []string `json:"values" yaml:"values" toml:"values"`
+
+	// Backend receives the matching requests
+	Backend string `json:"backend" yaml:"backend" toml:"backend"`
+}
+
+// validate checks the rules, the backends they select and the body size limit.
+func (c *ContentRoutingConfig) validate(backends map[string]string) error {
+	if len(c.Rules) == 0 {
+		return fmt.Errorf("%w: rules is empty", ErrInvalidContentRouting)
+	}
+	if c.MaxBodySize < 0 {
+		return fmt.Errorf("%w: max_body_size %d is negative", ErrInvalidContentRouting, c.MaxBodySize)
+	}
+	if _, ok := backends[c.Fallback]; c.Fallback != "" && !ok {
+		return fmt.Errorf("%w: unknown fallback backend %q", ErrInvalidContentRouting, c.Fallback)
+	}
+	for i, rule := range c.Rules {
+		if (rule.Header == "") == (rule.JSONField == "") {
+			return fmt.Errorf("%w: rules[%d]: exactly one of header and json_field is required", ErrInvalidContentRouting, i)
+		}
+		if rule.JSONField != "" && slices.Contains(strings.Split(rule.JSONField, "."), "") {
+			return fmt.Errorf("%w: rules[%d]: json_field %q has an empty segment", ErrInvalidContentRouting, i, rule.JSONField)
+		}
+		if _, ok := backends[rule.Backend]; !ok {
+			return fmt.Errorf("%w: rules[%d]: unknown backend %q", ErrInvalidContentRouting, i, rule.Backend)
+		}
+	}
+	return nil
+}
+
+func (c *ContentRoutingConfig) maxBodySize() int64 {
+	if c.MaxBodySize > 0 {
+		return c.MaxBodySize
+	}
+	return defaultContentRoutingMaxBodySize
+}
+
+// inspectsBody reports whether any rule matches a JSON body field.
+func (c *ContentRoutingConfig) inspectsBody() bool {
+	for _, rule := range c.Rules {
+		if rule.JSONField != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// selectBackend returns the backend of the first rule matching the request, or
+// Fallback. It buffers at most maxBodySize bytes of a JSON body and leaves r.Body
+// readable from the start.
+func (c *ContentRoutingConfig) selectBackend(r *http.Request) (string, error) {
+	var body any
+	if c.inspectsBody() {
+		var err error
+		if body, err = c.readJSONBody(r); err != nil {
+			return "", err
+		}
+	}
+
+	for _, rule := range c.Rules {
+		var value string
+		var ok bool
+		if rule.Header != "" {
+			value, ok = r.Header.Get(rule.Header), len(r.Header.Values(rule.Header)) > 0
+		} else if body != nil {
+			value, ok = jsonFieldText(body, rule.JSONField)
+		}
+		if ok && (len(rule.Values) == 0 || slices.Contains(rule.Values, value)) {
+			return rule.Backend, nil
+		}
+	}
+	return c.Fallback, nil
+}
+
+// readJSONBody decodes a JSON request body of at most maxBodySize bytes. It returns
+// nil for other, larger or malformed bodies, and an error only when the body can't be
+// read. The bytes read are put back in front of the rest of the body.
+func (c *ContentRoutingConfig) readJSONBody(r *http.Request) (any, error) {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength > c.maxBodySize() {
+		return nil, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || (mediaType != MediaTypeJSON && !strings.HasSuffix(mediaType, "+json")) {
+		return nil, nil
+	}
+
+	original := r.Body
+	buffered, err := io.ReadAll(io.LimitReader(original, c.maxBodySize()+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+	if int64(len(buffered)) > c.maxBodySize() {
+		r.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buffered), original), original}
+		return nil, nil
+	}
+	_ = original.Close()
+	r.Body = io.NopCloser(bytes.NewReader(buffered))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buffered)), nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(buffered))
+	decoder.UseNumber()
+	var body any
+	if err := decoder.Decode(&body); err != nil {
+		return nil, nil
+	}
+	return body, nil
+}
+
+// jsonFieldText returns the value at the dot-separated path as text. Objects and
+// arrays have no text form and only match rules without values.
+func jsonFieldText(body any, path string) (string, bool) {
+	value := body
+	for _, segment := range strings.Split(path, ".") {
+		switch node := value.(type) {
+		case map[string]any:
+			next, ok := node[segment]
+			if !ok {
+				return "", false
+			}
+			value = next
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", false
+			}
+			value = node[index]
+		default:
+			return "", false
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	case nil:
+		return "null", true
+	default:
+		return "", true
+	}
+}
+
+// withContentRouting sends requests to the backend selected by the route's content
+// routing rules, and the others to handler.
+func (m *ReverseProxyModule) withContentRouting(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.config == nil || handler == nil {
+		return handler
+	}
+	routeConfig, ok := m.config.RouteConfigs[pattern]
+	if !ok || routeConfig.ContentRouting == nil {
+		return handler
+	}
+	routing := routeConfig.ContentRouting
+	return func(w http.ResponseWriter, r *http.Request) {
+		backend, err := routing.selectBackend(r)
+		if err != nil {
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Warn("Content routing failed to read request body", "route", pattern, "error", err)
+			}
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		if backend == "" {
+			handler(w, r)
+			return
+		}
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Content routing selected backend", "route", pattern, "backend", backend)
+		}
+		m.createBackendProxyHandler(backend)(w, r)
+	}
+}
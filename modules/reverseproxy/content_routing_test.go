@@ -0,0 +1,132 @@
+package reverseproxy
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFieldText(t *testing.T) {
+	config := &ContentRoutingConfig{Rules: []ContentRoutingRule{{JSONField: "x", Backend: "a"}}}
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(
+		`{"version":"v2","meta":{"rev":2,"beta":true,"tags":["a","b"],"owner":null}}`))
+	req.Header.Set("Content-Type", "application/json")
+	body, err := config.readJSONBody(req)
+	require.NoError(t, err)
+
+	tests := []struct {
+		path  string
+		want  string
+		found bool
+	}{
+		{"version", "v2", true},
+		{"meta.rev", "2", true},
+		{"meta.beta", "true", true},
+		{"meta.tags.1", "b", true},
+		{"meta.owner", "null", true},
+		{"meta", "", true},
+		{"meta.tags.2", "", false},
+		{"meta.tags.x", "", false},
+		{"missing", "", false},
+		{"version.x", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found := jsonFieldText(body, tt.path)
+			assert.Equal(t, tt.want, value)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestContentRouting_ThroughProxy(t *testing.T) {
+	newBackend := func(name string) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			_, _ = w.Write([]byte(name + ":" + string(body)))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	v1, v2, mobile := newBackend("v1"), newBackend("v2"), newBackend("mobile")
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"v1": v1.URL, "v2": v2.URL, "mobile": mobile.URL},
+		RequestTimeout:  10 * time.Second,
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*": {ContentRouting: &ContentRoutingConfig{
+				MaxBodySize: 64,
+				Rules: []ContentRoutingRule{
+					{JSONField: "version", Values: []string{"v2", "2"}, Backend: "v2"},
+					{Header: "X-Client", Values: []string{"mobile"}, Backend: "mobile"},
+				},
+			}},
+		},
+	})
+	handler := m.withContentRouting("/api/*", m.createBackendProxyHandler("v1"))
+
+	do := func(contentType, client, body string) string {
+		req := httptest.NewRequest(http.MethodPost, "/api/orders", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if client != "" {
+			req.Header.Set("X-Client", client)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	assert.Equal(t, `v2:{"version":"v2"}`, do("application/json", "", `{"version":"v2"}`))
+	assert.Equal(t, `v2:{"version":2}`, do("application/vnd.api+json", "mobile", `{"version":2}`))
+	assert.Equal(t, `mobile:{"version":"v1"}`, do("application/json", "mobile", `{"version":"v1"}`))
+	assert.Equal(t, `v1:{"version":"v1"}`, do("application/json", "", `{"version":"v1"}`))
+
+	// Bodies that can't be inspected fall through to the next rules, with the body intact
+	assert.Equal(t, `v1:{"version":"v2"`, do("application/json", "", `{"version":"v2"`))
+	assert.Equal(t, `v1:version=v2`, do("application/x-www-form-urlencoded", "", `version=v2`))
+	large := `{"version":"v2","padding":"` + strings.Repeat("x", 100) + `"}`
+	assert.Equal(t, "v1:"+large, do("application/json", "", large))
+	assert.Equal(t, "mobile:"+large, do("application/json", "mobile", large))
+
+	// A fallback replaces the route's backend for unmatched requests
+	m.config.RouteConfigs["/api/*"].ContentRouting.Fallback = "mobile"
+	assert.Equal(t, `mobile:{"version":"v1"}`, do("application/json", "", `{"version":"v1"}`))
+}
+
+func TestContentRoutingConfig_Validate(t *testing.T) {
+	backends := map[string]string{"a": "http://a", "b": "http://b"}
+	tests := []struct {
+		name   string
+		config ContentRoutingConfig
+		valid  bool
+	}{
+		{"header", ContentRoutingConfig{Rules: []ContentRoutingRule{{Header: "X-V", Backend: "a"}}}, true},
+		{"json field", ContentRoutingConfig{Fallback: "b", Rules: []ContentRoutingRule{{JSONField: "a.b", Backend: "a"}}}, true},
+		{"no rules", ContentRoutingConfig{}, false},
+		{"both", ContentRoutingConfig{Rules: []ContentRoutingRule{{Header: "X-V", JSONField: "v", Backend: "a"}}}, false},
+		{"neither", ContentRoutingConfig{Rules: []ContentRoutingRule{{Backend: "a"}}}, false},
+		{"empty segment", ContentRoutingConfig{Rules: []ContentRoutingRule{{JSONField: "a..b", Backend: "a"}}}, false},
+		{"unknown backend", ContentRoutingConfig{Rules: []ContentRoutingRule{{Header: "X-V", Backend: "c"}}}, false},
+		{"unknown fallback", ContentRoutingConfig{Fallback: "c", Rules: []ContentRoutingRule{{Header: "X-V", Backend: "a"}}}, false},
+		{"negative size", ContentRoutingConfig{MaxBodySize: -1, Rules: []ContentRoutingRule{{Header: "X-V", Backend: "a"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(backends)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidContentRouting)
+			}
+		})
+	}
+}
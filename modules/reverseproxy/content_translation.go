@@ -0,0 +1,751 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Media types with built-in content codecs.
+const (
+	MediaTypeJSON    = "application/json"
+	MediaTypeXML     = "application/xml"
+	MediaTypeTextXML = "text/xml"
+)
+
+// Defaults for content translation.
+const (
+	defaultTranslationMaxBodySize = 10 << 20
+	defaultXMLRoot                = "response"
+)
+
+// ContentCodec converts between an encoded body and generic values: maps with string
+// keys, slices, strings, numbers (json.Number or float64), booleans and nil. A
+// translation decodes with one media type's codec and encodes with another's.
+type ContentCodec interface {
+	// Decode reads a whole body into a generic value.
+	Decode(r io.Reader) (any, error)
+
+	// Encode writes a generic value as a body.
+	Encode(w io.Writer, v any) error
+}
+
+// ContentTranslationConfig configures translation between the media type a route's
+// backend speaks and other media types its clients use. Clients select the response
+// format with Accept; request bodies are translated based on their Content-Type.
+//
+//	route_configs:
+//	  "/legacy/*":
+//	    content_translation:
+//	      backend_format: application/json
+//	      client_formats: ["application/xml", "text/xml"]
+//	      xml_root: order
+type ContentTranslationConfig struct {
+	// BackendFormat is the media type the backend reads and writes. Defaults to application/json.
+	BackendFormat string `json:"backend_format" yaml:"backend_format" toml:"backend_format" env:"BACKEND_FORMAT"`
+
+	// ClientFormats lists the other media types clients may send and request
+	ClientFormats []string `json:"client_formats" yaml:"client_formats" toml:"client_formats" env:"CLIENT_FORMATS"`
+
+	// XMLRoot names the root element of XML documents produced by the built-in XML codec. Defaults to "response".
+	XMLRoot string `json:"xml_root" yaml:"xml_root" toml:"xml_root" env:"XML_ROOT"`
+
+	// XMLStringValues keeps every value decoded by the built-in XML codec a string,
+	// instead of turning numbers and booleans into JSON numbers and booleans
+	XMLStringValues bool `json:"xml_string_values" yaml:"xml_string_values" toml:"xml_string_values" env:"XML_STRING_VALUES"`
+
+	// MaxBodySize is the largest body in bytes translated. Larger responses pass through
+	// untranslated and larger requests are rejected with 413. Zero uses 10 MiB.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size" toml:"max_body_size" env:"MAX_BODY_SIZE"`
+}
+
+// validate checks the media types, the XML root and the body size limit.
+func (c *ContentTranslationConfig) validate() error {
+	if len(c.ClientFormats) == 0 {
+		return fmt.Errorf("%w: client_formats is empty", ErrInvalidContentTranslation)
+	}
+	if c.MaxBodySize < 0 {
+		return fmt.Errorf("%w: max_body_size %d is negative", ErrInvalidContentTranslation, c.MaxBodySize)
+	}
+	if c.XMLRoot != "" && !isXMLName(c.XMLRoot) {
+		return fmt.Errorf("%w: xml_root %q is not a valid element name", ErrInvalidContentTranslation, c.XMLRoot)
+	}
+	backend := c.backendFormat()
+	if _, _, err := mime.ParseMediaType(backend); err != nil || !strings.Contains(backend, "/") {
+		return fmt.Errorf("%w: backend_format %q is not a media type", ErrInvalidContentTranslation, c.BackendFormat)
+	}
+	for _, format := range c.ClientFormats {
+		mediaType, _, err := mime.ParseMediaType(format)
+		if err != nil || !strings.Contains(mediaType, "/") {
+			return fmt.Errorf("%w: client format %q is not a media type", ErrInvalidContentTranslation, format)
+		}
+		if mediaType == backend {
+			return fmt.Errorf("%w: client format %q is the backend format", ErrInvalidContentTranslation, format)
+		}
+	}
+	return nil
+}
+
+// backendFormat returns the backend media type with the default applied.
+func (c *ContentTranslationConfig) backendFormat() string {
+	if c.BackendFormat == "" {
+		return MediaTypeJSON
+	}
+	mediaType, _, err := mime.ParseMediaType(c.BackendFormat)
+	if err != nil {
+		return c.BackendFormat
+	}
+	return mediaType
+}
+
+// RegisterContentCodec makes codec available to content translation for mediaType,
+// replacing the built-in JSON or XML codec when registered for their media types.
+// It must be called before Start.
+func (m *ReverseProxyModule) RegisterContentCodec(mediaType string, codec ContentCodec) {
+	if m.contentCodecs == nil {
+		m.contentCodecs = make(map[string]ContentCodec)
+	}
+	m.contentCodecs[strings.ToLower(mediaType)] = codec
+}
+
+// contentTranslator translates one route's bodies between its backend format and
+// its client formats.
+type contentTranslator struct {
+	backendFormat string
+	// offers lists the backend format followed by the client formats, for Accept negotiation
+	offers      []string
+	codecs      map[string]ContentCodec
+	maxBodySize int64
+}
+
+// resolveContentTranslation builds the translator for every route config with a
+// content_translation block, failing when a format has no codec.
+func (m *ReverseProxyModule) resolveContentTranslation() error {
+	m.contentTranslators = make(map[string]*contentTranslator)
+	for pattern, routeConfig := range m.config.RouteConfigs {
+		config := routeConfig.ContentTranslation
+		if config == nil {
+			continue
+		}
+
+		translator := &contentTranslator{
+			backendFormat: config.backendFormat(),
+			codecs:        make(map[string]ContentCodec),
+			maxBodySize:   config.MaxBodySize,
+		}
+		if translator.maxBodySize == 0 {
+			translator.maxBodySize = defaultTranslationMaxBodySize
+		}
+		mediaTypes := []string{translator.backendFormat}
+		for _, format := range config.ClientFormats {
+			mediaType, _, _ := mime.ParseMediaType(format)
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+		for _, mediaType := range mediaTypes {
+			codec := m.contentCodec(mediaType, config)
+			if codec == nil {
+				return fmt.Errorf("%w: %q for route %s", ErrContentCodecNotFound, mediaType, pattern)
+			}
+			translator.offers = append(translator.offers, mediaType)
+			translator.codecs[mediaType] = codec
+		}
+		m.contentTranslators[pattern] = translator
+	}
+	return nil
+}
+
+// contentCodec returns the registered codec for mediaType, falling back to the
+// built-in JSON and XML codecs.
+func (m *ReverseProxyModule) contentCodec(mediaType string, config *ContentTranslationConfig) ContentCodec {
+	if codec, ok := m.contentCodecs[mediaType]; ok {
+		return codec
+	}
+	switch mediaType {
+	case MediaTypeJSON:
+		return JSONCodec{}
+	case MediaTypeXML, MediaTypeTextXML:
+		return XMLCodec{Root: config.XMLRoot, StringValues: config.XMLStringValues}
+	}
+	return nil
+}
+
+// withContentTranslation wraps handler to translate request and response bodies as
+// configured for pattern.
+func (m *ReverseProxyModule) withContentTranslation(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	translator := m.contentTranslators[pattern]
+	if translator == nil || handler == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			handler(w, r)
+			return
+		}
+
+		// A client sending a translated format without an Accept header gets it back
+		var requestFormat string
+		if r.Body != nil && r.Body != http.NoBody {
+			if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType != translator.backendFormat {
+				if codec := translator.codecs[mediaType]; codec != nil {
+					translated, status, err := translator.translateRequest(r, codec)
+					if err != nil {
+						m.logContentTranslation(pattern, "Failed to translate request body", err)
+						http.Error(w, http.StatusText(status), status)
+						return
+					}
+					r = translated
+					requestFormat = mediaType
+				}
+			}
+		}
+
+		format := negotiateMediaType(strings.Join(r.Header.Values("Accept"), ","), translator.offers)
+		if format == "" && len(r.Header.Values("Accept")) == 0 {
+			format = requestFormat
+		}
+		if format == "" || format == translator.backendFormat {
+			addVary(w.Header(), "Accept")
+			handler(w, r)
+			return
+		}
+
+		// Ask the backend for its own format, uncompressed so the body can be decoded
+		r = r.Clone(r.Context())
+		r.Header.Set("Accept", translator.backendFormat)
+		r.Header.Del("Accept-Encoding")
+
+		writer := &translateResponseWriter{ResponseWriter: w, translator: translator, format: format}
+		handler(writer, r)
+		if err := writer.Close(); err != nil {
+			m.logContentTranslation(pattern, "Failed to translate response body", err)
+		}
+	}
+}
+
+// logContentTranslation logs a translation failure when a logger is available.
+func (m *ReverseProxyModule) logContentTranslation(pattern, msg string, err error) {
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Debug(msg, "route", pattern, "error", err)
+	}
+}
+
+// translateRequest returns a copy of r with its body translated from codec's format
+// to the backend format, or the status to reply with when that fails.
+func (t *contentTranslator) translateRequest(r *http.Request, codec ContentCodec) (*http.Request, int, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, t.maxBodySize+1))
+	_ = r.Body.Close()
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("reading request body: %w", err)
+	}
+	if int64(len(body)) > t.maxBodySize {
+		return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("%w: request body exceeds %d bytes", ErrContentTranslationFailed, t.maxBodySize)
+	}
+	translated, err := translateBody(body, codec, t.codecs[t.backendFormat])
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	r = r.Clone(r.Context())
+	r.Body = io.NopCloser(bytes.NewReader(translated))
+	r.ContentLength = int64(len(translated))
+	r.Header.Set("Content-Length", strconv.Itoa(len(translated)))
+	r.Header.Set("Content-Type", t.backendFormat)
+	return r, 0, nil
+}
+
+// translateBody decodes body with from and encodes the result with to.
+func translateBody(body []byte, from, to ContentCodec) ([]byte, error) {
+	value, err := from.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: decoding: %w", ErrContentTranslationFailed, err)
+	}
+	var buf bytes.Buffer
+	if err := to.Encode(&buf, value); err != nil {
+		return nil, fmt.Errorf("%w: encoding: %w", ErrContentTranslationFailed, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiateMediaType returns the offer with the highest quality in an Accept header,
+// preferring earlier offers on ties, or "" when the client accepts none of them.
+// Exact media types take precedence over type/* ranges, and those over */*.
+func negotiateMediaType(accept string, offers []string) string {
+	if accept == "" {
+		return ""
+	}
+	type acceptRange struct {
+		mediaType string
+		quality   float64
+	}
+	var ranges []acceptRange
+	for _, entry := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil {
+			quality = q
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, quality: quality})
+	}
+
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		offerType, _, _ := strings.Cut(offer, "/")
+		quality, specificity := 0.0, -1
+		for _, ar := range ranges {
+			rangeType, rangeSubtype, _ := strings.Cut(ar.mediaType, "/")
+			level := -1
+			switch {
+			case ar.mediaType == offer:
+				level = 2
+			case rangeSubtype == "*" && rangeType == offerType:
+				level = 1
+			case ar.mediaType == "*/*":
+				level = 0
+			}
+			if level > specificity {
+				quality, specificity = ar.quality, level
+			}
+		}
+		if quality > bestQuality {
+			best, bestQuality = offer, quality
+		}
+	}
+	return best
+}
+
+// translateResponseWriter buffers a response in the backend format and writes it
+// translated to the negotiated format on Close. Responses in other formats, already
+// encoded or larger than the body size limit are written through unchanged.
+type translateResponseWriter struct {
+	http.ResponseWriter
+	translator *contentTranslator
+	format     string
+
+	status      int
+	buffering   bool
+	passthrough bool
+	buf         bytes.Buffer
+}
+
+func (w *translateResponseWriter) WriteHeader(status int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	if w.buffering {
+		return
+	}
+	if status < http.StatusOK {
+		if status == http.StatusSwitchingProtocols {
+			w.passthrough = true
+		}
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	if w.translatable() {
+		w.buffering = true
+		return
+	}
+	w.passthrough = true
+	addVary(w.Header(), "Accept")
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *translateResponseWriter) Write(p []byte) (int, error) {
+	if !w.passthrough && !w.buffering {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(p) //nolint:wrapcheck // passthrough of the underlying writer's error
+	}
+	if int64(w.buf.Len()+len(p)) > w.translator.maxBodySize {
+		if err := w.writeUntranslated(); err != nil {
+			return 0, err
+		}
+		return w.ResponseWriter.Write(p) //nolint:wrapcheck // passthrough of the underlying writer's error
+	}
+	return w.buf.Write(p) //nolint:wrapcheck // bytes.Buffer writes don't fail
+}
+
+// Flush is a no-op while a response is buffered for translation.
+func (w *translateResponseWriter) Flush() {
+	if w.passthrough {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *translateResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close writes out the buffered response, translated when possible.
+func (w *translateResponseWriter) Close() error {
+	if !w.buffering || w.passthrough {
+		return nil
+	}
+	if w.buf.Len() == 0 {
+		return w.writeUntranslated()
+	}
+	translated, err := translateBody(w.buf.Bytes(), w.translator.codecs[w.translator.backendFormat], w.translator.codecs[w.format])
+	if err != nil {
+		return errors.Join(err, w.writeUntranslated())
+	}
+
+	w.passthrough = true
+	h := w.Header()
+	h.Set("Content-Type", w.format)
+	h.Set("Content-Length", strconv.Itoa(len(translated)))
+	addVary(h, "Accept")
+	// A strong ETag no longer identifies the translated representation
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	if _, err := w.ResponseWriter.Write(translated); err != nil {
+		return fmt.Errorf("failed to write translated response: %w", err)
+	}
+	return nil
+}
+
+// translatable reports whether the status and headers allow translating the response.
+func (w *translateResponseWriter) translatable() bool {
+	switch w.status {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	h := w.Header()
+	if encoding := h.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+	if strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-transform") {
+		return false
+	}
+	if length, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64); err == nil && length > w.translator.maxBodySize {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(h.Get("Content-Type"))
+	return err == nil && mediaType == w.translator.backendFormat
+}
+
+// writeUntranslated sends the headers and buffered body unchanged.
+func (w *translateResponseWriter) writeUntranslated() error {
+	w.passthrough = true
+	addVary(w.Header(), "Accept")
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	if err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
+
+// JSONCodec is the built-in codec for application/json. Numbers decode as json.Number
+// so they keep their precision.
+type JSONCodec struct{}
+
+// Decode implements ContentCodec.
+func (JSONCodec) Decode(r io.Reader) (any, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("decoding JSON: %w", err)
+	}
+	return value, nil
+}
+
+// Encode implements ContentCodec.
+func (JSONCodec) Encode(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("encoding JSON: %w", err)
+	}
+	return nil
+}
+
+// XMLCodec is the built-in codec for application/xml and text/xml. Objects become
+// elements named after their keys, in key order; keys starting with "@" become
+// attributes and keys that are not valid element names become <entry key="...">.
+// Arrays become repeated <item> elements and null an element with nil="true".
+// Decoding reverses this: elements whose children are all <item> become arrays,
+// repeated elements become arrays, and text content and attribute values "true"
+// and "false" become booleans and those in JSON number syntax numbers, unless
+// StringValues is set. Other values, including "007" and " 42", stay strings.
+type XMLCodec struct {
+	// Root names the document's root element. Defaults to "response".
+	Root string
+
+	// StringValues keeps every decoded value a string
+	StringValues bool
+}
+
+// Decode implements ContentCodec. The root element's name is dropped.
+func (c XMLCodec) Decode(r io.Reader) (any, error) {
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("decoding XML: %w", err)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			_, value, err := c.decodeElement(decoder, start)
+			if err != nil {
+				return nil, fmt.Errorf("decoding XML: %w", err)
+			}
+			return value, nil
+		}
+	}
+}
+
+// Encode implements ContentCodec.
+func (c XMLCodec) Encode(w io.Writer, v any) error {
+	root := c.Root
+	if root == "" {
+		root = defaultXMLRoot
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("encoding XML: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	if err := encodeXMLElement(encoder, root, v); err != nil {
+		return fmt.Errorf("encoding XML: %w", err)
+	}
+	if err := encoder.Flush(); err != nil {
+		return fmt.Errorf("encoding XML: %w", err)
+	}
+	return nil
+}
+
+func encodeXMLElement(encoder *xml.Encoder, name string, v any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if !isXMLName(name) {
+		start.Name.Local = "entry"
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "key"}, Value: name})
+	}
+
+	var children func() error
+	switch value := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for key := range value {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var childKeys []string
+		for _, key := range keys {
+			attr, isAttr := strings.CutPrefix(key, "@")
+			if isAttr && isXMLName(attr) && isXMLScalar(value[key]) {
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attr}, Value: xmlScalar(value[key])})
+				continue
+			}
+			childKeys = append(childKeys, key)
+		}
+		children = func() error {
+			for _, key := range childKeys {
+				if err := encodeXMLElement(encoder, key, value[key]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case []any:
+		children = func() error {
+			for _, item := range value {
+				if err := encodeXMLElement(encoder, "item", item); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case nil:
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "nil"}, Value: "true"})
+	default:
+		children = func() error {
+			return encoder.EncodeToken(xml.CharData(xmlScalar(value))) //nolint:wrapcheck // wrapped by Encode
+		}
+	}
+
+	if err := encoder.EncodeToken(start); err != nil {
+		return err //nolint:wrapcheck // wrapped by Encode
+	}
+	if children != nil {
+		if err := children(); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End()) //nolint:wrapcheck // wrapped by Encode
+}
+
+// decodeElement decodes the element opened by start, returning the key it maps to
+// in its parent and its value.
+func (c XMLCodec) decodeElement(decoder *xml.Decoder, start xml.StartElement) (string, any, error) {
+	name := start.Name.Local
+	attrs := make(map[string]any)
+	isNil := false
+	for _, attr := range start.Attr {
+		switch {
+		case attr.Name.Local == "key" && name == "entry":
+			name = attr.Value
+		case attr.Name.Local == "nil" && attr.Value == "true":
+			isNil = true
+		default:
+			attrs["@"+attr.Name.Local] = c.scalar(attr.Value)
+		}
+	}
+
+	type child struct {
+		name  string
+		value any
+	}
+	var children []child
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", nil, err //nolint:wrapcheck // wrapped by Decode
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			childName, value, err := c.decodeElement(decoder, t)
+			if err != nil {
+				return "", nil, err
+			}
+			children = append(children, child{name: childName, value: value})
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			if len(children) == 0 {
+				switch {
+				case isNil:
+					return name, nil, nil
+				case len(attrs) == 0:
+					return name, c.scalar(text.String()), nil
+				}
+				if s := strings.TrimSpace(text.String()); s != "" {
+					attrs["#text"] = c.scalar(s)
+				}
+				return name, attrs, nil
+			}
+
+			allItems := len(attrs) == 0
+			for _, child := range children {
+				allItems = allItems && child.name == "item"
+			}
+			if allItems {
+				items := make([]any, len(children))
+				for i, child := range children {
+					items[i] = child.value
+				}
+				return name, items, nil
+			}
+			for _, child := range children {
+				existing, seen := attrs[child.name]
+				switch repeated, ok := existing.(repeatedXMLElements); {
+				case !seen:
+					attrs[child.name] = child.value
+				case ok:
+					attrs[child.name] = append(repeated, child.value)
+				default:
+					attrs[child.name] = repeatedXMLElements{existing, child.value}
+				}
+			}
+			for key, value := range attrs {
+				if repeated, ok := value.(repeatedXMLElements); ok {
+					attrs[key] = []any(repeated)
+				}
+			}
+			return name, attrs, nil
+		}
+	}
+}
+
+// scalar returns the JSON value of XML text: a boolean for "true" and "false", a
+// json.Number for text in JSON number syntax, and the text itself otherwise.
+func (c XMLCodec) scalar(text string) any {
+	if c.StringValues {
+		return text
+	}
+	switch text {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	if isJSONNumber(text) {
+		return json.Number(text)
+	}
+	return text
+}
+
+// isJSONNumber reports whether text is a number in JSON syntax, without leading
+// zeros, a plus sign or surrounding whitespace.
+func isJSONNumber(text string) bool {
+	if text == "" || text[0] != '-' && (text[0] < '0' || text[0] > '9') {
+		return false
+	}
+	if last := text[len(text)-1]; last < '0' || last > '9' {
+		return false
+	}
+	return json.Valid([]byte(text))
+}
+
+// repeatedXMLElements collects the values of sibling elements sharing a name.
+type repeatedXMLElements []any
+
+func isXMLScalar(v any) bool {
+	switch v.(type) {
+	case map[string]any, []any, nil:
+		return false
+	}
+	return true
+}
+
+func xmlScalar(v any) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case json.Number:
+		return value.String()
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return fmt.Sprint(v)
+}
+
+// isXMLName reports whether name can be used as an XML element or attribute name.
+// It accepts ASCII names and any non-ASCII letters, which covers what JSON keys
+// usually hold without implementing the full XML name grammar.
+func isXMLName(name string) bool {
+	if name == "" || strings.HasPrefix(strings.ToLower(name), "xml") {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r > 0x7f:
+		case i > 0 && (r == '-' || r == '.' || r >= '0' && r <= '9'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,278 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	offers := []string{MediaTypeJSON, MediaTypeXML, MediaTypeTextXML}
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"text/csv", ""},
+		{"application/json", MediaTypeJSON},
+		{"application/xml", MediaTypeXML},
+		{"text/xml, application/json;q=0.5", MediaTypeTextXML},
+		{"*/*", MediaTypeJSON},
+		{"application/*", MediaTypeJSON},
+		{"application/*;q=0.5, application/xml", MediaTypeXML},
+		{"*/*;q=0.1, application/json;q=0", MediaTypeXML},
+		{"text/*", MediaTypeTextXML},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			assert.Equal(t, tt.want, negotiateMediaType(tt.accept, offers))
+		})
+	}
+}
+
+func TestXMLCodec_RoundTrip(t *testing.T) {
+	input := `{"id":42,"name":"Ada & Co","active":true,"note":null,"tags":["a","b"],` +
+		`"address":{"city":"Paris","@kind":"home"},"2fa":"on"}`
+	value, err := JSONCodec{}.Decode(strings.NewReader(input))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, XMLCodec{Root: "user"}.Encode(&buf, value))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<user><entry key="2fa">on</entry><active>true</active><address kind="home"><city>Paris</city></address>`+
+		`<id>42</id><name>Ada &amp; Co</name><note nil="true"></note><tags><item>a</item><item>b</item></tags></user>`,
+		buf.String())
+
+	decoded, err := XMLCodec{}.Decode(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"2fa":     "on",
+		"active":  true,
+		"address": map[string]any{"@kind": "home", "city": "Paris"},
+		"id":      json.Number("42"),
+		"name":    "Ada & Co",
+		"note":    nil,
+		"tags":    []any{"a", "b"},
+	}, decoded)
+}
+
+func TestXMLCodec_DecodeRepeatedElements(t *testing.T) {
+	decoded, err := XMLCodec{}.Decode(strings.NewReader(
+		`<order id="7"><line>a</line><line>b</line><total currency="EUR">9.50</total></order>`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"@id":   json.Number("7"),
+		"line":  []any{"a", "b"},
+		"total": map[string]any{"@currency": "EUR", "#text": json.Number("9.50")},
+	}, decoded)
+}
+
+func TestXMLCodec_DecodeScalars(t *testing.T) {
+	input := `<r><int>-12</int><float>1.5e3</float><yes>true</yes><no>false</no>` +
+		`<zip>007</zip><plus>+1</plus><padded> 42 </padded><title>True</title><empty></empty></r>`
+
+	decoded, err := XMLCodec{}.Decode(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"int":    json.Number("-12"),
+		"float":  json.Number("1.5e3"),
+		"yes":    true,
+		"no":     false,
+		"zip":    "007",
+		"plus":   "+1",
+		"padded": " 42 ",
+		"title":  "True",
+		"empty":  "",
+	}, decoded)
+
+	decoded, err = XMLCodec{StringValues: true}.Decode(strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, "-12", decoded.(map[string]any)["int"])
+	assert.Equal(t, "true", decoded.(map[string]any)["yes"])
+}
+
+func TestContentTranslation_ThroughProxy(t *testing.T) {
+	var lastRequest struct {
+		contentType string
+		accept      string
+		body        string
+	}
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		lastRequest.contentType = r.Header.Get("Content-Type")
+		lastRequest.accept = r.Header.Get("Accept")
+		lastRequest.body = string(body)
+		switch r.URL.Path {
+		case "/legacy/text":
+			w.Header().Set("Content-Type", "text/plain")
+			_, _ = w.Write([]byte("plain"))
+		case "/legacy/broken":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte("{not json"))
+		default:
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte(`{"id":1,"items":["x"]}`))
+		}
+	}))
+	t.Cleanup(backend.Close)
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  10 * time.Second,
+		RouteConfigs: map[string]RouteConfig{
+			"/legacy/*": {ContentTranslation: &ContentTranslationConfig{
+				ClientFormats: []string{MediaTypeXML},
+				XMLRoot:       "order",
+				MaxBodySize:   64,
+			}},
+		},
+	})
+	require.NoError(t, m.resolveContentTranslation())
+	handler := m.withContentTranslation("/legacy/*", m.createBackendProxyHandler("api"))
+
+	do := func(method, path, contentType, accept, body string) *httptest.ResponseRecorder {
+		var reader io.Reader
+		if body != "" {
+			reader = strings.NewReader(body)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	t.Run("json response translated to xml", func(t *testing.T) {
+		rec := do(http.MethodGet, "/legacy/orders/1", "", "application/xml", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MediaTypeXML, rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+		assert.Equal(t, MediaTypeJSON, lastRequest.accept)
+		assert.Contains(t, rec.Body.String(), `<order><id>1</id><items><item>x</item></items></order>`)
+	})
+
+	t.Run("json clients untouched", func(t *testing.T) {
+		rec := do(http.MethodGet, "/legacy/orders/1", "", "application/json", "")
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		assert.Equal(t, `{"id":1,"items":["x"]}`, rec.Body.String())
+	})
+
+	t.Run("xml request body translated to json", func(t *testing.T) {
+		rec := do(http.MethodPost, "/legacy/orders", "application/xml", "", `<order><id>5</id></order>`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, MediaTypeJSON, lastRequest.contentType)
+		assert.JSONEq(t, `{"id":5}`, lastRequest.body, "numbers in XML become JSON numbers")
+		// Without an Accept header the client gets its own format back
+		assert.Equal(t, MediaTypeXML, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("invalid request body rejected", func(t *testing.T) {
+		rec := do(http.MethodPost, "/legacy/orders", "application/xml", "", `<order>`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("oversized request body rejected", func(t *testing.T) {
+		rec := do(http.MethodPost, "/legacy/orders", "application/xml", "", "<order>"+strings.Repeat("x", 100)+"</order>")
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+	})
+
+	t.Run("other content types pass through", func(t *testing.T) {
+		rec := do(http.MethodGet, "/legacy/text", "", "application/xml", "")
+		assert.Equal(t, "text/plain", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "plain", rec.Body.String())
+	})
+
+	t.Run("untranslatable response passes through", func(t *testing.T) {
+		rec := do(http.MethodGet, "/legacy/broken", "", "application/xml", "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{not json", rec.Body.String())
+	})
+}
+
+type csvCodec struct{}
+
+func (csvCodec) Decode(r io.Reader) (any, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, 0)
+	for _, field := range strings.Split(strings.TrimSpace(string(body)), ",") {
+		values = append(values, field)
+	}
+	return values, nil
+}
+
+func (csvCodec) Encode(w io.Writer, v any) error {
+	items, _ := v.([]any)
+	fields := make([]string, len(items))
+	for i, item := range items {
+		fields[i] = xmlScalar(item)
+	}
+	_, err := io.WriteString(w, strings.Join(fields, ","))
+	return err
+}
+
+func TestContentTranslation_RegisteredCodec(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{RouteConfigs: map[string]RouteConfig{
+		"/numbers": {ContentTranslation: &ContentTranslationConfig{ClientFormats: []string{"text/csv"}}},
+	}}
+	require.ErrorIs(t, m.resolveContentTranslation(), ErrContentCodecNotFound)
+
+	m.RegisterContentCodec("text/csv", csvCodec{})
+	require.NoError(t, m.resolveContentTranslation())
+	handler := m.withContentTranslation("/numbers", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", MediaTypeJSON)
+		_, _ = w.Write([]byte(`[1,2,3]`))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/numbers", nil)
+	req.Header.Set("Accept", "text/csv")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	assert.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "1,2,3", rec.Body.String())
+}
+
+func TestContentTranslationConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ContentTranslationConfig
+		wantErr bool
+	}{
+		{"valid", ContentTranslationConfig{ClientFormats: []string{MediaTypeXML}}, false},
+		{"no client formats", ContentTranslationConfig{}, true},
+		{"client format is backend format", ContentTranslationConfig{ClientFormats: []string{"application/json; charset=utf-8"}}, true},
+		{"invalid media type", ContentTranslationConfig{ClientFormats: []string{"xml"}}, true},
+		{"invalid xml root", ContentTranslationConfig{ClientFormats: []string{MediaTypeXML}, XMLRoot: "1root"}, true},
+		{"negative max body size", ContentTranslationConfig{ClientFormats: []string{MediaTypeXML}, MaxBodySize: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidContentTranslation)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -77,6 +77,7 @@ type DebugHandler struct {
 	logger          modular.Logger
 	circuitBreakers map[string]*CircuitBreaker
 	healthCheckers  map[string]*HealthChecker
+	sloStatus       func() []SLOStatus
 }
 
 // NewDebugHandler creates a new debug handler.
@@ -102,6 +103,11 @@ func (d *DebugHandler) SetHealthCheckers(healthCheckers map[string]*HealthChecke
 	d.healthCheckers = healthCheckers
 }
 
+// SetSLOStatusFunc sets the function reporting service level objective status.
+func (d *DebugHandler) SetSLOStatusFunc(sloStatus func() []SLOStatus) {
+	d.sloStatus = sloStatus
+}
+
 // RegisterRoutes registers debug endpoint routes with the provided mux.
 func (d *DebugHandler) RegisterRoutes(mux *http.ServeMux) {
 	if !d.config.Enabled {
@@ -123,6 +129,9 @@ func (d *DebugHandler) RegisterRoutes(mux *http.ServeMux) {
 	// Health check status endpoint
 	mux.HandleFunc(d.config.BasePath+"/health-checks", d.HandleHealthChecks)
 
+	// SLO status endpoint
+	mux.HandleFunc(d.config.BasePath+"/slo", d.HandleSLO)
+
 	d.logger.Info("Debug endpoints registered", "basePath", d.config.BasePath)
 }
 
@@ -463,6 +472,27 @@ func (d *DebugHandler) HandleHealthChecks(w http.ResponseWriter, r *http.Request
 	}
 }
 
+// HandleSLO handles the service level objectives debug endpoint.
+func (d *DebugHandler) HandleSLO(w http.ResponseWriter, r *http.Request) {
+	if !d.checkAuth(w, r) {
+		return
+	}
+
+	statuses := []SLOStatus{}
+	if d.sloStatus != nil {
+		if current := d.sloStatus(); current != nil {
+			statuses = current
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	sloResponse := map[string]interface{}{"timestamp": time.Now(), "slo": statuses}
+	if err := json.NewEncoder(w).Encode(sloResponse); err != nil {
+		d.logger.Error("Failed to encode SLO response", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+	}
+}
+
 // checkAuth checks authentication for debug endpoints.
 func (d *DebugHandler) checkAuth(w http.ResponseWriter, r *http.Request) bool {
 	if !d.config.RequireAuth {
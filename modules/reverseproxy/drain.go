@@ -0,0 +1,147 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultBackendDrainTimeout is how long RemoveBackend waits for in-flight requests
+// when ReverseProxyConfig.BackendDrainTimeout is not set.
+const DefaultBackendDrainTimeout = 30 * time.Second
+
+// backendDrainTracker counts in-flight requests per backend so that a backend
+// removal can stop accepting new requests and wait for the existing ones.
+// The zero value is ready to use.
+type backendDrainTracker struct {
+	mu       sync.Mutex
+	inFlight map[string]int
+	draining map[string]chan struct{} // closed once a draining backend has no requests left, which it keeps until finishDrain
+}
+
+// acquire registers a request to backend. It returns false when the backend is
+// draining; otherwise the returned function must be called when the request ends.
+func (t *backendDrainTracker) acquire(backend string) (func(), bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, draining := t.draining[backend]; draining {
+		return nil, false
+	}
+	if t.inFlight == nil {
+		t.inFlight = make(map[string]int)
+	}
+	t.inFlight[backend]++
+
+	var once sync.Once
+	return func() { once.Do(func() { t.release(backend) }) }, true
+}
+
+// release ends a request to backend, signalling a pending drain when it was the last one.
+func (t *backendDrainTracker) release(backend string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inFlight[backend]--
+	if t.inFlight[backend] > 0 {
+		return
+	}
+	delete(t.inFlight, backend)
+	if idle, draining := t.draining[backend]; draining {
+		close(idle)
+	}
+}
+
+// startDrain stops accepting requests to backend and returns a channel that is
+// closed once its in-flight requests have finished. A backend already draining
+// returns the channel of that drain.
+func (t *backendDrainTracker) startDrain(backend string) <-chan struct{} {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if idle, draining := t.draining[backend]; draining {
+		return idle
+	}
+	if t.draining == nil {
+		t.draining = make(map[string]chan struct{})
+	}
+	idle := make(chan struct{})
+	if t.inFlight[backend] == 0 {
+		close(idle)
+	}
+	t.draining[backend] = idle
+	return idle
+}
+
+// finishDrain accepts requests to backend again, e.g. once it has been removed and may be re-added.
+func (t *backendDrainTracker) finishDrain(backend string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.draining, backend)
+}
+
+// isDraining reports whether backend is draining and rejects new requests.
+func (t *backendDrainTracker) isDraining(backend string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, draining := t.draining[backend]
+	return draining
+}
+
+// inFlightCount returns the number of requests currently proxied to backend.
+func (t *backendDrainTracker) inFlightCount(backend string) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.inFlight[backend]
+}
+
+// drainBackend stops routing new requests to backend and waits for in-flight ones
+// until they finish, the drain timeout elapses or ctx is done, then emits backend.drained.
+// New requests stay rejected until the caller calls finishDrain.
+func (m *ReverseProxyModule) drainBackend(ctx context.Context, backendID string) {
+	timeout := DefaultBackendDrainTimeout
+	if m.config != nil && m.config.BackendDrainTimeout > 0 {
+		timeout = m.config.BackendDrainTimeout
+	}
+
+	start := time.Now()
+	inFlight := m.drains.inFlightCount(backendID)
+	idle := m.drains.startDrain(backendID)
+	if inFlight > 0 && m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Info("Draining backend before removal", "backend", backendID, "in_flight", inFlight, "timeout", timeout)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	remaining := m.drains.inFlightCount(backendID)
+
+	if remaining > 0 && m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Warn("Backend drain incomplete, removing with requests in flight",
+			"backend", backendID, "remaining", remaining, "waited", time.Since(start))
+	}
+	if m.initialized {
+		m.emitEvent(ctx, EventTypeBackendDrained, map[string]interface{}{
+			"backend":     backendID,
+			"in_flight":   inFlight,
+			"remaining":   remaining,
+			"duration_ms": time.Since(start).Milliseconds(),
+			"timed_out":   remaining > 0,
+		})
+	}
+}
+
+// closeProxyTransport closes the idle connections of a removed backend's proxy and
+// drops the request transports derived from it, leaving the module's shared HTTP
+// client transport untouched.
+func (m *ReverseProxyModule) closeProxyTransport(transport http.RoundTripper) {
+	if transport == nil || (m.httpClient != nil && transport == m.httpClient.Transport) {
+		return
+	}
+	if t, ok := transport.(interface{ CloseIdleConnections() }); ok {
+		t.CloseIdleConnections()
+	}
+	m.evictRequestTransports(transport)
+}
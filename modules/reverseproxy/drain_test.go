@@ -0,0 +1,155 @@
+package reverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDrainTestModule creates an initialized module whose "api" backend holds each
+// request until unblock is closed, signalling arrivals on entered.
+func newDrainTestModule(t *testing.T, drainTimeout time.Duration) (*ReverseProxyModule, *capturingSubject, chan struct{}, chan struct{}) {
+	t.Helper()
+
+	entered := make(chan struct{}, 4)
+	unblock := make(chan struct{})
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entered <- struct{}{}
+		select {
+		case <-unblock:
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+	t.Cleanup(func() {
+		select {
+		case <-unblock:
+		default:
+			close(unblock)
+		}
+	})
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices:     map[string]string{"api": backend.URL},
+		RequestTimeout:      5 * time.Second,
+		BackendDrainTimeout: drainTimeout,
+	})
+	return m, subject, entered, unblock
+}
+
+func TestRemoveBackend_DrainsInFlightRequests(t *testing.T) {
+	m, subject, entered, unblock := newDrainTestModule(t, 5*time.Second)
+	handler := m.createBackendProxyHandler("api")
+
+	inFlight := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		defer close(served)
+		handler(inFlight, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	}()
+	<-entered
+
+	removed := make(chan error, 1)
+	go func() { removed <- m.RemoveBackend("api") }()
+
+	// New requests are rejected once the backend drains. Probing before the drain starts
+	// would send a request that blocks in the backend, so wait for the drain first.
+	require.Eventually(t, func() bool {
+		m.drains.mu.Lock()
+		defer m.drains.mu.Unlock()
+		_, draining := m.drains.draining["api"]
+		return draining
+	}, time.Second, time.Millisecond)
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	select {
+	case <-removed:
+		t.Fatal("RemoveBackend returned before the in-flight request finished")
+	default:
+	}
+
+	close(unblock)
+	<-served
+	require.NoError(t, <-removed)
+	assert.Equal(t, http.StatusOK, inFlight.Code)
+
+	drained := subject.eventsOfType(EventTypeBackendDrained)
+	require.Len(t, drained, 1)
+	var data map[string]interface{}
+	require.NoError(t, drained[0].DataAs(&data))
+	assert.Equal(t, "api", data["backend"])
+	assert.InDelta(t, 1, data["in_flight"], 0)
+	assert.Equal(t, false, data["timed_out"])
+
+	// backend.drained precedes backend.removed
+	var types []string
+	for _, event := range subject.events {
+		if event.Type() == EventTypeBackendDrained || event.Type() == EventTypeBackendRemoved {
+			types = append(types, event.Type())
+		}
+	}
+	assert.Equal(t, []string{EventTypeBackendDrained, EventTypeBackendRemoved}, types)
+
+	_, exists := m.backendProxies["api"]
+	assert.False(t, exists)
+}
+
+func TestRemoveBackend_DrainTimeout(t *testing.T) {
+	m, subject, entered, _ := newDrainTestModule(t, 50*time.Millisecond)
+	handler := m.createBackendProxyHandler("api")
+
+	go handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	<-entered
+
+	start := time.Now()
+	require.NoError(t, m.RemoveBackend("api"))
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	drained := subject.eventsOfType(EventTypeBackendDrained)
+	require.Len(t, drained, 1)
+	var data map[string]interface{}
+	require.NoError(t, drained[0].DataAs(&data))
+	assert.Equal(t, true, data["timed_out"])
+	assert.InDelta(t, 1, data["remaining"], 0)
+	assert.Len(t, subject.eventsOfType(EventTypeBackendRemoved), 1)
+}
+
+func TestBackendDrainTracker(t *testing.T) {
+	var tracker backendDrainTracker
+
+	release, ok := tracker.acquire("api")
+	require.True(t, ok)
+	release()
+	release() // releasing twice is harmless
+	assert.Zero(t, tracker.inFlightCount("api"))
+
+	release, ok = tracker.acquire("api")
+	require.True(t, ok)
+	idle := tracker.startDrain("api")
+	assert.Equal(t, idle, tracker.startDrain("api"), "a second removal waits for the drain in progress")
+
+	_, ok = tracker.acquire("api")
+	assert.False(t, ok, "draining backend must reject new requests")
+	_, ok = tracker.acquire("other")
+	assert.True(t, ok, "other backends are unaffected")
+
+	select {
+	case <-idle:
+		t.Fatal("drain finished with a request in flight")
+	default:
+	}
+	release()
+	<-idle
+	<-tracker.startDrain("api")
+
+	tracker.finishDrain("api")
+	_, ok = tracker.acquire("api")
+	assert.True(t, ok, "backend accepts requests again after the drain finishes")
+}
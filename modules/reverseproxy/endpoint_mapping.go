@@ -30,4 +30,16 @@ type EndpointMapping struct {
 	// ResponseTransformer is a function that transforms multiple backend responses
 	// into a single composite response
 	ResponseTransformer func(ctx context.Context, req *http.Request, responses map[string]*http.Response) (*CompositeResponse, error)
+
+	// ResponseTransformerV2 transforms the backend responses like ResponseTransformer,
+	// and also receives the TransformContext of the request. It takes precedence over
+	// ResponseTransformer.
+	ResponseTransformerV2 EndpointResponseTransformerV2
+
+	// Metadata is passed to ResponseTransformerV2 in the TransformContext of each request
+	Metadata map[string]string
 }
+
+// EndpointResponseTransformerV2 transforms the responses of a custom endpoint's
+// backends into a single composite response, given the TransformContext of the request.
+type EndpointResponseTransformerV2 func(ctx context.Context, tc *TransformContext, req *http.Request, responses map[string]*http.Response) (*CompositeResponse, error)
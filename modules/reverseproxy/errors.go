@@ -27,6 +27,7 @@ var (
 	ErrInvalidDefaultFeatureFlagConfig = errors.New("invalid default configuration type for feature flags")
 	ErrConfigurationNotLoaded          = errors.New("configuration not loaded")
 	ErrBackendErrorStatus              = errors.New("backend returned non-success status")
+	ErrConfigValidationFailed          = errors.New("reverseproxy configuration validation failed")
 
 	// Feature flag evaluation sentinel errors
 	ErrNoDecision     = errors.New("no-decision")     // Evaluator abstains from making a decision
@@ -44,4 +45,102 @@ var (
 	ErrServiceURLRequired   = errors.New("service URL required")
 	ErrNoBackendsConfigured = errors.New("no backends configured")
 	ErrBackendNotConfigured = errors.New("backend not configured")
+	ErrBackendUnhealthy     = errors.New("backend unhealthy")
+
+	// Tenant TLS errors
+	ErrTenantTLSConfig          = errors.New("invalid tenant backend TLS configuration")
+	ErrTLSSecretResolverMissing = errors.New("tls secret_ref set but no secret resolver configured")
+	ErrTLSCertificateMissing    = errors.New("tls client certificate requires cert_file and key_file or secret_ref")
+	ErrTLSInvalidCABundle       = errors.New("no certificates found in CA bundle")
+
+	// Maintenance errors
+	ErrInvalidMaintenanceConfig = errors.New("invalid maintenance configuration")
+	ErrSchedulerUnsupported     = errors.New("scheduler service does not support recurring jobs")
+
+	// Upstream scheme errors
+	ErrInvalidUpstreamURL = errors.New("invalid upstream backend URL")
+
+	// Route middleware errors
+	ErrRouteMiddlewareNotFound = errors.New("route middleware not found")
+
+	// Event sampling errors
+	ErrInvalidSamplingRate = errors.New("event sampling rate must be between 0 and 1")
+
+	// Compression errors
+	ErrInvalidCompressionConfig = errors.New("invalid compression configuration")
+
+	// Scheduled route errors
+	ErrInvalidScheduledRoute = errors.New("invalid scheduled route")
+
+	// Connection pre-warming errors
+	ErrInvalidPrewarmConfig = errors.New("invalid prewarm configuration")
+	ErrBackendPrewarmFailed = errors.New("failed to pre-warm backend connections")
+
+	// Readiness gating errors
+	ErrInvalidReadinessConfig = errors.New("invalid readiness configuration")
+	ErrBackendNotReady        = errors.New("backend not ready")
+
+	// OpenAPI aggregation errors
+	ErrInvalidOpenAPIConfig = errors.New("invalid OpenAPI aggregation configuration")
+	ErrInvalidOpenAPISpec   = errors.New("invalid OpenAPI spec")
+
+	// SLO tracking errors
+	ErrInvalidSLOConfig = errors.New("invalid SLO configuration")
+
+	// Backend URL resolution errors
+	ErrInvalidBackendURLResolution = errors.New("invalid backend URL resolution configuration")
+
+	// Bandwidth throttling errors
+	ErrInvalidBandwidthConfig = errors.New("invalid bandwidth configuration")
+
+	// Rate limiting errors
+	ErrInvalidRateLimitConfig = errors.New("invalid rate limit configuration")
+
+	// Backend selection errors
+	ErrNoMatchingBackend = errors.New("no backend matches the labels")
+
+	// Load balancing errors
+	ErrInvalidLoadBalancingConfig = errors.New("invalid load balancing configuration")
+
+	// Content translation errors
+	ErrInvalidContentTranslation = errors.New("invalid content translation configuration")
+	ErrContentCodecNotFound      = errors.New("content codec not found")
+	ErrContentTranslationFailed  = errors.New("content translation failed")
+
+	// Content routing errors
+	ErrInvalidContentRouting = errors.New("invalid content routing configuration")
+
+	// Response validation errors
+	ErrInvalidResponseValidation = errors.New("invalid response validation configuration")
+
+	// Response cache errors
+	ErrInvalidCacheKeyConfig = errors.New("invalid cache key configuration")
+
+	// Tenant onboarding errors
+	ErrTenantIDEmpty                 = errors.New("tenant ID must not be empty")
+	ErrTenantServiceUnavailable      = errors.New("tenant service not available")
+	ErrInvalidTenantOnboardingConfig = errors.New("invalid tenant onboarding configuration")
+
+	// Fault injection errors
+	ErrInvalidFaultInjectionConfig = errors.New("invalid fault injection configuration")
+	ErrFaultInjectionInProduction  = errors.New("fault injection is not allowed in production")
+	ErrFaultInjectionNoProfile     = errors.New("fault injection requires an active non-production profile")
+	ErrFaultInjectionDisabled      = errors.New("fault injection is disabled")
+	ErrInjectedFault               = errors.New("injected fault")
+
+	// Retry policy errors
+	ErrInvalidRetryConfig = errors.New("invalid retry configuration")
+
+	// Locality errors
+	ErrInvalidLocalityConfig = errors.New("invalid locality configuration")
+
+	// Upload errors
+	ErrInvalidUploadConfig = errors.New("invalid upload configuration")
+
+	// Redirect errors
+	ErrInvalidRedirectConfig = errors.New("invalid redirect configuration")
+	ErrRedirectFailed        = errors.New("following backend redirect failed")
+
+	// Adaptive concurrency errors
+	ErrInvalidAdaptiveConcurrencyConfig = errors.New("invalid adaptive concurrency configuration")
 )
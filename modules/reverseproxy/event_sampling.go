@@ -0,0 +1,168 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// Metadata keys added to the data of events that have a sampling rule.
+const (
+	// EventDataSampleRate is the configured rate of the rule that admitted the event
+	EventDataSampleRate = "sample_rate"
+	// EventDataSampledCount is the number of events of the same type this event stands for:
+	// itself plus the events dropped since the previous rate-sampled one. Summing it over
+	// received events estimates the true event count.
+	EventDataSampledCount = "sampled_count"
+)
+
+// EventSamplingConfig configures probabilistic sampling of high-volume events such as
+// request.received, request.proxied and request.failed. Event types without a rule are
+// always emitted.
+//
+//	event_sampling:
+//	  rules:
+//	    com.modular.reverseproxy.request.received:
+//	      rate: 0.01
+//	    com.modular.reverseproxy.request.failed:
+//	      rate: 0.1
+//	      always_sample_errors: true
+//	      tenant_allowlist: ["tenant-under-investigation"]
+type EventSamplingConfig struct {
+	// Rules maps CloudEvent types to their sampling rule
+	Rules map[string]EventSamplingRule `json:"rules" yaml:"rules" toml:"rules"`
+}
+
+// EventSamplingRule controls how often events of one type are emitted.
+type EventSamplingRule struct {
+	// Rate is the fraction of events emitted, between 0 (none) and 1 (all)
+	Rate float64 `json:"rate" yaml:"rate" toml:"rate"`
+
+	// AlwaysSampleErrors emits events describing failures regardless of Rate: request.failed
+	// and request.timeout, and events carrying an error or a 5xx status
+	AlwaysSampleErrors bool `json:"always_sample_errors" yaml:"always_sample_errors" toml:"always_sample_errors"`
+
+	// TenantAllowlist lists tenants whose events are emitted regardless of Rate
+	TenantAllowlist []string `json:"tenant_allowlist" yaml:"tenant_allowlist" toml:"tenant_allowlist"`
+}
+
+// validate checks that every rule has a rate between 0 and 1.
+func (c *EventSamplingConfig) validate() error {
+	for eventType, rule := range c.Rules {
+		if rule.Rate < 0 || rule.Rate > 1 {
+			return fmt.Errorf("%w: %s has rate %v", ErrInvalidSamplingRate, eventType, rule.Rate)
+		}
+	}
+	return nil
+}
+
+// eventSampler applies sampling rules to events before they are emitted.
+type eventSampler struct {
+	rules         map[string]*samplingRule
+	tenantAllowed bool // whether any rule has a tenant allowlist
+}
+
+type samplingRule struct {
+	EventSamplingRule
+	tenants map[string]struct{}
+	dropped atomic.Uint64 // events dropped since the last rate-sampled event
+}
+
+// newEventSampler builds a sampler from config. It returns nil when no rules are
+// configured; a nil sampler emits every event.
+func newEventSampler(config EventSamplingConfig) *eventSampler {
+	if len(config.Rules) == 0 {
+		return nil
+	}
+	sampler := &eventSampler{rules: make(map[string]*samplingRule, len(config.Rules))}
+	for eventType, rule := range config.Rules {
+		compiled := &samplingRule{EventSamplingRule: rule}
+		if len(rule.TenantAllowlist) > 0 {
+			compiled.tenants = make(map[string]struct{}, len(rule.TenantAllowlist))
+			for _, tenant := range rule.TenantAllowlist {
+				compiled.tenants[tenant] = struct{}{}
+			}
+			sampler.tenantAllowed = true
+		}
+		sampler.rules[eventType] = compiled
+	}
+	return sampler
+}
+
+// sample reports whether the event should be emitted. For event types with a rule it
+// adds sample_rate and sampled_count to data. Events admitted by the error or tenant
+// exemptions count only themselves, so extrapolating from sampled_count is not skewed
+// by them.
+func (s *eventSampler) sample(ctx context.Context, eventType string, data map[string]interface{}) bool {
+	if s == nil {
+		return true
+	}
+	rule, ok := s.rules[eventType]
+	if !ok {
+		return true
+	}
+
+	var sampledCount uint64
+	switch {
+	case rule.AlwaysSampleErrors && isErrorEvent(eventType, data), rule.allowsTenant(ctx, data):
+		sampledCount = 1
+	case rule.Rate >= 1 || (rule.Rate > 0 && rand.Float64() < rule.Rate): //nolint:gosec // sampling does not need a secure source
+		sampledCount = rule.dropped.Swap(0) + 1
+	default:
+		rule.dropped.Add(1)
+		return false
+	}
+
+	if data != nil {
+		data[EventDataSampleRate] = rule.Rate
+		data[EventDataSampledCount] = sampledCount
+	}
+	return true
+}
+
+// allowsTenant reports whether the event belongs to an allowlisted tenant, taken from
+// the event's "tenant" field or the context.
+func (r *samplingRule) allowsTenant(ctx context.Context, data map[string]interface{}) bool {
+	if len(r.tenants) == 0 {
+		return false
+	}
+	tenant, _ := data["tenant"].(string)
+	if tenant == "" {
+		tenantID, ok := modular.GetTenantIDFromContext(ctx)
+		if !ok {
+			return false
+		}
+		tenant = string(tenantID)
+	}
+	_, ok := r.tenants[tenant]
+	return ok
+}
+
+// isErrorEvent reports whether an event describes a failed request.
+func isErrorEvent(eventType string, data map[string]interface{}) bool {
+	if eventType == EventTypeRequestFailed || eventType == EventTypeRequestTimeout {
+		return true
+	}
+	if errValue, ok := data["error"]; ok && errValue != nil && errValue != "" {
+		return true
+	}
+	status, _ := data["status"].(int)
+	return status >= 500
+}
+
+// withRequestTenant stores the tenant from the request header in the request context,
+// unless the context already carries one.
+func withRequestTenant(r *http.Request, header string) *http.Request {
+	if _, ok := modular.GetTenantIDFromContext(r.Context()); ok {
+		return r
+	}
+	tenantID, ok := TenantIDFromRequest(header, r)
+	if !ok {
+		return r
+	}
+	return r.WithContext(modular.NewTenantContext(r.Context(), modular.TenantID(tenantID)))
+}
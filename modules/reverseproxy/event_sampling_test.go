@@ -0,0 +1,99 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventSampler_SampledCountAccountsForDroppedEvents(t *testing.T) {
+	sampler := newEventSampler(EventSamplingConfig{Rules: map[string]EventSamplingRule{
+		EventTypeRequestReceived: {Rate: 0.25},
+	}})
+
+	const total = 1000
+	var emitted, represented uint64
+	for range total {
+		data := map[string]interface{}{"path": "/api"}
+		if !sampler.sample(context.Background(), EventTypeRequestReceived, data) {
+			continue
+		}
+		emitted++
+		represented += data[EventDataSampledCount].(uint64)
+		assert.InDelta(t, 0.25, data[EventDataSampleRate], 0)
+	}
+
+	assert.Greater(t, emitted, uint64(0))
+	assert.Less(t, emitted, uint64(total))
+	pending := sampler.rules[EventTypeRequestReceived].dropped.Load()
+	assert.Equal(t, uint64(total), represented+pending)
+
+	// Event types without a rule are emitted untouched
+	data := map[string]interface{}{}
+	assert.True(t, sampler.sample(context.Background(), EventTypeBackendAdded, data))
+	assert.Empty(t, data)
+}
+
+func TestEventSampler_Exemptions(t *testing.T) {
+	sampler := newEventSampler(EventSamplingConfig{Rules: map[string]EventSamplingRule{
+		EventTypeRequestFailed:   {Rate: 0, AlwaysSampleErrors: true},
+		EventTypeRequestProxied:  {Rate: 0, AlwaysSampleErrors: true},
+		EventTypeRequestReceived: {Rate: 0, TenantAllowlist: []string{"tenant-a"}},
+	}})
+	ctx := context.Background()
+
+	assert.True(t, sampler.sample(ctx, EventTypeRequestFailed, map[string]interface{}{"error": "boom"}))
+	assert.True(t, sampler.sample(ctx, EventTypeRequestProxied, map[string]interface{}{"status": 502}))
+	assert.False(t, sampler.sample(ctx, EventTypeRequestProxied, map[string]interface{}{"status": 200}))
+
+	assert.True(t, sampler.sample(ctx, EventTypeRequestReceived, map[string]interface{}{"tenant": "tenant-a"}))
+	tenantCtx := modular.NewTenantContext(ctx, "tenant-a")
+	data := map[string]interface{}{}
+	assert.True(t, sampler.sample(tenantCtx, EventTypeRequestReceived, data))
+	assert.Equal(t, uint64(1), data[EventDataSampledCount], "exempt events only count themselves")
+	assert.False(t, sampler.sample(ctx, EventTypeRequestReceived, map[string]interface{}{"tenant": "tenant-b"}))
+}
+
+func TestEventSampling_AppliedToRequestEvents(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		TenantIDHeader:  "X-Tenant-ID",
+		RequestTimeout:  5 * time.Second,
+		EventSampling: EventSamplingConfig{Rules: map[string]EventSamplingRule{
+			EventTypeRequestReceived: {Rate: 0, TenantAllowlist: []string{"tenant-a"}},
+		}},
+	})
+	m.eventSampler = newEventSampler(m.config.EventSampling)
+	handler := m.createBackendProxyHandler("api")
+
+	for _, tenant := range []string{"", "tenant-b", "tenant-a"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+		if tenant != "" {
+			req.Header.Set("X-Tenant-ID", tenant)
+		}
+		handler(httptest.NewRecorder(), req)
+	}
+
+	received := subject.eventsOfType(EventTypeRequestReceived)
+	require.Len(t, received, 1, "only the allowlisted tenant's request is emitted")
+	var data map[string]interface{}
+	require.NoError(t, received[0].DataAs(&data))
+	assert.InDelta(t, 1, data[EventDataSampledCount], 0)
+	assert.Len(t, subject.eventsOfType(EventTypeRequestProxied), 3, "event types without a rule are not sampled")
+}
+
+func TestEventSamplingConfig_InvalidRate(t *testing.T) {
+	config := EventSamplingConfig{Rules: map[string]EventSamplingRule{EventTypeRequestReceived: {Rate: 1.5}}}
+	require.ErrorIs(t, config.validate(), ErrInvalidSamplingRate)
+}
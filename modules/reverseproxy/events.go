@@ -6,6 +6,7 @@ const (
 	// Configuration events
 	EventTypeConfigLoaded    = "com.modular.reverseproxy.config.loaded"
 	EventTypeConfigValidated = "com.modular.reverseproxy.config.validated"
+	EventTypeConfigReloaded  = "com.modular.reverseproxy.config.reloaded"
 
 	// Proxy events
 	EventTypeProxyCreated = "com.modular.reverseproxy.proxy.created"
@@ -17,6 +18,11 @@ const (
 	EventTypeRequestProxied   = "com.modular.reverseproxy.request.proxied"
 	EventTypeRequestFailed    = "com.modular.reverseproxy.request.failed"
 	EventTypeRequestProcessed = "com.modular.reverseproxy.request.processed"
+	EventTypeRequestTimeout   = "com.modular.reverseproxy.request.timeout"
+
+	// EventTypeRequestClientAborted is emitted when the client disconnects before the
+	// backend responds. Such requests are not reported as failures.
+	EventTypeRequestClientAborted = "com.modular.reverseproxy.request.client_aborted"
 
 	// Dry-run events
 	EventTypeDryRunComparison = "com.modular.reverseproxy.dryrun.comparison"
@@ -27,10 +33,89 @@ const (
 	EventTypeBackendAdded     = "com.modular.reverseproxy.backend.added"
 	EventTypeBackendRemoved   = "com.modular.reverseproxy.backend.removed"
 
+	// EventTypeBackendDrained is emitted when a backend being removed has finished
+	// (or given up) waiting for its in-flight requests, just before backend.removed.
+	EventTypeBackendDrained = "com.modular.reverseproxy.backend.drained"
+
+	// Maintenance events
+	EventTypeMaintenanceEnabled  = "com.modular.reverseproxy.maintenance.enabled"
+	EventTypeMaintenanceDisabled = "com.modular.reverseproxy.maintenance.disabled"
+
+	// Tenant events
+
+	// EventTypeTenantTLSFailed is emitted when a tenant's backend client certificate
+	// cannot be loaded; that tenant's requests to the backend fail until it is fixed.
+	EventTypeTenantTLSFailed = "com.modular.reverseproxy.tenant.tls_failed"
+
+	// EventTypeTenantActivated is emitted when RegisterTenant has registered a tenant
+	// at runtime and its proxies and routes are serving.
+	EventTypeTenantActivated = "com.modular.reverseproxy.tenant.activated"
+
+	// Connection pre-warming events, emitted per backend during Start
+	EventTypeBackendPrewarmed     = "com.modular.reverseproxy.backend.prewarmed"
+	EventTypeBackendPrewarmFailed = "com.modular.reverseproxy.backend.prewarm_failed"
+
+	// Readiness events, emitted when a backend gating readiness first passes its probe
+	// and when Start stops waiting for one that hasn't
+	EventTypeBackendReady    = "com.modular.reverseproxy.backend.ready"
+	EventTypeBackendNotReady = "com.modular.reverseproxy.backend.not_ready"
+
+	// OpenAPI aggregation events, emitted when the aggregated document is rebuilt and
+	// when a backend's spec can't be fetched
+	EventTypeOpenAPIRefreshed   = "com.modular.reverseproxy.openapi.refreshed"
+	EventTypeOpenAPIFetchFailed = "com.modular.reverseproxy.openapi.fetch_failed"
+
+	// SLO events, emitted when a backend's or route's error budget runs out and
+	// when it is back within budget
+	EventTypeSLOBudgetExhausted = "com.modular.reverseproxy.slo.budget.exhausted"
+	EventTypeSLOBudgetRecovered = "com.modular.reverseproxy.slo.budget.recovered"
+
+	// Backend URL resolver events, emitted when the resolver fails for a request
+	EventTypeBackendURLResolveFailed = "com.modular.reverseproxy.backend.url.resolve_failed"
+
+	// EventTypeBandwidthRejected is emitted when a request is rejected because its
+	// tenant is over its bandwidth cap
+	EventTypeBandwidthRejected = "com.modular.reverseproxy.bandwidth.rejected"
+
+	// EventTypeRateLimited is emitted when a request is rejected because its tenant
+	// is over its request rate limit
+	EventTypeRateLimited = "com.modular.reverseproxy.ratelimit.rejected"
+
+	// EventTypeRateLimitStoreUnavailable is emitted when the store of distributed
+	// rate limits fails and the limits are enforced by each replica on its own
+	EventTypeRateLimitStoreUnavailable = "com.modular.reverseproxy.ratelimit.store_unavailable"
+
+	// EventTypeFaultInjected is emitted when a fault rule disturbs a backend request
+	EventTypeFaultInjected = "com.modular.reverseproxy.fault.injected"
+
+	// EventTypeRequestRetried is emitted when a failed backend request is retried
+	EventTypeRequestRetried = "com.modular.reverseproxy.request.retried"
+
+	// Scheduled route events, emitted when a rule's window opens or closes
+	EventTypeScheduledRouteActivated   = "com.modular.reverseproxy.scheduled_route.activated"
+	EventTypeScheduledRouteDeactivated = "com.modular.reverseproxy.scheduled_route.deactivated"
+
 	// Load balancing events
 	EventTypeLoadBalanceDecision   = "com.modular.reverseproxy.loadbalance.decision"
 	EventTypeLoadBalanceRoundRobin = "com.modular.reverseproxy.loadbalance.roundrobin"
 
+	// EventTypeLocalitySpillover is emitted when a request to a backend group is sent
+	// to farther backends because the closest ones are unavailable
+	EventTypeLocalitySpillover = "com.modular.reverseproxy.locality.spillover"
+
+	// EventTypeUploadFinished is emitted when an upload tracked for progress ends,
+	// completed or not
+	EventTypeUploadFinished = "com.modular.reverseproxy.upload.finished"
+
+	// EventTypeResponseValidationFailed is emitted when a backend response fails its
+	// route's response validation
+	EventTypeResponseValidationFailed = "com.modular.reverseproxy.response.validation_failed"
+
+	// Adaptive concurrency events, emitted when a backend's concurrency limit changes
+	// and when a request is shed because the backend is at its limit
+	EventTypeConcurrencyLimitChanged = "com.modular.reverseproxy.concurrency.limit_changed"
+	EventTypeConcurrencyShed         = "com.modular.reverseproxy.concurrency.shed"
+
 	// Circuit breaker events
 	EventTypeCircuitBreakerOpen     = "com.modular.reverseproxy.circuitbreaker.open"
 	EventTypeCircuitBreakerClosed   = "com.modular.reverseproxy.circuitbreaker.closed"
@@ -0,0 +1,636 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// Fault types, selecting how a FaultRule disturbs the requests it applies to.
+const (
+	// FaultTypeLatency delays the backend request by FaultRule.Latency
+	FaultTypeLatency = "latency"
+	// FaultTypeError answers with FaultRule.StatusCode without contacting the backend
+	FaultTypeError = "error"
+	// FaultTypeReset fails the backend request as if the connection had been reset
+	FaultTypeReset = "reset"
+	// FaultTypeTruncate ends the backend's response body after FaultRule.TruncateAfter
+	// bytes, while keeping its Content-Length
+	FaultTypeTruncate = "truncate"
+)
+
+// FaultInjectedHeader names the rule that answered a request with an error fault.
+const FaultInjectedHeader = "X-Fault-Injected"
+
+// defaultFaultInjectionBasePath is where the fault injection API is mounted when BasePath is unset.
+const defaultFaultInjectionBasePath = "/admin/faults"
+
+// maxFaultRuleBody limits the size of a rule posted to the fault injection API.
+const maxFaultRuleBody = 64 << 10
+
+// profileEnvVar is the environment variable modular reads the active configuration
+// profiles from when the application doesn't report them.
+const profileEnvVar = "APP_ENV"
+
+// defaultProductionProfiles are the profiles fault injection refuses to run in when
+// ProductionProfiles is unset.
+var defaultProductionProfiles = []string{"prod", "production"}
+
+// FaultInjectionConfig configures chaos testing of the proxy. Rules disturb a
+// percentage of the requests to a backend or route with added latency, error statuses,
+// connection resets or truncated bodies, injected at the backend transport so circuit
+// breakers, retries and error handling react as they would to a failing backend.
+// Rules can be changed at runtime through the HTTP API mounted at BasePath.
+//
+// Fault injection only starts while a non-production profile is active, as reported
+// by the application or the APP_ENV environment variable, and its API requires a
+// bearer token by default.
+//
+//	fault_injection:
+//	  enabled: true
+//	  require_auth: true
+//	  auth_token: ${FAULT_INJECTION_TOKEN}
+//	  rules:
+//	    - id: slow-payments
+//	      backend: payments
+//	      type: latency
+//	      latency: 2s
+//	      percentage: 25
+type FaultInjectionConfig struct {
+	// Enabled turns on fault injection and registers its API
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"FAULT_INJECTION_ENABLED"`
+
+	// ProductionProfiles lists the configuration profiles fault injection refuses to
+	// run in. Defaults to "prod" and "production".
+	ProductionProfiles []string `json:"production_profiles" yaml:"production_profiles" toml:"production_profiles"`
+
+	// BasePath is the path the API is mounted under
+	BasePath string `json:"base_path" yaml:"base_path" toml:"base_path" env:"FAULT_INJECTION_BASE_PATH" default:"/admin/faults"`
+
+	// RequireAuth requires an "Authorization: Bearer <auth_token>" header. Defaults to true.
+	RequireAuth bool `json:"require_auth" yaml:"require_auth" toml:"require_auth" env:"FAULT_INJECTION_REQUIRE_AUTH" default:"true"`
+
+	// AuthToken is the token required by the API. It is checked whenever it is set,
+	// even with RequireAuth off.
+	AuthToken string `json:"auth_token" yaml:"auth_token" toml:"auth_token" env:"FAULT_INJECTION_AUTH_TOKEN"` //nolint:gosec // G117: auth_token is an endpoint configuration field, not a credential
+
+	// Rules are the faults injected from startup
+	Rules []FaultRule `json:"rules" yaml:"rules" toml:"rules"`
+}
+
+// FaultRule injects a fault into a percentage of the requests to a backend or route.
+// The first rule matching a request and winning its roll applies.
+type FaultRule struct {
+	// ID identifies the rule in the API and events
+	ID string `json:"id" yaml:"id" toml:"id"`
+
+	// Backend limits the rule to requests proxied to this backend. Empty matches all.
+	Backend string `json:"backend,omitempty" yaml:"backend" toml:"backend"`
+
+	// Route limits the rule to request paths matching this route pattern. Empty matches all.
+	Route string `json:"route,omitempty" yaml:"route" toml:"route"`
+
+	// Type is the fault to inject: "latency", "error", "reset" or "truncate"
+	Type string `json:"type" yaml:"type" toml:"type"`
+
+	// Percentage of matching requests the fault is injected into, above 0 and up to 100
+	Percentage float64 `json:"percentage" yaml:"percentage" toml:"percentage"`
+
+	// Latency is the delay added by latency faults
+	Latency time.Duration `json:"latency,omitempty" yaml:"latency" toml:"latency"`
+
+	// StatusCode is the status answered by error faults. Default 503.
+	StatusCode int `json:"status_code,omitempty" yaml:"status_code" toml:"status_code"`
+
+	// TruncateAfter is the number of body bytes truncate faults let through
+	TruncateAfter int64 `json:"truncate_after,omitempty" yaml:"truncate_after" toml:"truncate_after"`
+}
+
+// faultRuleJSON is the JSON form of a FaultRule, with the latency as a duration string
+// such as "250ms".
+type faultRuleJSON struct {
+	ID            string  `json:"id"`
+	Backend       string  `json:"backend,omitempty"`
+	Route         string  `json:"route,omitempty"`
+	Type          string  `json:"type"`
+	Percentage    float64 `json:"percentage"`
+	Latency       string  `json:"latency,omitempty"`
+	StatusCode    int     `json:"status_code,omitempty"`
+	TruncateAfter int64   `json:"truncate_after,omitempty"`
+}
+
+// MarshalJSON writes the latency as a duration string.
+func (r FaultRule) MarshalJSON() ([]byte, error) {
+	out := faultRuleJSON{
+		ID: r.ID, Backend: r.Backend, Route: r.Route, Type: r.Type, Percentage: r.Percentage,
+		StatusCode: r.StatusCode, TruncateAfter: r.TruncateAfter,
+	}
+	if r.Latency != 0 {
+		out.Latency = r.Latency.String()
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal fault rule: %w", err)
+	}
+	return data, nil
+}
+
+// UnmarshalJSON reads the latency as a duration string.
+func (r *FaultRule) UnmarshalJSON(data []byte) error {
+	var in faultRuleJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return fmt.Errorf("failed to unmarshal fault rule: %w", err)
+	}
+	var latency time.Duration
+	if in.Latency != "" {
+		parsed, err := time.ParseDuration(in.Latency)
+		if err != nil {
+			return fmt.Errorf("%w: latency: %w", ErrInvalidFaultInjectionConfig, err)
+		}
+		latency = parsed
+	}
+	*r = FaultRule{
+		ID: in.ID, Backend: in.Backend, Route: in.Route, Type: in.Type, Percentage: in.Percentage,
+		Latency: latency, StatusCode: in.StatusCode, TruncateAfter: in.TruncateAfter,
+	}
+	return nil
+}
+
+// validate checks the type, percentage and the parameters of the rule's fault type.
+func (r *FaultRule) validate() error {
+	if r.ID == "" || strings.Contains(r.ID, "/") {
+		return fmt.Errorf("%w: rule id %q must be non-empty and contain no slash", ErrInvalidFaultInjectionConfig, r.ID)
+	}
+	if r.Percentage <= 0 || r.Percentage > 100 {
+		return fmt.Errorf("%w: rule %s percentage %g must be above 0 and at most 100", ErrInvalidFaultInjectionConfig, r.ID, r.Percentage)
+	}
+	switch r.Type {
+	case FaultTypeLatency:
+		if r.Latency <= 0 {
+			return fmt.Errorf("%w: rule %s needs a positive latency", ErrInvalidFaultInjectionConfig, r.ID)
+		}
+	case FaultTypeError:
+		if r.StatusCode != 0 && (r.StatusCode < 400 || r.StatusCode > 599) {
+			return fmt.Errorf("%w: rule %s status_code %d must be 4xx or 5xx", ErrInvalidFaultInjectionConfig, r.ID, r.StatusCode)
+		}
+	case FaultTypeReset:
+	case FaultTypeTruncate:
+		if r.TruncateAfter < 0 {
+			return fmt.Errorf("%w: rule %s truncate_after %d is negative", ErrInvalidFaultInjectionConfig, r.ID, r.TruncateAfter)
+		}
+	default:
+		return fmt.Errorf("%w: rule %s type %q must be %q, %q, %q or %q", ErrInvalidFaultInjectionConfig, r.ID, r.Type,
+			FaultTypeLatency, FaultTypeError, FaultTypeReset, FaultTypeTruncate)
+	}
+	return nil
+}
+
+// validate checks the API settings and every rule, which must have unique IDs.
+func (c *FaultInjectionConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.RequireAuth && c.AuthToken == "" {
+		return fmt.Errorf("%w: require_auth is set but auth_token is empty", ErrInvalidFaultInjectionConfig)
+	}
+	seen := make(map[string]bool, len(c.Rules))
+	for i := range c.Rules {
+		if err := c.Rules[i].validate(); err != nil {
+			return err
+		}
+		if seen[c.Rules[i].ID] {
+			return fmt.Errorf("%w: duplicate rule id %s", ErrInvalidFaultInjectionConfig, c.Rules[i].ID)
+		}
+		seen[c.Rules[i].ID] = true
+	}
+	return nil
+}
+
+// basePath returns the configured base path without a trailing slash.
+func (c *FaultInjectionConfig) basePath() string {
+	if c.BasePath == "" {
+		return defaultFaultInjectionBasePath
+	}
+	return strings.TrimSuffix(c.BasePath, "/")
+}
+
+// checkProfiles fails closed: fault injection needs at least one active profile, and
+// none of them may be a production profile.
+func (c *FaultInjectionConfig) checkProfiles(active []string) error {
+	if len(active) == 0 {
+		return fmt.Errorf("%w: set a profile or %s", ErrFaultInjectionNoProfile, profileEnvVar)
+	}
+	if profile := c.productionProfile(active); profile != "" {
+		return fmt.Errorf("%w: profile %q is active", ErrFaultInjectionInProduction, profile)
+	}
+	return nil
+}
+
+// productionProfile returns the first active profile that is a production profile.
+func (c *FaultInjectionConfig) productionProfile(active []string) string {
+	production := c.ProductionProfiles
+	if len(production) == 0 {
+		production = defaultProductionProfiles
+	}
+	for _, profile := range active {
+		for _, candidate := range production {
+			if strings.EqualFold(profile, candidate) {
+				return profile
+			}
+		}
+	}
+	return ""
+}
+
+// profileAware is implemented by applications reporting their active configuration profiles.
+type profileAware interface {
+	Profiles() []string
+}
+
+// activeProfiles returns the active configuration profiles of app, falling back to
+// the comma-separated APP_ENV environment variable.
+func activeProfiles(app modular.Application) []string {
+	if aware, ok := app.(profileAware); ok {
+		return aware.Profiles()
+	}
+	var profiles []string
+	for _, profile := range strings.Split(os.Getenv(profileEnvVar), ",") {
+		if profile = strings.TrimSpace(profile); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// FaultRuleStatus is a fault rule and the number of requests it was injected into.
+type FaultRuleStatus struct {
+	Rule     FaultRule `json:"rule"`
+	Injected uint64    `json:"injected"`
+}
+
+// faultRuleState is an active rule and its injection count.
+type faultRuleState struct {
+	rule     FaultRule
+	injected atomic.Uint64
+}
+
+// faultInjector holds the active fault rules in evaluation order.
+type faultInjector struct {
+	mu    sync.RWMutex
+	rules []*faultRuleState
+}
+
+// newFaultInjector returns an injector with the configured rules, or nil when fault
+// injection is disabled.
+func newFaultInjector(cfg FaultInjectionConfig) *faultInjector {
+	if !cfg.Enabled {
+		return nil
+	}
+	f := &faultInjector{}
+	for _, rule := range cfg.Rules {
+		f.set(rule)
+	}
+	return f
+}
+
+// pick returns the first rule matching the backend and request path that wins its
+// percentage roll, or nil.
+func (f *faultInjector) pick(backend, path string, matchesRoute func(path, pattern string) bool) *faultRuleState {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for _, state := range f.rules {
+		rule := &state.rule
+		if rule.Backend != "" && rule.Backend != backend {
+			continue
+		}
+		if rule.Route != "" && !matchesRoute(path, rule.Route) {
+			continue
+		}
+		if rule.Percentage >= 100 || rand.Float64()*100 < rule.Percentage { //nolint:gosec // fault injection does not need a secure source
+			return state
+		}
+	}
+	return nil
+}
+
+// set adds rule, or replaces the rule with its ID in place. It reports whether the
+// rule is new.
+func (f *faultInjector) set(rule FaultRule) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, state := range f.rules {
+		if state.rule.ID == rule.ID {
+			f.rules[i] = &faultRuleState{rule: rule}
+			return false
+		}
+	}
+	f.rules = append(f.rules, &faultRuleState{rule: rule})
+	return true
+}
+
+// remove deletes the rule with id and reports whether it existed.
+func (f *faultInjector) remove(id string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, state := range f.rules {
+		if state.rule.ID == id {
+			f.rules = append(f.rules[:i], f.rules[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// clear deletes every rule.
+func (f *faultInjector) clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rules = nil
+}
+
+// statuses returns the rules in evaluation order with their injection counts.
+func (f *faultInjector) statuses() []FaultRuleStatus {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	statuses := make([]FaultRuleStatus, 0, len(f.rules))
+	for _, state := range f.rules {
+		statuses = append(statuses, FaultRuleStatus{Rule: state.rule, Injected: state.injected.Load()})
+	}
+	return statuses
+}
+
+// SetFaultRule adds a fault rule, or replaces the rule with the same ID, while the
+// application is running. It returns ErrFaultInjectionDisabled unless fault
+// injection is enabled, and errors wrapping ErrInvalidFaultInjectionConfig for
+// invalid rules.
+func (m *ReverseProxyModule) SetFaultRule(rule FaultRule) error {
+	if m.faults == nil {
+		return ErrFaultInjectionDisabled
+	}
+	if err := m.validateFaultRule(&rule); err != nil {
+		return err
+	}
+	m.faults.set(rule)
+	return nil
+}
+
+// RemoveFaultRule deletes the fault rule with id and reports whether it existed.
+func (m *ReverseProxyModule) RemoveFaultRule(id string) bool {
+	if m.faults == nil {
+		return false
+	}
+	return m.faults.remove(id)
+}
+
+// FaultRules returns the active fault rules in evaluation order with the number of
+// requests each was injected into.
+func (m *ReverseProxyModule) FaultRules() []FaultRuleStatus {
+	if m.faults == nil {
+		return nil
+	}
+	return m.faults.statuses()
+}
+
+// validateFaultRule validates rule and checks that its backend is known.
+func (m *ReverseProxyModule) validateFaultRule(rule *FaultRule) error {
+	if err := rule.validate(); err != nil {
+		return err
+	}
+	if rule.Backend != "" && m.config != nil {
+		if _, ok := m.config.BackendServices[rule.Backend]; !ok {
+			return fmt.Errorf("%w: rule %s has unknown backend %q", ErrInvalidFaultInjectionConfig, rule.ID, rule.Backend)
+		}
+	}
+	return nil
+}
+
+// faultKey is the context key of the fault selected for a request.
+type faultKey struct{}
+
+// selectFault rolls the fault rules for a request to backend and, when one applies,
+// returns the request carrying it for the backend transport to inject.
+func (m *ReverseProxyModule) selectFault(r *http.Request, backend string) *http.Request {
+	if m.faults == nil {
+		return r
+	}
+	state := m.faults.pick(backend, r.URL.Path, m.matchesRoute)
+	if state == nil {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), faultKey{}, state))
+}
+
+// faultTransport wraps base so it injects the fault selected for each request, or
+// returns base when fault injection is disabled.
+func (m *ReverseProxyModule) faultTransport(base http.RoundTripper) http.RoundTripper {
+	if m.faults == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &faultRoundTripper{base: base, module: m}
+}
+
+// faultProxy returns a copy of proxy whose transport injects faults, or proxy itself
+// when fault injection is disabled.
+func (m *ReverseProxyModule) faultProxy(proxy *httputil.ReverseProxy) *httputil.ReverseProxy {
+	if m.faults == nil {
+		return proxy
+	}
+	return &httputil.ReverseProxy{
+		Director:       proxy.Director,
+		Transport:      m.faultTransport(proxy.Transport),
+		FlushInterval:  proxy.FlushInterval,
+		ErrorLog:       proxy.ErrorLog,
+		BufferPool:     proxy.BufferPool,
+		ModifyResponse: proxy.ModifyResponse,
+		ErrorHandler:   proxy.ErrorHandler,
+	}
+}
+
+// faultRoundTripper injects the fault carried by a request's context.
+type faultRoundTripper struct {
+	base   http.RoundTripper
+	module *ReverseProxyModule
+}
+
+func (t *faultRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	state, ok := req.Context().Value(faultKey{}).(*faultRuleState)
+	if !ok {
+		return t.base.RoundTrip(req) //nolint:wrapcheck // transport errors are passed through unchanged
+	}
+	rule := &state.rule
+	state.injected.Add(1)
+	t.module.emitEvent(req.Context(), EventTypeFaultInjected, map[string]interface{}{
+		"rule":    rule.ID,
+		"type":    rule.Type,
+		"backend": rule.Backend,
+		"path":    req.URL.Path,
+	})
+
+	switch rule.Type {
+	case FaultTypeLatency:
+		timer := time.NewTimer(rule.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			return nil, fmt.Errorf("%w %s: %w", ErrInjectedFault, rule.ID, req.Context().Err())
+		}
+		return t.base.RoundTrip(req) //nolint:wrapcheck // transport errors are passed through unchanged
+	case FaultTypeError:
+		status := rule.StatusCode
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		body := fmt.Sprintf("Injected fault %s\n", rule.ID)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": {"text/plain; charset=utf-8"}, FaultInjectedHeader: {rule.ID}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	case FaultTypeReset:
+		return nil, fmt.Errorf("%w %s: %w", ErrInjectedFault, rule.ID, syscall.ECONNRESET)
+	case FaultTypeTruncate:
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err //nolint:wrapcheck // transport errors are passed through unchanged
+		}
+		resp.Body = &truncatedBody{ReadCloser: resp.Body, remaining: rule.TruncateAfter}
+		return resp, nil
+	}
+	return t.base.RoundTrip(req) //nolint:wrapcheck // transport errors are passed through unchanged
+}
+
+// truncatedBody ends a response body early. It reports a clean end of body so the
+// proxy finishes the response, which then falls short of its Content-Length.
+type truncatedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err //nolint:wrapcheck // body read errors are passed through unchanged
+}
+
+// registerFaultInjectionEndpoints mounts the fault injection API.
+func (m *ReverseProxyModule) registerFaultInjectionEndpoints() {
+	basePath := m.config.FaultInjection.basePath()
+	m.safeHandleFunc(basePath, m.handleFaultInjection)
+	m.safeHandleFunc(basePath+"/*", m.handleFaultInjection)
+	m.app.Logger().Warn("Fault injection enabled", "endpoint", basePath, "rules", len(m.config.FaultInjection.Rules))
+}
+
+// handleFaultInjection serves the fault injection API:
+//
+//	GET    {base_path}       lists the rules with their injection counts
+//	DELETE {base_path}       removes every rule
+//	GET    {base_path}/{id}  returns a rule
+//	PUT    {base_path}/{id}  adds or replaces a rule from its JSON
+//	DELETE {base_path}/{id}  removes a rule
+func (m *ReverseProxyModule) handleFaultInjection(w http.ResponseWriter, r *http.Request) {
+	cfg := m.config.FaultInjection
+	if (cfg.RequireAuth || cfg.AuthToken != "") && !checkBearerAuth(w, r, cfg.AuthToken) {
+		return
+	}
+
+	basePath := cfg.basePath()
+	id := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, basePath), "/")
+	if strings.Contains(id, "/") {
+		http.Error(w, "Expected "+basePath+"/{id}", http.StatusNotFound)
+		return
+	}
+
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			writeFaultJSON(w, http.StatusOK, map[string]interface{}{"rules": m.faults.statuses()})
+		case http.MethodDelete:
+			m.faults.clear()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		for _, status := range m.faults.statuses() {
+			if status.Rule.ID == id {
+				writeFaultJSON(w, http.StatusOK, status)
+				return
+			}
+		}
+		http.Error(w, "Fault rule not found", http.StatusNotFound)
+	case http.MethodPut:
+		var rule FaultRule
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxFaultRuleBody)).Decode(&rule); err != nil {
+			http.Error(w, "Invalid fault rule: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if rule.ID == "" {
+			rule.ID = id
+		}
+		if rule.ID != id {
+			http.Error(w, fmt.Sprintf("Rule id %q does not match the path", rule.ID), http.StatusBadRequest)
+			return
+		}
+		if err := m.validateFaultRule(&rule); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		status := http.StatusOK
+		if m.faults.set(rule) {
+			status = http.StatusCreated
+		}
+		m.app.Logger().Info("Fault rule set", "rule", rule.ID, "type", rule.Type, "backend", rule.Backend,
+			"route", rule.Route, "percentage", rule.Percentage)
+		writeFaultJSON(w, status, rule)
+	case http.MethodDelete:
+		if !m.faults.remove(id) {
+			http.Error(w, "Fault rule not found", http.StatusNotFound)
+			return
+		}
+		m.app.Logger().Info("Fault rule removed", "rule", id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeFaultJSON writes value as a JSON response with status.
+func writeFaultJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,294 @@
+package reverseproxy
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFaultTestModule starts a module proxying /api/* to a backend with fault injection
+// enabled, and returns the number of requests reaching the backend.
+func newFaultTestModule(t *testing.T, faults FaultInjectionConfig, configure func(*ReverseProxyConfig)) (*ReverseProxyModule, *testRouter, *capturingSubject, *atomic.Int64) {
+	t.Helper()
+	t.Setenv("APP_ENV", "staging")
+
+	var hits atomic.Int64
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	t.Cleanup(backend.Close)
+
+	faults.Enabled = true
+	cfg := &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL, "other": backend.URL},
+		Routes:          map[string]string{"/api/*": "api", "/other/*": "other"},
+		DefaultBackend:  "api",
+		TenantIDHeader:  "X-Tenant-ID",
+		RequestTimeout:  5 * time.Second,
+		FaultInjection:  faults,
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	m, router, subject := newStartedTestModule(t, cfg)
+	return m, router, subject, &hits
+}
+
+func TestFaultInjection_ConfigValidation(t *testing.T) {
+	valid := FaultRule{ID: "slow", Type: FaultTypeLatency, Latency: time.Second, Percentage: 10}
+	tests := []struct {
+		name   string
+		config FaultInjectionConfig
+	}{
+		{"missing id", FaultInjectionConfig{Rules: []FaultRule{{Type: FaultTypeReset, Percentage: 10}}}},
+		{"id with slash", FaultInjectionConfig{Rules: []FaultRule{{ID: "a/b", Type: FaultTypeReset, Percentage: 10}}}},
+		{"unknown type", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: "explode", Percentage: 10}}}},
+		{"zero percentage", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: FaultTypeReset}}}},
+		{"percentage above 100", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: FaultTypeReset, Percentage: 101}}}},
+		{"latency without duration", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: FaultTypeLatency, Percentage: 10}}}},
+		{"success status", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: FaultTypeError, StatusCode: 200, Percentage: 10}}}},
+		{"negative truncation", FaultInjectionConfig{Rules: []FaultRule{{ID: "x", Type: FaultTypeTruncate, TruncateAfter: -1, Percentage: 10}}}},
+		{"duplicate id", FaultInjectionConfig{Rules: []FaultRule{valid, valid}}},
+		{"auth without token", FaultInjectionConfig{RequireAuth: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Enabled = true
+			require.ErrorIs(t, tt.config.validate(), ErrInvalidFaultInjectionConfig)
+		})
+	}
+
+	config := FaultInjectionConfig{Enabled: true, Rules: []FaultRule{valid}}
+	require.NoError(t, config.validate())
+	config.Enabled = false
+	config.Rules = append(config.Rules, FaultRule{})
+	require.NoError(t, config.validate(), "disabled configurations are not validated")
+}
+
+func TestFaultInjection_RefusesProduction(t *testing.T) {
+	config := FaultInjectionConfig{Enabled: true}
+	assert.Equal(t, "prod", config.productionProfile([]string{"eu", "prod"}))
+	assert.Equal(t, "Production", config.productionProfile([]string{"Production"}))
+	assert.Empty(t, config.productionProfile([]string{"staging"}))
+	config.ProductionProfiles = []string{"live"}
+	assert.Equal(t, "live", config.productionProfile([]string{"live"}))
+	assert.Empty(t, config.productionProfile([]string{"prod"}))
+	require.NoError(t, config.checkProfiles([]string{"staging"}))
+	require.ErrorIs(t, config.checkProfiles(nil), ErrFaultInjectionNoProfile, "an unknown environment fails closed")
+	require.ErrorIs(t, config.checkProfiles([]string{"live"}), ErrFaultInjectionInProduction)
+
+	t.Setenv("APP_ENV", "eu, prod")
+	app := NewMockTenantApplication()
+	m := NewModule()
+	require.NoError(t, m.RegisterConfig(app))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://localhost:9000"},
+		FaultInjection:  FaultInjectionConfig{Enabled: true},
+	}))
+	require.ErrorIs(t, m.Init(app), ErrFaultInjectionInProduction)
+}
+
+func TestFaultInjection_UnknownBackend(t *testing.T) {
+	t.Setenv("APP_ENV", "staging")
+	app := NewMockTenantApplication()
+	m := NewModule()
+	require.NoError(t, m.RegisterConfig(app))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://localhost:9000"},
+		FaultInjection: FaultInjectionConfig{Enabled: true, Rules: []FaultRule{
+			{ID: "x", Backend: "missing", Type: FaultTypeReset, Percentage: 10},
+		}},
+	}))
+	require.ErrorIs(t, m.Init(app), ErrInvalidFaultInjectionConfig)
+}
+
+func TestFaultInjection_ErrorFault(t *testing.T) {
+	m, router, subject, hits := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "api-down", Backend: "api", Type: FaultTypeError, StatusCode: http.StatusBadGateway, Percentage: 100},
+	}}, nil)
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, "api-down", rec.Header().Get(FaultInjectedHeader))
+	assert.Equal(t, int64(0), hits.Load(), "error faults don't reach the backend")
+
+	rec = serveVia(router, http.MethodGet, "/other/users", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "other backends are not affected")
+	assert.Equal(t, int64(1), hits.Load())
+
+	rules := m.FaultRules()
+	require.Len(t, rules, 1)
+	assert.Equal(t, uint64(1), rules[0].Injected)
+	events := subject.eventsOfType(EventTypeFaultInjected)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "api-down", data["rule"])
+}
+
+func TestFaultInjection_ResetFault(t *testing.T) {
+	_, router, _, hits := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "reset", Route: "/api/orders/*", Type: FaultTypeReset, Percentage: 100},
+	}}, nil)
+
+	rec := serveVia(router, http.MethodGet, "/api/orders/1", "", "", "")
+	assert.GreaterOrEqual(t, rec.Code, http.StatusInternalServerError)
+	assert.Equal(t, int64(0), hits.Load())
+
+	rec = serveVia(router, http.MethodGet, "/api/users/1", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "routes not matching the rule are not affected")
+}
+
+func TestFaultInjection_LatencyFault(t *testing.T) {
+	_, router, _, hits := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "slow", Type: FaultTypeLatency, Latency: 100 * time.Millisecond, Percentage: 100},
+	}}, nil)
+
+	start := time.Now()
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, int64(1), hits.Load())
+}
+
+func TestFaultInjection_LatencyTriggersTimeout(t *testing.T) {
+	_, router, _, hits := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "slow", Type: FaultTypeLatency, Latency: 5 * time.Second, Percentage: 100},
+	}}, func(cfg *ReverseProxyConfig) { cfg.RequestTimeout = 100 * time.Millisecond })
+
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusGatewayTimeout, rec.Code)
+	assert.Equal(t, int64(0), hits.Load())
+}
+
+func TestFaultInjection_TruncateFault(t *testing.T) {
+	_, router, _, _ := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "cut", Type: FaultTypeTruncate, TruncateAfter: 10, Percentage: 100},
+	}}, nil)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/users")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, int64(100), resp.ContentLength)
+	body, err := io.ReadAll(resp.Body)
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF), "expected a truncated body, got %v", err)
+	assert.Len(t, body, 10)
+}
+
+func TestFaultInjection_OpensCircuitBreaker(t *testing.T) {
+	_, router, _, hits := newFaultTestModule(t, FaultInjectionConfig{Rules: []FaultRule{
+		{ID: "api-down", Backend: "api", Type: FaultTypeError, Percentage: 100},
+	}}, func(cfg *ReverseProxyConfig) {
+		cfg.CircuitBreakerConfig = CircuitBreakerConfig{Enabled: true, FailureThreshold: 2, OpenTimeout: time.Minute}
+	})
+
+	for range 2 {
+		assert.Equal(t, http.StatusServiceUnavailable, serveVia(router, http.MethodGet, "/api/users", "", "", "").Code)
+	}
+	rec := serveVia(router, http.MethodGet, "/api/users", "", "", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "CIRCUIT_OPEN")
+	assert.Equal(t, int64(0), hits.Load())
+}
+
+func TestFaultInjection_Percentage(t *testing.T) {
+	injector := newFaultInjector(FaultInjectionConfig{Enabled: true, Rules: []FaultRule{
+		{ID: "half", Type: FaultTypeReset, Percentage: 50},
+	}})
+	matchAll := func(string, string) bool { return true }
+
+	var picked int
+	for range 1000 {
+		if injector.pick("api", "/", matchAll) != nil {
+			picked++
+		}
+	}
+	assert.InDelta(t, 500, picked, 100)
+}
+
+func TestFaultInjection_HTTPAPI(t *testing.T) {
+	m, router, _, hits := newFaultTestModule(t, FaultInjectionConfig{RequireAuth: true, AuthToken: "secret"}, nil)
+
+	t.Run("requires auth", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serveVia(router, http.MethodGet, "/admin/faults", "", "", "").Code)
+		assert.Equal(t, http.StatusForbidden, serveVia(router, http.MethodGet, "/admin/faults", "", "wrong", "").Code)
+
+		_, tokenOnly, _, _ := newFaultTestModule(t, FaultInjectionConfig{AuthToken: "secret"}, nil)
+		assert.Equal(t, http.StatusUnauthorized, serveVia(tokenOnly, http.MethodGet, "/admin/faults", "", "", "").Code,
+			"a configured token is checked even with require_auth off")
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serveVia(router, http.MethodPut, "/admin/faults/x", "", "secret", `{`).Code)
+		assert.Equal(t, http.StatusBadRequest, serveVia(router, http.MethodPut, "/admin/faults/x", "", "secret", `{"latency":"soon"}`).Code)
+		assert.Equal(t, http.StatusBadRequest, serveVia(router, http.MethodPut, "/admin/faults/x", "", "secret", `{"id":"y"}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, serveVia(router, http.MethodPut, "/admin/faults/x", "", "secret", `{"type":"reset"}`).Code)
+		assert.Equal(t, http.StatusUnprocessableEntity, serveVia(router, http.MethodPut, "/admin/faults/x", "", "secret",
+			`{"type":"reset","percentage":100,"backend":"missing"}`).Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, serveVia(router, http.MethodPost, "/admin/faults/x", "", "secret", "").Code)
+	})
+
+	t.Run("manages rules", func(t *testing.T) {
+		rec := serveVia(router, http.MethodPut, "/admin/faults/slow", "", "secret",
+			`{"type":"latency","latency":"10ms","percentage":100,"backend":"other"}`)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		assert.JSONEq(t, `{"id":"slow","backend":"other","type":"latency","latency":"10ms","percentage":100}`, rec.Body.String())
+
+		rec = serveVia(router, http.MethodPut, "/admin/faults/down", "", "secret", `{"type":"error","percentage":100,"route":"/api/down/*"}`)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		rec = serveVia(router, http.MethodPut, "/admin/faults/down", "", "secret", `{"type":"error","status_code":500,"percentage":100,"route":"/api/down/*"}`)
+		require.Equal(t, http.StatusOK, rec.Code, "replacing a rule")
+
+		assert.Equal(t, http.StatusInternalServerError, serveVia(router, http.MethodGet, "/api/down/1", "", "", "").Code)
+		assert.Equal(t, http.StatusOK, serveVia(router, http.MethodGet, "/api/up/1", "", "", "").Code)
+		assert.Equal(t, http.StatusOK, serveVia(router, http.MethodGet, "/other/1", "", "", "").Code)
+		assert.Equal(t, int64(2), hits.Load())
+
+		rec = serveVia(router, http.MethodGet, "/admin/faults", "", "secret", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"rules":[
+			{"rule":{"id":"slow","backend":"other","type":"latency","latency":"10ms","percentage":100},"injected":1},
+			{"rule":{"id":"down","route":"/api/down/*","type":"error","status_code":500,"percentage":100},"injected":1}
+		]}`, rec.Body.String())
+
+		rec = serveVia(router, http.MethodGet, "/admin/faults/slow", "", "secret", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Body.String(), `"injected":1`)
+		assert.Equal(t, http.StatusNotFound, serveVia(router, http.MethodGet, "/admin/faults/missing", "", "secret", "").Code)
+
+		assert.Equal(t, http.StatusNoContent, serveVia(router, http.MethodDelete, "/admin/faults/slow", "", "secret", "").Code)
+		assert.Equal(t, http.StatusNotFound, serveVia(router, http.MethodDelete, "/admin/faults/slow", "", "secret", "").Code)
+		assert.Equal(t, http.StatusInternalServerError, serveVia(router, http.MethodGet, "/api/down/1", "", "", "").Code)
+
+		assert.Equal(t, http.StatusNoContent, serveVia(router, http.MethodDelete, "/admin/faults", "", "secret", "").Code)
+		assert.Empty(t, m.FaultRules())
+		assert.Equal(t, http.StatusOK, serveVia(router, http.MethodGet, "/api/down/1", "", "", "").Code)
+	})
+}
+
+func TestFaultInjection_GoAPI(t *testing.T) {
+	disabled := NewModule()
+	require.ErrorIs(t, disabled.SetFaultRule(FaultRule{ID: "x", Type: FaultTypeReset, Percentage: 1}), ErrFaultInjectionDisabled)
+	assert.False(t, disabled.RemoveFaultRule("x"))
+	assert.Nil(t, disabled.FaultRules())
+
+	m, _, _, _ := newFaultTestModule(t, FaultInjectionConfig{}, nil)
+	require.ErrorIs(t, m.SetFaultRule(FaultRule{ID: "x", Type: FaultTypeReset}), ErrInvalidFaultInjectionConfig)
+	require.NoError(t, m.SetFaultRule(FaultRule{ID: "x", Type: FaultTypeReset, Percentage: 1}))
+	require.Len(t, m.FaultRules(), 1)
+	assert.True(t, m.RemoveFaultRule("x"))
+	assert.Empty(t, m.FaultRules())
+}
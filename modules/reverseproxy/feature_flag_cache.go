@@ -0,0 +1,136 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// featureFlagCacheKey identifies a memoized decision.
+type featureFlagCacheKey struct {
+	flagID   string
+	tenantID modular.TenantID
+}
+
+// featureFlagCacheEntry is a memoized decision and its expiry.
+type featureFlagCacheEntry struct {
+	value     bool
+	expiresAt time.Time
+}
+
+// CachingFeatureFlagEvaluator memoizes decisions of another FeatureFlagEvaluator,
+// keyed by flag and tenant, for a fixed TTL. Only successful decisions are cached;
+// errors such as ErrNoDecision or ErrFeatureFlagNotFound are always re-evaluated.
+//
+// Because the request is not part of the cache key, caching must only be enabled
+// when flag decisions do not depend on individual request attributes.
+type CachingFeatureFlagEvaluator struct {
+	inner   FeatureFlagEvaluator
+	ttl     time.Duration
+	metrics *MetricsCollector
+	now     func() time.Time
+
+	mu      sync.RWMutex
+	entries map[featureFlagCacheKey]featureFlagCacheEntry
+}
+
+// NewCachingFeatureFlagEvaluator wraps inner with a decision cache. Evaluation
+// latency and cache hits are recorded in metrics when it is non-nil.
+func NewCachingFeatureFlagEvaluator(inner FeatureFlagEvaluator, ttl time.Duration, metrics *MetricsCollector) *CachingFeatureFlagEvaluator {
+	return &CachingFeatureFlagEvaluator{
+		inner:   inner,
+		ttl:     ttl,
+		metrics: metrics,
+		now:     time.Now,
+		entries: make(map[featureFlagCacheKey]featureFlagCacheEntry),
+	}
+}
+
+// EvaluateFlag returns the cached decision for the flag and tenant if it has not
+// expired, otherwise evaluates it with the wrapped evaluator.
+func (c *CachingFeatureFlagEvaluator) EvaluateFlag(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request) (bool, error) {
+	start := c.now()
+	key := featureFlagCacheKey{flagID: flagID, tenantID: tenantID}
+
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && start.Before(entry.expiresAt) {
+		c.record(flagID, start, true)
+		return entry.value, nil
+	}
+
+	value, err := c.inner.EvaluateFlag(ctx, flagID, tenantID, req)
+	c.record(flagID, start, false)
+	if err != nil {
+		return value, err //nolint:wrapcheck // preserve sentinel errors from the wrapped evaluator
+	}
+
+	c.mu.Lock()
+	c.entries[key] = featureFlagCacheEntry{value: value, expiresAt: c.now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// EvaluateFlagWithDefault evaluates a feature flag, returning defaultValue on error.
+func (c *CachingFeatureFlagEvaluator) EvaluateFlagWithDefault(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request, defaultValue bool) bool {
+	value, err := c.EvaluateFlag(ctx, flagID, tenantID, req)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// Invalidate removes cached decisions for the given flags across all tenants.
+// With no arguments every cached decision is removed.
+func (c *CachingFeatureFlagEvaluator) Invalidate(flagIDs ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(flagIDs) == 0 {
+		c.entries = make(map[featureFlagCacheKey]featureFlagCacheEntry)
+		return
+	}
+
+	flags := make(map[string]bool, len(flagIDs))
+	for _, flagID := range flagIDs {
+		flags[flagID] = true
+	}
+	for key := range c.entries {
+		if flags[key.flagID] {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// InvalidateTenant removes all cached decisions for a tenant.
+func (c *CachingFeatureFlagEvaluator) InvalidateTenant(tenantID modular.TenantID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.tenantID == tenantID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *CachingFeatureFlagEvaluator) record(flagID string, start time.Time, cacheHit bool) {
+	if c.metrics != nil {
+		c.metrics.RecordFeatureFlagEvaluation(flagID, c.now().Sub(start), cacheHit)
+	}
+}
+
+// InvalidateFeatureFlagCache discards memoized feature flag decisions for the
+// given flags, or all decisions when called without arguments. Call it after
+// changing flag files or the state of an external flag service. It is a no-op
+// when feature_flags.decision_cache_ttl is not set.
+func (m *ReverseProxyModule) InvalidateFeatureFlagCache(flagIDs ...string) {
+	if cache, ok := m.featureFlagEvaluator.(*CachingFeatureFlagEvaluator); ok {
+		cache.Invalidate(flagIDs...)
+	}
+}
@@ -0,0 +1,110 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFlagEvaluator returns a fixed decision and counts evaluations
+type countingFlagEvaluator struct {
+	value bool
+	err   error
+	calls atomic.Int32
+}
+
+func (e *countingFlagEvaluator) EvaluateFlag(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request) (bool, error) {
+	e.calls.Add(1)
+	return e.value, e.err
+}
+
+func (e *countingFlagEvaluator) EvaluateFlagWithDefault(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request, defaultValue bool) bool {
+	value, err := e.EvaluateFlag(ctx, flagID, tenantID, req)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+func TestCachingFeatureFlagEvaluator_MemoizesPerFlagAndTenant(t *testing.T) {
+	inner := &countingFlagEvaluator{value: true}
+	metrics := NewMetricsCollector()
+	cache := NewCachingFeatureFlagEvaluator(inner, time.Minute, metrics)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		value, err := cache.EvaluateFlag(ctx, "new-api", "tenant-a", nil)
+		require.NoError(t, err)
+		assert.True(t, value)
+	}
+	assert.Equal(t, int32(1), inner.calls.Load())
+
+	// A different tenant is a different cache key
+	_, err := cache.EvaluateFlag(ctx, "new-api", "tenant-b", nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), inner.calls.Load())
+
+	flagMetrics := metrics.GetMetrics()["feature_flags"].(map[string]interface{})["new-api"].(map[string]interface{})
+	assert.Equal(t, 4, flagMetrics["evaluations"])
+	assert.Equal(t, 2, flagMetrics["cache_hits"])
+}
+
+func TestCachingFeatureFlagEvaluator_ExpiryAndInvalidation(t *testing.T) {
+	inner := &countingFlagEvaluator{value: true}
+	cache := NewCachingFeatureFlagEvaluator(inner, time.Minute, nil)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = cache.EvaluateFlag(ctx, "a", "t1", nil)
+	_, _ = cache.EvaluateFlag(ctx, "b", "t1", nil)
+	_, _ = cache.EvaluateFlag(ctx, "a", "t2", nil)
+	require.Equal(t, int32(3), inner.calls.Load())
+
+	cache.Invalidate("a")
+	_, _ = cache.EvaluateFlag(ctx, "a", "t1", nil)
+	_, _ = cache.EvaluateFlag(ctx, "b", "t1", nil)
+	_, _ = cache.EvaluateFlag(ctx, "a", "t2", nil)
+	assert.Equal(t, int32(5), inner.calls.Load(), "only flag a should be re-evaluated for every tenant")
+
+	cache.InvalidateTenant("t1")
+	_, _ = cache.EvaluateFlag(ctx, "b", "t1", nil)
+	_, _ = cache.EvaluateFlag(ctx, "a", "t2", nil)
+	assert.Equal(t, int32(6), inner.calls.Load(), "only tenant t1 should be re-evaluated")
+
+	now = now.Add(2 * time.Minute)
+	_, _ = cache.EvaluateFlag(ctx, "a", "t2", nil)
+	assert.Equal(t, int32(7), inner.calls.Load(), "expired decisions should be re-evaluated")
+}
+
+func TestCachingFeatureFlagEvaluator_ErrorsAreNotCached(t *testing.T) {
+	inner := &countingFlagEvaluator{err: ErrNoDecision}
+	cache := NewCachingFeatureFlagEvaluator(inner, time.Minute, nil)
+
+	assert.True(t, cache.EvaluateFlagWithDefault(context.Background(), "missing", "", nil, true))
+	_, err := cache.EvaluateFlag(context.Background(), "missing", "", nil)
+	assert.ErrorIs(t, err, ErrNoDecision)
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
+
+func TestReverseProxyModule_InvalidateFeatureFlagCache(t *testing.T) {
+	inner := &countingFlagEvaluator{value: true}
+	m := NewModule()
+	m.featureFlagEvaluator = NewCachingFeatureFlagEvaluator(inner, time.Minute, nil)
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	require.NoError(t, err)
+	assert.True(t, m.evaluateFeatureFlag("flag", req))
+	assert.True(t, m.evaluateFeatureFlag("flag", req))
+	assert.Equal(t, int32(1), inner.calls.Load())
+
+	m.InvalidateFeatureFlagCache()
+	assert.True(t, m.evaluateFeatureFlag("flag", req))
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
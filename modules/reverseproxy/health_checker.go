@@ -362,11 +362,24 @@ func (hc *HealthChecker) runPeriodicHealthCheck(backendID, baseURL string) {
 				hc.logger.Debug("Health check goroutine stopping - context cancelled", "backend", backendID)
 				return
 			}
+			if !hc.monitors(backendID, baseURL) {
+				hc.logger.Debug("Health check goroutine stopping - backend removed or moved", "backend", backendID)
+				return
+			}
 			hc.performHealthCheck(ctx, backendID, baseURL)
 		}
 	}
 }
 
+// monitors reports whether baseURL is still the URL of a backend to check, which
+// UpdateBackends changes.
+func (hc *HealthChecker) monitors(backendID, baseURL string) bool {
+	hc.statusMutex.RLock()
+	defer hc.statusMutex.RUnlock()
+	url, ok := hc.backends[backendID]
+	return ok && url == baseURL
+}
+
 // performHealthCheck performs a health check for a specific backend.
 func (hc *HealthChecker) performHealthCheck(ctx context.Context, backendID, baseURL string) {
 	// Early exit if context is already cancelled
@@ -665,7 +678,8 @@ func (hc *HealthChecker) isBackendHealthCheckEnabled(backendID string) bool {
 	return true
 }
 
-// UpdateBackends updates the list of backends to monitor.
+// UpdateBackends updates the list of backends to monitor. Backends with a new URL
+// start over with a fresh status, and checks of removed backends stop.
 func (hc *HealthChecker) UpdateBackends(ctx context.Context, backends map[string]string) {
 	// Clone incoming map first
 	cloned := make(map[string]string, len(backends))
@@ -684,7 +698,8 @@ func (hc *HealthChecker) UpdateBackends(ctx context.Context, backends map[string
 	// Track new backends to start goroutines after releasing status lock if running
 	newBackends := make(map[string]string)
 	for backendID, baseURL := range cloned {
-		if _, exists := hc.healthStatus[backendID]; !exists {
+		_, exists := hc.healthStatus[backendID]
+		if moved := hc.backends[backendID] != baseURL; !exists || moved {
 			hc.healthStatus[backendID] = &HealthStatus{
 				BackendID:   backendID,
 				URL:         baseURL,
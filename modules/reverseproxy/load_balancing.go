@@ -0,0 +1,144 @@
+package reverseproxy
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// Load balancing policies for backend groups, set per route with
+// RouteConfig.LoadBalancingPolicy.
+const (
+	// LoadBalancingRoundRobin rotates through the group's backends in turn (default)
+	LoadBalancingRoundRobin = "round_robin"
+	// LoadBalancingWeightedRoundRobin rotates through the backends in proportion to
+	// their weights, interleaving them smoothly
+	LoadBalancingWeightedRoundRobin = "weighted_round_robin"
+	// LoadBalancingLeastConnections picks the backend with the fewest in-flight
+	// requests relative to its weight
+	LoadBalancingLeastConnections = "least_connections"
+	// LoadBalancingRandom picks a backend at random in proportion to its weight
+	LoadBalancingRandom = "random"
+)
+
+// validateLoadBalancing checks the route's load balancing policy and weights.
+func (c *RouteConfig) validateLoadBalancing(backendServices map[string]string) error {
+	switch c.LoadBalancingPolicy {
+	case "", LoadBalancingRoundRobin, LoadBalancingWeightedRoundRobin, LoadBalancingLeastConnections, LoadBalancingRandom:
+	default:
+		return fmt.Errorf("%w: unknown policy %q", ErrInvalidLoadBalancingConfig, c.LoadBalancingPolicy)
+	}
+	for backendID, weight := range c.Weights {
+		if _, ok := backendServices[backendID]; !ok {
+			return fmt.Errorf("%w: weight for unknown backend %q", ErrInvalidLoadBalancingConfig, backendID)
+		}
+		if weight < 0 {
+			return fmt.Errorf("%w: negative weight for backend %q", ErrInvalidLoadBalancingConfig, backendID)
+		}
+	}
+	return nil
+}
+
+// backendWeight returns the weight of backend on route: the route's weight when set,
+// else the backend's, else 1.
+func (m *ReverseProxyModule) backendWeight(route RouteConfig, backend string) int {
+	if weight, ok := route.Weights[backend]; ok {
+		return weight
+	}
+	if m.config != nil {
+		if weight := m.config.BackendConfigs[backend].Weight; weight > 0 {
+			return weight
+		}
+	}
+	return 1
+}
+
+// pickBackend selects one of candidates with the route's policy, tracking rotation
+// state under counter. It must be called with loadBalanceMutex held.
+func (m *ReverseProxyModule) pickBackend(route RouteConfig, counter string, candidates []string) string {
+	if route.LoadBalancingPolicy == "" || route.LoadBalancingPolicy == LoadBalancingRoundRobin || len(candidates) == 1 {
+		selected := candidates[m.loadBalanceCounters[counter]%len(candidates)]
+		m.loadBalanceCounters[counter]++
+		return selected
+	}
+
+	weights := make([]int, len(candidates))
+	total := 0
+	for i, backend := range candidates {
+		weights[i] = m.backendWeight(route, backend)
+		total += weights[i]
+	}
+	// Without weight left among the candidates, e.g. when only a zero-weight canary is
+	// available, they share the traffic equally
+	if total == 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		total = len(weights)
+	}
+
+	switch route.LoadBalancingPolicy {
+	case LoadBalancingWeightedRoundRobin:
+		return m.pickSmoothWeighted(counter, candidates, weights, total)
+	case LoadBalancingLeastConnections:
+		return m.pickLeastConnections(counter, candidates, weights)
+	default: // LoadBalancingRandom
+		intN := m.loadBalanceRandom
+		if intN == nil {
+			intN = rand.IntN
+		}
+		n := intN(total)
+		for i, weight := range weights {
+			if n < weight {
+				return candidates[i]
+			}
+			n -= weight
+		}
+		return candidates[len(candidates)-1]
+	}
+}
+
+// pickSmoothWeighted implements smooth weighted round-robin: each backend gains its
+// weight on every pick and the one with the most is picked and loses the total, so a
+// 90/10 split sends the minority backend one request in ten rather than ten in a row.
+func (m *ReverseProxyModule) pickSmoothWeighted(counter string, candidates []string, weights []int, total int) string {
+	if m.loadBalanceWeights == nil {
+		m.loadBalanceWeights = make(map[string]map[string]int)
+	}
+	current := m.loadBalanceWeights[counter]
+	if current == nil {
+		current = make(map[string]int)
+		m.loadBalanceWeights[counter] = current
+	}
+	best := 0
+	for i, backend := range candidates {
+		current[backend] += weights[i]
+		if current[backend] > current[candidates[best]] {
+			best = i
+		}
+	}
+	current[candidates[best]] -= total
+	return candidates[best]
+}
+
+// pickLeastConnections picks the backend with the fewest in-flight requests per unit of
+// weight. Ties rotate so that idle backends share the traffic.
+func (m *ReverseProxyModule) pickLeastConnections(counter string, candidates []string, weights []int) string {
+	start := m.loadBalanceCounters[counter] % len(candidates)
+	m.loadBalanceCounters[counter]++
+	best, bestLoad, bestWeight := -1, 0, 0
+	for offset := range candidates {
+		i := (start + offset) % len(candidates)
+		if weights[i] == 0 {
+			continue
+		}
+		load := m.drains.inFlightCount(candidates[i])
+		// load/weight < bestLoad/bestWeight, without division
+		if best < 0 || load*bestWeight < bestLoad*weights[i] {
+			best, bestLoad, bestWeight = i, load, weights[i]
+		}
+	}
+	if best < 0 {
+		return candidates[start]
+	}
+	return candidates[best]
+}
@@ -0,0 +1,145 @@
+package reverseproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLoadBalancingTestModule returns a module with the backends stable, canary and spare.
+func newLoadBalancingTestModule() *ReverseProxyModule {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"stable": "http://stable.internal",
+			"canary": "http://canary.internal",
+			"spare":  "http://spare.internal",
+		},
+		BackendConfigs: map[string]BackendServiceConfig{"spare": {Weight: 2}},
+	}
+	return m
+}
+
+// selections returns how often each backend of group is selected in n requests.
+func selections(m *ReverseProxyModule, group string, route RouteConfig, n int) map[string]int {
+	counts := make(map[string]int)
+	for range n {
+		selected, _, _ := m.selectBackendFromGroup(context.Background(), group, route)
+		counts[selected]++
+	}
+	return counts
+}
+
+func TestLoadBalancing_WeightedRoundRobin(t *testing.T) {
+	m := newLoadBalancingTestModule()
+	route := RouteConfig{
+		LoadBalancingPolicy: LoadBalancingWeightedRoundRobin,
+		Weights:             map[string]int{"stable": 90, "canary": 10},
+	}
+
+	var sequence []string
+	for range 10 {
+		selected, _, _ := m.selectBackendFromGroup(context.Background(), "stable,canary", route)
+		sequence = append(sequence, selected)
+	}
+	assert.Equal(t, 1, countOf(sequence, "canary"), "the canary gets one request in ten")
+	assert.NotEqual(t, "canary", sequence[0], "the minority backend is interleaved, not served first")
+	assert.Equal(t, map[string]int{"stable": 900, "canary": 100}, selections(m, "stable,canary", route, 1000))
+
+	// Backend weights apply where the route sets none, and default to 1
+	route = RouteConfig{LoadBalancingPolicy: LoadBalancingWeightedRoundRobin}
+	assert.Equal(t, map[string]int{"stable": 100, "spare": 200}, selections(m, "stable,spare", route, 300))
+
+	// A zero weight takes a backend out of rotation
+	route.Weights = map[string]int{"canary": 0}
+	assert.Equal(t, map[string]int{"stable": 20}, selections(m, "stable,canary", route, 20))
+	assert.Equal(t, map[string]int{"canary": 3}, selections(m, "canary", route, 3),
+		"a group with no weight left is still served")
+}
+
+func TestLoadBalancing_LeastConnections(t *testing.T) {
+	m := newLoadBalancingTestModule()
+	route := RouteConfig{LoadBalancingPolicy: LoadBalancingLeastConnections}
+
+	release, ok := m.drains.acquire("stable")
+	require.True(t, ok)
+	assert.Equal(t, map[string]int{"canary": 4}, selections(m, "stable,canary", route, 4))
+	release()
+
+	assert.Equal(t, map[string]int{"stable": 2, "canary": 2}, selections(m, "stable,canary", route, 4),
+		"idle backends share the traffic")
+
+	// With two requests each, spare's weight of 2 makes it the least loaded
+	for _, backend := range []string{"stable", "stable", "spare", "spare"} {
+		release, ok := m.drains.acquire(backend)
+		require.True(t, ok)
+		defer release()
+	}
+	assert.Equal(t, map[string]int{"spare": 3}, selections(m, "stable,spare", route, 3))
+}
+
+func TestLoadBalancing_Random(t *testing.T) {
+	m := newLoadBalancingTestModule()
+	route := RouteConfig{LoadBalancingPolicy: LoadBalancingRandom, Weights: map[string]int{"stable": 3, "canary": 1}}
+	draws := []int{0, 2, 3, 1}
+	m.loadBalanceRandom = func(n int) int {
+		require.Equal(t, 4, n)
+		draw := draws[0]
+		draws = draws[1:]
+		return draw
+	}
+
+	var sequence []string
+	for range 4 {
+		selected, _, _ := m.selectBackendFromGroup(context.Background(), "stable,canary", route)
+		sequence = append(sequence, selected)
+	}
+	assert.Equal(t, []string{"stable", "stable", "canary", "stable"}, sequence)
+}
+
+func TestLoadBalancing_DecisionEventNamesPolicy(t *testing.T) {
+	m := newLoadBalancingTestModule()
+	subject := &capturingSubject{}
+	m.subject = subject
+	m.initialized = true
+
+	m.selectBackendFromGroup(context.Background(), "stable,canary", RouteConfig{LoadBalancingPolicy: LoadBalancingRandom})
+	m.selectBackendFromGroup(context.Background(), "stable,canary", RouteConfig{})
+
+	decisions := subject.eventsOfType(EventTypeLoadBalanceDecision)
+	require.Len(t, decisions, 2)
+	var data map[string]interface{}
+	require.NoError(t, decisions[0].DataAs(&data))
+	assert.Equal(t, LoadBalancingRandom, data["policy"])
+	assert.Len(t, subject.eventsOfType(EventTypeLoadBalanceRoundRobin), 1, "only round-robin selections emit the rotation event")
+}
+
+func TestRouteConfig_ValidateLoadBalancing(t *testing.T) {
+	backends := map[string]string{"stable": "http://stable.internal"}
+	valid := RouteConfig{LoadBalancingPolicy: LoadBalancingLeastConnections, Weights: map[string]int{"stable": 0}}
+	require.NoError(t, valid.validateLoadBalancing(backends))
+
+	for name, route := range map[string]RouteConfig{
+		"policy":          {LoadBalancingPolicy: "fastest"},
+		"unknown backend": {Weights: map[string]int{"other": 1}},
+		"negative weight": {Weights: map[string]int{"stable": -1}},
+	} {
+		assert.ErrorIs(t, route.validateLoadBalancing(backends), ErrInvalidLoadBalancingConfig, name)
+	}
+
+	m := newLoadBalancingTestModule()
+	m.config.BackendConfigs["canary"] = BackendServiceConfig{Weight: -1}
+	assert.ErrorIs(t, m.validateConfig(), ErrInvalidLoadBalancingConfig)
+}
+
+func countOf(values []string, value string) int {
+	count := 0
+	for _, v := range values {
+		if v == value {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,355 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Locality scopes, describing where a backend runs relative to the proxy instance.
+const (
+	// LocalitySameZone is a backend in the proxy's zone
+	LocalitySameZone = "same_zone"
+	// LocalityCrossZone is a backend in the proxy's region but another zone
+	LocalityCrossZone = "cross_zone"
+	// LocalityCrossRegion is a backend in another region
+	LocalityCrossRegion = "cross_region"
+	// LocalityUnknown is a backend without region or zone metadata
+	LocalityUnknown = "unknown"
+)
+
+// Reasons a backend is passed over by locality-aware selection.
+const (
+	localityReasonCircuitOpen = "circuit_open"
+	localityReasonUnhealthy   = "unhealthy"
+	localityReasonDraining    = "draining"
+	localityReasonSlow        = "slow"
+)
+
+// localityTiers are the scopes in order of preference. Untagged backends are
+// preferred least, like backends in other regions.
+var localityTiers = [][]string{
+	{LocalitySameZone},
+	{LocalityCrossZone},
+	{LocalityCrossRegion, LocalityUnknown},
+}
+
+// defaultLocalityLatencyCooldown is how long a slow backend is passed over before it
+// is tried again.
+const defaultLocalityLatencyCooldown = 30 * time.Second
+
+// localityLatencyWeight is the weight of a new sample in a backend's average latency.
+const localityLatencyWeight = 0.3
+
+// LocalityConfig makes backend groups prefer backends close to the proxy instance.
+// Backends are tagged with BackendServiceConfig.Region and Zone; a request routed to a
+// group such as "api-a,api-b,api-c" goes to a backend in the proxy's zone, then in its
+// region, then anywhere else, round-robin within each. A closer backend is passed over
+// when its circuit breaker is open, its health check fails, it is draining, or its
+// average latency exceeds LatencyThreshold.
+//
+//	locality:
+//	  enabled: true
+//	  region: us-east-1
+//	  zone: us-east-1a
+//	  latency_threshold: 250ms
+//	backend_configs:
+//	  api-a: {region: us-east-1, zone: us-east-1a}
+//	  api-b: {region: us-east-1, zone: us-east-1b}
+//	  api-c: {region: us-west-2, zone: us-west-2a}
+type LocalityConfig struct {
+	// Enabled turns on locality-aware selection from backend groups
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"LOCALITY_ENABLED"`
+
+	// Region is the region the proxy instance runs in
+	Region string `json:"region" yaml:"region" toml:"region" env:"LOCALITY_REGION"`
+
+	// Zone is the zone the proxy instance runs in
+	Zone string `json:"zone" yaml:"zone" toml:"zone" env:"LOCALITY_ZONE"`
+
+	// LatencyThreshold spills traffic over to farther backends while a backend's
+	// average response time exceeds it. Zero disables latency-based spillover.
+	LatencyThreshold time.Duration `json:"latency_threshold" yaml:"latency_threshold" toml:"latency_threshold" env:"LOCALITY_LATENCY_THRESHOLD"`
+
+	// LatencyCooldown is how long a backend that exceeded LatencyThreshold is passed
+	// over before it is tried again. Defaults to 30s.
+	LatencyCooldown time.Duration `json:"latency_cooldown" yaml:"latency_cooldown" toml:"latency_cooldown" env:"LOCALITY_LATENCY_COOLDOWN"`
+}
+
+// validate checks the locality configuration.
+func (c LocalityConfig) validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Region == "" && c.Zone == "" {
+		return fmt.Errorf("%w: region or zone is required", ErrInvalidLocalityConfig)
+	}
+	if c.LatencyThreshold < 0 {
+		return fmt.Errorf("%w: latency_threshold must not be negative", ErrInvalidLocalityConfig)
+	}
+	if c.LatencyCooldown < 0 {
+		return fmt.Errorf("%w: latency_cooldown must not be negative", ErrInvalidLocalityConfig)
+	}
+	return nil
+}
+
+// scope returns where a backend tagged with region and zone runs relative to the proxy.
+func (c LocalityConfig) scope(region, zone string) string {
+	switch {
+	case zone != "" && zone == c.Zone:
+		return LocalitySameZone
+	case region != "" && region == c.Region:
+		return LocalityCrossZone
+	case region == "" && zone == "":
+		return LocalityUnknown
+	default:
+		return LocalityCrossRegion
+	}
+}
+
+// LocalityStatus reports the traffic routed by locality-aware selection.
+type LocalityStatus struct {
+	Region string `json:"region,omitempty"`
+	Zone   string `json:"zone,omitempty"`
+	// Requests counts the requests routed to each scope
+	Requests map[string]uint64 `json:"requests"`
+	// Spillovers counts the requests sent farther than the closest backends because
+	// those were unavailable
+	Spillovers uint64                  `json:"spillovers"`
+	Backends   []BackendLocalityStatus `json:"backends"`
+}
+
+// BackendLocalityStatus reports the traffic routed to one backend of a group.
+type BackendLocalityStatus struct {
+	Backend  string `json:"backend"`
+	Region   string `json:"region,omitempty"`
+	Zone     string `json:"zone,omitempty"`
+	Scope    string `json:"scope"`
+	Requests uint64 `json:"requests"`
+	// AverageLatencyMs is the moving average of the backend's response time
+	AverageLatencyMs float64 `json:"average_latency_ms"`
+	// Slow reports whether the backend is passed over for exceeding LatencyThreshold
+	Slow bool `json:"slow"`
+}
+
+// localityRouter tracks the traffic and latency of backends selected from groups.
+type localityRouter struct {
+	config     LocalityConfig
+	now        func() time.Time
+	spillovers atomic.Uint64
+
+	mu       sync.Mutex
+	backends map[string]*localityBackend
+}
+
+// localityBackend is the traffic and latency of one backend.
+type localityBackend struct {
+	scope    string
+	requests uint64
+	latency  time.Duration // moving average
+	slowAt   time.Time     // when the average last exceeded the threshold
+}
+
+// newLocalityRouter returns the router for config, or nil when locality is disabled.
+func newLocalityRouter(config LocalityConfig) *localityRouter {
+	if !config.Enabled {
+		return nil
+	}
+	if config.LatencyCooldown == 0 {
+		config.LatencyCooldown = defaultLocalityLatencyCooldown
+	}
+	return &localityRouter{config: config, now: time.Now, backends: make(map[string]*localityBackend)}
+}
+
+// backend returns the stats of the named backend. The caller holds l.mu.
+func (l *localityRouter) backend(name string) *localityBackend {
+	b, ok := l.backends[name]
+	if !ok {
+		b = &localityBackend{}
+		l.backends[name] = b
+	}
+	return b
+}
+
+// slow reports whether the named backend exceeded the latency threshold within the
+// cooldown. Once the cooldown has passed its average is reset, so the next response
+// decides whether it is still slow.
+func (l *localityRouter) slow(name string) bool {
+	if l.config.LatencyThreshold <= 0 {
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.backends[name]
+	if !ok || b.slowAt.IsZero() {
+		return false
+	}
+	if l.now().Sub(b.slowAt) < l.config.LatencyCooldown {
+		return true
+	}
+	b.slowAt = time.Time{}
+	b.latency = 0
+	return false
+}
+
+// recordSelection counts a request routed to the named backend.
+func (l *localityRouter) recordSelection(name, scope string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.backend(name)
+	b.scope = scope
+	b.requests++
+}
+
+// observe folds a response time of the named backend into its average.
+func (l *localityRouter) observe(name string, latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b := l.backend(name)
+	if b.latency == 0 {
+		b.latency = latency
+	} else {
+		b.latency += time.Duration(localityLatencyWeight * float64(latency-b.latency))
+	}
+	if l.config.LatencyThreshold > 0 && b.latency > l.config.LatencyThreshold {
+		b.slowAt = l.now()
+	} else {
+		b.slowAt = time.Time{}
+	}
+}
+
+// status returns the locality counters; config returns a backend's region and zone.
+func (l *localityRouter) status(config func(backend string) (string, string)) LocalityStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	status := LocalityStatus{
+		Region:     l.config.Region,
+		Zone:       l.config.Zone,
+		Requests:   make(map[string]uint64),
+		Spillovers: l.spillovers.Load(),
+		Backends:   make([]BackendLocalityStatus, 0, len(l.backends)),
+	}
+	now := l.now()
+	for name, b := range l.backends {
+		region, zone := config(name)
+		status.Requests[b.scope] += b.requests
+		status.Backends = append(status.Backends, BackendLocalityStatus{
+			Backend:          name,
+			Region:           region,
+			Zone:             zone,
+			Scope:            b.scope,
+			Requests:         b.requests,
+			AverageLatencyMs: float64(b.latency) / float64(time.Millisecond),
+			Slow:             !b.slowAt.IsZero() && now.Sub(b.slowAt) < l.config.LatencyCooldown,
+		})
+	}
+	sort.Slice(status.Backends, func(i, j int) bool { return status.Backends[i].Backend < status.Backends[j].Backend })
+	return status
+}
+
+// writePrometheus writes the locality counters to p.
+func (l *localityRouter) writePrometheus(p *prometheusWriter, status LocalityStatus) {
+	p.family("reverseproxy_locality_requests_total", "counter")
+	for _, b := range status.Backends {
+		p.sample("reverseproxy_locality_requests_total", float64(b.Requests), "backend", b.Backend, "scope", b.Scope)
+	}
+	p.family("reverseproxy_locality_spillovers_total", "counter")
+	p.sample("reverseproxy_locality_spillovers_total", float64(status.Spillovers))
+}
+
+// LocalityStatus returns the traffic routed by locality-aware selection, or nil when
+// locality is disabled.
+func (m *ReverseProxyModule) LocalityStatus() *LocalityStatus {
+	if m.locality == nil {
+		return nil
+	}
+	status := m.locality.status(m.backendLocality)
+	return &status
+}
+
+// backendLocality returns the region and zone a backend is tagged with.
+func (m *ReverseProxyModule) backendLocality(backend string) (string, string) {
+	if m.config == nil {
+		return "", ""
+	}
+	cfg := m.config.BackendConfigs[backend]
+	return cfg.Region, cfg.Zone
+}
+
+// localityCandidates narrows the backends of group to the closest tier with available
+// backends, and returns the round-robin key of that tier. Without available backends
+// all of them are returned, as without locality.
+func (m *ReverseProxyModule) localityCandidates(ctx context.Context, group string, backends []string) ([]string, string) {
+	byScope := make(map[string][]string)
+	for _, backend := range backends {
+		scope := m.locality.config.scope(m.backendLocality(backend))
+		byScope[scope] = append(byScope[scope], backend)
+	}
+
+	var preferred string
+	passedOver := make(map[string]interface{})
+	for _, tier := range localityTiers {
+		var members, available []string
+		for _, scope := range tier {
+			members = append(members, byScope[scope]...)
+		}
+		for _, backend := range members {
+			if reason := m.localityUnavailable(backend); reason != "" {
+				passedOver[backend] = reason
+				continue
+			}
+			available = append(available, backend)
+		}
+		if len(members) > 0 && preferred == "" {
+			preferred = tier[0]
+		}
+		if len(available) == 0 {
+			continue
+		}
+		if preferred != tier[0] {
+			m.locality.spillovers.Add(1)
+			if m.initialized {
+				m.emitEvent(ctx, EventTypeLocalitySpillover, map[string]interface{}{
+					"group":       group,
+					"from_scope":  preferred,
+					"to_scope":    tier[0],
+					"passed_over": passedOver,
+				})
+			}
+		}
+		return available, group + "#" + tier[0]
+	}
+	return backends, group
+}
+
+// localityUnavailable returns why a backend should be passed over, or "" when it can
+// take traffic.
+func (m *ReverseProxyModule) localityUnavailable(backend string) string {
+	if reason := m.backendUnavailable(backend); reason != "" {
+		return reason
+	}
+	if m.locality.slow(backend) {
+		return localityReasonSlow
+	}
+	return ""
+}
+
+// withLocalityLatency serves handler for a backend selected from a group, recording
+// its response time unless the response is a server error.
+func (m *ReverseProxyModule) withLocalityLatency(backend string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.locality == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := m.locality.now()
+		writer := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+		handler(writer, r)
+		if writer.status < http.StatusInternalServerError {
+			m.locality.observe(backend, m.locality.now().Sub(start))
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLocalityTestModule starts a module in us-east-1a routing /api/* to the group
+// "near,same-region,far" and returns the router and the captured events.
+func newLocalityTestModule(t *testing.T, locality LocalityConfig, configure func(*ReverseProxyConfig)) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+
+	locality.Enabled = true
+	locality.Region = "us-east-1"
+	locality.Zone = "us-east-1a"
+	cfg := &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"near":        newNamedBackend(t, "near").URL,
+			"same-region": newNamedBackend(t, "same-region").URL,
+			"far":         newNamedBackend(t, "far").URL,
+		},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"near":        {Region: "us-east-1", Zone: "us-east-1a"},
+			"same-region": {Region: "us-east-1", Zone: "us-east-1b"},
+			"far":         {Region: "us-west-2", Zone: "us-west-2a"},
+		},
+		Routes:         map[string]string{"/api/*": "near,same-region,far"},
+		TenantIDHeader: "X-Tenant-ID",
+		RequestTimeout: 5 * time.Second,
+		Locality:       locality,
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	return newStartedTestModule(t, cfg)
+}
+
+// servedBy returns which backend served each of n requests to /api/data.
+func servedBy(t *testing.T, router *testRouter, n int) []string {
+	t.Helper()
+	served := make([]string, 0, n)
+	for range n {
+		rec := serveVia(router, http.MethodGet, "/api/data", "", "", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		served = append(served, rec.Body.String())
+	}
+	return served
+}
+
+func TestLocalityConfig_Validate(t *testing.T) {
+	assert.NoError(t, LocalityConfig{}.validate(), "disabled config is not validated")
+	assert.NoError(t, LocalityConfig{Enabled: true, Zone: "us-east-1a"}.validate())
+
+	tests := []struct {
+		name   string
+		config LocalityConfig
+	}{
+		{"no region or zone", LocalityConfig{}},
+		{"negative threshold", LocalityConfig{Region: "us-east-1", LatencyThreshold: -time.Second}},
+		{"negative cooldown", LocalityConfig{Region: "us-east-1", LatencyCooldown: -time.Second}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Enabled = true
+			require.ErrorIs(t, tt.config.validate(), ErrInvalidLocalityConfig)
+		})
+	}
+}
+
+func TestLocalityConfig_Scope(t *testing.T) {
+	config := LocalityConfig{Region: "us-east-1", Zone: "us-east-1a"}
+	assert.Equal(t, LocalitySameZone, config.scope("us-east-1", "us-east-1a"))
+	assert.Equal(t, LocalitySameZone, config.scope("", "us-east-1a"))
+	assert.Equal(t, LocalityCrossZone, config.scope("us-east-1", "us-east-1b"))
+	assert.Equal(t, LocalityCrossZone, config.scope("us-east-1", ""))
+	assert.Equal(t, LocalityCrossRegion, config.scope("us-west-2", "us-west-2a"))
+	assert.Equal(t, LocalityCrossRegion, config.scope("", "us-west-2a"))
+	assert.Equal(t, LocalityUnknown, config.scope("", ""))
+}
+
+func TestLocality_PrefersSameZone(t *testing.T) {
+	m, router, subject := newLocalityTestModule(t, LocalityConfig{}, nil)
+
+	assert.Equal(t, []string{"near", "near", "near"}, servedBy(t, router, 3))
+	assert.Empty(t, subject.eventsOfType(EventTypeLocalitySpillover))
+
+	status := m.LocalityStatus()
+	require.NotNil(t, status)
+	assert.Equal(t, map[string]uint64{LocalitySameZone: 3}, status.Requests)
+	assert.Zero(t, status.Spillovers)
+	require.Len(t, status.Backends, 1)
+	assert.Equal(t, BackendLocalityStatus{
+		Backend:          "near",
+		Region:           "us-east-1",
+		Zone:             "us-east-1a",
+		Scope:            LocalitySameZone,
+		Requests:         3,
+		AverageLatencyMs: status.Backends[0].AverageLatencyMs,
+	}, status.Backends[0])
+	assert.Positive(t, status.Backends[0].AverageLatencyMs)
+}
+
+func TestLocality_SpillsOverUnavailableBackends(t *testing.T) {
+	m, router, subject := newLocalityTestModule(t, LocalityConfig{}, nil)
+
+	breaker := NewCircuitBreaker("near", nil)
+	for range 5 {
+		breaker.RecordFailure()
+	}
+	m.circuitBreakers["near"] = breaker
+	assert.Equal(t, []string{"same-region", "same-region"}, servedBy(t, router, 2))
+
+	events := subject.eventsOfType(EventTypeLocalitySpillover)
+	require.Len(t, events, 2)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "near,same-region,far", data["group"])
+	assert.Equal(t, LocalitySameZone, data["from_scope"])
+	assert.Equal(t, LocalityCrossZone, data["to_scope"])
+	assert.Equal(t, map[string]interface{}{"near": localityReasonCircuitOpen}, data["passed_over"])
+
+	m.drains.startDrain("same-region")
+	assert.Equal(t, []string{"far"}, servedBy(t, router, 1))
+
+	status := m.LocalityStatus()
+	assert.Equal(t, map[string]uint64{LocalityCrossZone: 2, LocalityCrossRegion: 1}, status.Requests)
+	assert.Equal(t, uint64(3), status.Spillovers)
+
+	// Once every backend is unavailable, the group is served as without locality
+	m.drains.startDrain("far")
+	backends := []string{"near", "same-region", "far"}
+	candidates, counter := m.localityCandidates(context.Background(), "near,same-region,far", backends)
+	assert.Equal(t, backends, candidates)
+	assert.Equal(t, "near,same-region,far", counter)
+
+	breaker.Reset()
+	m.drains.finishDrain("same-region")
+	m.drains.finishDrain("far")
+	assert.Equal(t, []string{"near"}, servedBy(t, router, 1))
+}
+
+func TestLocality_LatencySpillover(t *testing.T) {
+	m, router, subject := newLocalityTestModule(t, LocalityConfig{
+		LatencyThreshold: 100 * time.Millisecond,
+		LatencyCooldown:  time.Minute,
+	}, nil)
+
+	// Each request to near appears to take 200ms
+	var clock time.Time
+	m.locality.now = func() time.Time { return clock }
+	near := m.withLocalityLatency("near", func(w http.ResponseWriter, r *http.Request) {
+		clock = clock.Add(200 * time.Millisecond)
+	})
+	near(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	assert.True(t, m.LocalityStatus().Backends[0].Slow)
+
+	assert.Equal(t, []string{"same-region"}, servedBy(t, router, 1))
+	events := subject.eventsOfType(EventTypeLocalitySpillover)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, map[string]interface{}{"near": localityReasonSlow}, data["passed_over"])
+
+	// After the cooldown near is tried again, and kept while it responds quickly
+	clock = clock.Add(time.Minute)
+	assert.Equal(t, []string{"near", "near"}, servedBy(t, router, 2))
+	assert.False(t, m.LocalityStatus().Backends[0].Slow)
+}
+
+func TestLocality_IgnoresServerErrorLatency(t *testing.T) {
+	m, _, _ := newLocalityTestModule(t, LocalityConfig{LatencyThreshold: 100 * time.Millisecond}, nil)
+
+	var clock time.Time
+	m.locality.now = func() time.Time { return clock }
+	failing := m.withLocalityLatency("near", func(w http.ResponseWriter, r *http.Request) {
+		clock = clock.Add(time.Second)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	failing(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/data", nil))
+	assert.False(t, m.locality.slow("near"))
+}
+
+func TestLocality_Metrics(t *testing.T) {
+	_, router, _ := newLocalityTestModule(t, LocalityConfig{}, func(cfg *ReverseProxyConfig) {
+		cfg.MetricsEnabled = true
+		cfg.MetricsEndpoint = "/metrics"
+	})
+	servedBy(t, router, 2)
+
+	rec := serveVia(router, http.MethodGet, "/metrics?format=prometheus", "", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `reverseproxy_locality_requests_total{backend="near",scope="same_zone"} 2`)
+	assert.Contains(t, rec.Body.String(), "reverseproxy_locality_spillovers_total 0")
+
+	rec = serveVia(router, http.MethodGet, "/metrics", "", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Locality LocalityStatus `json:"locality"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, map[string]uint64{LocalitySameZone: 2}, body.Locality.Requests)
+	assert.Equal(t, "us-east-1a", body.Locality.Zone)
+}
+
+func TestLocality_DisabledByDefault(t *testing.T) {
+	m := NewModule()
+	assert.Nil(t, m.LocalityStatus())
+	assert.Nil(t, newLocalityRouter(LocalityConfig{Region: "us-east-1"}))
+}
@@ -0,0 +1,442 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SchedulerServiceName is the name of the scheduler module's service, used to
+// schedule maintenance windows.
+const SchedulerServiceName = "scheduler.provider"
+
+// ConfigMaintenanceName is the name of the maintenance entry switched on by
+// MaintenanceConfig.Enabled.
+const ConfigMaintenanceName = "config"
+
+// DefaultMaintenanceMessage is the body of maintenance responses without a
+// configured message or page.
+const DefaultMaintenanceMessage = "Service temporarily unavailable for maintenance"
+
+// MaintenanceConfig puts backends, routes or tenants into maintenance: their requests
+// are answered with a 503 and a Retry-After header, or a static maintenance page,
+// without being proxied. Backends stay configured and are proxied to again as soon as
+// maintenance ends.
+//
+//	maintenance:
+//	  enabled: true
+//	  backends: [billing]
+//	  retry_after: 30m
+//	  page_file: /etc/proxy/maintenance.html
+//	  admin_endpoint: /admin/maintenance
+//	  admin_token: secret
+//	  windows:
+//	    - name: weekly-db-upgrade
+//	      schedule: "0 2 * * 0"
+//	      duration: 1h
+//	      backends: [orders]
+type MaintenanceConfig struct {
+	// Enabled puts the scope given by Backends, Routes and Tenants into maintenance
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"MAINTENANCE_ENABLED"`
+
+	// Backends, Routes and Tenants are the scope put into maintenance, see MaintenanceScope
+	Backends []string `json:"backends" yaml:"backends" toml:"backends"`
+	Routes   []string `json:"routes" yaml:"routes" toml:"routes"`
+	Tenants  []string `json:"tenants" yaml:"tenants" toml:"tenants"`
+
+	// StatusCode is the status of maintenance responses. Default 503.
+	StatusCode int `json:"status_code" yaml:"status_code" toml:"status_code" env:"MAINTENANCE_STATUS_CODE"`
+
+	// RetryAfter is sent as the Retry-After header of maintenance responses. Entries
+	// with an end time send the time remaining instead.
+	RetryAfter time.Duration `json:"retry_after" yaml:"retry_after" toml:"retry_after" env:"MAINTENANCE_RETRY_AFTER"`
+
+	// Message is the plain text body of maintenance responses
+	Message string `json:"message" yaml:"message" toml:"message" env:"MAINTENANCE_MESSAGE"`
+
+	// PageFile is an HTML page served as the body of maintenance responses instead
+	// of Message. It is read once during Init.
+	PageFile string `json:"page_file" yaml:"page_file" toml:"page_file" env:"MAINTENANCE_PAGE_FILE"`
+
+	// AdminEndpoint, if set, registers an endpoint listing maintenance entries (GET),
+	// switching one on (POST) and off (DELETE ?name=)
+	AdminEndpoint string `json:"admin_endpoint" yaml:"admin_endpoint" toml:"admin_endpoint" env:"MAINTENANCE_ADMIN_ENDPOINT"`
+
+	// AdminToken is the bearer token required by the admin endpoint. The endpoint
+	// is not registered without one.
+	AdminToken string `json:"admin_token" yaml:"admin_token" toml:"admin_token" env:"MAINTENANCE_ADMIN_TOKEN"` //nolint:gosec // G117: configuration field, not a credential
+
+	// Windows are recurring maintenance windows scheduled with the scheduler module
+	Windows []MaintenanceWindowConfig `json:"windows" yaml:"windows" toml:"windows"`
+}
+
+// MaintenanceScope selects the requests a maintenance entry applies to: those to one
+// of Backends, matching one of Routes, from one of Tenants. An empty list doesn't
+// restrict the scope, so an empty scope applies to every proxied request.
+type MaintenanceScope struct {
+	// Backends are backend IDs
+	Backends []string `json:"backends,omitempty"`
+
+	// Routes are route patterns, e.g. "/api/orders/*"
+	Routes []string `json:"routes,omitempty"`
+
+	// Tenants are tenant IDs, taken from the tenant ID header
+	Tenants []string `json:"tenants,omitempty"`
+}
+
+// MaintenanceWindowConfig is a recurring maintenance window: the scope is in
+// maintenance for Duration each time Schedule fires.
+type MaintenanceWindowConfig struct {
+	// Name identifies the window's maintenance entry and scheduler job
+	Name string `json:"name" yaml:"name" toml:"name"`
+
+	// Schedule is a cron expression, as accepted by the scheduler module
+	Schedule string `json:"schedule" yaml:"schedule" toml:"schedule"`
+
+	// Duration is how long each window lasts
+	Duration time.Duration `json:"duration" yaml:"duration" toml:"duration"`
+
+	// Backends, Routes and Tenants are the scope put into maintenance, see MaintenanceScope
+	Backends []string `json:"backends" yaml:"backends" toml:"backends"`
+	Routes   []string `json:"routes" yaml:"routes" toml:"routes"`
+	Tenants  []string `json:"tenants" yaml:"tenants" toml:"tenants"`
+}
+
+// scope returns the scope the configuration puts into maintenance.
+func (c *MaintenanceConfig) scope() MaintenanceScope {
+	return MaintenanceScope{Backends: c.Backends, Routes: c.Routes, Tenants: c.Tenants}
+}
+
+// scope returns the scope the window puts into maintenance.
+func (w *MaintenanceWindowConfig) scope() MaintenanceScope {
+	return MaintenanceScope{Backends: w.Backends, Routes: w.Routes, Tenants: w.Tenants}
+}
+
+// MaintenanceStatus describes an active maintenance entry.
+type MaintenanceStatus struct {
+	Name string `json:"name"`
+	MaintenanceScope
+	Since time.Time `json:"since"`
+	// Until is when the entry ends by itself, zero if it lasts until disabled
+	Until time.Time `json:"until,omitzero"`
+}
+
+// maintenanceEntry is an active maintenance entry.
+type maintenanceEntry struct {
+	scope MaintenanceScope
+	since time.Time
+	until time.Time
+}
+
+// maintenanceState holds the active maintenance entries by name. The zero value is
+// ready to use.
+type maintenanceState struct {
+	mu      sync.RWMutex
+	entries map[string]maintenanceEntry
+	page    []byte   // maintenance page read from PageFile
+	jobs    []string // scheduler jobs of the maintenance windows
+}
+
+// validate checks the maintenance windows and applies defaults.
+func (c *MaintenanceConfig) validate() error {
+	if c.StatusCode == 0 {
+		c.StatusCode = http.StatusServiceUnavailable
+	}
+	if c.StatusCode < 100 || c.StatusCode > 599 {
+		return fmt.Errorf("%w: invalid status code %d", ErrInvalidMaintenanceConfig, c.StatusCode)
+	}
+	if c.AdminEndpoint != "" && c.AdminToken == "" {
+		return fmt.Errorf("%w: admin_endpoint requires admin_token", ErrInvalidMaintenanceConfig)
+	}
+	names := make(map[string]bool, len(c.Windows))
+	for i, window := range c.Windows {
+		switch {
+		case window.Name == "":
+			return fmt.Errorf("%w: windows[%d]: name is required", ErrInvalidMaintenanceConfig, i)
+		case window.Name == ConfigMaintenanceName || names[window.Name]:
+			return fmt.Errorf("%w: %s: duplicate name", ErrInvalidMaintenanceConfig, window.Name)
+		case window.Schedule == "":
+			return fmt.Errorf("%w: %s: schedule is required", ErrInvalidMaintenanceConfig, window.Name)
+		case window.Duration <= 0:
+			return fmt.Errorf("%w: %s: duration must be positive", ErrInvalidMaintenanceConfig, window.Name)
+		}
+		names[window.Name] = true
+	}
+	return nil
+}
+
+// setupMaintenance loads the maintenance page and switches on the configured
+// maintenance.
+func (m *ReverseProxyModule) setupMaintenance() error {
+	cfg := &m.config.Maintenance
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	if cfg.PageFile != "" {
+		page, err := os.ReadFile(cfg.PageFile)
+		if err != nil {
+			return fmt.Errorf("%w: failed to read page file: %w", ErrInvalidMaintenanceConfig, err)
+		}
+		m.maintenance.page = page
+	}
+	if cfg.Enabled {
+		m.EnableMaintenance(ConfigMaintenanceName, cfg.scope(), 0)
+	}
+	return nil
+}
+
+// scheduleMaintenanceWindows registers the maintenance windows with the scheduler.
+func (m *ReverseProxyModule) scheduleMaintenanceWindows() error {
+	windows := m.config.Maintenance.Windows
+	if len(windows) == 0 {
+		return nil
+	}
+	if m.scheduler == nil {
+		return fmt.Errorf("%w: maintenance windows require the %s service", ErrInvalidMaintenanceConfig, SchedulerServiceName)
+	}
+	for _, window := range windows {
+		job := func(context.Context) error {
+			m.EnableMaintenance(window.Name, window.scope(), window.Duration)
+			return nil
+		}
+		jobID, err := scheduleRecurring(m.scheduler, "reverseproxy-maintenance-"+window.Name, window.Schedule, job)
+		if err != nil {
+			return fmt.Errorf("failed to schedule maintenance window %s: %w", window.Name, err)
+		}
+		m.maintenance.jobs = append(m.maintenance.jobs, jobID)
+	}
+	return nil
+}
+
+// cancelMaintenanceWindows removes the maintenance windows' scheduler jobs.
+func (m *ReverseProxyModule) cancelMaintenanceWindows() {
+	canceller, ok := m.scheduler.(interface{ CancelJob(jobID string) error })
+	if !ok {
+		return
+	}
+	for _, jobID := range m.maintenance.jobs {
+		if err := canceller.CancelJob(jobID); err != nil && m.app != nil {
+			m.app.Logger().Warn("Failed to cancel maintenance window", "job", jobID, "error", err)
+		}
+	}
+	m.maintenance.jobs = nil
+}
+
+// scheduleRecurring calls the scheduler module's
+// ScheduleRecurring(name, cronExpr string, jobFunc JobFunc) (string, error). JobFunc
+// is a named type of that module, which this module doesn't import, so the method
+// is called by reflection.
+func scheduleRecurring(scheduler any, name, cronExpr string, job func(context.Context) error) (string, error) {
+	method := reflect.ValueOf(scheduler).MethodByName("ScheduleRecurring")
+	if !method.IsValid() {
+		return "", fmt.Errorf("%w: %T has no ScheduleRecurring method", ErrSchedulerUnsupported, scheduler)
+	}
+	fn := reflect.ValueOf(job)
+	t := method.Type()
+	if t.NumIn() != 3 || t.In(0).Kind() != reflect.String || t.In(1).Kind() != reflect.String ||
+		!fn.Type().ConvertibleTo(t.In(2)) || t.NumOut() != 2 || t.Out(0).Kind() != reflect.String ||
+		t.Out(1) != reflect.TypeFor[error]() {
+		return "", fmt.Errorf("%w: unexpected ScheduleRecurring signature %s", ErrSchedulerUnsupported, t)
+	}
+	out := method.Call([]reflect.Value{reflect.ValueOf(name), reflect.ValueOf(cronExpr), fn.Convert(t.In(2))})
+	if err, _ := out[1].Interface().(error); err != nil {
+		return "", err
+	}
+	return out[0].String(), nil
+}
+
+// EnableMaintenance puts the scope into maintenance under name, replacing an entry
+// of that name. A positive duration ends the entry after it; otherwise it lasts
+// until DisableMaintenance is called.
+func (m *ReverseProxyModule) EnableMaintenance(name string, scope MaintenanceScope, duration time.Duration) {
+	now := time.Now()
+	entry := maintenanceEntry{scope: scope, since: now}
+	if duration > 0 {
+		entry.until = now.Add(duration)
+	}
+	m.maintenance.mu.Lock()
+	if m.maintenance.entries == nil {
+		m.maintenance.entries = make(map[string]maintenanceEntry)
+	}
+	m.maintenance.entries[name] = entry
+	m.maintenance.mu.Unlock()
+
+	data := map[string]interface{}{
+		"name":     name,
+		"backends": scope.Backends,
+		"routes":   scope.Routes,
+		"tenants":  scope.Tenants,
+	}
+	if !entry.until.IsZero() {
+		data["until"] = entry.until.Format(time.RFC3339)
+	}
+	m.emitEvent(context.Background(), EventTypeMaintenanceEnabled, data)
+}
+
+// DisableMaintenance ends the named maintenance entry, reporting whether it was active.
+func (m *ReverseProxyModule) DisableMaintenance(name string) bool {
+	m.maintenance.mu.Lock()
+	entry, ok := m.maintenance.entries[name]
+	delete(m.maintenance.entries, name)
+	m.maintenance.mu.Unlock()
+	active := ok && (entry.until.IsZero() || time.Now().Before(entry.until))
+	if active {
+		m.emitEvent(context.Background(), EventTypeMaintenanceDisabled, map[string]interface{}{"name": name})
+	}
+	return active
+}
+
+// MaintenanceStatus returns the active maintenance entries, ordered by name.
+func (m *ReverseProxyModule) MaintenanceStatus() []MaintenanceStatus {
+	now := time.Now()
+	m.maintenance.mu.RLock()
+	defer m.maintenance.mu.RUnlock()
+	statuses := make([]MaintenanceStatus, 0, len(m.maintenance.entries))
+	for name, entry := range m.maintenance.entries {
+		if !entry.until.IsZero() && !now.Before(entry.until) {
+			continue
+		}
+		statuses = append(statuses, MaintenanceStatus{Name: name, MaintenanceScope: entry.scope, Since: entry.since, Until: entry.until})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// matchingMaintenance returns the active maintenance entry applying to a request
+// for path from tenant to backend.
+func (m *ReverseProxyModule) matchingMaintenance(backend, path, tenant string) (maintenanceEntry, bool) {
+	m.maintenance.mu.RLock()
+	defer m.maintenance.mu.RUnlock()
+	if len(m.maintenance.entries) == 0 {
+		return maintenanceEntry{}, false
+	}
+	now := time.Now()
+	for _, entry := range m.maintenance.entries {
+		if !entry.until.IsZero() && !now.Before(entry.until) {
+			continue
+		}
+		scope := entry.scope
+		if len(scope.Backends) > 0 && !slices.Contains(scope.Backends, backend) {
+			continue
+		}
+		if len(scope.Tenants) > 0 && !slices.Contains(scope.Tenants, tenant) {
+			continue
+		}
+		if len(scope.Routes) > 0 && !slices.ContainsFunc(scope.Routes, func(route string) bool {
+			return m.matchesRoute(path, route)
+		}) {
+			continue
+		}
+		return entry, true
+	}
+	return maintenanceEntry{}, false
+}
+
+// serveMaintenance answers a request to backend with the maintenance response if
+// the request is in maintenance, reporting whether it did.
+func (m *ReverseProxyModule) serveMaintenance(w http.ResponseWriter, r *http.Request, backend string) bool {
+	entry, ok := m.matchingMaintenance(backend, r.URL.Path, r.Header.Get(m.config.TenantIDHeader))
+	if !ok {
+		return false
+	}
+	cfg := m.config.Maintenance
+	retryAfter := cfg.RetryAfter
+	if !entry.until.IsZero() {
+		retryAfter = time.Until(entry.until)
+	}
+	if retryAfter > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+	}
+	statusCode := cfg.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if m.maintenance.page != nil {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write(m.maintenance.page)
+		return true
+	}
+	message := cfg.Message
+	if message == "" {
+		message = DefaultMaintenanceMessage
+	}
+	http.Error(w, message, statusCode)
+	return true
+}
+
+// maintenanceRequest is the body of a POST to the maintenance admin endpoint.
+type maintenanceRequest struct {
+	Name string `json:"name"`
+	MaintenanceScope
+	// Duration is a Go duration string, e.g. "30m"; empty lasts until disabled
+	Duration string `json:"duration,omitempty"`
+}
+
+// handleMaintenanceAdmin serves the maintenance admin endpoint.
+func (m *ReverseProxyModule) handleMaintenanceAdmin(w http.ResponseWriter, r *http.Request) {
+	token := m.config.Maintenance.AdminToken
+	if token == "" {
+		// The endpoint is not registered without a token; never accept an empty one
+		http.NotFound(w, r)
+		return
+	}
+	if !checkBearerAuth(w, r, token) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		var req maintenanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid maintenance request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		var duration time.Duration
+		if req.Duration != "" {
+			parsed, err := time.ParseDuration(req.Duration)
+			if err != nil || parsed < 0 {
+				http.Error(w, "Invalid maintenance duration: "+req.Duration, http.StatusBadRequest)
+				return
+			}
+			duration = parsed
+		}
+		if len(req.Backends) == 0 && len(req.Routes) == 0 && len(req.Tenants) == 0 {
+			http.Error(w, "Maintenance request needs backends, routes or tenants", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			req.Name = "admin"
+		}
+		m.EnableMaintenance(req.Name, req.MaintenanceScope, duration)
+	case http.MethodDelete:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "Missing maintenance name", http.StatusBadRequest)
+			return
+		}
+		if !m.DisableMaintenance(name) {
+			http.Error(w, "Maintenance "+name+" is not active", http.StatusNotFound)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"maintenance": m.MaintenanceStatus()}); err != nil && m.app != nil {
+		m.app.Logger().Error("Failed to encode maintenance status", "error", err)
+	}
+}
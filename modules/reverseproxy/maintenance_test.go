@@ -0,0 +1,212 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMaintenanceTestModule creates a module proxying the "api" and "billing"
+// backends to a backend answering 200, with the given maintenance configuration.
+func newMaintenanceTestModule(t *testing.T, maintenance MaintenanceConfig) (*ReverseProxyModule, *capturingSubject) {
+	t.Helper()
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL, "billing": backend.URL},
+		TenantIDHeader:  "X-Tenant-ID",
+		RequestTimeout:  5 * time.Second,
+		Maintenance:     maintenance,
+	})
+	require.NoError(t, m.setupMaintenance())
+	return m, subject
+}
+
+func serveMaintenanceTest(m *ReverseProxyModule, backend, path, tenant string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if tenant != "" {
+		req.Header.Set("X-Tenant-ID", tenant)
+	}
+	rec := httptest.NewRecorder()
+	m.createBackendProxyHandler(backend)(rec, req)
+	return rec
+}
+
+func TestMaintenance_ConfiguredBackend(t *testing.T) {
+	m, subject := newMaintenanceTestModule(t, MaintenanceConfig{
+		Enabled:    true,
+		Backends:   []string{"billing"},
+		RetryAfter: 90 * time.Second,
+	})
+
+	rec := serveMaintenanceTest(m, "billing", "/billing/invoices", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), DefaultMaintenanceMessage)
+
+	rec = serveMaintenanceTest(m, "api", "/api/users", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "other backends are proxied")
+
+	require.Len(t, subject.eventsOfType(EventTypeMaintenanceEnabled), 1)
+	assert.True(t, m.DisableMaintenance(ConfigMaintenanceName))
+	assert.False(t, m.DisableMaintenance(ConfigMaintenanceName))
+	require.Len(t, subject.eventsOfType(EventTypeMaintenanceDisabled), 1)
+
+	rec = serveMaintenanceTest(m, "billing", "/billing/invoices", "")
+	assert.Equal(t, http.StatusOK, rec.Code, "the backend is proxied again once maintenance ends")
+}
+
+func TestMaintenance_Scope(t *testing.T) {
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{})
+
+	m.EnableMaintenance("orders", MaintenanceScope{Routes: []string{"/api/orders/*"}, Tenants: []string{"acme"}}, 0)
+	assert.Equal(t, http.StatusServiceUnavailable, serveMaintenanceTest(m, "api", "/api/orders/1", "acme").Code)
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "api", "/api/orders/1", "globex").Code, "other tenants are proxied")
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "api", "/api/users", "acme").Code, "other routes are proxied")
+
+	// Entries with a duration end by themselves and report the time remaining
+	m.EnableMaintenance("all", MaintenanceScope{}, 100*time.Millisecond)
+	rec := serveMaintenanceTest(m, "billing", "/billing", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "an empty scope applies to every request")
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	statuses := m.MaintenanceStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, "all", statuses[0].Name)
+	assert.False(t, statuses[0].Until.IsZero())
+
+	require.Eventually(t, func() bool {
+		return serveMaintenanceTest(m, "billing", "/billing", "").Code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	assert.Len(t, m.MaintenanceStatus(), 1)
+}
+
+func TestMaintenance_Page(t *testing.T) {
+	page := filepath.Join(t.TempDir(), "maintenance.html")
+	require.NoError(t, os.WriteFile(page, []byte("<h1>Back soon</h1>"), 0o600))
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{Enabled: true, PageFile: page, StatusCode: http.StatusOK})
+
+	rec := serveMaintenanceTest(m, "api", "/api/users", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "<h1>Back soon</h1>", rec.Body.String())
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+
+	m.config.Maintenance.PageFile = filepath.Join(t.TempDir(), "missing.html")
+	require.ErrorIs(t, m.setupMaintenance(), ErrInvalidMaintenanceConfig)
+}
+
+func TestMaintenance_AdminEndpoint(t *testing.T) {
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{AdminToken: "secret"})
+	admin := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		m.handleMaintenanceAdmin(rec, req)
+		return rec
+	}
+
+	rec := httptest.NewRecorder()
+	m.handleMaintenanceAdmin(rec, httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = admin(http.MethodPost, "/admin/maintenance", `{"name":"deploy","backends":["api"],"duration":"10m"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var listed struct {
+		Maintenance []MaintenanceStatus `json:"maintenance"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &listed))
+	require.Len(t, listed.Maintenance, 1)
+	assert.Equal(t, "deploy", listed.Maintenance[0].Name)
+	assert.Equal(t, []string{"api"}, listed.Maintenance[0].Backends)
+	assert.Equal(t, http.StatusServiceUnavailable, serveMaintenanceTest(m, "api", "/api/users", "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, admin(http.MethodPost, "/admin/maintenance", `{"duration":"soon"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, admin(http.MethodPost, "/admin/maintenance", `{"name":"all"}`).Code, "an empty scope is rejected")
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/admin/maintenance", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	m.handleMaintenanceAdmin(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, http.StatusNotFound, admin(http.MethodDelete, "/admin/maintenance?name=other", "").Code)
+	assert.Equal(t, http.StatusOK, admin(http.MethodDelete, "/admin/maintenance?name=deploy", "").Code)
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "api", "/api/users", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, admin(http.MethodPut, "/admin/maintenance", "").Code)
+}
+
+// testJobFunc mirrors the scheduler module's named job function type.
+type testJobFunc func(ctx context.Context) error
+
+// testScheduler records recurring jobs like the scheduler module's service.
+type testScheduler struct {
+	jobs      map[string]testJobFunc
+	cancelled []string
+}
+
+func (s *testScheduler) ScheduleRecurring(name string, cronExpr string, jobFunc testJobFunc) (string, error) {
+	if s.jobs == nil {
+		s.jobs = make(map[string]testJobFunc)
+	}
+	s.jobs[name+" "+cronExpr] = jobFunc
+	return name, nil
+}
+
+func (s *testScheduler) CancelJob(jobID string) error {
+	s.cancelled = append(s.cancelled, jobID)
+	return nil
+}
+
+func TestMaintenance_Windows(t *testing.T) {
+	window := MaintenanceWindowConfig{Name: "weekly", Schedule: "0 2 * * 0", Duration: time.Hour, Backends: []string{"billing"}}
+	m, _ := newMaintenanceTestModule(t, MaintenanceConfig{Windows: []MaintenanceWindowConfig{window}})
+	require.ErrorIs(t, m.scheduleMaintenanceWindows(), ErrInvalidMaintenanceConfig, "windows require the scheduler")
+
+	scheduler := &testScheduler{}
+	m.scheduler = scheduler
+	require.NoError(t, m.scheduleMaintenanceWindows())
+	job := scheduler.jobs["reverseproxy-maintenance-weekly 0 2 * * 0"]
+	require.NotNil(t, job)
+
+	assert.Equal(t, http.StatusOK, serveMaintenanceTest(m, "billing", "/billing", "").Code)
+	require.NoError(t, job(context.Background()))
+	rec := serveMaintenanceTest(m, "billing", "/billing", "")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "the window starts when the job runs")
+	assert.Equal(t, "3600", rec.Header().Get("Retry-After"))
+
+	m.cancelMaintenanceWindows()
+	assert.Equal(t, []string{"reverseproxy-maintenance-weekly"}, scheduler.cancelled)
+
+	_, err := scheduleRecurring(struct{}{}, "job", "@daily", job)
+	require.ErrorIs(t, err, ErrSchedulerUnsupported)
+}
+
+func TestMaintenance_ConfigValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MaintenanceConfig
+	}{
+		{"invalid status code", MaintenanceConfig{StatusCode: 42}},
+		{"admin endpoint without token", MaintenanceConfig{AdminEndpoint: "/admin/maintenance"}},
+		{"window without name", MaintenanceConfig{Windows: []MaintenanceWindowConfig{{Schedule: "@daily", Duration: time.Hour}}}},
+		{"window named config", MaintenanceConfig{Windows: []MaintenanceWindowConfig{{Name: ConfigMaintenanceName, Schedule: "@daily", Duration: time.Hour}}}},
+		{"window without schedule", MaintenanceConfig{Windows: []MaintenanceWindowConfig{{Name: "w", Duration: time.Hour}}}},
+		{"window without duration", MaintenanceConfig{Windows: []MaintenanceWindowConfig{{Name: "w", Schedule: "@daily"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.config.validate(), ErrInvalidMaintenanceConfig)
+		})
+	}
+}
@@ -20,9 +20,20 @@ type MetricsCollector struct {
 	latencyPercentiles map[string]map[string]time.Duration
 	latencySamples     map[string][]time.Duration
 	metadata           map[string]map[string]map[string]int // backend -> key -> value -> count
+	featureFlags       map[string]*featureFlagMetrics
+	clientAborts       map[string]int
+	routes             map[string]*routeMetrics // route pattern -> histograms
 	startTime          time.Time
 }
 
+// featureFlagMetrics tracks evaluation counts and latency for a single flag.
+type featureFlagMetrics struct {
+	evaluations  int
+	cacheHits    int
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
 // NewMetricsCollector creates a new MetricsCollector.
 func NewMetricsCollector() *MetricsCollector {
 	return &MetricsCollector{
@@ -34,6 +45,9 @@ func NewMetricsCollector() *MetricsCollector {
 		latencyPercentiles: make(map[string]map[string]time.Duration),
 		latencySamples:     make(map[string][]time.Duration),
 		metadata:           make(map[string]map[string]map[string]int),
+		featureFlags:       make(map[string]*featureFlagMetrics),
+		clientAborts:       make(map[string]int),
+		routes:             make(map[string]*routeMetrics),
 		startTime:          time.Now(),
 	}
 }
@@ -93,6 +107,36 @@ func (m *MetricsCollector) RecordRequest(backend string, start time.Time, status
 	}
 }
 
+// RecordClientAbort records a request to a backend that the client abandoned before
+// the backend responded. Such requests are not counted as requests or errors.
+func (m *MetricsCollector) RecordClientAbort(backend string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clientAborts[backend]++
+}
+
+// RecordFeatureFlagEvaluation records the latency of a feature flag evaluation
+// and whether it was served from the decision cache.
+func (m *MetricsCollector) RecordFeatureFlagEvaluation(flagID string, latency time.Duration, cacheHit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	flag, exists := m.featureFlags[flagID]
+	if !exists {
+		flag = &featureFlagMetrics{}
+		m.featureFlags[flagID] = flag
+	}
+	flag.evaluations++
+	if cacheHit {
+		flag.cacheHits++
+	}
+	flag.totalLatency += latency
+	if latency > flag.maxLatency {
+		flag.maxLatency = latency
+	}
+}
+
 // SetCircuitBreakerStatus sets the status of a circuit breaker.
 func (m *MetricsCollector) SetCircuitBreakerStatus(backend string, isOpen bool) {
 	m.mu.Lock()
@@ -162,11 +206,16 @@ func (m *MetricsCollector) GetMetrics() map[string]interface{} {
 	for _, count := range m.requestCounts {
 		totalRequests += count
 	}
+	totalClientAborts := 0
+	for _, count := range m.clientAborts {
+		totalClientAborts += count
+	}
 
 	metrics := map[string]interface{}{
-		"uptime_seconds": time.Since(m.startTime).Seconds(),
-		"total_requests": totalRequests,
-		"backends":       make(map[string]interface{}),
+		"uptime_seconds":      time.Since(m.startTime).Seconds(),
+		"total_requests":      totalRequests,
+		"total_client_aborts": totalClientAborts,
+		"backends":            make(map[string]interface{}),
 	}
 
 	backendMetrics := metrics["backends"].(map[string]interface{})
@@ -201,12 +250,47 @@ func (m *MetricsCollector) GetMetrics() map[string]interface{} {
 		}
 	}
 
+	// Add client aborts, including backends whose every request was abandoned
+	for backend, count := range m.clientAborts {
+		if entry, exists := backendMetrics[backend]; exists {
+			entry.(map[string]interface{})["client_aborts"] = count
+		} else {
+			backendMetrics[backend] = map[string]interface{}{"client_aborts": count}
+		}
+	}
+
+	// Add feature flag evaluation metrics if any flags were evaluated
+	if len(m.featureFlags) > 0 {
+		flagMetrics := make(map[string]interface{}, len(m.featureFlags))
+		for flagID, flag := range m.featureFlags {
+			flagMetrics[flagID] = map[string]interface{}{
+				"evaluations":    flag.evaluations,
+				"cache_hits":     flag.cacheHits,
+				"cache_hit_rate": float64(flag.cacheHits) / float64(flag.evaluations),
+				"avg_latency_us": flag.totalLatency.Microseconds() / int64(flag.evaluations),
+				"max_latency_us": flag.maxLatency.Microseconds(),
+			}
+		}
+		metrics["feature_flags"] = flagMetrics
+	}
+
+	if len(m.routes) > 0 {
+		metrics["routes"] = m.routeMetricsMap()
+	}
+
 	return metrics
 }
 
-// MetricsHandler returns an HTTP handler for metrics endpoint.
+// MetricsHandler returns an HTTP handler for metrics endpoint. Metrics are served
+// as JSON unless the Prometheus text format is requested.
 func (m *MetricsCollector) MetricsHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		if wantsPrometheus(r) {
+			w.Header().Set("Content-Type", PrometheusContentType)
+			_ = m.WritePrometheus(w)
+			return
+		}
+
 		metrics := m.GetMetrics()
 
 		w.Header().Set("Content-Type", "application/json")
@@ -2,10 +2,13 @@
 package reverseproxy
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/CrisisTextLine/modular"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -80,3 +83,41 @@ func (m *MockTenantApplicationWithMock) GetTenantConfig(tid modular.TenantID, se
 	}
 	return args.Get(0).(modular.ConfigProvider), nil
 }
+
+// capturingSubject is a minimal synchronous modular.Subject that records every emitted event
+type capturingSubject struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (s *capturingSubject) RegisterObserver(observer modular.Observer, eventTypes ...string) error {
+	return nil
+}
+
+func (s *capturingSubject) UnregisterObserver(observer modular.Observer) error {
+	return nil
+}
+
+func (s *capturingSubject) NotifyObservers(ctx context.Context, event cloudevents.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event.Clone())
+	return nil
+}
+
+func (s *capturingSubject) GetObservers() []modular.ObserverInfo {
+	return nil
+}
+
+// eventsOfType returns the recorded events with the given type
+func (s *capturingSubject) eventsOfType(eventType string) []cloudevents.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var matched []cloudevents.Event
+	for _, e := range s.events {
+		if e.Type() == eventType {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
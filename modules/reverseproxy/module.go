@@ -19,6 +19,7 @@ import (
 	"net/url"
 	"path"
 	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -56,6 +57,10 @@ type ReverseProxyModule struct {
 	backendProxies  map[string]*httputil.ReverseProxy
 	backendRoutes   map[string]map[string]http.HandlerFunc
 	compositeRoutes map[string]http.HandlerFunc
+
+	// Guards backendRoutes, which tenants registered at runtime extend
+	backendRoutesMutex sync.RWMutex
+
 	defaultBackend  string
 	app             modular.Application
 	tenantApp       modular.TenantApplication
@@ -64,14 +69,21 @@ type ReverseProxyModule struct {
 	directorFactory func(backend string, tenant modular.TenantID) func(*http.Request)
 
 	tenants              map[modular.TenantID]*ReverseProxyConfig
+	tenantsMutex         sync.RWMutex
 	tenantBackendProxies map[modular.TenantID]map[string]*httputil.ReverseProxy
 	preProxyTransforms   map[string]func(*http.Request)
 
+	// Per-tenant backend client certificates (mTLS), guarded by tenantProxiesMutex
+	tenantTransports  map[modular.TenantID]map[string]http.RoundTripper
+	tenantTLSErrors   map[modular.TenantID]error
+	tlsSecretResolver TLSSecretResolver
+
 	// Response header modification callback
 	responseHeaderModifier func(*http.Response, string, modular.TenantID) error
 
 	// Response transformers for composite routes (keyed by route pattern)
-	responseTransformers map[string]ResponseTransformer
+	responseTransformers   map[string]ResponseTransformer
+	responseTransformersV2 map[string]ResponseTransformerV2
 
 	// Metrics collection
 	metrics       *MetricsCollector
@@ -91,14 +103,104 @@ type ReverseProxyModule struct {
 	// Event observation
 	subject modular.Subject
 
-	// Load balancing (simple round-robin) support
-	loadBalanceCounters map[string]int // key: backend group spec string (comma-separated)
+	// Load balancing support
+	loadBalanceCounters map[string]int            // key: backend group spec string (comma-separated)
+	loadBalanceWeights  map[string]map[string]int // smooth weighted round-robin state, by group
+	loadBalanceRandom   func(n int) int           // replaces rand.IntN in tests
 	loadBalanceMutex    sync.Mutex
 
 	// Synchronization for concurrent map access
 	backendProxiesMutex sync.RWMutex
 	tenantProxiesMutex  sync.RWMutex
 
+	// In-flight request tracking for draining removed backends
+	drains backendDrainTracker
+
+	// Maintenance mode, with windows scheduled by the optional scheduler service
+	maintenance maintenanceState
+	scheduler   any
+
+	// Adaptive concurrency limits of the backends that enable one
+	concurrency adaptiveConcurrency
+
+	// Shared transports for unix:// and h2c:// backends, keyed by backend URL
+	upstreamTransports      map[string]*upstreamTransport
+	upstreamTransportsMutex sync.Mutex
+
+	// Timeout-aware transports derived from backend proxy transports, keyed by
+	// requestTransportKey, so requests and connection pre-warming share idle connections
+	requestTransports sync.Map
+
+	// Service level objectives tracked per backend and route; nil when disabled
+	slo *sloTracker
+
+	// Per-tenant bandwidth caps; nil when disabled
+	bandwidth *bandwidthLimiter
+
+	// Per-tenant request rate limits; nil when disabled
+	rateLimit *rateLimiter
+	// Counts distributed rate limits across replicas; nil enforces them per replica
+	rateLimitStore RateLimitStore
+
+	// Active fault injection rules; nil when disabled
+	faults *faultInjector
+
+	// Whether any route or backend retries failed requests
+	retriesEnabled bool
+
+	// Locality-aware selection from backend groups; nil when disabled
+	locality *localityRouter
+
+	// uploads tracks the progress of large uploads, nil when no route tracks it
+	uploads *uploadTracker
+
+	// Computes per-request upstream URLs, and caches them; nil proxies to configured URLs
+	backendURLResolver BackendURLResolver
+	backendURLCache    *backendURLCache
+
+	// Middleware applied around every generated backend and composite proxy handler
+	proxyMiddleware   []func(http.Handler) http.Handler
+	proxyChainOnce    sync.Once
+	proxyChainHandler http.Handler
+
+	// Named route middleware and the per-route chains built from route_configs
+	routeMiddleware map[string]func(http.Handler) http.Handler
+	routeChains     map[string]func(http.Handler) http.Handler
+
+	// Codecs registered for content translation and the per-route translators built from route_configs
+	contentCodecs      map[string]ContentCodec
+	contentTranslators map[string]*contentTranslator
+
+	// Per-route response validators built from route_configs
+	responseValidators map[string]*responseValidator
+
+	// Backends gating readiness, probed from Start until they are healthy
+	readiness *readinessGate
+
+	// Backend specs and the OpenAPI document merged from them, refreshed from Start
+	openAPI *openAPIAggregator
+
+	// Replaces the configured response cache key; nil uses the cache_key configuration
+	cacheKeyFunc CacheKeyFunc
+
+	// Time-based routing rules and the watcher emitting their activation events
+	scheduledRoutes     []*scheduledRoute
+	scheduledRoutesStop context.CancelFunc
+	scheduledRoutesDone chan struct{}
+
+	// Route patterns registered at Start, extended by tenants registered at runtime;
+	// nil until routes are registered
+	routePatterns      map[string]bool
+	routePatternsMutex sync.Mutex
+
+	// Routes of tenants registered at runtime, served through the registered routes
+	// instead of being added to the router while it serves requests
+	tenantRoutes       tenantRouteTable
+	catchAllRegistered bool
+
+	// Sampling rules applied to events in emitEvent; nil emits everything
+	eventSampler *eventSampler
+
 	// Tracks whether Init has completed; used to suppress backend.added events during initial load
 	initialized bool
 }
@@ -170,17 +272,21 @@ func NewModule() *ReverseProxyModule {
 	// either in Constructor (if httpclient service is available)
 	// or in Init (with default settings)
 	module := &ReverseProxyModule{
-		httpClient:           nil,
-		backendProxies:       make(map[string]*httputil.ReverseProxy),
-		backendRoutes:        make(map[string]map[string]http.HandlerFunc),
-		compositeRoutes:      make(map[string]http.HandlerFunc),
-		tenants:              make(map[modular.TenantID]*ReverseProxyConfig),
-		tenantBackendProxies: make(map[modular.TenantID]map[string]*httputil.ReverseProxy),
-		preProxyTransforms:   make(map[string]func(*http.Request)),
-		circuitBreakers:      make(map[string]*CircuitBreaker),
-		enableMetrics:        true,
-		loadBalanceCounters:  make(map[string]int),
-		responseTransformers: make(map[string]ResponseTransformer),
+		httpClient:             nil,
+		backendProxies:         make(map[string]*httputil.ReverseProxy),
+		backendRoutes:          make(map[string]map[string]http.HandlerFunc),
+		compositeRoutes:        make(map[string]http.HandlerFunc),
+		tenants:                make(map[modular.TenantID]*ReverseProxyConfig),
+		tenantBackendProxies:   make(map[modular.TenantID]map[string]*httputil.ReverseProxy),
+		tenantTransports:       make(map[modular.TenantID]map[string]http.RoundTripper),
+		tenantTLSErrors:        make(map[modular.TenantID]error),
+		preProxyTransforms:     make(map[string]func(*http.Request)),
+		circuitBreakers:        make(map[string]*CircuitBreaker),
+		enableMetrics:          true,
+		loadBalanceCounters:    make(map[string]int),
+		loadBalanceWeights:     make(map[string]map[string]int),
+		responseTransformers:   make(map[string]ResponseTransformer),
+		responseTransformersV2: make(map[string]ResponseTransformerV2),
 	}
 
 	return module
@@ -260,6 +366,18 @@ func (m *ReverseProxyModule) Init(app modular.Application) error {
 	if err := m.validateConfig(); err != nil {
 		return fmt.Errorf("configuration validation failed: %w", err)
 	}
+	m.eventSampler = newEventSampler(m.config.EventSampling)
+	m.slo = newSLOTracker(m.config.SLO)
+	m.bandwidth = newBandwidthLimiter(m.config.Bandwidth, m.isRegisteredTenant)
+	m.setupRateLimit()
+	m.faults = newFaultInjector(m.config.FaultInjection)
+	m.locality = newLocalityRouter(m.config.Locality)
+	m.uploads = newUploadTracker(m.config.RouteConfigs)
+
+	// Load the maintenance page and switch on configured maintenance
+	if err := m.setupMaintenance(); err != nil {
+		return err
+	}
 
 	// Initialize metrics collector
 	if m.enableMetrics {
@@ -310,14 +428,7 @@ func (m *ReverseProxyModule) Init(app modular.Application) error {
 		}
 
 		// Initialize route map for this backend
-		// Ensure backendRoutes map is initialized
-		if m.backendRoutes == nil {
-			m.backendRoutes = make(map[string]map[string]http.HandlerFunc)
-		}
-		// Initialize route map for this backend
-		if _, ok := m.backendRoutes[backendID]; !ok {
-			m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
-		}
+		m.setBackendRoute(backendID, "", nil)
 	}
 
 	// Create tenant-specific backend proxies for any tenants already registered
@@ -379,28 +490,7 @@ func (m *ReverseProxyModule) Init(app modular.Application) error {
 	// Initialize circuit breakers for all backends if enabled
 	if m.config.CircuitBreakerConfig.Enabled {
 		for backendID := range m.config.BackendServices {
-			// Check for backend-specific circuit breaker config
-			var cbConfig CircuitBreakerConfig
-			if backendCB, exists := m.config.BackendCircuitBreakers[backendID]; exists {
-				cbConfig = backendCB
-			} else {
-				cbConfig = m.config.CircuitBreakerConfig
-			}
-
-			// Use module's request timeout if circuit breaker config doesn't specify one
-			if cbConfig.RequestTimeout == 0 && m.config.RequestTimeout > 0 {
-				cbConfig.RequestTimeout = m.config.RequestTimeout
-			}
-
-			// Create circuit breaker for this backend
-			cb := NewCircuitBreakerWithConfig(backendID, cbConfig, m.metrics)
-			cb.eventEmitter = func(eventType string, data map[string]interface{}) {
-				m.emitEvent(context.Background(), eventType, data) //nolint:contextcheck // circuit breaker transitions occur outside request scope
-			}
-			m.circuitBreakers[backendID] = cb
-
-			app.Logger().Debug("Initialized circuit breaker", "backend", backendID,
-				"failure_threshold", cbConfig.FailureThreshold, "open_timeout", cbConfig.OpenTimeout)
+			m.setupCircuitBreaker(backendID)
 		}
 		app.Logger().Info("Circuit breakers initialized", "backends", len(m.circuitBreakers))
 	}
@@ -428,6 +518,33 @@ func (m *ReverseProxyModule) Init(app modular.Application) error {
 	return nil
 }
 
+// setupCircuitBreaker creates the circuit breaker of a backend, from its own config
+// or the global one.
+func (m *ReverseProxyModule) setupCircuitBreaker(backendID string) {
+	// Check for backend-specific circuit breaker config
+	var cbConfig CircuitBreakerConfig
+	if backendCB, exists := m.config.BackendCircuitBreakers[backendID]; exists {
+		cbConfig = backendCB
+	} else {
+		cbConfig = m.config.CircuitBreakerConfig
+	}
+
+	// Use module's request timeout if circuit breaker config doesn't specify one
+	if cbConfig.RequestTimeout == 0 && m.config.RequestTimeout > 0 {
+		cbConfig.RequestTimeout = m.config.RequestTimeout
+	}
+
+	// Create circuit breaker for this backend
+	cb := NewCircuitBreakerWithConfig(backendID, cbConfig, m.metrics)
+	cb.eventEmitter = func(eventType string, data map[string]interface{}) {
+		m.emitEvent(context.Background(), eventType, data) //nolint:contextcheck // circuit breaker transitions occur outside request scope
+	}
+	m.circuitBreakers[backendID] = cb
+
+	m.app.Logger().Debug("Initialized circuit breaker", "backend", backendID,
+		"failure_threshold", cbConfig.FailureThreshold, "open_timeout", cbConfig.OpenTimeout)
+}
+
 // validateConfig validates the module configuration.
 // It checks for valid URLs, timeout values, and other configuration parameters.
 func (m *ReverseProxyModule) validateConfig() error {
@@ -462,10 +579,13 @@ func (m *ReverseProxyModule) validateConfig() error {
 		}
 
 		// Try to parse the URL
-		_, err := url.Parse(serviceURL)
+		parsedURL, err := url.Parse(serviceURL)
 		if err != nil {
 			return fmt.Errorf("invalid URL for backend '%s': %s - %w", backendID, serviceURL, err)
 		}
+		if err := validateUpstreamURL(parsedURL); err != nil {
+			return fmt.Errorf("invalid URL for backend '%s': %s - %w", backendID, serviceURL, err)
+		}
 	}
 
 	// Validate default backend is defined if specified
@@ -497,6 +617,131 @@ func (m *ReverseProxyModule) validateConfig() error {
 		return ErrTenantIDRequired
 	}
 
+	if err := m.config.EventSampling.validate(); err != nil {
+		return err
+	}
+
+	if err := m.config.Compression.validate(); err != nil {
+		return err
+	}
+	if err := m.config.TenantOnboarding.validate(); err != nil {
+		return err
+	}
+	if err := m.config.Prewarm.validate(); err != nil {
+		return err
+	}
+	for _, backendID := range m.config.Prewarm.Backends {
+		if _, ok := m.config.BackendServices[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidPrewarmConfig, backendID)
+		}
+	}
+	if err := m.config.Readiness.validate(m.config.BackendServices); err != nil {
+		return err
+	}
+	if err := m.config.OpenAPI.validate(m.config.BackendServices); err != nil {
+		return err
+	}
+	if err := m.config.SLO.validate(); err != nil {
+		return err
+	}
+	for backendID := range m.config.SLO.Backends {
+		if _, ok := m.config.BackendServices[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidSLOConfig, backendID)
+		}
+	}
+	if err := m.config.BackendURLResolution.validate(); err != nil {
+		return err
+	}
+	if err := m.config.Bandwidth.validate(); err != nil {
+		return err
+	}
+	if err := m.config.RateLimit.validate(); err != nil {
+		return err
+	}
+	if err := m.config.Locality.validate(); err != nil {
+		return err
+	}
+	if err := m.config.CacheKey.validate(); err != nil {
+		return err
+	}
+	if err := m.config.FaultInjection.validate(); err != nil {
+		return err
+	}
+	if m.config.FaultInjection.Enabled {
+		if err := m.config.FaultInjection.checkProfiles(activeProfiles(m.app)); err != nil {
+			return err
+		}
+		for i := range m.config.FaultInjection.Rules {
+			if err := m.validateFaultRule(&m.config.FaultInjection.Rules[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	scheduledRoutes, err := compileScheduledRoutes(m.config)
+	if err != nil {
+		return err
+	}
+	m.scheduledRoutes = scheduledRoutes
+	for pattern, routeConfig := range m.config.RouteConfigs {
+		if routeConfig.Compression != nil {
+			if err := routeConfig.Compression.validate(); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if routeConfig.ContentTranslation != nil {
+			if err := routeConfig.ContentTranslation.validate(); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if routeConfig.CacheKey != nil {
+			if err := routeConfig.CacheKey.validate(); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if routeConfig.Upload != nil {
+			if err := routeConfig.Upload.validate(routeConfig); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if routeConfig.ContentRouting != nil {
+			if err := routeConfig.ContentRouting.validate(m.config.BackendServices); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if routeConfig.ResponseValidation != nil {
+			if err := routeConfig.ResponseValidation.validate(); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+		}
+		if err := routeConfig.validateLoadBalancing(m.config.BackendServices); err != nil {
+			return fmt.Errorf("route %s: %w", pattern, err)
+		}
+		if routeConfig.Retry != nil {
+			if err := routeConfig.Retry.validate(); err != nil {
+				return fmt.Errorf("route %s: %w", pattern, err)
+			}
+			m.retriesEnabled = m.retriesEnabled || routeConfig.Retry.enabled()
+		}
+	}
+	for backendID, backendCfg := range m.config.BackendConfigs {
+		if backendCfg.Weight < 0 {
+			return fmt.Errorf("%w: negative weight for backend %q", ErrInvalidLoadBalancingConfig, backendID)
+		}
+		if err := backendCfg.Redirects.validate(); err != nil {
+			return fmt.Errorf("backend %s: %w", backendID, err)
+		}
+		if err := backendCfg.AdaptiveConcurrency.validate(); err != nil {
+			return fmt.Errorf("backend %s: %w", backendID, err)
+		}
+		if backendCfg.Retry != nil {
+			if err := backendCfg.Retry.validate(); err != nil {
+				return fmt.Errorf("backend %s: %w", backendID, err)
+			}
+			m.retriesEnabled = m.retriesEnabled || backendCfg.Retry.enabled()
+		}
+	}
+
 	return nil
 }
 
@@ -542,6 +787,33 @@ func (m *ReverseProxyModule) Constructor() modular.ModuleConstructor {
 			}
 		}
 
+		// Get the optional scheduler service for maintenance windows
+		if schedulerSvc, exists := services[SchedulerServiceName]; exists {
+			m.scheduler = schedulerSvc
+		}
+
+		// Get the optional backend URL resolver service
+		if resolverSvc, exists := services["backendURLResolver"]; exists {
+			if resolver, ok := resolverSvc.(BackendURLResolver); ok {
+				m.SetBackendURLResolver(resolver)
+				app.Logger().Debug("Using backend URL resolver from service")
+			} else {
+				app.Logger().Warn("backendURLResolver service found but does not implement BackendURLResolver",
+					"type", fmt.Sprintf("%T", resolverSvc))
+			}
+		}
+
+		// Get the optional cache service counting distributed rate limits
+		if cacheSvc, exists := services["cache.provider"]; exists {
+			if store, ok := cacheSvc.(RateLimitStore); ok {
+				m.rateLimitStore = store
+				app.Logger().Debug("Using cache service for distributed rate limits")
+			} else {
+				app.Logger().Warn("cache.provider service found but does not implement RateLimitStore",
+					"type", fmt.Sprintf("%T", cacheSvc))
+			}
+		}
+
 		// If no HTTP client service was found, we'll create a default one in Init()
 		if m.httpClient == nil {
 			app.Logger().Debug("No httpclient service available, will create default client")
@@ -566,6 +838,21 @@ func (m *ReverseProxyModule) Start(ctx context.Context) error {
 	// This handles tenants that were registered after Init()
 	m.createTenantProxies(ctx)
 
+	// Resolve named middleware referenced by route configs before any route is registered
+	if err := m.resolveRouteMiddleware(); err != nil {
+		return err
+	}
+	if err := m.resolveContentTranslation(); err != nil {
+		return err
+	}
+	if err := m.resolveResponseValidation(); err != nil {
+		return err
+	}
+	m.resolveReadiness()
+
+	// Scheduled routes take over matching requests on every route registered below
+	m.startScheduledRoutes(ctx)
+
 	// Setup routes for all backends
 	if err := m.setupBackendRoutes(); err != nil {
 		return err
@@ -593,6 +880,35 @@ func (m *ReverseProxyModule) Start(ctx context.Context) error {
 		}
 	}
 
+	// Register the maintenance admin endpoint and schedule maintenance windows
+	if endpoint := m.config.Maintenance.AdminEndpoint; endpoint != "" {
+		m.safeHandleFunc(endpoint, m.handleMaintenanceAdmin)
+		m.app.Logger().Info("Registered maintenance admin endpoint", "endpoint", endpoint)
+	}
+	if err := m.scheduleMaintenanceWindows(); err != nil {
+		return err
+	}
+
+	// Register the tenant onboarding API if enabled
+	if m.config.TenantOnboarding.Enabled {
+		m.registerTenantOnboardingEndpoint()
+	}
+
+	// Register the readiness endpoint if configured
+	if m.config.Readiness.Endpoint != "" {
+		m.registerReadinessEndpoint()
+	}
+
+	// Register the aggregated OpenAPI document if enabled
+	if m.config.OpenAPI.Enabled {
+		m.registerOpenAPIEndpoint()
+	}
+
+	// Register the fault injection API if enabled
+	if m.faults != nil {
+		m.registerFaultInjectionEndpoints()
+	}
+
 	// Set up feature flag evaluation using aggregator pattern
 	if err := m.setupFeatureFlagEvaluation(ctx); err != nil {
 		return fmt.Errorf("failed to set up feature flag evaluation: %w", err)
@@ -610,6 +926,19 @@ func (m *ReverseProxyModule) Start(ctx context.Context) error {
 		}
 	}
 
+	// Open idle connections to backends before reporting the module as started
+	if err := m.prewarmBackends(ctx); err != nil {
+		return err
+	}
+
+	// Wait for backends gating readiness, failing Start for those that must be ready
+	if err := m.awaitReadiness(ctx); err != nil {
+		return err
+	}
+
+	// Fetch backend OpenAPI specs in the background, refreshing them periodically
+	m.startOpenAPIAggregation(ctx)
+
 	// Emit module started event
 	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
 		"backend_count":          len(m.config.BackendServices),
@@ -635,6 +964,18 @@ func (m *ReverseProxyModule) Stop(ctx context.Context) error {
 		m.app.Logger().Info("Shutting down reverseproxy module")
 	}
 
+	// Remove maintenance windows from the scheduler
+	m.cancelMaintenanceWindows()
+
+	// Stop watching scheduled routes
+	m.stopScheduledRoutes()
+
+	// Stop probing backends that never became ready
+	m.stopReadiness()
+
+	// Stop refreshing backend OpenAPI specs
+	m.stopOpenAPIAggregation()
+
 	// Stop health checker if running
 	if m.healthChecker != nil {
 		m.healthChecker.Stop(ctx)
@@ -664,7 +1005,9 @@ func (m *ReverseProxyModule) Stop(ctx context.Context) error {
 
 	// Reset all internal state maps to release memory
 	m.compositeRoutes = make(map[string]http.HandlerFunc)
+	m.backendRoutesMutex.Lock()
 	m.backendRoutes = make(map[string]map[string]http.HandlerFunc)
+	m.backendRoutesMutex.Unlock()
 
 	// Reset circuit breakers
 	for id := range m.circuitBreakers {
@@ -682,10 +1025,20 @@ func (m *ReverseProxyModule) Stop(ctx context.Context) error {
 	for tenantId := range m.tenantBackendProxies {
 		m.tenantBackendProxies[tenantId] = make(map[string]*httputil.ReverseProxy)
 	}
+	// Tenant transports are kept for a restart, but their idle connections are closed
+	for _, transports := range m.tenantTransports {
+		for _, transport := range transports {
+			if t, ok := transport.(*http.Transport); ok {
+				t.CloseIdleConnections()
+			}
+		}
+	}
 	m.tenantProxiesMutex.Unlock()
+	m.closeUpstreamTransports()
+	m.closeRequestTransports()
 
 	// Keep tenant configs but clear proxies
-	for tenantID := range m.tenants {
+	for tenantID := range m.tenantConfigs() {
 		if m.app != nil && m.app.Logger() != nil {
 			m.app.Logger().Debug("Cleaned up resources for tenant", "tenant", tenantID)
 		}
@@ -712,13 +1065,54 @@ func (m *ReverseProxyModule) Stop(ctx context.Context) error {
 	return nil
 }
 
+// tenantConfig returns the merged configuration of a tenant, or nil if it hasn't been loaded.
+func (m *ReverseProxyModule) tenantConfig(tenantID modular.TenantID) *ReverseProxyConfig {
+	m.tenantsMutex.RLock()
+	defer m.tenantsMutex.RUnlock()
+	return m.tenants[tenantID]
+}
+
+// setTenantConfig stores the merged configuration of a tenant.
+func (m *ReverseProxyModule) setTenantConfig(tenantID modular.TenantID, cfg *ReverseProxyConfig) {
+	m.tenantsMutex.Lock()
+	defer m.tenantsMutex.Unlock()
+	m.tenants[tenantID] = cfg
+}
+
+// tenantConfigs returns a snapshot of the known tenants and their merged configurations,
+// so tenants can be iterated while others are registered at runtime.
+func (m *ReverseProxyModule) tenantConfigs() map[modular.TenantID]*ReverseProxyConfig {
+	m.tenantsMutex.RLock()
+	defer m.tenantsMutex.RUnlock()
+	tenants := make(map[modular.TenantID]*ReverseProxyConfig, len(m.tenants))
+	for tenantID, cfg := range m.tenants {
+		tenants[tenantID] = cfg
+	}
+	return tenants
+}
+
+// isRegisteredTenant reports whether tenantID is registered with the application.
+func (m *ReverseProxyModule) isRegisteredTenant(tenantID string) bool {
+	m.tenantsMutex.RLock()
+	defer m.tenantsMutex.RUnlock()
+	_, ok := m.tenants[modular.TenantID(tenantID)]
+	return ok
+}
+
 // OnTenantRegistered is called when a new tenant is registered with the application.
 // Instead of immediately querying for tenant configuration, we store the tenant ID
 // and defer configuration loading until the next appropriate phase to avoid deadlocks.
 func (m *ReverseProxyModule) OnTenantRegistered(tenantID modular.TenantID) {
 	// Store the tenant ID first, defer config loading to avoid deadlock
 	// The actual configuration will be loaded in Start() or when needed
+	m.tenantsMutex.Lock()
 	m.tenants[tenantID] = nil
+	m.tenantsMutex.Unlock()
+
+	// Tenant flag overrides may have changed; drop memoized decisions for this tenant
+	if cache, ok := m.featureFlagEvaluator.(*CachingFeatureFlagEvaluator); ok {
+		cache.InvalidateTenant(tenantID)
+	}
 
 	// Check if app is available (module might not be fully initialized yet)
 	if m.app != nil && m.app.Logger() != nil {
@@ -730,7 +1124,7 @@ func (m *ReverseProxyModule) OnTenantRegistered(tenantID modular.TenantID) {
 // This should be called during Start() or another safe phase after tenant registration.
 func (m *ReverseProxyModule) loadTenantConfigs() {
 	if m.app != nil && m.app.Logger() != nil {
-		m.app.Logger().Debug("Loading tenant configs", "count", len(m.tenants))
+		m.app.Logger().Debug("Loading tenant configs", "count", len(m.tenantConfigs()))
 	}
 
 	// Ensure we have a tenant application reference (tests may call this before Init)
@@ -746,7 +1140,7 @@ func (m *ReverseProxyModule) loadTenantConfigs() {
 			return
 		}
 	}
-	for tenantID := range m.tenants {
+	for tenantID := range m.tenantConfigs() {
 		cp, err := ta.GetTenantConfig(tenantID, m.Name())
 		if err != nil {
 			m.app.Logger().Error("Failed to get config for tenant", "tenant", tenantID, "module", m.Name(), "error", err)
@@ -763,10 +1157,13 @@ func (m *ReverseProxyModule) loadTenantConfigs() {
 		mergedCfg := mergeConfigs(m.config, tenantCfg)
 
 		// Store the merged configuration
-		m.tenants[tenantID] = mergedCfg
+		m.setTenantConfig(tenantID, mergedCfg)
 		if m.app != nil && m.app.Logger() != nil {
 			m.app.Logger().Debug("Loaded and merged tenant config", "tenantID", tenantID, "defaultBackend", mergedCfg.DefaultBackend)
 		}
+
+		// Load client certificates from the tenant's own config so global settings never leak into it
+		m.loadTenantTLS(context.Background(), tenantID, tenantCfg)
 	}
 }
 
@@ -774,95 +1171,106 @@ func (m *ReverseProxyModule) loadTenantConfigs() {
 // This method should be called after loadTenantConfigs() to ensure tenant proxies are
 // created for any tenants that were registered after Init() was called.
 func (m *ReverseProxyModule) createTenantProxies(ctx context.Context) {
-	for tenantID, tenantCfg := range m.tenants {
-		if tenantCfg == nil || tenantCfg.BackendServices == nil {
-			continue
-		}
+	for tenantID, tenantCfg := range m.tenantConfigs() {
+		m.createTenantProxiesFor(ctx, tenantID, tenantCfg)
+	}
+}
 
-		// Process each backend in tenant config
-		for backendID, serviceURL := range tenantCfg.BackendServices {
-			// Skip if URL is not provided
-			if serviceURL == "" {
-				continue
-			}
+// createTenantProxiesFor creates the reverse proxies for one tenant's backend services.
+func (m *ReverseProxyModule) createTenantProxiesFor(ctx context.Context, tenantID modular.TenantID, tenantCfg *ReverseProxyConfig) {
+	if tenantCfg == nil || tenantCfg.BackendServices == nil {
+		return
+	}
 
-			// Check if proxy already exists - use write lock to avoid race with test setup
-			m.tenantProxiesMutex.Lock()
-			tenantProxies, tenantMapExists := m.tenantBackendProxies[tenantID]
-			proxyExists := tenantMapExists && tenantProxies != nil && tenantProxies[backendID] != nil
+	// Process each backend in tenant config
+	for backendID, serviceURL := range tenantCfg.BackendServices {
+		// Skip if URL is not provided
+		if serviceURL == "" {
+			continue
+		}
 
-			if proxyExists {
-				// Proxy already exists, skip creation
-				m.tenantProxiesMutex.Unlock()
-				continue
-			}
+		// Check if proxy already exists - use write lock to avoid race with test setup
+		m.tenantProxiesMutex.Lock()
+		tenantProxies, tenantMapExists := m.tenantBackendProxies[tenantID]
+		proxyExists := tenantMapExists && tenantProxies != nil && tenantProxies[backendID] != nil
 
-			// No proxy exists, unlock to create it (avoid holding lock during creation)
+		if proxyExists {
+			// Proxy already exists, skip creation
 			m.tenantProxiesMutex.Unlock()
+			continue
+		}
 
-			// Create a new proxy for this tenant's backend
-			backendURL, err := url.Parse(serviceURL)
-			if err != nil {
-				if m.app != nil && m.app.Logger() != nil {
-					m.app.Logger().Error("Failed to parse tenant backend URL",
-						"tenant", tenantID, "backend", backendID, "url", serviceURL, "error", err)
-				}
-				continue
+		// No proxy exists, unlock to create it (avoid holding lock during creation)
+		m.tenantProxiesMutex.Unlock()
+
+		// Create a new proxy for this tenant's backend
+		backendURL, err := url.Parse(serviceURL)
+		if err != nil {
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Error("Failed to parse tenant backend URL",
+					"tenant", tenantID, "backend", backendID, "url", serviceURL, "error", err)
 			}
+			continue
+		}
 
-			proxy := m.createReverseProxyForBackend(ctx, backendURL, backendID, "")
+		proxy := m.createReverseProxyForBackend(ctx, backendURL, backendID, "")
 
-			// Re-acquire lock and double-check before storing (double-checked locking pattern)
-			m.tenantProxiesMutex.Lock()
-			if _, exists := m.tenantBackendProxies[tenantID]; !exists {
-				m.tenantBackendProxies[tenantID] = make(map[string]*httputil.ReverseProxy)
-			}
+		// Tenant client certificates get their own transport, and such a proxy must
+		// never be shared as the global one
+		globalProxy := proxy
+		if transport := m.tenantTransport(tenantID, backendID); transport != nil {
+			proxy.Transport = transport
+			globalProxy = m.createReverseProxyForBackend(ctx, backendURL, backendID, "")
+		}
 
-			// Check again in case another goroutine/test created it while we were unlocked
-			if m.tenantBackendProxies[tenantID][backendID] == nil {
-				// Store the tenant-specific proxy only if still nil
-				m.tenantBackendProxies[tenantID][backendID] = proxy
-			}
-			m.tenantProxiesMutex.Unlock()
+		// Re-acquire lock and double-check before storing (double-checked locking pattern)
+		m.tenantProxiesMutex.Lock()
+		if _, exists := m.tenantBackendProxies[tenantID]; !exists {
+			m.tenantBackendProxies[tenantID] = make(map[string]*httputil.ReverseProxy)
+		}
 
-			// If there's no global URL for this backend, create one in the global map
-			// BUT only if we actually stored a tenant proxy (not if test overrode it)
-			m.tenantProxiesMutex.RLock()
-			actuallyStoredTenantProxy := m.tenantBackendProxies[tenantID] != nil && m.tenantBackendProxies[tenantID][backendID] == proxy
-			m.tenantProxiesMutex.RUnlock()
+		// Check again in case another goroutine/test created it while we were unlocked
+		if m.tenantBackendProxies[tenantID][backendID] == nil {
+			// Store the tenant-specific proxy only if still nil
+			m.tenantBackendProxies[tenantID][backendID] = proxy
+		}
+		m.tenantProxiesMutex.Unlock()
 
-			backendWasAdded := false
-			if actuallyStoredTenantProxy {
-				m.backendProxiesMutex.Lock()
-				if _, exists := m.backendProxies[backendID]; !exists {
-					if m.app != nil && m.app.Logger() != nil {
-						m.app.Logger().Debug("Using tenant-specific backend URL as global",
-							"tenant_hash", obfuscateTenantID(tenantID), "backend", backendID, "url", serviceURL)
-					}
-					m.backendProxies[backendID] = proxy
-					backendWasAdded = true
-				}
-				m.backendProxiesMutex.Unlock()
-
-				// Emit backend added event only for dynamic additions after initialization
-				if backendWasAdded && m.initialized {
-					m.emitEvent(ctx, EventTypeBackendAdded, map[string]interface{}{
-						"backend": backendID,
-						"url":     serviceURL,
-						"time":    time.Now().UTC().Format(time.RFC3339Nano),
-					})
+		// If there's no global URL for this backend, create one in the global map
+		// BUT only if we actually stored a tenant proxy (not if test overrode it)
+		m.tenantProxiesMutex.RLock()
+		actuallyStoredTenantProxy := m.tenantBackendProxies[tenantID] != nil && m.tenantBackendProxies[tenantID][backendID] == proxy
+		m.tenantProxiesMutex.RUnlock()
+
+		backendWasAdded := false
+		if actuallyStoredTenantProxy {
+			m.backendProxiesMutex.Lock()
+			if _, exists := m.backendProxies[backendID]; !exists {
+				if m.app != nil && m.app.Logger() != nil {
+					m.app.Logger().Debug("Using tenant-specific backend URL as global",
+						"tenant_hash", obfuscateTenantID(tenantID), "backend", backendID, "url", serviceURL)
 				}
+				m.backendProxies[backendID] = globalProxy
+				backendWasAdded = true
 			}
-
-			// Initialize route map for this backend if needed
-			if _, ok := m.backendRoutes[backendID]; !ok {
-				m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
+			m.backendProxiesMutex.Unlock()
+
+			// Emit backend added event only for dynamic additions after initialization
+			if backendWasAdded && m.initialized {
+				m.emitEvent(ctx, EventTypeBackendAdded, map[string]interface{}{
+					"backend": backendID,
+					"url":     serviceURL,
+					"time":    time.Now().UTC().Format(time.RFC3339Nano),
+				})
 			}
+		}
 
-			if m.app != nil && m.app.Logger() != nil {
-				m.app.Logger().Debug("Created tenant-specific proxy",
-					"tenant", tenantID, "backend", backendID, "url", serviceURL)
-			}
+		// Initialize route map for this backend if needed
+		m.setBackendRoute(backendID, "", nil)
+
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Created tenant-specific proxy",
+				"tenant", tenantID, "backend", backendID, "url", serviceURL)
 		}
 	}
 }
@@ -871,7 +1279,21 @@ func (m *ReverseProxyModule) createTenantProxies(ctx context.Context) {
 // It removes the tenant's configuration and any associated resources.
 func (m *ReverseProxyModule) OnTenantRemoved(tenantID modular.TenantID) {
 	// Clean up tenant-specific resources
+	m.tenantsMutex.Lock()
 	delete(m.tenants, tenantID)
+	m.tenantsMutex.Unlock()
+	m.releaseTenantTLS(tenantID)
+	if m.bandwidth != nil {
+		m.bandwidth.forget(string(tenantID))
+	}
+	if m.rateLimit != nil {
+		m.rateLimit.forget(string(tenantID))
+	}
+
+	// Drop memoized flag decisions so a re-registered tenant starts fresh
+	if cache, ok := m.featureFlagEvaluator.(*CachingFeatureFlagEvaluator); ok {
+		cache.InvalidateTenant(tenantID)
+	}
 
 	// Check if app is available (module might not be fully initialized yet)
 	if m.app != nil && m.app.Logger() != nil {
@@ -898,6 +1320,13 @@ func (m *ReverseProxyModule) ProvidesServices() []modular.ServiceProvider {
 		Instance:    m,
 	})
 
+	// Provide label-based backend selection to other modules
+	services = append(services, modular.ServiceProvider{
+		Name:        BackendSelectorServiceName,
+		Description: "Selects available backends by label for service-to-service calls",
+		Instance:    BackendSelector(m),
+	})
+
 	// Provide the feature flag evaluator service if we have one and feature flags are enabled.
 	// This includes both internally created and externally provided evaluators so other modules can use them.
 	if m.featureFlagEvaluator != nil && m.config.FeatureFlags.Enabled {
@@ -920,9 +1349,17 @@ type routerService interface {
 	ServeHTTP(w http.ResponseWriter, r *http.Request)
 }
 
+// ShutdownPhaseOrder returns the proxy phase of the application's shutdown,
+// modular.ShutdownPhaseProxy, so in-flight requests drain after the HTTP server
+// stops accepting new ones.
+func (m *ReverseProxyModule) ShutdownPhaseOrder() int {
+	return 200
+}
+
 // RequiresServices returns the services required by this module.
 // The reverseproxy module requires a service that implements the routerService
-// interface to register routes with, and optionally a http.Client and FeatureFlagEvaluator.
+// interface to register routes with, and optionally a http.Client, FeatureFlagEvaluator,
+// BackendURLResolver and the scheduler service.
 func (m *ReverseProxyModule) RequiresServices() []modular.ServiceDependency {
 	return []modular.ServiceDependency{
 		{
@@ -943,6 +1380,22 @@ func (m *ReverseProxyModule) RequiresServices() []modular.ServiceDependency {
 			MatchByInterface:   true,
 			SatisfiesInterface: reflect.TypeOf((*FeatureFlagEvaluator)(nil)).Elem(),
 		},
+		{
+			Name:     SchedulerServiceName,
+			Required: false, // Optional dependency for maintenance windows
+		},
+		{
+			Name:               "backendURLResolver",
+			Required:           false, // Optional dependency
+			MatchByInterface:   true,
+			SatisfiesInterface: reflect.TypeOf((*BackendURLResolver)(nil)).Elem(),
+		},
+		{
+			Name:               "cache.provider",
+			Required:           false, // Optional dependency
+			MatchByInterface:   false, // Use name-based matching
+			SatisfiesInterface: nil,
+		},
 	}
 }
 
@@ -956,6 +1409,10 @@ func (m *ReverseProxyModule) safeHandleFunc(pattern string, handler http.Handler
 		fmt.Printf("WARNING: attempted to register nil handler for pattern '%s'\n", pattern)
 		return
 	}
+	handler = m.withTenantRoutes(pattern, m.routeHandler(pattern, handler))
+	if pattern == "/*" {
+		m.catchAllRegistered = true
+	}
 	defer func() {
 		if r := recover(); r != nil {
 			fmt.Printf("WARNING: router.HandleFunc panicked for pattern '%s': %v\n", pattern, r)
@@ -975,6 +1432,27 @@ func (m *ReverseProxyModule) safeHandleFunc(pattern string, handler http.Handler
 	}
 }
 
+// routeHandler wraps the handler of a route pattern with the per-route middleware.
+func (m *ReverseProxyModule) routeHandler(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	return m.withRouteMetrics(pattern, m.withRateLimit(m.withBandwidth(m.withUploads(pattern, m.withRouteSLO(pattern, m.withCompression(pattern, m.withContentTranslation(pattern, m.withResponseValidation(pattern, m.withScheduledRoutes(m.withContentRouting(pattern, handler))))))))))
+}
+
+// setBackendRoute records the handler serving route for a backend. With an empty route
+// it only makes sure the backend has a route map.
+func (m *ReverseProxyModule) setBackendRoute(backendID, route string, handler http.HandlerFunc) {
+	m.backendRoutesMutex.Lock()
+	defer m.backendRoutesMutex.Unlock()
+	if m.backendRoutes == nil {
+		m.backendRoutes = make(map[string]map[string]http.HandlerFunc)
+	}
+	if _, ok := m.backendRoutes[backendID]; !ok {
+		m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
+	}
+	if route != "" {
+		m.backendRoutes[backendID][route] = handler
+	}
+}
+
 // setupBackendRoutes sets up routes for all configured backends.
 // For each backend with a valid URL, it registers a default catch-all route.
 func (m *ReverseProxyModule) setupBackendRoutes() error {
@@ -1000,10 +1478,7 @@ func (m *ReverseProxyModule) registerBackendRoute(backendID, route string) {
 	handler := m.createBackendProxyHandler(backendID)
 
 	// Store the handler in the backend routes map
-	if _, ok := m.backendRoutes[backendID]; !ok {
-		m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
-	}
-	m.backendRoutes[backendID][route] = handler
+	m.setBackendRoute(backendID, route, handler)
 
 	// Register the handler with the router immediately if router is available
 	if m.router != nil {
@@ -1051,6 +1526,7 @@ func (m *ReverseProxyModule) setupCompositeRoutes(ctx context.Context) error {
 			}
 			handlerFunc = handler.ServeHTTP
 		}
+		handlerFunc = m.withProxyMiddleware(ProxyTarget{CompositeRoute: routePath}, handlerFunc)
 
 		// Initialize the handler map for this route if not exists
 		if _, exists := compositeHandlers[routePath]; !exists {
@@ -1062,7 +1538,7 @@ func (m *ReverseProxyModule) setupCompositeRoutes(ctx context.Context) error {
 	}
 
 	// Now set up tenant-specific composite handlers
-	for tenantID, tenantConfig := range m.tenants {
+	for tenantID, tenantConfig := range m.tenantConfigs() {
 		// Skip if tenant config is nil
 		if tenantConfig == nil || tenantConfig.CompositeRoutes == nil {
 			continue
@@ -1090,6 +1566,7 @@ func (m *ReverseProxyModule) setupCompositeRoutes(ctx context.Context) error {
 				}
 				handlerFunc = handler.ServeHTTP
 			}
+			handlerFunc = m.withProxyMiddleware(ProxyTarget{CompositeRoute: routePath}, handlerFunc)
 
 			// Initialize the handler map for this route if not exists
 			if _, exists := compositeHandlers[routePath]; !exists {
@@ -1160,8 +1637,10 @@ func (m *ReverseProxyModule) registerRoutes() error {
 		return fmt.Errorf("%w: router interface is nil", ErrCannotRegisterRoutes)
 	}
 
+	m.recordRoutePatterns()
+
 	// Case 1: No tenants - register basic and composite routes as usual
-	if len(m.tenants) == 0 {
+	if len(m.tenantConfigs()) == 0 {
 		return m.registerBasicRoutes()
 	}
 
@@ -1189,186 +1668,216 @@ func (m *ReverseProxyModule) registerBasicRoutes() error {
 		}
 
 		// Create a handler that considers route configs for feature flag evaluation
-		handler := func(routePath, backendID string) http.HandlerFunc {
-			return func(w http.ResponseWriter, r *http.Request) {
-				// Check tenant header enforcement first
-				_, hasTenant := TenantIDFromRequest(m.config.TenantIDHeader, r)
-				if m.config.RequireTenantID && !hasTenant {
-					http.Error(w, fmt.Sprintf("Header %s is required", m.config.TenantIDHeader), http.StatusBadRequest)
-					return
-				}
+		handler := m.configuredRouteHandler(routePath, backendID)
 
-				// If this is a backend group, pick one now (round-robin) and substitute
-				resolvedBackendID := backendID
-				if strings.Contains(backendID, ",") {
-					selected, _, _ := m.selectBackendFromGroup(r.Context(), backendID)
-					if selected != "" {
-						resolvedBackendID = selected
-					}
-				}
-				// Check if this route has feature flag configuration
-				if m.config.RouteConfigs != nil {
-					if routeConfig, ok := m.config.RouteConfigs[routePath]; ok && routeConfig.FeatureFlagID != "" {
-						if !m.evaluateFeatureFlag(routeConfig.FeatureFlagID, r) {
-							// Feature flag is disabled, use alternative backend
-							alternativeBackend := m.getAlternativeBackend(routeConfig.AlternativeBackend)
-							if alternativeBackend != "" {
-								m.app.Logger().Debug("Feature flag disabled for route, using alternative backend",
-									"route", routePath, "flagID", routeConfig.FeatureFlagID,
-									"primary", backendID, "alternative", alternativeBackend)
+		// Remember the handler for dynamic route resolution (especially for wildcard patterns)
+		m.setBackendRoute(backendID, routePath, handler)
 
-								// Check if dry run is enabled for this route
-								if routeConfig.DryRun && m.dryRunHandler != nil {
-									// Determine which backend to compare against
-									dryRunBackend := routeConfig.DryRunBackend
-									if dryRunBackend == "" {
-										dryRunBackend = backendID // Default to primary for comparison
-									}
+		m.safeHandleFunc(routePath, m.withRouteMiddleware(routePath, handler))
+		registeredPaths[routePath] = true
 
-									m.app.Logger().Debug("Processing dry run request (feature flag disabled)",
-										"route", routePath, "returnBackend", alternativeBackend, "compareBackend", dryRunBackend)
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Info("Registered route", "route", routePath, "backend", backendID)
+		}
+	}
 
-									// Use dry run handler - return alternative backend response, compare with dry run backend
-									m.handleDryRunRequest(r.Context(), w, r, routeConfig, alternativeBackend, dryRunBackend)
-									return
-								}
+	// Register all composite routes
+	for pattern, handler := range m.compositeRoutes {
+		m.safeHandleFunc(pattern, m.withRouteMiddleware(pattern, handler))
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Info("Registered composite route", "route", pattern)
+		}
+	}
 
-								// Create handler for alternative backend
-								altHandler := m.createBackendProxyHandler(alternativeBackend)
-								altHandler(w, r)
-								return
-							} else {
-								// No alternative backend available
-								http.Error(w, "Backend temporarily unavailable", http.StatusServiceUnavailable)
-								return
-							}
-						} else {
-							// Feature flag is enabled, check for dry run
-							if routeConfig.DryRun && m.dryRunHandler != nil {
-								// Determine which backend to compare against
-								dryRunBackend := routeConfig.DryRunBackend
-								if dryRunBackend == "" {
-									dryRunBackend = m.getAlternativeBackend(routeConfig.AlternativeBackend) // Default to alternative for comparison
-								}
+	// Register catch-all route if not already registered and a default backend is configured
+	if m.defaultBackend != "" && !registeredPaths["/*"] {
+		m.backendProxiesMutex.RLock()
+		defaultProxy, exists := m.backendProxies[m.defaultBackend]
+		m.backendProxiesMutex.RUnlock()
+		if !exists || defaultProxy == nil {
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Warn("Default backend configured but proxy not available", "backend", m.defaultBackend)
+			}
+			return nil
+		}
+		handler := m.catchAllHandler()
 
-								if dryRunBackend != "" && dryRunBackend != backendID {
-									m.app.Logger().Debug("Processing dry run request (feature flag enabled)",
-										"route", routePath, "returnBackend", backendID, "compareBackend", dryRunBackend)
+		m.safeHandleFunc("/*", m.withRouteMiddleware("/*", handler))
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Info("Registered catch-all route with default backend fallback", "backend", m.defaultBackend)
+		}
+	}
 
-									// Use dry run handler - return primary backend response, compare with dry run backend
-									m.handleDryRunRequest(r.Context(), w, r, routeConfig, backendID, dryRunBackend)
-									return
-								}
-							}
-						}
-					}
-				}
+	return nil
+}
 
-				// Debug backend resolution
-				if m.app != nil && m.app.Logger() != nil {
-					m.app.Logger().Debug("Using primary backend for route",
-						"path", sanitizeForLogging(r.URL.Path),
-						"route", routePath,
-						"original_backend", backendID,
-						"resolved_backend", resolvedBackendID)
-				}
+// catchAllHandler serves requests no more specific pattern was registered for with
+// the best matching composite or configured route, or else the default backend.
+// The routes' middleware chains are built once, for the routes configured now;
+// only custom endpoints registered later get theirs on each request.
+func (m *ReverseProxyModule) catchAllHandler() http.HandlerFunc {
+	composites := make(map[string]http.HandlerFunc, len(m.compositeRoutes))
+	for pattern, handler := range m.compositeRoutes {
+		if handler != nil {
+			composites[pattern] = m.withRouteMiddleware(pattern, handler)
+		}
+	}
+	routes := make(map[string]http.HandlerFunc, len(m.config.Routes))
+	for pattern := range m.config.Routes {
+		routes[pattern] = m.withRouteMiddleware(pattern, m.createTenantAwareHandler(pattern))
+	}
 
-				// Use primary backend (feature flag enabled or no feature flag)
-				primaryHandler := m.createBackendProxyHandler(resolvedBackendID)
-				primaryHandler(w, r)
-			}
-		}(routePath, backendID)
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Exclude internal endpoints from proxying
+		if m.shouldExcludeFromProxy(r.URL.Path) {
+			http.NotFound(w, r)
+			return
+		}
 
-		// Remember the handler for dynamic route resolution (especially for wildcard patterns)
-		if _, ok := m.backendRoutes[backendID]; !ok {
-			m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
+		// Enforce tenant header requirement before attempting resolution
+		_, hasTenant := TenantIDFromRequest(m.config.TenantIDHeader, r)
+		if m.config.RequireTenantID && !hasTenant {
+			http.Error(w, fmt.Sprintf("Header %s is required", m.config.TenantIDHeader), http.StatusBadRequest)
+			return
 		}
-		m.backendRoutes[backendID][routePath] = handler
 
-		m.safeHandleFunc(routePath, handler)
-		registeredPaths[routePath] = true
+		// Try to match composite routes first
+		if pattern, compositeHandler, ok := m.findBestCompositeHandler(r.URL.Path, m.compositeRoutes); ok {
+			if chained, built := composites[pattern]; built {
+				compositeHandler = chained
+			} else {
+				compositeHandler = m.withRouteMiddleware(pattern, compositeHandler)
+			}
+			setMatchedRoute(r, pattern)
+			compositeHandler(w, r)
+			return
+		}
 
-		if m.app != nil && m.app.Logger() != nil {
-			m.app.Logger().Info("Registered route", "route", routePath, "backend", backendID)
+		// Then try explicit route patterns (including wildcard patterns)
+		if pattern, routeHandler, ok := m.findBestCompositeHandler(r.URL.Path, routes); ok {
+			setMatchedRoute(r, pattern)
+			routeHandler(w, r)
+			return
 		}
-	}
 
-	// Register all composite routes
-	for pattern, handler := range m.compositeRoutes {
-		m.safeHandleFunc(pattern, handler)
-		if m.app != nil && m.app.Logger() != nil {
-			m.app.Logger().Info("Registered composite route", "route", pattern)
+		// Fallback to default backend
+		if m.defaultBackend != "" {
+			h := m.createBackendProxyHandler(m.defaultBackend)
+			h(w, r)
+		} else {
+			// No default backend configured, return 404
+			http.NotFound(w, r)
 		}
 	}
+}
 
-	// Register catch-all route if not already registered and a default backend is configured
-	if m.defaultBackend != "" && !registeredPaths["/*"] {
-		m.backendProxiesMutex.RLock()
-		defaultProxy, exists := m.backendProxies[m.defaultBackend]
-		m.backendProxiesMutex.RUnlock()
-		if !exists || defaultProxy == nil {
-			if m.app != nil && m.app.Logger() != nil {
-				m.app.Logger().Warn("Default backend configured but proxy not available", "backend", m.defaultBackend)
-			}
-			return nil
+// configuredRouteHandler serves a route from the routes config, picking a backend of
+// a backend group and evaluating the route's feature flag on each request.
+func (m *ReverseProxyModule) configuredRouteHandler(routePath, backendID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Check tenant header enforcement first
+		_, hasTenant := TenantIDFromRequest(m.config.TenantIDHeader, r)
+		if m.config.RequireTenantID && !hasTenant {
+			http.Error(w, fmt.Sprintf("Header %s is required", m.config.TenantIDHeader), http.StatusBadRequest)
+			return
 		}
-		handler := func(w http.ResponseWriter, r *http.Request) {
-			// Exclude internal endpoints from proxying
-			if m.shouldExcludeFromProxy(r.URL.Path) {
-				http.NotFound(w, r)
-				return
-			}
 
-			// Enforce tenant header requirement before attempting resolution
-			_, hasTenant := TenantIDFromRequest(m.config.TenantIDHeader, r)
-			if m.config.RequireTenantID && !hasTenant {
-				http.Error(w, fmt.Sprintf("Header %s is required", m.config.TenantIDHeader), http.StatusBadRequest)
-				return
+		// If this is a backend group, pick one now (round-robin) and substitute
+		resolvedBackendID := backendID
+		if strings.Contains(backendID, ",") {
+			selected, _, _ := m.selectBackendFromGroup(r.Context(), backendID, m.config.RouteConfigs[routePath])
+			if selected != "" {
+				resolvedBackendID = selected
+				r = withRetryGroup(r, parseBackendGroup(backendID))
 			}
+		}
+		// Check if this route has feature flag configuration
+		if m.config.RouteConfigs != nil {
+			if routeConfig, ok := m.config.RouteConfigs[routePath]; ok && routeConfig.FeatureFlagID != "" {
+				if !m.evaluateFeatureFlag(routeConfig.FeatureFlagID, r) {
+					// Feature flag is disabled, use alternative backend
+					alternativeBackend := m.getAlternativeBackend(routeConfig.AlternativeBackend)
+					if alternativeBackend != "" {
+						m.app.Logger().Debug("Feature flag disabled for route, using alternative backend",
+							"route", routePath, "flagID", routeConfig.FeatureFlagID,
+							"primary", backendID, "alternative", alternativeBackend)
+
+						// Check if dry run is enabled for this route
+						if routeConfig.DryRun && m.dryRunHandler != nil {
+							// Determine which backend to compare against
+							dryRunBackend := routeConfig.DryRunBackend
+							if dryRunBackend == "" {
+								dryRunBackend = backendID // Default to primary for comparison
+							}
 
-			// Try to match composite routes first
-			if compositeHandler, ok := m.findBestCompositeHandler(r.URL.Path); ok {
-				compositeHandler(w, r)
-				return
-			}
+							m.app.Logger().Debug("Processing dry run request (feature flag disabled)",
+								"route", routePath, "returnBackend", alternativeBackend, "compareBackend", dryRunBackend)
 
-			// Then try explicit route patterns (including wildcard patterns)
-			if pattern, ok := m.findBestRoutePattern(r.URL.Path, m.config.Routes); ok {
-				routeHandler := m.createTenantAwareHandler(pattern)
-				routeHandler(w, r)
-				return
-			}
+							// Use dry run handler - return alternative backend response, compare with dry run backend
+							m.handleDryRunRequest(r.Context(), w, r, routeConfig, alternativeBackend, dryRunBackend)
+							return
+						}
 
-			// Fallback to default backend
-			if m.defaultBackend != "" {
-				h := m.createBackendProxyHandler(m.defaultBackend)
-				h(w, r)
-			} else {
-				// No default backend configured, return 404
-				http.NotFound(w, r)
+						// Create handler for alternative backend
+						altHandler := m.createBackendProxyHandler(alternativeBackend)
+						altHandler(w, r)
+						return
+					} else {
+						// No alternative backend available
+						http.Error(w, "Backend temporarily unavailable", http.StatusServiceUnavailable)
+						return
+					}
+				} else {
+					// Feature flag is enabled, check for dry run
+					if routeConfig.DryRun && m.dryRunHandler != nil {
+						// Determine which backend to compare against
+						dryRunBackend := routeConfig.DryRunBackend
+						if dryRunBackend == "" {
+							dryRunBackend = m.getAlternativeBackend(routeConfig.AlternativeBackend) // Default to alternative for comparison
+						}
+
+						if dryRunBackend != "" && dryRunBackend != backendID {
+							m.app.Logger().Debug("Processing dry run request (feature flag enabled)",
+								"route", routePath, "returnBackend", backendID, "compareBackend", dryRunBackend)
+
+							// Use dry run handler - return primary backend response, compare with dry run backend
+							m.handleDryRunRequest(r.Context(), w, r, routeConfig, backendID, dryRunBackend)
+							return
+						}
+					}
+				}
 			}
 		}
 
-		m.safeHandleFunc("/*", handler)
+		// Debug backend resolution
 		if m.app != nil && m.app.Logger() != nil {
-			m.app.Logger().Info("Registered catch-all route with default backend fallback", "backend", m.defaultBackend)
+			m.app.Logger().Debug("Using primary backend for route",
+				"path", sanitizeForLogging(r.URL.Path),
+				"route", routePath,
+				"original_backend", backendID,
+				"resolved_backend", resolvedBackendID)
 		}
-	}
 
-	return nil
+		// Use primary backend (feature flag enabled or no feature flag)
+		primaryHandler := m.createBackendProxyHandler(resolvedBackendID)
+		if resolvedBackendID != backendID {
+			primaryHandler = m.withLocalityLatency(resolvedBackendID, primaryHandler)
+		}
+		primaryHandler(w, r)
+	}
 }
 
-// findBestCompositeHandler returns the most specific composite route handler that matches the request path.
-// Specificity is determined by the longest matching pattern. If patterns have the same length, the first
-// encountered handler is used, which is acceptable because composite route keys are typically unique.
-func (m *ReverseProxyModule) findBestCompositeHandler(requestPath string) (http.HandlerFunc, bool) {
+// findBestCompositeHandler returns the most specific route pattern of handlers and its handler that match the
+// request path. Specificity is determined by the longest matching pattern. If patterns have the same length, the
+// first encountered handler is used, which is acceptable because route keys are typically unique.
+func (m *ReverseProxyModule) findBestCompositeHandler(requestPath string, handlers map[string]http.HandlerFunc) (string, http.HandlerFunc, bool) {
 	var (
 		selectedHandler http.HandlerFunc
 		selectedPattern string
 		matched         bool
 	)
 
-	for pattern, handler := range m.compositeRoutes {
+	for pattern, handler := range handlers {
 		if handler == nil {
 			continue
 		}
@@ -1383,7 +1892,7 @@ func (m *ReverseProxyModule) findBestCompositeHandler(requestPath string) (http.
 		}
 	}
 
-	return selectedHandler, matched
+	return selectedPattern, selectedHandler, matched
 }
 
 // findBestRoutePattern identifies the most specific route pattern matching the request path across the provided
@@ -1461,7 +1970,7 @@ func (m *ReverseProxyModule) registerTenantAwareRoutes() error {
 	}
 
 	// Add tenant-specific routes
-	for _, tenantCfg := range m.tenants {
+	for _, tenantCfg := range m.tenantConfigs() {
 		if tenantCfg != nil && tenantCfg.Routes != nil {
 			for routePath := range tenantCfg.Routes {
 				allPaths[routePath] = true
@@ -1472,7 +1981,7 @@ func (m *ReverseProxyModule) registerTenantAwareRoutes() error {
 	// Register specific routes first
 	for path := range allPaths {
 		// Create a handler that checks for tenant-specific routing
-		handler := m.createTenantAwareHandler(path)
+		handler := m.withRouteMiddleware(path, m.createTenantAwareHandler(path))
 
 		m.safeHandleFunc(path, handler)
 
@@ -1496,7 +2005,7 @@ func (m *ReverseProxyModule) registerTenantAwareRoutes() error {
 			tenantHandler := m.createTenantAwareCatchAllHandler()
 			tenantHandler(w, r)
 		}
-		m.safeHandleFunc("/*", catchAllHandler)
+		m.safeHandleFunc("/*", m.withRouteMiddleware("/*", catchAllHandler))
 
 		if m.app != nil && m.app.Logger() != nil {
 			m.app.Logger().Debug("Registered tenant-aware catch-all route")
@@ -1533,9 +2042,10 @@ func (m *ReverseProxyModule) SetHttpClient(client *http.Client) {
 	// Update the module's HTTP client
 	m.httpClient = client
 
-	// Update transport for all existing reverse proxies
+	// Update transport for all existing reverse proxies, except unix and h2c
+	// backends which need their scheme-specific transport
 	for _, proxy := range m.backendProxies {
-		if proxy != nil {
+		if proxy != nil && !isUpstreamTransport(proxy.Transport) {
 			proxy.Transport = client.Transport
 		}
 	}
@@ -1543,7 +2053,7 @@ func (m *ReverseProxyModule) SetHttpClient(client *http.Client) {
 	// Update transport for tenant-specific reverse proxies
 	for _, tenantProxies := range m.tenantBackendProxies {
 		for _, proxy := range tenantProxies {
-			if proxy != nil {
+			if proxy != nil && !isUpstreamTransport(proxy.Transport) {
 				proxy.Transport = client.Transport
 			}
 		}
@@ -1565,9 +2075,25 @@ func (m *ReverseProxyModule) SetResponseTransformer(pattern string, transformer
 	m.responseTransformers[pattern] = transformer
 }
 
+// SetResponseTransformerV2 sets a custom response transformer for a specific composite
+// route pattern that also receives the TransformContext of the request: its tenant,
+// evaluated feature flags, route metadata and the outcome of each backend request.
+// It takes precedence over a transformer set with SetResponseTransformer.
+func (m *ReverseProxyModule) SetResponseTransformerV2(pattern string, transformer ResponseTransformerV2) {
+	m.responseTransformersV2[pattern] = transformer
+}
+
 // createReverseProxyForBackend creates a reverse proxy for a specific backend with per-backend configuration.
 func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, target *url.URL, backendID string, endpoint string) *httputil.ReverseProxy {
-	proxy := httputil.NewSingleHostReverseProxy(target)
+	// unix:// and h2c:// backends are proxied as http through a scheme-specific transport
+	upstream, upstreamTransport, err := m.resolveUpstream(target)
+	if err != nil {
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Error("Invalid upstream URL for backend", "backend", backendID, "url", target.String(), "error", err)
+		}
+		upstream = target
+	}
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
 
 	// Emit proxy created event
 	m.emitEvent(ctx, EventTypeProxyCreated, map[string]interface{}{
@@ -1576,8 +2102,11 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 		"endpoint":   endpoint,
 	})
 
-	// Use the module's custom transport if available, otherwise set a default timeout-aware transport
-	if m.httpClient != nil && m.httpClient.Transport != nil {
+	// Use the scheme-specific transport or the module's custom transport if available,
+	// otherwise set a default timeout-aware transport
+	if upstreamTransport != nil {
+		proxy.Transport = upstreamTransport
+	} else if m.httpClient != nil && m.httpClient.Transport != nil {
 		proxy.Transport = m.httpClient.Transport
 	} else {
 		// Create a timeout-aware transport that respects request context
@@ -1596,7 +2125,7 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 	}
 
 	// Store the original target for use in the director function
-	originalTarget := *target
+	originalTarget := *upstream
 
 	// Create a custom director that handles hostname forwarding and path rewriting
 	proxy.Director = func(req *http.Request) {
@@ -1609,9 +2138,9 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 
 		// Get the appropriate configuration (tenant-specific or global)
 		var config *ReverseProxyConfig
-		if m.config != nil && hasTenant && m.tenants != nil {
+		if m.config != nil && hasTenant {
 			tenantID := modular.TenantID(tenantIDStr)
-			if tenantCfg, ok := m.tenants[tenantID]; ok && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				config = tenantCfg
 			} else {
 				config = m.config
@@ -1623,20 +2152,28 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 		// Apply path rewriting if configured
 		rewrittenPath := m.applyPathRewritingForBackend(req.URL.Path, config, backendID, endpoint)
 
+		// Use the URL picked by the backend URL resolver, if any
+		target := originalTarget
+		if resolved, ok := resolvedBackendURLFromContext(req.Context(), backendID); ok {
+			target = *resolved
+		}
+
 		// Set up the request URL
-		req.URL.Scheme = originalTarget.Scheme
-		req.URL.Host = originalTarget.Host
-		req.URL.Path = singleJoiningSlash(originalTarget.Path, rewrittenPath)
+		req.URL.Scheme = target.Scheme
+		req.URL.Host = target.Host
+		req.URL.Path = singleJoiningSlash(target.Path, rewrittenPath)
 
 		// Handle query parameters
-		if originalTarget.RawQuery != "" && req.URL.RawQuery != "" {
-			req.URL.RawQuery = originalTarget.RawQuery + "&" + req.URL.RawQuery
-		} else if originalTarget.RawQuery != "" {
-			req.URL.RawQuery = originalTarget.RawQuery
+		if target.RawQuery != "" && req.URL.RawQuery != "" {
+			req.URL.RawQuery = target.RawQuery + "&" + req.URL.RawQuery
+		} else if target.RawQuery != "" {
+			req.URL.RawQuery = target.RawQuery
 		}
 
-		// Apply header rewriting
-		m.applyHeaderRewritingForBackend(req, config, backendID, endpoint, &originalTarget)
+		// Remember the host the client addressed for rewriting redirects, then apply
+		// header rewriting
+		*req = *withPublicOrigin(req)
+		m.applyHeaderRewritingForBackend(req, config, backendID, endpoint, &target)
 	}
 
 	// If a custom director factory is available, use it (this is for advanced use cases)
@@ -1677,6 +2214,23 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 
 	// Set up error handler to return proper HTTP status codes for connection failures
 	proxy.ErrorHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+		// The client went away; the backend is not at fault and nobody will read a response.
+		// Handlers report the abort once they observe the cancelled request context.
+		if clientAborted(r.Context()) {
+			if sw, ok := w.(*statusCapturingResponseWriter); ok {
+				sw.mu.Lock()
+				defer sw.mu.Unlock()
+				if !sw.wroteHeader {
+					sw.status = StatusClientClosedRequest
+					sw.wroteHeader = true
+					sw.ResponseWriter.WriteHeader(StatusClientClosedRequest)
+				}
+				return
+			}
+			w.WriteHeader(StatusClientClosedRequest)
+			return
+		}
+
 		// Log the error for debugging
 		if m.app != nil && m.app.Logger() != nil {
 			m.app.Logger().Error("Proxy error", "backend", backendID, "error", err.Error())
@@ -1704,11 +2258,15 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 
 			// Directly access underlying ResponseWriter since we already hold the lock
 			// Do not call sw.WriteHeader() or sw.Write() as they would try to acquire the lock again
-			sw.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
-			sw.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
-
 			sw.status = statusCode
 			sw.wroteHeader = true
+			if statusCode == http.StatusGatewayTimeout {
+				m.writeGatewayTimeout(sw.ResponseWriter, r, backendID, classifyTimeoutStage(err), message)
+				return
+			}
+
+			sw.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			sw.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
 			sw.ResponseWriter.WriteHeader(statusCode)
 			if _, writeErr := sw.ResponseWriter.Write([]byte(message + "\n")); writeErr != nil {
 				// Log write error but don't block response completion
@@ -1716,6 +2274,8 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 					m.app.Logger().Warn("Failed to write error response body", "backend", backendID, "error", writeErr.Error())
 				}
 			}
+		} else if statusCode == http.StatusGatewayTimeout {
+			m.writeGatewayTimeout(w, r, backendID, classifyTimeoutStage(err), message)
 		} else {
 			// For non-statusCapturingResponseWriter, use standard http.Error
 			http.Error(w, message, statusCode)
@@ -1738,9 +2298,9 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 
 		// Get the appropriate configuration (tenant-specific or global)
 		var config *ReverseProxyConfig
-		if m.config != nil && hasTenant && m.tenants != nil {
+		if m.config != nil && hasTenant {
 			tenantID := modular.TenantID(tenantIDStr)
-			if tenantCfg, ok := m.tenants[tenantID]; ok && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				config = tenantCfg
 			} else {
 				config = m.config
@@ -1749,6 +2309,11 @@ func (m *ReverseProxyModule) createReverseProxyForBackend(ctx context.Context, t
 			config = m.config
 		}
 
+		// Follow or rewrite backend redirects, before the final response's headers are rewritten
+		if err := m.applyRedirectPolicy(resp, config, backendID, proxy.Transport); err != nil {
+			return err
+		}
+
 		// Apply configured response header rewriting
 		m.applyResponseHeaderRewritingForBackend(resp, config, backendID, endpoint)
 
@@ -1897,8 +2462,15 @@ func (m *ReverseProxyModule) AddBackend(backendID, serviceURL string) error { //
 	return nil
 }
 
-// RemoveBackend removes an existing backend at runtime and emits a backend.removed event.
+// RemoveBackend removes an existing backend at runtime. New requests to the backend are
+// rejected with 503 while in-flight ones get up to BackendDrainTimeout to finish; the
+// proxy is then torn down and backend.drained and backend.removed events are emitted.
 func (m *ReverseProxyModule) RemoveBackend(backendID string) error { //nolint:ireturn
+	return m.RemoveBackendWithContext(context.Background(), backendID)
+}
+
+// RemoveBackendWithContext is like RemoveBackend, but cancelling ctx cuts the drain short.
+func (m *ReverseProxyModule) RemoveBackendWithContext(ctx context.Context, backendID string) error { //nolint:ireturn
 	if backendID == "" {
 		return ErrBackendIDRequired
 	}
@@ -1910,15 +2482,28 @@ func (m *ReverseProxyModule) RemoveBackend(backendID string) error { //nolint:ir
 		return fmt.Errorf("%w: %s", ErrBackendNotConfigured, backendID)
 	}
 
-	// Remove from maps
+	// Stop routing new requests and let in-flight ones finish
+	m.drainBackend(ctx, backendID)
+	defer m.drains.finishDrain(backendID)
+
+	// Remove from maps and release the proxy's connections
 	delete(m.config.BackendServices, backendID)
+	m.backendProxiesMutex.Lock()
+	proxy := m.backendProxies[backendID]
 	delete(m.backendProxies, backendID)
+	m.backendProxiesMutex.Unlock()
+	m.backendRoutesMutex.Lock()
 	delete(m.backendRoutes, backendID)
+	m.backendRoutesMutex.Unlock()
 	delete(m.circuitBreakers, backendID)
+	m.concurrency.remove(backendID)
+	if proxy != nil {
+		m.closeProxyTransport(proxy.Transport)
+	}
 
 	// Emit removal event
 	if m.initialized {
-		m.emitEvent(context.Background(), EventTypeBackendRemoved, map[string]interface{}{ //nolint:contextcheck // backend removal triggered outside request path
+		m.emitEvent(ctx, EventTypeBackendRemoved, map[string]interface{}{
 			"backend": backendID,
 			"url":     serviceURL,
 			"time":    time.Now().UTC().Format(time.RFC3339Nano),
@@ -1928,45 +2513,53 @@ func (m *ReverseProxyModule) RemoveBackend(backendID string) error { //nolint:ir
 	return nil
 }
 
-// selectBackendFromGroup performs a simple round-robin selection from a comma-separated backend group spec.
+// selectBackendFromGroup selects a backend from a comma-separated backend group spec with the
+// route's load balancing policy, round-robin by default.
 // Returns selected backend id, selected index, and total backends.
-func (m *ReverseProxyModule) selectBackendFromGroup(ctx context.Context, group string) (string, int, int) {
-	parts := strings.Split(group, ",")
-	var backends []string
-	for _, p := range parts {
-		b := strings.TrimSpace(p)
-		if b != "" {
-			backends = append(backends, b)
-		}
-	}
+func (m *ReverseProxyModule) selectBackendFromGroup(ctx context.Context, group string, route RouteConfig) (string, int, int) {
+	backends := parseBackendGroup(group)
 	if len(backends) == 0 {
 		return "", 0, 0
 	}
+	// With locality, rotate among the closest available backends
+	candidates, counter := backends, group
+	if m.locality != nil {
+		candidates, counter = m.localityCandidates(ctx, group, backends)
+	}
 	m.loadBalanceMutex.Lock()
-	idx := m.loadBalanceCounters[group] % len(backends)
-	m.loadBalanceCounters[group] = m.loadBalanceCounters[group] + 1
+	selected := m.pickBackend(route, counter, candidates)
 	m.loadBalanceMutex.Unlock()
 
-	selected := backends[idx]
+	idx := slices.Index(backends, selected)
+	if m.locality != nil {
+		m.locality.recordSelection(selected, m.locality.config.scope(m.backendLocality(selected)))
+	}
 
 	// Emit load balancing decision events if module initialized so tests can observe
 	if m.initialized {
 		// Generic decision event (once per selection)
+		policy := route.LoadBalancingPolicy
+		if policy == "" {
+			policy = LoadBalancingRoundRobin
+		}
 		m.emitEvent(ctx, EventTypeLoadBalanceDecision, map[string]interface{}{
 			"group":            group,
+			"policy":           policy,
 			"selected_backend": selected,
 			"index":            idx,
 			"total":            len(backends),
 			"time":             time.Now().UTC().Format(time.RFC3339Nano),
 		})
 		// Round-robin specific event includes rotation information
-		m.emitEvent(ctx, EventTypeLoadBalanceRoundRobin, map[string]interface{}{
-			"group":         group,
-			"backend":       selected,
-			"current_index": idx,
-			"total":         len(backends),
-			"time":          time.Now().UTC().Format(time.RFC3339Nano),
-		})
+		if policy == LoadBalancingRoundRobin {
+			m.emitEvent(ctx, EventTypeLoadBalanceRoundRobin, map[string]interface{}{
+				"group":         group,
+				"backend":       selected,
+				"current_index": idx,
+				"total":         len(backends),
+				"time":          time.Now().UTC().Format(time.RFC3339Nano),
+			})
+		}
 	}
 
 	return selected, idx, len(backends)
@@ -2365,6 +2958,14 @@ func (w *bufferingResponseWriter) flushTo(target http.ResponseWriter) error {
 // to a specific backend, with support for tenant-specific backends and feature flag evaluation
 func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.HandlerFunc {
 	handler := func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r = withInboundContext(r)
+
+		// Make the tenant visible to sampling rules with a tenant allowlist
+		if m.eventSampler != nil && m.eventSampler.tenantAllowed {
+			r = withRequestTenant(r, m.config.TenantIDHeader)
+		}
+
 		// Emit request received event
 		m.emitEvent(r.Context(), EventTypeRequestReceived, map[string]interface{}{
 			"backend":     backend,
@@ -2410,9 +3011,17 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 				"timeout_source", timeoutSource)
 		}
 
+		// Streaming uploads are bounded by their own deadline; the request timeout then
+		// only limits waiting for the response headers
+		deadline := requestTimeout
+		if upload, ok := streamingUpload(r.Context()); ok {
+			deadline = upload.maxDuration()
+		}
+
 		// Create context with timeout
-		ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
+		ctx, cancel := context.WithTimeout(r.Context(), deadline)
 		defer cancel()
+		ctx = withRequestTimeoutInfo(ctx, m.newRequestTimeoutInfo(r, backend, deadline, timeoutSource))
 		r = r.WithContext(ctx)
 
 		// Extract tenant ID from request header, if present
@@ -2446,11 +3055,31 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 			}
 		}
 
+		// Answer requests in maintenance without proxying them
+		if m.serveMaintenance(w, r, finalBackend) {
+			return
+		}
+
 		// Record request to backend for health checking
 		if m.healthChecker != nil {
 			m.healthChecker.RecordBackendRequest(finalBackend)
 		}
 
+		// Track the request so that removing the backend drains it instead of cutting it off
+		release, accepted := m.drains.acquire(finalBackend)
+		if !accepted {
+			http.Error(w, fmt.Sprintf("Backend %s is being removed", finalBackend), http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+
+		// Shed the requests beyond the backend's adaptive concurrency limit
+		w, releaseConcurrency, admitted := m.acquireConcurrency(w, r, finalBackend)
+		if !admitted {
+			return
+		}
+		defer releaseConcurrency()
+
 		// Get the appropriate proxy for this backend and tenant
 		proxy, exists := m.getProxyForBackendAndTenant(finalBackend, tenantID)
 		if !exists {
@@ -2458,6 +3087,16 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 			return
 		}
 
+		// Let the backend URL resolver pick the upstream of this request
+		r, ok := m.resolveBackendURL(w, r, finalBackend, tenantID)
+		if !ok {
+			return
+		}
+
+		// Roll the fault injection rules; the chosen fault is injected by the transport
+		r = m.selectFault(r, finalBackend)
+		r = m.selectRetry(r, finalBackend)
+
 		// Check if circuit breaker is enabled for this backend
 		var cb *CircuitBreaker
 		var cbEnabled bool
@@ -2519,21 +3158,12 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 					m.emitEvent(context.Background(), eventType, data)
 				}
 			}
-			// Create a timeout-aware transport
-			timeoutTransport := &http.Transport{
-				DialContext: (&net.Dialer{
-					Timeout:   requestTimeout,
-					KeepAlive: 30 * time.Second,
-				}).DialContext,
-				TLSHandshakeTimeout:   10 * time.Second,
-				ResponseHeaderTimeout: requestTimeout,
-				ExpectContinueTimeout: 1 * time.Second,
-			}
-
 			// Create a copy of the proxy with the timeout transport
+			transport := m.requestTransport(proxy.Transport, requestTimeout)
+			setRequestTransport(r.Context(), transport)
 			proxyCopy := &httputil.ReverseProxy{
 				Director:       proxy.Director,
-				Transport:      timeoutTransport,
+				Transport:      m.retryTransport(m.faultTransport(transport)),
 				FlushInterval:  proxy.FlushInterval,
 				ErrorLog:       proxy.ErrorLog,
 				BufferPool:     proxy.BufferPool,
@@ -2590,6 +3220,11 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 					}
 				}
 
+				if clientAborted(r.Context()) {
+					m.recordClientAbort(r, finalBackend, tenantID, start)
+					return
+				}
+
 				// Check if the request context was cancelled due to timeout OR if circuit breaker error indicates timeout
 				contextCancelled := r.Context().Err() != nil
 				timeoutError := cbErr != nil && (strings.Contains(cbErr.Error(), "context deadline exceeded") ||
@@ -2610,20 +3245,26 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 						sw.mu.Lock()
 						if !sw.wroteHeader {
 							// Directly access underlying ResponseWriter since we already hold the lock
-							sw.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
-							sw.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
 							sw.status = http.StatusGatewayTimeout
 							sw.wroteHeader = true
-							sw.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
-							fmt.Fprintln(sw.ResponseWriter, "Request timeout")
+							m.writeGatewayTimeout(sw.ResponseWriter, r, backend, TimeoutStageTotal, "Request timeout")
 						}
+						bufferedTimeout := sw.status == http.StatusGatewayTimeout
 						sw.mu.Unlock()
+
+						// Deliver the buffered timeout response (which may carry stage details from the
+						// proxy error handler); a late backend response is discarded in favor of a timeout
+						bufWriter, ok := sw.ResponseWriter.(*bufferingResponseWriter)
+						if ok && bufferedTimeout {
+							if err := bufWriter.flushTo(w); err != nil && m.app != nil && m.app.Logger() != nil {
+								m.app.Logger().Error("Failed to flush buffered timeout response", "error", err)
+							}
+						} else {
+							m.writeGatewayTimeout(w, r, backend, TimeoutStageTotal, "Request timeout")
+						}
 					} else {
 						// Fallback for direct writing (buffering writer case)
-						w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-						w.Header().Set("X-Content-Type-Options", "nosniff")
-						w.WriteHeader(http.StatusGatewayTimeout)
-						fmt.Fprintln(w, "Request timeout")
+						m.writeGatewayTimeout(w, r, backend, TimeoutStageTotal, "Request timeout")
 					}
 					return
 				}
@@ -2688,6 +3329,11 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 					}
 				}
 			case <-r.Context().Done():
+				if clientAborted(r.Context()) {
+					m.recordClientAbort(r, finalBackend, tenantID, start)
+					return
+				}
+
 				// Request timed out
 				// Emit request failed event for timeout
 				m.emitEvent(r.Context(), EventTypeRequestFailed, map[string]interface{}{
@@ -2698,10 +3344,7 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 				})
 				// Since we used a buffering response writer, write timeout response through buffer
 				// This is safe because bufferingResponseWriter doesn't write to actual response yet
-				w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-				w.Header().Set("X-Content-Type-Options", "nosniff")
-				w.WriteHeader(http.StatusGatewayTimeout)
-				fmt.Fprintln(w, "Request timeout")
+				m.writeGatewayTimeout(w, r, backend, TimeoutStageTotal, "Request timeout")
 				return
 			}
 
@@ -2727,9 +3370,11 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 		} else {
 			// No circuit breaker, use the proxy directly but capture status and apply timeout
 			// Create a request-specific proxy to avoid race conditions on shared Transport field
+			transport := m.requestTransport(proxy.Transport, requestTimeout)
+			setRequestTransport(r.Context(), transport)
 			proxyForRequest := &httputil.ReverseProxy{
 				Director:       proxy.Director,
-				Transport:      proxy.Transport, // Start with the original transport
+				Transport:      m.retryTransport(m.faultTransport(transport)),
 				FlushInterval:  proxy.FlushInterval,
 				ErrorLog:       proxy.ErrorLog,
 				BufferPool:     proxy.BufferPool,
@@ -2737,34 +3382,6 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 				ErrorHandler:   proxy.ErrorHandler, // Critical: copy the custom error handler
 			}
 
-			// Configure request-specific timeout transport without modifying shared proxy
-			if proxyForRequest.Transport != nil {
-				if transport, ok := proxyForRequest.Transport.(*http.Transport); ok {
-					// Clone the transport and update timeout settings for this request
-					transportCopy := transport.Clone()
-					transportCopy.ResponseHeaderTimeout = requestTimeout
-					transportCopy.DialContext = (&net.Dialer{
-						Timeout:   requestTimeout,
-						KeepAlive: 30 * time.Second,
-					}).DialContext
-					proxyForRequest.Transport = transportCopy
-				}
-			} else {
-				// Set a timeout-aware transport if none exists
-				proxyForRequest.Transport = &http.Transport{
-					DialContext: (&net.Dialer{
-						Timeout:   requestTimeout,
-						KeepAlive: 30 * time.Second,
-					}).DialContext,
-					TLSHandshakeTimeout:   10 * time.Second,
-					ResponseHeaderTimeout: requestTimeout,
-					ExpectContinueTimeout: 1 * time.Second,
-					MaxIdleConns:          100,
-					MaxIdleConnsPerHost:   10,
-					IdleConnTimeout:       90 * time.Second,
-				}
-			}
-
 			// Create a timeout context for the request
 			done := make(chan struct{})
 			var swMutex sync.Mutex
@@ -2792,6 +3409,11 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 			case <-done:
 				// Request completed successfully
 			case <-r.Context().Done():
+				if clientAborted(r.Context()) {
+					m.recordClientAbort(r, finalBackend, tenantID, start)
+					return
+				}
+
 				// Request timed out
 				// Emit request failed event for timeout
 				m.emitEvent(r.Context(), EventTypeRequestFailed, map[string]interface{}{
@@ -2810,24 +3432,23 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 					localSW.mu.Lock()
 					if !localSW.wroteHeader {
 						// Directly access underlying ResponseWriter since we already hold the lock
-						localSW.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
-						localSW.ResponseWriter.Header().Set("X-Content-Type-Options", "nosniff")
 						localSW.status = http.StatusGatewayTimeout
 						localSW.wroteHeader = true
-						localSW.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
-						fmt.Fprintln(localSW.ResponseWriter, "Request timeout")
+						m.writeGatewayTimeout(localSW.ResponseWriter, r, backend, TimeoutStageTotal, "Request timeout")
 					}
 					localSW.mu.Unlock()
 				} else {
 					// Fallback to direct response writer (shouldn't happen in normal flow)
-					w.Header().Set("Content-Type", "text/plain; charset=utf-8")
-					w.Header().Set("X-Content-Type-Options", "nosniff")
-					w.WriteHeader(http.StatusGatewayTimeout)
-					fmt.Fprintln(w, "Request timeout")
+					m.writeGatewayTimeout(w, r, backend, TimeoutStageTotal, "Request timeout")
 				}
 				return
 			}
 
+			if clientAborted(r.Context()) {
+				m.recordClientAbort(r, finalBackend, tenantID, start)
+				return
+			}
+
 			// Emit success or failure event based on status code
 			swMutex.Lock()
 			localSW := sw
@@ -2858,12 +3479,14 @@ func (m *ReverseProxyModule) createBackendProxyHandler(backend string) http.Hand
 		}
 	}
 
+	handler = m.withBackendSLO(backend, handler)
+
 	// Wrap with cache if enabled
 	if m.responseCache != nil {
-		return m.withCache(handler, backend)
+		handler = m.withCache(handler, backend)
 	}
 
-	return handler
+	return m.withReadinessGate(backend, m.withProxyMiddleware(ProxyTarget{Backend: backend}, handler))
 }
 
 // createBackendProxyHandler creates an http.HandlerFunc that handles proxying requests
@@ -2901,7 +3524,10 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 		}
 	}
 
-	return func(w http.ResponseWriter, r *http.Request) {
+	return m.withReadinessGate(backend, m.withProxyMiddleware(ProxyTarget{Backend: backend}, m.withBackendSLO(backend, func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r = withInboundContext(r)
+
 		// Emit request received event (tenant-aware)
 		m.emitEvent(r.Context(), EventTypeRequestReceived, map[string]interface{}{
 			"backend": backend,
@@ -2912,7 +3538,7 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 
 		// Get tenant-specific merged config (fallback to global if not found)
 		tenantCfg := m.config
-		if mergedCfg, exists := m.tenants[tenantID]; exists && mergedCfg != nil {
+		if mergedCfg := m.tenantConfig(tenantID); mergedCfg != nil {
 			tenantCfg = mergedCfg
 		}
 
@@ -2959,8 +3585,14 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 		// Create context with timeout
 		ctx, cancel := context.WithTimeout(r.Context(), requestTimeout)
 		defer cancel()
+		ctx = withRequestTimeoutInfo(ctx, m.newRequestTimeoutInfo(r, backend, requestTimeout, timeoutSource))
 		r = r.WithContext(ctx)
 
+		// Answer requests in maintenance without proxying them
+		if m.serveMaintenance(w, r, backend) {
+			return
+		}
+
 		// Record request to backend for health checking
 		if m.healthChecker != nil {
 			m.healthChecker.RecordBackendRequest(backend)
@@ -2971,6 +3603,31 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 			return
 		}
 
+		// Track the request so that removing the backend drains it instead of cutting it off
+		release, accepted := m.drains.acquire(backend)
+		if !accepted {
+			http.Error(w, fmt.Sprintf("Backend %s is being removed", backend), http.StatusServiceUnavailable)
+			return
+		}
+		defer release()
+
+		// Shed the requests beyond the backend's adaptive concurrency limit
+		w, releaseConcurrency, admitted := m.acquireConcurrency(w, r, backend)
+		if !admitted {
+			return
+		}
+		defer releaseConcurrency()
+
+		// Let the backend URL resolver pick the upstream of this request
+		r, ok := m.resolveBackendURL(w, r, backend, tenantID)
+		if !ok {
+			return
+		}
+
+		// Roll the fault injection rules; the chosen fault is injected by the transport
+		r = m.selectFault(r, backend)
+		r = m.selectRetry(r, backend)
+
 		// If circuit breaker is available, wrap the proxy request with it
 		if cb != nil {
 			// Create a custom RoundTripper that applies circuit breaking
@@ -2978,6 +3635,8 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 			if originalTransport == nil {
 				originalTransport = http.DefaultTransport
 			}
+			setRequestTransport(r.Context(), originalTransport)
+			originalTransport = m.retryTransport(m.faultTransport(originalTransport))
 
 			// Execute the request via circuit breaker
 			resp, err := cb.Execute(r, func(req *http.Request) (*http.Response, error) {
@@ -3002,6 +3661,14 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 				return recorder.Result(), nil
 			})
 
+			if clientAborted(ctx) {
+				if resp != nil && resp.Body != nil {
+					resp.Body.Close()
+				}
+				m.recordClientAbort(r, backend, tenantID, start)
+				return
+			}
+
 			if errors.Is(err, ErrCircuitOpen) {
 				// Circuit is open, return service unavailable
 				if m.app != nil && m.app.Logger() != nil {
@@ -3073,7 +3740,13 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 		} else {
 			// No circuit breaker, use the proxy directly but capture status
 			sw := &statusCapturingResponseWriter{ResponseWriter: w, status: http.StatusOK}
-			proxy.ServeHTTP(sw, r) //nolint:gosec // G704: reverse proxy intentionally forwards requests to configured backends
+			setRequestTransport(r.Context(), proxy.Transport)
+			m.retryProxy(m.faultProxy(proxy)).ServeHTTP(sw, r) //nolint:gosec // G704: reverse proxy intentionally forwards requests to configured backends
+
+			if clientAborted(ctx) {
+				m.recordClientAbort(r, backend, tenantID, start)
+				return
+			}
 
 			// Emit success or failure event based on status code
 			if sw.status >= 400 {
@@ -3095,7 +3768,7 @@ func (m *ReverseProxyModule) createBackendProxyHandlerForTenant(tenantID modular
 				})
 			}
 		}
-	}
+	})))
 }
 
 // getProxyForBackendAndTenant returns the appropriate proxy for a backend and tenant.
@@ -3156,10 +3829,7 @@ func (m *ReverseProxyModule) AddBackendRoute(backendID, routePattern string) err
 	handler := m.createBackendProxyHandler(backendID)
 
 	// Store the handler in the backend routes map
-	if _, ok := m.backendRoutes[backendID]; !ok {
-		m.backendRoutes[backendID] = make(map[string]http.HandlerFunc)
-	}
-	m.backendRoutes[backendID][routePattern] = handler
+	m.setBackendRoute(backendID, routePattern, handler)
 
 	// Register the handler with the router immediately if router is available
 	if m.router != nil {
@@ -3234,6 +3904,15 @@ func (m *ReverseProxyModule) RegisterCustomEndpoint(pattern string, mapping Endp
 			return
 		}
 
+		var tc *TransformContext
+		if mapping.ResponseTransformerV2 != nil {
+			backends := make([]string, 0, len(mapping.Endpoints))
+			for _, endpoint := range mapping.Endpoints {
+				backends = append(backends, endpoint.Backend)
+			}
+			tc = m.newTransformContext(r, pattern, nil, backends, mapping.Metadata)
+		}
+
 		// Execute all endpoint requests
 		for _, endpoint := range mapping.Endpoints {
 			// Get the backend service URL
@@ -3241,7 +3920,7 @@ func (m *ReverseProxyModule) RegisterCustomEndpoint(pattern string, mapping Endp
 
 			// First check if we have a tenant-specific service URL
 			if hasTenant {
-				if tenantCfg, ok := m.tenants[tenantID]; ok && tenantCfg != nil {
+				if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 					if tenantURL, ok := tenantCfg.BackendServices[endpoint.Backend]; ok && tenantURL != "" {
 						backendURL = tenantURL
 					}
@@ -3302,7 +3981,15 @@ func (m *ReverseProxyModule) RegisterCustomEndpoint(pattern string, mapping Endp
 			}
 
 			// Execute the request
+			start := time.Now()
 			resp, err := m.httpClient.Do(req) //nolint:bodyclose,gosec // bodyclose: body is closed in defer cleanup; G704: reverse proxy intentionally forwards requests to configured backends
+			if tc != nil {
+				result := BackendResult{Latency: time.Since(start), Err: err}
+				if err == nil {
+					result.StatusCode = resp.StatusCode
+				}
+				tc.Backends[endpoint.Backend] = result
+			}
 			if err != nil {
 				m.app.Logger().Error("Failed to execute request", "backend", endpoint.Backend, "error", err)
 				continue
@@ -3316,7 +4003,13 @@ func (m *ReverseProxyModule) RegisterCustomEndpoint(pattern string, mapping Endp
 		}
 
 		// Apply the response transformer
-		result, err := mapping.ResponseTransformer(ctx, r, responses)
+		var result *CompositeResponse
+		var err error
+		if tc != nil {
+			result, err = mapping.ResponseTransformerV2(ctx, tc, r, responses)
+		} else {
+			result, err = mapping.ResponseTransformer(ctx, r, responses)
+		}
 		if err != nil {
 			m.app.Logger().Error("Failed to transform response", "error", err)
 			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
@@ -3438,6 +4131,18 @@ func mergeConfigs(global, tenant *ReverseProxyConfig) *ReverseProxyConfig {
 		merged.CacheEnabled = global.CacheEnabled
 		merged.CacheTTL = global.CacheTTL
 	}
+	merged.CacheStaleWhileRevalidate = global.CacheStaleWhileRevalidate
+	if tenant.CacheStaleWhileRevalidate > 0 {
+		merged.CacheStaleWhileRevalidate = tenant.CacheStaleWhileRevalidate
+	}
+	merged.CacheStaleIfError = global.CacheStaleIfError
+	if tenant.CacheStaleIfError > 0 {
+		merged.CacheStaleIfError = tenant.CacheStaleIfError
+	}
+	merged.CacheKey = global.CacheKey
+	if tenant.CacheKey.isSet() {
+		merged.CacheKey = tenant.CacheKey
+	}
 
 	// Request timeout - prefer tenant's if specified
 	if tenant.RequestTimeout > 0 {
@@ -3489,6 +4194,15 @@ func mergeConfigs(global, tenant *ReverseProxyConfig) *ReverseProxyConfig {
 		}
 	}
 
+	// Timeout response settings - prefer tenant's if specified
+	merged.TimeoutResponse = global.TimeoutResponse
+	if tenant.TimeoutResponse.Verbosity != "" {
+		merged.TimeoutResponse.Verbosity = tenant.TimeoutResponse.Verbosity
+	}
+	if tenant.TimeoutResponse.CorrelationIDHeader != "" {
+		merged.TimeoutResponse.CorrelationIDHeader = tenant.TimeoutResponse.CorrelationIDHeader
+	}
+
 	// Health check config - prefer tenant's if specified
 	if tenant.HealthCheck.Enabled {
 		merged.HealthCheck = tenant.HealthCheck
@@ -3514,8 +4228,49 @@ func (m *ReverseProxyModule) registerMetricsEndpoint(endpoint string) {
 	}
 
 	metricsHandler := func(w http.ResponseWriter, r *http.Request) {
+		if wantsPrometheus(r) {
+			w.Header().Set("Content-Type", PrometheusContentType)
+			p := newPrometheusWriter(w)
+			m.metrics.writePrometheus(p)
+			if m.slo != nil {
+				m.slo.writePrometheus(p)
+			}
+			if m.bandwidth != nil {
+				m.bandwidth.writePrometheus(p)
+			}
+			m.concurrency.writePrometheus(p)
+			if status := m.LocalityStatus(); status != nil {
+				m.locality.writePrometheus(p, *status)
+			}
+			if m.uploads != nil {
+				m.uploads.writePrometheus(p)
+			}
+			if err := p.flush(); err != nil && m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Error("Failed to write metrics response", "error", err)
+			}
+			return
+		}
+
 		// Get current metrics data
 		metrics := m.metrics.GetMetrics()
+		if m.slo != nil {
+			metrics["slo"] = m.slo.statuses()
+		}
+		if m.bandwidth != nil {
+			metrics["bandwidth"] = m.bandwidth.statuses()
+		}
+		if m.rateLimit != nil {
+			metrics["rate_limit"] = m.rateLimit.statuses()
+		}
+		if statuses := m.concurrency.statuses(); len(statuses) > 0 {
+			metrics["adaptive_concurrency"] = statuses
+		}
+		if status := m.LocalityStatus(); status != nil {
+			metrics["locality"] = status
+		}
+		if status := m.UploadStatus(); status != nil {
+			metrics["uploads"] = status
+		}
 
 		// Convert to JSON
 		jsonData, err := json.Marshal(metrics)
@@ -3615,6 +4370,7 @@ func (m *ReverseProxyModule) registerDebugEndpoints() error {
 		}
 		debugHandler.SetHealthCheckers(healthCheckers)
 	}
+	debugHandler.SetSLOStatusFunc(m.SLOStatus)
 
 	// Register debug endpoints individually since our routerService doesn't support http.ServeMux
 	basePath := m.config.DebugEndpoints.BasePath
@@ -3644,6 +4400,11 @@ func (m *ReverseProxyModule) registerDebugEndpoints() error {
 	m.safeHandleFunc(healthChecksEndpoint, debugHandler.HandleHealthChecks)
 	m.app.Logger().Info("Registered debug endpoint", "endpoint", healthChecksEndpoint)
 
+	// SLO status endpoint
+	sloEndpoint := basePath + "/slo"
+	m.safeHandleFunc(sloEndpoint, debugHandler.HandleSLO)
+	m.app.Logger().Info("Registered debug endpoint", "endpoint", sloEndpoint)
+
 	m.app.Logger().Info("Debug endpoints registered", "basePath", basePath)
 	return nil
 }
@@ -3667,7 +4428,7 @@ func (m *ReverseProxyModule) createTenantAwareHandler(path string) http.HandlerF
 		var effectiveConfig *ReverseProxyConfig
 		if hasTenant {
 			tenantID := modular.TenantID(tenantIDStr)
-			if tenantCfg, exists := m.tenants[tenantID]; exists && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				effectiveConfig = tenantCfg
 			} else {
 				effectiveConfig = m.config
@@ -3763,7 +4524,7 @@ func (m *ReverseProxyModule) createTenantAwareHandler(path string) http.HandlerF
 			tenantID := modular.TenantID(tenantIDStr)
 
 			// Check if we have a tenant-specific configuration
-			if tenantCfg, exists := m.tenants[tenantID]; exists && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				// Check for tenant-specific route
 				if tenantCfg.Routes != nil {
 					if backendID, ok := tenantCfg.Routes[path]; ok {
@@ -3812,7 +4573,7 @@ func (m *ReverseProxyModule) createTenantAwareHandler(path string) http.HandlerF
 		// After global routes are checked, check for tenant default backend
 		if hasTenant {
 			tenantID := modular.TenantID(tenantIDStr)
-			if tenantCfg, exists := m.tenants[tenantID]; exists && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				// Check if tenant has default backend
 				if tenantCfg.DefaultBackend != "" {
 					handler := m.createBackendProxyHandlerForTenant(tenantID, tenantCfg.DefaultBackend) //nolint:contextcheck // tenant handler leverages request context
@@ -3871,7 +4632,7 @@ func (m *ReverseProxyModule) createTenantAwareCatchAllHandler() http.HandlerFunc
 			}
 
 			// Check if we have a tenant-specific configuration
-			if tenantCfg, exists := m.tenants[tenantID]; exists && tenantCfg != nil {
+			if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 				// Check if tenant has a default backend (use it regardless of global default)
 				if tenantCfg.DefaultBackend != "" {
 					if m.app != nil && m.app.Logger() != nil {
@@ -3960,6 +4721,12 @@ func (m *ReverseProxyModule) setupFeatureFlagEvaluation(ctx context.Context) err
 	aggregator := NewFeatureFlagAggregator(m.app, logger)
 	m.featureFlagEvaluator = aggregator
 
+	// Memoize decisions to keep evaluation off the hot path when configured
+	if m.config.FeatureFlags.DecisionCacheTTL > 0 {
+		m.featureFlagEvaluator = NewCachingFeatureFlagEvaluator(aggregator, m.config.FeatureFlags.DecisionCacheTTL, m.metrics)
+		m.app.Logger().Info("Feature flag decision caching enabled", "ttl", m.config.FeatureFlags.DecisionCacheTTL.String())
+	}
+
 	if m.featureFlagEvaluatorProvided {
 		m.app.Logger().Info("Created feature flag aggregator with external evaluator and file-based fallback")
 	} else {
@@ -3977,7 +4744,7 @@ func (m *ReverseProxyModule) setupResponseCache() error {
 
 	// Check if caching is enabled for any tenant
 	if !cachingEnabled {
-		for _, tenantConfig := range m.tenants {
+		for _, tenantConfig := range m.tenantConfigs() {
 			if tenantConfig != nil && tenantConfig.CacheEnabled {
 				cachingEnabled = true
 				// Use the first tenant's TTL if global TTL is not set
@@ -4030,21 +4797,29 @@ func (m *ReverseProxyModule) withCache(handler http.HandlerFunc, backend string)
 			return
 		}
 
-		// Generate cache key
-		cacheKey := m.generateCacheKey(r, backend)
+		// Generate cache key, selecting the variant of responses with a Vary header
+		keyConfig := m.cacheKeyConfig(r, effectiveConfig)
+		baseKey := m.generateCacheKey(r, backend, keyConfig)
+		cacheKey := baseKey
+		if !keyConfig.IgnoreVary {
+			cacheKey = m.responseCache.variantKey(baseKey, r)
+		}
 
 		// Check for cached response
-		if cachedResp, found := m.responseCache.Get(cacheKey); found && cachedResp != nil {
-			// Serve from cache
-			copyResponseHeaders(cachedResp.Headers, w.Header())
-			w.Header().Set("X-Cache", "HIT")
-			w.WriteHeader(cachedResp.StatusCode)
-			if _, err := w.Write(cachedResp.Body); err != nil { //nolint:gosec // G705: reverse proxy transparently forwards upstream cached response body
-				if m.app != nil && m.app.Logger() != nil {
-					m.app.Logger().Error("Failed to write cached response body", "error", err)
-				}
+		cachedResp, freshness, found := m.responseCache.Lookup(cacheKey)
+		if found {
+			switch freshness {
+			case cacheFresh:
+				m.writeCachedResponse(w, cachedResp, "HIT")
+				return
+			case cacheStaleRevalidate:
+				// Serve stale immediately; one background request refreshes the entry
+				m.writeCachedResponse(w, cachedResp, "STALE")
+				m.revalidateCachedResponse(handler, r, backend, cacheKey, baseKey, keyConfig, effectiveConfig)
+				return
+			case cacheStaleIfError:
+				// Fetch below and fall back to the stale response if the backend fails
 			}
-			return
 		}
 
 		// Cache miss - capture response
@@ -4054,15 +4829,23 @@ func (m *ReverseProxyModule) withCache(handler http.HandlerFunc, backend string)
 			headers:        make(http.Header),
 			body:           make([]byte, 0),
 		}
+		if found {
+			// Keep backend headers off the client response until we know the stale
+			// response isn't served instead
+			recorder.ResponseWriter = nil
+		}
 
 		// Call original handler
 		handler(recorder, r)
 
-		// Cache successful GET responses
-		if recorder.statusCode == http.StatusOK && len(recorder.body) > 0 {
-			m.responseCache.Set(cacheKey, recorder.statusCode, recorder.headers, recorder.body, effectiveConfig.CacheTTL)
+		if found && recorder.statusCode >= http.StatusInternalServerError {
+			m.writeCachedResponse(w, cachedResp, "STALE-IF-ERROR")
+			return
 		}
 
+		// Cache successful GET responses
+		m.storeCachedResponse(r, baseKey, keyConfig, recorder, effectiveConfig)
+
 		// Send response to client
 		copyResponseHeaders(recorder.headers, w.Header())
 		w.Header().Set("X-Cache", "MISS")
@@ -4075,29 +4858,94 @@ func (m *ReverseProxyModule) withCache(handler http.HandlerFunc, backend string)
 	}
 }
 
+// writeCachedResponse serves a cached response, marking it with the given X-Cache value.
+func (m *ReverseProxyModule) writeCachedResponse(w http.ResponseWriter, cachedResp *CachedResponse, cacheStatus string) {
+	copyResponseHeaders(cachedResp.Headers, w.Header())
+	w.Header().Set("X-Cache", cacheStatus)
+	w.WriteHeader(cachedResp.StatusCode)
+	if _, err := w.Write(cachedResp.Body); err != nil { //nolint:gosec // G705: reverse proxy transparently forwards upstream cached response body
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Error("Failed to write cached response body", "error", err)
+		}
+	}
+}
+
+// storeCachedResponse caches a successful response with the stale windows of its route,
+// as the variant of baseKey selected by its Vary header unless the key ignores Vary.
+func (m *ReverseProxyModule) storeCachedResponse(r *http.Request, baseKey string, keyConfig *CacheKeyConfig, recorder *cacheResponseRecorder, config *ReverseProxyConfig) {
+	if recorder.statusCode != http.StatusOK || len(recorder.body) == 0 {
+		return
+	}
+	var vary []string
+	if !keyConfig.IgnoreVary {
+		var cacheable bool
+		if vary, cacheable = responseVary(recorder.headers); !cacheable {
+			return
+		}
+	}
+	cacheKey := varyKey(baseKey, vary, r)
+	staleWhileRevalidate, staleIfError := m.cacheStaleWindows(r, config)
+	m.responseCache.SetWithStale(cacheKey, recorder.statusCode, recorder.headers, recorder.body, config.CacheTTL, staleWhileRevalidate, staleIfError)
+	if !keyConfig.IgnoreVary {
+		m.responseCache.setVary(baseKey, vary, cacheKey)
+	}
+}
+
+// revalidateCachedResponse refreshes a stale cache entry in the background, unless a
+// refresh of the same entry is already in flight.
+func (m *ReverseProxyModule) revalidateCachedResponse(handler http.HandlerFunc, r *http.Request, backend, cacheKey, baseKey string, keyConfig *CacheKeyConfig, config *ReverseProxyConfig) {
+	if !m.responseCache.beginRevalidation(cacheKey) {
+		return
+	}
+	// The client already has its response, so the refresh must outlive the request
+	req := r.Clone(context.WithoutCancel(r.Context()))
+	go func() {
+		defer m.responseCache.endRevalidation(cacheKey)
+		recorder := &cacheResponseRecorder{
+			statusCode: http.StatusOK,
+			headers:    make(http.Header),
+		}
+		handler(recorder, req)
+		m.storeCachedResponse(req, baseKey, keyConfig, recorder, config)
+
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Revalidated stale cache entry", "backend", backend, "path", req.URL.Path, "status", recorder.statusCode)
+		}
+	}()
+}
+
+// cacheStaleWindows returns the stale-while-revalidate and stale-if-error windows for a
+// request: those of the most specific matching route that sets either, else the
+// global ones.
+func (m *ReverseProxyModule) cacheStaleWindows(r *http.Request, config *ReverseProxyConfig) (time.Duration, time.Duration) {
+	staleWhileRevalidate, staleIfError := config.CacheStaleWhileRevalidate, config.CacheStaleIfError
+	routeConfig, ok := m.routeConfigFor(r.URL.Path, config.RouteConfigs, func(route RouteConfig) bool {
+		return route.CacheStaleWhileRevalidate > 0 || route.CacheStaleIfError > 0
+	})
+	if !ok {
+		return staleWhileRevalidate, staleIfError
+	}
+	if routeConfig.CacheStaleWhileRevalidate > 0 {
+		staleWhileRevalidate = routeConfig.CacheStaleWhileRevalidate
+	}
+	if routeConfig.CacheStaleIfError > 0 {
+		staleIfError = routeConfig.CacheStaleIfError
+	}
+	return staleWhileRevalidate, staleIfError
+}
+
 // getEffectiveConfigForRequest returns the effective configuration for a request (considering tenant overrides)
 func (m *ReverseProxyModule) getEffectiveConfigForRequest(r *http.Request) *ReverseProxyConfig {
 	tenantIDStr, hasTenant := TenantIDFromRequest(m.config.TenantIDHeader, r)
 	if hasTenant {
 		tenantID := modular.TenantID(tenantIDStr)
-		if tenantCfg, exists := m.tenants[tenantID]; exists && tenantCfg != nil {
+		if tenantCfg := m.tenantConfig(tenantID); tenantCfg != nil {
 			return tenantCfg
 		}
 	}
 	return m.config
 }
 
-// generateCacheKey creates a unique cache key for the request
-func (m *ReverseProxyModule) generateCacheKey(r *http.Request, backend string) string {
-	// Include tenant ID in cache key for tenant isolation
-	tenantIDStr, _ := TenantIDFromRequest(m.config.TenantIDHeader, r)
-	key := fmt.Sprintf("%s:%s:%s:%s", backend, tenantIDStr, r.Method, r.URL.String())
-
-	// Hash the key to keep it manageable
-	hash := sha256.Sum256([]byte(key))
-	return hex.EncodeToString(hash[:])
-}
-
 // matchesRoute checks if a request path matches a route pattern
 func (m *ReverseProxyModule) matchesRoute(requestPath, routePattern string) bool {
 	// Handle exact matches
@@ -4124,13 +4972,15 @@ func (m *ReverseProxyModule) matchesRoute(requestPath, routePattern string) bool
 // cacheResponseRecorder captures response data for caching
 type cacheResponseRecorder struct {
 	http.ResponseWriter
-	statusCode int
-	headers    http.Header
-	body       []byte
+	statusCode  int
+	headers     http.Header
+	body        []byte
+	wroteHeader bool
 }
 
 func (r *cacheResponseRecorder) WriteHeader(statusCode int) {
 	r.statusCode = statusCode
+	r.wroteHeader = true
 	// Copy headers from the underlying response writer
 	for key, values := range r.Header() {
 		r.headers[key] = values
@@ -4138,12 +4988,20 @@ func (r *cacheResponseRecorder) WriteHeader(statusCode int) {
 }
 
 func (r *cacheResponseRecorder) Write(data []byte) (int, error) {
+	if !r.wroteHeader {
+		// Like net/http, a write without WriteHeader implies 200 OK with the headers set so far
+		r.WriteHeader(http.StatusOK)
+	}
 	// Capture the data for caching
 	r.body = append(r.body, data...)
 	return len(data), nil
 }
 
 func (r *cacheResponseRecorder) Header() http.Header {
+	if r.ResponseWriter == nil {
+		// Detached recorders keep the backend's headers to themselves
+		return r.headers
+	}
 	return r.ResponseWriter.Header()
 }
 
@@ -4475,6 +5333,10 @@ func (m *ReverseProxyModule) emitEvent(ctx context.Context, eventType string, da
 		return
 	}
 
+	if !m.eventSampler.sample(ctx, eventType, data) {
+		return
+	}
+
 	event := modular.NewCloudEvent(eventType, "reverseproxy-service", data, nil)
 
 	// Try to emit through the module's registered subject first
@@ -4504,6 +5366,7 @@ func (m *ReverseProxyModule) GetRegisteredEventTypes() []string {
 	return []string{
 		EventTypeConfigLoaded,
 		EventTypeConfigValidated,
+		EventTypeConfigReloaded,
 		EventTypeProxyCreated,
 		EventTypeProxyStarted,
 		EventTypeProxyStopped,
@@ -4511,13 +5374,41 @@ func (m *ReverseProxyModule) GetRegisteredEventTypes() []string {
 		EventTypeRequestProxied,
 		EventTypeRequestFailed,
 		EventTypeRequestProcessed,
+		EventTypeRequestTimeout,
+		EventTypeRequestClientAborted,
 		EventTypeDryRunComparison,
 		EventTypeBackendHealthy,
 		EventTypeBackendUnhealthy,
 		EventTypeBackendAdded,
 		EventTypeBackendRemoved,
+		EventTypeBackendDrained,
+		EventTypeMaintenanceEnabled,
+		EventTypeMaintenanceDisabled,
+		EventTypeTenantTLSFailed,
+		EventTypeTenantActivated,
+		EventTypeBackendPrewarmed,
+		EventTypeBackendPrewarmFailed,
+		EventTypeBackendReady,
+		EventTypeBackendNotReady,
+		EventTypeOpenAPIRefreshed,
+		EventTypeOpenAPIFetchFailed,
+		EventTypeSLOBudgetExhausted,
+		EventTypeSLOBudgetRecovered,
+		EventTypeBackendURLResolveFailed,
+		EventTypeBandwidthRejected,
+		EventTypeRateLimited,
+		EventTypeRateLimitStoreUnavailable,
+		EventTypeFaultInjected,
+		EventTypeRequestRetried,
+		EventTypeScheduledRouteActivated,
+		EventTypeScheduledRouteDeactivated,
 		EventTypeLoadBalanceDecision,
 		EventTypeLoadBalanceRoundRobin,
+		EventTypeLocalitySpillover,
+		EventTypeUploadFinished,
+		EventTypeResponseValidationFailed,
+		EventTypeConcurrencyLimitChanged,
+		EventTypeConcurrencyShed,
 		EventTypeCircuitBreakerOpen,
 		EventTypeCircuitBreakerClosed,
 		EventTypeCircuitBreakerHalfOpen,
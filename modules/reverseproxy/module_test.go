@@ -968,16 +968,22 @@ func (tr *testRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if tr == nil {
 		panic("testRouter is nil")
 	}
+	// Handlers run without the lock so they can register routes
+	if handler := tr.match(r.URL.Path); handler != nil {
+		handler(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// match returns the handler for path, or nil if no route matches.
+func (tr *testRouter) match(path string) http.HandlerFunc {
 	tr.mu.RLock()
 	defer tr.mu.RUnlock()
-	if tr.routes == nil {
-		tr.routes = make(map[string]http.HandlerFunc)
-	}
 
 	// Try exact path match first
-	if handler, ok := tr.routes[r.URL.Path]; ok {
-		handler(w, r)
-		return
+	if handler, ok := tr.routes[path]; ok {
+		return handler
 	}
 
 	// Try wildcard pattern matching - use deterministic order
@@ -993,21 +999,14 @@ func (tr *testRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	})
 
 	for _, pattern := range patterns {
-		handler := tr.routes[pattern]
 		prefix := strings.TrimSuffix(pattern, "/*")
-		if strings.HasPrefix(r.URL.Path, prefix+"/") || r.URL.Path == prefix {
-			handler(w, r)
-			return
+		if strings.HasPrefix(path, prefix+"/") || path == prefix {
+			return tr.routes[pattern]
 		}
 	}
 
 	// Try global wildcard
-	if handler, ok := tr.routes["/*"]; ok {
-		handler(w, r)
-		return
-	}
-
-	http.NotFound(w, r)
+	return tr.routes["/*"]
 }
 
 // TestTenantSpecificBackendProxiesCreated tests that tenant-specific backend proxies
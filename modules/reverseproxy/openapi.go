@@ -0,0 +1,482 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults applied to OpenAPIConfig fields left unset.
+const (
+	defaultOpenAPIEndpoint        = "/openapi.json"
+	defaultOpenAPISpecPath        = "/openapi.json"
+	defaultOpenAPIRefreshInterval = 5 * time.Minute
+	defaultOpenAPITitle           = "API Gateway"
+	defaultOpenAPIVersion         = "1.0.0"
+	defaultOpenAPIFetchTimeout    = 10 * time.Second
+	// maxOpenAPISpecSize bounds the spec read from a backend
+	maxOpenAPISpecSize = 10 << 20
+)
+
+// OpenAPIConfig serves one OpenAPI document for the whole gateway, merged from the
+// specs of the backends. Each backend's paths are prefixed with the path the proxy
+// serves them under, only paths routed to the backend are kept, and server URLs are
+// replaced by the proxy's.
+//
+//	openapi:
+//	  enabled: true
+//	  server_url: https://api.example.com
+//	  refresh_interval: 5m
+//	  backends:
+//	    users:
+//	      spec_path: /v3/api-docs
+//	    orders:
+//	      path_prefix: /orders
+type OpenAPIConfig struct {
+	// Enabled serves the aggregated document
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"OPENAPI_ENABLED"`
+
+	// Endpoint is the path serving the document. Default "/openapi.json".
+	Endpoint string `json:"endpoint" yaml:"endpoint" toml:"endpoint" env:"OPENAPI_ENDPOINT"`
+
+	// RefreshInterval is how often backend specs are fetched again. Default 5m.
+	RefreshInterval time.Duration `json:"refresh_interval" yaml:"refresh_interval" toml:"refresh_interval" env:"OPENAPI_REFRESH_INTERVAL"`
+
+	// Title and Version fill the document's info. Default "API Gateway" and "1.0.0".
+	Title   string `json:"title" yaml:"title" toml:"title" env:"OPENAPI_TITLE"`
+	Version string `json:"version" yaml:"version" toml:"version" env:"OPENAPI_VERSION"`
+
+	// ServerURL is the proxy's public URL, the only server of the document. Default "/".
+	ServerURL string `json:"server_url" yaml:"server_url" toml:"server_url" env:"OPENAPI_SERVER_URL"`
+
+	// Backends limits aggregation to these backends by ID and configures where their
+	// specs are. Empty aggregates every backend with a route, with defaults.
+	Backends map[string]OpenAPIBackendConfig `json:"backends" yaml:"backends" toml:"backends"`
+}
+
+// OpenAPIBackendConfig configures how a backend's spec is fetched and merged.
+type OpenAPIBackendConfig struct {
+	// SpecPath is requested on the backend for its JSON spec. Default "/openapi.json".
+	SpecPath string `json:"spec_path" yaml:"spec_path" toml:"spec_path"`
+
+	// PathPrefix is prepended to the backend's paths. Default the backend's
+	// path_rewriting.strip_base_path, which the proxy removes from requests.
+	PathPrefix string `json:"path_prefix" yaml:"path_prefix" toml:"path_prefix"`
+}
+
+// validate checks the endpoint, refresh interval and aggregated backends.
+func (c *OpenAPIConfig) validate(backends map[string]string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "/") {
+		return fmt.Errorf("%w: endpoint %q must start with /", ErrInvalidOpenAPIConfig, c.Endpoint)
+	}
+	if c.RefreshInterval < 0 {
+		return fmt.Errorf("%w: refresh_interval %s is negative", ErrInvalidOpenAPIConfig, c.RefreshInterval)
+	}
+	for backendID, backend := range c.Backends {
+		if _, ok := backends[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidOpenAPIConfig, backendID)
+		}
+		if backend.SpecPath != "" && !strings.HasPrefix(backend.SpecPath, "/") {
+			return fmt.Errorf("%w: backend %s: spec_path %q must start with /", ErrInvalidOpenAPIConfig, backendID, backend.SpecPath)
+		}
+		if backend.PathPrefix != "" && !strings.HasPrefix(backend.PathPrefix, "/") {
+			return fmt.Errorf("%w: backend %s: path_prefix %q must start with /", ErrInvalidOpenAPIConfig, backendID, backend.PathPrefix)
+		}
+	}
+	return nil
+}
+
+// endpoint returns the configured endpoint or the default.
+func (c *OpenAPIConfig) endpoint() string {
+	if c.Endpoint == "" {
+		return defaultOpenAPIEndpoint
+	}
+	return c.Endpoint
+}
+
+// openAPIAggregator holds the specs last fetched from each backend and the document
+// merged from them.
+type openAPIAggregator struct {
+	mu sync.RWMutex
+	// specs holds the last spec fetched from each backend, kept when a refresh fails
+	specs    map[string]map[string]interface{}
+	document []byte
+
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+// openAPIBackends returns the backends to aggregate, sorted by ID.
+func (m *ReverseProxyModule) openAPIBackends() []string {
+	var ids []string
+	if len(m.config.OpenAPI.Backends) > 0 {
+		for id := range m.config.OpenAPI.Backends {
+			ids = append(ids, id)
+		}
+	} else {
+		seen := make(map[string]bool)
+		for _, backendID := range m.config.Routes {
+			if _, ok := m.config.BackendServices[backendID]; ok && !seen[backendID] {
+				seen[backendID] = true
+				ids = append(ids, backendID)
+			}
+		}
+		if m.config.DefaultBackend != "" && !seen[m.config.DefaultBackend] {
+			ids = append(ids, m.config.DefaultBackend)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// openAPIPathPrefix returns the prefix of the paths the proxy serves backendID's
+// paths under.
+func (m *ReverseProxyModule) openAPIPathPrefix(backendID string) string {
+	if prefix := m.config.OpenAPI.Backends[backendID].PathPrefix; prefix != "" {
+		return strings.TrimSuffix(prefix, "/")
+	}
+	return strings.TrimSuffix(m.config.BackendConfigs[backendID].PathRewriting.StripBasePath, "/")
+}
+
+// routesOpenAPIPath reports whether a request for path reaches backendID through the
+// route mapping.
+func (m *ReverseProxyModule) routesOpenAPIPath(backendID, path string) bool {
+	// OpenAPI path templates like /users/{id} are matched as a concrete path
+	path = strings.NewReplacer("{", "", "}", "").Replace(path)
+	matched := false
+	for pattern, routeBackend := range m.config.Routes {
+		if m.matchesRoute(path, pattern) {
+			if routeBackend == backendID {
+				return true
+			}
+			matched = true
+		}
+	}
+	return !matched && backendID == m.config.DefaultBackend
+}
+
+// startOpenAPIAggregation fetches the backend specs and refreshes them every refresh
+// interval until stopOpenAPIAggregation.
+func (m *ReverseProxyModule) startOpenAPIAggregation(ctx context.Context) {
+	if !m.config.OpenAPI.Enabled {
+		return
+	}
+	m.openAPI = &openAPIAggregator{specs: make(map[string]map[string]interface{})}
+	interval := m.config.OpenAPI.RefreshInterval
+	if interval == 0 {
+		interval = defaultOpenAPIRefreshInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.openAPI.stop = cancel
+	m.openAPI.done = make(chan struct{})
+	go func() {
+		defer close(m.openAPI.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			m.RefreshOpenAPI(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stopOpenAPIAggregation stops refreshing backend specs.
+func (m *ReverseProxyModule) stopOpenAPIAggregation() {
+	if m.openAPI == nil || m.openAPI.stop == nil {
+		return
+	}
+	m.openAPI.stop()
+	<-m.openAPI.done
+	m.openAPI.stop = nil
+}
+
+// RefreshOpenAPI fetches the spec of every aggregated backend and rebuilds the
+// aggregated document. A backend whose spec can't be fetched keeps its last one. The
+// document is also refreshed every refresh_interval.
+func (m *ReverseProxyModule) RefreshOpenAPI(ctx context.Context) {
+	agg := m.openAPI
+	if agg == nil {
+		return
+	}
+	backends := m.openAPIBackends()
+	var failed []string
+	for _, backendID := range backends {
+		spec, err := m.fetchOpenAPISpec(ctx, backendID)
+		if err != nil {
+			failed = append(failed, backendID)
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Warn("Failed to fetch OpenAPI spec", "backend", backendID, "error", err)
+			}
+			m.emitEvent(ctx, EventTypeOpenAPIFetchFailed, map[string]interface{}{
+				"backend_id": backendID,
+				"error":      err.Error(),
+			})
+			continue
+		}
+		agg.mu.Lock()
+		agg.specs[backendID] = spec
+		agg.mu.Unlock()
+	}
+
+	agg.mu.Lock()
+	document, err := json.Marshal(m.mergeOpenAPISpecs(backends, agg.specs))
+	if err == nil {
+		agg.document = document
+	}
+	agg.mu.Unlock()
+	if err != nil {
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Error("Failed to encode aggregated OpenAPI document", "error", err)
+		}
+		return
+	}
+	m.emitEvent(ctx, EventTypeOpenAPIRefreshed, map[string]interface{}{
+		"backends": len(backends) - len(failed),
+		"failed":   failed,
+	})
+}
+
+// fetchOpenAPISpec requests the JSON spec of backendID.
+func (m *ReverseProxyModule) fetchOpenAPISpec(ctx context.Context, backendID string) (map[string]interface{}, error) {
+	specPath := m.config.OpenAPI.Backends[backendID].SpecPath
+	if specPath == "" {
+		specPath = defaultOpenAPISpecPath
+	}
+	timeout := m.config.RequestTimeout
+	if timeout <= 0 {
+		timeout = defaultOpenAPIFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	specURL := strings.TrimSuffix(m.config.BackendServices[backendID], "/") + specPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, specURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	client := m.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req) //nolint:gosec // G704: specs are fetched from configured backend URLs
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+	var spec map[string]interface{}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOpenAPISpecSize)).Decode(&spec); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidOpenAPISpec, err)
+	}
+	if _, ok := spec["paths"].(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("%w: no paths", ErrInvalidOpenAPISpec)
+	}
+	return spec, nil
+}
+
+// mergeOpenAPISpecs merges the specs of backends, in order, into one document. Paths
+// are prefixed and filtered by the route mapping, and a path served by two backends
+// is kept from the first. Components are merged by name; a component whose name is
+// taken by a different definition is renamed to "<backend>_<name>", along with its
+// references.
+func (m *ReverseProxyModule) mergeOpenAPISpecs(backends []string, specs map[string]map[string]interface{}) map[string]interface{} {
+	cfg := &m.config.OpenAPI
+	info := map[string]interface{}{"title": cfg.Title, "version": cfg.Version}
+	if cfg.Title == "" {
+		info["title"] = defaultOpenAPITitle
+	}
+	if cfg.Version == "" {
+		info["version"] = defaultOpenAPIVersion
+	}
+	serverURL := cfg.ServerURL
+	if serverURL == "" {
+		serverURL = "/"
+	}
+	document := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info":    info,
+		"servers": []interface{}{map[string]interface{}{"url": serverURL}},
+	}
+	paths := make(map[string]interface{})
+	components := make(map[string]map[string]interface{})
+	var tags []interface{}
+	seenTags := make(map[string]bool)
+
+	for i, backendID := range backends {
+		spec, ok := specs[backendID]
+		if !ok {
+			continue
+		}
+		if version, ok := spec["openapi"].(string); ok && i == 0 {
+			document["openapi"] = version
+		}
+
+		// Rename components clashing with ones already merged, then rewrite references
+		renames := make(map[string]string)
+		specComponents, _ := spec["components"].(map[string]interface{})
+		for kind, entries := range specComponents {
+			entries, ok := entries.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for name, definition := range entries {
+				if existing, ok := components[kind][name]; ok && !jsonEqual(existing, definition) {
+					renames["#/components/"+kind+"/"+name] = "#/components/" + kind + "/" + backendID + "_" + name
+				}
+			}
+		}
+		if len(renames) > 0 {
+			spec, _ = rewriteOpenAPIRefs(spec, renames).(map[string]interface{})
+			specComponents, _ = spec["components"].(map[string]interface{})
+		}
+		for kind, entries := range specComponents {
+			entries, ok := entries.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if components[kind] == nil {
+				components[kind] = make(map[string]interface{})
+			}
+			for name, definition := range entries {
+				if renamed, ok := renames["#/components/"+kind+"/"+name]; ok {
+					name = strings.TrimPrefix(renamed, "#/components/"+kind+"/")
+				}
+				components[kind][name] = definition
+			}
+		}
+
+		prefix := m.openAPIPathPrefix(backendID)
+		specPaths, _ := spec["paths"].(map[string]interface{})
+		for path, item := range specPaths {
+			proxyPath := prefix + path
+			if _, taken := paths[proxyPath]; taken || !m.routesOpenAPIPath(backendID, proxyPath) {
+				continue
+			}
+			if item, ok := item.(map[string]interface{}); ok {
+				paths[proxyPath] = withoutOpenAPIServers(item)
+			}
+		}
+
+		specTags, _ := spec["tags"].([]interface{})
+		for _, tag := range specTags {
+			if tag, ok := tag.(map[string]interface{}); ok {
+				if name, _ := tag["name"].(string); name != "" && !seenTags[name] {
+					seenTags[name] = true
+					tags = append(tags, tag)
+				}
+			}
+		}
+	}
+
+	document["paths"] = paths
+	if len(components) > 0 {
+		document["components"] = components
+	}
+	if len(tags) > 0 {
+		document["tags"] = tags
+	}
+	return document
+}
+
+// withoutOpenAPIServers returns a copy of a path item without the servers of the path
+// and its operations, which would bypass the proxy.
+func withoutOpenAPIServers(item map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(item))
+	for key, value := range item {
+		if key == "servers" {
+			continue
+		}
+		if operation, ok := value.(map[string]interface{}); ok {
+			if _, hasServers := operation["servers"]; hasServers {
+				copied := make(map[string]interface{}, len(operation))
+				for k, v := range operation {
+					if k != "servers" {
+						copied[k] = v
+					}
+				}
+				value = copied
+			}
+		}
+		result[key] = value
+	}
+	return result
+}
+
+// rewriteOpenAPIRefs returns a copy of node with the $ref values found in renames
+// replaced.
+func rewriteOpenAPIRefs(node interface{}, renames map[string]string) interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				if renamed, ok := renames[ref]; ok {
+					value = renamed
+				}
+			}
+			result[key] = rewriteOpenAPIRefs(value, renames)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			result[i] = rewriteOpenAPIRefs(value, renames)
+		}
+		return result
+	default:
+		return node
+	}
+}
+
+// jsonEqual reports whether a and b encode to the same JSON.
+func jsonEqual(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+// OpenAPIDocument returns the aggregated OpenAPI document as JSON, nil until specs
+// have been fetched or when aggregation is disabled.
+func (m *ReverseProxyModule) OpenAPIDocument() []byte {
+	if m.openAPI == nil {
+		return nil
+	}
+	m.openAPI.mu.RLock()
+	defer m.openAPI.mu.RUnlock()
+	return m.openAPI.document
+}
+
+// registerOpenAPIEndpoint serves the aggregated document at the configured endpoint.
+func (m *ReverseProxyModule) registerOpenAPIEndpoint() {
+	endpoint := m.config.OpenAPI.endpoint()
+	m.safeHandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		document := m.OpenAPIDocument()
+		if document == nil {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "OpenAPI document not available yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(document); err != nil && m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Error("Failed to write OpenAPI document", "error", err)
+		}
+	})
+	m.app.Logger().Info("Registered OpenAPI endpoint", "endpoint", endpoint)
+}
@@ -0,0 +1,184 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOpenAPITestBackend returns a backend serving spec at /openapi.json.
+func newOpenAPITestBackend(t *testing.T, spec *atomic.Value) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/openapi.json" {
+			http.NotFound(w, r)
+			return
+		}
+		body, _ := spec.Load().(string)
+		if body == "" {
+			http.Error(w, "no spec", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func specValue(spec string) *atomic.Value {
+	v := &atomic.Value{}
+	v.Store(spec)
+	return v
+}
+
+const usersSpec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Users", "version": "2.1.0"},
+	"servers": [{"url": "http://users.internal"}],
+	"tags": [{"name": "users"}],
+	"paths": {
+		"/users/{id}": {"get": {"tags": ["users"], "responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/User"}}}}}}},
+		"/internal/metrics": {"get": {"responses": {"200": {}}}}
+	},
+	"components": {"schemas": {
+		"User": {"type": "object", "properties": {"id": {"type": "integer"}, "error": {"$ref": "#/components/schemas/Error"}}},
+		"Error": {"type": "object", "properties": {"message": {"type": "string"}}}
+	}}
+}`
+
+const ordersSpec = `{
+	"openapi": "3.0.3",
+	"info": {"title": "Orders", "version": "1.0.0"},
+	"tags": [{"name": "orders"}, {"name": "users"}],
+	"paths": {
+		"/": {"get": {"servers": [{"url": "http://orders.internal"}], "responses": {"200": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}}}},
+		"/{id}": {"get": {"responses": {"200": {}}}}
+	},
+	"components": {"schemas": {
+		"Error": {"type": "object", "properties": {"code": {"type": "integer"}}}
+	}}
+}`
+
+func newOpenAPITestModule(t *testing.T, users, orders string) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+	cfg := &ReverseProxyConfig{
+		BackendServices: map[string]string{"users": users, "orders": orders},
+		Routes:          map[string]string{"/users/*": "users", "/orders/*": "orders", "/orders": "orders"},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"orders": {PathRewriting: PathRewritingConfig{StripBasePath: "/orders"}},
+		},
+		RequestTimeout: time.Second,
+		OpenAPI: OpenAPIConfig{
+			Enabled:   true,
+			ServerURL: "https://api.example.com",
+			Title:     "Example Gateway",
+		},
+	}
+
+	return newInitializedTestModule(t, cfg)
+}
+
+func TestOpenAPI_AggregatesBackendSpecs(t *testing.T) {
+	users := newOpenAPITestBackend(t, specValue(usersSpec))
+	orders := newOpenAPITestBackend(t, specValue(ordersSpec))
+	m, router, subject := newOpenAPITestModule(t, users.URL, orders.URL)
+	require.NoError(t, m.Start(context.Background()))
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool { return m.OpenAPIDocument() != nil }, 2*time.Second, 10*time.Millisecond)
+	rec := serveTestRoute(router, "/openapi.json", "/openapi.json")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &document))
+	assert.Equal(t, map[string]interface{}{"title": "Example Gateway", "version": "1.0.0"}, document["info"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"url": "https://api.example.com"}}, document["servers"])
+
+	paths := document["paths"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"/users/{id}", "/orders/", "/orders/{id}"}, keysOf(paths),
+		"paths are prefixed, and those not routed to their backend are dropped")
+	ordersList := paths["/orders/"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.NotContains(t, ordersList, "servers")
+
+	// Backends merge in order, so the clashing Error schema of users is renamed along
+	// with its references
+	schemas := document["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	assert.ElementsMatch(t, []string{"Error", "User", "users_Error"}, keysOf(schemas))
+	userError := schemas["User"].(map[string]interface{})["properties"].(map[string]interface{})["error"]
+	assert.Equal(t, map[string]interface{}{"$ref": "#/components/schemas/users_Error"}, userError)
+	assert.Contains(t, schemas["users_Error"].(map[string]interface{})["properties"], "message")
+
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "orders"}, map[string]interface{}{"name": "users"}}, document["tags"])
+	assert.NotEmpty(t, subject.eventsOfType(EventTypeOpenAPIRefreshed))
+}
+
+func TestOpenAPI_KeepsLastSpecWhenFetchFails(t *testing.T) {
+	usersSpecValue := specValue(usersSpec)
+	users := newOpenAPITestBackend(t, usersSpecValue)
+	orders := newOpenAPITestBackend(t, specValue(""))
+	m, router, subject := newOpenAPITestModule(t, users.URL, orders.URL)
+
+	// Before the first refresh the document isn't available
+	m.registerOpenAPIEndpoint()
+	m.openAPI = &openAPIAggregator{specs: make(map[string]map[string]interface{})}
+	assert.Equal(t, http.StatusServiceUnavailable, serveTestRoute(router, "/openapi.json", "/openapi.json").Code)
+
+	ctx := context.Background()
+	m.RefreshOpenAPI(ctx)
+	failures := subject.eventsOfType(EventTypeOpenAPIFetchFailed)
+	require.Len(t, failures, 1)
+	var data map[string]interface{}
+	require.NoError(t, failures[0].DataAs(&data))
+	assert.Equal(t, "orders", data["backend_id"])
+
+	usersSpecValue.Store("")
+	m.RefreshOpenAPI(ctx)
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(m.OpenAPIDocument(), &document))
+	assert.Equal(t, []string{"/users/{id}"}, keysOf(document["paths"].(map[string]interface{})))
+}
+
+func TestOpenAPIConfig_Validate(t *testing.T) {
+	backends := map[string]string{"api": "http://api"}
+	tests := []struct {
+		name   string
+		config OpenAPIConfig
+		valid  bool
+	}{
+		{"disabled", OpenAPIConfig{Endpoint: "openapi"}, true},
+		{"defaults", OpenAPIConfig{Enabled: true}, true},
+		{"configured", OpenAPIConfig{Enabled: true, Backends: map[string]OpenAPIBackendConfig{"api": {SpecPath: "/v3/api-docs", PathPrefix: "/api"}}}, true},
+		{"relative endpoint", OpenAPIConfig{Enabled: true, Endpoint: "openapi.json"}, false},
+		{"negative interval", OpenAPIConfig{Enabled: true, RefreshInterval: -time.Second}, false},
+		{"unknown backend", OpenAPIConfig{Enabled: true, Backends: map[string]OpenAPIBackendConfig{"db": {}}}, false},
+		{"relative spec path", OpenAPIConfig{Enabled: true, Backends: map[string]OpenAPIBackendConfig{"api": {SpecPath: "openapi.json"}}}, false},
+		{"relative prefix", OpenAPIConfig{Enabled: true, Backends: map[string]OpenAPIBackendConfig{"api": {PathPrefix: "api"}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(backends)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidOpenAPIConfig)
+			}
+		})
+	}
+}
+
+func keysOf(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,330 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Defaults applied to PrewarmConfig fields left unset.
+const (
+	defaultPrewarmConnections = 2
+	defaultPrewarmPath        = "/"
+	defaultPrewarmTimeout     = 5 * time.Second
+)
+
+// PrewarmConfig configures opening idle connections to backends during Start, so the
+// first requests after a deploy don't pay for dialing and TLS handshakes. Each
+// connection is opened by a concurrent HEAD request whose connection is returned to
+// the backend transport's idle pool.
+//
+//	prewarm:
+//	  enabled: true
+//	  connections: 4
+//	  path: /healthz
+//	  timeout: 5s
+type PrewarmConfig struct {
+	// Enabled turns on connection pre-warming at startup
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"PREWARM_ENABLED"`
+
+	// Connections is the number of connections opened per backend. Default 2. The
+	// transport only keeps as many idle connections per host as its
+	// MaxIdleConnsPerHost allows.
+	Connections int `json:"connections" yaml:"connections" toml:"connections" env:"PREWARM_CONNECTIONS"`
+
+	// Path is requested on each backend to open a connection. Default "/".
+	Path string `json:"path" yaml:"path" toml:"path" env:"PREWARM_PATH"`
+
+	// Timeout bounds pre-warming of each backend. Default 5s.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" toml:"timeout" env:"PREWARM_TIMEOUT"`
+
+	// Backends limits pre-warming to these backend IDs. Empty warms every backend.
+	Backends []string `json:"backends" yaml:"backends" toml:"backends" env:"PREWARM_BACKENDS"`
+
+	// Required fails Start when no connection could be opened to a pre-warmed backend
+	Required bool `json:"required" yaml:"required" toml:"required" env:"PREWARM_REQUIRED"`
+}
+
+// validate checks the connection count, timeout and path.
+func (c *PrewarmConfig) validate() error {
+	if c.Connections < 0 {
+		return fmt.Errorf("%w: connections %d is negative", ErrInvalidPrewarmConfig, c.Connections)
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("%w: timeout %s is negative", ErrInvalidPrewarmConfig, c.Timeout)
+	}
+	if c.Path != "" && !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("%w: path %q must start with /", ErrInvalidPrewarmConfig, c.Path)
+	}
+	return nil
+}
+
+// prewarmResult reports how pre-warming went for one backend.
+type prewarmResult struct {
+	BackendID string
+	// Opened is the number of requests that completed, each leaving a connection
+	// in the idle pool.
+	Opened int
+	// Failed is the number of requests that could not complete
+	Failed   int
+	Duration time.Duration
+	// Err is the first error encountered, if any
+	Err error
+}
+
+// prewarmBackends opens idle connections to the configured backends and emits a
+// backend.prewarmed or backend.prewarm_failed event per backend. With Required set it
+// returns an error naming the backends that got no connection.
+func (m *ReverseProxyModule) prewarmBackends(ctx context.Context) error {
+	if m.config == nil || !m.config.Prewarm.Enabled {
+		return nil
+	}
+	cfg := m.config.Prewarm
+
+	backendIDs := cfg.Backends
+	if len(backendIDs) == 0 {
+		for backendID := range m.config.BackendServices {
+			backendIDs = append(backendIDs, backendID)
+		}
+		sort.Strings(backendIDs)
+	}
+
+	results := make([]prewarmResult, len(backendIDs))
+	var wg sync.WaitGroup
+	for i, backendID := range backendIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = m.prewarmBackend(ctx, backendID)
+		}()
+	}
+	wg.Wait()
+
+	var failed []string
+	opened := 0
+	for _, result := range results {
+		opened += result.Opened
+		data := map[string]interface{}{
+			"backend_id":  result.BackendID,
+			"opened":      result.Opened,
+			"failed":      result.Failed,
+			"duration_ms": result.Duration.Milliseconds(),
+		}
+		if result.Opened == 0 {
+			failed = append(failed, result.BackendID)
+			if result.Err != nil {
+				data["error"] = result.Err.Error()
+			}
+			m.emitEvent(ctx, EventTypeBackendPrewarmFailed, data)
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Warn("Failed to pre-warm backend connections", "backend", result.BackendID, "error", result.Err)
+			}
+			continue
+		}
+		m.emitEvent(ctx, EventTypeBackendPrewarmed, data)
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Pre-warmed backend connections", "backend", result.BackendID,
+				"opened", result.Opened, "failed", result.Failed, "duration", result.Duration)
+		}
+	}
+
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Info("Backend connection pre-warming complete", "backends", len(backendIDs),
+			"connections", opened, "failed_backends", len(failed))
+	}
+
+	if cfg.Required && len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrBackendPrewarmFailed, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// prewarmBackend sends concurrent HEAD requests through the backend's proxy transport
+// and drains their responses so each connection goes back to the idle pool.
+func (m *ReverseProxyModule) prewarmBackend(ctx context.Context, backendID string) prewarmResult {
+	cfg := m.config.Prewarm
+	result := prewarmResult{BackendID: backendID}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	target, transport, err := m.prewarmTarget(backendID)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	connections := cfg.Connections
+	if connections == 0 {
+		connections = defaultPrewarmConnections
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = defaultPrewarmTimeout
+	}
+	path := cfg.Path
+	if path == "" {
+		path = defaultPrewarmPath
+	}
+	target.Path = singleJoiningSlash(target.Path, path)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var opened, failed atomic.Int32
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	for range connections {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := prewarmConnection(ctx, transport, target.String()); err != nil {
+				failed.Add(1)
+				errOnce.Do(func() { firstErr = err })
+				return
+			}
+			opened.Add(1)
+		}()
+	}
+	wg.Wait()
+
+	result.Opened = int(opened.Load())
+	result.Failed = int(failed.Load())
+	result.Err = firstErr
+	return result
+}
+
+// prewarmTarget returns the URL and transport the backend's proxy sends requests to.
+func (m *ReverseProxyModule) prewarmTarget(backendID string) (*url.URL, http.RoundTripper, error) {
+	backendURL, ok := m.config.BackendServices[backendID]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBackendNotFound, backendID)
+	}
+	m.backendProxiesMutex.RLock()
+	proxy := m.backendProxies[backendID]
+	m.backendProxiesMutex.RUnlock()
+	if proxy == nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBackendProxyNil, backendID)
+	}
+
+	target, err := url.Parse(backendURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse backend URL %s: %w", backendURL, err)
+	}
+	upstream, _, err := m.resolveUpstream(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	resolved := *upstream
+	return &resolved, m.requestTransport(proxy.Transport, m.defaultRequestTimeout()), nil
+}
+
+// defaultRequestTimeout returns the timeout backend handlers use for routes without
+// their own timeout.
+func (m *ReverseProxyModule) defaultRequestTimeout() time.Duration {
+	switch {
+	case m.config.GlobalTimeout > 0:
+		return m.config.GlobalTimeout
+	case m.config.RequestTimeout > 0:
+		return m.config.RequestTimeout
+	default:
+		return 30 * time.Second
+	}
+}
+
+// requestTransportKey identifies a transport derived by requestTransport.
+type requestTransportKey struct {
+	base    *http.Transport
+	timeout time.Duration
+}
+
+// requestTransport returns the transport backend handlers use for a request with the
+// given timeout through a proxy using base. Transports for unix, h2c, TLS-error and
+// custom round trippers, such as a wrapped HTTP client transport, are used as they
+// are. An *http.Transport is cloned, keeping its proxy, dial, TLS and connection pool
+// settings, and gets the timeout as its response header timeout; without a base,
+// http.DefaultTransport is cloned. Derived transports are cached so that requests
+// reuse each other's idle connections, including those opened by pre-warming.
+func (m *ReverseProxyModule) requestTransport(base http.RoundTripper, timeout time.Duration) http.RoundTripper {
+	httpBase, isHTTPTransport := base.(*http.Transport)
+	if base != nil && !isHTTPTransport {
+		return base
+	}
+
+	key := requestTransportKey{base: httpBase, timeout: timeout}
+	if transport, ok := m.requestTransports.Load(key); ok {
+		return transport.(*http.Transport)
+	}
+
+	var transport *http.Transport
+	if httpBase != nil {
+		transport = httpBase.Clone()
+	} else {
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+		transport.MaxIdleConnsPerHost = 10
+	}
+	if transport.DialContext == nil && transport.DialTLSContext == nil {
+		transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	transport.ResponseHeaderTimeout = timeout
+	// Hold request bodies back until the backend accepts "Expect: 100-continue"
+	if transport.ExpectContinueTimeout == 0 {
+		transport.ExpectContinueTimeout = 1 * time.Second
+	}
+	actual, _ := m.requestTransports.LoadOrStore(key, transport)
+	return actual.(*http.Transport)
+}
+
+// closeRequestTransports closes idle connections held by transports derived by requestTransport.
+func (m *ReverseProxyModule) closeRequestTransports() {
+	m.requestTransports.Range(func(_, transport any) bool {
+		transport.(*http.Transport).CloseIdleConnections()
+		return true
+	})
+}
+
+// evictRequestTransports drops the transports requestTransport derived from base and
+// closes their idle connections, once no proxy uses base any more.
+func (m *ReverseProxyModule) evictRequestTransports(base http.RoundTripper) {
+	httpBase, ok := base.(*http.Transport)
+	if !ok {
+		return
+	}
+	m.requestTransports.Range(func(key, transport any) bool {
+		if key.(requestTransportKey).base == httpBase {
+			m.requestTransports.Delete(key)
+			transport.(*http.Transport).CloseIdleConnections()
+		}
+		return true
+	})
+}
+
+// prewarmConnection sends one HEAD request and drains the response. Any response,
+// whatever its status, means a connection was established.
+func prewarmConnection(ctx context.Context, transport http.RoundTripper, target string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create pre-warm request: %w", err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("pre-warm request timed out: %w", err)
+		}
+		return fmt.Errorf("pre-warm request failed: %w", err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if err := resp.Body.Close(); err != nil {
+		return fmt.Errorf("failed to close pre-warm response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package reverseproxy
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPrewarmTestModule creates an initialized module with an "api" backend at backendURL.
+func newPrewarmTestModule(t *testing.T, backendURL string, prewarm PrewarmConfig) (*ReverseProxyModule, *capturingSubject) {
+	t.Helper()
+
+	return newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backendURL},
+		RequestTimeout:  5 * time.Second,
+		Prewarm:         prewarm,
+	})
+}
+
+func TestPrewarm_OpensConnections(t *testing.T) {
+	var prewarmRequests, conns atomic.Int32
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && r.URL.Path == "/healthz" {
+			prewarmRequests.Add(1)
+		}
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	backend.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	backend.Start()
+	t.Cleanup(backend.Close)
+
+	m, subject := newPrewarmTestModule(t, backend.URL, PrewarmConfig{Enabled: true, Connections: 3, Path: "/healthz"})
+	require.NoError(t, m.prewarmBackends(context.Background()))
+
+	assert.Equal(t, int32(3), prewarmRequests.Load())
+	assert.Equal(t, int32(3), conns.Load())
+
+	events := subject.eventsOfType(EventTypeBackendPrewarmed)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "api", data["backend_id"])
+	assert.InDelta(t, 3, data["opened"], 0)
+
+	// A proxied request reuses a warm connection instead of dialing
+	rec := httptest.NewRecorder()
+	m.createBackendProxyHandler("api")(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(3), conns.Load())
+}
+
+func TestPrewarm_UnreachableBackend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	backendURL := "http://" + listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	m, subject := newPrewarmTestModule(t, backendURL, PrewarmConfig{Enabled: true, Timeout: time.Second})
+	require.NoError(t, m.prewarmBackends(context.Background()))
+	assert.Len(t, subject.eventsOfType(EventTypeBackendPrewarmFailed), 1)
+	assert.Empty(t, subject.eventsOfType(EventTypeBackendPrewarmed))
+
+	m.config.Prewarm.Required = true
+	err = m.prewarmBackends(context.Background())
+	require.ErrorIs(t, err, ErrBackendPrewarmFailed)
+	assert.Contains(t, err.Error(), "api")
+}
+
+func TestPrewarm_Disabled(t *testing.T) {
+	var requests atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newPrewarmTestModule(t, backend.URL, PrewarmConfig{})
+	require.NoError(t, m.prewarmBackends(context.Background()))
+	assert.Zero(t, requests.Load())
+	assert.Empty(t, subject.eventsOfType(EventTypeBackendPrewarmed))
+}
+
+func TestPrewarmConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config PrewarmConfig
+	}{
+		{"negative connections", PrewarmConfig{Connections: -1}},
+		{"negative timeout", PrewarmConfig{Timeout: -time.Second}},
+		{"relative path", PrewarmConfig{Path: "healthz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.config.validate(), ErrInvalidPrewarmConfig)
+		})
+	}
+	valid := PrewarmConfig{Enabled: true, Connections: 4, Path: "/healthz", Timeout: time.Second}
+	assert.NoError(t, valid.validate())
+}
+
+func TestRequestTransport_ClonesBaseTransport(t *testing.T) {
+	proxyURL, err := url.Parse("http://proxy.internal:3128")
+	require.NoError(t, err)
+	base := &http.Transport{
+		Proxy:               http.ProxyURL(proxyURL),
+		MaxIdleConnsPerHost: 42,
+	}
+
+	m := NewModule()
+	m.config = &ReverseProxyConfig{CircuitBreakerConfig: CircuitBreakerConfig{Enabled: true}}
+
+	transport, ok := m.requestTransport(base, 3*time.Second).(*http.Transport)
+	require.True(t, ok)
+	assert.NotSame(t, base, transport)
+	require.NotNil(t, transport.Proxy, "the base transport's proxy is kept")
+	got, err := transport.Proxy(httptest.NewRequest(http.MethodGet, "http://backend.internal/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, proxyURL, got)
+	assert.Equal(t, 42, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 3*time.Second, transport.ResponseHeaderTimeout)
+	assert.Same(t, transport, m.requestTransport(base, 3*time.Second), "derived transports are reused")
+}
+
+func TestRequestTransport_KeepsWrappedTransport(t *testing.T) {
+	wrapped := &testTransport{handler: http.NotFoundHandler()}
+
+	m := NewModule()
+	m.config = &ReverseProxyConfig{}
+
+	transport := m.requestTransport(wrapped, time.Second)
+	assert.Same(t, wrapped, transport, "a wrapped transport is not replaced")
+}
+
+func TestRequestTransport_EvictedWithTheBackend(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{BackendServices: map[string]string{
+		"api":   newNamedBackend(t, "api").URL,
+		"users": newNamedBackend(t, "users").URL,
+	}}
+	require.NoError(t, m.createBackendProxy("api", m.config.BackendServices["api"]))
+	require.NoError(t, m.createBackendProxy("users", m.config.BackendServices["users"]))
+	m.requestTransport(m.backendProxies["api"].Transport, time.Second)
+	m.requestTransport(m.backendProxies["api"].Transport, 2*time.Second)
+	kept := m.requestTransport(m.backendProxies["users"].Transport, time.Second)
+
+	require.NoError(t, m.RemoveBackend("api"))
+
+	var remaining []any
+	m.requestTransports.Range(func(_, transport any) bool {
+		remaining = append(remaining, transport)
+		return true
+	})
+	assert.Equal(t, []any{kept}, remaining, "only the removed backend's request transports are dropped")
+}
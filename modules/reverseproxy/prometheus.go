@@ -0,0 +1,81 @@
+package reverseproxy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format.
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// wantsPrometheus reports whether a metrics request asked for the Prometheus text
+// format, either with ?format=prometheus or an Accept header preferring text.
+func wantsPrometheus(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "prometheus"
+	}
+	accept := r.Header.Get("Accept")
+	return strings.Contains(accept, "text/plain") || strings.Contains(accept, "application/openmetrics-text")
+}
+
+// prometheusWriter writes metrics in the Prometheus text exposition format. Every
+// feature exposing metrics writes its families to the same writer, which buffers
+// them until flush.
+type prometheusWriter struct {
+	out *bufio.Writer
+}
+
+func newPrometheusWriter(w io.Writer) *prometheusWriter {
+	return &prometheusWriter{out: bufio.NewWriter(w)}
+}
+
+// family starts a metric family of the given kind: counter, gauge or histogram.
+func (p *prometheusWriter) family(name, kind string) {
+	fmt.Fprintf(p.out, "# TYPE %s %s\n", name, kind)
+}
+
+// sample writes one sample of the named metric. Labels are given as alternating
+// names and values; values are escaped.
+func (p *prometheusWriter) sample(name string, value float64, labels ...string) {
+	p.out.WriteString(name)
+	if len(labels) > 0 {
+		p.out.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				p.out.WriteByte(',')
+			}
+			fmt.Fprintf(p.out, "%s=\"%s\"", labels[i], prometheusLabelReplacer.Replace(labels[i+1]))
+		}
+		p.out.WriteByte('}')
+	}
+	p.out.WriteByte(' ')
+	p.out.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+	p.out.WriteByte('\n')
+}
+
+// histogram writes the buckets, sum and count of h for the labels.
+func (p *prometheusWriter) histogram(name string, h *histogram, labels ...string) {
+	labels = labels[:len(labels):len(labels)] // bucket labels must not share its array
+	for i, count := range h.cumulative() {
+		p.sample(name+"_bucket", float64(count), append(labels, "le", formatBound(h.bounds[i]))...)
+	}
+	p.sample(name+"_bucket", float64(h.count), append(labels, "le", "+Inf")...)
+	p.sample(name+"_sum", h.sum, labels...)
+	p.sample(name+"_count", float64(h.count), labels...)
+}
+
+// flush writes the buffered metrics.
+func (p *prometheusWriter) flush() error {
+	if err := p.out.Flush(); err != nil {
+		return fmt.Errorf("failed to write metrics: %w", err)
+	}
+	return nil
+}
+
+// prometheusLabelReplacer escapes the characters the exposition format requires
+// escaped in label values.
+var prometheusLabelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
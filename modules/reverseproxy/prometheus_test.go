@@ -0,0 +1,55 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWantsPrometheus(t *testing.T) {
+	tests := []struct {
+		url    string
+		accept string
+		want   bool
+	}{
+		{"/metrics", "", false},
+		{"/metrics", "application/json", false},
+		{"/metrics", "text/plain;version=0.0.4", true},
+		{"/metrics?format=prometheus", "", true},
+		{"/metrics?format=json", "text/plain", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		req.Header.Set("Accept", tt.accept)
+		assert.Equal(t, tt.want, wantsPrometheus(req), "%s with Accept %q", tt.url, tt.accept)
+	}
+}
+
+func TestPrometheusWriter(t *testing.T) {
+	var out bytes.Buffer
+	p := newPrometheusWriter(&out)
+	p.family("requests_total", "counter")
+	p.sample("requests_total", 1234567, "route", "/a\"b\\c\nd", "method", "GET")
+	p.sample("requests_total", 0.25)
+	h := newHistogram([]float64{0.1, 1})
+	h.observe(0.05)
+	h.observe(5)
+	p.family("latency_seconds", "histogram")
+	p.histogram("latency_seconds", h, "route", "/a")
+	require.NoError(t, p.flush())
+
+	assert.Equal(t, `# TYPE requests_total counter
+requests_total{route="/a\"b\\c\nd",method="GET"} 1234567
+requests_total 0.25
+# TYPE latency_seconds histogram
+latency_seconds_bucket{route="/a",le="0.1"} 1
+latency_seconds_bucket{route="/a",le="1"} 1
+latency_seconds_bucket{route="/a",le="+Inf"} 2
+latency_seconds_sum{route="/a"} 5.05
+latency_seconds_count{route="/a"} 2
+`, out.String())
+}
@@ -0,0 +1,75 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+)
+
+// ProxyTarget identifies what a proxy handler forwards a request to. Exactly one of
+// its fields is set.
+type ProxyTarget struct {
+	// Backend is the ID of the backend the request is proxied to
+	Backend string
+
+	// CompositeRoute is the pattern of the composite route serving the request
+	CompositeRoute string
+}
+
+// proxyTargetKey is the context key under which the ProxyTarget of a request is stored.
+type proxyTargetKey struct{}
+
+// ProxyTargetFromContext returns the target chosen for a request, for use by proxy
+// middleware. It reports false outside proxy middleware and proxy handlers.
+func ProxyTargetFromContext(ctx context.Context) (ProxyTarget, bool) {
+	target, ok := ctx.Value(proxyTargetKey{}).(ProxyTarget)
+	return target, ok
+}
+
+// UseProxyMiddleware adds middleware applied around every backend and composite proxy
+// handler the module generates, once the request has been routed and before it is
+// sent upstream. Unlike router middleware it only sees proxied requests and can look
+// up their target with ProxyTargetFromContext. Middleware runs in the order added,
+// the first being the outermost, and runs once per request even when a handler
+// delegates to another, such as a composite route falling back to a backend. Each
+// middleware is called once to wrap the handlers, so it must be called before Start.
+func (m *ReverseProxyModule) UseProxyMiddleware(middleware func(next http.Handler) http.Handler) {
+	m.proxyMiddleware = append(m.proxyMiddleware, middleware)
+}
+
+// proxyHandlerKey is the context key under which the proxy handler the proxy
+// middleware chain ends in is stored.
+type proxyHandlerKey struct{}
+
+// proxyChain returns the middleware added with UseProxyMiddleware around the proxy
+// handler stored in the request context. The middleware are called once, when the
+// first proxy handler is created, so every handler shares their instances.
+func (m *ReverseProxyModule) proxyChain() http.Handler {
+	m.proxyChainOnce.Do(func() {
+		var chain http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Context().Value(proxyHandlerKey{}).(http.HandlerFunc)(w, r)
+		})
+		for i := len(m.proxyMiddleware) - 1; i >= 0; i-- {
+			chain = m.proxyMiddleware[i](chain)
+		}
+		m.proxyChainHandler = chain
+	})
+	return m.proxyChainHandler
+}
+
+// withProxyMiddleware wraps handler in the middleware added with UseProxyMiddleware
+// and records target in the request context.
+func (m *ReverseProxyModule) withProxyMiddleware(target ProxyTarget, handler http.HandlerFunc) http.HandlerFunc {
+	if len(m.proxyMiddleware) == 0 || handler == nil {
+		return handler
+	}
+
+	chain := m.proxyChain()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, applied := ProxyTargetFromContext(r.Context()); applied {
+			handler(w, r)
+			return
+		}
+		ctx := context.WithValue(r.Context(), proxyTargetKey{}, target)
+		chain.ServeHTTP(w, r.WithContext(context.WithValue(ctx, proxyHandlerKey{}, handler)))
+	}
+}
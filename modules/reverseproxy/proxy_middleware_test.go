@@ -0,0 +1,166 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordTargetMiddleware stores the ProxyTarget seen by the middleware in target.
+func recordTargetMiddleware(target *ProxyTarget) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*target, _ = ProxyTargetFromContext(r.Context())
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func TestProxyMiddleware_WrapsBackendHandler(t *testing.T) {
+	var upstreamCalls atomic.Int32
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamCalls.Add(1)
+		w.Header().Set("X-Upstream-User", r.Header.Get("X-User"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  5 * time.Second,
+	})
+
+	var target ProxyTarget
+	m.UseProxyMiddleware(tracingMiddleware("outer"))
+	m.UseProxyMiddleware(recordTargetMiddleware(&target))
+	m.UseProxyMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			r.Header.Set("X-User", "alice")
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.UseProxyMiddleware(tracingMiddleware("inner"))
+	handler := m.createBackendProxyHandler("api")
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Zero(t, upstreamCalls.Load(), "rejected requests never reach the backend")
+	assert.Equal(t, ProxyTarget{Backend: "api"}, target)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	rec = httptest.NewRecorder()
+	handler(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(1), upstreamCalls.Load())
+	assert.Equal(t, "alice", rec.Header().Get("X-Upstream-User"))
+	assert.Equal(t, []string{"outer", "inner"}, rec.Header().Values("X-Middleware"))
+}
+
+func TestProxyMiddleware_WrapsCompositeRoutes(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(backend.Close)
+
+	m := NewModule()
+	m.app = NewMockTenantApplication()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"users": backend.URL, "orders": backend.URL},
+		CompositeRoutes: map[string]CompositeRoute{
+			"/api/summary": {Pattern: "/api/summary", Backends: []string{"users", "orders"}, Strategy: "merge"},
+		},
+		RequestTimeout: 5 * time.Second,
+	}
+	m.httpClient = &http.Client{Timeout: time.Second}
+	m.router = &testRouter{routes: make(map[string]http.HandlerFunc)}
+
+	var calls atomic.Int32
+	var target ProxyTarget
+	m.UseProxyMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.UseProxyMiddleware(recordTargetMiddleware(&target))
+	require.NoError(t, m.setupCompositeRoutes(context.Background()))
+
+	rec := httptest.NewRecorder()
+	m.compositeRoutes["/api/summary"](rec, httptest.NewRequest(http.MethodGet, "/api/summary", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, ProxyTarget{CompositeRoute: "/api/summary"}, target)
+}
+
+func TestProxyMiddleware_NoneRegistered(t *testing.T) {
+	m := NewModule()
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, ok := ProxyTargetFromContext(r.Context())
+		assert.False(t, ok)
+		w.WriteHeader(http.StatusNoContent)
+	}
+	rec := httptest.NewRecorder()
+	m.withProxyMiddleware(ProxyTarget{Backend: "api"}, handler)(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestProxyMiddleware_NestedHandlersRunOnce(t *testing.T) {
+	m := NewModule()
+	var calls atomic.Int32
+	var target ProxyTarget
+	m.UseProxyMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			next.ServeHTTP(w, r)
+		})
+	})
+	m.UseProxyMiddleware(recordTargetMiddleware(&target))
+
+	// A composite route falling back to a backend handler
+	fallback := m.withProxyMiddleware(ProxyTarget{Backend: "legacy"}, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	composite := m.withProxyMiddleware(ProxyTarget{CompositeRoute: "/api/summary"}, fallback)
+
+	rec := httptest.NewRecorder()
+	composite(rec, httptest.NewRequest(http.MethodGet, "/api/summary", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, ProxyTarget{CompositeRoute: "/api/summary"}, target)
+}
+
+func TestProxyMiddleware_FactoriesCalledOnce(t *testing.T) {
+	m := NewModule()
+	var built atomic.Int32
+	var target ProxyTarget
+	m.UseProxyMiddleware(func(next http.Handler) http.Handler {
+		built.Add(1)
+		return next
+	})
+	m.UseProxyMiddleware(recordTargetMiddleware(&target))
+
+	// Handlers created per request, as tenant and catch-all routing do, share the chain
+	for _, backend := range []string{"api", "legacy", "api"} {
+		handler := m.withProxyMiddleware(ProxyTarget{Backend: backend}, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Served-By", backend)
+		})
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, backend, rec.Header().Get("X-Served-By"))
+		assert.Equal(t, ProxyTarget{Backend: backend}, target)
+	}
+	assert.Equal(t, int32(1), built.Load())
+}
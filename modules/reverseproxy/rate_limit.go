@@ -0,0 +1,606 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Rate limit defaults.
+const (
+	defaultRateLimitKeyPrefix     = "reverseproxy:ratelimit"
+	defaultRateLimitWindow        = time.Second
+	defaultRateLimitStoreTimeout  = 50 * time.Millisecond
+	defaultRateLimitFallbackRetry = 5 * time.Second
+)
+
+// RateLimitStore is a counter store shared by proxy replicas to coordinate
+// distributed rate limits. The cache module's service implements it; with the Redis
+// engine every replica using the same Redis sees the same counters.
+type RateLimitStore interface {
+	// Increment atomically adds delta to the counter at key and returns its new
+	// value. A missing counter starts from zero and expires after ttl.
+	Increment(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
+
+// RateLimitConfig limits the requests each tenant may make per window. Limits use a
+// sliding window: a request is admitted while the requests of the current window, plus
+// the previous window's requests weighted by how much of that window is still within
+// the last window's duration, stay within the limit. Requests without a tenant ID are
+// not limited, and requests with a tenant ID neither listed in Tenants nor registered
+// with the application share the Default counters recorded as "other".
+//
+// Distributed limits are counted in a RateLimitStore shared by all replicas, by
+// default the cache module's service. While the store is unavailable each replica
+// enforces the limit's fallback_requests on its own.
+//
+//	rate_limit:
+//	  enabled: true
+//	  default:
+//	    requests: 100
+//	    window: 1s
+//	  tenants:
+//	    partner-api:
+//	      requests: 6000
+//	      window: 1m
+//	      distributed: true
+//	      sync_batch: 10
+//	      fallback_requests: 2000
+type RateLimitConfig struct {
+	// Enabled turns on request rate limiting
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"RATE_LIMIT_ENABLED"`
+
+	// RejectStatus is the status of rejected requests, 429 (default) or 503
+	RejectStatus int `json:"reject_status" yaml:"reject_status" toml:"reject_status" env:"RATE_LIMIT_REJECT_STATUS"`
+
+	// KeyPrefix prefixes the store keys of distributed limits. Defaults to
+	// "reverseproxy:ratelimit"; proxies sharing a store but not their limits need
+	// different prefixes.
+	KeyPrefix string `json:"key_prefix" yaml:"key_prefix" toml:"key_prefix" env:"RATE_LIMIT_KEY_PREFIX"`
+
+	// StoreTimeout bounds each call to the store. A slower store is treated as
+	// unavailable. Defaults to 50ms.
+	StoreTimeout time.Duration `json:"store_timeout" yaml:"store_timeout" toml:"store_timeout" env:"RATE_LIMIT_STORE_TIMEOUT"`
+
+	// FallbackRetry is how long distributed limits are enforced locally after the
+	// store fails, before it is tried again. Defaults to 5s.
+	FallbackRetry time.Duration `json:"fallback_retry" yaml:"fallback_retry" toml:"fallback_retry" env:"RATE_LIMIT_FALLBACK_RETRY"`
+
+	// Default is the limit of tenants not listed in Tenants. Zero requests leave them unlimited.
+	Default RateLimit `json:"default" yaml:"default" toml:"default"`
+
+	// Tenants maps tenant IDs to their limits, replacing Default
+	Tenants map[string]RateLimit `json:"tenants" yaml:"tenants" toml:"tenants"`
+}
+
+// RateLimit is a tenant's request limit. Zero requests leave the tenant unlimited.
+type RateLimit struct {
+	// Requests is the number of requests admitted per window
+	Requests int64 `json:"requests" yaml:"requests" toml:"requests"`
+
+	// Window is the duration Requests applies to. Defaults to 1s.
+	Window time.Duration `json:"window" yaml:"window" toml:"window"`
+
+	// Distributed counts the tenant's requests in the shared RateLimitStore, so the
+	// limit applies to all replicas together. Without a store the limit is enforced
+	// by each replica on its own.
+	Distributed bool `json:"distributed" yaml:"distributed" toml:"distributed"`
+
+	// SyncBatch is how many requests a replica admits on its own knowledge of the
+	// shared count before adding them to the store. 1 (default) consults the store
+	// for every request; larger batches save store round trips but let replicas
+	// together overshoot the limit by up to a batch each.
+	SyncBatch int64 `json:"sync_batch" yaml:"sync_batch" toml:"sync_batch"`
+
+	// FallbackRequests is the limit each replica enforces on its own while the store
+	// is unavailable. Defaults to Requests; set it to Requests divided by the number
+	// of replicas to keep the overall limit during store outages.
+	FallbackRequests int64 `json:"fallback_requests" yaml:"fallback_requests" toml:"fallback_requests"`
+}
+
+// validate checks the reject status, durations and every limit.
+func (c *RateLimitConfig) validate() error {
+	switch c.RejectStatus {
+	case 0, http.StatusTooManyRequests, http.StatusServiceUnavailable:
+	default:
+		return fmt.Errorf("%w: reject_status %d must be 429 or 503", ErrInvalidRateLimitConfig, c.RejectStatus)
+	}
+	if c.StoreTimeout < 0 {
+		return fmt.Errorf("%w: store_timeout %s is negative", ErrInvalidRateLimitConfig, c.StoreTimeout)
+	}
+	if c.FallbackRetry < 0 {
+		return fmt.Errorf("%w: fallback_retry %s is negative", ErrInvalidRateLimitConfig, c.FallbackRetry)
+	}
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for tenantID, limit := range c.Tenants {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("tenant %s: %w", tenantID, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the limit's values are not negative.
+func (l RateLimit) validate() error {
+	for name, value := range map[string]int64{
+		"requests":          l.Requests,
+		"window":            int64(l.Window),
+		"sync_batch":        l.SyncBatch,
+		"fallback_requests": l.FallbackRequests,
+	} {
+		if value < 0 {
+			return fmt.Errorf("%w: %s %d is negative", ErrInvalidRateLimitConfig, name, value)
+		}
+	}
+	return nil
+}
+
+// withDefaults returns the limit with its defaults filled in.
+func (l RateLimit) withDefaults() RateLimit {
+	if l.Window <= 0 {
+		l.Window = defaultRateLimitWindow
+	}
+	if l.SyncBatch <= 0 {
+		l.SyncBatch = 1
+	}
+	if l.FallbackRequests <= 0 {
+		l.FallbackRequests = l.Requests
+	}
+	return l
+}
+
+// TenantRateLimitStatus reports how many of a tenant's requests were admitted and
+// rejected, and how many were decided locally because the store was unavailable.
+type TenantRateLimitStatus struct {
+	Tenant   string `json:"tenant"`
+	Allowed  uint64 `json:"allowed"`
+	Rejected uint64 `json:"rejected"`
+	Fallback uint64 `json:"fallback"`
+}
+
+// slidingWindow counts requests in consecutive fixed windows.
+type slidingWindow struct {
+	index    int64 // number of the current window since the Unix epoch
+	current  int64
+	previous int64
+}
+
+// advance moves the counts to the window containing now.
+func (w *slidingWindow) advance(now time.Time, window time.Duration) {
+	index := now.UnixNano() / int64(window)
+	switch {
+	case index == w.index+1:
+		w.previous, w.current = w.current, 0
+	case index > w.index+1:
+		w.previous, w.current = 0, 0
+	default:
+		return
+	}
+	w.index = index
+}
+
+// windowElapsed returns the fraction of the fixed window containing now that has passed.
+func windowElapsed(now time.Time, window time.Duration) float64 {
+	return float64(now.UnixNano()%int64(window)) / float64(window)
+}
+
+// slidingCount estimates the requests within the last window's duration.
+func slidingCount(previous, current int64, elapsed float64) float64 {
+	return float64(previous)*(1-elapsed) + float64(current)
+}
+
+// slidingRetryAfter returns how long until one more request fits within limit, given
+// the counts of the previous and current window.
+func slidingRetryAfter(previous, current, limit int64, elapsed float64, window time.Duration) time.Duration {
+	if current < limit {
+		if previous <= 0 {
+			return 0
+		}
+		// The request fits once enough of the previous window has slid out
+		needed := 1 - float64(limit-current-1)/float64(previous)
+		return time.Duration(math.Max(0, needed-elapsed) * float64(window))
+	}
+	// The current window is full; in the next one it becomes the previous window
+	wait := (1 - elapsed) * float64(window)
+	if current > 0 {
+		wait += math.Max(0, 1-float64(limit-1)/float64(current)) * float64(window)
+	}
+	return time.Duration(wait)
+}
+
+// tenantRateLimit holds a tenant's counts and counters.
+type tenantRateLimit struct {
+	tenant string
+	limit  RateLimit
+
+	mu sync.Mutex
+	// local counts the requests this replica admitted, for local limits and as the
+	// starting point of the fallback
+	local slidingWindow
+	// shared holds the counts last read from the store, for distributed limits
+	shared slidingWindow
+	// pending counts requests admitted in shared's current window not yet added to
+	// the store, and carried those of its previous window
+	pending        int64
+	carried        int64
+	previousSynced bool
+
+	allowed  uint64
+	rejected uint64
+	fallback uint64
+}
+
+// advanceShared moves the shared counts to the window index, carrying requests not
+// yet added to the store over to the previous window.
+func (t *tenantRateLimit) advanceShared(index int64) {
+	if index == t.shared.index {
+		return
+	}
+	if index == t.shared.index+1 {
+		t.shared.previous = t.shared.current + t.pending
+		t.carried = t.pending
+	} else {
+		t.shared.previous = 0
+		t.carried = 0
+	}
+	t.shared.index = index
+	t.shared.current = 0
+	t.pending = 0
+	t.previousSynced = false
+}
+
+func (t *tenantRateLimit) status() TenantRateLimitStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return TenantRateLimitStatus{Tenant: t.tenant, Allowed: t.allowed, Rejected: t.rejected, Fallback: t.fallback}
+}
+
+// rateLimiter holds the counts of every tenant seen.
+type rateLimiter struct {
+	config        RateLimitConfig
+	status        int
+	keyPrefix     string
+	storeTimeout  time.Duration
+	fallbackRetry time.Duration
+	now           func() time.Time
+	known         func(tenantID string) bool // reports tenants registered with the application
+
+	mu        sync.Mutex
+	store     RateLimitStore
+	storeDown time.Time // the store is not used before this time
+	tenants   map[string]*tenantRateLimit
+
+	// onStoreFailure is called when the store fails and limits fall back to local
+	// enforcement
+	onStoreFailure func(ctx context.Context, err error)
+}
+
+// newRateLimiter returns a limiter for the configured limits counting distributed
+// limits in store, or nil when rate limiting is disabled. Known reports the tenants
+// registered with the application, which are counted individually through the
+// default limit.
+func newRateLimiter(config RateLimitConfig, store RateLimitStore, known func(tenantID string) bool) *rateLimiter {
+	if !config.Enabled {
+		return nil
+	}
+	l := &rateLimiter{
+		config:        config,
+		status:        config.RejectStatus,
+		keyPrefix:     config.KeyPrefix,
+		storeTimeout:  config.StoreTimeout,
+		fallbackRetry: config.FallbackRetry,
+		now:           time.Now,
+		known:         known,
+		store:         store,
+		tenants:       make(map[string]*tenantRateLimit),
+	}
+	if l.status == 0 {
+		l.status = http.StatusTooManyRequests
+	}
+	if l.keyPrefix == "" {
+		l.keyPrefix = defaultRateLimitKeyPrefix
+	}
+	if l.storeTimeout == 0 {
+		l.storeTimeout = defaultRateLimitStoreTimeout
+	}
+	if l.fallbackRetry == 0 {
+		l.fallbackRetry = defaultRateLimitFallbackRetry
+	}
+	return l
+}
+
+// hasDistributedLimits reports whether any configured limit is distributed.
+func (l *rateLimiter) hasDistributedLimits() bool {
+	if l.config.Default.Distributed && l.config.Default.Requests > 0 {
+		return true
+	}
+	for _, limit := range l.config.Tenants {
+		if limit.Distributed && limit.Requests > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// tenant returns the counts of tenantID, creating them on first use, or nil when the
+// tenant is not limited.
+func (l *rateLimiter) tenant(tenantID string) *tenantRateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if t, ok := l.tenants[tenantID]; ok {
+		return t
+	}
+	limit, listed := l.config.Tenants[tenantID]
+	if !listed {
+		limit = l.config.Default
+		if !l.known(tenantID) {
+			tenantID = otherTenantsLabel
+			if t, ok := l.tenants[tenantID]; ok {
+				return t
+			}
+		}
+	}
+	if limit.Requests <= 0 {
+		return nil
+	}
+	t := &tenantRateLimit{tenant: tenantID, limit: limit.withDefaults()}
+	l.tenants[tenantID] = t
+	return t
+}
+
+// sharedStore returns the store to count distributed limits in, or nil while it is
+// unavailable.
+func (l *rateLimiter) sharedStore(now time.Time) RateLimitStore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Before(l.storeDown) {
+		return nil
+	}
+	return l.store
+}
+
+// storeFailed switches distributed limits to local enforcement for fallbackRetry.
+func (l *rateLimiter) storeFailed(ctx context.Context, now time.Time, err error) {
+	l.mu.Lock()
+	alreadyDown := now.Before(l.storeDown)
+	l.storeDown = now.Add(l.fallbackRetry)
+	l.mu.Unlock()
+	if !alreadyDown && l.onStoreFailure != nil {
+		l.onStoreFailure(ctx, err)
+	}
+}
+
+// allow decides whether a request of tenant t is admitted, returning how long the
+// client should wait before retrying when it is not.
+func (l *rateLimiter) allow(ctx context.Context, t *tenantRateLimit) (bool, time.Duration) {
+	now := l.now()
+	if t.limit.Distributed {
+		if store := l.sharedStore(now); store != nil {
+			allowed, wait, err := l.allowShared(ctx, store, t, now)
+			if err == nil {
+				t.mu.Lock()
+				t.local.advance(now, t.limit.Window)
+				if allowed {
+					t.local.current++
+					t.allowed++
+				} else {
+					t.rejected++
+				}
+				t.mu.Unlock()
+				return allowed, wait
+			}
+			l.storeFailed(ctx, now, err)
+		}
+	}
+
+	limit := t.limit.Requests
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.limit.Distributed {
+		limit = t.limit.FallbackRequests
+		t.fallback++
+	}
+	t.local.advance(now, t.limit.Window)
+	elapsed := windowElapsed(now, t.limit.Window)
+	if slidingCount(t.local.previous, t.local.current+1, elapsed) > float64(limit) {
+		t.rejected++
+		return false, slidingRetryAfter(t.local.previous, t.local.current, limit, elapsed, t.limit.Window)
+	}
+	t.local.current++
+	t.allowed++
+	return true, 0
+}
+
+// allowShared decides on a request of a distributed limit from the counts in store,
+// consulting the store once every SyncBatch requests.
+func (l *rateLimiter) allowShared(ctx context.Context, store RateLimitStore, t *tenantRateLimit, now time.Time) (bool, time.Duration, error) {
+	window := t.limit.Window
+	limit := t.limit.Requests
+	index := now.UnixNano() / int64(window)
+	elapsed := windowElapsed(now, window)
+
+	t.mu.Lock()
+	t.advanceShared(index)
+	current := t.shared.current + t.pending
+	if slidingCount(t.shared.previous, current+1, elapsed) > float64(limit) {
+		// The counts known here only grow until the next sync, so the request is over
+		// the limit without asking the store
+		previous := t.shared.previous
+		t.mu.Unlock()
+		return false, slidingRetryAfter(previous, current, limit, elapsed, window), nil
+	}
+	t.pending++
+	if t.pending < t.limit.SyncBatch && t.previousSynced {
+		t.mu.Unlock()
+		return true, 0, nil
+	}
+	delta, carried, syncPrevious := t.pending, t.carried, !t.previousSynced
+	t.pending, t.carried, t.previousSynced = 0, 0, true
+	t.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, l.storeTimeout)
+	defer cancel()
+	ttl := 2 * window
+	previous := int64(-1)
+	if syncPrevious {
+		count, err := store.Increment(ctx, l.key(t, index-1), carried, ttl)
+		if err != nil {
+			l.resync(t, index)
+			return false, 0, fmt.Errorf("failed to read previous rate limit window: %w", err)
+		}
+		previous = count
+	}
+	count, err := store.Increment(ctx, l.key(t, index), delta, ttl)
+	if err != nil {
+		l.resync(t, index)
+		return false, 0, fmt.Errorf("failed to count rate limited request: %w", err)
+	}
+
+	t.mu.Lock()
+	if t.shared.index == index {
+		t.shared.previous = max(t.shared.previous, previous)
+		t.shared.current = max(t.shared.current, count)
+	}
+	previous = t.shared.previous
+	t.mu.Unlock()
+	if slidingCount(previous, count, elapsed) <= float64(limit) {
+		return true, 0, nil
+	}
+
+	// Over the limit: take the rejected request back out of the shared count
+	if undone, err := store.Increment(ctx, l.key(t, index), -1, ttl); err == nil {
+		t.mu.Lock()
+		if t.shared.index == index && t.shared.current == count {
+			t.shared.current = undone
+		}
+		t.mu.Unlock()
+	}
+	return false, slidingRetryAfter(previous, count-1, limit, elapsed, window), nil
+}
+
+// resync makes the next request of t in window index read the previous window again
+// after a failed sync.
+func (l *rateLimiter) resync(t *tenantRateLimit, index int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shared.index == index {
+		t.previousSynced = false
+	}
+}
+
+// key returns the store key counting t's requests in window index.
+func (l *rateLimiter) key(t *tenantRateLimit, index int64) string {
+	return fmt.Sprintf("%s:%s:%s:%d", l.keyPrefix, t.tenant, t.limit.Window, index)
+}
+
+// forget stops counting a tenant removed from the application.
+func (l *rateLimiter) forget(tenantID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.tenants, tenantID)
+}
+
+// statuses returns the counters of every tracked tenant, sorted by tenant ID.
+func (l *rateLimiter) statuses() []TenantRateLimitStatus {
+	l.mu.Lock()
+	tenants := make(map[string]*tenantRateLimit, len(l.tenants))
+	for tenantID, t := range l.tenants {
+		tenants[tenantID] = t
+	}
+	l.mu.Unlock()
+
+	statuses := make([]TenantRateLimitStatus, 0, len(tenants))
+	for _, tenantID := range sortedKeys(tenants) {
+		statuses = append(statuses, tenants[tenantID].status())
+	}
+	return statuses
+}
+
+// SetRateLimitStore sets the store distributed rate limits are counted in, replacing
+// the cache module service found at construction. It must be called before Start.
+func (m *ReverseProxyModule) SetRateLimitStore(store RateLimitStore) {
+	m.rateLimitStore = store
+	if m.rateLimit != nil {
+		m.rateLimit.mu.Lock()
+		m.rateLimit.store = store
+		m.rateLimit.mu.Unlock()
+	}
+}
+
+// RateLimitStatus returns the rate limit counters of every limited tenant seen, or
+// nil when rate limiting is disabled.
+func (m *ReverseProxyModule) RateLimitStatus() []TenantRateLimitStatus {
+	if m.rateLimit == nil {
+		return nil
+	}
+	return m.rateLimit.statuses()
+}
+
+// setupRateLimit creates the rate limiter, reporting store failures through the
+// module's logger and events.
+func (m *ReverseProxyModule) setupRateLimit() {
+	m.rateLimit = newRateLimiter(m.config.RateLimit, m.rateLimitStore, m.isRegisteredTenant)
+	if m.rateLimit == nil {
+		return
+	}
+	if m.rateLimitStore == nil && m.rateLimit.hasDistributedLimits() && m.app != nil {
+		m.app.Logger().Warn("No rate limit store available, distributed rate limits are enforced by each replica on its own")
+	}
+	m.rateLimit.onStoreFailure = func(ctx context.Context, err error) {
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Warn("Rate limit store unavailable, enforcing distributed limits locally",
+				"error", err, "retry_in", m.rateLimit.fallbackRetry)
+		}
+		m.emitEvent(ctx, EventTypeRateLimitStoreUnavailable, map[string]interface{}{
+			"error":    err.Error(),
+			"retry_in": m.rateLimit.fallbackRetry.String(),
+		})
+	}
+}
+
+// withRateLimit rejects requests of tenants over their request rate limit.
+func (m *ReverseProxyModule) withRateLimit(handler http.HandlerFunc) http.HandlerFunc {
+	limiter := m.rateLimit
+	if limiter == nil || handler == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, ok := m.requestTenant(r)
+		if !ok {
+			handler(w, r)
+			return
+		}
+		tenant := limiter.tenant(tenantID)
+		if tenant == nil {
+			handler(w, r)
+			return
+		}
+		if allowed, wait := limiter.allow(r.Context(), tenant); !allowed {
+			m.rejectRateLimit(w, r, limiter, tenant, wait)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// rejectRateLimit answers a request from a tenant over its rate limit.
+func (m *ReverseProxyModule) rejectRateLimit(w http.ResponseWriter, r *http.Request, limiter *rateLimiter, tenant *tenantRateLimit, wait time.Duration) {
+	retryAfter := max(1, int(math.Ceil(wait.Seconds())))
+	if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Debug("Rejected request over tenant rate limit", "tenant", tenant.tenant, "path", r.URL.Path, "retry_after", retryAfter)
+	}
+	m.emitEvent(r.Context(), EventTypeRateLimited, map[string]interface{}{
+		"tenant":      tenant.tenant,
+		"path":        r.URL.Path,
+		"status":      limiter.status,
+		"retry_after": retryAfter,
+	})
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, "tenant rate limit exceeded", limiter.status)
+}
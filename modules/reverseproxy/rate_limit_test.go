@@ -0,0 +1,239 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRateLimitStore is a RateLimitStore shared by test replicas.
+type memoryRateLimitStore struct {
+	mu     sync.Mutex
+	counts map[string]int64
+	calls  int
+	err    error
+}
+
+func (s *memoryRateLimitStore) Increment(_ context.Context, key string, delta int64, _ time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return 0, s.err
+	}
+	if s.counts == nil {
+		s.counts = make(map[string]int64)
+	}
+	s.counts[key] += delta
+	return s.counts[key], nil
+}
+
+func (s *memoryRateLimitStore) setErr(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+func (s *memoryRateLimitStore) callCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+// newTestRateLimitModule returns a module limiting with config and store on clock.
+func newTestRateLimitModule(t *testing.T, config RateLimitConfig, store RateLimitStore, clock *sloTestClock) (*ReverseProxyModule, *capturingSubject, http.HandlerFunc) {
+	t.Helper()
+	config.Enabled = true
+	m, subject := newTestModule(t, &ReverseProxyConfig{TenantIDHeader: "X-Tenant-ID", RateLimit: config})
+	require.NoError(t, m.config.RateLimit.validate())
+	if store != nil {
+		m.SetRateLimitStore(store)
+	}
+	m.setupRateLimit()
+	m.rateLimit.now = clock.Now
+	// Tenant IDs not registered with the application share the default counters
+	m.OnTenantRegistered("acme")
+	handler := m.withRateLimit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return m, subject, handler
+}
+
+func newRateLimitTestClock() *sloTestClock {
+	return &sloTestClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func rateLimitedStatus(handler http.HandlerFunc, tenantID string) (int, string) {
+	rec := httptest.NewRecorder()
+	handler(rec, tenantRequest(http.MethodGet, tenantID, nil))
+	return rec.Code, rec.Header().Get("Retry-After")
+}
+
+func TestSlidingRetryAfter(t *testing.T) {
+	assert.Equal(t, 1500*time.Millisecond, slidingRetryAfter(0, 2, 2, 0, time.Second),
+		"a full window waits for the next one and for half of it to slide out")
+	assert.Equal(t, 250*time.Millisecond, slidingRetryAfter(4, 2, 4, 0.5, time.Second),
+		"the request fits once three quarters of the previous window slid out")
+	assert.Zero(t, slidingRetryAfter(0, 1, 4, 0.5, time.Second))
+	assert.InDelta(t, 3.5, slidingCount(5, 1, 0.5), 0.0001)
+}
+
+func TestRateLimit_LocalLimitPerTenant(t *testing.T) {
+	clock := newRateLimitTestClock()
+	m, subject, handler := newTestRateLimitModule(t, RateLimitConfig{
+		Default: RateLimit{Requests: 2},
+		Tenants: map[string]RateLimit{"partner": {Requests: 5, Window: time.Minute}},
+	}, nil, clock)
+
+	for range 2 {
+		status, _ := rateLimitedStatus(handler, "acme")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	status, retryAfter := rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, "2", retryAfter)
+
+	events := subject.eventsOfType(EventTypeRateLimited)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "acme", data["tenant"])
+
+	for range 5 {
+		status, _ = rateLimitedStatus(handler, "partner")
+		assert.Equal(t, http.StatusOK, status, "listed tenants have their own limit")
+	}
+	status, _ = rateLimitedStatus(handler, "")
+	assert.Equal(t, http.StatusOK, status, "requests without a tenant are not limited")
+
+	// Half a second into the next window, half of the previous window still counts
+	clock.Advance(1500 * time.Millisecond)
+	status, _ = rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	statuses := m.RateLimitStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, TenantRateLimitStatus{Tenant: "acme", Allowed: 3, Rejected: 2}, statuses[0])
+	assert.Equal(t, TenantRateLimitStatus{Tenant: "partner", Allowed: 5}, statuses[1])
+}
+
+func TestRateLimit_UnknownTenantsShareTheDefaultLimit(t *testing.T) {
+	m, _, handler := newTestRateLimitModule(t, RateLimitConfig{
+		Default: RateLimit{Requests: 2},
+	}, nil, newRateLimitTestClock())
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		status, _ := rateLimitedStatus(handler, fmt.Sprintf("made-up-%d", i))
+		assert.Equal(t, want, status)
+	}
+	status, _ := rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusOK, status, "registered tenants have their own counters")
+
+	statuses := m.RateLimitStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, TenantRateLimitStatus{Tenant: otherTenantsLabel, Allowed: 2, Rejected: 1}, statuses[1])
+}
+
+func TestRateLimit_DistributedAcrossReplicas(t *testing.T) {
+	clock := newRateLimitTestClock()
+	store := &memoryRateLimitStore{}
+	config := RateLimitConfig{Default: RateLimit{Requests: 4, Window: time.Minute, Distributed: true}}
+	_, _, replicaA := newTestRateLimitModule(t, config, store, clock)
+	_, _, replicaB := newTestRateLimitModule(t, config, store, clock)
+
+	for _, replica := range []http.HandlerFunc{replicaA, replicaB, replicaA, replicaB} {
+		status, _ := rateLimitedStatus(replica, "acme")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	status, _ := rateLimitedStatus(replicaA, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status, "the replicas share the limit")
+	status, _ = rateLimitedStatus(replicaB, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+
+	key := "reverseproxy:ratelimit:acme:1m0s:29454480"
+	assert.Equal(t, int64(4), store.counts[key], "rejected requests are not counted")
+
+	// In the next window the replicas learn the previous window's count from the store
+	clock.Advance(90 * time.Second)
+	status, _ = rateLimitedStatus(replicaB, "acme")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = rateLimitedStatus(replicaA, "acme")
+	assert.Equal(t, http.StatusOK, status)
+	status, _ = rateLimitedStatus(replicaB, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+}
+
+func TestRateLimit_SyncBatchSavesStoreCalls(t *testing.T) {
+	clock := newRateLimitTestClock()
+	store := &memoryRateLimitStore{}
+	m, _, handler := newTestRateLimitModule(t, RateLimitConfig{
+		Default: RateLimit{Requests: 10, Distributed: true, SyncBatch: 3},
+	}, store, clock)
+
+	for range 6 {
+		status, _ := rateLimitedStatus(handler, "acme")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	// The first request reads the previous window and counts itself, the fourth
+	// adds the batch of three
+	assert.Equal(t, 3, store.callCount())
+	assert.Equal(t, int64(4), store.counts["reverseproxy:ratelimit:acme:1s:1767268800"])
+
+	for range 4 {
+		status, _ := rateLimitedStatus(handler, "acme")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	status, _ := rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status)
+	assert.Equal(t, uint64(10), m.RateLimitStatus()[0].Allowed)
+}
+
+func TestRateLimit_FallsBackToLocalLimit(t *testing.T) {
+	clock := newRateLimitTestClock()
+	store := &memoryRateLimitStore{err: errors.New("connection refused")}
+	m, subject, handler := newTestRateLimitModule(t, RateLimitConfig{
+		FallbackRetry: 10 * time.Second,
+		Default:       RateLimit{Requests: 10, Window: time.Minute, Distributed: true, FallbackRequests: 2},
+	}, store, clock)
+
+	for range 2 {
+		status, _ := rateLimitedStatus(handler, "acme")
+		assert.Equal(t, http.StatusOK, status)
+	}
+	status, _ := rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusTooManyRequests, status, "the fallback limit applies per replica")
+	assert.Equal(t, 1, store.callCount(), "the store is not retried before fallback_retry")
+	require.Len(t, subject.eventsOfType(EventTypeRateLimitStoreUnavailable), 1)
+	assert.Equal(t, uint64(3), m.RateLimitStatus()[0].Fallback)
+
+	store.setErr(nil)
+	clock.Advance(11 * time.Second)
+	status, _ = rateLimitedStatus(handler, "acme")
+	assert.Equal(t, http.StatusOK, status, "the shared limit applies again once the store recovers")
+	assert.Equal(t, uint64(3), m.RateLimitStatus()[0].Fallback)
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	valid := RateLimitConfig{Default: RateLimit{Requests: 10, Window: time.Second}}
+	require.NoError(t, valid.validate())
+
+	for name, config := range map[string]RateLimitConfig{
+		"reject status":  {RejectStatus: http.StatusForbidden},
+		"store timeout":  {StoreTimeout: -time.Second},
+		"negative limit": {Default: RateLimit{Requests: -1}},
+		"tenant window":  {Tenants: map[string]RateLimit{"acme": {Requests: 1, Window: -time.Second}}},
+		"sync batch":     {Default: RateLimit{Requests: 1, SyncBatch: -1}},
+	} {
+		assert.ErrorIs(t, config.validate(), ErrInvalidRateLimitConfig, name)
+	}
+}
@@ -0,0 +1,313 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultReadinessInterval is how often gated backends are probed when the readiness
+// config doesn't set an interval.
+const defaultReadinessInterval = time.Second
+
+// ReadinessConfig makes the proxy report ready only once critical backends are
+// healthy, so a deploy where one of them is down fails its readiness probe instead of
+// serving 502s. Backends are probed at their health check endpoint, as configured in
+// health_check, until they first answer with an expected status code.
+//
+//	readiness:
+//	  endpoint: /ready
+//	  backends:
+//	    payments:
+//	      wait_timeout: 30s
+//	      fail_start: true
+//	    search:
+//	      gate_routes: true
+type ReadinessConfig struct {
+	// Endpoint is the path serving the readiness status, 200 once every gated backend
+	// is ready and 503 before. Empty doesn't register one.
+	Endpoint string `json:"endpoint" yaml:"endpoint" toml:"endpoint" env:"READINESS_ENDPOINT"`
+
+	// Interval between probes of a backend that isn't ready, also bounding each probe.
+	// Default 1s.
+	Interval time.Duration `json:"interval" yaml:"interval" toml:"interval" env:"READINESS_INTERVAL"`
+
+	// Backends gates readiness on these backends by ID
+	Backends map[string]BackendReadinessConfig `json:"backends" yaml:"backends" toml:"backends"`
+}
+
+// BackendReadinessConfig configures how a backend gates the proxy's readiness.
+type BackendReadinessConfig struct {
+	// WaitTimeout is how long Start waits for the backend to become healthy. Zero
+	// doesn't wait; the proxy reports not ready until it is.
+	WaitTimeout time.Duration `json:"wait_timeout" yaml:"wait_timeout" toml:"wait_timeout"`
+
+	// FailStart fails Start when the backend isn't healthy within WaitTimeout
+	FailStart bool `json:"fail_start" yaml:"fail_start" toml:"fail_start"`
+
+	// GateRoutes answers requests for the backend with 503 until it is healthy,
+	// instead of proxying them
+	GateRoutes bool `json:"gate_routes" yaml:"gate_routes" toml:"gate_routes"`
+}
+
+// validate checks the endpoint, interval and gated backends.
+func (c *ReadinessConfig) validate(backends map[string]string) error {
+	if c.Endpoint != "" && !strings.HasPrefix(c.Endpoint, "/") {
+		return fmt.Errorf("%w: endpoint %q must start with /", ErrInvalidReadinessConfig, c.Endpoint)
+	}
+	if c.Interval < 0 {
+		return fmt.Errorf("%w: interval %s is negative", ErrInvalidReadinessConfig, c.Interval)
+	}
+	for backendID, backend := range c.Backends {
+		if _, ok := backends[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidReadinessConfig, backendID)
+		}
+		if backend.WaitTimeout < 0 {
+			return fmt.Errorf("%w: backend %s: wait_timeout %s is negative", ErrInvalidReadinessConfig, backendID, backend.WaitTimeout)
+		}
+		if backend.FailStart && backend.WaitTimeout == 0 {
+			return fmt.Errorf("%w: backend %s: fail_start requires wait_timeout", ErrInvalidReadinessConfig, backendID)
+		}
+	}
+	return nil
+}
+
+// ReadinessStatus reports whether the proxy is ready and which gated backends are.
+type ReadinessStatus struct {
+	Ready    bool            `json:"ready"`
+	Backends map[string]bool `json:"backends,omitempty"`
+}
+
+// backendReadiness tracks whether a gated backend has been healthy.
+type backendReadiness struct {
+	id     string
+	config BackendReadinessConfig
+	ready  atomic.Bool
+	// readyCh is closed once the backend is ready
+	readyCh chan struct{}
+}
+
+// readinessGate probes gated backends until they are ready.
+type readinessGate struct {
+	backends map[string]*backendReadiness
+	interval time.Duration
+	// prober resolves health check endpoints and expected status codes
+	prober *HealthChecker
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// resolveReadiness builds the readiness state of the gated backends, all not ready.
+func (m *ReverseProxyModule) resolveReadiness() {
+	m.readiness = nil
+	if len(m.config.Readiness.Backends) == 0 {
+		return
+	}
+	gate := &readinessGate{
+		backends: make(map[string]*backendReadiness, len(m.config.Readiness.Backends)),
+		interval: m.config.Readiness.Interval,
+		prober:   m.healthChecker,
+	}
+	if gate.interval == 0 {
+		gate.interval = defaultReadinessInterval
+	}
+	if gate.prober == nil {
+		gate.prober = NewHealthChecker(&m.config.HealthCheck, m.config.BackendServices, m.httpClient, slog.Default())
+	}
+	for backendID, config := range m.config.Readiness.Backends {
+		gate.backends[backendID] = &backendReadiness{id: backendID, config: config, readyCh: make(chan struct{})}
+	}
+	m.readiness = gate
+}
+
+// awaitReadiness starts probing the gated backends and waits for those with a
+// wait_timeout. It fails when a backend with fail_start isn't ready in time, or ctx
+// ends first; the probes then stop, since the module won't start.
+func (m *ReverseProxyModule) awaitReadiness(ctx context.Context) (err error) {
+	gate := m.readiness
+	if gate == nil {
+		return nil
+	}
+	probeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	gate.stop = cancel
+	defer func() {
+		if err != nil {
+			m.stopReadiness()
+		}
+	}()
+	for _, backend := range gate.backends {
+		gate.wg.Add(1)
+		go func() {
+			defer gate.wg.Done()
+			m.probeUntilReady(probeCtx, backend)
+		}()
+	}
+
+	ids := make([]string, 0, len(gate.backends))
+	for id := range gate.backends {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	start := time.Now()
+	var failed []string
+	for _, id := range ids {
+		backend := gate.backends[id]
+		if backend.config.WaitTimeout == 0 {
+			continue
+		}
+		timer := time.NewTimer(max(backend.config.WaitTimeout-time.Since(start), 0))
+		select {
+		case <-backend.readyCh:
+			timer.Stop()
+			continue
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("waiting for backend %s to become ready: %w", id, ctx.Err())
+		case <-timer.C:
+		}
+		m.emitEvent(ctx, EventTypeBackendNotReady, map[string]interface{}{
+			"backend_id":      id,
+			"wait_timeout_ms": backend.config.WaitTimeout.Milliseconds(),
+			"fail_start":      backend.config.FailStart,
+		})
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Warn("Backend not ready after waiting", "backend", id, "wait_timeout", backend.config.WaitTimeout)
+		}
+		if backend.config.FailStart {
+			failed = append(failed, id)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %s", ErrBackendNotReady, strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// probeUntilReady probes a backend every interval until it answers with an expected
+// status code or ctx is cancelled.
+func (m *ReverseProxyModule) probeUntilReady(ctx context.Context, backend *backendReadiness) {
+	gate := m.readiness
+	baseURL := m.config.BackendServices[backend.id]
+	start := time.Now()
+	ticker := time.NewTicker(gate.interval)
+	defer ticker.Stop()
+	for {
+		if err := gate.probe(ctx, backend.id, baseURL); err == nil {
+			backend.ready.Store(true)
+			close(backend.readyCh)
+			m.emitEvent(ctx, EventTypeBackendReady, map[string]interface{}{
+				"backend_id": backend.id,
+				"waited_ms":  time.Since(start).Milliseconds(),
+			})
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Info("Backend ready", "backend", backend.id, "waited", time.Since(start))
+			}
+			return
+		} else if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Backend not ready yet", "backend", backend.id, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probe requests the backend's health check endpoint once.
+func (g *readinessGate) probe(ctx context.Context, backendID, baseURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, g.interval)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.prober.getHealthCheckEndpoint(backendID, baseURL), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "modular-reverseproxy-health-check/1.0")
+	resp, err := g.prober.httpClient.Do(req) //nolint:gosec // G704: readiness probes are sent to configured backend URLs
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	_ = resp.Body.Close()
+	if !slices.Contains(g.prober.getExpectedStatusCodes(backendID), resp.StatusCode) {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatusCode, resp.StatusCode)
+	}
+	return nil
+}
+
+// stopReadiness stops probing backends that aren't ready.
+func (m *ReverseProxyModule) stopReadiness() {
+	if m.readiness == nil || m.readiness.stop == nil {
+		return
+	}
+	m.readiness.stop()
+	m.readiness.wg.Wait()
+	m.readiness.stop = nil
+}
+
+// withReadinessGate answers requests for a backend with gate_routes with 503 until
+// the backend is ready.
+func (m *ReverseProxyModule) withReadinessGate(backendID string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.readiness == nil {
+		return handler
+	}
+	backend, ok := m.readiness.backends[backendID]
+	if !ok || !backend.config.GateRoutes {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !backend.ready.Load() {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Backend not ready", http.StatusServiceUnavailable)
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// Ready reports whether every backend gating readiness has been healthy. It is
+// always true without gated backends.
+func (m *ReverseProxyModule) Ready() bool {
+	return m.ReadinessStatus().Ready
+}
+
+// ReadinessStatus returns the readiness of the proxy and of each gated backend.
+func (m *ReverseProxyModule) ReadinessStatus() ReadinessStatus {
+	status := ReadinessStatus{Ready: true}
+	if m.readiness == nil {
+		return status
+	}
+	status.Backends = make(map[string]bool, len(m.readiness.backends))
+	for id, backend := range m.readiness.backends {
+		ready := backend.ready.Load()
+		status.Backends[id] = ready
+		status.Ready = status.Ready && ready
+	}
+	return status
+}
+
+// registerReadinessEndpoint serves the readiness status at the configured endpoint.
+func (m *ReverseProxyModule) registerReadinessEndpoint() {
+	endpoint := m.config.Readiness.Endpoint
+	m.safeHandleFunc(endpoint, func(w http.ResponseWriter, r *http.Request) {
+		status := m.ReadinessStatus()
+		w.Header().Set("Content-Type", "application/json")
+		if status.Ready {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(status); err != nil && m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Error("Failed to write readiness response", "error", err)
+		}
+	})
+	m.app.Logger().Info("Registered readiness endpoint", "endpoint", endpoint)
+}
@@ -0,0 +1,153 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReadinessTestBackend returns a backend whose /healthz answers 503 until healthy
+// is set.
+func newReadinessTestBackend(t *testing.T) (*httptest.Server, *atomic.Bool) {
+	t.Helper()
+	healthy := &atomic.Bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/healthz" && !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(server.Close)
+	return server, healthy
+}
+
+// newReadinessTestModule initializes a module proxying /api/* to backend with the
+// given readiness config.
+func newReadinessTestModule(t *testing.T, backendURL string, readiness ReadinessConfig) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+	cfg := &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backendURL},
+		Routes:          map[string]string{"/api/*": "api"},
+		RequestTimeout:  time.Second,
+		HealthCheck:     HealthCheckConfig{HealthEndpoints: map[string]string{"api": "/healthz"}},
+		Readiness:       readiness,
+	}
+
+	return newInitializedTestModule(t, cfg)
+}
+
+func serveTestRoute(router *testRouter, pattern, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	router.routes[pattern](rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestReadiness_GatesRoutesUntilHealthy(t *testing.T) {
+	backend, healthy := newReadinessTestBackend(t)
+	m, router, subject := newReadinessTestModule(t, backend.URL, ReadinessConfig{
+		Endpoint: "/ready",
+		Interval: 20 * time.Millisecond,
+		Backends: map[string]BackendReadinessConfig{"api": {GateRoutes: true}},
+	})
+	require.NoError(t, m.Start(context.Background()))
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+
+	assert.False(t, m.Ready())
+	rec := serveTestRoute(router, "/ready", "/ready")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var status ReadinessStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.Equal(t, ReadinessStatus{Ready: false, Backends: map[string]bool{"api": false}}, status)
+
+	rec = serveTestRoute(router, "/api/*", "/api/orders")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	healthy.Store(true)
+	require.Eventually(t, m.Ready, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, serveTestRoute(router, "/ready", "/ready").Code)
+	rec = serveTestRoute(router, "/api/*", "/api/orders")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	events := subject.eventsOfType(EventTypeBackendReady)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "api", data["backend_id"])
+}
+
+func TestReadiness_WaitOnStart(t *testing.T) {
+	backend, healthy := newReadinessTestBackend(t)
+	healthy.Store(true)
+	m, router, _ := newReadinessTestModule(t, backend.URL, ReadinessConfig{
+		Interval: 20 * time.Millisecond,
+		Backends: map[string]BackendReadinessConfig{"api": {WaitTimeout: 2 * time.Second, FailStart: true}},
+	})
+	require.NoError(t, m.Start(context.Background()))
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+
+	assert.True(t, m.Ready(), "Start waits for the backend")
+	assert.Equal(t, http.StatusOK, serveTestRoute(router, "/api/*", "/api/orders").Code)
+}
+
+func TestReadiness_FailStart(t *testing.T) {
+	backend, _ := newReadinessTestBackend(t)
+	m, _, subject := newReadinessTestModule(t, backend.URL, ReadinessConfig{
+		Interval: 20 * time.Millisecond,
+		Backends: map[string]BackendReadinessConfig{"api": {WaitTimeout: 100 * time.Millisecond, FailStart: true}},
+	})
+	err := m.Start(context.Background())
+	require.ErrorIs(t, err, ErrBackendNotReady)
+	assert.Contains(t, err.Error(), "api")
+	assert.Len(t, subject.eventsOfType(EventTypeBackendNotReady), 1)
+	assert.False(t, m.Ready())
+}
+
+func TestReadiness_StartCancelledStopsProbing(t *testing.T) {
+	backend, _ := newReadinessTestBackend(t)
+	m, _, _ := newReadinessTestModule(t, backend.URL, ReadinessConfig{
+		Interval: 20 * time.Millisecond,
+		Backends: map[string]BackendReadinessConfig{"api": {WaitTimeout: 10 * time.Second}},
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, m.Start(ctx), context.DeadlineExceeded)
+	assert.Nil(t, m.readiness.stop, "the probes were stopped and waited for")
+}
+
+func TestReadinessConfig_Validate(t *testing.T) {
+	backends := map[string]string{"api": "http://api"}
+	tests := []struct {
+		name   string
+		config ReadinessConfig
+		valid  bool
+	}{
+		{"empty", ReadinessConfig{}, true},
+		{"gated", ReadinessConfig{Endpoint: "/ready", Backends: map[string]BackendReadinessConfig{"api": {WaitTimeout: time.Second, FailStart: true}}}, true},
+		{"relative endpoint", ReadinessConfig{Endpoint: "ready"}, false},
+		{"negative interval", ReadinessConfig{Interval: -time.Second}, false},
+		{"unknown backend", ReadinessConfig{Backends: map[string]BackendReadinessConfig{"db": {}}}, false},
+		{"negative wait", ReadinessConfig{Backends: map[string]BackendReadinessConfig{"api": {WaitTimeout: -time.Second}}}, false},
+		{"fail without wait", ReadinessConfig{Backends: map[string]BackendReadinessConfig{"api": {FailStart: true}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate(backends)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidReadinessConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Redirect policies for backend responses, set per backend with
+// BackendServiceConfig.Redirects.
+const (
+	// RedirectPolicyPassthrough returns backend redirects to the client unchanged (default)
+	RedirectPolicyPassthrough = "passthrough"
+	// RedirectPolicyRewrite points Location headers that name an internal backend host
+	// at the host the client used instead
+	RedirectPolicyRewrite = "rewrite"
+	// RedirectPolicyFollow follows backend redirects server-side and returns the final
+	// response to the client
+	RedirectPolicyFollow = "follow"
+)
+
+// defaultRedirectMaxHops is the number of redirects followed when MaxHops is unset.
+const defaultRedirectMaxHops = 5
+
+// BackendRedirectConfig controls how redirects returned by a backend are handled.
+type BackendRedirectConfig struct {
+	// Policy is one of "passthrough" (default), "rewrite" or "follow"
+	Policy string `json:"policy" yaml:"policy" toml:"policy" env:"POLICY"`
+
+	// MaxHops is the number of redirects the follow policy follows before returning
+	// the redirect to the client. Defaults to 5.
+	MaxHops int `json:"max_hops" yaml:"max_hops" toml:"max_hops" env:"MAX_HOPS"`
+
+	// SameHostOnly limits the follow policy to redirects to the backend's own host;
+	// redirects elsewhere are returned to the client
+	SameHostOnly bool `json:"same_host_only" yaml:"same_host_only" toml:"same_host_only" env:"SAME_HOST_ONLY"`
+}
+
+// validate checks the redirect policy and hop limit.
+func (c BackendRedirectConfig) validate() error {
+	switch c.Policy {
+	case "", RedirectPolicyPassthrough, RedirectPolicyRewrite, RedirectPolicyFollow:
+	default:
+		return fmt.Errorf("%w: unknown policy %q", ErrInvalidRedirectConfig, c.Policy)
+	}
+	if c.MaxHops < 0 {
+		return fmt.Errorf("%w: negative max_hops", ErrInvalidRedirectConfig)
+	}
+	return nil
+}
+
+// publicOriginKey is the context key of the scheme and host the client addressed.
+type publicOriginKey struct{}
+
+// withPublicOrigin returns req carrying the scheme and host the client addressed, before
+// header rewriting replaces the Host, for rewriting redirects to internal hosts.
+func withPublicOrigin(req *http.Request) *http.Request {
+	origin := &url.URL{Scheme: "http", Host: req.Host}
+	if req.TLS != nil {
+		origin.Scheme = "https"
+	} else if proto := req.Header.Get("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		origin.Scheme = proto
+	}
+	return req.WithContext(context.WithValue(req.Context(), publicOriginKey{}, origin))
+}
+
+// applyRedirectPolicy handles a redirect from backendID according to the backend's
+// redirect policy. Followed redirects replace resp with the final response, using
+// transport for the further requests.
+func (m *ReverseProxyModule) applyRedirectPolicy(resp *http.Response, config *ReverseProxyConfig, backendID string, transport http.RoundTripper) error {
+	if config == nil || !isRedirect(resp.StatusCode) || resp.Request == nil {
+		return nil
+	}
+	policy := config.BackendConfigs[backendID].Redirects
+	switch policy.Policy {
+	case RedirectPolicyRewrite:
+		m.rewriteRedirectLocation(resp, config)
+	case RedirectPolicyFollow:
+		return m.followRedirects(resp, policy, transport)
+	}
+	return nil
+}
+
+// rewriteRedirectLocation points resp's Location at the client-facing origin when it
+// names the backend's host or another configured backend's.
+func (m *ReverseProxyModule) rewriteRedirectLocation(resp *http.Response, config *ReverseProxyConfig) {
+	location, err := resp.Location()
+	if err != nil {
+		return
+	}
+	origin, ok := resp.Request.Context().Value(publicOriginKey{}).(*url.URL)
+	if !ok || origin.Host == "" || !isInternalHost(location.Host, resp.Request.URL.Host, config) {
+		return
+	}
+	location.Scheme = origin.Scheme
+	location.Host = origin.Host
+	resp.Header.Set("Location", location.String())
+}
+
+// isInternalHost reports whether host is the backend's own host or that of a
+// configured backend.
+func isInternalHost(host, backendHost string, config *ReverseProxyConfig) bool {
+	if strings.EqualFold(host, backendHost) {
+		return true
+	}
+	matches := func(rawURL string) bool {
+		u, err := url.Parse(rawURL)
+		return err == nil && u.Host != "" && strings.EqualFold(u.Host, host)
+	}
+	for _, backendURL := range config.BackendServices {
+		if matches(backendURL) {
+			return true
+		}
+	}
+	for _, backendCfg := range config.BackendConfigs {
+		if matches(backendCfg.URL) {
+			return true
+		}
+	}
+	return false
+}
+
+// followRedirects requests the locations resp redirects to, up to the policy's hop
+// limit, and replaces resp with the last response. Redirects that can't be followed,
+// such as a 307 for a request whose body was already sent, are returned as they are.
+func (m *ReverseProxyModule) followRedirects(resp *http.Response, policy BackendRedirectConfig, transport http.RoundTripper) error {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	maxHops := policy.MaxHops
+	if maxHops == 0 {
+		maxHops = defaultRedirectMaxHops
+	}
+	backendHost := resp.Request.URL.Host
+
+	for hop := 0; hop < maxHops && isRedirect(resp.StatusCode); hop++ {
+		next, ok := nextRedirectRequest(resp, policy.SameHostOnly, backendHost)
+		if !ok {
+			return nil
+		}
+		nextResp, err := transport.RoundTrip(next)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrRedirectFailed, next.URL.Redacted(), err)
+		}
+		// The redirect's body is discarded in favour of the response it leads to
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		*resp = *nextResp
+	}
+	return nil
+}
+
+// nextRedirectRequest builds the request following the redirect in resp, reporting
+// false when the redirect can't or mustn't be followed.
+func nextRedirectRequest(resp *http.Response, sameHostOnly bool, backendHost string) (*http.Request, bool) {
+	location, err := resp.Location()
+	if err != nil || (location.Scheme != "http" && location.Scheme != "https") {
+		return nil, false
+	}
+	if sameHostOnly && !strings.EqualFold(location.Host, backendHost) {
+		return nil, false
+	}
+
+	prev := resp.Request
+	method := prev.Method
+	keepBody := resp.StatusCode == http.StatusTemporaryRedirect || resp.StatusCode == http.StatusPermanentRedirect
+	if !keepBody && method != http.MethodHead {
+		method = http.MethodGet
+	}
+	var body io.ReadCloser
+	if keepBody && prev.Body != nil && prev.Body != http.NoBody {
+		// The body was consumed by the previous request; replay it if possible
+		if prev.GetBody == nil {
+			return nil, false
+		}
+		if body, err = prev.GetBody(); err != nil {
+			return nil, false
+		}
+	}
+
+	next, err := http.NewRequestWithContext(prev.Context(), method, location.String(), body)
+	if err != nil {
+		return nil, false
+	}
+	next.Header = prev.Header.Clone()
+	if keepBody {
+		next.ContentLength = prev.ContentLength
+		next.GetBody = prev.GetBody
+	} else {
+		next.Header.Del("Content-Length")
+		next.Header.Del("Content-Type")
+	}
+	if strings.EqualFold(location.Host, prev.URL.Host) {
+		next.Host = prev.Host
+	} else {
+		// Credentials aren't forwarded to another host
+		next.Header.Del("Authorization")
+		next.Header.Del("Cookie")
+	}
+	return next, true
+}
+
+// isRedirect reports whether status is a redirect with a Location to follow.
+func isRedirect(status int) bool {
+	switch status {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
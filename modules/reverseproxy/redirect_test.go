@@ -0,0 +1,139 @@
+package reverseproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveThroughRedirectProxy proxies a request for path to backendURL with the given
+// redirect policy and returns the client's response.
+func serveThroughRedirectProxy(t *testing.T, backendURL string, policy BackendRedirectConfig, method, path string) *http.Response {
+	t.Helper()
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backendURL, "auth": "http://auth.internal:8081"},
+		BackendConfigs:  map[string]BackendServiceConfig{"api": {Redirects: policy}},
+	}
+	target, err := url.Parse(backendURL)
+	require.NoError(t, err)
+	proxy := m.createReverseProxyForBackend(context.Background(), target, "api", "")
+
+	req := httptest.NewRequest(method, "https://www.example.com"+path, strings.NewReader("payload"))
+	w := httptest.NewRecorder()
+	proxy.ServeHTTP(w, req)
+	return w.Result()
+}
+
+func newRedirectingBackend(t *testing.T) *httptest.Server {
+	t.Helper()
+	var backend *httptest.Server
+	backend = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, backend.URL+"/new?x=1", http.StatusFound)
+		case "/relative":
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+		case "/login":
+			http.Redirect(w, r, "http://auth.internal:8081/login", http.StatusFound)
+		case "/external":
+			http.Redirect(w, r, "https://idp.example.org/authorize", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/temporary":
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+		case "/new":
+			body, _ := io.ReadAll(r.Body)
+			_, _ = io.WriteString(w, r.Method+" "+r.URL.RequestURI()+" "+string(body))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(backend.Close)
+	return backend
+}
+
+func TestRedirectPolicy_Passthrough(t *testing.T) {
+	backend := newRedirectingBackend(t)
+
+	resp := serveThroughRedirectProxy(t, backend.URL, BackendRedirectConfig{}, http.MethodGet, "/old")
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, backend.URL+"/new?x=1", resp.Header.Get("Location"))
+}
+
+func TestRedirectPolicy_Rewrite(t *testing.T) {
+	backend := newRedirectingBackend(t)
+	policy := BackendRedirectConfig{Policy: RedirectPolicyRewrite}
+
+	resp := serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/old")
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://www.example.com/new?x=1", resp.Header.Get("Location"))
+
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/login")
+	assert.Equal(t, "https://www.example.com/login", resp.Header.Get("Location"),
+		"locations on other backends' hosts are rewritten too")
+
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/relative")
+	assert.Equal(t, "https://www.example.com/new", resp.Header.Get("Location"))
+
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/external")
+	assert.Equal(t, "https://idp.example.org/authorize", resp.Header.Get("Location"),
+		"external locations are left alone")
+}
+
+func TestRedirectPolicy_Follow(t *testing.T) {
+	backend := newRedirectingBackend(t)
+	policy := BackendRedirectConfig{Policy: RedirectPolicyFollow}
+
+	resp := serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/old")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "GET /new?x=1 ", string(body))
+	assert.Empty(t, resp.Header.Get("Location"))
+
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodPost, "/old")
+	body, _ = io.ReadAll(resp.Body)
+	assert.Equal(t, "GET /new?x=1 ", string(body), "a 302 after a POST is followed with a GET")
+
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodPost, "/temporary")
+	assert.Equal(t, http.StatusTemporaryRedirect, resp.StatusCode,
+		"a 307 is returned when the request body can't be replayed")
+
+	resp = serveThroughRedirectProxy(t, backend.URL, BackendRedirectConfig{Policy: RedirectPolicyFollow, MaxHops: 3}, http.MethodGet, "/loop")
+	assert.Equal(t, http.StatusFound, resp.StatusCode, "the redirect is returned once the hop limit is reached")
+
+	policy.SameHostOnly = true
+	resp = serveThroughRedirectProxy(t, backend.URL, policy, http.MethodGet, "/external")
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "https://idp.example.org/authorize", resp.Header.Get("Location"))
+}
+
+func TestRedirectPolicy_FollowFailure(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://127.0.0.1:1/unreachable", http.StatusFound)
+	}))
+	defer backend.Close()
+
+	resp := serveThroughRedirectProxy(t, backend.URL, BackendRedirectConfig{Policy: RedirectPolicyFollow}, http.MethodGet, "/")
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func TestBackendRedirectConfig_Validate(t *testing.T) {
+	require.NoError(t, BackendRedirectConfig{Policy: RedirectPolicyFollow, MaxHops: 2, SameHostOnly: true}.validate())
+	assert.ErrorIs(t, BackendRedirectConfig{Policy: "bounce"}.validate(), ErrInvalidRedirectConfig)
+	assert.ErrorIs(t, BackendRedirectConfig{MaxHops: -1}.validate(), ErrInvalidRedirectConfig)
+
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://api.internal"},
+		BackendConfigs:  map[string]BackendServiceConfig{"api": {Redirects: BackendRedirectConfig{Policy: "bounce"}}},
+	}
+	assert.ErrorIs(t, m.validateConfig(), ErrInvalidRedirectConfig)
+}
@@ -0,0 +1,233 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httputil"
+)
+
+// Reload applies changed backends, routes and default backend of the reverseproxy
+// config section while the proxy keeps serving, which makes the module Reloadable for
+// the application's ReloadConfig. New backends and backends with a new URL get a new
+// proxy, removed backends are drained like RemoveBackend, and changed route patterns
+// are registered again. Tenants are merged with the new global config.
+//
+// The reloaded config is validated as a whole first, so a route or setting left
+// referencing a removed backend rejects it and the proxy keeps its previous
+// configuration. Other settings keep their previous values until a restart.
+func (m *ReverseProxyModule) Reload(ctx context.Context, _ []string) error {
+	if m.app == nil || m.config == nil {
+		return ErrConfigurationNotLoaded
+	}
+	section, err := m.app.GetConfigSection(m.Name())
+	if err != nil {
+		return fmt.Errorf("failed to get config section '%s': %w", m.Name(), err)
+	}
+	var next *ReverseProxyConfig
+	switch v := section.GetConfig().(type) {
+	case *ReverseProxyConfig:
+		next = v
+	case ReverseProxyConfig:
+		next = &v
+	default:
+		return fmt.Errorf("%w: %T", ErrUnexpectedConfigType, v)
+	}
+
+	// The running config with the reloaded backends and routes
+	reloaded := *m.config
+	reloaded.BackendServices = maps.Clone(next.BackendServices)
+	reloaded.Routes = maps.Clone(next.Routes)
+	reloaded.DefaultBackend = next.DefaultBackend
+	if err := m.validateReload(&reloaded); err != nil {
+		return err
+	}
+
+	previousBackends := maps.Clone(m.config.BackendServices)
+	previousRoutes := maps.Clone(m.config.Routes)
+	if m.config.BackendServices == nil {
+		m.config.BackendServices = make(map[string]string)
+	}
+
+	// Proxies for new and moved backends first, so changed routes find them
+	var changed []string
+	for _, backendID := range sortedKeys(reloaded.BackendServices) {
+		serviceURL := reloaded.BackendServices[backendID]
+		if previous, ok := previousBackends[backendID]; ok && previous == serviceURL {
+			continue
+		}
+		m.config.BackendServices[backendID] = serviceURL
+		if serviceURL == "" {
+			continue
+		}
+		changed = append(changed, backendID)
+		m.backendProxiesMutex.RLock()
+		previousProxy := m.backendProxies[backendID]
+		m.backendProxiesMutex.RUnlock()
+		if err := m.createBackendProxy(backendID, serviceURL); err != nil {
+			return err
+		}
+		if previousProxy != nil {
+			m.closeProxyTransport(previousProxy.Transport)
+		}
+		if _, exists := m.circuitBreakers[backendID]; !exists && m.config.CircuitBreakerConfig.Enabled {
+			m.setupCircuitBreaker(backendID)
+		}
+	}
+
+	previousDefault := m.defaultBackend
+	m.config.Routes = reloaded.Routes
+	m.config.DefaultBackend = reloaded.DefaultBackend
+	m.defaultBackend = reloaded.DefaultBackend
+	m.reloadRoutes(previousRoutes, previousDefault)
+
+	// Removed backends last, once no route sends requests to them
+	var removed []string
+	for _, backendID := range sortedKeys(previousBackends) {
+		if _, ok := reloaded.BackendServices[backendID]; ok {
+			continue
+		}
+		removed = append(removed, backendID)
+		if err := m.RemoveBackendWithContext(ctx, backendID); err != nil {
+			return err
+		}
+	}
+
+	m.reloadTenantBackends(ctx, append(changed, removed...))
+	if m.healthChecker != nil {
+		m.healthChecker.UpdateBackends(ctx, m.config.BackendServices)
+	}
+
+	m.app.Logger().Info("Reloaded reverse proxy backends and routes",
+		"changed_backends", changed, "removed_backends", removed, "routes", len(m.config.Routes))
+	m.emitEvent(ctx, EventTypeConfigReloaded, map[string]interface{}{
+		"changed_backends": changed,
+		"removed_backends": removed,
+		"backend_count":    len(m.config.BackendServices),
+		"route_count":      len(m.config.Routes),
+		"default_backend":  m.defaultBackend,
+	})
+	return nil
+}
+
+// validateReload checks the running config with reloaded backends and routes, which
+// must not reference a backend the reload removes.
+func (m *ReverseProxyModule) validateReload(cfg *ReverseProxyConfig) error {
+	report := &ConfigValidationReport{}
+	m.validateConfigFull(cfg, "", report)
+	if err := report.Err(); err != nil {
+		return err
+	}
+	for _, backendID := range cfg.Prewarm.Backends {
+		if _, ok := cfg.BackendServices[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidPrewarmConfig, backendID)
+		}
+	}
+	for backendID := range cfg.SLO.Backends {
+		if _, ok := cfg.BackendServices[backendID]; !ok {
+			return fmt.Errorf("%w: unknown backend %q", ErrInvalidSLOConfig, backendID)
+		}
+	}
+	if err := cfg.Readiness.validate(cfg.BackendServices); err != nil {
+		return err
+	}
+	return cfg.OpenAPI.validate(cfg.BackendServices)
+}
+
+// reloadRoutes registers the handlers of route patterns added, removed or pointed at
+// another backend since previous. Patterns can't be unregistered, so a removed one
+// gets the handler of requests no route matches.
+func (m *ReverseProxyModule) reloadRoutes(previous map[string]string, previousDefault string) {
+	if m.router == nil {
+		// Start registers the reloaded routes
+		return
+	}
+	tenantAware := len(m.tenantConfigs()) > 0
+
+	patterns := maps.Clone(previous)
+	maps.Copy(patterns, m.config.Routes)
+	changed := false
+	for _, pattern := range sortedKeys(patterns) {
+		backendID, routed := m.config.Routes[pattern]
+		previousBackend, wasRouted := previous[pattern]
+		if routed == wasRouted && backendID == previousBackend {
+			continue
+		}
+		if wasRouted {
+			m.removeBackendRoute(previousBackend, pattern)
+		}
+
+		var handler http.HandlerFunc
+		switch {
+		case tenantAware:
+			// Tenant-aware handlers look the route up on each request
+			handler = m.createTenantAwareHandler(pattern)
+		case routed:
+			handler = m.configuredRouteHandler(pattern, backendID)
+			m.setBackendRoute(backendID, pattern, handler)
+		default:
+			handler = m.catchAllHandler()
+		}
+		m.safeHandleFunc(pattern, m.withRouteMiddleware(pattern, handler))
+		changed = true
+
+		m.routePatternsMutex.Lock()
+		if m.routePatterns != nil {
+			m.routePatterns[pattern] = true
+		}
+		m.routePatternsMutex.Unlock()
+	}
+
+	// The catch-all route serves a new default backend and the changed routes,
+	// unless a route took it over
+	if _, routed := m.config.Routes["/*"]; !routed && !tenantAware && m.defaultBackend != "" &&
+		(changed || m.defaultBackend != previousDefault) {
+		m.safeHandleFunc("/*", m.withRouteMiddleware("/*", m.catchAllHandler()))
+	}
+}
+
+// removeBackendRoute forgets the handler of a route pattern that no longer sends
+// requests to a backend.
+func (m *ReverseProxyModule) removeBackendRoute(backendID, route string) {
+	m.backendRoutesMutex.Lock()
+	defer m.backendRoutesMutex.Unlock()
+	delete(m.backendRoutes[backendID], route)
+}
+
+// reloadTenantBackends merges the reloaded global config into the config of every
+// tenant, and replaces the tenant proxies of the given backends unless the tenant
+// sets its own URL for them.
+func (m *ReverseProxyModule) reloadTenantBackends(ctx context.Context, backends []string) {
+	if m.tenantApp == nil {
+		return
+	}
+	for tenantID := range m.tenantConfigs() {
+		cp, err := m.tenantApp.GetTenantConfig(tenantID, m.Name())
+		if err != nil {
+			continue
+		}
+		raw, ok := cp.GetConfig().(*ReverseProxyConfig)
+		if !ok {
+			continue
+		}
+		m.setTenantConfig(tenantID, mergeConfigs(m.config, raw))
+
+		var stale []*httputil.ReverseProxy
+		m.tenantProxiesMutex.Lock()
+		for _, backendID := range backends {
+			if raw.BackendServices[backendID] != "" {
+				continue
+			}
+			if proxy := m.tenantBackendProxies[tenantID][backendID]; proxy != nil {
+				stale = append(stale, proxy)
+			}
+			delete(m.tenantBackendProxies[tenantID], backendID)
+		}
+		m.tenantProxiesMutex.Unlock()
+		for _, proxy := range stale {
+			m.closeProxyTransport(proxy.Transport)
+		}
+	}
+	m.createTenantProxies(ctx)
+}
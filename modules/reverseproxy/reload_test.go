@@ -0,0 +1,131 @@
+package reverseproxy
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReloadTestModule starts a module with cfg and returns the application to
+// reload its config section through.
+func newReloadTestModule(t *testing.T, cfg *ReverseProxyConfig) (*ReverseProxyModule, *MockTenantApplication, *testRouter, *capturingSubject) {
+	t.Helper()
+	cfg.RequestTimeout = 5 * time.Second
+
+	m, router, subject := newStartedTestModule(t, cfg)
+	return m, m.app.(*MockTenantApplication), router, subject
+}
+
+func TestReload_AppliesBackendsAndRoutes(t *testing.T) {
+	m, app, router, subject := newReloadTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api": newNamedBackend(t, "api-v1").URL,
+			"old": newNamedBackend(t, "old").URL,
+		},
+		Routes: map[string]string{
+			"/api/*": "api",
+			"/old/*": "old",
+		},
+		DefaultBackend: "api",
+	})
+	assert.Equal(t, "old", serveVia(router, http.MethodGet, "/old/items", "", "", "").Body.String())
+
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api": newNamedBackend(t, "api-v2").URL,
+			"new": newNamedBackend(t, "new").URL,
+		},
+		Routes: map[string]string{
+			"/api/*": "api",
+			"/new/*": "new",
+		},
+		DefaultBackend: "api",
+	}))
+	require.NoError(t, m.Reload(context.Background(), []string{"reverseproxy"}))
+
+	assert.Equal(t, "api-v2", serveVia(router, http.MethodGet, "/api/items", "", "", "").Body.String(), "moved backend is proxied to its new URL")
+	assert.Equal(t, "new", serveVia(router, http.MethodGet, "/new/items", "", "", "").Body.String(), "added route is registered")
+	assert.Equal(t, "api-v2", serveVia(router, http.MethodGet, "/old/items", "", "", "").Body.String(), "removed route falls back to the default backend")
+	assert.NotContains(t, m.config.BackendServices, "old")
+	require.Len(t, subject.eventsOfType(EventTypeConfigReloaded), 1)
+	require.Len(t, subject.eventsOfType(EventTypeBackendRemoved), 1)
+}
+
+func TestReload_RejectsRouteToRemovedBackend(t *testing.T) {
+	m, app, router, _ := newReloadTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api":   newNamedBackend(t, "api").URL,
+			"users": newNamedBackend(t, "users").URL,
+		},
+		Routes: map[string]string{"/users/*": "users"},
+	})
+
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": newNamedBackend(t, "api").URL},
+		Routes:          map[string]string{"/users/*": "users"},
+	}))
+	require.ErrorIs(t, m.Reload(context.Background(), []string{"reverseproxy"}), ErrConfigValidationFailed)
+
+	assert.Contains(t, m.config.BackendServices, "users")
+	assert.Equal(t, "users", serveVia(router, http.MethodGet, "/users/1", "", "", "").Body.String())
+}
+
+func TestReload_AddsDefaultBackend(t *testing.T) {
+	m, app, router, _ := newReloadTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": newNamedBackend(t, "api").URL},
+		Routes:          map[string]string{"/api/*": "api"},
+	})
+	assert.Equal(t, "api", serveVia(router, http.MethodGet, "/other", "", "", "").Body.String(), "without a default backend a backend proxy serves the catch-all")
+
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api":      newNamedBackend(t, "api").URL,
+			"fallback": newNamedBackend(t, "fallback").URL,
+		},
+		Routes:         map[string]string{"/api/*": "api"},
+		DefaultBackend: "fallback",
+	}))
+	require.NoError(t, m.Reload(context.Background(), []string{"reverseproxy"}))
+
+	assert.Equal(t, "fallback", serveVia(router, http.MethodGet, "/other", "", "", "").Body.String())
+}
+
+func TestHealthChecker_UpdateBackendsStopsRemovedChecks(t *testing.T) {
+	var removedHits, movedHits atomic.Int64
+	removed := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { removedHits.Add(1) }))
+	defer removed.Close()
+	moved := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { movedHits.Add(1) }))
+	defer moved.Close()
+	kept := newNamedBackend(t, "kept").URL
+
+	hc := NewHealthChecker(&HealthCheckConfig{
+		Enabled:             true,
+		Interval:            10 * time.Millisecond,
+		Timeout:             time.Second,
+		ExpectedStatusCodes: []int{http.StatusOK},
+	}, map[string]string{"removed": removed.URL, "api": moved.URL}, http.DefaultClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, hc.Start(context.Background()))
+	defer hc.Stop(context.Background())
+
+	hc.UpdateBackends(context.Background(), map[string]string{"api": kept})
+	time.Sleep(30 * time.Millisecond) // let checks in flight finish
+	removedBefore, movedBefore := removedHits.Load(), movedHits.Load()
+	time.Sleep(100 * time.Millisecond)
+
+	assert.Equal(t, removedBefore, removedHits.Load(), "removed backend is no longer checked")
+	assert.Equal(t, movedBefore, movedHits.Load(), "old URL of a moved backend is no longer checked")
+	status, ok := hc.GetBackendHealthStatus("api")
+	require.True(t, ok)
+	assert.Equal(t, kept, status.URL)
+	_, ok = hc.GetBackendHealthStatus("removed")
+	assert.False(t, ok)
+}
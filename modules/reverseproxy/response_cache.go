@@ -16,6 +16,46 @@ type CachedResponse struct {
 	Body           []byte
 	LastAccessed   time.Time
 	ExpirationTime time.Time
+
+	// StaleWhileRevalidate is how long after ExpirationTime the response may be served
+	// while it is refreshed in the background
+	StaleWhileRevalidate time.Duration
+
+	// StaleIfError is how long after ExpirationTime the response may be served in place
+	// of an error response
+	StaleIfError time.Duration
+
+	// varyBase is the key of the request the response is a Vary variant of
+	varyBase string
+}
+
+// cacheFreshness describes whether a cached response can be served as is.
+type cacheFreshness int
+
+const (
+	// cacheFresh responses have not expired
+	cacheFresh cacheFreshness = iota
+	// cacheStaleRevalidate responses have expired but are within their stale-while-revalidate window
+	cacheStaleRevalidate
+	// cacheStaleIfError responses may only be served if the backend fails
+	cacheStaleIfError
+)
+
+// freshness returns how the response may be served at now.
+func (c *CachedResponse) freshness(now time.Time) cacheFreshness {
+	switch {
+	case !now.After(c.ExpirationTime):
+		return cacheFresh
+	case !now.After(c.ExpirationTime.Add(c.StaleWhileRevalidate)):
+		return cacheStaleRevalidate
+	default:
+		return cacheStaleIfError
+	}
+}
+
+// retainedUntil returns when the response stops being usable, even as a stale fallback.
+func (c *CachedResponse) retainedUntil() time.Time {
+	return c.ExpirationTime.Add(max(c.StaleWhileRevalidate, c.StaleIfError))
 }
 
 // responseCache implements a simple cache for HTTP responses
@@ -26,6 +66,14 @@ type responseCache struct {
 	maxCacheSize int
 	cacheable    func(r *http.Request, statusCode int) bool
 	stopCleanup  chan struct{}
+
+	// revalidating holds the keys with a background refresh in flight, so each stale
+	// entry triggers a single backend request no matter how many clients ask for it
+	revalidating map[string]struct{}
+
+	// vary holds the request headers responses vary on, by the key of the request
+	// before its variant is selected
+	vary map[string][]string
 }
 
 // newResponseCache creates a new response cache with the specified TTL and max size
@@ -37,6 +85,8 @@ func newResponseCache(defaultTTL time.Duration, maxCacheSize int, cleanupInterva
 		defaultTTL:   defaultTTL,
 		maxCacheSize: maxCacheSize,
 		stopCleanup:  make(chan struct{}),
+		revalidating: make(map[string]struct{}),
+		vary:         make(map[string][]string),
 		cacheable: func(r *http.Request, statusCode int) bool {
 			// Only cache GET requests with 200 OK responses by default
 			return r.Method == http.MethodGet && statusCode == http.StatusOK
@@ -48,6 +98,13 @@ func newResponseCache(defaultTTL time.Duration, maxCacheSize int, cleanupInterva
 
 // Set adds or updates a response in the cache
 func (rc *responseCache) Set(key string, statusCode int, headers http.Header, body []byte, ttl time.Duration) {
+	rc.SetWithStale(key, statusCode, headers, body, ttl, 0, 0)
+}
+
+// SetWithStale adds or updates a response in the cache that remains usable after it
+// expires: for staleWhileRevalidate while it is refreshed, and for staleIfError in place
+// of backend errors.
+func (rc *responseCache) SetWithStale(key string, statusCode int, headers http.Header, body []byte, ttl, staleWhileRevalidate, staleIfError time.Duration) {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
 
@@ -73,6 +130,9 @@ func (rc *responseCache) Set(key string, statusCode int, headers http.Header, bo
 		Body:           body,
 		LastAccessed:   time.Now(),
 		ExpirationTime: time.Now().Add(ttl),
+
+		StaleWhileRevalidate: staleWhileRevalidate,
+		StaleIfError:         staleIfError,
 	}
 }
 
@@ -88,9 +148,7 @@ func (rc *responseCache) Get(key string) (*CachedResponse, bool) {
 
 	// Check if the response has expired
 	if time.Now().After(cachedResp.ExpirationTime) {
-		rc.mutex.Lock()
-		delete(rc.cache, key)
-		rc.mutex.Unlock()
+		rc.deleteIfUnusable(key, cachedResp)
 		return nil, false
 	}
 
@@ -102,6 +160,78 @@ func (rc *responseCache) Get(key string) (*CachedResponse, bool) {
 	return cachedResp, true
 }
 
+// Lookup retrieves a response from the cache, including expired responses that can still
+// be served stale, and reports its freshness.
+func (rc *responseCache) Lookup(key string) (*CachedResponse, cacheFreshness, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+
+	cachedResp, found := rc.cache[key]
+	if !found {
+		return nil, 0, false
+	}
+
+	now := time.Now()
+	if now.After(cachedResp.retainedUntil()) {
+		delete(rc.cache, key)
+		return nil, 0, false
+	}
+	cachedResp.LastAccessed = now
+	return cachedResp, cachedResp.freshness(now), true
+}
+
+// deleteIfUnusable removes an expired response unless it can still be served stale.
+func (rc *responseCache) deleteIfUnusable(key string, cachedResp *CachedResponse) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if rc.cache[key] == cachedResp && time.Now().After(cachedResp.retainedUntil()) {
+		delete(rc.cache, key)
+	}
+}
+
+// beginRevalidation reports whether the caller should refresh key. It returns false
+// while another refresh of the same key is in flight.
+func (rc *responseCache) beginRevalidation(key string) bool {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if _, inFlight := rc.revalidating[key]; inFlight {
+		return false
+	}
+	rc.revalidating[key] = struct{}{}
+	return true
+}
+
+// endRevalidation marks the refresh of key as done.
+func (rc *responseCache) endRevalidation(key string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	delete(rc.revalidating, key)
+}
+
+// variantKey returns the key of the response variant for r among the responses cached
+// under baseKey, as selected by their Vary header.
+func (rc *responseCache) variantKey(baseKey string, r *http.Request) string {
+	rc.mutex.RLock()
+	vary := rc.vary[baseKey]
+	rc.mutex.RUnlock()
+	return varyKey(baseKey, vary, r)
+}
+
+// setVary records the headers the responses cached under baseKey vary on, and that
+// the response cached under variantKey is one of them.
+func (rc *responseCache) setVary(baseKey string, vary []string, variantKey string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if len(vary) == 0 {
+		delete(rc.vary, baseKey)
+	} else {
+		rc.vary[baseKey] = vary
+	}
+	if cachedResp, ok := rc.cache[variantKey]; ok {
+		cachedResp.varyBase = baseKey
+	}
+}
+
 // GenerateKey creates a cache key from an HTTP request
 func (rc *responseCache) GenerateKey(r *http.Request) string {
 	// Create a hash of the method, URL, and relevant headers
@@ -162,9 +292,17 @@ func (rc *responseCache) cleanup() {
 	defer rc.mutex.Unlock()
 
 	now := time.Now()
+	referenced := make(map[string]bool, len(rc.vary))
 	for k, v := range rc.cache {
-		if now.After(v.ExpirationTime) {
+		if now.After(v.retainedUntil()) {
 			delete(rc.cache, k)
+			continue
+		}
+		referenced[v.varyBase] = true
+	}
+	for baseKey := range rc.vary {
+		if !referenced[baseKey] {
+			delete(rc.vary, baseKey)
 		}
 	}
 }
@@ -174,6 +312,7 @@ func (rc *responseCache) Clear() {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
 	rc.cache = make(map[string]*CachedResponse)
+	rc.vary = make(map[string][]string)
 }
 
 // periodicCleanup runs a cleanup on the cache at regular intervals
@@ -3,9 +3,12 @@ package reverseproxy
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"testing/synctest"
 	"time"
 
 	"github.com/stretchr/testify/assert"
@@ -288,3 +291,134 @@ func TestConcurrentAccess(t *testing.T) {
 	assert.Positive(t, count, "Cache should contain items after concurrent operations")
 	assert.LessOrEqual(t, count, 1000, "Cache should not exceed max size")
 }
+
+func TestWithCache_StaleWhileRevalidate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var calls atomic.Int32
+		m := NewModule()
+		m.config = &ReverseProxyConfig{
+			CacheEnabled:              true,
+			CacheTTL:                  time.Minute,
+			CacheStaleWhileRevalidate: time.Minute,
+		}
+		m.responseCache = newResponseCache(time.Minute, 100, time.Hour)
+		handler := m.withCache(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "v%d", calls.Add(1))
+		}, "api")
+
+		get := func() *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+			return rec
+		}
+
+		assert.Equal(t, "MISS", get().Header().Get("X-Cache"))
+		time.Sleep(time.Minute + time.Second)
+
+		for range 5 {
+			rec := get()
+			assert.Equal(t, "STALE", rec.Header().Get("X-Cache"))
+			assert.Equal(t, "v1", rec.Body.String())
+		}
+		synctest.Wait()
+		assert.Equal(t, int32(2), calls.Load(), "stale requests trigger a single background refresh")
+
+		rec := get()
+		assert.Equal(t, "HIT", rec.Header().Get("X-Cache"))
+		assert.Equal(t, "v2", rec.Body.String())
+	})
+}
+
+func TestWithCache_StaleIfError(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var failing atomic.Bool
+		m := NewModule()
+		m.config = &ReverseProxyConfig{
+			CacheEnabled: true,
+			CacheTTL:     time.Minute,
+			RouteConfigs: map[string]RouteConfig{
+				"/api/*": {CacheStaleIfError: 10 * time.Minute},
+			},
+		}
+		m.responseCache = newResponseCache(time.Minute, 100, time.Hour)
+		handler := m.withCache(func(w http.ResponseWriter, r *http.Request) {
+			if failing.Load() {
+				http.Error(w, "Backend temporarily unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}, "api")
+
+		get := func() *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/api/items", nil))
+			return rec
+		}
+
+		get()
+		failing.Store(true)
+		time.Sleep(2 * time.Minute)
+
+		rec := get()
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "STALE-IF-ERROR", rec.Header().Get("X-Cache"))
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"), "error response headers do not leak")
+		assert.JSONEq(t, `{"ok":true}`, rec.Body.String())
+
+		time.Sleep(10 * time.Minute)
+		rec = get()
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code, "errors pass through once the window ends")
+	})
+}
+
+func TestResponseCache_LookupRetainsStaleEntries(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		rc := newResponseCache(time.Minute, 100, time.Hour)
+		rc.SetWithStale("key", http.StatusOK, nil, []byte("body"), time.Minute, time.Minute, 5*time.Minute)
+
+		_, freshness, found := rc.Lookup("key")
+		assert.True(t, found)
+		assert.Equal(t, cacheFresh, freshness)
+
+		time.Sleep(90 * time.Second)
+		_, found = rc.Get("key")
+		assert.False(t, found, "Get only returns fresh entries")
+		_, freshness, found = rc.Lookup("key")
+		assert.True(t, found, "stale entries are retained for their windows")
+		assert.Equal(t, cacheStaleRevalidate, freshness)
+
+		time.Sleep(time.Minute)
+		_, freshness, _ = rc.Lookup("key")
+		assert.Equal(t, cacheStaleIfError, freshness)
+
+		time.Sleep(5 * time.Minute)
+		rc.cleanup()
+		_, _, found = rc.Lookup("key")
+		assert.False(t, found)
+		assert.Empty(t, rc.cache)
+	})
+}
+
+func TestCacheStaleWindows_MostSpecificRoute(t *testing.T) {
+	m := NewModule()
+	config := &ReverseProxyConfig{
+		CacheStaleWhileRevalidate: time.Second,
+		CacheStaleIfError:         2 * time.Second,
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*":        {CacheStaleWhileRevalidate: time.Minute, CacheStaleIfError: time.Hour},
+			"/api/search/*": {CacheStaleWhileRevalidate: 5 * time.Minute},
+		},
+	}
+	windows := func(path string) [2]time.Duration {
+		staleWhileRevalidate, staleIfError := m.cacheStaleWindows(httptest.NewRequest(http.MethodGet, path, nil), config)
+		return [2]time.Duration{staleWhileRevalidate, staleIfError}
+	}
+
+	for range 20 {
+		assert.Equal(t, [2]time.Duration{5 * time.Minute, 2 * time.Second}, windows("/api/search/items"))
+	}
+	assert.Equal(t, [2]time.Duration{time.Minute, time.Hour}, windows("/api/users"))
+	assert.Equal(t, [2]time.Duration{time.Second, 2 * time.Second}, windows("/other"))
+}
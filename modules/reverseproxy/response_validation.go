@@ -0,0 +1,343 @@
+package reverseproxy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// Actions taken on responses failing validation.
+const (
+	// ResponseValidationLog logs violations and returns the response unchanged
+	ResponseValidationLog = "log"
+	// ResponseValidationReject replaces violating responses with 502 Bad Gateway
+	ResponseValidationReject = "reject"
+	// ResponseValidationTolerate only emits the violation event
+	ResponseValidationTolerate = "tolerate"
+)
+
+// defaultValidationMaxBodySize is the largest body validated against a schema when
+// the route doesn't set one.
+const defaultValidationMaxBodySize = 1 << 20
+
+// ResponseValidationConfig checks a route's backend responses against the contract
+// clients expect, to catch backend regressions at the proxy. Every violation emits
+// a response.validation_failed event.
+//
+//	route_configs:
+//	  "/api/users/*":
+//	    response_validation:
+//	      allowed_status_codes: [200, 404]
+//	      schema_file: schemas/user.json
+//	      on_violation: reject
+type ResponseValidationConfig struct {
+	// AllowedStatusCodes lists the status codes the backend may answer with. Empty
+	// allows any.
+	AllowedStatusCodes []int `json:"allowed_status_codes" yaml:"allowed_status_codes" toml:"allowed_status_codes" env:"ALLOWED_STATUS_CODES"`
+
+	// Schema is a JSON Schema the JSON bodies of 2xx responses must satisfy
+	Schema string `json:"schema" yaml:"schema" toml:"schema" env:"SCHEMA"`
+
+	// SchemaFile is the path of a JSON Schema file, used instead of Schema
+	SchemaFile string `json:"schema_file" yaml:"schema_file" toml:"schema_file" env:"SCHEMA_FILE"`
+
+	// OnViolation is "log" (default), "reject" or "tolerate"
+	OnViolation string `json:"on_violation" yaml:"on_violation" toml:"on_violation" env:"ON_VIOLATION"`
+
+	// MaxBodySize is the largest body in bytes validated against the schema. Larger
+	// bodies pass unvalidated. Zero uses 1 MiB.
+	MaxBodySize int64 `json:"max_body_size" yaml:"max_body_size" toml:"max_body_size" env:"MAX_BODY_SIZE"`
+}
+
+// validate checks the action, status codes and body size limit, and compiles the schema.
+func (c *ResponseValidationConfig) validate() error {
+	switch c.OnViolation {
+	case "", ResponseValidationLog, ResponseValidationReject, ResponseValidationTolerate:
+	default:
+		return fmt.Errorf("%w: unknown on_violation %q", ErrInvalidResponseValidation, c.OnViolation)
+	}
+	for _, code := range c.AllowedStatusCodes {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("%w: invalid status code %d", ErrInvalidResponseValidation, code)
+		}
+	}
+	if c.MaxBodySize < 0 {
+		return fmt.Errorf("%w: max_body_size %d is negative", ErrInvalidResponseValidation, c.MaxBodySize)
+	}
+	if c.Schema != "" && c.SchemaFile != "" {
+		return fmt.Errorf("%w: schema and schema_file are mutually exclusive", ErrInvalidResponseValidation)
+	}
+	if len(c.AllowedStatusCodes) == 0 && c.Schema == "" && c.SchemaFile == "" {
+		return fmt.Errorf("%w: allowed_status_codes, schema or schema_file is required", ErrInvalidResponseValidation)
+	}
+	_, err := c.compileSchema()
+	return err
+}
+
+// compileSchema compiles the inline or file schema, returning nil without one.
+func (c *ResponseValidationConfig) compileSchema() (*jsonschema.Schema, error) {
+	source, name := c.Schema, "response-schema.json"
+	if c.SchemaFile != "" {
+		data, err := os.ReadFile(c.SchemaFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading schema_file: %w", ErrInvalidResponseValidation, err)
+		}
+		source, name = string(data), c.SchemaFile
+	}
+	if source == "" {
+		return nil, nil
+	}
+
+	document, err := jsonschema.UnmarshalJSON(strings.NewReader(source))
+	if err != nil {
+		return nil, fmt.Errorf("%w: parsing schema: %w", ErrInvalidResponseValidation, err)
+	}
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource(name, document); err != nil {
+		return nil, fmt.Errorf("%w: loading schema: %w", ErrInvalidResponseValidation, err)
+	}
+	schema, err := compiler.Compile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: compiling schema: %w", ErrInvalidResponseValidation, err)
+	}
+	return schema, nil
+}
+
+// responseValidator validates one route's responses.
+type responseValidator struct {
+	pattern     string
+	statusCodes []int
+	schema      *jsonschema.Schema
+	action      string
+	maxBodySize int64
+}
+
+// resolveResponseValidation builds the validator for every route config with a
+// response_validation block.
+func (m *ReverseProxyModule) resolveResponseValidation() error {
+	m.responseValidators = make(map[string]*responseValidator)
+	for pattern, routeConfig := range m.config.RouteConfigs {
+		config := routeConfig.ResponseValidation
+		if config == nil {
+			continue
+		}
+		schema, err := config.compileSchema()
+		if err != nil {
+			return fmt.Errorf("route %s: %w", pattern, err)
+		}
+		validator := &responseValidator{
+			pattern:     pattern,
+			statusCodes: config.AllowedStatusCodes,
+			schema:      schema,
+			action:      config.OnViolation,
+			maxBodySize: config.MaxBodySize,
+		}
+		if validator.action == "" {
+			validator.action = ResponseValidationLog
+		}
+		if validator.maxBodySize == 0 {
+			validator.maxBodySize = defaultValidationMaxBodySize
+		}
+		m.responseValidators[pattern] = validator
+	}
+	return nil
+}
+
+// withResponseValidation wraps handler to validate its responses as configured for
+// pattern.
+func (m *ReverseProxyModule) withResponseValidation(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	validator := m.responseValidators[pattern]
+	if validator == nil || handler == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		writer := &validatingResponseWriter{ResponseWriter: w, validator: validator, report: func(status int, reason string) {
+			m.reportResponseViolation(r, validator, status, reason)
+		}}
+		handler(writer, r)
+		writer.Close()
+	}
+}
+
+// reportResponseViolation logs and emits a response failing validation.
+func (m *ReverseProxyModule) reportResponseViolation(r *http.Request, validator *responseValidator, status int, reason string) {
+	if validator.action != ResponseValidationTolerate && m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Warn("Backend response failed validation", "route", validator.pattern,
+			"path", sanitizeForLogging(r.URL.Path), "status", status, "reason", reason, "action", validator.action)
+	}
+	m.emitEvent(context.WithoutCancel(r.Context()), EventTypeResponseValidationFailed, map[string]interface{}{
+		"route":  validator.pattern,
+		"path":   r.URL.Path,
+		"method": r.Method,
+		"status": status,
+		"reason": reason,
+		"action": validator.action,
+	})
+}
+
+// validatingResponseWriter checks the status code when the header is written and
+// collects the body for schema validation on Close. Rejecting validators hold the
+// response back until it passed; the others let it through as it is written.
+type validatingResponseWriter struct {
+	http.ResponseWriter
+	validator *responseValidator
+	report    func(status int, reason string)
+
+	status      int
+	wroteHeader bool
+	collecting  bool // body is collected for schema validation
+	holding     bool // headers and body are held back until validated
+	rejected    bool // response was replaced by 502
+	buf         bytes.Buffer
+}
+
+func (w *validatingResponseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	if status < http.StatusOK {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader, w.status = true, status
+
+	if len(w.validator.statusCodes) > 0 && !slices.Contains(w.validator.statusCodes, status) {
+		w.violation(fmt.Sprintf("status %d is not allowed", status))
+		if w.rejected {
+			return
+		}
+	} else if w.validator.schema != nil && w.schemaApplies() {
+		mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+		if mediaType == MediaTypeJSON || strings.HasSuffix(mediaType, "+json") {
+			w.collecting = true
+			w.holding = w.validator.action == ResponseValidationReject
+		} else {
+			w.violation(fmt.Sprintf("content type %q is not JSON", w.Header().Get("Content-Type")))
+			if w.rejected {
+				return
+			}
+		}
+	}
+	if !w.holding {
+		w.ResponseWriter.WriteHeader(status)
+	}
+}
+
+// schemaApplies reports whether the response has a body the schema can be checked
+// against: a 2xx with content, not encoded and within the size limit.
+func (w *validatingResponseWriter) schemaApplies() bool {
+	if w.status < 200 || w.status > 299 || w.status == http.StatusNoContent {
+		return false
+	}
+	h := w.Header()
+	if encoding := h.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+	length, err := strconv.ParseInt(h.Get("Content-Length"), 10, 64)
+	return err != nil || (length > 0 && length <= w.validator.maxBodySize)
+}
+
+func (w *validatingResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.rejected {
+		return len(p), nil
+	}
+	if w.collecting {
+		if int64(w.buf.Len()+len(p)) > w.validator.maxBodySize {
+			// Too large to validate; send what was held back and stop collecting
+			w.collecting = false
+			if err := w.release(); err != nil {
+				return 0, err
+			}
+		} else {
+			w.buf.Write(p)
+			if w.holding {
+				return len(p), nil
+			}
+		}
+	}
+	return w.ResponseWriter.Write(p) //nolint:wrapcheck // passthrough of the underlying writer's error
+}
+
+// Flush is a no-op while a response is held back for validation.
+func (w *validatingResponseWriter) Flush() {
+	if !w.holding && !w.rejected {
+		_ = http.NewResponseController(w.ResponseWriter).Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *validatingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close validates the collected body and writes out a held back response.
+func (w *validatingResponseWriter) Close() {
+	if !w.collecting {
+		return
+	}
+	w.collecting = false
+	if w.buf.Len() > 0 {
+		if reason := w.validateBody(); reason != "" {
+			w.violation(reason)
+		}
+	}
+	if !w.rejected {
+		_ = w.release()
+	}
+}
+
+// validateBody returns why the collected body fails the schema, or "".
+func (w *validatingResponseWriter) validateBody() string {
+	instance, err := jsonschema.UnmarshalJSON(bytes.NewReader(w.buf.Bytes()))
+	if err != nil {
+		return "body is not valid JSON: " + err.Error()
+	}
+	if err := w.validator.schema.Validate(instance); err != nil {
+		return "body does not match schema: " + err.Error()
+	}
+	return ""
+}
+
+// violation reports a violation, replacing the response with 502 when rejecting.
+func (w *validatingResponseWriter) violation(reason string) {
+	w.report(w.status, reason)
+	if w.validator.action != ResponseValidationReject {
+		return
+	}
+	w.rejected, w.holding = true, false
+	w.buf.Reset()
+	h := w.Header()
+	for key := range h {
+		delete(h, key)
+	}
+	http.Error(w.ResponseWriter, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+}
+
+// release writes the held back header and body.
+func (w *validatingResponseWriter) release() error {
+	if !w.holding {
+		return nil
+	}
+	w.holding = false
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	if err != nil {
+		return fmt.Errorf("failed to write response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package reverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "name"],
+	"properties": {"id": {"type": "integer"}, "name": {"type": "string"}}
+}`
+
+func TestResponseValidation_ThroughProxy(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":1,"name":"Ada"}`))
+		case "/users/2":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"2"}`))
+		case "/users/3":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = w.Write([]byte(`<html>maintenance</html>`))
+		case "/users/large":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"padding":"` + strings.Repeat("x", 100) + `"}`))
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer backend.Close()
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"users": backend.URL},
+		RequestTimeout:  10 * time.Second,
+		RouteConfigs: map[string]RouteConfig{
+			"/users/*": {ResponseValidation: &ResponseValidationConfig{
+				AllowedStatusCodes: []int{200, 404},
+				Schema:             userSchema,
+				MaxBodySize:        64,
+			}},
+		},
+	})
+	require.NoError(t, m.resolveResponseValidation())
+	handler := m.withResponseValidation("/users/*", m.createBackendProxyHandler("users"))
+
+	do := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	tests := []struct {
+		path   string
+		action string
+		status int
+		body   string
+	}{
+		{"/users/1", ResponseValidationLog, http.StatusOK, `{"id":1,"name":"Ada"}`},
+		{"/users/1", ResponseValidationReject, http.StatusOK, `{"id":1,"name":"Ada"}`},
+		{"/users/2", ResponseValidationLog, http.StatusOK, `{"id":"2"}`},
+		{"/users/2", ResponseValidationTolerate, http.StatusOK, `{"id":"2"}`},
+		{"/users/2", ResponseValidationReject, http.StatusBadGateway, "Bad Gateway\n"},
+		{"/users/3", ResponseValidationReject, http.StatusBadGateway, "Bad Gateway\n"},
+		{"/users/fail", ResponseValidationLog, http.StatusInternalServerError, "boom\n"},
+		{"/users/fail", ResponseValidationReject, http.StatusBadGateway, "Bad Gateway\n"},
+		// Bodies over the size limit pass unvalidated
+		{"/users/large", ResponseValidationReject, http.StatusOK, `{"padding":"` + strings.Repeat("x", 100) + `"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action+tt.path, func(t *testing.T) {
+			m.responseValidators["/users/*"].action = tt.action
+			rec := do(tt.path)
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, rec.Body.String())
+			if tt.status == http.StatusBadGateway {
+				assert.NotEqual(t, "application/json", rec.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestResponseValidation_EmitsEvent(t *testing.T) {
+	subject := &capturingSubject{}
+	m := NewModule()
+	m.subject = subject
+	m.config = &ReverseProxyConfig{RouteConfigs: map[string]RouteConfig{
+		"/api/*": {ResponseValidation: &ResponseValidationConfig{AllowedStatusCodes: []int{200}}},
+	}}
+	require.NoError(t, m.resolveResponseValidation())
+	handler := m.withResponseValidation("/api/*", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/api/tea", nil))
+	assert.Equal(t, http.StatusTeapot, rec.Code, "violations are only logged by default")
+
+	require.Eventually(t, func() bool {
+		return len(subject.eventsOfType(EventTypeResponseValidationFailed)) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	var data map[string]interface{}
+	require.NoError(t, subject.eventsOfType(EventTypeResponseValidationFailed)[0].DataAs(&data))
+	assert.Equal(t, "/api/*", data["route"])
+	assert.Equal(t, "/api/tea", data["path"])
+	assert.InDelta(t, http.StatusTeapot, data["status"], 0)
+	assert.Equal(t, "status 418 is not allowed", data["reason"])
+	assert.Equal(t, ResponseValidationLog, data["action"])
+}
+
+func TestResponseValidationConfig_Validate(t *testing.T) {
+	schemaFile := filepath.Join(t.TempDir(), "user.json")
+	require.NoError(t, os.WriteFile(schemaFile, []byte(userSchema), 0o600))
+
+	tests := []struct {
+		name   string
+		config ResponseValidationConfig
+		valid  bool
+	}{
+		{"status codes", ResponseValidationConfig{AllowedStatusCodes: []int{200, 204}}, true},
+		{"schema", ResponseValidationConfig{Schema: userSchema, OnViolation: ResponseValidationReject}, true},
+		{"schema file", ResponseValidationConfig{SchemaFile: schemaFile}, true},
+		{"empty", ResponseValidationConfig{}, false},
+		{"unknown action", ResponseValidationConfig{AllowedStatusCodes: []int{200}, OnViolation: "drop"}, false},
+		{"invalid status", ResponseValidationConfig{AllowedStatusCodes: []int{42}}, false},
+		{"negative size", ResponseValidationConfig{Schema: userSchema, MaxBodySize: -1}, false},
+		{"both schemas", ResponseValidationConfig{Schema: userSchema, SchemaFile: schemaFile}, false},
+		{"malformed schema", ResponseValidationConfig{Schema: `{"type":`}, false},
+		{"invalid schema", ResponseValidationConfig{Schema: `{"type":"thing"}`}, false},
+		{"missing file", ResponseValidationConfig{SchemaFile: filepath.Join(t.TempDir(), "missing.json")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidResponseValidation)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package reverseproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// maxRouteMetrics bounds the number of route patterns tracked. Requests for routes
+// beyond the limit, such as routes added dynamically at runtime, are recorded under
+// otherRoutesLabel.
+const maxRouteMetrics = 256
+
+// otherRoutesLabel is the route recorded once maxRouteMetrics patterns are tracked.
+const otherRoutesLabel = "other"
+
+// Histogram bucket upper bounds for route latency in seconds and body sizes in bytes.
+var (
+	routeLatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	routeSizeBuckets    = []float64{100, 1000, 10000, 100000, 1000000, 10000000}
+)
+
+// histogram counts observations into fixed buckets.
+type histogram struct {
+	bounds []float64
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+func (h *histogram) observe(value float64) {
+	h.count++
+	h.sum += value
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+			return
+		}
+	}
+}
+
+// cumulative returns the number of observations at or below each bound.
+func (h *histogram) cumulative() []uint64 {
+	counts := make([]uint64, len(h.counts))
+	var total uint64
+	for i, count := range h.counts {
+		total += count
+		counts[i] = total
+	}
+	return counts
+}
+
+// toMap returns the histogram in the shape used by the JSON metrics output.
+func (h *histogram) toMap() map[string]interface{} {
+	buckets := make(map[string]uint64, len(h.bounds)+1)
+	for i, count := range h.cumulative() {
+		buckets[formatBound(h.bounds[i])] = count
+	}
+	buckets["+Inf"] = h.count
+	return map[string]interface{}{
+		"count":   h.count,
+		"sum":     h.sum,
+		"buckets": buckets,
+	}
+}
+
+// routeMetrics holds the histograms recorded for a route pattern.
+type routeMetrics struct {
+	latency       *histogram
+	requestBytes  *histogram
+	responseBytes *histogram
+}
+
+func newRouteMetrics() *routeMetrics {
+	return &routeMetrics{
+		latency:       newHistogram(routeLatencyBuckets),
+		requestBytes:  newHistogram(routeSizeBuckets),
+		responseBytes: newHistogram(routeSizeBuckets),
+	}
+}
+
+// RecordRouteRequest records the latency and the request and response body sizes
+// of a request served by the route registered under pattern.
+func (m *MetricsCollector) RecordRouteRequest(pattern string, latency time.Duration, requestBytes, responseBytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	route, exists := m.routes[pattern]
+	if !exists {
+		if len(m.routes) >= maxRouteMetrics {
+			pattern = otherRoutesLabel
+			route = m.routes[pattern]
+		}
+		if route == nil {
+			route = newRouteMetrics()
+			m.routes[pattern] = route
+		}
+	}
+	route.latency.observe(latency.Seconds())
+	route.requestBytes.observe(float64(requestBytes))
+	route.responseBytes.observe(float64(responseBytes))
+}
+
+// routeMetricsMap returns per-route metrics for the JSON output. Callers hold m.mu.
+func (m *MetricsCollector) routeMetricsMap() map[string]interface{} {
+	routes := make(map[string]interface{}, len(m.routes))
+	for pattern, route := range m.routes {
+		routes[pattern] = map[string]interface{}{
+			"request_count":        route.latency.count,
+			"latency_seconds":      route.latency.toMap(),
+			"request_size_bytes":   route.requestBytes.toMap(),
+			"response_size_bytes":  route.responseBytes.toMap(),
+			"request_bytes_total":  route.requestBytes.sum,
+			"response_bytes_total": route.responseBytes.sum,
+		}
+	}
+	return routes
+}
+
+// WritePrometheus writes backend counters and per-route histograms in the
+// Prometheus text exposition format.
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	p := newPrometheusWriter(w)
+	m.writePrometheus(p)
+	return p.flush()
+}
+
+// writePrometheus writes backend counters and per-route histograms to p.
+func (m *MetricsCollector) writePrometheus(p *prometheusWriter) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	backends := sortedKeys(m.requestCounts)
+	p.family("reverseproxy_backend_requests_total", "counter")
+	for _, backend := range backends {
+		p.sample("reverseproxy_backend_requests_total", float64(m.requestCounts[backend]), "backend", backend)
+	}
+	p.family("reverseproxy_backend_errors_total", "counter")
+	for _, backend := range backends {
+		p.sample("reverseproxy_backend_errors_total", float64(m.errorCounts[backend]), "backend", backend)
+	}
+
+	patterns := sortedKeys(m.routes)
+	routeHistograms := []struct {
+		name string
+		get  func(*routeMetrics) *histogram
+	}{
+		{"reverseproxy_route_request_duration_seconds", func(r *routeMetrics) *histogram { return r.latency }},
+		{"reverseproxy_route_request_size_bytes", func(r *routeMetrics) *histogram { return r.requestBytes }},
+		{"reverseproxy_route_response_size_bytes", func(r *routeMetrics) *histogram { return r.responseBytes }},
+	}
+	for _, metric := range routeHistograms {
+		p.family(metric.name, "histogram")
+		for _, pattern := range patterns {
+			p.histogram(metric.name, metric.get(m.routes[pattern]), "route", pattern)
+		}
+	}
+}
+
+func formatBound(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// withRouteMetrics records latency and body sizes for requests served by handler
+// under the route pattern when metrics are enabled. A catch-all handler that
+// passes a request on to a configured or composite route records it under that
+// route's pattern with setMatchedRoute.
+func (m *ReverseProxyModule) withRouteMetrics(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.metrics == nil {
+		return handler
+	}
+	metrics := m.metrics
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		route := pattern
+		r = r.WithContext(context.WithValue(r.Context(), matchedRouteKey{}, &route))
+		body := &countingReadCloser{ReadCloser: r.Body}
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = body
+		}
+		writer := &countingResponseWriter{ResponseWriter: w}
+		defer func() {
+			metrics.RecordRouteRequest(route, time.Since(start), body.n.Load(), writer.n)
+		}()
+		handler(writer, r)
+	}
+}
+
+// matchedRouteKey is the context key of the route pattern a request's metrics are
+// recorded under.
+type matchedRouteKey struct{}
+
+// setMatchedRoute records the metrics of r under the route pattern that serves it.
+func setMatchedRoute(r *http.Request, pattern string) {
+	if route, ok := r.Context().Value(matchedRouteKey{}).(*string); ok {
+		*route = pattern
+	}
+}
+
+// countingReadCloser counts the bytes read from a request body. The transport may
+// still be reading the body when the handler gives up on a request.
+type countingReadCloser struct {
+	io.ReadCloser
+	n atomic.Int64
+}
+
+func (c *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n.Add(int64(n))
+	return n, err //nolint:wrapcheck // io.EOF must reach callers unwrapped
+}
+
+// countingResponseWriter counts the bytes written to a response body.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.ResponseWriter.Write(p)
+	c.n += int64(n)
+	return n, err //nolint:wrapcheck // passthrough of the underlying writer's error
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so flushing
+// and connection hijacking keep working for streamed and upgraded responses.
+func (c *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
@@ -0,0 +1,96 @@
+package reverseproxy
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteMetrics_RecordsSizesAndLatencyPerRoute(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write([]byte(strings.Repeat("x", 2*len(body))))
+	}))
+	t.Cleanup(backend.Close)
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  10 * time.Second,
+	})
+	m.metrics = NewMetricsCollector()
+	handler := m.withRouteMetrics("/api/*", m.createBackendProxyHandler("api"))
+
+	for range 2 {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(strings.Repeat("a", 600))))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, 1200, rec.Body.Len())
+	}
+
+	routes, ok := m.metrics.GetMetrics()["routes"].(map[string]interface{})
+	require.True(t, ok)
+	route := routes["/api/*"].(map[string]interface{})
+	assert.Equal(t, uint64(2), route["request_count"])
+	assert.InDelta(t, 1200, route["request_bytes_total"], 0)
+	assert.InDelta(t, 2400, route["response_bytes_total"], 0)
+	responseSizes := route["response_size_bytes"].(map[string]interface{})["buckets"].(map[string]uint64)
+	assert.Equal(t, uint64(0), responseSizes["1000"])
+	assert.Equal(t, uint64(2), responseSizes["10000"])
+
+	rec := httptest.NewRecorder()
+	m.metrics.MetricsHandler()(rec, httptest.NewRequest(http.MethodGet, "/metrics?format=prometheus", nil))
+	assert.Equal(t, PrometheusContentType, rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE reverseproxy_route_request_duration_seconds histogram\n")
+	assert.Contains(t, body, "reverseproxy_route_request_duration_seconds_count{route=\"/api/*\"} 2\n")
+	assert.Contains(t, body, "reverseproxy_route_request_size_bytes_bucket{route=\"/api/*\",le=\"1000\"} 2\n")
+	assert.Contains(t, body, "reverseproxy_route_response_size_bytes_sum{route=\"/api/*\"} 2400\n")
+
+	rec = httptest.NewRecorder()
+	m.metrics.MetricsHandler()(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+}
+
+func TestRouteMetrics_CatchAllRecordsTheMatchedRoute(t *testing.T) {
+	backend := newNamedBackend(t, "api")
+
+	m, _ := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		DefaultBackend:  "api",
+		RequestTimeout:  10 * time.Second,
+	})
+	m.metrics = NewMetricsCollector()
+	m.defaultBackend = "api"
+	handler := m.withRouteMetrics("/*", m.catchAllHandler())
+
+	for _, path := range []string{"/api/users", "/api/orders", "/other"} {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, rec.Code, path)
+	}
+
+	routes := m.metrics.GetMetrics()["routes"].(map[string]interface{})
+	assert.Equal(t, uint64(2), routes["/api/*"].(map[string]interface{})["request_count"])
+	assert.Equal(t, uint64(1), routes["/*"].(map[string]interface{})["request_count"], "only requests for the default backend stay on the catch-all")
+}
+
+func TestRouteMetrics_BoundedCardinality(t *testing.T) {
+	metrics := NewMetricsCollector()
+	for i := range maxRouteMetrics + 10 {
+		metrics.RecordRouteRequest(fmt.Sprintf("/route-%d", i), time.Millisecond, 0, 0)
+	}
+	metrics.RecordRouteRequest("/route-0", time.Millisecond, 0, 0)
+
+	routes := metrics.GetMetrics()["routes"].(map[string]interface{})
+	assert.Len(t, routes, maxRouteMetrics+1)
+	assert.Equal(t, uint64(10), routes[otherRoutesLabel].(map[string]interface{})["request_count"])
+	assert.Equal(t, uint64(2), routes["/route-0"].(map[string]interface{})["request_count"], "tracked routes keep their own series")
+}
@@ -0,0 +1,116 @@
+package reverseproxy
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// RouteMiddlewareProvider is implemented by services that offer named middleware
+// for use in route_configs. Any service in the application's service registry that
+// implements it is consulted when a route references middleware by name, e.g.
+//
+//	route_configs:
+//	  "/api/admin/*":
+//	    middleware: ["auth", "rate-limit"]
+//
+// Middleware values have the same signature as chimux.Middleware.
+type RouteMiddlewareProvider interface {
+	// RouteMiddleware returns middleware keyed by the name routes refer to.
+	RouteMiddleware() map[string]func(http.Handler) http.Handler
+}
+
+// RegisterRouteMiddleware makes middleware available to route_configs under name.
+// Middleware registered here takes precedence over RouteMiddlewareProvider services.
+// It must be called before Start.
+func (m *ReverseProxyModule) RegisterRouteMiddleware(name string, middleware func(http.Handler) http.Handler) {
+	if m.routeMiddleware == nil {
+		m.routeMiddleware = make(map[string]func(http.Handler) http.Handler)
+	}
+	m.routeMiddleware[name] = middleware
+}
+
+// resolveRouteMiddleware builds the middleware chain for every route config that
+// lists middleware. Names are looked up in explicitly registered middleware first
+// and then in RouteMiddlewareProvider services (in service name order). The service
+// registry is only consulted when some route needs middleware.
+func (m *ReverseProxyModule) resolveRouteMiddleware() error {
+	m.routeChains = make(map[string]func(http.Handler) http.Handler)
+
+	var available map[string]func(http.Handler) http.Handler
+	for pattern, routeConfig := range m.config.RouteConfigs {
+		if len(routeConfig.Middleware) == 0 {
+			continue
+		}
+		if available == nil {
+			available = m.availableRouteMiddleware()
+		}
+
+		chain := make([]func(http.Handler) http.Handler, 0, len(routeConfig.Middleware))
+		for _, name := range routeConfig.Middleware {
+			middleware, ok := available[name]
+			if !ok || middleware == nil {
+				return fmt.Errorf("%w: %q for route %s", ErrRouteMiddlewareNotFound, name, pattern)
+			}
+			chain = append(chain, middleware)
+		}
+		m.routeChains[pattern] = func(next http.Handler) http.Handler {
+			// The first listed middleware is the outermost, matching chi's Use order
+			for i := len(chain) - 1; i >= 0; i-- {
+				next = chain[i](next)
+			}
+			return next
+		}
+
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Resolved route middleware", "route", pattern, "middleware", routeConfig.Middleware)
+		}
+	}
+	return nil
+}
+
+// availableRouteMiddleware merges middleware from RouteMiddlewareProvider services
+// with explicitly registered middleware.
+func (m *ReverseProxyModule) availableRouteMiddleware() map[string]func(http.Handler) http.Handler {
+	available := make(map[string]func(http.Handler) http.Handler)
+
+	if m.app != nil {
+		registry := m.app.SvcRegistry()
+		names := make([]string, 0, len(registry))
+		for name := range registry {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, serviceName := range names {
+			provider, ok := registry[serviceName].(RouteMiddlewareProvider)
+			if !ok {
+				continue
+			}
+			for name, middleware := range provider.RouteMiddleware() {
+				if _, exists := available[name]; exists {
+					if m.app.Logger() != nil {
+						m.app.Logger().Warn("Route middleware provided by multiple services, keeping the first",
+							"middleware", name, "ignored_service", serviceName)
+					}
+					continue
+				}
+				available[name] = middleware
+			}
+		}
+	}
+
+	for name, middleware := range m.routeMiddleware {
+		available[name] = middleware
+	}
+	return available
+}
+
+// withRouteMiddleware wraps handler in the middleware chain configured for pattern, if any.
+func (m *ReverseProxyModule) withRouteMiddleware(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	chain, ok := m.routeChains[pattern]
+	if !ok || handler == nil {
+		return handler
+	}
+	return chain(handler).ServeHTTP
+}
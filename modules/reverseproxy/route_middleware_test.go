@@ -0,0 +1,146 @@
+package reverseproxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testMiddlewareProvider offers "auth", which requires an Authorization header,
+// and "trace", which records its name in the X-Middleware response header.
+type testMiddlewareProvider struct{}
+
+func (testMiddlewareProvider) RouteMiddleware() map[string]func(http.Handler) http.Handler {
+	return map[string]func(http.Handler) http.Handler{
+		"auth": func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" {
+					http.Error(w, "unauthorized", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		},
+		"trace": tracingMiddleware("provider-trace"),
+	}
+}
+
+func tracingMiddleware(name string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", name)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// startRouteMiddlewareModule starts a module with the given route configs and returns its router.
+func startRouteMiddlewareModule(t *testing.T, routeConfigs map[string]RouteConfig, setup func(*ReverseProxyModule)) (*testRouter, error) {
+	t.Helper()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	app := NewMockTenantApplication()
+	require.NoError(t, app.RegisterService("middleware.auth", testMiddlewareProvider{}))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		Routes: map[string]string{
+			"/api/admin/*":  "api",
+			"/api/public/*": "api",
+		},
+		RouteConfigs: routeConfigs,
+	}))
+
+	router := &testRouter{routes: make(map[string]http.HandlerFunc)}
+	module := NewModule()
+	require.NoError(t, module.RegisterConfig(app))
+	constructed, err := module.Constructor()(app, map[string]any{"router": router})
+	require.NoError(t, err)
+	m := constructed.(*ReverseProxyModule)
+	require.NoError(t, m.Init(app))
+	if setup != nil {
+		setup(m)
+	}
+	return router, m.Start(app.Context())
+}
+
+func serveRoute(router *testRouter, pattern, path string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	rec := httptest.NewRecorder()
+	router.routes[pattern](rec, req)
+	return rec
+}
+
+func TestRouteMiddleware_AppliedPerRoute(t *testing.T) {
+	router, err := startRouteMiddlewareModule(t, map[string]RouteConfig{
+		"/api/admin/*": {Middleware: []string{"trace", "auth"}},
+	}, nil)
+	require.NoError(t, err)
+
+	rec := serveRoute(router, "/api/admin/*", "/api/admin/users", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, []string{"provider-trace"}, rec.Header().Values("X-Middleware"), "trace runs before auth")
+
+	rec = serveRoute(router, "/api/admin/*", "/api/admin/users", http.Header{"Authorization": {"Bearer token"}})
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Routes without middleware configured are untouched
+	rec = serveRoute(router, "/api/public/*", "/api/public/docs", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Values("X-Middleware"))
+}
+
+func TestRouteMiddleware_RegisteredMiddlewareTakesPrecedence(t *testing.T) {
+	router, err := startRouteMiddlewareModule(t, map[string]RouteConfig{
+		"/api/public/*": {Middleware: []string{"trace", "body-limit"}},
+	}, func(m *ReverseProxyModule) {
+		m.RegisterRouteMiddleware("trace", tracingMiddleware("registered-trace"))
+		m.RegisterRouteMiddleware("body-limit", tracingMiddleware("body-limit"))
+	})
+	require.NoError(t, err)
+
+	rec := serveRoute(router, "/api/public/*", "/api/public/docs", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, []string{"registered-trace", "body-limit"}, rec.Header().Values("X-Middleware"))
+}
+
+func TestRouteMiddleware_UnknownNameFailsStart(t *testing.T) {
+	_, err := startRouteMiddlewareModule(t, map[string]RouteConfig{
+		"/api/admin/*": {Middleware: []string{"auth", "rate-limit"}},
+	}, nil)
+	require.ErrorIs(t, err, ErrRouteMiddlewareNotFound)
+	assert.Contains(t, err.Error(), `"rate-limit"`)
+}
+
+func TestRouteMiddleware_ChainsBuiltOnceAtRegistration(t *testing.T) {
+	var built atomic.Int32
+	router, err := startRouteMiddlewareModule(t, map[string]RouteConfig{
+		"/api/admin/*": {Middleware: []string{"counted"}},
+	}, func(m *ReverseProxyModule) {
+		m.RegisterRouteMiddleware("counted", func(next http.Handler) http.Handler {
+			built.Add(1)
+			return next
+		})
+		m.config.DefaultBackend = "api"
+		m.defaultBackend = "api"
+	})
+	require.NoError(t, err)
+	registered := built.Load()
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serveRoute(router, "/api/admin/*", "/api/admin/users", nil).Code)
+		assert.Equal(t, http.StatusOK, serveRoute(router, "/*", "/api/admin/users", nil).Code, "catch-all serves the route's chain")
+	}
+	assert.Equal(t, registered, built.Load(), "requests don't build middleware chains")
+}
@@ -0,0 +1,252 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// maxScheduledRouteWait bounds how long the rule watcher sleeps, so clock changes
+// delay activation events by at most this much.
+const maxScheduledRouteWait = time.Minute
+
+// ScheduledRouteConfig routes requests matching Pattern to Backend while the rule is
+// active. A rule with a Schedule is active for Duration after each time the cron
+// expression fires; a rule without one is active from Start until End. On a recurring
+// rule, Start and End limit the period in which its windows apply.
+//
+//	scheduled_routes:
+//	  - name: nightly-batch
+//	    pattern: /api/batch/*
+//	    backend: batch
+//	    schedule: "0 1 * * *"
+//	    duration: 4h
+//	    timezone: America/New_York
+//	  - name: deploy-maintenance
+//	    pattern: /*
+//	    backend: maintenance
+//	    start: 2026-10-20T22:00:00Z
+//	    end: 2026-10-20T23:00:00Z
+type ScheduledRouteConfig struct {
+	// Name identifies the rule in events and logs
+	Name string `json:"name" yaml:"name" toml:"name"`
+
+	// Pattern is the route pattern requests must match, e.g. "/api/batch/*"
+	Pattern string `json:"pattern" yaml:"pattern" toml:"pattern"`
+
+	// Backend receives the matching requests while the rule is active
+	Backend string `json:"backend" yaml:"backend" toml:"backend"`
+
+	// Schedule is a standard five-field cron expression, or a descriptor such as
+	// "@daily", for when each window opens
+	Schedule string `json:"schedule" yaml:"schedule" toml:"schedule"`
+
+	// Duration is how long each scheduled window stays open; required with Schedule
+	Duration time.Duration `json:"duration" yaml:"duration" toml:"duration"`
+
+	// Start is when the rule becomes active, or when its schedule starts applying
+	Start time.Time `json:"start" yaml:"start" toml:"start"`
+
+	// End is when the rule stops being active, or when its schedule stops applying
+	End time.Time `json:"end" yaml:"end" toml:"end"`
+
+	// Timezone is the IANA zone the schedule is evaluated in. Defaults to UTC.
+	Timezone string `json:"timezone" yaml:"timezone" toml:"timezone"`
+}
+
+// scheduledRoute is a compiled ScheduledRouteConfig.
+type scheduledRoute struct {
+	ScheduledRouteConfig
+	schedule cron.Schedule
+	location *time.Location
+	handler  http.HandlerFunc
+	active   bool // last state reported by the watcher
+}
+
+// compileScheduledRoutes validates the rules and parses their schedules.
+func compileScheduledRoutes(cfg *ReverseProxyConfig) ([]*scheduledRoute, error) {
+	routes := make([]*scheduledRoute, 0, len(cfg.ScheduledRoutes))
+	names := make(map[string]bool, len(cfg.ScheduledRoutes))
+	for i, rc := range cfg.ScheduledRoutes {
+		if rc.Name == "" {
+			return nil, fmt.Errorf("%w: scheduled_routes[%d]: name is required", ErrInvalidScheduledRoute, i)
+		}
+		if names[rc.Name] {
+			return nil, fmt.Errorf("%w: %s: duplicate name", ErrInvalidScheduledRoute, rc.Name)
+		}
+		names[rc.Name] = true
+		if rc.Pattern == "" {
+			return nil, fmt.Errorf("%w: %s: pattern is required", ErrInvalidScheduledRoute, rc.Name)
+		}
+		if _, ok := cfg.BackendServices[rc.Backend]; !ok {
+			return nil, fmt.Errorf("%w: %s: unknown backend %q", ErrInvalidScheduledRoute, rc.Name, rc.Backend)
+		}
+		if !rc.Start.IsZero() && !rc.End.IsZero() && !rc.End.After(rc.Start) {
+			return nil, fmt.Errorf("%w: %s: end must be after start", ErrInvalidScheduledRoute, rc.Name)
+		}
+
+		route := &scheduledRoute{ScheduledRouteConfig: rc, location: time.UTC}
+		if rc.Timezone != "" {
+			location, err := time.LoadLocation(rc.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: timezone: %w", ErrInvalidScheduledRoute, rc.Name, err)
+			}
+			route.location = location
+		}
+
+		switch {
+		case rc.Schedule != "":
+			schedule, err := cron.ParseStandard(rc.Schedule)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s: schedule: %w", ErrInvalidScheduledRoute, rc.Name, err)
+			}
+			if rc.Duration <= 0 {
+				return nil, fmt.Errorf("%w: %s: a schedule requires a positive duration", ErrInvalidScheduledRoute, rc.Name)
+			}
+			route.schedule = schedule
+		case rc.Start.IsZero() || rc.End.IsZero():
+			return nil, fmt.Errorf("%w: %s: either schedule and duration or start and end are required", ErrInvalidScheduledRoute, rc.Name)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// activeAt reports whether the rule is active at now.
+func (r *scheduledRoute) activeAt(now time.Time) bool {
+	if !r.Start.IsZero() && now.Before(r.Start) {
+		return false
+	}
+	if !r.End.IsZero() && !now.Before(r.End) {
+		return false
+	}
+	if r.schedule == nil {
+		return true
+	}
+	// The window containing now is the first one opening after now-Duration
+	return !r.windowOpenedAfter(now.Add(-r.Duration)).After(now)
+}
+
+// windowOpenedAfter returns the first time the schedule fires after t, in the rule's timezone.
+func (r *scheduledRoute) windowOpenedAfter(t time.Time) time.Time {
+	return r.schedule.Next(t.In(r.location))
+}
+
+// nextChange returns the earliest time after now at which the rule may change state,
+// or the zero time if it never will.
+func (r *scheduledRoute) nextChange(now time.Time) time.Time {
+	var next time.Time
+	consider := func(t time.Time) {
+		if t.After(now) && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	consider(r.Start)
+	consider(r.End)
+	if r.schedule != nil && (r.End.IsZero() || now.Before(r.End)) {
+		consider(r.windowOpenedAfter(now))
+		consider(r.windowOpenedAfter(now.Add(-r.Duration)).Add(r.Duration))
+	}
+	return next
+}
+
+// matchScheduledRoute returns the first rule, in configuration order, that is active
+// at now and whose pattern matches path.
+func (m *ReverseProxyModule) matchScheduledRoute(path string, now time.Time) (*scheduledRoute, bool) {
+	for _, route := range m.scheduledRoutes {
+		if route.handler != nil && m.matchesRoute(path, route.Pattern) && route.activeAt(now) {
+			return route, true
+		}
+	}
+	return nil, false
+}
+
+// withScheduledRoutes sends requests matching an active scheduled route to the rule's
+// backend instead of handler. Health, metrics and debug endpoints are never rerouted.
+func (m *ReverseProxyModule) withScheduledRoutes(handler http.HandlerFunc) http.HandlerFunc {
+	if len(m.scheduledRoutes) == 0 {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.shouldExcludeFromProxy(r.URL.Path) {
+			if route, ok := m.matchScheduledRoute(r.URL.Path, time.Now()); ok {
+				route.handler(w, r)
+				return
+			}
+		}
+		handler(w, r)
+	}
+}
+
+// startScheduledRoutes builds the rules' handlers and watches for rules activating
+// and deactivating until Stop.
+func (m *ReverseProxyModule) startScheduledRoutes(ctx context.Context) {
+	if len(m.scheduledRoutes) == 0 {
+		return
+	}
+	for _, route := range m.scheduledRoutes {
+		route.handler = m.createBackendProxyHandler(route.Backend)
+	}
+
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	m.scheduledRoutesStop = cancel
+	m.scheduledRoutesDone = make(chan struct{})
+	go func() {
+		defer close(m.scheduledRoutesDone)
+		for {
+			wait := maxScheduledRouteWait
+			if next := m.updateScheduledRoutes(ctx, time.Now()); !next.IsZero() {
+				wait = min(time.Until(next), wait)
+			}
+			timer := time.NewTimer(max(wait, 0))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+}
+
+// stopScheduledRoutes stops the rule watcher.
+func (m *ReverseProxyModule) stopScheduledRoutes() {
+	if m.scheduledRoutesStop == nil {
+		return
+	}
+	m.scheduledRoutesStop()
+	<-m.scheduledRoutesDone
+	m.scheduledRoutesStop = nil
+}
+
+// updateScheduledRoutes emits an event for each rule whose state changed and returns
+// the earliest time a rule may change again.
+func (m *ReverseProxyModule) updateScheduledRoutes(ctx context.Context, now time.Time) time.Time {
+	var next time.Time
+	for _, route := range m.scheduledRoutes {
+		if active := route.activeAt(now); active != route.active {
+			route.active = active
+			eventType := EventTypeScheduledRouteDeactivated
+			if active {
+				eventType = EventTypeScheduledRouteActivated
+			}
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Info("Scheduled route changed", "rule", route.Name, "pattern", route.Pattern,
+					"backend", route.Backend, "active", active)
+			}
+			m.emitEvent(ctx, eventType, map[string]interface{}{
+				"rule":    route.Name,
+				"pattern": route.Pattern,
+				"backend": route.Backend,
+				"time":    now.UTC().Format(time.RFC3339Nano),
+			})
+		}
+		if change := route.nextChange(now); !change.IsZero() && (next.IsZero() || change.Before(next)) {
+			next = change
+		}
+	}
+	return next
+}
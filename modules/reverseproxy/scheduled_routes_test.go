@@ -0,0 +1,124 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledRoute_ActiveAt(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	backends := map[string]string{"batch": "http://batch.internal"}
+
+	routes, err := compileScheduledRoutes(&ReverseProxyConfig{
+		BackendServices: backends,
+		ScheduledRoutes: []ScheduledRouteConfig{
+			{Name: "nightly", Pattern: "/api/batch/*", Backend: "batch", Schedule: "0 1 * * *", Duration: 4 * time.Hour, Timezone: "America/New_York"},
+			{Name: "deploy", Pattern: "/*", Backend: "batch",
+				Start: time.Date(2026, 10, 20, 22, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 20, 23, 0, 0, 0, time.UTC)},
+		},
+	})
+	require.NoError(t, err)
+	nightly, deploy := routes[0], routes[1]
+
+	tests := []struct {
+		name  string
+		route *scheduledRoute
+		at    time.Time
+		want  bool
+	}{
+		{"before window", nightly, time.Date(2026, 1, 15, 0, 59, 0, 0, newYork), false},
+		{"window opens", nightly, time.Date(2026, 1, 15, 1, 0, 0, 0, newYork), true},
+		{"inside window", nightly, time.Date(2026, 1, 15, 4, 59, 0, 0, newYork), true},
+		{"window closes", nightly, time.Date(2026, 1, 15, 5, 0, 0, 0, newYork), false},
+		{"timezone applied", nightly, time.Date(2026, 1, 15, 1, 30, 0, 0, time.UTC), false},
+		{"one-off before", deploy, time.Date(2026, 10, 20, 21, 59, 0, 0, time.UTC), false},
+		{"one-off during", deploy, time.Date(2026, 10, 20, 22, 30, 0, 0, time.UTC), true},
+		{"one-off after", deploy, time.Date(2026, 10, 20, 23, 0, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.route.activeAt(tt.at))
+		})
+	}
+
+	assert.Equal(t, time.Date(2026, 1, 15, 1, 0, 0, 0, newYork), nightly.nextChange(time.Date(2026, 1, 15, 0, 0, 0, 0, newYork)).In(newYork))
+	assert.Equal(t, time.Date(2026, 1, 15, 5, 0, 0, 0, newYork), nightly.nextChange(time.Date(2026, 1, 15, 2, 0, 0, 0, newYork)).In(newYork))
+	assert.True(t, deploy.nextChange(time.Date(2026, 10, 21, 0, 0, 0, 0, time.UTC)).IsZero())
+}
+
+func TestCompileScheduledRoutes_Errors(t *testing.T) {
+	start := time.Date(2026, 10, 20, 22, 0, 0, 0, time.UTC)
+	tests := map[string]ScheduledRouteConfig{
+		"missing name":      {Pattern: "/*", Backend: "api", Start: start, End: start.Add(time.Hour)},
+		"unknown backend":   {Name: "r", Pattern: "/*", Backend: "missing", Start: start, End: start.Add(time.Hour)},
+		"bad schedule":      {Name: "r", Pattern: "/*", Backend: "api", Schedule: "every night", Duration: time.Hour},
+		"missing duration":  {Name: "r", Pattern: "/*", Backend: "api", Schedule: "@daily"},
+		"bad timezone":      {Name: "r", Pattern: "/*", Backend: "api", Schedule: "@daily", Duration: time.Hour, Timezone: "Mars/Olympus"},
+		"end before start":  {Name: "r", Pattern: "/*", Backend: "api", Start: start, End: start},
+		"no window defined": {Name: "r", Pattern: "/*", Backend: "api"},
+	}
+	for name, rc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := compileScheduledRoutes(&ReverseProxyConfig{
+				BackendServices: map[string]string{"api": "http://api.internal"},
+				ScheduledRoutes: []ScheduledRouteConfig{rc},
+			})
+			require.ErrorIs(t, err, ErrInvalidScheduledRoute)
+		})
+	}
+}
+
+func TestScheduledRoutes_RerouteAndEvents(t *testing.T) {
+	api := newNamedBackend(t, "api")
+	batch := newNamedBackend(t, "batch")
+	now := time.Now()
+
+	subject := &capturingSubject{}
+	m := NewModule()
+	m.subject = subject
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": api.URL, "batch": batch.URL},
+		RequestTimeout:  5 * time.Second,
+		ScheduledRoutes: []ScheduledRouteConfig{{
+			Name: "batch-window", Pattern: "/api/batch/*", Backend: "batch",
+			Start: now.Add(-time.Hour), End: now.Add(time.Hour),
+		}},
+	}
+	routes, err := compileScheduledRoutes(m.config)
+	require.NoError(t, err)
+	m.scheduledRoutes = routes
+	require.NoError(t, m.createBackendProxy("api", api.URL))
+	require.NoError(t, m.createBackendProxy("batch", batch.URL))
+
+	m.startScheduledRoutes(context.Background())
+	t.Cleanup(m.stopScheduledRoutes)
+	handler := m.withScheduledRoutes(m.createBackendProxyHandler("api"))
+
+	serve := func(path string) string {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Body.String()
+	}
+	assert.Equal(t, "batch", serve("/api/batch/jobs"))
+	assert.Equal(t, "api", serve("/api/users"))
+
+	require.Eventually(t, func() bool {
+		return len(subject.eventsOfType(EventTypeScheduledRouteActivated)) == 1
+	}, time.Second, 5*time.Millisecond)
+	m.stopScheduledRoutes()
+
+	m.updateScheduledRoutes(context.Background(), now.Add(2*time.Hour))
+	events := subject.eventsOfType(EventTypeScheduledRouteDeactivated)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "batch-window", data["rule"])
+	assert.Equal(t, "/api/batch/*", data["pattern"])
+}
@@ -83,7 +83,7 @@ func TestServiceDependencyConfiguration(t *testing.T) {
 
 	// Get service dependencies
 	dependencies := serviceAware.RequiresServices()
-	require.Len(t, dependencies, 3, "reverseproxy should declare 3 service dependencies")
+	require.Len(t, dependencies, 6, "reverseproxy should declare 6 service dependencies")
 
 	// Map dependencies by name for easy checking
 	depMap := make(map[string]modular.ServiceDependency)
@@ -110,6 +110,24 @@ func TestServiceDependencyConfiguration(t *testing.T) {
 	assert.False(t, featureFlagDep.Required, "featureFlagEvaluator dependency should be optional")
 	assert.True(t, featureFlagDep.MatchByInterface, "featureFlagEvaluator dependency should use interface matching")
 	assert.NotNil(t, featureFlagDep.SatisfiesInterface, "featureFlagEvaluator dependency should specify interface")
+
+	// Check scheduler dependency (optional, name-based)
+	schedulerDep, exists := depMap[SchedulerServiceName]
+	assert.True(t, exists, "scheduler dependency should exist")
+	assert.False(t, schedulerDep.Required, "scheduler dependency should be optional")
+
+	// Check backendURLResolver dependency (optional, interface-based)
+	resolverDep, exists := depMap["backendURLResolver"]
+	assert.True(t, exists, "backendURLResolver dependency should exist")
+	assert.False(t, resolverDep.Required, "backendURLResolver dependency should be optional")
+	assert.True(t, resolverDep.MatchByInterface, "backendURLResolver dependency should use interface matching")
+	assert.NotNil(t, resolverDep.SatisfiesInterface, "backendURLResolver dependency should specify interface")
+
+	// Check cache dependency (optional, name-based)
+	cacheDep, exists := depMap["cache.provider"]
+	assert.True(t, exists, "cache.provider dependency should exist")
+	assert.False(t, cacheDep.Required, "cache.provider dependency should be optional")
+	assert.False(t, cacheDep.MatchByInterface, "cache.provider dependency should use name-based matching")
 }
 
 // testLoggerDep is a simple test logger implementation
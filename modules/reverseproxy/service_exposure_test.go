@@ -102,9 +102,9 @@ func TestFeatureFlagEvaluatorServiceExposure(t *testing.T) {
 			providedServices := module.ProvidesServices()
 
 			if tt.expectService {
-				// Should provide two services (reverseproxy.provider + featureFlagEvaluator)
-				if len(providedServices) != 2 {
-					t.Errorf("Expected 2 provided services, got %d", len(providedServices))
+				// Should provide three services (reverseproxy.provider + reverseproxy.backendSelector + featureFlagEvaluator)
+				if len(providedServices) != 3 {
+					t.Errorf("Expected 3 provided services, got %d", len(providedServices))
 					return
 				}
 
@@ -151,9 +151,9 @@ func TestFeatureFlagEvaluatorServiceExposure(t *testing.T) {
 				}
 
 			} else {
-				// Should provide only one service (reverseproxy.provider)
-				if len(providedServices) != 1 {
-					t.Errorf("Expected 1 provided service, got %d", len(providedServices))
+				// Should provide only the module services (reverseproxy.provider + reverseproxy.backendSelector)
+				if len(providedServices) != 2 {
+					t.Errorf("Expected 2 provided services, got %d", len(providedServices))
 					return
 				}
 
@@ -273,10 +273,10 @@ func TestFeatureFlagEvaluatorServiceDependencyResolution(t *testing.T) {
 		t.Error("Expected internal flag to be true via aggregator fallback")
 	}
 
-	// The module should still provide both services (reverseproxy.provider + external evaluator)
+	// The module should still provide its services (reverseproxy.provider + reverseproxy.backendSelector + external evaluator)
 	providedServices := module.ProvidesServices()
-	if len(providedServices) != 2 {
-		t.Errorf("Expected 2 provided services, got %d", len(providedServices))
+	if len(providedServices) != 3 {
+		t.Errorf("Expected 3 provided services, got %d", len(providedServices))
 		return
 	}
 
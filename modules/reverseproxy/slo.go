@@ -0,0 +1,422 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults applied to SLOConfig fields left unset.
+const (
+	defaultSLOWindow      = time.Hour
+	defaultSLOMinRequests = 100
+)
+
+// sloBucketCount is the number of buckets a rolling SLO window is divided into.
+const sloBucketCount = 60
+
+// SLO kinds, identifying whether an objective tracks a backend or a route pattern.
+const (
+	SLOKindBackend = "backend"
+	SLOKindRoute   = "route"
+)
+
+// SLOConfig configures service level objectives for backends and routes. Requests are
+// counted over a rolling window; a 5xx response counts against the availability
+// target and a response slower than the latency target counts against the latency
+// objective, which allows 1% of requests to be slower than its p99 target. An
+// objective whose error budget runs out emits slo.budget.exhausted, and
+// slo.budget.recovered once it is back within budget.
+//
+//	slo:
+//	  enabled: true
+//	  window: 1h
+//	  backends:
+//	    api:
+//	      availability: 99.9
+//	      latency_p99: 500ms
+//	  routes:
+//	    /api/checkout:
+//	      availability: 99.95
+type SLOConfig struct {
+	// Enabled turns on SLO tracking
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"SLO_ENABLED"`
+
+	// Window is the rolling window error budgets are computed over. Default 1h.
+	Window time.Duration `json:"window" yaml:"window" toml:"window" env:"SLO_WINDOW"`
+
+	// MinRequests is the number of requests an objective needs within its window
+	// before its budget can be considered exhausted. Default 100.
+	MinRequests int `json:"min_requests" yaml:"min_requests" toml:"min_requests" env:"SLO_MIN_REQUESTS"`
+
+	// Backends maps backend IDs to their objectives
+	Backends map[string]SLOTarget `json:"backends" yaml:"backends" toml:"backends"`
+
+	// Routes maps route patterns, as registered with the router, to their objectives
+	Routes map[string]SLOTarget `json:"routes" yaml:"routes" toml:"routes"`
+}
+
+// SLOTarget is a service level objective for a backend or route.
+type SLOTarget struct {
+	// Availability is the percentage of requests that must not fail with a 5xx
+	// response, such as 99.9. Zero disables the availability objective.
+	Availability float64 `json:"availability" yaml:"availability" toml:"availability"`
+
+	// LatencyP99 is the latency 99% of requests must stay under. Zero disables the
+	// latency objective.
+	LatencyP99 time.Duration `json:"latency_p99" yaml:"latency_p99" toml:"latency_p99"`
+
+	// Window overrides SLOConfig.Window for this objective
+	Window time.Duration `json:"window" yaml:"window" toml:"window"`
+}
+
+// validate checks the window, minimum request count and every target.
+func (c *SLOConfig) validate() error {
+	if c.Window < 0 {
+		return fmt.Errorf("%w: window %s is negative", ErrInvalidSLOConfig, c.Window)
+	}
+	if c.MinRequests < 0 {
+		return fmt.Errorf("%w: min_requests %d is negative", ErrInvalidSLOConfig, c.MinRequests)
+	}
+	for backendID, target := range c.Backends {
+		if err := target.validate(); err != nil {
+			return fmt.Errorf("backend %s: %w", backendID, err)
+		}
+	}
+	for pattern, target := range c.Routes {
+		if err := target.validate(); err != nil {
+			return fmt.Errorf("route %s: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// validate checks that the target sets at least one objective within range.
+func (t SLOTarget) validate() error {
+	if t.Availability < 0 || t.Availability >= 100 {
+		return fmt.Errorf("%w: availability %g must be at least 0 and below 100", ErrInvalidSLOConfig, t.Availability)
+	}
+	if t.LatencyP99 < 0 {
+		return fmt.Errorf("%w: latency_p99 %s is negative", ErrInvalidSLOConfig, t.LatencyP99)
+	}
+	if t.Window < 0 {
+		return fmt.Errorf("%w: window %s is negative", ErrInvalidSLOConfig, t.Window)
+	}
+	if t.Availability == 0 && t.LatencyP99 == 0 {
+		return fmt.Errorf("%w: target sets neither availability nor latency_p99", ErrInvalidSLOConfig)
+	}
+	return nil
+}
+
+// SLOStatus reports an objective's performance over its current window. Budgets
+// are the fraction of allowed failures or slow requests still unspent, from 1 when
+// untouched down to 0 when exhausted.
+type SLOStatus struct {
+	Kind                 string        `json:"kind"`
+	Name                 string        `json:"name"`
+	Window               time.Duration `json:"window"`
+	Requests             uint64        `json:"requests"`
+	Errors               uint64        `json:"errors"`
+	SlowRequests         uint64        `json:"slow_requests"`
+	AvailabilityTarget   float64       `json:"availability_target,omitempty"`
+	Availability         float64       `json:"availability"`
+	ErrorBudgetRemaining float64       `json:"error_budget_remaining"`
+	LatencyP99Target     time.Duration `json:"latency_p99_target,omitempty"`
+	LatencyBudget        float64       `json:"latency_budget_remaining"`
+	Exhausted            bool          `json:"exhausted"`
+}
+
+// eventData returns the status in the shape used by SLO event payloads.
+func (s SLOStatus) eventData() map[string]interface{} {
+	data := map[string]interface{}{
+		"kind":                     s.Kind,
+		"name":                     s.Name,
+		"window":                   s.Window.String(),
+		"requests":                 s.Requests,
+		"errors":                   s.Errors,
+		"slow_requests":            s.SlowRequests,
+		"availability":             s.Availability,
+		"error_budget_remaining":   s.ErrorBudgetRemaining,
+		"latency_budget_remaining": s.LatencyBudget,
+	}
+	if s.AvailabilityTarget > 0 {
+		data["availability_target"] = s.AvailabilityTarget
+	}
+	if s.LatencyP99Target > 0 {
+		data["latency_p99_target"] = s.LatencyP99Target.String()
+	}
+	return data
+}
+
+// sloBucket counts the requests of one slice of a rolling window.
+type sloBucket struct {
+	start    int64 // bucket start in nanoseconds since the epoch
+	requests uint64
+	errors   uint64
+	slow     uint64
+}
+
+// sloObjective tracks one backend's or route's requests against its target.
+type sloObjective struct {
+	kind        string
+	name        string
+	target      SLOTarget
+	window      time.Duration
+	bucketSize  time.Duration
+	minRequests uint64
+
+	mu        sync.Mutex
+	buckets   [sloBucketCount]sloBucket
+	exhausted bool
+}
+
+func newSLOObjective(kind, name string, target SLOTarget, window time.Duration, minRequests int) *sloObjective {
+	if target.Window > 0 {
+		window = target.Window
+	}
+	bucketSize := window / sloBucketCount
+	if bucketSize <= 0 {
+		bucketSize = time.Nanosecond
+	}
+	return &sloObjective{
+		kind:        kind,
+		name:        name,
+		target:      target,
+		window:      window,
+		bucketSize:  bucketSize,
+		minRequests: uint64(minRequests), //nolint:gosec // validated non-negative
+	}
+}
+
+// record counts a request and reports the objective's status along with whether
+// the request exhausted the budget or brought it back within budget.
+func (o *sloObjective) record(now time.Time, status int, latency time.Duration) (SLOStatus, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	start := now.UnixNano() - now.UnixNano()%int64(o.bucketSize)
+	bucket := &o.buckets[(start/int64(o.bucketSize))%sloBucketCount]
+	if bucket.start != start {
+		*bucket = sloBucket{start: start}
+	}
+	bucket.requests++
+	if status >= http.StatusInternalServerError {
+		bucket.errors++
+	}
+	if o.target.LatencyP99 > 0 && latency > o.target.LatencyP99 {
+		bucket.slow++
+	}
+
+	current := o.statusLocked(now)
+	changed := current.Exhausted != o.exhausted
+	o.exhausted = current.Exhausted
+	return current, changed
+}
+
+// status returns the objective's status over the window ending at now.
+func (o *sloObjective) status(now time.Time) SLOStatus {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.statusLocked(now)
+}
+
+func (o *sloObjective) statusLocked(now time.Time) SLOStatus {
+	s := SLOStatus{
+		Kind:               o.kind,
+		Name:               o.name,
+		Window:             o.window,
+		AvailabilityTarget: o.target.Availability,
+		LatencyP99Target:   o.target.LatencyP99,
+	}
+	oldest := now.Add(-o.window).UnixNano()
+	for _, bucket := range o.buckets {
+		if bucket.start <= oldest {
+			continue
+		}
+		s.Requests += bucket.requests
+		s.Errors += bucket.errors
+		s.SlowRequests += bucket.slow
+	}
+
+	s.Availability = 100
+	s.ErrorBudgetRemaining = 1
+	s.LatencyBudget = 1
+	if s.Requests == 0 {
+		return s
+	}
+	s.Availability = 100 * float64(s.Requests-s.Errors) / float64(s.Requests)
+	if o.target.Availability > 0 {
+		s.ErrorBudgetRemaining = budgetRemaining(s.Errors, s.Requests, 1-o.target.Availability/100)
+	}
+	if o.target.LatencyP99 > 0 {
+		s.LatencyBudget = budgetRemaining(s.SlowRequests, s.Requests, 0.01)
+	}
+	s.Exhausted = s.Requests >= o.minRequests && (s.ErrorBudgetRemaining <= 0 || s.LatencyBudget <= 0)
+	return s
+}
+
+// budgetRemaining returns the unspent fraction of a budget allowing the given ratio
+// of bad requests, clamped at zero.
+func budgetRemaining(bad, total uint64, allowedRatio float64) float64 {
+	allowed := float64(total) * allowedRatio
+	if allowed <= 0 {
+		if bad > 0 {
+			return 0
+		}
+		return 1
+	}
+	return math.Max(0, 1-float64(bad)/allowed)
+}
+
+// sloTracker holds the objectives configured for backends and routes.
+type sloTracker struct {
+	backends map[string]*sloObjective
+	routes   map[string]*sloObjective
+	now      func() time.Time
+}
+
+// newSLOTracker returns a tracker for the configured objectives, or nil when SLO
+// tracking is disabled.
+func newSLOTracker(config SLOConfig) *sloTracker {
+	if !config.Enabled {
+		return nil
+	}
+	window := config.Window
+	if window <= 0 {
+		window = defaultSLOWindow
+	}
+	minRequests := config.MinRequests
+	if minRequests == 0 {
+		minRequests = defaultSLOMinRequests
+	}
+
+	t := &sloTracker{
+		backends: make(map[string]*sloObjective, len(config.Backends)),
+		routes:   make(map[string]*sloObjective, len(config.Routes)),
+		now:      time.Now,
+	}
+	for backendID, target := range config.Backends {
+		t.backends[backendID] = newSLOObjective(SLOKindBackend, backendID, target, window, minRequests)
+	}
+	for pattern, target := range config.Routes {
+		t.routes[pattern] = newSLOObjective(SLOKindRoute, pattern, target, window, minRequests)
+	}
+	return t
+}
+
+// statuses returns the status of every objective, backends first, sorted by name.
+func (t *sloTracker) statuses() []SLOStatus {
+	now := t.now()
+	statuses := make([]SLOStatus, 0, len(t.backends)+len(t.routes))
+	for _, objectives := range []map[string]*sloObjective{t.backends, t.routes} {
+		for _, name := range sortedKeys(objectives) {
+			statuses = append(statuses, objectives[name].status(now))
+		}
+	}
+	return statuses
+}
+
+// writePrometheus writes each objective's request counts and remaining budgets to p.
+func (t *sloTracker) writePrometheus(p *prometheusWriter) {
+	statuses := t.statuses()
+	gauges := []struct {
+		name string
+		get  func(SLOStatus) float64
+	}{
+		{"reverseproxy_slo_requests", func(s SLOStatus) float64 { return float64(s.Requests) }},
+		{"reverseproxy_slo_errors", func(s SLOStatus) float64 { return float64(s.Errors) }},
+		{"reverseproxy_slo_slow_requests", func(s SLOStatus) float64 { return float64(s.SlowRequests) }},
+		{"reverseproxy_slo_availability_percent", func(s SLOStatus) float64 { return s.Availability }},
+		{"reverseproxy_slo_error_budget_remaining", func(s SLOStatus) float64 { return s.ErrorBudgetRemaining }},
+		{"reverseproxy_slo_latency_budget_remaining", func(s SLOStatus) float64 { return s.LatencyBudget }},
+	}
+	for _, gauge := range gauges {
+		p.family(gauge.name, "gauge")
+		for _, s := range statuses {
+			p.sample(gauge.name, gauge.get(s), "kind", s.Kind, "name", s.Name)
+		}
+	}
+}
+
+// SLOStatus returns the status of every configured service level objective, or nil
+// when SLO tracking is disabled.
+func (m *ReverseProxyModule) SLOStatus() []SLOStatus {
+	if m.slo == nil {
+		return nil
+	}
+	return m.slo.statuses()
+}
+
+// withBackendSLO records requests handled by handler against the backend's objective.
+func (m *ReverseProxyModule) withBackendSLO(backend string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.slo == nil || m.slo.backends[backend] == nil {
+		return handler
+	}
+	return m.withSLO(m.slo.backends[backend], handler)
+}
+
+// withRouteSLO records requests served under the route pattern against its objective.
+func (m *ReverseProxyModule) withRouteSLO(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	if m.slo == nil || m.slo.routes[pattern] == nil {
+		return handler
+	}
+	return m.withSLO(m.slo.routes[pattern], handler)
+}
+
+func (m *ReverseProxyModule) withSLO(objective *sloObjective, handler http.HandlerFunc) http.HandlerFunc {
+	now := m.slo.now
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := now()
+		writer := &statusRecordingWriter{ResponseWriter: w, status: http.StatusOK}
+		handler(writer, r)
+		end := now()
+		status, changed := objective.record(end, writer.status, end.Sub(start))
+		if changed {
+			m.sloBudgetChanged(r.Context(), status)
+		}
+	}
+}
+
+// sloBudgetChanged logs and emits an objective exhausting or recovering its budget.
+func (m *ReverseProxyModule) sloBudgetChanged(ctx context.Context, status SLOStatus) {
+	eventType := EventTypeSLOBudgetRecovered
+	if status.Exhausted {
+		eventType = EventTypeSLOBudgetExhausted
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Warn("SLO error budget exhausted", "kind", status.Kind, "name", status.Name,
+				"availability", status.Availability, "error_budget_remaining", status.ErrorBudgetRemaining,
+				"latency_budget_remaining", status.LatencyBudget)
+		}
+	} else if m.app != nil && m.app.Logger() != nil {
+		m.app.Logger().Info("SLO error budget recovered", "kind", status.Kind, "name", status.Name)
+	}
+	m.emitEvent(ctx, eventType, status.eventData())
+}
+
+// statusRecordingWriter records the status code written to a response.
+type statusRecordingWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (s *statusRecordingWriter) WriteHeader(code int) {
+	if !s.wroteHeader {
+		s.status = code
+		s.wroteHeader = true
+	}
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecordingWriter) Write(p []byte) (int, error) {
+	s.wroteHeader = true
+	return s.ResponseWriter.Write(p) //nolint:wrapcheck // passthrough of the underlying writer's error
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (s *statusRecordingWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,207 @@
+package reverseproxy
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sloTestClock is a manually advanced clock for SLO trackers.
+type sloTestClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *sloTestClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *sloTestClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestSLOObjective_ErrorBudget(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	o := newSLOObjective(SLOKindBackend, "api", SLOTarget{Availability: 99}, time.Hour, 10)
+
+	for i := 0; i < 199; i++ {
+		_, changed := o.record(now, http.StatusOK, time.Millisecond)
+		assert.False(t, changed)
+	}
+	status, changed := o.record(now, http.StatusBadGateway, time.Millisecond)
+	assert.False(t, changed, "one error in 200 requests spends half the budget")
+	assert.InDelta(t, 0.5, status.ErrorBudgetRemaining, 0.0001)
+	assert.InDelta(t, 99.5, status.Availability, 0.0001)
+
+	o.record(now, http.StatusBadGateway, time.Millisecond)
+	status, changed = o.record(now, http.StatusServiceUnavailable, time.Millisecond)
+	assert.True(t, changed, "three errors in 202 requests exceed the 1% budget")
+	assert.True(t, status.Exhausted)
+	assert.Equal(t, uint64(202), status.Requests)
+	assert.Equal(t, uint64(3), status.Errors)
+	assert.Zero(t, status.ErrorBudgetRemaining)
+
+	// Once the window has rolled past the errors the budget is restored
+	status, changed = o.record(now.Add(time.Hour+time.Minute), http.StatusOK, time.Millisecond)
+	assert.True(t, changed)
+	assert.False(t, status.Exhausted)
+	assert.Equal(t, uint64(1), status.Requests)
+	assert.InDelta(t, 1, status.ErrorBudgetRemaining, 0)
+}
+
+func TestSLOObjective_LatencyBudget(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	o := newSLOObjective(SLOKindRoute, "/api/*", SLOTarget{LatencyP99: 100 * time.Millisecond}, time.Minute, 10)
+
+	for i := 0; i < 99; i++ {
+		o.record(now, http.StatusOK, 10*time.Millisecond)
+	}
+	status, changed := o.record(now, http.StatusInternalServerError, 150*time.Millisecond)
+	assert.True(t, changed)
+	assert.True(t, status.Exhausted)
+	assert.Equal(t, uint64(1), status.SlowRequests)
+	assert.InDelta(t, 1, status.ErrorBudgetRemaining, 0, "availability is not an objective of this target")
+	assert.Zero(t, status.LatencyBudget)
+}
+
+func TestSLOObjective_MinRequests(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	o := newSLOObjective(SLOKindBackend, "api", SLOTarget{Availability: 99.9}, time.Hour, 10)
+
+	for i := 0; i < 9; i++ {
+		status, changed := o.record(now, http.StatusBadGateway, time.Millisecond)
+		assert.False(t, changed)
+		assert.False(t, status.Exhausted)
+	}
+	status, changed := o.record(now, http.StatusBadGateway, time.Millisecond)
+	assert.True(t, changed)
+	assert.True(t, status.Exhausted)
+}
+
+func TestSLO_BackendBudgetEvents(t *testing.T) {
+	var failing atomic.Bool
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(backend.Close)
+
+	m, subject := newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": backend.URL},
+		RequestTimeout:  5 * time.Second,
+		SLO: SLOConfig{
+			Enabled:     true,
+			Window:      time.Minute,
+			MinRequests: 5,
+			Backends:    map[string]SLOTarget{"api": {Availability: 90}},
+		},
+	})
+	require.NoError(t, m.validateConfig())
+	m.slo = newSLOTracker(m.config.SLO)
+	clock := &sloTestClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	m.slo.now = clock.Now
+	handler := m.createBackendProxyHandler("api")
+
+	serve := func(n int) {
+		for i := 0; i < n; i++ {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		}
+	}
+
+	serve(10)
+	failing.Store(true)
+	serve(2)
+	require.Len(t, subject.eventsOfType(EventTypeSLOBudgetExhausted), 1)
+	serve(2)
+	require.Len(t, subject.eventsOfType(EventTypeSLOBudgetExhausted), 1, "emitted once per exhaustion")
+
+	var data map[string]interface{}
+	require.NoError(t, subject.eventsOfType(EventTypeSLOBudgetExhausted)[0].DataAs(&data))
+	assert.Equal(t, SLOKindBackend, data["kind"])
+	assert.Equal(t, "api", data["name"])
+	assert.InDelta(t, 90, data["availability_target"], 0)
+
+	statuses := m.SLOStatus()
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Exhausted)
+	assert.Equal(t, uint64(14), statuses[0].Requests)
+	assert.Equal(t, uint64(4), statuses[0].Errors)
+
+	failing.Store(false)
+	clock.Advance(2 * time.Minute)
+	serve(1)
+	assert.Len(t, subject.eventsOfType(EventTypeSLOBudgetRecovered), 1)
+	assert.False(t, m.SLOStatus()[0].Exhausted)
+}
+
+func TestSLO_RouteObjectiveAndDebugEndpoint(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		SLO: SLOConfig{
+			Enabled: true,
+			Routes:  map[string]SLOTarget{"/api/*": {Availability: 99.9, LatencyP99: time.Second}},
+		},
+	}
+	m.slo = newSLOTracker(m.config.SLO)
+
+	handler := m.withRouteSLO("/api/*", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+
+	// Routes without an objective are left unwrapped
+	other := m.withRouteSLO("/other", func(w http.ResponseWriter, r *http.Request) {})
+	other(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+
+	debug := NewDebugHandler(DebugEndpointsConfig{Enabled: true}, nil, m.config, nil, &testLogger{})
+	debug.SetSLOStatusFunc(m.SLOStatus)
+	rec := httptest.NewRecorder()
+	debug.HandleSLO(rec, httptest.NewRequest(http.MethodGet, "/debug/slo", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response struct {
+		SLO []SLOStatus `json:"slo"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Len(t, response.SLO, 1)
+	assert.Equal(t, SLOKindRoute, response.SLO[0].Kind)
+	assert.Equal(t, "/api/*", response.SLO[0].Name)
+	assert.Equal(t, uint64(2), response.SLO[0].Errors)
+	assert.Equal(t, time.Hour, response.SLO[0].Window)
+}
+
+func TestSLOConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config SLOConfig
+	}{
+		{"negative window", SLOConfig{Window: -time.Minute}},
+		{"negative min requests", SLOConfig{MinRequests: -1}},
+		{"availability of 100", SLOConfig{Backends: map[string]SLOTarget{"api": {Availability: 100}}}},
+		{"negative latency", SLOConfig{Routes: map[string]SLOTarget{"/api": {LatencyP99: -time.Second}}}},
+		{"empty target", SLOConfig{Routes: map[string]SLOTarget{"/api": {}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, tt.config.validate(), ErrInvalidSLOConfig)
+		})
+	}
+	valid := SLOConfig{Enabled: true, Window: time.Hour, Backends: map[string]SLOTarget{"api": {Availability: 99.9, LatencyP99: time.Second}}}
+	assert.NoError(t, valid.validate())
+}
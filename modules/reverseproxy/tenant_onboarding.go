@@ -0,0 +1,303 @@
+package reverseproxy
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// defaultTenantOnboardingBasePath is where the onboarding API is mounted when BasePath is unset.
+const defaultTenantOnboardingBasePath = "/admin/tenants"
+
+// maxTenantOnboardingBody limits the size of a tenant configuration posted to the onboarding API.
+const maxTenantOnboardingBody = 1 << 20
+
+// TenantOnboardingConfig configures the HTTP API for registering tenants at runtime.
+// Once enabled, PUT or POST {base_path}/{tenantID} with the tenant's reverseproxy
+// configuration as JSON registers the tenant, as RegisterTenant does.
+//
+// The endpoint requires a bearer token by default, since registered tenants can point
+// the proxy at arbitrary URLs.
+//
+//	tenant_onboarding:
+//	  enabled: true
+//	  base_path: /admin/tenants
+//	  auth_token: ${ONBOARDING_TOKEN}
+type TenantOnboardingConfig struct {
+	// Enabled registers the onboarding endpoint
+	Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled" env:"TENANT_ONBOARDING_ENABLED" default:"false"`
+
+	// BasePath is the path the endpoint is mounted under
+	BasePath string `json:"base_path" yaml:"base_path" toml:"base_path" env:"TENANT_ONBOARDING_BASE_PATH" default:"/admin/tenants"`
+
+	// RequireAuth requires an "Authorization: Bearer <auth_token>" header. Defaults to true.
+	RequireAuth bool `json:"require_auth" yaml:"require_auth" toml:"require_auth" env:"TENANT_ONBOARDING_REQUIRE_AUTH" default:"true"`
+
+	// AuthToken is the token required by the endpoint. It is checked whenever it is
+	// set, even with RequireAuth off.
+	AuthToken string `json:"auth_token" yaml:"auth_token" toml:"auth_token" env:"TENANT_ONBOARDING_AUTH_TOKEN"` //nolint:gosec // G117: auth_token is an endpoint configuration field, not a credential
+}
+
+// validate checks that an authenticated endpoint has a token to compare against.
+func (c *TenantOnboardingConfig) validate() error {
+	if c.Enabled && c.RequireAuth && c.AuthToken == "" {
+		return fmt.Errorf("%w: require_auth is set but auth_token is empty", ErrInvalidTenantOnboardingConfig)
+	}
+	return nil
+}
+
+// basePath returns the configured base path without a trailing slash.
+func (c *TenantOnboardingConfig) basePath() string {
+	if c.BasePath == "" {
+		return defaultTenantOnboardingBasePath
+	}
+	return strings.TrimSuffix(c.BasePath, "/")
+}
+
+// RegisterTenant registers a tenant with its reverseproxy configuration while the
+// application is running. It validates the configuration merged with the global one,
+// registers the tenant with the application's TenantService, builds the tenant's
+// backend proxies and client certificates, registers routes that only this tenant
+// defines and emits EventTypeTenantActivated. Registering a known tenant again
+// replaces its configuration and proxies.
+//
+// Validation errors wrap ErrConfigValidationFailed or ErrTenantTLSConfig and leave
+// the tenant untouched.
+func (m *ReverseProxyModule) RegisterTenant(ctx context.Context, tenantID modular.TenantID, cfg *ReverseProxyConfig) error {
+	if tenantID == "" {
+		return ErrTenantIDEmpty
+	}
+	if cfg == nil {
+		return ErrConfigurationNil
+	}
+	if m.config == nil || m.app == nil {
+		return ErrConfigurationNotLoaded
+	}
+
+	merged := mergeConfigs(m.config, cfg)
+	report := &ConfigValidationReport{}
+	m.validateConfigFull(merged, tenantID, report)
+	if err := report.Err(); err != nil {
+		return err
+	}
+	for _, backendID := range sortedKeys(cfg.BackendConfigs) {
+		if clientTLS := cfg.BackendConfigs[backendID].ClientTLS; clientTLS != nil {
+			if _, err := buildClientTLSConfig(clientTLS, m.tlsSecretResolver); err != nil {
+				return fmt.Errorf("%w: backend %s: %w", ErrTenantTLSConfig, backendID, err)
+			}
+		}
+	}
+
+	var tenantService modular.TenantService
+	if err := m.app.GetService("tenantService", &tenantService); err != nil || tenantService == nil {
+		return ErrTenantServiceUnavailable
+	}
+	// The tenant service notifies OnTenantRegistered while holding its lock, so the
+	// configuration is applied once it returns
+	if err := tenantService.RegisterTenant(tenantID, map[string]modular.ConfigProvider{
+		m.Name(): modular.NewStdConfigProvider(cfg),
+	}); err != nil {
+		return fmt.Errorf("failed to register tenant %s: %w", tenantID, err)
+	}
+
+	m.releaseTenantTLS(tenantID)
+	m.setTenantConfig(tenantID, merged)
+	m.loadTenantTLS(ctx, tenantID, cfg)
+
+	m.tenantProxiesMutex.Lock()
+	delete(m.tenantBackendProxies, tenantID)
+	m.tenantProxiesMutex.Unlock()
+	m.createTenantProxiesFor(ctx, tenantID, merged)
+
+	routes := m.registerTenantRoutes(merged)
+
+	m.app.Logger().Info("Tenant activated", "tenant_hash", obfuscateTenantID(tenantID),
+		"backends", len(cfg.BackendServices), "new_routes", len(routes))
+	m.emitEvent(ctx, EventTypeTenantActivated, map[string]interface{}{
+		"tenant":     string(tenantID),
+		"backends":   sortedKeys(cfg.BackendServices),
+		"new_routes": routes,
+		"time":       time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	return nil
+}
+
+// tenantRouteTable holds the handlers of route patterns added by tenants registered
+// after Start. The router is not safe to change while it serves requests, so the
+// registered routes look these up instead.
+type tenantRouteTable struct {
+	mu       sync.RWMutex
+	handlers map[string]http.HandlerFunc
+}
+
+// add serves pattern with handler.
+func (t *tenantRouteTable) add(pattern string, handler http.HandlerFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.handlers == nil {
+		t.handlers = make(map[string]http.HandlerFunc)
+	}
+	t.handlers[pattern] = handler
+}
+
+// match returns the handler of the longest pattern matching path that is more
+// specific than the registered pattern the request was routed to.
+func (t *tenantRouteTable) match(m *ReverseProxyModule, path, registered string) (http.HandlerFunc, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var (
+		best    http.HandlerFunc
+		bestLen = len(registered)
+	)
+	for pattern, handler := range t.handlers {
+		if len(pattern) > bestLen && m.matchesRoute(path, pattern) {
+			best, bestLen = handler, len(pattern)
+		}
+	}
+	return best, best != nil
+}
+
+// withTenantRoutes serves requests routed to the wildcard pattern with the handler
+// of a more specific pattern added by a tenant at runtime, if one matches.
+// Exact patterns are more specific than any wildcard and always keep the request.
+func (m *ReverseProxyModule) withTenantRoutes(pattern string, next http.HandlerFunc) http.HandlerFunc {
+	if !strings.Contains(pattern, "*") {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if handler, ok := m.tenantRoutes.match(m, r.URL.Path, pattern); ok {
+			handler(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// registerTenantRoutes adds tenant-aware handlers for the tenant's route patterns
+// that no registered handler serves yet, and returns them. Before Start it does nothing;
+// Start registers every tenant's routes.
+func (m *ReverseProxyModule) registerTenantRoutes(tenantCfg *ReverseProxyConfig) []string {
+	m.routePatternsMutex.Lock()
+	defer m.routePatternsMutex.Unlock()
+	if m.routePatterns == nil {
+		return nil
+	}
+
+	var added []string
+	for _, pattern := range sortedKeys(tenantCfg.Routes) {
+		if m.routePatterns[pattern] {
+			continue
+		}
+		m.tenantRoutes.add(pattern, m.routeHandler(pattern, m.withRouteMiddleware(pattern, m.createTenantAwareHandler(pattern))))
+		m.routePatterns[pattern] = true
+		added = append(added, pattern)
+	}
+	return added
+}
+
+// recordRoutePatterns remembers the route patterns registered at Start, so tenants
+// registered later only add the patterns nothing serves yet.
+func (m *ReverseProxyModule) recordRoutePatterns() {
+	m.routePatternsMutex.Lock()
+	defer m.routePatternsMutex.Unlock()
+	m.routePatterns = map[string]bool{"/*": true}
+	for pattern := range m.config.Routes {
+		m.routePatterns[pattern] = true
+	}
+	for pattern := range m.compositeRoutes {
+		m.routePatterns[pattern] = true
+	}
+	for _, tenantCfg := range m.tenantConfigs() {
+		if tenantCfg == nil {
+			continue
+		}
+		for pattern := range tenantCfg.Routes {
+			m.routePatterns[pattern] = true
+		}
+	}
+}
+
+// registerTenantOnboardingEndpoint mounts the onboarding API.
+func (m *ReverseProxyModule) registerTenantOnboardingEndpoint() {
+	pattern := m.config.TenantOnboarding.basePath() + "/*"
+	m.safeHandleFunc(pattern, m.handleTenantOnboarding)
+	m.app.Logger().Info("Registered tenant onboarding endpoint", "endpoint", pattern)
+
+	// Routes added by onboarded tenants are reached through a wildcard route
+	if !m.catchAllRegistered {
+		m.safeHandleFunc("/*", http.NotFound)
+	}
+}
+
+// handleTenantOnboarding serves PUT and POST {base_path}/{tenantID}.
+func (m *ReverseProxyModule) handleTenantOnboarding(w http.ResponseWriter, r *http.Request) {
+	onboarding := m.config.TenantOnboarding
+	if (onboarding.RequireAuth || onboarding.AuthToken != "") && !checkBearerAuth(w, r, onboarding.AuthToken) {
+		return
+	}
+
+	if r.Method != http.MethodPut && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "PUT, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenantID := strings.TrimPrefix(r.URL.Path, onboarding.basePath()+"/")
+	if tenantID == "" || tenantID == r.URL.Path || strings.Contains(tenantID, "/") {
+		http.Error(w, "Expected "+onboarding.basePath()+"/{tenantID}", http.StatusNotFound)
+		return
+	}
+
+	cfg := &ReverseProxyConfig{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTenantOnboardingBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(cfg); err != nil {
+		http.Error(w, "Invalid tenant configuration: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := m.RegisterTenant(r.Context(), modular.TenantID(tenantID), cfg); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, ErrConfigValidationFailed), errors.Is(err, ErrTenantTLSConfig):
+			status = http.StatusUnprocessableEntity
+		case errors.Is(err, ErrTenantServiceUnavailable):
+			status = http.StatusServiceUnavailable
+		}
+		m.app.Logger().Warn("Tenant onboarding failed", "tenant_hash", obfuscateTenantID(modular.TenantID(tenantID)), "error", err)
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"tenant":   tenantID,
+		"backends": sortedKeys(cfg.BackendServices),
+		"routes":   sortedKeys(cfg.Routes),
+	})
+}
+
+// checkBearerAuth checks that r carries "Authorization: Bearer <token>", answering 401
+// without credentials and 403 with the wrong token. It reports whether r is authorized.
+func checkBearerAuth(w http.ResponseWriter, r *http.Request, token string) bool {
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return false
+	}
+	if subtle.ConstantTimeCompare([]byte(authHeader), []byte("Bearer "+token)) != 1 {
+		http.Error(w, "Invalid authentication token", http.StatusForbidden)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package reverseproxy
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newNamedBackend starts a backend that answers with its name.
+func newNamedBackend(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(name))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newOnboardingTestModule starts a module without tenants and with the onboarding API enabled.
+func newOnboardingTestModule(t *testing.T) (*ReverseProxyModule, *testRouter, modular.TenantService, *capturingSubject) {
+	t.Helper()
+
+	global := newNamedBackend(t, "global")
+	app := NewMockTenantApplication()
+	tenantService := modular.NewStandardTenantService(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	require.NoError(t, app.RegisterService("tenantService", tenantService))
+
+	router := &testRouter{routes: make(map[string]http.HandlerFunc)}
+	subject := &capturingSubject{}
+	m := NewModule()
+	require.NoError(t, m.RegisterConfig(app))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(&ReverseProxyConfig{
+		BackendServices: map[string]string{"api": global.URL},
+		Routes:          map[string]string{"/api/*": "api"},
+		DefaultBackend:  "api",
+		TenantIDHeader:  "X-Tenant-ID",
+		RequestTimeout:  5 * time.Second,
+		TenantOnboarding: TenantOnboardingConfig{
+			Enabled:     true,
+			RequireAuth: true,
+			AuthToken:   "secret",
+		},
+	}))
+	require.NoError(t, m.Init(app))
+	m.router = router
+	m.subject = subject
+	require.NoError(t, tenantService.RegisterTenantAwareModule(m))
+	require.NoError(t, m.Start(context.Background()))
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+	return m, router, tenantService, subject
+}
+
+func serveVia(router http.Handler, method, path, tenant, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if tenant != "" {
+		req.Header.Set("X-Tenant-ID", tenant)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestTenantOnboarding_HTTPAPI(t *testing.T) {
+	m, router, tenantService, subject := newOnboardingTestModule(t)
+	tenantBackend := newNamedBackend(t, "acme")
+	body := `{"backend_services":{"api":"` + tenantBackend.URL + `"},"routes":{"/acme/*":"api"}}`
+
+	t.Run("requires auth", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, serveVia(router, http.MethodPut, "/admin/tenants/acme", "", "", body).Code)
+		assert.Equal(t, http.StatusForbidden, serveVia(router, http.MethodPut, "/admin/tenants/acme", "", "wrong", body).Code)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		rec := serveVia(router, http.MethodPut, "/admin/tenants/acme", "", "secret", `{"backend_services":{"api":"localhost"}}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, http.StatusBadRequest, serveVia(router, http.MethodPut, "/admin/tenants/acme", "", "secret", `{"unknown":1}`).Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, serveVia(router, http.MethodGet, "/admin/tenants/acme", "", "secret", "").Code)
+		assert.Empty(t, tenantService.GetTenants())
+	})
+
+	t.Run("activates tenant", func(t *testing.T) {
+		rec := serveVia(router, http.MethodPut, "/admin/tenants/acme", "", "secret", body)
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, "acme", resp["tenant"])
+
+		assert.Equal(t, []modular.TenantID{"acme"}, tenantService.GetTenants())
+		assert.Equal(t, "acme", serveVia(router, http.MethodGet, "/api/users", "acme", "", "").Body.String())
+		assert.Equal(t, "global", serveVia(router, http.MethodGet, "/api/users", "", "", "").Body.String())
+		assert.Equal(t, "acme", serveVia(router, http.MethodGet, "/acme/users", "acme", "", "").Body.String())
+		router.mu.RLock()
+		_, mounted := router.routes["/acme/*"]
+		router.mu.RUnlock()
+		assert.False(t, mounted, "runtime routes are looked up, not added to the live router")
+
+		events := subject.eventsOfType(EventTypeTenantActivated)
+		require.Len(t, events, 1)
+		var data map[string]interface{}
+		require.NoError(t, events[0].DataAs(&data))
+		assert.Equal(t, "acme", data["tenant"])
+		assert.Equal(t, []interface{}{"/acme/*"}, data["new_routes"])
+		assert.NotNil(t, m.tenantConfig("acme"))
+	})
+}
+
+func TestRegisterTenant_ReplacesConfiguration(t *testing.T) {
+	m, router, _, subject := newOnboardingTestModule(t)
+	first := newNamedBackend(t, "first")
+	second := newNamedBackend(t, "second")
+
+	require.NoError(t, m.RegisterTenant(context.Background(), "acme", &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": first.URL},
+	}))
+	assert.Equal(t, "first", serveVia(router, http.MethodGet, "/api/users", "acme", "", "").Body.String())
+
+	require.NoError(t, m.RegisterTenant(context.Background(), "acme", &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": second.URL},
+	}))
+	assert.Equal(t, "second", serveVia(router, http.MethodGet, "/api/users", "acme", "", "").Body.String())
+	assert.Len(t, subject.eventsOfType(EventTypeTenantActivated), 2)
+}
+
+func TestRegisterTenant_Errors(t *testing.T) {
+	m, _, tenantService, _ := newOnboardingTestModule(t)
+	ctx := context.Background()
+
+	require.ErrorIs(t, m.RegisterTenant(ctx, "", &ReverseProxyConfig{}), ErrTenantIDEmpty)
+	require.ErrorIs(t, m.RegisterTenant(ctx, "acme", nil), ErrConfigurationNil)
+	require.ErrorIs(t, m.RegisterTenant(ctx, "acme", &ReverseProxyConfig{
+		Routes: map[string]string{"/acme/*": "missing"},
+	}), ErrConfigValidationFailed)
+	require.ErrorIs(t, m.RegisterTenant(ctx, "acme", &ReverseProxyConfig{
+		BackendConfigs: map[string]BackendServiceConfig{"api": {ClientTLS: &BackendTLSConfig{CertFile: "/missing.crt", KeyFile: "/missing.key"}}},
+	}), ErrTenantTLSConfig)
+	assert.Empty(t, tenantService.GetTenants())
+}
+
+func TestTenantOnboardingConfig_Validate(t *testing.T) {
+	require.NoError(t, (&TenantOnboardingConfig{Enabled: true}).validate())
+	require.ErrorIs(t, (&TenantOnboardingConfig{Enabled: true, RequireAuth: true}).validate(), ErrInvalidTenantOnboardingConfig)
+	assert.Equal(t, "/admin/tenants", (&TenantOnboardingConfig{}).basePath())
+	assert.Equal(t, "/tenants", (&TenantOnboardingConfig{BasePath: "/tenants/"}).basePath())
+}
@@ -0,0 +1,201 @@
+package reverseproxy
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// TLSSecretResolver resolves a BackendTLSConfig.SecretRef into a PEM-encoded
+// client certificate and private key.
+type TLSSecretResolver func(ref string) (certPEM, keyPEM []byte, err error)
+
+// SetTLSSecretResolver sets the resolver used for tenant client certificates that
+// are configured with secret_ref. It must be called before the module loads
+// tenant configuration.
+func (m *ReverseProxyModule) SetTLSSecretResolver(resolver TLSSecretResolver) {
+	m.tlsSecretResolver = resolver
+}
+
+// TenantTLSError returns the error encountered while loading the tenant's backend
+// client certificates, or nil if they loaded or none are configured.
+func (m *ReverseProxyModule) TenantTLSError(tenantID modular.TenantID) error {
+	m.tenantProxiesMutex.RLock()
+	defer m.tenantProxiesMutex.RUnlock()
+	return m.tenantTLSErrors[tenantID]
+}
+
+// tlsErrorTransport fails every request with the certificate load error, so a
+// tenant's traffic is never sent to its backend without the configured certificate.
+type tlsErrorTransport struct {
+	err error
+}
+
+// RoundTrip implements http.RoundTripper
+func (t tlsErrorTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, t.err
+}
+
+// buildClientTLSConfig loads the client certificate and optional CA bundle described by cfg.
+func buildClientTLSConfig(cfg *BackendTLSConfig, resolver TLSSecretResolver) (*tls.Config, error) {
+	var cert tls.Certificate
+	var err error
+	switch {
+	case cfg.SecretRef != "":
+		if resolver == nil {
+			return nil, ErrTLSSecretResolverMissing
+		}
+		certPEM, keyPEM, resolveErr := resolver(cfg.SecretRef)
+		if resolveErr != nil {
+			return nil, fmt.Errorf("resolve secret %q: %w", cfg.SecretRef, resolveErr)
+		}
+		cert, err = tls.X509KeyPair(certPEM, keyPEM)
+	case cfg.CertFile != "" && cfg.KeyFile != "":
+		cert, err = tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	default:
+		return nil, ErrTLSCertificateMissing
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load client certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ServerName:   cfg.ServerName,
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("%w: %s", ErrTLSInvalidCABundle, cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// newTenantTransport returns a transport for a tenant's dedicated backend connections,
+// based on the module's HTTP client transport when it is an *http.Transport.
+func (m *ReverseProxyModule) newTenantTransport(tlsConfig *tls.Config) *http.Transport {
+	var transport *http.Transport
+	if m.httpClient != nil {
+		if base, ok := m.httpClient.Transport.(*http.Transport); ok {
+			transport = base.Clone()
+		}
+	}
+	if transport == nil {
+		transport = &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 30 * time.Second,
+			ExpectContinueTimeout: 1 * time.Second,
+			MaxIdleConns:          100,
+			MaxIdleConnsPerHost:   10,
+			IdleConnTimeout:       90 * time.Second,
+		}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport
+}
+
+// loadTenantTLS builds dedicated transports for the backends whose tenant configuration
+// specifies a client certificate. Certificate errors are logged, emitted as
+// EventTypeTenantTLSFailed and kept for TenantTLSError; the affected backends get a
+// transport that fails every request for this tenant. Tenants are loaded only once
+// until they are removed.
+func (m *ReverseProxyModule) loadTenantTLS(ctx context.Context, tenantID modular.TenantID, tenantCfg *ReverseProxyConfig) {
+	m.tenantProxiesMutex.RLock()
+	_, loaded := m.tenantTransports[tenantID]
+	m.tenantProxiesMutex.RUnlock()
+	if loaded {
+		return
+	}
+
+	transports := make(map[string]http.RoundTripper)
+	var errs []error
+	for backendID, backendCfg := range tenantCfg.BackendConfigs {
+		if backendCfg.ClientTLS == nil {
+			continue
+		}
+
+		tlsConfig, err := buildClientTLSConfig(backendCfg.ClientTLS, m.tlsSecretResolver)
+		if err != nil {
+			err = fmt.Errorf("%w: backend %s: %w", ErrTenantTLSConfig, backendID, err)
+			transports[backendID] = tlsErrorTransport{err: err}
+			errs = append(errs, err)
+
+			if m.app != nil && m.app.Logger() != nil {
+				m.app.Logger().Error("Failed to load tenant backend client certificate",
+					"tenant", tenantID, "backend", backendID, "error", err)
+			}
+			m.emitEvent(ctx, EventTypeTenantTLSFailed, map[string]interface{}{
+				"tenant":  string(tenantID),
+				"backend": backendID,
+				"error":   err.Error(),
+			})
+			continue
+		}
+
+		transports[backendID] = m.newTenantTransport(tlsConfig)
+		if m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Debug("Loaded tenant backend client certificate", "tenant", tenantID, "backend", backendID)
+		}
+	}
+
+	m.tenantProxiesMutex.Lock()
+	if m.tenantTransports == nil {
+		m.tenantTransports = make(map[modular.TenantID]map[string]http.RoundTripper)
+	}
+	if m.tenantTLSErrors == nil {
+		m.tenantTLSErrors = make(map[modular.TenantID]error)
+	}
+	m.tenantTransports[tenantID] = transports
+	if err := errors.Join(errs...); err != nil {
+		m.tenantTLSErrors[tenantID] = err
+	} else {
+		delete(m.tenantTLSErrors, tenantID)
+	}
+	m.tenantProxiesMutex.Unlock()
+}
+
+// tenantTransport returns the tenant's dedicated transport for a backend, if any.
+func (m *ReverseProxyModule) tenantTransport(tenantID modular.TenantID, backendID string) http.RoundTripper {
+	m.tenantProxiesMutex.RLock()
+	defer m.tenantProxiesMutex.RUnlock()
+	return m.tenantTransports[tenantID][backendID]
+}
+
+// releaseTenantTLS drops the tenant's dedicated transports and the request transports
+// derived from them, and closes their idle connections. Reloading the tenant's
+// certificates then builds new ones.
+func (m *ReverseProxyModule) releaseTenantTLS(tenantID modular.TenantID) {
+	m.tenantProxiesMutex.Lock()
+	transports := m.tenantTransports[tenantID]
+	delete(m.tenantTransports, tenantID)
+	delete(m.tenantTLSErrors, tenantID)
+	m.tenantProxiesMutex.Unlock()
+
+	for _, transport := range transports {
+		if t, ok := transport.(*http.Transport); ok {
+			t.CloseIdleConnections()
+		}
+		m.evictRequestTransports(transport)
+	}
+}
@@ -0,0 +1,221 @@
+package reverseproxy
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantTLSFixture holds an mTLS backend and the PEM material needed to reach it.
+type tenantTLSFixture struct {
+	server  *httptest.Server
+	certPEM []byte
+	keyPEM  []byte
+	dir     string
+}
+
+// newTenantTLSFixture starts a backend that requires a client certificate signed by a
+// freshly generated CA and echoes the client certificate's common name.
+func newTenantTLSFixture(t *testing.T) *tenantTLSFixture {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "tenant-a"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Client-CN", r.TLS.PeerCertificates[0].Subject.CommonName)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs, MinVersion: tls.VersionTLS12}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	f := &tenantTLSFixture{
+		server:  server,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKeyDER}),
+		dir:     t.TempDir(),
+	}
+	serverCAPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(f.path("client.crt"), f.certPEM, 0600))
+	require.NoError(t, os.WriteFile(f.path("client.key"), f.keyPEM, 0600))
+	require.NoError(t, os.WriteFile(f.path("server-ca.pem"), serverCAPEM, 0600))
+	return f
+}
+
+func (f *tenantTLSFixture) path(name string) string {
+	return filepath.Join(f.dir, name)
+}
+
+// newTenantTLSModule loads a single tenant whose "api" backend uses clientTLS.
+func newTenantTLSModule(t *testing.T, f *tenantTLSFixture, tenantID modular.TenantID, clientTLS *BackendTLSConfig, resolver TLSSecretResolver) (*ReverseProxyModule, *capturingSubject) {
+	t.Helper()
+
+	subject := &capturingSubject{}
+	m := NewModule()
+	m.subject = subject
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": f.server.URL},
+		RequestTimeout:  5 * time.Second,
+	}
+	m.SetTLSSecretResolver(resolver)
+
+	tenantCfg := &ReverseProxyConfig{
+		BackendConfigs: map[string]BackendServiceConfig{"api": {ClientTLS: clientTLS}},
+	}
+	m.tenants[tenantID] = mergeConfigs(m.config, tenantCfg)
+	m.loadTenantTLS(context.Background(), tenantID, tenantCfg)
+	m.createTenantProxies(context.Background())
+	return m, subject
+}
+
+func serveTenantRequest(m *ReverseProxyModule, tenantID modular.TenantID) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	m.createBackendProxyHandlerForTenant(tenantID, "api")(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	return rec
+}
+
+func TestTenantTLS_PresentsClientCertificate(t *testing.T) {
+	f := newTenantTLSFixture(t)
+	m, _ := newTenantTLSModule(t, f, "tenant-a", &BackendTLSConfig{
+		CertFile: f.path("client.crt"),
+		KeyFile:  f.path("client.key"),
+		CAFile:   f.path("server-ca.pem"),
+	}, nil)
+
+	require.NoError(t, m.TenantTLSError("tenant-a"))
+
+	rec := serveTenantRequest(m, "tenant-a")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "tenant-a", rec.Header().Get("X-Client-CN"))
+
+	// The tenant proxy must not have been promoted to the global backend proxy
+	m.backendProxiesMutex.RLock()
+	globalProxy := m.backendProxies["api"]
+	m.backendProxiesMutex.RUnlock()
+	require.NotNil(t, globalProxy)
+	assert.NotSame(t, m.tenantTransport("tenant-a", "api"), globalProxy.Transport)
+
+	// Releasing the tenant's certificates drops the request transports derived from them
+	tenantTransport := m.tenantTransport("tenant-a", "api")
+	m.requestTransport(tenantTransport, time.Second)
+	derived := func() int {
+		count := 0
+		m.requestTransports.Range(func(key, _ any) bool {
+			if key.(requestTransportKey).base == tenantTransport {
+				count++
+			}
+			return true
+		})
+		return count
+	}
+	require.Equal(t, 1, derived())
+	m.releaseTenantTLS("tenant-a")
+	assert.Zero(t, derived())
+}
+
+func TestTenantTLS_SecretRef(t *testing.T) {
+	f := newTenantTLSFixture(t)
+	var resolved string
+	m, _ := newTenantTLSModule(t, f, "tenant-a", &BackendTLSConfig{
+		SecretRef: "vault://tenants/tenant-a/mtls",
+		CAFile:    f.path("server-ca.pem"),
+	}, func(ref string) ([]byte, []byte, error) {
+		resolved = ref
+		return f.certPEM, f.keyPEM, nil
+	})
+
+	require.NoError(t, m.TenantTLSError("tenant-a"))
+	assert.Equal(t, "vault://tenants/tenant-a/mtls", resolved)
+	assert.Equal(t, http.StatusOK, serveTenantRequest(m, "tenant-a").Code)
+}
+
+func TestTenantTLS_LoadErrorSurfaced(t *testing.T) {
+	f := newTenantTLSFixture(t)
+	m, subject := newTenantTLSModule(t, f, "tenant-a", &BackendTLSConfig{
+		CertFile: f.path("missing.crt"),
+		KeyFile:  f.path("client.key"),
+	}, nil)
+
+	err := m.TenantTLSError("tenant-a")
+	require.ErrorIs(t, err, ErrTenantTLSConfig)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	events := subject.eventsOfType(EventTypeTenantTLSFailed)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "tenant-a", data["tenant"])
+	assert.Equal(t, "api", data["backend"])
+
+	// Requests fail rather than reaching the backend without the certificate
+	rec := serveTenantRequest(m, "tenant-a")
+	assert.GreaterOrEqual(t, rec.Code, http.StatusInternalServerError)
+
+	// Removing the tenant clears the recorded error
+	m.OnTenantRemoved("tenant-a")
+	assert.NoError(t, m.TenantTLSError("tenant-a"))
+}
+
+func TestBuildClientTLSConfig_Errors(t *testing.T) {
+	_, err := buildClientTLSConfig(&BackendTLSConfig{SecretRef: "ref"}, nil)
+	require.ErrorIs(t, err, ErrTLSSecretResolverMissing)
+
+	_, err = buildClientTLSConfig(&BackendTLSConfig{CertFile: "client.crt"}, nil)
+	require.ErrorIs(t, err, ErrTLSCertificateMissing)
+
+	f := newTenantTLSFixture(t)
+	require.NoError(t, os.WriteFile(f.path("bad-ca.pem"), []byte("not a certificate"), 0600))
+	_, err = buildClientTLSConfig(&BackendTLSConfig{
+		CertFile: f.path("client.crt"),
+		KeyFile:  f.path("client.key"),
+		CAFile:   f.path("bad-ca.pem"),
+	}, nil)
+	require.ErrorIs(t, err, ErrTLSInvalidCABundle)
+}
@@ -0,0 +1,54 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestModule returns a module using cfg as is, without Init, with proxies
+// created for its backend services and events recorded by the returned subject.
+// Features Init would set up, such as maintenance or the bandwidth limiter, are
+// left to the caller.
+func newTestModule(t *testing.T, cfg *ReverseProxyConfig) (*ReverseProxyModule, *capturingSubject) {
+	t.Helper()
+	subject := &capturingSubject{}
+	m := NewModule()
+	m.subject = subject
+	m.config = cfg
+	for _, backend := range sortedKeys(cfg.BackendServices) {
+		require.NoError(t, m.createBackendProxy(backend, cfg.BackendServices[backend]))
+	}
+	m.initialized = true
+	return m, subject
+}
+
+// newInitializedTestModule initializes a module with cfg through a mock tenant
+// application, registering routes on the returned router and recording events in
+// the returned subject.
+func newInitializedTestModule(t *testing.T, cfg *ReverseProxyConfig) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+	app := NewMockTenantApplication()
+	router := &testRouter{routes: make(map[string]http.HandlerFunc)}
+	subject := &capturingSubject{}
+	m := NewModule()
+	require.NoError(t, m.RegisterConfig(app))
+	app.RegisterConfigSection("reverseproxy", modular.NewStdConfigProvider(cfg))
+	require.NoError(t, m.Init(app))
+	m.router = router
+	m.subject = subject
+	return m, router, subject
+}
+
+// newStartedTestModule is newInitializedTestModule followed by Start. The module is
+// stopped when the test ends.
+func newStartedTestModule(t *testing.T, cfg *ReverseProxyConfig) (*ReverseProxyModule, *testRouter, *capturingSubject) {
+	t.Helper()
+	m, router, subject := newInitializedTestModule(t, cfg)
+	require.NoError(t, m.Start(context.Background()))
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+	return m, router, subject
+}
@@ -0,0 +1,239 @@
+package reverseproxy
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Timeout stages reported in gateway timeout responses and events.
+const (
+	// TimeoutStageDial indicates the TCP connection to the backend could not be established in time
+	TimeoutStageDial = "dial"
+	// TimeoutStageTLS indicates the TLS handshake with the backend did not complete in time
+	TimeoutStageTLS = "tls"
+	// TimeoutStageResponseHeader indicates the backend did not send response headers in time
+	TimeoutStageResponseHeader = "response_header"
+	// TimeoutStageTotal indicates the overall request deadline was exceeded
+	TimeoutStageTotal = "total"
+)
+
+// Timeout response verbosity levels.
+const (
+	// TimeoutVerbosityMinimal returns the plain-text body and the correlation ID header without diagnostic headers (default)
+	TimeoutVerbosityMinimal = "minimal"
+	// TimeoutVerbosityHeaders adds diagnostic headers describing the timeout
+	TimeoutVerbosityHeaders = "headers"
+	// TimeoutVerbosityDetailed adds diagnostic headers and a JSON body describing the timeout
+	TimeoutVerbosityDetailed = "detailed"
+)
+
+// Diagnostic response headers set on gateway timeouts when verbosity allows it.
+const (
+	HeaderTimeoutStage   = "X-Proxy-Timeout-Stage"
+	HeaderTimeoutValue   = "X-Proxy-Timeout"
+	HeaderTimeoutBackend = "X-Proxy-Timeout-Backend"
+)
+
+// TimeoutResponseConfig controls how gateway timeouts are reported to clients.
+type TimeoutResponseConfig struct {
+	// Verbosity controls how much timeout detail is exposed: minimal, headers or detailed
+	Verbosity string `json:"verbosity" yaml:"verbosity" toml:"verbosity" env:"VERBOSITY" default:"minimal" desc:"Timeout response verbosity (minimal, headers, detailed)"`
+
+	// CorrelationIDHeader is the request header used as correlation ID; one is generated when absent
+	CorrelationIDHeader string `json:"correlation_id_header" yaml:"correlation_id_header" toml:"correlation_id_header" env:"CORRELATION_ID_HEADER" default:"X-Request-ID" desc:"Header carrying the request correlation ID"`
+}
+
+// GatewayTimeoutDetails describes a gateway timeout returned to a client.
+type GatewayTimeoutDetails struct {
+	Error         string `json:"error"`
+	Stage         string `json:"stage"`
+	Timeout       string `json:"timeout"`
+	TimeoutSource string `json:"timeout_source,omitempty"`
+	Backend       string `json:"backend,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// requestTimeoutInfo carries the timeout applied to a proxied request so that
+// error handlers created at proxy construction time can report it.
+type requestTimeoutInfo struct {
+	timeout       time.Duration
+	source        string
+	backend       string
+	correlationID string
+	// transport sends the request to the backend, once the handler chose it
+	transport http.RoundTripper
+}
+
+type requestTimeoutInfoKey struct{}
+
+func withRequestTimeoutInfo(ctx context.Context, info *requestTimeoutInfo) context.Context {
+	return context.WithValue(ctx, requestTimeoutInfoKey{}, info)
+}
+
+func requestTimeoutInfoFromContext(ctx context.Context) *requestTimeoutInfo {
+	info, _ := ctx.Value(requestTimeoutInfoKey{}).(*requestTimeoutInfo)
+	return info
+}
+
+// setRequestTransport records the transport that sends the request of ctx to the
+// backend, whose stage timeouts gateway timeouts report.
+func setRequestTransport(ctx context.Context, transport http.RoundTripper) {
+	if info := requestTimeoutInfoFromContext(ctx); info != nil {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		info.transport = transport
+	}
+}
+
+// newRequestTimeoutInfo builds the timeout info for a request, reusing the
+// client's correlation ID header when present.
+func (m *ReverseProxyModule) newRequestTimeoutInfo(r *http.Request, backend string, timeout time.Duration, source string) *requestTimeoutInfo {
+	header := "X-Request-ID"
+	if m.config != nil && m.config.TimeoutResponse.CorrelationIDHeader != "" {
+		header = m.config.TimeoutResponse.CorrelationIDHeader
+	}
+	correlationID := r.Header.Get(header)
+	if correlationID == "" {
+		correlationID = generateCorrelationID()
+	}
+	return &requestTimeoutInfo{
+		timeout:       timeout,
+		source:        source,
+		backend:       backend,
+		correlationID: correlationID,
+	}
+}
+
+func generateCorrelationID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// classifyTimeoutStage determines which stage of the upstream exchange timed out.
+func classifyTimeoutStage(err error) string {
+	if err == nil {
+		return TimeoutStageTotal
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "tls handshake timeout"):
+		return TimeoutStageTLS
+	case strings.Contains(msg, "timeout awaiting response headers"):
+		return TimeoutStageResponseHeader
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout() {
+		return TimeoutStageDial
+	}
+	if strings.Contains(msg, "dial") && strings.Contains(msg, "i/o timeout") {
+		return TimeoutStageDial
+	}
+
+	return TimeoutStageTotal
+}
+
+// gatewayTimeoutDetails assembles the details for a timeout at the given stage.
+func (m *ReverseProxyModule) gatewayTimeoutDetails(r *http.Request, backend, stage string) GatewayTimeoutDetails {
+	details := GatewayTimeoutDetails{
+		Error:   "Gateway timeout",
+		Stage:   stage,
+		Backend: backend,
+	}
+
+	info := requestTimeoutInfoFromContext(r.Context())
+	if info == nil {
+		info = m.newRequestTimeoutInfo(r, backend, 0, "")
+	}
+	details.CorrelationID = info.correlationID
+	details.TimeoutSource = info.source
+	if details.Backend == "" {
+		details.Backend = info.backend
+	}
+
+	applied := info.timeout
+	switch stage {
+	case TimeoutStageTLS, TimeoutStageResponseHeader:
+		// The transport's own timeout applies to these stages; it is unknown for
+		// round trippers other than *http.Transport
+		applied = 0
+		if transport, ok := info.transport.(*http.Transport); ok {
+			if stage == TimeoutStageTLS {
+				applied = transport.TLSHandshakeTimeout
+			} else {
+				applied = transport.ResponseHeaderTimeout
+			}
+		}
+	}
+	if applied > 0 {
+		details.Timeout = applied.String()
+	}
+
+	return details
+}
+
+// writeGatewayTimeout writes a 504 response describing the timeout according to the
+// configured verbosity and emits a request timeout event. The caller is responsible
+// for ensuring that no response headers have been written to w yet.
+func (m *ReverseProxyModule) writeGatewayTimeout(w http.ResponseWriter, r *http.Request, backend, stage, message string) {
+	details := m.gatewayTimeoutDetails(r, backend, stage)
+
+	m.emitEvent(r.Context(), EventTypeRequestTimeout, map[string]interface{}{
+		"backend":        details.Backend,
+		"method":         r.Method,
+		"path":           r.URL.Path,
+		"stage":          details.Stage,
+		"timeout":        details.Timeout,
+		"timeout_source": details.TimeoutSource,
+		"correlation_id": details.CorrelationID,
+	})
+
+	verbosity := TimeoutVerbosityMinimal
+	if m.config != nil && m.config.TimeoutResponse.Verbosity != "" {
+		verbosity = strings.ToLower(m.config.TimeoutResponse.Verbosity)
+	}
+
+	header := w.Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	// Every level returns the correlation ID to look the timeout up in the logs and events with
+	correlationHeader := "X-Request-ID"
+	if m.config != nil && m.config.TimeoutResponse.CorrelationIDHeader != "" {
+		correlationHeader = m.config.TimeoutResponse.CorrelationIDHeader
+	}
+	header.Set(correlationHeader, details.CorrelationID)
+	if verbosity == TimeoutVerbosityHeaders || verbosity == TimeoutVerbosityDetailed {
+		header.Set(HeaderTimeoutStage, details.Stage)
+		if details.Timeout != "" {
+			header.Set(HeaderTimeoutValue, details.Timeout)
+		}
+		if details.Backend != "" {
+			header.Set(HeaderTimeoutBackend, details.Backend)
+		}
+	}
+
+	if verbosity == TimeoutVerbosityDetailed {
+		header.Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusGatewayTimeout)
+		if err := json.NewEncoder(w).Encode(details); err != nil && m.app != nil && m.app.Logger() != nil {
+			m.app.Logger().Warn("Failed to write gateway timeout response body", "backend", backend, "error", err.Error())
+		}
+		return
+	}
+
+	header.Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusGatewayTimeout)
+	fmt.Fprintln(w, message)
+}
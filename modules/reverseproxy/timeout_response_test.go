@@ -0,0 +1,139 @@
+package reverseproxy
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyTimeoutStage(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil error", nil, TimeoutStageTotal},
+		{"context deadline", errors.New("context deadline exceeded"), TimeoutStageTotal},
+		{"tls handshake", errors.New("net/http: TLS handshake timeout"), TimeoutStageTLS},
+		{"response headers", errors.New("net/http: timeout awaiting response headers"), TimeoutStageResponseHeader},
+		{"dial op error", &net.OpError{Op: "dial", Net: "tcp", Err: timeoutErr{}}, TimeoutStageDial},
+		{"dial message", errors.New("dial tcp 10.0.0.1:80: i/o timeout"), TimeoutStageDial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, classifyTimeoutStage(tt.err))
+		})
+	}
+}
+
+type timeoutErr struct{}
+
+func (timeoutErr) Error() string   { return "i/o timeout" }
+func (timeoutErr) Timeout() bool   { return true }
+func (timeoutErr) Temporary() bool { return true }
+
+// newTimeoutTestModule creates a module proxying to a backend slower than the configured route timeout
+func newTimeoutTestModule(t *testing.T, verbosity string) (*ReverseProxyModule, *capturingSubject) {
+	t.Helper()
+
+	slowBackend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(2 * time.Second):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(slowBackend.Close)
+
+	return newTestModule(t, &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": slowBackend.URL},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/slow": {Timeout: 100 * time.Millisecond},
+		},
+		RequestTimeout:  5 * time.Second,
+		TimeoutResponse: TimeoutResponseConfig{Verbosity: verbosity},
+	})
+}
+
+func TestGatewayTimeout_DetailedResponse(t *testing.T) {
+	m, subject := newTimeoutTestModule(t, TimeoutVerbosityDetailed)
+	handler := m.createBackendProxyHandler("api")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/slow", nil)
+	req.Header.Set("X-Request-ID", "req-123")
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+	assert.Equal(t, "req-123", rr.Header().Get("X-Request-ID"))
+	assert.Equal(t, "100ms", rr.Header().Get(HeaderTimeoutValue))
+	assert.Equal(t, "api", rr.Header().Get(HeaderTimeoutBackend))
+	assert.NotEmpty(t, rr.Header().Get(HeaderTimeoutStage))
+
+	var details GatewayTimeoutDetails
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &details))
+	assert.Equal(t, "req-123", details.CorrelationID)
+	assert.Equal(t, "100ms", details.Timeout)
+	assert.Equal(t, "route /api/slow", details.TimeoutSource)
+
+	events := subject.eventsOfType(EventTypeRequestTimeout)
+	require.NotEmpty(t, events)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, "req-123", data["correlation_id"])
+	assert.Equal(t, "100ms", data["timeout"])
+}
+
+func TestGatewayTimeout_HeadersVerbosity(t *testing.T) {
+	m, _ := newTimeoutTestModule(t, TimeoutVerbosityHeaders)
+	handler := m.createBackendProxyHandler("api")
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Header().Get("Content-Type"), "text/plain"))
+	assert.NotEmpty(t, rr.Header().Get("X-Request-ID"), "a correlation ID should be generated")
+	assert.Equal(t, "100ms", rr.Header().Get(HeaderTimeoutValue))
+}
+
+func TestGatewayTimeout_MinimalVerbosity(t *testing.T) {
+	m, subject := newTimeoutTestModule(t, "")
+	handler := m.createBackendProxyHandler("api")
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+
+	require.Equal(t, http.StatusGatewayTimeout, rr.Code)
+	assert.Empty(t, rr.Header().Get(HeaderTimeoutStage))
+	assert.Empty(t, rr.Header().Get(HeaderTimeoutValue))
+	assert.NotEmpty(t, rr.Header().Get("X-Request-ID"), "the correlation ID is returned at every verbosity")
+	assert.NotEmpty(t, subject.eventsOfType(EventTypeRequestTimeout), "timeout events are emitted regardless of verbosity")
+}
+
+func TestGatewayTimeout_ReportsTheTransportStageTimeouts(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{}
+	req := httptest.NewRequest(http.MethodGet, "/api/slow", nil)
+	req = req.WithContext(withRequestTimeoutInfo(req.Context(), m.newRequestTimeoutInfo(req, "api", 5*time.Second, "request")))
+	setRequestTransport(req.Context(), &http.Transport{TLSHandshakeTimeout: 3 * time.Second, ResponseHeaderTimeout: 2 * time.Second})
+
+	assert.Equal(t, "2s", m.gatewayTimeoutDetails(req, "api", TimeoutStageResponseHeader).Timeout)
+	assert.Equal(t, "3s", m.gatewayTimeoutDetails(req, "api", TimeoutStageTLS).Timeout)
+	assert.Equal(t, "5s", m.gatewayTimeoutDetails(req, "api", TimeoutStageTotal).Timeout)
+
+	// The stage timeouts of other round trippers are unknown
+	setRequestTransport(req.Context(), &testTransport{})
+	assert.Empty(t, m.gatewayTimeoutDetails(req, "api", TimeoutStageTLS).Timeout)
+	assert.Equal(t, "5s", m.gatewayTimeoutDetails(req, "api", TimeoutStageDial).Timeout)
+}
@@ -0,0 +1,134 @@
+package reverseproxy
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// TransformContext describes the request a composite route or custom endpoint
+// combines backend responses for, so response transformers can make decisions
+// without re-parsing headers or re-evaluating feature flags.
+type TransformContext struct {
+	// Route is the pattern of the composite route or custom endpoint
+	Route string
+
+	// Strategy is the strategy of the composite route, empty for custom endpoints
+	Strategy CompositeStrategy
+
+	// TenantID is the tenant of the request, empty when it has no tenant header
+	TenantID modular.TenantID
+
+	// FeatureFlags holds the feature flags of the route and its backends as
+	// evaluated for the request, keyed by flag ID
+	FeatureFlags map[string]bool
+
+	// WebSocket reports whether the request asks to upgrade to a websocket
+	WebSocket bool
+
+	// Metadata is the metadata configured for the route
+	Metadata map[string]string
+
+	// Backends holds the outcome of the request to each backend, keyed by backend ID,
+	// including backends that failed or were skipped
+	Backends map[string]BackendResult
+}
+
+// BackendResult is the outcome of the request a composite route or custom endpoint
+// sent to one backend.
+type BackendResult struct {
+	// StatusCode is the status of the backend's response, 0 when there is none
+	StatusCode int
+
+	// Latency is how long the backend took to respond
+	Latency time.Duration
+
+	// Err is the error of the request, if it failed
+	Err error
+
+	// Skipped reports whether the request wasn't sent because the backend's circuit
+	// breaker is open
+	Skipped bool
+}
+
+// ResponseTransformerV2 transforms the responses of a composite route's backends like
+// ResponseTransformer, and also receives the TransformContext of the request.
+type ResponseTransformerV2 func(tc *TransformContext, responses map[string]*http.Response) (*http.Response, error)
+
+// transformContextKey is the context key under which the module passes the
+// TransformContext of a request to the composite handler.
+type transformContextKey struct{}
+
+// newTransformContext returns the TransformContext of a request to route. flags holds
+// the feature flags already evaluated for the request, to which those of the backends
+// are added.
+func (m *ReverseProxyModule) newTransformContext(r *http.Request, route string, flags map[string]bool, backends []string, metadata map[string]string) *TransformContext {
+	tc := &TransformContext{
+		Route:        route,
+		FeatureFlags: flags,
+		WebSocket:    isWebSocketUpgrade(r),
+		Metadata:     metadata,
+		Backends:     make(map[string]BackendResult),
+	}
+	if m.config != nil && m.config.TenantIDHeader != "" {
+		tenantID, _ := TenantIDFromRequest(m.config.TenantIDHeader, r)
+		tc.TenantID = modular.TenantID(tenantID)
+	}
+
+	if tc.FeatureFlags == nil {
+		tc.FeatureFlags = make(map[string]bool)
+	}
+	if m.config == nil {
+		return tc
+	}
+	for _, backend := range backends {
+		backendConfig, ok := m.config.BackendConfigs[backend]
+		if !ok {
+			continue
+		}
+		for _, flagID := range []string{backendConfig.FeatureFlagID, backendConfig.FeatureFlag} {
+			if _, evaluated := tc.FeatureFlags[flagID]; flagID != "" && !evaluated {
+				tc.FeatureFlags[flagID] = m.evaluateFeatureFlag(flagID, r)
+			}
+		}
+	}
+	return tc
+}
+
+// transformContextFor returns the TransformContext the module attached to the
+// request, or a new one for composite handlers used on their own.
+func (h *CompositeHandler) transformContextFor(r *http.Request) *TransformContext {
+	if tc, ok := r.Context().Value(transformContextKey{}).(*TransformContext); ok {
+		tc.Strategy = h.strategy
+		return tc
+	}
+	return &TransformContext{
+		Strategy:     h.strategy,
+		FeatureFlags: make(map[string]bool),
+		WebSocket:    isWebSocketUpgrade(r),
+		Backends:     make(map[string]BackendResult),
+	}
+}
+
+// withTransformContext returns r carrying tc for the composite handler.
+func withTransformContext(r *http.Request, tc *TransformContext) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), transformContextKey{}, tc))
+}
+
+// isWebSocketUpgrade reports whether r asks to upgrade the connection to a websocket.
+func isWebSocketUpgrade(r *http.Request) bool {
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		return false
+	}
+	for _, value := range r.Header.Values("Connection") {
+		for _, token := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
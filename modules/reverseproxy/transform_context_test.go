@@ -0,0 +1,197 @@
+package reverseproxy
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticFlagEvaluator evaluates the flags it holds, and any other flag to false.
+type staticFlagEvaluator map[string]bool
+
+func (e staticFlagEvaluator) EvaluateFlag(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request) (bool, error) {
+	return e[flagID], nil
+}
+
+func (e staticFlagEvaluator) EvaluateFlagWithDefault(ctx context.Context, flagID string, tenantID modular.TenantID, req *http.Request, defaultValue bool) bool {
+	enabled, ok := e[flagID]
+	if !ok {
+		return defaultValue
+	}
+	return enabled
+}
+
+// newTransformContextTestModule creates a module proxying to a healthy "users" backend
+// and a failing "orders" backend.
+func newTransformContextTestModule(t *testing.T) *ReverseProxyModule {
+	t.Helper()
+	users := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"name":"alice"}`))
+	}))
+	t.Cleanup(users.Close)
+	orders := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(orders.Close)
+
+	m := NewModule()
+	m.app = NewMockTenantApplication()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"users": users.URL, "orders": orders.URL},
+		BackendConfigs: map[string]BackendServiceConfig{
+			"orders": {FeatureFlagID: "orders-v2"},
+		},
+		TenantIDHeader: "X-Tenant-ID",
+		RequestTimeout: 5 * time.Second,
+	}
+	m.httpClient = &http.Client{Timeout: time.Second}
+	m.router = &testRouter{routes: make(map[string]http.HandlerFunc)}
+	m.featureFlagEvaluator = staticFlagEvaluator{"summary-enabled": true, "orders-v2": false}
+	return m
+}
+
+func TestTransformContext_CompositeRoute(t *testing.T) {
+	m := newTransformContextTestModule(t)
+	m.config.CompositeRoutes = map[string]CompositeRoute{
+		"/api/summary": {
+			Pattern:       "/api/summary",
+			Backends:      []string{"users", "orders"},
+			Strategy:      "merge",
+			FeatureFlagID: "summary-enabled",
+			Metadata:      map[string]string{"owner": "profile-team"},
+		},
+	}
+
+	legacyCalled := false
+	m.SetResponseTransformer("/api/summary", func(map[string]*http.Response) (*http.Response, error) {
+		legacyCalled = true
+		return nil, nil
+	})
+	var tc *TransformContext
+	m.SetResponseTransformerV2("/api/summary", func(got *TransformContext, responses map[string]*http.Response) (*http.Response, error) {
+		tc = got
+		assert.Contains(t, responses, "users")
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"X-Tenant": []string{string(got.TenantID)}},
+			Body:       io.NopCloser(strings.NewReader("ok")),
+		}, nil
+	})
+	require.NoError(t, m.setupCompositeRoutes(context.Background()))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/summary", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.Header.Set("Connection", "keep-alive, Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	rec := httptest.NewRecorder()
+	m.compositeRoutes["/api/summary"](rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme", rec.Header().Get("X-Tenant"))
+	assert.False(t, legacyCalled, "the V2 transformer takes precedence")
+	require.NotNil(t, tc)
+	assert.Equal(t, "/api/summary", tc.Route)
+	assert.Equal(t, StrategyMerge, tc.Strategy)
+	assert.Equal(t, modular.TenantID("acme"), tc.TenantID)
+	assert.True(t, tc.WebSocket)
+	assert.Equal(t, map[string]string{"owner": "profile-team"}, tc.Metadata)
+	assert.Equal(t, map[string]bool{"summary-enabled": true, "orders-v2": false}, tc.FeatureFlags)
+
+	require.Len(t, tc.Backends, 2)
+	assert.Equal(t, http.StatusOK, tc.Backends["users"].StatusCode)
+	assert.GreaterOrEqual(t, tc.Backends["users"].Latency, 5*time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, tc.Backends["orders"].StatusCode)
+	assert.NoError(t, tc.Backends["orders"].Err)
+}
+
+func TestTransformContext_CompositeHandlerWithoutModule(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	t.Cleanup(backend.Close)
+
+	handler := NewCompositeHandler([]*Backend{
+		{ID: "up", URL: backend.URL, Client: http.DefaultClient},
+		{ID: "down", URL: "http://127.0.0.1:1", Client: http.DefaultClient},
+	}, StrategyMerge, time.Second)
+	var tc *TransformContext
+	handler.SetResponseTransformerV2(func(got *TransformContext, responses map[string]*http.Response) (*http.Response, error) {
+		tc = got
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, tc)
+	assert.Empty(t, tc.Route)
+	assert.Equal(t, StrategyMerge, tc.Strategy)
+	assert.False(t, tc.WebSocket)
+	assert.Equal(t, http.StatusOK, tc.Backends["up"].StatusCode)
+	assert.Error(t, tc.Backends["down"].Err)
+	assert.Zero(t, tc.Backends["down"].StatusCode)
+}
+
+func TestTransformContext_CustomEndpoint(t *testing.T) {
+	m := newTransformContextTestModule(t)
+
+	var tc *TransformContext
+	m.RegisterCustomEndpoint("/api/custom", EndpointMapping{
+		Endpoints: []BackendEndpointRequest{
+			{Backend: "users", Method: http.MethodGet, Path: "/users"},
+			{Backend: "orders", Method: http.MethodGet, Path: "/orders"},
+		},
+		ResponseTransformer: func(context.Context, *http.Request, map[string]*http.Response) (*CompositeResponse, error) {
+			t.Error("the V2 transformer takes precedence")
+			return nil, nil
+		},
+		ResponseTransformerV2: func(ctx context.Context, got *TransformContext, req *http.Request, responses map[string]*http.Response) (*CompositeResponse, error) {
+			tc = got
+			return &CompositeResponse{StatusCode: http.StatusAccepted, Body: []byte(`{}`)}, nil
+		},
+		Metadata: map[string]string{"version": "2"},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/custom", nil)
+	req.Header.Set("X-Tenant-ID", "globex")
+	rec := httptest.NewRecorder()
+	m.compositeRoutes["/api/custom"](rec, req)
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.NotNil(t, tc)
+	assert.Equal(t, "/api/custom", tc.Route)
+	assert.Empty(t, tc.Strategy)
+	assert.Equal(t, modular.TenantID("globex"), tc.TenantID)
+	assert.Equal(t, map[string]string{"version": "2"}, tc.Metadata)
+	assert.Equal(t, map[string]bool{"orders-v2": false}, tc.FeatureFlags)
+	assert.Equal(t, http.StatusOK, tc.Backends["users"].StatusCode)
+	assert.Equal(t, http.StatusServiceUnavailable, tc.Backends["orders"].StatusCode)
+}
+
+func TestIsWebSocketUpgrade(t *testing.T) {
+	tests := []struct {
+		connection, upgrade string
+		want                bool
+	}{
+		{"Upgrade", "websocket", true},
+		{"keep-alive, upgrade", "WebSocket", true},
+		{"keep-alive", "websocket", false},
+		{"Upgrade", "h2c", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Connection", tt.connection)
+		req.Header.Set("Upgrade", tt.upgrade)
+		assert.Equal(t, tt.want, isWebSocketUpgrade(req), "Connection %q, Upgrade %q", tt.connection, tt.upgrade)
+	}
+}
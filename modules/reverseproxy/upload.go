@@ -0,0 +1,327 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultUploadMaxDuration bounds streaming uploads on routes without max_duration.
+const defaultUploadMaxDuration = time.Hour
+
+// Outcomes of uploads tracked for progress.
+const (
+	// UploadOutcomeCompleted is an upload whose body was read to the end
+	UploadOutcomeCompleted = "completed"
+	// UploadOutcomeIncomplete is an upload whose body was not read to the end, because
+	// the client went away, the backend rejected it early or the request timed out
+	UploadOutcomeIncomplete = "incomplete"
+)
+
+// UploadConfig configures how a route handles large request bodies. Request bodies are
+// always streamed to the backend as they arrive, and a client sending
+// "Expect: 100-continue" only sends its body once the backend asked for it; a backend
+// rejecting the request from its headers alone spares the client the upload.
+//
+// By default the route's timeout covers the whole exchange, upload included, which
+// cuts large uploads from slow clients off. Streaming mode bounds the exchange with
+// MaxDuration instead, and applies the route's timeout only to waiting for the
+// backend's response headers once the body was sent:
+//
+//	route_configs:
+//	  "/files/*":
+//	    timeout: 30s
+//	    upload:
+//	      streaming: true
+//	      max_duration: 2h
+//	      progress_threshold: 104857600 # 100 MiB
+type UploadConfig struct {
+	// Streaming decouples the duration of uploads from the route's timeout
+	Streaming bool `json:"streaming" yaml:"streaming" toml:"streaming" env:"STREAMING"`
+
+	// MaxDuration bounds the whole exchange of a streaming upload. Defaults to 1h.
+	MaxDuration time.Duration `json:"max_duration" yaml:"max_duration" toml:"max_duration" env:"MAX_DURATION"`
+
+	// ProgressThreshold tracks the progress of uploads larger than this many bytes in
+	// UploadStatus and the metrics endpoint. Zero disables progress tracking.
+	ProgressThreshold int64 `json:"progress_threshold" yaml:"progress_threshold" toml:"progress_threshold" env:"PROGRESS_THRESHOLD"`
+}
+
+// validate checks the upload configuration of the route config.
+func (c *UploadConfig) validate(route RouteConfig) error {
+	if c.MaxDuration < 0 {
+		return fmt.Errorf("%w: max_duration must not be negative", ErrInvalidUploadConfig)
+	}
+	if c.ProgressThreshold < 0 {
+		return fmt.Errorf("%w: progress_threshold must not be negative", ErrInvalidUploadConfig)
+	}
+	if c.Streaming && route.DryRun {
+		return fmt.Errorf("%w: streaming uploads cannot be combined with dry_run, which buffers request bodies", ErrInvalidUploadConfig)
+	}
+	if c.Streaming && route.ContentTranslation != nil {
+		return fmt.Errorf("%w: streaming uploads cannot be combined with content_translation, which buffers request bodies", ErrInvalidUploadConfig)
+	}
+	return nil
+}
+
+// maxDuration returns the bound of streaming uploads.
+func (c *UploadConfig) maxDuration() time.Duration {
+	if c.MaxDuration > 0 {
+		return c.MaxDuration
+	}
+	return defaultUploadMaxDuration
+}
+
+// UploadStatus reports the uploads tracked for progress.
+type UploadStatus struct {
+	Routes []RouteUploadStatus `json:"routes"`
+	// Active lists the uploads in progress, oldest first
+	Active []ActiveUploadStatus `json:"active"`
+}
+
+// RouteUploadStatus counts the finished uploads of a route.
+type RouteUploadStatus struct {
+	Route      string `json:"route"`
+	Completed  uint64 `json:"completed"`
+	Incomplete uint64 `json:"incomplete"`
+	// Bytes counts the body bytes received by finished uploads
+	Bytes uint64 `json:"bytes"`
+}
+
+// ActiveUploadStatus is the progress of an upload.
+type ActiveUploadStatus struct {
+	Route  string `json:"route"`
+	Tenant string `json:"tenant,omitempty"`
+	Path   string `json:"path"`
+	// BytesReceived counts the body bytes received so far
+	BytesReceived int64 `json:"bytes_received"`
+	// ExpectedBytes is the request's Content-Length, or -1 when unknown
+	ExpectedBytes  int64     `json:"expected_bytes"`
+	StartedAt      time.Time `json:"started_at"`
+	BytesPerSecond float64   `json:"bytes_per_second"`
+}
+
+// requestUploadKey is the context key holding the upload config of a request's route.
+type requestUploadKey struct{}
+
+// streamingUpload returns the upload config of a request on a streaming upload route.
+func streamingUpload(ctx context.Context) (*UploadConfig, bool) {
+	config, ok := ctx.Value(requestUploadKey{}).(*UploadConfig)
+	return config, ok && config.Streaming
+}
+
+// uploadTracker tracks the progress of large uploads.
+type uploadTracker struct {
+	mu     sync.Mutex
+	nextID uint64
+	active map[uint64]*activeUpload
+	routes map[string]*RouteUploadStatus
+}
+
+// activeUpload is an upload in progress.
+type activeUpload struct {
+	route    string
+	tenant   string
+	path     string
+	expected int64
+	started  time.Time
+	received atomic.Int64
+}
+
+// newUploadTracker returns the tracker for the routes configs, or nil when no route
+// tracks upload progress.
+func newUploadTracker(routeConfigs map[string]RouteConfig) *uploadTracker {
+	for _, routeConfig := range routeConfigs {
+		if routeConfig.Upload != nil && routeConfig.Upload.ProgressThreshold > 0 {
+			return &uploadTracker{
+				active: make(map[uint64]*activeUpload),
+				routes: make(map[string]*RouteUploadStatus),
+			}
+		}
+	}
+	return nil
+}
+
+// start begins tracking an upload and returns its ID.
+func (t *uploadTracker) start(upload *activeUpload) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.nextID++
+	t.active[t.nextID] = upload
+	return t.nextID
+}
+
+// finish stops tracking an upload and counts it as completed or incomplete.
+func (t *uploadTracker) finish(id uint64, outcome string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	upload, ok := t.active[id]
+	if !ok {
+		return
+	}
+	delete(t.active, id)
+	route, ok := t.routes[upload.route]
+	if !ok {
+		route = &RouteUploadStatus{Route: upload.route}
+		t.routes[upload.route] = route
+	}
+	if outcome == UploadOutcomeCompleted {
+		route.Completed++
+	} else {
+		route.Incomplete++
+	}
+	route.Bytes += uint64(upload.received.Load())
+}
+
+// status returns the finished uploads per route and the uploads in progress.
+func (t *uploadTracker) status() UploadStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := UploadStatus{
+		Routes: make([]RouteUploadStatus, 0, len(t.routes)),
+		Active: make([]ActiveUploadStatus, 0, len(t.active)),
+	}
+	for _, route := range t.routes {
+		status.Routes = append(status.Routes, *route)
+	}
+	sort.Slice(status.Routes, func(i, j int) bool { return status.Routes[i].Route < status.Routes[j].Route })
+
+	now := time.Now()
+	for _, upload := range t.active {
+		received := upload.received.Load()
+		active := ActiveUploadStatus{
+			Route:         upload.route,
+			Tenant:        upload.tenant,
+			Path:          upload.path,
+			BytesReceived: received,
+			ExpectedBytes: upload.expected,
+			StartedAt:     upload.started,
+		}
+		if elapsed := now.Sub(upload.started).Seconds(); elapsed > 0 {
+			active.BytesPerSecond = float64(received) / elapsed
+		}
+		status.Active = append(status.Active, active)
+	}
+	sort.Slice(status.Active, func(i, j int) bool { return status.Active[i].StartedAt.Before(status.Active[j].StartedAt) })
+	return status
+}
+
+// writePrometheus writes the upload counters to p.
+func (t *uploadTracker) writePrometheus(p *prometheusWriter) {
+	status := t.status()
+	inProgress := make(map[string]int)
+	for _, upload := range status.Active {
+		inProgress[upload.Route]++
+	}
+
+	p.family("reverseproxy_uploads_total", "counter")
+	for _, route := range status.Routes {
+		p.sample("reverseproxy_uploads_total", float64(route.Completed), "route", route.Route, "outcome", UploadOutcomeCompleted)
+		p.sample("reverseproxy_uploads_total", float64(route.Incomplete), "route", route.Route, "outcome", UploadOutcomeIncomplete)
+	}
+	p.family("reverseproxy_upload_bytes_total", "counter")
+	for _, route := range status.Routes {
+		p.sample("reverseproxy_upload_bytes_total", float64(route.Bytes), "route", route.Route)
+	}
+	routes := make([]string, 0, len(inProgress))
+	for route := range inProgress {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	p.family("reverseproxy_uploads_in_progress", "gauge")
+	for _, route := range routes {
+		p.sample("reverseproxy_uploads_in_progress", float64(inProgress[route]), "route", route)
+	}
+}
+
+// UploadStatus returns the progress of large uploads, or nil when no route tracks it.
+func (m *ReverseProxyModule) UploadStatus() *UploadStatus {
+	if m.uploads == nil {
+		return nil
+	}
+	status := m.uploads.status()
+	return &status
+}
+
+// withUploads applies the upload config of the route pattern to requests served by
+// handler: streaming uploads get their own deadline, and large uploads are tracked.
+func (m *ReverseProxyModule) withUploads(pattern string, handler http.HandlerFunc) http.HandlerFunc {
+	routeConfig, ok := m.config.RouteConfigs[pattern]
+	if !ok || routeConfig.Upload == nil {
+		return handler
+	}
+	config := routeConfig.Upload
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Body == nil || r.Body == http.NoBody {
+			handler(w, r)
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), requestUploadKey{}, config))
+		if m.uploads == nil || config.ProgressThreshold == 0 {
+			handler(w, r)
+			return
+		}
+
+		body := &uploadProgressReader{ReadCloser: r.Body, threshold: config.ProgressThreshold}
+		body.upload = &activeUpload{
+			route:    pattern,
+			tenant:   r.Header.Get(m.config.TenantIDHeader),
+			path:     r.URL.Path,
+			expected: r.ContentLength,
+			started:  time.Now(),
+		}
+		body.start = func() { body.id = m.uploads.start(body.upload) }
+		if r.ContentLength >= config.ProgressThreshold {
+			body.startOnce.Do(body.start)
+		}
+		r.Body = body
+		defer func() {
+			body.startOnce.Do(func() {}) // uploads finishing below the threshold are not tracked
+			if body.id == 0 {
+				return
+			}
+			outcome := UploadOutcomeIncomplete
+			if body.eof.Load() || body.upload.received.Load() == body.upload.expected {
+				outcome = UploadOutcomeCompleted
+			}
+			m.uploads.finish(body.id, outcome)
+			m.emitEvent(r.Context(), EventTypeUploadFinished, map[string]interface{}{
+				"route":          pattern,
+				"path":           r.URL.Path,
+				"outcome":        outcome,
+				"bytes_received": body.upload.received.Load(),
+				"expected_bytes": body.upload.expected,
+				"duration_ms":    time.Since(body.upload.started).Milliseconds(),
+			})
+		}()
+		handler(w, r)
+	}
+}
+
+// uploadProgressReader counts the bytes of a request body, tracking the upload once
+// they exceed the threshold.
+type uploadProgressReader struct {
+	io.ReadCloser
+	threshold int64
+	upload    *activeUpload
+	start     func()
+	startOnce sync.Once
+	id        uint64
+	eof       atomic.Bool
+}
+
+func (u *uploadProgressReader) Read(p []byte) (int, error) {
+	n, err := u.ReadCloser.Read(p)
+	if u.upload.received.Add(int64(n)) >= u.threshold {
+		u.startOnce.Do(u.start)
+	}
+	if err == io.EOF {
+		u.eof.Store(true)
+	}
+	return n, err //nolint:wrapcheck // io.EOF must reach callers unwrapped
+}
@@ -0,0 +1,307 @@
+package reverseproxy
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUploadTestModule starts a module proxying /files/* to backend with the given
+// upload config, and returns the URL of a server in front of the module.
+func newUploadTestModule(t *testing.T, backend http.HandlerFunc, upload *UploadConfig, configure func(*ReverseProxyConfig)) (*ReverseProxyModule, string, *capturingSubject) {
+	t.Helper()
+
+	server := httptest.NewServer(backend)
+	t.Cleanup(server.Close)
+
+	cfg := &ReverseProxyConfig{
+		BackendServices: map[string]string{"files": server.URL},
+		Routes:          map[string]string{"/files/*": "files"},
+		RouteConfigs:    map[string]RouteConfig{"/files/*": {Timeout: 300 * time.Millisecond, Upload: upload}},
+		TenantIDHeader:  "X-Tenant-ID",
+		RequestTimeout:  300 * time.Millisecond,
+		MetricsEnabled:  true,
+		MetricsEndpoint: "/metrics",
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+
+	m, router, subject := newStartedTestModule(t, cfg)
+	proxy := httptest.NewServer(router)
+	t.Cleanup(proxy.Close)
+	return m, proxy.URL, subject
+}
+
+// countingBackend answers with the number of body bytes it read.
+func countingBackend(w http.ResponseWriter, r *http.Request) {
+	n, err := io.Copy(io.Discard, r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	_, _ = io.WriteString(w, strconv.FormatInt(n, 10))
+}
+
+// countingReader serves size bytes and counts those read.
+type countingReader struct {
+	size int64
+	read atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	remaining := c.size - c.read.Load()
+	if remaining <= 0 {
+		return 0, io.EOF
+	}
+	n := min(int64(len(p)), remaining)
+	c.read.Add(n)
+	return int(n), nil
+}
+
+// slowUpload sends 1 KiB chunks with delay between them, returning the
+// response. The first chunk is only followed by the others once firstReceived fires.
+func slowUpload(t *testing.T, url string, chunks int, delay time.Duration, firstReceived <-chan struct{}) *http.Response {
+	t.Helper()
+	reader, writer := io.Pipe()
+	go func() {
+		chunk := strings.Repeat("x", 1024)
+		for i := range chunks {
+			if _, err := io.WriteString(writer, chunk); err != nil {
+				return
+			}
+			if i == 0 && firstReceived != nil {
+				select {
+				case <-firstReceived:
+				case <-time.After(5 * time.Second):
+				}
+			}
+			time.Sleep(delay)
+		}
+		_ = writer.Close()
+	}()
+	req, err := http.NewRequest(http.MethodPut, url, reader)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestUploadConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		route RouteConfig
+	}{
+		{"negative max duration", RouteConfig{Upload: &UploadConfig{MaxDuration: -time.Second}}},
+		{"negative threshold", RouteConfig{Upload: &UploadConfig{ProgressThreshold: -1}}},
+		{"streaming with dry run", RouteConfig{DryRun: true, Upload: &UploadConfig{Streaming: true}}},
+		{"streaming with content translation", RouteConfig{
+			ContentTranslation: &ContentTranslationConfig{ClientFormats: []string{MediaTypeXML}},
+			Upload:             &UploadConfig{Streaming: true},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.ErrorIs(t, tt.route.Upload.validate(tt.route), ErrInvalidUploadConfig)
+		})
+	}
+	assert.NoError(t, (&UploadConfig{Streaming: true}).validate(RouteConfig{}))
+	assert.Equal(t, defaultUploadMaxDuration, (&UploadConfig{}).maxDuration())
+}
+
+func TestUpload_ExpectContinue(t *testing.T) {
+	_, proxyURL, _ := newUploadTestModule(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Expect") != "100-continue" {
+			http.Error(w, "expected 100-continue", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/files/too-large" {
+			// Rejected from the headers alone, without asking for the body
+			http.Error(w, "too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		countingBackend(w, r)
+	}, &UploadConfig{Streaming: true}, nil)
+
+	client := &http.Client{Transport: &http.Transport{ExpectContinueTimeout: 5 * time.Second}}
+	upload := func(path string, body *countingReader) *http.Response {
+		req, err := http.NewRequest(http.MethodPut, proxyURL+path, body)
+		require.NoError(t, err)
+		req.ContentLength = body.size
+		req.Header.Set("Expect", "100-continue")
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = resp.Body.Close() })
+		return resp
+	}
+
+	rejected := &countingReader{size: 1 << 20}
+	resp := upload("/files/too-large", rejected)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.Zero(t, rejected.read.Load(), "the client must not send a body the backend rejected")
+
+	accepted := &countingReader{size: 1 << 20}
+	resp = upload("/files/ok", accepted)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, strconv.Itoa(1<<20), readBody(t, resp))
+}
+
+func TestUpload_StreamingOutlastsRouteTimeout(t *testing.T) {
+	for _, circuitBreaker := range []bool{false, true} {
+		t.Run("circuit breaker "+strconv.FormatBool(circuitBreaker), func(t *testing.T) {
+			firstReceived := make(chan struct{})
+			var once atomic.Bool
+			_, proxyURL, _ := newUploadTestModule(t, func(w http.ResponseWriter, r *http.Request) {
+				buf := make([]byte, 1024)
+				n, _ := io.ReadFull(r.Body, buf)
+				if once.CompareAndSwap(false, true) {
+					close(firstReceived) // the body streams: the rest is still with the client
+				}
+				rest, _ := io.Copy(io.Discard, r.Body)
+				_, _ = io.WriteString(w, strconv.FormatInt(int64(n)+rest, 10))
+			}, &UploadConfig{Streaming: true, MaxDuration: 10 * time.Second}, func(cfg *ReverseProxyConfig) {
+				cfg.CircuitBreakerConfig = CircuitBreakerConfig{Enabled: circuitBreaker, RequestTimeout: 300 * time.Millisecond}
+			})
+
+			resp := slowUpload(t, proxyURL+"/files/big", 6, 150*time.Millisecond, firstReceived)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "6144", readBody(t, resp))
+		})
+	}
+}
+
+func TestUpload_WithoutStreamingRouteTimeoutCoversUpload(t *testing.T) {
+	_, proxyURL, _ := newUploadTestModule(t, countingBackend, nil, nil)
+
+	resp := slowUpload(t, proxyURL+"/files/big", 6, 150*time.Millisecond, nil)
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+}
+
+func TestUpload_StreamingKeepsResponseHeaderTimeout(t *testing.T) {
+	_, proxyURL, _ := newUploadTestModule(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-time.After(2 * time.Second):
+		case <-r.Context().Done():
+		}
+	}, &UploadConfig{Streaming: true, MaxDuration: 10 * time.Second}, nil)
+
+	start := time.Now()
+	resp, err := http.Post(proxyURL+"/files/slow", "text/plain", strings.NewReader("payload"))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestUpload_ProgressTracking(t *testing.T) {
+	m, proxyURL, subject := newUploadTestModule(t, countingBackend, &UploadConfig{Streaming: true, ProgressThreshold: 2048}, nil)
+
+	// An upload below the threshold is not tracked
+	resp, err := http.Post(proxyURL+"/files/small", "text/plain", strings.NewReader("small"))
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, &UploadStatus{Routes: []RouteUploadStatus{}, Active: []ActiveUploadStatus{}}, m.UploadStatus())
+
+	// A chunked upload is tracked once it crosses the threshold
+	reader, writer := io.Pipe()
+	done := make(chan *http.Response, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodPut, proxyURL+"/files/big", reader)
+		req.Header.Set("X-Tenant-ID", "acme")
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+		done <- resp
+	}()
+	_, err = io.WriteString(writer, strings.Repeat("x", 3000))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return len(m.UploadStatus().Active) == 1 && m.UploadStatus().Active[0].BytesReceived == 3000
+	}, 2*time.Second, 10*time.Millisecond)
+	active := m.UploadStatus().Active[0]
+	assert.Equal(t, "/files/*", active.Route)
+	assert.Equal(t, "acme", active.Tenant)
+	assert.Equal(t, "/files/big", active.Path)
+	assert.Equal(t, int64(-1), active.ExpectedBytes)
+
+	_, err = io.WriteString(writer, strings.Repeat("x", 1000))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	select {
+	case resp := <-done:
+		require.NotNil(t, resp)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	case <-time.After(5 * time.Second):
+		t.Fatal("upload did not finish")
+	}
+
+	status := m.UploadStatus()
+	assert.Empty(t, status.Active)
+	assert.Equal(t, []RouteUploadStatus{{Route: "/files/*", Completed: 1, Bytes: 4000}}, status.Routes)
+
+	events := subject.eventsOfType(EventTypeUploadFinished)
+	require.Len(t, events, 1)
+	var data map[string]interface{}
+	require.NoError(t, events[0].DataAs(&data))
+	assert.Equal(t, UploadOutcomeCompleted, data["outcome"])
+	assert.InDelta(t, 4000, data["bytes_received"], 0)
+
+	resp, err = http.Get(proxyURL + "/metrics?format=prometheus")
+	require.NoError(t, err)
+	metrics := readBody(t, resp)
+	require.NoError(t, resp.Body.Close())
+	assert.Contains(t, metrics, `reverseproxy_uploads_total{route="/files/*",outcome="completed"} 1`)
+	assert.Contains(t, metrics, `reverseproxy_upload_bytes_total{route="/files/*"} 4000`)
+
+	resp, err = http.Get(proxyURL + "/metrics")
+	require.NoError(t, err)
+	var body struct {
+		Uploads UploadStatus `json:"uploads"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.NoError(t, resp.Body.Close())
+	assert.Equal(t, status.Routes, body.Uploads.Routes)
+}
+
+func TestUpload_IncompleteUpload(t *testing.T) {
+	m, proxyURL, subject := newUploadTestModule(t, countingBackend, &UploadConfig{ProgressThreshold: 512}, nil)
+
+	// The client gives up halfway through the upload
+	reader, writer := io.Pipe()
+	go func() {
+		req, _ := http.NewRequest(http.MethodPut, proxyURL+"/files/big", reader)
+		req.ContentLength = 4096
+		if resp, err := http.DefaultClient.Do(req); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	_, err := io.WriteString(writer, strings.Repeat("x", 1024))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		active := m.UploadStatus().Active
+		return len(active) == 1 && active[0].BytesReceived == 1024 && active[0].ExpectedBytes == 4096
+	}, 2*time.Second, 10*time.Millisecond)
+	writer.CloseWithError(io.ErrUnexpectedEOF)
+
+	require.Eventually(t, func() bool { return len(subject.eventsOfType(EventTypeUploadFinished)) == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []RouteUploadStatus{{Route: "/files/*", Incomplete: 1, Bytes: 1024}}, m.UploadStatus().Routes)
+}
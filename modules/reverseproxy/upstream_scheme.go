@@ -0,0 +1,150 @@
+package reverseproxy
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Backend URL schemes handled by dedicated transports, in addition to http and https.
+const (
+	// SchemeUnix proxies to an HTTP server listening on a Unix domain socket,
+	// e.g. "unix:///var/run/app.sock".
+	SchemeUnix = "unix"
+
+	// SchemeH2C proxies using HTTP/2 over cleartext with prior knowledge,
+	// e.g. "h2c://service:8080".
+	SchemeH2C = "h2c"
+)
+
+// unixSocketHost is the Host used for requests to Unix socket backends.
+const unixSocketHost = "localhost"
+
+// upstreamTransport is the transport created for a unix or h2c backend. It is a
+// distinct type so request-scoped timeout handling, which rebuilds plain
+// *http.Transport values with a TCP dialer, keeps it intact.
+type upstreamTransport struct {
+	*http.Transport
+}
+
+// isUpstreamTransport reports whether transport was created for a unix or h2c backend.
+func isUpstreamTransport(transport http.RoundTripper) bool {
+	_, ok := transport.(*upstreamTransport)
+	return ok
+}
+
+// validateUpstreamURL checks the scheme-specific parts of a backend URL.
+func validateUpstreamURL(target *url.URL) error {
+	switch target.Scheme {
+	case SchemeUnix:
+		if target.Path == "" {
+			return fmt.Errorf("%w: unix backend URL needs a socket path, e.g. unix:///var/run/app.sock", ErrInvalidUpstreamURL)
+		}
+	case SchemeH2C:
+		if target.Host == "" {
+			return fmt.Errorf("%w: h2c backend URL needs a host, e.g. h2c://service:8080", ErrInvalidUpstreamURL)
+		}
+	}
+	return nil
+}
+
+// resolveUpstream maps a unix or h2c backend URL to the http URL the proxy director
+// should target and returns the transport that reaches it. Transports are shared by
+// all proxies for the same backend URL. Other URLs are returned unchanged with a nil
+// transport.
+func (m *ReverseProxyModule) resolveUpstream(target *url.URL) (*url.URL, *upstreamTransport, error) {
+	if target.Scheme != SchemeUnix && target.Scheme != SchemeH2C {
+		return target, nil, nil
+	}
+	if err := validateUpstreamURL(target); err != nil {
+		return nil, nil, err
+	}
+
+	resolved := *target
+	resolved.Scheme = "http"
+	if target.Scheme == SchemeUnix {
+		resolved.Host = unixSocketHost
+		resolved.Path = ""
+		resolved.RawPath = ""
+	}
+
+	key := target.String()
+	m.upstreamTransportsMutex.Lock()
+	defer m.upstreamTransportsMutex.Unlock()
+	if transport, ok := m.upstreamTransports[key]; ok {
+		return &resolved, transport, nil
+	}
+
+	transport := &upstreamTransport{Transport: m.baseUpstreamTransport()}
+	switch target.Scheme {
+	case SchemeUnix:
+		socketPath := target.Path
+		dialer := &net.Dialer{Timeout: 30 * time.Second}
+		transport.Proxy = nil
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socketPath)
+		}
+	case SchemeH2C:
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = protocols
+	}
+
+	if m.upstreamTransports == nil {
+		m.upstreamTransports = make(map[string]*upstreamTransport)
+	}
+	m.upstreamTransports[key] = transport
+	return &resolved, transport, nil
+}
+
+// newCompositeBackend creates a composite route backend. It uses the module's HTTP client
+// directly, or a client with the scheme-specific transport for unix and h2c backends.
+func (m *ReverseProxyModule) newCompositeBackend(backendID, backendURL string) *Backend {
+	backend := &Backend{ID: backendID, URL: backendURL, Client: m.httpClient}
+
+	target, err := url.Parse(backendURL)
+	if err != nil {
+		return backend
+	}
+	upstream, transport, err := m.resolveUpstream(target)
+	if err != nil || transport == nil {
+		return backend
+	}
+	backend.URL = strings.TrimSuffix(upstream.String(), "/")
+	backend.Client = &http.Client{Transport: transport}
+	if m.httpClient != nil {
+		backend.Client.Timeout = m.httpClient.Timeout
+	}
+	return backend
+}
+
+// baseUpstreamTransport returns a copy of the module's HTTP client transport, or the
+// default proxy transport settings when the client does not use an *http.Transport.
+func (m *ReverseProxyModule) baseUpstreamTransport() *http.Transport {
+	if m.httpClient != nil {
+		if t, ok := m.httpClient.Transport.(*http.Transport); ok {
+			return t.Clone()
+		}
+	}
+	return &http.Transport{
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 30 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+}
+
+// closeUpstreamTransports closes idle connections held by unix and h2c transports.
+func (m *ReverseProxyModule) closeUpstreamTransports() {
+	m.upstreamTransportsMutex.Lock()
+	defer m.upstreamTransportsMutex.Unlock()
+	for _, transport := range m.upstreamTransports {
+		transport.CloseIdleConnections()
+	}
+}
@@ -0,0 +1,115 @@
+package reverseproxy
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newUpstreamTestModule creates an initialized module with a single "api" backend.
+func newUpstreamTestModule(t *testing.T, backendURL string, circuitBreaker bool) *ReverseProxyModule {
+	t.Helper()
+
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices:      map[string]string{"api": backendURL},
+		RequestTimeout:       5 * time.Second,
+		CircuitBreakerConfig: CircuitBreakerConfig{Enabled: circuitBreaker},
+	}
+	if circuitBreaker {
+		m.circuitBreakers["api"] = NewCircuitBreakerWithConfig("api", m.config.CircuitBreakerConfig, nil)
+	}
+	require.NoError(t, m.createBackendProxy("api", backendURL))
+	m.initialized = true
+	t.Cleanup(m.closeUpstreamTransports)
+	return m
+}
+
+func TestUpstreamScheme_UnixSocket(t *testing.T) {
+	// Unix socket paths are length-limited, so avoid the long t.TempDir() path
+	dir, err := os.MkdirTemp("", "rp")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, "app.sock")
+
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Path", r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		}),
+		ReadHeaderTimeout: time.Second,
+	}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	for _, circuitBreaker := range []bool{false, true} {
+		m := newUpstreamTestModule(t, "unix://"+socketPath, circuitBreaker)
+
+		rec := httptest.NewRecorder()
+		m.createBackendProxyHandler("api")(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+		assert.Equal(t, http.StatusOK, rec.Code, "circuit breaker enabled: %v", circuitBreaker)
+		assert.Equal(t, "/api/users", rec.Header().Get("X-Path"))
+	}
+}
+
+func TestUpstreamScheme_H2C(t *testing.T) {
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Proto", r.Proto)
+		w.WriteHeader(http.StatusOK)
+	}))
+	backend.Config.Protocols = new(http.Protocols)
+	backend.Config.Protocols.SetHTTP1(true)
+	backend.Config.Protocols.SetUnencryptedHTTP2(true)
+	backend.Start()
+	t.Cleanup(backend.Close)
+
+	backendURL, err := url.Parse(backend.URL)
+	require.NoError(t, err)
+	m := newUpstreamTestModule(t, "h2c://"+backendURL.Host, false)
+
+	rec := httptest.NewRecorder()
+	m.createBackendProxyHandler("api")(rec, httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "HTTP/2.0", rec.Header().Get("X-Proto"))
+
+	// Composite routes reach h2c backends through the same transport
+	composite := m.newCompositeBackend("api", "h2c://"+backendURL.Host)
+	assert.Equal(t, backend.URL, composite.URL)
+	assert.NotSame(t, m.httpClient, composite.Client)
+}
+
+func TestUpstreamScheme_TransportsSurviveClientChanges(t *testing.T) {
+	m := newUpstreamTestModule(t, "unix:///var/run/app.sock", false)
+
+	m.SetHttpClient(&http.Client{Transport: &http.Transport{}})
+	assert.True(t, isUpstreamTransport(m.backendProxies["api"].Transport))
+}
+
+func TestValidateUpstreamURL(t *testing.T) {
+	for raw, valid := range map[string]bool{
+		"unix:///var/run/app.sock": true,
+		"unix://":                  false,
+		"h2c://service:8080":       true,
+		"h2c:///path":              false,
+		"http://service:8080":      true,
+	} {
+		target, err := url.Parse(raw)
+		require.NoError(t, err)
+		err = validateUpstreamURL(target)
+		if valid {
+			assert.NoError(t, err, raw)
+		} else {
+			assert.ErrorIs(t, err, ErrInvalidUpstreamURL, raw)
+		}
+	}
+}
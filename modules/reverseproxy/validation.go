@@ -0,0 +1,323 @@
+package reverseproxy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/CrisisTextLine/modular"
+)
+
+// ConfigIssueSeverity indicates how serious a configuration issue is.
+type ConfigIssueSeverity string
+
+const (
+	// ConfigIssueError marks configuration that will fail or misroute requests at runtime
+	ConfigIssueError ConfigIssueSeverity = "error"
+	// ConfigIssueWarning marks configuration that is suspicious but may be intentional
+	ConfigIssueWarning ConfigIssueSeverity = "warning"
+)
+
+// ConfigIssue describes a single problem found by ValidateFull.
+type ConfigIssue struct {
+	Severity ConfigIssueSeverity `json:"severity"`
+	// Field is the configuration path of the offending value, e.g. "route_configs./api/*.alternative_backend"
+	Field string `json:"field"`
+	// Tenant is set when the issue comes from a tenant's merged configuration
+	Tenant  modular.TenantID `json:"tenant,omitempty"`
+	Message string           `json:"message"`
+}
+
+// String formats the issue for logging.
+func (i ConfigIssue) String() string {
+	if i.Tenant != "" {
+		return fmt.Sprintf("[%s] tenant %s: %s: %s", i.Severity, i.Tenant, i.Field, i.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Field, i.Message)
+}
+
+// ConfigValidationReport is the structured result of ValidateFull.
+type ConfigValidationReport struct {
+	Issues []ConfigIssue `json:"issues"`
+}
+
+// HasErrors reports whether any issue has error severity.
+func (r *ConfigValidationReport) HasErrors() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == ConfigIssueError {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns nil when the report has no errors, otherwise an error wrapping
+// ErrConfigValidationFailed that lists every error-severity issue.
+func (r *ConfigValidationReport) Err() error {
+	var messages []string
+	for _, issue := range r.Issues {
+		if issue.Severity == ConfigIssueError {
+			messages = append(messages, issue.String())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrConfigValidationFailed, strings.Join(messages, "; "))
+}
+
+// ValidateFull checks the complete module configuration, including tenant
+// overrides, without starting the proxy. Unlike the validation performed during
+// Init, which stops at the first problem, it collects every issue it finds:
+// routes and composite routes referencing unknown backends, missing alternative
+// and dry-run backends, feature flags referenced while flag evaluation is
+// disabled, and malformed backend URLs.
+//
+// It is intended for a validate-only startup mode: initialize the application,
+// call ValidateFull, print the report and exit. The returned error is non-nil
+// only when validation could not be performed; configuration problems are
+// reported in the returned report.
+func (m *ReverseProxyModule) ValidateFull(ctx context.Context) (*ConfigValidationReport, error) {
+	cfg := m.config
+	if cfg == nil && m.app != nil {
+		section, err := m.app.GetConfigSection(m.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get config section '%s': %w", m.Name(), err)
+		}
+		switch v := section.GetConfig().(type) {
+		case *ReverseProxyConfig:
+			cfg = v
+		case ReverseProxyConfig:
+			cfg = &v
+		default:
+			return nil, fmt.Errorf("%w: %T", ErrUnexpectedConfigType, v)
+		}
+	}
+	if cfg == nil {
+		return nil, ErrConfigurationNil
+	}
+
+	report := &ConfigValidationReport{}
+	m.validateConfigFull(cfg, "", report)
+
+	tenants := m.tenantConfigs()
+	tenantIDs := make([]modular.TenantID, 0, len(tenants))
+	for tenantID := range tenants {
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	sort.Slice(tenantIDs, func(i, j int) bool { return tenantIDs[i] < tenantIDs[j] })
+
+	for _, tenantID := range tenantIDs {
+		if err := ctx.Err(); err != nil {
+			return report, fmt.Errorf("config validation interrupted: %w", err)
+		}
+
+		tenantCfg := tenants[tenantID]
+		if tenantCfg == nil && m.tenantApp != nil {
+			cp, err := m.tenantApp.GetTenantConfig(tenantID, m.Name())
+			if err != nil {
+				continue
+			}
+			if raw, ok := cp.GetConfig().(*ReverseProxyConfig); ok {
+				tenantCfg = mergeConfigs(cfg, raw)
+			}
+		}
+		if tenantCfg == nil {
+			continue
+		}
+		m.validateConfigFull(tenantCfg, tenantID, report)
+	}
+
+	return report, nil
+}
+
+// configValidator accumulates issues for a single (global or tenant) configuration.
+type configValidator struct {
+	cfg      *ReverseProxyConfig
+	tenant   modular.TenantID
+	report   *ConfigValidationReport
+	flagsOn  bool
+	backends map[string]bool
+}
+
+func (v *configValidator) add(severity ConfigIssueSeverity, field, format string, args ...any) {
+	v.report.Issues = append(v.report.Issues, ConfigIssue{
+		Severity: severity,
+		Field:    field,
+		Tenant:   v.tenant,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// checkBackend reports an error if backend is set but not defined.
+func (v *configValidator) checkBackend(field, backend string) {
+	if backend == "" || v.backends[backend] {
+		return
+	}
+	v.add(ConfigIssueError, field, "references unknown backend %q", backend)
+}
+
+// checkFlag reports flags that can never take effect.
+func (v *configValidator) checkFlag(field, flagID string) {
+	if flagID == "" {
+		return
+	}
+	if !v.flagsOn {
+		v.add(ConfigIssueWarning, field, "feature flag %q is referenced but feature flag evaluation is disabled; the flag will be treated as enabled", flagID)
+		return
+	}
+	if _, defined := v.cfg.FeatureFlags.Flags[flagID]; !defined {
+		v.add(ConfigIssueWarning, field, "feature flag %q has no default in feature_flags.flags; it must be provided by a tenant or external evaluator", flagID)
+	}
+}
+
+// validateConfigFull appends every issue found in cfg to report.
+func (m *ReverseProxyModule) validateConfigFull(cfg *ReverseProxyConfig, tenant modular.TenantID, report *ConfigValidationReport) {
+	v := &configValidator{
+		cfg:      cfg,
+		tenant:   tenant,
+		report:   report,
+		flagsOn:  cfg.FeatureFlags.Enabled || m.featureFlagEvaluatorProvided,
+		backends: make(map[string]bool, len(cfg.BackendServices)),
+	}
+
+	for _, backendID := range sortedKeys(cfg.BackendServices) {
+		v.backends[backendID] = true
+		serviceURL := cfg.BackendServices[backendID]
+		if serviceURL == "" {
+			if bc, ok := cfg.BackendConfigs[backendID]; !ok || bc.URL == "" {
+				v.add(ConfigIssueWarning, "backend_services."+backendID, "backend has no URL; requests will fail unless a tenant provides one")
+			}
+			continue
+		}
+		validateBackendURL(v, "backend_services."+backendID, serviceURL)
+	}
+
+	for _, backendID := range sortedKeys(cfg.BackendConfigs) {
+		bc := cfg.BackendConfigs[backendID]
+		field := "backend_configs." + backendID
+		if !v.backends[backendID] {
+			v.add(ConfigIssueWarning, field, "configuration for backend %q which is not defined in backend_services", backendID)
+		}
+		if bc.URL != "" {
+			validateBackendURL(v, field+".url", bc.URL)
+		}
+		v.checkBackend(field+".alternative_backend", bc.AlternativeBackend)
+		for _, alt := range bc.AlternativeBackends {
+			v.checkBackend(field+".alternative_backends", alt)
+		}
+		v.checkFlag(field+".feature_flag_id", firstNonEmpty(bc.FeatureFlagID, bc.FeatureFlag))
+	}
+
+	for _, backendID := range sortedKeys(cfg.BackendCircuitBreakers) {
+		if !v.backends[backendID] {
+			v.add(ConfigIssueWarning, "backend_circuit_breakers."+backendID, "circuit breaker configured for unknown backend %q", backendID)
+		}
+	}
+
+	v.checkBackend("default_backend", cfg.DefaultBackend)
+
+	for _, pattern := range sortedKeys(cfg.Routes) {
+		field := "routes." + pattern
+		group := cfg.Routes[pattern]
+		if strings.TrimSpace(group) == "" {
+			v.add(ConfigIssueError, field, "route has no backend")
+			continue
+		}
+		for _, backend := range strings.Split(group, ",") {
+			v.checkBackend(field, strings.TrimSpace(backend))
+		}
+	}
+
+	for _, pattern := range sortedKeys(cfg.RouteConfigs) {
+		rc := cfg.RouteConfigs[pattern]
+		field := "route_configs." + pattern
+		if _, routed := cfg.Routes[pattern]; !routed {
+			if _, composite := cfg.CompositeRoutes[pattern]; !composite {
+				v.add(ConfigIssueWarning, field, "route config has no matching entry in routes or composite_routes")
+			}
+		}
+		v.checkBackend(field+".alternative_backend", rc.AlternativeBackend)
+		for _, alt := range rc.AlternativeBackends {
+			v.checkBackend(field+".alternative_backends", alt)
+		}
+		for _, backend := range rc.CompositeBackends {
+			v.checkBackend(field+".composite_backends", backend)
+		}
+		v.checkBackend(field+".dry_run_backend", rc.DryRunBackend)
+		flagID := firstNonEmpty(rc.FeatureFlagID, rc.FeatureFlag)
+		v.checkFlag(field+".feature_flag_id", flagID)
+		if flagID != "" && rc.AlternativeBackend == "" && len(rc.AlternativeBackends) == 0 && cfg.DefaultBackend == "" {
+			v.add(ConfigIssueWarning, field, "feature flag %q has no alternative backend and no default_backend to fall back to", flagID)
+		}
+		if rc.DryRun {
+			if !cfg.DryRun.Enabled {
+				v.add(ConfigIssueWarning, field+".dry_run", "dry run requested but dry_run.enabled is false")
+			}
+			if rc.DryRunBackend == "" && rc.AlternativeBackend == "" {
+				v.add(ConfigIssueError, field+".dry_run_backend", "dry run requires dry_run_backend or alternative_backend")
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(cfg.CompositeRoutes) {
+		cr := cfg.CompositeRoutes[name]
+		field := "composite_routes." + name
+		if len(cr.Backends) == 0 {
+			v.add(ConfigIssueError, field+".backends", "composite route has no backends")
+		}
+		for _, backend := range cr.Backends {
+			v.checkBackend(field+".backends", backend)
+		}
+		v.checkBackend(field+".alternative_backend", cr.AlternativeBackend)
+		v.checkFlag(field+".feature_flag_id", cr.FeatureFlagID)
+	}
+
+	for i, sr := range cfg.ScheduledRoutes {
+		v.checkBackend(fmt.Sprintf("scheduled_routes[%d].backend", i), sr.Backend)
+	}
+
+	if cfg.RequireTenantID && cfg.TenantIDHeader == "" {
+		v.add(ConfigIssueError, "tenant_id_header", "%s", ErrTenantIDRequired.Error())
+	}
+}
+
+// validateBackendURL reports URLs that parse but cannot be proxied to.
+func validateBackendURL(v *configValidator, field, rawURL string) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		v.add(ConfigIssueError, field, "invalid URL %q: %v", rawURL, errors.Unwrap(err))
+		return
+	}
+	switch parsed.Scheme {
+	case SchemeUnix, SchemeH2C:
+		if err := validateUpstreamURL(parsed); err != nil {
+			v.add(ConfigIssueError, field, "URL %q: %v", rawURL, err)
+		}
+	default:
+		if parsed.Scheme == "" || parsed.Host == "" {
+			v.add(ConfigIssueError, field, "URL %q must include a scheme and host", rawURL)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package reverseproxy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issueFields(report *ConfigValidationReport, severity ConfigIssueSeverity, tenant modular.TenantID) []string {
+	var fields []string
+	for _, issue := range report.Issues {
+		if issue.Severity == severity && issue.Tenant == tenant {
+			fields = append(fields, issue.Field)
+		}
+	}
+	return fields
+}
+
+func TestValidateFull_ValidConfig(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api":    "http://api.internal:8080",
+			"legacy": "http://legacy.internal:8080",
+		},
+		DefaultBackend: "api",
+		Routes:         map[string]string{"/api/*": "api,legacy"},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*": {FeatureFlagID: "new-api", AlternativeBackend: "legacy"},
+		},
+		FeatureFlags: FeatureFlagsConfig{Enabled: true, Flags: map[string]bool{"new-api": true}},
+	}
+
+	report, err := m.ValidateFull(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, report.Issues)
+	assert.NoError(t, report.Err())
+}
+
+func TestValidateFull_UpstreamSchemes(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"socket":      "unix:///var/run/app.sock",
+			"grpc":        "h2c://grpc.internal:8080",
+			"no-socket":   "unix://",
+			"no-h2c-host": "h2c:///path",
+		},
+	}
+
+	report, err := m.ValidateFull(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"backend_services.no-socket",
+		"backend_services.no-h2c-host",
+	}, issueFields(report, ConfigIssueError, ""))
+}
+
+func TestValidateFull_CollectsAllIssues(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{
+			"api":    "http://api.internal:8080",
+			"broken": "api.internal",
+		},
+		DefaultBackend: "missing-default",
+		Routes: map[string]string{
+			"/api/*":   "api",
+			"/users/*": "users",
+		},
+		RouteConfigs: map[string]RouteConfig{
+			"/api/*": {FeatureFlagID: "new-api", AlternativeBackend: "api-v1"},
+		},
+		CompositeRoutes: map[string]CompositeRoute{
+			"/dashboard": {Pattern: "/dashboard", Backends: []string{"api", "stats"}},
+		},
+		BackendCircuitBreakers: map[string]CircuitBreakerConfig{"ghost": {Enabled: true}},
+	}
+
+	report, err := m.ValidateFull(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"backend_services.broken",
+		"default_backend",
+		"routes./users/*",
+		"route_configs./api/*.alternative_backend",
+		"composite_routes./dashboard.backends",
+	}, issueFields(report, ConfigIssueError, ""))
+	assert.ElementsMatch(t, []string{
+		"backend_circuit_breakers.ghost",
+		"route_configs./api/*.feature_flag_id",
+	}, issueFields(report, ConfigIssueWarning, ""))
+
+	assert.True(t, report.HasErrors())
+	assert.ErrorIs(t, report.Err(), ErrConfigValidationFailed)
+}
+
+func TestValidateFull_TenantOverrides(t *testing.T) {
+	m := NewModule()
+	m.config = &ReverseProxyConfig{
+		BackendServices: map[string]string{"api": "http://api.internal:8080"},
+		Routes:          map[string]string{"/api/*": "api"},
+	}
+	m.tenants[modular.TenantID("tenant-a")] = mergeConfigs(m.config, &ReverseProxyConfig{
+		Routes: map[string]string{"/reports/*": "reports"},
+	})
+
+	report, err := m.ValidateFull(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, issueFields(report, ConfigIssueError, ""))
+	assert.Equal(t, []string{"routes./reports/*"}, issueFields(report, ConfigIssueError, "tenant-a"))
+}
+
+func TestValidateFull_NoConfig(t *testing.T) {
+	m := NewModule()
+	_, err := m.ValidateFull(context.Background())
+	assert.ErrorIs(t, err, ErrConfigurationNil)
+}
@@ -0,0 +1,100 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// holidayLayout is the date format used for holidays in CalendarConfig.
+const holidayLayout = "2006-01-02"
+
+// CalendarConfig defines periods during which jobs do not run. Recurring jobs skip
+// occurrences that fall inside an exclusion; one-time jobs are deferred until it ends.
+//
+//	calendar:
+//	  timezone: America/New_York
+//	  holidays: ["2025-12-25", "2026-01-01"]
+//	  blackouts:
+//	    - name: year-end-freeze
+//	      start: 2025-12-20T00:00:00Z
+//	      end: 2026-01-02T00:00:00Z
+type CalendarConfig struct {
+	// Timezone is the IANA location holidays are evaluated in. Defaults to the local timezone.
+	Timezone string `json:"timezone" yaml:"timezone" env:"CALENDAR_TIMEZONE"`
+
+	// Holidays are whole days (YYYY-MM-DD) on which jobs do not run
+	Holidays []string `json:"holidays" yaml:"holidays"`
+
+	// Blackouts are time windows during which jobs do not run
+	Blackouts []BlackoutWindow `json:"blackouts" yaml:"blackouts"`
+}
+
+// BlackoutWindow is a named time range, inclusive of Start and exclusive of End.
+type BlackoutWindow struct {
+	Name  string    `json:"name" yaml:"name"`
+	Start time.Time `json:"start" yaml:"start"`
+	End   time.Time `json:"end" yaml:"end"`
+}
+
+// Calendar answers whether a point in time falls inside a holiday or blackout window.
+type Calendar struct {
+	windows []BlackoutWindow
+}
+
+// NewCalendar builds a Calendar from config. Holidays become day-long windows in the
+// configured timezone.
+func NewCalendar(config CalendarConfig) (*Calendar, error) {
+	location := time.Local
+	if config.Timezone != "" {
+		loc, err := time.LoadLocation(config.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("%w: timezone %q: %w", ErrInvalidCalendar, config.Timezone, err)
+		}
+		location = loc
+	}
+
+	calendar := &Calendar{}
+	for _, holiday := range config.Holidays {
+		day, err := time.ParseInLocation(holidayLayout, holiday, location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: holiday %q must be YYYY-MM-DD", ErrInvalidCalendar, holiday)
+		}
+		calendar.windows = append(calendar.windows, BlackoutWindow{
+			Name:  "holiday " + holiday,
+			Start: day,
+			End:   day.AddDate(0, 0, 1),
+		})
+	}
+	for _, window := range config.Blackouts {
+		if !window.End.After(window.Start) {
+			return nil, fmt.Errorf("%w: blackout %q must end after it starts", ErrInvalidCalendar, window.Name)
+		}
+		calendar.windows = append(calendar.windows, window)
+	}
+	return calendar, nil
+}
+
+// Blocked returns the exclusion window containing t, if any. A nil Calendar blocks nothing.
+func (c *Calendar) Blocked(t time.Time) (BlackoutWindow, bool) {
+	if c == nil {
+		return BlackoutWindow{}, false
+	}
+	for _, window := range c.windows {
+		if !t.Before(window.Start) && t.Before(window.End) {
+			return window, true
+		}
+	}
+	return BlackoutWindow{}, false
+}
+
+// NextAllowed returns the earliest time at or after t that is outside every exclusion.
+// Adjacent and overlapping windows are skipped together.
+func (c *Calendar) NextAllowed(t time.Time) time.Time {
+	for {
+		window, blocked := c.Blocked(t)
+		if !blocked {
+			return t
+		}
+		t = window.End
+	}
+}
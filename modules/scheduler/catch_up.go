@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+)
+
+// CatchUpPolicy decides what happens to a job whose run was missed because the
+// application was down when it was due.
+type CatchUpPolicy string
+
+const (
+	// CatchUpOnce runs the job once on restart, however many runs were missed (default)
+	CatchUpOnce CatchUpPolicy = "once"
+	// CatchUpSkip drops the missed runs. Recurring jobs wait for their next
+	// occurrence; one-time jobs are cancelled.
+	CatchUpSkip CatchUpPolicy = "skip"
+)
+
+// SkipReasonMissed is reported in job skipped events for runs missed while the
+// application was down and not caught up.
+const SkipReasonMissed = "missed"
+
+// validate reports whether p is a known policy. The empty policy means the default.
+func (p CatchUpPolicy) validate() error {
+	switch p {
+	case "", CatchUpOnce, CatchUpSkip:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidCatchUpPolicy, p)
+	}
+}
+
+// catchUpFor returns the catch-up policy for a job.
+func (s *Scheduler) catchUpFor(job Job) CatchUpPolicy {
+	if job.CatchUp != "" {
+		return job.CatchUp
+	}
+	return s.catchUpPolicy
+}
+
+// catchUp reschedules a job whose run at missed passed while the application was
+// down, applying its catch-up policy. It reports false when the job won't run again.
+func (s *Scheduler) catchUp(job Job, missed, now time.Time) (Job, bool) {
+	policy := s.catchUpFor(job)
+	if policy == CatchUpOnce && (s.catchUpWindow <= 0 || now.Sub(missed) <= s.catchUpWindow) {
+		job.NextRun = &now
+		return job, true
+	}
+
+	details := map[string]interface{}{
+		"missed_run":      missed.Format(time.RFC3339),
+		"catch_up_policy": string(policy),
+	}
+	if !job.IsRecurring {
+		job.Status = JobStatusCancelled
+		job.NextRun = nil
+		s.emitSkipped(job, SkipReasonMissed, details)
+		return job, false
+	}
+	next := s.nextRunAfter(job, now)
+	job.NextRun = &next
+	details["next_run"] = next.Format(time.RFC3339)
+	s.emitSkipped(job, SkipReasonMissed, details)
+	return job, true
+}
@@ -0,0 +1,173 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// persistMissedJobs returns a persistence handler holding an hourly job and a
+// one-time job whose runs were due two hours ago.
+func persistMissedJobs(t *testing.T, reminderCatchUp CatchUpPolicy) *MemoryPersistenceHandler {
+	t.Helper()
+	missed := time.Now().Add(-2 * time.Hour)
+	handler := NewMemoryPersistenceHandler()
+	require.NoError(t, handler.Save([]Job{
+		{ID: "report", Name: "report", Schedule: "0 * * * *", IsRecurring: true, Status: JobStatusPending, NextRun: &missed},
+		{ID: "reminder", Name: "reminder", RunAt: missed, Status: JobStatusPending, CatchUp: reminderCatchUp},
+	}))
+	return handler
+}
+
+// initCatchUpModule initializes a module restoring jobs from handler.
+func initCatchUpModule(t *testing.T, handler PersistenceHandler, policy CatchUpPolicy, window time.Duration) *SchedulerModule {
+	t.Helper()
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&SchedulerConfig{
+		WorkerCount:        1,
+		QueueSize:          10,
+		StorageType:        "memory",
+		CheckInterval:      time.Hour,
+		ShutdownTimeout:    time.Second,
+		PersistenceBackend: PersistenceBackendCustom,
+		PersistenceHandler: handler,
+		CatchUpPolicy:      policy,
+		CatchUpWindow:      window,
+	}))
+	module := NewModule().(*SchedulerModule)
+	require.NoError(t, module.Init(app))
+	return module
+}
+
+func assertJobState(t *testing.T, module *SchedulerModule, id string, status JobStatus, due bool) {
+	t.Helper()
+	job, err := module.GetJob(id)
+	require.NoError(t, err)
+	assert.Equal(t, status, job.Status, id)
+	if status == JobStatusCancelled {
+		assert.Nil(t, job.NextRun, id)
+		return
+	}
+	require.NotNil(t, job.NextRun, id)
+	assert.Equal(t, due, !job.NextRun.After(time.Now()), "%s due now", id)
+}
+
+func TestCatchUp_OnceRunsMissedJobsOnRestart(t *testing.T) {
+	module := initCatchUpModule(t, persistMissedJobs(t, ""), CatchUpOnce, 0)
+	assertJobState(t, module, "report", JobStatusPending, true)
+	assertJobState(t, module, "reminder", JobStatusPending, true)
+
+	// Functions registered by name run the restored jobs
+	var reports, reminders atomic.Int32
+	module.RegisterJobFunc("report", func(context.Context) error { reports.Add(1); return nil })
+	module.RegisterJobFunc("reminder", func(context.Context) error { reminders.Add(1); return nil })
+	require.NoError(t, module.Start(context.Background()))
+	t.Cleanup(func() { _ = module.Stop(context.Background()) })
+
+	require.Eventually(t, func() bool { return reports.Load() == 1 && reminders.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+	assertJobState(t, module, "report", JobStatusPending, false)
+}
+
+func TestCatchUp_SkipWaitsForNextOccurrence(t *testing.T) {
+	module := initCatchUpModule(t, persistMissedJobs(t, ""), CatchUpSkip, 0)
+	assertJobState(t, module, "report", JobStatusPending, false)
+	assertJobState(t, module, "reminder", JobStatusCancelled, false)
+}
+
+func TestCatchUp_WindowSkipsOldMissedRuns(t *testing.T) {
+	module := initCatchUpModule(t, persistMissedJobs(t, ""), CatchUpOnce, time.Hour)
+	assertJobState(t, module, "report", JobStatusPending, false)
+	assertJobState(t, module, "reminder", JobStatusCancelled, false)
+
+	module = initCatchUpModule(t, persistMissedJobs(t, ""), CatchUpOnce, 3*time.Hour)
+	assertJobState(t, module, "report", JobStatusPending, true)
+	assertJobState(t, module, "reminder", JobStatusPending, true)
+}
+
+func TestCatchUp_JobPolicyOverridesDefault(t *testing.T) {
+	module := initCatchUpModule(t, persistMissedJobs(t, CatchUpSkip), CatchUpOnce, 0)
+	assertJobState(t, module, "report", JobStatusPending, true)
+	assertJobState(t, module, "reminder", JobStatusCancelled, false)
+}
+
+func TestCatchUp_EmitsSkippedEvents(t *testing.T) {
+	emitter := &recordingEmitter{}
+	s := NewScheduler(NewMemoryJobStore(time.Hour), WithEventEmitter(emitter), WithCatchUpPolicy(CatchUpSkip, 0))
+	now := time.Now()
+	missed := now.Add(-time.Hour)
+
+	job, active := s.catchUp(Job{ID: "report", Name: "report", Schedule: "*/5 * * * *", IsRecurring: true}, missed, now)
+	assert.True(t, active)
+	require.NotNil(t, job.NextRun)
+	assert.True(t, job.NextRun.After(now))
+
+	_, active = s.catchUp(Job{ID: "reminder", Name: "reminder", RunAt: missed}, missed, now)
+	assert.False(t, active)
+
+	skipped := emitter.dataOf(t, EventTypeJobSkipped)
+	require.Len(t, skipped, 2)
+	for _, data := range skipped {
+		assert.Equal(t, SkipReasonMissed, data["reason"])
+		assert.Equal(t, missed.Format(time.RFC3339), data["missed_run"])
+		assert.Equal(t, string(CatchUpSkip), data["catch_up_policy"])
+	}
+	assert.Equal(t, job.NextRun.Format(time.RFC3339), skipped[0]["next_run"])
+}
+
+func TestSchedulerModule_ValidatesCatchUpAndTimezone(t *testing.T) {
+	for name, config := range map[string]struct {
+		config SchedulerConfig
+		err    error
+	}{
+		"catch-up policy": {SchedulerConfig{CatchUpPolicy: "all"}, ErrInvalidCatchUpPolicy},
+		"timezone":        {SchedulerConfig{Timezone: "Mars/Olympus_Mons"}, ErrInvalidTimezone},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.config
+			cfg.WorkerCount, cfg.QueueSize, cfg.StorageType = 1, 1, "memory"
+			app := newMockApp()
+			app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&cfg))
+			require.ErrorIs(t, NewModule().(*SchedulerModule).Init(app), config.err)
+		})
+	}
+}
+
+func TestScheduler_Timezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	s := NewScheduler(NewMemoryJobStore(time.Hour), WithTimezone("Asia/Tokyo"))
+
+	nextRunIn := func(id string, location *time.Location) time.Time {
+		job, err := s.GetJob(id)
+		require.NoError(t, err)
+		require.NotNil(t, job.NextRun)
+		return job.NextRun.In(location)
+	}
+
+	id, err := s.ScheduleRecurring("tokyo-digest", "30 9 * * *", nil)
+	require.NoError(t, err)
+	next := nextRunIn(id, tokyo)
+	assert.Equal(t, 9, next.Hour())
+	assert.Equal(t, 30, next.Minute())
+
+	// A job's timezone overrides the scheduler's
+	id, err = s.ScheduleJob(Job{Name: "ny-digest", Schedule: "30 9 * * *", IsRecurring: true, Timezone: "America/New_York"})
+	require.NoError(t, err)
+	assert.Equal(t, 9, nextRunIn(id, newYork).Hour())
+
+	// So does a CRON_TZ prefix in the expression
+	id, err = s.ScheduleRecurring("ny-prefixed", "CRON_TZ=America/New_York 30 9 * * *", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 9, nextRunIn(id, newYork).Hour())
+	assert.Equal(t, "CRON_TZ=America/New_York 30 9 * * *", s.cronSpec(Job{Schedule: "CRON_TZ=America/New_York 30 9 * * *"}))
+
+	_, err = s.ScheduleJob(Job{Name: "nowhere", Schedule: "30 9 * * *", IsRecurring: true, Timezone: "Mars/Olympus_Mons"})
+	require.Error(t, err)
+}
@@ -21,6 +21,8 @@ const (
 	PersistenceBackendMemory PersistenceBackend = "memory"
 	// PersistenceBackendCustom allows injection of custom persistence handlers
 	PersistenceBackendCustom PersistenceBackend = "custom"
+	// PersistenceBackendDatabase stores jobs in a table of the database module's database
+	PersistenceBackendDatabase PersistenceBackend = "database"
 )
 
 // PersistenceHandler defines the interface for custom persistence backends
@@ -54,6 +56,52 @@ type SchedulerConfig struct {
 	// PersistenceBackend determines the type of persistence to use
 	PersistenceBackend PersistenceBackend `json:"persistenceBackend" yaml:"persistenceBackend" env:"PERSISTENCE_BACKEND" default:"none"`
 
+	// Jitter delays each job run by a random duration below this value so instances
+	// sharing a schedule don't all fire at once. Zero disables jitter.
+	Jitter time.Duration `json:"jitter" yaml:"jitter" env:"JITTER"`
+
+	// MaxConcurrentPerJob limits simultaneous executions of the same job. Zero
+	// allows any number of overlapping executions.
+	MaxConcurrentPerJob int `json:"maxConcurrentPerJob" yaml:"maxConcurrentPerJob" env:"MAX_CONCURRENT_PER_JOB"`
+
+	// OverlapPolicy applies when a job is triggered while MaxConcurrentPerJob
+	// executions are running: skip, queue or replace
+	OverlapPolicy OverlapPolicy `json:"overlapPolicy" yaml:"overlapPolicy" env:"OVERLAP_POLICY" default:"skip"`
+
+	// Calendar defines holidays and blackout windows during which jobs do not run
+	Calendar CalendarConfig `json:"calendar" yaml:"calendar"`
+
+	// Timezone is the IANA location cron schedules are evaluated in, unless a job sets
+	// its own. Defaults to the local timezone.
+	Timezone string `json:"timezone" yaml:"timezone" env:"TIMEZONE"`
+
+	// CatchUpPolicy applies to runs missed while the application was down: once runs
+	// a missed job once on restart, skip waits for its next occurrence
+	CatchUpPolicy CatchUpPolicy `json:"catchUpPolicy" yaml:"catchUpPolicy" env:"CATCH_UP_POLICY" default:"once"`
+
+	// CatchUpWindow limits catching up to runs missed within this duration; older
+	// missed runs are skipped. Zero catches up runs missed any time ago.
+	CatchUpWindow time.Duration `json:"catchUpWindow" yaml:"catchUpWindow" env:"CATCH_UP_WINDOW"`
+
+	// PersistInterval saves jobs periodically while the scheduler runs, so they
+	// survive a crash. Zero saves jobs only when the module stops.
+	PersistInterval time.Duration `json:"persistInterval" yaml:"persistInterval" env:"PERSIST_INTERVAL"`
+
+	// PersistenceTable is the table the database backend stores jobs in
+	PersistenceTable string `json:"persistenceTable" yaml:"persistenceTable" env:"PERSISTENCE_TABLE" default:"scheduler_jobs"`
+
+	// DatabaseDialect is postgres or mysql. Only used by the database backend.
+	DatabaseDialect Dialect `json:"databaseDialect" yaml:"databaseDialect" env:"DATABASE_DIALECT" default:"postgres"`
+
+	// LockJobs runs every job under a lock of the injected lock service (LockServiceName),
+	// so that when several instances schedule the same job only one runs each occurrence.
+	// Jobs are locked by name.
+	LockJobs bool `json:"lockJobs" yaml:"lockJobs" env:"LOCK_JOBS"`
+
+	// LockTTL is how long a job's lock is held without a refresh; it is refreshed while
+	// the job runs. Zero uses the lock service's default TTL.
+	LockTTL time.Duration `json:"lockTTL" yaml:"lockTTL" env:"LOCK_TTL"`
+
 	// PersistenceHandler allows injection of custom persistence logic
 	// This field is not serializable and must be set programmatically
 	PersistenceHandler PersistenceHandler `json:"-" yaml:"-"`
@@ -0,0 +1,182 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+)
+
+// DBProvider is implemented by services exposing a database connection pool, such
+// as the database module's database.service.
+type DBProvider interface {
+	DB() *sql.DB
+}
+
+// Dialect identifies the SQL placeholder syntax used by the database backend.
+type Dialect string
+
+const (
+	// DialectPostgres uses $1-style placeholders
+	DialectPostgres Dialect = "postgres"
+	// DialectMySQL uses ?-style placeholders
+	DialectMySQL Dialect = "mysql"
+)
+
+// DefaultPersistenceTable is the table the database backend stores jobs in by default.
+const DefaultPersistenceTable = "scheduler_jobs"
+
+// tableNamePattern matches table names, optionally schema-qualified, that are safe
+// to interpolate into queries.
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// DatabasePersistenceHandler implements PersistenceHandler by storing each job as
+// a JSON document in a database table, which it creates if missing:
+//
+//	CREATE TABLE scheduler_jobs (id VARCHAR(255) PRIMARY KEY, data TEXT NOT NULL)
+//
+// Save upserts the jobs by ID in a transaction and deletes the jobs this handler
+// loaded or saved before that are no longer saved, so scheduler instances sharing
+// the table keep each other's jobs.
+type DatabasePersistenceHandler struct {
+	db      *sql.DB
+	table   string
+	dialect Dialect
+
+	mu sync.Mutex
+	// owned holds the IDs of the jobs this handler loaded or saved
+	owned map[string]struct{}
+}
+
+// NewDatabasePersistenceHandler creates a handler storing jobs in table. An empty
+// table uses DefaultPersistenceTable and an empty dialect DialectPostgres.
+func NewDatabasePersistenceHandler(db *sql.DB, table string, dialect Dialect) (*DatabasePersistenceHandler, error) {
+	if table == "" {
+		table = DefaultPersistenceTable
+	}
+	if dialect == "" {
+		dialect = DialectPostgres
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPersistenceTable, table)
+	}
+	if dialect != DialectPostgres && dialect != DialectMySQL {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDialect, dialect)
+	}
+	return &DatabasePersistenceHandler{db: db, table: table, dialect: dialect, owned: make(map[string]struct{})}, nil
+}
+
+// Save persists jobs, replacing the persisted jobs of the same IDs, and deletes the
+// jobs of this handler's previous loads and saves that jobs no longer holds
+func (h *DatabasePersistenceHandler) Save(jobs []Job) error {
+	ctx := context.Background()
+	if err := h.createTable(ctx); err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	saved := make(map[string]struct{}, len(jobs))
+	upsert := h.upsertQuery()
+	for _, job := range jobs {
+		job.JobFunc = nil
+		data, err := json.Marshal(job)
+		if err != nil {
+			return fmt.Errorf("failed to marshal job %s: %w", job.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, upsert, job.ID, string(data)); err != nil {
+			return fmt.Errorf("failed to persist job %s: %w", job.ID, err)
+		}
+		saved[job.ID] = struct{}{}
+	}
+
+	remove := fmt.Sprintf("DELETE FROM %s WHERE id = %s", h.table, h.placeholder(1))
+	for id := range h.owned {
+		if _, ok := saved[id]; ok {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, remove, id); err != nil {
+			return fmt.Errorf("failed to delete persisted job %s: %w", id, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit persisted jobs: %w", err)
+	}
+	h.owned = saved
+	return nil
+}
+
+// upsertQuery returns the statement inserting a job, or replacing the data of the
+// job with its ID.
+func (h *DatabasePersistenceHandler) upsertQuery() string {
+	insert := fmt.Sprintf("INSERT INTO %s (id, data) VALUES (%s, %s)", h.table, h.placeholder(1), h.placeholder(2))
+	if h.dialect == DialectMySQL {
+		return insert + " ON DUPLICATE KEY UPDATE data = VALUES(data)"
+	}
+	return insert + " ON CONFLICT (id) DO UPDATE SET data = excluded.data"
+}
+
+// Load retrieves the persisted jobs, which this handler's saves then replace
+func (h *DatabasePersistenceHandler) Load() ([]Job, error) {
+	ctx := context.Background()
+	if err := h.createTable(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := h.db.QueryContext(ctx, "SELECT data FROM "+h.table+" ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to query persisted jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []Job
+	loaded := make(map[string]struct{})
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read persisted job: %w", err)
+		}
+		var job Job
+		if err := json.Unmarshal([]byte(data), &job); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal persisted job: %w", err)
+		}
+		jobs = append(jobs, job)
+		loaded[job.ID] = struct{}{}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read persisted jobs: %w", err)
+	}
+
+	h.mu.Lock()
+	for id := range loaded {
+		h.owned[id] = struct{}{}
+	}
+	h.mu.Unlock()
+	return jobs, nil
+}
+
+// createTable creates the jobs table if it doesn't exist yet.
+func (h *DatabasePersistenceHandler) createTable(ctx context.Context) error {
+	query := "CREATE TABLE IF NOT EXISTS " + h.table + " (id VARCHAR(255) PRIMARY KEY, data TEXT NOT NULL)"
+	if _, err := h.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", h.table, err)
+	}
+	return nil
+}
+
+func (h *DatabasePersistenceHandler) placeholder(n int) string {
+	if h.dialect == DialectMySQL {
+		return "?"
+	}
+	return fmt.Sprintf("$%d", n)
+}
@@ -0,0 +1,140 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+// testDBProvider exposes a database like the database module's service.
+type testDBProvider struct{ db *sql.DB }
+
+func (p *testDBProvider) DB() *sql.DB { return p.db }
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// Each connection to :memory: opens a separate database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestDatabasePersistenceHandler(t *testing.T) {
+	handler, err := NewDatabasePersistenceHandler(openTestDB(t), "", "")
+	require.NoError(t, err)
+
+	jobs, err := handler.Load()
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "the table is created on first use")
+
+	next := time.Now().Add(time.Hour).Truncate(time.Second)
+	require.NoError(t, handler.Save([]Job{
+		{ID: "b", Name: "report", Schedule: "0 9 * * *", IsRecurring: true, Timezone: "Europe/Paris", NextRun: &next},
+		{ID: "a", Name: "reminder", RunAt: next, CatchUp: CatchUpSkip, JobFunc: func(context.Context) error { return nil }},
+	}))
+	jobs, err = handler.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "reminder", jobs[0].Name)
+	assert.Equal(t, CatchUpSkip, jobs[0].CatchUp)
+	assert.True(t, next.Equal(jobs[0].RunAt))
+	assert.Equal(t, "Europe/Paris", jobs[1].Timezone)
+	require.NotNil(t, jobs[1].NextRun)
+	assert.True(t, next.Equal(*jobs[1].NextRun))
+
+	// Saving replaces the jobs the handler persisted
+	require.NoError(t, handler.Save([]Job{{ID: "c", Name: "cleanup", RunAt: next}}))
+	jobs, err = handler.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "c", jobs[0].ID)
+}
+
+func TestDatabasePersistenceHandler_SharedTable(t *testing.T) {
+	db := openTestDB(t)
+	first, err := NewDatabasePersistenceHandler(db, "", "")
+	require.NoError(t, err)
+	second, err := NewDatabasePersistenceHandler(db, "", "")
+	require.NoError(t, err)
+
+	require.NoError(t, first.Save([]Job{{ID: "a", Name: "reminder"}, {ID: "b", Name: "report"}}))
+	require.NoError(t, second.Save([]Job{{ID: "c", Name: "cleanup"}}))
+
+	// Each instance removes only its own jobs and updates the others by ID
+	require.NoError(t, first.Save([]Job{{ID: "a", Name: "reminder-v2"}}))
+	jobs, err := second.Load()
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, "reminder-v2", jobs[0].Name)
+	assert.Equal(t, "c", jobs[1].ID)
+
+	// Jobs loaded by an instance are its own to remove
+	require.NoError(t, second.Save(nil))
+	jobs, err = first.Load()
+	require.NoError(t, err)
+	assert.Empty(t, jobs)
+}
+
+func TestNewDatabasePersistenceHandler_Validation(t *testing.T) {
+	db := openTestDB(t)
+
+	_, err := NewDatabasePersistenceHandler(db, "jobs; DROP TABLE users", DialectPostgres)
+	require.ErrorIs(t, err, ErrInvalidPersistenceTable)
+	_, err = NewDatabasePersistenceHandler(db, "scheduler_jobs", "oracle")
+	require.ErrorIs(t, err, ErrUnknownDialect)
+
+	handler, err := NewDatabasePersistenceHandler(db, "app.scheduler_jobs", DialectMySQL)
+	require.NoError(t, err)
+	assert.Equal(t, "?", handler.placeholder(1))
+	handler, err = NewDatabasePersistenceHandler(db, "", DialectPostgres)
+	require.NoError(t, err)
+	assert.Equal(t, "$2", handler.placeholder(2))
+}
+
+func TestSchedulerModule_DatabasePersistence(t *testing.T) {
+	db := &testDBProvider{db: openTestDB(t)}
+	config := &SchedulerConfig{
+		WorkerCount:        1,
+		QueueSize:          10,
+		StorageType:        "memory",
+		CheckInterval:      time.Hour,
+		ShutdownTimeout:    time.Second,
+		PersistenceBackend: PersistenceBackendDatabase,
+		PersistenceTable:   "scheduled_jobs",
+	}
+	newDatabaseModule := func() *SchedulerModule {
+		app := newMockApp()
+		app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+		constructed, err := NewModule().(*SchedulerModule).Constructor()(app, map[string]any{DatabaseServiceName: db})
+		require.NoError(t, err)
+		module := constructed.(*SchedulerModule)
+		require.NoError(t, module.Init(app))
+		return module
+	}
+
+	module := newDatabaseModule()
+	require.NoError(t, module.Start(context.Background()))
+	id, err := module.ScheduleJob(Job{Name: "nightly-report", Schedule: "0 2 * * *", IsRecurring: true, Timezone: "UTC"})
+	require.NoError(t, err)
+	require.NoError(t, module.Stop(context.Background()))
+
+	restarted := newDatabaseModule()
+	job, err := restarted.GetJob(id)
+	require.NoError(t, err)
+	assert.Equal(t, "nightly-report", job.Name)
+	assert.Equal(t, "UTC", job.Timezone)
+	assert.Equal(t, JobStatusPending, job.Status)
+
+	// The database backend needs a database service
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+	require.ErrorIs(t, NewModule().(*SchedulerModule).Init(app), ErrNoDatabase)
+}
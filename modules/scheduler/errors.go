@@ -9,4 +9,28 @@ import (
 var (
 	// ErrNoSubjectForEventEmission is returned when trying to emit events without a subject
 	ErrNoSubjectForEventEmission = errors.New("no subject available for event emission")
+
+	// ErrInvalidCalendar is returned when the holiday or blackout configuration cannot be parsed
+	ErrInvalidCalendar = errors.New("invalid scheduler calendar")
+
+	// ErrInvalidOverlapPolicy is returned for overlap policies other than skip, queue and replace
+	ErrInvalidOverlapPolicy = errors.New("invalid overlap policy")
+
+	// ErrInvalidCatchUpPolicy is returned for catch-up policies other than once and skip
+	ErrInvalidCatchUpPolicy = errors.New("invalid catch-up policy")
+
+	// ErrInvalidTimezone is returned when the scheduler timezone is not a known IANA location
+	ErrInvalidTimezone = errors.New("invalid scheduler timezone")
+
+	// ErrNoDatabase is returned when the database persistence backend is used without a database service
+	ErrNoDatabase = errors.New("database persistence backend requires a database service")
+
+	// ErrNoLockService is returned when LockJobs is enabled without a lock service
+	ErrNoLockService = errors.New("lockJobs requires a lock service")
+
+	// ErrUnknownDialect is returned for database dialects other than postgres and mysql
+	ErrUnknownDialect = errors.New("unknown database dialect")
+
+	// ErrInvalidPersistenceTable is returned when the persistence table is not a valid SQL identifier
+	ErrInvalidPersistenceTable = errors.New("invalid persistence table name")
 )
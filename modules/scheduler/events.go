@@ -14,6 +14,10 @@ const (
 	EventTypeJobFailed    = "com.modular.scheduler.job.failed"
 	EventTypeJobCancelled = "com.modular.scheduler.job.cancelled"
 	EventTypeJobRemoved   = "com.modular.scheduler.job.removed"
+	EventTypeJobSkipped   = "com.modular.scheduler.job.skipped"
+	EventTypeJobPaused    = "com.modular.scheduler.job.paused"
+	EventTypeJobResumed   = "com.modular.scheduler.job.resumed"
+	EventTypeJobTriggered = "com.modular.scheduler.job.triggered"
 
 	// Scheduler events
 	EventTypeSchedulerStarted = "com.modular.scheduler.scheduler.started"
@@ -0,0 +1,138 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PauseJob stops a job from running on its schedule until ResumePausedJob is called.
+// Runs already started or queued are not affected.
+func (s *Scheduler) PauseJob(jobID string) error {
+	s.statusMutex.Lock()
+	job, err := s.jobStore.GetJob(jobID)
+	if err != nil {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("failed to get job to pause: %w", err)
+	}
+	if job.Status == JobStatusPaused {
+		s.statusMutex.Unlock()
+		return nil
+	}
+	if job.Status == JobStatusCancelled || (!job.IsRecurring && job.Status == JobStatusCompleted) {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("%w: %s", ErrJobNotActive, jobID)
+	}
+
+	job.Status = JobStatusPaused
+	job.NextRun = nil
+	job.UpdatedAt = time.Now()
+	err = s.jobStore.UpdateJob(job)
+	s.statusMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to update job status to paused: %w", err)
+	}
+	s.removeFromCron(jobID)
+
+	s.emitEvent(context.Background(), EventTypeJobPaused, map[string]interface{}{
+		"job_id":    job.ID,
+		"job_name":  job.Name,
+		"paused_at": time.Now().Format(time.RFC3339),
+	})
+	return nil
+}
+
+// ResumePausedJob lets a job paused by PauseJob run on its schedule again. A recurring
+// job runs at its next occurrence; a one-time job whose run time passed while paused
+// runs right away.
+func (s *Scheduler) ResumePausedJob(jobID string) error {
+	s.statusMutex.Lock()
+	job, err := s.jobStore.GetJob(jobID)
+	if err != nil {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("failed to get job to resume: %w", err)
+	}
+	if job.Status != JobStatusPaused {
+		s.statusMutex.Unlock()
+		return fmt.Errorf("%w: %s", ErrJobNotPaused, jobID)
+	}
+
+	now := time.Now()
+	next := job.RunAt
+	if job.IsRecurring {
+		schedule, err := s.parseSchedule(job)
+		if err != nil {
+			s.statusMutex.Unlock()
+			return err
+		}
+		next = s.nextOccurrence(schedule, now, job.IgnoreCalendar)
+	} else if next.Before(now) {
+		next = now
+	}
+	job.Status = JobStatusPending
+	job.NextRun = &next
+	job.UpdatedAt = now
+	err = s.jobStore.UpdateJob(job)
+	s.statusMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to update job for resume: %w", err)
+	}
+	if job.IsRecurring && s.started() {
+		s.registerWithCron(job)
+	}
+
+	s.emitEvent(context.Background(), EventTypeJobResumed, map[string]interface{}{
+		"job_id":   job.ID,
+		"job_name": job.Name,
+		"next_run": next.Format(time.RFC3339),
+	})
+	return nil
+}
+
+// TriggerNow runs a job right away, outside its schedule and even while it is paused.
+// The exclusion calendar and jitter don't apply, but the job's overlap policy does: a
+// run skipped because of it emits EventTypeJobSkipped. The job's schedule is not
+// changed, except that a one-time job doesn't run again once it completed.
+func (s *Scheduler) TriggerNow(jobID string) error {
+	if !s.started() {
+		return ErrSchedulerNotStarted
+	}
+	job, err := s.jobStore.GetJob(jobID)
+	if err != nil {
+		return fmt.Errorf("failed to get job to trigger: %w", err)
+	}
+	if job.Status == JobStatusCancelled {
+		return fmt.Errorf("%w: %s", ErrJobNotActive, jobID)
+	}
+
+	s.emitEvent(context.Background(), EventTypeJobTriggered, map[string]interface{}{
+		"job_id":       job.ID,
+		"job_name":     job.Name,
+		"triggered_at": time.Now().Format(time.RFC3339),
+	})
+	s.admit(job, 0, false)
+	return nil
+}
+
+// started reports whether the scheduler is running.
+func (s *Scheduler) started() bool {
+	s.schedulerMutex.Lock()
+	defer s.schedulerMutex.Unlock()
+	return s.isStarted
+}
+
+// isPaused reports whether the stored job is paused. The caller must hold statusMutex.
+func (s *Scheduler) isPaused(jobID string) bool {
+	job, err := s.jobStore.GetJob(jobID)
+	return err == nil && job.Status == JobStatusPaused
+}
+
+// removeFromCron removes a recurring job's entry from the cron scheduler.
+func (s *Scheduler) removeFromCron(jobID string) {
+	s.entryMutex.Lock()
+	defer s.entryMutex.Unlock()
+	if entryID, exists := s.cronEntries[jobID]; exists {
+		s.cronScheduler.Remove(entryID)
+		delete(s.cronEntries, jobID)
+	}
+}
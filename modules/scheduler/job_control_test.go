@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startControlScheduler starts a scheduler checking for due jobs every second. It
+// must be called inside a synctest bubble.
+func startControlScheduler(t *testing.T) (*Scheduler, *recordingEmitter) {
+	t.Helper()
+	emitter := &recordingEmitter{}
+	s := NewScheduler(NewMemoryJobStore(time.Hour), WithEventEmitter(emitter), WithCheckInterval(time.Second))
+	require.NoError(t, s.Start(context.Background()))
+	t.Cleanup(func() { _ = s.Stop(context.Background()) })
+	return s, emitter
+}
+
+func countingJob(runs *atomic.Int32, err error) JobFunc {
+	return func(context.Context) error {
+		runs.Add(1)
+		return err
+	}
+}
+
+func TestScheduler_PauseAndResumeOneTimeJob(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter := startControlScheduler(t)
+		var runs atomic.Int32
+		id, err := s.ScheduleJob(Job{Name: "report", RunAt: time.Now().Add(time.Minute), JobFunc: countingJob(&runs, nil)})
+		require.NoError(t, err)
+
+		require.NoError(t, s.PauseJob(id))
+		require.NoError(t, s.PauseJob(id), "pausing a paused job does nothing")
+		job, err := s.GetJob(id)
+		require.NoError(t, err)
+		assert.Equal(t, JobStatusPaused, job.Status)
+		assert.Nil(t, job.NextRun)
+
+		time.Sleep(2 * time.Minute)
+		synctest.Wait()
+		assert.Zero(t, runs.Load(), "paused jobs don't run")
+
+		require.NoError(t, s.ResumePausedJob(id))
+		time.Sleep(time.Second)
+		synctest.Wait()
+		assert.Equal(t, int32(1), runs.Load(), "a one-time job whose run time passed runs on resume")
+
+		require.Len(t, emitter.dataOf(t, EventTypeJobPaused), 1)
+		resumed := emitter.dataOf(t, EventTypeJobResumed)
+		require.Len(t, resumed, 1)
+		assert.Equal(t, id, resumed[0]["job_id"])
+
+		assert.ErrorIs(t, s.ResumePausedJob(id), ErrJobNotPaused)
+		assert.ErrorIs(t, s.PauseJob(id), ErrJobNotActive, "completed one-time jobs can't be paused")
+	})
+}
+
+func TestScheduler_PauseAndResumeRecurringJob(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, _ := startControlScheduler(t)
+		var runs atomic.Int32
+		id, err := s.ScheduleRecurring("sync", "* * * * *", countingJob(&runs, nil))
+		require.NoError(t, err)
+
+		require.NoError(t, s.PauseJob(id))
+		s.entryMutex.RLock()
+		assert.NotContains(t, s.cronEntries, id)
+		s.entryMutex.RUnlock()
+
+		time.Sleep(3 * time.Minute)
+		synctest.Wait()
+		assert.Zero(t, runs.Load())
+
+		require.NoError(t, s.ResumePausedJob(id))
+		job, err := s.GetJob(id)
+		require.NoError(t, err)
+		assert.Equal(t, JobStatusPending, job.Status)
+		require.NotNil(t, job.NextRun)
+		assert.True(t, job.NextRun.After(time.Now()))
+		s.entryMutex.RLock()
+		assert.Contains(t, s.cronEntries, id)
+		s.entryMutex.RUnlock()
+
+		time.Sleep(time.Minute)
+		synctest.Wait()
+		assert.Positive(t, runs.Load())
+	})
+}
+
+func TestScheduler_TriggerNow(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter := startControlScheduler(t)
+		var runs atomic.Int32
+		id, err := s.ScheduleRecurring("cleanup", "0 3 * * *", countingJob(&runs, errors.New("disk full")))
+		require.NoError(t, err)
+		require.NoError(t, s.PauseJob(id))
+
+		require.NoError(t, s.TriggerNow(id))
+		synctest.Wait()
+		assert.Equal(t, int32(1), runs.Load(), "paused jobs can be triggered")
+
+		jobs, err := s.ListJobs()
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, JobStatusPaused, jobs[0].Status, "the job stays paused after the run")
+		assert.Equal(t, JobStatusFailed, jobs[0].LastResult)
+		assert.Equal(t, "disk full", jobs[0].LastError)
+		assert.NotNil(t, jobs[0].LastRun)
+		assert.Len(t, emitter.dataOf(t, EventTypeJobTriggered), 1)
+
+		require.NoError(t, s.CancelJob(id))
+		assert.ErrorIs(t, s.TriggerNow(id), ErrJobNotActive)
+		assert.ErrorIs(t, s.PauseJob(id), ErrJobNotActive)
+	})
+}
+
+func TestScheduler_TriggerNowBeforeStart(t *testing.T) {
+	s := NewScheduler(NewMemoryJobStore(time.Hour))
+	id, err := s.ScheduleRecurring("cleanup", "0 3 * * *", func(context.Context) error { return nil })
+	require.NoError(t, err)
+	assert.ErrorIs(t, s.TriggerNow(id), ErrSchedulerNotStarted)
+}
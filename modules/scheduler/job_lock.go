@@ -0,0 +1,84 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// LockServiceName is the key under which the lock service used by LockJobs is
+// injected, such as the locks module's service.
+const LockServiceName = "locks.provider"
+
+// SkipReasonLocked is reported in job skipped events for runs skipped because another
+// instance holds the job's lock.
+const SkipReasonLocked = "locked"
+
+// JobLocker runs a function under a distributed lock shared by every instance of an
+// application. The locks module's service implements it.
+type JobLocker interface {
+	// TryWithLock runs fn while holding the lock on key if it is free, and reports
+	// whether it ran. It returns false without running fn when the lock is held.
+	TryWithLock(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) error) (bool, error)
+}
+
+// WithJobLocker runs every job under a lock of locker, so that of several instances
+// triggering the same job only one runs it. The lock is refreshed while the job runs;
+// a ttl of zero uses the locker's default.
+func WithJobLocker(locker JobLocker, ttl time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		s.locker = locker
+		s.lockTTL = ttl
+	}
+}
+
+// jobLockKey returns the lock key of a job. Instances register the same job under
+// the same name but may generate different IDs, so the name is preferred.
+func jobLockKey(job Job) string {
+	if job.Name != "" {
+		return "scheduler:job:" + job.Name
+	}
+	return "scheduler:job:" + job.ID
+}
+
+// runLocked runs the job through run while holding its lock. A run skipped because
+// another instance holds the lock counts as run elsewhere: recurring jobs move on to
+// their next occurrence and one-off jobs complete.
+func (s *Scheduler) runLocked(ctx context.Context, job Job, run func(ctx context.Context)) {
+	key := jobLockKey(job)
+	ran, err := s.locker.TryWithLock(ctx, key, s.lockTTL, func(ctx context.Context) error {
+		run(ctx)
+		return nil
+	})
+	if ran {
+		if err != nil && s.logger != nil {
+			s.logger.Warn("Job lock was lost or could not be released", "id", job.ID, "name", job.Name, "error", err)
+		}
+		return
+	}
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Error("Failed to acquire job lock", "id", job.ID, "name", job.Name, "error", err)
+		}
+		s.emitSkipped(job, SkipReasonLocked, map[string]interface{}{"lock_key": key, "error": err.Error()})
+	} else {
+		s.emitSkipped(job, SkipReasonLocked, map[string]interface{}{"lock_key": key})
+	}
+
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	if s.isPaused(job.ID) {
+		return
+	}
+	if job.IsRecurring {
+		next := s.nextRunAfter(job, time.Now())
+		job.Status = JobStatusPending
+		job.NextRun = &next
+	} else {
+		job.Status = JobStatusCompleted
+		job.NextRun = nil
+	}
+	job.UpdatedAt = time.Now()
+	if err := s.jobStore.UpdateJob(job); err != nil && s.logger != nil {
+		s.logger.Warn("Failed to update job skipped for its lock", "jobID", job.ID, "error", err)
+	}
+}
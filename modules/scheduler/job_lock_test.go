@@ -0,0 +1,86 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/CrisisTextLine/modular"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sharedLocker stands in for a lock service shared by several instances.
+type sharedLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+	keys []string
+}
+
+func (l *sharedLocker) TryWithLock(ctx context.Context, key string, _ time.Duration, fn func(ctx context.Context) error) (bool, error) {
+	l.mu.Lock()
+	if l.held[key] {
+		l.mu.Unlock()
+		return false, nil
+	}
+	l.held[key] = true
+	l.keys = append(l.keys, key)
+	l.mu.Unlock()
+
+	err := fn(ctx)
+	l.mu.Lock()
+	delete(l.held, key)
+	l.mu.Unlock()
+	return true, err
+}
+
+func TestScheduler_JobLockRunsOnOneInstance(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		locker := &sharedLocker{held: make(map[string]bool)}
+		first, firstEvents, firstJob, release := startPolicyScheduler(t, Job{Name: "nightly-report"}, WithJobLocker(locker, time.Minute))
+		second, secondEvents, secondJob, _ := startPolicyScheduler(t, Job{Name: "nightly-report"}, WithJobLocker(locker, time.Minute))
+		require.NotEqual(t, firstJob.ID, secondJob.ID)
+
+		slot := time.Now().Truncate(time.Minute)
+		first.trigger(firstJob, slot, false)
+		synctest.Wait()
+		second.trigger(secondJob, slot, false)
+		synctest.Wait()
+
+		assert.Len(t, firstEvents.dataOf(t, EventTypeJobStarted), 1)
+		assert.Empty(t, secondEvents.dataOf(t, EventTypeJobStarted))
+		skipped := secondEvents.dataOf(t, EventTypeJobSkipped)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, SkipReasonLocked, skipped[0]["reason"])
+		assert.Equal(t, "scheduler:job:nightly-report", skipped[0]["lock_key"])
+
+		// The skipping instance moves on to the next occurrence
+		job, err := second.GetJob(secondJob.ID)
+		require.NoError(t, err)
+		assert.Equal(t, JobStatusPending, job.Status)
+		require.NotNil(t, job.NextRun)
+		assert.True(t, job.NextRun.After(slot))
+
+		close(release)
+		synctest.Wait()
+		assert.Len(t, firstEvents.dataOf(t, EventTypeJobCompleted), 1)
+		assert.Equal(t, []string{"scheduler:job:nightly-report"}, locker.keys)
+	})
+}
+
+func TestSchedulerModule_LockJobs(t *testing.T) {
+	config := &SchedulerConfig{WorkerCount: 1, QueueSize: 1, StorageType: "memory", LockJobs: true}
+
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(config))
+	require.ErrorIs(t, NewModule().(*SchedulerModule).Init(app), ErrNoLockService)
+
+	locker := &sharedLocker{held: make(map[string]bool)}
+	constructed, err := NewModule().(*SchedulerModule).Constructor()(app, map[string]any{LockServiceName: locker})
+	require.NoError(t, err)
+	module := constructed.(*SchedulerModule)
+	require.NoError(t, module.Init(app))
+	assert.Same(t, locker, module.scheduler.locker)
+}
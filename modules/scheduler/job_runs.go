@@ -0,0 +1,367 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// OverlapPolicy decides what happens when a job is triggered while it already has
+// its maximum number of concurrent executions in flight.
+type OverlapPolicy string
+
+const (
+	// OverlapSkip drops the new run (default)
+	OverlapSkip OverlapPolicy = "skip"
+	// OverlapQueue runs the new one as soon as an execution finishes. At most
+	// MaxConcurrent runs are queued per job; further triggers are skipped.
+	OverlapQueue OverlapPolicy = "queue"
+	// OverlapReplace cancels the oldest running execution and starts the new one
+	OverlapReplace OverlapPolicy = "replace"
+)
+
+// Reasons reported in job skipped events.
+const (
+	SkipReasonBlackout = "blackout"
+	SkipReasonOverlap  = "overlap"
+)
+
+// validate reports whether p is a known policy. The empty policy means the default.
+func (p OverlapPolicy) validate() error {
+	switch p {
+	case "", OverlapSkip, OverlapQueue, OverlapReplace:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidOverlapPolicy, p)
+	}
+}
+
+// jobRun is a job admitted for execution, with how it got there.
+type jobRun struct {
+	job      Job
+	jitter   time.Duration
+	queued   bool // waited for an earlier execution to finish
+	replaced bool // cancelled an earlier execution to run
+}
+
+// activeExecution is an execution currently running in a worker.
+type activeExecution struct {
+	cancel   context.CancelFunc
+	replaced bool // cancelled by a newer run under OverlapReplace
+}
+
+// jobRunState tracks in-flight runs of one job.
+type jobRunState struct {
+	admitted int                // runs queued for or running in a worker
+	queued   int                // runs waiting for a slot under OverlapQueue
+	active   []*activeExecution // running executions, oldest first
+	lastSlot time.Time          // schedule slot of the last trigger, to drop duplicates
+}
+
+// jobRunTracker tracks in-flight runs for all jobs.
+type jobRunTracker struct {
+	mu   sync.Mutex
+	jobs map[string]*jobRunState
+}
+
+// state returns the run state for jobID. The caller must hold mu.
+func (t *jobRunTracker) state(jobID string) *jobRunState {
+	if t.jobs == nil {
+		t.jobs = make(map[string]*jobRunState)
+	}
+	state, ok := t.jobs[jobID]
+	if !ok {
+		state = &jobRunState{}
+		t.jobs[jobID] = state
+	}
+	return state
+}
+
+// claimSlot records a trigger for the given schedule slot and reports whether it is
+// the first one. Recurring jobs can be triggered by both the cron scheduler and the
+// due-job dispatcher for the same occurrence; only the first trigger runs.
+func (t *jobRunTracker) claimSlot(jobID string, slot time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	state := t.state(jobID)
+	if slot.Equal(state.lastSlot) {
+		return false
+	}
+	state.lastSlot = slot
+	return true
+}
+
+// scheduledSlot returns the schedule occurrence the cron scheduler fired for at now:
+// the latest occurrence at or before now, so both the cron and the due-job triggers
+// for one occurrence share the exact slot the dispatcher sees in NextRun.
+func (s *Scheduler) scheduledSlot(job Job, now time.Time) time.Time {
+	schedule, err := s.parseSchedule(job)
+	if err != nil {
+		return now
+	}
+	slot := schedule.Next(now.Add(-time.Minute))
+	if slot.After(now) {
+		return now
+	}
+	for next := schedule.Next(slot); !next.After(now); next = schedule.Next(slot) {
+		slot = next
+	}
+	return slot
+}
+
+// releaseSlot forgets the last claimed slot so the occurrence can be triggered again.
+func (t *jobRunTracker) releaseSlot(jobID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state(jobID).lastSlot = time.Time{}
+}
+
+// trigger runs a job that is due, applying the exclusion calendar, jitter and the
+// job's overlap policy. claimed reports whether the job store already marked the job
+// as running for this trigger, in which case skipped runs hand the job back to it.
+func (s *Scheduler) trigger(job Job, slot time.Time, claimed bool) {
+	if job.IsRecurring && !slot.IsZero() && !s.runs.claimSlot(job.ID, slot) {
+		dbg("Trigger: duplicate trigger for job id=%s slot=%s", job.ID, slot)
+		return
+	}
+
+	now := time.Now()
+	if !job.IgnoreCalendar {
+		if window, blocked := s.calendar.Blocked(now); blocked {
+			next := s.nextRunAfter(job, now)
+			if claimed {
+				s.releaseJob(job, next)
+			}
+			s.emitSkipped(job, SkipReasonBlackout, map[string]interface{}{
+				"blackout": window.Name,
+				"next_run": next.Format(time.RFC3339),
+			})
+			return
+		}
+	}
+
+	jitter := s.jitterFor(job)
+	if jitter <= 0 {
+		s.admit(job, jitter, claimed)
+		return
+	}
+
+	dbg("Trigger: delaying job id=%s by jitter=%s", job.ID, jitter)
+	timer := time.NewTimer(jitter)
+	go func() {
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+			s.admit(job, jitter, claimed)
+		case <-s.ctx.Done():
+		}
+	}()
+}
+
+// admit applies the overlap policy and queues the run for a worker.
+func (s *Scheduler) admit(job Job, jitter time.Duration, claimed bool) {
+	policy, limit := s.overlapFor(job)
+	run := jobRun{job: job, jitter: jitter}
+
+	s.runs.mu.Lock()
+	state := s.runs.state(job.ID)
+	switch {
+	case limit <= 0 || state.admitted < limit:
+	case policy == OverlapQueue && state.queued < limit:
+		state.queued++
+		s.runs.mu.Unlock()
+		dbg("Admit: queued job id=%s behind running executions", job.ID)
+		return
+	case policy == OverlapReplace && len(state.active) > 0:
+		oldest := state.active[0]
+		oldest.replaced = true
+		oldest.cancel()
+		run.replaced = true
+	default:
+		s.runs.mu.Unlock()
+		if claimed {
+			s.releaseJob(job, s.nextRunAfter(job, time.Now()))
+		}
+		s.emitSkipped(job, SkipReasonOverlap, map[string]interface{}{
+			"overlap_policy": string(policy),
+			"max_concurrent": limit,
+		})
+		return
+	}
+	state.admitted++
+	s.runs.mu.Unlock()
+
+	s.enqueue(run, claimed)
+}
+
+// enqueue hands a run to the workers. When the queue is full the run is dropped and,
+// for jobs claimed from the store, retried on the next dispatcher tick.
+func (s *Scheduler) enqueue(run jobRun, claimed bool) {
+	select {
+	case s.jobQueue <- run:
+		if s.logger != nil {
+			s.logger.Debug("Dispatched job", "id", run.job.ID, "name", run.job.Name)
+		}
+		dbg("Dispatcher: queued job id=%s", run.job.ID)
+	default:
+		s.runs.mu.Lock()
+		s.runs.state(run.job.ID).admitted--
+		s.runs.mu.Unlock()
+		s.runs.releaseSlot(run.job.ID)
+		if claimed {
+			next := time.Now()
+			if run.job.NextRun != nil {
+				next = *run.job.NextRun
+			}
+			s.releaseJob(run.job, next)
+		}
+		if s.logger != nil {
+			s.logger.Warn("Job queue is full, job execution delayed", "id", run.job.ID, "name", run.job.Name)
+		}
+		dbg("Dispatcher: queue full for job id=%s", run.job.ID)
+	}
+}
+
+// startExecution registers a running execution and returns it with the number of
+// executions of the job now running.
+func (s *Scheduler) startExecution(jobID string, cancel context.CancelFunc) (*activeExecution, int) {
+	s.runs.mu.Lock()
+	defer s.runs.mu.Unlock()
+	state := s.runs.state(jobID)
+	execution := &activeExecution{cancel: cancel}
+	state.active = append(state.active, execution)
+	return execution, len(state.active)
+}
+
+// wasReplaced reports whether the execution was cancelled by a replacing run.
+func (s *Scheduler) wasReplaced(execution *activeExecution) bool {
+	s.runs.mu.Lock()
+	defer s.runs.mu.Unlock()
+	return execution.replaced
+}
+
+// finishExecution releases the execution's slot and starts a queued run, if any.
+func (s *Scheduler) finishExecution(jobID string, execution *activeExecution) {
+	s.runs.mu.Lock()
+	state := s.runs.state(jobID)
+	for i, active := range state.active {
+		if active == execution {
+			state.active = append(state.active[:i:i], state.active[i+1:]...)
+			break
+		}
+	}
+	state.admitted--
+	runQueued := state.queued > 0 && s.ctx.Err() == nil
+	if runQueued {
+		state.queued--
+		state.admitted++
+	}
+	s.runs.mu.Unlock()
+
+	if runQueued {
+		job, err := s.jobStore.GetJob(jobID)
+		if err != nil || job.Status == JobStatusCancelled {
+			s.runs.mu.Lock()
+			s.runs.state(jobID).admitted--
+			s.runs.mu.Unlock()
+			return
+		}
+		s.enqueue(jobRun{job: job, queued: true}, false)
+	}
+}
+
+// overlapFor returns the overlap policy and concurrency limit for a job. A limit of
+// zero means executions are unlimited and the policy never applies.
+func (s *Scheduler) overlapFor(job Job) (OverlapPolicy, int) {
+	policy := job.OverlapPolicy
+	if policy == "" {
+		policy = s.overlapPolicy
+	}
+	limit := job.MaxConcurrent
+	if limit <= 0 {
+		limit = s.maxConcurrent
+	}
+	return policy, limit
+}
+
+// jitterFor returns a random delay in [0, jitter) for the job's configured jitter.
+func (s *Scheduler) jitterFor(job Job) time.Duration {
+	jitter := job.Jitter
+	if jitter <= 0 {
+		jitter = s.jitter
+	}
+	if jitter <= 0 {
+		return 0
+	}
+	return rand.N(jitter) //nolint:gosec // jitter does not need a secure source
+}
+
+// nextRunAfter returns the job's next run time after t outside the exclusion calendar:
+// the next schedule occurrence for recurring jobs, or the end of the exclusion for
+// one-time jobs.
+func (s *Scheduler) nextRunAfter(job Job, t time.Time) time.Time {
+	if !job.IsRecurring {
+		if job.IgnoreCalendar {
+			return t
+		}
+		return s.calendar.NextAllowed(t)
+	}
+	schedule, err := s.parseSchedule(job)
+	if err != nil {
+		return t
+	}
+	return s.nextOccurrence(schedule, t, job.IgnoreCalendar)
+}
+
+// nextOccurrence returns the first schedule occurrence after t that is outside the
+// exclusion calendar.
+func (s *Scheduler) nextOccurrence(schedule cron.Schedule, t time.Time, ignoreCalendar bool) time.Time {
+	next := schedule.Next(t)
+	if ignoreCalendar {
+		return next
+	}
+	for {
+		window, blocked := s.calendar.Blocked(next)
+		if !blocked {
+			return next
+		}
+		next = schedule.Next(window.End.Add(-time.Nanosecond))
+	}
+}
+
+// releaseJob returns a job claimed from the store to pending with the given next run.
+// A job paused since it was claimed stays paused.
+func (s *Scheduler) releaseJob(job Job, next time.Time) {
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	if s.isPaused(job.ID) {
+		return
+	}
+	job.Status = JobStatusPending
+	job.NextRun = &next
+	job.UpdatedAt = time.Now()
+	if err := s.jobStore.UpdateJob(job); err != nil && s.logger != nil {
+		s.logger.Warn("Failed to release skipped job", "jobID", job.ID, "error", err)
+	}
+}
+
+// emitSkipped emits a job skipped event with the given reason and details.
+func (s *Scheduler) emitSkipped(job Job, reason string, details map[string]interface{}) {
+	if s.logger != nil {
+		s.logger.Debug("Skipped job run", "id", job.ID, "name", job.Name, "reason", reason)
+	}
+	data := map[string]interface{}{
+		"job_id":     job.ID,
+		"job_name":   job.Name,
+		"reason":     reason,
+		"skipped_at": time.Now().Format(time.RFC3339),
+	}
+	for key, value := range details {
+		data[key] = value
+	}
+	s.emitEvent(context.Background(), EventTypeJobSkipped, data)
+}
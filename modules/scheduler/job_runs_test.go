@@ -0,0 +1,287 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingEmitter records events emitted by a Scheduler.
+type recordingEmitter struct {
+	mu     sync.Mutex
+	events []cloudevents.Event
+}
+
+func (e *recordingEmitter) EmitEvent(ctx context.Context, event cloudevents.Event) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, event)
+	return nil
+}
+
+// dataOf returns the data of the recorded events with the given type.
+func (e *recordingEmitter) dataOf(t *testing.T, eventType string) []map[string]interface{} {
+	t.Helper()
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var matched []map[string]interface{}
+	for _, event := range e.events {
+		if event.Type() != eventType {
+			continue
+		}
+		var data map[string]interface{}
+		require.NoError(t, event.DataAs(&data))
+		matched = append(matched, data)
+	}
+	return matched
+}
+
+// startPolicyScheduler starts a scheduler and schedules a recurring job whose runs
+// block until released. It must be called inside a synctest bubble.
+func startPolicyScheduler(t *testing.T, job Job, opts ...SchedulerOption) (*Scheduler, *recordingEmitter, Job, chan struct{}) {
+	t.Helper()
+
+	emitter := &recordingEmitter{}
+	s := NewScheduler(NewMemoryJobStore(time.Hour), append(opts, WithEventEmitter(emitter), WithCheckInterval(time.Hour))...)
+	require.NoError(t, s.Start(context.Background()))
+	t.Cleanup(func() { _ = s.Stop(context.Background()) })
+
+	release := make(chan struct{})
+	job.Schedule = "* * * * *"
+	job.IsRecurring = true
+	job.JobFunc = func(ctx context.Context) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	id, err := s.ScheduleJob(job)
+	require.NoError(t, err)
+	job, err = s.GetJob(id)
+	require.NoError(t, err)
+	return s, emitter, job, release
+}
+
+func TestScheduler_OverlapSkip(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "skip", MaxConcurrent: 1})
+
+		slot := time.Now().Truncate(time.Minute)
+		s.trigger(job, slot, false)
+		s.trigger(job, slot, false) // duplicate trigger for the same occurrence is ignored
+		synctest.Wait()
+		s.trigger(job, slot.Add(time.Minute), false)
+		synctest.Wait()
+
+		assert.Len(t, emitter.dataOf(t, EventTypeJobStarted), 1)
+		skipped := emitter.dataOf(t, EventTypeJobSkipped)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, SkipReasonOverlap, skipped[0]["reason"])
+		assert.Equal(t, string(OverlapSkip), skipped[0]["overlap_policy"])
+
+		close(release)
+		synctest.Wait()
+	})
+}
+
+func TestScheduler_OverlapQueue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "queue", MaxConcurrent: 1, OverlapPolicy: OverlapQueue})
+
+		slot := time.Now().Truncate(time.Minute)
+		s.trigger(job, slot, false)
+		synctest.Wait()
+		s.trigger(job, slot.Add(time.Minute), false)
+		s.trigger(job, slot.Add(2*time.Minute), false) // beyond one queued run
+		synctest.Wait()
+		assert.Len(t, emitter.dataOf(t, EventTypeJobStarted), 1)
+		assert.Len(t, emitter.dataOf(t, EventTypeJobSkipped), 1)
+
+		release <- struct{}{}
+		synctest.Wait()
+		started := emitter.dataOf(t, EventTypeJobStarted)
+		require.Len(t, started, 2)
+		assert.Equal(t, true, started[1]["queued"])
+
+		close(release)
+		synctest.Wait()
+		assert.Len(t, emitter.dataOf(t, EventTypeJobCompleted), 2)
+	})
+}
+
+func TestScheduler_OverlapReplace(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "replace", MaxConcurrent: 1, OverlapPolicy: OverlapReplace})
+
+		slot := time.Now().Truncate(time.Minute)
+		s.trigger(job, slot, false)
+		synctest.Wait()
+		s.trigger(job, slot.Add(time.Minute), false)
+		synctest.Wait()
+
+		failed := emitter.dataOf(t, EventTypeJobFailed)
+		require.Len(t, failed, 1)
+		assert.Equal(t, true, failed[0]["replaced"])
+		started := emitter.dataOf(t, EventTypeJobStarted)
+		require.Len(t, started, 2)
+		assert.Equal(t, true, started[1]["replaced_previous"])
+
+		close(release)
+		synctest.Wait()
+		assert.Len(t, emitter.dataOf(t, EventTypeJobCompleted), 1)
+	})
+}
+
+func TestScheduler_OverlapUnlimitedByDefault(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "unlimited"})
+
+		slot := time.Now().Truncate(time.Minute)
+		s.trigger(job, slot, false)
+		s.trigger(job, slot.Add(time.Minute), false)
+		synctest.Wait()
+
+		assert.Len(t, emitter.dataOf(t, EventTypeJobStarted), 2, "runs overlap unless MaxConcurrent is set")
+		assert.Empty(t, emitter.dataOf(t, EventTypeJobSkipped))
+
+		close(release)
+		synctest.Wait()
+	})
+}
+
+func TestScheduler_SubMinuteSchedule(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var mu sync.Mutex
+		runs := 0
+		s := NewScheduler(NewMemoryJobStore(time.Hour), WithCheckInterval(100*time.Millisecond))
+		require.NoError(t, s.Start(context.Background()))
+		_, err := s.ScheduleJob(Job{
+			Name:        "every-second",
+			Schedule:    "@every 1s",
+			IsRecurring: true,
+			JobFunc: func(ctx context.Context) error {
+				mu.Lock()
+				defer mu.Unlock()
+				runs++
+				return nil
+			},
+		})
+		require.NoError(t, err)
+
+		time.Sleep(5500 * time.Millisecond)
+		require.NoError(t, s.Stop(context.Background()))
+		synctest.Wait()
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.GreaterOrEqual(t, runs, 5, "each occurrence runs once, not once per minute")
+		assert.LessOrEqual(t, runs, 6, "the cron and due-job triggers of an occurrence run it once")
+	})
+}
+
+func TestScheduler_ScheduledSlot(t *testing.T) {
+	s := NewScheduler(NewMemoryJobStore(time.Hour))
+	occurrence := time.Date(2025, 6, 2, 10, 30, 0, 0, time.Local)
+	job := Job{Schedule: "*/15 * * * *", IsRecurring: true}
+	assert.Equal(t, occurrence, s.scheduledSlot(job, occurrence.Add(20*time.Millisecond)))
+	assert.Equal(t, occurrence, s.scheduledSlot(job, occurrence))
+
+	job.Schedule = "@every 1s"
+	now := occurrence.Add(2*time.Second + 5*time.Millisecond)
+	assert.Equal(t, now.Truncate(time.Second), s.scheduledSlot(job, now))
+}
+
+func TestScheduler_Jitter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "jitter"}, WithJitter(30*time.Second))
+		close(release)
+
+		start := time.Now()
+		s.trigger(job, start.Truncate(time.Minute), false)
+		synctest.Wait()
+		time.Sleep(30 * time.Second)
+		synctest.Wait()
+
+		started := emitter.dataOf(t, EventTypeJobStarted)
+		require.Len(t, started, 1)
+		jitter, err := time.ParseDuration(started[0]["jitter"].(string))
+		require.NoError(t, err)
+		assert.Less(t, jitter, 30*time.Second)
+	})
+}
+
+func TestScheduler_BlackoutSkipsRuns(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		now := time.Now()
+		calendar, err := NewCalendar(CalendarConfig{Blackouts: []BlackoutWindow{{
+			Name:  "freeze",
+			Start: now.Add(-time.Hour),
+			End:   now.Add(time.Hour),
+		}}})
+		require.NoError(t, err)
+		s, emitter, job, release := startPolicyScheduler(t, Job{Name: "blackout"}, WithCalendar(calendar))
+		close(release)
+
+		assert.False(t, job.NextRun.Before(now.Add(time.Hour)), "recurring jobs are scheduled past the blackout")
+
+		s.trigger(job, now.Truncate(time.Minute), false)
+		synctest.Wait()
+		assert.Empty(t, emitter.dataOf(t, EventTypeJobStarted))
+		skipped := emitter.dataOf(t, EventTypeJobSkipped)
+		require.Len(t, skipped, 1)
+		assert.Equal(t, SkipReasonBlackout, skipped[0]["reason"])
+		assert.Equal(t, "freeze", skipped[0]["blackout"])
+
+		// Jobs that ignore the calendar still run
+		job.IgnoreCalendar = true
+		s.trigger(job, now.Truncate(time.Minute).Add(time.Minute), false)
+		synctest.Wait()
+		assert.Len(t, emitter.dataOf(t, EventTypeJobStarted), 1)
+	})
+}
+
+func TestCalendar(t *testing.T) {
+	calendar, err := NewCalendar(CalendarConfig{
+		Timezone: "UTC",
+		Holidays: []string{"2025-12-25"},
+		Blackouts: []BlackoutWindow{{
+			Name:  "after-christmas",
+			Start: time.Date(2025, 12, 26, 0, 0, 0, 0, time.UTC),
+			End:   time.Date(2025, 12, 26, 6, 0, 0, 0, time.UTC),
+		}},
+	})
+	require.NoError(t, err)
+
+	window, blocked := calendar.Blocked(time.Date(2025, 12, 25, 13, 0, 0, 0, time.UTC))
+	assert.True(t, blocked)
+	assert.Equal(t, "holiday 2025-12-25", window.Name)
+	_, blocked = calendar.Blocked(time.Date(2025, 12, 24, 23, 59, 0, 0, time.UTC))
+	assert.False(t, blocked)
+
+	// Adjacent windows are skipped together
+	next := calendar.NextAllowed(time.Date(2025, 12, 25, 8, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 12, 26, 6, 0, 0, 0, time.UTC), next)
+
+	_, err = NewCalendar(CalendarConfig{Holidays: []string{"12/25/2025"}})
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+	_, err = NewCalendar(CalendarConfig{Timezone: "Mars/Olympus"})
+	require.ErrorIs(t, err, ErrInvalidCalendar)
+
+	var none *Calendar
+	_, blocked = none.Blocked(time.Now())
+	assert.False(t, blocked)
+}
+
+func TestScheduleJob_InvalidOverlapPolicy(t *testing.T) {
+	s := NewScheduler(NewMemoryJobStore(time.Hour))
+	_, err := s.ScheduleJob(Job{Name: "bad", RunAt: time.Now(), OverlapPolicy: "parallel"})
+	require.ErrorIs(t, err, ErrInvalidOverlapPolicy)
+}
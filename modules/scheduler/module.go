@@ -60,6 +60,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"reflect"
 	"sync"
 	"time"
 
@@ -79,6 +80,10 @@ const ModuleName = "scheduler"
 // Other modules can use this name to request the scheduler service through dependency injection.
 const ServiceName = "scheduler.provider"
 
+// DatabaseServiceName is the key under which the database used by the database
+// persistence backend is injected.
+const DatabaseServiceName = "database.service"
+
 // SchedulerModule provides job scheduling and task execution capabilities.
 // It manages a pool of worker goroutines that execute scheduled jobs and
 // provides persistence and lifecycle management for jobs.
@@ -100,6 +105,12 @@ type SchedulerModule struct {
 	running       bool
 	schedulerLock sync.Mutex
 	subject       modular.Subject // Added for event observation
+	db            DBProvider
+	locker        JobLocker
+
+	// persistDone stops the periodic save started when PersistInterval is set
+	persistDone chan struct{}
+	persistWg   sync.WaitGroup
 }
 
 // NewModule creates a new instance of the scheduler module.
@@ -133,6 +144,8 @@ func (m *SchedulerModule) Name() string {
 //   - StorageType: "memory" storage backend
 //   - CheckInterval: 1s for job polling
 //   - RetentionDays: 7 days for completed job retention
+//   - MaxConcurrentPerJob: 0, executions of the same job may overlap
+//   - CatchUpPolicy: "once", running jobs missed while down once on restart
 func (m *SchedulerModule) RegisterConfig(app modular.Application) error {
 	// If a non-nil config provider is already registered (e.g., tests), don't override it
 	if existing, err := app.GetConfigSection(m.Name()); err == nil && existing != nil {
@@ -141,14 +154,19 @@ func (m *SchedulerModule) RegisterConfig(app modular.Application) error {
 
 	// Register the configuration with default values
 	defaultConfig := &SchedulerConfig{
-		WorkerCount:        5,
-		QueueSize:          100,
-		ShutdownTimeout:    30 * time.Second,
-		StorageType:        "memory",
-		CheckInterval:      1 * time.Second, // Fast for unit tests
-		RetentionDays:      7,
-		PersistenceBackend: PersistenceBackendNone,
-		PersistenceHandler: nil,
+		WorkerCount:         5,
+		QueueSize:           100,
+		ShutdownTimeout:     30 * time.Second,
+		StorageType:         "memory",
+		CheckInterval:       1 * time.Second, // Fast for unit tests
+		RetentionDays:       7,
+		MaxConcurrentPerJob: 0, // unlimited; OverlapPolicy applies once a limit is set
+		OverlapPolicy:       OverlapSkip,
+		CatchUpPolicy:       CatchUpOnce,
+		PersistenceBackend:  PersistenceBackendNone,
+		PersistenceTable:    DefaultPersistenceTable,
+		DatabaseDialect:     DialectPostgres,
+		PersistenceHandler:  nil,
 	}
 
 	app.RegisterConfigSection(m.Name(), modular.NewStdConfigProvider(defaultConfig))
@@ -175,8 +193,38 @@ func (m *SchedulerModule) Init(app modular.Application) error {
 		"check_interval":      m.config.CheckInterval.String(),
 		"retention_days":      m.config.RetentionDays,
 		"persistence_backend": string(m.config.PersistenceBackend),
+		"jitter":              m.config.Jitter.String(),
+		"overlap_policy":      string(m.config.OverlapPolicy),
+		"max_concurrent":      m.config.MaxConcurrentPerJob,
+		"timezone":            m.config.Timezone,
+		"catch_up_policy":     string(m.config.CatchUpPolicy),
+		"lock_jobs":           m.config.LockJobs,
 	})
 
+	if err := m.config.OverlapPolicy.validate(); err != nil {
+		return err
+	}
+	if err := m.config.CatchUpPolicy.validate(); err != nil {
+		return err
+	}
+	if m.config.Timezone != "" {
+		if _, err := time.LoadLocation(m.config.Timezone); err != nil {
+			return fmt.Errorf("%w: %q: %w", ErrInvalidTimezone, m.config.Timezone, err)
+		}
+	}
+	if m.config.PersistenceBackend == PersistenceBackendDatabase {
+		if _, err := m.getPersistenceHandler(); err != nil {
+			return err
+		}
+	}
+	if m.config.LockJobs && m.locker == nil {
+		return fmt.Errorf("%w: register a service implementing JobLocker as %s", ErrNoLockService, LockServiceName)
+	}
+	calendar, err := NewCalendar(m.config.Calendar)
+	if err != nil {
+		return err
+	}
+
 	// Initialize job store based on configuration
 	switch m.config.StorageType {
 	case "memory":
@@ -188,14 +236,22 @@ func (m *SchedulerModule) Init(app modular.Application) error {
 	}
 
 	// Initialize the scheduler
-	m.scheduler = NewScheduler(
-		m.jobStore,
+	opts := []SchedulerOption{
 		WithWorkerCount(m.config.WorkerCount),
 		WithQueueSize(m.config.QueueSize),
 		WithCheckInterval(m.config.CheckInterval),
 		WithLogger(m.logger),
 		WithEventEmitter(m),
-	)
+		WithJitter(m.config.Jitter),
+		WithCalendar(calendar),
+		WithOverlapPolicy(m.config.OverlapPolicy, m.config.MaxConcurrentPerJob),
+		WithTimezone(m.config.Timezone),
+		WithCatchUpPolicy(m.config.CatchUpPolicy, m.config.CatchUpWindow),
+	}
+	if m.config.LockJobs {
+		opts = append(opts, WithJobLocker(m.locker, m.config.LockTTL))
+	}
+	m.scheduler = NewScheduler(m.jobStore, opts...)
 
 	// Load persisted jobs if enabled
 	if m.config.PersistenceBackend != PersistenceBackendNone {
@@ -235,6 +291,9 @@ func (m *SchedulerModule) Start(ctx context.Context) error {
 	})
 
 	m.running = true
+	if m.config.PersistenceBackend != PersistenceBackendNone && m.config.PersistInterval > 0 {
+		m.startPeriodicSave()
+	}
 
 	// Emit module started event
 	m.emitEvent(ctx, EventTypeModuleStarted, map[string]interface{}{
@@ -258,6 +317,8 @@ func (m *SchedulerModule) Stop(ctx context.Context) error {
 		return nil
 	}
 
+	m.stopPeriodicSave()
+
 	// Create a context with timeout for graceful shutdown
 	shutdownCtx, cancel := context.WithTimeout(ctx, m.config.ShutdownTimeout)
 	defer cancel()
@@ -315,14 +376,34 @@ func (m *SchedulerModule) ProvidesServices() []modular.ServiceProvider {
 	}
 }
 
-// RequiresServices declares services required by this module
+// RequiresServices declares services required by this module. A database is only
+// needed by the database persistence backend, and a lock service only by LockJobs.
 func (m *SchedulerModule) RequiresServices() []modular.ServiceDependency {
-	return nil
+	return []modular.ServiceDependency{
+		{
+			Name:               DatabaseServiceName,
+			Required:           false,
+			MatchByInterface:   true,
+			SatisfiesInterface: reflect.TypeOf((*DBProvider)(nil)).Elem(),
+		},
+		{
+			Name:               LockServiceName,
+			Required:           false,
+			MatchByInterface:   true,
+			SatisfiesInterface: reflect.TypeOf((*JobLocker)(nil)).Elem(),
+		},
+	}
 }
 
 // Constructor provides a dependency injection constructor for the module
 func (m *SchedulerModule) Constructor() modular.ModuleConstructor {
 	return func(app modular.Application, services map[string]any) (modular.Module, error) {
+		if db, ok := services[DatabaseServiceName].(DBProvider); ok {
+			m.db = db
+		}
+		if locker, ok := services[LockServiceName].(JobLocker); ok {
+			m.locker = locker
+		}
 		return m, nil
 	}
 }
@@ -350,6 +431,12 @@ func (m *SchedulerModule) ScheduleRecurring(name string, cronExpr string, jobFun
 	return m.scheduler.ScheduleRecurring(name, cronExpr, jobFunc)
 }
 
+// RegisterJobFunc sets the function run by jobs with the given name that have no
+// JobFunc of their own, such as jobs loaded from the persistence backend on restart
+func (m *SchedulerModule) RegisterJobFunc(name string, jobFunc JobFunc) {
+	m.scheduler.RegisterJobFunc(name, jobFunc)
+}
+
 // CancelJob cancels a scheduled job
 func (m *SchedulerModule) CancelJob(jobID string) error {
 	return m.scheduler.CancelJob(jobID)
@@ -365,6 +452,21 @@ func (m *SchedulerModule) ListJobs() ([]Job, error) {
 	return m.scheduler.ListJobs()
 }
 
+// PauseJob stops a job from running on its schedule until ResumeJob is called
+func (m *SchedulerModule) PauseJob(jobID string) error {
+	return m.scheduler.PauseJob(jobID)
+}
+
+// ResumeJob lets a paused job run on its schedule again
+func (m *SchedulerModule) ResumeJob(jobID string) error {
+	return m.scheduler.ResumePausedJob(jobID)
+}
+
+// TriggerNow runs a job right away, outside its schedule
+func (m *SchedulerModule) TriggerNow(jobID string) error {
+	return m.scheduler.TriggerNow(jobID)
+}
+
 // GetJobHistory returns the execution history for a job
 func (m *SchedulerModule) GetJobHistory(jobID string) ([]JobExecution, error) {
 	return m.scheduler.GetJobHistory(jobID)
@@ -406,37 +508,38 @@ func (m *SchedulerModule) loadPersistedJobs() error {
 				continue
 			}
 
-			// Normalize NextRun so due jobs are picked up promptly after restart
+			// Normalize NextRun so due jobs are picked up promptly after restart; paused
+			// jobs stay paused until resumed
 			now := time.Now()
-			if job.NextRun == nil {
-				if !job.RunAt.IsZero() {
-					// If run time already passed, schedule immediately; otherwise keep original RunAt
-					if !job.RunAt.After(now) {
-						nr := now
-						job.NextRun = &nr
-					} else {
-						j := job.RunAt
-						job.NextRun = &j
-					}
-				} else {
-					// No scheduling info — set to now to avoid being stuck
-					nr := now
-					job.NextRun = &nr
-				}
-			} else if job.NextRun.Before(now) {
-				// If persisted NextRun is in the past, schedule immediately
+			paused := job.Status == JobStatusPaused
+			active := true
+			if paused {
+				job.NextRun = nil
+			} else if job.NextRun == nil && job.RunAt.IsZero() {
+				// No scheduling info — set to now to avoid being stuck
 				nr := now
 				job.NextRun = &nr
 			} else {
-				// If NextRun is very near-future (within 750ms), pull it to now to avoid timing flakes on restart
-				if job.NextRun.Sub(now) <= 750*time.Millisecond {
+				due := job.RunAt
+				if job.NextRun != nil {
+					due = *job.NextRun
+				}
+				if due.Before(now) {
+					// The run was missed while the application was down
+					job, active = m.scheduler.catchUp(job, due, now)
+				} else if due.Sub(now) <= 750*time.Millisecond {
+					// If due very near-future (within 750ms), pull it to now to avoid timing flakes on restart
 					nr := now
 					job.NextRun = &nr
+				} else {
+					job.NextRun = &due
 				}
 			}
 
 			// Normalize status back to pending for rescheduled work
-			job.Status = JobStatusPending
+			if !paused && active {
+				job.Status = JobStatusPending
+			}
 			job.UpdatedAt = time.Now()
 
 			// Debug after normalization
@@ -498,6 +601,37 @@ func (m *SchedulerModule) savePersistedJobs() error {
 	return ErrJobStoreNotPersistable
 }
 
+// startPeriodicSave saves jobs every PersistInterval until stopPeriodicSave is called.
+func (m *SchedulerModule) startPeriodicSave() {
+	m.persistDone = make(chan struct{})
+	m.persistWg.Add(1)
+	go func(done <-chan struct{}) {
+		defer m.persistWg.Done()
+		ticker := time.NewTicker(m.config.PersistInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := m.savePersistedJobs(); err != nil {
+					m.logger.Warn("Periodic save of jobs failed", "error", err, "backend", string(m.config.PersistenceBackend))
+				}
+			}
+		}
+	}(m.persistDone)
+}
+
+// stopPeriodicSave stops the periodic save, if running, and waits for it to finish.
+func (m *SchedulerModule) stopPeriodicSave() {
+	if m.persistDone == nil {
+		return
+	}
+	close(m.persistDone)
+	m.persistDone = nil
+	m.persistWg.Wait()
+}
+
 // getPersistenceHandler returns the appropriate persistence handler based on configuration
 func (m *SchedulerModule) getPersistenceHandler() (PersistenceHandler, error) {
 	switch m.config.PersistenceBackend {
@@ -514,6 +648,11 @@ func (m *SchedulerModule) getPersistenceHandler() (PersistenceHandler, error) {
 			return nil, ErrNoPersistenceHandler
 		}
 		return m.config.PersistenceHandler, nil
+	case PersistenceBackendDatabase:
+		if m.db == nil || m.db.DB() == nil {
+			return nil, ErrNoDatabase
+		}
+		return NewDatabasePersistenceHandler(m.db.DB(), m.config.PersistenceTable, m.config.DatabaseDialect)
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnknownPersistenceBackend, m.config.PersistenceBackend)
 	}
@@ -577,6 +716,10 @@ func (m *SchedulerModule) GetRegisteredEventTypes() []string {
 		EventTypeJobFailed,
 		EventTypeJobCancelled,
 		EventTypeJobRemoved,
+		EventTypeJobSkipped,
+		EventTypeJobPaused,
+		EventTypeJobResumed,
+		EventTypeJobTriggered,
 		EventTypeSchedulerStarted,
 		EventTypeSchedulerStopped,
 		EventTypeSchedulerPaused,
@@ -486,9 +486,16 @@ func TestSchedulerServiceProvider(t *testing.T) {
 	assert.Equal(t, ServiceName, services[0].Name)
 	assert.Equal(t, "Job scheduling service", services[0].Description)
 
-	// Test required services
+	// Test required services: the database and lock service are optional, used by
+	// the database backend and lockJobs
 	required := module.RequiresServices()
-	assert.Empty(t, required)
+	require.Len(t, required, 2)
+	assert.Equal(t, DatabaseServiceName, required[0].Name)
+	assert.Equal(t, LockServiceName, required[1].Name)
+	for _, dependency := range required {
+		assert.False(t, dependency.Required)
+		assert.True(t, dependency.MatchByInterface)
+	}
 }
 
 func TestJobPersistence(t *testing.T) {
@@ -579,3 +586,30 @@ func TestJobPersistence(t *testing.T) {
 		persistenceHandler.Clear()
 	})
 }
+
+func TestSchedulerModule_PeriodicSave(t *testing.T) {
+	handler := NewMemoryPersistenceHandler()
+	app := newMockApp()
+	app.RegisterConfigSection(ModuleName, modular.NewStdConfigProvider(&SchedulerConfig{
+		WorkerCount:        1,
+		QueueSize:          10,
+		StorageType:        "memory",
+		ShutdownTimeout:    time.Second,
+		PersistenceBackend: PersistenceBackendCustom,
+		PersistenceHandler: handler,
+		PersistInterval:    20 * time.Millisecond,
+	}))
+	module := NewModule().(*SchedulerModule)
+	require.NoError(t, module.Init(app))
+	require.NoError(t, module.Start(context.Background()))
+	defer func() { _ = module.Stop(context.Background()) }()
+
+	id, err := module.ScheduleJob(Job{Name: "later", RunAt: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+
+	// Jobs are saved while the module runs, not only when it stops
+	require.Eventually(t, func() bool {
+		jobs, err := handler.Load()
+		return err == nil && len(jobs) == 1 && jobs[0].ID == id
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -31,6 +32,9 @@ var (
 	ErrJobNoValidNextRunTime     = errors.New("job has no valid next run time")
 	ErrRecurringJobIDRequired    = errors.New("job ID must be provided when resuming a recurring job")
 	ErrJobMustBeRecurring        = errors.New("job must be recurring and have a schedule")
+	ErrJobNotActive              = errors.New("job is cancelled or has completed")
+	ErrJobNotPaused              = errors.New("job is not paused")
+	ErrSchedulerNotStarted       = errors.New("scheduler is not started")
 )
 
 // JobFunc defines a function that can be executed as a job
@@ -63,6 +67,25 @@ type Job struct {
 	Status      JobStatus  `json:"status"`
 	LastRun     *time.Time `json:"lastRun,omitempty"`
 	NextRun     *time.Time `json:"nextRun,omitempty"`
+	// LastResult is the status the last run ended with, completed or failed
+	LastResult JobStatus `json:"lastResult,omitempty"`
+	// LastError is the error the last run failed with
+	LastError string `json:"lastError,omitempty"`
+
+	// Jitter delays each run by a random duration below it. Zero uses the scheduler default.
+	Jitter time.Duration `json:"jitter,omitempty"`
+	// MaxConcurrent limits simultaneous executions of this job. Zero uses the scheduler
+	// default, which allows any number of overlapping executions unless configured.
+	MaxConcurrent int `json:"maxConcurrent,omitempty"`
+	// OverlapPolicy applies when MaxConcurrent executions are already running. Empty uses the scheduler default.
+	OverlapPolicy OverlapPolicy `json:"overlapPolicy,omitempty"`
+	// IgnoreCalendar lets the job run during holidays and blackout windows
+	IgnoreCalendar bool `json:"ignoreCalendar,omitempty"`
+
+	// Timezone is the IANA location Schedule is evaluated in. Empty uses the scheduler default.
+	Timezone string `json:"timezone,omitempty"`
+	// CatchUp applies when the job's run was missed while the application was down. Empty uses the scheduler default.
+	CatchUp CatchUpPolicy `json:"catchUp,omitempty"`
 }
 
 // JobStatus represents the status of a job
@@ -79,6 +102,8 @@ const (
 	JobStatusFailed JobStatus = "failed"
 	// JobStatusCancelled indicates a job has been cancelled
 	JobStatusCancelled JobStatus = "cancelled"
+	// JobStatusPaused indicates a job won't run on its schedule until resumed
+	JobStatusPaused JobStatus = "paused"
 )
 
 // Scheduler handles scheduling and executing jobs
@@ -89,10 +114,23 @@ type Scheduler struct {
 	checkInterval  time.Duration
 	logger         modular.Logger
 	eventEmitter   EventEmitter
-	jobQueue       chan Job
+	jitter         time.Duration
+	calendar       *Calendar
+	overlapPolicy  OverlapPolicy
+	maxConcurrent  int
+	timezone       string
+	catchUpPolicy  CatchUpPolicy
+	catchUpWindow  time.Duration
+	locker         JobLocker
+	lockTTL        time.Duration
+	jobFuncs       map[string]JobFunc
+	jobFuncMutex   sync.RWMutex
+	runs           jobRunTracker
+	jobQueue       chan jobRun
 	cronScheduler  *cron.Cron
 	cronEntries    map[string]cron.EntryID
 	entryMutex     sync.RWMutex
+	statusMutex    sync.Mutex // serializes pausing and resuming with status updates of runs
 	ctx            context.Context
 	cancel         context.CancelFunc
 	wg             sync.WaitGroup
@@ -160,6 +198,57 @@ func WithEventEmitter(emitter EventEmitter) SchedulerOption {
 	}
 }
 
+// WithJitter delays every job run by a random duration below jitter, spreading
+// runs of the same schedule across instances
+func WithJitter(jitter time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if jitter > 0 {
+			s.jitter = jitter
+		}
+	}
+}
+
+// WithCalendar sets the holidays and blackout windows during which jobs do not run
+func WithCalendar(calendar *Calendar) SchedulerOption {
+	return func(s *Scheduler) {
+		s.calendar = calendar
+	}
+}
+
+// WithOverlapPolicy sets the default overlap policy and per-job concurrency limit.
+// The policy only applies once maxConcurrent is set; zero keeps runs unlimited.
+func WithOverlapPolicy(policy OverlapPolicy, maxConcurrent int) SchedulerOption {
+	return func(s *Scheduler) {
+		if policy != "" {
+			s.overlapPolicy = policy
+		}
+		if maxConcurrent > 0 {
+			s.maxConcurrent = maxConcurrent
+		}
+	}
+}
+
+// WithTimezone sets the IANA location cron schedules are evaluated in, for jobs
+// that don't set their own
+func WithTimezone(timezone string) SchedulerOption {
+	return func(s *Scheduler) {
+		s.timezone = timezone
+	}
+}
+
+// WithCatchUpPolicy sets the default catch-up policy for runs missed while the
+// application was down, and how long ago a run may have been missed to be caught up
+func WithCatchUpPolicy(policy CatchUpPolicy, window time.Duration) SchedulerOption {
+	return func(s *Scheduler) {
+		if policy != "" {
+			s.catchUpPolicy = policy
+		}
+		if window > 0 {
+			s.catchUpWindow = window
+		}
+	}
+}
+
 // NewScheduler creates a new scheduler
 func NewScheduler(jobStore JobStore, opts ...SchedulerOption) *Scheduler {
 	s := &Scheduler{
@@ -167,6 +256,9 @@ func NewScheduler(jobStore JobStore, opts ...SchedulerOption) *Scheduler {
 		workerCount:   5, // Default
 		queueSize:     100,
 		checkInterval: time.Second,
+		overlapPolicy: OverlapSkip,
+		catchUpPolicy: CatchUpOnce,
+		jobFuncs:      make(map[string]JobFunc),
 		cronEntries:   make(map[string]cron.EntryID),
 	}
 
@@ -195,7 +287,7 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	}
 
 	s.ctx, s.cancel = context.WithCancel(ctx)
-	s.jobQueue = make(chan Job, s.queueSize)
+	s.jobQueue = make(chan jobRun, s.queueSize)
 
 	// Start worker goroutines
 	for i := 0; i < s.workerCount; i++ {
@@ -309,7 +401,8 @@ func (s *Scheduler) worker(id int) {
 			})
 
 			return
-		case job := <-s.jobQueue:
+		case run := <-s.jobQueue:
+			job := run.job
 			dbg("Worker %d: picked job id=%s name=%s nextRun=%v status=%s", id, job.ID, job.Name, job.NextRun, job.Status)
 			// Emit worker busy event
 			s.emitEvent(context.Background(), EventTypeWorkerBusy, map[string]interface{}{
@@ -318,7 +411,7 @@ func (s *Scheduler) worker(id int) {
 				"job_name":  job.Name,
 			})
 
-			s.executeJob(job)
+			s.executeJob(run)
 
 			// Emit worker idle event
 			s.emitEvent(context.Background(), EventTypeWorkerIdle, map[string]interface{}{
@@ -329,25 +422,58 @@ func (s *Scheduler) worker(id int) {
 	}
 }
 
-// executeJob runs a job and records its execution
-func (s *Scheduler) executeJob(job Job) {
+// executeJob runs a job and records its execution, under the job's lock when the
+// scheduler has a JobLocker
+func (s *Scheduler) executeJob(run jobRun) {
+	job := run.job
 	if s.logger != nil {
 		s.logger.Debug("Executing job", "id", job.ID, "name", job.Name)
 	}
 
+	jobCtx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	active, concurrentRuns := s.startExecution(job.ID, cancel)
+	defer s.finishExecution(job.ID, active)
+
+	if s.locker != nil {
+		s.runLocked(jobCtx, job, func(ctx context.Context) {
+			s.runJob(ctx, run, active, concurrentRuns)
+		})
+		return
+	}
+	s.runJob(jobCtx, run, active, concurrentRuns)
+}
+
+// runJob runs an admitted job, recording its execution and scheduling its next run
+func (s *Scheduler) runJob(jobCtx context.Context, run jobRun, active *activeExecution, concurrentRuns int) {
+	job := run.job
+	policy, _ := s.overlapFor(job)
+
 	// Emit job started event
 	s.emitEvent(context.Background(), EventTypeJobStarted, map[string]interface{}{
-		"job_id":     job.ID,
-		"job_name":   job.Name,
-		"start_time": time.Now().Format(time.RFC3339),
+		"job_id":            job.ID,
+		"job_name":          job.Name,
+		"start_time":        time.Now().Format(time.RFC3339),
+		"jitter":            run.jitter.String(),
+		"queued":            run.queued,
+		"replaced_previous": run.replaced,
+		"concurrent_runs":   concurrentRuns,
+		"overlap_policy":    string(policy),
 	})
 
-	// Update job status to running
-	job.Status = JobStatusRunning
-	job.UpdatedAt = time.Now()
-	if err := s.jobStore.UpdateJob(job); err != nil && s.logger != nil {
-		s.logger.Warn("Failed to update job status to running", "jobID", job.ID, "error", err)
+	// Update job status to running, unless the job is paused and was triggered
+	// manually or paused since it was dispatched
+	s.statusMutex.Lock()
+	if s.isPaused(job.ID) {
+		job.Status = JobStatusPaused
+	} else {
+		job.Status = JobStatusRunning
+		job.UpdatedAt = time.Now()
+		if err := s.jobStore.UpdateJob(job); err != nil && s.logger != nil {
+			s.logger.Warn("Failed to update job status to running", "jobID", job.ID, "error", err)
+		}
 	}
+	s.statusMutex.Unlock()
 
 	// Create execution record
 	execution := JobExecution{
@@ -360,13 +486,11 @@ func (s *Scheduler) executeJob(job Job) {
 	}
 
 	// Execute the job
-	jobCtx, cancel := context.WithCancel(s.ctx)
-	defer cancel()
-
 	var err error
-	if job.JobFunc != nil {
-		err = job.JobFunc(jobCtx)
+	if jobFunc := s.jobFuncFor(job); jobFunc != nil {
+		err = jobFunc(jobCtx)
 	}
+	replaced := s.wasReplaced(active)
 
 	// Update execution record
 	execution.EndTime = time.Now()
@@ -383,6 +507,7 @@ func (s *Scheduler) executeJob(job Job) {
 			"job_name": job.Name,
 			"error":    err.Error(),
 			"end_time": time.Now().Format(time.RFC3339),
+			"replaced": replaced,
 		})
 	} else {
 		execution.Status = string(JobStatusCompleted)
@@ -407,12 +532,23 @@ func (s *Scheduler) executeJob(job Job) {
 	job.LastRun = &now
 	if err != nil {
 		job.Status = JobStatusFailed
+		job.LastError = err.Error()
 	} else {
 		job.Status = JobStatusCompleted
+		job.LastError = ""
 	}
+	job.LastResult = job.Status
+
+	s.statusMutex.Lock()
+	defer s.statusMutex.Unlock()
+	paused := s.isPaused(job.ID)
 
 	// For non-recurring jobs, we're done
-	if !job.IsRecurring {
+	if !job.IsRecurring || paused {
+		if paused {
+			job.Status = JobStatusPaused
+			job.NextRun = nil
+		}
 		if err := s.jobStore.UpdateJob(job); err != nil && s.logger != nil {
 			s.logger.Warn("Failed to update completed job", "jobID", job.ID, "error", err)
 		}
@@ -420,9 +556,9 @@ func (s *Scheduler) executeJob(job Job) {
 	}
 
 	// For recurring jobs, calculate next run time
-	schedule, err := cron.ParseStandard(job.Schedule)
+	schedule, err := s.parseSchedule(job)
 	if err == nil {
-		nextRun := schedule.Next(now)
+		nextRun := s.nextOccurrence(schedule, now, job.IgnoreCalendar)
 		job.NextRun = &nextRun
 		job.Status = JobStatusPending
 	} else {
@@ -487,19 +623,11 @@ func (s *Scheduler) checkAndDispatchJobs() {
 	}
 
 	for _, job := range dueJobs {
-		select {
-		case s.jobQueue <- job:
-			if s.logger != nil {
-				s.logger.Debug("Dispatched job", "id", job.ID, "name", job.Name)
-			}
-			dbg("Dispatcher: queued job id=%s", job.ID)
-		default:
-			if s.logger != nil {
-				s.logger.Warn("Job queue is full, job execution delayed", "id", job.ID, "name", job.Name)
-			}
-			dbg("Dispatcher: queue full for job id=%s", job.ID)
-			// If queue is full, we'll try again next tick
+		var slot time.Time
+		if job.NextRun != nil {
+			slot = *job.NextRun
 		}
+		s.trigger(job, slot, true)
 	}
 }
 
@@ -520,6 +648,9 @@ func (s *Scheduler) ScheduleJob(job Job) (string, error) {
 	if job.RunAt.IsZero() && job.Schedule == "" {
 		return "", ErrJobInvalidSchedule
 	}
+	if err := job.OverlapPolicy.validate(); err != nil {
+		return "", err
+	}
 
 	// For recurring jobs, calculate next run time
 	if job.IsRecurring {
@@ -528,11 +659,11 @@ func (s *Scheduler) ScheduleJob(job Job) (string, error) {
 		}
 
 		// Parse cron expression to verify and get next run
-		schedule, err := cron.ParseStandard(job.Schedule)
+		schedule, err := s.parseSchedule(job)
 		if err != nil {
-			return "", fmt.Errorf("invalid cron expression '%s': %w", job.Schedule, err)
+			return "", err
 		}
-		next := schedule.Next(now)
+		next := s.nextOccurrence(schedule, now, job.IgnoreCalendar)
 		job.NextRun = &next
 	} else {
 		job.NextRun = &job.RunAt
@@ -564,7 +695,7 @@ func (s *Scheduler) registerWithCron(job Job) {
 	}
 
 	// Add to cron scheduler
-	entryID, err := s.cronScheduler.AddFunc(job.Schedule, func() {
+	entryID, err := s.cronScheduler.AddFunc(s.cronSpec(job), func() {
 		retrievedJob, err := s.jobStore.GetJob(job.ID)
 		if err != nil {
 			if s.logger != nil {
@@ -573,19 +704,10 @@ func (s *Scheduler) registerWithCron(job Job) {
 			return
 		}
 
-		// Only queue if job still exists and isn't already running
-		if retrievedJob.Status != JobStatusRunning {
-			select {
-			case s.jobQueue <- retrievedJob:
-				if s.logger != nil {
-					s.logger.Debug("Queued job from cron", "id", job.ID, "name", job.Name)
-				}
-			default:
-				if s.logger != nil {
-					s.logger.Warn("Job queue is full, cron job execution delayed", "id", job.ID, "name", job.Name)
-				}
-			}
+		if retrievedJob.Status == JobStatusCancelled || retrievedJob.Status == JobStatusPaused {
+			return
 		}
+		s.trigger(retrievedJob, s.scheduledSlot(retrievedJob, time.Now()), false)
 	})
 
 	if err == nil {
@@ -606,6 +728,47 @@ func (s *Scheduler) ScheduleRecurring(name string, cronExpr string, jobFunc JobF
 	return s.ScheduleJob(job)
 }
 
+// RegisterJobFunc sets the function run by jobs with the given name that have no
+// JobFunc of their own. Job functions can't be persisted, so jobs loaded from a
+// persistence backend after a restart run the function registered for their name.
+func (s *Scheduler) RegisterJobFunc(name string, jobFunc JobFunc) {
+	s.jobFuncMutex.Lock()
+	defer s.jobFuncMutex.Unlock()
+	s.jobFuncs[name] = jobFunc
+}
+
+// jobFuncFor returns the job's function, or the one registered for its name.
+func (s *Scheduler) jobFuncFor(job Job) JobFunc {
+	if job.JobFunc != nil {
+		return job.JobFunc
+	}
+	s.jobFuncMutex.RLock()
+	defer s.jobFuncMutex.RUnlock()
+	return s.jobFuncs[job.Name]
+}
+
+// cronSpec returns the job's cron expression qualified with its timezone, or the
+// scheduler's. Expressions carrying their own CRON_TZ= or TZ= prefix are kept as is.
+func (s *Scheduler) cronSpec(job Job) string {
+	timezone := job.Timezone
+	if timezone == "" {
+		timezone = s.timezone
+	}
+	if timezone == "" || strings.HasPrefix(job.Schedule, "CRON_TZ=") || strings.HasPrefix(job.Schedule, "TZ=") {
+		return job.Schedule
+	}
+	return "CRON_TZ=" + timezone + " " + job.Schedule
+}
+
+// parseSchedule parses the job's cron expression in its timezone.
+func (s *Scheduler) parseSchedule(job Job) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(s.cronSpec(job))
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression '%s': %w", job.Schedule, err)
+	}
+	return schedule, nil
+}
+
 // CancelJob cancels a scheduled job
 func (s *Scheduler) CancelJob(jobID string) error {
 	job, err := s.jobStore.GetJob(jobID)
@@ -713,12 +876,12 @@ func (s *Scheduler) ResumeRecurringJob(job Job) (string, error) {
 	job.UpdatedAt = time.Now()
 
 	// Calculate next run time
-	schedule, err := cron.ParseStandard(job.Schedule)
+	schedule, err := s.parseSchedule(job)
 	if err != nil {
-		return "", fmt.Errorf("invalid cron expression '%s': %w", job.Schedule, err)
+		return "", err
 	}
 
-	next := schedule.Next(time.Now())
+	next := s.nextOccurrence(schedule, time.Now(), job.IgnoreCalendar)
 	job.NextRun = &next
 
 	// Store the job
@@ -32,7 +32,9 @@ type Observer interface {
 // Events use the CloudEvents specification for standardization.
 type Subject interface {
 	// RegisterObserver adds an observer to receive notifications.
-	// Observers can optionally filter events by type using the eventTypes parameter.
+	// Observers can optionally filter events by type using the eventTypes parameter,
+	// which accepts exact event types and wildcard patterns such as
+	// "com.modular.reverseproxy.request.*" (see EventTypeMatches).
 	// If eventTypes is empty, the observer receives all events.
 	RegisterObserver(observer Observer, eventTypes ...string) error
 
@@ -57,8 +59,8 @@ type ObserverInfo struct {
 	// ID is the unique identifier of the observer
 	ID string `json:"id"`
 
-	// EventTypes are the event types this observer is subscribed to.
-	// Empty slice means all events.
+	// EventTypes are the event types and event type patterns this observer is
+	// subscribed to. Empty slice means all events.
 	EventTypes []string `json:"eventTypes"`
 
 	// RegisteredAt indicates when the observer was registered
@@ -75,21 +77,28 @@ const (
 	EventTypeModuleStarted     = "com.modular.module.started"
 	EventTypeModuleStopped     = "com.modular.module.stopped"
 	EventTypeModuleFailed      = "com.modular.module.failed"
+	EventTypeModuleSwapped     = "com.modular.module.swapped"
+	EventTypeModuleSwapFailed  = "com.modular.module.swap_failed"
 
 	// Service lifecycle events
 	EventTypeServiceRegistered   = "com.modular.service.registered"
 	EventTypeServiceUnregistered = "com.modular.service.unregistered"
 	EventTypeServiceRequested    = "com.modular.service.requested"
+	EventTypeServiceReplaced     = "com.modular.service.replaced"
 
 	// Configuration events
-	EventTypeConfigLoaded    = "com.modular.config.loaded"
-	EventTypeConfigValidated = "com.modular.config.validated"
-	EventTypeConfigChanged   = "com.modular.config.changed"
+	EventTypeConfigLoaded       = "com.modular.config.loaded"
+	EventTypeConfigValidated    = "com.modular.config.validated"
+	EventTypeConfigChanged      = "com.modular.config.changed"
+	EventTypeConfigReloadFailed = "com.modular.config.reload_failed"
 
 	// Application lifecycle events
 	EventTypeApplicationStarted = "com.modular.application.started"
 	EventTypeApplicationStopped = "com.modular.application.stopped"
 	EventTypeApplicationFailed  = "com.modular.application.failed"
+
+	// Debug events
+	EventTypeContextAuditFinding = "com.modular.context.audit.finding"
 )
 
 // ObservableModule is an optional interface that modules can implement
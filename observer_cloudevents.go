@@ -90,6 +90,10 @@ func NewModuleLifecycleEvent(source, subject, name, version, action string, meta
 			evt.SetType(EventTypeModuleStopped)
 		case "failed":
 			evt.SetType(EventTypeModuleFailed)
+		case "swapped":
+			evt.SetType(EventTypeModuleSwapped)
+		case "swap_failed":
+			evt.SetType(EventTypeModuleSwapFailed)
 		default:
 			evt.SetType("com.modular.module.lifecycle")
 		}
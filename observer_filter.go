@@ -0,0 +1,51 @@
+package modular
+
+import "strings"
+
+// EventTypeMatches reports whether eventType matches pattern. Event types are
+// dot-separated; in a pattern "*" matches any characters within one segment and
+// "**" matches any characters across segments. A pattern without wildcards
+// matches only the identical event type.
+//
+//	EventTypeMatches("com.modular.reverseproxy.request.*", "com.modular.reverseproxy.request.failed")    // true
+//	EventTypeMatches("com.modular.reverseproxy.*", "com.modular.reverseproxy.request.failed")            // false
+//	EventTypeMatches("com.modular.reverseproxy.**", "com.modular.reverseproxy.request.failed")           // true
+//	EventTypeMatches("com.modular.*.started", "com.modular.module.started")                               // true
+func EventTypeMatches(pattern, eventType string) bool {
+	if !isEventTypePattern(pattern) {
+		return pattern == eventType
+	}
+	return matchEventType(pattern, eventType)
+}
+
+// isEventTypePattern reports whether an observer's event type filter contains wildcards.
+func isEventTypePattern(eventType string) bool {
+	return strings.Contains(eventType, "*")
+}
+
+// matchEventType matches eventType against a wildcard pattern.
+func matchEventType(pattern, eventType string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] != '*' {
+			if len(eventType) == 0 || pattern[0] != eventType[0] {
+				return false
+			}
+			pattern, eventType = pattern[1:], eventType[1:]
+			continue
+		}
+
+		crossesDots := strings.HasPrefix(pattern, "**")
+		pattern = strings.TrimLeft(pattern, "*")
+		// Try every length the wildcard can consume
+		for i := 0; i <= len(eventType); i++ {
+			if matchEventType(pattern, eventType[i:]) {
+				return true
+			}
+			if i < len(eventType) && eventType[i] == '.' && !crossesDots {
+				return false
+			}
+		}
+		return false
+	}
+	return len(eventType) == 0
+}
@@ -0,0 +1,101 @@
+package modular
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventTypeMatches(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		eventType string
+		matches   bool
+	}{
+		{"com.modular.module.started", "com.modular.module.started", true},
+		{"com.modular.module.started", "com.modular.module.stopped", false},
+		{"com.modular.reverseproxy.request.*", "com.modular.reverseproxy.request.failed", true},
+		{"com.modular.reverseproxy.request.*", "com.modular.reverseproxy.request.", true},
+		{"com.modular.reverseproxy.request.*", "com.modular.reverseproxy.backend.added", false},
+		{"com.modular.reverseproxy.request.*", "com.modular.reverseproxy.request", false},
+		{"com.modular.reverseproxy.*", "com.modular.reverseproxy.request.failed", false},
+		{"com.modular.reverseproxy.**", "com.modular.reverseproxy.request.failed", true},
+		{"com.modular.*.started", "com.modular.module.started", true},
+		{"com.modular.*.started", "com.modular.module.swap.started", false},
+		{"com.modular.**.started", "com.modular.module.swap.started", true},
+		{"com.modular.module.swap_*", "com.modular.module.swap_failed", true},
+		{"**.failed", "com.modular.reverseproxy.request.failed", true},
+		{"**.x.*", "a.x.b.x.c", true},
+		{"*", "com", true},
+		{"*", "com.modular", false},
+		{"**", "com.modular.module.started", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+"/"+tt.eventType, func(t *testing.T) {
+			assert.Equal(t, tt.matches, EventTypeMatches(tt.pattern, tt.eventType))
+		})
+	}
+}
+
+func TestObservableApplication_PatternSubscriptions(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{})
+
+	var mu sync.Mutex
+	var received []string
+	observer := NewFunctionalObserver("lifecycle", func(_ context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Type())
+		return nil
+	})
+	require.NoError(t, app.RegisterObserver(observer, "com.modular.reverseproxy.backend.*", EventTypeApplicationStarted))
+
+	ctx := WithSynchronousNotification(context.Background())
+	for _, eventType := range []string{
+		"com.modular.reverseproxy.request.received",
+		"com.modular.reverseproxy.backend.added",
+		"com.modular.reverseproxy.request.failed",
+		EventTypeApplicationStarted,
+		"com.modular.reverseproxy.backend.health.changed",
+	} {
+		require.NoError(t, app.NotifyObservers(ctx, NewCloudEvent(eventType, "test", nil, nil)))
+	}
+
+	assert.Equal(t, []string{"com.modular.reverseproxy.backend.added", EventTypeApplicationStarted}, received)
+	require.Len(t, app.GetObservers(), 1)
+	assert.ElementsMatch(t, []string{"com.modular.reverseproxy.backend.*", EventTypeApplicationStarted}, app.GetObservers()[0].EventTypes)
+	assert.Equal(t, uint64(2), app.ObserverStats()["lifecycle"].Delivered)
+}
+
+func TestObservableApplication_PatternSubscriptionsWithDispatcher(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &TestObserverLogger{},
+		WithEventDispatcher(EventDispatcherConfig{Workers: 1}))
+
+	var mu sync.Mutex
+	var received []string
+	observer := NewFunctionalObserver("requests", func(_ context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, event.Type())
+		return nil
+	})
+	require.NoError(t, app.RegisterObserver(observer, "com.modular.reverseproxy.request.*"))
+	require.NoError(t, app.Start())
+
+	for _, eventType := range []string{
+		"com.modular.reverseproxy.request.received",
+		"com.modular.reverseproxy.backend.added",
+		"com.modular.reverseproxy.request.proxied",
+	} {
+		require.NoError(t, app.NotifyObservers(context.Background(), NewCloudEvent(eventType, "test", nil, nil)))
+	}
+	require.NoError(t, app.Stop())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"com.modular.reverseproxy.request.received", "com.modular.reverseproxy.request.proxied"}, received)
+}
@@ -0,0 +1,108 @@
+package modular
+
+import (
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/CrisisTextLine/modular/feeders"
+)
+
+// ProfileEnvVar is the environment variable naming the active configuration profiles,
+// e.g. APP_ENV=prod or APP_ENV=prod,eu for several profiles.
+const ProfileEnvVar = "APP_ENV"
+
+// ProfileOptions configures profile-layered configuration loading.
+//
+// With the defaults and APP_ENV=prod, the application loads config.yaml, then
+// config.prod.yaml on top of it, then applies the regular config feeders (such as
+// environment variables). Files may also use the .yml, .json, .toml or .ini extensions;
+// missing files are skipped.
+type ProfileOptions struct {
+	// ConfigDir is the directory containing the configuration files. Default ".".
+	ConfigDir string
+
+	// BaseName is the configuration file name without extension. Default "config".
+	BaseName string
+
+	// Profiles lists the active profiles, lowest precedence first. When empty they
+	// are read from EnvVar as a comma-separated list.
+	Profiles []string
+
+	// EnvVar names the environment variable holding the active profiles. Default ProfileEnvVar.
+	EnvVar string
+}
+
+// normalized fills in defaults for unset fields and resolves the active profiles.
+func (o ProfileOptions) normalized() ProfileOptions {
+	if o.ConfigDir == "" {
+		o.ConfigDir = "."
+	}
+	if o.BaseName == "" {
+		o.BaseName = "config"
+	}
+	if o.EnvVar == "" {
+		o.EnvVar = ProfileEnvVar
+	}
+	if len(o.Profiles) == 0 {
+		o.Profiles = ParseProfiles(os.Getenv(o.EnvVar))
+	} else {
+		o.Profiles = ParseProfiles(strings.Join(o.Profiles, ","))
+	}
+	return o
+}
+
+// feeder returns the feeder loading the base file and the active profile files.
+func (o *ProfileOptions) feeder() Feeder {
+	return feeders.NewProfileFeeder(o.ConfigDir, o.BaseName, o.Profiles...)
+}
+
+// ProfileAware is implemented by applications that expose their active configuration profiles.
+type ProfileAware interface {
+	// Profiles returns the active profiles, lowest precedence first.
+	Profiles() []string
+}
+
+// ParseProfiles splits a comma-separated profile list, trimming whitespace and
+// dropping empty and duplicate entries.
+func ParseProfiles(value string) []string {
+	profiles := make([]string, 0)
+	for _, profile := range strings.Split(value, ",") {
+		profile = strings.TrimSpace(profile)
+		if profile != "" && !slices.Contains(profiles, profile) {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles
+}
+
+// ActiveProfiles returns the active profiles of app. Applications that do not
+// implement ProfileAware fall back to the APP_ENV environment variable.
+func ActiveProfiles(app Application) []string {
+	if aware, ok := app.(ProfileAware); ok {
+		return aware.Profiles()
+	}
+	return ParseProfiles(os.Getenv(ProfileEnvVar))
+}
+
+// HasProfile reports whether profile is active for app.
+func HasProfile(app Application, profile string) bool {
+	return slices.Contains(ActiveProfiles(app), profile)
+}
+
+// SetProfileOptions enables profile-layered configuration loading for this
+// application. Active profiles are resolved immediately, so APP_ENV must be set
+// before this is called.
+func (app *StdApplication) SetProfileOptions(options ProfileOptions) {
+	normalized := options.normalized()
+	app.profileOptions = &normalized
+}
+
+// Profiles returns the active configuration profiles, lowest precedence first.
+// Without profile options the APP_ENV environment variable is used.
+func (app *StdApplication) Profiles() []string {
+	if app.profileOptions != nil {
+		return slices.Clone(app.profileOptions.Profiles)
+	}
+	return ParseProfiles(os.Getenv(ProfileEnvVar))
+}
@@ -0,0 +1,72 @@
+package modular
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type profileAppConfig struct {
+	Name     string `yaml:"name"`
+	Port     int    `yaml:"port"`
+	LogLevel string `yaml:"log_level" env:"PROFILE_TEST_LOG_LEVEL"`
+}
+
+func TestParseProfiles(t *testing.T) {
+	assert.Equal(t, []string{}, ParseProfiles(""))
+	assert.Equal(t, []string{"prod"}, ParseProfiles("prod"))
+	assert.Equal(t, []string{"prod", "eu"}, ParseProfiles(" prod, eu ,,prod"))
+}
+
+func TestWithProfiles_LayersConfigAndEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("name: app\nport: 8080\nlog_level: debug\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.prod.yaml"), []byte("port: 80\nlog_level: warn\n"), 0600))
+
+	t.Setenv(ProfileEnvVar, "prod")
+	t.Setenv("PROFILE_TEST_LOG_LEVEL", "error")
+
+	cfg := &profileAppConfig{}
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithConfigProvider(NewStdConfigProvider(cfg)),
+		WithProfiles(ProfileOptions{ConfigDir: dir}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, app.Init())
+
+	assert.Equal(t, "app", cfg.Name, "base file value")
+	assert.Equal(t, 80, cfg.Port, "profile file overrides base file")
+	assert.Equal(t, "error", cfg.LogLevel, "environment overrides profile file")
+
+	assert.Equal(t, []string{"prod"}, ActiveProfiles(app))
+	assert.True(t, HasProfile(app, "prod"))
+	assert.False(t, HasProfile(app, "dev"))
+}
+
+func TestWithProfiles_ExplicitProfilesThroughDecorator(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "prod")
+
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithProfiles(ProfileOptions{ConfigDir: t.TempDir(), Profiles: []string{"staging", "eu"}}),
+		WithObserver(func(ctx context.Context, event cloudevents.Event) error { return nil }),
+	)
+	require.NoError(t, err)
+
+	_, decorated := app.(ApplicationDecorator)
+	require.True(t, decorated)
+	assert.Equal(t, []string{"staging", "eu"}, ActiveProfiles(app), "explicit profiles take precedence over APP_ENV")
+}
+
+func TestActiveProfiles_DefaultsToEnvironment(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "dev,local")
+
+	app := NewStdApplication(NewStdConfigProvider(&struct{}{}), &testLogger{})
+	assert.Equal(t, []string{"dev", "local"}, ActiveProfiles(app))
+}
@@ -3,6 +3,7 @@ package modular
 import (
 	"fmt"
 	"reflect"
+	"strings"
 )
 
 // ServiceRegistry allows registration and retrieval of services by name.
@@ -46,6 +47,62 @@ type EnhancedServiceRegistry struct {
 
 	// currentModule tracks the module currently being initialized
 	currentModule Module
+
+	// conflictPolicy controls what happens when a service name is already taken
+	conflictPolicy ServiceConflictPolicy
+
+	// namespaces maps reserved service name prefixes to the module that owns them
+	namespaces map[string]string
+
+	// onChange is called after a service is registered or replaced.
+	// previous is nil for new registrations.
+	onChange func(entry, previous *ServiceRegistryEntry)
+
+	// swap tracks the services of a module being replaced by SwapModule; nil otherwise
+	swap *serviceSwap
+}
+
+// ServiceConflictPolicy controls how the registry handles a service registered
+// under a name that is already in use.
+type ServiceConflictPolicy int
+
+const (
+	// ServiceConflictRename keeps the existing service and registers the new one
+	// under a derived name such as "name.moduleName". This is the default.
+	ServiceConflictRename ServiceConflictPolicy = iota
+
+	// ServiceConflictError rejects duplicate names with ErrServiceAlreadyRegistered
+	// unless the registration uses AllowOverride.
+	ServiceConflictError
+)
+
+// ServiceRegistrationOption customizes a single service registration.
+type ServiceRegistrationOption func(*serviceRegistrationOptions)
+
+type serviceRegistrationOptions struct {
+	allowOverride bool
+}
+
+// AllowOverride lets a registration replace an existing service with the same
+// name instead of being renamed or rejected. Replacements are reported with
+// EventTypeServiceReplaced so they can be traced back to the module that made them.
+func AllowOverride() ServiceRegistrationOption {
+	return func(o *serviceRegistrationOptions) {
+		o.allowOverride = true
+	}
+}
+
+// ServiceNamespaceProvider is implemented by modules that own a service name prefix.
+// Once reserved, only the owning module (or the application itself, outside module
+// initialization) may register services named "<namespace>" or "<namespace>.<name>".
+type ServiceNamespaceProvider interface {
+	// ServiceNamespaces returns the service name prefixes owned by this module.
+	ServiceNamespaces() []string
+}
+
+// NamespacedServiceName returns the service name for name within namespace.
+func NamespacedServiceName(namespace, name string) string {
+	return namespace + "." + name
 }
 
 // NewEnhancedServiceRegistry creates a new enhanced service registry.
@@ -54,9 +111,40 @@ func NewEnhancedServiceRegistry() *EnhancedServiceRegistry {
 		services:       make(map[string]*ServiceRegistryEntry),
 		moduleServices: make(map[string][]string),
 		nameCounters:   make(map[string]int),
+		namespaces:     make(map[string]string),
 	}
 }
 
+// SetConflictPolicy sets how duplicate service names are handled.
+func (r *EnhancedServiceRegistry) SetConflictPolicy(policy ServiceConflictPolicy) {
+	r.conflictPolicy = policy
+}
+
+// ReserveNamespace reserves a service name prefix for a module. Reserving a
+// namespace already owned by another module fails with ErrServiceNamespaceReserved.
+func (r *EnhancedServiceRegistry) ReserveNamespace(namespace, moduleName string) error {
+	if owner, exists := r.namespaces[namespace]; exists && owner != moduleName {
+		return fmt.Errorf("%w: %q is owned by module %q", ErrServiceNamespaceReserved, namespace, owner)
+	}
+	r.namespaces[namespace] = moduleName
+	return nil
+}
+
+// NamespaceOwner returns the module that owns the most specific namespace
+// containing name, if any.
+func (r *EnhancedServiceRegistry) NamespaceOwner(name string) (string, bool) {
+	var owner, matched string
+	for namespace, moduleName := range r.namespaces {
+		if name != namespace && !strings.HasPrefix(name, namespace+".") {
+			continue
+		}
+		if len(namespace) > len(matched) {
+			owner, matched = moduleName, namespace
+		}
+	}
+	return owner, matched != ""
+}
+
 // SetCurrentModule sets the module that is currently being initialized.
 // This is used to track which module is registering services.
 func (r *EnhancedServiceRegistry) SetCurrentModule(module Module) {
@@ -69,8 +157,29 @@ func (r *EnhancedServiceRegistry) ClearCurrentModule() {
 }
 
 // RegisterService registers a service with automatic conflict resolution.
-// If a service name conflicts, it will automatically append module information.
-func (r *EnhancedServiceRegistry) RegisterService(name string, service any) (string, error) {
+// If a service name conflicts, it will automatically append module information,
+// or fail when the conflict policy is ServiceConflictError. Pass AllowOverride to
+// replace the existing service instead. Names inside a namespace reserved by
+// another module are rejected with ErrServiceNamespaceReserved.
+func (r *EnhancedServiceRegistry) RegisterService(name string, service any, opts ...ServiceRegistrationOption) (string, error) {
+	entry, previous, err := r.register(name, service, opts...)
+	if err != nil {
+		return "", err
+	}
+	if r.onChange != nil {
+		r.onChange(entry, previous)
+	}
+	return entry.ActualName, nil
+}
+
+// register registers a service like RegisterService without notifying onChange,
+// returning the new entry and the one it replaced, if any.
+func (r *EnhancedServiceRegistry) register(name string, service any, opts ...ServiceRegistrationOption) (entry, previous *ServiceRegistryEntry, err error) {
+	var options serviceRegistrationOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	var moduleName string
 	var moduleType reflect.Type
 
@@ -79,11 +188,29 @@ func (r *EnhancedServiceRegistry) RegisterService(name string, service any) (str
 		moduleType = reflect.TypeOf(r.currentModule)
 	}
 
-	// Generate unique name handling conflicts
-	actualName := r.generateUniqueName(name, moduleName, moduleType)
+	if owner, reserved := r.NamespaceOwner(name); reserved && moduleName != "" && owner != moduleName {
+		return nil, nil, fmt.Errorf("%w: module %q cannot register %q, namespace is owned by module %q",
+			ErrServiceNamespaceReserved, moduleName, name, owner)
+	}
+
+	actualName := name
+	swapped := false
+	if target, ok := r.swapTarget(name, moduleName); ok {
+		// A swapped-in module takes over the services of the instance it replaces
+		actualName, previous, swapped = target, r.services[target], true
+	} else if existing, exists := r.services[name]; exists && options.allowOverride {
+		previous = existing
+		r.removeModuleService(existing.ModuleName, name)
+	} else if exists && r.conflictPolicy == ServiceConflictError {
+		return nil, nil, fmt.Errorf("%w: %q is provided by %s, use AllowOverride to replace it",
+			ErrServiceAlreadyRegistered, name, existing.owner())
+	} else {
+		// Generate unique name handling conflicts
+		actualName = r.generateUniqueName(name, moduleName, moduleType)
+	}
 
 	// Create registry entry
-	entry := &ServiceRegistryEntry{
+	entry = &ServiceRegistryEntry{
 		Service:      service,
 		ModuleName:   moduleName,
 		ModuleType:   moduleType,
@@ -95,11 +222,33 @@ func (r *EnhancedServiceRegistry) RegisterService(name string, service any) (str
 	r.services[actualName] = entry
 
 	// Track module associations
-	if moduleName != "" {
+	if moduleName != "" && !swapped {
 		r.moduleServices[moduleName] = append(r.moduleServices[moduleName], actualName)
+		if r.swap != nil && r.swap.moduleName == moduleName {
+			r.swap.added = append(r.swap.added, actualName)
+		}
 	}
 
-	return actualName, nil
+	return entry, previous, nil
+}
+
+// removeModuleService drops serviceName from the services tracked for moduleName.
+func (r *EnhancedServiceRegistry) removeModuleService(moduleName, serviceName string) {
+	names := r.moduleServices[moduleName]
+	for i, name := range names {
+		if name == serviceName {
+			r.moduleServices[moduleName] = append(names[:i:i], names[i+1:]...)
+			return
+		}
+	}
+}
+
+// owner describes who registered the entry, for error messages.
+func (e *ServiceRegistryEntry) owner() string {
+	if e.ModuleName == "" {
+		return "the application"
+	}
+	return fmt.Sprintf("module %q", e.ModuleName)
 }
 
 // GetService retrieves a service by name.
@@ -130,7 +279,7 @@ func (r *EnhancedServiceRegistry) GetServicesByInterface(interfaceType reflect.T
 		if entry.Service == nil {
 			continue // Skip nil services
 		}
-		serviceType := reflect.TypeOf(entry.Service)
+		serviceType := registeredType(entry.Service)
 		if serviceType != nil && serviceType.Implements(interfaceType) {
 			results = append(results, entry)
 		}
@@ -207,6 +356,10 @@ type ServiceProvider struct {
 	// Can be any type - struct, interface implementation, function, etc.
 	// Consuming modules are responsible for type assertion.
 	Instance any
+
+	// AllowOverride replaces an existing service with the same name instead
+	// of registering this one under a derived name. See AllowOverride.
+	AllowOverride bool
 }
 
 // ServiceDependency defines a requirement for a service from another module.
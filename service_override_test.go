@@ -0,0 +1,125 @@
+package modular
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serviceOverrideTestModule provides a single service, optionally owning namespaces.
+type serviceOverrideTestModule struct {
+	name         string
+	provides     ServiceProvider
+	namespaces   []string
+	dependencies []string
+}
+
+func (m *serviceOverrideTestModule) Name() string                          { return m.name }
+func (m *serviceOverrideTestModule) Init(Application) error                { return nil }
+func (m *serviceOverrideTestModule) Dependencies() []string                { return m.dependencies }
+func (m *serviceOverrideTestModule) RequiresServices() []ServiceDependency { return nil }
+func (m *serviceOverrideTestModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{m.provides}
+}
+func (m *serviceOverrideTestModule) ServiceNamespaces() []string { return m.namespaces }
+
+func TestObservableApplication_ServiceReplacedEvent(t *testing.T) {
+	app := NewObservableApplication(NewStdConfigProvider(&struct{}{}), &testLogger{})
+
+	var mu sync.Mutex
+	var events []cloudevents.Event
+	require.NoError(t, app.RegisterObserver(NewFunctionalObserver("registry-observer", func(ctx context.Context, event cloudevents.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}), EventTypeServiceRegistered, EventTypeServiceReplaced))
+
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:     "flags",
+		provides: ServiceProvider{Name: "featureFlagEvaluator", Instance: "flags evaluator"},
+	})
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:         "experiments",
+		provides:     ServiceProvider{Name: "featureFlagEvaluator", Instance: "experiments evaluator", AllowOverride: true},
+		dependencies: []string{"flags"},
+	})
+	require.NoError(t, app.Init())
+
+	var evaluator string
+	require.NoError(t, app.GetService("featureFlagEvaluator", &evaluator))
+	assert.Equal(t, "experiments evaluator", evaluator)
+
+	var replaced cloudevents.Event
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, event := range events {
+			if event.Type() == EventTypeServiceReplaced {
+				replaced = event
+				return true
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	var data map[string]interface{}
+	require.NoError(t, replaced.DataAs(&data))
+	assert.Equal(t, "featureFlagEvaluator", data["serviceName"])
+	assert.Equal(t, "experiments", data["moduleName"])
+	assert.Equal(t, "flags", data["previousModuleName"])
+}
+
+func TestStdApplication_ServiceConflictPolicy(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&struct{}{}), &testLogger{}).(*StdApplication)
+	app.SetServiceConflictPolicy(ServiceConflictError)
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:     "flags",
+		provides: ServiceProvider{Name: "featureFlagEvaluator", Instance: "flags evaluator"},
+	})
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:         "experiments",
+		provides:     ServiceProvider{Name: "featureFlagEvaluator", Instance: "experiments evaluator"},
+		dependencies: []string{"flags"},
+	})
+
+	err := app.Init()
+	require.ErrorIs(t, err, ErrServiceAlreadyRegistered)
+	assert.Contains(t, err.Error(), `module "flags"`)
+}
+
+func TestStdApplication_ServiceNamespaces(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&struct{}{}), &testLogger{}).(*StdApplication)
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:       "flags",
+		provides:   ServiceProvider{Name: NamespacedServiceName("flags", "evaluator"), Instance: "flags evaluator"},
+		namespaces: []string{"flags"},
+	})
+	app.RegisterModule(&serviceOverrideTestModule{
+		name:     "experiments",
+		provides: ServiceProvider{Name: NamespacedServiceName("flags", "evaluator"), Instance: "experiments evaluator"},
+	})
+
+	err := app.Init()
+	require.ErrorIs(t, err, ErrServiceNamespaceReserved)
+
+	var evaluator string
+	require.NoError(t, app.GetService("flags.evaluator", &evaluator))
+	assert.Equal(t, "flags evaluator", evaluator)
+}
+
+func TestStdApplication_SetLoggerUpdatesRegistry(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&struct{}{}), &testLogger{}).(*StdApplication)
+	replacement := &TestObserverLogger{}
+	app.SetLogger(replacement)
+	require.NoError(t, app.RegisterService("unrelated", 1))
+
+	var logger Logger
+	require.NoError(t, app.GetService("logger", &logger))
+	assert.Same(t, replacement, logger)
+}
@@ -0,0 +1,201 @@
+package modular
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ServiceProxyFactory wraps a service in a proxy that reports each call to tracker.
+// Factories are usually generated with "modcli generate proxy" and registered with
+// RegisterServiceProxy from the generated file's init function.
+type ServiceProxyFactory func(service any, tracker *ServiceCallTracker) any
+
+// serviceProxies holds the registered ServiceProxyFactory for each interface type.
+var serviceProxies sync.Map // reflect.Type -> ServiceProxyFactory
+
+// RegisterServiceProxy registers the proxy factory for the interface T. When service
+// instrumentation is enabled, a module receiving a service as a T, by interface-based
+// injection or by GetService with a *T target, gets the service wrapped by factory.
+// It panics if T is not an interface type.
+func RegisterServiceProxy[T any](factory func(service T, tracker *ServiceCallTracker) T) {
+	serviceType := reflect.TypeFor[T]()
+	if serviceType.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("modular: RegisterServiceProxy: %v is not an interface type", serviceType))
+	}
+	serviceProxies.Store(serviceType, ServiceProxyFactory(func(service any, tracker *ServiceCallTracker) any {
+		return factory(service.(T), tracker)
+	}))
+}
+
+// ServiceCallTracker records the calls one module makes to one service. Proxies call
+// Track around every method of the service they wrap.
+type ServiceCallTracker struct {
+	consumer string
+	service  string
+	provider string
+	metrics  MetricsRegistry
+	auditor  *ContextAuditor
+
+	mu      sync.Mutex
+	methods map[string]*ServiceCallStats
+}
+
+// ServiceCallStats summarizes the calls to one method of a service made by a module.
+type ServiceCallStats struct {
+	// Service is the name the service is registered under
+	Service string `json:"service"`
+	// Provider is the module that registered the service, empty for services
+	// registered by the application
+	Provider string `json:"provider,omitempty"`
+	Method   string `json:"method"`
+	Calls    uint64 `json:"calls"`
+	// Errors counts the calls whose last result was a non-nil error
+	Errors        uint64        `json:"errors"`
+	TotalDuration time.Duration `json:"total_duration"`
+	MaxDuration   time.Duration `json:"max_duration"`
+}
+
+// Track starts timing a call to method and returns the function that records it.
+// Proxies pass that function the call's error, or nil for methods without one:
+//
+//	done := proxy.tracker.Track("Get")
+//	value, err := proxy.service.Get(ctx, key)
+//	done(err)
+func (t *ServiceCallTracker) Track(method string) func(err error) {
+	started := time.Now()
+	return func(err error) {
+		t.record(method, time.Since(started), err)
+	}
+}
+
+// TrackContext is Track for methods taking a context, which it also audits when
+// the application has a ContextAuditor. Proxies pass the method's context argument:
+//
+//	done := proxy.tracker.TrackContext(ctx, "Get")
+func (t *ServiceCallTracker) TrackContext(ctx context.Context, method string) func(err error) {
+	t.auditor.Check(ctx, t.service+"."+method)
+	return t.Track(method)
+}
+
+// record adds a call to the method's statistics and to the metrics
+// modular.service.calls and modular.service.call.duration.
+func (t *ServiceCallTracker) record(method string, elapsed time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	t.metrics.Counter("modular.service.calls",
+		"consumer", t.consumer, "service", t.service, "method", method, "result", result).Inc()
+	t.metrics.Histogram("modular.service.call.duration",
+		"consumer", t.consumer, "service", t.service, "method", method).Observe(elapsed.Seconds())
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats, ok := t.methods[method]
+	if !ok {
+		stats = &ServiceCallStats{Service: t.service, Provider: t.provider, Method: method}
+		t.methods[method] = stats
+	}
+	stats.Calls++
+	if err != nil {
+		stats.Errors++
+	}
+	stats.TotalDuration += elapsed
+	stats.MaxDuration = max(stats.MaxDuration, elapsed)
+}
+
+// Stats returns the statistics of every method called so far, sorted by method.
+func (t *ServiceCallTracker) Stats() []ServiceCallStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	stats := make([]ServiceCallStats, 0, len(t.methods))
+	for _, method := range t.methods {
+		stats = append(stats, *method)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Method < stats[j].Method })
+	return stats
+}
+
+// serviceTrackerKey identifies the tracker of a module's calls to a service used as
+// a given interface.
+type serviceTrackerKey struct {
+	consumer    string
+	service     string
+	serviceType reflect.Type
+}
+
+// SetServiceInstrumentation enables or disables wrapping services in their registered
+// proxies when they are handed to another module. It must be called before Init.
+func (app *StdApplication) SetServiceInstrumentation(enabled bool) {
+	app.serviceInstrumentation = enabled
+}
+
+// instrumentService returns service wrapped in the proxy registered for serviceType
+// when instrumentation is enabled and consumer is a module other than the one that
+// provided the service. Otherwise it returns service unchanged.
+func (app *StdApplication) instrumentService(consumer, serviceName string, service any, serviceType reflect.Type) any {
+	if !app.serviceInstrumentation || consumer == "" || serviceType == nil {
+		return service
+	}
+	factory, ok := serviceProxies.Load(serviceType)
+	if !ok {
+		return service
+	}
+	var provider string
+	if entry, ok := app.GetServiceEntry(serviceName); ok {
+		provider = entry.ModuleName
+	}
+	if provider == consumer {
+		return service
+	}
+
+	key := serviceTrackerKey{consumer: consumer, service: serviceName, serviceType: serviceType}
+	app.serviceTrackersMu.Lock()
+	tracker, ok := app.serviceTrackers[key]
+	if !ok {
+		tracker = &ServiceCallTracker{
+			consumer: consumer,
+			service:  serviceName,
+			provider: provider,
+			metrics:  MetricsFor(app),
+			auditor:  app.contextAuditor,
+			methods:  make(map[string]*ServiceCallStats),
+		}
+		if app.serviceTrackers == nil {
+			app.serviceTrackers = make(map[serviceTrackerKey]*ServiceCallTracker)
+		}
+		app.serviceTrackers[key] = tracker
+	}
+	app.serviceTrackersMu.Unlock()
+
+	return factory.(ServiceProxyFactory)(service, tracker)
+}
+
+// serviceCallStats returns the statistics of the calls consumer made through
+// instrumented services, sorted by service and method.
+func (app *StdApplication) serviceCallStats(consumer string) []ServiceCallStats {
+	app.serviceTrackersMu.Lock()
+	var trackers []*ServiceCallTracker
+	for key, tracker := range app.serviceTrackers {
+		if key.consumer == consumer {
+			trackers = append(trackers, tracker)
+		}
+	}
+	app.serviceTrackersMu.Unlock()
+
+	var stats []ServiceCallStats
+	for _, tracker := range trackers {
+		stats = append(stats, tracker.Stats()...)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Service != stats[j].Service {
+			return stats[i].Service < stats[j].Service
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
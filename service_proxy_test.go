@@ -0,0 +1,130 @@
+package modular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type quoteService interface {
+	Quote(symbol string) (float64, error)
+	Symbols() []string
+}
+
+// quoteServiceProxy is what modcli generate proxy emits for quoteService.
+type quoteServiceProxy struct {
+	service quoteService
+	tracker *ServiceCallTracker
+}
+
+func (proxy *quoteServiceProxy) Quote(a0 string) (r0 float64, r1 error) {
+	done := proxy.tracker.Track("Quote")
+	defer func() { done(r1) }()
+	return proxy.service.Quote(a0)
+}
+
+func (proxy *quoteServiceProxy) Symbols() []string {
+	defer proxy.tracker.Track("Symbols")(nil)
+	return proxy.service.Symbols()
+}
+
+func init() {
+	RegisterServiceProxy(func(service quoteService, tracker *ServiceCallTracker) quoteService {
+		return &quoteServiceProxy{service: service, tracker: tracker}
+	})
+}
+
+var errUnknownSymbol = errors.New("unknown symbol")
+
+type staticQuotes struct{}
+
+func (staticQuotes) Quote(symbol string) (float64, error) {
+	if symbol != "ACME" {
+		return 0, errUnknownSymbol
+	}
+	return 42, nil
+}
+
+func (staticQuotes) Symbols() []string { return []string{"ACME"} }
+
+type quoteProviderModule struct{ testModule }
+
+func (m quoteProviderModule) ProvidesServices() []ServiceProvider {
+	return []ServiceProvider{{Name: "quotes", Instance: quoteService(staticQuotes{})}}
+}
+
+type quoteConsumerModule struct {
+	testModule
+	quotes quoteService
+}
+
+func (m *quoteConsumerModule) Init(app Application) error {
+	return app.GetService("quotes", &m.quotes)
+}
+
+func newQuoteApp(t *testing.T, instrumented bool) (*StdApplication, *quoteConsumerModule) {
+	t.Helper()
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	app.SetServiceInstrumentation(instrumented)
+	consumer := &quoteConsumerModule{testModule: testModule{name: "checkout", dependencies: []string{"pricing"}}}
+	app.RegisterModule(quoteProviderModule{testModule{name: "pricing"}})
+	app.RegisterModule(consumer)
+	require.NoError(t, app.Init())
+	return app, consumer
+}
+
+func TestServiceInstrumentation_TracksCalls(t *testing.T) {
+	app, consumer := newQuoteApp(t, true)
+	require.IsType(t, &quoteServiceProxy{}, consumer.quotes)
+
+	price, err := consumer.quotes.Quote("ACME")
+	require.NoError(t, err)
+	assert.InDelta(t, 42.0, price, 0)
+	_, err = consumer.quotes.Quote("INITECH")
+	require.ErrorIs(t, err, errUnknownSymbol)
+	assert.Equal(t, []string{"ACME"}, consumer.quotes.Symbols())
+
+	descriptions := app.DescribeModules()
+	require.Len(t, descriptions, 2)
+	checkout, pricing := descriptions[0], descriptions[1]
+	assert.Equal(t, []string{"pricing"}, checkout.Dependencies)
+	assert.Equal(t, []string{"quotes"}, pricing.ProvidedServices)
+	assert.Empty(t, pricing.ServiceCalls)
+
+	require.Len(t, checkout.ServiceCalls, 2)
+	quote := checkout.ServiceCalls[0]
+	assert.Equal(t, "quotes", quote.Service)
+	assert.Equal(t, "pricing", quote.Provider)
+	assert.Equal(t, "Quote", quote.Method)
+	assert.Equal(t, uint64(2), quote.Calls)
+	assert.Equal(t, uint64(1), quote.Errors)
+	assert.GreaterOrEqual(t, quote.TotalDuration, quote.MaxDuration)
+	assert.Equal(t, "Symbols", checkout.ServiceCalls[1].Method)
+
+	var errorCalls float64
+	for _, metric := range app.Metrics().(MetricsGatherer).Gather() {
+		if metric.Name == "modular.service.calls" && metric.Labels["result"] == "error" {
+			assert.Equal(t, map[string]string{"consumer": "checkout", "service": "quotes", "method": "Quote", "result": "error"}, metric.Labels)
+			errorCalls = metric.Value
+		}
+	}
+	assert.InDelta(t, 1.0, errorCalls, 0)
+}
+
+func TestServiceInstrumentation_DisabledByDefault(t *testing.T) {
+	app, consumer := newQuoteApp(t, false)
+	assert.Equal(t, staticQuotes{}, consumer.quotes)
+
+	_, _ = consumer.quotes.Quote("ACME")
+	for _, description := range app.DescribeModules() {
+		assert.Empty(t, description.ServiceCalls)
+	}
+}
+
+func TestRegisterServiceProxy_RequiresInterface(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterServiceProxy(func(service staticQuotes, _ *ServiceCallTracker) staticQuotes { return service })
+	})
+}
@@ -0,0 +1,125 @@
+package modular
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+)
+
+// ShutdownPhase orders module shutdown. Stop runs the phases in ascending order and
+// only begins a phase once every module of the previous phase has stopped, so
+// requests stop arriving before proxies drain, proxies drain before event consumers,
+// and consumers drain before the databases they write to close. Within a phase,
+// modules stop in reverse dependency order.
+//
+// The values leave room between the predefined phases for custom ones.
+type ShutdownPhase int
+
+const (
+	// ShutdownPhaseIngress stops servers accepting external requests
+	ShutdownPhaseIngress ShutdownPhase = 100
+	// ShutdownPhaseProxy drains proxies forwarding in-flight requests
+	ShutdownPhaseProxy ShutdownPhase = 200
+	// ShutdownPhaseDefault is the phase of modules without an annotation. Supervised
+	// workers drain at the start of this phase.
+	ShutdownPhaseDefault ShutdownPhase = 300
+	// ShutdownPhaseConsumers drains event bus and queue consumers
+	ShutdownPhaseConsumers ShutdownPhase = 400
+	// ShutdownPhaseStorage closes databases and caches
+	ShutdownPhaseStorage ShutdownPhase = 500
+)
+
+// String returns the phase name, or its number for custom phases.
+func (p ShutdownPhase) String() string {
+	switch p {
+	case ShutdownPhaseIngress:
+		return "ingress"
+	case ShutdownPhaseProxy:
+		return "proxy"
+	case ShutdownPhaseDefault:
+		return "default"
+	case ShutdownPhaseConsumers:
+		return "consumers"
+	case ShutdownPhaseStorage:
+		return "storage"
+	default:
+		return "phase(" + strconv.Itoa(int(p)) + ")"
+	}
+}
+
+// ShutdownPhaseAware is an optional interface for modules that declare the phase
+// they stop in. Annotations set with SetShutdownPhase take precedence.
+type ShutdownPhaseAware interface {
+	ShutdownPhase() ShutdownPhase
+}
+
+// ShutdownPhaseDeclarer is the form of ShutdownPhaseAware for modules that build
+// against framework versions without the ShutdownPhase type, such as the framework's
+// own modules. ShutdownPhaseOrder returns the value of a ShutdownPhase.
+type ShutdownPhaseDeclarer interface {
+	ShutdownPhaseOrder() int
+}
+
+// SetShutdownPhase annotates the named module with the phase it stops in, overriding
+// the module's own declaration.
+func (app *StdApplication) SetShutdownPhase(moduleName string, phase ShutdownPhase) {
+	if app.shutdownPhases == nil {
+		app.shutdownPhases = make(map[string]ShutdownPhase)
+	}
+	app.shutdownPhases[moduleName] = phase
+}
+
+// shutdownPhase returns the phase the named module stops in.
+func (app *StdApplication) shutdownPhase(name string) ShutdownPhase {
+	if phase, ok := app.shutdownPhases[name]; ok {
+		return phase
+	}
+	switch module := app.moduleRegistry[name].(type) {
+	case ShutdownPhaseAware:
+		return module.ShutdownPhase()
+	case ShutdownPhaseDeclarer:
+		return ShutdownPhase(module.ShutdownPhaseOrder())
+	}
+	return ShutdownPhaseDefault
+}
+
+// validateShutdownPhases rejects phases that stop a module after a module it
+// depends on, which the module could still be using while it stops.
+func (app *StdApplication) validateShutdownPhases() error {
+	_, edges := app.buildDependencyGraph()
+	for _, edge := range edges {
+		if edge.From == edge.To {
+			continue
+		}
+		from, to := app.shutdownPhase(edge.From), app.shutdownPhase(edge.To)
+		if from > to {
+			return fmt.Errorf("%w: %s →%s %s, but %s stops in the %s phase after %s in the %s phase",
+				ErrShutdownPhaseConflict, edge.From, app.findDependencyEdge(edge.From, edge.To, edges), edge.To,
+				edge.From, from, edge.To, to)
+		}
+	}
+	return nil
+}
+
+// shutdownStage is a phase and the modules stopping in it, in stop order.
+type shutdownStage struct {
+	phase   ShutdownPhase
+	modules []string
+}
+
+// shutdownPlan groups modules, given in reverse dependency order, into stages
+// ordered by phase.
+func (app *StdApplication) shutdownPlan(modules []string) []shutdownStage {
+	byPhase := make(map[ShutdownPhase][]string)
+	for _, name := range modules {
+		phase := app.shutdownPhase(name)
+		byPhase[phase] = append(byPhase[phase], name)
+	}
+
+	stages := make([]shutdownStage, 0, len(byPhase))
+	for phase, names := range byPhase {
+		stages = append(stages, shutdownStage{phase: phase, modules: names})
+	}
+	slices.SortFunc(stages, func(a, b shutdownStage) int { return int(a.phase) - int(b.phase) })
+	return stages
+}
@@ -0,0 +1,122 @@
+package modular
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shutdownOrderModule records when it is stopped.
+type shutdownOrderModule struct {
+	testModule
+	phase   *ShutdownPhase
+	mu      *sync.Mutex
+	stopped *[]string
+}
+
+func (m shutdownOrderModule) Stop(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	*m.stopped = append(*m.stopped, m.name)
+	return nil
+}
+
+type phasedShutdownOrderModule struct {
+	shutdownOrderModule
+}
+
+func (m phasedShutdownOrderModule) ShutdownPhase() ShutdownPhase {
+	return *m.phase
+}
+
+// declaredShutdownOrderModule declares its phase like the framework's own modules.
+type declaredShutdownOrderModule struct {
+	shutdownOrderModule
+	order int
+}
+
+func (m declaredShutdownOrderModule) ShutdownPhaseOrder() int {
+	return m.order
+}
+
+func TestStop_ShutsDownInPhases(t *testing.T) {
+	var mu sync.Mutex
+	var stopped []string
+	module := func(name string, deps ...string) shutdownOrderModule {
+		return shutdownOrderModule{testModule: testModule{name: name, dependencies: deps}, mu: &mu, stopped: &stopped}
+	}
+	declared := func(order ShutdownPhase, name string, deps ...string) declaredShutdownOrderModule {
+		return declaredShutdownOrderModule{shutdownOrderModule: module(name, deps...), order: int(order)}
+	}
+	consumersPhase := ShutdownPhaseConsumers
+
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	// The dependency graph alone would stop the event bus and database before the server
+	app.RegisterModule(declared(ShutdownPhaseStorage, "database"))
+	app.RegisterModule(declared(ShutdownPhaseConsumers, "eventbus"))
+	app.RegisterModule(module("orders", "database"))
+	app.RegisterModule(phasedShutdownOrderModule{shutdownOrderModule: shutdownOrderModule{
+		testModule: testModule{name: "order-consumer", dependencies: []string{"eventbus", "database"}},
+		phase:      &consumersPhase, mu: &mu, stopped: &stopped,
+	}})
+	app.RegisterModule(module("edge"))
+	app.RegisterModule(declared(ShutdownPhaseIngress, "httpserver", "database", "eventbus"))
+	app.SetShutdownPhase("edge", ShutdownPhaseIngress)
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+
+	require.Len(t, stopped, 6)
+	assert.ElementsMatch(t, []string{"edge", "httpserver"}, stopped[:2], "ingress stops first")
+	assert.Equal(t, []string{"orders", "order-consumer", "eventbus", "database"}, stopped[2:])
+}
+
+func TestShutdownPhase_Resolution(t *testing.T) {
+	storage := ShutdownPhaseStorage
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	app.RegisterModule(declaredShutdownOrderModule{shutdownOrderModule{testModule: testModule{name: "reverseproxy"}}, int(ShutdownPhaseProxy)})
+	app.RegisterModule(phasedShutdownOrderModule{shutdownOrderModule{testModule: testModule{name: "archive"}, phase: &storage}})
+	app.RegisterModule(testModule{name: "worker"})
+
+	assert.Equal(t, ShutdownPhaseProxy, app.shutdownPhase("reverseproxy"))
+	assert.Equal(t, ShutdownPhaseStorage, app.shutdownPhase("archive"))
+	assert.Equal(t, ShutdownPhaseDefault, app.shutdownPhase("worker"))
+
+	app.SetShutdownPhase("archive", ShutdownPhaseIngress)
+	assert.Equal(t, ShutdownPhaseIngress, app.shutdownPhase("archive"), "annotations override the module's declaration")
+
+	assert.Equal(t, "consumers", ShutdownPhaseConsumers.String())
+	assert.Equal(t, "phase(250)", ShutdownPhase(250).String())
+}
+
+func TestWithShutdownPhase(t *testing.T) {
+	app, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithConfigProvider(NewStdConfigProvider(&testCfg{Str: "app"})),
+		WithModules(testModule{name: "grpc"}),
+		WithShutdownPhase("grpc", ShutdownPhaseIngress),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, ShutdownPhaseIngress, app.(*StdApplication).shutdownPhase("grpc"))
+}
+
+func TestStart_RejectsShutdownPhasesContradictingDependencies(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{}).(*StdApplication)
+	app.RegisterModule(testModule{name: "orders"})
+	app.RegisterModule(testModule{name: "order-consumer", dependencies: []string{"orders"}})
+	require.NoError(t, app.Init())
+
+	// The consumer would stop after the orders module it still uses
+	app.SetShutdownPhase("order-consumer", ShutdownPhaseConsumers)
+	err := app.Start()
+	require.ErrorIs(t, err, ErrShutdownPhaseConflict)
+	assert.Contains(t, err.Error(), "order-consumer →(module) orders")
+
+	app.SetShutdownPhase("orders", ShutdownPhaseStorage)
+	require.NoError(t, app.Start())
+	require.NoError(t, app.Stop())
+}
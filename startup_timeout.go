@@ -0,0 +1,224 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ModuleTimeouts bound how long a module may take to initialize and to start. A
+// zero duration means no limit.
+type ModuleTimeouts struct {
+	// Init bounds the module's Init, or InitContext for ContextInitializer modules
+	Init time.Duration
+	// Start bounds the module's Start
+	Start time.Duration
+}
+
+// StartupTimeoutsAware is an optional interface for modules that declare their own
+// Init and Start timeouts. Timeouts set with SetModuleTimeouts take precedence.
+type StartupTimeoutsAware interface {
+	StartupTimeouts() ModuleTimeouts
+}
+
+// ContextInitializer is an optional interface for modules whose initialization can
+// be cancelled. The application calls InitContext instead of Init, with a context
+// that expires at the module's Init deadline, so a module connecting to an
+// unreachable host during Init can give up rather than linger in the background.
+type ContextInitializer interface {
+	InitContext(ctx context.Context, app Application) error
+}
+
+// SetStartupTimeout bounds application startup, from the beginning of Init to the
+// end of Start. Modules still initializing or starting when it elapses fail with
+// ErrModuleInitTimeout or ErrModuleStartTimeout once they return, and modules after
+// them are not initialized or started. Zero, the default, means no limit.
+func (app *StdApplication) SetStartupTimeout(timeout time.Duration) {
+	app.startupTimeout = timeout
+}
+
+// SetModuleTimeouts sets the Init and Start timeouts of the named module,
+// overriding the module's own StartupTimeoutsAware declaration and the defaults
+// set with SetDefaultModuleTimeouts.
+func (app *StdApplication) SetModuleTimeouts(moduleName string, timeouts ModuleTimeouts) {
+	if app.moduleTimeouts == nil {
+		app.moduleTimeouts = make(map[string]ModuleTimeouts)
+	}
+	app.moduleTimeouts[moduleName] = timeouts
+}
+
+// SetDefaultModuleTimeouts sets the Init and Start timeouts of modules without
+// timeouts of their own.
+func (app *StdApplication) SetDefaultModuleTimeouts(timeouts ModuleTimeouts) {
+	app.defaultModuleTimeouts = timeouts
+}
+
+// timeouts returns the Init and Start timeouts of the named module.
+func (app *StdApplication) timeouts(name string) ModuleTimeouts {
+	if timeouts, ok := app.moduleTimeouts[name]; ok {
+		return timeouts
+	}
+	if aware, ok := app.moduleRegistry[name].(StartupTimeoutsAware); ok {
+		return aware.StartupTimeouts()
+	}
+	return app.defaultModuleTimeouts
+}
+
+// beginStartup starts the startup deadline unless it's running already.
+func (app *StdApplication) beginStartup(now time.Time) {
+	if app.startupTimeout > 0 && app.startupDeadline.IsZero() {
+		app.startupDeadline = now.Add(app.startupTimeout)
+	}
+}
+
+// startupExpired reports whether the startup deadline has passed by now.
+func (app *StdApplication) startupExpired(now time.Time) bool {
+	return !app.startupDeadline.IsZero() && !now.Before(app.startupDeadline)
+}
+
+// phaseDeadline returns when a module must have finished a phase started now,
+// bounded by the startup deadline during startup, and whether the startup deadline
+// is the tighter one. The deadline is zero without a limit.
+func (app *StdApplication) phaseDeadline(timeout time.Duration, startup bool, now time.Time) (time.Time, bool) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = now.Add(timeout)
+	}
+	if startup && !app.startupDeadline.IsZero() && (deadline.IsZero() || app.startupDeadline.Before(deadline)) {
+		return app.startupDeadline, true
+	}
+	return deadline, false
+}
+
+// timeoutError describes a module that missed its deadline, keeping the error the
+// module returned unless it only reports the expired deadline.
+func timeoutError(sentinel error, deadline, started time.Time, startupLimited bool, timeout time.Duration, err error) error {
+	timeoutErr := fmt.Errorf("%w after %s", sentinel, timeout)
+	if startupLimited {
+		timeoutErr = fmt.Errorf("%w: startup timeout elapsed after %s", sentinel, deadline.Sub(started).Round(time.Millisecond))
+	}
+	if err == nil || errors.Is(err, context.DeadlineExceeded) {
+		return timeoutErr
+	}
+	return errors.Join(timeoutErr, err)
+}
+
+// initModule initializes the named module within its Init timeout, and the
+// startup timeout when startup is set. ContextInitializer modules get a context
+// that expires at the deadline; Init isn't abandoned when it passes, so a module
+// that misses it fails with ErrModuleInitTimeout once Init returned.
+func (app *StdApplication) initModule(name string, module Module, appToPass Application, startup bool) error {
+	started := time.Now()
+	timeout := app.timeouts(name).Init
+	deadline, startupLimited := app.phaseDeadline(timeout, startup, started)
+	initializer, withContext := module.(ContextInitializer)
+	if deadline.IsZero() {
+		if withContext {
+			return initializer.InitContext(context.Background(), appToPass)
+		}
+		return module.Init(appToPass)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	var err error
+	if withContext {
+		err = initializer.InitContext(ctx, appToPass)
+	} else {
+		err = module.Init(appToPass)
+	}
+	if ctx.Err() == nil {
+		return err
+	}
+	app.logger.Warn("Module did not initialize in time", "module", name, "took", time.Since(started))
+	return timeoutError(ErrModuleInitTimeout, deadline, started, startupLimited, timeout, err)
+}
+
+// startModule starts the named module within its Start timeout, and the startup
+// timeout when startup is set. The module starts with a context that expires at
+// its deadline while Start runs, and with ctx once Start returned, so it can keep
+// using the context for work outliving Start. Like Init, Start isn't abandoned
+// when the deadline passes; a module that misses it fails with
+// ErrModuleStartTimeout once Start returned.
+func (app *StdApplication) startModule(ctx context.Context, name string, module Startable, startup bool) error {
+	started := time.Now()
+	timeout := app.timeouts(name).Start
+	deadline, startupLimited := app.phaseDeadline(timeout, startup, started)
+	if deadline.IsZero() {
+		return module.Start(ctx)
+	}
+
+	startCtx := newStartContext(ctx, deadline)
+	defer startCtx.detach()
+	err := module.Start(startCtx)
+	if !errors.Is(startCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	app.logger.Warn("Module did not start in time", "module", name, "took", time.Since(started))
+	return timeoutError(ErrModuleStartTimeout, deadline, started, startupLimited, timeout, err)
+}
+
+// startContext is the context a module with a Start deadline starts with. It
+// carries the application context's values and is cancelled with it, and until
+// detached it also expires at the deadline.
+type startContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+
+	mu       sync.Mutex
+	err      error
+	detached bool
+	timer    *time.Timer
+}
+
+func newStartContext(parent context.Context, deadline time.Time) *startContext {
+	c := &startContext{Context: parent, deadline: deadline, done: make(chan struct{})}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	context.AfterFunc(parent, func() { c.cancel(parent.Err()) })
+	c.timer = time.AfterFunc(time.Until(deadline), func() { c.cancel(context.DeadlineExceeded) })
+	return c
+}
+
+// cancel closes the context with err unless it's closed or was detached from its
+// deadline and err is the deadline's.
+func (c *startContext) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil || (c.detached && errors.Is(err, context.DeadlineExceeded)) {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
+
+// detach drops the deadline, leaving the context to be cancelled with its parent.
+func (c *startContext) detach() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.detached = true
+	c.timer.Stop()
+}
+
+// Deadline returns the Start deadline until detached, and the parent's after.
+func (c *startContext) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.detached {
+		return c.Context.Deadline()
+	}
+	return c.deadline, true
+}
+
+func (c *startContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *startContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
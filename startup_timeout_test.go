@@ -0,0 +1,208 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hangingModule takes initDelay to initialize, and blocks in Start until released
+// or, when it watches its context, until the context is done.
+type hangingModule struct {
+	name         string
+	dependencies []string
+	initDelay    time.Duration
+	hangStart    bool
+	timeouts     *ModuleTimeouts
+	release      chan struct{}
+
+	initialized atomic.Bool
+	startCtx    atomic.Value // context.Context
+}
+
+func (m *hangingModule) Name() string { return m.name }
+
+func (m *hangingModule) Dependencies() []string { return m.dependencies }
+
+func (m *hangingModule) Init(Application) error {
+	time.Sleep(m.initDelay)
+	m.initialized.Store(true)
+	return nil
+}
+
+func (m *hangingModule) Start(ctx context.Context) error {
+	m.startCtx.Store(ctx)
+	if m.hangStart {
+		select {
+		case <-m.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (m *hangingModule) startContext() context.Context {
+	ctx, _ := m.startCtx.Load().(context.Context)
+	return ctx
+}
+
+func (m *hangingModule) StartupTimeouts() ModuleTimeouts {
+	if m.timeouts == nil {
+		return ModuleTimeouts{}
+	}
+	return *m.timeouts
+}
+
+// contextInitModule gives up connecting when its Init context is done.
+type contextInitModule struct {
+	initErr chan error
+}
+
+func (m *contextInitModule) Name() string { return "database" }
+
+func (m *contextInitModule) Init(Application) error {
+	return errors.New("Init called instead of InitContext")
+}
+
+func (m *contextInitModule) InitContext(ctx context.Context, _ Application) error {
+	<-ctx.Done()
+	m.initErr <- ctx.Err()
+	return ctx.Err()
+}
+
+func newTimeoutTestApp(t *testing.T, modules ...Module) *StdApplication {
+	t.Helper()
+	app := newLazyTestApp(modules...)
+	t.Cleanup(func() {
+		for _, module := range modules {
+			if hanging, ok := module.(*hangingModule); ok {
+				close(hanging.release)
+			}
+		}
+	})
+	return app
+}
+
+func TestModuleTimeouts_InitTimeout(t *testing.T) {
+	db := &hangingModule{name: "database", initDelay: 100 * time.Millisecond, release: make(chan struct{})}
+	app := newTimeoutTestApp(t, db, &hangingModule{name: "cache", release: make(chan struct{})})
+	app.SetModuleTimeouts("database", ModuleTimeouts{Init: 50 * time.Millisecond})
+
+	err := app.Init()
+	require.ErrorIs(t, err, ErrModuleInitTimeout)
+	assert.True(t, db.initialized.Load(), "Init fails once the late module returned, not while it still runs")
+	assert.Contains(t, err.Error(), "module 'database' failed to initialize: module init timed out after 50ms")
+}
+
+func TestModuleTimeouts_StartupTimeoutStopsInit(t *testing.T) {
+	a := &hangingModule{name: "a", initDelay: 200 * time.Millisecond, release: make(chan struct{})}
+	b := &hangingModule{name: "b", dependencies: []string{"a"}, initDelay: 200 * time.Millisecond, release: make(chan struct{})}
+	c := &hangingModule{name: "c", dependencies: []string{"b"}, initDelay: 200 * time.Millisecond, release: make(chan struct{})}
+	app := newTimeoutTestApp(t, c, b, a)
+	app.SetStartupTimeout(50 * time.Millisecond)
+
+	err := app.Init()
+	require.ErrorIs(t, err, ErrModuleInitTimeout)
+	assert.Contains(t, err.Error(), "module 'a' failed to initialize")
+	assert.Contains(t, err.Error(), "startup timeout elapsed before module 'b' initialized")
+	assert.True(t, a.initialized.Load())
+	time.Sleep(250 * time.Millisecond)
+	assert.False(t, b.initialized.Load(), "modules after the startup deadline are not initialized")
+	assert.False(t, c.initialized.Load(), "modules after the startup deadline are not initialized")
+}
+
+func TestModuleTimeouts_InitContext(t *testing.T) {
+	db := &contextInitModule{initErr: make(chan error, 1)}
+	app := newLazyTestApp(db)
+	app.SetDefaultModuleTimeouts(ModuleTimeouts{Init: 20 * time.Millisecond})
+
+	require.ErrorIs(t, app.Init(), ErrModuleInitTimeout)
+	select {
+	case err := <-db.initErr:
+		require.ErrorIs(t, err, context.DeadlineExceeded, "the module's Init context expires at the deadline")
+	case <-time.After(time.Second):
+		t.Fatal("InitContext was not cancelled")
+	}
+}
+
+func TestModuleTimeouts_StartTimeout(t *testing.T) {
+	timeouts := ModuleTimeouts{Start: 50 * time.Millisecond}
+	db := &hangingModule{name: "database", hangStart: true, timeouts: &timeouts, release: make(chan struct{})}
+	app := newTimeoutTestApp(t, db)
+	require.NoError(t, app.Init())
+
+	err := app.Start()
+	require.ErrorIs(t, err, ErrModuleStartTimeout)
+	assert.Contains(t, err.Error(), "failed to start module database: module start timed out after 50ms")
+	require.ErrorIs(t, db.startContext().Err(), context.DeadlineExceeded, "the module's Start context expires at the deadline")
+}
+
+func TestModuleTimeouts_StartContextOutlivesStart(t *testing.T) {
+	cache := &hangingModule{name: "cache", release: make(chan struct{})}
+	app := newTimeoutTestApp(t, cache)
+	app.SetModuleTimeouts("cache", ModuleTimeouts{Start: 20 * time.Millisecond})
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+
+	ctx := cache.startContext()
+	deadline, ok := ctx.Deadline()
+	assert.False(t, ok, "the deadline no longer applies once Start returned")
+	assert.Zero(t, deadline)
+	time.Sleep(40 * time.Millisecond)
+	require.NoError(t, ctx.Err(), "modules can keep using the context past the deadline")
+
+	require.NoError(t, app.Stop())
+	select {
+	case <-ctx.Done():
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("the context was not cancelled when the application stopped")
+	}
+}
+
+func TestModuleTimeouts_StartupTimeout(t *testing.T) {
+	db := &hangingModule{name: "database", hangStart: true, release: make(chan struct{})}
+	app := newTimeoutTestApp(t, db)
+	app.SetStartupTimeout(50 * time.Millisecond)
+	app.SetModuleTimeouts("database", ModuleTimeouts{Start: time.Minute})
+	require.NoError(t, app.Init())
+
+	err := app.Start()
+	require.ErrorIs(t, err, ErrModuleStartTimeout)
+	assert.Contains(t, err.Error(), "module database")
+	assert.Contains(t, err.Error(), "startup timeout elapsed")
+	assert.True(t, app.startupDeadline.IsZero(), "the startup deadline is cleared once Start returned")
+}
+
+func TestModuleTimeouts_Precedence(t *testing.T) {
+	declared := ModuleTimeouts{Init: time.Second}
+	db := &hangingModule{name: "database", timeouts: &declared}
+	app := newLazyTestApp(db, &hangingModule{name: "cache"})
+	app.SetDefaultModuleTimeouts(ModuleTimeouts{Start: time.Minute})
+
+	assert.Equal(t, declared, app.timeouts("database"), "modules declare their own timeouts")
+	assert.Equal(t, ModuleTimeouts{}, app.timeouts("cache"), "even when they declare none")
+	assert.Equal(t, ModuleTimeouts{Start: time.Minute}, app.timeouts("other"))
+	app.SetModuleTimeouts("database", ModuleTimeouts{Start: time.Second})
+	assert.Equal(t, ModuleTimeouts{Start: time.Second}, app.timeouts("database"))
+}
+
+func TestWithModuleTimeouts(t *testing.T) {
+	built, err := NewApplication(
+		WithLogger(&testLogger{}),
+		WithStartupTimeout(time.Minute),
+		WithModuleTimeouts("database", ModuleTimeouts{Init: time.Second}),
+		WithDefaultModuleTimeouts(ModuleTimeouts{Start: 5 * time.Second}),
+	)
+	require.NoError(t, err)
+	app := built.(*StdApplication)
+	assert.Equal(t, time.Minute, app.startupTimeout)
+	assert.Equal(t, ModuleTimeouts{Init: time.Second}, app.timeouts("database"))
+	assert.Equal(t, ModuleTimeouts{Start: 5 * time.Second}, app.timeouts("cache"))
+}
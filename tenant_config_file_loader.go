@@ -25,6 +25,9 @@ type TenantConfigParams struct {
 	ConfigDir string
 	// ConfigFeeders are the feeders to use for loading tenant configs.
 	ConfigFeeders []Feeder
+	// Decrypter decrypts encrypted values in YAML tenant config files. When nil,
+	// AES256_GCM values are decrypted with the key in feeders.ConfigKeyEnvVar.
+	Decrypter feeders.Decrypter
 }
 
 // LoadTenantConfigs scans the given directory for config files.
@@ -102,7 +105,7 @@ func loadTenantConfigsWithBaseSupport(app Application, tenantService TenantServi
 	// Load each unique tenant using base config feeder
 	loadedTenants := 0
 	for tenantID := range tenantFiles {
-		if err := loadBaseConfigTenant(app, tenantService, tenantID); err != nil {
+		if err := loadBaseConfigTenant(app, tenantService, tenantID, params.Decrypter); err != nil {
 			app.Logger().Warn("Failed to load tenant config, skipping", "tenantID", tenantID, "error", err)
 			continue
 		}
@@ -114,7 +117,7 @@ func loadTenantConfigsWithBaseSupport(app Application, tenantService TenantServi
 }
 
 // loadBaseConfigTenant loads a single tenant using base config structure
-func loadBaseConfigTenant(app Application, tenantService TenantService, tenantID string) error {
+func loadBaseConfigTenant(app Application, tenantService TenantService, tenantID string, decrypter feeders.Decrypter) error {
 	app.Logger().Debug("Loading base config tenant", "tenantID", tenantID)
 
 	// Create feeders list with separate feeders for base and environment tenant configs
@@ -123,7 +126,7 @@ func loadBaseConfigTenant(app Application, tenantService TenantService, tenantID
 	// Create base tenant feeder if base tenant config exists
 	baseTenantPath := findTenantConfigFile(BaseConfigSettings.ConfigDir, "base", "tenants", tenantID)
 	if baseTenantPath != "" {
-		baseTenantFeeder := createTenantFeeder(baseTenantPath)
+		baseTenantFeeder := createTenantFeeder(baseTenantPath, decrypter)
 		if baseTenantFeeder != nil {
 			tenantFeeders = append(tenantFeeders, baseTenantFeeder)
 		}
@@ -132,7 +135,7 @@ func loadBaseConfigTenant(app Application, tenantService TenantService, tenantID
 	// Create environment tenant feeder if environment tenant config exists
 	envTenantPath := findTenantConfigFile(BaseConfigSettings.ConfigDir, "environments", BaseConfigSettings.Environment, "tenants", tenantID)
 	if envTenantPath != "" {
-		envTenantFeeder := createTenantFeeder(envTenantPath)
+		envTenantFeeder := createTenantFeeder(envTenantPath, decrypter)
 		if envTenantFeeder != nil {
 			tenantFeeders = append(tenantFeeders, envTenantFeeder)
 		}
@@ -158,11 +161,11 @@ func loadBaseConfigTenant(app Application, tenantService TenantService, tenantID
 }
 
 // findTenantConfigFile searches for a tenant config file with multiple supported extensions.
-// It searches for files with extensions .yaml, .yml, .json, .toml in that order, returning
+// It searches for files with extensions .yaml, .yml, .json, .toml, .ini in that order, returning
 // the first file found. The pathComponents are used to construct the search directory path,
 // with the last component being the tenant name and earlier components forming the directory path.
 func findTenantConfigFile(baseDir string, pathComponents ...string) string {
-	extensions := []string{".yaml", ".yml", ".json", ".toml"}
+	extensions := []string{".yaml", ".yml", ".json", ".toml", ".ini"}
 
 	// Build the directory path
 	dirPath := filepath.Join(append([]string{baseDir}, pathComponents[:len(pathComponents)-1]...)...)
@@ -179,16 +182,18 @@ func findTenantConfigFile(baseDir string, pathComponents ...string) string {
 }
 
 // createTenantFeeder creates an appropriate feeder for a tenant config file
-func createTenantFeeder(filePath string) Feeder {
+func createTenantFeeder(filePath string, decrypter feeders.Decrypter) Feeder {
 	ext := strings.ToLower(filepath.Ext(filePath))
 
 	switch ext {
 	case ".yaml", ".yml":
-		return feeders.NewYamlFeeder(filePath)
+		return feeders.NewYamlFeeder(filePath).WithDecrypter(decrypter)
 	case ".json":
 		return feeders.NewJSONFeeder(filePath)
 	case ".toml":
 		return feeders.NewTomlFeeder(filePath)
+	case ".ini":
+		return feeders.NewIniFeeder(filePath)
 	default:
 		return nil
 	}
@@ -273,7 +278,7 @@ func loadAndRegisterTenant(
 ) error {
 	app.Logger().Debug("Loading tenant config file", "tenantID", tenantID, "file", configPath)
 
-	feederSlice, err := createFeederSlice(fileName, configPath, params.ConfigFeeders)
+	feederSlice, err := createFeederSlice(fileName, configPath, params.ConfigFeeders, params.Decrypter)
 	if err != nil {
 		app.Logger().Warn("Unsupported config file extension", "file", fileName, "error", err)
 		return err
@@ -292,7 +297,7 @@ func loadAndRegisterTenant(
 	return nil
 }
 
-func createFeederSlice(fileName, configPath string, additionalFeeders []Feeder) ([]Feeder, error) {
+func createFeederSlice(fileName, configPath string, additionalFeeders []Feeder, decrypter feeders.Decrypter) ([]Feeder, error) {
 	ext := filepath.Ext(fileName)
 	var feederSlice []Feeder
 
@@ -300,9 +305,11 @@ func createFeederSlice(fileName, configPath string, additionalFeeders []Feeder)
 	case ".json":
 		feederSlice = append(feederSlice, feeders.NewJSONFeeder(configPath))
 	case ".yaml", ".yml":
-		feederSlice = append(feederSlice, feeders.NewYamlFeeder(configPath))
+		feederSlice = append(feederSlice, feeders.NewYamlFeeder(configPath).WithDecrypter(decrypter))
 	case ".toml":
 		feederSlice = append(feederSlice, feeders.NewTomlFeeder(configPath))
+	case ".ini":
+		feederSlice = append(feederSlice, feeders.NewIniFeeder(configPath))
 	default:
 		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExtension, ext)
 	}
@@ -4,6 +4,9 @@ import (
 	"regexp"
 )
 
+// defaultTenantConfigNameRegex matches tenant config files named after their tenant
+var defaultTenantConfigNameRegex = regexp.MustCompile(`^\w+\.(json|yaml|yml|toml|ini)$`)
+
 // TenantConfigLoader is an interface for loading tenant configurations
 type TenantConfigLoader interface {
 	// LoadTenantConfigurations loads configurations for all tenants
@@ -57,7 +60,7 @@ func (l *FileBasedTenantConfigLoader) LoadTenantConfigurations(app Application,
 // DefaultTenantConfigLoader creates a loader with default configuration
 func DefaultTenantConfigLoader(configDir string) *FileBasedTenantConfigLoader {
 	return NewFileBasedTenantConfigLoader(TenantConfigParams{
-		ConfigNameRegex: regexp.MustCompile(`^\w+\.(json|yaml|yml|toml)$`),
+		ConfigNameRegex: defaultTenantConfigNameRegex,
 		ConfigDir:       configDir,
 		ConfigFeeders:   []Feeder{},
 	})
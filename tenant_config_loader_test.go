@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"testing"
+
+	"github.com/CrisisTextLine/modular/feeders"
 )
 
 // MockTenantService implements TenantService for testing
@@ -125,7 +127,7 @@ func TestDefaultTenantConfigLoader(t *testing.T) {
 		t.Errorf("Expected ConfigDir %s, got %s", configDir, loader.configParams.ConfigDir)
 	}
 
-	expectedRegex := `^\w+\.(json|yaml|yml|toml)$`
+	expectedRegex := `^\w+\.(json|yaml|yml|toml|ini)$`
 	if loader.configParams.ConfigNameRegex.String() != expectedRegex {
 		t.Errorf("Expected ConfigNameRegex %s, got %s",
 			expectedRegex, loader.configParams.ConfigNameRegex.String())
@@ -255,3 +257,49 @@ func TestLoadTenantConfigurationsNonExistentDirectory(t *testing.T) {
 		t.Error("Expected error with non-existent directory")
 	}
 }
+
+// Test that encrypted values in YAML tenant files are decrypted
+func TestLoadTenantConfigurationsEncryptedValues(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	password, err := feeders.EncryptValue(key, "tenant-secret")
+	if err != nil {
+		t.Fatalf("Failed to encrypt value: %v", err)
+	}
+	tempDir := t.TempDir()
+	content := "Database:\n  user: app\n  password: " + password + "\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "tenant1.yaml"), []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write tenant file: %v", err)
+	}
+
+	app := NewStdApplication(nil, &logger{t})
+	app.RegisterConfigSection("Database", NewStdConfigProvider(&struct {
+		User     string
+		Password string
+	}{}))
+	decrypter, err := feeders.NewAESGCMDecrypter(key)
+	if err != nil {
+		t.Fatalf("Failed to create decrypter: %v", err)
+	}
+
+	tenantService := NewMockTenantService()
+	loader := NewFileBasedTenantConfigLoader(TenantConfigParams{
+		ConfigNameRegex: regexp.MustCompile(`^tenant\d+\.yaml$`),
+		ConfigDir:       tempDir,
+		Decrypter:       decrypter,
+	})
+	if err := loader.LoadTenantConfigurations(app, tenantService); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	provider, _ := tenantService.GetTenantConfig("tenant1", "Database")
+	if provider == nil {
+		t.Fatal("Expected tenant1 Database config to be registered")
+	}
+	cfg := provider.GetConfig().(*struct {
+		User     string
+		Password string
+	})
+	if cfg.User != "app" || cfg.Password != "tenant-secret" {
+		t.Errorf("Expected decrypted config, got user %q password %q", cfg.User, cfg.Password)
+	}
+}
@@ -159,6 +159,37 @@ func (ts *StandardTenantService) RemoveTenant(tenantID TenantID) error {
 	return nil
 }
 
+// replaceTenantConfigs replaces all of a registered tenant's configuration sections.
+// Tenant-aware modules are notified with OnTenantRemoved and then OnTenantRegistered,
+// so they drop what they built from the previous configuration and set the tenant
+// up again from the new one.
+func (ts *StandardTenantService) replaceTenantConfigs(tenantID TenantID, configs map[string]ConfigProvider) {
+	ts.mutex.Lock()
+	defer ts.mutex.Unlock()
+
+	for _, module := range ts.tenantAwareModules {
+		module.OnTenantRemoved(tenantID)
+		if notifications, exists := ts.moduleNotifications[module]; exists {
+			delete(notifications, tenantID)
+		}
+	}
+
+	tenantCfg := NewTenantConfigProvider(nil)
+	tenantCfg.initializeConfigsForTenant(tenantID)
+	for section, provider := range configs {
+		if provider == nil || provider.GetConfig() == nil {
+			ts.logger.Warn("Skipping nil config provider or config", "tenantID", tenantID, "section", section)
+			continue
+		}
+		tenantCfg.SetTenantConfig(tenantID, section, provider)
+	}
+	ts.tenantConfigs[tenantID] = tenantCfg
+
+	for _, module := range ts.tenantAwareModules {
+		ts.notifyModuleAboutTenant(module, tenantID)
+	}
+}
+
 // RegisterTenantAwareModule registers a module to receive tenant events
 func (ts *StandardTenantService) RegisterTenantAwareModule(module TenantAwareModule) error {
 	ts.mutex.Lock()
@@ -0,0 +1,226 @@
+package modular
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sync"
+
+	"github.com/CrisisTextLine/modular/feeders"
+)
+
+// DirectoryTenantSource loads tenants from a directory of config files, one per
+// tenant, named after the tenant ID (e.g. "tenant123.yaml") as with
+// LoadTenantConfigs. A tenant is reloaded when its file's content changes.
+type DirectoryTenantSource struct {
+	// Dir is the directory holding the tenant config files
+	Dir string
+	// Pattern selects the tenant config files; it defaults to JSON, YAML, TOML and INI files
+	Pattern *regexp.Regexp
+	// Decrypter decrypts encrypted values in YAML files; see TenantConfigParams.Decrypter
+	Decrypter feeders.Decrypter
+}
+
+// NewDirectoryTenantSource creates a source for the tenant config files in dir.
+func NewDirectoryTenantSource(dir string) *DirectoryTenantSource {
+	return &DirectoryTenantSource{Dir: dir, Pattern: defaultTenantConfigNameRegex}
+}
+
+// LoadTenants returns a definition for each tenant config file in the directory.
+func (s *DirectoryTenantSource) LoadTenants(ctx context.Context) ([]TenantDefinition, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant directory %s: %w", s.Dir, err)
+	}
+
+	pattern := s.Pattern
+	if pattern == nil {
+		pattern = defaultTenantConfigNameRegex
+	}
+
+	var definitions []TenantDefinition
+	for _, entry := range entries {
+		if entry.IsDir() || !pattern.MatchString(entry.Name()) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("tenant directory load cancelled: %w", err)
+		}
+
+		tenantID, path := extractTenantInfo(entry.Name(), s.Dir)
+		content, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue // Removed since the directory was read
+			}
+			return nil, fmt.Errorf("failed to read tenant config file %s: %w", path, err)
+		}
+		configFeeders, err := createFeederSlice(entry.Name(), path, nil, s.Decrypter)
+		if err != nil {
+			continue // Unsupported extension
+		}
+
+		sum := sha256.Sum256(content)
+		definitions = append(definitions, TenantDefinition{
+			ID:      tenantID,
+			Feeders: configFeeders,
+			Version: hex.EncodeToString(sum[:]),
+		})
+	}
+	return definitions, nil
+}
+
+// SQLTenantSource loads tenants from a database table. Query must return two
+// columns: the tenant ID and the tenant's configuration as a JSON object of
+// sections, for example
+//
+//	SELECT id, config FROM tenants WHERE active
+//
+// where config holds {"database": {"dsn": "..."}, "cache": {"prefix": "..."}}.
+type SQLTenantSource struct {
+	DB    *sql.DB
+	Query string
+}
+
+// NewSQLTenantSource creates a source for the tenants returned by query.
+func NewSQLTenantSource(db *sql.DB, query string) *SQLTenantSource {
+	return &SQLTenantSource{DB: db, Query: query}
+}
+
+// LoadTenants returns a definition for each row returned by the query.
+func (s *SQLTenantSource) LoadTenants(ctx context.Context) ([]TenantDefinition, error) {
+	rows, err := s.DB.QueryContext(ctx, s.Query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []TenantDefinition
+	for rows.Next() {
+		var id string
+		var config []byte
+		if err := rows.Scan(&id, &config); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant row: %w", err)
+		}
+		sections, err := decodeTenantSections(config)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", id, err)
+		}
+		definitions = append(definitions, TenantDefinition{ID: TenantID(id), Sections: sections})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tenant rows: %w", err)
+	}
+	return definitions, nil
+}
+
+// HTTPTenantSource loads tenants from an HTTP endpoint returning a JSON object
+// that maps tenant IDs to their configuration sections:
+//
+//	{"tenant-a": {"database": {"dsn": "..."}}, "tenant-b": {}}
+//
+// When the endpoint returns an ETag, it is sent back with If-None-Match and a
+// 304 Not Modified response keeps the previous tenants.
+type HTTPTenantSource struct {
+	URL string
+	// Client sends the requests; it defaults to http.DefaultClient
+	Client *http.Client
+	// Header is added to each request, for example to authenticate
+	Header http.Header
+
+	mu          sync.Mutex
+	etag        string
+	definitions []TenantDefinition
+}
+
+// NewHTTPTenantSource creates a source for the tenants served at url.
+func NewHTTPTenantSource(url string) *HTTPTenantSource {
+	return &HTTPTenantSource{URL: url}
+}
+
+// LoadTenants fetches the tenants from the endpoint.
+func (s *HTTPTenantSource) LoadTenants(ctx context.Context) ([]TenantDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tenant request: %w", err)
+	}
+	for name, values := range s.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Accept", "application/json")
+	if s.etag != "" {
+		req.Header.Set("If-None-Match", s.etag)
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tenants from %s: %w", s.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && s.etag != "" {
+		return s.definitions, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w from %s: %s", ErrTenantSourceStatus, s.URL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants from %s: %w", s.URL, err)
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants from %s: %w", s.URL, err)
+	}
+
+	definitions := make([]TenantDefinition, 0, len(document))
+	for id, config := range document {
+		sections, err := decodeTenantSections(config)
+		if err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", id, err)
+		}
+		definitions = append(definitions, TenantDefinition{ID: TenantID(id), Sections: sections})
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	s.definitions = definitions
+	return definitions, nil
+}
+
+// decodeTenantSections decodes a tenant's configuration, a JSON object of
+// sections. An empty or null configuration has no sections.
+func decodeTenantSections(config []byte) (map[string]json.RawMessage, error) {
+	var sections map[string]json.RawMessage
+	if len(config) == 0 {
+		return sections, nil
+	}
+	if err := json.Unmarshal(config, &sections); err != nil {
+		return nil, fmt.Errorf("failed to decode tenant configuration: %w", err)
+	}
+	return sections, nil
+}
+
+// Compile-time checks that the sources implement TenantSource
+var (
+	_ TenantSource = (*DirectoryTenantSource)(nil)
+	_ TenantSource = (*SQLTenantSource)(nil)
+	_ TenantSource = (*HTTPTenantSource)(nil)
+)
@@ -0,0 +1,219 @@
+package modular
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TimelinePath is the conventional path for mounting NewTimelineHandler.
+const TimelinePath = "/__timeline"
+
+// TimelineEventKind identifies a step of the application lifecycle in its Timeline.
+type TimelineEventKind string
+
+// Kinds of timeline events.
+const (
+	// TimelineConfigLoaded covers loading the configuration, including overrides
+	// and config loaded hooks
+	TimelineConfigLoaded TimelineEventKind = "config.loaded"
+	// TimelineModuleInit covers one module's Init
+	TimelineModuleInit TimelineEventKind = "module.init"
+	// TimelineServiceRegistered marks a service registration
+	TimelineServiceRegistered TimelineEventKind = "service.registered"
+	// TimelineTenantRegistered marks a tenant registration with the tenant service
+	TimelineTenantRegistered TimelineEventKind = "tenant.registered"
+	// TimelineInitCompleted covers Init as a whole
+	TimelineInitCompleted TimelineEventKind = "init.completed"
+	// TimelineModuleStart covers one module's Start
+	TimelineModuleStart TimelineEventKind = "module.start"
+	// TimelineStarted covers Start as a whole
+	TimelineStarted TimelineEventKind = "app.started"
+	// TimelineModuleStop covers one module's Stop
+	TimelineModuleStop TimelineEventKind = "module.stop"
+	// TimelineStopped covers Stop as a whole
+	TimelineStopped TimelineEventKind = "app.stopped"
+)
+
+// maxTimelineEvents bounds the events kept in a timeline. Once reached, the oldest
+// events recorded after Start are dropped, so startup stays inspectable in
+// long-running applications registering tenants or swapping modules. Startup keeps
+// at most half of the events.
+const maxTimelineEvents = 4096
+
+// TimelineEvent is a step of the application lifecycle.
+type TimelineEvent struct {
+	// Seq orders events by when they completed, starting at 1
+	Seq  uint64            `json:"seq"`
+	Kind TimelineEventKind `json:"kind"`
+	// Time is when the step began
+	Time time.Time `json:"time"`
+	// Duration is how long the step took; zero for instantaneous events
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	// Module is the module the step concerns, if any
+	Module string `json:"module,omitempty"`
+	// Name is the registered service or tenant
+	Name string `json:"name,omitempty"`
+	// Error is the step's error message, if it failed
+	Error string `json:"error,omitempty"`
+}
+
+// End is when the step completed.
+func (e TimelineEvent) End() time.Time {
+	return e.Time.Add(e.Duration)
+}
+
+// Timeline is an ordered list of lifecycle events.
+type Timeline []TimelineEvent
+
+// Kind returns the events of the given kinds.
+func (t Timeline) Kind(kinds ...TimelineEventKind) Timeline {
+	return t.filter(func(e TimelineEvent) bool { return slices.Contains(kinds, e.Kind) })
+}
+
+// Module returns the events concerning the named module.
+func (t Timeline) Module(name string) Timeline {
+	return t.filter(func(e TimelineEvent) bool { return e.Module == name })
+}
+
+// Failed returns the events of steps that returned an error.
+func (t Timeline) Failed() Timeline {
+	return t.filter(func(e TimelineEvent) bool { return e.Error != "" })
+}
+
+// Slowest returns up to n events with the longest durations, slowest first.
+func (t Timeline) Slowest(n int) Timeline {
+	sorted := slices.Clone(t)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+	if n >= 0 && n < len(sorted) {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+func (t Timeline) filter(keep func(TimelineEvent) bool) Timeline {
+	filtered := Timeline{}
+	for _, e := range t {
+		if keep(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// TimelineProvider is implemented by applications recording their lifecycle.
+// StdApplication and ObservableApplication implement it.
+type TimelineProvider interface {
+	// Timeline returns the lifecycle events recorded so far, in order
+	Timeline() Timeline
+}
+
+var (
+	_ TimelineProvider = (*StdApplication)(nil)
+	_ TimelineProvider = (*ObservableApplication)(nil)
+)
+
+// Timeline returns the application's lifecycle events in the order they completed:
+// configuration loading, each module's init, start and stop with its duration, and
+// the services and tenants registered. Render it with `modcli timeline`.
+func (app *StdApplication) Timeline() Timeline {
+	return app.timeline.events()
+}
+
+// TimelineFor returns the lifecycle timeline of app, or an empty timeline for
+// applications that do not implement TimelineProvider.
+func TimelineFor(app Application) Timeline {
+	if provider, ok := app.(TimelineProvider); ok {
+		return provider.Timeline()
+	}
+	return Timeline{}
+}
+
+// NewTimelineHandler serves the provider's Timeline as JSON. Mount it at TimelinePath:
+//
+//	router.Handle(modular.TimelinePath, modular.NewTimelineHandler(app))
+func NewTimelineHandler(provider TimelineProvider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(provider.Timeline())
+	})
+}
+
+// timelineRecorder collects the events of an application's timeline.
+type timelineRecorder struct {
+	mu      sync.Mutex
+	seq     uint64
+	list    []TimelineEvent
+	started bool // set once Start completed
+	pinned  int  // number of events recorded until Start completed
+	tenants bool // set once tenant registrations are recorded
+}
+
+// step records a step that began at started and ends now.
+func (t *timelineRecorder) step(kind TimelineEventKind, module string, started time.Time, err error) {
+	event := TimelineEvent{Kind: kind, Time: started, Duration: time.Since(started), Module: module}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	t.add(event)
+}
+
+// mark records an instantaneous event.
+func (t *timelineRecorder) mark(kind TimelineEventKind, module, name string) {
+	t.add(TimelineEvent{Kind: kind, Time: time.Now(), Module: module, Name: name})
+}
+
+func (t *timelineRecorder) add(event TimelineEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.seq++
+	event.Seq = t.seq
+	if len(t.list) >= maxTimelineEvents {
+		oldest := min(t.pinned, maxTimelineEvents/2)
+		t.list = slices.Delete(t.list, oldest, oldest+1)
+	}
+	t.list = append(t.list, event)
+	if !t.started {
+		t.pinned = len(t.list)
+		t.started = event.Kind == TimelineStarted
+	}
+}
+
+func (t *timelineRecorder) events() Timeline {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append(Timeline{}, t.list...)
+}
+
+// recordTenantRegistrations records the tenants registered with svc in the timeline,
+// including those registered already. Later calls, such as when Init is retried,
+// have no effect.
+func (app *StdApplication) recordTenantRegistrations(svc TenantService) {
+	if app.timeline.tenants {
+		return
+	}
+	app.timeline.tenants = true
+	if err := svc.RegisterTenantAwareModule(&timelineTenantListener{app: app}); err != nil {
+		app.logger.Warn("Failed to record tenant registrations in the timeline", "error", err)
+	}
+}
+
+// timelineTenantListener records tenant registrations. It is registered with the
+// tenant service like a tenant-aware module, before tenant configs are loaded.
+type timelineTenantListener struct {
+	app *StdApplication
+}
+
+func (l *timelineTenantListener) Name() string { return "modular.timeline" }
+
+func (l *timelineTenantListener) Init(Application) error { return nil }
+
+func (l *timelineTenantListener) OnTenantRegistered(tenantID TenantID) {
+	l.app.timeline.mark(TimelineTenantRegistered, "", string(tenantID))
+}
+
+func (l *timelineTenantListener) OnTenantRemoved(TenantID) {}
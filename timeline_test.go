@@ -0,0 +1,143 @@
+package modular
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowInitModule takes initDelay to initialize and provides its services.
+type slowInitModule struct {
+	testModule
+	initDelay  time.Duration
+	services   []ServiceProvider
+	startError error
+}
+
+func (m *slowInitModule) Init(Application) error {
+	time.Sleep(m.initDelay)
+	return nil
+}
+
+func (m *slowInitModule) ProvidesServices() []ServiceProvider { return m.services }
+
+func (m *slowInitModule) Start(context.Context) error { return m.startError }
+
+// timelineTenantLoader registers the tenant "acme" when tenant configs are loaded.
+type timelineTenantLoader struct{}
+
+func (timelineTenantLoader) LoadTenantConfigurations(_ Application, tenantService TenantService) error {
+	return tenantService.RegisterTenant("acme", nil)
+}
+
+// timelineKinds returns the kind and subject of each event, for comparing orders.
+func timelineKinds(timeline Timeline) []string {
+	kinds := make([]string, 0, len(timeline))
+	for _, event := range timeline {
+		kind := string(event.Kind)
+		if event.Module != "" {
+			kind += " " + event.Module
+		}
+		if event.Name != "" {
+			kind += " " + event.Name
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds
+}
+
+func TestStdApplication_Timeline(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	tenants := NewStandardTenantService(app.Logger())
+	require.NoError(t, app.RegisterService("tenantService", tenants))
+	require.NoError(t, app.RegisterService("tenantConfigLoader", TenantConfigLoader(timelineTenantLoader{})))
+	app.RegisterModule(&slowInitModule{
+		testModule: testModule{name: "db"},
+		initDelay:  20 * time.Millisecond,
+		services:   []ServiceProvider{{Name: "db.conn", Instance: &MockStorage{}}},
+	})
+	app.RegisterModule(&slowInitModule{testModule: testModule{name: "api", dependencies: []string{"db"}}})
+
+	require.NoError(t, app.Init())
+	require.NoError(t, app.Start())
+	require.NoError(t, tenants.RegisterTenant("beta", nil))
+	require.NoError(t, app.Stop())
+
+	timeline := TimelineFor(app)
+	assert.Equal(t, []string{
+		"service.registered tenantService",
+		"service.registered tenantConfigLoader",
+		"config.loaded",
+		"module.init db",
+		"service.registered db db.conn",
+		"module.init api",
+		"tenant.registered acme",
+		"init.completed",
+		"module.start db",
+		"module.start api",
+		"app.started",
+		"tenant.registered beta",
+		"module.stop api",
+		"module.stop db",
+		"app.stopped",
+	}, timelineKinds(timeline))
+
+	for i, event := range timeline {
+		assert.Equal(t, uint64(i+1), event.Seq)
+		assert.False(t, event.Time.IsZero())
+	}
+	dbInit := timeline.Kind(TimelineModuleInit).Module("db")
+	require.Len(t, dbInit, 1)
+	assert.GreaterOrEqual(t, dbInit[0].Duration, 20*time.Millisecond)
+	assert.Equal(t, dbInit[0], timeline.Kind(TimelineModuleInit, TimelineModuleStart).Slowest(1)[0])
+	assert.GreaterOrEqual(t, timeline.Kind(TimelineInitCompleted)[0].Duration, dbInit[0].Duration)
+	assert.Empty(t, timeline.Failed())
+	assert.Zero(t, timeline.Kind(TimelineServiceRegistered)[0].Duration)
+
+	rec := httptest.NewRecorder()
+	NewTimelineHandler(app.(TimelineProvider)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, TimelinePath, nil))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	var served Timeline
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &served))
+	assert.Equal(t, timelineKinds(timeline), timelineKinds(served))
+	assert.Equal(t, dbInit[0].Duration, served.Kind(TimelineModuleInit).Module("db")[0].Duration)
+}
+
+func TestStdApplication_TimelineRecordsFailures(t *testing.T) {
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	app.RegisterModule(&slowInitModule{testModule: testModule{name: "api"}, startError: errors.New("port in use")})
+	require.NoError(t, app.Init())
+	require.Error(t, app.Start())
+
+	failed := TimelineFor(app).Failed()
+	assert.Equal(t, []string{"module.start api", "app.started"}, timelineKinds(failed))
+	assert.Equal(t, "port in use", failed[0].Error)
+	assert.Contains(t, failed[1].Error, "failed to start module api")
+}
+
+func TestTimelineRecorder_KeepsStartup(t *testing.T) {
+	var recorder timelineRecorder
+	recorder.step(TimelineConfigLoaded, "", time.Now(), nil)
+	recorder.step(TimelineStarted, "", time.Now(), nil)
+	for i := range maxTimelineEvents + 10 {
+		recorder.mark(TimelineTenantRegistered, "", string(rune('a'+i%26)))
+	}
+
+	timeline := recorder.events()
+	require.Len(t, timeline, maxTimelineEvents)
+	assert.Equal(t, TimelineConfigLoaded, timeline[0].Kind)
+	assert.Equal(t, TimelineStarted, timeline[1].Kind)
+	assert.Equal(t, uint64(maxTimelineEvents+12), timeline[len(timeline)-1].Seq)
+	assert.Equal(t, uint64(15), timeline[2].Seq, "the oldest events after Start are dropped")
+}
+
+func TestTimelineFor_OtherApplications(t *testing.T) {
+	assert.Equal(t, Timeline{}, TimelineFor(nil))
+}
@@ -0,0 +1,64 @@
+package modular
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// GetService returns the service registered under name as a T. It resolves the
+// service like Application.GetService, so T may be an interface the service
+// implements, the service's concrete type, or the type a pointer service points
+// to:
+//
+//	router, err := modular.GetService[chimux.BasicRouter](app, "router")
+//
+// It returns ErrServiceNotFound when no service is registered under name, and
+// ErrServiceIncompatible when the service is not a T. Both errors list the
+// registered services, to spot a misspelled name or a service registered under
+// another one.
+func GetService[T any](app Application, name string) (T, error) {
+	var target T
+	err := app.GetService(name, &target)
+	if err == nil {
+		return target, nil
+	}
+
+	switch {
+	case errors.Is(err, ErrServiceNotFound):
+		return target, fmt.Errorf("%w: %s as %s (registered services: %s)",
+			ErrServiceNotFound, name, reflect.TypeFor[T](), registeredServiceNames(app))
+	case errors.Is(err, ErrServiceIncompatible):
+		return target, fmt.Errorf("%w (registered services: %s)", err, registeredServiceNames(app))
+	default:
+		return target, err
+	}
+}
+
+// MustGetService is like GetService but panics when the service can't be
+// returned as a T. It suits wiring code where a missing service is a programming
+// error, such as main or a module's Init after declaring the service required.
+func MustGetService[T any](app Application, name string) T {
+	service, err := GetService[T](app, name)
+	if err != nil {
+		panic(err)
+	}
+	return service
+}
+
+// registeredServiceNames returns the sorted, comma-separated names of the
+// services registered with app.
+func registeredServiceNames(app Application) string {
+	registry := app.SvcRegistry()
+	if len(registry) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return strings.Join(names, ", ")
+}
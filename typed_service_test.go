@@ -0,0 +1,67 @@
+package modular
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{ name string }
+
+func (g *englishGreeter) Greet() string { return "hello " + g.name }
+
+func newTypedServiceTestApp(t *testing.T) Application {
+	t.Helper()
+	app := NewStdApplication(NewStdConfigProvider(&testCfg{Str: "app"}), &testLogger{})
+	require.NoError(t, app.RegisterService("greeter", &englishGreeter{name: "ada"}))
+	require.NoError(t, app.RegisterService("retries", 3))
+	return app
+}
+
+func TestGetService(t *testing.T) {
+	app := newTypedServiceTestApp(t)
+
+	byInterface, err := GetService[greeter](app, "greeter")
+	require.NoError(t, err)
+	assert.Equal(t, "hello ada", byInterface.Greet())
+
+	byPointer, err := GetService[*englishGreeter](app, "greeter")
+	require.NoError(t, err)
+	assert.Equal(t, "ada", byPointer.name)
+
+	byValue, err := GetService[englishGreeter](app, "greeter")
+	require.NoError(t, err)
+	assert.Equal(t, "ada", byValue.name)
+
+	retries, err := GetService[int](app, "retries")
+	require.NoError(t, err)
+	assert.Equal(t, 3, retries)
+}
+
+func TestGetService_Errors(t *testing.T) {
+	app := newTypedServiceTestApp(t)
+
+	_, err := GetService[greeter](app, "greeterr")
+	require.ErrorIs(t, err, ErrServiceNotFound)
+	assert.Contains(t, err.Error(), "greeterr as modular.greeter")
+	assert.Contains(t, err.Error(), "registered services: app.health, app.info, greeter, logger, retries")
+
+	_, err = GetService[greeter](app, "retries")
+	require.ErrorIs(t, err, ErrServiceIncompatible)
+	assert.Contains(t, err.Error(), "service 'retries' of type int cannot be assigned to modular.greeter")
+	assert.Contains(t, err.Error(), "(registered services: ")
+}
+
+func TestMustGetService(t *testing.T) {
+	app := newTypedServiceTestApp(t)
+
+	assert.Equal(t, "hello ada", MustGetService[greeter](app, "greeter").Greet())
+	assert.PanicsWithError(t,
+		"service not found: missing as int (registered services: app.health, app.info, greeter, logger, retries)",
+		func() { MustGetService[int](app, "missing") })
+}
@@ -0,0 +1,240 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// Worker is an optional interface for modules that run long-lived background
+// loops such as event consumers or queue processors.
+//
+// The application supervises workers instead of leaving modules to spawn and
+// track their own goroutines. After all modules have started, Run is invoked
+// (once per configured concurrency slot) with a context derived from the
+// application lifecycle. When the application stops, that context is cancelled
+// once the ingress and proxy shutdown phases are done, and the application waits
+// for every Run call to return before stopping the remaining modules, so in-flight
+// work can drain while dependencies are still available.
+//
+// Run should block until ctx is cancelled or the work is complete. Returned
+// errors and panics are recovered and handled according to the worker's
+// WorkerPolicy.
+//
+// Example:
+//
+//	func (m *ConsumerModule) Run(ctx context.Context) error {
+//	    for {
+//	        select {
+//	        case <-ctx.Done():
+//	            return nil
+//	        case msg := <-m.messages:
+//	            if err := m.handle(ctx, msg); err != nil {
+//	                return err // restarted according to policy
+//	            }
+//	        }
+//	    }
+//	}
+type Worker interface {
+	Run(ctx context.Context) error
+}
+
+// WorkerPolicyProvider is an optional interface for workers that need a
+// supervision policy other than DefaultWorkerPolicy.
+type WorkerPolicyProvider interface {
+	WorkerPolicy() WorkerPolicy
+}
+
+// RestartPolicy determines when a worker's Run method is invoked again after it returns.
+type RestartPolicy int
+
+const (
+	// RestartOnFailure restarts the worker when Run returns an error or panics (default)
+	RestartOnFailure RestartPolicy = iota
+	// RestartAlways restarts the worker whenever Run returns, even without an error
+	RestartAlways
+	// RestartNever runs the worker once; errors are logged and the worker is not restarted
+	RestartNever
+)
+
+// String returns a human-readable name for the restart policy.
+func (p RestartPolicy) String() string {
+	switch p {
+	case RestartOnFailure:
+		return "on-failure"
+	case RestartAlways:
+		return "always"
+	case RestartNever:
+		return "never"
+	default:
+		return "unknown"
+	}
+}
+
+// WorkerPolicy configures how the application supervises a worker.
+type WorkerPolicy struct {
+	// Concurrency is the number of Run invocations executed in parallel. Values below 1 are treated as 1.
+	Concurrency int
+
+	// Restart determines when Run is invoked again after returning.
+	Restart RestartPolicy
+
+	// MaxRestarts limits restarts per concurrency slot. Zero means unlimited.
+	MaxRestarts int
+
+	// InitialBackoff is the delay before the first restart. It doubles after each restart up to MaxBackoff.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between restarts.
+	MaxBackoff time.Duration
+}
+
+// DefaultWorkerPolicy returns the policy applied to workers that do not implement
+// WorkerPolicyProvider: a single instance restarted on failure with exponential
+// backoff from 1s up to 30s.
+func DefaultWorkerPolicy() WorkerPolicy {
+	return WorkerPolicy{
+		Concurrency:    1,
+		Restart:        RestartOnFailure,
+		InitialBackoff: time.Second,
+		MaxBackoff:     30 * time.Second,
+	}
+}
+
+// normalized fills in defaults for unset policy fields.
+func (p WorkerPolicy) normalized() WorkerPolicy {
+	defaults := DefaultWorkerPolicy()
+	if p.Concurrency < 1 {
+		p.Concurrency = defaults.Concurrency
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaults.InitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaults.MaxBackoff
+	}
+	if p.MaxBackoff < p.InitialBackoff {
+		p.MaxBackoff = p.InitialBackoff
+	}
+	return p
+}
+
+// workerSupervisor runs and drains the workers of an application.
+type workerSupervisor struct {
+	logger Logger
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// startWorkers launches every module implementing Worker, in the given module order.
+// It returns nil when no module is a worker.
+func startWorkers(parent context.Context, logger Logger, moduleOrder []string, modules ModuleRegistry) *workerSupervisor {
+	var supervisor *workerSupervisor
+
+	for _, name := range moduleOrder {
+		worker, ok := modules[name].(Worker)
+		if !ok {
+			continue
+		}
+
+		if supervisor == nil {
+			ctx, cancel := context.WithCancel(parent)
+			supervisor = &workerSupervisor{logger: logger, cancel: cancel}
+			parent = ctx
+		}
+
+		policy := DefaultWorkerPolicy()
+		if provider, ok := worker.(WorkerPolicyProvider); ok {
+			policy = provider.WorkerPolicy().normalized()
+		}
+
+		logger.Info("Starting worker", "module", name, "concurrency", policy.Concurrency, "restart", policy.Restart.String())
+		for i := 0; i < policy.Concurrency; i++ {
+			supervisor.wg.Add(1)
+			go supervisor.supervise(parent, name, i, worker, policy)
+		}
+	}
+
+	return supervisor
+}
+
+// supervise runs a single worker slot, restarting it according to policy until
+// the context is cancelled or the policy says to stop.
+func (s *workerSupervisor) supervise(ctx context.Context, name string, slot int, worker Worker, policy WorkerPolicy) {
+	defer s.wg.Done()
+
+	backoff := policy.InitialBackoff
+	restarts := 0
+
+	for {
+		err := runWorker(ctx, worker)
+
+		if ctx.Err() != nil {
+			if err != nil && !errors.Is(err, context.Canceled) {
+				s.logger.Warn("Worker returned error while draining", "module", name, "slot", slot, "error", err)
+			}
+			return
+		}
+
+		if err != nil {
+			s.logger.Error("Worker failed", "module", name, "slot", slot, "error", err)
+		}
+
+		restart := policy.Restart == RestartAlways || (policy.Restart == RestartOnFailure && err != nil)
+		if !restart {
+			s.logger.Debug("Worker finished", "module", name, "slot", slot)
+			return
+		}
+
+		if policy.MaxRestarts > 0 && restarts >= policy.MaxRestarts {
+			s.logger.Error("Worker exceeded maximum restarts, giving up", "module", name, "slot", slot, "restarts", restarts)
+			return
+		}
+		restarts++
+
+		s.logger.Info("Restarting worker", "module", name, "slot", slot, "attempt", restarts, "backoff", backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// runWorker invokes Run, converting a panic into an ErrWorkerPanicked error.
+func runWorker(ctx context.Context, worker Worker) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v\n%s", ErrWorkerPanicked, r, debug.Stack())
+		}
+	}()
+	return worker.Run(ctx)
+}
+
+// stop cancels all workers and waits for them to return or for ctx to expire.
+func (s *workerSupervisor) stop(ctx context.Context) error {
+	s.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", ErrWorkerDrainTimeout, ctx.Err())
+	}
+}
@@ -0,0 +1,179 @@
+package modular
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workerTestModule is a module whose Run behavior is configurable per test
+type workerTestModule struct {
+	testModule
+	policy  *WorkerPolicy
+	run     func(ctx context.Context, call int32) error
+	calls   atomic.Int32
+	running atomic.Int32
+	maxSeen atomic.Int32
+	stopped atomic.Bool
+	mu      sync.Mutex
+	drained []bool
+}
+
+func (m *workerTestModule) Run(ctx context.Context) error {
+	call := m.calls.Add(1)
+	current := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		seen := m.maxSeen.Load()
+		if current <= seen || m.maxSeen.CompareAndSwap(seen, current) {
+			break
+		}
+	}
+	return m.run(ctx, call)
+}
+
+func (m *workerTestModule) Stop(context.Context) error {
+	m.stopped.Store(true)
+	return nil
+}
+
+type policyWorkerTestModule struct {
+	*workerTestModule
+}
+
+func (m policyWorkerTestModule) WorkerPolicy() WorkerPolicy {
+	return *m.policy
+}
+
+func newWorkerTestApp(t *testing.T, modules ...Module) *StdApplication {
+	t.Helper()
+	app := &StdApplication{
+		cfgProvider:    NewStdConfigProvider(testCfg{Str: "test"}),
+		cfgSections:    make(map[string]ConfigProvider),
+		svcRegistry:    make(ServiceRegistry),
+		moduleRegistry: make(ModuleRegistry),
+		logger:         &testLogger{},
+	}
+	for _, m := range modules {
+		app.RegisterModule(m)
+	}
+	return app
+}
+
+func TestWorker_RunsUntilStopAndDrainsBeforeModulesStop(t *testing.T) {
+	var stoppedBeforeDrain atomic.Bool
+	worker := &workerTestModule{testModule: testModule{name: "consumer"}}
+	worker.run = func(ctx context.Context, _ int32) error {
+		<-ctx.Done()
+		// Simulate draining in-flight work; the module must not be stopped yet
+		time.Sleep(20 * time.Millisecond)
+		stoppedBeforeDrain.Store(worker.stopped.Load())
+		return nil
+	}
+
+	app := newWorkerTestApp(t, worker)
+	require.NoError(t, app.Start())
+
+	assert.Eventually(t, func() bool { return worker.running.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, app.Stop())
+	assert.Equal(t, int32(0), worker.running.Load())
+	assert.False(t, stoppedBeforeDrain.Load(), "module Stop must be called after the worker drained")
+	assert.True(t, worker.stopped.Load())
+}
+
+func TestWorker_ConcurrencyAndRestartOnFailure(t *testing.T) {
+	worker := &workerTestModule{testModule: testModule{name: "processor"}}
+	worker.policy = &WorkerPolicy{
+		Concurrency:    3,
+		Restart:        RestartOnFailure,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+	}
+	worker.run = func(ctx context.Context, call int32) error {
+		if call <= 3 {
+			return errors.New("transient failure")
+		}
+		<-ctx.Done()
+		return nil
+	}
+
+	app := newWorkerTestApp(t, policyWorkerTestModule{worker})
+	require.NoError(t, app.Start())
+
+	assert.Eventually(t, func() bool { return worker.running.Load() == 3 }, time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, worker.calls.Load(), int32(6), "failed slots should be restarted")
+	assert.Equal(t, int32(3), worker.maxSeen.Load())
+
+	require.NoError(t, app.Stop())
+}
+
+func TestWorker_PanicRecoveryAndMaxRestarts(t *testing.T) {
+	worker := &workerTestModule{testModule: testModule{name: "panicky"}}
+	worker.policy = &WorkerPolicy{
+		Restart:        RestartOnFailure,
+		MaxRestarts:    2,
+		InitialBackoff: time.Millisecond,
+	}
+	worker.run = func(ctx context.Context, _ int32) error {
+		panic("boom")
+	}
+
+	app := newWorkerTestApp(t, policyWorkerTestModule{worker})
+	require.NoError(t, app.Start())
+
+	assert.Eventually(t, func() bool { return worker.calls.Load() == 3 }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(3), worker.calls.Load(), "worker should give up after MaxRestarts")
+
+	require.NoError(t, app.Stop())
+}
+
+func TestWorker_RestartNeverAndAlways(t *testing.T) {
+	never := &workerTestModule{testModule: testModule{name: "oneshot"}}
+	never.policy = &WorkerPolicy{Restart: RestartNever}
+	never.run = func(ctx context.Context, _ int32) error { return errors.New("failed") }
+
+	always := &workerTestModule{testModule: testModule{name: "poller"}}
+	always.policy = &WorkerPolicy{Restart: RestartAlways, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	always.run = func(ctx context.Context, _ int32) error { return nil }
+
+	app := newWorkerTestApp(t, policyWorkerTestModule{never}, policyWorkerTestModule{always})
+	require.NoError(t, app.Start())
+
+	assert.Eventually(t, func() bool { return always.calls.Load() >= 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(1), never.calls.Load())
+
+	require.NoError(t, app.Stop())
+}
+
+func TestWorker_DrainTimeout(t *testing.T) {
+	supervisor := startWorkers(context.Background(), &testLogger{}, []string{"stuck"}, ModuleRegistry{
+		"stuck": &workerTestModule{
+			testModule: testModule{name: "stuck"},
+			run: func(ctx context.Context, _ int32) error {
+				time.Sleep(200 * time.Millisecond)
+				return nil
+			},
+		},
+	})
+	require.NotNil(t, supervisor)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := supervisor.stop(ctx)
+	assert.ErrorIs(t, err, ErrWorkerDrainTimeout)
+}
+
+func TestStartWorkers_NoWorkers(t *testing.T) {
+	supervisor := startWorkers(context.Background(), &testLogger{}, []string{"plain"}, ModuleRegistry{
+		"plain": testModule{name: "plain"},
+	})
+	assert.Nil(t, supervisor)
+}